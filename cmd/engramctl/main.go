@@ -0,0 +1,373 @@
+// engramctl is the admin CLI for Engram. It talks to a running server through
+// the HTTP API, so it needs no database access.
+//
+// Usage:
+//
+//	engramctl tenant create --org NAME
+//	engramctl key create --name NAME [--scopes read,write]
+//	engramctl agent create --name NAME [--external-id ID]
+//	engramctl agent list [--limit N]
+//	engramctl agent get ID
+//	engramctl agent delete ID
+//	engramctl consolidate --agent ID [--full]
+//	engramctl reembed --agent ID
+//	engramctl vectors sync --agent ID
+//	engramctl vectors quantize --agent ID
+//	engramctl memories export --agent ID [--out FILE]
+//	engramctl memories import --agent ID [--in FILE]
+//	engramctl runs tail --agent ID [--interval 10s] [--lines 10]
+//	engramctl backup create --out FILE
+//	engramctl backup verify --in FILE [--key KEY]
+//	engramctl backup restore --in FILE
+//
+// Environment variables:
+//
+//	ENGRAM_API_URL      Engram server URL (default: http://localhost:8080)
+//	ENGRAM_API_KEY      API key; key create, reembed, vectors and backup need
+//	                    admin scope
+//	ENGRAM_SETUP_TOKEN  Setup token, for tenant create only
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Harshitk-cp/engram/pkg/client"
+)
+
+const usage = `usage: engramctl <command> [flags]
+
+commands:
+  tenant create --org NAME          provision a tenant and its master key
+  key create --name NAME            create an API key (--scopes read,write)
+  agent create|list|get|delete      manage agents
+  consolidate --agent ID            run a consolidation pass (--full for all episodes)
+  reembed --agent ID                recompute an agent's embeddings
+  vectors sync --agent ID           copy an agent's embeddings to the external vector store
+  vectors quantize --agent ID       fill the quantized copy of an agent's embeddings
+  memories export --agent ID        write the agent's memories as NDJSON (--out FILE)
+  memories import --agent ID        store memories from NDJSON (--in FILE)
+  runs tail --agent ID              follow the agent's consolidation runs
+  backup create --out FILE          write a checksummed point-in-time backup of the tenant
+  backup verify --in FILE           check a backup's checksum and row counts offline
+  backup restore --in FILE          load a backup into the tenant
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := client.New(envOr("ENGRAM_API_URL", "http://localhost:8080"), os.Getenv("ENGRAM_API_KEY"))
+	if err := run(ctx, c, os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "engramctl:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches one command. Results go to out; progress goes to stderr.
+func run(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+	cmd, rest := args[0], args[1:]
+	sub := ""
+	if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		sub = rest[0]
+	}
+
+	switch cmd {
+	case "tenant":
+		if sub != "create" {
+			return fmt.Errorf("unknown tenant command %q (use create)", sub)
+		}
+		return tenantCreate(ctx, c, rest[1:], out)
+	case "key":
+		if sub != "create" {
+			return fmt.Errorf("unknown key command %q (use create)", sub)
+		}
+		return keyCreate(ctx, c, rest[1:], out)
+	case "agent":
+		return agentCmd(ctx, c, sub, rest, out)
+	case "consolidate":
+		return consolidate(ctx, c, rest, out)
+	case "reembed":
+		return reembed(ctx, c, rest, out)
+	case "vectors":
+		switch sub {
+		case "sync":
+			return syncVectors(ctx, c, rest[1:], out)
+		case "quantize":
+			return quantizeVectors(ctx, c, rest[1:], out)
+		}
+		return fmt.Errorf("unknown vectors command %q (use sync or quantize)", sub)
+	case "memories":
+		switch sub {
+		case "export":
+			return exportMemories(ctx, c, rest[1:], out)
+		case "import":
+			return importMemories(ctx, c, rest[1:], out)
+		}
+		return fmt.Errorf("unknown memories command %q (use export or import)", sub)
+	case "backup":
+		var args []string
+		if len(rest) > 0 {
+			args = rest[1:]
+		}
+		return backupCmd(ctx, c, sub, args, out)
+	case "runs":
+		if sub != "tail" {
+			return fmt.Errorf("unknown runs command %q (use tail)", sub)
+		}
+		return tailRuns(ctx, c, rest[1:], out)
+	case "help", "-h", "--help":
+		fmt.Fprint(out, usage)
+		return nil
+	}
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+func tenantCreate(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("tenant create", flag.ContinueOnError)
+	org := fs.String("org", "", "Organisation (tenant) name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	token := os.Getenv("ENGRAM_SETUP_TOKEN")
+	if *org == "" || token == "" {
+		return errors.New("tenant create needs --org and ENGRAM_SETUP_TOKEN")
+	}
+	t, err := c.CreateTenant(ctx, token, *org)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "store the api_key now; it is not shown again")
+	return printJSON(out, t)
+}
+
+func keyCreate(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("key create", flag.ContinueOnError)
+	name := fs.String("name", "", "Key name")
+	scopes := fs.String("scopes", "read,write", "Comma-separated scopes (read, write, admin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("key create needs --name")
+	}
+	k, err := c.CreateAPIKey(ctx, client.CreateAPIKeyRequest{Name: *name, Scopes: strings.Split(*scopes, ",")})
+	if err != nil {
+		return err
+	}
+	return printJSON(out, k)
+}
+
+func agentCmd(ctx context.Context, c *client.Client, sub string, rest []string, out io.Writer) error {
+	var args []string
+	if len(rest) > 0 {
+		args = rest[1:]
+	}
+	switch sub {
+	case "create":
+		fs := flag.NewFlagSet("agent create", flag.ContinueOnError)
+		name := fs.String("name", "", "Agent name")
+		externalID := fs.String("external-id", "", "External ID (derived from the name when empty)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if *name == "" {
+			return errors.New("agent create needs --name")
+		}
+		agent, err := c.CreateAgent(ctx, client.CreateAgentRequest{Name: *name, ExternalID: *externalID})
+		if err != nil {
+			return err
+		}
+		return printJSON(out, agent)
+	case "list":
+		fs := flag.NewFlagSet("agent list", flag.ContinueOnError)
+		limit := fs.Int("limit", 0, "Page size (server default when 0)")
+		offset := fs.Int("offset", 0, "Page offset")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		list, err := c.ListAgents(ctx, *limit, *offset)
+		if err != nil {
+			return err
+		}
+		return printJSON(out, list)
+	case "get", "delete":
+		if len(args) != 1 {
+			return fmt.Errorf("agent %s needs an agent ID", sub)
+		}
+		if sub == "delete" {
+			if err := c.DeleteAgent(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "deleted", args[0])
+			return nil
+		}
+		agent, err := c.GetAgent(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(out, agent)
+	}
+	return fmt.Errorf("unknown agent command %q (use create, list, get or delete)", sub)
+}
+
+func consolidate(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("consolidate", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Agent ID")
+	full := fs.Bool("full", false, "Consolidate all episodes, not just recent ones")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("consolidate needs --agent")
+	}
+	scope := client.ConsolidationRecent
+	if *full {
+		scope = client.ConsolidationFull
+	}
+	res, err := c.Consolidate(ctx, *agentID, scope)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, res)
+}
+
+func reembed(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("reembed", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Agent ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("reembed needs --agent")
+	}
+	n, err := c.Reembed(ctx, *agentID)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, map[string]int{"reembedded": n})
+}
+
+func syncVectors(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("vectors sync", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Agent ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("vectors sync needs --agent")
+	}
+	n, err := c.SyncVectors(ctx, *agentID)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, map[string]int{"synced": n})
+}
+
+func quantizeVectors(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("vectors quantize", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Agent ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("vectors quantize needs --agent")
+	}
+	n, err := c.QuantizeVectors(ctx, *agentID)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, map[string]int{"quantized": n})
+}
+
+// tailRuns prints the agent's last few consolidation runs, then polls for new
+// ones until interrupted.
+func tailRuns(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("runs tail", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Agent ID")
+	interval := fs.Duration("interval", 10*time.Second, "Poll interval")
+	lines := fs.Int("lines", 10, "Runs to show before following")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("runs tail needs --agent")
+	}
+
+	seen := make(map[string]bool)
+	first := true
+	for {
+		runs, err := c.ListConsolidationRuns(ctx, *agentID, 50)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		// Runs arrive newest first; print the unseen ones oldest first.
+		var fresh []client.ConsolidationRun
+		for _, run := range runs {
+			if !seen[run.ID] {
+				seen[run.ID] = true
+				fresh = append(fresh, run)
+			}
+		}
+		if first && len(fresh) > *lines {
+			fresh = fresh[:*lines]
+		}
+		first = false
+		sort.Slice(fresh, func(i, j int) bool { return fresh[i].StartedAt.Before(fresh[j].StartedAt) })
+		for _, run := range fresh {
+			fmt.Fprintln(out, formatRun(run))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+func formatRun(run client.ConsolidationRun) string {
+	var result bytes.Buffer
+	if err := json.Compact(&result, run.Result); err != nil {
+		result.Write(run.Result)
+	}
+	return fmt.Sprintf("%s  %-6s  %6s  llm_calls=%d cost=$%.4f  %s",
+		run.StartedAt.Format(time.RFC3339), run.Scope,
+		run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond),
+		run.Usage.LLMCalls, run.Usage.EstimatedCostUSD, result.String())
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Harshitk-cp/engram/pkg/client"
+)
+
+const exportPageSize = 200
+
+// exportMemories writes every memory of the agent as one JSON object per line.
+func exportMemories(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("memories export", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Agent ID")
+	path := fs.String("out", "", "Output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("memories export needs --agent")
+	}
+	if *path != "" {
+		f, err := os.Create(*path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	n, err := writeMemories(ctx, c, *agentID, out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d memories\n", n)
+	return nil
+}
+
+func writeMemories(ctx context.Context, c *client.Client, agentID string, out io.Writer) (int, error) {
+	enc := json.NewEncoder(out)
+	n := 0
+	for offset := 0; ; offset += exportPageSize {
+		page, err := c.ListMemories(ctx, agentID, exportPageSize, offset)
+		if err != nil {
+			return n, err
+		}
+		for i := range page.Items {
+			if err := enc.Encode(page.Items[i]); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(page.Items) < exportPageSize || offset+len(page.Items) >= page.Total {
+			return n, nil
+		}
+	}
+}
+
+// importMemories stores each NDJSON memory under the target agent. Each line's
+// original ID keys the request, so re-running an interrupted import does not
+// store twice.
+func importMemories(ctx context.Context, c *client.Client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("memories import", flag.ContinueOnError)
+	agentID := fs.String("agent", "", "Target agent ID")
+	path := fs.String("in", "", "Input file (default: stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *agentID == "" {
+		return errors.New("memories import needs --agent")
+	}
+	var in io.Reader = os.Stdin
+	if *path != "" {
+		f, err := os.Open(*path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	stored, reinforced, err := readMemories(ctx, c, *agentID, in)
+	fmt.Fprintf(os.Stderr, "imported %d memories (%d reinforced existing ones)\n", stored, reinforced)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, map[string]int{"imported": stored, "reinforced": reinforced})
+}
+
+func readMemories(ctx context.Context, c *client.Client, agentID string, in io.Reader) (stored, reinforced int, err error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var m client.Memory
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			return stored, reinforced, fmt.Errorf("line %d: %w", line, err)
+		}
+		if m.Content == "" {
+			continue
+		}
+
+		reqCtx := ctx
+		if m.ID != "" {
+			reqCtx = client.WithIdempotencyKey(ctx, "engramctl-import:"+agentID+":"+m.ID)
+		}
+		res, err := c.CreateMemory(reqCtx, importRequest(agentID, m))
+		if err != nil {
+			return stored, reinforced, fmt.Errorf("line %d: %w", line, err)
+		}
+		stored++
+		if res.Reinforced {
+			reinforced++
+		}
+	}
+	return stored, reinforced, scanner.Err()
+}
+
+// importRequest maps an exported memory onto a create request. Session
+// bindings are dropped, since sessions are short-lived and tenant-local; the
+// memory is stored privately or under its anchor instead.
+func importRequest(agentID string, m client.Memory) client.CreateMemoryRequest {
+	req := client.CreateMemoryRequest{
+		AgentID:    agentID,
+		Content:    m.Content,
+		Type:       m.Type,
+		Source:     m.Source,
+		Provenance: m.Provenance,
+		Confidence: m.Confidence,
+		Metadata:   m.Metadata,
+		AnchorID:   m.AnchorID,
+		Quarantine: m.Binding == "quarantine",
+	}
+	if m.EventDate != nil {
+		req.EventDate = m.EventDate.Format(time.RFC3339)
+	}
+	if m.ID != "" {
+		if req.Metadata == nil {
+			req.Metadata = map[string]any{}
+		}
+		req.Metadata["imported_from"] = m.ID
+	}
+	return req
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Content is drawn from small vocabularies so memories about the same topic
+// share entities; the server's graph builder links those, which is how
+// --topic-size controls association density.
+var (
+	firstNames = []string{"Alice", "Bruno", "Chen", "Dana", "Emeka", "Farah", "Goran", "Hana", "Ivan", "Julia", "Kofi", "Lena", "Mateo", "Nadia", "Omar", "Priya"}
+	lastNames  = []string{"Okafor", "Lindqvist", "Tanaka", "Moreau", "Silva", "Novak", "Haddad", "Fischer", "Kowalski", "Reyes"}
+	companies  = []string{"Northwind", "Acme Robotics", "Globex", "Initech", "Umbrella Labs", "Hooli", "Vandelay Imports", "Stark Freight"}
+	products   = []string{"the mobile app", "the billing dashboard", "the REST API", "the Slack integration", "the CSV importer", "the analytics export", "single sign-on", "the webhook relay"}
+	cities     = []string{"Berlin", "Lagos", "Toronto", "Singapore", "Lisbon", "Austin", "Nairobi", "Melbourne"}
+	languages  = []string{"Go", "Python", "TypeScript", "Rust", "Kotlin", "Ruby"}
+	foods      = []string{"vegetarian", "vegan", "gluten-free", "halal", "kosher", "pescatarian"}
+	weekdays   = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+)
+
+// topic is one subject memories and episodes are written about.
+type topic struct {
+	person  string
+	company string
+	product string
+	city    string
+}
+
+type memorySpec struct {
+	content string
+	kind    string
+}
+
+type episodeSpec struct {
+	content        string
+	conversationID string
+	occurredAt     time.Time
+	outcome        string
+}
+
+// generator produces deterministic content for a given seed.
+type generator struct {
+	rnd *rand.Rand
+	now time.Time
+}
+
+func newGenerator(seed int64, now time.Time) *generator {
+	return &generator{rnd: rand.New(rand.NewSource(seed)), now: now}
+}
+
+func (g *generator) pick(list []string) string {
+	return list[g.rnd.Intn(len(list))]
+}
+
+func (g *generator) topic() topic {
+	return topic{
+		person:  g.pick(firstNames) + " " + g.pick(lastNames),
+		company: g.pick(companies),
+		product: g.pick(products),
+		city:    g.pick(cities),
+	}
+}
+
+// topics returns enough topics for n items at size items per topic.
+func (g *generator) topics(n, size int) []topic {
+	if size < 1 {
+		size = 1
+	}
+	out := make([]topic, (n+size-1)/size)
+	for i := range out {
+		out[i] = g.topic()
+	}
+	return out
+}
+
+func (g *generator) memory(t topic) memorySpec {
+	switch g.rnd.Intn(6) {
+	case 0:
+		return memorySpec{fmt.Sprintf("%s prefers to be contacted by email rather than phone.", t.person), "preference"}
+	case 1:
+		return memorySpec{fmt.Sprintf("%s works at %s and is based in %s.", t.person, t.company, t.city), "fact"}
+	case 2:
+		return memorySpec{fmt.Sprintf("%s at %s reported that %s times out on large accounts.", t.person, t.company, t.product), "fact"}
+	case 3:
+		return memorySpec{fmt.Sprintf("%s's team writes most of its services in %s.", t.company, g.pick(languages)), "fact"}
+	case 4:
+		return memorySpec{fmt.Sprintf("%s is %s; book catering accordingly for on-site visits to %s.", t.person, g.pick(foods), t.city), "preference"}
+	default:
+		return memorySpec{fmt.Sprintf("Always confirm the renewal date with %s before changing %s's plan.", t.person, t.company), "rule"}
+	}
+}
+
+func (g *generator) episode(t topic, conversationID string, horizon time.Duration) episodeSpec {
+	lines := []string{
+		fmt.Sprintf("user: Hi, this is %s from %s.", t.person, t.company),
+		fmt.Sprintf("user: We're having trouble with %s since the last release.", t.product),
+		"assistant: Sorry about that. Can you tell me what you see?",
+	}
+	switch g.rnd.Intn(3) {
+	case 0:
+		lines = append(lines,
+			"user: Requests fail after about thirty seconds.",
+			fmt.Sprintf("assistant: I've raised the timeout for %s; please retry.", t.company),
+			"user: That fixed it, thanks!")
+	case 1:
+		lines = append(lines,
+			fmt.Sprintf("user: Our %s office can't sign in at all.", t.city),
+			"assistant: I've escalated this to the identity team.",
+			"user: Okay, please keep me posted.")
+	default:
+		lines = append(lines,
+			fmt.Sprintf("user: Can we move our weekly sync to %s?", g.pick(weekdays)),
+			"assistant: Done, the invite is updated.",
+			"user: Great.")
+	}
+	outcome := "success"
+	if strings.Contains(lines[len(lines)-1], "posted") {
+		outcome = "neutral"
+	}
+	back := time.Duration(g.rnd.Int63n(int64(horizon)))
+	return episodeSpec{
+		content:        strings.Join(lines, "\n"),
+		conversationID: conversationID,
+		occurredAt:     g.now.Add(-back).UTC().Truncate(time.Second),
+		outcome:        outcome,
+	}
+}
+
+// query returns a recall query about one of the topics.
+func (g *generator) query(topics []topic) string {
+	t := topics[g.rnd.Intn(len(topics))]
+	switch g.rnd.Intn(4) {
+	case 0:
+		return fmt.Sprintf("How does %s like to be contacted?", t.person)
+	case 1:
+		return fmt.Sprintf("What problems has %s had with %s?", t.company, t.product)
+	case 2:
+		return fmt.Sprintf("Where is %s based?", t.person)
+	default:
+		return fmt.Sprintf("Anything to know before changing %s's plan?", t.company)
+	}
+}
@@ -0,0 +1,275 @@
+// engramload generates synthetic agents against a running Engram server and
+// measures it under a scripted workload, for capacity planning. Like
+// engramctl it only uses the HTTP API.
+//
+// It runs in three phases:
+//
+//  1. seed: create the agents, then store their memories and episodes
+//  2. workload: run memory recalls, episode recalls and consolidations
+//  3. cleanup: delete the agents (skipped with --keep)
+//
+// and prints latency percentiles per operation. Content is generated from a
+// seed, so two runs with the same flags send the same data.
+//
+// Usage:
+//
+//	engramload [--agents 5] [--memories 500] [--episodes 50] [--topic-size 8]
+//	           [--recalls 1000] [--episode-recalls 100] [--consolidations 1]
+//	           [--concurrency 8] [--seed 1] [--keep] [--json]
+//
+// Environment variables:
+//
+//	ENGRAM_API_URL  Engram server URL (default: http://localhost:8080)
+//	ENGRAM_API_KEY  API key with write scope
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Harshitk-cp/engram/pkg/client"
+)
+
+// config is the shape of one load run.
+type config struct {
+	agents         int
+	memories       int
+	episodes       int
+	topicSize      int
+	recalls        int
+	episodeRecalls int
+	consolidations int
+	concurrency    int
+	horizon        time.Duration
+	seed           int64
+	prefix         string
+	keep           bool
+	json           bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "engramload:", err)
+		os.Exit(2)
+	}
+	c := client.New(envOr("ENGRAM_API_URL", "http://localhost:8080"), os.Getenv("ENGRAM_API_KEY"))
+	if err := run(ctx, c, cfg, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "engramload:", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("engramload", flag.ContinueOnError)
+	fs.IntVar(&cfg.agents, "agents", 5, "Agents to create")
+	fs.IntVar(&cfg.memories, "memories", 500, "Memories per agent")
+	fs.IntVar(&cfg.episodes, "episodes", 50, "Episodes per agent")
+	fs.IntVar(&cfg.topicSize, "topic-size", 8, "Memories per topic; larger topics share more entities and so produce more associations")
+	fs.IntVar(&cfg.recalls, "recalls", 1000, "Memory recalls, spread over the agents")
+	fs.IntVar(&cfg.episodeRecalls, "episode-recalls", 100, "Episode recalls, spread over the agents")
+	fs.IntVar(&cfg.consolidations, "consolidations", 1, "Consolidation passes per agent")
+	fs.IntVar(&cfg.concurrency, "concurrency", 8, "Concurrent requests")
+	fs.DurationVar(&cfg.horizon, "horizon", 30*24*time.Hour, "How far back episodes are spread")
+	fs.Int64Var(&cfg.seed, "seed", 1, "Content seed")
+	fs.StringVar(&cfg.prefix, "prefix", "engramload", "Agent name prefix")
+	fs.BoolVar(&cfg.keep, "keep", false, "Keep the generated agents after the run")
+	fs.BoolVar(&cfg.json, "json", false, "Print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if cfg.agents < 1 || cfg.concurrency < 1 || cfg.horizon <= 0 {
+		return cfg, errors.New("--agents, --concurrency and --horizon must be positive")
+	}
+	if cfg.memories < 0 || cfg.episodes < 0 || cfg.recalls < 0 || cfg.episodeRecalls < 0 || cfg.consolidations < 0 {
+		return cfg, errors.New("volumes must not be negative")
+	}
+	return cfg, nil
+}
+
+// agentPlan is the content generated for one agent.
+type agentPlan struct {
+	id       string
+	name     string
+	topics   []topic
+	memories []memorySpec
+	episodes []episodeSpec
+}
+
+func run(ctx context.Context, c *client.Client, cfg config, out io.Writer) error {
+	rec := newRecorder()
+	plans := plan(cfg, time.Now())
+
+	// Agents are created one by one so a bad key or URL fails fast.
+	seedStart := time.Now()
+	for _, p := range plans {
+		start := time.Now()
+		agent, err := c.CreateAgent(ctx, client.CreateAgentRequest{Name: p.name})
+		rec.observe("agent.create", time.Since(start), err)
+		if err != nil {
+			return fmt.Errorf("create agent %s: %w", p.name, err)
+		}
+		p.id = agent.ID
+	}
+	rec.phase("agent.create", time.Since(seedStart))
+	if !cfg.keep {
+		defer cleanup(c, plans)
+	}
+
+	var memJobs, epJobs []func(context.Context) error
+	for _, p := range plans {
+		for _, m := range p.memories {
+			req := client.CreateMemoryRequest{AgentID: p.id, Content: m.content, Type: m.kind, Source: "engramload"}
+			memJobs = append(memJobs, func(ctx context.Context) error {
+				_, err := c.CreateMemory(ctx, req)
+				return err
+			})
+		}
+		for _, e := range p.episodes {
+			at := e.occurredAt
+			req := client.CreateEpisodeRequest{AgentID: p.id, RawContent: e.content, ConversationID: e.conversationID, OccurredAt: &at, Outcome: e.outcome}
+			epJobs = append(epJobs, func(ctx context.Context) error {
+				_, err := c.CreateEpisode(ctx, req)
+				return err
+			})
+		}
+	}
+	fmt.Fprintf(os.Stderr, "seeding %d agents: %d memories, %d episodes\n", len(plans), len(memJobs), len(epJobs))
+	runPhase(ctx, rec, "memory.create", cfg.concurrency, memJobs)
+	runPhase(ctx, rec, "episode.create", cfg.concurrency, epJobs)
+
+	wl := newGenerator(cfg.seed^0x5eed, time.Now())
+	var recallJobs, epRecallJobs, consolidateJobs []func(context.Context) error
+	for i := 0; i < cfg.recalls; i++ {
+		p := plans[i%len(plans)]
+		req := client.RecallRequest{AgentID: p.id, Query: wl.query(p.topics), TopK: 10}
+		recallJobs = append(recallJobs, func(ctx context.Context) error {
+			_, err := c.Recall(ctx, req)
+			return err
+		})
+	}
+	for i := 0; i < cfg.episodeRecalls; i++ {
+		p := plans[i%len(plans)]
+		req := client.RecallEpisodesRequest{AgentID: p.id, Query: wl.query(p.topics), Limit: 10}
+		epRecallJobs = append(epRecallJobs, func(ctx context.Context) error {
+			_, err := c.RecallEpisodes(ctx, req)
+			return err
+		})
+	}
+	for _, p := range plans {
+		for i := 0; i < cfg.consolidations; i++ {
+			agentID := p.id
+			consolidateJobs = append(consolidateJobs, func(ctx context.Context) error {
+				_, err := c.Consolidate(ctx, agentID, client.ConsolidationRecent)
+				return err
+			})
+		}
+	}
+	fmt.Fprintf(os.Stderr, "running workload: %d recalls, %d episode recalls, %d consolidations\n",
+		len(recallJobs), len(epRecallJobs), len(consolidateJobs))
+	runPhase(ctx, rec, "memory.recall", cfg.concurrency, recallJobs)
+	runPhase(ctx, rec, "episode.recall", cfg.concurrency, epRecallJobs)
+	runPhase(ctx, rec, "consolidate", cfg.concurrency, consolidateJobs)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if cfg.json {
+		return writeJSON(out, rec.report())
+	}
+	return writeTable(out, rec.report())
+}
+
+// plan generates every agent's content up front, each from its own seed, so
+// the data does not depend on request scheduling.
+func plan(cfg config, now time.Time) []*agentPlan {
+	runTag := now.UTC().Format("20060102-150405")
+	plans := make([]*agentPlan, cfg.agents)
+	for i := range plans {
+		g := newGenerator(cfg.seed+int64(i), now)
+		p := &agentPlan{
+			name:   fmt.Sprintf("%s-%s-%d", cfg.prefix, runTag, i+1),
+			topics: g.topics(max(cfg.memories, 1), cfg.topicSize),
+		}
+		for j := 0; j < cfg.memories; j++ {
+			p.memories = append(p.memories, g.memory(p.topics[j/max(cfg.topicSize, 1)]))
+		}
+		for j := 0; j < cfg.episodes; j++ {
+			t := p.topics[g.rnd.Intn(len(p.topics))]
+			p.episodes = append(p.episodes, g.episode(t, fmt.Sprintf("%s-conv-%d", p.name, j/3), cfg.horizon))
+		}
+		plans[i] = p
+	}
+	return plans
+}
+
+// runPhase runs jobs on concurrency workers, timing each call under op.
+func runPhase(ctx context.Context, rec *recorder, op string, concurrency int, jobs []func(context.Context) error) {
+	if len(jobs) == 0 {
+		return
+	}
+	next := make(chan func(context.Context) error)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range next {
+				t := time.Now()
+				err := job(ctx)
+				if ctx.Err() != nil {
+					return
+				}
+				rec.observe(op, time.Since(t), err)
+			}
+		}()
+	}
+feed:
+	for _, job := range jobs {
+		select {
+		case next <- job:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	rec.phase(op, time.Since(start))
+}
+
+// cleanup deletes the generated agents, even after an interrupt.
+func cleanup(c *client.Client, plans []*agentPlan) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, p := range plans {
+		if p.id == "" {
+			continue
+		}
+		if err := c.DeleteAgent(ctx, p.id); err != nil {
+			fmt.Fprintf(os.Stderr, "delete agent %s: %v\n", p.id, err)
+		}
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
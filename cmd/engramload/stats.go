@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// recorder collects per-operation latencies from concurrent workers.
+type recorder struct {
+	mu    sync.Mutex
+	ops   map[string]*opSamples
+	order []string
+}
+
+type opSamples struct {
+	latencies []time.Duration
+	errors    int
+	wall      time.Duration
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: make(map[string]*opSamples)}
+}
+
+func (r *recorder) op(name string) *opSamples {
+	s, ok := r.ops[name]
+	if !ok {
+		s = &opSamples{}
+		r.ops[name] = s
+		r.order = append(r.order, name)
+	}
+	return s
+}
+
+// observe records one call of op; failed calls count as errors only.
+func (r *recorder) observe(op string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.op(op)
+	if err != nil {
+		// Only the first failure is printed; the rest are counted.
+		if s.errors == 0 {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", op, err)
+		}
+		s.errors++
+		return
+	}
+	s.latencies = append(s.latencies, d)
+}
+
+// phase records the wall time spent running op, for throughput.
+func (r *recorder) phase(op string, wall time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.op(op).wall += wall
+}
+
+// opReport summarises one operation; latencies are in milliseconds.
+type opReport struct {
+	Op         string  `json:"op"`
+	Count      int     `json:"count"`
+	Errors     int     `json:"errors"`
+	P50        float64 `json:"p50_ms"`
+	P90        float64 `json:"p90_ms"`
+	P95        float64 `json:"p95_ms"`
+	P99        float64 `json:"p99_ms"`
+	Max        float64 `json:"max_ms"`
+	Throughput float64 `json:"ops_per_sec"`
+}
+
+func (r *recorder) report() []opReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]opReport, 0, len(r.order))
+	for _, name := range r.order {
+		s := r.ops[name]
+		lat := append([]time.Duration(nil), s.latencies...)
+		sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+		rep := opReport{
+			Op:     name,
+			Count:  len(lat),
+			Errors: s.errors,
+			P50:    millis(percentile(lat, 0.50)),
+			P90:    millis(percentile(lat, 0.90)),
+			P95:    millis(percentile(lat, 0.95)),
+			P99:    millis(percentile(lat, 0.99)),
+		}
+		if len(lat) > 0 {
+			rep.Max = millis(lat[len(lat)-1])
+		}
+		if s.wall > 0 {
+			rep.Throughput = float64(len(lat)) / s.wall.Seconds()
+		}
+		out = append(out, rep)
+	}
+	return out
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func writeTable(w io.Writer, reports []opReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\tcount\terrors\tp50 ms\tp90 ms\tp95 ms\tp99 ms\tmax ms\tops/s\t")
+	for _, r := range reports {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			r.Op, r.Count, r.Errors, r.P50, r.P90, r.P95, r.P99, r.Max, r.Throughput)
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, reports []opReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
@@ -10,18 +10,26 @@ import (
 
 	"github.com/Harshitk-cp/engram/internal/api"
 	"github.com/Harshitk-cp/engram/internal/config"
+	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 func main() {
-	logger, _ := zap.NewProduction()
+	level := zap.NewAtomicLevel()
+	logCfg := zap.NewProductionConfig()
+	logCfg.Level = level
+	logger, _ := logCfg.Build()
 	defer func() { _ = logger.Sync() }()
 
 	if err := config.Load(); err != nil {
 		logger.Fatal("failed to load config", zap.Error(err))
 	}
+	if err := level.UnmarshalText([]byte(config.LogLevel())); err != nil {
+		logger.Fatal("invalid LOG_LEVEL", zap.Error(err))
+	}
 
 	dbURL := config.DatabaseURL()
 	if dbURL == "" {
@@ -30,14 +38,23 @@ func main() {
 
 	ctx := context.Background()
 
-	poolCfg, err := pgxpool.ParseConfig(dbURL)
+	poolOpts := store.PoolOptions{
+		MaxConns:        config.DBMaxConns(),
+		MinConns:        config.DBMinConns(),
+		MaxConnLifetime: config.DBMaxConnLifetime(),
+		MaxConnIdleTime: config.DBMaxConnIdleTime(),
+		QueryExecMode:   config.DBStatementCacheMode(),
+		PgBouncer:       config.DBPgBouncer(),
+		EfSearch:        config.VectorEfSearch(),
+		IVFFlatProbes:   config.VectorIVFFlatProbes(),
+	}
+	poolCfg, err := store.NewPoolConfig(dbURL, poolOpts)
 	if err != nil {
 		logger.Fatal("invalid DATABASE_URL", zap.Error(err))
 	}
-	poolCfg.MaxConns = config.DBMaxConns()
-	poolCfg.MinConns = config.DBMinConns()
-	poolCfg.MaxConnLifetime = config.DBMaxConnLifetime()
-	poolCfg.MaxConnIdleTime = config.DBMaxConnIdleTime()
+	if config.DBPgBouncer() && (config.VectorEfSearch() > 0 || config.VectorIVFFlatProbes() > 0) {
+		logger.Warn("PGVECTOR_EF_SEARCH and PGVECTOR_IVFFLAT_PROBES are not applied behind PgBouncer; set them on the database role with ALTER ROLE ... SET")
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
@@ -46,7 +63,9 @@ func main() {
 	defer pool.Close()
 	logger.Info("database pool configured",
 		zap.Int32("max_conns", poolCfg.MaxConns),
-		zap.Int32("min_conns", poolCfg.MinConns))
+		zap.Int32("min_conns", poolCfg.MinConns),
+		zap.Stringer("query_exec_mode", poolCfg.ConnConfig.DefaultQueryExecMode),
+		zap.Bool("pgbouncer", config.DBPgBouncer()))
 
 	if err := pool.Ping(ctx); err != nil {
 		logger.Fatal("failed to ping database", zap.Error(err))
@@ -56,15 +75,40 @@ func main() {
 	if err := store.EnsureEmbeddingDimension(ctx, pool, config.EmbeddingDim(), logger); err != nil {
 		logger.Fatal("embedding dimension reconciliation failed", zap.Error(err))
 	}
+	if err := store.EnsureVectorQuantization(ctx, pool, vectorQuantization(), logger); err != nil {
+		logger.Fatal("vector index reconciliation failed", zap.Error(err))
+	}
+
+	tenants := store.NewTenantRouter(pool)
+	defer tenants.Close()
+	for name, url := range config.TenantDatabases() {
+		tenantPool, err := connectTenantDatabase(ctx, url, poolOpts, logger)
+		if err != nil {
+			logger.Fatal("failed to connect to tenant database", zap.String("database", name), zap.Error(err))
+		}
+		if err := tenants.AddDatabase(name, tenantPool); err != nil {
+			logger.Fatal("invalid TENANT_DATABASES", zap.Error(err))
+		}
+		logger.Info("connected to tenant database", zap.String("database", name))
+	}
+	for tenant, name := range config.TenantRoutes() {
+		id, err := uuid.Parse(tenant)
+		if err != nil {
+			logger.Fatal("invalid TENANT_ROUTES", zap.String("tenant", tenant), zap.Error(err))
+		}
+		if err := tenants.Route(id, name); err != nil {
+			logger.Fatal("invalid TENANT_ROUTES", zap.Error(err))
+		}
+	}
+	if err := tenants.SyncTenants(ctx); err != nil {
+		logger.Fatal("failed to sync routed tenants", zap.Error(err))
+	}
 
-	app := api.NewApp(pool, logger)
+	app := api.NewTenantApp(tenants, logger)
+	app.SetLogLevel(level)
 
 	// Start background services
-	app.Tuner.Start()
-	app.Expirer.Start()
-	app.Decay.Start()
-	app.Consolidation.Start()
-	app.Learning.Start()
+	app.Start()
 
 	addr := config.ServerAddr()
 	srv := &http.Server{
@@ -83,15 +127,27 @@ func main() {
 		}
 	}()
 
+	// SIGHUP re-reads the config file and applies its reloadable settings.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			res, err := app.ReloadConfig()
+			if err != nil {
+				logger.Error("config reload failed", zap.Error(err))
+				continue
+			}
+			logger.Info("config reloaded",
+				zap.Strings("applied", res.Applied),
+				zap.Strings("restart_required", res.RestartRequired))
+		}
+	}()
+
 	<-quit
 	logger.Info("shutting down server")
 
 	// Stop background services
-	app.Tuner.Stop()
-	app.Expirer.Stop()
-	app.Decay.Stop()
-	app.Consolidation.Stop()
-	app.Learning.Stop()
+	app.Stop()
 
 	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -102,3 +158,39 @@ func main() {
 
 	logger.Info("server stopped")
 }
+
+// connectTenantDatabase opens a pool on a TENANT_DATABASES entry, sized and
+// tuned like the default one, and checks its embedding columns.
+func connectTenantDatabase(ctx context.Context, url string, opts store.PoolOptions, logger *zap.Logger) (*pgxpool.Pool, error) {
+	cfg, err := store.NewPoolConfig(url, opts)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	if err := store.EnsureEmbeddingDimension(ctx, pool, config.EmbeddingDim(), logger); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	if err := store.EnsureVectorQuantization(ctx, pool, vectorQuantization(), logger); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// vectorQuantization is the configured VECTOR_QUANTIZATION; an invalid value
+// searches full precision, as engram.New falls back to.
+func vectorQuantization() domain.VectorQuantization {
+	q, err := domain.ParseVectorQuantization(config.VectorQuantization())
+	if err != nil {
+		return domain.QuantizationNone
+	}
+	return q
+}
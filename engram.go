@@ -0,0 +1,608 @@
+// Package engram runs the Engram memory engine inside another Go process.
+//
+// New wires the same stores, model clients and services the HTTP server uses,
+// so a host program can call them directly — no server, no API keys:
+//
+//	eng, err := engram.New(engram.Options{DB: pool, Logger: logger})
+//	if err != nil { ... }
+//	eng.Start()
+//	defer eng.Stop()
+//
+//	eng.Hooks.OnMemoryCreated(func(ctx context.Context, e engram.MemoryEvent) { ... })
+//	_, err = eng.Memory.Create(ctx, &engram.Memory{TenantID: tenantID, AgentID: agentID, Content: "prefers email"})
+//	memories, err := eng.Memory.Recall(ctx, "how to reach them", agentID, tenantID, engram.RecallOpts{TopK: 5})
+//
+// The database must have the migrations applied. Dependencies left unset in
+// Options are built from the usual environment variables and config file, the
+// same way the server builds them.
+package engram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/config"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/embedding"
+	"github.com/Harshitk-cp/engram/internal/events"
+	"github.com/Harshitk-cp/engram/internal/llm"
+	"github.com/Harshitk-cp/engram/internal/redis"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/Harshitk-cp/engram/internal/transcribe"
+	"github.com/Harshitk-cp/engram/internal/vectorstore"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Types a host program needs to name when calling the services.
+type (
+	Memory          = domain.Memory
+	MemoryWithScore = domain.MemoryWithScore
+	MemoryType      = domain.MemoryType
+	RecallOpts      = domain.RecallOpts
+	Agent           = domain.Agent
+	Tenant          = domain.Tenant
+	Episode         = domain.Episode
+	Procedure       = domain.Procedure
+	Schema          = domain.Schema
+	Message         = domain.Message
+
+	EmbeddingClient = domain.EmbeddingClient
+	LLMClient       = domain.LLMClient
+	EventPublisher  = domain.EventPublisher
+	VectorStore     = domain.VectorStore
+	TenantRouter    = store.TenantRouter
+
+	Hooks              = service.Hooks
+	MemoryEvent        = service.MemoryEvent
+	ConsolidationEvent = service.ConsolidationEvent
+	ConsolidationScope = service.ConsolidationScope
+	CreateResult       = service.CreateResult
+
+	MemoryService        = service.MemoryService
+	WorkingMemoryService = service.WorkingMemoryService
+	ConsolidationService = service.ConsolidationService
+	EpisodeService       = service.EpisodeService
+	ProceduralService    = service.ProceduralService
+	SchemaService        = service.SchemaService
+)
+
+// NewTenantRouter returns a router that keeps every tenant on def until
+// databases and routes are added; pass it as Options.Tenants.
+func NewTenantRouter(def *pgxpool.Pool) *TenantRouter {
+	return store.NewTenantRouter(def)
+}
+
+// Options configures New. Only DB (or Tenants) is required.
+type Options struct {
+	// DB is the Postgres database holding Engram's schema.
+	DB *pgxpool.Pool
+	// Tenants spreads tenant data over several databases; it replaces DB.
+	Tenants *TenantRouter
+	// Logger defaults to a no-op logger.
+	Logger *zap.Logger
+
+	// LLM and Embedding default to the clients named by LLM_PROVIDER and
+	// EMBEDDING_PROVIDER.
+	LLM       LLMClient
+	Embedding EmbeddingClient
+	// Events receives outbox events once Start is called. Defaults to the
+	// EVENT_WEBHOOK_URL webhook; with neither, events stay in the outbox.
+	Events EventPublisher
+	// Vectors serves memory similarity search from an external index, with
+	// Postgres kept as the system of record. Defaults to the store named by
+	// VECTOR_STORE; with neither, search runs on pgvector.
+	Vectors VectorStore
+	// RedisURL caches working memory in Redis. Defaults to REDIS_URL; empty
+	// keeps working memory in Postgres.
+	RedisURL string
+
+	// JobWorkers and JobQueueSize size the background job pool. Zero uses
+	// JOB_WORKERS and JOB_QUEUE_SIZE.
+	JobWorkers   int
+	JobQueueSize int
+	// DisableGraph skips entity and relationship extraction on writes, as
+	// DISABLE_GRAPH=true does.
+	DisableGraph bool
+}
+
+// Stores are the stores behind the services, for reads the services don't
+// cover. The control-plane stores (Tenants, Settings, Idempotency) live on the
+// default database.
+type Stores struct {
+	Tenants             *store.TenantStore
+	Agents              *store.AgentStore
+	Memories            *store.MemoryStore
+	Policies            *store.PolicyStore
+	Feedback            *store.FeedbackStore
+	Contradictions      *store.ContradictionStore
+	Episodes            *store.EpisodeStore
+	Procedures          *store.ProcedureStore
+	Schemas             *store.SchemaStore
+	WorkingMemory       *store.WorkingMemoryStore
+	Associations        *store.MemoryAssociationStore
+	Graph               *store.GraphStore
+	Entities            *store.EntityStore
+	Sessions            *store.SessionStore
+	MutationLog         *store.MutationLogStore
+	EpisodeMemoryUsage  *store.EpisodeMemoryUsageStore
+	LearningStats       *store.LearningStatsStore
+	ConversationActs    *store.ConversationActivationStore
+	Outbox              *store.OutboxStore
+	Retention           *store.RetentionStore
+	Settings            *store.TenantSettingsStore
+	Idempotency         *store.IdempotencyStore
+	ConsolidationRuns   *store.ConsolidationRunStore
+	ConsolidationFailed *store.ConsolidationFailureStore
+	AgentStatistics     *store.AgentStatisticsStore
+	PipelineMetrics     *store.PipelineMetricsStore
+	SourceReliability   *store.SourceReliabilityStore
+	RecallLogs          *store.RecallLogStore
+	UnitOfWork          *store.UnitOfWork
+}
+
+// Engine is a wired Engram instance.
+type Engine struct {
+	Agents            *service.AgentService
+	Memory            *service.MemoryService
+	WorkingMemory     *service.WorkingMemoryService
+	Consolidation     *service.ConsolidationService
+	Episodes          *service.EpisodeService
+	EpisodePositions  *service.EpisodePositionService
+	Procedures        *service.ProceduralService
+	Schemas           *service.SchemaService
+	Policies          *service.PolicyService
+	Feedback          *service.FeedbackService
+	ImplicitFeedback  *service.ImplicitFeedbackDetector
+	Reconsolidation   *service.ReconsolidationService
+	Confidence        *service.ConfidenceService
+	Decay             *service.DecayService
+	Learning          *service.LearningService
+	Metacognition     *service.MetacognitiveService
+	HybridRecall      *service.HybridRecallService
+	GraphBuilder      *service.GraphBuilderService
+	Conversations     *service.ConversationService
+	Retention         *service.RetentionService
+	Admin             *service.AdminService
+	AgentStats        *service.AgentStatsService
+	TemporalPatterns  *service.TemporalPatternService
+	PipelineAnomalies *service.PipelineAnomalyService
+	SourceReliability *service.SourceReliabilityService
+	RecallLog         *service.RecallLogService
+
+	// Background workers, run by Start.
+	Scheduler   *service.Scheduler
+	Jobs        *service.JobPool
+	Tuner       *service.TunerService
+	Expirer     *service.ExpirerService
+	ColdSummary *service.ColdSummaryService
+	Tiers       *service.TierTransitionService
+	Outbox      *service.OutboxPublisherService
+	WMFlush     *service.WorkingMemoryFlushService
+	Ingest      *service.IngestBuffer
+
+	// Hooks reports memory lifecycle events to in-process callbacks.
+	Hooks *service.Hooks
+
+	Stores    Stores
+	LLM       LLMClient
+	Embedding EmbeddingClient
+	// Vectors is the external vector store, or nil on pgvector.
+	Vectors VectorStore
+	// DB is the default database; Tenants routes tenant data.
+	DB      *pgxpool.Pool
+	Tenants *TenantRouter
+	Logger  *zap.Logger
+}
+
+// New wires an Engine. Nothing runs in the background until Start.
+func New(opts Options) (*Engine, error) {
+	tenants := opts.Tenants
+	if tenants == nil {
+		if opts.DB == nil {
+			return nil, errors.New("engram: Options.DB or Options.Tenants is required")
+		}
+		tenants = store.NewTenantRouter(opts.DB)
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	db := tenants.Default()
+	fanout := service.DatabaseFanout(tenants.ForEachDatabase)
+
+	e := &Engine{DB: db, Tenants: tenants, Logger: logger}
+	st := &e.Stores
+
+	// Stores
+	st.Tenants = store.NewTenantStore(db)
+	st.Agents = store.NewAgentStore(tenants)
+	st.Memories = store.NewMemoryStore(tenants)
+	st.Policies = store.NewPolicyStore(tenants)
+	st.Feedback = store.NewFeedbackStore(tenants)
+	st.Contradictions = store.NewContradictionStore(tenants)
+	st.Episodes = store.NewEpisodeStore(tenants)
+	st.Procedures = store.NewProcedureStore(tenants)
+	st.Schemas = store.NewSchemaStore(tenants)
+	st.WorkingMemory = store.NewWorkingMemoryStore(tenants)
+	st.Associations = store.NewMemoryAssociationStore(tenants)
+	st.Graph = store.NewGraphStore(tenants)
+	st.Entities = store.NewEntityStore(tenants)
+	st.Sessions = store.NewSessionStore(tenants)
+	st.MutationLog = store.NewMutationLogStore(tenants)
+	st.EpisodeMemoryUsage = store.NewEpisodeMemoryUsageStore(tenants)
+	st.LearningStats = store.NewLearningStatsStore(tenants)
+	st.ConversationActs = store.NewConversationActivationStore(tenants)
+	st.Outbox = store.NewOutboxStore(tenants)
+	st.Retention = store.NewRetentionStore(tenants)
+	st.Settings = store.NewTenantSettingsStore(db)
+	st.Idempotency = store.NewIdempotencyStore(db)
+	st.ConsolidationRuns = store.NewConsolidationRunStore(tenants)
+	st.ConsolidationFailed = store.NewConsolidationFailureStore(tenants)
+	st.AgentStatistics = store.NewAgentStatisticsStore(tenants)
+	st.PipelineMetrics = store.NewPipelineMetricsStore(tenants)
+	st.SourceReliability = store.NewSourceReliabilityStore(tenants)
+	st.RecallLogs = store.NewRecallLogStore(tenants)
+
+	// Similarity search moves to an external vector store when one is set.
+	e.Vectors = opts.Vectors
+	if e.Vectors == nil {
+		e.Vectors = newVectorStore(logger)
+	}
+	if e.Vectors != nil {
+		st.Memories.SetVectorStore(e.Vectors, logger)
+	}
+	quantization, err := domain.ParseVectorQuantization(config.VectorQuantization())
+	if err != nil {
+		logger.Warn("invalid VECTOR_QUANTIZATION, searching full-precision embeddings", zap.Error(err))
+	} else if quantization != domain.QuantizationNone {
+		st.Memories.SetQuantization(quantization)
+	}
+
+	// Unit of work for atomic state-change + audit-log and consolidation writes.
+	uow := store.NewUnitOfWork(tenants, st.Memories, st.MutationLog, st.Contradictions, st.Episodes, st.Associations)
+	st.UnitOfWork = uow
+
+	// External clients
+	e.LLM, e.Embedding = opts.LLM, opts.Embedding
+	if e.LLM == nil {
+		e.LLM = newLLMClient(logger)
+	}
+	if e.Embedding == nil {
+		e.Embedding = newEmbeddingClient(logger)
+	}
+	llmClient, embeddingClient := e.LLM, e.Embedding
+
+	// Text a tenant has already embedded is served from the embeddings table.
+	var embeddingCache *store.EmbeddingStore
+	if config.EmbeddingDedup() && embeddingClient != nil {
+		model := embeddingDedupModel()
+		st.Memories.SetEmbeddingDedup(model)
+		st.Episodes.SetEmbeddingDedup(model)
+		embeddingCache = store.NewEmbeddingStore(tenants, model)
+		embeddingClient = service.NewDedupEmbeddingClient(embeddingClient, embeddingCache, logger)
+	}
+
+	// Services
+	e.Agents = service.NewAgentService(st.Agents)
+	memorySvc := service.NewMemoryService(st.Memories, st.Agents, embeddingClient, llmClient, logger)
+	e.Memory = memorySvc
+
+	// Background job pool shared by async extraction and consolidation
+	workers, queueSize := opts.JobWorkers, opts.JobQueueSize
+	if workers == 0 {
+		workers = config.JobWorkers()
+	}
+	if queueSize == 0 {
+		queueSize = config.JobQueueSize()
+	}
+	e.Jobs = service.NewJobPool(workers, queueSize, logger)
+	memorySvc.SetJobPool(e.Jobs)
+	e.Hooks = service.NewHooks(logger)
+	memorySvc.SetHooks(e.Hooks)
+	e.Policies = service.NewPolicyService(st.Policies, st.Memories, st.Agents, llmClient, embeddingClient, logger)
+	e.Feedback = service.NewFeedbackService(st.Feedback, st.Memories, st.Agents)
+	e.Feedback.SetUnitOfWork(uow)
+	e.SourceReliability = service.NewSourceReliabilityService(st.SourceReliability, logger)
+	e.Feedback.SetSourceReliability(e.SourceReliability)
+	memorySvc.SetSourceReliability(e.SourceReliability)
+	e.Tuner = service.NewTunerService(st.Feedback, st.Policies, logger)
+	e.Tuner.SetAgentRegistry(st.Agents)
+	e.Expirer = service.NewExpirerService(st.Memories, st.Policies, st.Feedback, logger)
+	e.Expirer.SetSessionStore(st.Sessions)
+	e.Expirer.SetAgentRegistry(st.Agents)
+	e.Expirer.SetIdempotencyStore(st.Idempotency)
+	e.Expirer.SetOutboxStore(st.Outbox)
+	e.Expirer.SetEpisodePartitions(st.Episodes, config.EpisodeRetentionMonths())
+	e.Expirer.SetRetentionStore(st.Retention)
+	if embeddingCache != nil {
+		e.Expirer.SetEmbeddingCache(embeddingCache)
+	}
+	e.ColdSummary = service.NewColdSummaryService(st.Memories, embeddingClient, llmClient, logger)
+	e.Tiers = service.NewTierTransitionService(st.Memories, logger)
+	e.Tiers.SetAgentRegistry(st.Agents)
+	e.Tiers.SetFanout(fanout)
+	e.Retention = service.NewRetentionService(st.Retention)
+	publisher := opts.Events
+	if publisher == nil {
+		if url := config.EventWebhookURL(); url != "" {
+			publisher = events.NewWebhookPublisher(url, config.EventWebhookSecret())
+		}
+	}
+	e.Outbox = service.NewOutboxPublisherService(st.Outbox, publisher, logger)
+	e.Outbox.SetFanout(fanout)
+	memorySvc.SetColdSummarizer(e.ColdSummary)
+	memorySvc.SetNeighborhoodStore(st.Memories)
+	memorySvc.SetEnvironmentBoost(config.RecallEnvironmentBoost())
+	e.Confidence = service.NewConfidenceService(st.Memories, logger)
+	e.Confidence.SetHooks(e.Hooks)
+	e.Episodes = service.NewEpisodeService(st.Episodes, st.Agents, embeddingClient, llmClient, logger)
+	e.EpisodePositions = service.NewEpisodePositionService(st.Episodes, logger)
+	e.EpisodePositions.SetSettingsStore(store.NewEpisodePositionSettingsStore(tenants))
+	e.Episodes.SetPositionWeighting(e.EpisodePositions)
+	e.Episodes.SetEnvironmentBoost(config.RecallEnvironmentBoost())
+	e.Procedures = service.NewProceduralService(st.Procedures, st.Episodes, st.Agents, embeddingClient, llmClient, logger)
+	e.Schemas = service.NewSchemaService(st.Schemas, st.Memories, st.Agents, embeddingClient, llmClient, logger)
+	e.Schemas.SetEpisodeStore(st.Episodes)
+	e.Schemas.SetMemoryScanner(st.Memories)
+
+	// Working memory is served from Redis when a Redis URL is set, with
+	// Postgres as the durable copy and fallback.
+	var wmCache domain.WorkingMemoryStore = st.WorkingMemory
+	var wmFlusher domain.WorkingMemoryFlusher
+	redisURL := opts.RedisURL
+	if redisURL == "" {
+		redisURL = config.RedisURL()
+	}
+	if redisURL != "" {
+		if rdb, err := redis.New(redisURL); err != nil {
+			logger.Warn("invalid REDIS_URL, working memory stays in Postgres", zap.Error(err))
+		} else {
+			cached := store.NewRedisWorkingMemoryStore(rdb, st.WorkingMemory, store.DefaultWorkingMemoryCacheTTL)
+			wmCache, wmFlusher = cached, cached
+			logger.Info("working memory cache enabled (redis)")
+		}
+	}
+	e.WMFlush = service.NewWorkingMemoryFlushService(wmFlusher, logger)
+	e.WorkingMemory = service.NewWorkingMemoryService(wmCache, st.Associations, st.Memories, st.Episodes, st.Procedures, st.Schemas, embeddingClient, logger)
+	e.WorkingMemory.SetSettingsStore(store.NewWorkingMemorySettingsStore(tenants))
+	e.WorkingMemory.SetSnapshotStore(store.NewWorkingMemorySnapshotStore(tenants))
+	e.WorkingMemory.SetConversationActivationStore(st.ConversationActs)
+	e.WorkingMemory.SetEntityStore(st.Entities)
+
+	consolidationSvc := service.NewConsolidationService(st.Memories, st.Episodes, st.Procedures, st.Schemas, st.Associations, st.Contradictions, embeddingClient, llmClient, logger)
+	e.Consolidation = consolidationSvc
+	e.Decay = service.NewDecayService(st.Memories, st.Episodes, logger)
+	e.Decay.SetMutationLogStore(st.MutationLog)
+	e.Decay.SetAgentRegistry(st.Agents)
+	e.Decay.SetUnitOfWork(uow)
+	e.Decay.SetHooks(e.Hooks)
+
+	// Per-tenant engine tuning (decay rate, floor, competition, confidence deltas).
+	e.Confidence.SetSettingsStore(st.Settings)
+	e.Decay.SetSettingsStore(st.Settings)
+	consolidationSvc.SetDecayService(e.Decay)
+	consolidationSvc.SetReplayBudget(config.ConsolidationReplayBudget())
+	consolidationSvc.SetUnitOfWork(uow)
+	consolidationSvc.SetGraphStore(st.Graph)
+	consolidationSvc.SetEntityStore(st.Entities)
+	consolidationSvc.SetRedundancyStore(st.Memories)
+	consolidationSvc.SetMemoryScanner(st.Memories)
+	consolidationSvc.SetHealthStore(store.NewHealthStore(tenants))
+	consolidationSvc.SetAgentRegistry(st.Agents)
+	consolidationSvc.SetRunStore(st.ConsolidationRuns)
+	consolidationSvc.SetJobPool(e.Jobs)
+	consolidationSvc.SetHooks(e.Hooks)
+	consolidationSvc.SetFailureStore(st.ConsolidationFailed)
+	consolidationSvc.SetSourceReliability(e.SourceReliability)
+	consolidationSvc.SetPositionWeighting(e.EpisodePositions)
+	e.Metacognition = service.NewMetacognitiveService(st.Memories, st.Episodes, st.Procedures, st.Schemas, st.Contradictions, embeddingClient, logger)
+	e.Metacognition.SetMemoryScanner(st.Memories)
+	e.Metacognition.SetSourceReliability(e.SourceReliability)
+	e.Metacognition.SetLLMClient(llmClient)
+	e.Admin = service.NewAdminService(st.Memories, embeddingClient, uow, logger)
+	e.Admin.SetMemoryScanner(st.Memories)
+	e.Admin.SetDerivedRecomputer(st.Memories)
+	e.Admin.SetTierTransitioner(e.Tiers)
+	if e.Vectors != nil {
+		e.Admin.SetVectorSyncer(st.Memories)
+	}
+	if quantization != domain.QuantizationNone {
+		e.Admin.SetVectorQuantizer(st.Memories)
+	}
+
+	// Graph services
+	e.HybridRecall = service.NewHybridRecallService(st.Memories, st.Graph, st.Entities, embeddingClient, llmClient)
+	e.HybridRecall.SetSessionStore(st.Sessions)
+	e.HybridRecall.SetColdSummarizer(e.ColdSummary)
+	e.HybridRecall.SetNeighborhoodStore(st.Memories)
+	e.HybridRecall.SetEnvironmentBoost(config.RecallEnvironmentBoost())
+	if cache := service.NewRecallCache(config.RecallCacheTTL(), service.DefaultRecallCacheEntries); cache != nil {
+		e.HybridRecall.SetRecallCache(cache)
+		st.Memories.SetChangeListener(cache)
+	}
+	e.GraphBuilder = service.NewGraphBuilderService(st.Memories, st.Graph, st.Entities, embeddingClient, llmClient, logger)
+
+	// Learning services
+	e.Learning = service.NewLearningService(st.Memories, st.Episodes, logger)
+	e.Learning.SetMutationLogStore(st.MutationLog)
+	e.Learning.SetEpisodeMemoryUsageStore(st.EpisodeMemoryUsage)
+	e.Learning.SetLearningStatsStore(st.LearningStats)
+	e.Learning.SetUnitOfWork(uow)
+	e.Learning.SetProcedureStore(st.Procedures)
+	e.Learning.SetConversationActivationStore(st.ConversationActs)
+	e.Learning.SetAgentRegistry(st.Agents)
+	e.Learning.SetFanout(fanout)
+	e.ImplicitFeedback = service.NewImplicitFeedbackDetector(llmClient, st.Feedback, st.Memories, logger)
+	e.ImplicitFeedback.SetMutationLogStore(st.MutationLog)
+	e.Reconsolidation = service.NewReconsolidationService(st.Memories, st.ConversationActs, embeddingClient, llmClient, logger)
+	e.Reconsolidation.SetMutationLogStore(st.MutationLog)
+	e.Reconsolidation.SetHooks(e.Hooks)
+
+	// Wire policy enforcer and contradiction store into memory service
+	memorySvc.SetPolicyEnforcer(e.Policies)
+	memorySvc.SetContradictionStore(st.Contradictions)
+	memorySvc.SetMutationLogStore(st.MutationLog)
+	memorySvc.SetSettingsStore(st.Settings) // Provenance Firewall policy
+	memorySvc.SetUnitOfWork(uow)
+	if !opts.DisableGraph && os.Getenv("DISABLE_GRAPH") != "true" {
+		memorySvc.SetGraphBuilder(e.GraphBuilder)
+	}
+
+	// Wire memory store into episode service for belief extraction
+	e.Episodes.SetMemoryStore(st.Memories)
+	e.Episodes.SetReconsolidator(e.Reconsolidation)
+	e.Episodes.SetProcedureStore(st.Procedures)
+	e.Episodes.SetMemoryUsageStore(st.EpisodeMemoryUsage)
+	e.Episodes.SetChunkStore(st.Episodes)
+	e.Episodes.SetEntityStore(st.Entities)
+	if vc := llm.NewVisionCaptioner(llmClient, config.VisionModel()); vc != nil {
+		e.Episodes.SetImageCaptioner(vc)
+	}
+	if tc := transcribe.New(config.TranscriptionBaseURL(), config.TranscriptionAPIKey(), config.TranscriptionModel()); tc != nil {
+		e.Episodes.SetTranscriber(tc)
+	}
+	e.Episodes.SetOutcomeAttributor(e.Learning)
+	e.Episodes.SetSourceReliability(e.SourceReliability)
+
+	e.Ingest = service.NewIngestBuffer(e.Episodes, memorySvc, embeddingClient,
+		config.IngestBufferSize(), config.IngestBatchSize(), config.IngestFlushInterval(), logger)
+
+	e.RecallLog = service.NewRecallLogService(st.RecallLogs, config.RecallLogSampleRate(), logger)
+	e.Conversations = service.NewConversationService(memorySvc, llmClient, logger)
+	e.AgentStats = service.NewAgentStatsService(st.AgentStatistics, logger)
+	e.TemporalPatterns = service.NewTemporalPatternService(st.Episodes, e.Schemas, st.Schemas, st.Agents, logger)
+	e.PipelineAnomalies = service.NewPipelineAnomalyService(st.PipelineMetrics, st.Agents, logger)
+	e.PipelineAnomalies.SetOutboxStore(st.Outbox)
+	e.PipelineAnomalies.SetHooks(e.Hooks)
+
+	// Periodic workers share one scheduler, which tracks their runs and lets
+	// operators pause them.
+	e.Scheduler = service.NewScheduler(logger)
+	e.Scheduler.SetFanout(fanout)
+	for _, task := range []service.ScheduledTask{
+		e.Tuner.ScheduledTask(),
+		e.Expirer.ScheduledTask(),
+		e.Decay.ScheduledTask(),
+		consolidationSvc.ScheduledTask(),
+		e.AgentStats.ScheduledTask(),
+		e.TemporalPatterns.ScheduledTask(),
+		e.PipelineAnomalies.ScheduledTask(),
+	} {
+		if err := e.Scheduler.Register(task); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Start runs the background workers: the job pool, the scheduled passes
+// (decay, consolidation, expiry, tuning, stats, temporal patterns, pipeline
+// anomalies), learning, cold summaries, tier transitions, outbox delivery, the
+// working memory flush, the ingest buffer and the recall log writer.
+func (e *Engine) Start() {
+	e.Jobs.Start()
+	e.Ingest.Start()
+	e.Scheduler.Start()
+	e.Learning.Start()
+	e.ColdSummary.Start()
+	e.Tiers.Start()
+	e.Outbox.Start()
+	e.WMFlush.Start()
+	e.RecallLog.Start()
+}
+
+// Stop stops the background workers started by Start. The ingest buffer goes
+// first, writing out what it holds while the rest is still running; the
+// recall log goes last, writing out its queued samples for up to
+// recallLogStopTimeout. The databases belong to the caller and stay open.
+func (e *Engine) Stop() {
+	e.Ingest.Stop()
+	e.Scheduler.Stop()
+	e.Jobs.Stop()
+	e.Learning.Stop()
+	e.ColdSummary.Stop()
+	e.Tiers.Stop()
+	e.Outbox.Stop()
+	e.WMFlush.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), recallLogStopTimeout)
+	defer cancel()
+	e.RecallLog.Stop(ctx)
+}
+
+// recallLogStopTimeout bounds how long Stop waits on queued recall logs.
+const recallLogStopTimeout = 5 * time.Second
+
+func newLLMClient(logger *zap.Logger) LLMClient {
+	provider := config.LLMProvider()
+	client, err := llm.NewClient(provider, config.LLMAPIKey())
+	if err != nil {
+		logger.Warn("LLM client initialization failed", zap.String("provider", provider), zap.Error(err))
+		return nil
+	}
+	logger.Info("LLM client initialized", zap.String("provider", provider))
+	return client
+}
+
+func newEmbeddingClient(logger *zap.Logger) EmbeddingClient {
+	provider := config.EmbeddingProvider()
+	client, err := embedding.NewClient(embedding.Config{
+		Provider:   provider,
+		APIKey:     config.EmbeddingAPIKey(),
+		BaseURL:    config.EmbeddingBaseURL(),
+		Model:      config.EmbeddingModel(),
+		Dimensions: config.EmbeddingDim(),
+	})
+	if err != nil {
+		logger.Warn("Embedding client initialization failed", zap.String("provider", provider), zap.Error(err))
+		return nil
+	}
+	logger.Info("Embedding client initialized",
+		zap.String("provider", provider),
+		zap.String("model", config.EmbeddingModel()),
+		zap.Int("dimension", config.EmbeddingDim()))
+	if provider != embedding.ProviderMock {
+		probeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if vec, perr := client.Embed(probeCtx, "dimension probe"); perr != nil {
+			logger.Warn("embedding provider probe failed (continuing); recall will be empty until it works", zap.Error(perr))
+		} else if len(vec) != config.EmbeddingDim() {
+			logger.Error("embedding dimension mismatch — writes will fail until resolved",
+				zap.Int("model_output_dim", len(vec)),
+				zap.Int("configured_dim", config.EmbeddingDim()),
+				zap.String("hint", "set EMBEDDING_DIM to the model's dimension (on a fresh DB) or pick a matching model"))
+		}
+		cancel()
+	}
+	return client
+}
+
+// embeddingDedupModel keys the embeddings table by everything that shapes a
+// vector, so switching provider, model or width never serves stale vectors.
+func embeddingDedupModel() string {
+	return fmt.Sprintf("%s/%s/%d", config.EmbeddingProvider(), config.EmbeddingModel(), config.EmbeddingDim())
+}
+
+// newVectorStore builds the store named by VECTOR_STORE, or returns nil to
+// search on pgvector.
+func newVectorStore(logger *zap.Logger) VectorStore {
+	kind, url := config.VectorStore(), config.VectorStoreURL()
+	if kind == "" || kind == "pgvector" {
+		return nil
+	}
+	if url == "" {
+		logger.Warn("VECTOR_STORE set without VECTOR_STORE_URL, searching on pgvector", zap.String("vector_store", kind))
+		return nil
+	}
+	vs, err := vectorstore.New(kind, url, config.VectorStoreAPIKey(), config.VectorStoreCollection())
+	if err != nil {
+		logger.Warn("invalid VECTOR_STORE, searching on pgvector", zap.Error(err))
+		return nil
+	}
+	logger.Info("similarity search on external vector store", zap.String("vector_store", kind))
+	return vs
+}
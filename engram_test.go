@@ -0,0 +1,51 @@
+package engram_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Harshitk-cp/engram"
+	"github.com/Harshitk-cp/engram/internal/embedding"
+	"github.com/Harshitk-cp/engram/internal/llm"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestNew_RequiresDatabase(t *testing.T) {
+	if _, err := engram.New(engram.Options{}); err == nil {
+		t.Fatal("expected an error without a database")
+	}
+}
+
+func TestNew_WiresEveryService(t *testing.T) {
+	// pgxpool only dials on first use, so no database is needed to wire.
+	pool, err := pgxpool.New(context.Background(), "postgres://engram@localhost:1/engram")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer pool.Close()
+
+	emb, model := embedding.NewMockClient(), llm.NewMockClient()
+	eng, err := engram.New(engram.Options{DB: pool, LLM: model, Embedding: emb, JobWorkers: 1, JobQueueSize: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if eng.LLM != model || eng.Embedding != emb {
+		t.Fatal("expected the injected model clients to be used")
+	}
+	if eng.DB != pool || eng.Tenants.Default() != pool {
+		t.Fatal("expected the engine to run on the given pool")
+	}
+
+	for _, v := range []reflect.Value{reflect.ValueOf(*eng), reflect.ValueOf(eng.Stores)} {
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Kind() == reflect.Pointer && f.IsNil() {
+				t.Errorf("%s.%s is not wired", v.Type().Name(), v.Type().Field(i).Name)
+			}
+		}
+	}
+
+	eng.Hooks.OnMemoryCreated(func(context.Context, engram.MemoryEvent) {})
+	eng.Start()
+	eng.Stop()
+}
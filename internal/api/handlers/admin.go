@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/config"
@@ -14,13 +16,19 @@ import (
 )
 
 type AdminHandler struct {
-	svc *service.AdminService
+	svc    *service.AdminService
+	search *service.TenantSearchService
 }
 
 func NewAdminHandler(svc *service.AdminService) *AdminHandler {
 	return &AdminHandler{svc: svc}
 }
 
+// SetSearchService enables GET /v1/admin/search.
+func (h *AdminHandler) SetSearchService(search *service.TenantSearchService) {
+	h.search = search
+}
+
 type updateMemoryRequest struct {
 	Confidence *float32 `json:"confidence,omitempty"`
 	Content    *string  `json:"content,omitempty"`
@@ -190,6 +198,157 @@ func (h *AdminHandler) Reembed(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"reembedded": n})
 }
 
+// SyncVectors handles POST /v1/admin/agents/{id}/vectors/sync — copy the
+// agent's stored embeddings to the external vector store.
+func (h *AdminHandler) SyncVectors(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+	n, err := h.svc.SyncVectors(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrNoVectorStore) {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"synced": n})
+}
+
+// QuantizeVectors handles POST /v1/admin/agents/{id}/vectors/quantize — fill
+// the quantized copy of the agent's stored embeddings.
+func (h *AdminHandler) QuantizeVectors(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+	n, err := h.svc.QuantizeVectors(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrQuantizationDisabled) {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"quantized": n})
+}
+
+type recomputeRequest struct {
+	Fields []domain.DerivedField `json:"fields"`
+}
+
+// Recompute handles POST /v1/admin/agents/{id}/recompute — recompute the
+// agent's derived fields (all, or the listed fields) from raw data.
+func (h *AdminHandler) Recompute(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	auth := middleware.AuthFromContext(r.Context())
+	if tenant == nil || auth == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+	var req recomputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	counts, err := h.svc.RecomputeDerived(r.Context(), agentID, tenant.ID, req.Fields, auth.ActorType(), auth.KeyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownDerivedField):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrRecomputeUnavailable):
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"recomputed": counts})
+}
+
+// Search handles GET /v1/admin/search?q=&mode=&agent_id=&include_episodes=&limit=&offset=
+// — find every memory (and optionally episode) across the tenant's agents that
+// mentions something, including archived and quarantined rows.
+func (h *AdminHandler) Search(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.search == nil {
+		writeError(w, http.StatusServiceUnavailable, "tenant search is not configured")
+		return
+	}
+	q := r.URL.Query()
+	input := service.TenantSearchInput{
+		TenantID: tenant.ID,
+		Query:    q.Get("q"),
+		Mode:     domain.SearchMode(q.Get("mode")),
+	}
+	if v := q.Get("agent_id"); v != "" {
+		agentID, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid agent_id")
+			return
+		}
+		input.AgentID = &agentID
+	}
+	if v := q.Get("include_episodes"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid include_episodes")
+			return
+		}
+		input.IncludeEpisodes = b
+	}
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			input.Limit = n
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			input.Offset = n
+		}
+	}
+
+	result, err := h.search.Search(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSearchQueryEmpty), errors.Is(err, service.ErrInvalidSearchMode):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSemanticSearchUnavailable):
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "search failed")
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (h *AdminHandler) writeServiceErr(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, service.ErrReasonRequired):
@@ -1,32 +1,68 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/service"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
 )
 
 type AgentHandler struct {
-	svc *service.AgentService
+	svc    *service.AgentService
+	seeder *service.AgentSeedService
+	cloner *service.AgentCloneService
 }
 
 func NewAgentHandler(svc *service.AgentService) *AgentHandler {
 	return &AgentHandler{svc: svc}
 }
 
+// SetSeedService enables POST /v1/agents/{id}/seed.
+func (h *AgentHandler) SetSeedService(seeder *service.AgentSeedService) {
+	h.seeder = seeder
+}
+
+// SetCloneService enables POST /v1/agents/{id}/clone.
+func (h *AgentHandler) SetCloneService(cloner *service.AgentCloneService) {
+	h.cloner = cloner
+}
+
+// maxSeedDocumentBytes bounds a seed document upload.
+const maxSeedDocumentBytes = 4 << 20
+
 type createAgentRequest struct {
 	ExternalID string         `json:"external_id"`
 	Name       string         `json:"name"`
 	Metadata   map[string]any `json:"metadata"`
 }
 
+type cloneAgentRequest struct {
+	ExternalID      string         `json:"external_id"`
+	Name            string         `json:"name"`
+	Metadata        map[string]any `json:"metadata"`
+	IncludeMemories bool           `json:"include_memories"`
+	// TargetAPIKey is a write-scoped key of another tenant to clone into.
+	TargetAPIKey string `json:"target_api_key,omitempty"`
+}
+
+type listAgentsResponse struct {
+	Agents []domain.Agent `json:"agents"`
+	Total  int            `json:"total"`
+	Count  int            `json:"count"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
 func (h *AgentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.TenantFromContext(r.Context())
 	if tenant == nil {
@@ -99,12 +135,12 @@ func (h *AgentHandler) List(w http.ResponseWriter, r *http.Request) {
 		total = len(agents)
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"agents": agents,
-		"total":  total,
-		"count":  len(agents),
-		"limit":  limit,
-		"offset": offset,
+	writeJSON(w, http.StatusOK, listAgentsResponse{
+		Agents: agents,
+		Total:  total,
+		Count:  len(agents),
+		Limit:  limit,
+		Offset: offset,
 	})
 }
 
@@ -160,3 +196,134 @@ func (h *AgentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, agent)
 }
+
+// Seed loads a declarative seed document into the agent. The body is JSON, or
+// YAML when the Content-Type says so; both use the same field names.
+func (h *AgentHandler) Seed(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.seeder == nil {
+		writeError(w, http.StatusServiceUnavailable, "agent seeding is not configured")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSeedDocumentBytes+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	if len(body) > maxSeedDocumentBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, "seed document too large")
+		return
+	}
+
+	doc, err := decodeSeedDocument(body, strings.Contains(r.Header.Get("Content-Type"), "yaml"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid seed document: "+err.Error())
+		return
+	}
+
+	result, err := h.seeder.Seed(r.Context(), id, tenant.ID, doc)
+	if err != nil {
+		var invalid *service.SeedValidationError
+		switch {
+		case errors.As(err, &invalid), errors.Is(err, service.ErrSeedEmpty),
+			errors.Is(err, service.ErrSchemaNotFound), errors.Is(err, service.ErrSchemaCycle):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to seed agent")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// decodeSeedDocument parses a JSON or YAML seed document. YAML is normalised
+// through JSON so one set of field tags serves both formats.
+func decodeSeedDocument(body []byte, isYAML bool) (domain.SeedDocument, error) {
+	var doc domain.SeedDocument
+	if isYAML {
+		var raw any
+		if err := yaml.Unmarshal(body, &raw); err != nil {
+			return doc, err
+		}
+		converted, err := json.Marshal(raw)
+		if err != nil {
+			return doc, err
+		}
+		body = converted
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
+// Clone copies the agent's schemas, procedures and, on request, its private
+// memories into a new agent, in this tenant or the one target_api_key belongs to.
+func (h *AgentHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.cloner == nil {
+		writeError(w, http.StatusServiceUnavailable, "agent cloning is not configured")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	var req cloneAgentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.cloner.Clone(r.Context(), service.CloneInput{
+		SourceID:        id,
+		TenantID:        tenant.ID,
+		ExternalID:      req.ExternalID,
+		Name:            req.Name,
+		Metadata:        req.Metadata,
+		IncludeMemories: req.IncludeMemories,
+		TargetAPIKey:    req.TargetAPIKey,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrCloneTargetInvalid):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrCloneTargetForbidden):
+			writeError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrAgentConflict):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrAgentQuotaExceeded):
+			writeError(w, http.StatusPaymentRequired, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to clone agent")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, result)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+func TestDecodeSeedDocument_YAMLMatchesJSON(t *testing.T) {
+	yamlDoc := []byte(`
+name: support-basics
+beliefs:
+  - key: tz
+    content: Customers expect replies in CET business hours
+    confidence: 0.8
+    metadata:
+      team: support
+schemas:
+  - schema_type: user_archetype
+    name: Impatient debugger
+    evidence: [tz]
+procedures:
+  - trigger_pattern: failed deploy
+    action_template: Ask for the deploy ID
+    action_type: problem_solving
+    examples:
+      - trigger: deploy broke
+        response: Which deploy ID?
+`)
+	jsonDoc := []byte(`{
+		"name": "support-basics",
+		"beliefs": [{"key": "tz", "content": "Customers expect replies in CET business hours", "confidence": 0.8, "metadata": {"team": "support"}}],
+		"schemas": [{"schema_type": "user_archetype", "name": "Impatient debugger", "evidence": ["tz"]}],
+		"procedures": [{"trigger_pattern": "failed deploy", "action_template": "Ask for the deploy ID", "action_type": "problem_solving",
+			"examples": [{"trigger": "deploy broke", "response": "Which deploy ID?"}]}]
+	}`)
+
+	fromYAML, err := decodeSeedDocument(yamlDoc, true)
+	if err != nil {
+		t.Fatalf("decode yaml: %v", err)
+	}
+	fromJSON, err := decodeSeedDocument(jsonDoc, false)
+	if err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+
+	if fromYAML.Name != fromJSON.Name || len(fromYAML.Beliefs) != 1 || len(fromYAML.Schemas) != 1 || len(fromYAML.Procedures) != 1 {
+		t.Fatalf("unexpected yaml document: %+v", fromYAML)
+	}
+	if fromYAML.Beliefs[0].Confidence != 0.8 || fromYAML.Beliefs[0].Metadata["team"] != "support" {
+		t.Fatalf("unexpected yaml belief: %+v", fromYAML.Beliefs[0])
+	}
+	if fromYAML.Procedures[0].ActionType != domain.ActionTypeProblemSolving || fromYAML.Procedures[0].Examples[0] != fromJSON.Procedures[0].Examples[0] {
+		t.Fatalf("unexpected yaml procedure: %+v", fromYAML.Procedures[0])
+	}
+}
+
+func TestDecodeSeedDocument_RejectsUnknownFields(t *testing.T) {
+	if _, err := decodeSeedDocument([]byte("beliefs:\n  - contnet: typo\n"), true); err == nil {
+		t.Fatal("expected an error for a misspelled field")
+	}
+}
@@ -3,11 +3,13 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
 	"github.com/Harshitk-cp/engram/internal/store"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -17,12 +19,18 @@ import (
 type AnchorHandler struct {
 	anchors  *store.EntityStore
 	memories *store.MemoryStore
+	exporter *service.SubjectExportService
 }
 
 func NewAnchorHandler(anchors *store.EntityStore, memories *store.MemoryStore) *AnchorHandler {
 	return &AnchorHandler{anchors: anchors, memories: memories}
 }
 
+// SetExportService enables GET /v1/anchors/{id}/export.
+func (h *AnchorHandler) SetExportService(exporter *service.SubjectExportService) {
+	h.exporter = exporter
+}
+
 type createAnchorRequest struct {
 	Name       string         `json:"name"`
 	EntityType string         `json:"entity_type,omitempty"`
@@ -158,6 +166,36 @@ func (h *AnchorHandler) ListMemories(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"memories": memories, "count": len(memories)})
 }
 
+// Export handles GET /v1/anchors/{id}/export — everything stored about the
+// subject as one JSON document, for right-of-access requests.
+func (h *AnchorHandler) Export(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.exporter == nil {
+		writeError(w, http.StatusServiceUnavailable, "subject export is not configured")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid anchor id")
+		return
+	}
+	export, err := h.exporter.Export(r.Context(), id, tenant.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrAnchorNotFound) {
+			writeError(w, http.StatusNotFound, "anchor not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "export failed")
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=engram-subject-%s.json", id))
+	writeJSON(w, http.StatusOK, export)
+}
+
 // Delete unlinks (default) or purges (?purge=true) an anchor. Purge is the
 // GDPR/HIPAA erasure path: it hard-deletes the anchor's traces, then the anchor.
 func (h *AnchorHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/service"
+)
+
+// BackupHandler exports and restores whole-tenant backups.
+type BackupHandler struct {
+	svc *service.BackupService
+}
+
+func NewBackupHandler(svc *service.BackupService) *BackupHandler {
+	return &BackupHandler{svc: svc}
+}
+
+// countingWriter records whether the response body has been started.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Export handles GET /v1/backup — streams a point-in-time NDJSON backup of
+// the tenant, embeddings included, ending in a checksummed trailer. A failure
+// mid-stream leaves the trailer off, so the backup reads as truncated.
+func (h *BackupHandler) Export(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=engram-backup-%s-%s.ndjson",
+		tenant.ID, time.Now().UTC().Format("20060102T150405Z")))
+	cw := &countingWriter{w: w}
+	if _, err := h.svc.Export(r.Context(), tenant.ID, cw); err != nil && cw.n == 0 {
+		writeError(w, http.StatusInternalServerError, "backup failed")
+	}
+}
+
+// Restore handles POST /v1/backup/restore — loads a backup into the tenant in
+// one transaction. Existing rows are kept; rows that aren't the tenant's are
+// skipped.
+func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	res, err := h.svc.Restore(r.Context(), tenant.ID, r.Body)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBackup) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "restore failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
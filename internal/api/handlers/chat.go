@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/google/uuid"
+)
+
+const chatMaxBodyBytes = 4 << 20 // 4 MiB
+
+// ChatHandler serves an OpenAI-compatible chat completions endpoint backed by
+// the agent's memory. Point an OpenAI SDK's base URL at <server>/v1 with an
+// Engram API key and name the agent with the X-Engram-Agent-Id header (or
+// ?agent_id=); X-Engram-Conversation-Id optionally names the conversation.
+type ChatHandler struct {
+	svc *service.ChatService
+}
+
+func NewChatHandler(svc *service.ChatService) *ChatHandler {
+	return &ChatHandler{svc: svc}
+}
+
+// Complete handles POST /v1/chat/completions. The provider's response is
+// returned unchanged; X-Engram-Context-Items reports how many memories were
+// injected and X-Engram-Capture-Job the job recording the exchange.
+func (h *ChatHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentParam := r.Header.Get("X-Engram-Agent-Id")
+	if agentParam == "" {
+		agentParam = r.URL.Query().Get("agent_id")
+	}
+	agentID, err := uuid.Parse(agentParam)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "X-Engram-Agent-Id header or agent_id query parameter must be an agent id")
+		return
+	}
+
+	input := service.ChatInput{AgentID: agentID, TenantID: tenant.ID}
+	if c := r.Header.Get("X-Engram-Conversation-Id"); c != "" {
+		conversationID, err := uuid.Parse(c)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid X-Engram-Conversation-Id")
+			return
+		}
+		input.ConversationID = &conversationID
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, chatMaxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	input.Request = body
+
+	result, err := h.svc.Complete(r.Context(), input)
+	if err != nil {
+		var upstream *domain.ChatUpstreamError
+		switch {
+		case errors.As(err, &upstream):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(upstream.StatusCode)
+			_, _ = w.Write(upstream.Body)
+		case errors.Is(err, service.ErrChatInvalid), errors.Is(err, service.ErrChatNoMessages), errors.Is(err, service.ErrChatStreaming):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		case errors.Is(err, service.ErrChatNotConfigured):
+			writeError(w, http.StatusNotImplemented, err.Error())
+		default:
+			writeError(w, http.StatusBadGateway, "chat completion failed")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Engram-Context-Items", strconv.Itoa(result.ContextItems))
+	if result.CaptureJob != nil {
+		w.Header().Set("X-Engram-Capture-Job", result.CaptureJob.ID.String())
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(result.Response)
+}
@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -123,6 +126,193 @@ func (h *CognitiveHandler) TriggerDecay(w http.ResponseWriter, r *http.Request)
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// GetForgettingForecast lists an agent's memories projected to be archived by
+// decay within ?days= (default 7), soonest first, so they can be verified or
+// reinforced before they fade.
+func (h *CognitiveHandler) GetForgettingForecast(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	days := service.DefaultForecastDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d <= 0 || d > service.MaxForecastDays {
+			writeError(w, http.StatusBadRequest, "days must be between 1 and 365")
+			return
+		}
+		days = d
+	}
+
+	limit := service.DefaultForecastLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	if !requireAgentInTenant(w, r, h.agentStore, agentID, tenant.ID) {
+		return
+	}
+
+	forecast, err := h.decayService.ForecastForgetting(r.Context(), agentID, tenant.ID, days, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to forecast forgetting")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, forecast)
+}
+
+const (
+	defaultConsolidationRunsLimit = 20
+	maxConsolidationRunsLimit     = 200
+)
+
+type consolidationRunsResponse struct {
+	Runs  []domain.ConsolidationRun `json:"runs"`
+	Total domain.LLMUsage           `json:"total"` // Summed over the returned runs
+}
+
+// ListConsolidationRuns returns the agent's consolidation history with the
+// model usage and estimated cost of each run.
+// GET /v1/agents/{id}/consolidation-runs?limit=
+func (h *CognitiveHandler) ListConsolidationRuns(w http.ResponseWriter, r *http.Request) {
+	if h.consolidationService == nil {
+		writeError(w, http.StatusServiceUnavailable, "consolidation service not available")
+		return
+	}
+
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	limit := defaultConsolidationRunsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 {
+			limit = min(l, maxConsolidationRunsLimit)
+		}
+	}
+
+	if !requireAgentInTenant(w, r, h.agentStore, agentID, tenant.ID) {
+		return
+	}
+
+	runs, err := h.consolidationService.ListRuns(r.Context(), agentID, tenant.ID, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list consolidation runs")
+		return
+	}
+
+	resp := consolidationRunsResponse{Runs: runs}
+	if resp.Runs == nil {
+		resp.Runs = []domain.ConsolidationRun{}
+	}
+	for _, run := range runs {
+		resp.Total.Add(run.Usage)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListDeadLetters returns the agent's episodes whose consolidation failed too
+// many times to keep retrying.
+// GET /v1/agents/{id}/dead-letters?limit=&offset=
+func (h *CognitiveHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.consolidationService == nil {
+		writeError(w, http.StatusServiceUnavailable, "consolidation service not available")
+		return
+	}
+
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	limit, offset := 50, 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, e := strconv.Atoi(v); e == nil {
+			limit = clampLimit(n)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, e := strconv.Atoi(v); e == nil && n > 0 {
+			offset = n
+		}
+	}
+
+	if !requireAgentInTenant(w, r, h.agentStore, agentID, tenant.ID) {
+		return
+	}
+
+	items, total, err := h.consolidationService.ListDeadLetters(r.Context(), agentID, tenant.ID, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list dead-lettered episodes")
+		return
+	}
+	if items == nil {
+		items = []domain.ConsolidationFailure{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// RequeueDeadLetter returns a dead-lettered episode to the consolidation
+// queue with its failure count reset.
+// POST /v1/episodes/{id}/requeue
+func (h *CognitiveHandler) RequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if h.consolidationService == nil {
+		writeError(w, http.StatusServiceUnavailable, "consolidation service not available")
+		return
+	}
+
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	episodeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid episode id")
+		return
+	}
+
+	if err := h.consolidationService.RequeueDeadLetter(r.Context(), episodeID, tenant.ID); err != nil {
+		if errors.Is(err, service.ErrDeadLetterNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to requeue episode")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"episode_id":           episodeID,
+		"consolidation_status": domain.ConsolidationProcessed,
+	})
+}
+
 type triggerConsolidationRequest struct {
 	AgentID string `json:"agent_id"`
 	Scope   string `json:"scope"` // "recent" or "full"
@@ -130,6 +320,7 @@ type triggerConsolidationRequest struct {
 
 type triggerConsolidationResponse struct {
 	EpisodesProcessed    int `json:"episodes_processed"`
+	EpisodesDeferred     int `json:"episodes_deferred"`
 	SemanticExtracted    int `json:"semantic_extracted"`
 	SemanticReinforced   int `json:"semantic_reinforced"`
 	ProceduresLearned    int `json:"procedures_learned"`
@@ -140,6 +331,8 @@ type triggerConsolidationResponse struct {
 	MemoriesArchived     int `json:"memories_archived"`
 	MemoriesMerged       int `json:"memories_merged"`
 	AssociationsCreated  int `json:"associations_created"`
+
+	Usage domain.LLMUsage `json:"usage"`
 }
 
 // TriggerConsolidation manually triggers the consolidation pipeline for an agent.
@@ -191,6 +384,7 @@ func (h *CognitiveHandler) TriggerConsolidation(w http.ResponseWriter, r *http.R
 
 	resp := triggerConsolidationResponse{
 		EpisodesProcessed:    result.EpisodesProcessed,
+		EpisodesDeferred:     result.EpisodesDeferred,
 		SemanticExtracted:    result.SemanticExtracted,
 		SemanticReinforced:   result.SemanticReinforced,
 		ProceduresLearned:    result.ProceduresLearned,
@@ -201,6 +395,7 @@ func (h *CognitiveHandler) TriggerConsolidation(w http.ResponseWriter, r *http.R
 		MemoriesArchived:     result.MemoriesArchived,
 		MemoriesMerged:       result.MemoriesMerged,
 		AssociationsCreated:  result.AssociationsCreated,
+		Usage:                result.Usage,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -219,6 +414,8 @@ type memoryHealthResponse struct {
 	UncertaintyAreas   []string `json:"uncertainty_areas"`
 	AverageConfidence  float32  `json:"average_confidence"`
 	OldestUnprocessed  *string  `json:"oldest_unprocessed,omitempty"`
+
+	Topics []domain.TopicStats `json:"topics,omitempty"`
 }
 
 // GetMemoryHealth returns statistics about the memory system health for an agent.
@@ -268,6 +465,7 @@ func (h *CognitiveHandler) GetMemoryHealth(w http.ResponseWriter, r *http.Reques
 		ContradictionCount: stats.ContradictionCount,
 		UncertaintyAreas:   stats.UncertaintyAreas,
 		AverageConfidence:  stats.AverageConfidence,
+		Topics:             stats.Topics,
 	}
 
 	if stats.OldestUnprocessed != nil {
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/config"
+)
+
+// ConfigHandler reloads the server's config file. Reloading affects every
+// tenant, so like the other deployment-wide admin endpoints it needs admin
+// scope plus the X-Setup-Token; with no token configured it is disabled.
+type ConfigHandler struct {
+	reload     func() (*config.ReloadResult, error)
+	setupToken string
+}
+
+func NewConfigHandler(reload func() (*config.ReloadResult, error), setupToken string) *ConfigHandler {
+	return &ConfigHandler{reload: reload, setupToken: setupToken}
+}
+
+// Reload handles POST /v1/admin/config/reload.
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	if h.setupToken == "" {
+		writeError(w, http.StatusServiceUnavailable, "config reload is not configured (ENGRAM_SETUP_TOKEN not set)")
+		return
+	}
+	if !tokenMatches(r.Header.Get("X-Setup-Token"), h.setupToken) {
+		writeError(w, http.StatusForbidden, "invalid setup token")
+		return
+	}
+	res, err := h.reload()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,13 +14,19 @@ import (
 )
 
 type ConsoleHandler struct {
-	svc *service.ConsoleService
+	svc      *service.ConsoleService
+	comparer *service.AgentCompareService
 }
 
 func NewConsoleHandler(svc *service.ConsoleService) *ConsoleHandler {
 	return &ConsoleHandler{svc: svc}
 }
 
+// SetCompareService enables GET /v1/agents/{id}/compare.
+func (h *ConsoleHandler) SetCompareService(comparer *service.AgentCompareService) {
+	h.comparer = comparer
+}
+
 // Dashboard handles GET /v1/agents/{id}/dashboard.
 func (h *ConsoleHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.TenantFromContext(r.Context())
@@ -116,6 +123,71 @@ func (h *ConsoleHandler) Snapshot(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, snap)
 }
 
+// Compare handles GET /v1/agents/{id}/compare?other_agent_id=&at=&other_at=&limit=.
+// Side A is the agent as of `at`, side B is other_agent_id (default: the same
+// agent) as of `other_at`; both instants default to now.
+func (h *ConsoleHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.comparer == nil {
+		writeError(w, http.StatusServiceUnavailable, "agent comparison is not configured")
+		return
+	}
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	q := r.URL.Query()
+	now := time.Now()
+	input := service.CompareInput{
+		TenantID: tenant.ID,
+		A:        service.CompareSide{AgentID: agentID, At: now},
+		B:        service.CompareSide{AgentID: agentID, At: now},
+	}
+	if v := q.Get("other_agent_id"); v != "" {
+		if input.B.AgentID, err = uuid.Parse(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid other_agent_id")
+			return
+		}
+	}
+	if v := q.Get("at"); v != "" {
+		if input.A.At, err = time.Parse(time.RFC3339, v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid 'at' (use RFC3339)")
+			return
+		}
+	}
+	if v := q.Get("other_at"); v != "" {
+		if input.B.At, err = time.Parse(time.RFC3339, v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid 'other_at' (use RFC3339)")
+			return
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			input.Limit = n
+		}
+	}
+
+	report, err := h.comparer.Compare(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCompareSameSide):
+			writeError(w, http.StatusBadRequest, "set other_agent_id, at or other_at: "+err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to compare agents")
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
 // Contradictions handles GET /v1/agents/{id}/contradictions.
 func (h *ConsoleHandler) Contradictions(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.TenantFromContext(r.Context())
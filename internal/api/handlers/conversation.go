@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 
 type ConversationHandler struct {
 	svc      *service.ConversationService
+	closer   *service.ConversationCloseService
 	anchors  *store.EntityStore
 	sessions *store.SessionStore
 }
@@ -24,6 +26,11 @@ func NewConversationHandler(svc *service.ConversationService, anchors *store.Ent
 	return &ConversationHandler{svc: svc, anchors: anchors, sessions: sessions}
 }
 
+// SetCloseService enables POST /v1/conversations/{id}/close.
+func (h *ConversationHandler) SetCloseService(closer *service.ConversationCloseService) {
+	h.closer = closer
+}
+
 type ingestRequest struct {
 	Messages  []domain.Message `json:"messages"`
 	EventDate string           `json:"event_date,omitempty"`
@@ -164,3 +171,66 @@ func (h *ConversationHandler) resolveBindings(r *http.Request, tenantID uuid.UUI
 	}
 	return anchorID, sessionID, nil
 }
+
+type closeConversationRequest struct {
+	Messages           []domain.Message `json:"messages,omitempty"`
+	Outcome            string           `json:"outcome,omitempty"`
+	OutcomeDescription string           `json:"outcome_description,omitempty"`
+}
+
+// Close handles POST /v1/conversations/{id}/close. It queues implicit
+// feedback detection, memory extraction, outcome recording and consolidation
+// for the conversation and returns 202 with the job. The body is optional:
+// messages default to the conversation's episodes and the outcome is inferred
+// from implicit feedback when not given.
+func (h *ConversationHandler) Close(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.closer == nil {
+		writeError(w, http.StatusServiceUnavailable, "conversation close not available")
+		return
+	}
+
+	conversationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid conversation id")
+		return
+	}
+
+	var req closeConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	input := service.CloseInput{
+		ConversationID:     conversationID,
+		TenantID:           tenant.ID,
+		Messages:           req.Messages,
+		OutcomeDescription: req.OutcomeDescription,
+	}
+	if req.Outcome != "" {
+		if !domain.ValidOutcomeType(req.Outcome) {
+			writeError(w, http.StatusBadRequest, "invalid outcome type (success, failure, neutral, unknown)")
+			return
+		}
+		outcome := domain.OutcomeType(req.Outcome)
+		input.Outcome = &outcome
+	}
+
+	job, err := h.closer.Close(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrConversationNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrJobQueueFull):
+			writeError(w, http.StatusServiceUnavailable, "job queue is full; retry later")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to close conversation")
+		}
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
@@ -15,43 +15,46 @@ import (
 )
 
 type EpisodeHandler struct {
-	svc *service.EpisodeService
+	svc       *service.EpisodeService
+	positions *service.EpisodePositionService
 }
 
 func NewEpisodeHandler(svc *service.EpisodeService) *EpisodeHandler {
 	return &EpisodeHandler{svc: svc}
 }
 
+// SetPositionService enables GET and PUT /v1/agents/{id}/episode-position.
+func (h *EpisodeHandler) SetPositionService(ps *service.EpisodePositionService) {
+	h.positions = ps
+}
+
 type createEpisodeRequest struct {
 	AgentID        string `json:"agent_id"`
 	RawContent     string `json:"raw_content"`
 	ConversationID string `json:"conversation_id,omitempty"`
 	OccurredAt     string `json:"occurred_at,omitempty"` // RFC3339 format
 	Outcome        string `json:"outcome,omitempty"`     // success, failure, neutral, unknown
+	// Attachments are images by URL; a caption left empty is written by the
+	// vision model when VISION_MODEL is set.
+	Attachments []domain.EpisodeAttachment `json:"attachments,omitempty"`
+	// Participants are who was present; mark the one who said raw_content
+	// as speaking so beliefs drawn from it are attributed to them.
+	Participants []domain.EpisodeParticipant `json:"participants,omitempty"`
+	// Environment is where the episode happened; recall leans toward
+	// episodes formed in the caller's environment.
+	Environment domain.Environment `json:"environment,omitempty"`
 }
 
-func (h *EpisodeHandler) Create(w http.ResponseWriter, r *http.Request) {
-	tenant := middleware.TenantFromContext(r.Context())
-	if tenant == nil {
-		writeError(w, http.StatusUnauthorized, "unauthorized")
-		return
-	}
-
-	var req createEpisodeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-
+// encodeInput validates the request's IDs, timestamp, outcome, attachments
+// and participants.
+func (req createEpisodeRequest) encodeInput(tenantID uuid.UUID) (service.EncodeInput, error) {
 	agentID, err := uuid.Parse(req.AgentID)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid agent_id")
-		return
+		return service.EncodeInput{}, errors.New("invalid agent_id")
 	}
-
 	input := service.EncodeInput{
 		AgentID:    agentID,
-		TenantID:   tenant.ID,
+		TenantID:   tenantID,
 		RawContent: req.RawContent,
 	}
 
@@ -59,8 +62,7 @@ func (h *EpisodeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if req.ConversationID != "" {
 		convID, err := uuid.Parse(req.ConversationID)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid conversation_id")
-			return
+			return service.EncodeInput{}, errors.New("invalid conversation_id")
 		}
 		input.ConversationID = &convID
 	}
@@ -69,8 +71,7 @@ func (h *EpisodeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if req.OccurredAt != "" {
 		t, err := time.Parse(time.RFC3339, req.OccurredAt)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid occurred_at format (use RFC3339)")
-			return
+			return service.EncodeInput{}, errors.New("invalid occurred_at format (use RFC3339)")
 		}
 		input.OccurredAt = t
 	}
@@ -78,18 +79,50 @@ func (h *EpisodeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	// Parse optional outcome
 	if req.Outcome != "" {
 		if !domain.ValidOutcomeType(req.Outcome) {
-			writeError(w, http.StatusBadRequest, "invalid outcome type")
-			return
+			return service.EncodeInput{}, errors.New("invalid outcome type")
 		}
 		outcome := domain.OutcomeType(req.Outcome)
 		input.Outcome = &outcome
 	}
 
+	if err := service.ValidateAttachments(req.Attachments); err != nil {
+		return service.EncodeInput{}, err
+	}
+	input.Attachments = req.Attachments
+	if err := service.ValidateParticipants(req.Participants); err != nil {
+		return service.EncodeInput{}, err
+	}
+	input.Participants = req.Participants
+	input.Environment = req.Environment
+	return input, nil
+}
+
+func (h *EpisodeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req createEpisodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	input, err := req.encodeInput(tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	episode, err := h.svc.Encode(r.Context(), input)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrEpisodeContentEmpty),
-			errors.Is(err, service.ErrEpisodeAgentIDMissing):
+			errors.Is(err, service.ErrEpisodeAgentIDMissing),
+			errors.Is(err, service.ErrInvalidAttachment),
+			errors.Is(err, service.ErrInvalidParticipant):
 			writeError(w, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrAgentNotFound):
 			writeError(w, http.StatusBadRequest, "agent not found")
@@ -175,6 +208,7 @@ func (h *EpisodeHandler) Recall(w http.ResponseWriter, r *http.Request) {
 		}
 		opts.MinImportance = float32(minImp)
 	}
+	opts.Environment = environmentFromQuery(r)
 
 	// Parse optional time range
 	if startStr := r.URL.Query().Get("start_time"); startStr != "" {
@@ -214,6 +248,76 @@ func (h *EpisodeHandler) Recall(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+type causalChainsResponse struct {
+	Chains []domain.CausalChain `json:"chains"`
+	Count  int                  `json:"count"`
+}
+
+// CausalChains assembles cause-and-effect chains across the agent's
+// episodes matching a query, for post-mortems of an outcome.
+func (h *EpisodeHandler) CausalChains(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentIDStr := r.URL.Query().Get("agent_id")
+	if agentIDStr == "" {
+		writeError(w, http.StatusBadRequest, "agent_id is required")
+		return
+	}
+	agentID, err := uuid.Parse(agentIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	opts := service.CausalChainOpts{Query: r.URL.Query().Get("query")}
+	if opts.Query == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+	if outcome := r.URL.Query().Get("outcome"); outcome != "" {
+		if !domain.ValidOutcomeType(outcome) {
+			writeError(w, http.StatusBadRequest, "invalid outcome type (success, failure, neutral, unknown)")
+			return
+		}
+		opts.Outcome = domain.OutcomeType(outcome)
+	}
+	if depthStr := r.URL.Query().Get("max_depth"); depthStr != "" {
+		depth, err := strconv.Atoi(depthStr)
+		if err != nil || depth <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid max_depth")
+			return
+		}
+		opts.MaxDepth = depth
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		opts.Limit = limit
+	}
+
+	chains, err := h.svc.CausalChains(r.Context(), agentID, tenant.ID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		case errors.Is(err, service.ErrCausalQueryEmpty), errors.Is(err, service.ErrInvalidOutcomeType):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to assemble causal chains")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, causalChainsResponse{Chains: chains, Count: len(chains)})
+}
+
 type recordOutcomeRequest struct {
 	Outcome     string `json:"outcome"`
 	Description string `json:"description,omitempty"`
@@ -300,3 +404,112 @@ func (h *EpisodeHandler) GetAssociations(w http.ResponseWriter, r *http.Request)
 		"count":        len(associations),
 	})
 }
+
+// Replay returns a conversation's episode timeline with derived memories,
+// associations and outcomes interleaved.
+// GET /v1/conversations/{id}/replay
+func (h *EpisodeHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid conversation id")
+		return
+	}
+
+	replay, err := h.svc.Replay(r.Context(), id, tenant.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrConversationNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to replay conversation")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, replay)
+}
+
+type updatePositionSettingsRequest struct {
+	PrimacyWeight float32 `json:"primacy_weight"`
+	RecencyWeight float32 `json:"recency_weight"`
+}
+
+// GetPositionSettings returns the agent's primacy and recency weights.
+// GET /v1/agents/{id}/episode-position
+func (h *EpisodeHandler) GetPositionSettings(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.positions == nil {
+		writeError(w, http.StatusServiceUnavailable, "episode position weighting is not configured")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	settings, err := h.positions.GetSettings(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get episode position settings")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdatePositionSettings sets the agent's primacy and recency weights; 0
+// turns an effect off.
+// PUT /v1/agents/{id}/episode-position
+func (h *EpisodeHandler) UpdatePositionSettings(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.positions == nil {
+		writeError(w, http.StatusServiceUnavailable, "episode position weighting is not configured")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	var req updatePositionSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	settings := &domain.EpisodePositionSettings{
+		AgentID:       agentID,
+		TenantID:      tenant.ID,
+		PrimacyWeight: req.PrimacyWeight,
+		RecencyWeight: req.RecencyWeight,
+	}
+	if err := h.positions.UpdateSettings(r.Context(), settings); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPositionWeight):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to update episode position settings")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/google/uuid"
+)
+
+// transcriptMaxAudioBytes is OpenAI's upload limit for transcription.
+const transcriptMaxAudioBytes = 25 << 20
+
+type transcriptRequest struct {
+	AgentID        string                     `json:"agent_id"`
+	ConversationID string                     `json:"conversation_id,omitempty"`
+	StartedAt      string                     `json:"started_at,omitempty"` // RFC3339
+	Speaker        string                     `json:"speaker,omitempty"`
+	Segments       []domain.TranscriptSegment `json:"segments"`
+	// Participants gives speakers roles; unlisted speakers are third parties.
+	Participants []domain.EpisodeParticipant `json:"participants,omitempty"`
+}
+
+type transcriptResponse struct {
+	ConversationID uuid.UUID         `json:"conversation_id"`
+	Episodes       []*domain.Episode `json:"episodes"`
+	Count          int               `json:"count"`
+}
+
+// IngestTranscript handles POST /v1/episodes/transcript — store a conversation
+// transcript as speaker-attributed episodes, one per turn. The body is either
+// JSON with pre-transcribed segments, or multipart/form-data with the
+// recording in an "audio" file part and the other fields as form values
+// (participants as a JSON array); audio needs TRANSCRIPTION_MODEL.
+func (h *EpisodeHandler) IngestTranscript(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req transcriptRequest
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	audio := mediaType == "multipart/form-data"
+	if audio {
+		r.Body = http.MaxBytesReader(w, r.Body, transcriptMaxAudioBytes+1<<20)
+		if err := r.ParseMultipartForm(8 << 20); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid multipart body (audio is limited to 25 MiB)")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+		req.AgentID = r.FormValue("agent_id")
+		req.ConversationID = r.FormValue("conversation_id")
+		req.StartedAt = r.FormValue("started_at")
+		req.Speaker = r.FormValue("speaker")
+		if v := r.FormValue("participants"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Participants); err != nil {
+				writeError(w, http.StatusBadRequest, "participants must be a JSON array")
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	agentID, err := uuid.Parse(req.AgentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+	input := service.TranscriptInput{
+		AgentID:      agentID,
+		TenantID:     tenant.ID,
+		Speaker:      req.Speaker,
+		Segments:     req.Segments,
+		Participants: req.Participants,
+	}
+	if req.ConversationID != "" {
+		convID, err := uuid.Parse(req.ConversationID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid conversation_id")
+			return
+		}
+		input.ConversationID = &convID
+	}
+	if req.StartedAt != "" {
+		t, err := time.Parse(time.RFC3339, req.StartedAt)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid started_at format (use RFC3339)")
+			return
+		}
+		input.StartedAt = t
+	}
+
+	if audio {
+		file, hdr, err := r.FormFile("audio")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "audio file is required")
+			return
+		}
+		defer file.Close()
+		if hdr.Size > transcriptMaxAudioBytes {
+			writeError(w, http.StatusRequestEntityTooLarge, "audio is limited to 25 MiB")
+			return
+		}
+		input.Segments, err = h.svc.TranscribeAudio(r.Context(), file, hdr.Filename)
+		if err != nil {
+			if errors.Is(err, service.ErrTranscriptionDisabled) {
+				writeError(w, http.StatusServiceUnavailable, err.Error())
+				return
+			}
+			writeError(w, http.StatusBadGateway, "transcription failed")
+			return
+		}
+	}
+
+	convID, episodes, err := h.svc.IngestTranscript(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTranscriptEmpty),
+			errors.Is(err, service.ErrInvalidTranscriptSegment),
+			errors.Is(err, service.ErrInvalidParticipant):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusBadRequest, "agent not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to store transcript")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, transcriptResponse{
+		ConversationID: *convID,
+		Episodes:       episodes,
+		Count:          len(episodes),
+	})
+}
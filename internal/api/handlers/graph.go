@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
@@ -218,9 +219,11 @@ type traverseResponse struct {
 }
 
 type pathResponse struct {
-	Path          []uuid.UUID `json:"path"`
-	PathLength    int         `json:"path_length"`
-	TotalStrength float32     `json:"total_strength"`
+	Path []uuid.UUID `json:"path"`
+	// Relations are the relation types of the path's edges, in order.
+	Relations     []domain.RelationType `json:"relations"`
+	PathLength    int                   `json:"path_length"`
+	TotalStrength float32               `json:"total_strength"`
 }
 
 type graphMemoryResponse struct {
@@ -269,11 +272,10 @@ func (h *GraphHandler) Traverse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var relationTypes []domain.RelationType
-	for _, rt := range req.RelationTypes {
-		if domain.ValidRelationType(rt) {
-			relationTypes = append(relationTypes, domain.RelationType(rt))
-		}
+	relationTypes, err := domain.ParseRelationTypes(req.RelationTypes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "relation_types: "+err.Error())
+		return
 	}
 
 	// Simple BFS traversal
@@ -282,8 +284,9 @@ func (h *GraphHandler) Traverse(w http.ResponseWriter, r *http.Request) {
 	memoryIDs := make(map[uuid.UUID]bool)
 
 	type queueItem struct {
-		path     []uuid.UUID
-		strength float32
+		path      []uuid.UUID
+		relations []domain.RelationType
+		strength  float32
 	}
 
 	queue := make([]queueItem, 0)
@@ -323,18 +326,25 @@ func (h *GraphHandler) Traverse(w http.ResponseWriter, r *http.Request) {
 				newPath := make([]uuid.UUID, len(item.path)+1)
 				copy(newPath, item.path)
 				newPath[len(item.path)] = targetID
+				newRelations := append(append([]domain.RelationType(nil), item.relations...), edge.RelationType)
 
+				// Each hop decays by its relation type, as in graph recall
 				newStrength := item.strength * edge.Strength
+				if m, ok := domain.RelationDecayMultipliers[edge.RelationType]; ok {
+					newStrength *= float32(m)
+				}
 
 				nextQueue = append(nextQueue, queueItem{
-					path:     newPath,
-					strength: newStrength,
+					path:      newPath,
+					relations: newRelations,
+					strength:  newStrength,
 				})
 
 				memoryIDs[targetID] = true
 
 				paths = append(paths, pathResponse{
 					Path:          newPath,
+					Relations:     newRelations,
 					PathLength:    len(newPath),
 					TotalStrength: newStrength,
 				})
@@ -350,6 +360,36 @@ func (h *GraphHandler) Traverse(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type relationTypeResponse struct {
+	Type      string  `json:"type"`
+	Decay     float64 `json:"decay"`
+	Symmetric bool    `json:"symmetric"`
+}
+
+type relationTypesResponse struct {
+	// Relations are the graph edge types, filterable in recall and traverse.
+	Relations []relationTypeResponse `json:"relations"`
+	// Associations are the cross-memory association types, filterable in
+	// working memory activation.
+	Associations []relationTypeResponse `json:"associations"`
+}
+
+// RelationTypes lists the relation and association types with the factor
+// each multiplies activation by per hop.
+// GET /v1/graph/relation-types
+func (h *GraphHandler) RelationTypes(w http.ResponseWriter, r *http.Request) {
+	resp := relationTypesResponse{}
+	for rt, decay := range domain.RelationDecayMultipliers {
+		resp.Relations = append(resp.Relations, relationTypeResponse{Type: string(rt), Decay: decay, Symmetric: domain.SymmetricRelations[rt]})
+	}
+	for at, decay := range domain.AssociationDecayMultipliers {
+		resp.Associations = append(resp.Associations, relationTypeResponse{Type: at, Decay: decay})
+	}
+	sort.Slice(resp.Relations, func(i, j int) bool { return resp.Relations[i].Type < resp.Relations[j].Type })
+	sort.Slice(resp.Associations, func(i, j int) bool { return resp.Associations[i].Type < resp.Associations[j].Type })
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func (h *GraphHandler) HybridRecall(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.TenantFromContext(r.Context())
 	if tenant == nil {
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/google/uuid"
+)
+
+// maxIngestItems bounds one POST /v1/ingest body.
+const maxIngestItems = 1000
+
+type IngestHandler struct {
+	buf    *service.IngestBuffer
+	agents domain.AgentStore
+}
+
+func NewIngestHandler(buf *service.IngestBuffer, agents domain.AgentStore) *IngestHandler {
+	return &IngestHandler{buf: buf, agents: agents}
+}
+
+type ingestMemoryRequest struct {
+	AgentID    string         `json:"agent_id"`
+	Content    string         `json:"content"`
+	Type       string         `json:"type,omitempty"`
+	Source     string         `json:"source,omitempty"`
+	Provenance string         `json:"provenance,omitempty"`
+	Confidence float32        `json:"confidence,omitempty"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	EventDate  string         `json:"event_date,omitempty"`
+}
+
+type bulkIngestRequest struct {
+	Episodes []createEpisodeRequest `json:"episodes"`
+	Memories []ingestMemoryRequest  `json:"memories"`
+}
+
+// Ingest handles POST /v1/ingest — queue a burst of episodes and memories for
+// batched writing and return 202 at once. The whole body is validated first;
+// when the buffer can't take all of it, nothing is queued and the response
+// is 503 with Retry-After.
+func (h *IngestHandler) Ingest(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req bulkIngestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	n := len(req.Episodes) + len(req.Memories)
+	if n == 0 {
+		writeError(w, http.StatusBadRequest, "episodes or memories are required")
+		return
+	}
+	if n > maxIngestItems {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d items per request", maxIngestItems))
+		return
+	}
+
+	items := make([]service.IngestItem, 0, n)
+	agents := map[uuid.UUID]bool{}
+	for i, er := range req.Episodes {
+		input, err := er.encodeInput(tenant.ID)
+		if err == nil && input.RawContent == "" {
+			err = service.ErrEpisodeContentEmpty
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("episodes[%d]: %v", i, err))
+			return
+		}
+		agents[input.AgentID] = true
+		items = append(items, service.IngestItem{Episode: &input})
+	}
+	for i, mr := range req.Memories {
+		m, err := mr.memory(tenant.ID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("memories[%d]: %v", i, err))
+			return
+		}
+		agents[m.AgentID] = true
+		items = append(items, service.IngestItem{Memory: m})
+	}
+	for agentID := range agents {
+		if !requireAgentInTenant(w, r, h.agents, agentID, tenant.ID) {
+			return
+		}
+	}
+
+	if err := h.buf.Submit(r.Context(), items); err != nil {
+		if errors.Is(err, service.ErrIngestBufferFull) || errors.Is(err, service.ErrIngestBufferStopped) {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to queue writes")
+		return
+	}
+	stats := h.buf.Stats()
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"accepted": n,
+		"queued":   stats.Queued,
+		"capacity": stats.Capacity,
+	})
+}
+
+// Stats handles GET /v1/ingest — the buffer's fill and write counters.
+func (h *IngestHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.buf.Stats())
+}
+
+// memory validates the request the way POST /v1/memories does.
+func (mr ingestMemoryRequest) memory(tenantID uuid.UUID) (*domain.Memory, error) {
+	agentID, err := uuid.Parse(mr.AgentID)
+	if err != nil {
+		return nil, errors.New("invalid agent_id")
+	}
+	if mr.Content == "" {
+		return nil, service.ErrMemoryContentEmpty
+	}
+	if mr.Type != "" && !domain.ValidMemoryType(mr.Type) {
+		return nil, service.ErrInvalidMemoryType
+	}
+	m := &domain.Memory{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       domain.MemoryType(mr.Type),
+		Content:    mr.Content,
+		Source:     mr.Source,
+		Confidence: mr.Confidence,
+		Metadata:   mr.Metadata,
+	}
+	if domain.ValidProvenance(mr.Provenance) {
+		m.Provenance = domain.Provenance(mr.Provenance)
+	} else if domain.ValidProvenance(mr.Source) {
+		m.Provenance = domain.Provenance(mr.Source)
+	}
+	if mr.EventDate != "" {
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, mr.EventDate); err == nil {
+				m.EventDate = &t
+				break
+			}
+		}
+	}
+	return m, nil
+}
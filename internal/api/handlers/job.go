@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// JobHandler reports on background jobs such as async extractions.
+type JobHandler struct {
+	pool *service.JobPool
+}
+
+func NewJobHandler(pool *service.JobPool) *JobHandler {
+	return &JobHandler{pool: pool}
+}
+
+// Get handles GET /v1/jobs/{id}.
+func (h *JobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := h.pool.Get(id, tenant.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrJobNotFound) {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get job")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/google/uuid"
+)
+
+// KnowledgeHandler answers "what do you know about X" with a cited narrative.
+type KnowledgeHandler struct {
+	svc *service.KnowledgeService
+}
+
+func NewKnowledgeHandler(svc *service.KnowledgeService) *KnowledgeHandler {
+	return &KnowledgeHandler{svc: svc}
+}
+
+// Describe handles GET /v1/cognitive/knowledge?agent_id=&topic=.
+func (h *KnowledgeHandler) Describe(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(r.URL.Query().Get("agent_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "agent_id query parameter must be an agent id")
+		return
+	}
+
+	narrative, err := h.svc.Describe(r.Context(), agentID, tenant.ID, r.URL.Query().Get("topic"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrKnowledgeTopicEmpty), errors.Is(err, service.ErrKnowledgeTopicTooLong):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrKnowledgeNotConfigured):
+			writeError(w, http.StatusNotImplemented, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to describe knowledge")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, narrative)
+}
@@ -22,12 +22,18 @@ type MemoryHandler struct {
 	hybridSvc *service.HybridRecallService
 	anchors   *store.EntityStore
 	sessions  *store.SessionStore
+	recallLog *service.RecallLogService
 }
 
 func NewMemoryHandler(svc *service.MemoryService, hybridSvc *service.HybridRecallService, anchors *store.EntityStore, sessions *store.SessionStore) *MemoryHandler {
 	return &MemoryHandler{svc: svc, hybridSvc: hybridSvc, anchors: anchors, sessions: sessions}
 }
 
+// SetRecallLogger enables sampled logging of recall rankings (optional).
+func (h *MemoryHandler) SetRecallLogger(l *service.RecallLogService) {
+	h.recallLog = l
+}
+
 type createMemoryRequest struct {
 	AgentID    string         `json:"agent_id"`
 	Content    string         `json:"content"`
@@ -44,6 +50,16 @@ type createMemoryRequest struct {
 	// SessionID binds this trace to a conversation (short-term, binding='session').
 	SessionID  string `json:"session_id,omitempty"`
 	Quarantine bool   `json:"quarantine,omitempty"`
+	// Subject is who or what the memory is about: "user", "assistant" or a
+	// name. Beliefs are only reinforced or contradicted by beliefs about the
+	// same subject.
+	Subject string `json:"subject,omitempty"`
+	// Topic is the area the memory belongs to (e.g. "diet"); health and
+	// uncertainty reports are broken down by it.
+	Topic string `json:"topic,omitempty"`
+	// Environment is where the memory was formed (channel, device, locale,
+	// surface); stored under metadata.environment.
+	Environment domain.Environment `json:"environment,omitempty"`
 }
 
 type createMemoryResponse struct {
@@ -115,8 +131,10 @@ func (h *MemoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Content:    req.Content,
 		Source:     req.Source,
 		Confidence: req.Confidence,
-		Metadata:   req.Metadata,
+		Metadata:   domain.WithEnvironment(req.Metadata, req.Environment),
 		Quarantine: req.Quarantine,
+		Subject:    domain.NormalizeSubject(req.Subject),
+		Topic:      req.Topic,
 	}
 	// Honor provenance (who originated the belief). Prefer an explicit provenance;
 	// otherwise accept a `source` that is itself a provenance value (e.g. "user").
@@ -223,7 +241,7 @@ func (h *MemoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tier := domain.ComputeTier(float64(memory.Confidence))
+	tier := memory.CurrentTier()
 	writeJSON(w, http.StatusOK, getMemoryResponse{
 		Memory:     memory,
 		Tier:       tier,
@@ -396,6 +414,24 @@ type recallResponse struct {
 	Memories []memoryWithDecayStatus `json:"memories"`
 	Query    string                  `json:"query"`
 	Count    int                     `json:"count"`
+	Control  bool                    `json:"control,omitempty"`
+}
+
+func newMemoryWithDecayStatus(sm domain.ScoredMemory, breakdown *service.ScoreBreakdown) memoryWithDecayStatus {
+	return memoryWithDecayStatus{
+		MemoryWithScore: domain.MemoryWithScore{
+			Memory: sm.Memory,
+			Score:  sm.FinalScore,
+		},
+		DecayStatus:    calculateDecayStatus(sm.Confidence),
+		Tier:           domain.ComputeTier(float64(sm.Confidence)),
+		TierReason:     domain.TierReason(float64(sm.Confidence)),
+		VectorScore:    sm.VectorScore,
+		GraphScore:     sm.GraphScore,
+		GraphPath:      sm.GraphPath,
+		PathLength:     sm.PathLength,
+		ScoreBreakdown: breakdown,
+	}
 }
 
 func calculateDecayStatus(confidence float32) string {
@@ -472,7 +508,7 @@ func (h *MemoryHandler) Recall(w http.ResponseWriter, r *http.Request) {
 
 	if typeStr := r.URL.Query().Get("type"); typeStr != "" {
 		mt := domain.MemoryType(typeStr)
-		if !domain.ValidMemoryType(typeStr) {
+		if !domain.ValidRecallMemoryType(typeStr) {
 			writeError(w, http.StatusBadRequest, "invalid type parameter")
 			return
 		}
@@ -502,6 +538,19 @@ func (h *MemoryHandler) Recall(w http.ResponseWriter, r *http.Request) {
 		req.IncludeTiers = parseIncludeTiers(tiersStr)
 	}
 
+	if rtStr := r.URL.Query().Get("relation_types"); rtStr != "" {
+		relationTypes, err := domain.ParseRelationTypes(splitList(rtStr))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "relation_types: "+err.Error())
+			return
+		}
+		req.RelationTypes = relationTypes
+	}
+
+	if subject := r.URL.Query().Get("subject"); subject != "" {
+		req.Subject = domain.NormalizeSubject(subject)
+	}
+
 	if rbStr := r.URL.Query().Get("recency_boost"); rbStr != "" {
 		if rb, err := strconv.ParseFloat(rbStr, 32); err == nil && rb >= 0 && rb <= 1 {
 			req.RecencyBoost = float32(rb)
@@ -525,7 +574,7 @@ func (h *MemoryHandler) Recall(w http.ResponseWriter, r *http.Request) {
 
 	if modeStr := r.URL.Query().Get("mode"); modeStr != "" {
 		switch domain.RecallMode(modeStr) {
-		case domain.RecallModeSimilarity, domain.RecallModeExhaustive, domain.RecallModeHybrid:
+		case domain.RecallModeSimilarity, domain.RecallModeExhaustive, domain.RecallModeHybrid, domain.RecallModeGraph:
 			req.Mode = domain.RecallMode(modeStr)
 		}
 	}
@@ -539,29 +588,59 @@ func (h *MemoryHandler) Recall(w http.ResponseWriter, r *http.Request) {
 			req.MaxResults = mr
 		}
 	}
+	if accStr := r.URL.Query().Get("accuracy"); accStr != "" {
+		acc, err := strconv.ParseFloat(accStr, 32)
+		if err != nil || !domain.ValidRecallAccuracy(float32(acc)) {
+			writeError(w, http.StatusBadRequest, "accuracy must be between 0 and 1")
+			return
+		}
+		req.Accuracy = float32(acc)
+	}
+	req.ExpandSummaries = r.URL.Query().Get("expand_summaries") == "true"
+	req.NoInterference = r.URL.Query().Get("no_interference") == "true"
+	req.Environment = environmentFromQuery(r)
+	// Each window value is a message already in the caller's context;
+	// memories repeating one verbatim are left out.
+	for _, text := range r.URL.Query()["window"] {
+		req.Conversation = append(req.Conversation, domain.Message{Content: text})
+	}
+	req.Control = r.URL.Query().Get("control") == "true"
+	explain := r.URL.Query().Get("explain") == "true"
+	sampled := h.recallLog != nil && (req.Control || h.recallLog.Sampled())
 
+	start := time.Now()
 	results, err := h.hybridSvc.Recall(r.Context(), req)
 	if err != nil {
 		handleRecallError(w, err)
 		return
 	}
+	latency := time.Since(start)
 
-	memoriesWithStatus := make([]memoryWithDecayStatus, 0, len(results))
-	for _, sm := range results {
-		tier := domain.ComputeTier(float64(sm.Confidence))
-		memoriesWithStatus = append(memoriesWithStatus, memoryWithDecayStatus{
-			MemoryWithScore: domain.MemoryWithScore{
-				Memory: sm.Memory,
-				Score:  sm.FinalScore,
-			},
-			DecayStatus: calculateDecayStatus(sm.Confidence),
-			Tier:        tier,
-			TierReason:  domain.TierReason(float64(sm.Confidence)),
-			VectorScore: sm.VectorScore,
-			GraphScore:  sm.GraphScore,
-			GraphPath:   sm.GraphPath,
-			PathLength:  sm.PathLength,
+	var breakdowns []*service.ScoreBreakdown
+	if explain || sampled {
+		breakdowns = h.svc.ExplainHybridScores(req, results)
+	}
+	if sampled {
+		h.recallLog.Record(req, results, breakdowns, latency)
+	}
+
+	// Control ("memory off") recall: the ranking is logged, the agent gets nothing
+	if req.Control {
+		writeJSON(w, http.StatusOK, recallResponse{
+			Memories: []memoryWithDecayStatus{},
+			Query:    query,
+			Control:  true,
 		})
+		return
+	}
+
+	memoriesWithStatus := make([]memoryWithDecayStatus, 0, len(results))
+	for i, sm := range results {
+		var breakdown *service.ScoreBreakdown
+		if explain {
+			breakdown = breakdowns[i]
+		}
+		memoriesWithStatus = append(memoriesWithStatus, newMemoryWithDecayStatus(sm, breakdown))
 	}
 
 	writeJSON(w, http.StatusOK, recallResponse{
@@ -571,10 +650,25 @@ func (h *MemoryHandler) Recall(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// environmentFromQuery reads the caller's environment from the channel,
+// device, locale and surface query parameters.
+func environmentFromQuery(r *http.Request) domain.Environment {
+	q := r.URL.Query()
+	return domain.Environment{
+		Channel: q.Get("channel"),
+		Device:  q.Get("device"),
+		Locale:  q.Get("locale"),
+		Surface: q.Get("surface"),
+	}.Normalize()
+}
+
 type extractRequest struct {
 	AgentID      string           `json:"agent_id"`
 	Conversation []domain.Message `json:"conversation"`
 	AutoStore    bool             `json:"auto_store"`
+	// Async queues the extraction and returns 202 with a job to poll at
+	// GET /v1/jobs/{id} instead of waiting for the LLM.
+	Async bool `json:"async"`
 }
 
 type extractResponse struct {
@@ -588,6 +682,8 @@ func handleRecallError(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusBadRequest, err.Error())
 	case errors.Is(err, service.ErrRecallAgentIDMissing):
 		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrRecallBatchTooLarge):
+		writeError(w, http.StatusBadRequest, err.Error())
 	default:
 		writeError(w, http.StatusInternalServerError, "failed to recall memories")
 	}
@@ -617,6 +713,20 @@ func (h *MemoryHandler) Extract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Async {
+		job, err := h.svc.ExtractAsync(r.Context(), agentID, tenant.ID, req.Conversation, req.AutoStore)
+		if err != nil {
+			if errors.Is(err, service.ErrJobQueueFull) {
+				writeError(w, http.StatusServiceUnavailable, "extraction queue is full; retry later")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to queue extraction")
+			return
+		}
+		writeJSON(w, http.StatusAccepted, job)
+		return
+	}
+
 	results, err := h.svc.Extract(r.Context(), agentID, tenant.ID, req.Conversation, req.AutoStore)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to extract memories")
@@ -633,6 +743,15 @@ func (h *MemoryHandler) Extract(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// splitList splits a comma-separated query value, trimming each item.
+func splitList(s string) []string {
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
 func parseIncludeTiers(s string) []domain.MemoryTier {
 	var tiers []domain.MemoryTier
 	for _, part := range strings.Split(s, ",") {
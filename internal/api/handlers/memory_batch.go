@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// batchRecallRequest is a set of queries recalled with the same options,
+// which mirror the query parameters of GET /v1/memories/recall.
+type batchRecallRequest struct {
+	Queries          []string           `json:"queries"`
+	AgentID          string             `json:"agent_id,omitempty"`
+	AnchorID         string             `json:"anchor_id,omitempty"`
+	AnchorExternalID string             `json:"anchor_external_id,omitempty"`
+	SessionID        string             `json:"session_id,omitempty"`
+	TopK             int                `json:"top_k,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	MinConfidence    float32            `json:"min_confidence,omitempty"`
+	GraphWeight      *float64           `json:"graph_weight,omitempty"`
+	MaxHops          int                `json:"max_hops,omitempty"`
+	IncludeTiers     []string           `json:"include_tiers,omitempty"`
+	RelationTypes    []string           `json:"relation_types,omitempty"`
+	Subject          string             `json:"subject,omitempty"`
+	RecencyBoost     float32            `json:"recency_boost,omitempty"`
+	Mode             domain.RecallMode  `json:"mode,omitempty"`
+	MinSimilarity    float32            `json:"min_similarity,omitempty"`
+	MaxResults       int                `json:"max_results,omitempty"`
+	Accuracy         float32            `json:"accuracy,omitempty"`
+	ExpandSummaries  bool               `json:"expand_summaries,omitempty"`
+	NoInterference   bool               `json:"no_interference,omitempty"`
+	Environment      domain.Environment `json:"environment,omitempty"`
+	// Window holds messages already in the caller's context; memories
+	// repeating one verbatim are left out.
+	Window []string `json:"window,omitempty"`
+}
+
+type batchRecallGroup struct {
+	Query        string                  `json:"query"`
+	Memories     []memoryWithDecayStatus `json:"memories"`
+	Count        int                     `json:"count"`
+	Deduplicated int                     `json:"deduplicated,omitempty"`
+}
+
+type batchRecallResponse struct {
+	Results []batchRecallGroup `json:"results"`
+	Count   int                `json:"count"`
+}
+
+// RecallBatch recalls for several queries at once, e.g. the sub-questions of
+// a decomposed task. The queries are embedded together and share every
+// option; results are grouped per query, in order, and a memory matched by
+// several queries is returned only under the one that ranked it highest.
+// POST /v1/memories/recall/batch
+func (h *MemoryHandler) RecallBatch(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body batchRecallRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(body.Queries) == 0 {
+		writeError(w, http.StatusBadRequest, "queries is required")
+		return
+	}
+
+	anchorID, err := h.resolveAnchor(r.Context(), tenant.ID, body.AnchorID, body.AnchorExternalID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var sessionID *uuid.UUID
+	if body.SessionID != "" {
+		sid, err := uuid.Parse(body.SessionID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid session_id")
+			return
+		}
+		sessionID = &sid
+	}
+
+	var agentID uuid.UUID
+	if body.AgentID != "" {
+		agentID, err = uuid.Parse(body.AgentID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid agent_id")
+			return
+		}
+	} else if anchorID == nil && sessionID == nil {
+		writeError(w, http.StatusBadRequest, "agent_id is required unless anchor_id/anchor_external_id or session_id is provided")
+		return
+	}
+
+	req := domain.HybridRecallRequest{
+		AgentID:         agentID,
+		AnchorID:        anchorID,
+		SessionID:       sessionID,
+		TenantID:        tenant.ID,
+		TopK:            10,
+		VectorWeight:    0.6,
+		GraphWeight:     0.4,
+		MaxGraphHops:    2,
+		UseGraph:        true,
+		MinConfidence:   body.MinConfidence,
+		ExpandSummaries: body.ExpandSummaries,
+		NoInterference:  body.NoInterference,
+		Environment:     body.Environment.Normalize(),
+	}
+	if body.TopK > 0 {
+		req.TopK = body.TopK
+	}
+	if body.Type != "" {
+		if !domain.ValidRecallMemoryType(body.Type) {
+			writeError(w, http.StatusBadRequest, "invalid type")
+			return
+		}
+		mt := domain.MemoryType(body.Type)
+		req.MemoryType = &mt
+	}
+	if gw := body.GraphWeight; gw != nil && *gw >= 0 && *gw <= 1 {
+		req.GraphWeight = *gw
+		req.VectorWeight = 1 - *gw
+	}
+	if body.MaxHops > 0 && body.MaxHops <= 5 {
+		req.MaxGraphHops = body.MaxHops
+	}
+	for _, t := range body.IncludeTiers {
+		if domain.ValidTier(t) {
+			req.IncludeTiers = append(req.IncludeTiers, domain.MemoryTier(t))
+		}
+	}
+	relationTypes, err := domain.ParseRelationTypes(body.RelationTypes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "relation_types: "+err.Error())
+		return
+	}
+	req.RelationTypes = relationTypes
+	if body.Subject != "" {
+		req.Subject = domain.NormalizeSubject(body.Subject)
+	}
+	if body.RecencyBoost >= 0 && body.RecencyBoost <= 1 {
+		req.RecencyBoost = body.RecencyBoost
+	}
+	switch body.Mode {
+	case domain.RecallModeSimilarity, domain.RecallModeExhaustive, domain.RecallModeHybrid, domain.RecallModeGraph:
+		req.Mode = body.Mode
+	}
+	if body.MinSimilarity >= 0 && body.MinSimilarity <= 1 {
+		req.MinSimilarity = body.MinSimilarity
+	}
+	if body.MaxResults > 0 {
+		req.MaxResults = body.MaxResults
+	}
+	if !domain.ValidRecallAccuracy(body.Accuracy) {
+		writeError(w, http.StatusBadRequest, "accuracy must be between 0 and 1")
+		return
+	}
+	req.Accuracy = body.Accuracy
+	for _, text := range body.Window {
+		req.Conversation = append(req.Conversation, domain.Message{Content: text})
+	}
+
+	groups, err := h.hybridSvc.RecallBatch(r.Context(), body.Queries, req)
+	if err != nil {
+		handleRecallError(w, err)
+		return
+	}
+	middleware.CountRecalls(r.Context(), len(body.Queries))
+
+	resp := batchRecallResponse{Results: make([]batchRecallGroup, 0, len(groups))}
+	for _, g := range groups {
+		memories := make([]memoryWithDecayStatus, 0, len(g.Memories))
+		for _, sm := range g.Memories {
+			memories = append(memories, newMemoryWithDecayStatus(sm, nil))
+		}
+		resp.Results = append(resp.Results, batchRecallGroup{
+			Query:        g.Query,
+			Memories:     memories,
+			Count:        len(memories),
+			Deduplicated: g.Deduplicated,
+		})
+		resp.Count += len(memories)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/service"
 	"github.com/google/uuid"
 )
@@ -48,6 +49,9 @@ type uncertaintyReportResponse struct {
 	LowConfidenceBeliefs []memoryResponse `json:"low_confidence_beliefs"`
 	StaleBeliefs         []memoryResponse `json:"stale_beliefs"`
 	Recommendation       string           `json:"recommendation"`
+
+	Topics    []service.TopicUncertainty    `json:"topics,omitempty"`
+	Questions []domain.VerificationQuestion `json:"questions,omitempty"`
 }
 
 type metacogProcedureResponse struct {
@@ -66,10 +70,13 @@ type procedureAssessmentResponse struct {
 }
 
 type failurePatternResponse struct {
-	Pattern    string   `json:"pattern"`
-	Frequency  int      `json:"frequency"`
-	Topics     []string `json:"topics,omitempty"`
-	Suggestion string   `json:"suggestion"`
+	Pattern    string            `json:"pattern"`
+	Frequency  int               `json:"frequency"`
+	Topics     []string          `json:"topics,omitempty"`
+	Suggestion string            `json:"suggestion"`
+	Recent     int               `json:"recent"`
+	Previous   int               `json:"previous"`
+	Trend      domain.TopicTrend `json:"trend,omitempty"`
 }
 
 type strategyReflectionResponse struct {
@@ -163,6 +170,8 @@ func (h *MetacognitiveHandler) Reflect(w http.ResponseWriter, r *http.Request) {
 			LowConfidenceBeliefs: make([]memoryResponse, len(ur.LowConfidenceBeliefs)),
 			StaleBeliefs:         make([]memoryResponse, len(ur.StaleBeliefs)),
 			Recommendation:       ur.Recommendation,
+			Topics:               ur.Topics,
+			Questions:            ur.Questions,
 		}
 
 		for i, m := range ur.ContradictedBeliefs {
@@ -239,6 +248,9 @@ func (h *MetacognitiveHandler) Reflect(w http.ResponseWriter, r *http.Request) {
 				Frequency:  fp.Frequency,
 				Topics:     fp.Topics,
 				Suggestion: fp.Suggestion,
+				Recent:     fp.Recent,
+				Previous:   fp.Previous,
+				Trend:      fp.Trend,
 			}
 		}
 	}
@@ -335,6 +347,8 @@ func (h *MetacognitiveHandler) DetectUncertainty(w http.ResponseWriter, r *http.
 		LowConfidenceBeliefs: make([]memoryResponse, len(result.LowConfidenceBeliefs)),
 		StaleBeliefs:         make([]memoryResponse, len(result.StaleBeliefs)),
 		Recommendation:       result.Recommendation,
+		Topics:               result.Topics,
+		Questions:            result.Questions,
 	}
 
 	for i, m := range result.ContradictedBeliefs {
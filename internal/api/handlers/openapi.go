@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/Harshitk-cp/engram/internal/api/openapi"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+)
+
+// DescribeAPI registers the request and response types of the core endpoints.
+// Every route is listed in the document whether described here or not; a
+// description adds its bodies, query parameters and summary.
+func DescribeAPI(g *openapi.Generator) {
+	g.Public("/health", "/livez", "/metrics", "/openapi.json", "/docs", "/tools", "/auth", "/v1/setup", "/v1/tenants", "/v1/billing/webhook")
+
+	limitOffset := []openapi.Param{
+		{Name: "limit", Type: "integer"},
+		{Name: "offset", Type: "integer"},
+	}
+
+	g.Describe(http.MethodGet, "/tools", openapi.Op{
+		Summary:  "OpenAI function schemas for the remember, recall, reflect and activate_context tools",
+		Response: listToolsResponse{},
+	})
+	g.Describe(http.MethodPost, "/v1/setup", openapi.Op{
+		Summary:  "Bootstrap a tenant and its first API key (X-Setup-Token)",
+		Request:  bootstrapRequest{},
+		Response: bootstrapResponse{},
+		Status:   http.StatusCreated,
+	})
+	g.Describe(http.MethodPost, "/v1/keys", openapi.Op{
+		Summary:  "Create an API key",
+		Request:  createKeyRequest{},
+		Response: createKeyResponse{},
+		Status:   http.StatusCreated,
+	})
+
+	g.Describe(http.MethodPost, "/v1/agents", openapi.Op{
+		Summary:  "Create an agent",
+		Request:  createAgentRequest{},
+		Response: domain.Agent{},
+		Status:   http.StatusCreated,
+	})
+	g.Describe(http.MethodGet, "/v1/agents", openapi.Op{
+		Summary:  "List agents",
+		Query:    limitOffset,
+		Response: listAgentsResponse{},
+	})
+	g.Describe(http.MethodGet, "/v1/agents/{id}", openapi.Op{
+		Summary:  "Get an agent",
+		Response: domain.Agent{},
+	})
+	g.Describe(http.MethodPost, "/v1/agents/{id}/seed", openapi.Op{
+		Summary:  "Seed an agent with beliefs, schemas and procedures (JSON or YAML)",
+		Request:  domain.SeedDocument{},
+		Response: service.SeedResult{},
+	})
+	g.Describe(http.MethodPost, "/v1/agents/{id}/clone", openapi.Op{
+		Summary:  "Clone an agent's schemas, procedures and optionally memories into a new agent",
+		Request:  cloneAgentRequest{},
+		Response: service.CloneResult{},
+		Status:   http.StatusCreated,
+	})
+	g.Describe(http.MethodGet, "/v1/agents/{id}/skills", openapi.Op{
+		Summary: "Export the agent's proven procedures as a skill pack",
+		Query: []openapi.Param{
+			{Name: "name", Description: "Pack name; defaults to the agent's name"},
+			{Name: "min_success_rate", Type: "number", Description: "Default 0.7"},
+			{Name: "min_uses", Type: "integer", Description: "Default 3"},
+		},
+		Response: domain.SkillPack{},
+	})
+	g.Describe(http.MethodPost, "/v1/agents/{id}/skills", openapi.Op{
+		Summary:  "Install a skill pack; its procedures are marked as transferred",
+		Request:  domain.SkillPack{},
+		Response: service.SkillImportResult{},
+	})
+	g.Describe(http.MethodGet, "/v1/agents/{id}/stats", openapi.Op{
+		Summary:  "Materialized memory statistics for an agent",
+		Response: domain.AgentStatistics{},
+	})
+	g.Describe(http.MethodGet, "/v1/stats/agents", openapi.Op{
+		Summary: "Materialized memory statistics for the tenant's agents, largest first",
+		Query:   limitOffset,
+	})
+	g.Describe(http.MethodGet, "/v1/agents/{id}/compare", openapi.Op{
+		Summary: "Compare two agents, or one agent at two instants, for memory drift",
+		Query: []openapi.Param{
+			{Name: "other_agent_id", Description: "Agent for side B; defaults to this agent"},
+			{Name: "at", Description: "RFC3339 instant for side A; defaults to now"},
+			{Name: "other_at", Description: "RFC3339 instant for side B; defaults to now"},
+			{Name: "limit", Type: "integer", Description: "Beliefs compared per side, highest confidence first (max 500)"},
+		},
+		Response: service.AgentComparison{},
+	})
+
+	g.Describe(http.MethodGet, "/v1/cognitive/knowledge", openapi.Op{
+		Summary: "What the agent knows about a topic, as a narrative citing the memories, episodes and schemas behind each claim",
+		Query: []openapi.Param{
+			{Name: "agent_id", Required: true},
+			{Name: "topic", Required: true},
+		},
+		Response: domain.KnowledgeNarrative{},
+	})
+
+	g.Describe(http.MethodPost, "/v1/memories", openapi.Op{
+		Summary:  "Store a memory",
+		Request:  createMemoryRequest{},
+		Response: createMemoryResponse{},
+		Status:   http.StatusCreated,
+	})
+	g.Describe(http.MethodGet, "/v1/memories/{id}", openapi.Op{
+		Summary:  "Get a memory",
+		Response: getMemoryResponse{},
+	})
+	g.Describe(http.MethodGet, "/v1/memories/recall", openapi.Op{
+		Summary: "Recall memories by hybrid vector and graph search",
+		Query: []openapi.Param{
+			{Name: "query", Required: true},
+			{Name: "agent_id", Description: "Required unless anchor_id, anchor_external_id or session_id is given"},
+			{Name: "anchor_id"},
+			{Name: "anchor_external_id"},
+			{Name: "session_id"},
+			{Name: "top_k", Type: "integer"},
+			{Name: "max_results", Type: "integer"},
+			{Name: "type"},
+			{Name: "mode"},
+			{Name: "min_confidence", Type: "number"},
+			{Name: "min_similarity", Type: "number"},
+			{Name: "graph_weight", Type: "number"},
+			{Name: "max_hops", Type: "integer"},
+			{Name: "recency_boost", Type: "number"},
+			{Name: "include_tiers", Description: "Comma-separated tiers"},
+			{Name: "subject", Description: "Only beliefs about this subject, e.g. user or a participant's name"},
+			{Name: "event_date_from"},
+			{Name: "event_date_to"},
+			{Name: "expand_summaries", Type: "boolean"},
+			{Name: "no_interference", Type: "boolean", Description: "Rank without the penalty for memories crowded by similar ones"},
+			{Name: "accuracy", Type: "number", Description: "0 to 1; lower trades recall quality for latency. 0 keeps the server's search settings"},
+			{Name: "channel", Description: "The caller's environment; memories formed in a matching one rank higher"},
+			{Name: "device"},
+			{Name: "locale"},
+			{Name: "surface", Description: "Product surface"},
+			{Name: "window", Description: "Repeatable; a message already in the conversation. Memories it contains verbatim are left out"},
+			{Name: "explain", Type: "boolean"},
+			{Name: "control", Type: "boolean"},
+		},
+		Response: recallResponse{},
+	})
+	g.Describe(http.MethodPost, "/v1/memories/recall/batch", openapi.Op{
+		Summary:  "Recall for several queries at once, grouped per query with each memory under the query that ranked it highest",
+		Request:  batchRecallRequest{},
+		Response: batchRecallResponse{},
+	})
+	g.Describe(http.MethodPost, "/v1/memories/extract", openapi.Op{
+		Summary:  "Extract memories from a conversation (202 with a job when async)",
+		Request:  extractRequest{},
+		Response: extractResponse{},
+	})
+	g.Describe(http.MethodGet, "/v1/jobs/{id}", openapi.Op{
+		Summary:  "Get a background job",
+		Response: domain.Job{},
+	})
+	g.Describe(http.MethodPost, "/v1/conversations/{id}/close", openapi.Op{
+		Summary:  "Close a conversation: implicit feedback, extraction, outcome and consolidation in one background job",
+		Request:  closeConversationRequest{},
+		Response: domain.Job{},
+		Status:   http.StatusAccepted,
+	})
+	g.Describe(http.MethodPost, "/v1/chat/completions", openapi.Op{
+		Summary: "OpenAI-compatible chat completion with the agent's memory injected; the exchange is captured in the background",
+		Query: []openapi.Param{
+			{Name: "agent_id", Description: "Agent to use; alternatively the X-Engram-Agent-Id header"},
+		},
+		Request:  json.RawMessage{},
+		Response: json.RawMessage{},
+	})
+
+	g.Describe(http.MethodPost, "/v1/episodes", openapi.Op{
+		Summary:  "Record an episode",
+		Request:  createEpisodeRequest{},
+		Response: domain.Episode{},
+		Status:   http.StatusCreated,
+	})
+	g.Describe(http.MethodPost, "/v1/episodes/transcript", openapi.Op{
+		Summary:  "Store a transcript as one speaker-attributed episode per turn; multipart/form-data with an audio file is transcribed first",
+		Request:  transcriptRequest{},
+		Response: transcriptResponse{},
+		Status:   http.StatusCreated,
+	})
+	g.Describe(http.MethodGet, "/v1/episodes/{id}", openapi.Op{
+		Summary:  "Get an episode",
+		Response: domain.Episode{},
+	})
+	g.Describe(http.MethodGet, "/v1/episodes/recall", openapi.Op{
+		Summary: "Recall episodes",
+		Query: []openapi.Param{
+			{Name: "agent_id", Required: true},
+			{Name: "query"},
+			{Name: "limit", Type: "integer"},
+			{Name: "min_importance", Type: "number"},
+			{Name: "start_time", Description: "RFC3339"},
+			{Name: "end_time", Description: "RFC3339"},
+			{Name: "channel", Description: "The caller's environment; episodes formed in a matching one rank higher"},
+			{Name: "device"},
+			{Name: "locale"},
+			{Name: "surface", Description: "Product surface"},
+		},
+		Response: recallEpisodesResponse{},
+	})
+
+	g.Describe(http.MethodGet, "/v1/backup", openapi.Op{
+		Summary: "Point-in-time NDJSON backup of the tenant, embeddings included, with a checksummed trailer",
+	})
+	g.Describe(http.MethodPost, "/v1/backup/restore", openapi.Op{
+		Summary:  "Restore an NDJSON backup into the tenant in one transaction",
+		Response: domain.BackupRestoreResult{},
+	})
+	g.Describe(http.MethodGet, "/v1/anchors/{id}/export", openapi.Op{
+		Summary:  "Export everything stored about a subject (right of access)",
+		Response: domain.SubjectExport{},
+	})
+	g.Describe(http.MethodGet, "/v1/admin/search", openapi.Op{
+		Summary: "Search every agent's memories (and optionally episodes), including archived and quarantined",
+		Query: []openapi.Param{
+			{Name: "q", Required: true},
+			{Name: "mode", Description: "text (default), exact or semantic"},
+			{Name: "agent_id", Description: "Restrict to one agent"},
+			{Name: "include_episodes", Type: "boolean"},
+			{Name: "limit", Type: "integer", Description: "Per list (default 50, max 200)"},
+			{Name: "offset", Type: "integer"},
+		},
+		Response: service.TenantSearchResult{},
+	})
+}
+
+// DocsHandler serves the OpenAPI document and a Swagger UI page for it. The
+// document is built once the router is complete, via SetSpec.
+type DocsHandler struct {
+	spec atomic.Pointer[openapi.Document]
+}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// SetSpec sets the document to serve.
+func (h *DocsHandler) SetSpec(doc *openapi.Document) {
+	h.spec.Store(doc)
+}
+
+// Spec handles GET /openapi.json.
+func (h *DocsHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	doc := h.spec.Load()
+	if doc == nil {
+		writeError(w, http.StatusServiceUnavailable, "API document not built")
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// UI handles GET /docs. Swagger UI's assets load from a CDN, so the page needs
+// internet access; the document itself is served locally.
+func (h *DocsHandler) UI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Engram API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
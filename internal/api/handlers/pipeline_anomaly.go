@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// PipelineAnomalyHandler reports anomalies in an agent's memory pipeline
+// metrics.
+type PipelineAnomalyHandler struct {
+	svc        *service.PipelineAnomalyService
+	agentStore domain.AgentStore
+}
+
+func NewPipelineAnomalyHandler(svc *service.PipelineAnomalyService, agentStore domain.AgentStore) *PipelineAnomalyHandler {
+	return &PipelineAnomalyHandler{svc: svc, agentStore: agentStore}
+}
+
+type pipelineAnomaliesResponse struct {
+	Anomalies []domain.PipelineAnomaly `json:"anomalies"`
+	Count     int                      `json:"count"`
+}
+
+// Get handles GET /v1/agents/{id}/anomalies: the agent's last 24 hours
+// checked against its baseline now, whether or not the scan has raised them.
+func (h *PipelineAnomalyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+	if !requireAgentInTenant(w, r, h.agentStore, agentID, tenant.ID) {
+		return
+	}
+
+	anomalies, err := h.svc.Detect(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to detect pipeline anomalies")
+		return
+	}
+	if anomalies == nil {
+		anomalies = []domain.PipelineAnomaly{}
+	}
+	writeJSON(w, http.StatusOK, pipelineAnomaliesResponse{Anomalies: anomalies, Count: len(anomalies)})
+}
@@ -26,6 +26,7 @@ type policyRequest struct {
 	RetentionDays  *int    `json:"retention_days"`
 	PriorityWeight float64 `json:"priority_weight"`
 	AutoSummarize  bool    `json:"auto_summarize"`
+	BeliefUpdate   string  `json:"belief_update,omitempty"`
 }
 
 type upsertPoliciesRequest struct {
@@ -38,6 +39,7 @@ type policyResponse struct {
 	RetentionDays  *int    `json:"retention_days,omitempty"`
 	PriorityWeight float64 `json:"priority_weight"`
 	AutoSummarize  bool    `json:"auto_summarize"`
+	BeliefUpdate   string  `json:"belief_update"`
 }
 
 type policiesResponse struct {
@@ -51,6 +53,7 @@ func toPolicyResponse(p domain.Policy) policyResponse {
 		RetentionDays:  p.RetentionDays,
 		PriorityWeight: p.PriorityWeight,
 		AutoSummarize:  p.AutoSummarize,
+		BeliefUpdate:   string(p.BeliefUpdate),
 	}
 }
 
@@ -117,6 +120,7 @@ func (h *PolicyHandler) Upsert(w http.ResponseWriter, r *http.Request) {
 			RetentionDays:  p.RetentionDays,
 			PriorityWeight: p.PriorityWeight,
 			AutoSummarize:  p.AutoSummarize,
+			BeliefUpdate:   domain.BeliefUpdateMode(p.BeliefUpdate),
 		})
 	}
 
@@ -131,6 +135,8 @@ func (h *PolicyHandler) Upsert(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrPolicyPriorityWeight):
 			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrPolicyBeliefUpdate):
+			writeError(w, http.StatusBadRequest, err.Error())
 		default:
 			writeError(w, http.StatusInternalServerError, "failed to upsert policies")
 		}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -34,21 +35,24 @@ type matchProceduresResponse struct {
 }
 
 type procedureResponse struct {
-	ID              string                   `json:"id"`
-	TriggerPattern  string                   `json:"trigger_pattern"`
-	TriggerKeywords []string                 `json:"trigger_keywords,omitempty"`
-	ActionTemplate  string                   `json:"action_template"`
-	ActionType      string                   `json:"action_type"`
-	UseCount        int                      `json:"use_count"`
-	SuccessCount    int                      `json:"success_count"`
-	FailureCount    int                      `json:"failure_count"`
-	SuccessRate     float32                  `json:"success_rate"`
-	Confidence      float32                  `json:"confidence"`
-	Version         int                      `json:"version"`
-	Score           float32                  `json:"score,omitempty"`
-	Examples        []domain.ExampleExchange `json:"examples,omitempty"`
-	CreatedAt       string                   `json:"created_at"`
-	UpdatedAt       string                   `json:"updated_at"`
+	ID              string                      `json:"id"`
+	TriggerPattern  string                      `json:"trigger_pattern"`
+	TriggerKeywords []string                    `json:"trigger_keywords,omitempty"`
+	Conditions      *domain.ProcedureConditions `json:"conditions,omitempty"`
+	ActionTemplate  string                      `json:"action_template"`
+	ActionType      string                      `json:"action_type"`
+	UseCount        int                         `json:"use_count"`
+	SuccessCount    int                         `json:"success_count"`
+	FailureCount    int                         `json:"failure_count"`
+	SuccessRate     float32                     `json:"success_rate"`
+	Confidence      float32                     `json:"confidence"`
+	Source          domain.ProcedureSource      `json:"source,omitempty"`
+	SourcePack      string                      `json:"source_pack,omitempty"`
+	Version         int                         `json:"version"`
+	Score           float32                     `json:"score,omitempty"`
+	Examples        []domain.ExampleExchange    `json:"examples,omitempty"`
+	CreatedAt       string                      `json:"created_at"`
+	UpdatedAt       string                      `json:"updated_at"`
 }
 
 // Match finds procedures applicable to the current situation.
@@ -134,6 +138,140 @@ func (h *ProcedureHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, toProcedureResponse(procedure))
 }
 
+type renderActionRequest struct {
+	Variables      map[string]string `json:"variables,omitempty"`
+	UserAttributes map[string]string `json:"user_attributes,omitempty"`
+	Goal           string            `json:"goal,omitempty"`
+	Topics         []string          `json:"topics,omitempty"`
+}
+
+// RenderAction fills a procedure's action template with the given variables.
+// POST /v1/procedures/:id/render
+func (h *ProcedureHandler) RenderAction(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid procedure id")
+		return
+	}
+
+	var req renderActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rendered, err := h.svc.RenderAction(r.Context(), id, tenant.ID, service.RenderActionInput{
+		Variables:      req.Variables,
+		UserAttributes: req.UserAttributes,
+		Goal:           req.Goal,
+		Topics:         req.Topics,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrProcedureNotFound) {
+			writeError(w, http.StatusNotFound, "procedure not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to render procedure")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rendered)
+}
+
+// ExportSkills packs the agent's proven procedures into a skill pack.
+// GET /v1/agents/:id/skills
+func (h *ProcedureHandler) ExportSkills(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	q := r.URL.Query()
+	opts := service.SkillExportOptions{Name: q.Get("name")}
+	if v := q.Get("min_success_rate"); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid min_success_rate")
+			return
+		}
+		opts.MinSuccessRate = float32(f)
+	}
+	if v := q.Get("min_uses"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid min_uses")
+			return
+		}
+		opts.MinUses = n
+	}
+
+	pack, err := h.svc.ExportSkills(r.Context(), agentID, tenant.ID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSkillExport):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to export skills")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pack)
+}
+
+// ImportSkills installs a skill pack into the agent.
+// POST /v1/agents/:id/skills
+func (h *ProcedureHandler) ImportSkills(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	var pack domain.SkillPack
+	if err := json.NewDecoder(r.Body).Decode(&pack); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.svc.ImportSkills(r.Context(), agentID, tenant.ID, pack)
+	if err != nil {
+		var invalid *service.SeedValidationError
+		switch {
+		case errors.As(err, &invalid), errors.Is(err, service.ErrSkillPackEmpty), errors.Is(err, service.ErrSkillPackVersion):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to import skills")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
 type procedureOutcomeRequest struct {
 	Success bool `json:"success"`
 }
@@ -223,6 +361,9 @@ func toProcedureResponse(p *domain.Procedure) procedureResponse {
 		ID:              p.ID.String(),
 		TriggerPattern:  p.TriggerPattern,
 		TriggerKeywords: p.TriggerKeywords,
+		Conditions:      p.Conditions,
+		Source:          p.Source,
+		SourcePack:      p.SourcePack,
 		ActionTemplate:  p.ActionTemplate,
 		ActionType:      string(p.ActionType),
 		UseCount:        p.UseCount,
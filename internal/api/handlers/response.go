@@ -35,6 +35,22 @@ func clampLimit(n int) int {
 	return n
 }
 
+// parseUUIDList parses a list of UUID strings, failing on the first invalid one.
+func parseUUIDList(ids []string) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, s := range ids {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
 func requireAgentInTenant(w http.ResponseWriter, r *http.Request, agents domain.AgentStore, agentID, tenantID uuid.UUID) bool {
 	agent, err := agents.GetByID(r.Context(), agentID, tenantID)
 	if err != nil {
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RetentionHandler exposes the tenant's retention rules and a dry-run preview
+// of what the expirer would delete under them.
+type RetentionHandler struct {
+	svc *service.RetentionService
+}
+
+func NewRetentionHandler(svc *service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{svc: svc}
+}
+
+type upsertRetentionRuleRequest struct {
+	Target     string `json:"target"`
+	MemoryType string `json:"memory_type,omitempty"`
+	AfterDays  int    `json:"after_days"`
+}
+
+// List handles GET /v1/retention-rules.
+func (h *RetentionHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	rules, err := h.svc.List(r.Context(), tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list retention rules")
+		return
+	}
+	if rules == nil {
+		rules = []domain.RetentionRule{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"rules": rules})
+}
+
+// Upsert handles PUT /v1/retention-rules. A rule for the same target and
+// memory type replaces the existing one.
+func (h *RetentionHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req upsertRetentionRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rule := &domain.RetentionRule{
+		TenantID:  tenant.ID,
+		Target:    domain.RetentionTarget(req.Target),
+		AfterDays: req.AfterDays,
+	}
+	if req.MemoryType != "" {
+		t := domain.MemoryType(req.MemoryType)
+		rule.MemoryType = &t
+	}
+	if err := h.svc.Upsert(r.Context(), rule); err != nil {
+		var invalid *service.InvalidRetentionRuleError
+		if errors.As(err, &invalid) {
+			writeError(w, http.StatusBadRequest, invalid.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to save retention rule")
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// Delete handles DELETE /v1/retention-rules/{id}.
+func (h *RetentionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid retention rule id")
+		return
+	}
+	if err := h.svc.Delete(r.Context(), id, tenant.ID); err != nil {
+		if errors.Is(err, service.ErrRetentionRuleNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete retention rule")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"deleted": true})
+}
+
+// Preview handles GET /v1/retention-rules/preview: per rule, how many rows
+// would be deleted if the expirer ran now, the oldest one's timestamp and a
+// sample of their IDs.
+func (h *RetentionHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	previews, err := h.svc.Preview(r.Context(), tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to preview retention rules")
+		return
+	}
+	var total int64
+	for _, p := range previews {
+		total += p.Count
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"previews": previews, "total": total})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// SchedulerHandler exposes the periodic background workers. They run across
+// every tenant, so like vector index management each call needs admin scope
+// plus the deployment's X-Setup-Token; with no token configured it is disabled.
+type SchedulerHandler struct {
+	scheduler  *service.Scheduler
+	setupToken string
+}
+
+func NewSchedulerHandler(scheduler *service.Scheduler, setupToken string) *SchedulerHandler {
+	return &SchedulerHandler{scheduler: scheduler, setupToken: setupToken}
+}
+
+func (h *SchedulerHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if h.setupToken == "" {
+		writeError(w, http.StatusServiceUnavailable, "background job management is not configured (ENGRAM_SETUP_TOKEN not set)")
+		return false
+	}
+	if !tokenMatches(r.Header.Get("X-Setup-Token"), h.setupToken) {
+		writeError(w, http.StatusForbidden, "invalid setup token")
+		return false
+	}
+	return true
+}
+
+// List handles GET /v1/admin/jobs.
+func (h *SchedulerHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": h.scheduler.List()})
+}
+
+// Pause handles POST /v1/admin/jobs/{name}/pause.
+func (h *SchedulerHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	status, err := h.scheduler.Pause(chi.URLParam(r, "name"))
+	h.writeResult(w, status, err)
+}
+
+// Resume handles POST /v1/admin/jobs/{name}/resume.
+func (h *SchedulerHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	status, err := h.scheduler.Resume(chi.URLParam(r, "name"))
+	h.writeResult(w, status, err)
+}
+
+func (h *SchedulerHandler) writeResult(w http.ResponseWriter, status *service.ScheduledTaskStatus, err error) {
+	if err != nil {
+		if errors.Is(err, service.ErrScheduledTaskNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to update job")
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
@@ -25,6 +25,7 @@ type matchSchemasRequest struct {
 	Query         string   `json:"query,omitempty"`
 	Contexts      []string `json:"contexts,omitempty"`
 	TimeOfDay     string   `json:"time_of_day,omitempty"`
+	DayOfWeek     string   `json:"day_of_week,omitempty"`
 	MinMatchScore float32  `json:"min_match_score,omitempty"`
 	Limit         int      `json:"limit,omitempty"`
 }
@@ -43,6 +44,7 @@ type schemaMatchResponse struct {
 type schemaResponse struct {
 	ID                 string         `json:"id"`
 	AgentID            string         `json:"agent_id"`
+	ParentID           string         `json:"parent_id,omitempty"`
 	SchemaType         string         `json:"schema_type"`
 	Name               string         `json:"name"`
 	Description        string         `json:"description,omitempty"`
@@ -55,6 +57,17 @@ type schemaResponse struct {
 	UpdatedAt          string         `json:"updated_at"`
 }
 
+type schemaDetailResponse struct {
+	schemaResponse
+	Ancestors           []schemaRefResponse `json:"ancestors"`
+	EffectiveAttributes map[string]any      `json:"effective_attributes"`
+}
+
+type schemaRefResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type listSchemasResponse struct {
 	Schemas []schemaResponse `json:"schemas"`
 	Count   int              `json:"count"`
@@ -62,6 +75,9 @@ type listSchemasResponse struct {
 
 type detectSchemasRequest struct {
 	AgentID string `json:"agent_id"`
+	// Full forces a re-cluster of every eligible memory instead of an
+	// incremental pass over memories new since the last run.
+	Full bool `json:"full,omitempty"`
 }
 
 type detectSchemasResponse struct {
@@ -69,6 +85,237 @@ type detectSchemasResponse struct {
 	Count           int              `json:"count"`
 }
 
+type createSchemaRequest struct {
+	AgentID            string         `json:"agent_id"`
+	SchemaType         string         `json:"schema_type"`
+	Name               string         `json:"name"`
+	Description        string         `json:"description,omitempty"`
+	Attributes         map[string]any `json:"attributes,omitempty"`
+	ApplicableContexts []string       `json:"applicable_contexts,omitempty"`
+	EvidenceMemories   []string       `json:"evidence_memories,omitempty"`
+	EvidenceEpisodes   []string       `json:"evidence_episodes,omitempty"`
+	Confidence         *float32       `json:"confidence,omitempty"`
+	ParentID           string         `json:"parent_id,omitempty"`
+	ParentName         string         `json:"parent_name,omitempty"` // Same-type parent; earlier entries in a seed batch resolve
+}
+
+type seedSchemasRequest struct {
+	AgentID string                `json:"agent_id"`
+	Schemas []createSchemaRequest `json:"schemas"`
+}
+
+type seedSchemasResponse struct {
+	Schemas []schemaResponse `json:"schemas"`
+	Count   int              `json:"count"`
+}
+
+type updateSchemaRequest struct {
+	Name               *string        `json:"name,omitempty"`
+	Description        *string        `json:"description,omitempty"`
+	Attributes         map[string]any `json:"attributes,omitempty"`
+	ApplicableContexts *[]string      `json:"applicable_contexts,omitempty"`
+	Confidence         *float32       `json:"confidence,omitempty"`
+	ParentID           *string        `json:"parent_id,omitempty"` // "" detaches from the current parent
+}
+
+type attachEvidenceRequest struct {
+	MemoryIDs  []string `json:"memory_ids,omitempty"`
+	EpisodeIDs []string `json:"episode_ids,omitempty"`
+}
+
+// Create manually creates a schema for an agent.
+// POST /v1/schemas
+func (h *SchemaHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req createSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	agentID, err := uuid.Parse(req.AgentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	input, err := toCreateSchemaInput(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	input.AgentID = agentID
+	input.TenantID = tenant.ID
+
+	schema, err := h.svc.CreateSchema(r.Context(), input)
+	if err != nil {
+		writeSchemaWriteError(w, err, "failed to create schema")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toSchemaResponse(schema))
+}
+
+// Seed creates or updates a batch of schemas for an agent, matched by type and name.
+// POST /v1/schemas/seed
+func (h *SchemaHandler) Seed(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req seedSchemasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	agentID, err := uuid.Parse(req.AgentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	if len(req.Schemas) == 0 {
+		writeError(w, http.StatusBadRequest, "schemas is required")
+		return
+	}
+	if len(req.Schemas) > MaxPageLimit {
+		writeError(w, http.StatusBadRequest, "too many schemas")
+		return
+	}
+
+	inputs := make([]service.CreateSchemaInput, len(req.Schemas))
+	for i, sr := range req.Schemas {
+		input, err := toCreateSchemaInput(sr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		inputs[i] = input
+	}
+
+	schemas, err := h.svc.SeedSchemas(r.Context(), agentID, tenant.ID, inputs)
+	if err != nil {
+		writeSchemaWriteError(w, err, "failed to seed schemas")
+		return
+	}
+
+	response := seedSchemasResponse{
+		Schemas: make([]schemaResponse, len(schemas)),
+		Count:   len(schemas),
+	}
+	for i, s := range schemas {
+		response.Schemas[i] = toSchemaResponse(&s)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Update applies a partial update to a schema.
+// PATCH /v1/schemas/:id
+func (h *SchemaHandler) Update(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid schema id")
+		return
+	}
+
+	var req updateSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	input := service.UpdateSchemaInput{
+		ID:                 id,
+		TenantID:           tenant.ID,
+		Name:               req.Name,
+		Description:        req.Description,
+		Attributes:         req.Attributes,
+		ApplicableContexts: req.ApplicableContexts,
+		Confidence:         req.Confidence,
+	}
+	if req.ParentID != nil {
+		parentID := uuid.Nil
+		if *req.ParentID != "" {
+			parentID, err = uuid.Parse(*req.ParentID)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid parent_id")
+				return
+			}
+		}
+		input.ParentID = &parentID
+	}
+
+	schema, err := h.svc.UpdateSchema(r.Context(), input)
+	if err != nil {
+		writeSchemaWriteError(w, err, "failed to update schema")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSchemaResponse(schema))
+}
+
+// AttachEvidence links supporting memories and episodes to a schema.
+// POST /v1/schemas/:id/evidence
+func (h *SchemaHandler) AttachEvidence(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid schema id")
+		return
+	}
+
+	var req attachEvidenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	memoryIDs, err := parseUUIDList(req.MemoryIDs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory_ids")
+		return
+	}
+	episodeIDs, err := parseUUIDList(req.EpisodeIDs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid episode_ids")
+		return
+	}
+	if len(memoryIDs) == 0 && len(episodeIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "memory_ids or episode_ids is required")
+		return
+	}
+
+	schema, err := h.svc.AttachEvidence(r.Context(), id, tenant.ID, memoryIDs, episodeIDs)
+	if err != nil {
+		writeSchemaWriteError(w, err, "failed to attach evidence")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSchemaResponse(schema))
+}
+
 // Match finds schemas that apply to the current situation.
 // POST /v1/schemas/match
 func (h *SchemaHandler) Match(w http.ResponseWriter, r *http.Request) {
@@ -96,6 +343,7 @@ func (h *SchemaHandler) Match(w http.ResponseWriter, r *http.Request) {
 		Query:         req.Query,
 		Contexts:      req.Contexts,
 		TimeOfDay:     req.TimeOfDay,
+		DayOfWeek:     req.DayOfWeek,
 		MinMatchScore: req.MinMatchScore,
 		Limit:         req.Limit,
 	}
@@ -177,7 +425,7 @@ func (h *SchemaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schema, err := h.svc.GetByID(r.Context(), id, tenant.ID)
+	schema, ancestors, effective, err := h.svc.GetWithAncestors(r.Context(), id, tenant.ID)
 	if err != nil {
 		if errors.Is(err, service.ErrSchemaNotFound) {
 			writeError(w, http.StatusNotFound, "schema not found")
@@ -187,7 +435,19 @@ func (h *SchemaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toSchemaResponse(schema))
+	response := schemaDetailResponse{
+		schemaResponse:      toSchemaResponse(schema),
+		Ancestors:           make([]schemaRefResponse, len(ancestors)),
+		EffectiveAttributes: effective,
+	}
+	for i, a := range ancestors {
+		response.Ancestors[i] = schemaRefResponse{ID: a.ID.String(), Name: a.Name}
+	}
+	if response.EffectiveAttributes == nil {
+		response.EffectiveAttributes = map[string]any{}
+	}
+
+	writeJSON(w, http.StatusOK, response)
 }
 
 // Delete removes a schema.
@@ -239,7 +499,12 @@ func (h *SchemaHandler) Detect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schemas, err := h.svc.DetectSchemas(r.Context(), agentID, tenant.ID)
+	var schemas []domain.Schema
+	if req.Full {
+		schemas, err = h.svc.RebuildSchemas(r.Context(), agentID, tenant.ID)
+	} else {
+		schemas, err = h.svc.DetectSchemas(r.Context(), agentID, tenant.ID)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to detect schemas")
 		return
@@ -329,6 +594,9 @@ func toSchemaResponse(s *domain.Schema) schemaResponse {
 		CreatedAt:          s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:          s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if s.ParentID != nil {
+		resp.ParentID = s.ParentID.String()
+	}
 
 	// Ensure slices and maps aren't nil for JSON
 	if resp.Attributes == nil {
@@ -340,3 +608,59 @@ func toSchemaResponse(s *domain.Schema) schemaResponse {
 
 	return resp
 }
+
+// toCreateSchemaInput converts a create request into service input, leaving
+// AgentID and TenantID for the caller to fill.
+func toCreateSchemaInput(req createSchemaRequest) (service.CreateSchemaInput, error) {
+	memoryIDs, err := parseUUIDList(req.EvidenceMemories)
+	if err != nil {
+		return service.CreateSchemaInput{}, errors.New("invalid evidence_memories")
+	}
+	episodeIDs, err := parseUUIDList(req.EvidenceEpisodes)
+	if err != nil {
+		return service.CreateSchemaInput{}, errors.New("invalid evidence_episodes")
+	}
+
+	var parentID *uuid.UUID
+	if req.ParentID != "" {
+		id, err := uuid.Parse(req.ParentID)
+		if err != nil {
+			return service.CreateSchemaInput{}, errors.New("invalid parent_id")
+		}
+		parentID = &id
+	}
+
+	return service.CreateSchemaInput{
+		ParentID:           parentID,
+		ParentName:         req.ParentName,
+		SchemaType:         domain.SchemaType(req.SchemaType),
+		Name:               req.Name,
+		Description:        req.Description,
+		Attributes:         req.Attributes,
+		ApplicableContexts: req.ApplicableContexts,
+		EvidenceMemories:   memoryIDs,
+		EvidenceEpisodes:   episodeIDs,
+		Confidence:         req.Confidence,
+	}, nil
+}
+
+// writeSchemaWriteError maps schema create/update service errors to HTTP responses.
+func writeSchemaWriteError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrSchemaNotFound):
+		writeError(w, http.StatusNotFound, "schema not found")
+	case errors.Is(err, service.ErrAgentNotFound):
+		writeError(w, http.StatusNotFound, "agent not found")
+	case errors.Is(err, service.ErrSchemaExists),
+		errors.Is(err, service.ErrSchemaCycle):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidSchemaType),
+		errors.Is(err, service.ErrSchemaNameRequired),
+		errors.Is(err, service.ErrInvalidConfidence),
+		errors.Is(err, service.ErrEvidenceNotFound),
+		errors.Is(err, service.ErrParentSchemaNotFound):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// StatsHandler serves the materialized per-agent memory statistics that
+// dashboards read instead of scanning memories.
+type StatsHandler struct {
+	svc        *service.AgentStatsService
+	agentStore domain.AgentStore
+}
+
+func NewStatsHandler(svc *service.AgentStatsService, agentStore domain.AgentStore) *StatsHandler {
+	return &StatsHandler{svc: svc, agentStore: agentStore}
+}
+
+// Get handles GET /v1/agents/{id}/stats.
+func (h *StatsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+	if !requireAgentInTenant(w, r, h.agentStore, agentID, tenant.ID) {
+		return
+	}
+
+	st, err := h.svc.Get(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get agent stats")
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+// List handles GET /v1/stats/agents, the tenant's agents by memory count.
+func (h *StatsHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit, offset := 50, 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, e := strconv.Atoi(v); e == nil {
+			limit = clampLimit(n)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, e := strconv.Atoi(v); e == nil && n > 0 {
+			offset = n
+		}
+	}
+
+	items, total, err := h.svc.ListByTenant(r.Context(), tenant.ID, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list agent stats")
+		return
+	}
+	if items == nil {
+		items = []domain.AgentStatistics{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "total": total, "limit": limit, "offset": offset})
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -13,10 +15,11 @@ import (
 
 type TierHandler struct {
 	memorySvc *service.MemoryService
+	tierSvc   *service.TierTransitionService
 }
 
-func NewTierHandler(memorySvc *service.MemoryService) *TierHandler {
-	return &TierHandler{memorySvc: memorySvc}
+func NewTierHandler(memorySvc *service.MemoryService, tierSvc *service.TierTransitionService) *TierHandler {
+	return &TierHandler{memorySvc: memorySvc, tierSvc: tierSvc}
 }
 
 type tierStatsResponse struct {
@@ -98,7 +101,7 @@ func (h *TierHandler) GetHotMemories(w http.ResponseWriter, r *http.Request) {
 		result = append(result, memoryWithTier{
 			Memory:     &mem,
 			Tier:       domain.TierHot,
-			TierReason: domain.TierReason(float64(m.Confidence)),
+			TierReason: hotTierReason(m),
 		})
 	}
 
@@ -107,3 +110,131 @@ func (h *TierHandler) GetHotMemories(w http.ResponseWriter, r *http.Request) {
 		Count:    len(result),
 	})
 }
+
+func hotTierReason(m domain.Memory) string {
+	if m.Pinned {
+		return "pinned"
+	}
+	return domain.TierReason(float64(m.Confidence))
+}
+
+type tierHistoryResponse struct {
+	Transitions []domain.TierTransition `json:"transitions"`
+	Count       int                     `json:"count"`
+}
+
+// Pin holds a memory in the hot tier regardless of its confidence.
+func (h *TierHandler) Pin(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, true)
+}
+
+// Unpin returns a memory to policy-driven tiering.
+func (h *TierHandler) Unpin(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, false)
+}
+
+func (h *TierHandler) setPinned(w http.ResponseWriter, r *http.Request, pinned bool) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+
+	memory, err := h.tierSvc.SetPinned(r.Context(), id, tenant.ID, pinned)
+	if err != nil {
+		if errors.Is(err, service.ErrMemoryNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to update pin")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, memory)
+}
+
+// GetMemoryTierHistory lists a memory's tier changes, newest first.
+func (h *TierHandler) GetMemoryTierHistory(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid memory id")
+		return
+	}
+
+	history, err := h.tierSvc.MemoryHistory(r.Context(), id, tenant.ID, historyLimit(r))
+	if err != nil {
+		if errors.Is(err, service.ErrMemoryNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get tier history")
+		return
+	}
+
+	writeTierHistory(w, history)
+}
+
+// GetAgentTierHistory lists tier changes across an agent's memories, newest
+// first, optionally since an RFC3339 time.
+func (h *TierHandler) GetAgentTierHistory(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	var since time.Time
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid since format (use RFC3339)")
+			return
+		}
+	}
+
+	history, err := h.tierSvc.AgentHistory(r.Context(), agentID, tenant.ID, since, historyLimit(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get tier history")
+		return
+	}
+
+	writeTierHistory(w, history)
+}
+
+func historyLimit(r *http.Request) int {
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	return limit
+}
+
+func writeTierHistory(w http.ResponseWriter, history []domain.TierTransition) {
+	if history == nil {
+		history = []domain.TierTransition{}
+	}
+	writeJSON(w, http.StatusOK, tierHistoryResponse{
+		Transitions: history,
+		Count:       len(history),
+	})
+}
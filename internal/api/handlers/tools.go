@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/mcp"
+)
+
+// ToolsHandler serves ready-made function-calling schemas for the core memory
+// tools, so an agent can add Engram to its tool list without writing them.
+type ToolsHandler struct {
+	tools []mcp.OpenAITool
+}
+
+func NewToolsHandler() *ToolsHandler {
+	return &ToolsHandler{tools: mcp.OpenAITools()}
+}
+
+type listToolsResponse struct {
+	Tools []mcp.OpenAITool `json:"tools"`
+}
+
+// List handles GET /tools. The schemas are static and hold no tenant data, so
+// the endpoint is public. A model's tool call runs by passing its name and
+// arguments to tools/call on /mcp.
+func (h *ToolsHandler) List(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, listToolsResponse{Tools: h.tools})
+}
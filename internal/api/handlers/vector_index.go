@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// VectorIndexHandler exposes pgvector index management. Indexes are shared by
+// every tenant in the deployment, so on top of admin scope each call must carry
+// the deployment's X-Setup-Token; with no token configured the API is disabled.
+type VectorIndexHandler struct {
+	svc        *service.VectorIndexService
+	setupToken string
+}
+
+func NewVectorIndexHandler(svc *service.VectorIndexService, setupToken string) *VectorIndexHandler {
+	return &VectorIndexHandler{svc: svc, setupToken: setupToken}
+}
+
+func (h *VectorIndexHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if h.setupToken == "" {
+		writeError(w, http.StatusServiceUnavailable, "vector index management is not configured (ENGRAM_SETUP_TOKEN not set)")
+		return false
+	}
+	if !tokenMatches(r.Header.Get("X-Setup-Token"), h.setupToken) {
+		writeError(w, http.StatusForbidden, "invalid setup token")
+		return false
+	}
+	return true
+}
+
+// List handles GET /v1/admin/vector-indexes.
+func (h *VectorIndexHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	indexes, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list vector indexes")
+		return
+	}
+	if indexes == nil {
+		indexes = []domain.VectorIndex{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"indexes": indexes})
+}
+
+// Get handles GET /v1/admin/vector-indexes/{name}.
+func (h *VectorIndexHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	idx, err := h.svc.Get(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		h.writeServiceErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, idx)
+}
+
+// Rebuild handles POST /v1/admin/vector-indexes/{name}/rebuild. The body holds
+// the build parameters ({"method":"hnsw","m":16,"ef_construction":64} or
+// {"method":"ivfflat","lists":100}); unset values take pgvector's defaults.
+// The build runs concurrently in the background; poll Get for its status.
+func (h *VectorIndexHandler) Rebuild(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	var params domain.VectorIndexParams
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	idx, err := h.svc.Rebuild(r.Context(), chi.URLParam(r, "name"), params)
+	if err != nil {
+		h.writeServiceErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, idx)
+}
+
+// Health handles GET /v1/admin/vector-indexes/{name}/health. Optional query
+// params: samples, k, ef_search (HNSW) and probes (IVFFlat).
+func (h *VectorIndexHandler) Health(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	q := r.URL.Query()
+	intParam := func(name string) int {
+		n, _ := strconv.Atoi(q.Get(name))
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	health, err := h.svc.Health(r.Context(), chi.URLParam(r, "name"),
+		intParam("samples"), intParam("k"), intParam("ef_search"), intParam("probes"))
+	if err != nil {
+		h.writeServiceErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, health)
+}
+
+func (h *VectorIndexHandler) writeServiceErr(w http.ResponseWriter, err error) {
+	var invalid *service.InvalidIndexParamsError
+	switch {
+	case errors.As(err, &invalid):
+		writeError(w, http.StatusBadRequest, invalid.Error())
+	case errors.Is(err, service.ErrVectorIndexNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrVectorIndexBuilding):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrVectorIndexEmpty):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "vector index operation failed")
+	}
+}
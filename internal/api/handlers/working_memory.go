@@ -4,31 +4,69 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/Harshitk-cp/engram/internal/api/middleware"
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/service"
+	"github.com/Harshitk-cp/engram/internal/websocket"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type WorkingMemoryHandler struct {
-	svc *service.WorkingMemoryService
+	svc       *service.WorkingMemoryService
+	recallLog *service.RecallLogService
+	upgrader  websocket.Upgrader
 }
 
 func NewWorkingMemoryHandler(svc *service.WorkingMemoryService) *WorkingMemoryHandler {
 	return &WorkingMemoryHandler{svc: svc}
 }
 
+// SetRecallLogger enables logging of control activations (optional).
+func (h *WorkingMemoryHandler) SetRecallLogger(l *service.RecallLogService) {
+	h.recallLog = l
+}
+
+// SetStreamOrigins lists the browser origins besides the server's own that
+// may open a streaming session.
+func (h *WorkingMemoryHandler) SetStreamOrigins(origins []string) {
+	h.upgrader.AllowedOrigins = origins
+}
+
 type activateRequest struct {
-	AgentID string           `json:"agent_id"`
-	Goal    string           `json:"goal,omitempty"`
-	Cues    []string         `json:"cues"`
-	Context []domain.Message `json:"context,omitempty"`
+	AgentID        string           `json:"agent_id"`
+	Goal           string           `json:"goal,omitempty"`
+	Cues           []string         `json:"cues"`
+	Context        []domain.Message `json:"context,omitempty"`
+	MaxSlots       int              `json:"max_slots,omitempty"`
+	ConversationID string           `json:"conversation_id,omitempty"`
+	Control        bool             `json:"control,omitempty"`
+	Vision         bool             `json:"vision,omitempty"`
+	Citations      bool             `json:"citations,omitempty"`
+	// IsolateConversation activates in a session of the conversation's own.
+	IsolateConversation bool `json:"isolate_conversation,omitempty"`
+	// MaxLatencyMs and MaxContextTokens budget the call; see
+	// domain.ActivationInput.
+	MaxLatencyMs     int `json:"max_latency_ms,omitempty"`
+	MaxContextTokens int `json:"max_context_tokens,omitempty"`
+	// UserAttributes and Topics are evaluated by procedure conditions.
+	UserAttributes map[string]string `json:"user_attributes,omitempty"`
+	Topics         []string          `json:"topics,omitempty"`
+	// AssociationTypes restricts spreading activation to these types.
+	AssociationTypes []string `json:"association_types,omitempty"`
 }
 
 type activateResponse struct {
 	WorkingMemory    workingMemoryResponse `json:"working_memory"`
 	AssembledContext string                `json:"assembled_context"`
+	Control          bool                  `json:"control,omitempty"`
+
+	Citations map[string]domain.ContextCitation `json:"citations,omitempty"`
+
+	SkippedStages []domain.ActivationStage `json:"skipped_stages,omitempty"`
+	Trimmed       int                      `json:"trimmed,omitempty"`
 }
 
 type workingMemoryResponse struct {
@@ -41,11 +79,12 @@ type workingMemoryResponse struct {
 }
 
 type activationResponse struct {
-	MemoryType string  `json:"memory_type"`
-	MemoryID   string  `json:"memory_id"`
-	Content    string  `json:"content"`
-	Confidence float32 `json:"confidence"`
-	Score      float32 `json:"score"`
+	MemoryType  string                     `json:"memory_type"`
+	MemoryID    string                     `json:"memory_id"`
+	Content     string                     `json:"content"`
+	Confidence  float32                    `json:"confidence"`
+	Score       float32                    `json:"score"`
+	Attachments []domain.EpisodeAttachment `json:"attachments,omitempty"`
 }
 
 type schemaMatchResp struct {
@@ -58,6 +97,7 @@ type schemaMatchResp struct {
 type getSessionResponse struct {
 	SessionID      string               `json:"session_id"`
 	AgentID        string               `json:"agent_id"`
+	ConversationID string               `json:"conversation_id,omitempty"`
 	CurrentGoal    string               `json:"current_goal,omitempty"`
 	ActiveContext  []domain.Message     `json:"active_context,omitempty"`
 	ReasoningState map[string]any       `json:"reasoning_state,omitempty"`
@@ -69,13 +109,51 @@ type getSessionResponse struct {
 	LastActivityAt string               `json:"last_activity_at"`
 }
 
+// ConversationID on the session requests below addresses the session
+// isolated to that conversation instead of the agent-wide one.
+
 type updateGoalRequest struct {
-	AgentID string `json:"agent_id"`
-	Goal    string `json:"goal"`
+	AgentID        string `json:"agent_id"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	Goal           string `json:"goal"`
 }
 
 type clearSessionRequest struct {
+	AgentID        string `json:"agent_id"`
+	ConversationID string `json:"conversation_id,omitempty"`
+}
+
+type patchReasoningRequest struct {
+	AgentID        string         `json:"agent_id"`
+	ConversationID string         `json:"conversation_id,omitempty"`
+	State          map[string]any `json:"state"`
+}
+
+type reasoningStateResponse struct {
+	SessionID      string         `json:"session_id"`
+	AgentID        string         `json:"agent_id"`
+	ReasoningState map[string]any `json:"reasoning_state"`
+}
+
+type createSnapshotRequest struct {
 	AgentID string `json:"agent_id"`
+	Label   string `json:"label,omitempty"`
+}
+
+type restoreSnapshotResponse struct {
+	SessionID      string         `json:"session_id"`
+	AgentID        string         `json:"agent_id"`
+	CurrentGoal    string         `json:"current_goal,omitempty"`
+	ReasoningState map[string]any `json:"reasoning_state,omitempty"`
+	Activations    int            `json:"activations"`
+	ActiveSchemas  int            `json:"active_schemas"`
+	Dropped        int            `json:"dropped"`
+	MaxSlots       int            `json:"max_slots"`
+}
+
+type updateWorkingMemorySettingsRequest struct {
+	Slots         int `json:"slots"`
+	ExpandedSlots int `json:"expanded_slots,omitempty"`
 }
 
 // Activate performs intelligent memory activation for a task.
@@ -105,19 +183,71 @@ func (h *WorkingMemoryHandler) Activate(w http.ResponseWriter, r *http.Request)
 	}
 
 	input := domain.ActivationInput{
-		AgentID:  agentID,
-		TenantID: tenant.ID,
-		Goal:     req.Goal,
-		Cues:     req.Cues,
-		Context:  req.Context,
+		AgentID:   agentID,
+		TenantID:  tenant.ID,
+		Goal:      req.Goal,
+		Cues:      req.Cues,
+		Context:   req.Context,
+		MaxSlots:  req.MaxSlots,
+		Control:   req.Control,
+		Vision:    req.Vision,
+		Citations: req.Citations,
+
+		MaxLatencyMs:     req.MaxLatencyMs,
+		MaxContextTokens: req.MaxContextTokens,
+		UserAttributes:   req.UserAttributes,
+		Topics:           req.Topics,
+		AssociationTypes: req.AssociationTypes,
+
+		IsolateConversation: req.IsolateConversation,
+	}
+	if req.ConversationID != "" {
+		convID, err := uuid.Parse(req.ConversationID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid conversation_id")
+			return
+		}
+		input.ConversationID = &convID
 	}
 
+	start := time.Now()
 	result, err := h.svc.Activate(r.Context(), input)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSlotCount) {
+			writeError(w, http.StatusBadRequest, "max_slots: "+err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidActivationBudget) || errors.Is(err, domain.ErrIsolationWithoutConversation) ||
+			errors.Is(err, domain.ErrInvalidAssociationType) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "failed to activate memories")
 		return
 	}
 
+	// Control ("memory off") activation: log the winners, hand back an empty
+	// working memory
+	if input.Control {
+		if h.recallLog != nil {
+			h.recallLog.RecordActivation(input, result, time.Since(start))
+		}
+		writeJSON(w, http.StatusOK, activateResponse{
+			WorkingMemory: workingMemoryResponse{
+				SessionID:   result.Session.ID.String(),
+				CurrentGoal: result.Session.CurrentGoal,
+				Activations: []activationResponse{},
+				MaxSlots:    result.MaxSlots,
+			},
+			Control: true,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newActivateResponse(result))
+}
+
+func newActivateResponse(result *domain.WorkingMemoryResult) activateResponse {
 	response := activateResponse{
 		WorkingMemory: workingMemoryResponse{
 			SessionID:   result.Session.ID.String(),
@@ -126,15 +256,19 @@ func (h *WorkingMemoryHandler) Activate(w http.ResponseWriter, r *http.Request)
 			MaxSlots:    result.MaxSlots,
 		},
 		AssembledContext: result.AssembledContext,
+		Citations:        result.Citations,
+		SkippedStages:    result.SkippedStages,
+		Trimmed:          result.Trimmed,
 	}
 
 	for _, act := range result.Activations {
 		response.WorkingMemory.Activations = append(response.WorkingMemory.Activations, activationResponse{
-			MemoryType: string(act.Type),
-			MemoryID:   act.ID.String(),
-			Content:    act.Content,
-			Confidence: act.Confidence,
-			Score:      act.Score,
+			MemoryType:  string(act.Type),
+			MemoryID:    act.ID.String(),
+			Content:     act.Content,
+			Confidence:  act.Confidence,
+			Score:       act.Score,
+			Attachments: act.Attachments,
 		})
 	}
 
@@ -146,8 +280,7 @@ func (h *WorkingMemoryHandler) Activate(w http.ResponseWriter, r *http.Request)
 			MatchScore:  sm.MatchScore,
 		})
 	}
-
-	writeJSON(w, http.StatusOK, response)
+	return response
 }
 
 // GetSession retrieves the current working memory session.
@@ -171,7 +304,12 @@ func (h *WorkingMemoryHandler) GetSession(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	session, err := h.svc.GetSession(r.Context(), agentID, tenant.ID)
+	conversationID, ok := parseConversationID(w, r.URL.Query().Get("conversation_id"))
+	if !ok {
+		return
+	}
+
+	session, err := h.svc.GetSession(r.Context(), agentID, tenant.ID, conversationID)
 	if err != nil {
 		if errors.Is(err, service.ErrSessionNotFound) {
 			writeError(w, http.StatusNotFound, "no active session for this agent")
@@ -192,6 +330,9 @@ func (h *WorkingMemoryHandler) GetSession(w http.ResponseWriter, r *http.Request
 		StartedAt:      session.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
 		LastActivityAt: session.LastActivityAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if session.ConversationID != nil {
+		response.ConversationID = session.ConversationID.String()
+	}
 
 	for _, act := range session.Activations {
 		response.Activations = append(response.Activations, activationResponse{
@@ -206,6 +347,45 @@ func (h *WorkingMemoryHandler) GetSession(w http.ResponseWriter, r *http.Request
 	writeJSON(w, http.StatusOK, response)
 }
 
+// ListSessions lists the agent's active sessions, agent-wide and per
+// conversation, with the slots each fills and their total.
+// GET /v1/cognitive/sessions?agent_id=...
+func (h *WorkingMemoryHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(r.URL.Query().Get("agent_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	usage, err := h.svc.ListSessions(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, usage)
+}
+
+// parseConversationID parses an optional conversation_id, writing a 400 and
+// returning false when it is malformed.
+func parseConversationID(w http.ResponseWriter, raw string) (*uuid.UUID, bool) {
+	if raw == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid conversation_id")
+		return nil, false
+	}
+	return &id, true
+}
+
 // UpdateGoal updates the current goal in working memory.
 // PUT /v1/cognitive/goal
 func (h *WorkingMemoryHandler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
@@ -227,7 +407,12 @@ func (h *WorkingMemoryHandler) UpdateGoal(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := h.svc.UpdateGoal(r.Context(), agentID, tenant.ID, req.Goal); err != nil {
+	conversationID, ok := parseConversationID(w, req.ConversationID)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.UpdateGoal(r.Context(), agentID, tenant.ID, conversationID, req.Goal); err != nil {
 		if errors.Is(err, service.ErrSessionNotFound) {
 			writeError(w, http.StatusNotFound, "no active session for this agent")
 			return
@@ -260,7 +445,12 @@ func (h *WorkingMemoryHandler) ClearSession(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := h.svc.ClearSession(r.Context(), agentID, tenant.ID); err != nil {
+	conversationID, ok := parseConversationID(w, req.ConversationID)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.ClearSession(r.Context(), agentID, tenant.ID, conversationID); err != nil {
 		if errors.Is(err, service.ErrSessionNotFound) {
 			writeError(w, http.StatusNotFound, "no active session for this agent")
 			return
@@ -271,3 +461,299 @@ func (h *WorkingMemoryHandler) ClearSession(w http.ResponseWriter, r *http.Reque
 
 	writeJSON(w, http.StatusOK, map[string]string{"status": "cleared"})
 }
+
+// GetReasoning returns the session's reasoning state.
+// GET /v1/cognitive/reasoning?agent_id=...
+func (h *WorkingMemoryHandler) GetReasoning(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentIDStr := r.URL.Query().Get("agent_id")
+	if agentIDStr == "" {
+		writeError(w, http.StatusBadRequest, "agent_id query parameter is required")
+		return
+	}
+
+	agentID, err := uuid.Parse(agentIDStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	conversationID, ok := parseConversationID(w, r.URL.Query().Get("conversation_id"))
+	if !ok {
+		return
+	}
+
+	session, err := h.svc.GetReasoningState(r.Context(), agentID, tenant.ID, conversationID)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			writeError(w, http.StatusNotFound, "no active session for this agent")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to get reasoning state")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reasoningStateResponse{
+		SessionID:      session.ID.String(),
+		AgentID:        session.AgentID.String(),
+		ReasoningState: session.ReasoningState,
+	})
+}
+
+// PatchReasoning merges keys into the session's reasoning state; a null value
+// removes a key. "conclusions" and "open_questions" must be string lists; open
+// questions also steer goal-directed activation.
+// PATCH /v1/cognitive/reasoning
+func (h *WorkingMemoryHandler) PatchReasoning(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req patchReasoningRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	agentID, err := uuid.Parse(req.AgentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	if len(req.State) == 0 {
+		writeError(w, http.StatusBadRequest, "state is required")
+		return
+	}
+
+	conversationID, ok := parseConversationID(w, req.ConversationID)
+	if !ok {
+		return
+	}
+
+	session, err := h.svc.PatchReasoningState(r.Context(), agentID, tenant.ID, conversationID, req.State)
+	if err != nil {
+		var invalid *service.InvalidReasoningStateError
+		switch {
+		case errors.As(err, &invalid):
+			writeError(w, http.StatusBadRequest, invalid.Error())
+		case errors.Is(err, service.ErrSessionNotFound):
+			writeError(w, http.StatusNotFound, "no active session for this agent")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to update reasoning state")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reasoningStateResponse{
+		SessionID:      session.ID.String(),
+		AgentID:        session.AgentID.String(),
+		ReasoningState: session.ReasoningState,
+	})
+}
+
+// CreateSnapshot saves the agent's current session.
+// POST /v1/cognitive/snapshots
+func (h *WorkingMemoryHandler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req createSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	agentID, err := uuid.Parse(req.AgentID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	snap, err := h.svc.Snapshot(r.Context(), agentID, tenant.ID, req.Label)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSessionNotFound):
+			writeError(w, http.StatusNotFound, "no active session for this agent")
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to snapshot session")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, snap)
+}
+
+// ListSnapshots lists the agent's snapshots, newest first.
+// GET /v1/cognitive/snapshots?agent_id=...
+func (h *WorkingMemoryHandler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(r.URL.Query().Get("agent_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+
+	snaps, err := h.svc.ListSnapshots(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list snapshots")
+		return
+	}
+	if snaps == nil {
+		snaps = []domain.WorkingMemorySnapshot{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"snapshots": snaps})
+}
+
+// RestoreSnapshot replaces the agent's session with a snapshot.
+// POST /v1/cognitive/snapshots/{id}/restore
+func (h *WorkingMemoryHandler) RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid snapshot id")
+		return
+	}
+
+	session, dropped, err := h.svc.RestoreSnapshot(r.Context(), id, tenant.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrSnapshotNotFound) {
+			writeError(w, http.StatusNotFound, "snapshot not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to restore snapshot")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, restoreSnapshotResponse{
+		SessionID:      session.ID.String(),
+		AgentID:        session.AgentID.String(),
+		CurrentGoal:    session.CurrentGoal,
+		ReasoningState: session.ReasoningState,
+		Activations:    len(session.Activations),
+		ActiveSchemas:  len(session.ActiveSchemas),
+		Dropped:        dropped,
+		MaxSlots:       session.MaxSlots,
+	})
+}
+
+// DeleteSnapshot deletes a snapshot.
+// DELETE /v1/cognitive/snapshots/{id}
+func (h *WorkingMemoryHandler) DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid snapshot id")
+		return
+	}
+
+	if err := h.svc.DeleteSnapshot(r.Context(), id, tenant.ID); err != nil {
+		if errors.Is(err, service.ErrSnapshotNotFound) {
+			writeError(w, http.StatusNotFound, "snapshot not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete snapshot")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSettings returns the agent's working memory capacity.
+// GET /v1/agents/{id}/working-memory
+func (h *WorkingMemoryHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	settings, err := h.svc.GetSettings(r.Context(), agentID, tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get working memory settings")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings sets the agent's working memory capacity. expanded_slots
+// defaults to slots (no expansion for complex goals).
+// PUT /v1/agents/{id}/working-memory
+func (h *WorkingMemoryHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent id")
+		return
+	}
+
+	var req updateWorkingMemorySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.ExpandedSlots == 0 {
+		req.ExpandedSlots = req.Slots
+	}
+
+	settings := &domain.WorkingMemorySettings{
+		AgentID:       agentID,
+		TenantID:      tenant.ID,
+		Slots:         req.Slots,
+		ExpandedSlots: req.ExpandedSlots,
+	}
+	if err := h.svc.UpdateSettings(r.Context(), settings); err != nil {
+		var invalid *service.InvalidWorkingMemorySettingsError
+		switch {
+		case errors.As(err, &invalid):
+			writeError(w, http.StatusBadRequest, invalid.Error())
+		case errors.Is(err, service.ErrAgentNotFound):
+			writeError(w, http.StatusNotFound, "agent not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to update working memory settings")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
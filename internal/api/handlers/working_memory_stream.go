@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/websocket"
+	"github.com/google/uuid"
+)
+
+const (
+	// streamContextMessages is how many of the latest messages of a streamed
+	// conversation are passed to activation as its context.
+	streamContextMessages = 20
+	// streamIdleTimeout closes a streaming session that sends nothing for
+	// this long.
+	streamIdleTimeout = 10 * time.Minute
+)
+
+// Stream message and event types.
+const (
+	streamMessageChat = "message"
+	streamMessageGoal = "goal"
+
+	streamEventReady      = "ready"
+	streamEventActivation = "activation"
+	streamEventSurfaced   = "surfaced"
+	streamEventError      = "error"
+)
+
+// streamMessage is sent up a streaming session: a conversation message, which
+// is activated on, or a new goal for the activations that follow.
+type streamMessage struct {
+	Type    string `json:"type"`
+	Role    string `json:"role,omitempty"` // of a message; defaults to user
+	Content string `json:"content,omitempty"`
+	Goal    string `json:"goal,omitempty"`
+}
+
+// streamEvent is sent down a streaming session: ready once connected, then an
+// activation per message, followed by surfaced when memories entered working
+// memory that weren't in it the turn before.
+type streamEvent struct {
+	Type             string                            `json:"type"`
+	SessionID        string                            `json:"session_id,omitempty"`
+	ConversationID   string                            `json:"conversation_id,omitempty"`
+	WorkingMemory    *workingMemoryResponse            `json:"working_memory,omitempty"`
+	AssembledContext string                            `json:"assembled_context,omitempty"`
+	Citations        map[string]domain.ContextCitation `json:"citations,omitempty"`
+	Surfaced         []activationResponse              `json:"surfaced,omitempty"`
+	Error            string                            `json:"error,omitempty"`
+}
+
+// Stream holds a WebSocket session for one conversation, activating working
+// memory in the conversation's own session on every message, without a
+// request per turn. conversation_id defaults to a new conversation, returned
+// in the ready event; vision and citations apply to every activation.
+// GET /v1/cognitive/stream?agent_id=...&conversation_id=...
+func (h *WorkingMemoryHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.TenantFromContext(r.Context())
+	if tenant == nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	agentID, err := uuid.Parse(r.URL.Query().Get("agent_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid agent_id")
+		return
+	}
+	conversationID, ok := parseConversationID(w, r.URL.Query().Get("conversation_id"))
+	if !ok {
+		return
+	}
+	if conversationID == nil {
+		id := uuid.New()
+		conversationID = &id
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close(websocket.CloseNormal, "")
+
+	if err := conn.WriteJSON(streamEvent{Type: streamEventReady, ConversationID: conversationID.String()}); err != nil {
+		return
+	}
+
+	input := domain.ActivationInput{
+		AgentID:             agentID,
+		TenantID:            tenant.ID,
+		ConversationID:      conversationID,
+		IsolateConversation: true,
+		Vision:              r.URL.Query().Get("vision") == "true",
+		Citations:           r.URL.Query().Get("citations") == "true",
+	}
+	active := make(map[string]bool)
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(streamIdleTimeout))
+		op, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg streamMessage
+		if op != websocket.OpText || json.Unmarshal(data, &msg) != nil {
+			if conn.WriteJSON(streamEvent{Type: streamEventError, Error: "expected a JSON text message"}) != nil {
+				return
+			}
+			continue
+		}
+
+		input.Cues = nil
+		switch msg.Type {
+		case streamMessageChat:
+			if msg.Content == "" {
+				if conn.WriteJSON(streamEvent{Type: streamEventError, Error: "content is required"}) != nil {
+					return
+				}
+				continue
+			}
+			if msg.Role == "" {
+				msg.Role = "user"
+			}
+			input.Context = append(input.Context, domain.Message{Role: msg.Role, Content: msg.Content})
+			if len(input.Context) > streamContextMessages {
+				input.Context = input.Context[len(input.Context)-streamContextMessages:]
+			}
+			input.Cues = []string{msg.Content}
+		case streamMessageGoal:
+			if msg.Goal == "" {
+				if conn.WriteJSON(streamEvent{Type: streamEventError, Error: "goal is required"}) != nil {
+					return
+				}
+				continue
+			}
+			input.Goal = msg.Goal
+		default:
+			if conn.WriteJSON(streamEvent{Type: streamEventError, Error: "unknown message type " + msg.Type}) != nil {
+				return
+			}
+			continue
+		}
+
+		if err := h.streamActivation(r, conn, input, active); err != nil {
+			return
+		}
+	}
+}
+
+// streamActivation activates for one turn and sends the result down, with the
+// activations that are new since the last turn as surfaced. Only a failed
+// write is returned; a failed activation is reported to the client.
+func (h *WorkingMemoryHandler) streamActivation(r *http.Request, conn *websocket.Conn, input domain.ActivationInput, active map[string]bool) error {
+	result, err := h.svc.Activate(r.Context(), input)
+	if err != nil {
+		msg := "failed to activate memories"
+		if errors.Is(err, domain.ErrInvalidActivationBudget) {
+			msg = err.Error()
+		}
+		return conn.WriteJSON(streamEvent{Type: streamEventError, Error: msg})
+	}
+
+	response := newActivateResponse(result)
+	if err := conn.WriteJSON(streamEvent{
+		Type:             streamEventActivation,
+		SessionID:        response.WorkingMemory.SessionID,
+		ConversationID:   input.ConversationID.String(),
+		WorkingMemory:    &response.WorkingMemory,
+		AssembledContext: response.AssembledContext,
+		Citations:        response.Citations,
+	}); err != nil {
+		return err
+	}
+
+	var surfaced []activationResponse
+	seen := make(map[string]bool, len(response.WorkingMemory.Activations))
+	for _, act := range response.WorkingMemory.Activations {
+		key := act.MemoryType + ":" + act.MemoryID
+		seen[key] = true
+		if !active[key] {
+			surfaced = append(surfaced, act)
+		}
+	}
+	clear(active)
+	for key := range seen {
+		active[key] = true
+	}
+	if len(surfaced) == 0 {
+		return nil
+	}
+	return conn.WriteJSON(streamEvent{Type: streamEventSurfaced, Surfaced: surfaced})
+}
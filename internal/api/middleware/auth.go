@@ -40,6 +40,15 @@ func AuthFromContext(ctx context.Context) *domain.APIKeyAuth {
 	return a
 }
 
+// withAuth stores auth in ctx, along with the tenant ID the store layer
+// routes on.
+func withAuth(ctx context.Context, auth *domain.APIKeyAuth) context.Context {
+	if auth.Tenant != nil {
+		ctx = domain.WithTenantID(ctx, auth.Tenant.ID)
+	}
+	return context.WithValue(ctx, authContextKey, auth)
+}
+
 // APIKeyAuth authenticates requests using the api_keys table.
 // On success it stores *domain.APIKeyAuth in the request context and
 // fires a non-blocking last_used_at update.
@@ -71,8 +80,7 @@ func APIKeyAuth(apiKeyStore domain.APIKeyStore) func(http.Handler) http.Handler
 				_ = apiKeyStore.UpdateLastUsed(context.Background(), auth.KeyID)
 			}(auth.KeyID)
 
-			ctx := context.WithValue(r.Context(), authContextKey, auth)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(withAuth(r.Context(), auth)))
 		})
 	}
 }
@@ -97,7 +105,7 @@ func SessionOrAPIKey(apiKeyStore domain.APIKeyStore, resolver SessionResolver, a
 							writeError(w, http.StatusForbidden, "cross-origin request blocked")
 							return
 						}
-						next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authContextKey, auth)))
+						next.ServeHTTP(w, r.WithContext(withAuth(r.Context(), auth)))
 						return
 					}
 				}
@@ -119,7 +127,7 @@ func SessionOrAPIKey(apiKeyStore domain.APIKeyStore, resolver SessionResolver, a
 				return
 			}
 			go func() { _ = apiKeyStore.UpdateLastUsed(context.Background(), auth.KeyID) }()
-			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authContextKey, auth)))
+			next.ServeHTTP(w, r.WithContext(withAuth(r.Context(), auth)))
 		})
 	}
 }
@@ -139,15 +147,26 @@ func RequireScope(scope string) func(http.Handler) http.Handler {
 	}
 }
 
+// readOnlyPosts are POST endpoints that only read, taking a body because their
+// input doesn't fit a query string.
+var readOnlyPosts = map[string]bool{
+	"/v1/memories/recall/batch": true,
+}
+
 // RequireWriteForMutations enforces the "write" scope on any state-changing
 // request (POST/PUT/PATCH/DELETE) while leaving safe, read-only methods
-// (GET/HEAD/OPTIONS) open to read-scoped keys.
+// (GET/HEAD/OPTIONS) and readOnlyPosts open to read-scoped keys.
 func RequireWriteForMutations(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet, http.MethodHead, http.MethodOptions:
 			next.ServeHTTP(w, r)
 			return
+		case http.MethodPost:
+			if readOnlyPosts[strings.TrimSuffix(r.URL.Path, "/")] {
+				next.ServeHTTP(w, r)
+				return
+			}
 		}
 		auth := AuthFromContext(r.Context())
 		if auth == nil || !auth.HasScope("write") {
@@ -33,7 +33,7 @@ func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 					w.Header().Add("Vary", "Origin")
 				}
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Setup-Token, X-Request-ID")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Setup-Token, X-Request-ID, Idempotency-Key")
 				w.Header().Set("Access-Control-Max-Age", "600")
 			}
 
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	IdempotencyKeyTTL         = 24 * time.Hour
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// idempotencyResponseWriter tees the response so it can be stored for replay.
+type idempotencyResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func writeIdempotencyError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// Idempotency makes create endpoints safe to retry. A request carrying an
+// Idempotency-Key header runs once per tenant and key; retries within
+// IdempotencyKeyTTL get the stored response back with Idempotent-Replayed set.
+// Reusing a key for a different request is a 422, and a retry that arrives
+// while the original is still running is a 409. Server errors are not stored,
+// so the client can retry them with the same key. Requests without the header
+// pass through untouched.
+func Idempotency(store domain.IdempotencyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if store == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			tenant := TenantFromContext(r.Context())
+			if key == "" || tenant == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeIdempotencyError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentRequestBytes+1))
+			if err != nil {
+				writeIdempotencyError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			if len(body) > maxIdempotentRequestBytes {
+				writeIdempotencyError(w, http.StatusRequestEntityTooLarge, "request body too large for an idempotent request")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := requestFingerprint(r, body)
+			existing, reserved, err := store.Reserve(r.Context(), tenant.ID, key, hash, IdempotencyKeyTTL)
+			if err != nil {
+				writeIdempotencyError(w, http.StatusConflict, "could not claim idempotency key, retry the request")
+				return
+			}
+
+			if !reserved {
+				switch {
+				case existing.RequestHash != hash:
+					writeIdempotencyError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				case !existing.Completed():
+					writeIdempotencyError(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+				default:
+					if existing.ContentType != "" {
+						w.Header().Set("Content-Type", existing.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(existing.StatusCode)
+					_, _ = w.Write(existing.ResponseBody)
+				}
+				return
+			}
+
+			iw := &idempotencyResponseWriter{ResponseWriter: w}
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				// Panic or server error: free the key so the retry runs again.
+				_ = store.Release(context.Background(), tenant.ID, key)
+			}()
+
+			next.ServeHTTP(iw, r)
+
+			status := iw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= 500 {
+				return
+			}
+			if err := store.Complete(context.Background(), tenant.ID, key, status, iw.Header().Get("Content-Type"), iw.body.Bytes()); err != nil {
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// requestFingerprint hashes what makes two requests "the same" for replay:
+// method, path and body.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fakeIdempotencyStore is an in-memory domain.IdempotencyStore for middleware tests.
+type fakeIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (f *fakeIdempotencyStore) Reserve(_ context.Context, tid uuid.UUID, key, hash string, ttl time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rec, ok := f.records[tid.String()+key]; ok {
+		cp := *rec
+		return &cp, false, nil
+	}
+	f.records[tid.String()+key] = &domain.IdempotencyRecord{TenantID: tid, Key: key, RequestHash: hash, ExpiresAt: time.Now().Add(ttl)}
+	return nil, true, nil
+}
+
+func (f *fakeIdempotencyStore) Complete(_ context.Context, tid uuid.UUID, key string, status int, contentType string, body []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rec := f.records[tid.String()+key]
+	rec.StatusCode = status
+	rec.ContentType = contentType
+	rec.ResponseBody = append([]byte(nil), body...)
+	return nil
+}
+
+func (f *fakeIdempotencyStore) Release(_ context.Context, tid uuid.UUID, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rec, ok := f.records[tid.String()+key]; ok && !rec.Completed() {
+		delete(f.records, tid.String()+key)
+	}
+	return nil
+}
+
+func (f *fakeIdempotencyStore) DeleteExpired(context.Context) (int64, error) { return 0, nil }
+
+func idempotentRequest(ctx context.Context, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/memories", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req.WithContext(ctx)
+}
+
+func TestIdempotency_ReplaysCompletedRequest(t *testing.T) {
+	calls := 0
+	h := Idempotency(newFakeIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"abc"}`))
+	}))
+	ctx := withTenant(httptest.NewRequest(http.MethodPost, "/", nil)).Context()
+
+	first := httptest.NewRecorder()
+	h.ServeHTTP(first, idempotentRequest(ctx, "k1", `{"content":"x"}`))
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, idempotentRequest(ctx, "k1", `{"content":"x"}`))
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != `{"id":"abc"}` {
+		t.Errorf("replay = %d %q, want 201 with original body", second.Code, second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("replay missing Idempotent-Replayed header")
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("original response should not be marked as replayed")
+	}
+}
+
+func TestIdempotency_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	h := Idempotency(newFakeIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	ctx := withTenant(httptest.NewRequest(http.MethodPost, "/", nil)).Context()
+
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(ctx, "k1", `{"content":"x"}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(ctx, "k1", `{"content":"y"}`))
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	calls := 0
+	h := Idempotency(newFakeIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	ctx := withTenant(httptest.NewRequest(http.MethodPost, "/", nil)).Context()
+
+	h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(ctx, "k1", `{}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, idempotentRequest(ctx, "k1", `{}`))
+
+	if calls != 2 || rec.Code != http.StatusCreated {
+		t.Fatalf("calls = %d status = %d, want retry to run and return 201", calls, rec.Code)
+	}
+}
+
+func TestIdempotency_KeysAreScopedPerTenant(t *testing.T) {
+	calls := 0
+	h := Idempotency(newFakeIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for i := 0; i < 2; i++ {
+		ctx := withTenant(httptest.NewRequest(http.MethodPost, "/", nil)).Context()
+		h.ServeHTTP(httptest.NewRecorder(), idempotentRequest(ctx, "shared", `{}`))
+	}
+	if calls != 2 {
+		t.Fatalf("handler ran %d times, want once per tenant", calls)
+	}
+}
@@ -29,6 +29,12 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// hijack the connection for a WebSocket.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logging returns middleware that logs each request with structured JSON output.
 func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -63,6 +69,7 @@ func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Int64("bytes", rw.written),
 				zap.Duration("duration", duration),
 				zap.String("request_id", requestID),
+				zap.String("correlation_id", CorrelationIDFromContext(r.Context())),
 				zap.String("tenant_id", tenantID),
 				zap.String("remote_addr", r.RemoteAddr),
 				zap.String("user_agent", r.UserAgent()),
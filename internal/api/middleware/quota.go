@@ -122,11 +122,23 @@ func MeterRecall(billing domain.BillingStore, enabled bool) func(http.Handler) h
 				next.ServeHTTP(w, r)
 				return
 			}
+			count := int64(1)
+			r = r.WithContext(context.WithValue(r.Context(), recallCountKey{}, &count))
 			qw := &quotaResponseWriter{ResponseWriter: w}
 			next.ServeHTTP(qw, r)
 			if qw.ok() {
-				go func() { _ = billing.IncrementRecalls(context.Background(), tenant.ID, 1) }()
+				go func() { _ = billing.IncrementRecalls(context.Background(), tenant.ID, count) }()
 			}
 		})
 	}
 }
+
+type recallCountKey struct{}
+
+// CountRecalls records that the request served n recalls, so a batch is
+// metered per query rather than once. MeterRecall counts one otherwise.
+func CountRecalls(ctx context.Context, n int) {
+	if count, ok := ctx.Value(recallCountKey{}).(*int64); ok {
+		*count = int64(n)
+	}
+}
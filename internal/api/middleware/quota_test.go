@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
@@ -139,3 +140,27 @@ func TestEnforceMemoryQuota_FailsOpenOnStoreError(t *testing.T) {
 		t.Fatalf("should fail open on lookup error, got %d", rec.Code)
 	}
 }
+
+func TestMeterRecall_CountsEveryQueryOfABatch(t *testing.T) {
+	store := &fakeBillingStore{plan: domain.PlanDeveloper}
+	h := MeterRecall(store, true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		CountRecalls(r.Context(), 3)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), withTenant(httptest.NewRequest(http.MethodPost, "/v1/memories/recall/batch", nil)))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		recalls := store.recalls
+		store.mu.Unlock()
+		if recalls == 3 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 recalls metered, got %d", recalls)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
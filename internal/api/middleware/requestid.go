@@ -4,38 +4,68 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 )
 
 const (
 	// RequestIDHeader is the header name for request ID.
 	RequestIDHeader = "X-Request-ID"
-	requestIDKey    = contextKey("request_id")
+	// CorrelationIDHeader is the header name for correlation ID.
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	maxTraceIDLen = 128
 )
 
 // RequestIDFromContext returns the request ID from context.
 func RequestIDFromContext(ctx context.Context) string {
-	id, _ := ctx.Value(requestIDKey).(string)
-	return id
+	return domain.RequestIDFrom(ctx)
+}
+
+// CorrelationIDFromContext returns the correlation ID from context.
+func CorrelationIDFromContext(ctx context.Context) string {
+	return domain.CorrelationIDFrom(ctx)
 }
 
 // RequestID middleware extracts or generates a request ID for each request.
 // If X-Request-ID header is present, it uses that value.
 // Otherwise, it generates a new UUID.
-// The request ID is added to the response headers and stored in context.
+// X-Correlation-ID is taken from the request too, defaulting to the request ID.
+// Both are added to the response headers and stored in context, where
+// service logs, async jobs and outbound LLM calls pick them up.
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestID := r.Header.Get(RequestIDHeader)
+		requestID := sanitizeTraceID(r.Header.Get(RequestIDHeader))
 		if requestID == "" {
 			requestID = uuid.NewString()
 		}
+		correlationID := sanitizeTraceID(r.Header.Get(CorrelationIDHeader))
+		if correlationID == "" {
+			correlationID = requestID
+		}
 
 		// Add to response header
 		w.Header().Set(RequestIDHeader, requestID)
+		w.Header().Set(CorrelationIDHeader, correlationID)
 
 		// Add to context
-		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		ctx := domain.WithRequestID(r.Context(), requestID)
+		ctx = domain.WithCorrelationID(ctx, correlationID)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// sanitizeTraceID drops client-supplied IDs that are too long or contain
+// anything but visible ASCII, so they can't forge log lines or headers.
+func sanitizeTraceID(id string) string {
+	if len(id) > maxTraceIDLen {
+		return ""
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return ""
+		}
+	}
+	return id
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID_PropagatesIDs(t *testing.T) {
+	var gotRequest, gotCorrelation string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = RequestIDFromContext(r.Context())
+		gotCorrelation = CorrelationIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name            string
+		requestID       string
+		correlationID   string
+		wantRequest     string // "" = generated
+		wantCorrelation string // "" = same as request ID
+	}{
+		{name: "generated", wantRequest: ""},
+		{name: "client request ID", requestID: "req-1", wantRequest: "req-1"},
+		{name: "client correlation ID", requestID: "req-2", correlationID: "flow-9", wantRequest: "req-2", wantCorrelation: "flow-9"},
+		{name: "unsafe IDs replaced", requestID: "bad\nid", correlationID: strings.Repeat("x", 200), wantRequest: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			if tt.correlationID != "" {
+				req.Header.Set(CorrelationIDHeader, tt.correlationID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if gotRequest == "" || (tt.wantRequest != "" && gotRequest != tt.wantRequest) {
+				t.Errorf("request ID = %q, want %q", gotRequest, tt.wantRequest)
+			}
+			if tt.wantRequest == "" && gotRequest == tt.requestID {
+				t.Errorf("request ID %q was not regenerated", gotRequest)
+			}
+			wantCorrelation := tt.wantCorrelation
+			if wantCorrelation == "" {
+				wantCorrelation = gotRequest
+			}
+			if gotCorrelation != wantCorrelation {
+				t.Errorf("correlation ID = %q, want %q", gotCorrelation, wantCorrelation)
+			}
+			if rec.Header().Get(RequestIDHeader) != gotRequest || rec.Header().Get(CorrelationIDHeader) != gotCorrelation {
+				t.Errorf("response headers = %v", rec.Header())
+			}
+		})
+	}
+}
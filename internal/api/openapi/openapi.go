@@ -0,0 +1,293 @@
+// Package openapi builds an OpenAPI 3 document for the HTTP API. Paths come
+// from walking the chi router, so every mounted endpoint is listed; request
+// and response bodies come from the Go types registered with Describe, turned
+// into JSON schemas by reflection.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const bearerScheme = "bearerAuth"
+
+// Document is the subset of an OpenAPI 3.0 document the generator emits.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps a lower-case HTTP method to its operation.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+// Param is a query parameter of a described operation.
+type Param struct {
+	Name        string
+	Type        string // "string" (default), "integer", "number" or "boolean"
+	Required    bool
+	Description string
+}
+
+// Op is what the router can't tell about an operation. Request and Response
+// are values of the body types (a nil pointer of the type is enough); Status
+// is the success status, 200 by default.
+type Op struct {
+	Summary  string
+	Query    []Param
+	Request  any
+	Response any
+	Status   int
+}
+
+// Generator collects operation descriptions and builds the document.
+type Generator struct {
+	ops     map[string]Op
+	public  []string
+	schemas *schemaSet
+}
+
+func New() *Generator {
+	return &Generator{ops: make(map[string]Op), schemas: newSchemaSet()}
+}
+
+// Describe records the bodies and summary of the operation at method and
+// pattern, written as mounted ("/v1/agents/{id}").
+func (g *Generator) Describe(method, pattern string, op Op) {
+	g.ops[strings.ToUpper(method)+" "+normalizePath(pattern)] = op
+}
+
+// Public marks paths under the given prefixes as not needing an API key.
+func (g *Generator) Public(prefixes ...string) {
+	g.public = append(g.public, prefixes...)
+}
+
+var paramPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build walks routes and returns the document.
+func (g *Generator) Build(routes chi.Routes, info Info) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", Description: "Engram API key"},
+			},
+		},
+		Security: []map[string][]string{{bearerScheme: {}}},
+	}
+	errorSchema := g.schemas.of(reflect.TypeOf(apiError{}))
+	seenIDs := make(map[string]int)
+
+	err := chi.Walk(routes, func(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.Contains(route, "*") {
+			return nil
+		}
+		path := paramPattern.ReplaceAllString(normalizePath(route), "{$1}")
+		desc := g.ops[method+" "+path]
+		tag, name := handlerName(handler)
+		id := lowerFirst(tag + name)
+		if tag == "" {
+			tag = firstSegment(path)
+		}
+		if n := seenIDs[id]; n > 0 {
+			seenIDs[id] = n + 1
+			id = fmt.Sprintf("%s%d", id, n+1)
+		} else {
+			seenIDs[id] = 1
+		}
+
+		op := &Operation{
+			OperationID: id,
+			Summary:     desc.Summary,
+			Tags:        []string{tag},
+			Responses:   make(map[string]Response),
+		}
+		for _, m := range paramPattern.FindAllStringSubmatch(path, -1) {
+			op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+		for _, q := range desc.Query {
+			typ := q.Type
+			if typ == "" {
+				typ = "string"
+			}
+			op.Parameters = append(op.Parameters, Parameter{Name: q.Name, In: "query", Description: q.Description, Required: q.Required, Schema: &Schema{Type: typ}})
+		}
+		if desc.Request != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.schemas.of(reflect.TypeOf(desc.Request)))}
+		}
+
+		status := desc.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		ok := Response{Description: http.StatusText(status)}
+		if desc.Response != nil {
+			ok.Content = jsonContent(g.schemas.of(reflect.TypeOf(desc.Response)))
+		}
+		op.Responses[fmt.Sprint(status)] = ok
+		op.Responses["default"] = Response{Description: "Error", Content: jsonContent(errorSchema)}
+
+		if g.isPublic(path) {
+			op.Security = &[]map[string][]string{}
+		}
+
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(method)] = op
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	doc.Components.Schemas = g.schemas.defs
+	return doc, nil
+}
+
+// apiError is the body of every error response.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (g *Generator) isPublic(path string) bool {
+	for _, p := range g.public {
+		if path == p || strings.HasPrefix(path, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// normalizePath drops the trailing slash chi leaves on subrouter roots.
+func normalizePath(p string) string {
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+func firstSegment(path string) string {
+	for _, s := range strings.Split(path, "/") {
+		if s != "" && s != "v1" && !strings.HasPrefix(s, "{") {
+			return upperFirst(s)
+		}
+	}
+	return "Root"
+}
+
+// handlerName derives a tag and method name from a handler's function name,
+// e.g. handlers.(*MemoryHandler).Recall-fm gives ("Memory", "Recall").
+func handlerName(h http.Handler) (tag, name string) {
+	v := reflect.ValueOf(h)
+	if v.Kind() != reflect.Func {
+		t := reflect.Indirect(v).Type()
+		return "", upperFirst(t.Name())
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return "", "Handler"
+	}
+	full := fn.Name()
+	full = full[strings.LastIndex(full, "/")+1:]
+	if i := strings.Index(full, ".func"); i >= 0 {
+		full = full[:i]
+	}
+	full = strings.TrimSuffix(full, "-fm")
+	parts := strings.Split(full, ".")
+	name = upperFirst(strings.TrimSuffix(parts[len(parts)-1], "Handler"))
+	if len(parts) >= 3 {
+		recv := strings.Trim(parts[len(parts)-2], "(*)")
+		tag = upperFirst(strings.TrimSuffix(recv, "Handler"))
+	}
+	return tag, name
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// lowerFirst lower-cases a leading word or initialism: "MCP" gives "mcp",
+// "WorkingMemory" gives "workingMemory".
+func lowerFirst(s string) string {
+	r := []rune(s)
+	for i := range r {
+		if !unicode.IsUpper(r[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type widgetHandler struct{}
+
+type widget struct {
+	ID        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"created_at"`
+	Parent    *widget           `json:"parent,omitempty"`
+	Secret    string            `json:"-"`
+}
+
+type createWidgetRequest struct {
+	Name string `json:"name"`
+}
+
+type widgetResponse struct {
+	*widget
+	Score float32 `json:"score"`
+}
+
+func (widgetHandler) Create(http.ResponseWriter, *http.Request) {}
+func (widgetHandler) Get(http.ResponseWriter, *http.Request)    {}
+
+func TestBuild(t *testing.T) {
+	var h widgetHandler
+	r := chi.NewRouter()
+	r.Get("/health", func(http.ResponseWriter, *http.Request) {})
+	r.Route("/v1/widgets", func(r chi.Router) {
+		r.Post("/", h.Create)
+		r.Get("/{id:[0-9a-f-]+}", h.Get)
+	})
+
+	g := New()
+	g.Public("/health")
+	g.Describe(http.MethodPost, "/v1/widgets", Op{Summary: "Create a widget", Request: createWidgetRequest{}, Response: widget{}, Status: http.StatusCreated})
+	g.Describe(http.MethodGet, "/v1/widgets/{id}", Op{Query: []Param{{Name: "verbose", Type: "boolean"}}, Response: widgetResponse{}})
+
+	doc, err := g.Build(r, Info{Title: "test", Version: "1"})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	create := doc.Paths["/v1/widgets"]["post"]
+	if create == nil || create.OperationID != "widgetCreate" || create.Tags[0] != "Widget" {
+		t.Fatalf("create op = %+v", create)
+	}
+	if create.RequestBody == nil || create.Responses["201"].Content["application/json"].Schema.Ref != "#/components/schemas/Widget" {
+		t.Errorf("create bodies = %+v, %+v", create.RequestBody, create.Responses)
+	}
+
+	get := doc.Paths["/v1/widgets/{id}"]["get"]
+	if get == nil || len(get.Parameters) != 2 || get.Parameters[0].In != "path" || get.Parameters[1].Name != "verbose" {
+		t.Fatalf("get op = %+v", get)
+	}
+	if get.Security != nil {
+		t.Error("authenticated op should inherit the document's security")
+	}
+	if health := doc.Paths["/health"]["get"]; health == nil || health.Security == nil || len(*health.Security) != 0 {
+		t.Errorf("public op should clear security, got %+v", health)
+	}
+
+	w := doc.Components.Schemas["Widget"]
+	if w == nil {
+		t.Fatal("Widget schema not defined")
+	}
+	if _, ok := w.Properties["Secret"]; ok {
+		t.Error(`json:"-" field documented`)
+	}
+	if w.Properties["id"].Format != "uuid" || w.Properties["created_at"].Format != "date-time" ||
+		w.Properties["tags"].Items.Type != "string" || w.Properties["labels"].AdditionalProperties.Type != "string" ||
+		w.Properties["parent"].Ref != "#/components/schemas/Widget" {
+		b, _ := json.Marshal(w)
+		t.Errorf("Widget schema = %s", b)
+	}
+	resp := doc.Components.Schemas["WidgetResponse"]
+	if resp == nil || resp.Properties["name"] == nil || resp.Properties["score"] == nil {
+		t.Errorf("embedded struct not flattened: %+v", resp)
+	}
+}
+
+func TestLowerFirst(t *testing.T) {
+	for in, want := range map[string]string{"MCP": "mcp", "WorkingMemory": "workingMemory", "VectorIndex": "vectorIndex", "": ""} {
+		if got := lowerFirst(in); got != want {
+			t.Errorf("lowerFirst(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
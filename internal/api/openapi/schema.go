@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is the subset of an OpenAPI 3.0 schema object the generator emits.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+	rawJSONType  = reflect.TypeOf(json.RawMessage{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// schemaSet turns Go types into schemas, naming each struct type once under
+// components.schemas.
+type schemaSet struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{defs: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+func (s *schemaSet) of(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawJSONType:
+		return &Schema{}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := s.of(t.Elem())
+		if inner.Ref == "" {
+			inner.Nullable = true
+		}
+		return inner
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.define(t)}
+	default:
+		return &Schema{}
+	}
+}
+
+// define registers a named struct type, returning its component name. The
+// name is reserved before the fields are walked so self-referencing types
+// terminate.
+func (s *schemaSet) define(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := upperFirst(strings.NewReplacer("[", "_", "]", "", "/", "_", "*", "").Replace(t.Name()))
+	if _, taken := s.defs[name]; taken {
+		pkg := t.PkgPath()
+		name = upperFirst(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	s.names[t] = name
+	s.defs[name] = &Schema{}
+	*s.defs[name] = *s.structSchema(t)
+	return name
+}
+
+func (s *schemaSet) structSchema(t reflect.Type) *Schema {
+	out := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(out, t)
+	return out
+}
+
+// addFields follows encoding/json's rules closely enough for documentation:
+// unexported and "-" fields are skipped, untagged embedded structs are
+// flattened, and the tag name (or the field name) is the property name.
+func (s *schemaSet) addFields(out *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(out, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out.Properties[name] = s.of(ft)
+	}
+}
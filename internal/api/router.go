@@ -1,20 +1,21 @@
 package api
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 	"runtime"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/Harshitk-cp/engram"
 	"github.com/Harshitk-cp/engram/console"
 	"github.com/Harshitk-cp/engram/internal/api/handlers"
 	mw "github.com/Harshitk-cp/engram/internal/api/middleware"
+	"github.com/Harshitk-cp/engram/internal/api/openapi"
 	"github.com/Harshitk-cp/engram/internal/billing"
+	"github.com/Harshitk-cp/engram/internal/buildconfig"
 	"github.com/Harshitk-cp/engram/internal/config"
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/embedding"
@@ -27,141 +28,67 @@ import (
 	"go.uber.org/zap"
 )
 
-// App holds the router and background services for lifecycle management.
+// App serves the engine's services over HTTP. Start and Stop, promoted from
+// the engine, run its background workers.
 type App struct {
-	Router        *chi.Mux
-	Tuner         *service.TunerService
-	Expirer       *service.ExpirerService
-	Decay         *service.DecayService
-	Consolidation *service.ConsolidationService
-	Learning      *service.LearningService
-	startTime     time.Time
-	requestCount  atomic.Int64
-	errorCount    atomic.Int64
+	*engram.Engine
+	Router       *chi.Mux
+	logLevel     *zap.AtomicLevel
+	logger       *zap.Logger
+	startTime    time.Time
+	requestCount atomic.Int64
+	errorCount   atomic.Int64
 }
 
-func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
-	// Stores
-	tenantStore := store.NewTenantStore(db)
-	agentStore := store.NewAgentStore(db)
-	memoryStore := store.NewMemoryStore(db)
-	policyStore := store.NewPolicyStore(db)
-	feedbackStore := store.NewFeedbackStore(db)
-	contradictionStore := store.NewContradictionStore(db)
-	episodeStore := store.NewEpisodeStore(db)
-	procedureStore := store.NewProcedureStore(db)
-	schemaStore := store.NewSchemaStore(db)
-	wmStore := store.NewWorkingMemoryStore(db)
-	assocStore := store.NewMemoryAssociationStore(db)
-	graphStore := store.NewGraphStore(db)
-	entityStore := store.NewEntityStore(db)
-	sessionStore := store.NewSessionStore(db)
-	mutationLogStore := store.NewMutationLogStore(db)
-	episodeMemUsageStore := store.NewEpisodeMemoryUsageStore(db)
-	learningStatsStore := store.NewLearningStatsStore(db)
-
-	// Unit of work for atomic state-change + audit-log writes.
-	uow := store.NewUnitOfWork(db, memoryStore, mutationLogStore, contradictionStore)
-
-	// External clients via provider factory
-	var embeddingClient domain.EmbeddingClient
-	var llmClient domain.LLMClient
-
-	llmProvider := config.LLMProvider()
-	llmAPIKey := config.LLMAPIKey()
-	embeddingProvider := config.EmbeddingProvider()
-
-	var err error
-	llmClient, err = llm.NewClient(llmProvider, llmAPIKey)
+// SetLogLevel lets config reloads change the level of the server's logger.
+func (a *App) SetLogLevel(level zap.AtomicLevel) {
+	a.logLevel = &level
+	a.applyRuntimeConfig()
+}
+
+// ReloadConfig re-reads the config file and applies the reloadable settings
+// to the running services.
+func (a *App) ReloadConfig() (*config.ReloadResult, error) {
+	res, err := config.Reload()
 	if err != nil {
-		logger.Warn("LLM client initialization failed", zap.String("provider", llmProvider), zap.Error(err))
-	} else {
-		logger.Info("LLM client initialized", zap.String("provider", llmProvider))
+		return nil, err
 	}
+	a.applyRuntimeConfig()
+	return res, nil
+}
 
-	embeddingClient, err = embedding.NewClient(embedding.Config{
-		Provider:   embeddingProvider,
-		APIKey:     config.EmbeddingAPIKey(),
-		BaseURL:    config.EmbeddingBaseURL(),
-		Model:      config.EmbeddingModel(),
-		Dimensions: config.EmbeddingDim(),
-	})
-	if err != nil {
-		logger.Warn("Embedding client initialization failed", zap.String("provider", embeddingProvider), zap.Error(err))
-	} else {
-		logger.Info("Embedding client initialized",
-			zap.String("provider", embeddingProvider),
-			zap.String("model", config.EmbeddingModel()),
-			zap.Int("dimension", config.EmbeddingDim()))
-		if embeddingProvider != embedding.ProviderMock {
-			probeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			if vec, perr := embeddingClient.Embed(probeCtx, "dimension probe"); perr != nil {
-				logger.Warn("embedding provider probe failed (continuing); recall will be empty until it works", zap.Error(perr))
-			} else if len(vec) != config.EmbeddingDim() {
-				logger.Error("embedding dimension mismatch — writes will fail until resolved",
-					zap.Int("model_output_dim", len(vec)),
-					zap.Int("configured_dim", config.EmbeddingDim()),
-					zap.String("hint", "set EMBEDDING_DIM to the model's dimension (on a fresh DB) or pick a matching model"))
-			}
-			cancel()
+// applyRuntimeConfig pushes the current values of reloadable settings into
+// the services that use them.
+func (a *App) applyRuntimeConfig() {
+	if a.logLevel != nil {
+		if err := a.logLevel.UnmarshalText([]byte(config.LogLevel())); err != nil {
+			a.logger.Warn("invalid log level", zap.String("level", config.LogLevel()), zap.Error(err))
 		}
 	}
-
-	// Services
-	agentSvc := service.NewAgentService(agentStore)
-	memorySvc := service.NewMemoryService(memoryStore, agentStore, embeddingClient, llmClient, logger)
-	policySvc := service.NewPolicyService(policyStore, memoryStore, agentStore, llmClient, embeddingClient, logger)
-	feedbackSvc := service.NewFeedbackService(feedbackStore, memoryStore, agentStore)
-	feedbackSvc.SetUnitOfWork(uow)
-	tunerSvc := service.NewTunerService(feedbackStore, policyStore, logger)
-	expirerSvc := service.NewExpirerService(memoryStore, policyStore, feedbackStore, logger)
-	expirerSvc.SetSessionStore(sessionStore)
-	confidenceSvc := service.NewConfidenceService(memoryStore, logger)
-	episodeSvc := service.NewEpisodeService(episodeStore, agentStore, embeddingClient, llmClient, logger)
-	proceduralSvc := service.NewProceduralService(procedureStore, episodeStore, agentStore, embeddingClient, llmClient, logger)
-	schemaSvc := service.NewSchemaService(schemaStore, memoryStore, agentStore, embeddingClient, llmClient, logger)
-	wmSvc := service.NewWorkingMemoryService(wmStore, assocStore, memoryStore, episodeStore, procedureStore, schemaStore, embeddingClient, logger)
-	consolidationSvc := service.NewConsolidationService(memoryStore, episodeStore, procedureStore, schemaStore, assocStore, contradictionStore, embeddingClient, llmClient, logger)
-	decaySvc := service.NewDecayService(memoryStore, episodeStore, logger)
-	decaySvc.SetMutationLogStore(mutationLogStore)
-	decaySvc.SetUnitOfWork(uow)
-
-	// Per-tenant engine tuning (decay rate, floor, competition, confidence deltas).
-	tenantSettingsStore := store.NewTenantSettingsStore(db)
-	confidenceSvc.SetSettingsStore(tenantSettingsStore)
-	decaySvc.SetSettingsStore(tenantSettingsStore)
-	consolidationSvc.SetDecayService(decaySvc)
-	consolidationSvc.SetGraphStore(graphStore)
-	metacognitiveSvc := service.NewMetacognitiveService(memoryStore, episodeStore, procedureStore, schemaStore, contradictionStore, embeddingClient, logger)
-	adminSvc := service.NewAdminService(memoryStore, embeddingClient, uow, logger)
-	consoleSvc := service.NewConsoleService(memoryStore, contradictionStore, learningStatsStore, logger)
-
-	// Graph services
-	hybridRecallSvc := service.NewHybridRecallService(memoryStore, graphStore, entityStore, embeddingClient, llmClient)
-	hybridRecallSvc.SetSessionStore(sessionStore)
-	graphBuilderSvc := service.NewGraphBuilderService(memoryStore, graphStore, entityStore, embeddingClient, llmClient, logger)
-
-	// Learning services
-	learningSvc := service.NewLearningService(memoryStore, episodeStore, logger)
-	learningSvc.SetMutationLogStore(mutationLogStore)
-	learningSvc.SetEpisodeMemoryUsageStore(episodeMemUsageStore)
-	learningSvc.SetLearningStatsStore(learningStatsStore)
-	learningSvc.SetUnitOfWork(uow)
-	implicitFeedbackSvc := service.NewImplicitFeedbackDetector(llmClient, feedbackStore, memoryStore, logger)
-	implicitFeedbackSvc.SetMutationLogStore(mutationLogStore)
-
-	// Wire policy enforcer and contradiction store into memory service
-	memorySvc.SetPolicyEnforcer(policySvc)
-	memorySvc.SetContradictionStore(contradictionStore)
-	memorySvc.SetMutationLogStore(mutationLogStore)
-	memorySvc.SetSettingsStore(tenantSettingsStore) // Provenance Firewall policy
-	memorySvc.SetUnitOfWork(uow)
-	if os.Getenv("DISABLE_GRAPH") != "true" {
-		memorySvc.SetGraphBuilder(graphBuilderSvc)
+	for _, task := range a.Scheduler.List() {
+		if err := a.Scheduler.SetInterval(task.Name, config.TaskInterval(task.Name)); err != nil {
+			a.logger.Warn("failed to set task interval", zap.String("task", task.Name), zap.Error(err))
+		}
 	}
+	a.RecallLog.SetSampleRate(config.RecallLogSampleRate())
+}
+
+func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
+	return NewTenantApp(store.NewTenantRouter(db), logger)
+}
 
-	// Wire memory store into episode service for belief extraction
-	episodeSvc.SetMemoryStore(memoryStore)
+// NewTenantApp builds the engine over a tenant router and serves it. Tenant
+// data is read and written in the database each tenant is routed to; the
+// control plane (tenants, users, API keys, billing, idempotency, settings) and
+// the vector index admin stay on the router's default database. Background
+// sweeps run once per database.
+func NewTenantApp(tenants *store.TenantRouter, logger *zap.Logger) *App {
+	eng, err := engram.New(engram.Options{Tenants: tenants, Logger: logger})
+	if err != nil {
+		logger.Fatal("failed to wire services", zap.Error(err))
+	}
+	db := tenants.Default()
+	st := eng.Stores
 
 	// Key store
 	apiKeyStore := store.NewAPIKeyStore(db)
@@ -170,6 +97,7 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 	// Razorpay endpoints activate only when RAZORPAY_KEY_ID + RAZORPAY_KEY_SECRET
 	// are configured; an unconfigured (self-hosted/OSS) server runs unmetered.
 	billingStore := store.NewBillingStore(db)
+	billingStore.SetAgentsDB(tenants)
 	rzpClient := billing.New(config.RazorpayKeyID(), config.RazorpayKeySecret(), config.RazorpayWebhookSecret(), config.RazorpayPlanIDs())
 	billingEnabled := config.BillingEnabled()
 	if billingEnabled {
@@ -182,52 +110,79 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 	membershipStore := store.NewMembershipStore(db)
 	consoleSessionStore := store.NewConsoleSessionStore(db)
 	sessionTTL := time.Duration(config.SessionTTLHours()) * time.Hour
-	authSvc := service.NewAuthService(userStore, oauthStore, membershipStore, consoleSessionStore, tenantStore, config.DefaultTenantID(), config.DefaultTenantRole(), sessionTTL, logger)
+	authSvc := service.NewAuthService(userStore, oauthStore, membershipStore, consoleSessionStore, st.Tenants, config.DefaultTenantID(), config.DefaultTenantRole(), sessionTTL, logger)
 
 	// Handlers
-	tenantHandler := handlers.NewTenantHandler(tenantStore, apiKeyStore, config.SetupToken())
-	setupHandler := handlers.NewSetupHandler(tenantStore, apiKeyStore, config.SetupToken())
+	tenantHandler := handlers.NewTenantHandler(st.Tenants, apiKeyStore, config.SetupToken())
+	setupHandler := handlers.NewSetupHandler(st.Tenants, apiKeyStore, config.SetupToken())
 	authHandler := handlers.NewAuthHandler(authSvc, sessionTTL)
-	agentHandler := handlers.NewAgentHandler(agentSvc)
-	memoryHandler := handlers.NewMemoryHandler(memorySvc, hybridRecallSvc, entityStore, sessionStore)
-	anchorHandler := handlers.NewAnchorHandler(entityStore, memoryStore)
-	sessionHandler := handlers.NewSessionHandler(sessionStore, entityStore, agentStore, 0)
-	canonHandler := handlers.NewCanonHandler(memorySvc, memoryStore)
-	policyHandler := handlers.NewPolicyHandler(policySvc)
-	feedbackHandler := handlers.NewFeedbackHandler(feedbackSvc)
-	episodeHandler := handlers.NewEpisodeHandler(episodeSvc)
-	procedureHandler := handlers.NewProcedureHandler(proceduralSvc)
-	schemaHandler := handlers.NewSchemaHandler(schemaSvc)
-	wmHandler := handlers.NewWorkingMemoryHandler(wmSvc)
-	cognitiveHandler := handlers.NewCognitiveHandler(decaySvc, consolidationSvc, agentStore)
-	cognitiveHandler.SetConfidenceService(confidenceSvc)
-	cognitiveHandler.SetCalibrationService(service.NewCalibrationService(mutationLogStore, logger))
-	metacognitiveHandler := handlers.NewMetacognitiveHandler(metacognitiveSvc)
-	adminHandler := handlers.NewAdminHandler(adminSvc)
+	agentHandler := handlers.NewAgentHandler(eng.Agents)
+	agentHandler.SetSeedService(service.NewAgentSeedService(st.Agents, eng.Memory, eng.Schemas, eng.Procedures, logger))
+	agentCloneSvc := service.NewAgentCloneService(st.Agents, st.Schemas, st.Procedures, st.Memories, eng.Embedding, apiKeyStore, logger)
+	agentCloneSvc.SetBilling(billingStore, billingEnabled)
+	agentHandler.SetCloneService(agentCloneSvc)
+	memoryHandler := handlers.NewMemoryHandler(eng.Memory, eng.HybridRecall, st.Entities, st.Sessions)
+	memoryHandler.SetRecallLogger(eng.RecallLog)
+	anchorHandler := handlers.NewAnchorHandler(st.Entities, st.Memories)
+	anchorHandler.SetExportService(service.NewSubjectExportService(st.Entities, st.Sessions, st.Memories, st.Episodes, logger))
+	sessionHandler := handlers.NewSessionHandler(st.Sessions, st.Entities, st.Agents, 0)
+	canonHandler := handlers.NewCanonHandler(eng.Memory, st.Memories)
+	policyHandler := handlers.NewPolicyHandler(eng.Policies)
+	feedbackHandler := handlers.NewFeedbackHandler(eng.Feedback)
+	episodeHandler := handlers.NewEpisodeHandler(eng.Episodes)
+	episodeHandler.SetPositionService(eng.EpisodePositions)
+	ingestHandler := handlers.NewIngestHandler(eng.Ingest, st.Agents)
+	procedureHandler := handlers.NewProcedureHandler(eng.Procedures)
+	schemaHandler := handlers.NewSchemaHandler(eng.Schemas)
+	wmHandler := handlers.NewWorkingMemoryHandler(eng.WorkingMemory)
+	wmHandler.SetRecallLogger(eng.RecallLog)
+	wmHandler.SetStreamOrigins(config.CORSAllowedOrigins())
+	cognitiveHandler := handlers.NewCognitiveHandler(eng.Decay, eng.Consolidation, st.Agents)
+	cognitiveHandler.SetConfidenceService(eng.Confidence)
+	cognitiveHandler.SetCalibrationService(service.NewCalibrationService(st.MutationLog, logger))
+	metacognitiveHandler := handlers.NewMetacognitiveHandler(eng.Metacognition)
+	knowledgeHandler := handlers.NewKnowledgeHandler(service.NewKnowledgeService(st.Memories, st.Episodes, st.Schemas, eng.Embedding, eng.LLM, logger))
+	adminHandler := handlers.NewAdminHandler(eng.Admin)
+	adminHandler.SetSearchService(service.NewTenantSearchService(st.Memories, st.Episodes, eng.Embedding, logger))
+	vectorIndexHandler := handlers.NewVectorIndexHandler(service.NewVectorIndexService(store.NewVectorIndexStore(db), logger), config.SetupToken())
 	embeddingHandler := handlers.NewEmbeddingHandler()
-	consoleHandler := handlers.NewConsoleHandler(consoleSvc)
-	auditHandler := handlers.NewAuditHandler(mutationLogStore, config.AuditSigningKey())
-	settingsHandler := handlers.NewSettingsHandler(tenantSettingsStore)
+	consoleHandler := handlers.NewConsoleHandler(service.NewConsoleService(st.Memories, st.Contradictions, st.LearningStats, logger))
+	consoleHandler.SetCompareService(service.NewAgentCompareService(st.Agents, st.Memories, st.Schemas, logger))
+	auditHandler := handlers.NewAuditHandler(st.MutationLog, config.AuditSigningKey())
+	backupSvc := service.NewBackupService(store.NewBackupStore(tenants), logger)
+	backupSvc.SetSigningKey(config.AuditSigningKey())
+	backupHandler := handlers.NewBackupHandler(backupSvc)
+	settingsHandler := handlers.NewSettingsHandler(st.Settings)
+	retentionHandler := handlers.NewRetentionHandler(eng.Retention)
 	billingHandler := handlers.NewBillingHandler(billingStore, rzpClient, config.AppBaseURL(), logger)
-	mindHandler := handlers.NewMindHandler(memoryStore, episodeStore, procedureStore, schemaStore, agentStore)
-	tierHandler := handlers.NewTierHandler(memorySvc)
-	graphHandler := handlers.NewGraphHandler(hybridRecallSvc, graphBuilderSvc, graphStore, entityStore, agentStore, memoryStore)
-	learningHandler := handlers.NewLearningHandler(learningSvc, implicitFeedbackSvc, mutationLogStore, agentStore)
-	conversationSvc := service.NewConversationService(memorySvc, llmClient, logger)
-	conversationHandler := handlers.NewConversationHandler(conversationSvc, entityStore, sessionStore)
+	mindHandler := handlers.NewMindHandler(st.Memories, st.Episodes, st.Procedures, st.Schemas, st.Agents)
+	tierHandler := handlers.NewTierHandler(eng.Memory, eng.Tiers)
+	graphHandler := handlers.NewGraphHandler(eng.HybridRecall, eng.GraphBuilder, st.Graph, st.Entities, st.Agents, st.Memories)
+	learningHandler := handlers.NewLearningHandler(eng.Learning, eng.ImplicitFeedback, st.MutationLog, st.Agents)
+	conversationHandler := handlers.NewConversationHandler(eng.Conversations, st.Entities, st.Sessions)
+	conversationHandler.SetCloseService(service.NewConversationCloseService(eng.Episodes, st.ConversationActs, st.Memories, eng.ImplicitFeedback, eng.Memory, eng.Consolidation, eng.Jobs, logger))
+	jobHandler := handlers.NewJobHandler(eng.Jobs)
+	statsHandler := handlers.NewStatsHandler(eng.AgentStats, st.Agents)
+	anomalyHandler := handlers.NewPipelineAnomalyHandler(eng.PipelineAnomalies, st.Agents)
+	// The chat proxy needs a provider that can forward chat completions; with
+	// none it answers 501.
+	chatCompleter, _ := eng.LLM.(domain.ChatCompleter)
+	chatSvc := service.NewChatService(chatCompleter, st.Agents, eng.WorkingMemory, eng.Memory, eng.Episodes, eng.Jobs, logger)
+	chatHandler := handlers.NewChatHandler(chatSvc)
+
+	schedulerHandler := handlers.NewSchedulerHandler(eng.Scheduler, config.SetupToken())
 
 	r := chi.NewRouter()
 
 	// Initialize app with metrics tracking
 	app := &App{
-		Router:        r,
-		Tuner:         tunerSvc,
-		Expirer:       expirerSvc,
-		Decay:         decaySvc,
-		Consolidation: consolidationSvc,
-		Learning:      learningSvc,
-		startTime:     time.Now(),
+		Engine:    eng,
+		Router:    r,
+		logger:    logger,
+		startTime: time.Now(),
 	}
+	app.applyRuntimeConfig()
+	configHandler := handlers.NewConfigHandler(app.ReloadConfig, config.SetupToken())
 
 	// Metrics collector for middleware
 	metricsCollector := mw.NewMetricsCollector(&app.requestCount, &app.errorCount)
@@ -264,6 +219,15 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 	// Metrics (no auth)
 	r.Get("/metrics", app.metricsHandler())
 
+	// OpenAPI document and Swagger UI (no auth). The document is built from the
+	// finished router at the end of NewApp.
+	docsHandler := handlers.NewDocsHandler()
+	r.Get("/openapi.json", docsHandler.Spec)
+	r.Get("/docs", docsHandler.UI)
+
+	// OpenAI function schemas for the core memory tools (no auth).
+	r.Get("/tools", handlers.NewToolsHandler().List)
+
 	// Embedded MCP endpoint (Streamable HTTP). Lets any MCP client connect with
 	// just a URL + an Engram API key — no local engram-mcp binary, no env vars:
 	//   claude mcp add --transport http engram https://<host>/mcp \
@@ -311,6 +275,8 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 		r.Use(mw.SessionOrAPIKey(apiKeyStore, authSvc, config.CORSAllowedOrigins()))
 		r.Use(mw.RequireWriteForMutations)
 
+		idempotent := mw.Idempotency(st.Idempotency)
+
 		// Key management (admin scope required)
 		r.Route("/keys", func(r chi.Router) {
 			r.Use(mw.RequireScope("admin"))
@@ -334,38 +300,66 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 		// Agents
 		r.Route("/agents", func(r chi.Router) {
 			r.Get("/", agentHandler.List)
-			r.With(mw.EnforceAgentQuota(billingStore, billingEnabled)).Post("/", agentHandler.Create)
+			r.With(idempotent, mw.EnforceAgentQuota(billingStore, billingEnabled)).Post("/", agentHandler.Create)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", agentHandler.GetByID)
 				r.Delete("/", agentHandler.Delete)
+				r.With(idempotent).Post("/seed", agentHandler.Seed)
+				r.With(idempotent, mw.EnforceAgentQuota(billingStore, billingEnabled)).Post("/clone", agentHandler.Clone)
+				r.Get("/skills", procedureHandler.ExportSkills)
+				r.With(idempotent).Post("/skills", procedureHandler.ImportSkills)
 				r.Get("/mind", mindHandler.GetMind)
 				r.Get("/policies", policyHandler.Get)
 				r.Put("/policies", policyHandler.Upsert)
+				r.Get("/working-memory", wmHandler.GetSettings)
+				r.Put("/working-memory", wmHandler.UpdateSettings)
+				r.Get("/episode-position", episodeHandler.GetPositionSettings)
+				r.Put("/episode-position", episodeHandler.UpdatePositionSettings)
 				r.Get("/tier-stats", tierHandler.GetTierStats)
 				r.Get("/hot-memories", tierHandler.GetHotMemories)
+				r.Get("/tier-history", tierHandler.GetAgentTierHistory)
+				r.Get("/forgetting-forecast", cognitiveHandler.GetForgettingForecast)
+				r.Get("/consolidation-runs", cognitiveHandler.ListConsolidationRuns)
+				r.Get("/dead-letters", cognitiveHandler.ListDeadLetters)
 				r.Get("/learning/stats", learningHandler.GetStats)
+				r.Get("/stats", statsHandler.Get)
+				r.Get("/anomalies", anomalyHandler.Get)
 				r.Get("/dashboard", consoleHandler.Dashboard)
 				r.Get("/review-queue", consoleHandler.ReviewQueue)
 				r.With(mw.RequireScope("admin")).Get("/quarantine", memoryHandler.ListQuarantine)
 				r.Get("/memories", consoleHandler.Memories)
 				r.Get("/snapshot", consoleHandler.Snapshot)
+				r.Get("/compare", consoleHandler.Compare)
 				r.Get("/contradictions", consoleHandler.Contradictions)
-				r.Post("/conversations/ingest", conversationHandler.Ingest)
+				r.With(idempotent).Post("/conversations/ingest", conversationHandler.Ingest)
 			})
 		})
 
 		// Memories
 		r.Route("/memories", func(r chi.Router) {
 			r.With(mw.MeterRecall(billingStore, billingEnabled)).Get("/recall", memoryHandler.Recall)
+			r.With(mw.MeterRecall(billingStore, billingEnabled)).Post("/recall/batch", memoryHandler.RecallBatch)
 			r.Post("/extract", memoryHandler.Extract)
-			r.With(mw.EnforceMemoryQuota(billingStore, billingEnabled)).Post("/", memoryHandler.Create)
+			r.With(idempotent, mw.EnforceMemoryQuota(billingStore, billingEnabled)).Post("/", memoryHandler.Create)
 			r.Get("/{id}", memoryHandler.GetByID)
 			r.Delete("/{id}", memoryHandler.Delete)
 			r.With(mw.RequireScope("admin")).Patch("/{id}", adminHandler.UpdateMemory)
 			r.Post("/{id}/restore", memoryHandler.Restore)
 			r.Get("/{id}/mutations", learningHandler.GetMutationHistory)
+			r.Get("/{id}/tier-history", tierHandler.GetMemoryTierHistory)
+			r.Post("/{id}/pin", tierHandler.Pin)
+			r.Delete("/{id}/pin", tierHandler.Unpin)
 		})
 
+		// Materialized per-agent memory statistics for dashboards
+		r.Get("/stats/agents", statsHandler.List)
+
+		// Background jobs (async extraction)
+		r.Get("/jobs/{id}", jobHandler.Get)
+
+		// OpenAI-compatible chat proxy with memory injection and capture
+		r.Post("/chat/completions", chatHandler.Complete)
+
 		// Provenance Firewall: review-queue decisions (admin-scoped).
 		r.Route("/quarantine", func(r chi.Router) {
 			r.Use(mw.RequireScope("admin"))
@@ -380,6 +374,20 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Post("/contradictions/resolve", adminHandler.ResolveContradiction)
 			r.Post("/anchors/{id}/shred", adminHandler.CryptoShredAnchor)
 			r.Post("/agents/{id}/reembed", adminHandler.Reembed)
+			r.Post("/agents/{id}/vectors/sync", adminHandler.SyncVectors)
+			r.Post("/agents/{id}/vectors/quantize", adminHandler.QuantizeVectors)
+			r.Post("/agents/{id}/recompute", adminHandler.Recompute)
+			r.Get("/search", adminHandler.Search)
+
+			// Deployment-wide pgvector indexes (also require X-Setup-Token).
+			r.Get("/vector-indexes", vectorIndexHandler.List)
+			r.Get("/vector-indexes/{name}", vectorIndexHandler.Get)
+			r.Post("/vector-indexes/{name}/rebuild", vectorIndexHandler.Rebuild)
+			r.Get("/vector-indexes/{name}/health", vectorIndexHandler.Health)
+			r.Get("/jobs", schedulerHandler.List)
+			r.Post("/jobs/{name}/pause", schedulerHandler.Pause)
+			r.Post("/jobs/{name}/resume", schedulerHandler.Resume)
+			r.Post("/config/reload", configHandler.Reload)
 		})
 
 		// Active embedding configuration (read-only; deploy-time choice).
@@ -393,20 +401,28 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Get("/export", auditHandler.Export)
 		})
 
+		// Whole-tenant backup and restore.
+		r.Route("/backup", func(r chi.Router) {
+			r.Use(mw.RequireScope("admin"))
+			r.Get("/", backupHandler.Export)
+			r.Post("/restore", backupHandler.Restore)
+		})
+
 		// Anchors (who/what memories are about)
 		r.Route("/anchors", func(r chi.Router) {
 			r.Get("/", anchorHandler.List)
-			r.Post("/", anchorHandler.Create)
+			r.With(idempotent).Post("/", anchorHandler.Create)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", anchorHandler.GetByID)
 				r.Delete("/", anchorHandler.Delete)
 				r.Get("/memories", anchorHandler.ListMemories)
+				r.With(mw.RequireScope("admin")).Get("/export", anchorHandler.Export)
 			})
 		})
 
 		// Sessions
 		r.Route("/sessions", func(r chi.Router) {
-			r.Post("/", sessionHandler.Create)
+			r.With(idempotent).Post("/", sessionHandler.Create)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", sessionHandler.GetByID)
 				r.Post("/end", sessionHandler.End)
@@ -419,7 +435,7 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Get("/", canonHandler.List)
 			r.Group(func(r chi.Router) {
 				r.Use(mw.RequireScope("admin"))
-				r.Post("/", canonHandler.Create)
+				r.With(idempotent).Post("/", canonHandler.Create)
 				r.Delete("/{id}", canonHandler.Delete)
 			})
 		})
@@ -430,13 +446,27 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Post("/detect-feedback", learningHandler.DetectImplicitFeedback)
 		})
 
+		// Conversation replay (what was learned from one conversation) and
+		// the end-of-conversation hook
+		r.Get("/conversations/{id}/replay", episodeHandler.Replay)
+		r.Post("/conversations/{id}/close", conversationHandler.Close)
+
+		// Buffered bulk ingestion of episodes and memories
+		r.Route("/ingest", func(r chi.Router) {
+			r.Post("/", ingestHandler.Ingest)
+			r.With(mw.RequireScope("admin")).Get("/", ingestHandler.Stats)
+		})
+
 		// Episodes (episodic memory)
 		r.Route("/episodes", func(r chi.Router) {
 			r.Get("/recall", episodeHandler.Recall)
-			r.Post("/", episodeHandler.Create)
+			r.Get("/causal-chains", episodeHandler.CausalChains)
+			r.With(idempotent).Post("/", episodeHandler.Create)
+			r.Post("/transcript", episodeHandler.IngestTranscript)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", episodeHandler.GetByID)
 				r.Post("/outcome", episodeHandler.RecordOutcome)
+				r.Post("/requeue", cognitiveHandler.RequeueDeadLetter)
 				r.Get("/associations", episodeHandler.GetAssociations)
 			})
 		})
@@ -451,17 +481,22 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", procedureHandler.GetByID)
 				r.Post("/outcome", procedureHandler.RecordOutcome)
+				r.Post("/render", procedureHandler.RenderAction)
 			})
 		})
 
 		// Schemas (mental models)
 		r.Route("/schemas", func(r chi.Router) {
 			r.Get("/", schemaHandler.List)
+			r.With(idempotent).Post("/", schemaHandler.Create)
+			r.Post("/seed", schemaHandler.Seed)
 			r.Post("/match", schemaHandler.Match)
 			r.Post("/detect", schemaHandler.Detect)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", schemaHandler.GetByID)
+				r.Patch("/", schemaHandler.Update)
 				r.Delete("/", schemaHandler.Delete)
+				r.Post("/evidence", schemaHandler.AttachEvidence)
 				r.Post("/contradict", schemaHandler.Contradict)
 				r.Post("/validate", schemaHandler.Validate)
 			})
@@ -472,6 +507,7 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Get("/entities", graphHandler.ListEntities)
 			r.Get("/relationships", graphHandler.GetRelationships)
 			r.Post("/traverse", graphHandler.Traverse)
+			r.Get("/relation-types", graphHandler.RelationTypes)
 		})
 
 		// Cognitive operations (working memory, decay, consolidation, metacognition, etc.)
@@ -481,12 +517,23 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Get("/health", cognitiveHandler.GetMemoryHealth)
 			r.Post("/activate", wmHandler.Activate)
 			r.Get("/session", wmHandler.GetSession)
+			r.Get("/sessions", wmHandler.ListSessions)
+			// A stream activates on every message, so it needs write scope
+			// even though it opens with a GET
+			r.With(mw.RequireScope("write")).Get("/stream", wmHandler.Stream)
 			r.Put("/goal", wmHandler.UpdateGoal)
+			r.Get("/reasoning", wmHandler.GetReasoning)
+			r.Patch("/reasoning", wmHandler.PatchReasoning)
+			r.Get("/snapshots", wmHandler.ListSnapshots)
+			r.Post("/snapshots", wmHandler.CreateSnapshot)
+			r.Post("/snapshots/{id}/restore", wmHandler.RestoreSnapshot)
+			r.Delete("/snapshots/{id}", wmHandler.DeleteSnapshot)
 			r.Delete("/session", wmHandler.ClearSession)
 			// Metacognitive operations
 			r.Post("/reflect", metacognitiveHandler.Reflect)
 			r.Get("/confidence", metacognitiveHandler.AssessConfidence)
 			r.Get("/uncertainty", metacognitiveHandler.DetectUncertainty)
+			r.Get("/knowledge", knowledgeHandler.Describe)
 			// Confidence lifecycle operations
 			r.Get("/confidence/stats", cognitiveHandler.GetConfidenceStats)
 			r.Post("/confidence/reinforce", cognitiveHandler.ReinforceMemory)
@@ -500,8 +547,24 @@ func NewApp(db *pgxpool.Pool, logger *zap.Logger) *App {
 			r.Get("/", settingsHandler.Get)
 			r.With(mw.RequireScope("admin")).Put("/", settingsHandler.Update)
 		})
+
+		// Retention rules, enforced by the expirer. Writes require admin scope.
+		r.Route("/retention-rules", func(r chi.Router) {
+			r.Get("/", retentionHandler.List)
+			r.Get("/preview", retentionHandler.Preview)
+			r.With(mw.RequireScope("admin")).Put("/", retentionHandler.Upsert)
+			r.With(mw.RequireScope("admin")).Delete("/{id}", retentionHandler.Delete)
+		})
 	})
 
+	apiDoc := openapi.New()
+	handlers.DescribeAPI(apiDoc)
+	spec, err := apiDoc.Build(r, openapi.Info{Title: "Engram API", Version: buildconfig.Version()})
+	if err != nil {
+		logger.Fatal("failed to build OpenAPI document", zap.Error(err))
+	}
+	docsHandler.SetSpec(spec)
+
 	return app
 }
 
@@ -514,7 +577,7 @@ func NewRouter(db *pgxpool.Pool, logger *zap.Logger) *chi.Mux {
 // under an API namespace, which get a JSON 404 so clients don't receive the HTML
 // shell in place of an error.
 func spaFallback(spa http.Handler) http.HandlerFunc {
-	apiPrefixes := []string{"/v1", "/auth", "/health", "/metrics", "/mcp"}
+	apiPrefixes := []string{"/v1", "/auth", "/health", "/metrics", "/mcp", "/tools"}
 	return func(w http.ResponseWriter, r *http.Request) {
 		for _, p := range apiPrefixes {
 			if r.URL.Path == p || strings.HasPrefix(r.URL.Path, p+"/") {
@@ -611,26 +674,43 @@ func wantsJSON(r *http.Request) bool {
 
 // Ensure stores and clients satisfy interfaces at compile time.
 var (
-	_ domain.TenantStore             = (*store.TenantStore)(nil)
-	_ domain.BillingStore            = (*store.BillingStore)(nil)
-	_ domain.AgentStore              = (*store.AgentStore)(nil)
-	_ domain.MemoryStore             = (*store.MemoryStore)(nil)
-	_ domain.PolicyStore             = (*store.PolicyStore)(nil)
-	_ domain.FeedbackStore           = (*store.FeedbackStore)(nil)
-	_ domain.ContradictionStore      = (*store.ContradictionStore)(nil)
-	_ domain.EpisodeStore            = (*store.EpisodeStore)(nil)
-	_ domain.ProcedureStore          = (*store.ProcedureStore)(nil)
-	_ domain.SchemaStore             = (*store.SchemaStore)(nil)
-	_ domain.WorkingMemoryStore      = (*store.WorkingMemoryStore)(nil)
-	_ domain.MemoryAssociationStore  = (*store.MemoryAssociationStore)(nil)
-	_ domain.MutationLogStore        = (*store.MutationLogStore)(nil)
-	_ domain.EpisodeMemoryUsageStore = (*store.EpisodeMemoryUsageStore)(nil)
-	_ domain.LearningStatsStore      = (*store.LearningStatsStore)(nil)
-	_ domain.EmbeddingClient         = (*embedding.CompatibleClient)(nil)
-	_ domain.EmbeddingClient         = (*embedding.MockClient)(nil)
-	_ domain.LLMClient               = (*llm.OpenAIClient)(nil)
-	_ domain.LLMClient               = (*llm.AnthropicClient)(nil)
-	_ domain.LLMClient               = (*llm.GeminiClient)(nil)
-	_ domain.LLMClient               = (*llm.CerebrasClient)(nil)
-	_ domain.LLMClient               = (*llm.MockClient)(nil)
+	_ domain.TenantStore                  = (*store.TenantStore)(nil)
+	_ domain.BillingStore                 = (*store.BillingStore)(nil)
+	_ domain.AgentStore                   = (*store.AgentStore)(nil)
+	_ domain.AgentRegistry                = (*store.AgentStore)(nil)
+	_ domain.BackupStore                  = (*store.BackupStore)(nil)
+	_ domain.MemoryStore                  = (*store.MemoryStore)(nil)
+	_ domain.UnsummarizedMemoryLoader     = (*store.MemoryStore)(nil)
+	_ domain.PolicyStore                  = (*store.PolicyStore)(nil)
+	_ domain.FeedbackStore                = (*store.FeedbackStore)(nil)
+	_ domain.ContradictionStore           = (*store.ContradictionStore)(nil)
+	_ domain.EpisodeStore                 = (*store.EpisodeStore)(nil)
+	_ domain.ConversationEndsStore        = (*store.EpisodeStore)(nil)
+	_ domain.EpisodePositionSettingsStore = (*store.EpisodePositionSettingsStore)(nil)
+	_ domain.ProcedureStore               = (*store.ProcedureStore)(nil)
+	_ domain.SchemaStore                  = (*store.SchemaStore)(nil)
+	_ domain.WorkingMemoryStore           = (*store.WorkingMemoryStore)(nil)
+	_ domain.WorkingMemorySettingsStore   = (*store.WorkingMemorySettingsStore)(nil)
+	_ domain.WorkingMemorySnapshotStore   = (*store.WorkingMemorySnapshotStore)(nil)
+	_ domain.ConversationActivationStore  = (*store.ConversationActivationStore)(nil)
+	_ domain.ConsolidationRunStore        = (*store.ConsolidationRunStore)(nil)
+	_ domain.AgentStatisticsStore         = (*store.AgentStatisticsStore)(nil)
+	_ domain.HealthStore                  = (*store.HealthStore)(nil)
+	_ service.OutcomeAttributor           = (*service.LearningService)(nil)
+	_ domain.MemoryAssociationStore       = (*store.MemoryAssociationStore)(nil)
+	_ domain.MutationLogStore             = (*store.MutationLogStore)(nil)
+	_ domain.EpisodeMemoryUsageStore      = (*store.EpisodeMemoryUsageStore)(nil)
+	_ domain.LearningStatsStore           = (*store.LearningStatsStore)(nil)
+	_ domain.EmbeddingClient              = (*embedding.CompatibleClient)(nil)
+	_ domain.EmbeddingClient              = (*embedding.MockClient)(nil)
+	_ domain.LLMClient                    = (*llm.OpenAIClient)(nil)
+	_ domain.LLMClient                    = (*llm.AnthropicClient)(nil)
+	_ domain.LLMClient                    = (*llm.GeminiClient)(nil)
+	_ domain.LLMClient                    = (*llm.CerebrasClient)(nil)
+	_ domain.LLMClient                    = (*llm.MockClient)(nil)
+	_ domain.ChatCompleter                = (*llm.OpenAIClient)(nil)
+	_ domain.ChatCompleter                = (*llm.AnthropicClient)(nil)
+	_ domain.ChatCompleter                = (*llm.GeminiClient)(nil)
+	_ domain.ChatCompleter                = (*llm.CerebrasClient)(nil)
+	_ domain.ChatCompleter                = (*llm.MockClient)(nil)
 )
@@ -0,0 +1,72 @@
+// Package clock is the time source for time-dependent cognition — decay,
+// consolidation windows, tier transitions. Production uses the wall clock;
+// tests install a Fake to make those behaviours reproducible.
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the wall clock.
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+type holder struct{ c Clock }
+
+var current atomic.Pointer[holder]
+
+func init() {
+	current.Store(&holder{Real{}})
+}
+
+// Now returns the current time of the installed clock.
+func Now() time.Time {
+	return current.Load().c.Now()
+}
+
+// Set installs c as the process-wide clock and returns a func restoring the
+// previous one. It is meant for tests; tests that set it must not run in
+// parallel with each other.
+func Set(c Clock) (restore func()) {
+	prev := current.Swap(&holder{c})
+	return func() { current.Store(prev) }
+}
+
+// Fake is a Clock that only moves when told to. It is safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a fake clock stopped at start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+// Set moves the clock to t.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t
+	f.mu.Unlock()
+}
@@ -32,7 +32,8 @@ func envDurationSecs(key string, defSecs int) time.Duration {
 }
 
 // Load reads the .env file specified by ENGRAM_ENV (or .env by default),
-// then loads the corresponding .secret file if it exists.
+// then loads the corresponding .secret file if it exists, then layers the
+// ENGRAM_CONFIG file (if any) underneath, and validates the result.
 // All config is flat env vars read via os.Getenv after loading.
 func Load() error {
 	envFile := os.Getenv("ENGRAM_ENV")
@@ -46,7 +47,10 @@ func Load() error {
 	// Load secret sidecar if it exists
 	_ = godotenv.Load(envFile + ".secret")
 
-	return nil
+	if err := loadFile(); err != nil {
+		return err
+	}
+	return Validate()
 }
 
 func ServerPort() int {
@@ -138,6 +142,32 @@ func EmbeddingBaseURL() string {
 	return os.Getenv("EMBEDDING_BASE_URL")
 }
 
+// VisionModel names the image-capable model, on the LLM provider, that
+// captions episode image attachments. Empty disables captioning.
+func VisionModel() string {
+	return os.Getenv("VISION_MODEL")
+}
+
+// TranscriptionModel names the speech-to-text model that transcribes episode
+// audio uploads (e.g. whisper-1). Empty disables audio uploads.
+func TranscriptionModel() string {
+	return os.Getenv("TRANSCRIPTION_MODEL")
+}
+
+// TranscriptionBaseURL is the OpenAI-compatible API root serving
+// /audio/transcriptions; empty uses OpenAI.
+func TranscriptionBaseURL() string {
+	return os.Getenv("TRANSCRIPTION_BASE_URL")
+}
+
+// TranscriptionAPIKey falls back to OPENAI_API_KEY.
+func TranscriptionAPIKey() string {
+	if v := os.Getenv("TRANSCRIPTION_API_KEY"); v != "" {
+		return v
+	}
+	return OpenAIAPIKey()
+}
+
 func EmbeddingDim() int {
 	if v := os.Getenv("EMBEDDING_DIM"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
@@ -147,6 +177,13 @@ func EmbeddingDim() int {
 	return 1536
 }
 
+// EmbeddingDedup reuses the stored vector of text the tenant has already
+// embedded instead of calling the provider again, from EMBEDDING_DEDUP, and
+// stores each distinct text's vector once, referenced by its memories and
+// episodes. Memories keep only their quantized copy, so it implies
+// VECTOR_QUANTIZATION=halfvec unless that is set.
+func EmbeddingDedup() bool { return strings.EqualFold(os.Getenv("EMBEDDING_DEDUP"), "true") }
+
 // SetupToken returns ENGRAM_SETUP_TOKEN. If empty, POST /v1/setup is disabled.
 func SetupToken() string {
 	return os.Getenv("ENGRAM_SETUP_TOKEN")
@@ -184,6 +221,153 @@ func RateLimitBurst() int {
 	return burst
 }
 
+// RecallLogSampleRate returns the fraction of recalls whose rankings are logged
+// to recall_logs for offline analysis, from RECALL_LOG_SAMPLE_RATE.
+// Defaults to 0 (disabled); values are clamped to [0, 1]. Reloadable.
+func RecallLogSampleRate() float64 {
+	rate, err := strconv.ParseFloat(os.Getenv("RECALL_LOG_SAMPLE_RATE"), 64)
+	if err != nil || rate <= 0 {
+		return 0
+	}
+	if rate > 1 {
+		return 1
+	}
+	return rate
+}
+
+// RecallCacheTTL is how long a recall result is reused for the same agent,
+// query and options, from RECALL_CACHE_TTL as a Go duration. Writes made
+// through this instance drop stale results sooner. Defaults to 30s; "0"
+// turns the cache off.
+func RecallCacheTTL() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("RECALL_CACHE_TTL"))
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// RecallEnvironmentBoost returns how much higher a memory or episode formed in
+// the caller's environment ranks in recall, from RECALL_ENVIRONMENT_BOOST: a
+// full match multiplies its score by 1 plus the boost. Defaults to 0.2; 0
+// disables the bias and values are clamped to [0, 1].
+func RecallEnvironmentBoost() float64 {
+	boost, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv("RECALL_ENVIRONMENT_BOOST")), 64)
+	if err != nil {
+		return 0.2
+	}
+	if boost < 0 {
+		return 0
+	}
+	if boost > 1 {
+		return 1
+	}
+	return boost
+}
+
+// VectorEfSearch is the hnsw.ef_search applied to every database session, from
+// PGVECTOR_EF_SEARCH. 0 keeps pgvector's default (40). Higher values raise
+// recall at the cost of latency; tune with the vector-index health report.
+func VectorEfSearch() int { return envNonNegativeInt("PGVECTOR_EF_SEARCH") }
+
+// VectorIVFFlatProbes is the ivfflat.probes applied to every database session,
+// from PGVECTOR_IVFFLAT_PROBES. 0 keeps pgvector's default (1).
+func VectorIVFFlatProbes() int { return envNonNegativeInt("PGVECTOR_IVFFLAT_PROBES") }
+
+// VectorQuantization is the compact embedding copy recall shortlists on before
+// reranking at full precision, from VECTOR_QUANTIZATION: "halfvec" or
+// "binary". "none" searches the full-precision column only, as does leaving
+// it empty, except under EMBEDDING_DEDUP, where it defaults to "halfvec".
+func VectorQuantization() string {
+	q := strings.ToLower(strings.TrimSpace(os.Getenv("VECTOR_QUANTIZATION")))
+	if q == "" && EmbeddingDedup() {
+		return "halfvec"
+	}
+	return q
+}
+
+func envNonNegativeInt(key string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// EpisodeRetentionMonths is how many whole months of episodes are kept, from
+// EPISODE_RETENTION_MONTHS. Older monthly partitions are dropped by the
+// expirer; 0 (the default) keeps episodes forever.
+func EpisodeRetentionMonths() int { return envNonNegativeInt("EPISODE_RETENTION_MONTHS") }
+
+// JobWorkers is the size of the background job pool shared by async
+// extraction and consolidation, from JOB_WORKERS. 0 uses the default (4).
+func JobWorkers() int { return envNonNegativeInt("JOB_WORKERS") }
+
+// JobQueueSize is how many jobs may wait for a worker before submissions are
+// rejected, from JOB_QUEUE_SIZE. 0 uses the default (256).
+func JobQueueSize() int { return envNonNegativeInt("JOB_QUEUE_SIZE") }
+
+// IngestBufferSize is how many episodes and memories POST /v1/ingest may hold
+// in memory before rejecting writes, from INGEST_BUFFER_SIZE. 0 uses the
+// default (1024).
+func IngestBufferSize() int { return envNonNegativeInt("INGEST_BUFFER_SIZE") }
+
+// IngestBatchSize caps how many buffered writes are embedded and inserted
+// together, from INGEST_BATCH_SIZE. 0 uses the default (64).
+func IngestBatchSize() int { return envNonNegativeInt("INGEST_BATCH_SIZE") }
+
+// IngestFlushInterval is the longest a buffered write waits for its batch to
+// fill, from INGEST_FLUSH_INTERVAL as a Go duration. 0 uses the default
+// (200ms).
+func IngestFlushInterval() time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("INGEST_FLUSH_INTERVAL")))
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// ConsolidationReplayBudget caps how many raw episodes each consolidation run
+// replays, from CONSOLIDATION_REPLAY_BUDGET. 0 uses the default (50).
+func ConsolidationReplayBudget() int { return envNonNegativeInt("CONSOLIDATION_REPLAY_BUDGET") }
+
+// TaskInterval is how often the named scheduled task (tuner, expirer, decay,
+// consolidation) runs, from <NAME>_INTERVAL as a Go duration ("30m"). 0 keeps
+// the task's built-in interval. Reloadable.
+func TaskInterval(task string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(os.Getenv(strings.ToUpper(task) + "_INTERVAL")))
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// RedisURL enables the Redis working memory cache, from REDIS_URL
+// (redis://[[user]:password@]host:port/db, or rediss:// for TLS). Empty keeps
+// working memory in Postgres.
+func RedisURL() string { return strings.TrimSpace(os.Getenv("REDIS_URL")) }
+
+// VectorStore names the similarity search backend, from VECTOR_STORE:
+// "qdrant" or "weaviate" at VECTOR_STORE_URL. Empty or "pgvector" searches
+// in Postgres.
+func VectorStore() string { return strings.TrimSpace(os.Getenv("VECTOR_STORE")) }
+
+func VectorStoreURL() string { return strings.TrimSpace(os.Getenv("VECTOR_STORE_URL")) }
+
+func VectorStoreAPIKey() string { return strings.TrimSpace(os.Getenv("VECTOR_STORE_API_KEY")) }
+
+// VectorStoreCollection is the Qdrant collection or Weaviate class holding
+// memory vectors, from VECTOR_STORE_COLLECTION.
+func VectorStoreCollection() string { return strings.TrimSpace(os.Getenv("VECTOR_STORE_COLLECTION")) }
+
+// EventWebhookURL is where memory change events from the outbox are POSTed,
+// from EVENT_WEBHOOK_URL. Empty disables event publishing.
+func EventWebhookURL() string { return strings.TrimSpace(os.Getenv("EVENT_WEBHOOK_URL")) }
+
+// EventWebhookSecret signs webhook deliveries with HMAC-SHA256 when set.
+func EventWebhookSecret() string { return strings.TrimSpace(os.Getenv("EVENT_WEBHOOK_SECRET")) }
+
 // CORSAllowedOrigins returns the browser origins permitted to call the API,
 // parsed from the comma-separated CORS_ALLOWED_ORIGINS env var. An empty result
 // disables CORS; a single "*" allows any origin. Used by the console frontend.
@@ -331,8 +515,65 @@ func DBMaxConnLifetime() time.Duration { return envDurationSecs("DB_MAX_CONN_LIF
 // Override with DB_MAX_CONN_IDLE_SECS. Default 30m.
 func DBMaxConnIdleTime() time.Duration { return envDurationSecs("DB_MAX_CONN_IDLE_SECS", 1800) }
 
+// DBStatementCacheMode is pgx's default query exec mode: "cache_statement"
+// (prepare and cache every statement, pgx's default), "cache_describe",
+// "describe_exec", "exec" or "simple_protocol". Override with
+// DB_STATEMENT_CACHE_MODE. Empty keeps the URL's default_query_exec_mode,
+// or "exec" when DBPgBouncer is set.
+func DBStatementCacheMode() string { return strings.TrimSpace(os.Getenv("DB_STATEMENT_CACHE_MODE")) }
+
+// DBPgBouncer says DATABASE_URL points at PgBouncer in transaction pooling
+// mode. Statements are then not prepared per connection and the pgvector
+// session settings are skipped (set them on the database role instead).
+// Set DB_PGBOUNCER=true.
+func DBPgBouncer() bool { return strings.EqualFold(os.Getenv("DB_PGBOUNCER"), "true") }
+
+// ---- Tenant databases ----
+//
+// Large tenants can be isolated on their own Postgres, or in another region,
+// without a separate deployment. DATABASE_URL stays the default database: it
+// holds the control plane (tenants, users, API keys, billing) and every
+// tenant that isn't routed elsewhere.
+
+// TenantDatabases maps extra database names to their URLs, from
+// TENANT_DATABASES ("eu=postgres://...,us=postgres://..."). Each gets a pool
+// sized like the default one and must be migrated like it.
+func TenantDatabases() map[string]string {
+	m, _ := parsePairs(os.Getenv("TENANT_DATABASES"))
+	return m
+}
+
+// TenantRoutes maps tenant IDs to the TENANT_DATABASES entry holding their
+// data, from TENANT_ROUTES ("<tenant-uuid>=eu,..."). Unlisted tenants use the
+// default database.
+func TenantRoutes() map[string]string {
+	m, _ := parsePairs(os.Getenv("TENANT_ROUTES"))
+	return m
+}
+
+// parsePairs parses a comma-separated list of name=value pairs. The value is
+// everything after the first "=", so URLs with query strings survive.
+func parsePairs(raw string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("%q is not a name=value pair", pair)
+		}
+		if _, dup := m[name]; dup {
+			return nil, fmt.Errorf("%q is listed twice", name)
+		}
+		m[name] = value
+	}
+	return m, nil
+}
+
 // LogLevel returns the log level (debug, info, warn, error).
-// Defaults to "info" if not set.
+// Defaults to "info" if not set. Reloadable.
 func LogLevel() string {
 	level := os.Getenv("LOG_LEVEL")
 	if level == "" {
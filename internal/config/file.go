@@ -0,0 +1,432 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A config file (YAML, path from ENGRAM_CONFIG) holds the same settings as
+// the environment, keyed by env var name in any case:
+//
+//	log_level: debug
+//	consolidation_interval: 30m
+//	recall_log_sample_rate: 0.05
+//
+// Environment variables (including .env files) win over the file. Values the
+// file supplies are exported into the process environment, so every getter
+// in this package reads them the same way as env-only config.
+
+// key describes one setting: how to validate a value, and whether a change
+// can be applied to a running server by Reload.
+type key struct {
+	check      func(string) error
+	reloadable bool
+}
+
+var keys = map[string]key{
+	"SERVER_PORT":                 {check: checkPort},
+	"DATABASE_URL":                {},
+	"MIGRATIONS_PATH":             {},
+	"ENGRAM_SETUP_TOKEN":          {},
+	"OPENAI_API_KEY":              {},
+	"ANTHROPIC_API_KEY":           {},
+	"ANTHROPIC_MODEL":             {},
+	"GEMINI_API_KEY":              {},
+	"CEREBRAS_API_KEY":            {},
+	"LLM_PROVIDER":                {check: oneOf("openai", "anthropic", "gemini", "cerebras", "mock", "none")},
+	"VISION_MODEL":                {},
+	"TRANSCRIPTION_MODEL":         {},
+	"TRANSCRIPTION_API_KEY":       {},
+	"TRANSCRIPTION_BASE_URL":      {check: checkURL},
+	"EMBEDDING_PROVIDER":          {},
+	"EMBEDDING_API_KEY":           {},
+	"EMBEDDING_MODEL":             {},
+	"EMBEDDING_BASE_URL":          {check: checkURL},
+	"EMBEDDING_DIM":               {check: checkPositiveInt},
+	"EMBEDDING_DEDUP":             {check: checkBool},
+	"CONTRADICTION_MODE":          {check: oneOf("hybrid", "llm", "embedding")},
+	"DISABLE_GRAPH":               {check: checkBool},
+	"RATE_LIMIT_RPS":              {check: checkPositiveFloat},
+	"RATE_LIMIT_BURST":            {check: checkPositiveInt},
+	"RECALL_LOG_SAMPLE_RATE":      {check: checkFraction, reloadable: true},
+	"RECALL_ENVIRONMENT_BOOST":    {check: checkFraction},
+	"RECALL_CACHE_TTL":            {check: checkNonNegativeDuration},
+	"PGVECTOR_EF_SEARCH":          {check: checkNonNegativeInt},
+	"PGVECTOR_IVFFLAT_PROBES":     {check: checkNonNegativeInt},
+	"VECTOR_QUANTIZATION":         {check: oneOf("none", "halfvec", "binary")},
+	"EPISODE_RETENTION_MONTHS":    {check: checkNonNegativeInt},
+	"JOB_WORKERS":                 {check: checkNonNegativeInt},
+	"JOB_QUEUE_SIZE":              {check: checkNonNegativeInt},
+	"INGEST_BUFFER_SIZE":          {check: checkNonNegativeInt},
+	"INGEST_BATCH_SIZE":           {check: checkNonNegativeInt},
+	"INGEST_FLUSH_INTERVAL":       {check: checkDuration},
+	"TUNER_INTERVAL":              {check: checkDuration, reloadable: true},
+	"EXPIRER_INTERVAL":            {check: checkDuration, reloadable: true},
+	"DECAY_INTERVAL":              {check: checkDuration, reloadable: true},
+	"CONSOLIDATION_INTERVAL":      {check: checkDuration, reloadable: true},
+	"CONSOLIDATION_REPLAY_BUDGET": {check: checkNonNegativeInt},
+	"REDIS_URL":                   {check: checkURL},
+	"VECTOR_STORE":                {check: oneOf("pgvector", "qdrant", "weaviate")},
+	"VECTOR_STORE_URL":            {check: checkURL},
+	"VECTOR_STORE_API_KEY":        {},
+	"VECTOR_STORE_COLLECTION":     {},
+	"EVENT_WEBHOOK_URL":           {check: checkURL},
+	"EVENT_WEBHOOK_SECRET":        {},
+	"CORS_ALLOWED_ORIGINS":        {},
+	"ENGRAM_DEFAULT_TENANT_ID":    {},
+	"ENGRAM_DEFAULT_TENANT_ROLE":  {check: oneOf("owner", "admin", "member")},
+	"SESSION_TTL_HOURS":           {check: checkPositiveInt},
+	"COOKIE_SECURE":               {check: checkBool},
+	"TRUST_PROXY_HEADERS":         {check: checkBool},
+	"APP_BASE_URL":                {check: checkURL},
+	"GOOGLE_OAUTH_CLIENT_ID":      {},
+	"GOOGLE_OAUTH_CLIENT_SECRET":  {},
+	"GITHUB_OAUTH_CLIENT_ID":      {},
+	"GITHUB_OAUTH_CLIENT_SECRET":  {},
+	"WORKOS_CLIENT_ID":            {},
+	"WORKOS_API_KEY":              {},
+	"AUDIT_SIGNING_KEY":           {},
+	"RAZORPAY_KEY_ID":             {},
+	"RAZORPAY_KEY_SECRET":         {},
+	"RAZORPAY_WEBHOOK_SECRET":     {},
+	"RAZORPAY_PLAN_DEVELOPER":     {},
+	"RAZORPAY_PLAN_TEAM":          {},
+	"RAZORPAY_PLAN_GROWTH":        {},
+	"DB_MAX_CONNS":                {check: checkPositiveInt},
+	"DB_MIN_CONNS":                {check: checkPositiveInt},
+	"DB_MAX_CONN_LIFETIME_SECS":   {check: checkNonNegativeInt},
+	"DB_MAX_CONN_IDLE_SECS":       {check: checkNonNegativeInt},
+	"DB_STATEMENT_CACHE_MODE":     {check: oneOf("cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol")},
+	"DB_PGBOUNCER":                {check: checkBool},
+	"TENANT_DATABASES":            {check: checkPairs},
+	"TENANT_ROUTES":               {check: checkPairs},
+	"LOG_LEVEL":                   {check: oneOf("debug", "info", "warn", "error"), reloadable: true},
+}
+
+var (
+	fileMu sync.Mutex
+	// fromEnv holds the keys set in the environment before the config file was
+	// read; the file never overrides them.
+	fromEnv map[string]bool
+	// fromFile holds the values the config file currently supplies.
+	fromFile map[string]string
+)
+
+// ReloadResult reports what a Reload changed.
+type ReloadResult struct {
+	// Applied lists the settings whose new values are now in effect.
+	Applied []string `json:"applied"`
+	// RestartRequired lists changed settings that are only read at startup;
+	// they keep their old values until the server restarts.
+	RestartRequired []string `json:"restart_required"`
+}
+
+// ConfigFile is the YAML config file path, from ENGRAM_CONFIG. Empty means
+// env-only configuration.
+func ConfigFile() string { return strings.TrimSpace(os.Getenv("ENGRAM_CONFIG")) }
+
+// loadFile layers the config file under the environment at startup.
+func loadFile() error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	fromEnv = make(map[string]bool)
+	for k := range keys {
+		if _, ok := os.LookupEnv(k); ok {
+			fromEnv[k] = true
+		}
+	}
+	fromFile = map[string]string{}
+
+	path := ConfigFile()
+	if path == "" {
+		return nil
+	}
+	values, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	for k, v := range values {
+		if fromEnv[k] {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+		fromFile[k] = v
+	}
+	return nil
+}
+
+// Reload re-reads the config file and applies changes to reloadable
+// settings. An invalid file is rejected as a whole and nothing changes.
+// Settings set in the environment are not affected.
+func Reload() (*ReloadResult, error) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	path := ConfigFile()
+	if path == "" {
+		return nil, errors.New("config: no config file to reload (ENGRAM_CONFIG not set)")
+	}
+	values, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	changed := make(map[string]bool)
+	for k, v := range values {
+		if old, ok := fromFile[k]; !ok || old != v {
+			changed[k] = true
+		}
+	}
+	for k := range fromFile {
+		if _, ok := values[k]; !ok {
+			changed[k] = true
+		}
+	}
+
+	for _, k := range sortedKeys(changed) {
+		if fromEnv[k] {
+			continue
+		}
+		if !keys[k].reloadable {
+			res.RestartRequired = append(res.RestartRequired, k)
+			continue
+		}
+		if v, ok := values[k]; ok {
+			if err := os.Setenv(k, v); err != nil {
+				return nil, err
+			}
+			fromFile[k] = v
+		} else {
+			_ = os.Unsetenv(k)
+			delete(fromFile, k)
+		}
+		res.Applied = append(res.Applied, k)
+	}
+	return res, nil
+}
+
+// readFile parses and validates the config file, returning its values keyed
+// by env var name.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	var errs []error
+	for name, v := range raw {
+		k := strings.ToUpper(strings.TrimSpace(name))
+		spec, ok := keys[k]
+		if !ok {
+			msg := fmt.Sprintf("config: %s: unknown setting %q", path, name)
+			if s := suggest(k); s != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", strings.ToLower(s))
+			}
+			errs = append(errs, errors.New(msg))
+			continue
+		}
+		s, err := scalar(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config: %s: %s: %w", path, name, err))
+			continue
+		}
+		if spec.check != nil && s != "" {
+			if err := spec.check(s); err != nil {
+				errs = append(errs, fmt.Errorf("config: %s: %s=%q: %w", path, name, s, err))
+				continue
+			}
+		}
+		values[k] = s
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(sortErrors(errs)...)
+	}
+	return values, nil
+}
+
+// Validate checks every known setting's effective value, reporting all
+// problems at once.
+func Validate() error {
+	var errs []error
+	for _, k := range sortedKeys(keys) {
+		spec := keys[k]
+		v := strings.TrimSpace(os.Getenv(k))
+		if spec.check == nil || v == "" {
+			continue
+		}
+		if err := spec.check(v); err != nil {
+			errs = append(errs, fmt.Errorf("config: %s=%q: %w", k, v, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// scalar renders a YAML value as an env var string. Lists become
+// comma-separated (e.g. cors_allowed_origins).
+func scalar(v any) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(t), nil
+	case []any:
+		parts := make([]string, 0, len(t))
+		for _, e := range t {
+			s, err := scalar(e)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ","), nil
+	default:
+		return "", errors.New("must be a scalar or a list of scalars")
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortErrors(errs []error) []error {
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// suggest returns the known setting closest to an unknown one, if any is
+// close enough to be a likely typo.
+func suggest(name string) string {
+	best, bestDist := "", 4
+	for k := range keys {
+		if d := editDistance(name, k); d < bestDist || (d == bestDist && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func oneOf(allowed ...string) func(string) error {
+	return func(v string) error {
+		for _, a := range allowed {
+			if v == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+func checkPort(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > 65535 {
+		return errors.New("must be a port number between 1 and 65535")
+	}
+	return nil
+}
+
+func checkPositiveInt(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+		return errors.New("must be a positive integer")
+	}
+	return nil
+}
+
+func checkNonNegativeInt(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n < 0 {
+		return errors.New("must be a non-negative integer")
+	}
+	return nil
+}
+
+func checkPositiveFloat(v string) error {
+	if f, err := strconv.ParseFloat(v, 64); err != nil || f <= 0 {
+		return errors.New("must be a positive number")
+	}
+	return nil
+}
+
+func checkFraction(v string) error {
+	if f, err := strconv.ParseFloat(v, 64); err != nil || f < 0 || f > 1 {
+		return errors.New("must be a number between 0 and 1")
+	}
+	return nil
+}
+
+func checkBool(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return errors.New("must be true or false")
+	}
+	return nil
+}
+
+func checkDuration(v string) error {
+	if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+		return errors.New(`must be a positive duration such as "30m" or "1h"`)
+	}
+	return nil
+}
+
+func checkNonNegativeDuration(v string) error {
+	if d, err := time.ParseDuration(v); err != nil || d < 0 {
+		return errors.New(`must be a duration such as "30s", or 0`)
+	}
+	return nil
+}
+
+func checkPairs(v string) error {
+	_, err := parsePairs(v)
+	return err
+}
+
+func checkURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme == "" {
+		return errors.New("must be an absolute URL")
+	}
+	return nil
+}
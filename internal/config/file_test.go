@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "engram.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// unsetForTest clears keys the file under test sets, restoring them after.
+func unsetForTest(t *testing.T, names ...string) {
+	t.Helper()
+	for _, k := range names {
+		t.Setenv(k, "")
+		_ = os.Unsetenv(k)
+	}
+}
+
+func TestLoadFile_EnvWinsOverFile(t *testing.T) {
+	unsetForTest(t, "DECAY_INTERVAL")
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("ENGRAM_CONFIG", writeConfig(t, "log_level: debug\ndecay_interval: 2h\n"))
+
+	if err := loadFile(); err != nil {
+		t.Fatalf("loadFile: %v", err)
+	}
+	if got := LogLevel(); got != "warn" {
+		t.Errorf("LogLevel = %q, want env value warn", got)
+	}
+	if got := TaskInterval("decay"); got.String() != "2h0m0s" {
+		t.Errorf("TaskInterval(decay) = %v, want 2h from file", got)
+	}
+}
+
+func TestReadFile_ReportsEveryProblem(t *testing.T) {
+	path := writeConfig(t, "consolidaton_interval: 30m\nrecall_log_sample_rate: 1.5\nlog_level: info\n")
+
+	_, err := readFile(path)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	msg := err.Error()
+	for _, want := range []string{"did you mean consolidation_interval?", `recall_log_sample_rate="1.5"`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not mention %q", msg, want)
+		}
+	}
+}
+
+func TestReload_AppliesOnlyReloadableSettings(t *testing.T) {
+	unsetForTest(t, "LOG_LEVEL", "EMBEDDING_DIM", "TUNER_INTERVAL")
+	path := writeConfig(t, "log_level: info\nembedding_dim: 1536\n")
+	t.Setenv("ENGRAM_CONFIG", path)
+	if err := loadFile(); err != nil {
+		t.Fatalf("loadFile: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("log_level: debug\nembedding_dim: 768\ntuner_interval: 1m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if !slices.Equal(res.Applied, []string{"LOG_LEVEL", "TUNER_INTERVAL"}) {
+		t.Errorf("Applied = %v", res.Applied)
+	}
+	if !slices.Equal(res.RestartRequired, []string{"EMBEDDING_DIM"}) {
+		t.Errorf("RestartRequired = %v", res.RestartRequired)
+	}
+	if LogLevel() != "debug" || EmbeddingDim() != 1536 {
+		t.Errorf("LogLevel = %q, EmbeddingDim = %d; want debug, 1536", LogLevel(), EmbeddingDim())
+	}
+
+	if err := os.WriteFile(path, []byte("log_level: verbose\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reload(); err == nil {
+		t.Error("expected invalid file to be rejected")
+	}
+	if LogLevel() != "debug" {
+		t.Errorf("LogLevel = %q after rejected reload, want debug", LogLevel())
+	}
+}
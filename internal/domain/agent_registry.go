@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AgentWorkFilter selects agents by the background work they have pending.
+// An agent matches if it has any of the kinds set; an empty filter matches
+// every agent.
+type AgentWorkFilter struct {
+	UnconsolidatedEpisodes bool // raw episodes awaiting consolidation
+	LiveMemories           bool // unarchived memories (decay, tiers, schemas, stats)
+	LiveEpisodes           bool // unarchived episodes (episode decay)
+	Feedback               bool // feedback signals (policy tuning)
+	RetentionPolicies      bool // memory policies with a retention period
+}
+
+// AgentRef identifies an agent and its tenant, so workers need not look the
+// tenant up from the agent's rows.
+type AgentRef struct {
+	ID       uuid.UUID
+	TenantID uuid.UUID
+}
+
+// AgentRegistry discovers agents for background workers from the agents
+// table, probing each agent's rows through its indexes instead of taking
+// DISTINCT over the memory or feedback tables.
+type AgentRegistry interface {
+	// ListWithWork returns the matching agents ordered by ID.
+	ListWithWork(ctx context.Context, f AgentWorkFilter) ([]AgentRef, error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgentStatistics is an agent's materialized memory_stats row. Triggers on
+// memories and consolidation_runs keep it current; ReconciledAt is when the
+// nightly reconcile last recomputed it from scratch.
+type AgentStatistics struct {
+	AgentID            uuid.UUID  `json:"agent_id"`
+	TenantID           uuid.UUID  `json:"tenant_id"`
+	MemoryCount        int64      `json:"memory_count"`
+	AverageConfidence  float64    `json:"average_confidence"`
+	HotCount           int64      `json:"hot_count"`
+	WarmCount          int64      `json:"warm_count"`
+	ColdCount          int64      `json:"cold_count"`
+	ArchiveCount       int64      `json:"archive_count"`
+	LastConsolidatedAt *time.Time `json:"last_consolidated_at,omitempty"`
+	ReconciledAt       *time.Time `json:"reconciled_at,omitempty"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+type AgentStatisticsStore interface {
+	// Get returns store.ErrNotFound if the agent has no row yet.
+	Get(ctx context.Context, agentID, tenantID uuid.UUID) (*AgentStatistics, error)
+	ListByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]AgentStatistics, int, error)
+	// Reconcile recomputes every agent's row from memories and
+	// consolidation_runs and returns how many rows it wrote.
+	Reconcile(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupRestoreResult counts, per table, the rows a restore inserted and the
+// rows it skipped because they already existed or don't belong to the
+// restoring tenant.
+type BackupRestoreResult struct {
+	Restored map[string]int `json:"restored"`
+	Skipped  map[string]int `json:"skipped"`
+	// SignatureVerified is set when the backup was signed with this
+	// server's signing key.
+	SignatureVerified bool `json:"signature_verified"`
+}
+
+// BackupStore reads a tenant's rows from one consistent snapshot and restores
+// rows from a backup.
+type BackupStore interface {
+	// Export opens a single read-only snapshot, calls start with its time,
+	// then calls emit with every backed-up row of the tenant, table by table
+	// in restore order.
+	Export(ctx context.Context, tenantID uuid.UUID, start func(snapshotAt time.Time) error, emit func(table string, row json.RawMessage) error) error
+	// Restore inserts the rows next yields, until it returns io.EOF, into
+	// tenantID in one transaction. Any other error from next, or any failed
+	// insert, rolls the whole restore back.
+	Restore(ctx context.Context, tenantID uuid.UUID, next func() (table string, row json.RawMessage, err error)) (*BackupRestoreResult, error)
+}
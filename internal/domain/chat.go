@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ChatCompleter forwards an OpenAI-format chat completion request to the
+// provider and returns its OpenAI-format response. LLM clients that can proxy
+// chat implement it alongside LLMClient.
+type ChatCompleter interface {
+	CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error)
+}
+
+// ChatUpstreamError is a non-2xx reply from the provider's chat endpoint. Body
+// is the provider's error body, passed back to the caller unchanged.
+type ChatUpstreamError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *ChatUpstreamError) Error() string {
+	return fmt.Sprintf("chat completion returned status %d", e.StatusCode)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DerivedField names a stored value computed from other data, which a bug or
+// a manual database edit can leave out of line with what it is computed from.
+type DerivedField string
+
+const (
+	// DerivedSuccessRate repairs procedures' use_count to the successes plus
+	// failures recorded; success_rate is generated from the two.
+	DerivedSuccessRate DerivedField = "success_rate"
+	// DerivedEvidenceCount sets schemas' evidence_count to the evidence they
+	// list.
+	DerivedEvidenceCount DerivedField = "evidence_count"
+	// DerivedConfidence resets the confidence of memories with evidence
+	// counts to the mean of their Beta evidence. Confidence is stateful, not
+	// derived: the reset discards decay, feedback and usage boosts applied
+	// since, so it is destructive and only runs when named explicitly.
+	DerivedConfidence DerivedField = "confidence"
+	// DerivedTiers re-applies the tier policy to memories' stored tiers.
+	DerivedTiers DerivedField = "tiers"
+	// DerivedAssociations sets thematic association strengths to the
+	// similarity of the linked embeddings they were created from.
+	DerivedAssociations DerivedField = "associations"
+)
+
+// AllDerivedFields returns every recomputable field, in the order they are
+// recomputed: confidence before the tiers drawn from it.
+func AllDerivedFields() []DerivedField {
+	return []DerivedField{DerivedSuccessRate, DerivedEvidenceCount, DerivedConfidence, DerivedTiers, DerivedAssociations}
+}
+
+// DefaultDerivedFields returns the fields recomputed when none are named:
+// every field except DerivedConfidence.
+func DefaultDerivedFields() []DerivedField {
+	return []DerivedField{DerivedSuccessRate, DerivedEvidenceCount, DerivedTiers, DerivedAssociations}
+}
+
+func ValidDerivedField(s string) bool {
+	switch DerivedField(s) {
+	case DerivedSuccessRate, DerivedEvidenceCount, DerivedConfidence, DerivedTiers, DerivedAssociations:
+		return true
+	}
+	return false
+}
+
+// DerivedAudit attributes the confidence changes of a recompute in the
+// mutation log.
+type DerivedAudit struct {
+	Reason    string
+	ActorType string
+	ActorID   uuid.UUID
+}
+
+// DerivedFieldRecomputer recomputes an agent's derived fields from the data
+// they derive from, in one transaction, returning how many rows changed per
+// field. It returns store.ErrNotFound if the agent is not in the tenant.
+// Tiers follow the tier policy rather than stored data and are skipped.
+type DerivedFieldRecomputer interface {
+	RecomputeDerived(ctx context.Context, agentID, tenantID uuid.UUID, fields []DerivedField, audit DerivedAudit) (map[DerivedField]int, error)
+}
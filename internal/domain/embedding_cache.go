@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// EmbeddingCache holds one vector per tenant and content hash (HashContent of
+// the embedded text) for the configured embedding model, written as memories
+// and episodes are stored with an embedding, which then reference it instead
+// of keeping a copy, and deleted with the last row referencing it.
+type EmbeddingCache interface {
+	// LookupEmbeddings returns the stored vectors of the hashes it knows,
+	// keyed by hash.
+	LookupEmbeddings(ctx context.Context, tenantID uuid.UUID, hashes []string) (map[string][]float32, error)
+	// PruneEmbeddings drops the vectors no memory or episode references any
+	// more.
+	PruneEmbeddings(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "strings"
+
+// MetadataEnvironment is the memory metadata key holding the environment the
+// memory was formed in.
+const MetadataEnvironment = "environment"
+
+// Environment is the context a memory or episode was formed in. Like people,
+// who recall more easily in the setting they learned something, recall leans
+// toward memories formed in the caller's current environment. Empty fields
+// are unknown.
+type Environment struct {
+	Channel string `json:"channel,omitempty"` // e.g. "slack", "email", "voice"
+	Device  string `json:"device,omitempty"`  // e.g. "mobile", "desktop"
+	Locale  string `json:"locale,omitempty"`  // e.g. "en-us"
+	Surface string `json:"surface,omitempty"` // product surface, e.g. "checkout"
+}
+
+// Normalize lowercases and trims every field.
+func (e Environment) Normalize() Environment {
+	norm := func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+	return Environment{Channel: norm(e.Channel), Device: norm(e.Device), Locale: norm(e.Locale), Surface: norm(e.Surface)}
+}
+
+// IsZero reports whether no field is known.
+func (e Environment) IsZero() bool {
+	return e == Environment{}
+}
+
+// environmentKeys names the fields in the order fields returns them.
+var environmentKeys = [4]string{"channel", "device", "locale", "surface"}
+
+func (e Environment) fields() [4]string {
+	return [4]string{e.Channel, e.Device, e.Locale, e.Surface}
+}
+
+// Match is the share of the known fields of e that formed has the same value
+// for, in [0, 1]. Both are expected normalized.
+func (e Environment) Match(formed Environment) float64 {
+	known, matched := 0, 0
+	f := formed.fields()
+	for i, v := range e.fields() {
+		if v == "" {
+			continue
+		}
+		known++
+		if f[i] == v {
+			matched++
+		}
+	}
+	if known == 0 {
+		return 0
+	}
+	return float64(matched) / float64(known)
+}
+
+// Metadata is the environment as stored under MetadataEnvironment.
+func (e Environment) Metadata() map[string]any {
+	out := make(map[string]any)
+	for i, v := range e.fields() {
+		if v != "" {
+			out[environmentKeys[i]] = v
+		}
+	}
+	return out
+}
+
+// EnvironmentOf is the environment a memory was formed in, from its
+// metadata; zero when none was recorded.
+func EnvironmentOf(m *Memory) Environment {
+	raw, ok := m.Metadata[MetadataEnvironment].(map[string]any)
+	if !ok {
+		return Environment{}
+	}
+	var f [4]string
+	for i, k := range environmentKeys {
+		f[i], _ = raw[k].(string)
+	}
+	return Environment{Channel: f[0], Device: f[1], Locale: f[2], Surface: f[3]}.Normalize()
+}
+
+// WithEnvironment returns metadata with the environment recorded, creating
+// the map when nil. A zero environment leaves metadata unchanged.
+func WithEnvironment(metadata map[string]any, e Environment) map[string]any {
+	e = e.Normalize()
+	if e.IsZero() {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata[MetadataEnvironment] = e.Metadata()
+	return metadata
+}
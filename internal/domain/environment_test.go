@@ -0,0 +1,43 @@
+package domain
+
+import "testing"
+
+func TestEnvironment_Match(t *testing.T) {
+	query := Environment{Channel: "slack", Locale: "en-us"}
+	tests := []struct {
+		name   string
+		formed Environment
+		want   float64
+	}{
+		{"same", Environment{Channel: "slack", Device: "mobile", Locale: "en-us"}, 1},
+		{"half", Environment{Channel: "slack", Locale: "de-de"}, 0.5},
+		{"unknown", Environment{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := query.Match(tt.formed); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := (Environment{}).Match(query); got != 0 {
+		t.Errorf("an unknown query environment should match nothing, got %v", got)
+	}
+}
+
+func TestEnvironmentOf_RoundTripsThroughMetadata(t *testing.T) {
+	m := &Memory{Metadata: WithEnvironment(map[string]any{"k": "v"}, Environment{Channel: " Email ", Surface: "checkout"})}
+	if m.Metadata["k"] != "v" {
+		t.Errorf("existing metadata should be kept, got %v", m.Metadata)
+	}
+	want := Environment{Channel: "email", Surface: "checkout"}
+	if got := EnvironmentOf(m); got != want {
+		t.Errorf("EnvironmentOf() = %+v, want %+v", got, want)
+	}
+	if got := WithEnvironment(nil, Environment{}); got != nil {
+		t.Errorf("a zero environment should leave metadata nil, got %v", got)
+	}
+	if got := EnvironmentOf(&Memory{}); !got.IsZero() {
+		t.Errorf("a memory without an environment should have none, got %+v", got)
+	}
+}
@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"context"
+	"errors"
+	"io"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,11 +18,14 @@ const (
 	ConsolidationProcessed  ConsolidationStatus = "processed"
 	ConsolidationAbstracted ConsolidationStatus = "abstracted"
 	ConsolidationArchived   ConsolidationStatus = "archived"
+	// ConsolidationDeadLetter marks an episode whose consolidation failed too
+	// many times; passes skip it until it is requeued.
+	ConsolidationDeadLetter ConsolidationStatus = "dead_letter"
 )
 
 func ValidConsolidationStatus(s string) bool {
 	switch ConsolidationStatus(s) {
-	case ConsolidationRaw, ConsolidationProcessed, ConsolidationAbstracted, ConsolidationArchived:
+	case ConsolidationRaw, ConsolidationProcessed, ConsolidationAbstracted, ConsolidationArchived, ConsolidationDeadLetter:
 		return true
 	}
 	return false
@@ -49,6 +56,100 @@ type CausalLink struct {
 	Confidence float32 `json:"confidence"`
 }
 
+// CausalStep is one cause-effect link of a causal chain, with the episode it
+// was extracted from.
+type CausalStep struct {
+	Cause      string      `json:"cause"`
+	Effect     string      `json:"effect"`
+	Confidence float32     `json:"confidence"`
+	EpisodeID  uuid.UUID   `json:"episode_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Outcome    OutcomeType `json:"outcome,omitempty"`
+}
+
+// CausalChain is a sequence of causal links across episodes in which each
+// link's effect is the next one's cause: X led to Y led to Z. Confidence is
+// the product of the links' confidences.
+type CausalChain struct {
+	Steps      []CausalStep `json:"steps"`
+	Confidence float32      `json:"confidence"`
+}
+
+// MaxEpisodeAttachments caps the images one episode may reference.
+const MaxEpisodeAttachments = 10
+
+// EpisodeAttachment is an image an episode refers to, stored elsewhere (object
+// storage) and referenced by URL. Caption describes the image in text, so it
+// can be embedded and recalled; the vision model writes it when the client
+// leaves it empty.
+type EpisodeAttachment struct {
+	URL       string `json:"url"`
+	MediaType string `json:"media_type,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+}
+
+// ImageCaptioner describes an image in a sentence or two using a vision model.
+type ImageCaptioner interface {
+	CaptionImage(ctx context.Context, imageURL string) (string, error)
+}
+
+// TranscriptSegment is a stretch of speech: who spoke, what was said, and when,
+// in seconds from the start of the recording.
+type TranscriptSegment struct {
+	Speaker string  `json:"speaker,omitempty"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+}
+
+// ParticipantRole is the part a participant plays in a conversation.
+type ParticipantRole string
+
+const (
+	ParticipantUser       ParticipantRole = "user"
+	ParticipantAgent      ParticipantRole = "agent"
+	ParticipantThirdParty ParticipantRole = "third_party"
+)
+
+func ValidParticipantRole(r string) bool {
+	switch ParticipantRole(r) {
+	case ParticipantUser, ParticipantAgent, ParticipantThirdParty:
+		return true
+	}
+	return false
+}
+
+// MaxEpisodeParticipants caps the participants recorded on one episode.
+const MaxEpisodeParticipants = 20
+
+// EpisodeParticipant is someone present in an episode. Speaking marks who
+// said the episode's content, so beliefs drawn from it are attributed to
+// them. EntityID is the participant's person node in the agent's entity
+// graph, resolved by name when the episode is stored.
+type EpisodeParticipant struct {
+	Name     string          `json:"name"`
+	Role     ParticipantRole `json:"role"`
+	Speaking bool            `json:"speaking,omitempty"`
+	EntityID *uuid.UUID      `json:"entity_id,omitempty"`
+}
+
+// Speakers returns the participants marked as speaking.
+func (e *Episode) Speakers() []EpisodeParticipant {
+	var out []EpisodeParticipant
+	for _, p := range e.Participants {
+		if p.Speaking {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Transcriber turns recorded audio into timestamped segments. filename
+// carries the audio format by its extension.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio io.Reader, filename string) ([]TranscriptSegment, error)
+}
+
 // Episode represents a rich experience with full context.
 // Unlike semantic memories (beliefs/facts), episodes preserve the raw experience
 // along with emotional context, entities, causal links, and outcome tracking.
@@ -62,6 +163,15 @@ type Episode struct {
 	ConversationID  *uuid.UUID `json:"conversation_id,omitempty"`
 	MessageSequence *int       `json:"message_sequence,omitempty"`
 
+	// Images referenced by the experience
+	Attachments []EpisodeAttachment `json:"attachments,omitempty"`
+
+	// Who was present, and who spoke
+	Participants []EpisodeParticipant `json:"participants,omitempty"`
+
+	// Where the experience happened (channel, device, locale, surface)
+	Environment *Environment `json:"environment,omitempty"`
+
 	// Temporal context
 	OccurredAt      time.Time `json:"occurred_at"`
 	DurationSeconds *int      `json:"duration_seconds,omitempty"`
@@ -103,6 +213,118 @@ type Episode struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Flashbulb episodes are the very important or emotionally intense events
+// that, like flashbulb memories in people, stay vivid: they decay at a small
+// fraction of the normal rate, never below a floor, and are never archived by
+// decay.
+const (
+	FlashbulbImportance         = 0.9
+	FlashbulbEmotionalIntensity = 0.8
+	FlashbulbDecaySlowdown      = 0.05 // Fraction of the episode's decay rate that applies
+	FlashbulbStrengthFloor      = 0.3  // Above every archive threshold
+)
+
+// IsFlashbulb reports whether the episode is protected as a flashbulb memory.
+func (e *Episode) IsFlashbulb() bool {
+	return e.ImportanceScore >= FlashbulbImportance ||
+		(e.EmotionalIntensity != nil && *e.EmotionalIntensity >= FlashbulbEmotionalIntensity)
+}
+
+// DecayedStrength is the episode's memory strength after days without
+// access: exponential decay at its decay rate, slowed and floored for a
+// flashbulb episode. EpisodeStore.ApplyDecay computes the same in SQL.
+func (e *Episode) DecayedStrength(days float64) float32 {
+	rate := float64(e.DecayRate)
+	if e.IsFlashbulb() {
+		rate *= FlashbulbDecaySlowdown
+	}
+	strength := float32(float64(e.MemoryStrength) * math.Exp(-rate*days))
+	if e.IsFlashbulb() && strength < FlashbulbStrengthFloor {
+		strength = min(e.MemoryStrength, FlashbulbStrengthFloor)
+	}
+	if strength < 0 {
+		strength = 0
+	}
+	return strength
+}
+
+// ReplayPriority ranks an unconsolidated episode for replay, in [0, 1]:
+// importance counts most, then emotional intensity, then a known outcome,
+// failures a little above successes.
+func (e *Episode) ReplayPriority() float32 {
+	p := 0.5 * e.ImportanceScore
+	if e.EmotionalIntensity != nil {
+		p += 0.3 * *e.EmotionalIntensity
+	}
+	switch e.Outcome {
+	case OutcomeFailure:
+		p += 0.2
+	case OutcomeSuccess:
+		p += 0.15
+	}
+	return p
+}
+
+// Serial position: what a conversation opens and closes with usually carries
+// its point (the request, the conclusion), so its first and last episodes are
+// weighted up in recall and consolidation. A conversation shorter than
+// MinPositionEpisodes has no middle to stand out from and is not weighted.
+const (
+	DefaultPrimacyWeight = 0.2 // Extra weight of a conversation's first episode
+	DefaultRecencyWeight = 0.2 // Extra weight of its last episode
+	MaxPositionWeight    = 1.0
+	MinPositionEpisodes  = 3
+)
+
+var ErrInvalidPositionWeight = errors.New("primacy_weight and recency_weight must be between 0 and 1")
+
+// EpisodePositionSettings is an agent's primacy and recency weighting. A
+// weight of 0 turns that effect off.
+type EpisodePositionSettings struct {
+	AgentID       uuid.UUID `json:"agent_id"`
+	TenantID      uuid.UUID `json:"tenant_id,omitempty"`
+	PrimacyWeight float32   `json:"primacy_weight"`
+	RecencyWeight float32   `json:"recency_weight"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultEpisodePositionSettings applies to agents without stored settings.
+func DefaultEpisodePositionSettings() EpisodePositionSettings {
+	return EpisodePositionSettings{PrimacyWeight: DefaultPrimacyWeight, RecencyWeight: DefaultRecencyWeight}
+}
+
+// Validate checks both weights are within bounds.
+func (s EpisodePositionSettings) Validate() error {
+	if s.PrimacyWeight < 0 || s.PrimacyWeight > MaxPositionWeight || s.RecencyWeight < 0 || s.RecencyWeight > MaxPositionWeight {
+		return ErrInvalidPositionWeight
+	}
+	return nil
+}
+
+// ConversationEnds identifies the first and last episodes of a conversation,
+// ordered by message sequence and then by time.
+type ConversationEnds struct {
+	First    uuid.UUID
+	Last     uuid.UUID
+	Episodes int
+}
+
+// Factor is what an episode's score or importance is multiplied by for its
+// position in its conversation: 1 plus the primacy weight for the first
+// episode, 1 plus the recency weight for the last, 1 otherwise.
+func (s EpisodePositionSettings) Factor(episodeID uuid.UUID, ends ConversationEnds) float32 {
+	if ends.Episodes < MinPositionEpisodes {
+		return 1
+	}
+	switch episodeID {
+	case ends.First:
+		return 1 + s.PrimacyWeight
+	case ends.Last:
+		return 1 + s.RecencyWeight
+	}
+	return 1
+}
+
 // AssociationType represents the type of link between episodes.
 type AssociationType string
 
@@ -135,4 +357,71 @@ type EpisodeAssociation struct {
 type EpisodeWithScore struct {
 	Episode
 	Score float32 `json:"score"`
+	// Passage is the chunk of a long episode that best matched the query,
+	// when recall matched at chunk level.
+	Passage string `json:"passage,omitempty"`
+}
+
+// EpisodeChunk is one passage of a long episode with its own embedding, so
+// recall can match the passage without the rest of the episode diluting it.
+type EpisodeChunk struct {
+	EpisodeID  uuid.UUID `json:"episode_id"`
+	AgentID    uuid.UUID `json:"agent_id"`
+	TenantID   uuid.UUID `json:"tenant_id"`
+	ChunkIndex int       `json:"chunk_index"`
+	Content    string    `json:"content"`
+	Embedding  []float32 `json:"-"`
+}
+
+// EpisodeChunkMatch is a chunk that matched a recall query.
+type EpisodeChunkMatch struct {
+	EpisodeID  uuid.UUID
+	ChunkIndex int
+	Content    string
+	Score      float32
+}
+
+// ConsolidationFailure counts an episode's failed consolidation attempts.
+// DeadLetteredAt is set once the episode has been moved to the dead-letter
+// status.
+type ConsolidationFailure struct {
+	EpisodeID      uuid.UUID  `json:"episode_id"`
+	TenantID       uuid.UUID  `json:"tenant_id"`
+	AgentID        uuid.UUID  `json:"agent_id"`
+	Stage          string     `json:"stage"`
+	FailureCount   int        `json:"failure_count"`
+	LastError      string     `json:"last_error"`
+	FirstFailedAt  time.Time  `json:"first_failed_at"`
+	LastFailedAt   time.Time  `json:"last_failed_at"`
+	DeadLetteredAt *time.Time `json:"dead_lettered_at,omitempty"`
+}
+
+// EpisodeSlot is a kind of time slot an episode falls in.
+type EpisodeSlot string
+
+const (
+	EpisodeSlotTimeOfDay EpisodeSlot = "time_of_day"
+	EpisodeSlotDayOfWeek EpisodeSlot = "day_of_week"
+)
+
+// EpisodeSlotCount counts an agent's episodes about one subject that fall in
+// one time slot. SubjectKind is "topic" or "mood" (Subject "negative mood" or
+// "positive mood"); an empty SubjectKind counts every episode in the slot.
+type EpisodeSlotCount struct {
+	SubjectKind string
+	Subject     string
+	Slot        EpisodeSlot
+	Value       string
+	Count       int
+	// Recent holds the IDs of the newest episodes counted, newest first.
+	Recent []uuid.UUID
+}
+
+// EpisodeSlotCounter counts an agent's episodes since a time by subject and
+// time slot in the store, so temporal patterns are mined without loading the
+// episodes. Topics are trimmed and lower-cased; an episode counts toward a
+// mood when |emotional_valence| >= valence. Up to recent episode IDs are kept
+// per count.
+type EpisodeSlotCounter interface {
+	CountEpisodeSlots(ctx context.Context, agentID, tenantID uuid.UUID, since time.Time, valence float64, recent int) ([]EpisodeSlotCount, error)
 }
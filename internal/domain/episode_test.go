@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// decayForAYear runs a daily decay pass over a year on an episode that is
+// never accessed. Like EpisodeStore.ApplyDecay, each pass decays the
+// already-decayed strength by the whole time since the last access.
+func decayForAYear(e Episode) Episode {
+	for day := 1; day <= 365; day++ {
+		e.MemoryStrength = e.DecayedStrength(float64(day))
+	}
+	return e
+}
+
+func TestEpisode_IsFlashbulb(t *testing.T) {
+	intense, mild := float32(0.85), float32(0.5)
+	tests := []struct {
+		name string
+		ep   Episode
+		want bool
+	}{
+		{"ordinary", Episode{ImportanceScore: 0.5}, false},
+		{"important", Episode{ImportanceScore: 0.95}, true},
+		{"emotional", Episode{ImportanceScore: 0.2, EmotionalIntensity: &intense}, true},
+		{"mildly emotional", Episode{ImportanceScore: 0.2, EmotionalIntensity: &mild}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ep.IsFlashbulb(); got != tt.want {
+				t.Errorf("IsFlashbulb() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEpisode_FlashbulbSurvivesAYearOfDecay(t *testing.T) {
+	const archiveThreshold = 0.15 // DecayService's default
+	intense := float32(0.9)
+
+	ordinary := decayForAYear(Episode{ImportanceScore: 0.5, MemoryStrength: 1, DecayRate: 0.1})
+	if ordinary.MemoryStrength >= archiveThreshold {
+		t.Fatalf("ordinary episode should fade below %v, got %v", archiveThreshold, ordinary.MemoryStrength)
+	}
+
+	for name, ep := range map[string]Episode{
+		"important": {ImportanceScore: 0.95, MemoryStrength: 1, DecayRate: 0.1},
+		"emotional": {ImportanceScore: 0.3, EmotionalIntensity: &intense, MemoryStrength: 1, DecayRate: 0.1},
+	} {
+		got := decayForAYear(ep).MemoryStrength
+		if got < FlashbulbStrengthFloor || got <= archiveThreshold {
+			t.Errorf("%s: flashbulb episode fell to %v", name, got)
+		}
+	}
+}
+
+func TestEpisode_DecayedStrengthKeepsWeakFlashbulbBelowFloor(t *testing.T) {
+	ep := Episode{ImportanceScore: 0.95, MemoryStrength: 0.2, DecayRate: 0.1}
+	if got := ep.DecayedStrength(365); got != 0.2 {
+		t.Fatalf("the floor must not raise a weaker episode, got %v", got)
+	}
+}
+
+func TestEpisode_ReplayPriority(t *testing.T) {
+	intense := float32(1)
+	routine := Episode{ImportanceScore: 0.3}
+	failure := Episode{ImportanceScore: 0.3, Outcome: OutcomeFailure}
+	success := Episode{ImportanceScore: 0.3, Outcome: OutcomeSuccess}
+	emotional := Episode{ImportanceScore: 0.3, EmotionalIntensity: &intense}
+	important := Episode{ImportanceScore: 1}
+
+	order := []Episode{important, emotional, failure, success, routine}
+	for i := 1; i < len(order); i++ {
+		if order[i-1].ReplayPriority() <= order[i].ReplayPriority() {
+			t.Errorf("expected priority %d (%v) above %d (%v)", i-1, order[i-1].ReplayPriority(), i, order[i].ReplayPriority())
+		}
+	}
+}
+
+func TestEpisodePositionSettings_Factor(t *testing.T) {
+	first, middle, last := uuid.New(), uuid.New(), uuid.New()
+	s := EpisodePositionSettings{PrimacyWeight: 0.3, RecencyWeight: 0.1}
+	ends := ConversationEnds{First: first, Last: last, Episodes: 3}
+
+	if got := s.Factor(first, ends); got != 1.3 {
+		t.Errorf("first episode: got %v, want 1.3", got)
+	}
+	if got := s.Factor(last, ends); got != 1.1 {
+		t.Errorf("last episode: got %v, want 1.1", got)
+	}
+	if got := s.Factor(middle, ends); got != 1 {
+		t.Errorf("middle episode: got %v, want 1", got)
+	}
+	ends.Episodes = 2
+	if got := s.Factor(first, ends); got != 1 {
+		t.Errorf("a conversation without a middle should not be weighted, got %v", got)
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox event types. Mutation events are "memory." followed by the
+// MutationType (memory.feedback, memory.deletion, ...) and come with an audit
+// row. The state events below are written by the store on every change of
+// that kind, whether or not the caller also logs a mutation for it.
+const (
+	EventMemoryCreated           = "memory.created"
+	EventMemoryReinforced        = "memory.reinforced"
+	EventMemoryConfidenceChanged = "memory.confidence_changed"
+	EventMemoryArchived          = "memory.archived"
+	EventMemoryRestored          = "memory.restored"
+	EventMemoryMutationPrefix    = "memory."
+)
+
+// OutboxEvent is a memory change event waiting in, or delivered from, the
+// transactional outbox. ID increases in commit order within a writer and is
+// the delivery cursor consumers can dedupe on.
+type OutboxEvent struct {
+	ID          int64          `json:"id"`
+	TenantID    *uuid.UUID     `json:"tenant_id,omitempty"`
+	AgentID     *uuid.UUID     `json:"agent_id,omitempty"`
+	AggregateID *uuid.UUID     `json:"aggregate_id,omitempty"`
+	Type        string         `json:"type"`
+	Payload     map[string]any `json:"data"`
+	CreatedAt   time.Time      `json:"occurred_at"`
+	Attempts    int            `json:"-"`
+}
@@ -0,0 +1,147 @@
+package domain
+
+import "math"
+
+// EvidencePriorWeight is how many observations a memory's confidence at write
+// time is worth: a belief written at 0.8 starts as Beta(1.6, 0.4), so a
+// handful of reinforcements or contradictions soon outweigh the estimate it
+// was written with.
+const EvidencePriorWeight = 2.0
+
+// DefaultCredibleLevel is the probability mass of the intervals reported for
+// memories.
+const DefaultCredibleLevel = 0.9
+
+// minEvidence keeps both Beta parameters positive for memories written at a
+// confidence of exactly 0 or 1.
+const minEvidence = 1e-3
+
+// ConfidenceInterval is an equal-tailed credible interval for the probability
+// that a memory is true, from its evidence counts. Observations is the total
+// evidence behind it, prior included: the more there is, the narrower the
+// interval.
+type ConfidenceInterval struct {
+	Lower        float64 `json:"lower"`
+	Upper        float64 `json:"upper"`
+	Mean         float64 `json:"mean"`
+	Level        float64 `json:"level"`
+	Observations float64 `json:"observations"`
+}
+
+// Width is Upper - Lower.
+func (ci ConfidenceInterval) Width() float64 {
+	return ci.Upper - ci.Lower
+}
+
+// PriorEvidence returns the evidence counts a memory written at confidence
+// starts with.
+func PriorEvidence(confidence float32) (alpha, beta float64) {
+	c := math.Min(math.Max(float64(confidence), 0), 1)
+	return c * EvidencePriorWeight, (1 - c) * EvidencePriorWeight
+}
+
+// Evidence returns the memory's evidence counts. A memory read without them
+// gets the prior implied by its confidence plus one supporting observation
+// per reinforcement, which is what the counts start from.
+func (m *Memory) Evidence() (alpha, beta float64) {
+	if m.EvidenceFor > 0 || m.EvidenceAgainst > 0 {
+		return m.EvidenceFor, m.EvidenceAgainst
+	}
+	alpha, beta = PriorEvidence(m.Confidence)
+	return alpha + float64(max(m.ReinforcementCount, 0)), beta
+}
+
+// ConfidenceInterval returns the memory's credible interval at level.
+func (m *Memory) ConfidenceInterval(level float64) ConfidenceInterval {
+	alpha, beta := m.Evidence()
+	return EvidenceInterval(alpha, beta, level)
+}
+
+// EvidenceInterval returns the equal-tailed credible interval of
+// Beta(alpha, beta) at level.
+func EvidenceInterval(alpha, beta, level float64) ConfidenceInterval {
+	alpha, beta = math.Max(alpha, minEvidence), math.Max(beta, minEvidence)
+	tail := (1 - level) / 2
+	return ConfidenceInterval{
+		Lower:        betaQuantile(alpha, beta, tail),
+		Upper:        betaQuantile(alpha, beta, 1-tail),
+		Mean:         alpha / (alpha + beta),
+		Level:        level,
+		Observations: alpha + beta,
+	}
+}
+
+// betaQuantile inverts the Beta CDF by bisection, which converges for any
+// parameters without a starting guess.
+func betaQuantile(alpha, beta, p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return 1
+	}
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 50; i++ {
+		mid := (lo + hi) / 2
+		if regIncBeta(alpha, beta, mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regIncBeta is the regularized incomplete beta function I_x(a, b), the Beta
+// CDF, evaluated by its continued fraction on whichever side converges.
+func regIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lab, _ := math.Lgamma(a + b)
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log1p(-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction for I_x(a, b) by the
+// modified Lentz method.
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		eps  = 1e-12
+		tiny = 1e-300
+	)
+	nonZero := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+	c := 1.0
+	d := 1 / nonZero(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		even := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 / nonZero(1+even*d)
+		c = nonZero(1 + even/c)
+		h *= d * c
+
+		odd := -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 / nonZero(1+odd*d)
+		c = nonZero(1 + odd/c)
+		step := d * c
+		h *= step
+		if math.Abs(step-1) < eps {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEvidenceInterval_MatchesKnownQuantiles(t *testing.T) {
+	// Beta(1, 1) is uniform; Beta(2, 1) has CDF x², so its quantile is √p.
+	uniform := EvidenceInterval(1, 1, 0.9)
+	if math.Abs(uniform.Lower-0.05) > 1e-6 || math.Abs(uniform.Upper-0.95) > 1e-6 {
+		t.Errorf("Beta(1,1): expected [0.05, 0.95], got [%f, %f]", uniform.Lower, uniform.Upper)
+	}
+	skewed := EvidenceInterval(2, 1, 0.9)
+	if math.Abs(skewed.Lower-math.Sqrt(0.05)) > 1e-6 || math.Abs(skewed.Upper-math.Sqrt(0.95)) > 1e-6 {
+		t.Errorf("Beta(2,1): expected [%f, %f], got [%f, %f]", math.Sqrt(0.05), math.Sqrt(0.95), skewed.Lower, skewed.Upper)
+	}
+}
+
+func TestEvidenceInterval_NarrowsWithEvidence(t *testing.T) {
+	fresh := Memory{Confidence: 0.8}
+	seasoned := Memory{Confidence: 0.8, EvidenceFor: 40, EvidenceAgainst: 10}
+
+	a, b := fresh.ConfidenceInterval(DefaultCredibleLevel), seasoned.ConfidenceInterval(DefaultCredibleLevel)
+	if math.Abs(a.Mean-0.8) > 1e-6 || math.Abs(b.Mean-0.8) > 1e-6 {
+		t.Fatalf("expected both means at 0.8, got %f and %f", a.Mean, b.Mean)
+	}
+	if b.Width() >= a.Width()/2 {
+		t.Errorf("expected 50 observations to narrow the interval well below the prior's, got %f vs %f", b.Width(), a.Width())
+	}
+	if b.Lower > 0.8 || b.Upper < 0.8 {
+		t.Errorf("expected the interval to contain the mean, got [%f, %f]", b.Lower, b.Upper)
+	}
+}
+
+func TestMemoryEvidence_FallsBackToPriorAndReinforcements(t *testing.T) {
+	m := Memory{Confidence: 0.75, ReinforcementCount: 3}
+	alpha, beta := m.Evidence()
+	if math.Abs(alpha-4.5) > 1e-6 || math.Abs(beta-0.5) > 1e-6 {
+		t.Fatalf("expected Beta(4.5, 0.5), got Beta(%f, %f)", alpha, beta)
+	}
+
+	certain := Memory{Confidence: 1}
+	if ci := certain.ConfidenceInterval(DefaultCredibleLevel); math.IsNaN(ci.Lower) || ci.Upper > 1 {
+		t.Fatalf("expected a finite interval at confidence 1, got %+v", ci)
+	}
+}
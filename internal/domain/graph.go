@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +30,22 @@ func ValidRelationType(r string) bool {
 	return false
 }
 
+// ParseRelationTypes parses a list of relation type names, failing on the
+// first unknown one.
+func ParseRelationTypes(names []string) ([]RelationType, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	out := make([]RelationType, 0, len(names))
+	for _, n := range names {
+		if !ValidRelationType(n) {
+			return nil, fmt.Errorf("unknown relation type %q", n)
+		}
+		out = append(out, RelationType(n))
+	}
+	return out, nil
+}
+
 // SymmetricRelations indicates which relations are bidirectional
 var SymmetricRelations = map[RelationType]bool{
 	RelationEntityLink: true,
@@ -234,8 +251,32 @@ type HybridRecallRequest struct {
 	// memory is about). When set, AgentID may be uuid.Nil for a cross-agent
 	// anchor lookup ("everything known about this guest").
 	AnchorID *uuid.UUID `json:"anchor_id,omitempty"`
+	// Subject restricts recall to beliefs about this subject, e.g. "user" or
+	// a participant's name.
+	Subject string `json:"subject,omitempty"`
 	// SessionID folds this session's short-term traces into the composed recall.
 	SessionID *uuid.UUID `json:"session_id,omitempty"`
+	// ExpandSummaries keeps detail memories in the results even when a summary
+	// covering them is also returned.
+	ExpandSummaries bool `json:"expand_summaries,omitempty"`
+	// Control marks a counterfactual "memory off" recall: results are ranked
+	// and logged, but usage side effects are skipped and the caller withholds
+	// them from the agent.
+	Control bool `json:"control,omitempty"`
+	// Conversation is the caller's current message window. Memories whose
+	// content already appears in it verbatim are suppressed.
+	Conversation []Message `json:"conversation,omitempty"`
+	// NoInterference ranks memories without the interference penalty for
+	// near-duplicate neighbors.
+	NoInterference bool `json:"no_interference,omitempty"`
+	// Environment is the caller's current environment; memories formed in
+	// it rank higher.
+	Environment Environment `json:"environment,omitempty"`
+	// Accuracy trades recall quality for latency; see RecallOpts.Accuracy.
+	Accuracy float32 `json:"accuracy,omitempty"`
+	// RelationTypes restricts graph traversal to these relation types, e.g.
+	// only causal links for a "why" question. Empty follows all.
+	RelationTypes []RelationType `json:"relation_types,omitempty"`
 }
 
 type ScoredMemory struct {
@@ -245,6 +286,21 @@ type ScoredMemory struct {
 	FinalScore  float32     `json:"score"`
 	GraphPath   []uuid.UUID `json:"graph_path,omitempty"`
 	PathLength  int         `json:"path_length,omitempty"`
+	// Interference is the factor FinalScore was multiplied by because
+	// similar memories crowd this one; zero when none did.
+	Interference float32 `json:"interference,omitempty"`
+	// EnvironmentBoost is the factor FinalScore was multiplied by because
+	// the memory was formed in the caller's environment; zero when it wasn't.
+	EnvironmentBoost float32 `json:"environment_boost,omitempty"`
+}
+
+// RecallGroup is the recall for one query of a batch.
+type RecallGroup struct {
+	Query    string         `json:"query"`
+	Memories []ScoredMemory `json:"memories"`
+	// Deduplicated counts the memories left out of this group because
+	// another query of the batch ranked them higher.
+	Deduplicated int `json:"deduplicated,omitempty"`
 }
 
 type GraphTraversalResult struct {
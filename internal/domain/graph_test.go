@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRelationTypes(t *testing.T) {
+	got, err := ParseRelationTypes([]string{"causal", "derived_from"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []RelationType{RelationCausal, RelationDerivedFrom}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got, err := ParseRelationTypes(nil); err != nil || got != nil {
+		t.Fatalf("expected no filter for no names, got %v, %v", got, err)
+	}
+	if _, err := ParseRelationTypes([]string{"causal", "causes"}); err == nil {
+		t.Fatal("expected an error for an unknown relation type")
+	}
+}
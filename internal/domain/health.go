@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgentHealthCounts are the non-memory tallies behind an agent's health
+// report.
+type AgentHealthCounts struct {
+	UnconsolidatedEpisodes int
+	OldestUnconsolidated   *time.Time // nil when no episode is waiting
+	Procedures             int
+	Schemas                int
+}
+
+// HealthStore answers the health endpoint with aggregate queries instead of
+// loading episodes, procedures and schemas to count them in Go.
+type HealthStore interface {
+	AgentHealthCounts(ctx context.Context, agentID, tenantID uuid.UUID) (*AgentHealthCounts, error)
+}
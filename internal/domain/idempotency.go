@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRecord is a stored Idempotency-Key. RequestHash fingerprints the
+// original request so a key reused for a different request can be rejected.
+// StatusCode is zero while the original request is still in flight.
+type IdempotencyRecord struct {
+	TenantID     uuid.UUID
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// Completed reports whether the original request has finished and its
+// response can be replayed.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
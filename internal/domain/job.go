@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobKind names the work a background job performs.
+type JobKind string
+
+const (
+	JobKindExtraction        JobKind = "extraction"
+	JobKindConsolidation     JobKind = "consolidation"
+	JobKindChatCapture       JobKind = "chat_capture"
+	JobKindConversationClose JobKind = "conversation_close"
+)
+
+// JobStatus is a background job's lifecycle state.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Done reports whether the job has finished, successfully or not.
+func (s JobStatus) Done() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// Job is a unit of work run on the shared background worker pool. Result holds
+// the job's output once it has succeeded. RequestID and CorrelationID are
+// those of the request or run that submitted it.
+type Job struct {
+	ID            uuid.UUID  `json:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id"`
+	AgentID       uuid.UUID  `json:"agent_id"`
+	Kind          JobKind    `json:"kind"`
+	Status        JobStatus  `json:"status"`
+	Result        any        `json:"result,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	RequestID     string     `json:"request_id,omitempty"`
+	CorrelationID string     `json:"correlation_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
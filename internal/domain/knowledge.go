@@ -0,0 +1,45 @@
+package domain
+
+import "github.com/google/uuid"
+
+// KnowledgeSourceKind is the kind of record a knowledge claim cites.
+type KnowledgeSourceKind string
+
+const (
+	KnowledgeSourceMemory  KnowledgeSourceKind = "memory"
+	KnowledgeSourceEpisode KnowledgeSourceKind = "episode"
+	KnowledgeSourceSchema  KnowledgeSourceKind = "schema"
+)
+
+// KnowledgeSources are the records recalled for a topic, which a narrative
+// about it may draw on.
+type KnowledgeSources struct {
+	Memories []Memory
+	Episodes []Episode
+	Schemas  []Schema
+}
+
+// KnowledgeCitation points a claim at a record it rests on. Confidence is the
+// record's own: a memory's or schema's confidence, or how strongly an episode
+// is still remembered.
+type KnowledgeCitation struct {
+	ID         uuid.UUID           `json:"id"`
+	Kind       KnowledgeSourceKind `json:"kind"`
+	Confidence float32             `json:"confidence"`
+}
+
+// KnowledgeClaim is one statement of a narrative with the records it cites.
+// Its Confidence is the mean of theirs.
+type KnowledgeClaim struct {
+	Statement  string              `json:"statement"`
+	Citations  []KnowledgeCitation `json:"citations"`
+	Confidence float32             `json:"confidence"`
+}
+
+// KnowledgeNarrative answers "what do you know about Topic": a short prose
+// summary and the claims behind it.
+type KnowledgeNarrative struct {
+	Topic   string           `json:"topic"`
+	Summary string           `json:"summary"`
+	Claims  []KnowledgeClaim `json:"claims"`
+}
@@ -23,6 +23,7 @@ const (
 	MutationQuarantine        MutationType = "quarantine"
 	MutationQuarantineRelease MutationType = "quarantine_release"
 	MutationQuarantineReject  MutationType = "quarantine_reject"
+	MutationReconsolidation   MutationType = "reconsolidation"
 )
 
 type MutationSourceType string
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,24 @@ const (
 	MemoryTypeDecision   MemoryType = "decision"
 	MemoryTypeConstraint MemoryType = "constraint"
 	MemoryTypeBelief     MemoryType = "belief"
+
+	// MemoryTypeSummary is a rollup written by consolidation from a cluster of
+	// related beliefs. The memories it covers stay stored and retrievable; their
+	// IDs are listed in its metadata under SummaryMembersKey.
+	MemoryTypeSummary MemoryType = "summary"
+)
+
+// Metadata keys carried by summary memories.
+const (
+	SummaryMembersKey = "summarized_memory_ids"
+	SummaryTopicKey   = "summary_topic"
+)
+
+// Metadata keys carried by beliefs consolidated from an episode with a
+// speaking participant: who the belief came from, and their person entity.
+const (
+	AttributedToKey       = "attributed_to"
+	AttributedEntityIDKey = "attributed_entity_id"
 )
 
 type EvidenceType string
@@ -93,6 +112,87 @@ func ValidMemoryType(t string) bool {
 	return false
 }
 
+// ComparableMemoryTypes returns the types whose memories can reinforce or
+// contradict a memory of type t. Subjective statements (preferences,
+// constraints) are compared with each other, and propositional ones (facts,
+// beliefs, decisions) with each other.
+func ComparableMemoryTypes(t MemoryType) []MemoryType {
+	switch t {
+	case MemoryTypePreference, MemoryTypeConstraint:
+		return []MemoryType{MemoryTypePreference, MemoryTypeConstraint}
+	case MemoryTypeFact, MemoryTypeBelief, MemoryTypeDecision:
+		return []MemoryType{MemoryTypeFact, MemoryTypeBelief, MemoryTypeDecision}
+	}
+	return []MemoryType{t}
+}
+
+// ValidRecallMemoryType reports whether t can be used to filter recall. It
+// accepts every writable type plus summaries, which only consolidation creates.
+func ValidRecallMemoryType(t string) bool {
+	return ValidMemoryType(t) || MemoryType(t) == MemoryTypeSummary
+}
+
+// SummaryMemberIDs returns the IDs of the memories a summary covers, or nil if
+// m is not a summary. Metadata read back from the store holds them as strings.
+func SummaryMemberIDs(m Memory) []uuid.UUID {
+	if m.Type != MemoryTypeSummary || m.Metadata == nil {
+		return nil
+	}
+	var ids []uuid.UUID
+	switch v := m.Metadata[SummaryMembersKey].(type) {
+	case []uuid.UUID:
+		ids = append(ids, v...)
+	case []string:
+		for _, s := range v {
+			if id, err := uuid.Parse(s); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	case []any:
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				if id, err := uuid.Parse(s); err == nil {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// Subjects extraction uses for the two sides of a conversation.
+const (
+	SubjectUser      = "user"
+	SubjectAssistant = "assistant"
+)
+
+// NormalizeSubject trims an extracted subject and folds the ways a model
+// refers to the conversation's two sides onto SubjectUser and
+// SubjectAssistant.
+func NormalizeSubject(s string) string {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "user", "the user":
+		return SubjectUser
+	case "assistant", "the assistant", "agent", "the agent":
+		return SubjectAssistant
+	}
+	return s
+}
+
+// SameSubject reports whether two beliefs may be about the same subject. A
+// belief with no subject (written before subjects were recorded, or by a
+// caller that gave none) may be about anyone.
+func SameSubject(a, b *Memory) bool {
+	if a.SubjectID != nil && b.SubjectID != nil {
+		return *a.SubjectID == *b.SubjectID
+	}
+	if a.Subject == "" || b.Subject == "" {
+		return true
+	}
+	return strings.EqualFold(a.Subject, b.Subject)
+}
+
 type MemoryBinding string
 
 const (
@@ -160,6 +260,23 @@ type Memory struct {
 	BeliefPredicate    string         `json:"belief_predicate,omitempty"`
 	BeliefObject       string         `json:"belief_object,omitempty"`
 
+	// Subject is who or what the belief is about: SubjectUser, SubjectAssistant
+	// or a name. SubjectID is its entity node, when resolved.
+	Subject   string     `json:"subject,omitempty"`
+	SubjectID *uuid.UUID `json:"subject_id,omitempty"`
+
+	// Topic is what the belief is about, normalized with NormalizeTopic
+	// (e.g. "diet", "work schedule"). Health and uncertainty reports are
+	// broken down by it.
+	Topic string `json:"topic,omitempty"`
+
+	// EvidenceFor and EvidenceAgainst are the Beta(alpha, beta) evidence
+	// counts behind Confidence: the prior implied by its confidence when
+	// written, plus one per reinforcement or contradiction since. Read paths
+	// that don't load them leave both zero; see Evidence.
+	EvidenceFor     float64 `json:"evidence_for,omitempty"`
+	EvidenceAgainst float64 `json:"evidence_against,omitempty"`
+
 	// Quarantine is an input-only hint: when true the caller is declaring this
 	// write untrusted, so the Provenance Firewall holds it for review regardless
 	// of tenant policy. Not a stored column.
@@ -168,10 +285,12 @@ type Memory struct {
 	QuarantineReason string     `json:"quarantine_reason,omitempty"`
 	QuarantinedAt    *time.Time `json:"quarantined_at,omitempty"`
 
-	// Tier is derived from Confidence (not a stored column). Populated on read
-	// for UI display so the server is the single source of truth for tiering;
-	// omitted when unset. See AnnotateTiers.
+	// Tier is the materialized tier maintained by the tier transition worker.
+	// Read paths that don't load it leave it empty; AnnotateTiers then derives
+	// it from Confidence for UI display. Omitted when unset.
 	Tier MemoryTier `json:"tier,omitempty"`
+	// Pinned memories are held in the hot tier regardless of confidence.
+	Pinned bool `json:"pinned,omitempty"`
 }
 
 type ConversationIngestRequest struct {
@@ -197,4 +316,6 @@ type ExtractedConversationMemory struct {
 	Confidence   float32      `json:"confidence,omitempty"`
 	EvidenceType EvidenceType `json:"evidence_type,omitempty"`
 	Source       string       `json:"source"`
+	Subject      string       `json:"subject,omitempty"`
+	Topic        string       `json:"topic,omitempty"`
 }
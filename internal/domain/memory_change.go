@@ -0,0 +1,19 @@
+package domain
+
+import "github.com/google/uuid"
+
+// MemoryChange scopes a write to memories, for caches of recall results.
+// MemoryID alone names one memory that was removed or rescored downwards,
+// which only affects recalls that returned it. Otherwise the write may have surfaced
+// memories no cached recall returned: it covers AgentID's recalls, every
+// agent of TenantID when AgentID is zero, and everything when both are.
+type MemoryChange struct {
+	TenantID uuid.UUID
+	AgentID  uuid.UUID
+	MemoryID uuid.UUID
+}
+
+// MemoryChangeListener is told of every write to memories, after the fact.
+type MemoryChangeListener interface {
+	MemoryChanged(c MemoryChange)
+}
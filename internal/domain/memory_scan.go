@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrStopIteration ends an iteration early without error when returned from
+// an iterator callback.
+var ErrStopIteration = errors.New("stop iteration")
+
+// MemoryStatsQuery sets the thresholds AgentMemoryStats buckets by.
+type MemoryStatsQuery struct {
+	AtRiskBelow    float32   // confidence below which a memory is at risk
+	UncertainBelow float32   // confidence below which a memory's type is an uncertainty area
+	RecentSince    time.Time // accessed after this counts as recently reinforced
+
+	// Topic trends compare the memories created since TrendSince with those
+	// created in the TrendWindow before it.
+	TrendSince  time.Time
+	TrendWindow time.Duration
+	MaxTopics   int // topics returned, largest first; 0 for all
+}
+
+// AgentMemoryStats aggregates an agent's live memories.
+type AgentMemoryStats struct {
+	Count              int
+	AverageConfidence  float32
+	AtRisk             int
+	RecentlyReinforced int
+	UncertainTypes     []string // sorted
+	ByType             map[string]int
+	ByTier             map[string]int
+
+	// ByTopic breaks the memories down by domain.TopicOf, largest first.
+	// UncertainTopics are the topics with a memory below UncertainBelow.
+	ByTopic         []TopicStats
+	UncertainTopics []string // sorted
+}
+
+// MemoryScanner walks an agent's live (unarchived, unquarantined) memories
+// without loading them all at once, for agents too large for
+// GetByAgentForDecay.
+type MemoryScanner interface {
+	// IterateForDecay calls fn for each live memory of the agent in ID order,
+	// reading a page at a time. Returning ErrStopIteration from fn ends the
+	// iteration with a nil error; any other error is returned as is.
+	IterateForDecay(ctx context.Context, agentID uuid.UUID, fn func(*Memory) error) error
+	// AgentMemoryStats computes the agent's memory statistics in the database.
+	AgentMemoryStats(ctx context.Context, agentID uuid.UUID, q MemoryStatsQuery) (*AgentMemoryStats, error)
+}
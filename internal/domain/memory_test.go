@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSummaryMemberIDs_RoundTripsThroughJSON(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	m := Memory{
+		Type:     MemoryTypeSummary,
+		Metadata: map[string]any{SummaryMembersKey: []string{ids[0].String(), ids[1].String()}},
+	}
+
+	// Metadata read back from the store decodes as []any.
+	raw, err := json.Marshal(m.Metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Metadata = nil
+	if err := json.Unmarshal(raw, &m.Metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	got := SummaryMemberIDs(m)
+	if len(got) != 2 || got[0] != ids[0] || got[1] != ids[1] {
+		t.Fatalf("expected %v, got %v", ids, got)
+	}
+
+	m.Type = MemoryTypeFact
+	if SummaryMemberIDs(m) != nil {
+		t.Fatal("expected non-summary memories to cover nothing")
+	}
+}
+
+func TestSameSubject(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	cases := []struct {
+		name string
+		a, b Memory
+		want bool
+	}{
+		{"unknown matches anything", Memory{}, Memory{Subject: "Alice"}, true},
+		{"names fold case", Memory{Subject: "alice"}, Memory{Subject: "Alice"}, true},
+		{"different names", Memory{Subject: "Alice"}, Memory{Subject: "Bob"}, false},
+		{"entities decide over names", Memory{Subject: "Al", SubjectID: &alice}, Memory{Subject: "Alice", SubjectID: &alice}, true},
+		{"different entities", Memory{Subject: "Alice", SubjectID: &alice}, Memory{Subject: "Alice", SubjectID: &bob}, false},
+	}
+	for _, c := range cases {
+		if got := SameSubject(&c.a, &c.b); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestNormalizeSubject(t *testing.T) {
+	for in, want := range map[string]string{
+		" The User ":    SubjectUser,
+		"agent":         SubjectAssistant,
+		"the assistant": SubjectAssistant,
+		" Maria ":       "Maria",
+		"":              "",
+	} {
+		if got := NormalizeSubject(in); got != want {
+			t.Errorf("NormalizeSubject(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
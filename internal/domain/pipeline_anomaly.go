@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventPipelineAnomaly is the outbox event raised for a PipelineAnomaly.
+const EventPipelineAnomaly = "pipeline.anomaly"
+
+// PipelineMetric names a memory pipeline measure watched for anomalies.
+type PipelineMetric string
+
+const (
+	// PipelineMetricContradictions counts contradictions detected per day.
+	PipelineMetricContradictions PipelineMetric = "contradictions"
+	// PipelineMetricExtractionYield is semantic memories extracted per
+	// episode consolidated.
+	PipelineMetricExtractionYield PipelineMetric = "extraction_yield"
+	// PipelineMetricDecayArchived counts memories decay archived per day.
+	PipelineMetricDecayArchived PipelineMetric = "decay_archived"
+)
+
+// PipelineAnomalyKind says which way a metric moved.
+type PipelineAnomalyKind string
+
+const (
+	PipelineAnomalySpike    PipelineAnomalyKind = "spike"
+	PipelineAnomalyCollapse PipelineAnomalyKind = "collapse"
+)
+
+// PipelineMetricDay is one day of an agent's pipeline activity. Age counts
+// whole days back from the time the metrics were read: 0 is the last 24
+// hours.
+type PipelineMetricDay struct {
+	Age               int
+	Contradictions    int
+	EpisodesProcessed int
+	SemanticExtracted int
+	DecayArchived     int
+}
+
+// PipelineAnomaly is a metric whose last 24 hours stand out from the agent's
+// baseline, as a silent prompt or model regression would make it. Score is
+// the standard deviations above the baseline for a spike and the fraction of
+// the baseline left for a collapse.
+type PipelineAnomaly struct {
+	AgentID    uuid.UUID           `json:"agent_id"`
+	TenantID   uuid.UUID           `json:"tenant_id"`
+	Metric     PipelineMetric      `json:"metric"`
+	Kind       PipelineAnomalyKind `json:"kind"`
+	Current    float64             `json:"current"`
+	Baseline   float64             `json:"baseline"`
+	Score      float64             `json:"score"`
+	DetectedAt time.Time           `json:"detected_at"`
+}
+
+// PipelineMetricsStore reads pipeline activity from contradictions,
+// consolidation runs and the decay audit log.
+type PipelineMetricsStore interface {
+	// DailyByAgent returns the agent's activity for each of the last days
+	// days before now that had any, keyed by age.
+	DailyByAgent(ctx context.Context, agentID, tenantID uuid.UUID, now time.Time, days int) ([]PipelineMetricDay, error)
+}
@@ -6,6 +6,28 @@ import (
 	"github.com/google/uuid"
 )
 
+// BeliefUpdateMode selects how the write path moves a belief's confidence
+// when a new memory reinforces or contradicts it.
+type BeliefUpdateMode string
+
+const (
+	// BeliefUpdateFixed applies fixed steps: +0.05 on reinforcement, -0.2 on
+	// a hard contradiction (half that on a soft one).
+	BeliefUpdateFixed BeliefUpdateMode = "fixed"
+	// BeliefUpdateBayesian treats each new memory as evidence: confidence is
+	// updated by the likelihood ratio of its source's reliability, and a new
+	// belief's prior comes from its evidence type.
+	BeliefUpdateBayesian BeliefUpdateMode = "bayesian"
+)
+
+func ValidBeliefUpdateMode(s string) bool {
+	switch BeliefUpdateMode(s) {
+	case BeliefUpdateFixed, BeliefUpdateBayesian:
+		return true
+	}
+	return false
+}
+
 type Policy struct {
 	ID             uuid.UUID  `json:"id"`
 	AgentID        uuid.UUID  `json:"agent_id"`
@@ -16,4 +38,8 @@ type Policy struct {
 	AutoSummarize  bool       `json:"auto_summarize"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// BeliefUpdate is how beliefs of this type are reinforced and
+	// contradicted; empty means BeliefUpdateFixed.
+	BeliefUpdate BeliefUpdateMode `json:"belief_update,omitempty"`
 }
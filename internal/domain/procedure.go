@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -47,6 +49,9 @@ type Procedure struct {
 	TriggerPattern   string    `json:"trigger_pattern"`
 	TriggerKeywords  []string  `json:"trigger_keywords,omitempty"`
 	TriggerEmbedding []float32 `json:"-"`
+	// Conditions are optional structured triggers evaluated exactly at
+	// activation time.
+	Conditions *ProcedureConditions `json:"conditions,omitempty"`
 
 	// Action/response pattern (what to do)
 	ActionTemplate string     `json:"action_template"`
@@ -68,6 +73,11 @@ type Procedure struct {
 	MemoryStrength float32    `json:"memory_strength"`
 	LastVerifiedAt *time.Time `json:"last_verified_at,omitempty"`
 
+	// Provenance: how the agent came to have this procedure, and for a
+	// transferred one the skill pack it came from.
+	Source     ProcedureSource `json:"source"`
+	SourcePack string          `json:"source_pack,omitempty"`
+
 	// Versioning
 	Version           int        `json:"version"`
 	PreviousVersionID *uuid.UUID `json:"previous_version_id,omitempty"`
@@ -76,6 +86,15 @@ type Procedure struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ProcedureSource records how an agent came to have a procedure.
+type ProcedureSource string
+
+const (
+	ProcedureSourceLearned     ProcedureSource = "learned"     // Extracted from the agent's own episodes
+	ProcedureSourceSeeded      ProcedureSource = "seeded"      // Declared in a seed document
+	ProcedureSourceTransferred ProcedureSource = "transferred" // Installed from another agent's skill pack
+)
+
 // ExampleExchange represents a concrete example of the procedure in use.
 type ExampleExchange struct {
 	Trigger  string `json:"trigger"`
@@ -96,3 +115,56 @@ type ProcedureExtraction struct {
 	ActionTemplate  string     `json:"action_template"`
 	ActionType      ActionType `json:"action_type"`
 }
+
+// MaxTriggerKeywordWords is the longest trigger keyword, in words, that the
+// keyword fast path can find in a cue. Longer keywords are still stored but
+// only match through trigger similarity.
+const MaxTriggerKeywordWords = 3
+
+// NormalizeTriggerKeyword folds a trigger keyword onto the form it is indexed
+// and matched under: lower case, punctuation dropped, words separated by
+// single spaces, so "Reset-Password" and "reset password" match.
+func NormalizeTriggerKeyword(s string) string {
+	return strings.Join(triggerWords(s), " ")
+}
+
+// NormalizeTriggerKeywords normalizes each keyword and drops empty and
+// repeated ones, keeping the first occurrence's position.
+func NormalizeTriggerKeywords(keywords []string) []string {
+	if len(keywords) == 0 {
+		return keywords
+	}
+	out := make([]string, 0, len(keywords))
+	seen := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		if k = NormalizeTriggerKeyword(k); k != "" && !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// TriggerTerms lists every normalized run of up to MaxTriggerKeywordWords
+// consecutive words in text: the keys under which a procedure's trigger
+// keywords can match it exactly.
+func TriggerTerms(text string) []string {
+	words := triggerWords(text)
+	var terms []string
+	seen := make(map[string]bool)
+	for n := 1; n <= MaxTriggerKeywordWords; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			if term := strings.Join(words[i:i+n], " "); !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+func triggerWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidProcedureConditions is returned for conditions that can never be
+// evaluated, such as a malformed time or an unknown time zone.
+var ErrInvalidProcedureConditions = errors.New("invalid procedure conditions")
+
+// ProcedureConditions are structured triggers checked exactly, rather than by
+// similarity, when working memory is activated. A procedure with conditions
+// fires whenever all of them hold, whatever the cues say, and is kept out of
+// working memory while any fails. Unset conditions always hold.
+type ProcedureConditions struct {
+	// TimeOfDay holds within the window.
+	TimeOfDay *TimeOfDayWindow `json:"time_of_day,omitempty"`
+	// UserAttributes holds when the user has every listed attribute with the
+	// listed value, compared case-insensitively.
+	UserAttributes map[string]string `json:"user_attributes,omitempty"`
+	// Topics holds when the conversation is tagged with any of these topics.
+	Topics []string `json:"topics,omitempty"`
+	// ActiveSchemas holds when any schema of these names is active.
+	ActiveSchemas []string `json:"active_schemas,omitempty"`
+}
+
+// TimeOfDayWindow is a daily window from After (inclusive) to Before
+// (exclusive), both "HH:MM". A window whose Before is earlier than its After
+// wraps past midnight.
+type TimeOfDayWindow struct {
+	After  string `json:"after"`
+	Before string `json:"before"`
+	// Timezone is an IANA zone name; empty means UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ConditionContext is what procedure conditions are evaluated against.
+type ConditionContext struct {
+	Now            time.Time
+	UserAttributes map[string]string
+	Topics         []string
+	ActiveSchemas  []string
+}
+
+// IsZero reports whether no condition is set.
+func (c *ProcedureConditions) IsZero() bool {
+	return c == nil || (c.TimeOfDay == nil && len(c.UserAttributes) == 0 && len(c.Topics) == 0 && len(c.ActiveSchemas) == 0)
+}
+
+// Validate checks that every condition can be evaluated.
+func (c *ProcedureConditions) Validate() error {
+	if c == nil || c.TimeOfDay == nil {
+		return nil
+	}
+	w := c.TimeOfDay
+	if _, err := parseClock(w.After); err != nil {
+		return fmt.Errorf("%w: time_of_day.after: %v", ErrInvalidProcedureConditions, err)
+	}
+	if _, err := parseClock(w.Before); err != nil {
+		return fmt.Errorf("%w: time_of_day.before: %v", ErrInvalidProcedureConditions, err)
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("%w: time_of_day.timezone: %v", ErrInvalidProcedureConditions, err)
+	}
+	return nil
+}
+
+// Match reports whether all set conditions hold in cc. Conditions that fail
+// validation never hold.
+func (c *ProcedureConditions) Match(cc ConditionContext) bool {
+	if c == nil {
+		return true
+	}
+	if c.TimeOfDay != nil && !c.TimeOfDay.contains(cc.Now) {
+		return false
+	}
+	for key, want := range c.UserAttributes {
+		got, ok := lookupFold(cc.UserAttributes, key)
+		if !ok || !strings.EqualFold(strings.TrimSpace(got), strings.TrimSpace(want)) {
+			return false
+		}
+	}
+	if len(c.Topics) > 0 && !anyTopic(c.Topics, cc.Topics) {
+		return false
+	}
+	if len(c.ActiveSchemas) > 0 && !anyFold(c.ActiveSchemas, cc.ActiveSchemas) {
+		return false
+	}
+	return true
+}
+
+func (w *TimeOfDayWindow) contains(now time.Time) bool {
+	after, err1 := parseClock(w.After)
+	before, err2 := parseClock(w.Before)
+	loc, err3 := time.LoadLocation(w.Timezone)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return false
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if after <= before {
+		return minute >= after && minute < before
+	}
+	return minute >= after || minute < before
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("want HH:MM, got %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func lookupFold(m map[string]string, key string) (string, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func anyTopic(want, have []string) bool {
+	present := make(map[string]bool, len(have))
+	for _, t := range have {
+		present[NormalizeTopic(t)] = true
+	}
+	for _, t := range want {
+		if present[NormalizeTopic(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+func anyFold(want, have []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if strings.EqualFold(strings.TrimSpace(w), strings.TrimSpace(h)) {
+				return true
+			}
+		}
+	}
+	return false
+}
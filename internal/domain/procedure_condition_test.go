@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProcedureConditions_TimeOfDay(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2026, 3, 2, hour, minute, 0, 0, time.UTC) }
+
+	office := &ProcedureConditions{TimeOfDay: &TimeOfDayWindow{After: "09:00", Before: "17:00"}}
+	if !office.Match(ConditionContext{Now: at(9, 0)}) || office.Match(ConditionContext{Now: at(17, 0)}) {
+		t.Error("window should include its start and exclude its end")
+	}
+
+	night := &ProcedureConditions{TimeOfDay: &TimeOfDayWindow{After: "22:00", Before: "06:00"}}
+	if !night.Match(ConditionContext{Now: at(23, 30)}) || !night.Match(ConditionContext{Now: at(5, 59)}) {
+		t.Error("window wrapping midnight should hold on both sides of it")
+	}
+	if night.Match(ConditionContext{Now: at(12, 0)}) {
+		t.Error("window wrapping midnight should not hold at noon")
+	}
+
+	// 20:00 UTC is 15:00 in New York (EST).
+	ny := &ProcedureConditions{TimeOfDay: &TimeOfDayWindow{After: "09:00", Before: "17:00", Timezone: "America/New_York"}}
+	if !ny.Match(ConditionContext{Now: at(20, 0)}) {
+		t.Error("window should be evaluated in its time zone")
+	}
+}
+
+func TestProcedureConditions_Match(t *testing.T) {
+	c := &ProcedureConditions{
+		UserAttributes: map[string]string{"plan": "Enterprise"},
+		Topics:         []string{"billing", "refunds"},
+		ActiveSchemas:  []string{"Frustrated customer"},
+	}
+	cc := ConditionContext{
+		UserAttributes: map[string]string{"Plan": "enterprise"},
+		Topics:         []string{"Refund"},
+		ActiveSchemas:  []string{"frustrated customer"},
+	}
+	if !c.Match(cc) {
+		t.Fatal("expected all conditions to hold")
+	}
+
+	cc.UserAttributes = map[string]string{"plan": "free"}
+	if c.Match(cc) {
+		t.Error("a differing attribute should fail")
+	}
+	cc.UserAttributes = nil
+	if c.Match(cc) {
+		t.Error("a missing attribute should fail")
+	}
+
+	var none *ProcedureConditions
+	if !none.IsZero() || !none.Match(ConditionContext{}) {
+		t.Error("no conditions should always hold")
+	}
+}
+
+func TestProcedureConditions_Validate(t *testing.T) {
+	for _, w := range []TimeOfDayWindow{
+		{After: "9am", Before: "17:00"},
+		{After: "09:00", Before: "25:00"},
+		{After: "09:00", Before: "17:00", Timezone: "Mars/Olympus"},
+	} {
+		c := &ProcedureConditions{TimeOfDay: &w}
+		if err := c.Validate(); !errors.Is(err, ErrInvalidProcedureConditions) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidProcedureConditions", w, err)
+		}
+	}
+	ok := &ProcedureConditions{TimeOfDay: &TimeOfDayWindow{After: "09:00", Before: "17:00", Timezone: "Europe/Berlin"}}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("Validate = %v, want nil", err)
+	}
+}
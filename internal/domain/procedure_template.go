@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidActionTemplate is returned for an action template with a
+// malformed or unknown {{variable}}.
+var ErrInvalidActionTemplate = errors.New("invalid action template")
+
+// Action template variables. Besides these, a template may name an entity
+// type ({{person}}, {{product}}, ...) for the most relevant entity of that
+// type in working memory, and {{user_<attribute>}} for a user attribute
+// passed with the activation, such as {{user_name}}.
+const (
+	TemplateVarGoal  = "goal"  // The session's current goal
+	TemplateVarTopic = "topic" // The first topic of the activation
+
+	TemplateVarUserPrefix = "user_"
+)
+
+var (
+	templatePlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+	templateVarName     = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// TemplateVariables lists the distinct variables a template names, in order
+// of first use.
+func TemplateVariables(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range templatePlaceholder.FindAllStringSubmatch(template, -1) {
+		if name := m[1]; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ValidateActionTemplate checks that every placeholder is closed and names a
+// variable that can be resolved at activation time.
+func ValidateActionTemplate(template string) error {
+	rest := templatePlaceholder.ReplaceAllString(template, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return fmt.Errorf("%w: unbalanced {{ }}", ErrInvalidActionTemplate)
+	}
+	for _, name := range TemplateVariables(template) {
+		if !KnownTemplateVariable(name) {
+			return fmt.Errorf("%w: unknown variable {{%s}}", ErrInvalidActionTemplate, name)
+		}
+	}
+	return nil
+}
+
+// KnownTemplateVariable reports whether name can be resolved at activation
+// time.
+func KnownTemplateVariable(name string) bool {
+	if !templateVarName.MatchString(name) {
+		return false
+	}
+	switch name {
+	case TemplateVarGoal, TemplateVarTopic:
+		return true
+	}
+	if strings.HasPrefix(name, TemplateVarUserPrefix) {
+		return len(name) > len(TemplateVarUserPrefix)
+	}
+	return name != string(EntityOther) && ValidEntityType(name)
+}
+
+// TemplateUserVariable is the variable a user attribute is available under:
+// "Name" becomes user_name and "home city" user_home_city.
+func TemplateUserVariable(attribute string) string {
+	words := strings.FieldsFunc(strings.ToLower(attribute), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	return TemplateVarUserPrefix + strings.Join(words, "_")
+}
+
+// RenderedAction is an action template with its variables substituted.
+type RenderedAction struct {
+	Template string `json:"template"`
+	Rendered string `json:"rendered"`
+	// Missing lists the variables that had no value; their placeholders are
+	// left in Rendered.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// RenderActionTemplate substitutes vars into template.
+func RenderActionTemplate(template string, vars map[string]string) RenderedAction {
+	out := RenderedAction{Template: template}
+	missing := make(map[string]bool)
+	out.Rendered = templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if v, ok := vars[name]; ok && v != "" {
+			return v
+		}
+		if !missing[name] {
+			missing[name] = true
+			out.Missing = append(out.Missing, name)
+		}
+		return placeholder
+	})
+	return out
+}
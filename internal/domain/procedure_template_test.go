@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateActionTemplate(t *testing.T) {
+	valid := []string{
+		"Apologize and offer a refund",
+		"Greet {{user_name}} and ask how {{product}} is working out",
+		"Stay on {{ topic }} until {{goal}} is done",
+	}
+	for _, tmpl := range valid {
+		if err := ValidateActionTemplate(tmpl); err != nil {
+			t.Errorf("ValidateActionTemplate(%q) = %v, want nil", tmpl, err)
+		}
+	}
+	invalid := []string{
+		"Greet {{user_name}",
+		"Greet user_name}}",
+		"Ask about {{weather}}",
+		"Ask about {{other}}",
+		"Greet {{user_}}",
+		"Greet {{User Name}}",
+	}
+	for _, tmpl := range invalid {
+		if err := ValidateActionTemplate(tmpl); !errors.Is(err, ErrInvalidActionTemplate) {
+			t.Errorf("ValidateActionTemplate(%q) = %v, want ErrInvalidActionTemplate", tmpl, err)
+		}
+	}
+}
+
+func TestRenderActionTemplate(t *testing.T) {
+	got := RenderActionTemplate("Hi {{user_name}}, about {{product}}: see {{product}} docs for {{topic}}",
+		map[string]string{"user_name": "Ada", "product": "Engram"})
+	if got.Rendered != "Hi Ada, about Engram: see Engram docs for {{topic}}" {
+		t.Errorf("Rendered = %q", got.Rendered)
+	}
+	if !reflect.DeepEqual(got.Missing, []string{"topic"}) {
+		t.Errorf("Missing = %v, want [topic]", got.Missing)
+	}
+	if vars := TemplateVariables(got.Template); !reflect.DeepEqual(vars, []string{"user_name", "product", "topic"}) {
+		t.Errorf("TemplateVariables = %v", vars)
+	}
+}
+
+func TestTemplateUserVariable(t *testing.T) {
+	cases := map[string]string{"Name": "user_name", "home city": "user_home_city", "!!": ""}
+	for in, want := range cases {
+		if got := TemplateUserVariable(in); got != want {
+			t.Errorf("TemplateUserVariable(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTriggerKeywords(t *testing.T) {
+	got := NormalizeTriggerKeywords([]string{"Reset-Password", "reset password", "  Refund ", "!!", "refund"})
+	want := []string{"reset password", "refund"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTriggerKeywords = %v, want %v", got, want)
+	}
+}
+
+func TestTriggerTerms(t *testing.T) {
+	got := TriggerTerms("How do I reset my password?")
+	for _, term := range []string{"password", "reset my", "reset my password"} {
+		found := false
+		for _, g := range got {
+			found = found || g == term
+		}
+		if !found {
+			t.Errorf("TriggerTerms missing %q in %v", term, got)
+		}
+	}
+	for _, g := range got {
+		if g == "how do i reset" {
+			t.Errorf("TriggerTerms should stop at %d words, got %q", MaxTriggerKeywordWords, g)
+		}
+	}
+	if terms := TriggerTerms("?!"); len(terms) != 0 {
+		t.Errorf("TriggerTerms of punctuation = %v, want none", terms)
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "math"
+
+// Recall accuracy trades recall quality for latency per call. It runs from
+// just above 0, the fastest and roughest search, to 1, the most thorough;
+// 0.5 takes about the default number of candidates. 0 leaves the search as
+// the server is configured.
+
+// ValidRecallAccuracy reports whether a is 0 or in (0, 1].
+func ValidRecallAccuracy(a float32) bool {
+	return a >= 0 && a <= 1
+}
+
+// SearchEffort is how thoroughly an approximate vector index is scanned.
+// Zero fields keep the database session's settings.
+type SearchEffort struct {
+	EfSearch int // hnsw.ef_search
+	Probes   int // ivfflat.probes
+}
+
+// IsZero reports whether the effort leaves the session's settings alone.
+func (e SearchEffort) IsZero() bool {
+	return e.EfSearch <= 0 && e.Probes <= 0
+}
+
+// RecallSearchEffort maps an accuracy to index settings, geometrically:
+// hnsw.ef_search from 10 to 400 and ivfflat.probes from 1 to 100.
+func RecallSearchEffort(accuracy float32) SearchEffort {
+	if accuracy <= 0 {
+		return SearchEffort{}
+	}
+	a := math.Min(float64(accuracy), 1)
+	return SearchEffort{
+		EfSearch: int(math.Round(10 * math.Pow(40, a))),
+		Probes:   int(math.Round(math.Pow(100, a))),
+	}
+}
+
+// RecallCandidates scales n, a candidate pool sized for the default search,
+// by accuracy: from n/2 near 0 through n at 0.5 to 2n at 1. Zero accuracy
+// keeps n.
+func RecallCandidates(n int, accuracy float32) int {
+	if accuracy <= 0 {
+		return n
+	}
+	a := math.Min(float64(accuracy), 1)
+	return max(1, int(math.Round(float64(n)*math.Pow(4, a)/2)))
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestRecallSearchEffort_ScalesWithAccuracy(t *testing.T) {
+	if !RecallSearchEffort(0).IsZero() {
+		t.Error("expected zero accuracy to keep the session's settings")
+	}
+	low, high := RecallSearchEffort(0.01), RecallSearchEffort(1)
+	if low.EfSearch != 10 || low.Probes != 1 {
+		t.Errorf("expected the lowest effort near ef_search 10 and 1 probe, got %+v", low)
+	}
+	if high.EfSearch != 400 || high.Probes != 100 {
+		t.Errorf("expected full accuracy at ef_search 400 and 100 probes, got %+v", high)
+	}
+	if mid := RecallSearchEffort(0.5); mid.EfSearch <= low.EfSearch || mid.EfSearch >= high.EfSearch {
+		t.Errorf("expected a middle effort between the bounds, got %+v", mid)
+	}
+}
+
+func TestRecallCandidates_ScalesPoolAroundDefault(t *testing.T) {
+	tests := []struct {
+		accuracy float32
+		want     int
+	}{
+		{0, 30},
+		{0.5, 30},
+		{1, 60},
+		{2, 60},
+		{0.0001, 15},
+	}
+	for _, tt := range tests {
+		if got := RecallCandidates(30, tt.accuracy); got != tt.want {
+			t.Errorf("RecallCandidates(30, %v) = %d, want %d", tt.accuracy, got, tt.want)
+		}
+	}
+	if !ValidRecallAccuracy(0) || !ValidRecallAccuracy(1) || ValidRecallAccuracy(1.5) || ValidRecallAccuracy(-0.1) {
+		t.Error("expected accuracy valid in [0, 1] only")
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecallModeActivation tags recall logs written for working memory
+// activations rather than memory recalls.
+const RecallModeActivation RecallMode = "activation"
+
+// RecallLog is a sampled record of one recall's ranking, kept for offline
+// analysis of scorer behavior. The query is stored only as a hash. Control
+// is set for counterfactual requests whose results were withheld from the
+// agent; those are always logged.
+type RecallLog struct {
+	ID            uuid.UUID         `json:"id"`
+	TenantID      uuid.UUID         `json:"tenant_id"`
+	AgentID       *uuid.UUID        `json:"agent_id,omitempty"`
+	QueryHash     string            `json:"query_hash"`
+	Mode          RecallMode        `json:"mode"`
+	ScorerVersion string            `json:"scorer_version"`
+	TopK          int               `json:"top_k"`
+	VectorWeight  float64           `json:"vector_weight"`
+	GraphWeight   float64           `json:"graph_weight"`
+	LatencyMs     int64             `json:"latency_ms"`
+	Control       bool              `json:"control"`
+	Results       []RecallLogResult `json:"results"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// RecallLogResult is one ranked result in a RecallLog. Breakdown holds the
+// scorer's per-factor explanation for the result. Activation logs set
+// ActivatedType instead of Type, since their results may be episodes or
+// procedures as well as memories.
+type RecallLogResult struct {
+	MemoryID      uuid.UUID           `json:"memory_id"`
+	Rank          int                 `json:"rank"`
+	Type          MemoryType          `json:"type,omitempty"`
+	ActivatedType ActivatedMemoryType `json:"activated_type,omitempty"`
+	Score         float32             `json:"score"`
+	Breakdown     any                 `json:"breakdown,omitempty"`
+}
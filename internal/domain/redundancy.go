@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RedundancyMember is one side of a redundant pair, with the fields a merge
+// decides on.
+type RedundancyMember struct {
+	ID                 uuid.UUID
+	Confidence         float32
+	ReinforcementCount int
+}
+
+// RedundantPair is two live memories of an agent whose embeddings are at
+// least the requested cosine similarity apart.
+type RedundantPair struct {
+	A, B       RedundancyMember
+	Similarity float32
+}
+
+// RedundancyStore finds near-duplicate memories in the database rather than by
+// comparing every pair in Go.
+type RedundancyStore interface {
+	// StreamRedundantPairs probes each memory's nearest neighbors (at most
+	// neighbors of them) and calls fn for each pair with similarity at least
+	// threshold, ordered by A then by decreasing similarity. Summaries, archived and quarantined
+	// memories are skipped. A pair may be reported from both sides. Returning
+	// an error from fn stops the stream.
+	StreamRedundantPairs(ctx context.Context, agentID uuid.UUID, threshold float32, neighbors int, fn func(RedundantPair) error) error
+}
+
+// NeighborhoodStore measures how crowded a memory's neighborhood is, for
+// interference during recall.
+type NeighborhoodStore interface {
+	// CountNeighbors probes the nearest neighbors (at most k) of each of the
+	// tenant's memories in ids and counts those of the same agent with
+	// similarity in [minSim, maxSim). Summaries, archived and quarantined
+	// memories are not counted. Memories without such neighbors are absent
+	// from the map.
+	CountNeighbors(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID, minSim, maxSim float32, k int) (map[uuid.UUID]int, error)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReplayEventKind is the kind of entry in a conversation replay timeline.
+type ReplayEventKind string
+
+const (
+	ReplayEpisode          ReplayEventKind = "episode"           // An episode was recorded
+	ReplayMemoryUsed       ReplayEventKind = "memory_used"       // A memory was retrieved/used during an episode
+	ReplayMemoryDerived    ReplayEventKind = "memory_derived"    // Consolidation derived a semantic memory
+	ReplayProcedureDerived ReplayEventKind = "procedure_derived" // Consolidation derived a procedure
+	ReplayAssociation      ReplayEventKind = "association"       // Episode linked to another episode
+	ReplayOutcome          ReplayEventKind = "outcome"           // Episode outcome recorded
+)
+
+// ReplayEvent is one entry in a conversation replay. EpisodeID is the
+// episode the event belongs to; exactly one of the detail fields is set,
+// matching Kind.
+type ReplayEvent struct {
+	Kind      ReplayEventKind `json:"kind"`
+	At        time.Time       `json:"at"`
+	EpisodeID uuid.UUID       `json:"episode_id"`
+
+	Episode     *Episode            `json:"episode,omitempty"`
+	Memory      *Memory             `json:"memory,omitempty"`
+	Usage       *EpisodeMemoryUsage `json:"usage,omitempty"`
+	Procedure   *Procedure          `json:"procedure,omitempty"`
+	Association *EpisodeAssociation `json:"association,omitempty"`
+	Outcome     *EpisodeOutcome     `json:"outcome,omitempty"`
+}
+
+// EpisodeOutcome is the outcome recorded on an episode.
+type EpisodeOutcome struct {
+	Outcome     OutcomeType `json:"outcome"`
+	Description string      `json:"description,omitempty"`
+	Valence     *float32    `json:"valence,omitempty"`
+}
+
+// ConversationReplay is a conversation's episodes with everything the agent
+// derived from them, interleaved in time order.
+type ConversationReplay struct {
+	ConversationID uuid.UUID     `json:"conversation_id"`
+	Episodes       int           `json:"episodes"`
+	Events         []ReplayEvent `json:"events"`
+}
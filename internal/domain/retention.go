@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionTarget is the class of rows a retention rule deletes, and decides
+// which timestamp the rule's age is measured from.
+type RetentionTarget string
+
+const (
+	// RetentionMemories deletes live memories by creation time. Pinned
+	// memories are never deleted by retention.
+	RetentionMemories RetentionTarget = "memories"
+	// RetentionArchivedMemories deletes archived memories by archival time.
+	RetentionArchivedMemories RetentionTarget = "archived_memories"
+	// RetentionAbstractedEpisodes deletes episodes whose content has already
+	// been consolidated into memories, by the time they were abstracted.
+	RetentionAbstractedEpisodes RetentionTarget = "abstracted_episodes"
+)
+
+// MaxRetentionDays bounds a rule's age to ten years.
+const MaxRetentionDays = 3650
+
+func ValidRetentionTarget(t string) bool {
+	switch RetentionTarget(t) {
+	case RetentionMemories, RetentionArchivedMemories, RetentionAbstractedEpisodes:
+		return true
+	}
+	return false
+}
+
+// RetentionRule deletes a tenant's rows of one target once they are older than
+// AfterDays. MemoryType narrows memory targets to one type; nil applies the
+// rule to every type.
+type RetentionRule struct {
+	ID         uuid.UUID       `json:"id"`
+	TenantID   uuid.UUID       `json:"tenant_id"`
+	Target     RetentionTarget `json:"target"`
+	MemoryType *MemoryType     `json:"memory_type,omitempty"`
+	AfterDays  int             `json:"after_days"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func (r RetentionRule) Validate() error {
+	if !ValidRetentionTarget(string(r.Target)) {
+		return fmt.Errorf("target must be one of %s, %s, %s", RetentionMemories, RetentionArchivedMemories, RetentionAbstractedEpisodes)
+	}
+	if r.AfterDays < 1 || r.AfterDays > MaxRetentionDays {
+		return fmt.Errorf("after_days must be between 1 and %d", MaxRetentionDays)
+	}
+	if r.MemoryType != nil {
+		if r.Target == RetentionAbstractedEpisodes {
+			return errors.New("memory_type does not apply to episodes")
+		}
+		if !ValidRecallMemoryType(string(*r.MemoryType)) {
+			return fmt.Errorf("invalid memory_type %q", *r.MemoryType)
+		}
+	}
+	return nil
+}
+
+// RetentionPreview is what a rule would delete if it ran now.
+type RetentionPreview struct {
+	Rule      RetentionRule `json:"rule"`
+	Count     int64         `json:"count"`
+	OldestAt  *time.Time    `json:"oldest_at,omitempty"`
+	SampleIDs []uuid.UUID   `json:"sample_ids"`
+}
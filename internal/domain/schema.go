@@ -41,6 +41,10 @@ type Schema struct {
 	AgentID  uuid.UUID `json:"agent_id"`
 	TenantID uuid.UUID `json:"tenant_id"`
 
+	// Optional parent in the schema hierarchy ("Developer" -> "Frontend Developer").
+	// Children inherit their ancestors' attributes.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+
 	// Schema identification
 	SchemaType  SchemaType `json:"schema_type"`
 	Name        string     `json:"name"`
@@ -101,3 +105,55 @@ type MemoryCluster struct {
 	Theme     string      `json:"theme"`
 	Centroid  []float32   `json:"-"`
 }
+
+// SchemaPassState records when schema maintenance last ran for an agent.
+// Incremental passes only consider memories that became eligible after
+// LastPassAt; IncrementalPasses counts passes since the last full re-cluster.
+type SchemaPassState struct {
+	AgentID           uuid.UUID `json:"agent_id"`
+	TenantID          uuid.UUID `json:"tenant_id"`
+	LastPassAt        time.Time `json:"last_pass_at"`
+	LastFullPassAt    time.Time `json:"last_full_pass_at"`
+	IncrementalPasses int       `json:"incremental_passes"`
+}
+
+// SchemaAncestors returns the parent chain of a schema, nearest first. Parents
+// missing from byID end the chain, and a repeated schema stops the walk so a
+// corrupt cycle cannot loop forever.
+func SchemaAncestors(s Schema, byID map[uuid.UUID]Schema) []Schema {
+	var ancestors []Schema
+	seen := map[uuid.UUID]bool{s.ID: true}
+	for parentID := s.ParentID; parentID != nil; {
+		if seen[*parentID] {
+			break
+		}
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		seen[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentID
+	}
+	return ancestors
+}
+
+// EffectiveSchemaAttributes merges a schema's attributes over those inherited
+// from its ancestors: the nearest definition of a key wins.
+func EffectiveSchemaAttributes(s Schema, byID map[uuid.UUID]Schema) map[string]any {
+	ancestors := SchemaAncestors(s, byID)
+	if len(ancestors) == 0 {
+		return s.Attributes
+	}
+
+	merged := make(map[string]any)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		for k, v := range ancestors[i].Attributes {
+			merged[k] = v
+		}
+	}
+	for k, v := range s.Attributes {
+		merged[k] = v
+	}
+	return merged
+}
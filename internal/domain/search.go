@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchMode selects how a tenant-wide search matches content.
+type SearchMode string
+
+const (
+	// SearchModeText is English full-text search (stemmed keywords).
+	SearchModeText SearchMode = "text"
+	// SearchModeExact is a case-insensitive substring match, for identifiers
+	// such as email addresses or account numbers.
+	SearchModeExact SearchMode = "exact"
+	// SearchModeSemantic ranks by embedding similarity to the query.
+	SearchModeSemantic SearchMode = "semantic"
+)
+
+// IsValid checks if the search mode is valid.
+func (m SearchMode) IsValid() bool {
+	switch m {
+	case SearchModeText, SearchModeExact, SearchModeSemantic:
+		return true
+	default:
+		return false
+	}
+}
+
+// TenantSearchQuery is a search over every agent of a tenant. Unlike recall it
+// also covers archived and quarantined rows, since the point is to find
+// everywhere a piece of information is stored.
+type TenantSearchQuery struct {
+	Mode      SearchMode
+	Query     string
+	Embedding []float32 // set for SearchModeSemantic
+	AgentID   *uuid.UUID
+	Limit     int
+	Offset    int
+}
+
+// MemorySearchHit is a memory matched by a tenant-wide search.
+type MemorySearchHit struct {
+	ID         uuid.UUID     `json:"id"`
+	AgentID    uuid.UUID     `json:"agent_id"`
+	Type       MemoryType    `json:"type"`
+	Content    string        `json:"content"`
+	Binding    MemoryBinding `json:"binding"`
+	AnchorID   *uuid.UUID    `json:"anchor_id,omitempty"`
+	SessionID  *uuid.UUID    `json:"session_id,omitempty"`
+	Confidence float32       `json:"confidence"`
+	Archived   bool          `json:"archived"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Score      float32       `json:"score"`
+}
+
+// EpisodeSearchHit is an episode matched by a tenant-wide search. Excerpt is
+// the part of the raw content around the match.
+type EpisodeSearchHit struct {
+	ID             uuid.UUID  `json:"id"`
+	AgentID        uuid.UUID  `json:"agent_id"`
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	Excerpt        string     `json:"excerpt"`
+	OccurredAt     time.Time  `json:"occurred_at"`
+	Archived       bool       `json:"archived"`
+	Score          float32    `json:"score"`
+	RawContent     string     `json:"-"`
+}
+
+// MemorySearchStore searches memories across all agents of a tenant.
+type MemorySearchStore interface {
+	SearchTenant(ctx context.Context, tenantID uuid.UUID, q TenantSearchQuery) ([]MemorySearchHit, error)
+}
+
+// EpisodeSearchStore searches episodes across all agents of a tenant.
+type EpisodeSearchStore interface {
+	SearchTenant(ctx context.Context, tenantID uuid.UUID, q TenantSearchQuery) ([]EpisodeSearchHit, error)
+}
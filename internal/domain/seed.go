@@ -0,0 +1,51 @@
+package domain
+
+// SeedSource is the memory source recorded on beliefs loaded from a seed
+// document, so curated knowledge stays distinguishable from learned knowledge.
+const SeedSource = "seed"
+
+// SeedDocument declares the initial knowledge of an agent: beliefs stored as
+// semantic memories, schemas, and procedures. It is accepted as JSON or YAML.
+type SeedDocument struct {
+	// Name identifies the document in the provenance of everything it seeds.
+	Name       string          `json:"name,omitempty"`
+	Beliefs    []SeedBelief    `json:"beliefs,omitempty"`
+	Schemas    []SeedSchema    `json:"schemas,omitempty"`
+	Procedures []SeedProcedure `json:"procedures,omitempty"`
+}
+
+// SeedBelief is a belief to store as a memory. Key is a document-local handle
+// that schemas can cite as evidence.
+type SeedBelief struct {
+	Key        string         `json:"key,omitempty"`
+	Content    string         `json:"content"`
+	Type       MemoryType     `json:"type,omitempty"`
+	Confidence float32        `json:"confidence,omitempty"`
+	Provenance Provenance     `json:"provenance,omitempty"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// SeedSchema is a schema to create or refresh by type and name. Evidence lists
+// belief keys from the same document.
+type SeedSchema struct {
+	SchemaType         SchemaType     `json:"schema_type"`
+	Name               string         `json:"name"`
+	Description        string         `json:"description,omitempty"`
+	Attributes         map[string]any `json:"attributes,omitempty"`
+	ApplicableContexts []string       `json:"applicable_contexts,omitempty"`
+	Confidence         *float32       `json:"confidence,omitempty"`
+	ParentName         string         `json:"parent_name,omitempty"`
+	Evidence           []string       `json:"evidence,omitempty"`
+}
+
+// SeedProcedure is a procedure to create unless the agent already has one with
+// a similar trigger.
+type SeedProcedure struct {
+	TriggerPattern  string               `json:"trigger_pattern"`
+	TriggerKeywords []string             `json:"trigger_keywords,omitempty"`
+	Conditions      *ProcedureConditions `json:"conditions,omitempty"`
+	ActionTemplate  string               `json:"action_template"`
+	ActionType      ActionType           `json:"action_type"`
+	Confidence      float32              `json:"confidence,omitempty"`
+	Examples        []ExampleExchange    `json:"examples,omitempty"`
+}
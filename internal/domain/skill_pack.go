@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SkillPackFormatVersion is the skill pack format this build writes and
+// reads.
+const SkillPackFormatVersion = 1
+
+// SkillPack is a portable bundle of an agent's proven procedures. Installing
+// it into another agent marks the procedures as transferred.
+type SkillPack struct {
+	FormatVersion int       `json:"format_version"`
+	Name          string    `json:"name"`
+	SourceAgentID uuid.UUID `json:"source_agent_id"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Skills        []Skill   `json:"skills"`
+}
+
+// Skill is one procedure of a skill pack: its trigger, action and the
+// statistics that earned it a place in the pack.
+type Skill struct {
+	TriggerPattern  string               `json:"trigger_pattern"`
+	TriggerKeywords []string             `json:"trigger_keywords,omitempty"`
+	Conditions      *ProcedureConditions `json:"conditions,omitempty"`
+	ActionTemplate  string               `json:"action_template"`
+	ActionType      ActionType           `json:"action_type"`
+	Examples        []ExampleExchange    `json:"examples,omitempty"`
+	Stats           SkillStats           `json:"stats"`
+}
+
+// SkillStats is a skill's track record with the agent it was exported from.
+type SkillStats struct {
+	UseCount     int     `json:"use_count"`
+	SuccessCount int     `json:"success_count"`
+	FailureCount int     `json:"failure_count"`
+	SuccessRate  float32 `json:"success_rate"`
+	Confidence   float32 `json:"confidence"`
+}
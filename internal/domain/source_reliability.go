@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SourceReliabilityPriorWeight is how many outcomes the built-in reliability
+// of a source counts as. A source's learned reliability only drifts far from
+// its default once it has a comparable number of observed outcomes.
+const SourceReliabilityPriorWeight = 10.0
+
+// SourceReliability is an agent's track record for one belief source: how
+// many of its beliefs were confirmed (helpful feedback, independent
+// reinforcement) and how many were refuted (contradicted feedback, a hard
+// contradiction by a newer belief).
+type SourceReliability struct {
+	AgentID   uuid.UUID    `json:"agent_id"`
+	TenantID  uuid.UUID    `json:"tenant_id"`
+	Source    BeliefSource `json:"source"`
+	Correct   float64      `json:"correct"`
+	Incorrect float64      `json:"incorrect"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Reliability is the posterior mean probability that a belief from the source
+// is correct, starting from prior with SourceReliabilityPriorWeight
+// pseudo-outcomes.
+func (r SourceReliability) Reliability(prior float64) float64 {
+	return (prior*SourceReliabilityPriorWeight + r.Correct) /
+		(SourceReliabilityPriorWeight + r.Correct + r.Incorrect)
+}
+
+// SourceOf classifies where a memory came from: its Source when that names a
+// BeliefSource, extraction for beliefs consolidated from an episode, and
+// otherwise its provenance. It returns "" when neither says.
+func SourceOf(m *Memory) BeliefSource {
+	switch src := BeliefSource(m.Source); src {
+	case SourceUserStatement, SourceAgentInference, SourceToolOutput, SourceExtraction:
+		return src
+	}
+	if strings.HasPrefix(m.Source, "episode:") {
+		return SourceExtraction
+	}
+	switch m.Provenance {
+	case ProvenanceUser:
+		return SourceUserStatement
+	case ProvenanceTool:
+		return SourceToolOutput
+	case ProvenanceAgent, ProvenanceInferred:
+		return SourceAgentInference
+	}
+	return ""
+}
+
+type SourceReliabilityStore interface {
+	// Record adds outcomes to the agent's row for source, creating it if needed.
+	Record(ctx context.Context, agentID, tenantID uuid.UUID, source BeliefSource, correct, incorrect float64) error
+	ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID) ([]SourceReliability, error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSourceOf(t *testing.T) {
+	cases := []struct {
+		m    Memory
+		want BeliefSource
+	}{
+		{Memory{Source: string(SourceToolOutput), Provenance: ProvenanceUser}, SourceToolOutput},
+		{Memory{Source: "episode:123"}, SourceExtraction},
+		{Memory{Provenance: ProvenanceUser}, SourceUserStatement},
+		{Memory{Provenance: ProvenanceInferred}, SourceAgentInference},
+		{Memory{Source: "seed", Provenance: ProvenanceDerived}, ""},
+	}
+	for _, c := range cases {
+		if got := SourceOf(&c.m); got != c.want {
+			t.Errorf("SourceOf(source=%q, provenance=%q) = %q, want %q", c.m.Source, c.m.Provenance, got, c.want)
+		}
+	}
+}
+
+func TestSourceReliability_Reliability(t *testing.T) {
+	if got := (SourceReliability{}).Reliability(0.7); got != 0.7 {
+		t.Errorf("expected the prior without outcomes, got %f", got)
+	}
+	r := SourceReliability{Correct: 30, Incorrect: 0}
+	if got := r.Reliability(0.6); math.Abs(got-0.9) > 1e-9 {
+		t.Errorf("expected (6+30)/40 = 0.9, got %f", got)
+	}
+}
@@ -48,6 +48,10 @@ const (
 	RecallModeSimilarity RecallMode = "similarity"
 	RecallModeExhaustive RecallMode = "exhaustive"
 	RecallModeHybrid     RecallMode = "hybrid"
+	// RecallModeGraph re-ranks a wider pool of vector candidates, boosting
+	// those connected within a few hops to the direct hits by the strength of
+	// the connecting path.
+	RecallModeGraph RecallMode = "graph"
 )
 
 type RecallOpts struct {
@@ -66,6 +70,33 @@ type RecallOpts struct {
 	AnchorID      *uuid.UUID
 	SessionID     *uuid.UUID
 	Binding       *MemoryBinding
+	// Subject restricts recall to beliefs about this subject (matched
+	// case-insensitively).
+	Subject string
+	// ExpandSummaries keeps detail memories alongside a recalled summary that
+	// covers them; by default the summary stands in for them.
+	ExpandSummaries bool
+	// Conversation is the caller's current message window. Memories whose
+	// content already appears in it verbatim are left out of the results.
+	Conversation []Message
+	// NoInterference ranks memories without the interference penalty for
+	// near-duplicate neighbors.
+	NoInterference bool
+	// Environment is the caller's current environment; memories formed in
+	// it rank higher.
+	Environment Environment
+	// Accuracy trades recall quality for latency: in (0, 1], lower values
+	// scan the vector index less and re-rank fewer candidates. 0 keeps the
+	// server's configured search; see RecallSearchEffort.
+	Accuracy float32
+}
+
+// SimilarityFilter restricts FindSimilarFiltered. Empty Types and a zero
+// Since leave that dimension unrestricted.
+type SimilarityFilter struct {
+	Types []MemoryType
+	Since time.Time // only memories created or last verified at or after Since
+	Limit int
 }
 
 type MemoryWithScore struct {
@@ -106,8 +137,18 @@ type MemoryStore interface {
 	DeleteByRetention(ctx context.Context, agentID uuid.UUID, memType MemoryType, retentionDays int) (int64, error)
 	// Belief system methods
 	FindSimilar(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32) ([]MemoryWithScore, error)
+	// FindSimilarFiltered is the bounded variant used on the write path: the
+	// nearest filter.Limit memories among the filtered set, then thresholded.
+	FindSimilarFiltered(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, filter SimilarityFilter) ([]MemoryWithScore, error)
 	GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType MemoryType, limit int) ([]MemoryWithScore, error)
 	UpdateReinforcement(ctx context.Context, id uuid.UUID, confidence float32, reinforcementCount int) error
+	// ReinforceBy atomically raises confidence by boost (capped at 0.99) and
+	// counts one more reinforcement, relative to the stored values rather
+	// than a snapshot the caller read earlier.
+	ReinforceBy(ctx context.Context, id uuid.UUID, boost float32) error
+	// AddEvidence adds observations for and against a memory to its
+	// Beta(alpha, beta) evidence counts.
+	AddEvidence(ctx context.Context, id uuid.UUID, supporting, contradicting float64) error
 	UpdateConfidence(ctx context.Context, id uuid.UUID, confidence float32) error
 	// ApplyConfidenceDelta atomically adjusts confidence by delta (clamped to
 	// [0,1]) so concurrent decay (negative delta) and recall boosts compose
@@ -132,6 +173,9 @@ type MemoryStore interface {
 	// Tier methods
 	GetByTier(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, tier MemoryTier, limit int) ([]Memory, error)
 	GetTierCounts(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (map[MemoryTier]int, error)
+	ApplyTierTransitions(ctx context.Context, transitions []TierTransition) error
+	SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) error
+	GetTierHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memoryID *uuid.UUID, since time.Time, limit int) ([]TierTransition, error)
 	// Review flag
 	SetNeedsReview(ctx context.Context, id uuid.UUID, needsReview bool) error
 	GetNeedsReview(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, limit int) ([]Memory, error)
@@ -140,6 +184,13 @@ type MemoryStore interface {
 	PromoteSessionToAnchor(ctx context.Context, id uuid.UUID) (bool, error)
 }
 
+// UnsummarizedMemoryLoader loads specific memories of an agent by ID,
+// skipping those a summary already covers; MemoryStore implementations may
+// provide it so the cold summarizer need not scan the whole agent.
+type UnsummarizedMemoryLoader interface {
+	GetUnsummarized(ctx context.Context, agentID uuid.UUID, ids []uuid.UUID) ([]Memory, error)
+}
+
 type BeliefContradiction struct {
 	ID               uuid.UUID
 	BeliefID         uuid.UUID
@@ -183,12 +234,26 @@ type ContradictionPair struct {
 	DetectedAt       time.Time `json:"detected_at"`
 }
 
+// ContradictionTopicCount is how contradicted an agent's topic is: the live
+// beliefs under it that are contradicted, and the open pairs they are part of.
+type ContradictionTopicCount struct {
+	Topic        string `json:"topic"`
+	Contradicted int    `json:"contradicted"`
+	Pairs        int    `json:"pairs"`
+}
+
 type ContradictionStore interface {
 	Create(ctx context.Context, beliefID, contradictedByID uuid.UUID) error
 	GetByBeliefID(ctx context.Context, beliefID uuid.UUID) ([]BeliefContradiction, error)
 	GetByContradictedByID(ctx context.Context, contradictedByID uuid.UUID) ([]BeliefContradiction, error)
 	CountByAgent(ctx context.Context, agentID, tenantID uuid.UUID) (int, error)
 	ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit int) ([]ContradictionPair, error)
+	// ListOpenByAgent pages through the pairs whose beliefs are both still
+	// live, newest first.
+	ListOpenByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]ContradictionPair, error)
+	// CountByTopic aggregates the open pairs by the topic of the contradicted
+	// belief (its type when it has none, as TopicOf), most contradicted first.
+	CountByTopic(ctx context.Context, agentID, tenantID uuid.UUID) ([]ContradictionTopicCount, error)
 }
 
 type Message struct {
@@ -201,6 +266,8 @@ type ExtractedMemory struct {
 	Content      string       `json:"content"`
 	Confidence   float32      `json:"confidence"`
 	EvidenceType EvidenceType `json:"evidence_type,omitempty"`
+	Subject      string       `json:"subject,omitempty"`
+	Topic        string       `json:"topic,omitempty"`
 }
 
 // EpisodeExtraction represents structured information extracted from an episode.
@@ -217,6 +284,12 @@ type EmbeddingClient interface {
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
 
+// BatchEmbedder is implemented by embedding clients that can embed several
+// texts in one provider call. Vectors are returned in input order.
+type BatchEmbedder interface {
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
 type LLMClient interface {
 	Classify(ctx context.Context, content string) (MemoryType, error)
 	Extract(ctx context.Context, conversation []Message) ([]ExtractedMemory, error)
@@ -230,6 +303,8 @@ type LLMClient interface {
 	DetectImplicitFeedback(ctx context.Context, memories []Memory, conversation []Message) ([]ImplicitFeedback, error)
 	ExtractEntities(ctx context.Context, content string) ([]ExtractedEntity, error)
 	DetectRelationships(ctx context.Context, memory *Memory, similarMemories []MemoryWithScore) ([]DetectedRelationship, error)
+	GenerateVerificationQuestions(ctx context.Context, beliefs []UncertainBelief) ([]VerificationQuestion, error)
+	NarrateKnowledge(ctx context.Context, topic string, sources KnowledgeSources) (*KnowledgeNarrative, error)
 }
 
 type PolicyStore interface {
@@ -268,6 +343,9 @@ type EpisodeStore interface {
 
 	// Consolidation
 	GetUnconsolidated(ctx context.Context, agentID uuid.UUID, limit int) ([]Episode, error)
+	// GetReplayCandidates returns up to limit of the oldest and up to limit of
+	// the newest raw episodes, oldest first.
+	GetReplayCandidates(ctx context.Context, agentID uuid.UUID, limit int) ([]Episode, error)
 	GetByConsolidationStatus(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, status ConsolidationStatus, limit int) ([]Episode, error)
 	UpdateConsolidationStatus(ctx context.Context, id uuid.UUID, status ConsolidationStatus) error
 	LinkDerivedMemory(ctx context.Context, episodeID uuid.UUID, memoryID uuid.UUID, memoryType string) error
@@ -292,6 +370,20 @@ type EpisodeStore interface {
 	GetByAgentForDecay(ctx context.Context, agentID uuid.UUID) ([]Episode, error)
 }
 
+// EpisodeBatchCreator inserts several episodes of one tenant in a single
+// transaction; EpisodeStore implementations may provide it for bulk ingestion.
+type EpisodeBatchCreator interface {
+	CreateBatch(ctx context.Context, episodes []*Episode) error
+}
+
+// EpisodeChunkStore holds passage-level embeddings of long episodes.
+type EpisodeChunkStore interface {
+	CreateChunks(ctx context.Context, chunks []EpisodeChunk) error
+	// FindSimilarChunks returns the agent's best-matching chunks, highest
+	// score first; an episode may contribute several.
+	FindSimilarChunks(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, limit int) ([]EpisodeChunkMatch, error)
+}
+
 // ProcedureStore handles storage and retrieval of procedural memories (skills & patterns).
 type ProcedureStore interface {
 	// Core CRUD
@@ -301,6 +393,12 @@ type ProcedureStore interface {
 
 	// Similarity search
 	FindByTriggerSimilarity(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, limit int) ([]ProcedureWithScore, error)
+	// FindByTriggerKeywords finds procedures whose trigger keywords appear
+	// among terms, scored by the fraction of their keywords found.
+	FindByTriggerKeywords(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, terms []string, limit int) ([]ProcedureWithScore, error)
+	// FindConditional returns the agent's live procedures that have
+	// structured conditions.
+	FindConditional(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]Procedure, error)
 
 	// Effectiveness tracking
 	RecordUse(ctx context.Context, id uuid.UUID, success bool) error
@@ -338,26 +436,42 @@ type SchemaStore interface {
 
 	// Updates
 	Update(ctx context.Context, s *Schema) error
+
+	// Hierarchy: SetParent re-parents a schema (nil detaches it) and returns
+	// store.ErrCycle if the move would make a schema its own ancestor.
+	SetParent(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, parentID *uuid.UUID) error
+
+	// Maintenance bookkeeping: GetPassState returns store.ErrNotFound if no
+	// schema pass has run for the agent yet.
+	GetPassState(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*SchemaPassState, error)
+	RecordPass(ctx context.Context, state *SchemaPassState) error
 }
 
 // WorkingMemoryStore handles storage of working memory sessions and activations.
 type WorkingMemoryStore interface {
 	// Session management
 	CreateSession(ctx context.Context, s *WorkingMemorySession) error
+	// GetSession and DeleteSession address the agent-wide session; the
+	// Conversation variants the session isolated to one conversation.
 	GetSession(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*WorkingMemorySession, error)
+	GetConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) (*WorkingMemorySession, error)
 	GetSessionByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*WorkingMemorySession, error)
+	ListSessions(ctx context.Context, agentID, tenantID uuid.UUID) ([]WorkingMemorySession, error)
 	UpdateSession(ctx context.Context, s *WorkingMemorySession) error
 	DeleteSession(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) error
+	DeleteConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) error
 	UpdateLastActivity(ctx context.Context, sessionID uuid.UUID) error
 
 	// Memory activations
 	CreateActivation(ctx context.Context, a *WorkingMemoryActivation) error
+	CreateActivationsBatch(ctx context.Context, acts []WorkingMemoryActivation) error
 	GetActivations(ctx context.Context, sessionID uuid.UUID) ([]WorkingMemoryActivation, error)
 	ClearActivations(ctx context.Context, sessionID uuid.UUID) error
 	DeleteActivation(ctx context.Context, sessionID uuid.UUID, memoryType ActivatedMemoryType, memoryID uuid.UUID) error
 
 	// Schema activations
 	CreateSchemaActivation(ctx context.Context, a *SchemaActivation) error
+	CreateSchemaActivationsBatch(ctx context.Context, acts []SchemaActivation) error
 	GetSchemaActivations(ctx context.Context, sessionID uuid.UUID) ([]SchemaActivation, error)
 	ClearSchemaActivations(ctx context.Context, sessionID uuid.UUID) error
 
@@ -365,7 +479,64 @@ type WorkingMemoryStore interface {
 	DeleteExpiredSessions(ctx context.Context) (int64, error)
 }
 
+// WorkingMemoryFlusher is implemented by working memory stores that buffer
+// writes outside Postgres; Flush persists them and reports how many sessions
+// were written.
+type WorkingMemoryFlusher interface {
+	Flush(ctx context.Context) (int, error)
+}
+
 // MemoryAssociationStore handles cross-memory associations for spreading activation.
+type WorkingMemorySettingsStore interface {
+	Get(ctx context.Context, agentID, tenantID uuid.UUID) (*WorkingMemorySettings, error)
+	Upsert(ctx context.Context, s *WorkingMemorySettings) error
+}
+
+// EpisodePositionSettingsStore persists per-agent primacy and recency
+// weighting.
+type EpisodePositionSettingsStore interface {
+	Get(ctx context.Context, agentID, tenantID uuid.UUID) (*EpisodePositionSettings, error)
+	Upsert(ctx context.Context, s *EpisodePositionSettings) error
+}
+
+// ConversationEndsStore finds where conversations begin and end. Conversations
+// without episodes are absent from the map.
+type ConversationEndsStore interface {
+	GetConversationEnds(ctx context.Context, tenantID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]ConversationEnds, error)
+}
+
+type ConsolidationRunStore interface {
+	Create(ctx context.Context, run *ConsolidationRun) error
+	ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit int) ([]ConsolidationRun, error)
+}
+
+type ConsolidationFailureStore interface {
+	// RecordFailure counts one failure for f.EpisodeID, setting dead_lettered_at
+	// once the count reaches deadLetterAfter, and fills in f from the stored row.
+	RecordFailure(ctx context.Context, f *ConsolidationFailure, deadLetterAfter int) error
+	ListDeadLettered(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]ConsolidationFailure, int, error)
+	// Requeue clears a dead-lettered episode's failures and returns it to the
+	// processed status. Returns ErrNotFound if it is not dead-lettered.
+	Requeue(ctx context.Context, episodeID, tenantID uuid.UUID) error
+}
+
+type ConversationActivationStore interface {
+	Record(ctx context.Context, acts []ConversationActivation) error
+	// ClaimForEpisode returns the conversation's activations that have not yet
+	// been attributed an outcome from episodeID, marking them as attributed.
+	ClaimForEpisode(ctx context.Context, episodeID, conversationID, tenantID uuid.UUID) ([]ConversationActivation, error)
+	// ListByConversation returns every activation recorded in the
+	// conversation, strongest first.
+	ListByConversation(ctx context.Context, conversationID, tenantID uuid.UUID) ([]ConversationActivation, error)
+}
+
+type WorkingMemorySnapshotStore interface {
+	Create(ctx context.Context, snap *WorkingMemorySnapshot) error
+	GetByID(ctx context.Context, id, tenantID uuid.UUID) (*WorkingMemorySnapshot, error)
+	ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID) ([]WorkingMemorySnapshot, error)
+	Delete(ctx context.Context, id, tenantID uuid.UUID) error
+}
+
 type MemoryAssociationStore interface {
 	Create(ctx context.Context, a *MemoryAssociation) error
 	GetBySource(ctx context.Context, tenantID uuid.UUID, sourceType ActivatedMemoryType, sourceID uuid.UUID) ([]MemoryAssociation, error)
@@ -389,6 +560,76 @@ type EpisodeMemoryUsageStore interface {
 	GetByMemoryID(ctx context.Context, memoryID uuid.UUID) ([]EpisodeMemoryUsage, error)
 }
 
+// IdempotencyStore deduplicates retried create requests by Idempotency-Key.
+type IdempotencyStore interface {
+	// Reserve claims key for a new request. When the key is already held (and
+	// not expired) it returns the existing record and reserved=false.
+	Reserve(ctx context.Context, tenantID uuid.UUID, key, requestHash string, ttl time.Duration) (existing *IdempotencyRecord, reserved bool, err error)
+	Complete(ctx context.Context, tenantID uuid.UUID, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, tenantID uuid.UUID, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// RetentionStore persists per-tenant retention rules and applies them.
+type RetentionStore interface {
+	Upsert(ctx context.Context, r *RetentionRule) error
+	List(ctx context.Context, tenantID uuid.UUID) ([]RetentionRule, error)
+	ListAll(ctx context.Context) ([]RetentionRule, error)
+	Delete(ctx context.Context, id, tenantID uuid.UUID) error
+	Preview(ctx context.Context, r *RetentionRule, sample int) (*RetentionPreview, error)
+	Apply(ctx context.Context, r *RetentionRule) (int64, error)
+}
+
+// EpisodePartitionStore manages the monthly partitions of the episodes table.
+type EpisodePartitionStore interface {
+	EnsurePartitions(ctx context.Context, from, through time.Time) error
+	DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error)
+}
+
+// OutboxStore drains the transactional event outbox. Events are written by
+// MemoryStore and MutationLogStore inside the change's own statement.
+type OutboxStore interface {
+	// Claim leases up to limit due, unpublished events in id order so that
+	// concurrent publishers don't deliver the same event; a lease that isn't
+	// resolved by MarkPublished or MarkFailed expires and the event is due again.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxEvent, error)
+	MarkPublished(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, id int64, errMsg string, nextAttempt time.Time) error
+	// DeleteBefore removes published events created before publishedCutoff and
+	// any event, delivered or not, created before abandonCutoff.
+	DeleteBefore(ctx context.Context, publishedCutoff, abandonCutoff time.Time) (int64, error)
+	// Enqueue writes an event that isn't tied to a memory write, such as a
+	// pipeline anomaly.
+	Enqueue(ctx context.Context, e *OutboxEvent) error
+}
+
+// EventPublisher delivers outbox events to an external sink (webhook, broker).
+type EventPublisher interface {
+	Publish(ctx context.Context, e OutboxEvent) error
+}
+
+// VectorIndexStore manages the deployment's pgvector indexes. Names are
+// validated against vector_index_settings, so only managed indexes are touched.
+type VectorIndexStore interface {
+	List(ctx context.Context) ([]VectorIndex, error)
+	Get(ctx context.Context, name string) (*VectorIndex, error)
+	SetBuildStatus(ctx context.Context, name string, status VectorIndexBuildStatus, errMsg string) error
+	// Rebuild builds a replacement index with params concurrently and swaps it
+	// in, recording the params and build time once the swap commits.
+	Rebuild(ctx context.Context, name string, params VectorIndexParams) error
+	SampleVectors(ctx context.Context, name string, n int) ([][]float32, error)
+	// Search runs one top-k cosine search, through the index or (exact) with
+	// index scans disabled. efSearch and probes override the session defaults
+	// when positive.
+	Search(ctx context.Context, name string, query []float32, k int, exact bool, efSearch, probes int) (*VectorSearchSample, error)
+	UsesIndex(ctx context.Context, name string, query []float32, k int) (bool, error)
+}
+
+// RecallLogStore persists sampled recall rankings for offline analysis.
+type RecallLogStore interface {
+	Create(ctx context.Context, l *RecallLog) error
+}
+
 // LearningStatsStore handles aggregated learning statistics.
 type LearningStatsStore interface {
 	Upsert(ctx context.Context, s *LearningStats) error
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+)
+
+// SubjectMemory is a memory in a subject export. Archived rows are included,
+// since a right-of-access answer covers everything still stored.
+type SubjectMemory struct {
+	Memory
+	Archived bool `json:"archived"`
+}
+
+// SubjectExport is everything stored about one end-user (an anchor): the
+// traces bound to or mentioning them, their sessions, the episodes those
+// traces came from, and the other entities the traces mention.
+type SubjectExport struct {
+	Anchor     *Entity         `json:"anchor"`
+	Sessions   []Session       `json:"sessions"`
+	Memories   []SubjectMemory `json:"memories"`
+	Episodes   []Episode       `json:"episodes"`
+	Entities   []Entity        `json:"entities"`
+	ExportedAt time.Time       `json:"exported_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// The tenant ID rides on the context next to the trace IDs so the store layer
+// can route a query to the database that holds the tenant's data. It is set
+// by the auth middleware for API requests and re-attached by background work
+// that acts for a known tenant.
+
+type tenantIDKey struct{}
+
+// WithTenantID returns a context carrying the tenant ID.
+func WithTenantID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, id)
+}
+
+// TenantIDFrom returns the context's tenant ID, if any.
+func TenantIDFrom(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(tenantIDKey{}).(uuid.UUID)
+	return id, ok
+}
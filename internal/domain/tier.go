@@ -28,18 +28,28 @@ func ComputeTier(confidence float64) MemoryTier {
 	}
 }
 
-// AnnotateTiers fills each memory's derived Tier from its Confidence, making the
-// API the single source of truth for tiering. Clients should display this rather
-// than re-deriving from confidence: the stored confidence is float4, and
+// AnnotateTiers fills each memory's Tier, keeping a materialized tier loaded
+// from the store and otherwise deriving it from Confidence, making the API the
+// single source of truth for tiering. Clients should display this rather than
+// re-deriving from confidence: the stored confidence is float4, and
 // re-bucketing the JSON-rounded value in another language disagrees with the
 // server at the band boundaries (e.g. a 0.85 stored as ~0.8500000238 is "hot"
 // here but "warm" if JS compares the rounded 0.85 against the same threshold).
 func AnnotateTiers(memories []Memory) {
 	for i := range memories {
-		memories[i].Tier = ComputeTier(float64(memories[i].Confidence))
+		memories[i].Tier = memories[i].CurrentTier()
 	}
 }
 
+// CurrentTier returns the memory's materialized tier, falling back to the
+// confidence band for rows read without one.
+func (m Memory) CurrentTier() MemoryTier {
+	if m.Tier != "" {
+		return m.Tier
+	}
+	return ComputeTier(float64(m.Confidence))
+}
+
 type TierBehavior struct {
 	Tier               MemoryTier
 	AutoInject         bool
@@ -126,8 +136,87 @@ func DefaultIncludeTiers() []MemoryTier {
 // TierTransition records when a memory moves between tiers
 type TierTransition struct {
 	MemoryID   uuid.UUID  `json:"memory_id"`
+	AgentID    uuid.UUID  `json:"-"`
+	TenantID   uuid.UUID  `json:"-"`
 	FromTier   MemoryTier `json:"from_tier"`
 	ToTier     MemoryTier `json:"to_tier"`
 	Reason     string     `json:"reason"`
+	Confidence float32    `json:"confidence"`
 	OccurredAt time.Time  `json:"occurred_at"`
 }
+
+// Reasons recorded in tier history by the transition worker.
+const (
+	TierReasonConfidence     = "confidence"
+	TierReasonFrequentAccess = "frequent_access"
+	TierReasonStale          = "stale"
+	TierReasonPinned         = "pinned"
+	TierReasonUnpinned       = "unpinned"
+)
+
+// TierPolicy holds the transition rules applied on top of the confidence
+// bands when materializing a memory's tier.
+type TierPolicy struct {
+	PromoteAccessCount int           // Accesses that lift a recently used memory one tier
+	PromoteWindow      time.Duration // How recent the last access must be to promote
+	DemoteAfter        time.Duration // Time without access that drops a memory one tier
+}
+
+func DefaultTierPolicy() TierPolicy {
+	return TierPolicy{
+		PromoteAccessCount: 5,
+		PromoteWindow:      7 * 24 * time.Hour,
+		DemoteAfter:        30 * 24 * time.Hour,
+	}
+}
+
+// Evaluate returns the tier a memory should be in and why. Pinned memories
+// stay hot. Otherwise the confidence band is the baseline, raised one tier for
+// frequently and recently accessed memories and lowered one tier for memories
+// untouched for longer than DemoteAfter.
+func (p TierPolicy) Evaluate(m Memory, now time.Time) (MemoryTier, string) {
+	if m.Pinned {
+		return TierHot, TierReasonPinned
+	}
+
+	base := ComputeTier(float64(m.Confidence))
+
+	lastUsed := m.CreatedAt
+	if m.LastAccessedAt != nil {
+		lastUsed = *m.LastAccessedAt
+	}
+	idle := now.Sub(lastUsed)
+
+	switch {
+	case p.PromoteAccessCount > 0 && m.AccessCount >= p.PromoteAccessCount &&
+		m.LastAccessedAt != nil && idle <= p.PromoteWindow:
+		if up := shiftTier(base, -1); up != base {
+			return up, TierReasonFrequentAccess
+		}
+	case p.DemoteAfter > 0 && idle > p.DemoteAfter:
+		if down := shiftTier(base, 1); down != base {
+			return down, TierReasonStale
+		}
+	}
+	return base, TierReasonConfidence
+}
+
+// shiftTier moves a tier by delta steps along hot → archive, clamping at the
+// ends.
+func shiftTier(tier MemoryTier, delta int) MemoryTier {
+	tiers := AllTiers()
+	for i, t := range tiers {
+		if t != tier {
+			continue
+		}
+		i += delta
+		if i < 0 {
+			i = 0
+		}
+		if i >= len(tiers) {
+			i = len(tiers) - 1
+		}
+		return tiers[i]
+	}
+	return tier
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestComputeTier(t *testing.T) {
 	tests := []struct {
@@ -190,3 +193,33 @@ func TestAnnotateTiers(t *testing.T) {
 		}
 	}
 }
+
+func TestTierPolicyEvaluate(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	stale := now.Add(-60 * 24 * time.Hour)
+	p := DefaultTierPolicy()
+
+	tests := []struct {
+		name       string
+		m          Memory
+		wantTier   MemoryTier
+		wantReason string
+	}{
+		{"confidence band", Memory{Confidence: 0.8, LastAccessedAt: &recent, AccessCount: 1}, TierWarm, TierReasonConfidence},
+		{"pinned", Memory{Confidence: 0.3, Pinned: true, LastAccessedAt: &stale}, TierHot, TierReasonPinned},
+		{"frequent access", Memory{Confidence: 0.6, LastAccessedAt: &recent, AccessCount: p.PromoteAccessCount}, TierWarm, TierReasonFrequentAccess},
+		{"frequent access already hot", Memory{Confidence: 0.9, LastAccessedAt: &recent, AccessCount: 50}, TierHot, TierReasonConfidence},
+		{"stale", Memory{Confidence: 0.9, LastAccessedAt: &stale, AccessCount: 50}, TierWarm, TierReasonStale},
+		{"never accessed and old", Memory{Confidence: 0.6, CreatedAt: stale}, TierArchive, TierReasonStale},
+		{"stale already archived", Memory{Confidence: 0.2, LastAccessedAt: &stale}, TierArchive, TierReasonConfidence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, reason := p.Evaluate(tt.m, now)
+			if tier != tt.wantTier || reason != tt.wantReason {
+				t.Errorf("Evaluate() = (%q, %q), want (%q, %q)", tier, reason, tt.wantTier, tt.wantReason)
+			}
+		})
+	}
+}
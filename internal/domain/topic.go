@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MaxTopicLength bounds a normalized topic, in bytes.
+const MaxTopicLength = 64
+
+// NormalizeTopic folds the ways a model or caller writes a topic onto one
+// key: lower case, punctuation dropped, words separated by single spaces, no
+// leading article and the last word singular, so "The Work Schedules" and
+// "work-schedule" are both "work schedule".
+func NormalizeTopic(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 {
+		switch words[0] {
+		case "the", "a", "an":
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] = singular(words[len(words)-1])
+	topic := strings.Join(words, " ")
+	if len(topic) > MaxTopicLength {
+		topic = strings.TrimSpace(topic[:MaxTopicLength])
+	}
+	return topic
+}
+
+// singular strips the common English plural endings. It is deliberately
+// conservative: words ending in ss, us or is keep their s.
+func singular(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 4 && (strings.HasSuffix(w, "ches") || strings.HasSuffix(w, "shes") || strings.HasSuffix(w, "xes")):
+		return w[:len(w)-2]
+	case len(w) > 3 && strings.HasSuffix(w, "s") &&
+		!strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+// NormalizeTopics normalizes each topic and drops empty and repeated ones,
+// keeping the first occurrence's position.
+func NormalizeTopics(topics []string) []string {
+	if len(topics) == 0 {
+		return topics
+	}
+	out := make([]string, 0, len(topics))
+	seen := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		if t = NormalizeTopic(t); t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// TopicOf is the topic a memory is reported under: its own, or its type for a
+// memory written before topics were recorded or by a caller that gave none.
+func TopicOf(m *Memory) string {
+	if m.Topic != "" {
+		return m.Topic
+	}
+	return string(m.Type)
+}
+
+// TopicTrend says whether a topic gained more new memories in the latest
+// window than in the one before it.
+type TopicTrend string
+
+const (
+	TopicTrendRising  TopicTrend = "rising"
+	TopicTrendFalling TopicTrend = "falling"
+	TopicTrendStable  TopicTrend = "stable"
+)
+
+// TrendOf compares the counts of the latest window and the one before it.
+func TrendOf(recent, previous int) TopicTrend {
+	switch {
+	case recent > previous:
+		return TopicTrendRising
+	case recent < previous:
+		return TopicTrendFalling
+	}
+	return TopicTrendStable
+}
+
+// TopicStats aggregates an agent's live memories on one topic. Recent and
+// Previous count the memories created in the latest trend window and in the
+// window before it.
+type TopicStats struct {
+	Topic             string     `json:"topic"`
+	Count             int        `json:"count"`
+	AverageConfidence float32    `json:"average_confidence"`
+	AtRisk            int        `json:"at_risk"`
+	Uncertain         int        `json:"uncertain"`
+	Recent            int        `json:"recent"`
+	Previous          int        `json:"previous"`
+	Trend             TopicTrend `json:"trend"`
+	// Contradicted counts the topic's beliefs in an open contradiction.
+	Contradicted int `json:"contradicted,omitempty"`
+}
+
+// RankTopics sets each topic's trend, orders the topics largest first and
+// keeps the first max (all when max is 0). It returns the sorted names of the
+// uncertain topics among all of them, kept or not.
+func RankTopics(topics []TopicStats, max int) ([]TopicStats, []string) {
+	uncertain := []string{}
+	for i := range topics {
+		topics[i].Trend = TrendOf(topics[i].Recent, topics[i].Previous)
+		if topics[i].Uncertain > 0 {
+			uncertain = append(uncertain, topics[i].Topic)
+		}
+	}
+	sort.Strings(uncertain)
+	sort.Slice(topics, func(i, j int) bool {
+		if topics[i].Count != topics[j].Count {
+			return topics[i].Count > topics[j].Count
+		}
+		return topics[i].Topic < topics[j].Topic
+	})
+	if max > 0 && len(topics) > max {
+		topics = topics[:max]
+	}
+	return topics, uncertain
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTopic(t *testing.T) {
+	cases := map[string]string{
+		"The Work Schedules": "work schedule",
+		"work-schedule":      "work schedule",
+		"  Diet  ":           "diet",
+		"hobbies":            "hobby",
+		"lunches":            "lunch",
+		"status":             "status",
+		"the":                "the",
+		"!!":                 "",
+	}
+	for in, want := range cases {
+		if got := NormalizeTopic(in); got != want {
+			t.Errorf("NormalizeTopic(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeTopics(t *testing.T) {
+	got := NormalizeTopics([]string{"Travel Plans", "", "travel plan", "Diet"})
+	if want := []string{"travel plan", "diet"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRankTopics(t *testing.T) {
+	topics, uncertain := RankTopics([]TopicStats{
+		{Topic: "diet", Count: 2, Uncertain: 1, Recent: 2},
+		{Topic: "work", Count: 5, Previous: 1},
+		{Topic: "travel", Count: 2, Uncertain: 2, Recent: 1, Previous: 1},
+	}, 2)
+
+	if len(topics) != 2 || topics[0].Topic != "work" || topics[1].Topic != "diet" {
+		t.Fatalf("expected work then diet, got %+v", topics)
+	}
+	if topics[0].Trend != TopicTrendFalling || topics[1].Trend != TopicTrendRising {
+		t.Fatalf("unexpected trends: %+v", topics)
+	}
+	if want := []string{"diet", "travel"}; !reflect.DeepEqual(uncertain, want) {
+		t.Fatalf("expected uncertain topics %v, got %v", want, uncertain)
+	}
+}
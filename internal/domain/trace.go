@@ -0,0 +1,51 @@
+package domain
+
+import "context"
+
+// Request and correlation IDs ride on the context so the logs, jobs and model
+// calls made for one API request can be followed together. The request ID
+// names a single HTTP request. The correlation ID is shared by everything
+// done on a request's behalf, including async jobs it queues, and defaults
+// to the request ID; callers may pass their own to tie several requests
+// together. Background runs get a correlation ID but no request ID.
+
+type requestIDKey struct{}
+
+type correlationIDKey struct{}
+
+// WithRequestID returns a context carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the context's request ID, or "".
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithCorrelationID returns a context carrying the correlation ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFrom returns the context's correlation ID, or "".
+func CorrelationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// CopyTraceIDs returns dst carrying src's request, correlation and tenant
+// IDs, for work that outlives the request that started it.
+func CopyTraceIDs(dst, src context.Context) context.Context {
+	if id, ok := TenantIDFrom(src); ok {
+		dst = WithTenantID(dst, id)
+	}
+	if id := RequestIDFrom(src); id != "" {
+		dst = WithRequestID(dst, id)
+	}
+	if id := CorrelationIDFrom(src); id != "" {
+		dst = WithCorrelationID(dst, id)
+	}
+	return dst
+}
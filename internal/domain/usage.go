@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LLMUsage totals the model calls made on behalf of one operation.
+type LLMUsage struct {
+	LLMCalls         int     `json:"llm_calls"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	EmbeddingCalls   int     `json:"embedding_calls"`
+	EmbeddingTokens  int64   `json:"embedding_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// Add folds o into u.
+func (u *LLMUsage) Add(o LLMUsage) {
+	u.LLMCalls += o.LLMCalls
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.EmbeddingCalls += o.EmbeddingCalls
+	u.EmbeddingTokens += o.EmbeddingTokens
+	u.EstimatedCostUSD += o.EstimatedCostUSD
+}
+
+// ModelPrice is a model's list price in USD per million tokens.
+type ModelPrice struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// ModelPrices holds list prices for the providers' default models, keyed by
+// model name prefix so dated snapshots match. Costs are estimates; models not
+// listed still have their tokens counted but cost nothing.
+var ModelPrices = map[string]ModelPrice{
+	"gpt-4o-mini":            {InputPerMTok: 0.15, OutputPerMTok: 0.60},
+	"claude-haiku-4-5":       {InputPerMTok: 1.00, OutputPerMTok: 5.00},
+	"gemini-2.0-flash":       {InputPerMTok: 0.10, OutputPerMTok: 0.40},
+	"gpt-oss-120b":           {InputPerMTok: 0.25, OutputPerMTok: 0.69},
+	"text-embedding-3-small": {InputPerMTok: 0.02},
+	"text-embedding-3-large": {InputPerMTok: 0.13},
+}
+
+// EstimateCost prices a call using the longest matching ModelPrices prefix.
+func EstimateCost(model string, inputTokens, outputTokens int64) float64 {
+	var price ModelPrice
+	best := -1
+	for prefix, p := range ModelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > best {
+			price, best = p, len(prefix)
+		}
+	}
+	return (float64(inputTokens)*price.InputPerMTok + float64(outputTokens)*price.OutputPerMTok) / 1e6
+}
+
+// UsageMeter accumulates LLM and embedding usage across the calls made with a
+// context carrying it. Safe for concurrent use.
+type UsageMeter struct {
+	mu    sync.Mutex
+	usage LLMUsage
+}
+
+// NewUsageMeter returns an empty meter.
+func NewUsageMeter() *UsageMeter {
+	return &UsageMeter{}
+}
+
+// Usage returns the totals so far.
+func (m *UsageMeter) Usage() LLMUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+func (m *UsageMeter) add(u LLMUsage) {
+	m.mu.Lock()
+	m.usage.Add(u)
+	m.mu.Unlock()
+}
+
+type usageMeterKey struct{}
+
+// WithUsageMeter returns a context whose LLM and embedding calls are
+// recorded on m.
+func WithUsageMeter(ctx context.Context, m *UsageMeter) context.Context {
+	return context.WithValue(ctx, usageMeterKey{}, m)
+}
+
+func usageMeterFrom(ctx context.Context) *UsageMeter {
+	m, _ := ctx.Value(usageMeterKey{}).(*UsageMeter)
+	return m
+}
+
+// RecordLLMUsage is called by LLM clients after each completion. It is a
+// no-op unless ctx carries a UsageMeter.
+func RecordLLMUsage(ctx context.Context, model string, inputTokens, outputTokens int64) {
+	if m := usageMeterFrom(ctx); m != nil {
+		m.add(LLMUsage{
+			LLMCalls:         1,
+			InputTokens:      inputTokens,
+			OutputTokens:     outputTokens,
+			EstimatedCostUSD: EstimateCost(model, inputTokens, outputTokens),
+		})
+	}
+}
+
+// RecordEmbeddingUsage is called by embedding clients after each request. It
+// is a no-op unless ctx carries a UsageMeter.
+func RecordEmbeddingUsage(ctx context.Context, model string, tokens int64) {
+	if m := usageMeterFrom(ctx); m != nil {
+		m.add(LLMUsage{
+			EmbeddingCalls:   1,
+			EmbeddingTokens:  tokens,
+			EstimatedCostUSD: EstimateCost(model, tokens, 0),
+		})
+	}
+}
+
+// ConsolidationRun is one recorded consolidation pass for an agent. Result is
+// the run's ConsolidationResult as returned by the API; Usage is broken out so
+// maintenance cost can be aggregated without decoding it.
+type ConsolidationRun struct {
+	ID         uuid.UUID       `json:"id"`
+	TenantID   uuid.UUID       `json:"tenant_id"`
+	AgentID    uuid.UUID       `json:"agent_id"`
+	Scope      string          `json:"scope"`
+	Result     json.RawMessage `json:"result"`
+	Usage      LLMUsage        `json:"usage"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestEstimateCost_LongestPrefixWins(t *testing.T) {
+	// Dated snapshots match their base model
+	got := EstimateCost("claude-haiku-4-5-20251001", 1_000_000, 1_000_000)
+	if math.Abs(got-6.0) > 1e-9 {
+		t.Errorf("EstimateCost = %v, want 6.0", got)
+	}
+	if got := EstimateCost("unknown-model", 1000, 1000); got != 0 {
+		t.Errorf("unknown model cost = %v, want 0", got)
+	}
+}
+
+func TestUsageMeter_RecordsOnlyWithMeter(t *testing.T) {
+	// No meter on the context: recording is a no-op
+	RecordLLMUsage(context.Background(), "gpt-4o-mini", 10, 10)
+
+	m := NewUsageMeter()
+	ctx := WithUsageMeter(context.Background(), m)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RecordLLMUsage(ctx, "gpt-4o-mini", 100, 50)
+			RecordEmbeddingUsage(ctx, "text-embedding-3-small", 20)
+		}()
+	}
+	wg.Wait()
+
+	u := m.Usage()
+	if u.LLMCalls != 10 || u.InputTokens != 1000 || u.OutputTokens != 500 {
+		t.Errorf("unexpected LLM usage %+v", u)
+	}
+	if u.EmbeddingCalls != 10 || u.EmbeddingTokens != 200 {
+		t.Errorf("unexpected embedding usage %+v", u)
+	}
+	want := EstimateCost("gpt-4o-mini", 1000, 500) + EstimateCost("text-embedding-3-small", 200, 0)
+	if math.Abs(u.EstimatedCostUSD-want) > 1e-12 {
+		t.Errorf("EstimatedCostUSD = %v, want %v", u.EstimatedCostUSD, want)
+	}
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VectorIndexMethod string
+
+const (
+	VectorIndexHNSW    VectorIndexMethod = "hnsw"
+	VectorIndexIVFFlat VectorIndexMethod = "ivfflat"
+)
+
+type VectorIndexBuildStatus string
+
+const (
+	VectorIndexReady    VectorIndexBuildStatus = "ready"
+	VectorIndexBuilding VectorIndexBuildStatus = "building"
+	VectorIndexFailed   VectorIndexBuildStatus = "failed"
+)
+
+// VectorIndexParams are the build-time parameters of a pgvector index. M and
+// EfConstruction apply to HNSW, Lists to IVFFlat.
+type VectorIndexParams struct {
+	Method         VectorIndexMethod `json:"method"`
+	M              int               `json:"m,omitempty"`
+	EfConstruction int               `json:"ef_construction,omitempty"`
+	Lists          int               `json:"lists,omitempty"`
+}
+
+// DefaultVectorIndexParams returns pgvector's HNSW defaults.
+func DefaultVectorIndexParams() VectorIndexParams {
+	return VectorIndexParams{Method: VectorIndexHNSW, M: 16, EfConstruction: 64}
+}
+
+// Normalize fills unset parameters for the method with pgvector's defaults
+// and clears the ones that don't apply to it.
+func (p VectorIndexParams) Normalize() VectorIndexParams {
+	switch p.Method {
+	case VectorIndexIVFFlat:
+		if p.Lists == 0 {
+			p.Lists = 100
+		}
+		p.M, p.EfConstruction = 0, 0
+	default:
+		if p.Method == "" {
+			p.Method = VectorIndexHNSW
+		}
+		if p.M == 0 {
+			p.M = 16
+		}
+		if p.EfConstruction == 0 {
+			p.EfConstruction = 64
+		}
+		p.Lists = 0
+	}
+	return p
+}
+
+// Validate checks the parameters against pgvector's accepted ranges.
+func (p VectorIndexParams) Validate() error {
+	switch p.Method {
+	case VectorIndexHNSW:
+		if p.M < 2 || p.M > 100 {
+			return errors.New("m must be between 2 and 100")
+		}
+		if p.EfConstruction < 4 || p.EfConstruction > 1000 {
+			return errors.New("ef_construction must be between 4 and 1000")
+		}
+		if p.EfConstruction < 2*p.M {
+			return errors.New("ef_construction must be at least 2 * m")
+		}
+	case VectorIndexIVFFlat:
+		if p.Lists < 1 || p.Lists > 32768 {
+			return errors.New("lists must be between 1 and 32768")
+		}
+	default:
+		return errors.New("method must be hnsw or ivfflat")
+	}
+	return nil
+}
+
+// VectorIndex describes one managed pgvector index: its stored build
+// parameters and rebuild status alongside what Postgres reports for it.
+type VectorIndex struct {
+	Name            string                 `json:"name"`
+	Table           string                 `json:"table"`
+	Column          string                 `json:"column"`
+	Params          VectorIndexParams      `json:"params"`
+	Exists          bool                   `json:"exists"`
+	Valid           bool                   `json:"valid"`
+	Definition      string                 `json:"definition,omitempty"`
+	SizeBytes       int64                  `json:"size_bytes"`
+	EstimatedRows   int64                  `json:"estimated_rows"`
+	BuildStatus     VectorIndexBuildStatus `json:"build_status"`
+	LastError       string                 `json:"last_error,omitempty"`
+	LastBuiltAt     *time.Time             `json:"last_built_at,omitempty"`
+	BuildDurationMs *int64                 `json:"build_duration_ms,omitempty"`
+}
+
+// VectorIndexHealth compares approximate (index) and exact (sequential)
+// nearest-neighbour searches over sampled stored vectors. Recall is the mean
+// fraction of the exact top K that the index also returned.
+type VectorIndexHealth struct {
+	Index        string  `json:"index"`
+	Samples      int     `json:"samples"`
+	K            int     `json:"k"`
+	EfSearch     int     `json:"ef_search,omitempty"`
+	Probes       int     `json:"probes,omitempty"`
+	UsesIndex    bool    `json:"uses_index"`
+	Recall       float64 `json:"recall"`
+	ApproxP50Ms  float64 `json:"approx_p50_ms"`
+	ApproxP95Ms  float64 `json:"approx_p95_ms"`
+	ExactP50Ms   float64 `json:"exact_p50_ms"`
+	ExactP95Ms   float64 `json:"exact_p95_ms"`
+	SpeedupRatio float64 `json:"speedup_ratio"`
+}
+
+// VectorSearchSample is one timed nearest-neighbour search used by the
+// health report.
+type VectorSearchSample struct {
+	IDs      []uuid.UUID
+	Duration time.Duration
+}
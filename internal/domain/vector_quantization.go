@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// VectorQuantization names the compact copy of each memory embedding that
+// similarity search shortlists on before reranking at full precision.
+type VectorQuantization string
+
+const (
+	// QuantizationNone searches the full-precision embeddings only.
+	QuantizationNone VectorQuantization = ""
+	// QuantizationHalfvec keeps a 16-bit float copy (pgvector halfvec): half
+	// the index size, with near-identical ranking.
+	QuantizationHalfvec VectorQuantization = "halfvec"
+	// QuantizationBinary keeps one bit per dimension (pgvector
+	// binary_quantize): a 32x smaller index, needing a wider shortlist.
+	// pgvector has no int8 vector type, so this is the coarse option.
+	QuantizationBinary VectorQuantization = "binary"
+)
+
+// ParseVectorQuantization reads a quantization mode; "" and "none" disable it.
+func ParseVectorQuantization(s string) (VectorQuantization, error) {
+	switch q := VectorQuantization(strings.ToLower(strings.TrimSpace(s))); q {
+	case "none", QuantizationNone:
+		return QuantizationNone, nil
+	case QuantizationHalfvec, QuantizationBinary:
+		return q, nil
+	default:
+		return QuantizationNone, fmt.Errorf("unknown vector quantization %q (want none, halfvec or binary)", s)
+	}
+}
+
+// VectorQuantizer fills the quantized copy of an agent's embeddings where it
+// is missing, returning how many were written.
+type VectorQuantizer interface {
+	QuantizeVectors(ctx context.Context, agentID, tenantID uuid.UUID) (int, error)
+}
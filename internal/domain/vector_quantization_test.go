@@ -0,0 +1,27 @@
+package domain
+
+import "testing"
+
+func TestParseVectorQuantization(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    VectorQuantization
+		wantErr bool
+	}{
+		{"", QuantizationNone, false},
+		{"none", QuantizationNone, false},
+		{" HalfVec ", QuantizationHalfvec, false},
+		{"binary", QuantizationBinary, false},
+		{"int8", QuantizationNone, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVectorQuantization(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVectorQuantization(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVectorQuantization(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// VectorPoint is a memory's embedding as held by an external VectorStore,
+// with the fields searches are scoped by.
+type VectorPoint struct {
+	ID        uuid.UUID
+	TenantID  uuid.UUID
+	AgentID   uuid.UUID
+	Type      MemoryType
+	Embedding []float32
+}
+
+// VectorQuery asks a VectorStore for the Limit points nearest Embedding.
+// AgentID uuid.Nil searches every agent of the tenant; empty Types searches
+// every type. Matches scoring below MinScore (cosine similarity) are dropped.
+type VectorQuery struct {
+	TenantID  uuid.UUID
+	AgentID   uuid.UUID
+	Embedding []float32
+	Types     []MemoryType
+	MinScore  float32
+	Limit     int
+}
+
+// VectorMatch is one search hit; Score is cosine similarity.
+type VectorMatch struct {
+	ID    uuid.UUID
+	Score float32
+}
+
+// VectorStore is an external approximate-nearest-neighbour index (Qdrant,
+// Weaviate) that serves memory similarity search in place of pgvector.
+// Postgres stays the system of record: the store only proposes candidate IDs,
+// which are loaded, filtered and scored from the memories table, so a stale or
+// missing point costs recall quality but never returns a deleted, archived or
+// out-of-scope memory.
+type VectorStore interface {
+	Upsert(ctx context.Context, points []VectorPoint) error
+	Delete(ctx context.Context, ids []uuid.UUID) error
+	Search(ctx context.Context, q VectorQuery) ([]VectorMatch, error)
+}
+
+// VectorSyncer copies an agent's live embeddings from Postgres into the
+// external VectorStore, returning how many were sent.
+type VectorSyncer interface {
+	SyncVectors(ctx context.Context, agentID, tenantID uuid.UUID) (int, error)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "github.com/google/uuid"
+
+// UncertaintyReason is why a belief needs verifying.
+type UncertaintyReason string
+
+const (
+	UncertaintyContradicted  UncertaintyReason = "contradicted"
+	UncertaintyLowConfidence UncertaintyReason = "low_confidence"
+	UncertaintyStale         UncertaintyReason = "stale"
+)
+
+// UncertainBelief is a belief the agent should confirm with the user, why,
+// and the content of the beliefs that contradict it.
+type UncertainBelief struct {
+	Memory         Memory
+	Reasons        []UncertaintyReason
+	Contradictions []string
+}
+
+// VerificationQuestion is a question the agent can ask in conversation to
+// confirm or correct one belief. Priority orders the questions, most urgent
+// first.
+type VerificationQuestion struct {
+	MemoryID uuid.UUID           `json:"memory_id"`
+	Question string              `json:"question"`
+	Reasons  []UncertaintyReason `json:"reasons,omitempty"`
+	Priority float32             `json:"priority"`
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,12 +12,13 @@ import (
 type ActivationSource string
 
 const (
-	ActivationSourceDirect   ActivationSource = "direct"   // Direct semantic match
-	ActivationSourceSpread   ActivationSource = "spread"   // Spreading activation from associated memories
-	ActivationSourceGoal     ActivationSource = "goal"     // Goal-directed activation
-	ActivationSourceTemporal ActivationSource = "temporal" // Recent temporal activation
-	ActivationSourceRecency  ActivationSource = "recency"  // Recently accessed memory
-	ActivationSourceSchema   ActivationSource = "schema"   // Activated by matching schema
+	ActivationSourceDirect    ActivationSource = "direct"    // Direct semantic match
+	ActivationSourceSpread    ActivationSource = "spread"    // Spreading activation from associated memories
+	ActivationSourceGoal      ActivationSource = "goal"      // Goal-directed activation
+	ActivationSourceTemporal  ActivationSource = "temporal"  // Recent temporal activation
+	ActivationSourceRecency   ActivationSource = "recency"   // Recently accessed memory
+	ActivationSourceSchema    ActivationSource = "schema"    // Activated by matching schema
+	ActivationSourceCondition ActivationSource = "condition" // Procedure whose structured conditions all hold
 )
 
 // ActivatedMemoryType indicates the type of memory in an activation.
@@ -30,10 +33,14 @@ const (
 
 // WorkingMemorySession represents an active working memory session for an agent.
 // Working memory is the agent's "mental workspace" with limited capacity.
+// An agent has one agent-wide session and may hold one more per conversation
+// it isolates; all of them recall from the same long-term memory.
 type WorkingMemorySession struct {
 	ID       uuid.UUID `json:"id"`
 	AgentID  uuid.UUID `json:"agent_id"`
 	TenantID uuid.UUID `json:"tenant_id"`
+	// ConversationID is set on a session isolated to one conversation.
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
 
 	// Current state
 	CurrentGoal    string         `json:"current_goal,omitempty"`
@@ -116,13 +123,171 @@ const (
 	AssociationTypeEntity   = "entity"   // Shared entities
 )
 
+// AssociationDecayMultipliers scales activation spreading across each
+// association type, as RelationDecayMultipliers does for graph edges: causal
+// and derivation links carry activation further than temporal proximity.
+var AssociationDecayMultipliers = map[string]float64{
+	AssociationTypeDerived:  0.8,
+	AssociationTypeThematic: 0.7,
+	AssociationTypeCausal:   0.9,
+	AssociationTypeTemporal: 0.6,
+	AssociationTypeEntity:   0.7,
+}
+
+// ValidMemoryAssociationType reports whether s is a memory association type.
+func ValidMemoryAssociationType(s string) bool {
+	_, ok := AssociationDecayMultipliers[s]
+	return ok
+}
+
+// WorkingMemorySessionUsage is how many of its slots one of an agent's
+// sessions fills.
+type WorkingMemorySessionUsage struct {
+	SessionID      uuid.UUID  `json:"session_id"`
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	CurrentGoal    string     `json:"current_goal,omitempty"`
+	SlotsUsed      int        `json:"slots_used"`
+	MaxSlots       int        `json:"max_slots"`
+	LastActivityAt time.Time  `json:"last_activity_at"`
+}
+
+// WorkingMemoryUsage lists an agent's active sessions with the slots they
+// fill in total.
+type WorkingMemoryUsage struct {
+	AgentID        uuid.UUID                   `json:"agent_id"`
+	Sessions       []WorkingMemorySessionUsage `json:"sessions"`
+	TotalSlotsUsed int                         `json:"total_slots_used"`
+	TotalMaxSlots  int                         `json:"total_max_slots"`
+}
+
+// WorkingMemorySnapshot is a saved copy of a session's mental context, so an
+// agent can suspend a task and later resume with the same goal, reasoning and
+// activations. Activations and schemas are stored by reference.
+type WorkingMemorySnapshot struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id,omitempty"`
+	AgentID  uuid.UUID `json:"agent_id"`
+	Label    string    `json:"label,omitempty"`
+
+	Goal           string                    `json:"goal,omitempty"`
+	ActiveContext  []Message                 `json:"active_context,omitempty"`
+	ReasoningState map[string]any            `json:"reasoning_state,omitempty"`
+	MaxSlots       int                       `json:"max_slots"`
+	Activations    []WorkingMemoryActivation `json:"activations,omitempty"`
+	ActiveSchemas  []SchemaActivation        `json:"active_schemas,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Well-known reasoning state keys. Both hold lists of strings; open questions
+// also bias goal-directed activation. Other keys are stored verbatim.
+const (
+	ReasoningKeyConclusions   = "conclusions"
+	ReasoningKeyOpenQuestions = "open_questions"
+)
+
+// MaxReasoningStateBytes caps a session's encoded reasoning state.
+const MaxReasoningStateBytes = 64 << 10
+
 // ActivationInput contains input for memory activation.
 type ActivationInput struct {
 	AgentID  uuid.UUID `json:"agent_id"`
 	TenantID uuid.UUID `json:"tenant_id"`
-	Goal     string    `json:"goal,omitempty"`    // Current task goal
-	Cues     []string  `json:"cues"`              // Activation cues (query, keywords)
-	Context  []Message `json:"context,omitempty"` // Recent conversation
+	Goal     string    `json:"goal,omitempty"` // Current task goal
+	Cues     []string  `json:"cues"`           // Activation cues (query, keywords)
+	// Context is the recent conversation. Items whose content it already
+	// contains verbatim don't compete for a slot.
+	Context []Message `json:"context,omitempty"`
+	// MaxSlots overrides the agent's slot count for this call (0 = use the
+	// agent's settings). Must lie within the working memory slot bounds.
+	MaxSlots int `json:"max_slots,omitempty"`
+	// ConversationID ties the winning activations to a conversation so a
+	// later episode outcome can be attributed back to them.
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	// IsolateConversation activates in a session of ConversationID's own,
+	// so the goal, context and slots of concurrent conversations don't mix.
+	IsolateConversation bool `json:"isolate_conversation,omitempty"`
+	// Control marks a counterfactual "memory off" activation: the result is
+	// computed for logging but nothing is persisted to the session.
+	Control bool `json:"control,omitempty"`
+	// Vision marks the calling agent as able to see images: activated
+	// episodes then carry their image attachments.
+	Vision bool `json:"vision,omitempty"`
+	// Citations has the assembled context mark each item with a citation
+	// marker such as [m:3f2a9c1b], resolved in WorkingMemoryResult.Citations.
+	Citations bool `json:"citations,omitempty"`
+	// MaxLatencyMs budgets the call: expensive stages that won't fit in
+	// the time left are skipped. Zero means no limit.
+	MaxLatencyMs int `json:"max_latency_ms,omitempty"`
+	// MaxContextTokens caps the estimated size of the assembled context;
+	// the lowest-scoring items are dropped until it fits. Zero means no limit.
+	MaxContextTokens int `json:"max_context_tokens,omitempty"`
+	// UserAttributes and Topics describe the user and conversation for
+	// procedure conditions.
+	UserAttributes map[string]string `json:"user_attributes,omitempty"`
+	Topics         []string          `json:"topics,omitempty"`
+	// AssociationTypes restricts spreading activation to these association
+	// types, e.g. only causal links for a "why" question. Empty follows all.
+	// Either way each type is weighted by its AssociationDecayMultipliers
+	// factor.
+	AssociationTypes []string `json:"association_types,omitempty"`
+}
+
+// ActivationStage is an optional activation stage a budget can skip.
+type ActivationStage string
+
+const (
+	ActivationStageSchemas   ActivationStage = "schemas"
+	ActivationStageSpreading ActivationStage = "spreading"
+)
+
+// Working memory capacity bounds. Below the minimum activation has too little
+// room to be useful; above the maximum the assembled context stops being a
+// focused working set.
+const (
+	MinWorkingMemorySlots     = 3
+	MaxWorkingMemorySlots     = 20
+	DefaultWorkingMemorySlots = 7 // Miller's Law: 7 +/- 2
+)
+
+var ErrInvalidSlotCount = fmt.Errorf("slot count must be between %d and %d", MinWorkingMemorySlots, MaxWorkingMemorySlots)
+
+var ErrInvalidActivationBudget = errors.New("max_latency_ms and max_context_tokens must not be negative")
+
+var ErrIsolationWithoutConversation = errors.New("isolate_conversation requires conversation_id")
+
+var ErrInvalidAssociationType = errors.New("association_types must be derived, thematic, causal, temporal or entity")
+
+// WorkingMemorySettings is an agent's working memory capacity. Slots is the
+// base capacity; complex goals may expand it up to ExpandedSlots.
+type WorkingMemorySettings struct {
+	AgentID       uuid.UUID `json:"agent_id"`
+	TenantID      uuid.UUID `json:"tenant_id,omitempty"`
+	Slots         int       `json:"slots"`
+	ExpandedSlots int       `json:"expanded_slots"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultWorkingMemorySettings applies to agents without stored settings.
+func DefaultWorkingMemorySettings() WorkingMemorySettings {
+	return WorkingMemorySettings{Slots: DefaultWorkingMemorySlots, ExpandedSlots: DefaultWorkingMemorySlots + 2}
+}
+
+// Validate checks both slot counts are within bounds and ExpandedSlots is not
+// below Slots.
+func (s WorkingMemorySettings) Validate() error {
+	if !ValidSlotCount(s.Slots) || !ValidSlotCount(s.ExpandedSlots) {
+		return ErrInvalidSlotCount
+	}
+	if s.ExpandedSlots < s.Slots {
+		return errors.New("expanded_slots must be at least slots")
+	}
+	return nil
+}
+
+// ValidSlotCount reports whether n is an allowed working memory capacity.
+func ValidSlotCount(n int) bool {
+	return n >= MinWorkingMemorySlots && n <= MaxWorkingMemorySlots
 }
 
 // ActivatedContent holds full content for an activated memory.
@@ -132,6 +297,8 @@ type ActivatedContent struct {
 	Content    string              `json:"content"`
 	Confidence float32             `json:"confidence"`
 	Score      float32             `json:"score"` // Combined activation score
+	// Attachments are an episode's images, returned to vision-capable callers.
+	Attachments []EpisodeAttachment `json:"attachments,omitempty"`
 }
 
 // WorkingMemoryResult is the result of memory activation.
@@ -142,4 +309,42 @@ type WorkingMemoryResult struct {
 	SlotUsage        int                   `json:"slot_usage"`
 	MaxSlots         int                   `json:"max_slots"`
 	AssembledContext string                `json:"assembled_context"` // Ready-to-use context for LLM
+	// Citations maps each marker in AssembledContext, without its brackets,
+	// to the item it cites. Only set when the activation asked for them.
+	Citations map[string]ContextCitation `json:"citations,omitempty"`
+	// SkippedStages lists the stages left out to stay within the latency
+	// budget.
+	SkippedStages []ActivationStage `json:"skipped_stages,omitempty"`
+	// Trimmed counts the items and schemas dropped to fit the context token
+	// budget.
+	Trimmed int `json:"trimmed,omitempty"`
+}
+
+// Citation marker kinds: the letter before the ID prefix in a marker.
+const (
+	CitationKindMemory    = "m"
+	CitationKindEpisode   = "e"
+	CitationKindProcedure = "p"
+	CitationKindSchema    = "s"
+)
+
+// ContextCitation is the item an inline citation marker in an assembled
+// context points at, so a statement can be attributed back to it.
+type ContextCitation struct {
+	Type       ActivatedMemoryType `json:"type"`
+	ID         uuid.UUID           `json:"id"`
+	Confidence float32             `json:"confidence"`
+}
+
+// ConversationActivation records that a semantic memory or procedure held a
+// working memory slot during a conversation. Outcomes recorded on the
+// conversation's episodes are attributed back to these.
+type ConversationActivation struct {
+	TenantID        uuid.UUID           `json:"tenant_id"`
+	AgentID         uuid.UUID           `json:"agent_id"`
+	ConversationID  uuid.UUID           `json:"conversation_id"`
+	MemoryType      ActivatedMemoryType `json:"memory_type"`
+	MemoryID        uuid.UUID           `json:"memory_id"`
+	ActivationLevel float32             `json:"activation_level"` // Peak level seen in the conversation
+	ActivatedAt     time.Time           `json:"activated_at"`
 }
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
 )
 
 // defaultEmbeddingHTTPTimeout bounds outbound embedding calls so a stalled
@@ -49,24 +51,63 @@ func NewCompatibleClient(baseURL, apiKey, model string, dimensions int) *Compati
 }
 
 type compatibleRequest struct {
-	Model      string `json:"model"`
-	Input      string `json:"input"`
-	Dimensions int    `json:"dimensions,omitempty"`
+	Model string `json:"model"`
+	// Input is a string, or a list of strings for a batch.
+	Input      any `json:"input"`
+	Dimensions int `json:"dimensions,omitempty"`
 }
 
 // embeddingResponse is the OpenAI /embeddings response shape, shared by all
 // OpenAI-compatible providers.
 type embeddingResponse struct {
 	Data []struct {
+		Index     int       `json:"index"`
 		Embedding []float32 `json:"embedding"`
 	} `json:"data"`
+	Usage struct {
+		PromptTokens int64 `json:"prompt_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
 }
 
 func (c *CompatibleClient) Embed(ctx context.Context, text string) ([]float32, error) {
-	body, err := json.Marshal(compatibleRequest{Model: c.model, Input: text, Dimensions: c.dimensions})
+	result, err := c.post(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("embedding API returned no data")
+	}
+	return result.Data[0].Embedding, nil
+}
+
+// EmbedBatch embeds texts in one request. The response's data[].index puts
+// the vectors back in input order.
+func (c *CompatibleClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	result, err := c.post(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding API returned %d vectors for %d inputs", len(result.Data), len(texts))
+	}
+	vecs := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(texts) || vecs[d.Index] != nil {
+			return nil, fmt.Errorf("embedding API returned an unexpected index %d", d.Index)
+		}
+		vecs[d.Index] = d.Embedding
+	}
+	return vecs, nil
+}
+
+func (c *CompatibleClient) post(ctx context.Context, input any) (*embeddingResponse, error) {
+	body, err := json.Marshal(compatibleRequest{Model: c.model, Input: input, Dimensions: c.dimensions})
 	if err != nil {
 		return nil, fmt.Errorf("marshal embedding request: %w", err)
 	}
@@ -79,6 +120,7 @@ func (c *CompatibleClient) Embed(ctx context.Context, text string) ([]float32, e
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
+	setTraceHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -102,8 +144,6 @@ func (c *CompatibleClient) Embed(ctx context.Context, text string) ([]float32, e
 	if result.Error != nil {
 		return nil, fmt.Errorf("embedding API error: %s", result.Error.Message)
 	}
-	if len(result.Data) == 0 {
-		return nil, fmt.Errorf("embedding API returned no data")
-	}
-	return result.Data[0].Embedding, nil
+	domain.RecordEmbeddingUsage(ctx, c.model, result.Usage.PromptTokens)
+	return &result, nil
 }
@@ -33,6 +33,19 @@ func NewMockClientDim(dim int) *MockClient {
 	return &MockClient{dim: dim}
 }
 
+// EmbedBatch embeds each text in turn, so EmbedCalls records every input.
+func (c *MockClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, len(texts))
+	for i, t := range texts {
+		v, err := c.Embed(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = v
+	}
+	return vecs, nil
+}
+
 func (c *MockClient) Embed(ctx context.Context, text string) ([]float32, error) {
 	c.EmbedCalls = append(c.EmbedCalls, text)
 	if c.EmbedError != nil {
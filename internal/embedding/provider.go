@@ -2,6 +2,7 @@ package embedding
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 )
@@ -49,3 +50,14 @@ func NewClient(cfg Config) (domain.EmbeddingClient, error) {
 		return nil, fmt.Errorf("unknown embedding provider: %s (valid: openai, openai-compatible, local, mock)", cfg.Provider)
 	}
 }
+
+// setTraceHeaders forwards the request and correlation IDs to the provider,
+// so calls can be matched to engram's logs in the provider's request logs.
+func setTraceHeaders(req *http.Request) {
+	if id := domain.RequestIDFrom(req.Context()); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+	if id := domain.CorrelationIDFrom(req.Context()); id != "" {
+		req.Header.Set("X-Correlation-ID", id)
+	}
+}
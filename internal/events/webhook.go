@@ -0,0 +1,88 @@
+// Package events delivers memory change events drained from the transactional
+// outbox to external sinks. The webhook publisher POSTs each event as JSON over
+// plain HTTP and signs the body with HMAC-SHA256 so receivers can verify it came
+// from this server. Other sinks (a message broker, a queue) plug in by
+// implementing domain.EventPublisher.
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+const (
+	// SignatureHeader carries "sha256=<hex HMAC of the body>" when a secret is set.
+	SignatureHeader = "X-Engram-Signature"
+	// EventIDHeader carries the outbox id; deliveries are at-least-once, so
+	// receivers should dedupe on it.
+	EventIDHeader = "X-Engram-Event-Id"
+	// EventTypeHeader carries the event type, e.g. memory.created.
+	EventTypeHeader = "X-Engram-Event-Type"
+)
+
+// WebhookPublisher POSTs outbox events to a single configured URL.
+type WebhookPublisher struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewWebhookPublisher builds a publisher for url. When secret is empty
+// deliveries are sent unsigned.
+func NewWebhookPublisher(url, secret string) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Publish delivers one event. Any non-2xx response is an error so the event
+// is retried.
+func (p *WebhookPublisher) Publish(ctx context.Context, e domain.OutboxEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatInt(e.ID, 10))
+	req.Header.Set(EventTypeHeader, e.Type)
+	if p.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(p.secret, body))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value for body under secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
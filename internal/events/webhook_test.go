@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestWebhookPublisher_SignsAndDelivers(t *testing.T) {
+	secret := "whsec_test"
+	memID := uuid.New()
+
+	var got domain.OutboxEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign(secret, body) {
+			t.Errorf("signature mismatch")
+		}
+		if r.Header.Get(EventIDHeader) != "42" || r.Header.Get(EventTypeHeader) != domain.EventMemoryCreated {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	p := NewWebhookPublisher(srv.URL, secret)
+	err := p.Publish(context.Background(), domain.OutboxEvent{
+		ID:          42,
+		AggregateID: &memID,
+		Type:        domain.EventMemoryCreated,
+		Payload:     map[string]any{"memory_id": memID.String()},
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if got.ID != 42 || got.AggregateID == nil || *got.AggregateID != memID {
+		t.Errorf("receiver decoded %+v", got)
+	}
+}
+
+func TestWebhookPublisher_NonSuccessIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SignatureHeader) != "" {
+			t.Errorf("unsigned publisher sent a signature")
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookPublisher(srv.URL, "").Publish(context.Background(), domain.OutboxEvent{ID: 1, Type: "memory.deletion"}); err == nil {
+		t.Fatal("expected error for 502 response")
+	}
+}
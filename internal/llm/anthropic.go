@@ -53,6 +53,10 @@ type anthropicResponse struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
+	Usage struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Type    string `json:"type"`
 		Message string `json:"message"`
@@ -76,6 +80,7 @@ func (c *AnthropicClient) complete(ctx context.Context, messages []anthropicMess
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-api-key", c.apiKey)
 	req.Header.Set("anthropic-version", anthropicVersion)
+	setTraceHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -100,6 +105,7 @@ func (c *AnthropicClient) complete(ctx context.Context, messages []anthropicMess
 	if result.Error != nil {
 		return "", fmt.Errorf("anthropic API error: %s", result.Error.Message)
 	}
+	domain.RecordLLMUsage(ctx, c.model, result.Usage.InputTokens, result.Usage.OutputTokens)
 
 	if len(result.Content) == 0 {
 		return "", fmt.Errorf("anthropic API returned no content")
@@ -517,3 +523,31 @@ func (c *AnthropicClient) DetectRelationships(ctx context.Context, memory *domai
 
 	return relationships, nil
 }
+
+func (c *AnthropicClient) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	if len(beliefs) == 0 {
+		return nil, nil
+	}
+
+	messages := []anthropicMessage{
+		{Role: "user", Content: fmt.Sprintf(verificationQuestionPrompt, formatUncertainBeliefs(beliefs))},
+	}
+
+	result, err := c.complete(ctx, messages, 1024)
+	if err != nil {
+		return nil, fmt.Errorf("generate verification questions: %w", err)
+	}
+	return parseVerificationQuestions(result, beliefs)
+}
+
+func (c *AnthropicClient) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	messages := []anthropicMessage{
+		{Role: "user", Content: knowledgeNarrativeMessage(topic, sources)},
+	}
+
+	result, err := c.complete(ctx, messages, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("narrate knowledge: %w", err)
+	}
+	return parseKnowledgeNarrative(result, topic)
+}
@@ -54,6 +54,10 @@ type cerebrasResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
@@ -90,6 +94,7 @@ func (c *CerebrasClient) complete(ctx context.Context, messages []cerebrasMessag
 		}
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		setTraceHeaders(req)
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
@@ -117,6 +122,7 @@ func (c *CerebrasClient) complete(ctx context.Context, messages []cerebrasMessag
 		if err := json.Unmarshal(respBody, &result); err != nil {
 			return "", fmt.Errorf("unmarshal cerebras response: %w", err)
 		}
+		domain.RecordLLMUsage(ctx, cerebrasModel, result.Usage.PromptTokens, result.Usage.CompletionTokens)
 		if len(result.Choices) == 0 {
 			return "", fmt.Errorf("cerebras returned no choices")
 		}
@@ -534,3 +540,31 @@ func (c *CerebrasClient) DetectRelationships(ctx context.Context, memory *domain
 
 	return relationships, nil
 }
+
+func (c *CerebrasClient) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	if len(beliefs) == 0 {
+		return nil, nil
+	}
+
+	messages := []cerebrasMessage{
+		{Role: "user", Content: fmt.Sprintf(verificationQuestionPrompt, formatUncertainBeliefs(beliefs))},
+	}
+
+	result, err := c.complete(ctx, messages, 0.3)
+	if err != nil {
+		return nil, fmt.Errorf("generate verification questions: %w", err)
+	}
+	return parseVerificationQuestions(result, beliefs)
+}
+
+func (c *CerebrasClient) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	messages := []cerebrasMessage{
+		{Role: "user", Content: knowledgeNarrativeMessage(topic, sources)},
+	}
+
+	result, err := c.complete(ctx, messages, 0.2)
+	if err != nil {
+		return nil, fmt.Errorf("narrate knowledge: %w", err)
+	}
+	return parseKnowledgeNarrative(result, topic)
+}
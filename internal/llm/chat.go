@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+const (
+	anthropicChatURL = "https://api.anthropic.com/v1/chat/completions"
+	geminiChatURL    = "https://generativelanguage.googleapis.com/v1beta/openai/chat/completions"
+
+	// maxChatResponseBytes bounds a proxied completion held in memory.
+	maxChatResponseBytes = 16 << 20
+)
+
+// forwardChat posts an OpenAI-format chat request to an OpenAI-compatible
+// endpoint, filling in model when the request leaves it empty.
+func forwardChat(ctx context.Context, hc *http.Client, url, apiKey, model string, request json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(request, &fields); err != nil {
+		return nil, fmt.Errorf("decode chat request: %w", err)
+	}
+	var requested string
+	_ = json.Unmarshal(fields["model"], &requested)
+	if requested == "" {
+		fields["model"], _ = json.Marshal(model)
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("marshal chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	setTraceHeaders(req)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxChatResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read chat response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &domain.ChatUpstreamError{StatusCode: resp.StatusCode, Body: respBody}
+	}
+	return respBody, nil
+}
+
+func (c *OpenAIClient) CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	return forwardChat(ctx, c.httpClient, openAIChatURL, c.apiKey, chatModel, request)
+}
+
+func (c *CerebrasClient) CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	return forwardChat(ctx, c.httpClient, cerebrasAPIURL, c.apiKey, cerebrasModel, request)
+}
+
+// CompleteChat uses Anthropic's OpenAI-compatible endpoint, so the request and
+// response keep the OpenAI shape.
+func (c *AnthropicClient) CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	return forwardChat(ctx, c.httpClient, anthropicChatURL, c.apiKey, c.model, request)
+}
+
+// CompleteChat uses Gemini's OpenAI-compatible endpoint, so the request and
+// response keep the OpenAI shape.
+func (c *GeminiClient) CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	return forwardChat(ctx, c.httpClient, geminiChatURL, c.apiKey, geminiModel, request)
+}
@@ -13,7 +13,8 @@ import (
 )
 
 const (
-	geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent"
+	geminiModel   = "gemini-2.0-flash"
+	geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta/models/" + geminiModel + ":generateContent"
 )
 
 type GeminiClient struct {
@@ -49,6 +50,10 @@ type geminiResponse struct {
 			} `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int64 `json:"promptTokenCount"`
+		CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
 	Error *struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
@@ -75,6 +80,7 @@ func (c *GeminiClient) complete(ctx context.Context, prompt string) (string, err
 		return "", fmt.Errorf("create gemini request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	setTraceHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -99,6 +105,7 @@ func (c *GeminiClient) complete(ctx context.Context, prompt string) (string, err
 	if result.Error != nil {
 		return "", fmt.Errorf("gemini API error: %s", result.Error.Message)
 	}
+	domain.RecordLLMUsage(ctx, geminiModel, result.UsageMetadata.PromptTokenCount, result.UsageMetadata.CandidatesTokenCount)
 
 	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("gemini API returned no content")
@@ -492,3 +499,23 @@ func (c *GeminiClient) DetectRelationships(ctx context.Context, memory *domain.M
 
 	return relationships, nil
 }
+
+func (c *GeminiClient) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	if len(beliefs) == 0 {
+		return nil, nil
+	}
+
+	result, err := c.complete(ctx, fmt.Sprintf(verificationQuestionPrompt, formatUncertainBeliefs(beliefs)))
+	if err != nil {
+		return nil, fmt.Errorf("generate verification questions: %w", err)
+	}
+	return parseVerificationQuestions(result, beliefs)
+}
+
+func (c *GeminiClient) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	result, err := c.complete(ctx, knowledgeNarrativeMessage(topic, sources))
+	if err != nil {
+		return nil, fmt.Errorf("narrate knowledge: %w", err)
+	}
+	return parseKnowledgeNarrative(result, topic)
+}
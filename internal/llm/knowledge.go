@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// knowledgeNarrativeMessage fills knowledgeNarrativePrompt with the topic and
+// the records recalled for it.
+func knowledgeNarrativeMessage(topic string, sources domain.KnowledgeSources) string {
+	var memSb strings.Builder
+	for _, m := range sources.Memories {
+		memSb.WriteString(fmt.Sprintf("- ID: %s [%.2f] %s\n", m.ID.String(), m.Confidence, m.Content))
+	}
+	var epSb strings.Builder
+	for _, e := range sources.Episodes {
+		epSb.WriteString(fmt.Sprintf("- ID: %s (%s) %s\n", e.ID.String(), e.OccurredAt.Format("2006-01-02"), e.RawContent))
+	}
+	var schemaSb strings.Builder
+	for _, s := range sources.Schemas {
+		schemaSb.WriteString(fmt.Sprintf("- ID: %s [%.2f] %s: %s\n", s.ID.String(), s.Confidence, s.Name, s.Description))
+	}
+	return fmt.Sprintf(knowledgeNarrativePrompt, topic, orNone(memSb.String()), orNone(epSb.String()), orNone(schemaSb.String()))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)\n"
+	}
+	return s
+}
+
+// parseKnowledgeNarrative decodes the model's answer. Citations carry only
+// the cited IDs; resolving them against the records is left to the caller.
+func parseKnowledgeNarrative(result, topic string) (*domain.KnowledgeNarrative, error) {
+	result = strings.TrimPrefix(result, "```json")
+	result = strings.TrimPrefix(result, "```")
+	result = strings.TrimSuffix(result, "```")
+	result = strings.TrimSpace(result)
+
+	var response struct {
+		Summary string `json:"summary"`
+		Claims  []struct {
+			Statement string   `json:"statement"`
+			Sources   []string `json:"sources"`
+		} `json:"claims"`
+	}
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		return nil, fmt.Errorf("parse knowledge narrative result: %w (raw: %s)", err, result)
+	}
+
+	narrative := &domain.KnowledgeNarrative{Topic: topic, Summary: strings.TrimSpace(response.Summary)}
+	for _, c := range response.Claims {
+		claim := domain.KnowledgeClaim{Statement: strings.TrimSpace(c.Statement)}
+		for _, src := range c.Sources {
+			id, err := parseUUID(src)
+			if err != nil {
+				continue
+			}
+			claim.Citations = append(claim.Citations, domain.KnowledgeCitation{ID: id})
+		}
+		narrative.Claims = append(narrative.Claims, claim)
+	}
+	return narrative, nil
+}
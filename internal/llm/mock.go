@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 )
@@ -33,6 +34,13 @@ type MockClient struct {
 	DetectRelationshipsError        error
 	IngestConversationResponse      []domain.ExtractedConversationMemory
 	IngestConversationError         error
+	CompleteChatResponse            json.RawMessage
+	CompleteChatError               error
+
+	GenerateVerificationQuestionsResponse []domain.VerificationQuestion
+	GenerateVerificationQuestionsError    error
+	NarrateKnowledgeResponse              *domain.KnowledgeNarrative
+	NarrateKnowledgeError                 error
 
 	// Call tracking for assertions
 	ClassifyCalls                []string
@@ -53,6 +61,13 @@ type MockClient struct {
 		Similar []domain.MemoryWithScore
 	}
 	IngestConversationCalls [][]domain.Message
+	CompleteChatCalls       []json.RawMessage
+
+	GenerateVerificationQuestionsCalls [][]domain.UncertainBelief
+	NarrateKnowledgeCalls              []struct {
+		Topic   string
+		Sources domain.KnowledgeSources
+	}
 }
 
 func NewMockClient() *MockClient {
@@ -190,6 +205,30 @@ func (c *MockClient) IngestConversation(ctx context.Context, messages []domain.M
 	return c.IngestConversationResponse, nil
 }
 
+func (c *MockClient) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	c.GenerateVerificationQuestionsCalls = append(c.GenerateVerificationQuestionsCalls, beliefs)
+	if c.GenerateVerificationQuestionsError != nil {
+		return nil, c.GenerateVerificationQuestionsError
+	}
+	return c.GenerateVerificationQuestionsResponse, nil
+}
+
+// NarrateKnowledge returns NarrateKnowledgeResponse, or a narrative with no
+// claims when it is unset.
+func (c *MockClient) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	c.NarrateKnowledgeCalls = append(c.NarrateKnowledgeCalls, struct {
+		Topic   string
+		Sources domain.KnowledgeSources
+	}{topic, sources})
+	if c.NarrateKnowledgeError != nil {
+		return nil, c.NarrateKnowledgeError
+	}
+	if c.NarrateKnowledgeResponse != nil {
+		return c.NarrateKnowledgeResponse, nil
+	}
+	return &domain.KnowledgeNarrative{Topic: topic, Summary: "Mock narrative"}, nil
+}
+
 // Reset clears all recorded calls and resets responses to defaults.
 func (c *MockClient) Reset() {
 	c.ClassifyResponse = domain.MemoryTypeFact
@@ -235,6 +274,10 @@ func (c *MockClient) Reset() {
 	c.ExtractEntitiesError = nil
 	c.DetectRelationshipsResponse = nil
 	c.DetectRelationshipsError = nil
+	c.GenerateVerificationQuestionsResponse = nil
+	c.GenerateVerificationQuestionsError = nil
+	c.NarrateKnowledgeResponse = nil
+	c.NarrateKnowledgeError = nil
 	c.ClassifyCalls = nil
 	c.ExtractCalls = nil
 	c.SummarizeCalls = nil
@@ -246,4 +289,22 @@ func (c *MockClient) Reset() {
 	c.DetectImplicitFeedbackCalls = nil
 	c.ExtractEntitiesCalls = nil
 	c.DetectRelationshipsCalls = nil
+	c.CompleteChatResponse = nil
+	c.CompleteChatError = nil
+	c.CompleteChatCalls = nil
+	c.GenerateVerificationQuestionsCalls = nil
+	c.NarrateKnowledgeCalls = nil
+}
+
+// CompleteChat returns CompleteChatResponse, or a one-choice completion
+// saying "Mock reply" when it is unset.
+func (c *MockClient) CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	c.CompleteChatCalls = append(c.CompleteChatCalls, request)
+	if c.CompleteChatError != nil {
+		return nil, c.CompleteChatError
+	}
+	if c.CompleteChatResponse != nil {
+		return c.CompleteChatResponse, nil
+	}
+	return json.RawMessage(`{"id":"mock","object":"chat.completion","model":"mock","choices":[{"index":0,"message":{"role":"assistant","content":"Mock reply"},"finish_reason":"stop"}]}`), nil
 }
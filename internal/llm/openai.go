@@ -47,6 +47,10 @@ type chatResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
@@ -68,6 +72,7 @@ func (c *OpenAIClient) complete(ctx context.Context, messages []chatMessage, tem
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	setTraceHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -92,6 +97,7 @@ func (c *OpenAIClient) complete(ctx context.Context, messages []chatMessage, tem
 	if result.Error != nil {
 		return "", fmt.Errorf("chat API error: %s", result.Error.Message)
 	}
+	domain.RecordLLMUsage(ctx, chatModel, result.Usage.PromptTokens, result.Usage.CompletionTokens)
 
 	if len(result.Choices) == 0 {
 		return "", fmt.Errorf("chat API returned no choices")
@@ -520,3 +526,31 @@ func (c *OpenAIClient) DetectRelationships(ctx context.Context, memory *domain.M
 
 	return relationships, nil
 }
+
+func (c *OpenAIClient) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	if len(beliefs) == 0 {
+		return nil, nil
+	}
+
+	messages := []chatMessage{
+		{Role: "user", Content: fmt.Sprintf(verificationQuestionPrompt, formatUncertainBeliefs(beliefs))},
+	}
+
+	result, err := c.complete(ctx, messages, 0.3)
+	if err != nil {
+		return nil, fmt.Errorf("generate verification questions: %w", err)
+	}
+	return parseVerificationQuestions(result, beliefs)
+}
+
+func (c *OpenAIClient) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	messages := []chatMessage{
+		{Role: "user", Content: knowledgeNarrativeMessage(topic, sources)},
+	}
+
+	result, err := c.complete(ctx, messages, 0.2)
+	if err != nil {
+		return nil, fmt.Errorf("narrate knowledge: %w", err)
+	}
+	return parseKnowledgeNarrative(result, topic)
+}
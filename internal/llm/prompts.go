@@ -18,6 +18,8 @@ From ASSISTANT turns, extract:
 
 Rules:
 - source="user" for user statements, source="assistant" for assistant statements
+- subject is who or what the fact is about: "user", "assistant", or the name of the person, organization or thing (e.g. "Maria" for "My sister Maria lives in Lisbon")
+- topic is the area the fact belongs to, a short lowercase noun phrase of one to three words (e.g. "dining", "family", "career"); reuse the same topic for related facts
 - Be specific and self-contained: "Assistant recommended Roscioli near the Vatican" not "Assistant gave a recommendation"
 - Do NOT transcribe long numbered lists (10+ items) item-by-item — they are preserved verbatim automatically. Name the list's topic once and spend the fact budget on prose details instead.
 - Max 30 facts total. Prioritise specificity over generality.
@@ -31,11 +33,11 @@ Conversation:
 
 Respond ONLY with a JSON array. No markdown, no explanation.
 [
-  {"type":"fact","content":"User wants a romantic Italian restaurant near the Vatican","source":"user","evidence_type":"explicit_statement"},
-  {"type":"fact","content":"Assistant recommended Roscioli restaurant near the Vatican for a romantic dinner","source":"assistant","evidence_type":"explicit_statement"},
-  {"type":"fact","content":"The 7th item in the assistant's list of work-from-home jobs for seniors is Transcriptionist","source":"assistant","evidence_type":"explicit_statement"},
-  {"type":"preference","content":"User prefers non-touristy restaurants","source":"user","evidence_type":"explicit_statement"},
-  {"type":"fact","content":"Assistant recommended Memrise as the language app that uses mnemonics","source":"assistant","evidence_type":"explicit_statement"}
+  {"type":"fact","content":"User wants a romantic Italian restaurant near the Vatican","source":"user","subject":"user","topic":"dining","evidence_type":"explicit_statement"},
+  {"type":"fact","content":"Assistant recommended Roscioli restaurant near the Vatican for a romantic dinner","source":"assistant","subject":"assistant","topic":"dining","evidence_type":"explicit_statement"},
+  {"type":"fact","content":"The 7th item in the assistant's list of work-from-home jobs for seniors is Transcriptionist","source":"assistant","subject":"assistant","topic":"remote work","evidence_type":"explicit_statement"},
+  {"type":"preference","content":"User prefers non-touristy restaurants","source":"user","subject":"user","topic":"dining","evidence_type":"explicit_statement"},
+  {"type":"fact","content":"Maria, the user's sister, lives in Lisbon","source":"user","subject":"Maria","topic":"family","evidence_type":"explicit_statement"}
 ]`
 
 const extractPrompt = `You are a memory extraction system. Analyze the following conversation and extract distinct memories.
@@ -43,13 +45,15 @@ const extractPrompt = `You are a memory extraction system. Analyze the following
 For each memory, determine:
 - type: one of "preference", "fact", "decision", "constraint"
 - content: a clear, concise statement of the memory
+- subject: who or what the memory is about: "user", "assistant", or the name of the person, organization or thing. When the conversation lists participants, use the participant's name.
+- topic: the area the memory belongs to, a short lowercase noun phrase of one to three words (e.g. "diet", "work schedule"); reuse the same topic for related memories
 - evidence_type: how this belief was derived:
   - "explicit_statement": user directly stated this
   - "implicit_inference": inferred from indirect statements or patterns
   - "behavioral_signal": observed from user actions or behavior
 
 Respond ONLY with a JSON array. No markdown, no explanation. Example:
-[{"type":"preference","content":"User prefers dark mode","evidence_type":"explicit_statement"}]
+[{"type":"preference","content":"User prefers dark mode","subject":"user","topic":"user interface","evidence_type":"explicit_statement"}]
 
 If no memories can be extracted, respond with an empty array: []
 
@@ -128,7 +132,7 @@ Experience: %s
 
 Extract:
 1. entities: List of key entities mentioned (people, things, concepts)
-2. topics: Main topics/themes
+2. topics: Main topics/themes, each a short lowercase noun phrase of one to three words (e.g. "travel", "work schedule")
 3. causal_links: Any cause-effect relationships (as [{cause, effect, confidence}])
 4. emotional_valence: Overall sentiment (-1 negative to +1 positive)
 5. emotional_intensity: How strong is the emotion (0 to 1)
@@ -151,7 +155,7 @@ Interaction: %s
 Extract a reusable procedure:
 1. trigger_pattern: A description of the situation/trigger that should invoke this procedure
 2. trigger_keywords: Key words/phrases that indicate this trigger
-3. action_template: The response pattern or approach that worked
+3. action_template: The response pattern or approach that worked. Where it depends on who or what the conversation is about, use placeholders instead of specifics: {{user_name}} (or another user attribute as {{user_<attribute>}}), {{topic}}, {{goal}}, or an entity type: {{person}}, {{organization}}, {{tool}}, {{concept}}, {{location}}, {{event}}, {{product}}. Use no other placeholders.
 4. action_type: One of "response_style", "problem_solving", "communication", "workflow"
 
 Respond ONLY with JSON, no markdown fences:
@@ -258,3 +262,30 @@ Respond ONLY with JSON array, no markdown fences:
 [{"target_id":"uuid","relation_type":"thematic","strength":0.7,"reason":"Both about user preferences"}]
 
 If no relationships found, return empty array: []`
+
+const imageCaptionPrompt = `Describe this image in one or two plain sentences for a memory system that will later search for it by text. Name the visible objects, people, text and setting; do not speculate beyond what is shown.`
+
+const verificationQuestionPrompt = `You help an assistant keep its memory of a user accurate. Each belief below is uncertain: it has been contradicted, is held with low confidence, or has not been confirmed in a long time.
+
+Beliefs:
+%s
+
+For each belief, write one short, natural question the assistant could ask the user in conversation to confirm or correct it. Address the user directly ("you"), don't mention memory, confidence or databases, and for a contradicted belief ask which of the conflicting statements is right.
+
+Respond ONLY with JSON array, no markdown fences:
+[{"memory_id":"uuid","question":"Are you still working night shifts?"}]
+
+If no question makes sense, return empty array: []`
+
+const knowledgeNarrativePrompt = `You answer the question "What do you know about %s?" for an assistant, using only its memory records below.
+
+Beliefs (confidence in brackets):
+%s
+Past experiences:
+%s
+Mental models:
+%s
+Write a short, plain summary of what the records say about the topic, then list the individual claims behind it. Every claim must cite the IDs of the records that support it, and only those. Leave out anything the records don't support, and say so in the summary if they say little or nothing about the topic. Where records disagree, state both claims separately.
+
+Respond ONLY with JSON, no markdown fences:
+{"summary":"The user is vegetarian and cooks at home most evenings.","claims":[{"statement":"The user is vegetarian","sources":["uuid"]}]}`
@@ -2,6 +2,7 @@ package llm
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
@@ -11,6 +12,17 @@ func parseUUID(s string) (uuid.UUID, error) {
 	return uuid.Parse(s)
 }
 
+// setTraceHeaders forwards the request and correlation IDs to the provider,
+// so calls can be matched to engram's logs in the provider's request logs.
+func setTraceHeaders(req *http.Request) {
+	if id := domain.RequestIDFrom(req.Context()); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+	if id := domain.CorrelationIDFrom(req.Context()); id != "" {
+		req.Header.Set("X-Correlation-ID", id)
+	}
+}
+
 func provenanceTag(p domain.Provenance) string {
 	switch p {
 	case domain.ProvenanceUser:
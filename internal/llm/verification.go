@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// formatUncertainBeliefs lists beliefs for verificationQuestionPrompt with the
+// reasons each is uncertain and what contradicts it.
+func formatUncertainBeliefs(beliefs []domain.UncertainBelief) string {
+	var sb strings.Builder
+	for _, b := range beliefs {
+		reasons := make([]string, len(b.Reasons))
+		for i, r := range b.Reasons {
+			reasons[i] = strings.ReplaceAll(string(r), "_", " ")
+		}
+		sb.WriteString(fmt.Sprintf("- ID: %s\n  Content: %s\n  Uncertain because: %s\n",
+			b.Memory.ID.String(), b.Memory.Content, strings.Join(reasons, ", ")))
+		for _, c := range b.Contradictions {
+			sb.WriteString(fmt.Sprintf("  Contradicted by: %s\n", c))
+		}
+	}
+	return sb.String()
+}
+
+// parseVerificationQuestions decodes the model's answer, keeping one question
+// per belief that was asked about.
+func parseVerificationQuestions(result string, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	result = strings.TrimPrefix(result, "```json")
+	result = strings.TrimPrefix(result, "```")
+	result = strings.TrimSuffix(result, "```")
+	result = strings.TrimSpace(result)
+
+	var responses []struct {
+		MemoryID string `json:"memory_id"`
+		Question string `json:"question"`
+	}
+	if err := json.Unmarshal([]byte(result), &responses); err != nil {
+		return nil, fmt.Errorf("parse verification questions result: %w (raw: %s)", err, result)
+	}
+
+	asked := make(map[string]bool, len(beliefs))
+	for _, b := range beliefs {
+		asked[b.Memory.ID.String()] = true
+	}
+	var questions []domain.VerificationQuestion
+	for _, r := range responses {
+		memID, err := parseUUID(r.MemoryID)
+		if err != nil || !asked[memID.String()] {
+			continue
+		}
+		question := strings.TrimSpace(r.Question)
+		if question == "" {
+			continue
+		}
+		asked[memID.String()] = false
+		questions = append(questions, domain.VerificationQuestion{MemoryID: memID, Question: question})
+	}
+	return questions, nil
+}
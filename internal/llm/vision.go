@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// VisionCaptioner captions images through a provider's OpenAI-compatible chat
+// endpoint, sending the image by URL. The provider fetches the image itself,
+// so the URL must be reachable from it (a presigned object-storage URL, for
+// instance). model must accept image input.
+type VisionCaptioner struct {
+	chat  domain.ChatCompleter
+	model string
+}
+
+// NewVisionCaptioner returns a captioner for model on client's provider, or
+// nil when model is empty or the client can't proxy chat.
+func NewVisionCaptioner(client domain.LLMClient, model string) *VisionCaptioner {
+	chat, ok := client.(domain.ChatCompleter)
+	if !ok || model == "" {
+		return nil
+	}
+	return &VisionCaptioner{chat: chat, model: model}
+}
+
+type visionContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *visionImageURL `json:"image_url,omitempty"`
+}
+
+type visionImageURL struct {
+	URL string `json:"url"`
+}
+
+func (c *VisionCaptioner) CaptionImage(ctx context.Context, imageURL string) (string, error) {
+	image := visionContentPart{Type: "image_url", ImageURL: &visionImageURL{URL: imageURL}}
+	body, err := json.Marshal(map[string]any{
+		"model": c.model,
+		"messages": []map[string]any{{
+			"role":    "user",
+			"content": []visionContentPart{{Type: "text", Text: imageCaptionPrompt}, image},
+		}},
+		"temperature": 0,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal caption request: %w", err)
+	}
+
+	raw, err := c.chat.CompleteChat(ctx, body)
+	if err != nil {
+		return "", err
+	}
+	var resp chatResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", fmt.Errorf("decode caption response: %w", err)
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("caption error: %s", resp.Error.Message)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("caption response has no choices")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+var _ domain.ImageCaptioner = (*VisionCaptioner)(nil)
@@ -0,0 +1,346 @@
+// Package redis is a minimal RESP2 client covering the commands the working
+// memory cache needs: plain and pipelined commands over a small pool of
+// connections, with TLS, AUTH and SELECT from a redis:// or rediss:// URL.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNil is returned by the typed helpers when the reply is a nil bulk string
+// or array, i.e. the key does not exist.
+var ErrNil = errors.New("redis: nil")
+
+// Error is an error reply sent by the server.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+const (
+	defaultPoolSize = 16
+	defaultTimeout  = 2 * time.Second
+)
+
+type Client struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      *tls.Config // nil for plain TCP
+	timeout  time.Duration
+	pool     chan *conn
+}
+
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+	w  *bufio.Writer
+}
+
+// New returns a client for a redis://[[user]:password@]host[:port][/db] URL,
+// or rediss:// for TLS. With a user the connection authenticates as that ACL
+// user (AUTH user password), otherwise with the password alone. Connections
+// are opened lazily.
+func New(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	c := &Client{
+		addr:    u.Host,
+		timeout: defaultTimeout,
+		pool:    make(chan *conn, defaultPoolSize),
+	}
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		c.tls = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	default:
+		return nil, fmt.Errorf("unsupported redis url scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	if p := strings.Trim(u.Path, "/"); p != "" {
+		if c.db, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("invalid redis db %q", p)
+		}
+	}
+	return c, nil
+}
+
+// Do runs one command and returns its reply: string, int64, []any, nil, or an
+// Error for server error replies.
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	replies, err := c.Pipeline(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := replies[0].(Error); ok {
+		return nil, e
+	}
+	return replies[0], nil
+}
+
+// Pipeline sends all commands in one round trip and returns their replies in
+// order. A server error reply to one command is returned in its slot (as an
+// Error) and does not fail the others.
+func (c *Client) Pipeline(ctx context.Context, cmds ...[]any) ([]any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := cn.roundTrip(ctx, c.timeout, cmds)
+	if err != nil {
+		_ = cn.nc.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return replies, nil
+}
+
+// Close closes the idle connections.
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.pool:
+			_ = cn.nc.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+
+	d := &net.Dialer{Timeout: c.timeout}
+	var nc net.Conn
+	var err error
+	if c.tls != nil {
+		nc, err = (&tls.Dialer{NetDialer: d, Config: c.tls}).DialContext(ctx, "tcp", c.addr)
+	} else {
+		nc, err = d.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	var setup [][]any
+	switch {
+	case c.username != "":
+		setup = append(setup, []any{"AUTH", c.username, c.password})
+	case c.password != "":
+		setup = append(setup, []any{"AUTH", c.password})
+	}
+	if c.db != 0 {
+		setup = append(setup, []any{"SELECT", c.db})
+	}
+	if len(setup) > 0 {
+		replies, err := cn.roundTrip(ctx, c.timeout, setup)
+		if err == nil {
+			for _, r := range replies {
+				if e, ok := r.(Error); ok {
+					err = e
+					break
+				}
+			}
+		}
+		if err != nil {
+			_ = nc.Close()
+			return nil, fmt.Errorf("redis connection setup: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		_ = cn.nc.Close()
+	}
+}
+
+func (cn *conn) roundTrip(ctx context.Context, timeout time.Duration, cmds [][]any) ([]any, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.nc.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	for _, cmd := range cmds {
+		if err := writeCommand(cn.w, cmd); err != nil {
+			return nil, err
+		}
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(cmds))
+	for i := range cmds {
+		r, err := readReply(cn.r)
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = r
+	}
+	return replies, nil
+}
+
+func writeCommand(w *bufio.Writer, args []any) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, a := range args {
+		var s string
+		switch v := a.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", a)
+		}
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+	}
+	return nil
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	body := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", line[0])
+}
+
+// String converts a bulk or simple string reply. A nil reply yields ErrNil.
+func String(reply any, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	switch v := reply.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", ErrNil
+	case Error:
+		return "", v
+	}
+	return "", fmt.Errorf("redis: unexpected reply type %T", reply)
+}
+
+// Strings converts an array reply of bulk strings; nil elements become "".
+func Strings(reply any, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	switch v := reply.(type) {
+	case []any:
+		out := make([]string, len(v))
+		for i, item := range v {
+			s, _ := item.(string)
+			out[i] = s
+		}
+		return out, nil
+	case nil:
+		return nil, ErrNil
+	case Error:
+		return nil, v
+	}
+	return nil, fmt.Errorf("redis: unexpected reply type %T", reply)
+}
+
+// Int64 converts an integer reply.
+func Int64(reply any, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case nil:
+		return 0, ErrNil
+	case Error:
+		return 0, v
+	}
+	return 0, fmt.Errorf("redis: unexpected reply type %T", reply)
+}
+
+// FirstError returns the first server error among pipeline replies.
+func FirstError(replies []any) error {
+	for _, r := range replies {
+		if e, ok := r.(Error); ok {
+			return e
+		}
+	}
+	return nil
+}
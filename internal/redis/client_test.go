@@ -0,0 +1,198 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer answers each command from a canned reply table keyed by the
+// command name, and records the commands it saw.
+func fakeServer(t *testing.T, replies map[string]string) (string, func() []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	return serve(t, ln, replies)
+}
+
+// fakeTLSServer is fakeServer behind TLS with a self-signed certificate for
+// 127.0.0.1, returned as a pool for the client to trust.
+func fakeTLSServer(t *testing.T, replies map[string]string) (string, *x509.CertPool, func() []string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "redis-test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr, seen := serve(t, ln, replies)
+	return addr, roots, seen
+}
+
+func serve(t *testing.T, ln net.Listener, replies map[string]string) (string, func() []string) {
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(nc net.Conn) {
+				defer nc.Close()
+				r := bufio.NewReader(nc)
+				for {
+					reply, err := readReply(r)
+					if err != nil {
+						return
+					}
+					args, _ := reply.([]any)
+					parts := make([]string, len(args))
+					for i, a := range args {
+						parts[i] = fmt.Sprint(a)
+					}
+					mu.Lock()
+					seen = append(seen, strings.Join(parts, " "))
+					mu.Unlock()
+					resp, ok := replies[strings.ToUpper(parts[0])]
+					if !ok {
+						resp = "-ERR unknown command\r\n"
+					}
+					if _, err := nc.Write([]byte(resp)); err != nil {
+						return
+					}
+				}
+			}(nc)
+		}
+	}()
+	return ln.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func TestClient_DoAndPipeline(t *testing.T) {
+	addr, seen := fakeServer(t, map[string]string{
+		"AUTH":     "+OK\r\n",
+		"SELECT":   "+OK\r\n",
+		"GET":      "$5\r\nhello\r\n",
+		"HGET":     "$-1\r\n",
+		"SADD":     ":2\r\n",
+		"SMEMBERS": "*2\r\n$1\r\na\r\n$1\r\nb\r\n",
+	})
+	c, err := New("redis://:secret@" + addr + "/3")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+	ctx := context.Background()
+
+	if s, err := String(c.Do(ctx, "GET", "k")); err != nil || s != "hello" {
+		t.Errorf("GET = %q, %v", s, err)
+	}
+	if _, err := String(c.Do(ctx, "HGET", "h", "f")); !errors.Is(err, ErrNil) {
+		t.Errorf("expected ErrNil for missing field, got %v", err)
+	}
+
+	replies, err := c.Pipeline(ctx, []any{"SADD", "s", "a", "b"}, []any{"SMEMBERS", "s"}, []any{"BOGUS"})
+	if err != nil {
+		t.Fatalf("Pipeline: %v", err)
+	}
+	if n, err := Int64(replies[0], nil); err != nil || n != 2 {
+		t.Errorf("SADD = %d, %v", n, err)
+	}
+	if m, err := Strings(replies[1], nil); err != nil || strings.Join(m, ",") != "a,b" {
+		t.Errorf("SMEMBERS = %v, %v", m, err)
+	}
+	var rerr Error
+	if err := FirstError(replies); !errors.As(err, &rerr) {
+		t.Errorf("expected server error in pipeline, got %v", err)
+	}
+
+	if got := seen(); len(got) < 2 || got[0] != "AUTH secret" || got[1] != "SELECT 3" {
+		t.Errorf("expected AUTH and SELECT on connect, saw %v", got)
+	}
+}
+
+func TestClient_TLSWithACLUser(t *testing.T) {
+	addr, roots, seen := fakeTLSServer(t, map[string]string{
+		"AUTH": "+OK\r\n",
+		"GET":  "$2\r\nok\r\n",
+	})
+	c, err := New("rediss://cache:secret@" + addr)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+	c.tls.RootCAs = roots
+
+	if s, err := String(c.Do(context.Background(), "GET", "k")); err != nil || s != "ok" {
+		t.Fatalf("GET over TLS = %q, %v", s, err)
+	}
+	if got := seen(); len(got) < 1 || got[0] != "AUTH cache secret" {
+		t.Errorf("expected AUTH as the ACL user on connect, saw %v", got)
+	}
+}
+
+func TestClient_TLSRejectsUntrustedServer(t *testing.T) {
+	addr, _, _ := fakeTLSServer(t, map[string]string{"GET": "$2\r\nok\r\n"})
+	c, err := New("rediss://" + addr)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.Do(context.Background(), "GET", "k"); err == nil {
+		t.Fatal("expected the self-signed certificate to be rejected")
+	}
+}
+
+func TestNew_RejectsBadURLs(t *testing.T) {
+	for _, u := range []string{"http://localhost", "redis://localhost/notadb"} {
+		if _, err := New(u); err == nil {
+			t.Errorf("expected error for %q", u)
+		}
+	}
+}
@@ -23,6 +23,11 @@ const redactionTombstone = "[REDACTED]"
 type AdminService struct {
 	memoryStore     domain.MemoryStore
 	embeddingClient domain.EmbeddingClient
+	scanner         domain.MemoryScanner
+	vectors         domain.VectorSyncer
+	quantizer       domain.VectorQuantizer
+	derived         domain.DerivedFieldRecomputer
+	tiers           AgentTierTransitioner
 	uow             *store.UnitOfWork
 	logger          *zap.Logger
 }
@@ -31,6 +36,35 @@ func NewAdminService(ms domain.MemoryStore, ec domain.EmbeddingClient, uow *stor
 	return &AdminService{memoryStore: ms, embeddingClient: ec, uow: uow, logger: logger}
 }
 
+// SetMemoryScanner lets re-embedding page through every memory of an agent;
+// without it only the first decay batch is re-embedded.
+func (s *AdminService) SetMemoryScanner(ms domain.MemoryScanner) {
+	s.scanner = ms
+}
+
+// SetVectorSyncer enables SyncVectors; set it when similarity search runs on
+// an external vector store.
+func (s *AdminService) SetVectorSyncer(vs domain.VectorSyncer) {
+	s.vectors = vs
+}
+
+// SetVectorQuantizer enables QuantizeVectors; set it when VECTOR_QUANTIZATION
+// is on.
+func (s *AdminService) SetVectorQuantizer(vq domain.VectorQuantizer) {
+	s.quantizer = vq
+}
+
+// SetDerivedRecomputer enables RecomputeDerived.
+func (s *AdminService) SetDerivedRecomputer(dr domain.DerivedFieldRecomputer) {
+	s.derived = dr
+}
+
+// SetTierTransitioner lets RecomputeDerived re-apply the tier policy; without
+// it tiers are left to the tier worker.
+func (s *AdminService) SetTierTransitioner(t AgentTierTransitioner) {
+	s.tiers = t
+}
+
 // adminMutation builds an audit row for an operator action. ContentHash is the
 // hash of the memory's content at the time of the action.
 func adminMutation(mem *domain.Memory, mtype domain.MutationType, reason, actorType string, actorID uuid.UUID) *domain.MutationLog {
@@ -102,7 +136,7 @@ func (s *AdminService) UpdateContent(ctx context.Context, memID, tenantID uuid.U
 	var embedding []float32
 	if s.embeddingClient != nil {
 		if emb, embErr := s.embeddingClient.Embed(ctx, content); embErr != nil {
-			s.logger.Warn("failed to re-embed corrected content; keeping prior embedding", zap.Error(embErr))
+			logFor(ctx, s.logger).Warn("failed to re-embed corrected content; keeping prior embedding", zap.Error(embErr))
 		} else {
 			embedding = emb
 		}
@@ -240,33 +274,142 @@ func (s *AdminService) ReembedAgent(ctx context.Context, agentID, tenantID uuid.
 	if s.embeddingClient == nil {
 		return 0, ErrReembedUnavailable
 	}
-	mems, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
-	if err != nil {
-		return 0, err
-	}
 	count := 0
-	for i := range mems {
-		m := &mems[i]
+	err := forEachLiveMemory(ctx, s.scanner, s.memoryStore, agentID, func(m *domain.Memory) error {
 		if m.TenantID != tenantID || m.Content == "" {
-			continue
+			return nil
 		}
 		vec, err := s.embeddingClient.Embed(ctx, m.Content)
 		if err != nil {
-			return count, fmt.Errorf("re-embed memory %s: %w", m.ID, err)
+			return fmt.Errorf("re-embed memory %s: %w", m.ID, err)
 		}
 		if expectedDim > 0 && len(vec) != expectedDim {
-			return count, fmt.Errorf("re-embed produced dimension %d, expected %d — the new model's width must match the schema; a different width needs a fresh database", len(vec), expectedDim)
+			return fmt.Errorf("re-embed produced dimension %d, expected %d — the new model's width must match the schema; a different width needs a fresh database", len(vec), expectedDim)
 		}
 		if err := s.memoryStore.UpdateContent(ctx, m.ID, m.Content, vec); err != nil {
-			return count, fmt.Errorf("update embedding for %s: %w", m.ID, err)
+			return fmt.Errorf("update embedding for %s: %w", m.ID, err)
 		}
 		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
 	}
-	s.logger.Info("re-embedded agent memories",
+	logFor(ctx, s.logger).Info("re-embedded agent memories",
 		zap.String("agent_id", agentID.String()), zap.Int("count", count))
 	return count, nil
 }
 
+// ErrNoVectorStore is returned by SyncVectors when search runs on pgvector.
+var ErrNoVectorStore = errors.New("no external vector store configured")
+
+// SyncVectors pushes every live embedding of an agent to the external vector
+// store, without re-embedding. Use it after pointing VECTOR_STORE at a new
+// store, or to repair writes that failed to mirror.
+func (s *AdminService) SyncVectors(ctx context.Context, agentID, tenantID uuid.UUID) (int, error) {
+	if s.vectors == nil {
+		return 0, ErrNoVectorStore
+	}
+	n, err := s.vectors.SyncVectors(ctx, agentID, tenantID)
+	if err != nil {
+		return n, fmt.Errorf("sync vectors: %w", err)
+	}
+	logFor(ctx, s.logger).Info("synced agent vectors",
+		zap.String("agent_id", agentID.String()), zap.Int("count", n))
+	return n, nil
+}
+
+// ErrQuantizationDisabled is returned by QuantizeVectors when
+// VECTOR_QUANTIZATION is off.
+var ErrQuantizationDisabled = errors.New("vector quantization is not enabled")
+
+// QuantizeVectors fills the quantized copy of every embedding of an agent
+// stored before VECTOR_QUANTIZATION was enabled, without re-embedding. Until
+// it runs, those memories are missed by the quantized shortlist.
+func (s *AdminService) QuantizeVectors(ctx context.Context, agentID, tenantID uuid.UUID) (int, error) {
+	if s.quantizer == nil {
+		return 0, ErrQuantizationDisabled
+	}
+	n, err := s.quantizer.QuantizeVectors(ctx, agentID, tenantID)
+	if err != nil {
+		return n, fmt.Errorf("quantize vectors: %w", err)
+	}
+	logFor(ctx, s.logger).Info("quantized agent vectors",
+		zap.String("agent_id", agentID.String()), zap.Int("count", n))
+	return n, nil
+}
+
+// AgentTierTransitioner moves an agent's memories to their policy tier.
+type AgentTierTransitioner interface {
+	TransitionAgent(ctx context.Context, agentID uuid.UUID) ([]domain.TierTransition, error)
+}
+
+var (
+	// ErrRecomputeUnavailable is returned by RecomputeDerived when no
+	// recomputer is configured.
+	ErrRecomputeUnavailable = errors.New("derived field recompute is not configured")
+	ErrUnknownDerivedField  = errors.New("unknown derived field")
+)
+
+// derivedRecomputeReason is the audit reason of confidence changes made by
+// RecomputeDerived.
+const derivedRecomputeReason = "recompute derived fields: confidence from evidence counts"
+
+// RecomputeDerived recomputes an agent's derived fields from the data they
+// derive from, for recovery after a bug or a manual database edit, and
+// returns how many rows each field changed. No fields means
+// domain.DefaultDerivedFields. Confidence runs only when named: it resets
+// each memory's confidence to the mean of its evidence counts, destroying
+// decay, feedback and usage boosts applied since; each change is audited and
+// tiers are recomputed after it.
+func (s *AdminService) RecomputeDerived(ctx context.Context, agentID, tenantID uuid.UUID, fields []domain.DerivedField, actorType string, actorID uuid.UUID) (map[domain.DerivedField]int, error) {
+	if s.derived == nil {
+		return nil, ErrRecomputeUnavailable
+	}
+	if len(fields) == 0 {
+		fields = domain.DefaultDerivedFields()
+	}
+	want := make(map[domain.DerivedField]bool, len(fields))
+	for _, f := range fields {
+		if !domain.ValidDerivedField(string(f)) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDerivedField, f)
+		}
+		want[f] = true
+	}
+
+	// Recompute in dependency order whatever order the fields were given in.
+	var stored []domain.DerivedField
+	for _, f := range domain.AllDerivedFields() {
+		if want[f] && f != domain.DerivedTiers {
+			stored = append(stored, f)
+		}
+	}
+	counts, err := s.derived.RecomputeDerived(ctx, agentID, tenantID, stored, domain.DerivedAudit{
+		Reason: derivedRecomputeReason, ActorType: actorType, ActorID: actorID,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+	if want[domain.DerivedTiers] && s.tiers != nil {
+		transitions, err := s.tiers.TransitionAgent(ctx, agentID)
+		if err != nil {
+			return counts, fmt.Errorf("recompute tiers: %w", err)
+		}
+		counts[domain.DerivedTiers] = len(transitions)
+	}
+
+	logFields := make([]zap.Field, 0, len(counts)+1)
+	logFields = append(logFields, zap.String("agent_id", agentID.String()))
+	for f, n := range counts {
+		logFields = append(logFields, zap.Int(string(f), n))
+	}
+	logFor(ctx, s.logger).Info("recomputed derived fields", logFields...)
+	return counts, nil
+}
+
 // ResolveContradiction manually settles a contradiction: the demoted belief is
 // archived, the kept belief's review flag is cleared, and the action is audited.
 func (s *AdminService) ResolveContradiction(ctx context.Context, tenantID, keepID, demoteID uuid.UUID, reason, actorType string, actorID uuid.UUID) error {
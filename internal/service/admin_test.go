@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDerivedRecomputer struct {
+	fields []domain.DerivedField
+	audit  domain.DerivedAudit
+	err    error
+}
+
+func (f *fakeDerivedRecomputer) RecomputeDerived(_ context.Context, _, _ uuid.UUID, fields []domain.DerivedField, audit domain.DerivedAudit) (map[domain.DerivedField]int, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.fields, f.audit = fields, audit
+	counts := make(map[domain.DerivedField]int, len(fields))
+	for _, field := range fields {
+		counts[field] = 1
+	}
+	return counts, nil
+}
+
+type fakeTierTransitioner struct{ calls int }
+
+func (f *fakeTierTransitioner) TransitionAgent(context.Context, uuid.UUID) ([]domain.TierTransition, error) {
+	f.calls++
+	return make([]domain.TierTransition, 3), nil
+}
+
+func TestAdminService_RecomputeDerived(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID, actorID := uuid.New(), uuid.New(), uuid.New()
+
+	svc := NewAdminService(newMockMemoryStore(), nil, nil, testLogger())
+	_, err := svc.RecomputeDerived(ctx, agentID, tenantID, nil, "api_key", actorID)
+	assert.ErrorIs(t, err, ErrRecomputeUnavailable)
+
+	derived := &fakeDerivedRecomputer{}
+	tiers := &fakeTierTransitioner{}
+	svc.SetDerivedRecomputer(derived)
+	svc.SetTierTransitioner(tiers)
+
+	t.Run("default fields leave confidence alone, tiers through the tier policy", func(t *testing.T) {
+		counts, err := svc.RecomputeDerived(ctx, agentID, tenantID, nil, "api_key", actorID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.DerivedField{
+			domain.DerivedSuccessRate, domain.DerivedEvidenceCount, domain.DerivedAssociations,
+		}, derived.fields)
+		assert.Equal(t, 1, tiers.calls)
+		assert.Equal(t, 3, counts[domain.DerivedTiers])
+		assert.NotContains(t, counts, domain.DerivedConfidence)
+	})
+
+	t.Run("listed fields in dependency order", func(t *testing.T) {
+		tiers.calls = 0
+		counts, err := svc.RecomputeDerived(ctx, agentID, tenantID,
+			[]domain.DerivedField{domain.DerivedAssociations, domain.DerivedConfidence}, "api_key", actorID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.DerivedField{domain.DerivedConfidence, domain.DerivedAssociations}, derived.fields)
+		assert.Equal(t, actorID, derived.audit.ActorID)
+		assert.NotEmpty(t, derived.audit.Reason)
+		assert.Zero(t, tiers.calls)
+		assert.NotContains(t, counts, domain.DerivedTiers)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := svc.RecomputeDerived(ctx, agentID, tenantID, []domain.DerivedField{"use_count"}, "api_key", actorID)
+		assert.ErrorIs(t, err, ErrUnknownDerivedField)
+	})
+
+	t.Run("agent outside the tenant", func(t *testing.T) {
+		derived.err = store.ErrNotFound
+		_, err := svc.RecomputeDerived(ctx, agentID, tenantID, nil, "api_key", actorID)
+		assert.ErrorIs(t, err, ErrAgentNotFound)
+	})
+}
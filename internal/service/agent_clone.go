@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCloneTargetInvalid   = errors.New("target_api_key is not a valid API key")
+	ErrCloneTargetForbidden = errors.New("target_api_key lacks the write scope")
+	ErrAgentQuotaExceeded   = errors.New("target tenant has reached its agent limit")
+)
+
+// cloneMemoryPageSize is how many memories are read per page while cloning.
+const cloneMemoryPageSize = 200
+
+// AgentCloneService copies a tuned agent's schemas, procedures and optionally
+// its private semantic memories into a new agent. Episodes are never copied:
+// they are the source agent's own experience, not its persona.
+type AgentCloneService struct {
+	agentStore      domain.AgentStore
+	schemaStore     domain.SchemaStore
+	procedureStore  domain.ProcedureStore
+	memoryStore     domain.MemoryStore
+	embeddingClient domain.EmbeddingClient
+	apiKeys         domain.APIKeyStore
+	billing         domain.BillingStore
+	billingEnabled  bool
+	logger          *zap.Logger
+}
+
+// NewAgentCloneService creates a new agent clone service.
+func NewAgentCloneService(
+	agentStore domain.AgentStore,
+	schemaStore domain.SchemaStore,
+	procedureStore domain.ProcedureStore,
+	memoryStore domain.MemoryStore,
+	embeddingClient domain.EmbeddingClient,
+	apiKeys domain.APIKeyStore,
+	logger *zap.Logger,
+) *AgentCloneService {
+	return &AgentCloneService{
+		agentStore:      agentStore,
+		schemaStore:     schemaStore,
+		procedureStore:  procedureStore,
+		memoryStore:     memoryStore,
+		embeddingClient: embeddingClient,
+		apiKeys:         apiKeys,
+		logger:          logger,
+	}
+}
+
+// SetBilling enforces the target tenant's agent limit on cross-tenant clones.
+// Same-tenant clones are covered by the route's quota middleware.
+func (s *AgentCloneService) SetBilling(billing domain.BillingStore, enabled bool) {
+	s.billing = billing
+	s.billingEnabled = enabled
+}
+
+// CloneInput describes the agent to create. TargetAPIKey, when set, is a raw
+// write-scoped key of the tenant that receives the clone; otherwise the clone
+// stays in the source tenant.
+type CloneInput struct {
+	SourceID        uuid.UUID
+	TenantID        uuid.UUID
+	ExternalID      string
+	Name            string
+	Metadata        map[string]any
+	IncludeMemories bool
+	TargetAPIKey    string
+}
+
+// CloneResult is the new agent and what was copied into it.
+type CloneResult struct {
+	Agent      domain.Agent `json:"agent"`
+	Schemas    int          `json:"schemas"`
+	Procedures int          `json:"procedures"`
+	Memories   int          `json:"memories"`
+}
+
+// Clone creates the new agent and copies the source's knowledge into it.
+// Copies are re-embedded, keep their confidence and usage statistics, and
+// schema hierarchy is preserved. Schema evidence points at the copied memories
+// when memories are cloned and is dropped otherwise. A failure part-way leaves
+// the partly filled agent in place; delete it before retrying.
+func (s *AgentCloneService) Clone(ctx context.Context, input CloneInput) (*CloneResult, error) {
+	source, err := s.agentStore.GetByID(ctx, input.SourceID, input.TenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+
+	targetTenant, err := s.resolveTargetTenant(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]any, len(source.Metadata)+len(input.Metadata)+1)
+	for k, v := range source.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range input.Metadata {
+		metadata[k] = v
+	}
+	metadata["cloned_from"] = source.ID.String()
+
+	name := input.Name
+	if name == "" {
+		name = source.Name
+	}
+	clone := &domain.Agent{
+		TenantID:   targetTenant,
+		ExternalID: input.ExternalID,
+		Name:       name,
+		Metadata:   metadata,
+	}
+	if clone.ExternalID == "" {
+		clone.ExternalID = generateExternalID(name)
+	}
+	if err := s.agentStore.Create(ctx, clone); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return nil, ErrAgentConflict
+		}
+		return nil, err
+	}
+
+	result := &CloneResult{Agent: *clone}
+
+	memoryIDs := make(map[uuid.UUID]uuid.UUID)
+	if input.IncludeMemories {
+		if err := s.cloneMemories(ctx, source, clone, memoryIDs); err != nil {
+			return nil, err
+		}
+		result.Memories = len(memoryIDs)
+	}
+
+	if result.Schemas, err = s.cloneSchemas(ctx, source, clone, memoryIDs); err != nil {
+		return nil, err
+	}
+	if result.Procedures, err = s.cloneProcedures(ctx, source, clone); err != nil {
+		return nil, err
+	}
+
+	logFor(ctx, s.logger).Info("cloned agent",
+		zap.String("source_agent_id", source.ID.String()),
+		zap.String("agent_id", clone.ID.String()),
+		zap.Bool("cross_tenant", targetTenant != input.TenantID),
+		zap.Int("schemas", result.Schemas),
+		zap.Int("procedures", result.Procedures),
+		zap.Int("memories", result.Memories))
+
+	return result, nil
+}
+
+// resolveTargetTenant returns the tenant that receives the clone, proving
+// authority over another tenant through one of its write-scoped API keys.
+func (s *AgentCloneService) resolveTargetTenant(ctx context.Context, input CloneInput) (uuid.UUID, error) {
+	if input.TargetAPIKey == "" {
+		return input.TenantID, nil
+	}
+	auth, err := s.apiKeys.GetAuthByHash(ctx, hashToken(input.TargetAPIKey))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return uuid.Nil, ErrCloneTargetInvalid
+		}
+		return uuid.Nil, err
+	}
+	if !auth.HasScope(domain.ScopeWrite) {
+		return uuid.Nil, ErrCloneTargetForbidden
+	}
+	target := auth.Tenant.ID
+	if target != input.TenantID && s.billingEnabled && s.billing != nil {
+		if err := s.checkAgentQuota(ctx, target); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return target, nil
+}
+
+func (s *AgentCloneService) checkAgentQuota(ctx context.Context, tenantID uuid.UUID) error {
+	b, err := s.billing.GetBilling(ctx, tenantID)
+	if err != nil {
+		return nil
+	}
+	limits := domain.LimitsFor(b.Plan)
+	if limits.MaxAgents == domain.Unlimited {
+		return nil
+	}
+	count, err := s.billing.CountAgents(ctx, tenantID)
+	if err == nil && count >= limits.MaxAgents {
+		return ErrAgentQuotaExceeded
+	}
+	return nil
+}
+
+// cloneMemories copies the source's active private memories, recording the
+// old-to-new ID mapping. Anchored, session and canon memories belong to their
+// subject or tenant rather than the agent, so they are left behind.
+func (s *AgentCloneService) cloneMemories(ctx context.Context, source, clone *domain.Agent, ids map[uuid.UUID]uuid.UUID) error {
+	filter := domain.MemoryFilter{Binding: string(domain.BindingPrivate)}
+	for offset := 0; ; offset += cloneMemoryPageSize {
+		page, total, err := s.memoryStore.ListByAgentFiltered(ctx, source.ID, source.TenantID, filter, cloneMemoryPageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, m := range page {
+			metadata := make(map[string]any, len(m.Metadata)+1)
+			for k, v := range m.Metadata {
+				metadata[k] = v
+			}
+			metadata["cloned_from"] = m.ID.String()
+
+			copied := &domain.Memory{
+				AgentID:            clone.ID,
+				TenantID:           clone.TenantID,
+				Type:               m.Type,
+				Content:            m.Content,
+				Embedding:          s.embed(ctx, m.Content),
+				Source:             m.Source,
+				Provenance:         m.Provenance,
+				Confidence:         m.Confidence,
+				Metadata:           metadata,
+				ExpiresAt:          m.ExpiresAt,
+				ReinforcementCount: m.ReinforcementCount,
+				DecayRate:          m.DecayRate,
+				Topic:              m.Topic,
+			}
+			if err := s.memoryStore.Create(ctx, copied); err != nil {
+				return err
+			}
+			ids[m.ID] = copied.ID
+		}
+		if len(page) == 0 || offset+len(page) >= total {
+			return nil
+		}
+	}
+}
+
+// cloneSchemas copies every schema, then re-links the hierarchy once all the
+// new IDs are known.
+func (s *AgentCloneService) cloneSchemas(ctx context.Context, source, clone *domain.Agent, memoryIDs map[uuid.UUID]uuid.UUID) (int, error) {
+	schemas, err := s.schemaStore.GetByAgent(ctx, source.ID, source.TenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make(map[uuid.UUID]uuid.UUID, len(schemas))
+	for _, sc := range schemas {
+		evidence := []uuid.UUID{}
+		for _, mid := range sc.EvidenceMemories {
+			if id, ok := memoryIDs[mid]; ok {
+				evidence = append(evidence, id)
+			}
+		}
+		copied := &domain.Schema{
+			AgentID:            clone.ID,
+			TenantID:           clone.TenantID,
+			SchemaType:         sc.SchemaType,
+			Name:               sc.Name,
+			Description:        sc.Description,
+			Attributes:         sc.Attributes,
+			EvidenceMemories:   evidence,
+			EvidenceEpisodes:   []uuid.UUID{},
+			EvidenceCount:      len(evidence),
+			Confidence:         sc.Confidence,
+			LastValidatedAt:    sc.LastValidatedAt,
+			ApplicableContexts: sc.ApplicableContexts,
+			Embedding:          s.embed(ctx, sc.Name+": "+sc.Description),
+		}
+		if err := s.schemaStore.Create(ctx, copied); err != nil {
+			return 0, err
+		}
+		ids[sc.ID] = copied.ID
+	}
+
+	for _, sc := range schemas {
+		if sc.ParentID == nil {
+			continue
+		}
+		parent, ok := ids[*sc.ParentID]
+		if !ok {
+			continue
+		}
+		if err := s.schemaStore.SetParent(ctx, ids[sc.ID], clone.TenantID, &parent); err != nil {
+			return 0, err
+		}
+	}
+	return len(schemas), nil
+}
+
+// cloneProcedures copies every procedure with its usage statistics, so the
+// clone inherits the source's sense of what works.
+func (s *AgentCloneService) cloneProcedures(ctx context.Context, source, clone *domain.Agent) (int, error) {
+	procedures, err := s.procedureStore.GetByAgent(ctx, source.ID, source.TenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range procedures {
+		copied := &domain.Procedure{
+			AgentID:             clone.ID,
+			TenantID:            clone.TenantID,
+			TriggerPattern:      p.TriggerPattern,
+			TriggerKeywords:     p.TriggerKeywords,
+			TriggerEmbedding:    s.embed(ctx, p.TriggerPattern),
+			Conditions:          p.Conditions,
+			Source:              p.Source,
+			SourcePack:          p.SourcePack,
+			ActionTemplate:      p.ActionTemplate,
+			ActionType:          p.ActionType,
+			UseCount:            p.UseCount,
+			SuccessCount:        p.SuccessCount,
+			FailureCount:        p.FailureCount,
+			LastUsedAt:          p.LastUsedAt,
+			DerivedFromEpisodes: []uuid.UUID{},
+			ExampleExchanges:    p.ExampleExchanges,
+			Confidence:          p.Confidence,
+			MemoryStrength:      p.MemoryStrength,
+			LastVerifiedAt:      p.LastVerifiedAt,
+		}
+		if err := s.procedureStore.Create(ctx, copied); err != nil {
+			return 0, err
+		}
+	}
+	return len(procedures), nil
+}
+
+// embed returns the embedding for text, or nil when embeddings are unavailable;
+// an unembedded copy is still stored, it just won't match similarity searches.
+func (s *AgentCloneService) embed(ctx context.Context, text string) []float32 {
+	if s.embeddingClient == nil {
+		return nil
+	}
+	emb, err := s.embeddingClient.Embed(ctx, text)
+	if err != nil {
+		logFor(ctx, s.logger).Warn("embedding generation failed while cloning", zap.Error(err))
+		return nil
+	}
+	return emb
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+)
+
+// cloneMemoryStore pages through the embedded mock's memories so cloning can
+// list them; the shared mock's ListByAgentFiltered is a stub.
+type cloneMemoryStore struct {
+	*mockMemoryStore
+}
+
+func (m *cloneMemoryStore) ListByAgentFiltered(ctx context.Context, agentID, tenantID uuid.UUID, f domain.MemoryFilter, limit, offset int) ([]domain.Memory, int, error) {
+	var matched []domain.Memory
+	for _, mem := range m.memories {
+		binding := mem.Binding
+		if binding == "" {
+			binding = domain.BindingPrivate
+		}
+		if mem.AgentID == agentID && mem.TenantID == tenantID && (f.Binding == "" || string(binding) == f.Binding) {
+			matched = append(matched, *mem)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Content < matched[j].Content })
+	total := len(matched)
+	if offset >= total {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return matched[offset:end], total, nil
+}
+
+type fakeAPIKeyStore struct {
+	byHash map[string]*domain.APIKeyAuth
+}
+
+func (f *fakeAPIKeyStore) Create(ctx context.Context, k *domain.APIKey) error { return nil }
+
+func (f *fakeAPIKeyStore) GetAuthByHash(ctx context.Context, hash string) (*domain.APIKeyAuth, error) {
+	auth, ok := f.byHash[hash]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return auth, nil
+}
+
+func (f *fakeAPIKeyStore) ListByTenantID(ctx context.Context, tenantID uuid.UUID) ([]domain.APIKey, error) {
+	return nil, nil
+}
+
+func (f *fakeAPIKeyStore) Revoke(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeAPIKeyStore) UpdateLastUsed(ctx context.Context, id uuid.UUID) error { return nil }
+
+type cloneFixture struct {
+	svc        *AgentCloneService
+	agents     *mockAgentStore
+	schemas    *mockSchemaStore
+	procedures *mockProcedureStore
+	memories   *cloneMemoryStore
+	keys       *fakeAPIKeyStore
+	source     *domain.Agent
+	remembered *domain.Memory
+}
+
+func newCloneFixture(t *testing.T) *cloneFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &cloneFixture{
+		agents:     newMockAgentStore(),
+		schemas:    newMockSchemaStore(),
+		procedures: newMockProcedureStore(),
+		memories:   &cloneMemoryStore{newMockMemoryStore()},
+		keys:       &fakeAPIKeyStore{byHash: make(map[string]*domain.APIKeyAuth)},
+	}
+	f.svc = NewAgentCloneService(f.agents, f.schemas, f.procedures, f.memories, &mockEmbeddingClient{}, f.keys, testLogger())
+
+	f.source = &domain.Agent{TenantID: uuid.New(), ExternalID: "support-tuned", Name: "Support", Metadata: map[string]any{"persona": "calm"}}
+	_ = f.agents.Create(ctx, f.source)
+
+	f.remembered = &domain.Memory{AgentID: f.source.ID, TenantID: f.source.TenantID, Type: domain.MemoryTypePreference, Content: "Users prefer short answers", Confidence: 0.8}
+	_ = f.memories.Create(ctx, f.remembered)
+	anchored := &domain.Memory{AgentID: f.source.ID, TenantID: f.source.TenantID, Content: "Alice is on the Pro plan", Binding: domain.BindingAnchored}
+	_ = f.memories.Create(ctx, anchored)
+
+	parent := &domain.Schema{AgentID: f.source.ID, TenantID: f.source.TenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: "Developer", Confidence: 0.7}
+	_ = f.schemas.Create(ctx, parent)
+	child := &domain.Schema{
+		AgentID: f.source.ID, TenantID: f.source.TenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: "Impatient debugger",
+		ParentID: &parent.ID, EvidenceMemories: []uuid.UUID{f.remembered.ID}, EvidenceEpisodes: []uuid.UUID{uuid.New()}, Confidence: 0.6,
+	}
+	_ = f.schemas.Create(ctx, child)
+
+	_ = f.procedures.Create(ctx, &domain.Procedure{
+		AgentID: f.source.ID, TenantID: f.source.TenantID, TriggerPattern: "failed deploy", ActionTemplate: "Ask for the deploy ID",
+		ActionType: domain.ActionTypeProblemSolving, UseCount: 4, SuccessCount: 3, DerivedFromEpisodes: []uuid.UUID{uuid.New()}, Confidence: 0.9,
+	})
+	return f
+}
+
+func (f *cloneFixture) schemasOf(agentID uuid.UUID) map[string]domain.Schema {
+	byName := make(map[string]domain.Schema)
+	for _, s := range f.schemas.schemas {
+		if s.AgentID == agentID {
+			byName[s.Name] = *s
+		}
+	}
+	return byName
+}
+
+func TestAgentCloneService_CloneWithMemories(t *testing.T) {
+	f := newCloneFixture(t)
+
+	result, err := f.svc.Clone(context.Background(), CloneInput{
+		SourceID:        f.source.ID,
+		TenantID:        f.source.TenantID,
+		ExternalID:      "support-eu",
+		Metadata:        map[string]any{"region": "eu"},
+		IncludeMemories: true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Schemas != 2 || result.Procedures != 1 || result.Memories != 1 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+
+	clone := result.Agent
+	if clone.TenantID != f.source.TenantID || clone.Name != "Support" || clone.ExternalID != "support-eu" {
+		t.Fatalf("unexpected clone agent: %+v", clone)
+	}
+	if clone.Metadata["persona"] != "calm" || clone.Metadata["region"] != "eu" || clone.Metadata["cloned_from"] != f.source.ID.String() {
+		t.Fatalf("unexpected clone metadata: %v", clone.Metadata)
+	}
+
+	var copiedMemory *domain.Memory
+	for _, m := range f.memories.memories {
+		if m.AgentID == clone.ID {
+			if copiedMemory != nil {
+				t.Fatal("expected only the private memory to be copied")
+			}
+			copiedMemory = m
+		}
+	}
+	if copiedMemory == nil || copiedMemory.Content != f.remembered.Content || copiedMemory.Confidence != 0.8 || len(copiedMemory.Embedding) == 0 {
+		t.Fatalf("unexpected copied memory: %+v", copiedMemory)
+	}
+
+	schemas := f.schemasOf(clone.ID)
+	child, parent := schemas["Impatient debugger"], schemas["Developer"]
+	if child.ParentID == nil || *child.ParentID != parent.ID {
+		t.Fatal("expected the schema hierarchy to point at the cloned parent")
+	}
+	if len(child.EvidenceMemories) != 1 || child.EvidenceMemories[0] != copiedMemory.ID || len(child.EvidenceEpisodes) != 0 {
+		t.Fatalf("expected evidence remapped to the copied memory and episodes dropped, got %v / %v", child.EvidenceMemories, child.EvidenceEpisodes)
+	}
+
+	procedures, _ := f.procedures.GetByAgent(context.Background(), clone.ID, clone.TenantID)
+	if len(procedures) != 1 || procedures[0].SuccessCount != 3 || len(procedures[0].DerivedFromEpisodes) != 0 {
+		t.Fatalf("unexpected cloned procedures: %+v", procedures)
+	}
+}
+
+func TestAgentCloneService_CloneWithoutMemories(t *testing.T) {
+	f := newCloneFixture(t)
+
+	result, err := f.svc.Clone(context.Background(), CloneInput{SourceID: f.source.ID, TenantID: f.source.TenantID, ExternalID: "support-2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Memories != 0 || len(f.memories.memories) != 2 {
+		t.Fatal("expected no memories to be copied")
+	}
+	if child := f.schemasOf(result.Agent.ID)["Impatient debugger"]; len(child.EvidenceMemories) != 0 || child.EvidenceCount != 0 {
+		t.Fatalf("expected evidence to be dropped, got %v", child.EvidenceMemories)
+	}
+}
+
+func TestAgentCloneService_CrossTenant(t *testing.T) {
+	f := newCloneFixture(t)
+	ctx := context.Background()
+	target := &domain.Tenant{ID: uuid.New()}
+	f.keys.byHash[hashToken("target-write")] = &domain.APIKeyAuth{Tenant: target, Scopes: []string{domain.ScopeWrite}}
+	f.keys.byHash[hashToken("target-read")] = &domain.APIKeyAuth{Tenant: target, Scopes: []string{domain.ScopeRead}}
+
+	result, err := f.svc.Clone(ctx, CloneInput{SourceID: f.source.ID, TenantID: f.source.TenantID, TargetAPIKey: "target-write", IncludeMemories: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Agent.TenantID != target.ID {
+		t.Fatalf("expected the clone in tenant %s, got %s", target.ID, result.Agent.TenantID)
+	}
+	if len(f.schemasOf(result.Agent.ID)) != 2 {
+		t.Fatal("expected schemas in the target tenant")
+	}
+	for _, s := range f.schemasOf(result.Agent.ID) {
+		if s.TenantID != target.ID {
+			t.Fatal("expected cloned schemas to belong to the target tenant")
+		}
+	}
+
+	if _, err := f.svc.Clone(ctx, CloneInput{SourceID: f.source.ID, TenantID: f.source.TenantID, TargetAPIKey: "target-read"}); !errors.Is(err, ErrCloneTargetForbidden) {
+		t.Fatalf("expected ErrCloneTargetForbidden, got %v", err)
+	}
+	if _, err := f.svc.Clone(ctx, CloneInput{SourceID: f.source.ID, TenantID: f.source.TenantID, TargetAPIKey: "nope"}); !errors.Is(err, ErrCloneTargetInvalid) {
+		t.Fatalf("expected ErrCloneTargetInvalid, got %v", err)
+	}
+}
+
+func TestAgentCloneService_Errors(t *testing.T) {
+	f := newCloneFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.svc.Clone(ctx, CloneInput{SourceID: uuid.New(), TenantID: f.source.TenantID}); !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("expected ErrAgentNotFound, got %v", err)
+	}
+	if _, err := f.svc.Clone(ctx, CloneInput{SourceID: f.source.ID, TenantID: f.source.TenantID, ExternalID: "support-tuned"}); !errors.Is(err, ErrAgentConflict) {
+		t.Fatalf("expected ErrAgentConflict, got %v", err)
+	}
+}
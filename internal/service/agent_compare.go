@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// CompareMaxBeliefs is the most beliefs read per side, highest confidence first.
+	CompareMaxBeliefs = 500
+	// compareSampleSize bounds each list of example beliefs in a comparison.
+	compareSampleSize = 20
+	// schemaConfidenceTolerance is the confidence change below which a schema
+	// present on both sides is not reported as changed.
+	schemaConfidenceTolerance = 0.05
+	confidenceHistogramBins   = 10
+)
+
+var ErrCompareSameSide = errors.New("both sides name the same agent at the same instant")
+
+// AgentCompareService reports how two agents, or one agent at two instants,
+// differ in beliefs, schemas and confidence — e.g. to catch drift between a
+// production agent and its staging copy.
+type AgentCompareService struct {
+	agentStore  domain.AgentStore
+	memoryStore domain.MemoryStore
+	schemaStore domain.SchemaStore
+	logger      *zap.Logger
+}
+
+// NewAgentCompareService creates a new agent compare service.
+func NewAgentCompareService(agentStore domain.AgentStore, memoryStore domain.MemoryStore, schemaStore domain.SchemaStore, logger *zap.Logger) *AgentCompareService {
+	return &AgentCompareService{agentStore: agentStore, memoryStore: memoryStore, schemaStore: schemaStore, logger: logger}
+}
+
+// CompareSide is one side of a comparison: an agent as of an instant.
+type CompareSide struct {
+	AgentID uuid.UUID
+	At      time.Time
+}
+
+// CompareInput names the two sides. Limit caps the beliefs read per side and
+// defaults to CompareMaxBeliefs.
+type CompareInput struct {
+	TenantID uuid.UUID
+	A        CompareSide
+	B        CompareSide
+	Limit    int
+}
+
+// AgentComparison is the drift report. Deltas are B minus A.
+type AgentComparison struct {
+	A          ComparisonSide  `json:"a"`
+	B          ComparisonSide  `json:"b"`
+	Beliefs    BeliefOverlap   `json:"beliefs"`
+	Schemas    SchemaDiff      `json:"schemas"`
+	Confidence ConfidenceDrift `json:"confidence"`
+	// DriftScore in [0,1] averages belief non-overlap, the mean confidence
+	// change of shared beliefs and the confidence distribution distance.
+	DriftScore float64 `json:"drift_score"`
+}
+
+// ComparisonSide summarises one side's beliefs.
+type ComparisonSide struct {
+	AgentID      uuid.UUID              `json:"agent_id"`
+	AsOf         time.Time              `json:"as_of"`
+	TotalBeliefs int                    `json:"total_beliefs"`
+	Compared     int                    `json:"compared"`
+	Distribution ConfidenceDistribution `json:"distribution"`
+}
+
+// ConfidenceDistribution describes the confidences of the compared beliefs.
+// Histogram has ten 0.1-wide bins from 0 to 1.
+type ConfidenceDistribution struct {
+	Mean      float64                   `json:"mean"`
+	Median    float64                   `json:"median"`
+	Tiers     map[domain.MemoryTier]int `json:"tiers"`
+	Histogram []int                     `json:"histogram"`
+}
+
+// BeliefOverlap matches beliefs by ID for one agent and by normalised content
+// across agents.
+type BeliefOverlap struct {
+	Shared                 int                   `json:"shared"`
+	OnlyInA                int                   `json:"only_in_a"`
+	OnlyInB                int                   `json:"only_in_b"`
+	Jaccard                float64               `json:"jaccard"`
+	MeanConfidenceDelta    float64               `json:"mean_confidence_delta"`
+	MeanAbsConfidenceDelta float64               `json:"mean_abs_confidence_delta"`
+	OnlyInASample          []domain.BeliefAtTime `json:"only_in_a_sample"`
+	OnlyInBSample          []domain.BeliefAtTime `json:"only_in_b_sample"`
+	Shifted                []BeliefShift         `json:"shifted"`
+}
+
+// BeliefShift is a shared belief whose confidence differs between the sides.
+type BeliefShift struct {
+	Content     string  `json:"content"`
+	AConfidence float32 `json:"a_confidence"`
+	BConfidence float32 `json:"b_confidence"`
+	Delta       float32 `json:"delta"`
+}
+
+// SchemaDiff matches schemas by type and name. Schemas keep no history, so a
+// side in the past sees the schemas that existed then with their current
+// values; comparing one agent with itself shows only schemas added since.
+type SchemaDiff struct {
+	OnlyInA []SchemaRef    `json:"only_in_a"`
+	OnlyInB []SchemaRef    `json:"only_in_b"`
+	Changed []SchemaChange `json:"changed"`
+}
+
+// SchemaRef identifies a schema in a comparison.
+type SchemaRef struct {
+	SchemaType domain.SchemaType `json:"schema_type"`
+	Name       string            `json:"name"`
+	Confidence float32           `json:"confidence"`
+}
+
+// SchemaChange is a schema on both sides whose confidence or attributes differ.
+type SchemaChange struct {
+	SchemaType        domain.SchemaType `json:"schema_type"`
+	Name              string            `json:"name"`
+	AConfidence       float32           `json:"a_confidence"`
+	BConfidence       float32           `json:"b_confidence"`
+	ChangedAttributes []string          `json:"changed_attributes,omitempty"`
+}
+
+// ConfidenceDrift compares the two confidence distributions. HistogramDistance
+// is the total variation distance between the normalised histograms.
+type ConfidenceDrift struct {
+	MeanShift         float64 `json:"mean_shift"`
+	HistogramDistance float64 `json:"histogram_distance"`
+}
+
+// Compare builds the drift report for the two sides.
+func (s *AgentCompareService) Compare(ctx context.Context, input CompareInput) (*AgentComparison, error) {
+	if input.A.AgentID == input.B.AgentID && input.A.At.Equal(input.B.At) {
+		return nil, ErrCompareSameSide
+	}
+	if input.Limit <= 0 || input.Limit > CompareMaxBeliefs {
+		input.Limit = CompareMaxBeliefs
+	}
+
+	for _, id := range []uuid.UUID{input.A.AgentID, input.B.AgentID} {
+		if _, err := s.agentStore.GetByID(ctx, id, input.TenantID); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, ErrAgentNotFound
+			}
+			return nil, err
+		}
+	}
+
+	beliefsA, totalA, err := s.memoryStore.BeliefsAsOf(ctx, input.A.AgentID, input.TenantID, input.A.At, input.Limit)
+	if err != nil {
+		return nil, err
+	}
+	beliefsB, totalB, err := s.memoryStore.BeliefsAsOf(ctx, input.B.AgentID, input.TenantID, input.B.At, input.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	schemasA, err := s.schemasAsOf(ctx, input.A, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	schemasB, err := s.schemasAsOf(ctx, input.B, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	distA := confidenceDistribution(beliefsA)
+	distB := confidenceDistribution(beliefsB)
+	byID := input.A.AgentID == input.B.AgentID
+	overlap := compareBeliefs(beliefsA, beliefsB, byID)
+
+	report := &AgentComparison{
+		A:       ComparisonSide{AgentID: input.A.AgentID, AsOf: input.A.At, TotalBeliefs: totalA, Compared: len(beliefsA), Distribution: distA},
+		B:       ComparisonSide{AgentID: input.B.AgentID, AsOf: input.B.At, TotalBeliefs: totalB, Compared: len(beliefsB), Distribution: distB},
+		Beliefs: overlap,
+		Schemas: compareSchemas(schemasA, schemasB),
+		Confidence: ConfidenceDrift{
+			MeanShift:         distB.Mean - distA.Mean,
+			HistogramDistance: histogramDistance(distA.Histogram, distB.Histogram),
+		},
+	}
+	nonOverlap := 0.0
+	if overlap.Shared+overlap.OnlyInA+overlap.OnlyInB > 0 {
+		nonOverlap = 1 - overlap.Jaccard
+	}
+	report.DriftScore = (nonOverlap + overlap.MeanAbsConfidenceDelta + report.Confidence.HistogramDistance) / 3
+
+	logFor(ctx, s.logger).Debug("compared agents",
+		zap.String("a", input.A.AgentID.String()),
+		zap.String("b", input.B.AgentID.String()),
+		zap.Float64("drift_score", report.DriftScore))
+
+	return report, nil
+}
+
+// schemasAsOf returns the side's schemas that existed at its instant.
+func (s *AgentCompareService) schemasAsOf(ctx context.Context, side CompareSide, tenantID uuid.UUID) ([]domain.Schema, error) {
+	schemas, err := s.schemaStore.GetByAgent(ctx, side.AgentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]domain.Schema, 0, len(schemas))
+	for _, sc := range schemas {
+		if !sc.CreatedAt.After(side.At) {
+			existing = append(existing, sc)
+		}
+	}
+	return existing, nil
+}
+
+// beliefKey matches beliefs across sides: by ID within one agent, otherwise by
+// case- and whitespace-insensitive content.
+func beliefKey(b domain.BeliefAtTime, byID bool) string {
+	if byID {
+		return b.ID.String()
+	}
+	return strings.Join(strings.Fields(strings.ToLower(b.Content)), " ")
+}
+
+func compareBeliefs(a, b []domain.BeliefAtTime, byID bool) BeliefOverlap {
+	overlap := BeliefOverlap{
+		OnlyInASample: []domain.BeliefAtTime{},
+		OnlyInBSample: []domain.BeliefAtTime{},
+		Shifted:       []BeliefShift{},
+	}
+
+	inA := make(map[string]domain.BeliefAtTime, len(a))
+	for _, belief := range a {
+		inA[beliefKey(belief, byID)] = belief
+	}
+
+	matched := make(map[string]bool, len(b))
+	var sumDelta, sumAbs float64
+	for _, belief := range b {
+		key := beliefKey(belief, byID)
+		if matched[key] {
+			continue
+		}
+		prev, ok := inA[key]
+		if !ok {
+			overlap.OnlyInB++
+			if len(overlap.OnlyInBSample) < compareSampleSize {
+				overlap.OnlyInBSample = append(overlap.OnlyInBSample, belief)
+			}
+			continue
+		}
+		matched[key] = true
+		overlap.Shared++
+		delta := belief.Confidence - prev.Confidence
+		sumDelta += float64(delta)
+		sumAbs += math.Abs(float64(delta))
+		if delta != 0 {
+			overlap.Shifted = append(overlap.Shifted, BeliefShift{
+				Content:     belief.Content,
+				AConfidence: prev.Confidence,
+				BConfidence: belief.Confidence,
+				Delta:       delta,
+			})
+		}
+	}
+	for _, belief := range a {
+		if matched[beliefKey(belief, byID)] {
+			continue
+		}
+		overlap.OnlyInA++
+		if len(overlap.OnlyInASample) < compareSampleSize {
+			overlap.OnlyInASample = append(overlap.OnlyInASample, belief)
+		}
+	}
+
+	if union := overlap.Shared + overlap.OnlyInA + overlap.OnlyInB; union > 0 {
+		overlap.Jaccard = float64(overlap.Shared) / float64(union)
+	}
+	if overlap.Shared > 0 {
+		overlap.MeanConfidenceDelta = sumDelta / float64(overlap.Shared)
+		overlap.MeanAbsConfidenceDelta = sumAbs / float64(overlap.Shared)
+	}
+
+	sort.Slice(overlap.Shifted, func(i, j int) bool {
+		return math.Abs(float64(overlap.Shifted[i].Delta)) > math.Abs(float64(overlap.Shifted[j].Delta))
+	})
+	if len(overlap.Shifted) > compareSampleSize {
+		overlap.Shifted = overlap.Shifted[:compareSampleSize]
+	}
+	return overlap
+}
+
+func compareSchemas(a, b []domain.Schema) SchemaDiff {
+	diff := SchemaDiff{OnlyInA: []SchemaRef{}, OnlyInB: []SchemaRef{}, Changed: []SchemaChange{}}
+	key := func(sc domain.Schema) string { return string(sc.SchemaType) + "\x00" + sc.Name }
+
+	inA := make(map[string]domain.Schema, len(a))
+	for _, sc := range a {
+		inA[key(sc)] = sc
+	}
+	inB := make(map[string]bool, len(b))
+	for _, sc := range b {
+		inB[key(sc)] = true
+		prev, ok := inA[key(sc)]
+		if !ok {
+			diff.OnlyInB = append(diff.OnlyInB, SchemaRef{SchemaType: sc.SchemaType, Name: sc.Name, Confidence: sc.Confidence})
+			continue
+		}
+		changedAttrs := changedAttributes(prev.Attributes, sc.Attributes)
+		if len(changedAttrs) > 0 || math.Abs(float64(sc.Confidence-prev.Confidence)) >= schemaConfidenceTolerance {
+			diff.Changed = append(diff.Changed, SchemaChange{
+				SchemaType:        sc.SchemaType,
+				Name:              sc.Name,
+				AConfidence:       prev.Confidence,
+				BConfidence:       sc.Confidence,
+				ChangedAttributes: changedAttrs,
+			})
+		}
+	}
+	for _, sc := range a {
+		if !inB[key(sc)] {
+			diff.OnlyInA = append(diff.OnlyInA, SchemaRef{SchemaType: sc.SchemaType, Name: sc.Name, Confidence: sc.Confidence})
+		}
+	}
+	return diff
+}
+
+// changedAttributes lists, sorted, the attribute keys added, removed or
+// changed between two schemas.
+func changedAttributes(a, b map[string]any) []string {
+	var changed []string
+	for k, v := range a {
+		if other, ok := b[k]; !ok || !reflect.DeepEqual(v, other) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func confidenceDistribution(beliefs []domain.BeliefAtTime) ConfidenceDistribution {
+	dist := ConfidenceDistribution{
+		Tiers:     map[domain.MemoryTier]int{},
+		Histogram: make([]int, confidenceHistogramBins),
+	}
+	if len(beliefs) == 0 {
+		return dist
+	}
+
+	values := make([]float64, 0, len(beliefs))
+	var sum float64
+	for _, b := range beliefs {
+		c := float64(b.Confidence)
+		values = append(values, c)
+		sum += c
+		dist.Tiers[domain.ComputeTier(c)]++
+		// The epsilon keeps float32 values such as 0.9 out of the bin below.
+		bin := int(c*confidenceHistogramBins + 1e-6)
+		if bin >= confidenceHistogramBins {
+			bin = confidenceHistogramBins - 1
+		}
+		if bin < 0 {
+			bin = 0
+		}
+		dist.Histogram[bin]++
+	}
+	sort.Float64s(values)
+
+	dist.Mean = sum / float64(len(values))
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		dist.Median = (values[mid-1] + values[mid]) / 2
+	} else {
+		dist.Median = values[mid]
+	}
+	return dist
+}
+
+// histogramDistance is the total variation distance between two histograms
+// after normalising each to sum to one. An empty side counts as fully distant
+// from a non-empty one.
+func histogramDistance(a, b []int) float64 {
+	var totalA, totalB int
+	for i := range a {
+		totalA += a[i]
+		totalB += b[i]
+	}
+	switch {
+	case totalA == 0 && totalB == 0:
+		return 0
+	case totalA == 0 || totalB == 0:
+		return 1
+	}
+	var d float64
+	for i := range a {
+		d += math.Abs(float64(a[i])/float64(totalA) - float64(b[i])/float64(totalB))
+	}
+	return d / 2
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// compareMemoryStore serves preset belief snapshots per agent, filtered by
+// creation time the way BeliefsAsOf is.
+type compareMemoryStore struct {
+	*mockMemoryStore
+	beliefs map[uuid.UUID][]domain.BeliefAtTime
+}
+
+func (m *compareMemoryStore) BeliefsAsOf(ctx context.Context, agentID, tenantID uuid.UUID, at time.Time, limit int) ([]domain.BeliefAtTime, int, error) {
+	var out []domain.BeliefAtTime
+	for _, b := range m.beliefs[agentID] {
+		if !b.CreatedAt.After(at) {
+			out = append(out, b)
+		}
+	}
+	total := len(out)
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, total, nil
+}
+
+type compareFixture struct {
+	svc      *AgentCompareService
+	memories *compareMemoryStore
+	schemas  *mockSchemaStore
+	tenantID uuid.UUID
+	prod     uuid.UUID
+	staging  uuid.UUID
+}
+
+func newCompareFixture() *compareFixture {
+	agents := newMockAgentStore()
+	tenantID := uuid.New()
+	prod := &domain.Agent{TenantID: tenantID, ExternalID: "prod", Name: "Prod"}
+	staging := &domain.Agent{TenantID: tenantID, ExternalID: "staging", Name: "Staging"}
+	_ = agents.Create(context.Background(), prod)
+	_ = agents.Create(context.Background(), staging)
+
+	f := &compareFixture{
+		memories: &compareMemoryStore{mockMemoryStore: newMockMemoryStore(), beliefs: map[uuid.UUID][]domain.BeliefAtTime{}},
+		schemas:  newMockSchemaStore(),
+		tenantID: tenantID,
+		prod:     prod.ID,
+		staging:  staging.ID,
+	}
+	f.svc = NewAgentCompareService(agents, f.memories, f.schemas, testLogger())
+	return f
+}
+
+func (f *compareFixture) addSchema(agentID uuid.UUID, name string, confidence float32, attrs map[string]any, createdAt time.Time) {
+	sc := &domain.Schema{AgentID: agentID, TenantID: f.tenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: name, Confidence: confidence, Attributes: attrs}
+	_ = f.schemas.Create(context.Background(), sc)
+	sc.CreatedAt = createdAt
+}
+
+func belief(content string, confidence float32, createdAt time.Time) domain.BeliefAtTime {
+	return domain.BeliefAtTime{ID: uuid.New(), Content: content, Confidence: confidence, CreatedAt: createdAt}
+}
+
+func TestAgentCompareService_TwoAgents(t *testing.T) {
+	f := newCompareFixture()
+	past := time.Now().Add(-time.Hour)
+	f.memories.beliefs[f.prod] = []domain.BeliefAtTime{
+		belief("User prefers dark mode", 0.9, past),
+		belief("Deploys happen on Fridays", 0.6, past),
+	}
+	f.memories.beliefs[f.staging] = []domain.BeliefAtTime{
+		belief("user prefers  DARK mode", 0.7, past),
+		belief("Staging uses a mock payment provider", 0.5, past),
+	}
+	f.addSchema(f.prod, "Night owl", 0.8, map[string]any{"active_hours": "late"}, past)
+	f.addSchema(f.staging, "Night owl", 0.8, map[string]any{"active_hours": "early"}, past)
+	f.addSchema(f.prod, "Power user", 0.6, nil, past)
+	f.addSchema(f.staging, "Newcomer", 0.5, nil, past)
+
+	now := time.Now()
+	report, err := f.svc.Compare(context.Background(), CompareInput{
+		TenantID: f.tenantID,
+		A:        CompareSide{AgentID: f.prod, At: now},
+		B:        CompareSide{AgentID: f.staging, At: now},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	b := report.Beliefs
+	if b.Shared != 1 || b.OnlyInA != 1 || b.OnlyInB != 1 {
+		t.Fatalf("unexpected overlap: %+v", b)
+	}
+	if math.Abs(b.Jaccard-1.0/3) > 1e-9 {
+		t.Fatalf("expected jaccard 1/3, got %v", b.Jaccard)
+	}
+	if len(b.Shifted) != 1 || math.Abs(float64(b.Shifted[0].Delta)+0.2) > 1e-6 {
+		t.Fatalf("expected one belief shifted by -0.2, got %+v", b.Shifted)
+	}
+
+	s := report.Schemas
+	if len(s.OnlyInA) != 1 || s.OnlyInA[0].Name != "Power user" || len(s.OnlyInB) != 1 || s.OnlyInB[0].Name != "Newcomer" {
+		t.Fatalf("unexpected schema presence diff: %+v", s)
+	}
+	if len(s.Changed) != 1 || len(s.Changed[0].ChangedAttributes) != 1 || s.Changed[0].ChangedAttributes[0] != "active_hours" {
+		t.Fatalf("expected the night owl attribute change, got %+v", s.Changed)
+	}
+
+	if report.A.Distribution.Histogram[9] != 1 || report.B.Distribution.Tiers[domain.TierCold] != 2 {
+		t.Fatalf("unexpected distributions: %+v / %+v", report.A.Distribution, report.B.Distribution)
+	}
+	if report.DriftScore <= 0 || report.DriftScore > 1 {
+		t.Fatalf("expected a drift score in (0,1], got %v", report.DriftScore)
+	}
+}
+
+func TestAgentCompareService_OneAgentOverTime(t *testing.T) {
+	f := newCompareFixture()
+	now := time.Now()
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+	old := belief("User prefers dark mode", 0.9, weekAgo.Add(-time.Hour))
+	f.memories.beliefs[f.prod] = []domain.BeliefAtTime{old, belief("User moved to Berlin", 0.8, now.Add(-time.Hour))}
+	f.addSchema(f.prod, "Night owl", 0.8, nil, weekAgo.Add(-time.Hour))
+	f.addSchema(f.prod, "Traveller", 0.5, nil, now.Add(-time.Hour))
+
+	report, err := f.svc.Compare(context.Background(), CompareInput{
+		TenantID: f.tenantID,
+		A:        CompareSide{AgentID: f.prod, At: weekAgo},
+		B:        CompareSide{AgentID: f.prod, At: now},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Beliefs.Shared != 1 || report.Beliefs.OnlyInB != 1 || report.Beliefs.OnlyInA != 0 {
+		t.Fatalf("unexpected overlap: %+v", report.Beliefs)
+	}
+	if len(report.Schemas.OnlyInB) != 1 || report.Schemas.OnlyInB[0].Name != "Traveller" || len(report.Schemas.Changed) != 0 {
+		t.Fatalf("expected only the new schema, got %+v", report.Schemas)
+	}
+}
+
+func TestAgentCompareService_Errors(t *testing.T) {
+	f := newCompareFixture()
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := f.svc.Compare(ctx, CompareInput{TenantID: f.tenantID, A: CompareSide{AgentID: f.prod, At: now}, B: CompareSide{AgentID: f.prod, At: now}})
+	if !errors.Is(err, ErrCompareSameSide) {
+		t.Fatalf("expected ErrCompareSameSide, got %v", err)
+	}
+	_, err = f.svc.Compare(ctx, CompareInput{TenantID: f.tenantID, A: CompareSide{AgentID: f.prod, At: now}, B: CompareSide{AgentID: uuid.New(), At: now}})
+	if !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("expected ErrAgentNotFound, got %v", err)
+	}
+}
+
+func TestHistogramDistance(t *testing.T) {
+	if d := histogramDistance([]int{1, 1}, []int{2, 2}); d != 0 {
+		t.Fatalf("expected identical shapes to be 0 apart, got %v", d)
+	}
+	if d := histogramDistance([]int{1, 0}, []int{0, 1}); d != 1 {
+		t.Fatalf("expected disjoint histograms to be 1 apart, got %v", d)
+	}
+	if d := histogramDistance([]int{0, 0}, []int{3, 0}); d != 1 {
+		t.Fatalf("expected an empty side to be 1 apart, got %v", d)
+	}
+}
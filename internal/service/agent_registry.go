@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// agentsWithWork lists the agents a background worker should visit. With a
+// registry they come from the agents table with their tenants; without one
+// the worker's legacy DISTINCT query is used and TenantID is left nil.
+func agentsWithWork(ctx context.Context, reg domain.AgentRegistry, f domain.AgentWorkFilter, legacy func(context.Context) ([]uuid.UUID, error)) ([]domain.AgentRef, error) {
+	if reg != nil {
+		return reg.ListWithWork(ctx, f)
+	}
+	ids, err := legacy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]domain.AgentRef, len(ids))
+	for i, id := range ids {
+		refs[i] = domain.AgentRef{ID: id}
+	}
+	return refs, nil
+}
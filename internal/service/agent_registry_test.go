@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// fakeAgentRegistry returns fixed agents and records the filters asked for.
+type fakeAgentRegistry struct {
+	refs    []domain.AgentRef
+	filters []domain.AgentWorkFilter
+}
+
+func (f *fakeAgentRegistry) ListWithWork(ctx context.Context, filter domain.AgentWorkFilter) ([]domain.AgentRef, error) {
+	f.filters = append(f.filters, filter)
+	return f.refs, nil
+}
+
+func TestAgentsWithWork_PrefersRegistry(t *testing.T) {
+	ref := domain.AgentRef{ID: uuid.New(), TenantID: uuid.New()}
+	reg := &fakeAgentRegistry{refs: []domain.AgentRef{ref}}
+	legacy := func(context.Context) ([]uuid.UUID, error) {
+		t.Fatal("legacy discovery must not run with a registry")
+		return nil, nil
+	}
+
+	got, err := agentsWithWork(context.Background(), reg, domain.AgentWorkFilter{Feedback: true}, legacy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != ref {
+		t.Fatalf("expected the registry's agents, got %+v", got)
+	}
+	if len(reg.filters) != 1 || !reg.filters[0].Feedback {
+		t.Fatalf("expected the feedback filter to be passed through, got %+v", reg.filters)
+	}
+}
+
+func TestAgentsWithWork_LegacyFallback(t *testing.T) {
+	id := uuid.New()
+	got, err := agentsWithWork(context.Background(), nil, domain.AgentWorkFilter{LiveMemories: true}, func(context.Context) ([]uuid.UUID, error) {
+		return []uuid.UUID{id}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != id || got[0].TenantID != uuid.Nil {
+		t.Fatalf("expected the legacy IDs with unknown tenants, got %+v", got)
+	}
+}
+
+func TestConsolidationService_AgentsFromRegistry(t *testing.T) {
+	// An agent with only episodes has no memories to be discovered from.
+	ref := domain.AgentRef{ID: uuid.New(), TenantID: uuid.New()}
+	reg := &fakeAgentRegistry{refs: []domain.AgentRef{ref}}
+	svc := NewConsolidationService(newMockMemoryStore(), nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetAgentRegistry(reg)
+
+	got, err := svc.GetAgentsNeedingConsolidation(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != ref {
+		t.Fatalf("expected the episode-only agent, got %+v", got)
+	}
+	want := domain.AgentWorkFilter{UnconsolidatedEpisodes: true, LiveMemories: true}
+	if reg.filters[0] != want {
+		t.Fatalf("expected filter %+v, got %+v", want, reg.filters[0])
+	}
+}
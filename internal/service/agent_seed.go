@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSeedEmpty = errors.New("seed document declares no beliefs, schemas or procedures")
+)
+
+// SeedValidationError locates the invalid entry of a seed document, e.g.
+// "beliefs[2]: content is required".
+type SeedValidationError struct {
+	Section string
+	Index   int
+	Err     error
+}
+
+func (e *SeedValidationError) Error() string {
+	return fmt.Sprintf("%s[%d]: %v", e.Section, e.Index, e.Err)
+}
+
+func (e *SeedValidationError) Unwrap() error { return e.Err }
+
+// BeliefWriter stores a belief through the full write path (embedding,
+// classification, reinforcement and the provenance firewall).
+type BeliefWriter interface {
+	Create(ctx context.Context, m *domain.Memory) (*CreateResult, error)
+}
+
+// SchemaSeeder creates or refreshes schemas by type and name.
+type SchemaSeeder interface {
+	SeedSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, inputs []CreateSchemaInput) ([]domain.Schema, error)
+}
+
+// ProcedureSeeder creates declared procedures, skipping known triggers.
+type ProcedureSeeder interface {
+	SeedProcedures(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, seeds []domain.SeedProcedure) ([]domain.Procedure, int, error)
+}
+
+// AgentSeedService loads a declarative seed document into an agent so it
+// starts with curated beliefs, schemas and procedures.
+type AgentSeedService struct {
+	agentStore domain.AgentStore
+	beliefs    BeliefWriter
+	schemas    SchemaSeeder
+	procedures ProcedureSeeder
+	logger     *zap.Logger
+}
+
+// NewAgentSeedService creates a new agent seed service.
+func NewAgentSeedService(agentStore domain.AgentStore, beliefs BeliefWriter, schemas SchemaSeeder, procedures ProcedureSeeder, logger *zap.Logger) *AgentSeedService {
+	return &AgentSeedService{
+		agentStore: agentStore,
+		beliefs:    beliefs,
+		schemas:    schemas,
+		procedures: procedures,
+		logger:     logger,
+	}
+}
+
+// SeedResult summarises what a seed document changed.
+type SeedResult struct {
+	BeliefsCreated     int                `json:"beliefs_created"`
+	BeliefsReinforced  int                `json:"beliefs_reinforced"`
+	BeliefsQuarantined int                `json:"beliefs_quarantined"`
+	Schemas            []domain.Schema    `json:"schemas"`
+	Procedures         []domain.Procedure `json:"procedures"`
+	ProceduresSkipped  int                `json:"procedures_skipped"`
+}
+
+// Seed validates the whole document, then applies beliefs, schemas and
+// procedures in that order so schemas can cite seeded beliefs as evidence.
+// Seeding is safe to repeat: beliefs already held are reinforced, schemas are
+// refreshed by name and procedures with known triggers are skipped.
+func (s *AgentSeedService) Seed(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, doc domain.SeedDocument) (*SeedResult, error) {
+	if err := validateSeedDocument(doc); err != nil {
+		return nil, err
+	}
+	if _, err := s.agentStore.GetByID(ctx, agentID, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+
+	result := &SeedResult{Schemas: []domain.Schema{}, Procedures: []domain.Procedure{}}
+
+	keyed := make(map[string]uuid.UUID)
+	for i, b := range doc.Beliefs {
+		m := seedMemory(agentID, tenantID, doc.Name, b)
+		res, err := s.beliefs.Create(ctx, m)
+		if err != nil {
+			return nil, fmt.Errorf("seed beliefs[%d]: %w", i, err)
+		}
+		var id uuid.UUID
+		switch {
+		case res.Quarantined:
+			result.BeliefsQuarantined++
+		case res.Reinforced:
+			result.BeliefsReinforced++
+			id = res.ReinforcedMemoryID
+		default:
+			result.BeliefsCreated++
+			id = m.ID
+		}
+		if b.Key != "" && id != uuid.Nil {
+			keyed[b.Key] = id
+		}
+	}
+
+	if len(doc.Schemas) > 0 {
+		inputs := make([]CreateSchemaInput, 0, len(doc.Schemas))
+		for _, sc := range doc.Schemas {
+			// Evidence held back by the firewall has no active memory to cite.
+			var evidence []uuid.UUID
+			for _, key := range sc.Evidence {
+				if id, ok := keyed[key]; ok {
+					evidence = append(evidence, id)
+				}
+			}
+			inputs = append(inputs, CreateSchemaInput{
+				SchemaType:         sc.SchemaType,
+				Name:               sc.Name,
+				Description:        sc.Description,
+				Attributes:         sc.Attributes,
+				ApplicableContexts: sc.ApplicableContexts,
+				EvidenceMemories:   evidence,
+				Confidence:         sc.Confidence,
+				ParentName:         sc.ParentName,
+			})
+		}
+		schemas, err := s.schemas.SeedSchemas(ctx, agentID, tenantID, inputs)
+		if err != nil {
+			return nil, fmt.Errorf("seed schemas: %w", err)
+		}
+		result.Schemas = schemas
+	}
+
+	if len(doc.Procedures) > 0 {
+		procedures, skipped, err := s.procedures.SeedProcedures(ctx, agentID, tenantID, doc.Procedures)
+		if err != nil {
+			return nil, fmt.Errorf("seed procedures: %w", err)
+		}
+		result.Procedures = procedures
+		result.ProceduresSkipped = skipped
+	}
+
+	logFor(ctx, s.logger).Info("seeded agent",
+		zap.String("agent_id", agentID.String()),
+		zap.String("document", doc.Name),
+		zap.Int("beliefs_created", result.BeliefsCreated),
+		zap.Int("beliefs_reinforced", result.BeliefsReinforced),
+		zap.Int("schemas", len(result.Schemas)),
+		zap.Int("procedures", len(result.Procedures)))
+
+	return result, nil
+}
+
+// seedMemory builds the memory for a seeded belief. Seeded beliefs default to
+// user provenance — the document is operator-curated — and record the seed
+// document they came from.
+func seedMemory(agentID uuid.UUID, tenantID uuid.UUID, docName string, b domain.SeedBelief) *domain.Memory {
+	metadata := make(map[string]any, len(b.Metadata)+1)
+	for k, v := range b.Metadata {
+		metadata[k] = v
+	}
+	if docName != "" {
+		metadata["seeded_from"] = docName
+	}
+
+	provenance := b.Provenance
+	if provenance == "" {
+		provenance = domain.ProvenanceUser
+	}
+
+	return &domain.Memory{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       b.Type,
+		Content:    strings.TrimSpace(b.Content),
+		Source:     domain.SeedSource,
+		Provenance: provenance,
+		Confidence: b.Confidence,
+		Metadata:   metadata,
+	}
+}
+
+// validateSeedDocument rejects the document before anything is written.
+func validateSeedDocument(doc domain.SeedDocument) error {
+	if len(doc.Beliefs) == 0 && len(doc.Schemas) == 0 && len(doc.Procedures) == 0 {
+		return ErrSeedEmpty
+	}
+
+	keys := make(map[string]bool, len(doc.Beliefs))
+	for i, b := range doc.Beliefs {
+		var err error
+		switch {
+		case strings.TrimSpace(b.Content) == "":
+			err = ErrMemoryContentEmpty
+		case b.Type != "" && !domain.ValidMemoryType(string(b.Type)):
+			err = ErrInvalidMemoryType
+		case b.Provenance != "" && !domain.ValidProvenance(string(b.Provenance)):
+			err = errors.New("invalid provenance")
+		case b.Confidence < 0 || b.Confidence > 1:
+			err = ErrInvalidConfidence
+		case b.Key != "" && keys[b.Key]:
+			err = fmt.Errorf("duplicate key %q", b.Key)
+		}
+		if err != nil {
+			return &SeedValidationError{Section: "beliefs", Index: i, Err: err}
+		}
+		if b.Key != "" {
+			keys[b.Key] = true
+		}
+	}
+
+	for i, sc := range doc.Schemas {
+		var err error
+		switch {
+		case !sc.SchemaType.IsValid():
+			err = ErrInvalidSchemaType
+		case strings.TrimSpace(sc.Name) == "":
+			err = ErrSchemaNameRequired
+		case sc.Confidence != nil && (*sc.Confidence < 0 || *sc.Confidence > 1):
+			err = ErrInvalidConfidence
+		}
+		for _, key := range sc.Evidence {
+			if err == nil && !keys[key] {
+				err = fmt.Errorf("evidence %q matches no belief key", key)
+			}
+		}
+		if err != nil {
+			return &SeedValidationError{Section: "schemas", Index: i, Err: err}
+		}
+	}
+
+	for i, p := range doc.Procedures {
+		if err := ValidateSeedProcedure(p); err != nil {
+			return &SeedValidationError{Section: "procedures", Index: i, Err: err}
+		}
+	}
+	return nil
+}
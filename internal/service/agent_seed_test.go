@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fakeBeliefWriter reinforces a belief whose content it has seen before and
+// quarantines inferred ones, mimicking the memory write path.
+type fakeBeliefWriter struct {
+	byContent map[string]uuid.UUID
+	written   []*domain.Memory
+}
+
+func (f *fakeBeliefWriter) Create(ctx context.Context, m *domain.Memory) (*CreateResult, error) {
+	f.written = append(f.written, m)
+	if m.Provenance == domain.ProvenanceInferred {
+		return &CreateResult{Quarantined: true, QuarantineReason: "untrusted"}, nil
+	}
+	if id, ok := f.byContent[m.Content]; ok {
+		return &CreateResult{Reinforced: true, ReinforcedMemoryID: id}, nil
+	}
+	m.ID = uuid.New()
+	f.byContent[m.Content] = m.ID
+	return &CreateResult{}, nil
+}
+
+type fakeSchemaSeeder struct {
+	inputs []CreateSchemaInput
+}
+
+func (f *fakeSchemaSeeder) SeedSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, inputs []CreateSchemaInput) ([]domain.Schema, error) {
+	f.inputs = append(f.inputs, inputs...)
+	schemas := make([]domain.Schema, 0, len(inputs))
+	for _, in := range inputs {
+		schemas = append(schemas, domain.Schema{ID: uuid.New(), Name: in.Name, SchemaType: in.SchemaType})
+	}
+	return schemas, nil
+}
+
+type fakeProcedureSeeder struct {
+	seeds []domain.SeedProcedure
+}
+
+func (f *fakeProcedureSeeder) SeedProcedures(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, seeds []domain.SeedProcedure) ([]domain.Procedure, int, error) {
+	f.seeds = append(f.seeds, seeds...)
+	created := make([]domain.Procedure, 0, len(seeds))
+	for _, s := range seeds {
+		created = append(created, domain.Procedure{ID: uuid.New(), TriggerPattern: s.TriggerPattern})
+	}
+	return created, 0, nil
+}
+
+type seedFixture struct {
+	svc        *AgentSeedService
+	beliefs    *fakeBeliefWriter
+	schemas    *fakeSchemaSeeder
+	procedures *fakeProcedureSeeder
+	agentID    uuid.UUID
+	tenantID   uuid.UUID
+}
+
+func newSeedFixture() *seedFixture {
+	agents := newMockAgentStore()
+	agent := &domain.Agent{TenantID: uuid.New(), ExternalID: "seeded", Name: "Seeded"}
+	_ = agents.Create(context.Background(), agent)
+
+	f := &seedFixture{
+		beliefs:    &fakeBeliefWriter{byContent: make(map[string]uuid.UUID)},
+		schemas:    &fakeSchemaSeeder{},
+		procedures: &fakeProcedureSeeder{},
+		agentID:    agent.ID,
+		tenantID:   agent.TenantID,
+	}
+	f.svc = NewAgentSeedService(agents, f.beliefs, f.schemas, f.procedures, testLogger())
+	return f
+}
+
+func sampleSeedDocument() domain.SeedDocument {
+	return domain.SeedDocument{
+		Name: "support-basics",
+		Beliefs: []domain.SeedBelief{
+			{Key: "tz", Content: "Customers expect replies in CET business hours", Type: domain.MemoryTypeFact},
+			{Key: "guess", Content: "Customers dislike emoji", Provenance: domain.ProvenanceInferred},
+			{Content: "Refunds need manager approval", Metadata: map[string]any{"team": "billing"}},
+		},
+		Schemas: []domain.SeedSchema{
+			{SchemaType: domain.SchemaTypeUserArchetype, Name: "Impatient debugger", Evidence: []string{"tz", "guess"}},
+		},
+		Procedures: []domain.SeedProcedure{
+			{TriggerPattern: "failed deploy", ActionTemplate: "Ask for the deploy ID", ActionType: domain.ActionTypeProblemSolving},
+		},
+	}
+}
+
+func TestAgentSeedService_Seed(t *testing.T) {
+	f := newSeedFixture()
+
+	result, err := f.svc.Seed(context.Background(), f.agentID, f.tenantID, sampleSeedDocument())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.BeliefsCreated != 2 || result.BeliefsQuarantined != 1 || result.BeliefsReinforced != 0 {
+		t.Fatalf("unexpected belief counts: %+v", result)
+	}
+	if len(result.Schemas) != 1 || len(result.Procedures) != 1 {
+		t.Fatalf("expected 1 schema and 1 procedure, got %d and %d", len(result.Schemas), len(result.Procedures))
+	}
+
+	first := f.beliefs.written[0]
+	if first.Source != domain.SeedSource || first.Provenance != domain.ProvenanceUser {
+		t.Fatalf("expected seed source and user provenance, got %q and %q", first.Source, first.Provenance)
+	}
+	if first.Metadata["seeded_from"] != "support-basics" {
+		t.Fatalf("expected seeded_from metadata, got %v", first.Metadata)
+	}
+	if f.beliefs.written[2].Metadata["team"] != "billing" {
+		t.Fatal("expected declared metadata to be kept")
+	}
+
+	// The quarantined belief has no active memory, so only "tz" is evidence.
+	evidence := f.schemas.inputs[0].EvidenceMemories
+	if len(evidence) != 1 || evidence[0] != first.ID {
+		t.Fatalf("expected evidence [%s], got %v", first.ID, evidence)
+	}
+}
+
+func TestAgentSeedService_SeedTwiceReinforces(t *testing.T) {
+	f := newSeedFixture()
+	ctx := context.Background()
+
+	if _, err := f.svc.Seed(ctx, f.agentID, f.tenantID, sampleSeedDocument()); err != nil {
+		t.Fatalf("first seed: %v", err)
+	}
+	result, err := f.svc.Seed(ctx, f.agentID, f.tenantID, sampleSeedDocument())
+	if err != nil {
+		t.Fatalf("second seed: %v", err)
+	}
+	if result.BeliefsCreated != 0 || result.BeliefsReinforced != 2 {
+		t.Fatalf("expected beliefs to be reinforced, got %+v", result)
+	}
+
+	evidence := f.schemas.inputs[1].EvidenceMemories
+	if len(evidence) != 1 || evidence[0] != f.beliefs.written[0].ID {
+		t.Fatalf("expected evidence to cite the reinforced memory, got %v", evidence)
+	}
+}
+
+func TestAgentSeedService_InvalidDocumentWritesNothing(t *testing.T) {
+	cases := map[string]func(*domain.SeedDocument){
+		"empty belief":        func(d *domain.SeedDocument) { d.Beliefs[2].Content = "  " },
+		"duplicate key":       func(d *domain.SeedDocument) { d.Beliefs[1].Key = "tz" },
+		"bad provenance":      func(d *domain.SeedDocument) { d.Beliefs[0].Provenance = "rumour" },
+		"unknown evidence":    func(d *domain.SeedDocument) { d.Schemas[0].Evidence = []string{"nope"} },
+		"bad schema type":     func(d *domain.SeedDocument) { d.Schemas[0].SchemaType = "vibe" },
+		"bad procedure":       func(d *domain.SeedDocument) { d.Procedures[0].ActionTemplate = "" },
+		"confidence too high": func(d *domain.SeedDocument) { d.Beliefs[0].Confidence = 1.5 },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := newSeedFixture()
+			doc := sampleSeedDocument()
+			mutate(&doc)
+
+			_, err := f.svc.Seed(context.Background(), f.agentID, f.tenantID, doc)
+			var invalid *SeedValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected a SeedValidationError, got %v", err)
+			}
+			if len(f.beliefs.written) != 0 || len(f.schemas.inputs) != 0 || len(f.procedures.seeds) != 0 {
+				t.Fatal("expected nothing written for an invalid document")
+			}
+		})
+	}
+}
+
+func TestAgentSeedService_Errors(t *testing.T) {
+	f := newSeedFixture()
+	ctx := context.Background()
+
+	if _, err := f.svc.Seed(ctx, f.agentID, f.tenantID, domain.SeedDocument{Name: "empty"}); !errors.Is(err, ErrSeedEmpty) {
+		t.Fatalf("expected ErrSeedEmpty, got %v", err)
+	}
+	if _, err := f.svc.Seed(ctx, uuid.New(), f.tenantID, sampleSeedDocument()); !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("expected ErrAgentNotFound, got %v", err)
+	}
+}
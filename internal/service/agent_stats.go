@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const defaultStatsReconcileInterval = 24 * time.Hour
+
+// AgentStatsService serves the materialized per-agent memory statistics and
+// reconciles them nightly against the source tables.
+type AgentStatsService struct {
+	store    domain.AgentStatisticsStore
+	logger   *zap.Logger
+	interval time.Duration
+}
+
+func NewAgentStatsService(st domain.AgentStatisticsStore, logger *zap.Logger) *AgentStatsService {
+	return &AgentStatsService{store: st, logger: logger, interval: defaultStatsReconcileInterval}
+}
+
+// Get returns the agent's statistics. An agent that has never had a memory or
+// consolidation run has no row yet and reads as zeros.
+func (s *AgentStatsService) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.AgentStatistics, error) {
+	st, err := s.store.Get(ctx, agentID, tenantID)
+	if errors.Is(err, store.ErrNotFound) {
+		return &domain.AgentStatistics{AgentID: agentID, TenantID: tenantID}, nil
+	}
+	return st, err
+}
+
+func (s *AgentStatsService) ListByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]domain.AgentStatistics, int, error) {
+	return s.store.ListByTenant(ctx, tenantID, limit, offset)
+}
+
+// ScheduledTask returns the nightly reconcile for the Scheduler.
+func (s *AgentStatsService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "memory-stats-reconcile", Interval: s.interval, Timeout: 10 * time.Minute, Run: s.reconcile}
+}
+
+func (s *AgentStatsService) reconcile(ctx context.Context) error {
+	n, err := s.store.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	logFor(ctx, s.logger).Info("reconciled memory stats", zap.Int64("agents", n))
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeAgentStatisticsStore struct {
+	rows       map[uuid.UUID]domain.AgentStatistics
+	reconciles int
+}
+
+func (f *fakeAgentStatisticsStore) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.AgentStatistics, error) {
+	st, ok := f.rows[agentID]
+	if !ok || st.TenantID != tenantID {
+		return nil, store.ErrNotFound
+	}
+	return &st, nil
+}
+
+func (f *fakeAgentStatisticsStore) ListByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]domain.AgentStatistics, int, error) {
+	var out []domain.AgentStatistics
+	for _, st := range f.rows {
+		if st.TenantID == tenantID {
+			out = append(out, st)
+		}
+	}
+	return out, len(out), nil
+}
+
+func (f *fakeAgentStatisticsStore) Reconcile(ctx context.Context) (int64, error) {
+	f.reconciles++
+	return int64(len(f.rows)), nil
+}
+
+func TestAgentStatsService_GetMissingRowReadsAsZero(t *testing.T) {
+	agentID, tenantID := uuid.New(), uuid.New()
+	svc := NewAgentStatsService(&fakeAgentStatisticsStore{}, zap.NewNop())
+
+	st, err := svc.Get(context.Background(), agentID, tenantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.AgentID != agentID || st.TenantID != tenantID || st.MemoryCount != 0 || st.LastConsolidatedAt != nil {
+		t.Fatalf("expected zero stats for the agent, got %+v", st)
+	}
+}
+
+func TestAgentStatsService_GetReturnsRow(t *testing.T) {
+	agentID, tenantID := uuid.New(), uuid.New()
+	fs := &fakeAgentStatisticsStore{rows: map[uuid.UUID]domain.AgentStatistics{
+		agentID: {AgentID: agentID, TenantID: tenantID, MemoryCount: 42, AverageConfidence: 0.7, HotCount: 10},
+	}}
+	svc := NewAgentStatsService(fs, zap.NewNop())
+
+	st, err := svc.Get(context.Background(), agentID, tenantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.MemoryCount != 42 || st.HotCount != 10 {
+		t.Fatalf("expected the stored row, got %+v", st)
+	}
+
+	// Another tenant must not see it.
+	other, err := svc.Get(context.Background(), agentID, uuid.New())
+	if err != nil || other.MemoryCount != 0 {
+		t.Fatalf("expected zeros across tenants, got %+v (%v)", other, err)
+	}
+}
+
+func TestAgentStatsService_ScheduledReconcile(t *testing.T) {
+	fs := &fakeAgentStatisticsStore{}
+	task := NewAgentStatsService(fs, zap.NewNop()).ScheduledTask()
+	if task.Interval != defaultStatsReconcileInterval {
+		t.Fatalf("expected a nightly interval, got %v", task.Interval)
+	}
+	if err := task.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fs.reconciles != 1 {
+		t.Fatalf("expected one reconcile, got %d", fs.reconciles)
+	}
+}
@@ -63,3 +63,15 @@ func TestSameUUIDPtr(t *testing.T) {
 		t.Error("different values should not match")
 	}
 }
+
+func TestSameScopeCandidates_DifferentSubjectsAreExcluded(t *testing.T) {
+	mk := func(subject string) domain.MemoryWithScore {
+		return domain.MemoryWithScore{Memory: domain.Memory{ID: uuid.New(), Subject: subject}}
+	}
+	candidates := []domain.MemoryWithScore{mk("Alice"), mk("Bob"), mk("")}
+
+	got := sameScopeCandidates(candidates, &domain.Memory{Subject: "alice"})
+	if len(got) != 2 || got[0].Subject != "Alice" || got[1].Subject != "" {
+		t.Fatalf("want Alice's and the unattributed candidate, got %+v", got)
+	}
+}
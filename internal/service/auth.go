@@ -272,7 +272,7 @@ func (s *AuthService) provisionTenant(ctx context.Context, u *domain.User) (uuid
 				return tid, nil
 			}
 		}
-		s.logger.Warn("ENGRAM_DEFAULT_TENANT_ID invalid; creating a personal org instead")
+		logFor(ctx, s.logger).Warn("ENGRAM_DEFAULT_TENANT_ID invalid; creating a personal org instead")
 	}
 	t := &domain.Tenant{Name: u.Name + "'s Org"}
 	if err := s.tenants.Create(ctx, t); err != nil {
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// maxLikelihoodReliability caps the reliability used as a likelihood, so no
+// single source is treated as infallible: one memory moves a belief's
+// log-odds by at most log(9).
+const maxLikelihoodReliability = 0.9
+
+// BeliefUpdateProvider is an optional interface that PolicyEnforcer can
+// implement to select how an agent's beliefs of a type are updated.
+type BeliefUpdateProvider interface {
+	BeliefUpdateMode(ctx context.Context, agentID uuid.UUID, memType domain.MemoryType) domain.BeliefUpdateMode
+}
+
+// beliefUpdateMode returns the agent's update mode for memories of memType,
+// BeliefUpdateFixed unless a policy selects otherwise.
+func (s *MemoryService) beliefUpdateMode(ctx context.Context, agentID uuid.UUID, memType domain.MemoryType) domain.BeliefUpdateMode {
+	if bp, ok := s.policyEnforcer.(BeliefUpdateProvider); ok {
+		if mode := bp.BeliefUpdateMode(ctx, agentID, memType); mode != "" {
+			return mode
+		}
+	}
+	return domain.BeliefUpdateFixed
+}
+
+// provenanceReliability is the probability that a memory of provenance p is
+// true, on the scale MetacognitiveService uses for source reliability.
+func provenanceReliability(p domain.Provenance) float64 {
+	switch p {
+	case domain.ProvenanceUser:
+		return SourceReliabilityUserStatement
+	case domain.ProvenanceTool:
+		return SourceReliabilityToolOutput
+	case domain.ProvenanceDerived:
+		return SourceReliabilityExtraction
+	case domain.ProvenanceAgent, domain.ProvenanceInferred:
+		return SourceReliabilityAgentInference
+	default:
+		return SourceReliabilityDefault
+	}
+}
+
+// bayesianLogOdds is the log-likelihood ratio of one memory from a source of
+// provenance p: a source that is right with probability r is r/(1-r) times
+// as likely to report a true belief as a false one.
+func bayesianLogOdds(p domain.Provenance) float64 {
+	r := math.Min(math.Max(provenanceReliability(p), 0.5), maxLikelihoodReliability)
+	return math.Log(r / (1 - r))
+}
+
+// beliefPrior is a new belief's confidence under the Bayesian update: the
+// initial confidence of the evidence type recorded in its metadata, or of its
+// provenance when none was.
+func beliefPrior(m *domain.Memory) float32 {
+	if et, ok := m.Metadata["evidence_type"].(string); ok && domain.ValidEvidenceType(et) {
+		return domain.EvidenceType(et).InitialConfidence()
+	}
+	return m.Provenance.InitialConfidence()
+}
+
+// reinforcedConfidence is the confidence of a belief at prior after m
+// reinforces it.
+func reinforcedConfidence(mode domain.BeliefUpdateMode, prior float32, m *domain.Memory) float32 {
+	var c float32
+	if mode == domain.BeliefUpdateBayesian {
+		c = ApplyLogOddsDelta(prior, bayesianLogOdds(m.Provenance))
+	} else {
+		c = prior + ReinforcementConfidenceBoost
+	}
+	if c > MaxConfidence {
+		c = MaxConfidence
+	}
+	return c
+}
+
+// contradictedConfidence is the confidence of a belief at prior after m
+// contradicts it. weight is 1 for a hard contradiction and 0.5 for a soft one.
+func contradictedConfidence(mode domain.BeliefUpdateMode, prior float32, m *domain.Memory, weight float64) float32 {
+	var c float32
+	if mode == domain.BeliefUpdateBayesian {
+		c = ApplyLogOddsDelta(prior, -weight*bayesianLogOdds(m.Provenance))
+	} else {
+		c = prior - float32(weight)*ContradictionConfidencePenalty
+	}
+	if c < MinConfidence {
+		c = MinConfidence
+	}
+	return c
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+func TestReinforcedConfidence(t *testing.T) {
+	user := &domain.Memory{Provenance: domain.ProvenanceUser}
+	agent := &domain.Memory{Provenance: domain.ProvenanceAgent}
+
+	if got := reinforcedConfidence(domain.BeliefUpdateFixed, 0.6, user); math.Abs(float64(got)-0.65) > 1e-6 {
+		t.Errorf("fixed: expected 0.65, got %f", got)
+	}
+	// Odds 1.5 times a likelihood ratio of 9 (user reliability, capped).
+	if got := reinforcedConfidence(domain.BeliefUpdateBayesian, 0.6, user); math.Abs(float64(got)-13.5/14.5) > 1e-4 {
+		t.Errorf("bayesian, user source: expected %f, got %f", 13.5/14.5, got)
+	}
+	if fromAgent, fromUser := reinforcedConfidence(domain.BeliefUpdateBayesian, 0.6, agent), reinforcedConfidence(domain.BeliefUpdateBayesian, 0.6, user); fromAgent <= 0.6 || fromAgent >= fromUser {
+		t.Errorf("expected a less reliable source to reinforce less, got %f (agent) vs %f (user)", fromAgent, fromUser)
+	}
+	if got := reinforcedConfidence(domain.BeliefUpdateBayesian, 0.98, user); got > MaxConfidence {
+		t.Errorf("expected the ceiling to hold, got %f", got)
+	}
+}
+
+func TestContradictedConfidence(t *testing.T) {
+	user := &domain.Memory{Provenance: domain.ProvenanceUser}
+
+	if got := contradictedConfidence(domain.BeliefUpdateFixed, 0.8, user, 0.5); math.Abs(float64(got)-0.7) > 1e-6 {
+		t.Errorf("fixed soft: expected 0.7, got %f", got)
+	}
+	hard := contradictedConfidence(domain.BeliefUpdateBayesian, 0.8, user, 1)
+	soft := contradictedConfidence(domain.BeliefUpdateBayesian, 0.8, user, 0.5)
+	if !(hard < soft && soft < 0.8) {
+		t.Errorf("expected hard < soft < prior, got hard=%f soft=%f", hard, soft)
+	}
+	if hard < MinConfidence {
+		t.Errorf("expected the floor to hold, got %f", hard)
+	}
+}
+
+func TestBeliefPrior(t *testing.T) {
+	m := &domain.Memory{Provenance: domain.ProvenanceUser, Metadata: map[string]any{"evidence_type": string(domain.EvidenceBehavioral)}}
+	if got := beliefPrior(m); got != domain.EvidenceBehavioral.InitialConfidence() {
+		t.Errorf("expected the evidence type's prior, got %f", got)
+	}
+	m.Metadata = nil
+	if got := beliefPrior(m); got != domain.ProvenanceUser.InitialConfidence() {
+		t.Errorf("expected the provenance's prior without an evidence type, got %f", got)
+	}
+}
+
+func TestMemoryService_Create_BayesianPolicyReinforcement(t *testing.T) {
+	svc, memStore, tenantID, agentID := setupMemoryTest()
+	svc.embeddingClient = constantEmbeddingClient{}
+	policyStore := newMockPolicyStore()
+	_ = policyStore.Upsert(context.Background(), &domain.Policy{
+		AgentID: agentID, MemoryType: domain.MemoryTypePreference, MaxMemories: 100, PriorityWeight: 1,
+		BeliefUpdate: domain.BeliefUpdateBayesian,
+	})
+	svc.SetPolicyEnforcer(NewPolicyService(policyStore, memStore, nil, nil, nil, testLogger()))
+	ctx := context.Background()
+
+	first := &domain.Memory{
+		AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode", Type: domain.MemoryTypePreference,
+		Metadata: map[string]any{"evidence_type": string(domain.EvidenceImplicit)},
+	}
+	if _, err := svc.Create(ctx, first); err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	if first.Confidence != domain.EvidenceImplicit.InitialConfidence() {
+		t.Fatalf("expected the evidence type's prior %f, got %f", domain.EvidenceImplicit.InitialConfidence(), first.Confidence)
+	}
+
+	second := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode", Type: domain.MemoryTypePreference, Provenance: domain.ProvenanceUser}
+	res, err := svc.Create(ctx, second)
+	if err != nil {
+		t.Fatalf("second create: %v", err)
+	}
+	if !res.Reinforced {
+		t.Fatal("expected the second write to reinforce the first")
+	}
+	want := ApplyLogOddsDelta(domain.EvidenceImplicit.InitialConfidence(), bayesianLogOdds(domain.ProvenanceUser))
+	if got := memStore.memories[first.ID].Confidence; math.Abs(float64(got-want)) > 1e-6 {
+		t.Errorf("expected a Bayesian update to %f, got %f", want, got)
+	}
+}
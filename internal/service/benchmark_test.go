@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Benchmarks for the recall and consolidation hot paths. Run with
+//
+//	go test -run '^$' -bench . -benchmem ./internal/service ./internal/store
+//
+// and compare runs with benchstat.
+
+const benchDim = 1536
+
+var benchSink float32
+
+// benchVector returns a random unit vector.
+func benchVector(rnd *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	var norm float64
+	for i := range v {
+		v[i] = float32(rnd.NormFloat64())
+		norm += float64(v[i]) * float64(v[i])
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range v {
+		v[i] *= scale
+	}
+	return v
+}
+
+// benchMemories returns n memories whose embeddings are noisy copies of
+// topics centroids, so clustering and merging find real structure. noise is
+// the per-dimension noise relative to a unit centroid; near 0.01 most
+// memories of a topic are redundant (similarity above RedundancyThreshold).
+func benchMemories(n, topics int, noise float64) []domain.Memory {
+	rnd := rand.New(rand.NewSource(42))
+	centroids := make([][]float32, topics)
+	for i := range centroids {
+		centroids[i] = benchVector(rnd, benchDim)
+	}
+	memories := make([]domain.Memory, n)
+	for i := range memories {
+		c := centroids[rnd.Intn(topics)]
+		emb := make([]float32, benchDim)
+		for d := range emb {
+			emb[d] = c[d] + float32(rnd.NormFloat64()*noise)
+		}
+		memories[i] = domain.Memory{
+			ID:         uuid.New(),
+			Type:       domain.MemoryTypeFact,
+			Confidence: 0.5 + 0.4*rnd.Float32(),
+			Embedding:  emb,
+		}
+	}
+	return memories
+}
+
+func BenchmarkCosineSimilarity(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x, y := benchVector(rnd, benchDim), benchVector(rnd, benchDim)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchSink = cosineSimilarity(x, y)
+	}
+}
+
+// BenchmarkClusterMemories covers both clusterers: inputs above
+// DefaultAgglomerativeMaxItems fall back to greedy clustering.
+func BenchmarkClusterMemories(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		memories := benchMemories(n, 50, 0.01)
+		f := &schemaFormer{}
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchSink = float32(len(f.clusterMemories(memories)))
+			}
+		})
+	}
+}
+
+// BenchmarkMergeRedundantMemories tracks the pairwise O(n²) scan. Half the
+// inputs are near-duplicates, so archives thin out the inner loop as it goes.
+func BenchmarkMergeRedundantMemories(b *testing.B) {
+	for _, n := range []int{500, 2000} {
+		base := benchMemories(n, n/2, 0.005)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				memories := make([]domain.Memory, len(base))
+				copy(memories, base)
+				store := newMockMemoryStore()
+				for j := range memories {
+					m := memories[j]
+					store.memories[m.ID] = &m
+				}
+				svc := &ConsolidationService{memoryStore: store, logger: zap.NewNop()}
+				b.StartTimer()
+
+				benchSink = float32(svc.mergeRedundantMemories(context.Background(), uuid.Nil, uuid.Nil, memories))
+			}
+		})
+	}
+}
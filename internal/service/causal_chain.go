@@ -0,0 +1,237 @@
+package service
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+)
+
+var ErrCausalQueryEmpty = errors.New("query is required")
+
+const (
+	// causalChainEpisodes is how many episodes matching the query are
+	// searched for causal links.
+	causalChainEpisodes = 50
+	// causalChainThreshold is the similarity an episode needs to the query.
+	causalChainThreshold = 0.4
+	// causalMatchThreshold is how similar one link's effect and the next
+	// link's cause must be to join them; identical phrases always join.
+	causalMatchThreshold = 0.85
+	// defaultCausalDepth and maxCausalDepth bound the links in one chain.
+	defaultCausalDepth = 5
+	maxCausalDepth     = 10
+	// defaultCausalChains is how many chains are returned by default.
+	defaultCausalChains = 10
+	// causalPathsPerRoot caps the chains walked from one step, and
+	// causalWalkBudget the steps visited in one request, so a dense causal
+	// graph, where the paths grow exponentially with depth, can't stall it.
+	causalPathsPerRoot = 20
+	causalWalkBudget   = 5000
+)
+
+// CausalChainOpts selects the causal chains to assemble.
+type CausalChainOpts struct {
+	// Query is the outcome or topic the chains should explain.
+	Query string
+	// Outcome keeps only chains whose last link comes from an episode with
+	// this outcome, e.g. failure for a post-mortem. Empty keeps all.
+	Outcome domain.OutcomeType
+	// MaxDepth bounds the links in a chain.
+	MaxDepth int
+	Limit    int
+}
+
+// CausalChains assembles chains of causal links (X led to Y led to Z) from
+// the agent's episodes matching the query. A link follows another when its
+// cause restates the other's effect and its episode did not happen earlier.
+// Longer chains rank first, then more confident ones.
+func (s *EpisodeService) CausalChains(ctx context.Context, agentID, tenantID uuid.UUID, opts CausalChainOpts) ([]domain.CausalChain, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, ErrCausalQueryEmpty
+	}
+	if opts.Outcome != "" && !domain.ValidOutcomeType(string(opts.Outcome)) {
+		return nil, ErrInvalidOutcomeType
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = defaultCausalDepth
+	}
+	opts.MaxDepth = min(opts.MaxDepth, maxCausalDepth)
+	if opts.Limit <= 0 {
+		opts.Limit = defaultCausalChains
+	}
+	if s.embeddingClient == nil {
+		return nil, errors.New("embedding client not configured")
+	}
+	if _, err := s.agentStore.GetByID(ctx, agentID, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+
+	emb, err := s.embeddingClient.Embed(ctx, opts.Query)
+	if err != nil {
+		return nil, err
+	}
+	episodes, err := s.findSimilar(ctx, agentID, tenantID, emb, causalChainThreshold, causalChainEpisodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []domain.CausalStep
+	for _, ep := range episodes {
+		for _, l := range ep.CausalLinks {
+			if strings.TrimSpace(l.Cause) == "" || strings.TrimSpace(l.Effect) == "" {
+				continue
+			}
+			steps = append(steps, domain.CausalStep{
+				Cause: l.Cause, Effect: l.Effect, Confidence: l.Confidence,
+				EpisodeID: ep.ID, OccurredAt: ep.OccurredAt, Outcome: ep.Outcome,
+			})
+		}
+	}
+	if len(steps) == 0 {
+		return []domain.CausalChain{}, nil
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].OccurredAt.Before(steps[j].OccurredAt) })
+
+	next, err := s.linkCausalSteps(ctx, steps)
+	if err != nil {
+		return nil, err
+	}
+	var keep func(domain.CausalChain) bool
+	if opts.Outcome != "" {
+		keep = func(c domain.CausalChain) bool { return c.Steps[len(c.Steps)-1].Outcome == opts.Outcome }
+	}
+	return assembleCausalChains(steps, next, opts.MaxDepth, opts.Limit, keep), nil
+}
+
+// linkCausalSteps returns, for each step (in time order), the later steps
+// whose cause matches its effect.
+func (s *EpisodeService) linkCausalSteps(ctx context.Context, steps []domain.CausalStep) ([][]int, error) {
+	phrases := make(map[string]int)
+	var texts []string
+	for _, st := range steps {
+		for _, p := range []string{st.Cause, st.Effect} {
+			key := normalizeCausalPhrase(p)
+			if _, ok := phrases[key]; !ok {
+				phrases[key] = len(texts)
+				texts = append(texts, key)
+			}
+		}
+	}
+	vecs, err := s.embedPassages(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make([][]int, len(steps))
+	for i, a := range steps {
+		effect := normalizeCausalPhrase(a.Effect)
+		for j := i + 1; j < len(steps); j++ {
+			cause := normalizeCausalPhrase(steps[j].Cause)
+			if effect == cause || cosineSimilarity(vecs[phrases[effect]], vecs[phrases[cause]]) >= causalMatchThreshold {
+				next[i] = append(next[i], j)
+			}
+		}
+	}
+	return next, nil
+}
+
+// assembleCausalChains walks the paths from each step nothing leads to, up to
+// maxDepth steps, and returns the best limit of the maximal ones kept by keep
+// (nil keeps all) as chains, best first. The walk follows the most confident
+// links first and is bounded by causalPathsPerRoot chains per root and
+// causalWalkBudget steps visited, not by how many chains it has found, so a
+// long chain late in the walk still displaces a short one found early.
+func assembleCausalChains(steps []domain.CausalStep, next [][]int, maxDepth, limit int, keep func(domain.CausalChain) bool) []domain.CausalChain {
+	led := make([]bool, len(steps))
+	for _, js := range next {
+		for _, j := range js {
+			led[j] = true
+		}
+		slices.SortStableFunc(js, func(a, b int) int { return cmp.Compare(steps[b].Confidence, steps[a].Confidence) })
+	}
+
+	best := make(causalChainHeap, 0, limit)
+	budget := causalWalkBudget
+	var fromRoot int
+	var walk func(path []int)
+	walk = func(path []int) {
+		if budget <= 0 || fromRoot >= causalPathsPerRoot {
+			return
+		}
+		budget--
+		last := path[len(path)-1]
+		if len(path) < maxDepth && len(next[last]) > 0 {
+			for _, j := range next[last] {
+				walk(append(path, j))
+			}
+			return
+		}
+		chain := domain.CausalChain{Steps: make([]domain.CausalStep, len(path)), Confidence: 1}
+		for k, i := range path {
+			chain.Steps[k] = steps[i]
+			chain.Confidence *= steps[i].Confidence
+		}
+		fromRoot++
+		if keep != nil && !keep(chain) {
+			return
+		}
+		if len(best) < limit {
+			heap.Push(&best, chain)
+		} else if limit > 0 && betterCausalChain(chain, best[0]) {
+			best[0] = chain
+			heap.Fix(&best, 0)
+		}
+	}
+	for i := range steps {
+		if !led[i] {
+			fromRoot = 0
+			walk([]int{i})
+		}
+	}
+
+	chains := make([]domain.CausalChain, len(best))
+	for i := len(chains) - 1; i >= 0; i-- {
+		chains[i] = heap.Pop(&best).(domain.CausalChain)
+	}
+	return chains
+}
+
+// betterCausalChain ranks longer chains first, then more confident ones.
+func betterCausalChain(a, b domain.CausalChain) bool {
+	if len(a.Steps) != len(b.Steps) {
+		return len(a.Steps) > len(b.Steps)
+	}
+	return a.Confidence > b.Confidence
+}
+
+// causalChainHeap holds the best chains found so far with the worst on top,
+// where the next better chain replaces it.
+type causalChainHeap []domain.CausalChain
+
+func (h causalChainHeap) Len() int           { return len(h) }
+func (h causalChainHeap) Less(i, j int) bool { return betterCausalChain(h[j], h[i]) }
+func (h causalChainHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *causalChainHeap) Push(x any)        { *h = append(*h, x.(domain.CausalChain)) }
+func (h *causalChainHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// normalizeCausalPhrase folds case, surrounding space and a trailing period,
+// so restatements of the same event compare equal.
+func normalizeCausalPhrase(s string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// causalEpisodeStore returns every stored episode of the agent as a match.
+type causalEpisodeStore struct {
+	*mockEpisodeStore
+}
+
+func (m *causalEpisodeStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, limit int) ([]domain.EpisodeWithScore, error) {
+	var out []domain.EpisodeWithScore
+	for _, e := range m.episodes {
+		if e.AgentID == agentID {
+			out = append(out, domain.EpisodeWithScore{Episode: *e, Score: 0.9})
+		}
+	}
+	return out, nil
+}
+
+func TestEpisodeService_CausalChainsJoinLinksAcrossEpisodes(t *testing.T) {
+	agentStore := newMockAgentStore()
+	episodes := &causalEpisodeStore{newMockEpisodeStore()}
+	svc := NewEpisodeService(episodes, agentStore, &mockEmbeddingClient{}, newMockLLMClient(), testLogger())
+	ctx := context.Background()
+
+	tenantID := uuid.New()
+	agent := &domain.Agent{TenantID: tenantID, ExternalID: "bot-1", Name: "Test Bot"}
+	_ = agentStore.Create(ctx, agent)
+
+	base := time.Now().Add(-time.Hour)
+	add := func(at time.Time, outcome domain.OutcomeType, links ...domain.CausalLink) {
+		ep := &domain.Episode{AgentID: agent.ID, TenantID: tenantID, OccurredAt: at, Outcome: outcome, CausalLinks: links}
+		_ = episodes.Create(ctx, ep)
+	}
+	add(base, domain.OutcomeSuccess,
+		domain.CausalLink{Cause: "Config flag was removed", Effect: "Cache warmup skipped", Confidence: 0.9})
+	add(base.Add(10*time.Minute), domain.OutcomeFailure,
+		domain.CausalLink{Cause: "cache warmup skipped.", Effect: "Checkout latency spiked", Confidence: 0.5})
+	// Happened before the warmup was skipped, so it cannot follow from it.
+	add(base.Add(-10*time.Minute), domain.OutcomeFailure,
+		domain.CausalLink{Cause: "Cache warmup skipped", Effect: "Stale prices shown", Confidence: 0.8})
+
+	chains, err := svc.CausalChains(ctx, agent.ID, tenantID, CausalChainOpts{Query: "checkout latency"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chains) != 2 {
+		t.Fatalf("expected 2 chains, got %+v", chains)
+	}
+	top := chains[0]
+	if len(top.Steps) != 2 || top.Steps[0].Cause != "Config flag was removed" || top.Steps[1].Effect != "Checkout latency spiked" {
+		t.Fatalf("expected the flag removal to lead to the latency spike, got %+v", top.Steps)
+	}
+	if top.Confidence < 0.449 || top.Confidence > 0.451 {
+		t.Errorf("expected chain confidence 0.45, got %.3f", top.Confidence)
+	}
+	if len(chains[1].Steps) != 1 || chains[1].Steps[0].Effect != "Stale prices shown" {
+		t.Errorf("expected the earlier episode to stand alone, got %+v", chains[1].Steps)
+	}
+
+	chains, _ = svc.CausalChains(ctx, agent.ID, tenantID, CausalChainOpts{Query: "checkout latency", Outcome: domain.OutcomeSuccess})
+	if len(chains) != 0 {
+		t.Errorf("expected no chain ending in a success, got %+v", chains)
+	}
+	chains, _ = svc.CausalChains(ctx, agent.ID, tenantID, CausalChainOpts{Query: "checkout latency", MaxDepth: 1})
+	if len(chains) != 2 || len(chains[0].Steps) != 1 {
+		t.Errorf("expected max_depth 1 to cut chains to single links, got %+v", chains)
+	}
+
+	if _, err := svc.CausalChains(ctx, agent.ID, tenantID, CausalChainOpts{}); !errors.Is(err, ErrCausalQueryEmpty) {
+		t.Errorf("expected ErrCausalQueryEmpty, got %v", err)
+	}
+	if _, err := svc.CausalChains(ctx, uuid.New(), tenantID, CausalChainOpts{Query: "x"}); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("expected ErrAgentNotFound, got %v", err)
+	}
+}
+
+func TestAssembleCausalChainsBoundsDenseFanOut(t *testing.T) {
+	// Every step's effect is every later step's cause: walked in full, the
+	// paths of up to 10 steps from the first step alone number in the
+	// billions.
+	const n = 60
+	base := time.Now()
+	steps := make([]domain.CausalStep, n)
+	next := make([][]int, n)
+	for i := range steps {
+		steps[i] = domain.CausalStep{Cause: "x", Effect: "x", Confidence: 0.5 + float32(i%5)/10, OccurredAt: base.Add(time.Duration(i) * time.Minute)}
+		for j := i + 1; j < n; j++ {
+			next[i] = append(next[i], j)
+		}
+	}
+
+	done := make(chan []domain.CausalChain, 1)
+	go func() { done <- assembleCausalChains(steps, next, maxCausalDepth, 40, nil) }()
+	var chains []domain.CausalChain
+	select {
+	case chains = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("walk over a dense fan-out did not finish")
+	}
+	if len(chains) == 0 || len(chains) > causalPathsPerRoot {
+		t.Fatalf("expected between 1 and %d chains from the single root, got %d", causalPathsPerRoot, len(chains))
+	}
+	for _, c := range chains {
+		if len(c.Steps) != maxCausalDepth {
+			t.Fatalf("expected depth-first chains of %d steps, got %d", maxCausalDepth, len(c.Steps))
+		}
+	}
+	// The most confident link is followed first.
+	if got := chains[0].Steps[1].Confidence; got < 0.89 {
+		t.Errorf("expected the walk to follow the most confident link first, got %.2f", got)
+	}
+
+	top := assembleCausalChains(steps, next, maxCausalDepth, 3, nil)
+	if len(top) != 3 {
+		t.Fatalf("expected the best 3 chains, got %d", len(top))
+	}
+	for i := range top {
+		if top[i].Confidence != chains[i].Confidence {
+			t.Errorf("chain %d: expected confidence %v, got %v", i, chains[i].Confidence, top[i].Confidence)
+		}
+	}
+}
+
+func TestAssembleCausalChainsKeepsLongChainsFoundLate(t *testing.T) {
+	// Ten unconnected steps come first; the only three-step chain is walked
+	// last.
+	base := time.Now()
+	var steps []domain.CausalStep
+	for i := 0; i < 13; i++ {
+		steps = append(steps, domain.CausalStep{Cause: "c", Effect: "e", Confidence: 0.9, OccurredAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+	next := make([][]int, len(steps))
+	next[10] = []int{11}
+	next[11] = []int{12}
+
+	chains := assembleCausalChains(steps, next, maxCausalDepth, 1, nil)
+	if len(chains) != 1 || len(chains[0].Steps) != 3 {
+		t.Fatalf("expected the three-step chain, got %+v", chains)
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrChatNotConfigured = errors.New("chat completion is not available with the configured LLM provider")
+	ErrChatStreaming     = errors.New("streaming chat completions are not supported")
+	ErrChatNoMessages    = errors.New("messages are required")
+	ErrChatInvalid       = errors.New("invalid chat completion request")
+)
+
+// chatPreamble introduces the assembled context in the injected system
+// message.
+const chatPreamble = "The following is what you remember about this user and conversation. Use it where it is relevant; do not mention that it was provided.\n\n"
+
+// ContextActivator assembles the working memory context for a turn.
+type ContextActivator interface {
+	Activate(ctx context.Context, input domain.ActivationInput) (*domain.WorkingMemoryResult, error)
+}
+
+// ConversationExtractor extracts, and optionally stores, memories from a
+// conversation.
+type ConversationExtractor interface {
+	Extract(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, conversation []domain.Message, autoStore bool) ([]ExtractResult, error)
+}
+
+// EpisodeEncoder records an episode.
+type EpisodeEncoder interface {
+	Encode(ctx context.Context, input EncodeInput) (*domain.Episode, error)
+}
+
+// ChatService proxies chat completions through the agent's memory: each
+// request gets the agent's working memory context as a system preamble, and
+// each exchange is captured afterwards as an episode and extracted memories.
+type ChatService struct {
+	completer  domain.ChatCompleter
+	agentStore domain.AgentStore
+	activator  ContextActivator
+	extractor  ConversationExtractor
+	episodes   EpisodeEncoder
+	jobs       *JobPool
+	logger     *zap.Logger
+}
+
+// NewChatService creates the chat proxy. completer may be nil, in which case
+// Complete returns ErrChatNotConfigured.
+func NewChatService(completer domain.ChatCompleter, agentStore domain.AgentStore, activator ContextActivator, extractor ConversationExtractor, episodes EpisodeEncoder, jobs *JobPool, logger *zap.Logger) *ChatService {
+	return &ChatService{
+		completer:  completer,
+		agentStore: agentStore,
+		activator:  activator,
+		extractor:  extractor,
+		episodes:   episodes,
+		jobs:       jobs,
+		logger:     logger,
+	}
+}
+
+// ChatInput is an OpenAI-format chat completion request for an agent.
+// ConversationID, if set, ties the activations and the captured episode to a
+// conversation.
+type ChatInput struct {
+	AgentID        uuid.UUID
+	TenantID       uuid.UUID
+	Request        json.RawMessage
+	ConversationID *uuid.UUID
+}
+
+// ChatResult is the provider's response, unchanged. ContextItems is how many
+// activated memories went into the preamble; CaptureJob is the background job
+// recording the exchange, nil when there was nothing to capture or the job
+// queue was full.
+type ChatResult struct {
+	Response     json.RawMessage
+	ContextItems int
+	CaptureJob   *domain.Job
+}
+
+// ChatCapture is the result of a capture job.
+type ChatCapture struct {
+	EpisodeID uuid.UUID       `json:"episode_id"`
+	Extracted []ExtractResult `json:"extracted"`
+}
+
+type chatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// Complete injects context, forwards the request and queues the capture of
+// the new exchange. Only the turns after the last assistant message are
+// captured, since clients resend the whole history on every call.
+func (s *ChatService) Complete(ctx context.Context, input ChatInput) (*ChatResult, error) {
+	if s.completer == nil {
+		return nil, ErrChatNotConfigured
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(input.Request, &fields); err != nil || fields == nil {
+		return nil, ErrChatInvalid
+	}
+	var stream bool
+	_ = json.Unmarshal(fields["stream"], &stream)
+	if stream {
+		return nil, ErrChatStreaming
+	}
+	var rawMessages []json.RawMessage
+	if err := json.Unmarshal(fields["messages"], &rawMessages); err != nil {
+		return nil, ErrChatInvalid
+	}
+	if len(rawMessages) == 0 {
+		return nil, ErrChatNoMessages
+	}
+	messages := make([]chatMessage, len(rawMessages))
+	for i, raw := range rawMessages {
+		if err := json.Unmarshal(raw, &messages[i]); err != nil {
+			return nil, ErrChatInvalid
+		}
+	}
+
+	if _, err := s.agentStore.GetByID(ctx, input.AgentID, input.TenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+
+	turn := newTurn(messages)
+	result := &ChatResult{}
+
+	if cue := lastUserText(turn); cue != "" {
+		wm, err := s.activator.Activate(ctx, domain.ActivationInput{
+			AgentID:        input.AgentID,
+			TenantID:       input.TenantID,
+			Cues:           []string{cue},
+			Context:        windowMessages(messages),
+			ConversationID: input.ConversationID,
+		})
+		if err != nil {
+			// Memory is an enhancement here; the chat still goes through.
+			logFor(ctx, s.logger).Warn("chat context activation failed", zap.Error(err))
+		} else if wm.AssembledContext != "" {
+			preamble, _ := json.Marshal(map[string]string{"role": "system", "content": chatPreamble + wm.AssembledContext})
+			rawMessages = append([]json.RawMessage{preamble}, rawMessages...)
+			fields["messages"], _ = json.Marshal(rawMessages)
+			result.ContextItems = len(wm.Activations)
+		}
+	}
+
+	request, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("marshal chat request: %w", err)
+	}
+	response, err := s.completer.CompleteChat(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	result.Response = response
+
+	exchange := turnMessages(turn)
+	if len(exchange) == 0 {
+		return result, nil
+	}
+	if reply := replyText(response); reply != "" {
+		exchange = append(exchange, domain.Message{Role: "assistant", Content: reply})
+	}
+	result.CaptureJob = s.capture(ctx, input, exchange)
+	return result, nil
+}
+
+// capture queues recording the exchange as an episode and extracting its
+// memories. A full queue only costs the capture, so it is logged, not
+// returned.
+func (s *ChatService) capture(ctx context.Context, input ChatInput, exchange []domain.Message) *domain.Job {
+	job, err := s.jobs.Submit(ctx, input.TenantID, input.AgentID, domain.JobKindChatCapture, ExtractionJobTimeout, func(ctx context.Context) (any, error) {
+		var out ChatCapture
+		episode, err := s.episodes.Encode(ctx, EncodeInput{
+			AgentID:        input.AgentID,
+			TenantID:       input.TenantID,
+			RawContent:     formatExchange(exchange),
+			ConversationID: input.ConversationID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("record episode: %w", err)
+		}
+		out.EpisodeID = episode.ID
+		if out.Extracted, err = s.extractor.Extract(ctx, input.AgentID, input.TenantID, exchange, true); err != nil {
+			return nil, fmt.Errorf("extract memories: %w", err)
+		}
+		if out.Extracted == nil {
+			out.Extracted = []ExtractResult{}
+		}
+		return out, nil
+	})
+	if err != nil {
+		logFor(ctx, s.logger).Warn("failed to queue chat capture", zap.String("agent_id", input.AgentID.String()), zap.Error(err))
+		return nil
+	}
+	return job
+}
+
+// newTurn returns the messages after the last assistant message.
+func newTurn(messages []chatMessage) []chatMessage {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "assistant" {
+			return messages[i+1:]
+		}
+	}
+	return messages
+}
+
+// turnMessages keeps the user turns with text content.
+func turnMessages(turn []chatMessage) []domain.Message {
+	var out []domain.Message
+	for _, m := range turn {
+		if m.Role != "user" {
+			continue
+		}
+		if text := messageText(m.Content); text != "" {
+			out = append(out, domain.Message{Role: m.Role, Content: text})
+		}
+	}
+	return out
+}
+
+// windowMessages is the text of the whole request history, which the model
+// will see anyway, so activation needn't repeat any of it.
+func windowMessages(messages []chatMessage) []domain.Message {
+	var out []domain.Message
+	for _, m := range messages {
+		if text := messageText(m.Content); text != "" {
+			out = append(out, domain.Message{Role: m.Role, Content: text})
+		}
+	}
+	return out
+}
+
+func lastUserText(turn []chatMessage) string {
+	msgs := turnMessages(turn)
+	if len(msgs) == 0 {
+		return ""
+	}
+	return msgs[len(msgs)-1].Content
+}
+
+// messageText returns a message's text, which is either a string or an array
+// of content parts of which only the text parts count.
+func messageText(content json.RawMessage) string {
+	var s string
+	if json.Unmarshal(content, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &parts) != nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range parts {
+		if p.Type == "text" && strings.TrimSpace(p.Text) != "" {
+			texts = append(texts, strings.TrimSpace(p.Text))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// replyText returns the first choice's text, empty for tool-call replies.
+func replyText(response json.RawMessage) string {
+	var resp struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if json.Unmarshal(response, &resp) != nil || len(resp.Choices) == 0 {
+		return ""
+	}
+	return messageText(resp.Choices[0].Message.Content)
+}
+
+func formatExchange(exchange []domain.Message) string {
+	var b strings.Builder
+	for i, m := range exchange {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(m.Role + ": " + m.Content)
+	}
+	return b.String()
+}
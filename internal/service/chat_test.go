@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type fakeChatCompleter struct {
+	requests []json.RawMessage
+	response json.RawMessage
+	err      error
+}
+
+func (f *fakeChatCompleter) CompleteChat(ctx context.Context, request json.RawMessage) (json.RawMessage, error) {
+	f.requests = append(f.requests, request)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.response, nil
+}
+
+type fakeActivator struct {
+	inputs []domain.ActivationInput
+	result *domain.WorkingMemoryResult
+}
+
+func (f *fakeActivator) Activate(ctx context.Context, input domain.ActivationInput) (*domain.WorkingMemoryResult, error) {
+	f.inputs = append(f.inputs, input)
+	return f.result, nil
+}
+
+type fakeChatCapture struct {
+	mu           sync.Mutex
+	episodes     []EncodeInput
+	conversation []domain.Message
+}
+
+func (f *fakeChatCapture) Encode(ctx context.Context, input EncodeInput) (*domain.Episode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.episodes = append(f.episodes, input)
+	return &domain.Episode{ID: uuid.New()}, nil
+}
+
+func (f *fakeChatCapture) Extract(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, conversation []domain.Message, autoStore bool) ([]ExtractResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.conversation = conversation
+	return []ExtractResult{{Content: "prefers tea", Stored: autoStore}}, nil
+}
+
+func newChatTestService(t *testing.T, completer domain.ChatCompleter, activator ContextActivator, capture *fakeChatCapture) (*ChatService, *domain.Agent) {
+	t.Helper()
+	agents := newMockAgentStore()
+	agent := &domain.Agent{TenantID: uuid.New(), ExternalID: "bot"}
+	_ = agents.Create(context.Background(), agent)
+	jobs := NewJobPool(1, 4, testLogger())
+	jobs.Start()
+	t.Cleanup(jobs.Stop)
+	return NewChatService(completer, agents, activator, capture, capture, jobs, testLogger()), agent
+}
+
+const chatReply = `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"Green tea it is."}}]}`
+
+func TestChatService_InjectsContextAndCapturesTurn(t *testing.T) {
+	completer := &fakeChatCompleter{response: json.RawMessage(chatReply)}
+	activator := &fakeActivator{result: &domain.WorkingMemoryResult{
+		Activations:      []domain.ActivatedContent{{Content: "User prefers tea"}},
+		AssembledContext: "- User prefers tea",
+	}}
+	capture := &fakeChatCapture{}
+	svc, agent := newChatTestService(t, completer, activator, capture)
+
+	request := `{"model":"gpt-4o","temperature":0.2,"messages":[
+		{"role":"system","content":"Be brief."},
+		{"role":"user","content":"Hi"},
+		{"role":"assistant","content":"Hello!"},
+		{"role":"user","content":[{"type":"text","text":"What should I drink?"}]}]}`
+	result, err := svc.Complete(context.Background(), ChatInput{AgentID: agent.ID, TenantID: agent.TenantID, Request: json.RawMessage(request)})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if string(result.Response) != chatReply {
+		t.Errorf("Response = %s, want the provider's body unchanged", result.Response)
+	}
+	if result.ContextItems != 1 {
+		t.Errorf("ContextItems = %d, want 1", result.ContextItems)
+	}
+	if len(activator.inputs) != 1 || activator.inputs[0].Cues[0] != "What should I drink?" {
+		t.Errorf("activation inputs = %+v", activator.inputs)
+	}
+	if window := activator.inputs[0].Context; len(window) != 4 || window[1].Content != "Hi" || window[3].Content != "What should I drink?" {
+		t.Errorf("activation context = %+v, want the request history", window)
+	}
+
+	var sent struct {
+		Model       string        `json:"model"`
+		Temperature float64       `json:"temperature"`
+		Messages    []chatMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(completer.requests[0], &sent); err != nil {
+		t.Fatalf("forwarded request: %v", err)
+	}
+	if sent.Model != "gpt-4o" || sent.Temperature != 0.2 {
+		t.Errorf("request fields not preserved: %+v", sent)
+	}
+	if len(sent.Messages) != 5 || sent.Messages[0].Role != "system" || !strings.Contains(messageText(sent.Messages[0].Content), "- User prefers tea") {
+		t.Fatalf("messages = %+v, want the context preamble first", sent.Messages)
+	}
+
+	if result.CaptureJob == nil {
+		t.Fatal("no capture job")
+	}
+	job := waitJob(t, svc.jobs, result.CaptureJob.ID)
+	if job.Status != domain.JobSucceeded || job.Kind != domain.JobKindChatCapture {
+		t.Fatalf("capture job = %+v", job)
+	}
+	want := "user: What should I drink?\nassistant: Green tea it is."
+	if len(capture.episodes) != 1 || capture.episodes[0].RawContent != want {
+		t.Errorf("episodes = %+v, want the last exchange only", capture.episodes)
+	}
+	if len(capture.conversation) != 2 || capture.conversation[1].Content != "Green tea it is." {
+		t.Errorf("extracted conversation = %+v", capture.conversation)
+	}
+}
+
+func TestChatService_NoContextLeavesRequestMessages(t *testing.T) {
+	completer := &fakeChatCompleter{response: json.RawMessage(chatReply)}
+	svc, agent := newChatTestService(t, completer, &fakeActivator{result: &domain.WorkingMemoryResult{}}, &fakeChatCapture{})
+
+	_, err := svc.Complete(context.Background(), ChatInput{AgentID: agent.ID, TenantID: agent.TenantID,
+		Request: json.RawMessage(`{"messages":[{"role":"user","content":"Hi"}]}`)})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	var sent struct {
+		Messages []domain.Message `json:"messages"`
+	}
+	_ = json.Unmarshal(completer.requests[0], &sent)
+	if len(sent.Messages) != 1 || sent.Messages[0].Role != "user" {
+		t.Errorf("messages = %+v", sent.Messages)
+	}
+}
+
+func TestChatService_Errors(t *testing.T) {
+	upstream := &domain.ChatUpstreamError{StatusCode: 429, Body: []byte(`{"error":{"message":"slow down"}}`)}
+	capture := &fakeChatCapture{}
+	svc, agent := newChatTestService(t, &fakeChatCompleter{err: upstream}, &fakeActivator{result: &domain.WorkingMemoryResult{}}, capture)
+	ok := `{"messages":[{"role":"user","content":"Hi"}]}`
+
+	tests := []struct {
+		name    string
+		agentID uuid.UUID
+		request string
+		want    error
+	}{
+		{"invalid json", agent.ID, `{"messages":`, ErrChatInvalid},
+		{"no messages", agent.ID, `{"messages":[]}`, ErrChatNoMessages},
+		{"streaming", agent.ID, `{"stream":true,"messages":[{"role":"user","content":"Hi"}]}`, ErrChatStreaming},
+		{"unknown agent", uuid.New(), ok, ErrAgentNotFound},
+		{"upstream error", agent.ID, ok, upstream},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Complete(context.Background(), ChatInput{AgentID: tt.agentID, TenantID: agent.TenantID, Request: json.RawMessage(tt.request)})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if len(capture.episodes) != 0 {
+		t.Error("failed calls must not be captured")
+	}
+
+	unconfigured := NewChatService(nil, newMockAgentStore(), nil, nil, nil, nil, testLogger())
+	if _, err := unconfigured.Complete(context.Background(), ChatInput{Request: json.RawMessage(ok)}); !errors.Is(err, ErrChatNotConfigured) {
+		t.Errorf("err = %v, want ErrChatNotConfigured", err)
+	}
+}
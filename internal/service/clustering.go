@@ -0,0 +1,279 @@
+package service
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultAgglomerativeMaxItems bounds the pairwise similarity matrix the
+// agglomerative clusterer builds (n² / 2 float32s, ~50MB at 5000 items).
+// Larger inputs fall back to greedy clustering.
+const DefaultAgglomerativeMaxItems = 5000
+
+// Clusterer groups memories into clusters of semantically related items.
+// Implementations must be deterministic: the same set of memories yields the
+// same clusters regardless of input order. Memories without embeddings are
+// ignored. Returned clusters have Memories, MemoryIDs and Centroid set.
+type Clusterer interface {
+	Cluster(memories []domain.Memory) []domain.MemoryCluster
+}
+
+// GreedyClusterer is the original single-pass clustering: each unassigned
+// memory seeds a cluster and absorbs every later memory within Threshold of
+// the running centroid. It is cheap but order-dependent, so input is sorted
+// by ID first to keep results stable.
+type GreedyClusterer struct {
+	Threshold float32
+}
+
+// NewGreedyClusterer creates a greedy clusterer with the given cosine
+// similarity threshold.
+func NewGreedyClusterer(threshold float32) *GreedyClusterer {
+	return &GreedyClusterer{Threshold: threshold}
+}
+
+// Cluster implements Clusterer.
+func (c *GreedyClusterer) Cluster(memories []domain.Memory) []domain.MemoryCluster {
+	items := sortedWithEmbeddings(memories)
+
+	assigned := make([]bool, len(items))
+	var clusters []domain.MemoryCluster
+
+	for i, seed := range items {
+		if assigned[i] {
+			continue
+		}
+
+		cluster := domain.MemoryCluster{
+			Memories:  []domain.Memory{seed},
+			MemoryIDs: []uuid.UUID{seed.ID},
+			Centroid:  cloneVector(seed.Embedding),
+		}
+		assigned[i] = true
+
+		for j := i + 1; j < len(items); j++ {
+			if assigned[j] {
+				continue
+			}
+			candidate := items[j]
+
+			if cosineSimilarity(cluster.Centroid, candidate.Embedding) >= c.Threshold {
+				cluster.Memories = append(cluster.Memories, candidate)
+				cluster.MemoryIDs = append(cluster.MemoryIDs, candidate.ID)
+				assigned[j] = true
+
+				cluster.Centroid = incrementalMean(cluster.Centroid, candidate.Embedding, len(cluster.Memories))
+			}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
+
+// AgglomerativeClusterer performs average-linkage hierarchical clustering on
+// cosine similarity and cuts the dendrogram at Threshold: two clusters are
+// joined only if the mean pairwise similarity between their members is at
+// least Threshold. Unlike greedy clustering, no memory's placement depends on
+// which memory happened to seed a cluster.
+//
+// The dendrogram is built with the nearest-neighbor chain algorithm, which is
+// O(n²) time for reducible linkages such as average linkage.
+type AgglomerativeClusterer struct {
+	Threshold float32
+	// MaxItems caps input size for the O(n²) similarity matrix; above it the
+	// clusterer falls back to greedy clustering. Zero means
+	// DefaultAgglomerativeMaxItems.
+	MaxItems int
+}
+
+// NewAgglomerativeClusterer creates an average-linkage clusterer with the
+// given cosine similarity threshold.
+func NewAgglomerativeClusterer(threshold float32) *AgglomerativeClusterer {
+	return &AgglomerativeClusterer{Threshold: threshold}
+}
+
+// Cluster implements Clusterer.
+func (c *AgglomerativeClusterer) Cluster(memories []domain.Memory) []domain.MemoryCluster {
+	items := sortedWithEmbeddings(memories)
+	n := len(items)
+	if n == 0 {
+		return nil
+	}
+
+	maxItems := c.MaxItems
+	if maxItems <= 0 {
+		maxItems = DefaultAgglomerativeMaxItems
+	}
+	if n > maxItems {
+		return NewGreedyClusterer(c.Threshold).Cluster(items)
+	}
+
+	sim := newCondensedMatrix(n)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			sim.set(i, j, cosineSimilarity(items[i].Embedding, items[j].Embedding))
+		}
+	}
+
+	// Slot i always holds the cluster containing item i, so merges can be
+	// replayed over item indices with a union-find afterwards.
+	size := make([]int, n)
+	active := make([]bool, n)
+	for i := range size {
+		size[i] = 1
+		active[i] = true
+	}
+
+	type merge struct {
+		a, b int
+		sim  float32
+	}
+	merges := make([]merge, 0, n-1)
+	chain := make([]int, 0, n)
+	remaining := n
+
+	for remaining > 1 {
+		if len(chain) == 0 {
+			for i := 0; i < n; i++ {
+				if active[i] {
+					chain = append(chain, i)
+					break
+				}
+			}
+		}
+
+		a := chain[len(chain)-1]
+
+		// Nearest neighbor of a; ties prefer the previous chain element (which
+		// guarantees termination), then the lowest index.
+		best, bestSim := -1, float32(0)
+		if len(chain) >= 2 {
+			best = chain[len(chain)-2]
+			bestSim = sim.get(a, best)
+		}
+		for k := 0; k < n; k++ {
+			if !active[k] || k == a {
+				continue
+			}
+			if s := sim.get(a, k); best == -1 || s > bestSim {
+				best, bestSim = k, s
+			}
+		}
+
+		if len(chain) >= 2 && best == chain[len(chain)-2] {
+			chain = chain[:len(chain)-2]
+
+			keep, drop := a, best
+			if drop < keep {
+				keep, drop = drop, keep
+			}
+			merges = append(merges, merge{a: keep, b: drop, sim: bestSim})
+
+			// Lance-Williams update for average linkage.
+			na, nb := float32(size[keep]), float32(size[drop])
+			for k := 0; k < n; k++ {
+				if !active[k] || k == keep || k == drop {
+					continue
+				}
+				sim.set(keep, k, (na*sim.get(keep, k)+nb*sim.get(drop, k))/(na+nb))
+			}
+			size[keep] += size[drop]
+			active[drop] = false
+			remaining--
+			continue
+		}
+
+		chain = append(chain, best)
+	}
+
+	// Average linkage is monotone, so cutting at Threshold keeps exactly the
+	// merges at or above it.
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(x int) int {
+		for parent[x] != x {
+			parent[x] = parent[parent[x]]
+			x = parent[x]
+		}
+		return x
+	}
+	for _, m := range merges {
+		if m.sim < c.Threshold {
+			continue
+		}
+		ra, rb := find(m.a), find(m.b)
+		if ra == rb {
+			continue
+		}
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		parent[rb] = ra
+	}
+
+	// Build clusters ordered by their lowest member index.
+	index := make(map[int]int)
+	var clusters []domain.MemoryCluster
+	for i, m := range items {
+		root := find(i)
+		ci, ok := index[root]
+		if !ok {
+			ci = len(clusters)
+			index[root] = ci
+			clusters = append(clusters, domain.MemoryCluster{Centroid: cloneVector(m.Embedding)})
+		} else {
+			clusters[ci].Centroid = incrementalMean(clusters[ci].Centroid, m.Embedding, len(clusters[ci].Memories)+1)
+		}
+		clusters[ci].Memories = append(clusters[ci].Memories, m)
+		clusters[ci].MemoryIDs = append(clusters[ci].MemoryIDs, m.ID)
+	}
+
+	return clusters
+}
+
+// sortedWithEmbeddings returns the memories that have embeddings, ordered by
+// ID so clustering does not depend on the order the store returned them in.
+func sortedWithEmbeddings(memories []domain.Memory) []domain.Memory {
+	items := make([]domain.Memory, 0, len(memories))
+	for _, m := range memories {
+		if len(m.Embedding) > 0 {
+			items = append(items, m)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return bytes.Compare(items[i].ID[:], items[j].ID[:]) < 0
+	})
+	return items
+}
+
+// condensedMatrix stores a symmetric n×n matrix as its upper triangle.
+type condensedMatrix struct {
+	n    int
+	data []float32
+}
+
+func newCondensedMatrix(n int) *condensedMatrix {
+	return &condensedMatrix{n: n, data: make([]float32, n*(n-1)/2)}
+}
+
+func (m *condensedMatrix) offset(i, j int) int {
+	if i > j {
+		i, j = j, i
+	}
+	return i*(2*m.n-i-1)/2 + (j - i - 1)
+}
+
+func (m *condensedMatrix) get(i, j int) float32 {
+	return m.data[m.offset(i, j)]
+}
+
+func (m *condensedMatrix) set(i, j int, v float32) {
+	m.data[m.offset(i, j)] = v
+}
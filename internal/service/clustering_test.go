@@ -0,0 +1,211 @@
+package service
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// syntheticTopics generates perTopic noisy memories around each of topics
+// random directions, returning the memories and their true topic label.
+func syntheticTopics(seed int64, topics, perTopic, dim int, noise float64) ([]domain.Memory, map[uuid.UUID]int) {
+	rng := rand.New(rand.NewSource(seed))
+
+	centers := make([][]float64, topics)
+	for t := range centers {
+		centers[t] = make([]float64, dim)
+		for d := range centers[t] {
+			centers[t][d] = rng.NormFloat64()
+		}
+	}
+
+	var memories []domain.Memory
+	labels := make(map[uuid.UUID]int)
+	for t, c := range centers {
+		for i := 0; i < perTopic; i++ {
+			emb := make([]float32, dim)
+			for d := range emb {
+				emb[d] = float32(c[d] + rng.NormFloat64()*noise)
+			}
+			// IDs come from the seeded source so the ID-sorted order, and
+			// therefore every clusterer's output, is reproducible.
+			id, _ := uuid.NewRandomFromReader(rng)
+			memories = append(memories, domain.Memory{ID: id, Embedding: emb})
+			labels[id] = t
+		}
+	}
+
+	rng.Shuffle(len(memories), func(i, j int) { memories[i], memories[j] = memories[j], memories[i] })
+	return memories, labels
+}
+
+// pairwiseF1 scores a clustering against ground-truth labels: precision is
+// the fraction of same-cluster pairs that share a label, recall the fraction
+// of same-label pairs placed in one cluster.
+func pairwiseF1(clusters []domain.MemoryCluster, labels map[uuid.UUID]int) float64 {
+	assignment := make(map[uuid.UUID]int)
+	for ci, c := range clusters {
+		for _, id := range c.MemoryIDs {
+			assignment[id] = ci
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(labels))
+	for id := range labels {
+		ids = append(ids, id)
+	}
+
+	var tp, fp, fn float64
+	for i := 0; i < len(ids); i++ {
+		for j := i + 1; j < len(ids); j++ {
+			sameCluster := assignment[ids[i]] == assignment[ids[j]]
+			sameLabel := labels[ids[i]] == labels[ids[j]]
+			switch {
+			case sameCluster && sameLabel:
+				tp++
+			case sameCluster:
+				fp++
+			case sameLabel:
+				fn++
+			}
+		}
+	}
+	if tp == 0 {
+		return 0
+	}
+	precision := tp / (tp + fp)
+	recall := tp / (tp + fn)
+	return 2 * precision * recall / (precision + recall)
+}
+
+func clusterSignature(clusters []domain.MemoryCluster) [][]uuid.UUID {
+	sig := make([][]uuid.UUID, len(clusters))
+	for i, c := range clusters {
+		sig[i] = append([]uuid.UUID(nil), c.MemoryIDs...)
+	}
+	return sig
+}
+
+func TestAgglomerativeClusterer_Deterministic(t *testing.T) {
+	memories, _ := syntheticTopics(7, 4, 10, 16, 0.6)
+	c := NewAgglomerativeClusterer(ClusteringThreshold)
+
+	want := clusterSignature(c.Cluster(memories))
+
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 5; run++ {
+		shuffled := append([]domain.Memory(nil), memories...)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		got := clusterSignature(c.Cluster(shuffled))
+		if len(got) != len(want) {
+			t.Fatalf("run %d: expected %d clusters, got %d", run, len(want), len(got))
+		}
+		for i := range want {
+			if len(got[i]) != len(want[i]) {
+				t.Fatalf("run %d: cluster %d size changed from %d to %d", run, i, len(want[i]), len(got[i]))
+			}
+			for j := range want[i] {
+				if got[i][j] != want[i][j] {
+					t.Fatalf("run %d: cluster %d membership changed with input order", run, i)
+				}
+			}
+		}
+	}
+}
+
+func TestAgglomerativeClusterer_QualityVersusGreedy(t *testing.T) {
+	greedy := NewGreedyClusterer(ClusteringThreshold)
+	agglomerative := NewAgglomerativeClusterer(ClusteringThreshold)
+
+	// Low-dimensional topics sit close enough together that the greedy
+	// pass's drifting centroid regularly swallows neighbouring topics.
+	var greedyTotal, agglomerativeTotal float64
+	const trials = 10
+	for seed := int64(0); seed < trials; seed++ {
+		memories, labels := syntheticTopics(seed, 5, 12, 8, 0.3)
+
+		g := pairwiseF1(greedy.Cluster(memories), labels)
+		a := pairwiseF1(agglomerative.Cluster(memories), labels)
+		greedyTotal += g
+		agglomerativeTotal += a
+	}
+
+	greedyAvg := greedyTotal / trials
+	agglomerativeAvg := agglomerativeTotal / trials
+	t.Logf("pairwise F1: greedy=%.3f agglomerative=%.3f", greedyAvg, agglomerativeAvg)
+
+	if agglomerativeAvg <= greedyAvg {
+		t.Fatalf("expected agglomerative F1 (%.3f) > greedy F1 (%.3f)", agglomerativeAvg, greedyAvg)
+	}
+	if agglomerativeAvg < 0.9 {
+		t.Fatalf("expected agglomerative F1 >= 0.9, got %.3f", agglomerativeAvg)
+	}
+}
+
+// TestAgglomerativeClusterer_NoCentroidDrift covers the failure mode of the
+// greedy pass: a chain of memories each close to the next lets the running
+// centroid drift until one cluster spans items that are unrelated to each
+// other. Average linkage keeps every cluster's mean pairwise similarity at or
+// above the threshold.
+func TestAgglomerativeClusterer_NoCentroidDrift(t *testing.T) {
+	var memories []domain.Memory
+	for deg := 0; deg <= 120; deg += 15 {
+		rad := float64(deg) * math.Pi / 180
+		memories = append(memories, domain.Memory{
+			ID:        uuid.New(),
+			Embedding: []float32{float32(math.Cos(rad)), float32(math.Sin(rad))},
+		})
+	}
+
+	meanPairwise := func(c domain.MemoryCluster) float32 {
+		if len(c.Memories) < 2 {
+			return 1
+		}
+		var sum float32
+		var pairs int
+		for i := range c.Memories {
+			for j := i + 1; j < len(c.Memories); j++ {
+				sum += cosineSimilarity(c.Memories[i].Embedding, c.Memories[j].Embedding)
+				pairs++
+			}
+		}
+		return sum / float32(pairs)
+	}
+
+	for _, c := range NewAgglomerativeClusterer(ClusteringThreshold).Cluster(memories) {
+		if got := meanPairwise(c); got < ClusteringThreshold {
+			t.Fatalf("cluster of %d has mean pairwise similarity %.3f below threshold", len(c.Memories), got)
+		}
+	}
+}
+
+func TestAgglomerativeClusterer_FallsBackAboveMaxItems(t *testing.T) {
+	memories, _ := syntheticTopics(3, 3, 5, 8, 0.3)
+	c := &AgglomerativeClusterer{Threshold: ClusteringThreshold, MaxItems: 4}
+
+	got := clusterSignature(c.Cluster(memories))
+	want := clusterSignature(NewGreedyClusterer(ClusteringThreshold).Cluster(memories))
+	if len(got) != len(want) {
+		t.Fatalf("expected greedy fallback with %d clusters, got %d", len(want), len(got))
+	}
+}
+
+func TestClusterers_SkipMemoriesWithoutEmbeddings(t *testing.T) {
+	memories := []domain.Memory{
+		{ID: uuid.New(), Embedding: []float32{1, 0}},
+		{ID: uuid.New()},
+	}
+	for name, c := range map[string]Clusterer{
+		"greedy":        NewGreedyClusterer(ClusteringThreshold),
+		"agglomerative": NewAgglomerativeClusterer(ClusteringThreshold),
+	} {
+		clusters := c.Cluster(memories)
+		if len(clusters) != 1 || len(clusters[0].Memories) != 1 {
+			t.Fatalf("%s: expected one single-member cluster, got %v", name, clusterSignature(clusters))
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultColdSummaryInterval = 10 * time.Minute
+	ColdSummaryMinMembers      = 2    // Minimum related cold memories to summarize together
+	ColdSummaryMaxPending      = 200  // Per-agent cap on queued memories between flushes
+	WarmSummaryConfidence      = 0.78 // Lands summaries in the warm tier
+)
+
+// ColdSummarizer receives cold-tier memories that recall returned so they can
+// be condensed into warm summaries in the background.
+type ColdSummarizer interface {
+	Enqueue(m domain.Memory)
+}
+
+// ColdSummaryService batches accessed cold and archive tier memories per agent
+// and periodically rolls related ones into a warm summary memory. The
+// originals are kept and linked from the summary's metadata, so recall returns
+// the summary in their place while they remain individually retrievable.
+type ColdSummaryService struct {
+	memoryStore     domain.MemoryStore
+	embeddingClient domain.EmbeddingClient
+	llmClient       domain.LLMClient
+	logger          *zap.Logger
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]map[uuid.UUID]bool // agent ID -> queued memory IDs
+	tenants map[uuid.UUID]uuid.UUID          // agent ID -> tenant ID, for routing the flush
+
+	interval   time.Duration
+	stopCh     chan struct{}
+	cancelRuns context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewColdSummaryService creates a new cold-tier summarization worker.
+func NewColdSummaryService(ms domain.MemoryStore, ec domain.EmbeddingClient, lc domain.LLMClient, logger *zap.Logger) *ColdSummaryService {
+	return &ColdSummaryService{
+		memoryStore:     ms,
+		embeddingClient: ec,
+		llmClient:       lc,
+		logger:          logger,
+		pending:         make(map[uuid.UUID]map[uuid.UUID]bool),
+		tenants:         make(map[uuid.UUID]uuid.UUID),
+		interval:        defaultColdSummaryInterval,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// SetInterval sets how often queued memories are summarized.
+func (s *ColdSummaryService) SetInterval(d time.Duration) {
+	s.interval = d
+}
+
+// Enqueue queues a recalled memory for summarization if its tier calls for
+// it. Summaries and memories beyond the per-agent cap are ignored. Safe for
+// concurrent use and never blocks on I/O.
+func (s *ColdSummaryService) Enqueue(m domain.Memory) {
+	if m.Type == domain.MemoryTypeSummary {
+		return
+	}
+	if !domain.GetTierBehavior(m.CurrentTier()).SummarizeOnAccess {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := s.pending[m.AgentID]
+	if batch == nil {
+		batch = make(map[uuid.UUID]bool)
+		s.pending[m.AgentID] = batch
+	}
+	if len(batch) >= ColdSummaryMaxPending {
+		return
+	}
+	batch[m.ID] = true
+	s.tenants[m.AgentID] = m.TenantID
+}
+
+// Start runs the summarizer on a periodic schedule in a background goroutine.
+func (s *ColdSummaryService) Start() {
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancelRuns = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.logger.Info("cold summary worker started", zap.Duration("interval", s.interval))
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, tickCancel := context.WithTimeout(baseCtx, 2*time.Minute)
+				guardPanic(s.logger, "cold summary tick", func() { s.Flush(ctx) })
+				tickCancel()
+			case <-s.stopCh:
+				s.logger.Info("cold summary worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop gracefully stops the worker, cancelling any in-flight flush.
+func (s *ColdSummaryService) Stop() {
+	if s.cancelRuns != nil {
+		s.cancelRuns()
+	}
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Flush summarizes everything queued so far and returns the number of
+// summaries created. Memories that don't cluster with another are dropped;
+// they are queued again the next time recall returns them.
+func (s *ColdSummaryService) Flush(ctx context.Context) int {
+	s.mu.Lock()
+	pending, tenants := s.pending, s.tenants
+	s.pending = make(map[uuid.UUID]map[uuid.UUID]bool)
+	s.tenants = make(map[uuid.UUID]uuid.UUID)
+	s.mu.Unlock()
+
+	if s.llmClient == nil {
+		return 0
+	}
+
+	created := 0
+	for agentID, batch := range pending {
+		if len(batch) < ColdSummaryMinMembers {
+			continue
+		}
+		ctx := domain.WithTenantID(ctx, tenants[agentID])
+		memories, err := s.loadQueued(ctx, agentID, batch)
+		if err != nil {
+			logFor(ctx, s.logger).Warn("failed to load queued cold memories", zap.String("agent_id", agentID.String()), zap.Error(err))
+			continue
+		}
+		if len(memories) < ColdSummaryMinMembers {
+			continue
+		}
+		created += s.summarizeBatch(ctx, agentID, memories)
+	}
+	return created
+}
+
+// loadQueued re-reads queued memories with their embeddings (recall results
+// don't carry them), dropping any that were archived, have since left the
+// summarize-on-access tiers, or are already members of a summary, so a memory
+// recalled again after it was summarized is not summarized twice.
+func (s *ColdSummaryService) loadQueued(ctx context.Context, agentID uuid.UUID, queued map[uuid.UUID]bool) ([]domain.Memory, error) {
+	var loaded []domain.Memory
+	if l, ok := s.memoryStore.(domain.UnsummarizedMemoryLoader); ok {
+		ids := make([]uuid.UUID, 0, len(queued))
+		for id := range queued {
+			ids = append(ids, id)
+		}
+		var err error
+		if loaded, err = l.GetUnsummarized(ctx, agentID, ids); err != nil {
+			return nil, err
+		}
+	} else {
+		all, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
+		if err != nil {
+			return nil, err
+		}
+		summarized := make(map[uuid.UUID]bool)
+		for _, m := range all {
+			if m.Type == domain.MemoryTypeSummary {
+				for _, id := range domain.SummaryMemberIDs(m) {
+					summarized[id] = true
+				}
+			}
+		}
+		for _, m := range all {
+			if queued[m.ID] && !summarized[m.ID] {
+				loaded = append(loaded, m)
+			}
+		}
+	}
+
+	var memories []domain.Memory
+	for _, m := range loaded {
+		if len(m.Embedding) == 0 || !domain.GetTierBehavior(m.CurrentTier()).SummarizeOnAccess {
+			continue
+		}
+		memories = append(memories, m)
+	}
+	return memories, nil
+}
+
+func (s *ColdSummaryService) summarizeBatch(ctx context.Context, agentID uuid.UUID, memories []domain.Memory) int {
+	created := 0
+	for _, cluster := range NewAgglomerativeClusterer(ClusteringThreshold).Cluster(memories) {
+		if len(cluster.Memories) < ColdSummaryMinMembers {
+			continue
+		}
+
+		content, err := s.llmClient.Summarize(ctx, cluster.Memories)
+		if err != nil || content == "" {
+			logFor(ctx, s.logger).Debug("failed to summarize cold memories", zap.String("agent_id", agentID.String()), zap.Error(err))
+			continue
+		}
+
+		summary := newSummaryMemory(agentID, cluster.Memories[0].TenantID, content, cluster)
+		summary.Source = "cold-summarize"
+		summary.Confidence = WarmSummaryConfidence
+		if s.embeddingClient != nil {
+			if emb, err := s.embeddingClient.Embed(ctx, content); err == nil {
+				summary.Embedding = emb
+			}
+		}
+
+		if err := s.memoryStore.Create(ctx, summary); err != nil {
+			logFor(ctx, s.logger).Warn("failed to store cold summary", zap.String("agent_id", agentID.String()), zap.Error(err))
+			continue
+		}
+		created++
+	}
+
+	if created > 0 {
+		logFor(ctx, s.logger).Info("summarized cold memories",
+			zap.String("agent_id", agentID.String()),
+			zap.Int("memories", len(memories)),
+			zap.Int("summaries", created))
+	}
+	return created
+}
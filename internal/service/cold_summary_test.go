@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestColdSummaryService_FlushSummarizesRelatedColdMemories(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	memStore := newMockMemoryStoreForConsolidation()
+	llm := newMockLLMClient()
+	llm.summarizeResult = "User used to deploy on Fridays"
+	svc := NewColdSummaryService(memStore, &mockEmbeddingClient{}, llm, zap.NewNop())
+
+	add := func(confidence float32, embedding []float32) domain.Memory {
+		m := domain.Memory{
+			ID:         uuid.New(),
+			AgentID:    agentID,
+			TenantID:   tenantID,
+			Type:       domain.MemoryTypeFact,
+			Content:    "deploy habit",
+			Confidence: confidence,
+			Embedding:  embedding,
+		}
+		memStore.memories = append(memStore.memories, m)
+		return m
+	}
+
+	cold1 := add(0.5, []float32{1, 0, 0})
+	cold2 := add(0.45, []float32{0.95, 0.1, 0})
+	lonely := add(0.5, []float32{0, 0, 1})
+	warm := add(0.8, []float32{1, 0.05, 0})
+
+	// Recall results carry no embeddings; the worker reloads them.
+	for _, m := range []domain.Memory{cold1, cold2, lonely, warm, cold1} {
+		m.Embedding = nil
+		svc.Enqueue(m)
+	}
+
+	if got := svc.Flush(context.Background()); got != 1 {
+		t.Fatalf("expected 1 summary, got %d", got)
+	}
+
+	var summary *domain.Memory
+	for i := range memStore.memories {
+		if memStore.memories[i].Type == domain.MemoryTypeSummary {
+			summary = &memStore.memories[i]
+		}
+	}
+	if summary == nil {
+		t.Fatal("expected a summary memory to be stored")
+	}
+	if domain.ComputeTier(float64(summary.Confidence)) != domain.TierWarm {
+		t.Fatalf("expected a warm summary, got confidence %f", summary.Confidence)
+	}
+
+	members := make(map[uuid.UUID]bool)
+	for _, id := range domain.SummaryMemberIDs(*summary) {
+		members[id] = true
+	}
+	if len(members) != 2 || !members[cold1.ID] || !members[cold2.ID] {
+		t.Fatalf("expected summary to link the two related cold memories, got %v", members)
+	}
+
+	// The queue is drained by a flush.
+	if got := svc.Flush(context.Background()); got != 0 {
+		t.Fatalf("expected empty queue after flush, got %d summaries", got)
+	}
+
+	// Recalling the summarized memories again does not summarize them twice.
+	for _, m := range []domain.Memory{cold1, cold2} {
+		m.Embedding = nil
+		svc.Enqueue(m)
+	}
+	if got := svc.Flush(context.Background()); got != 0 {
+		t.Fatalf("expected already summarized memories to be skipped, got %d summaries", got)
+	}
+}
+
+// idLoadingMemoryStore loads queued memories by ID and fails any agent scan.
+type idLoadingMemoryStore struct {
+	*mockMemoryStoreForConsolidation
+	t      *testing.T
+	loaded []uuid.UUID
+}
+
+func (m *idLoadingMemoryStore) GetByAgentForDecay(ctx context.Context, agentID uuid.UUID) ([]domain.Memory, error) {
+	m.t.Fatal("expected queued memories to be loaded by ID, not by scanning the agent")
+	return nil, nil
+}
+
+func (m *idLoadingMemoryStore) GetUnsummarized(ctx context.Context, agentID uuid.UUID, ids []uuid.UUID) ([]domain.Memory, error) {
+	m.loaded = append(m.loaded, ids...)
+	var result []domain.Memory
+	for _, mem := range m.memories {
+		if slices.Contains(ids, mem.ID) {
+			result = append(result, mem)
+		}
+	}
+	return result, nil
+}
+
+func TestColdSummaryService_LoadsQueuedMemoriesByID(t *testing.T) {
+	agentID := uuid.New()
+	memStore := &idLoadingMemoryStore{mockMemoryStoreForConsolidation: newMockMemoryStoreForConsolidation(), t: t}
+	llm := newMockLLMClient()
+	llm.summarizeResult = "User used to deploy on Fridays"
+	svc := NewColdSummaryService(memStore, &mockEmbeddingClient{}, llm, zap.NewNop())
+
+	for _, emb := range [][]float32{{1, 0, 0}, {0.95, 0.1, 0}} {
+		m := domain.Memory{ID: uuid.New(), AgentID: agentID, TenantID: uuid.New(), Type: domain.MemoryTypeFact,
+			Content: "deploy habit", Confidence: 0.5, Embedding: emb}
+		memStore.memories = append(memStore.memories, m)
+		m.Embedding = nil
+		svc.Enqueue(m)
+	}
+
+	if got := svc.Flush(context.Background()); got != 1 {
+		t.Fatalf("expected 1 summary, got %d", got)
+	}
+	if len(memStore.loaded) != 2 {
+		t.Fatalf("expected the 2 queued IDs to be loaded, got %v", memStore.loaded)
+	}
+}
+
+func TestColdSummaryService_EnqueueCapsPending(t *testing.T) {
+	svc := NewColdSummaryService(newMockMemoryStoreForConsolidation(), nil, nil, zap.NewNop())
+	agentID := uuid.New()
+
+	for i := 0; i < ColdSummaryMaxPending+10; i++ {
+		svc.Enqueue(domain.Memory{ID: uuid.New(), AgentID: agentID, Confidence: 0.5})
+	}
+	if got := len(svc.pending[agentID]); got != ColdSummaryMaxPending {
+		t.Fatalf("expected pending capped at %d, got %d", ColdSummaryMaxPending, got)
+	}
+}
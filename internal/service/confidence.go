@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"math"
-	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
@@ -44,9 +43,35 @@ func clampConfidence(p float64) float64 {
 	return p
 }
 
+// recordEvidence adds observations for and against a memory to its evidence
+// counts. The counts refine confidence rather than drive it, so a failure is
+// logged and not returned.
+func recordEvidence(ctx context.Context, logger *zap.Logger, ms domain.MemoryStore, id uuid.UUID, supporting, contradicting float64) {
+	if supporting == 0 && contradicting == 0 {
+		return
+	}
+	if err := ms.AddEvidence(ctx, id, supporting, contradicting); err != nil {
+		logFor(ctx, logger).Warn("failed to record evidence",
+			zap.String("memory_id", id.String()), zap.Error(err))
+	}
+}
+
+// evidenceFor maps the direction of a confidence update to one observation
+// for or against the memory.
+func evidenceFor(logOddsDelta float64) (supporting, contradicting float64) {
+	switch {
+	case logOddsDelta > 0:
+		return 1, 0
+	case logOddsDelta < 0:
+		return 0, 1
+	}
+	return 0, 0
+}
+
 type ConfidenceService struct {
 	store    domain.MemoryStore
 	settings domain.TenantSettingsStore // optional; nil → use service defaults
+	hooks    *Hooks
 	logger   *zap.Logger
 
 	ReinforcementLogOdds float64
@@ -74,6 +99,11 @@ func (s *ConfidenceService) SetSettingsStore(ts domain.TenantSettingsStore) {
 	s.settings = ts
 }
 
+// SetHooks reports Reinforce calls to in-process callbacks.
+func (s *ConfidenceService) SetHooks(h *Hooks) {
+	s.hooks = h
+}
+
 // reinforcementDelta resolves the per-tenant reinforcement Δ (falling back to
 // the service default if no settings store or on error).
 func (s *ConfidenceService) reinforcementDelta(ctx context.Context, tenantID uuid.UUID) float64 {
@@ -112,13 +142,19 @@ func (s *ConfidenceService) Reinforce(ctx context.Context, memoryID uuid.UUID, t
 	}
 	newCount := memory.ReinforcementCount + 1
 
-	s.logger.Debug("reinforcing memory",
+	logFor(ctx, s.logger).Debug("reinforcing memory",
 		zap.String("memory_id", memoryID.String()),
 		zap.Float32("old_confidence", memory.Confidence),
 		zap.Float32("new_confidence", newConfidence),
 		zap.Int("reinforcement_count", newCount))
 
-	return s.store.UpdateReinforcement(ctx, memoryID, newConfidence, newCount)
+	if err := s.store.UpdateReinforcement(ctx, memoryID, newConfidence, newCount); err != nil {
+		return err
+	}
+	recordEvidence(ctx, s.logger, s.store, memoryID, 1, 0)
+	memory.Confidence, memory.ReinforcementCount = newConfidence, newCount
+	s.hooks.memoryReinforced(ctx, memoryEvent(memory, "reinforced"))
+	return nil
 }
 
 func (s *ConfidenceService) Penalize(ctx context.Context, memoryID uuid.UUID, tenantID uuid.UUID) error {
@@ -133,13 +169,17 @@ func (s *ConfidenceService) Penalize(ctx context.Context, memoryID uuid.UUID, te
 		newCount = 0
 	}
 
-	s.logger.Debug("penalizing memory",
+	logFor(ctx, s.logger).Debug("penalizing memory",
 		zap.String("memory_id", memoryID.String()),
 		zap.Float32("old_confidence", memory.Confidence),
 		zap.Float32("new_confidence", newConfidence),
 		zap.Int("reinforcement_count", newCount))
 
-	return s.store.UpdateReinforcement(ctx, memoryID, newConfidence, newCount)
+	if err := s.store.UpdateReinforcement(ctx, memoryID, newConfidence, newCount); err != nil {
+		return err
+	}
+	recordEvidence(ctx, s.logger, s.store, memoryID, 0, 1)
+	return nil
 }
 
 func (s *ConfidenceService) ApplyDecay(memory *domain.Memory) float64 {
@@ -147,7 +187,7 @@ func (s *ConfidenceService) ApplyDecay(memory *domain.Memory) float64 {
 		return float64(memory.Confidence)
 	}
 
-	elapsed := time.Since(*memory.LastAccessedAt)
+	elapsed := timeNow().Sub(*memory.LastAccessedAt)
 	hours := elapsed.Hours()
 
 	decayFactor := math.Exp(-s.DecayLambda * hours)
@@ -176,6 +216,8 @@ type ConfidenceStats struct {
 	Provenance         string    `json:"provenance"`
 	HoursSinceAccess   float64   `json:"hours_since_access"`
 	DecayFactor        float64   `json:"decay_factor"`
+
+	Interval domain.ConfidenceInterval `json:"interval"`
 }
 
 func (s *ConfidenceService) GetStats(ctx context.Context, memoryID uuid.UUID, tenantID uuid.UUID) (*ConfidenceStats, error) {
@@ -187,7 +229,7 @@ func (s *ConfidenceService) GetStats(ctx context.Context, memoryID uuid.UUID, te
 	var hoursSinceAccess float64
 	var decayFactor float64 = 1.0
 	if memory.LastAccessedAt != nil {
-		hoursSinceAccess = time.Since(*memory.LastAccessedAt).Hours()
+		hoursSinceAccess = timeNow().Sub(*memory.LastAccessedAt).Hours()
 		decayFactor = math.Exp(-s.DecayLambda * hoursSinceAccess)
 	}
 
@@ -199,5 +241,6 @@ func (s *ConfidenceService) GetStats(ctx context.Context, memoryID uuid.UUID, te
 		Provenance:         string(memory.Provenance),
 		HoursSinceAccess:   hoursSinceAccess,
 		DecayFactor:        decayFactor,
+		Interval:           memory.ConfidenceInterval(domain.DefaultCredibleLevel),
 	}, nil
 }
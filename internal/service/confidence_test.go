@@ -37,6 +37,7 @@ func (m *mockMemoryStoreForConfidence) Create(ctx context.Context, mem *domain.M
 	if mem.LastAccessedAt == nil {
 		mem.LastAccessedAt = &now
 	}
+	mem.EvidenceFor, mem.EvidenceAgainst = mem.Evidence()
 	m.memories[mem.ID] = mem
 	return nil
 }
@@ -85,6 +86,10 @@ func (m *mockMemoryStoreForConfidence) FindSimilar(ctx context.Context, agentID
 	return nil, nil
 }
 
+func (m *mockMemoryStoreForConfidence) FindSimilarFiltered(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, filter domain.SimilarityFilter) ([]domain.MemoryWithScore, error) {
+	return m.FindSimilar(ctx, agentID, tenantID, embedding, threshold)
+}
+
 func (m *mockMemoryStoreForConfidence) GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType domain.MemoryType, limit int) ([]domain.MemoryWithScore, error) {
 	return nil, nil
 }
@@ -102,6 +107,22 @@ func (m *mockMemoryStoreForConfidence) UpdateReinforcement(ctx context.Context,
 	return nil
 }
 
+func (m *mockMemoryStoreForConfidence) ReinforceBy(ctx context.Context, id uuid.UUID, boost float32) error {
+	if mem := m.memories[id]; mem != nil {
+		mem.Confidence = clampConf(mem.Confidence + boost)
+		mem.ReinforcementCount++
+	}
+	return nil
+}
+
+func (m *mockMemoryStoreForConfidence) AddEvidence(ctx context.Context, id uuid.UUID, supporting, contradicting float64) error {
+	if mem := m.memories[id]; mem != nil {
+		mem.EvidenceFor += supporting
+		mem.EvidenceAgainst += contradicting
+	}
+	return nil
+}
+
 func (m *mockMemoryStoreForConfidence) UpdateContent(ctx context.Context, id uuid.UUID, content string, embedding []float32) error {
 	return nil
 }
@@ -168,6 +189,18 @@ func (m *mockMemoryStoreForConfidence) GetTierCounts(ctx context.Context, agentI
 	return nil, nil
 }
 
+func (m *mockMemoryStoreForConfidence) ApplyTierTransitions(ctx context.Context, transitions []domain.TierTransition) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForConfidence) SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForConfidence) GetTierHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memoryID *uuid.UUID, since time.Time, limit int) ([]domain.TierTransition, error) {
+	return nil, nil
+}
+
 func (m *mockMemoryStoreForConfidence) SetNeedsReview(ctx context.Context, id uuid.UUID, needsReview bool) error {
 	return nil
 }
@@ -221,6 +254,27 @@ func TestConfidenceService_Reinforce(t *testing.T) {
 	}
 }
 
+func TestConfidenceService_ReinforceAndPenalizeRecordEvidence(t *testing.T) {
+	memStore := newMockMemoryStoreForConfidence()
+	mem := &domain.Memory{TenantID: uuid.New(), Confidence: 0.5}
+	_ = memStore.Create(context.Background(), mem)
+	svc := NewConfidenceService(memStore, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		if err := svc.Reinforce(context.Background(), mem.ID, mem.TenantID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := svc.Penalize(context.Background(), mem.ID, mem.TenantID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The prior at 0.5 is Beta(1, 1).
+	if mem.EvidenceFor != 4 || mem.EvidenceAgainst != 2 {
+		t.Errorf("expected Beta(4, 2), got Beta(%v, %v)", mem.EvidenceFor, mem.EvidenceAgainst)
+	}
+}
+
 func TestConfidenceService_Reinforce_CapsAtMax(t *testing.T) {
 	logger := zap.NewNop()
 	agentID := uuid.New()
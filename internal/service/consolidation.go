@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
-	"sync"
+	"sort"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -15,36 +18,51 @@ import (
 // Consolidation constants
 const (
 	// Episode processing
-	EpisodeBatchSize = 50 // Process episodes in batches
+	EpisodeBatchSize    = 50 // Process episodes in batches
+	DefaultReplayBudget = 50 // Raw episodes stage 1 replays per run
 
 	// Semantic extraction
 	SemanticExtractionConfidenceDiscount = 0.8 // Applied to auto-extracted beliefs
 	SemanticSimilarityThreshold          = 0.85
+	episodeBeliefReinforcementBoost      = 0.05 // Confidence added when an episode restates a belief
 
-	// Schema formation
-	SchemaMinEvidenceCount = 5 // Minimum memories to form a schema
+	// Summary rollups
+	SummaryClusterThreshold = 0.75 // Tighter than schema clustering: one topic per summary
+	SummaryMinMembers       = 4    // Minimum related beliefs to roll up
+	SummaryRefreshOverlap   = 0.5  // Jaccard overlap at which a cluster replaces an existing summary
 
 	// Pruning
 	RedundancyThreshold     = 0.92  // Merge memories above this similarity
+	redundancyNeighbors     = 10    // Nearest neighbors probed per memory for redundancy
 	ProcedureMergeThreshold = 0.9   // Merge procedures above this similarity
 	ProceduralDecayRate     = 0.01  // Very slow decay for procedures
 	SchemaDecayRate         = 0.005 // Almost no decay for schemas
 	MinProcedureSuccessRate = 0.2   // Archive procedures below this
+
+	// Health breakdown
+	MaxHealthTopics  = 20                 // Topics listed in health and uncertainty reports
+	TopicTrendWindow = 7 * 24 * time.Hour // Window topic trends compare against the one before
 )
 
 // ConsolidationResult contains the results of a consolidation run.
 type ConsolidationResult struct {
 	EpisodesProcessed    int `json:"episodes_processed"`
+	EpisodesDeferred     int `json:"episodes_deferred"` // Raw episodes left for a later run by the replay budget
 	SemanticExtracted    int `json:"semantic_extracted"`
 	SemanticReinforced   int `json:"semantic_reinforced"`
 	ProceduresLearned    int `json:"procedures_learned"`
 	ProceduresReinforced int `json:"procedures_reinforced"`
 	SchemasDetected      int `json:"schemas_detected"`
 	SchemasUpdated       int `json:"schemas_updated"`
+	SummariesCreated     int `json:"summaries_created"`
+	SummariesRefreshed   int `json:"summaries_refreshed"`
 	MemoriesDecayed      int `json:"memories_decayed"`
 	MemoriesArchived     int `json:"memories_archived"`
 	MemoriesMerged       int `json:"memories_merged"`
 	AssociationsCreated  int `json:"associations_created"`
+
+	// Usage totals the LLM and embedding calls the run made.
+	Usage domain.LLMUsage `json:"usage"`
 }
 
 // MemoryHealthStats contains statistics about memory system health.
@@ -56,9 +74,16 @@ type MemoryHealthStats struct {
 	MemoriesAtRisk     int        `json:"memories_at_risk"` // confidence/strength < 0.3
 	RecentlyReinforced int        `json:"recently_reinforced"`
 	ContradictionCount int        `json:"contradiction_count"`
-	UncertaintyAreas   []string   `json:"uncertainty_areas"`
+	UncertaintyAreas   []string   `json:"uncertainty_areas"` // topics with a low-confidence memory
 	AverageConfidence  float32    `json:"average_confidence"`
 	OldestUnprocessed  *time.Time `json:"oldest_unprocessed,omitempty"`
+
+	MemoriesByType map[string]int `json:"memories_by_type,omitempty"`
+	MemoriesByTier map[string]int `json:"memories_by_tier,omitempty"`
+
+	// Topics are the agent's largest topics, with a trend comparing the
+	// memories created this week with the week before.
+	Topics []domain.TopicStats `json:"topics,omitempty"`
 }
 
 const (
@@ -74,16 +99,28 @@ type ConsolidationService struct {
 	assocStore         domain.MemoryAssociationStore
 	contradictionStore domain.ContradictionStore
 	graphStore         domain.GraphStore
+	entityStore        domain.EntityStore
+	redundancyStore    domain.RedundancyStore
+	scanner            domain.MemoryScanner
+	healthStore        domain.HealthStore
+	agents             domain.AgentRegistry
 	embeddingClient    domain.EmbeddingClient
 	llmClient          domain.LLMClient
 	logger             *zap.Logger
 	decayService       *DecayService
-
-	// Background worker fields
-	interval   time.Duration
-	stopCh     chan struct{}
-	cancelRuns context.CancelFunc
-	wg         sync.WaitGroup
+	clusterer          Clusterer
+	uow                *store.UnitOfWork
+	runStore           domain.ConsolidationRunStore
+	failureStore       domain.ConsolidationFailureStore
+	jobs               *JobPool
+	hooks              *Hooks
+	reliability        *SourceReliabilityService
+	positions          *EpisodePositionService
+
+	// Background worker interval
+	interval time.Duration
+	// Raw episodes stage 1 replays per run
+	replayBudget int
 }
 
 // NewConsolidationService creates a new consolidation service.
@@ -109,115 +146,213 @@ func NewConsolidationService(
 		llmClient:          llmClient,
 		logger:             logger,
 		interval:           defaultConsolidationInterval,
-		stopCh:             make(chan struct{}),
+		replayBudget:       DefaultReplayBudget,
 	}
 }
 
+// SetRunStore records every consolidation pass, with its model usage, in the
+// run history.
+func (s *ConsolidationService) SetRunStore(rs domain.ConsolidationRunStore) {
+	s.runStore = rs
+}
+
+// SetHooks reports finished runs, and the summaries and merges they make, to
+// in-process callbacks. Archives by decay are reported by the DecayService.
+func (s *ConsolidationService) SetHooks(h *Hooks) {
+	s.hooks = h
+}
+
+// SetSourceReliability scales the discount on consolidated beliefs by how the
+// agent's extractions have fared.
+func (s *ConsolidationService) SetSourceReliability(sr *SourceReliabilityService) {
+	s.reliability = sr
+}
+
+// SetJobPool runs background consolidation passes on the shared job pool
+// instead of one at a time on the ticker goroutine.
+func (s *ConsolidationService) SetJobPool(p *JobPool) {
+	s.jobs = p
+}
+
+// SetUnitOfWork makes each episode's semantic-extraction writes atomic.
+func (s *ConsolidationService) SetUnitOfWork(uow *store.UnitOfWork) {
+	s.uow = uow
+}
+
 // SetInterval sets the consolidation interval.
 func (s *ConsolidationService) SetInterval(d time.Duration) {
 	s.interval = d
 }
 
+// SetReplayBudget caps how many raw episodes stage 1 replays per run. 0 keeps
+// the default.
+func (s *ConsolidationService) SetReplayBudget(n int) {
+	if n > 0 {
+		s.replayBudget = n
+	}
+}
+
 func (s *ConsolidationService) SetDecayService(cd *DecayService) {
 	s.decayService = cd
 }
 
+// SetClusterer overrides the clustering algorithm used for schema formation.
+// Defaults to average-linkage agglomerative clustering.
+func (s *ConsolidationService) SetClusterer(c Clusterer) {
+	s.clusterer = c
+}
+
 // SetGraphStore sets the graph store for edge decay and pruning.
 func (s *ConsolidationService) SetGraphStore(gs domain.GraphStore) {
 	s.graphStore = gs
 }
 
-// Start begins the background consolidation worker.
-func (s *ConsolidationService) Start() {
-	baseCtx, cancel := context.WithCancel(context.Background())
-	s.cancelRuns = cancel
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		ticker := time.NewTicker(s.interval)
-		defer ticker.Stop()
-
-		s.logger.Info("consolidation worker started", zap.Duration("interval", s.interval))
-
-		for {
-			select {
-			case <-ticker.C:
-				ctx, tickCancel := context.WithTimeout(baseCtx, 30*time.Minute)
-				guardPanic(s.logger, "consolidation tick", func() { s.runConsolidation(ctx) })
-				tickCancel()
-			case <-s.stopCh:
-				s.logger.Info("consolidation worker stopped")
-				return
-			}
-		}
-	}()
+// SetEntityStore links beliefs drawn from an episode to the person entity of
+// the participant who spoke it.
+func (s *ConsolidationService) SetEntityStore(es domain.EntityStore) {
+	s.entityStore = es
 }
 
-// Stop halts the background consolidation worker, cancelling any in-flight
-// pass so shutdown is not held for the remainder of a long tick.
-func (s *ConsolidationService) Stop() {
-	if s.cancelRuns != nil {
-		s.cancelRuns()
-	}
-	close(s.stopCh)
-	s.wg.Wait()
+// SetRedundancyStore moves duplicate detection during full prunes into the
+// database. Without it every pair of memories is compared in Go.
+func (s *ConsolidationService) SetRedundancyStore(rs domain.RedundancyStore) {
+	s.redundancyStore = rs
+}
+
+// SetPositionWeighting raises the importance of the episodes that open or
+// close their conversation when deciding what to replay and extract.
+func (s *ConsolidationService) SetPositionWeighting(p *EpisodePositionService) {
+	s.positions = p
+}
+
+// SetAgentRegistry finds agents to consolidate from the agents table, which
+// includes agents that so far have only episodes.
+func (s *ConsolidationService) SetAgentRegistry(r domain.AgentRegistry) {
+	s.agents = r
+}
+
+// SetHealthStore counts episodes, procedures and schemas for GetMemoryHealth
+// in the database instead of loading them.
+func (s *ConsolidationService) SetHealthStore(hs domain.HealthStore) {
+	s.healthStore = hs
+}
+
+// SetMemoryScanner streams memories during schema formation and rollups, and
+// computes health stats in the database, instead of loading every memory.
+func (s *ConsolidationService) SetMemoryScanner(ms domain.MemoryScanner) {
+	s.scanner = ms
 }
 
-// runConsolidation runs consolidation for all agents needing it.
-func (s *ConsolidationService) runConsolidation(ctx context.Context) {
+// ScheduledTask returns the background consolidation pass for the Scheduler.
+func (s *ConsolidationService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "consolidation", Interval: s.interval, Timeout: 30 * time.Minute, Run: s.runConsolidation}
+}
+
+// runConsolidation runs consolidation for all agents needing it. With a job
+// pool each agent's pass is queued on it, sharing its workers with async
+// extraction; the tick still waits for every pass so ticks never overlap.
+func (s *ConsolidationService) runConsolidation(ctx context.Context) error {
 	agents, err := s.GetAgentsNeedingConsolidation(ctx)
 	if err != nil {
-		s.logger.Error("failed to get agents needing consolidation", zap.Error(err))
-		return
+		return fmt.Errorf("get agents needing consolidation: %w", err)
 	}
 
-	for _, agentID := range agents {
+	var queued []uuid.UUID
+	for _, agent := range agents {
 		if ctx.Err() != nil {
-			return
+			break
 		}
-		// Get tenant ID for this agent - we need it from the memory store
-		tenantID, err := s.getTenantForAgent(ctx, agentID)
-		if err != nil {
-			s.logger.Warn("failed to get tenant for agent", zap.String("agent_id", agentID.String()), zap.Error(err))
-			continue
+		agentID, tenantID := agent.ID, agent.TenantID
+		if tenantID == uuid.Nil {
+			// Legacy discovery: look the tenant up from the agent's memories.
+			tenantID, err = s.getTenantForAgent(ctx, agentID)
+			if err != nil {
+				logFor(ctx, s.logger).Warn("failed to get tenant for agent", zap.String("agent_id", agentID.String()), zap.Error(err))
+				continue
+			}
 		}
 
-		var result *ConsolidationResult
-		guardPanic(s.logger, "consolidation agent "+agentID.String(), func() {
-			result, err = s.Consolidate(ctx, agentID, tenantID, ConsolidationScopeRecent)
+		if s.jobs == nil {
+			_, _ = s.consolidateAgent(ctx, agentID, tenantID)
+			continue
+		}
+		// The pass runs under the tick's context rather than the job's, so
+		// stopping consolidation cancels it.
+		job, err := s.jobs.Submit(ctx, tenantID, agentID, domain.JobKindConsolidation, 0, func(context.Context) (any, error) {
+			return s.consolidateAgent(ctx, agentID, tenantID)
 		})
 		if err != nil {
-			s.logger.Error("consolidation failed",
+			logFor(ctx, s.logger).Warn("failed to queue consolidation; retrying next tick",
 				zap.String("agent_id", agentID.String()),
 				zap.Error(err))
 			continue
 		}
-		if result == nil { // tick panicked for this agent; already logged
-			continue
-		}
+		queued = append(queued, job.ID)
+	}
 
-		if result.EpisodesProcessed > 0 || result.SemanticExtracted > 0 || result.ProceduresLearned > 0 || result.MemoriesArchived > 0 {
-			s.logger.Info("consolidation complete",
-				zap.String("agent_id", agentID.String()),
-				zap.Int("episodes_processed", result.EpisodesProcessed),
-				zap.Int("semantic_extracted", result.SemanticExtracted),
-				zap.Int("procedures_learned", result.ProceduresLearned),
-				zap.Int("memories_archived", result.MemoriesArchived))
+	for _, id := range queued {
+		if _, err := s.jobs.Wait(ctx, id); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// Schedule queues a consolidation pass for one agent on the job pool, for
+// callers that want it sooner than the next tick.
+func (s *ConsolidationService) Schedule(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.Job, error) {
+	if s.jobs == nil {
+		return nil, errors.New("job pool not configured")
+	}
+	return s.jobs.Submit(ctx, tenantID, agentID, domain.JobKindConsolidation, s.ScheduledTask().Timeout, func(ctx context.Context) (any, error) {
+		return s.consolidateAgent(ctx, agentID, tenantID)
+	})
+}
+
+// consolidateAgent runs one background pass for an agent, logging failures
+// and panics rather than letting them escape the worker.
+func (s *ConsolidationService) consolidateAgent(ctx context.Context, agentID, tenantID uuid.UUID) (*ConsolidationResult, error) {
+	var result *ConsolidationResult
+	var err error
+	guardPanic(s.logger, "consolidation agent "+agentID.String(), func() {
+		result, err = s.Consolidate(ctx, agentID, tenantID, ConsolidationScopeRecent)
+	})
+	if err != nil {
+		logFor(ctx, s.logger).Error("consolidation failed",
+			zap.String("agent_id", agentID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+	if result == nil { // tick panicked for this agent; already logged
+		return nil, fmt.Errorf("consolidation panicked for agent %s", agentID)
+	}
+
+	if result.EpisodesProcessed > 0 || result.SemanticExtracted > 0 || result.ProceduresLearned > 0 || result.MemoriesArchived > 0 {
+		logFor(ctx, s.logger).Info("consolidation complete",
+			zap.String("agent_id", agentID.String()),
+			zap.Int("episodes_processed", result.EpisodesProcessed),
+			zap.Int("semantic_extracted", result.SemanticExtracted),
+			zap.Int("procedures_learned", result.ProceduresLearned),
+			zap.Int("memories_archived", result.MemoriesArchived))
+	}
+	return result, nil
 }
 
 // getTenantForAgent retrieves the tenant ID for an agent.
 func (s *ConsolidationService) getTenantForAgent(ctx context.Context, agentID uuid.UUID) (uuid.UUID, error) {
 	// Get a memory for this agent to extract tenant ID
-	memories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
+	tenantID := uuid.Nil
+	err := forEachLiveMemory(ctx, s.scanner, s.memoryStore, agentID, func(m *domain.Memory) error {
+		tenantID = m.TenantID
+		return domain.ErrStopIteration
+	})
 	if err != nil {
 		return uuid.Nil, err
 	}
-	if len(memories) == 0 {
+	if tenantID == uuid.Nil {
 		return uuid.Nil, fmt.Errorf("no memories found for agent")
 	}
-	return memories[0].TenantID, nil
+	return tenantID, nil
 }
 
 // ConsolidationScope defines the scope of consolidation.
@@ -231,14 +366,18 @@ const (
 // Consolidate runs the full consolidation pipeline for an agent.
 func (s *ConsolidationService) Consolidate(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, scope ConsolidationScope) (*ConsolidationResult, error) {
 	result := &ConsolidationResult{}
+	startedAt := timeNow()
+	meter := domain.NewUsageMeter()
+	ctx = domain.WithUsageMeter(ctx, meter)
 
-	s.logger.Info("starting consolidation",
+	logFor(ctx, s.logger).Info("starting consolidation",
 		zap.String("agent_id", agentID.String()),
 		zap.String("scope", string(scope)))
 
 	// Stage 1: Process raw episodes
 	stage1Result := s.processEpisodes(ctx, agentID, tenantID, scope)
 	result.EpisodesProcessed = stage1Result.processed
+	result.EpisodesDeferred = stage1Result.deferred
 	result.AssociationsCreated = stage1Result.associations
 
 	// Stage 2: Extract semantic beliefs from processed episodes
@@ -252,46 +391,105 @@ func (s *ConsolidationService) Consolidate(ctx context.Context, agentID uuid.UUI
 	result.ProceduresReinforced = stage3Result.reinforced
 
 	// Stage 4: Form/update schemas from semantic clusters
-	stage4Result := s.formSchemas(ctx, agentID, tenantID)
+	stage4Result := s.formSchemas(ctx, agentID, tenantID, scope == ConsolidationScopeFull)
 	result.SchemasDetected = stage4Result.detected
 	result.SchemasUpdated = stage4Result.updated
 
+	// Stage 4b: Roll up related beliefs into topic summaries
+	rollup := s.rollupSummaries(ctx, agentID, tenantID)
+	result.SummariesCreated = rollup.created
+	result.SummariesRefreshed = rollup.refreshed
+
 	// Stage 5: Apply forgetting and pruning
 	stage5Result := s.applyForgetting(ctx, agentID, tenantID, scope == ConsolidationScopeFull)
 	result.MemoriesDecayed = stage5Result.decayed
 	result.MemoriesArchived = stage5Result.archived
 	result.MemoriesMerged = stage5Result.merged
 
-	s.logger.Info("consolidation complete",
+	result.Usage = meter.Usage()
+	s.recordRun(ctx, agentID, tenantID, scope, result, startedAt)
+	s.hooks.consolidationComplete(ctx, ConsolidationEvent{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Scope:      scope,
+		Result:     result,
+		StartedAt:  startedAt,
+		FinishedAt: timeNow(),
+	})
+
+	logFor(ctx, s.logger).Info("consolidation complete",
 		zap.String("agent_id", agentID.String()),
 		zap.Int("episodes_processed", result.EpisodesProcessed),
+		zap.Int("episodes_deferred", result.EpisodesDeferred),
 		zap.Int("semantic_extracted", result.SemanticExtracted),
 		zap.Int("procedures_learned", result.ProceduresLearned),
 		zap.Int("schemas_detected", result.SchemasDetected),
-		zap.Int("memories_archived", result.MemoriesArchived))
+		zap.Int("memories_archived", result.MemoriesArchived),
+		zap.Int("llm_calls", result.Usage.LLMCalls),
+		zap.Float64("estimated_cost_usd", result.Usage.EstimatedCostUSD))
 
 	return result, nil
 }
 
+// recordRun appends the run to the history. Best-effort: a failed write is
+// logged, not returned, since the consolidation itself succeeded.
+func (s *ConsolidationService) recordRun(ctx context.Context, agentID, tenantID uuid.UUID, scope ConsolidationScope, result *ConsolidationResult, startedAt time.Time) {
+	if s.runStore == nil {
+		return
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		logFor(ctx, s.logger).Warn("failed to marshal consolidation result", zap.Error(err))
+		return
+	}
+	run := &domain.ConsolidationRun{
+		TenantID:   tenantID,
+		AgentID:    agentID,
+		Scope:      string(scope),
+		Result:     raw,
+		Usage:      result.Usage,
+		StartedAt:  startedAt,
+		FinishedAt: timeNow(),
+	}
+	if err := s.runStore.Create(ctx, run); err != nil {
+		logFor(ctx, s.logger).Warn("failed to record consolidation run",
+			zap.String("agent_id", agentID.String()),
+			zap.Error(err))
+	}
+}
+
+// ListRuns returns the agent's most recent consolidation runs, newest first.
+func (s *ConsolidationService) ListRuns(ctx context.Context, agentID, tenantID uuid.UUID, limit int) ([]domain.ConsolidationRun, error) {
+	if s.runStore == nil {
+		return nil, nil
+	}
+	return s.runStore.ListByAgent(ctx, agentID, tenantID, limit)
+}
+
 // Stage 1: Episode Processing
 type stage1Result struct {
 	processed    int
+	deferred     int
 	associations int
 }
 
 func (s *ConsolidationService) processEpisodes(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, _ ConsolidationScope) stage1Result {
 	result := stage1Result{}
 
-	// Get unprocessed episodes
-	episodes, err := s.episodeStore.GetUnconsolidated(ctx, agentID, EpisodeBatchSize)
+	// Replay the most valuable unprocessed episodes from both ends of the
+	// backlog, like prioritized experience replay
+	candidates, err := s.episodeStore.GetReplayCandidates(ctx, agentID, s.replayBudget)
 	if err != nil {
-		s.logger.Warn("failed to get unprocessed episodes", zap.Error(err))
+		logFor(ctx, s.logger).Warn("failed to get unprocessed episodes", zap.Error(err))
 		return result
 	}
 
-	if len(episodes) == 0 {
+	if len(candidates) == 0 {
 		return result
 	}
+	s.positions.WeighImportance(ctx, agentID, tenantID, candidates)
+	episodes := selectReplay(candidates, s.replayBudget)
+	result.deferred = len(candidates) - len(episodes)
 
 	for _, ep := range episodes {
 		// Skip if already processed (raw → processed)
@@ -306,7 +504,7 @@ func (s *ConsolidationService) processEpisodes(ctx context.Context, agentID uuid
 			extraction, err := s.llmClient.ExtractEpisodeStructure(ctx, ep.RawContent)
 			if err == nil && extraction != nil {
 				ep.Entities = extraction.Entities
-				ep.Topics = extraction.Topics
+				ep.Topics = domain.NormalizeTopics(extraction.Topics)
 				ep.CausalLinks = extraction.CausalLinks
 				if extraction.EmotionalValence != nil {
 					ep.EmotionalValence = extraction.EmotionalValence
@@ -326,7 +524,7 @@ func (s *ConsolidationService) processEpisodes(ctx context.Context, agentID uuid
 
 		// Mark as processed
 		if err := s.episodeStore.UpdateConsolidationStatus(ctx, ep.ID, domain.ConsolidationProcessed); err != nil {
-			s.logger.Warn("failed to update episode status", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to update episode status", zap.Error(err))
 			continue
 		}
 
@@ -336,6 +534,32 @@ func (s *ConsolidationService) processEpisodes(ctx context.Context, agentID uuid
 	return result
 }
 
+// selectReplay picks up to budget episodes to replay from candidates ordered
+// oldest first. The older and newer halves are each ranked by replay
+// priority and taken in turn, so a run neither starves the backlog nor
+// ignores what just happened.
+func selectReplay(candidates []domain.Episode, budget int) []domain.Episode {
+	mid := len(candidates) / 2
+	older := append([]domain.Episode(nil), candidates[:mid]...)
+	newer := append([]domain.Episode(nil), candidates[mid:]...)
+	for _, half := range [][]domain.Episode{older, newer} {
+		sort.SliceStable(half, func(i, j int) bool {
+			return half[i].ReplayPriority() > half[j].ReplayPriority()
+		})
+	}
+
+	selected := make([]domain.Episode, 0, min(budget, len(candidates)))
+	for i := 0; len(selected) < budget && (i < len(older) || i < len(newer)); i++ {
+		if i < len(older) {
+			selected = append(selected, older[i])
+		}
+		if i < len(newer) && len(selected) < budget {
+			selected = append(selected, newer[i])
+		}
+	}
+	return selected
+}
+
 // createEpisodeAssociations creates cross-memory associations for an episode.
 func (s *ConsolidationService) createEpisodeAssociations(ctx context.Context, ep *domain.Episode, tenantID uuid.UUID) int {
 	if s.assocStore == nil || len(ep.Embedding) == 0 {
@@ -393,6 +617,8 @@ func (s *ConsolidationService) extractSemanticBeliefs(ctx context.Context, agent
 		episodes = append(episodes, processedEps...)
 	}
 
+	s.positions.WeighImportance(ctx, agentID, tenantID, episodes)
+
 	// The two queries can overlap (stage 1 may have just flipped an episode to
 	// "processed"), so dedupe by ID — otherwise the same episode is extracted
 	// twice before the first LinkDerivedMemory lands.
@@ -420,85 +646,193 @@ func (s *ConsolidationService) extractSemanticBeliefs(ctx context.Context, agent
 
 		// Extract beliefs using LLM
 		extracted, err := s.llmClient.Extract(ctx, []domain.Message{
-			{Role: "user", Content: ep.RawContent},
+			{Role: "user", Content: participantPreamble(&ep) + ep.RawContent},
 		})
 		if err != nil {
-			s.logger.Debug("failed to extract beliefs", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to extract beliefs", zap.Error(err))
+			s.recordEpisodeFailure(ctx, &ep, FailureStageBeliefExtraction, err)
 			continue
 		}
 
+		var writes []beliefWrite
 		for _, belief := range extracted {
-			// Generate embedding
-			var embedding []float32
+			w := beliefWrite{belief: belief}
+			w.subject, w.subjectID = beliefSubject(&ep, belief.Subject)
 			if s.embeddingClient != nil {
-				embedding, _ = s.embeddingClient.Embed(ctx, belief.Content)
+				w.embedding, _ = s.embeddingClient.Embed(ctx, belief.Content)
 			}
 
-			// Check for similar existing beliefs
-			if len(embedding) > 0 {
-				similar, err := s.memoryStore.FindSimilar(ctx, agentID, tenantID, embedding, SemanticSimilarityThreshold)
-				if err == nil && len(similar) > 0 {
-					// Reinforce existing belief
-					existingMem := similar[0]
-					newConfidence := existingMem.Confidence + 0.05
-					if newConfidence > 0.99 {
-						newConfidence = 0.99
+			// Check for similar existing beliefs about the same subject
+			if len(w.embedding) > 0 {
+				similar, err := s.memoryStore.FindSimilar(ctx, agentID, tenantID, w.embedding, SemanticSimilarityThreshold)
+				if err == nil {
+					incoming := &domain.Memory{Subject: w.subject, SubjectID: w.subjectID}
+					for i := range similar {
+						if domain.SameSubject(&similar[i].Memory, incoming) {
+							w.existing = &similar[i].Memory
+							break
+						}
 					}
-					_ = s.memoryStore.UpdateReinforcement(ctx, existingMem.ID, newConfidence, existingMem.ReinforcementCount+1)
-					result.reinforced++
-
-					// Link episode to existing memory
-					_ = s.episodeStore.LinkDerivedMemory(ctx, ep.ID, existingMem.ID, "semantic")
-					continue
 				}
 			}
+			writes = append(writes, w)
+		}
 
-			// Create new belief with confidence from EvidenceType if available
-			confidence := belief.Confidence * SemanticExtractionConfidenceDiscount
-			if belief.EvidenceType != "" {
-				confidence = belief.EvidenceType.InitialConfidence() * SemanticExtractionConfidenceDiscount
-			}
+		var extractedN, reinforcedN int
+		apply := func(ms domain.MemoryStore, es domain.EpisodeStore, as domain.MemoryAssociationStore) error {
+			var err error
+			extractedN, reinforcedN, err = s.writeEpisodeBeliefs(ctx, ms, es, as, &ep, agentID, tenantID, writes)
+			return err
+		}
 
-			mem := &domain.Memory{
-				AgentID:    agentID,
-				TenantID:   tenantID,
-				Content:    belief.Content,
-				Type:       belief.Type,
-				Confidence: confidence,
-				Source:     fmt.Sprintf("episode:%s", ep.ID),
-				Embedding:  embedding,
-			}
+		if s.uow != nil {
+			// Atomic path: derived memories, episode links, associations and the
+			// status flip commit together, so a failure leaves the episode
+			// "processed" and it is retried cleanly on the next pass.
+			err = s.uow.Do(ctx, func(st *store.TxStores) error {
+				return apply(st.Memory, st.Episode, st.Association)
+			})
+		} else {
+			err = apply(s.memoryStore, s.episodeStore, s.assocStore)
+		}
+		if err != nil {
+			logFor(ctx, s.logger).Warn("failed to write consolidated beliefs",
+				zap.String("episode_id", ep.ID.String()),
+				zap.Error(err))
+			s.recordEpisodeFailure(ctx, &ep, FailureStageBeliefWrite, err)
+			continue
+		}
+		s.attributeBeliefs(ctx, &ep, writes)
+		result.extracted += extractedN
+		result.reinforced += reinforcedN
+	}
 
-			if err := s.memoryStore.Create(ctx, mem); err != nil {
-				s.logger.Debug("failed to create belief", zap.Error(err))
-				continue
+	return result
+}
+
+// beliefWrite is one extracted belief with its embedding and, when a similar
+// belief already exists, the memory it reinforces instead of creating a new one.
+type beliefWrite struct {
+	belief    domain.ExtractedMemory
+	embedding []float32
+	existing  *domain.Memory
+	// subject and subjectID are who the belief is about, resolved against
+	// the episode's participants.
+	subject   string
+	subjectID *uuid.UUID
+	// memoryID is the belief created or reinforced, set once written.
+	memoryID uuid.UUID
+}
+
+// writeEpisodeBeliefs performs every write stage 2 makes for one episode and
+// stops at the first failure. The stores are either the service's own or
+// bound to a single transaction by the unit of work.
+func (s *ConsolidationService) writeEpisodeBeliefs(ctx context.Context, ms domain.MemoryStore, es domain.EpisodeStore, as domain.MemoryAssociationStore, ep *domain.Episode, agentID uuid.UUID, tenantID uuid.UUID, writes []beliefWrite) (extracted int, reinforced int, err error) {
+	speakers := ep.Speakers()
+	discount := s.reliability.extractionDiscount(ctx, agentID, tenantID, SemanticExtractionConfidenceDiscount)
+	for i := range writes {
+		w := &writes[i]
+		if w.existing != nil {
+			// Reinforce existing belief. The boost applies to the stored
+			// confidence: w.existing was read before extraction, and recall
+			// or another consolidation may have changed it since.
+			if err := ms.ReinforceBy(ctx, w.existing.ID, episodeBeliefReinforcementBoost); err != nil {
+				return 0, 0, fmt.Errorf("reinforce belief: %w", err)
+			}
+			if err := ms.AddEvidence(ctx, w.existing.ID, 1, 0); err != nil {
+				return 0, 0, fmt.Errorf("record evidence: %w", err)
 			}
+			if err := es.LinkDerivedMemory(ctx, ep.ID, w.existing.ID, "semantic"); err != nil {
+				return 0, 0, fmt.Errorf("link episode: %w", err)
+			}
+			w.memoryID = w.existing.ID
+			reinforced++
+			continue
+		}
 
-			// Link to episode
-			_ = s.episodeStore.LinkDerivedMemory(ctx, ep.ID, mem.ID, "semantic")
+		// Create new belief with confidence from EvidenceType if available
+		confidence := w.belief.Confidence * discount
+		if w.belief.EvidenceType != "" {
+			confidence = w.belief.EvidenceType.InitialConfidence() * discount
+		}
 
-			// Create association
-			if s.assocStore != nil {
-				assoc := &domain.MemoryAssociation{
-					TenantID:            ep.TenantID,
-					SourceMemoryType:    domain.ActivatedMemoryTypeEpisodic,
-					SourceMemoryID:      ep.ID,
-					TargetMemoryType:    domain.ActivatedMemoryTypeSemantic,
-					TargetMemoryID:      mem.ID,
-					AssociationType:     domain.AssociationTypeDerived,
-					AssociationStrength: 0.9,
-				}
-				_ = s.assocStore.Create(ctx, assoc)
+		mem := &domain.Memory{
+			AgentID:    agentID,
+			TenantID:   tenantID,
+			Content:    w.belief.Content,
+			Type:       w.belief.Type,
+			Confidence: confidence,
+			Source:     fmt.Sprintf("episode:%s", ep.ID),
+			Embedding:  w.embedding,
+			Subject:    w.subject,
+			SubjectID:  w.subjectID,
+			Topic:      domain.NormalizeTopic(w.belief.Topic),
+		}
+		if len(speakers) == 1 {
+			mem.Metadata = map[string]any{domain.AttributedToKey: speakers[0].Name}
+			if speakers[0].EntityID != nil {
+				mem.Metadata[domain.AttributedEntityIDKey] = speakers[0].EntityID.String()
 			}
+		}
+		if ep.Environment != nil {
+			mem.Metadata = domain.WithEnvironment(mem.Metadata, *ep.Environment)
+		}
+		if err := ms.Create(ctx, mem); err != nil {
+			return 0, 0, fmt.Errorf("create belief: %w", err)
+		}
+		w.memoryID = mem.ID
+		if err := es.LinkDerivedMemory(ctx, ep.ID, mem.ID, "semantic"); err != nil {
+			return 0, 0, fmt.Errorf("link episode: %w", err)
+		}
 
-			result.extracted++
+		if as != nil {
+			assoc := &domain.MemoryAssociation{
+				TenantID:            ep.TenantID,
+				SourceMemoryType:    domain.ActivatedMemoryTypeEpisodic,
+				SourceMemoryID:      ep.ID,
+				TargetMemoryType:    domain.ActivatedMemoryTypeSemantic,
+				TargetMemoryID:      mem.ID,
+				AssociationType:     domain.AssociationTypeDerived,
+				AssociationStrength: 0.9,
+			}
+			if err := as.Create(ctx, assoc); err != nil {
+				return 0, 0, fmt.Errorf("create association: %w", err)
+			}
 		}
+		extracted++
+	}
 
-		// Mark episode as abstracted
-		_ = s.episodeStore.UpdateConsolidationStatus(ctx, ep.ID, domain.ConsolidationAbstracted)
+	// Mark episode as abstracted
+	if err := es.UpdateConsolidationStatus(ctx, ep.ID, domain.ConsolidationAbstracted); err != nil {
+		return 0, 0, fmt.Errorf("mark abstracted: %w", err)
 	}
+	return extracted, reinforced, nil
+}
 
-	return result
+// attributeBeliefs records each belief written from ep as said by the
+// episode's speaker: a subject mention linking the speaker's person entity to
+// the belief, so recall about that person reaches it. Episodes with several
+// speakers or none are left unattributed, since which of them a belief came
+// from is unknown.
+func (s *ConsolidationService) attributeBeliefs(ctx context.Context, ep *domain.Episode, writes []beliefWrite) {
+	speakers := ep.Speakers()
+	if s.entityStore == nil || len(speakers) != 1 || speakers[0].EntityID == nil {
+		return
+	}
+	for _, w := range writes {
+		if w.memoryID == uuid.Nil {
+			continue
+		}
+		err := s.entityStore.CreateMention(ctx, &domain.EntityMention{
+			EntityID:    *speakers[0].EntityID,
+			MemoryID:    w.memoryID,
+			MentionType: domain.MentionSubject,
+		})
+		if err != nil {
+			logFor(ctx, s.logger).Warn("failed to attribute belief to speaker",
+				zap.String("episode_id", ep.ID.String()), zap.Error(err))
+		}
+	}
 }
 
 // getProcessedEpisodes gets episodes in "processed" state ready for semantic extraction.
@@ -522,10 +856,11 @@ func (s *ConsolidationService) learnProcedures(ctx context.Context, agentID uuid
 	// Get episodes with successful outcomes that haven't been processed for procedures
 	// For now, get recent episodes and check outcomes
 	episodes, err := s.episodeStore.GetByTimeRange(ctx, agentID, tenantID,
-		time.Now().Add(-24*time.Hour*7), time.Now()) // Last 7 days
+		timeNow().Add(-24*time.Hour*7), timeNow()) // Last 7 days
 	if err != nil {
 		return result
 	}
+	s.positions.WeighImportance(ctx, agentID, tenantID, episodes)
 
 	for _, ep := range episodes {
 		if ep.Outcome != domain.OutcomeSuccess {
@@ -546,6 +881,9 @@ func (s *ConsolidationService) learnProcedures(ctx context.Context, agentID uuid
 		if err != nil || pattern == nil || pattern.TriggerPattern == "" {
 			continue
 		}
+		if domain.ValidateActionTemplate(pattern.ActionTemplate) != nil {
+			continue
+		}
 
 		// Generate embedding
 		var embedding []float32
@@ -579,11 +917,11 @@ func (s *ConsolidationService) learnProcedures(ctx context.Context, agentID uuid
 			MemoryStrength:      1.0,
 		}
 
-		now := time.Now()
+		now := timeNow()
 		proc.LastVerifiedAt = &now
 
 		if err := s.procedureStore.Create(ctx, proc); err != nil {
-			s.logger.Debug("failed to create procedure", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to create procedure", zap.Error(err))
 			continue
 		}
 
@@ -615,174 +953,167 @@ type stage4Result struct {
 	updated  int
 }
 
-func (s *ConsolidationService) formSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) stage4Result {
-	result := stage4Result{}
+func (s *ConsolidationService) formSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, fullPass bool) stage4Result {
+	if s.schemaStore == nil || s.memoryStore == nil {
+		return stage4Result{}
+	}
 
-	if s.llmClient == nil || s.schemaStore == nil || s.memoryStore == nil {
-		return result
+	former := &schemaFormer{
+		schemaStore:     s.schemaStore,
+		memoryStore:     s.memoryStore,
+		scanner:         s.scanner,
+		assocStore:      s.assocStore,
+		embeddingClient: s.embeddingClient,
+		llmClient:       s.llmClient,
+		clusterer:       s.clusterer,
+		logger:          s.logger,
 	}
+	pass, err := former.pass(ctx, agentID, tenantID, fullPass)
+	if err != nil {
+		logFor(ctx, s.logger).Warn("schema formation failed", zap.String("agent_id", agentID.String()), zap.Error(err))
+		return stage4Result{}
+	}
+	return stage4Result{detected: len(pass.created), updated: pass.grown}
+}
+
+// Stage 4b: Summary Rollups
+type rollupResult struct {
+	created   int
+	refreshed int
+}
+
+// rollupSummaries clusters related beliefs tightly and writes one summary
+// memory per topic so recall can return the rollup instead of every detail.
+// A cluster that substantially overlaps an existing summary replaces it when
+// its membership changed; identical clusters are left alone.
+func (s *ConsolidationService) rollupSummaries(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) rollupResult {
+	result := rollupResult{}
 
-	// Get all semantic memories for clustering
-	allMemories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
-	if err != nil || len(allMemories) < SchemaMinEvidenceCount {
+	if s.llmClient == nil || s.memoryStore == nil {
 		return result
 	}
 
-	// Filter to memories with embeddings, sufficient confidence, and stability
-	now := time.Now()
-	var memoriesWithEmbeddings []domain.Memory
-	for _, m := range allMemories {
-		if len(m.Embedding) == 0 {
-			continue
+	var beliefs, summaries []domain.Memory
+	err := forEachLiveMemory(ctx, s.scanner, s.memoryStore, agentID, func(m *domain.Memory) error {
+		if m.Type == domain.MemoryTypeSummary {
+			summaries = append(summaries, *m)
+			return nil
 		}
-		if m.Confidence < 0.6 {
-			continue
-		}
-		if now.Sub(m.CreatedAt) < 24*time.Hour {
-			continue
+		if len(m.Embedding) == 0 || m.Confidence < MinEvidenceConfidence {
+			return nil
 		}
-		memoriesWithEmbeddings = append(memoriesWithEmbeddings, m)
+		beliefs = append(beliefs, *m)
+		return nil
+	})
+	if err != nil {
+		return result
 	}
-
-	if len(memoriesWithEmbeddings) < SchemaMinEvidenceCount {
+	if len(beliefs) < SummaryMinMembers {
 		return result
 	}
 
-	// Cluster memories by similarity
-	clusters := s.clusterMemories(memoriesWithEmbeddings)
-
-	for _, cluster := range clusters {
-		if len(cluster.Memories) < SchemaMinEvidenceCount {
-			continue
+	existingMembers := make([]map[uuid.UUID]bool, len(summaries))
+	for i, sm := range summaries {
+		existingMembers[i] = make(map[uuid.UUID]bool)
+		for _, id := range domain.SummaryMemberIDs(sm) {
+			existingMembers[i][id] = true
 		}
+	}
 
-		// Use LLM to detect schema pattern
-		extraction, err := s.llmClient.DetectSchemaPattern(ctx, cluster.Memories)
-		if err != nil || extraction == nil {
+	clusters := NewAgglomerativeClusterer(SummaryClusterThreshold).Cluster(beliefs)
+	for _, cluster := range clusters {
+		if len(cluster.Memories) < SummaryMinMembers {
 			continue
 		}
 
-		// Check if schema already exists
-		existing, err := s.schemaStore.GetByName(ctx, agentID, tenantID, extraction.SchemaType, extraction.Name)
-		if err == nil && existing != nil {
-			// Update existing schema with new evidence
-			newCount := 0
-			existingIDs := make(map[uuid.UUID]bool)
-			for _, id := range existing.EvidenceMemories {
-				existingIDs[id] = true
-			}
-			for _, id := range cluster.MemoryIDs {
-				if !existingIDs[id] {
-					_ = s.schemaStore.AddEvidence(ctx, existing.ID, &id, nil)
-					newCount++
-				}
-			}
-			if newCount > 0 {
-				// Boost confidence
-				newConfidence := existing.Confidence + float32(newCount)*0.02
-				if newConfidence > 0.95 {
-					newConfidence = 0.95
-				}
-				_ = s.schemaStore.UpdateConfidence(ctx, existing.ID, newConfidence)
-				result.updated++
+		// Find the existing summary this cluster most overlaps.
+		replace, bestOverlap := -1, float32(0)
+		for i, members := range existingMembers {
+			if overlap := jaccard(members, cluster.MemoryIDs); overlap > bestOverlap {
+				replace, bestOverlap = i, overlap
 			}
+		}
+		if bestOverlap == 1 {
 			continue
 		}
-
-		// Create new schema
-		schema := &domain.Schema{
-			AgentID:            agentID,
-			TenantID:           tenantID,
-			SchemaType:         extraction.SchemaType,
-			Name:               extraction.Name,
-			Description:        extraction.Description,
-			Attributes:         extraction.Attributes,
-			ApplicableContexts: extraction.ApplicableContexts,
-			EvidenceMemories:   cluster.MemoryIDs,
-			EvidenceCount:      len(cluster.Memories),
-			Confidence:         0.5 + float32(len(cluster.Memories))*0.05,
+		if bestOverlap < SummaryRefreshOverlap {
+			replace = -1
 		}
 
-		if schema.Confidence > 0.8 {
-			schema.Confidence = 0.8
+		content, err := s.llmClient.Summarize(ctx, cluster.Memories)
+		if err != nil || content == "" {
+			logFor(ctx, s.logger).Debug("failed to summarize cluster", zap.Error(err))
+			continue
 		}
 
-		now := time.Now()
-		schema.LastValidatedAt = &now
-
-		// Generate embedding for schema
+		summary := newSummaryMemory(agentID, tenantID, content, cluster)
 		if s.embeddingClient != nil {
-			schemaText := schema.Name + ": " + schema.Description
-			embedding, _ := s.embeddingClient.Embed(ctx, schemaText)
-			schema.Embedding = embedding
+			if emb, err := s.embeddingClient.Embed(ctx, content); err == nil {
+				summary.Embedding = emb
+			}
 		}
-
-		if err := s.schemaStore.Create(ctx, schema); err != nil {
-			s.logger.Debug("failed to create schema", zap.Error(err))
+		if err := s.memoryStore.Create(ctx, summary); err != nil {
+			logFor(ctx, s.logger).Debug("failed to store summary", zap.Error(err))
 			continue
 		}
+		s.hooks.memoryCreated(ctx, summary)
 
-		// Create associations from memories to schema
-		if s.assocStore != nil {
-			for _, memID := range cluster.MemoryIDs {
-				assoc := &domain.MemoryAssociation{
-					TenantID:            tenantID,
-					SourceMemoryType:    domain.ActivatedMemoryTypeSemantic,
-					SourceMemoryID:      memID,
-					TargetMemoryType:    domain.ActivatedMemoryTypeSchema,
-					TargetMemoryID:      schema.ID,
-					AssociationType:     domain.AssociationTypeDerived,
-					AssociationStrength: 0.8,
-				}
-				_ = s.assocStore.Create(ctx, assoc)
+		if replace >= 0 {
+			if err := s.memoryStore.Archive(ctx, summaries[replace].ID); err == nil {
+				s.hooks.memoryArchived(ctx, memoryEvent(&summaries[replace], "summary replaced by a refreshed one"))
 			}
+			existingMembers[replace] = nil
+			result.refreshed++
+			continue
 		}
-
-		result.detected++
+		result.created++
 	}
 
 	return result
 }
 
-// clusterMemories groups memories by embedding similarity.
-func (s *ConsolidationService) clusterMemories(memories []domain.Memory) []domain.MemoryCluster {
-	const clusterThreshold = 0.65
-
-	assigned := make(map[uuid.UUID]bool)
-	var clusters []domain.MemoryCluster
-
-	for i, seed := range memories {
-		if assigned[seed.ID] {
-			continue
-		}
-
-		cluster := domain.MemoryCluster{
-			Memories:  []domain.Memory{seed},
-			MemoryIDs: []uuid.UUID{seed.ID},
-			Centroid:  cloneVector(seed.Embedding),
+// newSummaryMemory builds the rollup memory for a cluster. Its confidence is
+// the members' mean, and its topic is the content of the member closest to
+// the cluster centroid.
+func newSummaryMemory(agentID uuid.UUID, tenantID uuid.UUID, content string, cluster domain.MemoryCluster) *domain.Memory {
+	var total float32
+	topic, bestSim := "", float32(-1)
+	memberIDs := make([]string, len(cluster.MemoryIDs))
+	for i, m := range cluster.Memories {
+		total += m.Confidence
+		memberIDs[i] = m.ID.String()
+		if sim := cosineSimilarity(cluster.Centroid, m.Embedding); sim > bestSim {
+			topic, bestSim = m.Content, sim
 		}
-		assigned[seed.ID] = true
-
-		for j := i + 1; j < len(memories); j++ {
-			candidate := memories[j]
-			if assigned[candidate.ID] {
-				continue
-			}
+	}
 
-			similarity := cosineSimilarity(cluster.Centroid, candidate.Embedding)
-			if similarity >= clusterThreshold {
-				cluster.Memories = append(cluster.Memories, candidate)
-				cluster.MemoryIDs = append(cluster.MemoryIDs, candidate.ID)
-				assigned[candidate.ID] = true
+	return &domain.Memory{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       domain.MemoryTypeSummary,
+		Content:    content,
+		Source:     "consolidation-rollup",
+		Provenance: domain.ProvenanceDerived,
+		Confidence: total / float32(len(cluster.Memories)),
+		Metadata: map[string]any{
+			domain.SummaryMembersKey: memberIDs,
+			domain.SummaryTopicKey:   topic,
+		},
+	}
+}
 
-				cluster.Centroid = incrementalMean(cluster.Centroid, candidate.Embedding, len(cluster.Memories))
-			}
+// jaccard returns |a ∩ b| / |a ∪ b| for a member set and a list of IDs.
+func jaccard(a map[uuid.UUID]bool, b []uuid.UUID) float32 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for _, id := range b {
+		if a[id] {
+			inter++
 		}
-
-		clusters = append(clusters, cluster)
 	}
-
-	return clusters
+	return float32(inter) / float32(len(a)+len(b)-inter)
 }
 
 // Stage 5: Forgetting and Pruning
@@ -794,25 +1125,31 @@ type stage5Result struct {
 
 func (s *ConsolidationService) applyForgetting(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, fullPrune bool) stage5Result {
 	result := stage5Result{}
-	now := time.Now()
+	now := timeNow()
 
 	// Apply decay to semantic memories
 	if s.memoryStore != nil && s.decayService != nil {
 		cogResult, err := s.decayService.BatchDecay(ctx, agentID)
 		if err != nil {
-			s.logger.Warn("decay failed", zap.Error(err))
+			logFor(ctx, s.logger).Warn("decay failed", zap.Error(err))
 		} else {
 			result.decayed = cogResult.Decayed
 			result.archived = cogResult.Archived
 
 			if len(cogResult.TierTransitions) > 0 {
-				s.logger.Debug("tier transitions during decay",
+				logFor(ctx, s.logger).Debug("tier transitions during decay",
 					zap.Int("count", len(cogResult.TierTransitions)))
 			}
 		}
 
 		// Merge redundant memories if full prune
-		if fullPrune {
+		if fullPrune && s.redundancyStore != nil {
+			merged, err := s.mergeRedundantPairs(ctx, agentID, tenantID)
+			if err != nil {
+				logFor(ctx, s.logger).Warn("redundancy merge failed", zap.Error(err))
+			}
+			result.merged = merged
+		} else if fullPrune {
 			memories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
 			if err == nil {
 				merged := s.mergeRedundantMemories(ctx, agentID, tenantID, memories)
@@ -854,16 +1191,17 @@ func (s *ConsolidationService) applyForgetting(ctx context.Context, agentID uuid
 		}
 	}
 
-	// Episodes are handled by DecayService
+	// Episodes are decayed and archived by DecayService, which spares
+	// flashbulb episodes
 	if s.graphStore != nil {
 		const edgeDecayRate = 0.0005 // λ = 0.0005 per hour (slower than memories)
 
 		// Decay edge strength
 		decayResult, err := s.graphStore.ApplyEdgeDecay(ctx, agentID, edgeDecayRate)
 		if err != nil {
-			s.logger.Warn("edge decay failed", zap.Error(err))
+			logFor(ctx, s.logger).Warn("edge decay failed", zap.Error(err))
 		} else if decayResult.Decayed > 0 {
-			s.logger.Debug("edge decay applied",
+			logFor(ctx, s.logger).Debug("edge decay applied",
 				zap.Int("decayed", decayResult.Decayed))
 		}
 
@@ -871,9 +1209,9 @@ func (s *ConsolidationService) applyForgetting(ctx context.Context, agentID uuid
 		if fullPrune {
 			pruneResult, err := s.graphStore.PruneGraph(ctx, agentID, domain.DefaultPruningRules())
 			if err != nil {
-				s.logger.Warn("graph pruning failed", zap.Error(err))
+				logFor(ctx, s.logger).Warn("graph pruning failed", zap.Error(err))
 			} else if pruneResult.Pruned > 0 {
-				s.logger.Debug("graph pruning complete",
+				logFor(ctx, s.logger).Debug("graph pruning complete",
 					zap.Int("pruned", pruneResult.Pruned))
 			}
 		}
@@ -883,7 +1221,7 @@ func (s *ConsolidationService) applyForgetting(ctx context.Context, agentID uuid
 }
 
 // mergeRedundantMemories finds and merges highly similar memories.
-func (s *ConsolidationService) mergeRedundantMemories(ctx context.Context, _, _ uuid.UUID, memories []domain.Memory) int {
+func (s *ConsolidationService) mergeRedundantMemories(ctx context.Context, agentID, tenantID uuid.UUID, memories []domain.Memory) int {
 	merged := 0
 	toArchive := make(map[uuid.UUID]bool)
 
@@ -891,7 +1229,9 @@ func (s *ConsolidationService) mergeRedundantMemories(ctx context.Context, _, _
 		if toArchive[memories[i].ID] {
 			continue
 		}
-		if len(memories[i].Embedding) == 0 {
+		// Summaries sit close to the memories they cover by construction;
+		// merging would archive one or the other.
+		if len(memories[i].Embedding) == 0 || memories[i].Type == domain.MemoryTypeSummary {
 			continue
 		}
 
@@ -899,33 +1239,20 @@ func (s *ConsolidationService) mergeRedundantMemories(ctx context.Context, _, _
 			if toArchive[memories[j].ID] {
 				continue
 			}
-			if len(memories[j].Embedding) == 0 {
+			if len(memories[j].Embedding) == 0 || memories[j].Type == domain.MemoryTypeSummary {
 				continue
 			}
 
 			similarity := cosineSimilarity(memories[i].Embedding, memories[j].Embedding)
 			if similarity >= RedundancyThreshold {
-				// Merge: keep the one with higher confidence/reinforcement
-				keepIdx, archiveIdx := i, j
-				if memories[j].Confidence > memories[i].Confidence ||
-					memories[j].ReinforcementCount > memories[i].ReinforcementCount {
-					keepIdx, archiveIdx = j, i
+				kept, archived := s.mergeRedundantPair(ctx, agentID, tenantID, redundancyMember(&memories[i]), redundancyMember(&memories[j]))
+				keepIdx := i
+				if kept.ID == memories[j].ID {
+					keepIdx = j
 				}
-
-				// Reinforce the kept memory
-				newConfidence := memories[keepIdx].Confidence + 0.02
-				if newConfidence > 0.99 {
-					newConfidence = 0.99
-				}
-				newCount := memories[keepIdx].ReinforcementCount + 1
-				_ = s.memoryStore.UpdateReinforcement(ctx, memories[keepIdx].ID, newConfidence, newCount)
-
-				memories[keepIdx].Confidence = newConfidence
-				memories[keepIdx].ReinforcementCount = newCount
-
-				// Archive the redundant one
-				_ = s.memoryStore.Archive(ctx, memories[archiveIdx].ID)
-				toArchive[memories[archiveIdx].ID] = true
+				memories[keepIdx].Confidence = kept.Confidence
+				memories[keepIdx].ReinforcementCount = kept.ReinforcementCount
+				toArchive[archived] = true
 				merged++
 			}
 		}
@@ -934,6 +1261,61 @@ func (s *ConsolidationService) mergeRedundantMemories(ctx context.Context, _, _
 	return merged
 }
 
+// mergeRedundantPairs merges the near-duplicates the redundancy store streams
+// back. Pairs come from a snapshot, so memories archived or reinforced earlier
+// in the stream are tracked here.
+func (s *ConsolidationService) mergeRedundantPairs(ctx context.Context, agentID, tenantID uuid.UUID) (int, error) {
+	merged := 0
+	archived := make(map[uuid.UUID]bool)
+	current := make(map[uuid.UUID]domain.RedundancyMember)
+	latest := func(m domain.RedundancyMember) domain.RedundancyMember {
+		if c, ok := current[m.ID]; ok {
+			return c
+		}
+		return m
+	}
+
+	err := s.redundancyStore.StreamRedundantPairs(ctx, agentID, RedundancyThreshold, redundancyNeighbors, func(p domain.RedundantPair) error {
+		if archived[p.A.ID] || archived[p.B.ID] {
+			return nil
+		}
+		kept, dropped := s.mergeRedundantPair(ctx, agentID, tenantID, latest(p.A), latest(p.B))
+		current[kept.ID] = kept
+		archived[dropped] = true
+		merged++
+		return ctx.Err()
+	})
+	return merged, err
+}
+
+// mergeRedundantPair keeps the memory with higher confidence/reinforcement,
+// reinforces it and archives the other. It returns the kept memory's new
+// state and the archived ID.
+func (s *ConsolidationService) mergeRedundantPair(ctx context.Context, agentID, tenantID uuid.UUID, a, b domain.RedundancyMember) (domain.RedundancyMember, uuid.UUID) {
+	keep, drop := a, b
+	if b.Confidence > a.Confidence || b.ReinforcementCount > a.ReinforcementCount {
+		keep, drop = b, a
+	}
+
+	keep.Confidence += 0.02
+	if keep.Confidence > 0.99 {
+		keep.Confidence = 0.99
+	}
+	keep.ReinforcementCount++
+	if err := s.memoryStore.UpdateReinforcement(ctx, keep.ID, keep.Confidence, keep.ReinforcementCount); err == nil {
+		s.hooks.memoryReinforced(ctx, MemoryEvent{MemoryID: keep.ID, AgentID: agentID, TenantID: tenantID, Reason: "merged with a redundant memory"})
+	}
+	if err := s.memoryStore.Archive(ctx, drop.ID); err == nil {
+		s.hooks.memoryArchived(ctx, MemoryEvent{MemoryID: drop.ID, AgentID: agentID, TenantID: tenantID, Reason: "merged into a redundant memory"})
+	}
+
+	return keep, drop.ID
+}
+
+func redundancyMember(m *domain.Memory) domain.RedundancyMember {
+	return domain.RedundancyMember{ID: m.ID, Confidence: m.Confidence, ReinforcementCount: m.ReinforcementCount}
+}
+
 // GetMemoryHealth returns statistics about the memory system health for an agent.
 func (s *ConsolidationService) GetMemoryHealth(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*MemoryHealthStats, error) {
 	stats := &MemoryHealthStats{
@@ -942,47 +1324,61 @@ func (s *ConsolidationService) GetMemoryHealth(ctx context.Context, agentID uuid
 
 	// Count memories by type
 	if s.memoryStore != nil {
-		memories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
+		ms, err := agentMemoryStats(ctx, s.scanner, s.memoryStore, agentID, domain.MemoryStatsQuery{
+			AtRiskBelow:    0.3,
+			UncertainBelow: 0.5, // low-confidence topics become uncertainty areas
+			RecentSince:    timeNow().Add(-24 * time.Hour),
+			TrendSince:     timeNow().Add(-TopicTrendWindow),
+			TrendWindow:    TopicTrendWindow,
+			MaxTopics:      MaxHealthTopics,
+		})
 		if err == nil {
-			stats.SemanticCount = len(memories)
-
-			var totalConfidence float32
-			recentThreshold := time.Now().Add(-24 * time.Hour)
-
-			for _, m := range memories {
-				totalConfidence += m.Confidence
+			stats.SemanticCount = ms.Count
+			stats.MemoriesAtRisk = ms.AtRisk
+			stats.RecentlyReinforced = ms.RecentlyReinforced
+			stats.UncertaintyAreas = ms.UncertainTopics
+			stats.AverageConfidence = ms.AverageConfidence
+			stats.MemoriesByType = ms.ByType
+			stats.MemoriesByTier = ms.ByTier
+			stats.Topics = ms.ByTopic
+		}
+	}
 
-				if m.Confidence < 0.3 {
-					stats.MemoriesAtRisk++
-				}
+	if s.healthStore != nil {
+		if c, err := s.healthStore.AgentHealthCounts(ctx, agentID, tenantID); err == nil {
+			stats.EpisodicCount = c.UnconsolidatedEpisodes
+			stats.OldestUnprocessed = c.OldestUnconsolidated
+			stats.ProceduralCount = c.Procedures
+			stats.SchemaCount = c.Schemas
+		}
+	} else {
+		s.countByLoading(ctx, stats, agentID, tenantID)
+	}
 
-				if m.LastAccessedAt != nil && m.LastAccessedAt.After(recentThreshold) {
-					stats.RecentlyReinforced++
+	// Count contradictions
+	if s.contradictionStore != nil {
+		if n, err := s.contradictionStore.CountByAgent(ctx, agentID, tenantID); err == nil {
+			stats.ContradictionCount = n
+		}
+		if len(stats.Topics) > 0 {
+			if counts, err := s.contradictionStore.CountByTopic(ctx, agentID, tenantID); err == nil {
+				byTopic := make(map[string]int, len(counts))
+				for _, c := range counts {
+					byTopic[c.Topic] = c.Contradicted
 				}
-
-				// Track low confidence areas
-				if m.Confidence < 0.5 {
-					// Group by type for uncertainty areas
-					typeStr := string(m.Type)
-					found := false
-					for _, area := range stats.UncertaintyAreas {
-						if area == typeStr {
-							found = true
-							break
-						}
-					}
-					if !found {
-						stats.UncertaintyAreas = append(stats.UncertaintyAreas, typeStr)
-					}
+				for i := range stats.Topics {
+					stats.Topics[i].Contradicted = byTopic[stats.Topics[i].Topic]
 				}
 			}
-
-			if len(memories) > 0 {
-				stats.AverageConfidence = totalConfidence / float32(len(memories))
-			}
 		}
 	}
 
+	return stats, nil
+}
+
+// countByLoading fills the episode, procedure and schema counts by loading the
+// rows, for deployments without a HealthStore. Episodes are capped at 1000.
+func (s *ConsolidationService) countByLoading(ctx context.Context, stats *MemoryHealthStats, agentID, tenantID uuid.UUID) {
 	// Count episodes
 	if s.episodeStore != nil {
 		episodes, err := s.episodeStore.GetUnconsolidated(ctx, agentID, 1000)
@@ -1016,19 +1412,11 @@ func (s *ConsolidationService) GetMemoryHealth(ctx context.Context, agentID uuid
 			stats.SchemaCount = len(schemas)
 		}
 	}
-
-	// Count contradictions
-	if s.contradictionStore != nil {
-		if n, err := s.contradictionStore.CountByAgent(ctx, agentID, tenantID); err == nil {
-			stats.ContradictionCount = n
-		}
-	}
-
-	return stats, nil
 }
 
-// GetAgentsNeedingConsolidation returns agent IDs that have unprocessed episodes.
-func (s *ConsolidationService) GetAgentsNeedingConsolidation(ctx context.Context) ([]uuid.UUID, error) {
-	// This would need a new store method. For now, use memory store's list.
-	return s.memoryStore.ListDistinctAgentIDs(ctx)
+// GetAgentsNeedingConsolidation returns the agents with raw episodes to
+// consolidate or live memories to maintain. Without an agent registry it
+// falls back to the agents with live memories, with unknown tenants.
+func (s *ConsolidationService) GetAgentsNeedingConsolidation(ctx context.Context) ([]domain.AgentRef, error) {
+	return agentsWithWork(ctx, s.agents, domain.AgentWorkFilter{UnconsolidatedEpisodes: true, LiveMemories: true}, s.memoryStore.ListDistinctAgentIDs)
 }
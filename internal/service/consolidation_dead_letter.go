@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var ErrDeadLetterNotFound = errors.New("episode is not dead-lettered")
+
+// MaxConsolidationFailures is how many times an episode's belief extraction
+// may fail before it is dead-lettered instead of retried on every pass.
+const MaxConsolidationFailures = 3
+
+// Consolidation stages that record failures.
+const (
+	FailureStageBeliefExtraction = "belief_extraction"
+	FailureStageBeliefWrite      = "belief_write"
+)
+
+// SetFailureStore enables failure counting and dead-lettering. Without it a
+// failing episode is retried on every pass.
+func (s *ConsolidationService) SetFailureStore(fs domain.ConsolidationFailureStore) {
+	s.failureStore = fs
+}
+
+// recordEpisodeFailure counts a failed attempt at consolidating ep and moves
+// it to the dead-letter status once it has failed MaxConsolidationFailures
+// times. Failures caused by the pass itself being cancelled are not counted.
+func (s *ConsolidationService) recordEpisodeFailure(ctx context.Context, ep *domain.Episode, stage string, cause error) {
+	if s.failureStore == nil || ctx.Err() != nil {
+		return
+	}
+	f := &domain.ConsolidationFailure{
+		EpisodeID: ep.ID,
+		TenantID:  ep.TenantID,
+		AgentID:   ep.AgentID,
+		Stage:     stage,
+		LastError: cause.Error(),
+	}
+	if err := s.failureStore.RecordFailure(ctx, f, MaxConsolidationFailures); err != nil {
+		logFor(ctx, s.logger).Warn("failed to record consolidation failure",
+			zap.String("episode_id", ep.ID.String()),
+			zap.Error(err))
+		return
+	}
+	if f.DeadLetteredAt == nil {
+		return
+	}
+	if err := s.episodeStore.UpdateConsolidationStatus(ctx, ep.ID, domain.ConsolidationDeadLetter); err != nil {
+		logFor(ctx, s.logger).Warn("failed to dead-letter episode",
+			zap.String("episode_id", ep.ID.String()),
+			zap.Error(err))
+		return
+	}
+	logFor(ctx, s.logger).Warn("episode dead-lettered after repeated consolidation failures",
+		zap.String("episode_id", ep.ID.String()),
+		zap.String("stage", stage),
+		zap.Int("failures", f.FailureCount),
+		zap.Error(cause))
+}
+
+// ListDeadLetters returns the agent's dead-lettered episodes with their
+// failure details.
+func (s *ConsolidationService) ListDeadLetters(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]domain.ConsolidationFailure, int, error) {
+	if s.failureStore == nil {
+		return nil, 0, nil
+	}
+	return s.failureStore.ListDeadLettered(ctx, agentID, tenantID, limit, offset)
+}
+
+// RequeueDeadLetter clears a dead-lettered episode's failure count and returns
+// it to the processed status, so the next consolidation pass retries it.
+func (s *ConsolidationService) RequeueDeadLetter(ctx context.Context, episodeID, tenantID uuid.UUID) error {
+	if s.failureStore == nil {
+		return ErrDeadLetterNotFound
+	}
+	if err := s.failureStore.Requeue(ctx, episodeID, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrDeadLetterNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -81,6 +85,10 @@ func (m *mockMemoryStoreForConsolidation) FindSimilar(ctx context.Context, agent
 	return nil, nil
 }
 
+func (m *mockMemoryStoreForConsolidation) FindSimilarFiltered(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, filter domain.SimilarityFilter) ([]domain.MemoryWithScore, error) {
+	return m.FindSimilar(ctx, agentID, tenantID, embedding, threshold)
+}
+
 func (m *mockMemoryStoreForConsolidation) GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType domain.MemoryType, limit int) ([]domain.MemoryWithScore, error) {
 	return nil, nil
 }
@@ -90,6 +98,23 @@ func (m *mockMemoryStoreForConsolidation) UpdateReinforcement(ctx context.Contex
 	return nil
 }
 
+func (m *mockMemoryStoreForConsolidation) ReinforceBy(ctx context.Context, id uuid.UUID, boost float32) error {
+	conf, ok := m.updated[id]
+	if !ok {
+		for _, mem := range m.memories {
+			if mem.ID == id {
+				conf = mem.Confidence
+			}
+		}
+	}
+	m.updated[id] = clampConf(conf + boost)
+	return nil
+}
+
+func (m *mockMemoryStoreForConsolidation) AddEvidence(ctx context.Context, id uuid.UUID, supporting, contradicting float64) error {
+	return nil
+}
+
 func (m *mockMemoryStoreForConsolidation) UpdateContent(ctx context.Context, id uuid.UUID, content string, embedding []float32) error {
 	return nil
 }
@@ -159,6 +184,18 @@ func (m *mockMemoryStoreForConsolidation) GetTierCounts(ctx context.Context, age
 	return nil, nil
 }
 
+func (m *mockMemoryStoreForConsolidation) ApplyTierTransitions(ctx context.Context, transitions []domain.TierTransition) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForConsolidation) SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForConsolidation) GetTierHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memoryID *uuid.UUID, since time.Time, limit int) ([]domain.TierTransition, error) {
+	return nil, nil
+}
+
 func (m *mockMemoryStoreForConsolidation) SetNeedsReview(ctx context.Context, id uuid.UUID, needsReview bool) error {
 	return nil
 }
@@ -229,6 +266,16 @@ func (m *mockEpisodeStoreForConsolidation) FindSimilar(ctx context.Context, agen
 	return nil, nil
 }
 
+func (m *mockEpisodeStoreForConsolidation) GetReplayCandidates(ctx context.Context, agentID uuid.UUID, limit int) ([]domain.Episode, error) {
+	var raw []domain.Episode
+	for _, ep := range m.episodes {
+		if ep.AgentID == agentID && ep.ConsolidationStatus == domain.ConsolidationRaw {
+			raw = append(raw, ep)
+		}
+	}
+	return replayCandidates(raw, limit), nil
+}
+
 func (m *mockEpisodeStoreForConsolidation) GetUnconsolidated(ctx context.Context, agentID uuid.UUID, limit int) ([]domain.Episode, error) {
 	var result []domain.Episode
 	for _, ep := range m.episodes {
@@ -355,6 +402,14 @@ func (m *mockProcedureStoreForConsolidation) FindByTriggerSimilarity(ctx context
 	return nil, nil
 }
 
+func (m *mockProcedureStoreForConsolidation) FindByTriggerKeywords(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, terms []string, limit int) ([]domain.ProcedureWithScore, error) {
+	return nil, nil
+}
+
+func (m *mockProcedureStoreForConsolidation) FindConditional(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]domain.Procedure, error) {
+	return nil, nil
+}
+
 func (m *mockProcedureStoreForConsolidation) Reinforce(ctx context.Context, id uuid.UUID, episodeID uuid.UUID, boost float32) error {
 	return nil
 }
@@ -466,6 +521,18 @@ func (m *mockSchemaStoreForConsolidation) UpdateValidation(ctx context.Context,
 	return nil
 }
 
+func (m *mockSchemaStoreForConsolidation) SetParent(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, parentID *uuid.UUID) error {
+	return nil
+}
+
+func (m *mockSchemaStoreForConsolidation) GetPassState(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*domain.SchemaPassState, error) {
+	return nil, store.ErrNotFound
+}
+
+func (m *mockSchemaStoreForConsolidation) RecordPass(ctx context.Context, state *domain.SchemaPassState) error {
+	return nil
+}
+
 func (m *mockSchemaStoreForConsolidation) RemoveEvidence(ctx context.Context, id uuid.UUID, memoryID *uuid.UUID, episodeID *uuid.UUID) error {
 	return nil
 }
@@ -476,9 +543,13 @@ func (m *mockSchemaStoreForConsolidation) Update(ctx context.Context, s *domain.
 
 type mockAssocStoreForConsolidation struct {
 	associations []domain.MemoryAssociation
+	createErr    error
 }
 
 func (m *mockAssocStoreForConsolidation) Create(ctx context.Context, a *domain.MemoryAssociation) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
 	a.ID = uuid.New()
 	a.CreatedAt = time.Now()
 	m.associations = append(m.associations, *a)
@@ -604,6 +675,89 @@ func TestConsolidationService_ProcessEpisodes(t *testing.T) {
 	}
 }
 
+func TestConsolidationService_ProcessEpisodes_ReplayBudget(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	memStore := newMockMemoryStoreForConsolidation()
+	memStore.agentIDs = []uuid.UUID{agentID}
+	episodeStore := newMockEpisodeStoreForConsolidation()
+
+	// Ten days of raw episodes; the store offers the four oldest and the four
+	// newest, the middle two wait for a later run.
+	importance := []float32{0.1, 0.2, 0.9, 0.3, 0.9, 0.9, 0.1, 0.2, 0.2, 0.5}
+	start := time.Now().Add(-10 * 24 * time.Hour)
+	for i, imp := range importance {
+		ep := domain.Episode{
+			ID:                  uuid.New(),
+			AgentID:             agentID,
+			TenantID:            tenantID,
+			RawContent:          fmt.Sprintf("episode %d", i),
+			ImportanceScore:     imp,
+			ConsolidationStatus: domain.ConsolidationRaw,
+			OccurredAt:          start.Add(time.Duration(i) * 24 * time.Hour),
+		}
+		if i == 8 {
+			ep.Outcome = domain.OutcomeFailure
+		}
+		episodeStore.episodes = append(episodeStore.episodes, ep)
+	}
+
+	svc := NewConsolidationService(memStore, episodeStore, newMockProcedureStoreForConsolidation(),
+		newMockSchemaStoreForConsolidation(), &mockAssocStoreForConsolidation{}, nil, nil, nil, zap.NewNop())
+	svc.SetReplayBudget(4)
+
+	result, err := svc.Consolidate(context.Background(), agentID, tenantID, ConsolidationScopeRecent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.EpisodesProcessed != 4 || result.EpisodesDeferred != 4 {
+		t.Fatalf("expected 4 processed and 4 deferred, got %d and %d", result.EpisodesProcessed, result.EpisodesDeferred)
+	}
+
+	// The most valuable two of each end are replayed: the important old
+	// episode, the failure and the most important recent one.
+	for i, ep := range episodeStore.episodes {
+		_, replayed := episodeStore.statusUpdate[ep.ID]
+		want := i == 2 || i == 3 || i == 8 || i == 9
+		if replayed != want {
+			t.Errorf("episode %d: replayed = %v, want %v", i, replayed, want)
+		}
+	}
+}
+
+func TestConsolidationService_ExtractSemanticBeliefs_FailedWriteLeavesEpisodeProcessed(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	memStore := newMockMemoryStoreForConsolidation()
+	episodeStore := newMockEpisodeStoreForConsolidation()
+	epID := uuid.New()
+	episodeStore.episodes = []domain.Episode{{
+		ID:                  epID,
+		AgentID:             agentID,
+		TenantID:            tenantID,
+		RawContent:          "User said they prefer dark mode",
+		ConsolidationStatus: domain.ConsolidationProcessed,
+		ImportanceScore:     0.8,
+		CreatedAt:           time.Now(),
+	}}
+	assocStore := &mockAssocStoreForConsolidation{createErr: errors.New("connection reset")}
+	llm := &mockLLMClient{extractResult: []domain.ExtractedMemory{
+		{Type: domain.MemoryTypePreference, Content: "User prefers dark mode", Confidence: 0.9},
+	}}
+
+	svc := NewConsolidationService(memStore, episodeStore, nil, nil, assocStore, nil, nil, llm, zap.NewNop())
+	result := svc.extractSemanticBeliefs(context.Background(), agentID, tenantID)
+
+	if result.extracted != 0 {
+		t.Errorf("expected no beliefs counted after failed write, got %d", result.extracted)
+	}
+	if status := episodeStore.statusUpdate[epID]; status == domain.ConsolidationAbstracted {
+		t.Error("episode marked abstracted despite failed write; it would never be retried")
+	}
+}
+
 func TestConsolidationService_ApplyForgetting(t *testing.T) {
 	logger := zap.NewNop()
 	agentID := uuid.New()
@@ -755,66 +909,7 @@ func TestConsolidationService_GetMemoryHealth(t *testing.T) {
 	}
 }
 
-func TestConsolidationService_ClusterMemories(t *testing.T) {
-	svc := &ConsolidationService{}
-
-	// Create memories with similar embeddings
-	agentID := uuid.New()
-	tenantID := uuid.New()
-
-	// Two memories in one cluster (similar embeddings)
-	mem1 := domain.Memory{
-		ID:        uuid.New(),
-		AgentID:   agentID,
-		TenantID:  tenantID,
-		Content:   "memory 1",
-		Embedding: []float32{1.0, 0.0, 0.0},
-	}
-	mem2 := domain.Memory{
-		ID:        uuid.New(),
-		AgentID:   agentID,
-		TenantID:  tenantID,
-		Content:   "memory 2",
-		Embedding: []float32{0.95, 0.1, 0.0}, // Very similar to mem1
-	}
-	// One memory in another cluster
-	mem3 := domain.Memory{
-		ID:        uuid.New(),
-		AgentID:   agentID,
-		TenantID:  tenantID,
-		Content:   "memory 3",
-		Embedding: []float32{0.0, 1.0, 0.0}, // Different direction
-	}
-
-	memories := []domain.Memory{mem1, mem2, mem3}
-	clusters := svc.clusterMemories(memories)
-
-	if len(clusters) != 2 {
-		t.Errorf("expected 2 clusters, got %d", len(clusters))
-	}
-
-	// First cluster should have mem1 and mem2
-	found1 := false
-	found2 := false
-	for _, cluster := range clusters {
-		if len(cluster.Memories) == 2 {
-			for _, m := range cluster.Memories {
-				if m.ID == mem1.ID {
-					found1 = true
-				}
-				if m.ID == mem2.ID {
-					found2 = true
-				}
-			}
-		}
-	}
-
-	if !found1 || !found2 {
-		t.Error("mem1 and mem2 should be in the same cluster")
-	}
-}
-
-func TestConsolidationService_StartStop(t *testing.T) {
+func TestConsolidationService_ScheduledStartStop(t *testing.T) {
 	logger := zap.NewNop()
 
 	memStore := newMockMemoryStoreForConsolidation()
@@ -837,12 +932,20 @@ func TestConsolidationService_StartStop(t *testing.T) {
 	// Set a very short interval for testing
 	svc.SetInterval(10 * time.Millisecond)
 
+	scheduler := NewScheduler(logger)
+	if err := scheduler.Register(svc.ScheduledTask()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
 	// Start and immediately stop - should not panic
-	svc.Start()
+	scheduler.Start()
 	time.Sleep(20 * time.Millisecond) // Let it tick once
-	svc.Stop()
+	scheduler.Stop()
 
 	// Should be able to stop without hanging
+	if st := scheduler.List()[0]; st.Name != "consolidation" || st.ErrorCount != 0 {
+		t.Errorf("unexpected status after ticking: %+v", st)
+	}
 }
 
 func (m *mockMemoryStoreForConsolidation) ListByAgentFiltered(ctx context.Context, agentID, tenantID uuid.UUID, f domain.MemoryFilter, limit, offset int) ([]domain.Memory, int, error) {
@@ -852,3 +955,339 @@ func (m *mockMemoryStoreForConsolidation) ListByAgentFiltered(ctx context.Contex
 func (m *mockMemoryStoreForConsolidation) BeliefsAsOf(ctx context.Context, agentID, tenantID uuid.UUID, at time.Time, limit int) ([]domain.BeliefAtTime, int, error) {
 	return nil, 0, nil
 }
+
+func TestConsolidationService_RollupSummaries(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	memStore := newMockMemoryStoreForConsolidation()
+	llm := newMockLLMClient()
+	llm.summarizeResult = "User wants notifications batched, muted at night, and sent by email"
+
+	// One tight topic of four beliefs plus an unrelated one.
+	var topic []uuid.UUID
+	for i := 0; i < SummaryMinMembers; i++ {
+		id := uuid.New()
+		topic = append(topic, id)
+		memStore.memories = append(memStore.memories, domain.Memory{
+			ID:         id,
+			AgentID:    agentID,
+			TenantID:   tenantID,
+			Type:       domain.MemoryTypePreference,
+			Content:    "notification preference",
+			Confidence: 0.8,
+			Embedding:  []float32{1, 0.02 * float32(i), 0},
+		})
+	}
+	memStore.memories = append(memStore.memories, domain.Memory{
+		ID:         uuid.New(),
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       domain.MemoryTypeFact,
+		Content:    "unrelated",
+		Confidence: 0.8,
+		Embedding:  []float32{0, 0, 1},
+	})
+
+	svc := NewConsolidationService(memStore, nil, nil, nil, nil, nil, &mockEmbeddingClient{}, llm, zap.NewNop())
+	ctx := context.Background()
+
+	result := svc.rollupSummaries(ctx, agentID, tenantID)
+	if result.created != 1 || result.refreshed != 0 {
+		t.Fatalf("expected one summary created, got %+v", result)
+	}
+
+	var summary *domain.Memory
+	for i := range memStore.memories {
+		if memStore.memories[i].Type == domain.MemoryTypeSummary {
+			summary = &memStore.memories[i]
+		}
+	}
+	if summary == nil {
+		t.Fatal("expected a summary memory to be stored")
+	}
+	if summary.Content != llm.summarizeResult {
+		t.Fatalf("expected LLM summary content, got %q", summary.Content)
+	}
+	members := domain.SummaryMemberIDs(*summary)
+	if len(members) != len(topic) {
+		t.Fatalf("expected summary to cover %d memories, got %d", len(topic), len(members))
+	}
+
+	// Unchanged membership: nothing to do.
+	result = svc.rollupSummaries(ctx, agentID, tenantID)
+	if result.created != 0 || result.refreshed != 0 {
+		t.Fatalf("expected no-op on unchanged topic, got %+v", result)
+	}
+
+	// A new belief joins the topic: the summary is replaced.
+	memStore.memories = append(memStore.memories, domain.Memory{
+		ID:         uuid.New(),
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       domain.MemoryTypePreference,
+		Content:    "another notification preference",
+		Confidence: 0.8,
+		Embedding:  []float32{1, 0.1, 0},
+	})
+	result = svc.rollupSummaries(ctx, agentID, tenantID)
+	if result.refreshed != 1 || result.created != 0 {
+		t.Fatalf("expected summary refresh, got %+v", result)
+	}
+	if len(memStore.archived) != 1 || memStore.archived[0] != summary.ID {
+		t.Fatalf("expected the stale summary to be archived, got %v", memStore.archived)
+	}
+}
+
+type mockConsolidationRunStore struct {
+	runs []domain.ConsolidationRun
+}
+
+func (m *mockConsolidationRunStore) Create(ctx context.Context, run *domain.ConsolidationRun) error {
+	run.ID = uuid.New()
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *mockConsolidationRunStore) ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit int) ([]domain.ConsolidationRun, error) {
+	return m.runs, nil
+}
+
+// meteredLLMClient reports token usage the way the real clients do.
+type meteredLLMClient struct {
+	*mockLLMClient
+}
+
+func (c *meteredLLMClient) ExtractEpisodeStructure(ctx context.Context, content string) (*domain.EpisodeExtraction, error) {
+	domain.RecordLLMUsage(ctx, "gpt-4o-mini", 1000, 200)
+	return c.mockLLMClient.ExtractEpisodeStructure(ctx, content)
+}
+
+func TestConsolidationService_Consolidate_RecordsUsageAndRun(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	memStore := newMockMemoryStoreForConsolidation()
+	memStore.agentIDs = []uuid.UUID{agentID}
+	episodeStore := newMockEpisodeStoreForConsolidation()
+	episodeStore.episodes = []domain.Episode{{
+		ID:                  uuid.New(),
+		AgentID:             agentID,
+		TenantID:            tenantID,
+		RawContent:          "User asked about dark mode",
+		ImportanceScore:     0.9,
+		ConsolidationStatus: domain.ConsolidationRaw,
+		CreatedAt:           time.Now(),
+	}}
+	runStore := &mockConsolidationRunStore{}
+
+	svc := NewConsolidationService(
+		memStore,
+		episodeStore,
+		newMockProcedureStoreForConsolidation(),
+		newMockSchemaStoreForConsolidation(),
+		&mockAssocStoreForConsolidation{},
+		nil,
+		nil,
+		&meteredLLMClient{newMockLLMClient()},
+		zap.NewNop(),
+	)
+	svc.SetRunStore(runStore)
+
+	result, err := svc.Consolidate(context.Background(), agentID, tenantID, ConsolidationScopeFull)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Usage.LLMCalls != 1 || result.Usage.InputTokens != 1000 || result.Usage.OutputTokens != 200 {
+		t.Errorf("unexpected usage %+v", result.Usage)
+	}
+	want := domain.EstimateCost("gpt-4o-mini", 1000, 200)
+	if math.Abs(result.Usage.EstimatedCostUSD-want) > 1e-12 || want == 0 {
+		t.Errorf("EstimatedCostUSD = %v, want %v", result.Usage.EstimatedCostUSD, want)
+	}
+
+	if len(runStore.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(runStore.runs))
+	}
+	run := runStore.runs[0]
+	if run.AgentID != agentID || run.TenantID != tenantID || run.Scope != string(ConsolidationScopeFull) {
+		t.Errorf("unexpected run header %+v", run)
+	}
+	if run.Usage != result.Usage || run.FinishedAt.Before(run.StartedAt) {
+		t.Errorf("run usage/timing mismatch: %+v", run)
+	}
+	var stored ConsolidationResult
+	if err := json.Unmarshal(run.Result, &stored); err != nil || stored.EpisodesProcessed != result.EpisodesProcessed {
+		t.Errorf("stored result does not round-trip: %v %+v", err, stored)
+	}
+}
+
+type mockConsolidationFailureStore struct {
+	failures map[uuid.UUID]*domain.ConsolidationFailure
+}
+
+func newMockConsolidationFailureStore() *mockConsolidationFailureStore {
+	return &mockConsolidationFailureStore{failures: make(map[uuid.UUID]*domain.ConsolidationFailure)}
+}
+
+func (m *mockConsolidationFailureStore) RecordFailure(ctx context.Context, f *domain.ConsolidationFailure, deadLetterAfter int) error {
+	stored, ok := m.failures[f.EpisodeID]
+	if !ok {
+		stored = &domain.ConsolidationFailure{EpisodeID: f.EpisodeID, TenantID: f.TenantID, AgentID: f.AgentID, FirstFailedAt: time.Now()}
+		m.failures[f.EpisodeID] = stored
+	}
+	stored.Stage, stored.LastError, stored.LastFailedAt = f.Stage, f.LastError, time.Now()
+	stored.FailureCount++
+	if stored.DeadLetteredAt == nil && stored.FailureCount >= deadLetterAfter {
+		now := time.Now()
+		stored.DeadLetteredAt = &now
+	}
+	*f = *stored
+	return nil
+}
+
+func (m *mockConsolidationFailureStore) ListDeadLettered(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]domain.ConsolidationFailure, int, error) {
+	var out []domain.ConsolidationFailure
+	for _, f := range m.failures {
+		if f.AgentID == agentID && f.TenantID == tenantID && f.DeadLetteredAt != nil {
+			out = append(out, *f)
+		}
+	}
+	return out, len(out), nil
+}
+
+func (m *mockConsolidationFailureStore) Requeue(ctx context.Context, episodeID, tenantID uuid.UUID) error {
+	f, ok := m.failures[episodeID]
+	if !ok || f.TenantID != tenantID || f.DeadLetteredAt == nil {
+		return store.ErrNotFound
+	}
+	delete(m.failures, episodeID)
+	return nil
+}
+
+func TestConsolidationService_ExtractSemanticBeliefs_DeadLettersRepeatedFailures(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	episodeStore := newMockEpisodeStoreForConsolidation()
+	epID := uuid.New()
+	episodeStore.episodes = []domain.Episode{{
+		ID:                  epID,
+		AgentID:             agentID,
+		TenantID:            tenantID,
+		RawContent:          "User said they prefer dark mode",
+		ConsolidationStatus: domain.ConsolidationProcessed,
+		ImportanceScore:     0.8,
+		CreatedAt:           time.Now(),
+	}}
+	assocStore := &mockAssocStoreForConsolidation{createErr: errors.New("connection reset")}
+	llm := &mockLLMClient{extractResult: []domain.ExtractedMemory{
+		{Type: domain.MemoryTypePreference, Content: "User prefers dark mode", Confidence: 0.9},
+	}}
+	failures := newMockConsolidationFailureStore()
+
+	svc := NewConsolidationService(newMockMemoryStoreForConsolidation(), episodeStore, nil, nil, assocStore, nil, nil, llm, zap.NewNop())
+	svc.SetFailureStore(failures)
+	ctx := context.Background()
+
+	for i := 1; i < MaxConsolidationFailures; i++ {
+		svc.extractSemanticBeliefs(ctx, agentID, tenantID)
+		if status := episodeStore.episodes[0].ConsolidationStatus; status != domain.ConsolidationProcessed {
+			t.Fatalf("after %d failures status = %s, want processed (still retried)", i, status)
+		}
+	}
+	svc.extractSemanticBeliefs(ctx, agentID, tenantID)
+	if status := episodeStore.episodes[0].ConsolidationStatus; status != domain.ConsolidationDeadLetter {
+		t.Fatalf("after %d failures status = %s, want dead_letter", MaxConsolidationFailures, status)
+	}
+
+	// Dead-lettered episodes are no longer attempted
+	svc.extractSemanticBeliefs(ctx, agentID, tenantID)
+	dead, total, err := svc.ListDeadLetters(ctx, agentID, tenantID, 50, 0)
+	if err != nil || total != 1 {
+		t.Fatalf("ListDeadLetters = %d, %v; want 1", total, err)
+	}
+	if dead[0].FailureCount != MaxConsolidationFailures || dead[0].Stage != FailureStageBeliefWrite || dead[0].LastError == "" {
+		t.Errorf("unexpected dead letter: %+v", dead[0])
+	}
+
+	if err := svc.RequeueDeadLetter(ctx, epID, uuid.New()); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("requeue from another tenant: err = %v, want ErrDeadLetterNotFound", err)
+	}
+	if err := svc.RequeueDeadLetter(ctx, epID, tenantID); err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+	if _, total, _ := svc.ListDeadLetters(ctx, agentID, tenantID, 50, 0); total != 0 {
+		t.Errorf("expected no dead letters after requeue, got %d", total)
+	}
+}
+
+type fakeRedundancyStore struct {
+	pairs     []domain.RedundantPair
+	threshold float32
+}
+
+func (f *fakeRedundancyStore) StreamRedundantPairs(ctx context.Context, agentID uuid.UUID, threshold float32, neighbors int, fn func(domain.RedundantPair) error) error {
+	f.threshold = threshold
+	for _, p := range f.pairs {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestConsolidationService_MergeRedundantPairs(t *testing.T) {
+	x := domain.RedundancyMember{ID: uuid.New(), Confidence: 0.5}
+	y := domain.RedundancyMember{ID: uuid.New(), Confidence: 0.7}
+	z := domain.RedundancyMember{ID: uuid.New(), Confidence: 0.6}
+	redundancy := &fakeRedundancyStore{pairs: []domain.RedundantPair{
+		{A: x, B: y, Similarity: 0.97},
+		{A: y, B: x, Similarity: 0.97}, // reported from both sides
+		{A: y, B: z, Similarity: 0.95},
+		{A: x, B: z, Similarity: 0.93},
+	}}
+
+	memStore := newMockMemoryStoreForConsolidation()
+	svc := NewConsolidationService(memStore, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetRedundancyStore(redundancy)
+
+	merged, err := svc.mergeRedundantPairs(context.Background(), uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged != 2 || redundancy.threshold != RedundancyThreshold {
+		t.Fatalf("expected 2 merges at the redundancy threshold, got %d at %v", merged, redundancy.threshold)
+	}
+	if len(memStore.archived) != 2 || memStore.archived[0] != x.ID || memStore.archived[1] != z.ID {
+		t.Fatalf("expected x then z archived, got %v", memStore.archived)
+	}
+	// y is reinforced twice; the second merge must start from its new confidence.
+	if got := memStore.updated[y.ID]; math.Abs(float64(got-0.74)) > 1e-6 {
+		t.Fatalf("expected y reinforced to 0.74, got %v", got)
+	}
+}
+
+func TestConsolidation_ReinforcesFromTheStoredConfidence(t *testing.T) {
+	memStore := newMockMemoryStoreForConsolidation()
+	existing := domain.Memory{ID: uuid.New(), Confidence: 0.5, ReinforcementCount: 1}
+	memStore.memories = append(memStore.memories, existing)
+	// A recall boost lands between the similarity search and the write.
+	memStore.updated[existing.ID] = 0.7
+
+	svc := NewConsolidationService(memStore, newMockEpisodeStoreForConsolidation(), nil, nil, nil, nil, nil, nil, zap.NewNop())
+	ep := &domain.Episode{ID: uuid.New()}
+	writes := []beliefWrite{{belief: domain.ExtractedMemory{Content: "likes tea"}, existing: &existing}}
+
+	_, reinforced, err := svc.writeEpisodeBeliefs(context.Background(), memStore, svc.episodeStore, nil, ep, uuid.New(), uuid.New(), writes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reinforced != 1 || writes[0].memoryID != existing.ID {
+		t.Fatalf("expected the existing belief reinforced, got %d", reinforced)
+	}
+	if got := memStore.updated[existing.ID]; math.Abs(float64(got-0.75)) > 1e-6 {
+		t.Errorf("expected the boost on top of the stored 0.7, got %v", got)
+	}
+}
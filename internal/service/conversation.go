@@ -54,7 +54,7 @@ func (s *ConversationService) Ingest(ctx context.Context, req *domain.Conversati
 		// Synchronous path: block until LLM extraction is complete.
 		stored, err := s.runExtraction(ctx, req, userMem.ID)
 		if err != nil {
-			s.logger.Warn("sync extraction failed, user-turns memory retained",
+			logFor(ctx, s.logger).Warn("sync extraction failed, user-turns memory retained",
 				zap.String("agent_id", req.AgentID.String()),
 				zap.Error(err),
 			)
@@ -77,7 +77,7 @@ func (s *ConversationService) Ingest(ctx context.Context, req *domain.Conversati
 		sessionID := req.SessionID
 
 		go func() {
-			bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			bgCtx, cancel := context.WithTimeout(domain.CopyTraceIDs(context.Background(), ctx), 5*time.Minute)
 			defer cancel()
 			asyncReq := &domain.ConversationIngestRequest{
 				AgentID:   agentID,
@@ -89,7 +89,7 @@ func (s *ConversationService) Ingest(ctx context.Context, req *domain.Conversati
 				SessionID: sessionID,
 			}
 			if _, err := s.runExtraction(bgCtx, asyncReq, userMemID); err != nil {
-				s.logger.Warn("async extraction failed",
+				logFor(ctx, s.logger).Warn("async extraction failed",
 					zap.String("agent_id", agentID.String()),
 					zap.Error(err),
 				)
@@ -114,7 +114,7 @@ func (s *ConversationService) runExtraction(ctx context.Context, req *domain.Con
 		}
 		m, err := s.storeExtractedFact(ctx, req, fact)
 		if err != nil {
-			s.logger.Warn("failed to store extracted fact",
+			logFor(ctx, s.logger).Warn("failed to store extracted fact",
 				zap.String("content", fact.Content[:min(50, len(fact.Content))]),
 				zap.Error(err),
 			)
@@ -125,14 +125,14 @@ func (s *ConversationService) runExtraction(ctx context.Context, req *domain.Con
 
 	if len(stored) > 0 {
 		if err := s.memorySvc.Delete(ctx, userMemID, req.TenantID); err != nil {
-			s.logger.Debug("could not archive user-turns memory",
+			logFor(ctx, s.logger).Debug("could not archive user-turns memory",
 				zap.String("memory_id", userMemID.String()),
 				zap.Error(err),
 			)
 		}
 	}
 
-	s.logger.Debug("extraction complete",
+	logFor(ctx, s.logger).Debug("extraction complete",
 		zap.String("agent_id", req.AgentID.String()),
 		zap.Int("facts", len(stored)),
 	)
@@ -181,6 +181,8 @@ func (s *ConversationService) storeExtractedFact(ctx context.Context, req *domai
 		Metadata:   metadata,
 		AnchorID:   req.AnchorID,
 		SessionID:  req.SessionID,
+		Subject:    domain.NormalizeSubject(fact.Subject),
+		Topic:      fact.Topic,
 	}
 
 	if _, err := s.memorySvc.Create(ctx, m); err != nil {
@@ -261,7 +263,7 @@ func (s *ConversationService) storeEnumerationTurns(ctx context.Context, req *do
 			SessionID:  req.SessionID,
 		}
 		if _, err := s.memorySvc.Create(ctx, m); err != nil {
-			s.logger.Warn("failed to store enumeration turn",
+			logFor(ctx, s.logger).Warn("failed to store enumeration turn",
 				zap.String("agent_id", req.AgentID.String()), zap.Error(err))
 			continue
 		}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ConversationEpisodes reads a conversation's episodes and records outcomes.
+type ConversationEpisodes interface {
+	GetByConversationID(ctx context.Context, conversationID uuid.UUID, tenantID uuid.UUID) ([]domain.Episode, error)
+	RecordOutcome(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, outcome domain.OutcomeType, description string) error
+}
+
+// FeedbackDetector finds and applies implicit feedback on memories used in a
+// conversation.
+type FeedbackDetector interface {
+	DetectAndApply(ctx context.Context, req DetectRequest) ([]domain.ImplicitFeedback, error)
+}
+
+// ConsolidationScheduler queues a consolidation pass for an agent.
+type ConsolidationScheduler interface {
+	Schedule(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.Job, error)
+}
+
+// ConversationCloseService runs the end-of-conversation work in one job:
+// implicit feedback on the memories activated during the conversation,
+// memory extraction, an outcome for the conversation's last episode, and a
+// consolidation pass for the agent.
+type ConversationCloseService struct {
+	episodes     ConversationEpisodes
+	activations  domain.ConversationActivationStore
+	memoryStore  domain.MemoryStore
+	feedback     FeedbackDetector
+	extractor    ConversationExtractor
+	consolidator ConsolidationScheduler
+	jobs         *JobPool
+	logger       *zap.Logger
+}
+
+func NewConversationCloseService(
+	episodes ConversationEpisodes,
+	activations domain.ConversationActivationStore,
+	memoryStore domain.MemoryStore,
+	feedback FeedbackDetector,
+	extractor ConversationExtractor,
+	consolidator ConsolidationScheduler,
+	jobs *JobPool,
+	logger *zap.Logger,
+) *ConversationCloseService {
+	return &ConversationCloseService{
+		episodes:     episodes,
+		activations:  activations,
+		memoryStore:  memoryStore,
+		feedback:     feedback,
+		extractor:    extractor,
+		consolidator: consolidator,
+		jobs:         jobs,
+		logger:       logger,
+	}
+}
+
+// CloseInput closes a conversation. Messages is the transcript; when empty it
+// is rebuilt from the conversation's episodes. Outcome, if set, is recorded
+// as given instead of being inferred.
+type CloseInput struct {
+	ConversationID     uuid.UUID
+	TenantID           uuid.UUID
+	Messages           []domain.Message
+	Outcome            *domain.OutcomeType
+	OutcomeDescription string
+}
+
+// CloseResult is the result of a close job. A step that fails is reported in
+// Errors and the remaining steps still run.
+type CloseResult struct {
+	FeedbackDetected   int             `json:"feedback_detected"`
+	Extracted          []ExtractResult `json:"extracted"`
+	Outcome            string          `json:"outcome,omitempty"`
+	OutcomeInferred    bool            `json:"outcome_inferred,omitempty"`
+	OutcomeEpisodeID   *uuid.UUID      `json:"outcome_episode_id,omitempty"`
+	ConsolidationJobID *uuid.UUID      `json:"consolidation_job_id,omitempty"`
+	Errors             []string        `json:"errors,omitempty"`
+}
+
+// Close queues the close job for a conversation. The conversation must have
+// at least one episode, which also identifies its agent.
+func (s *ConversationCloseService) Close(ctx context.Context, input CloseInput) (*domain.Job, error) {
+	episodes, err := s.episodes.GetByConversationID(ctx, input.ConversationID, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(episodes) == 0 {
+		return nil, ErrConversationNotFound
+	}
+	agentID := episodes[len(episodes)-1].AgentID
+	return s.jobs.Submit(ctx, input.TenantID, agentID, domain.JobKindConversationClose, ExtractionJobTimeout, func(ctx context.Context) (any, error) {
+		return s.close(ctx, input, agentID, episodes), nil
+	})
+}
+
+func (s *ConversationCloseService) close(ctx context.Context, input CloseInput, agentID uuid.UUID, episodes []domain.Episode) *CloseResult {
+	result := &CloseResult{Extracted: []ExtractResult{}}
+	fail := func(step string, err error) {
+		logFor(ctx, s.logger).Warn("conversation close step failed",
+			zap.String("conversation_id", input.ConversationID.String()),
+			zap.String("step", step),
+			zap.Error(err))
+		result.Errors = append(result.Errors, step+": "+err.Error())
+	}
+
+	conversation := input.Messages
+	if len(conversation) == 0 {
+		conversation = episodeTranscript(episodes)
+	}
+
+	feedbacks, err := s.detectFeedback(ctx, input, agentID, conversation)
+	if err != nil {
+		fail("implicit_feedback", err)
+	}
+	result.FeedbackDetected = len(feedbacks)
+
+	if extracted, err := s.extractor.Extract(ctx, agentID, input.TenantID, conversation, true); err != nil {
+		fail("extraction", err)
+	} else if extracted != nil {
+		result.Extracted = extracted
+	}
+
+	// The outcome goes on the last episode only: attribution already covers
+	// every memory activated in the conversation, so recording it on each
+	// episode would count it once per episode.
+	last := episodes[len(episodes)-1]
+	outcome, description := input.Outcome, input.OutcomeDescription
+	if outcome == nil {
+		if inferred, ok := inferOutcome(feedbacks); ok {
+			outcome, description = &inferred, "inferred from implicit feedback at conversation close"
+			result.OutcomeInferred = true
+		}
+	}
+	if outcome != nil && (input.Outcome != nil || last.Outcome == "" || last.Outcome == domain.OutcomeUnknown) {
+		if err := s.episodes.RecordOutcome(ctx, last.ID, input.TenantID, *outcome, description); err != nil {
+			fail("outcome", err)
+		} else {
+			result.Outcome = string(*outcome)
+			result.OutcomeEpisodeID = &last.ID
+		}
+	}
+
+	if job, err := s.consolidator.Schedule(ctx, agentID, input.TenantID); err != nil {
+		fail("consolidation", err)
+	} else {
+		result.ConsolidationJobID = &job.ID
+	}
+	return result
+}
+
+// detectFeedback runs implicit feedback detection over the semantic memories
+// activated during the conversation.
+func (s *ConversationCloseService) detectFeedback(ctx context.Context, input CloseInput, agentID uuid.UUID, conversation []domain.Message) ([]domain.ImplicitFeedback, error) {
+	acts, err := s.activations.ListByConversation(ctx, input.ConversationID, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	var memories []domain.Memory
+	for _, a := range acts {
+		if a.MemoryType != domain.ActivatedMemoryTypeSemantic {
+			continue
+		}
+		m, err := s.memoryStore.GetByID(ctx, a.MemoryID, input.TenantID)
+		if err != nil {
+			continue // deleted since it was activated
+		}
+		memories = append(memories, *m)
+	}
+	return s.feedback.DetectAndApply(ctx, DetectRequest{
+		AgentID:      agentID,
+		TenantID:     input.TenantID,
+		Memories:     memories,
+		Conversation: conversation,
+	})
+}
+
+// inferOutcome reads a conversation's outcome from its implicit feedback:
+// success when helpful signals outnumber unhelpful ones, failure when the
+// reverse holds, and nothing when they tie.
+func inferOutcome(feedbacks []domain.ImplicitFeedback) (domain.OutcomeType, bool) {
+	score := 0
+	for _, fb := range feedbacks {
+		switch fb.SignalType {
+		case domain.FeedbackTypeHelpful, domain.FeedbackTypeUsed:
+			score++
+		case domain.FeedbackTypeUnhelpful, domain.FeedbackTypeContradicted, domain.FeedbackTypeOutdated:
+			score--
+		}
+	}
+	switch {
+	case score > 0:
+		return domain.OutcomeSuccess, true
+	case score < 0:
+		return domain.OutcomeFailure, true
+	}
+	return "", false
+}
+
+// episodeTranscript rebuilds a conversation from its episodes. Lines starting
+// with "user: " or "assistant: " (as the chat proxy records them) begin a new
+// message; any other text continues the current one, or is a user message.
+func episodeTranscript(episodes []domain.Episode) []domain.Message {
+	var msgs []domain.Message
+	for _, ep := range episodes {
+		current := -1
+		for _, line := range strings.Split(ep.RawContent, "\n") {
+			role, content := "", line
+			for _, r := range []string{"user", "assistant"} {
+				if rest, ok := strings.CutPrefix(line, r+": "); ok {
+					role, content = r, rest
+				}
+			}
+			if role == "" && current >= 0 {
+				msgs[current].Content += "\n" + line
+				continue
+			}
+			if role == "" {
+				role = "user"
+			}
+			msgs = append(msgs, domain.Message{Role: role, Content: content})
+			current = len(msgs) - 1
+		}
+	}
+	return msgs
+}
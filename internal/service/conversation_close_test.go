@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type fakeConversationEpisodes struct {
+	episodes []domain.Episode
+	outcomes map[uuid.UUID]domain.OutcomeType
+}
+
+func (f *fakeConversationEpisodes) GetByConversationID(ctx context.Context, conversationID uuid.UUID, tenantID uuid.UUID) ([]domain.Episode, error) {
+	var out []domain.Episode
+	for _, ep := range f.episodes {
+		if ep.ConversationID != nil && *ep.ConversationID == conversationID && ep.TenantID == tenantID {
+			out = append(out, ep)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeConversationEpisodes) RecordOutcome(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, outcome domain.OutcomeType, description string) error {
+	f.outcomes[id] = outcome
+	return nil
+}
+
+type fakeFeedbackDetector struct {
+	requests []DetectRequest
+	signals  []domain.FeedbackType
+}
+
+func (f *fakeFeedbackDetector) DetectAndApply(ctx context.Context, req DetectRequest) ([]domain.ImplicitFeedback, error) {
+	f.requests = append(f.requests, req)
+	var out []domain.ImplicitFeedback
+	for i, s := range f.signals {
+		out = append(out, domain.ImplicitFeedback{MemoryID: req.Memories[i%len(req.Memories)].ID, SignalType: s})
+	}
+	return out, nil
+}
+
+type fakeConsolidationScheduler struct {
+	err    error
+	agents []uuid.UUID
+}
+
+func (f *fakeConsolidationScheduler) Schedule(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.Job, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.agents = append(f.agents, agentID)
+	return &domain.Job{ID: uuid.New()}, nil
+}
+
+type closeFixture struct {
+	svc            *ConversationCloseService
+	episodes       *fakeConversationEpisodes
+	feedback       *fakeFeedbackDetector
+	capture        *fakeChatCapture
+	consolidator   *fakeConsolidationScheduler
+	tenantID       uuid.UUID
+	agentID        uuid.UUID
+	conversationID uuid.UUID
+	memory         *domain.Memory
+}
+
+func newCloseFixture(t *testing.T) *closeFixture {
+	t.Helper()
+	f := &closeFixture{
+		feedback:       &fakeFeedbackDetector{},
+		capture:        &fakeChatCapture{},
+		consolidator:   &fakeConsolidationScheduler{},
+		tenantID:       uuid.New(),
+		agentID:        uuid.New(),
+		conversationID: uuid.New(),
+	}
+	f.episodes = &fakeConversationEpisodes{
+		outcomes: make(map[uuid.UUID]domain.OutcomeType),
+		episodes: []domain.Episode{
+			{ID: uuid.New(), TenantID: f.tenantID, AgentID: f.agentID, ConversationID: &f.conversationID, RawContent: "user: I moved to Lisbon\nassistant: Noted!"},
+			{ID: uuid.New(), TenantID: f.tenantID, AgentID: f.agentID, ConversationID: &f.conversationID, RawContent: "Book a table for two"},
+		},
+	}
+	memories := newMockMemoryStore()
+	f.memory = &domain.Memory{TenantID: f.tenantID, AgentID: f.agentID, Content: "User lives in Porto"}
+	_ = memories.Create(context.Background(), f.memory)
+
+	acts := newMockConversationActivationStore()
+	_ = acts.Record(context.Background(), []domain.ConversationActivation{
+		{TenantID: f.tenantID, AgentID: f.agentID, ConversationID: f.conversationID, MemoryType: domain.ActivatedMemoryTypeSemantic, MemoryID: f.memory.ID},
+		{TenantID: f.tenantID, AgentID: f.agentID, ConversationID: f.conversationID, MemoryType: domain.ActivatedMemoryTypeProcedural, MemoryID: uuid.New()},
+	})
+
+	jobs := NewJobPool(1, 4, testLogger())
+	jobs.Start()
+	t.Cleanup(jobs.Stop)
+	f.svc = NewConversationCloseService(f.episodes, acts, memories, f.feedback, f.capture, f.consolidator, jobs, testLogger())
+	return f
+}
+
+func (f *closeFixture) close(t *testing.T, input CloseInput) *CloseResult {
+	t.Helper()
+	input.ConversationID, input.TenantID = f.conversationID, f.tenantID
+	job, err := f.svc.Close(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if job.Kind != domain.JobKindConversationClose || job.AgentID != f.agentID {
+		t.Errorf("job = %+v", job)
+	}
+	done := waitJob(t, f.svc.jobs, job.ID)
+	if done.Status != domain.JobSucceeded {
+		t.Fatalf("close job %s: %s", done.Status, done.Error)
+	}
+	return done.Result.(*CloseResult)
+}
+
+func TestConversationClose_RunsEveryStep(t *testing.T) {
+	f := newCloseFixture(t)
+	f.feedback.signals = []domain.FeedbackType{domain.FeedbackTypeContradicted}
+
+	result := f.close(t, CloseInput{})
+
+	want := []domain.Message{
+		{Role: "user", Content: "I moved to Lisbon"},
+		{Role: "assistant", Content: "Noted!"},
+		{Role: "user", Content: "Book a table for two"},
+	}
+	if len(f.feedback.requests) != 1 {
+		t.Fatalf("feedback detection ran %d times", len(f.feedback.requests))
+	}
+	req := f.feedback.requests[0]
+	if !reflect.DeepEqual(req.Conversation, want) {
+		t.Errorf("conversation = %+v, want the episode transcript", req.Conversation)
+	}
+	if len(req.Memories) != 1 || req.Memories[0].ID != f.memory.ID {
+		t.Errorf("feedback memories = %+v, want only the activated semantic memory", req.Memories)
+	}
+	if !reflect.DeepEqual(f.capture.conversation, want) {
+		t.Errorf("extracted conversation = %+v", f.capture.conversation)
+	}
+
+	last := f.episodes.episodes[1].ID
+	if result.FeedbackDetected != 1 || result.Outcome != string(domain.OutcomeFailure) || !result.OutcomeInferred || *result.OutcomeEpisodeID != last {
+		t.Errorf("result = %+v, want a failure inferred on the last episode", result)
+	}
+	if len(f.episodes.outcomes) != 1 || f.episodes.outcomes[last] != domain.OutcomeFailure {
+		t.Errorf("outcomes = %v", f.episodes.outcomes)
+	}
+	if len(f.consolidator.agents) != 1 || f.consolidator.agents[0] != f.agentID || result.ConsolidationJobID == nil {
+		t.Errorf("consolidation not scheduled: %+v", result)
+	}
+	if len(result.Extracted) != 1 || len(result.Errors) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestConversationClose_ExplicitOutcomeAndFailedStep(t *testing.T) {
+	f := newCloseFixture(t)
+	f.episodes.episodes[1].Outcome = domain.OutcomeSuccess
+	f.feedback.signals = []domain.FeedbackType{domain.FeedbackTypeHelpful}
+	f.consolidator.err = ErrJobQueueFull
+	failure := domain.OutcomeFailure
+	messages := []domain.Message{{Role: "user", Content: "That was wrong"}}
+
+	result := f.close(t, CloseInput{Messages: messages, Outcome: &failure})
+
+	if !reflect.DeepEqual(f.feedback.requests[0].Conversation, messages) {
+		t.Errorf("conversation = %+v, want the given messages", f.feedback.requests[0].Conversation)
+	}
+	if result.Outcome != string(domain.OutcomeFailure) || result.OutcomeInferred {
+		t.Errorf("result = %+v, want the given outcome", result)
+	}
+	if len(result.Errors) != 1 || result.ConsolidationJobID != nil {
+		t.Errorf("errors = %v, want the consolidation failure only", result.Errors)
+	}
+}
+
+func TestConversationClose_KeepsRecordedOutcome(t *testing.T) {
+	f := newCloseFixture(t)
+	f.episodes.episodes[1].Outcome = domain.OutcomeSuccess
+	f.feedback.signals = []domain.FeedbackType{domain.FeedbackTypeOutdated}
+
+	result := f.close(t, CloseInput{})
+
+	if result.Outcome != "" || len(f.episodes.outcomes) != 0 {
+		t.Errorf("inferred outcome overwrote a recorded one: %+v", result)
+	}
+}
+
+func TestConversationClose_NotFound(t *testing.T) {
+	f := newCloseFixture(t)
+	_, err := f.svc.Close(context.Background(), CloseInput{ConversationID: uuid.New(), TenantID: f.tenantID})
+	if !errors.Is(err, ErrConversationNotFound) {
+		t.Errorf("err = %v, want ErrConversationNotFound", err)
+	}
+}
+
+func TestInferOutcome(t *testing.T) {
+	tests := []struct {
+		signals []domain.FeedbackType
+		want    domain.OutcomeType
+		ok      bool
+	}{
+		{nil, "", false},
+		{[]domain.FeedbackType{domain.FeedbackTypeUsed, domain.FeedbackTypeHelpful, domain.FeedbackTypeOutdated}, domain.OutcomeSuccess, true},
+		{[]domain.FeedbackType{domain.FeedbackTypeHelpful, domain.FeedbackTypeContradicted, domain.FeedbackTypeUnhelpful}, domain.OutcomeFailure, true},
+		{[]domain.FeedbackType{domain.FeedbackTypeIgnored, domain.FeedbackTypeUsed, domain.FeedbackTypeOutdated}, "", false},
+	}
+	for _, tt := range tests {
+		var feedbacks []domain.ImplicitFeedback
+		for _, s := range tt.signals {
+			feedbacks = append(feedbacks, domain.ImplicitFeedback{SignalType: s})
+		}
+		got, ok := inferOutcome(feedbacks)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("inferOutcome(%v) = %q, %v; want %q, %v", tt.signals, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// MinWindowMatchLength is the shortest memory content (after normalization)
+// that is suppressed for appearing in the conversation window. Shorter
+// contents like "yes" would match almost any conversation.
+const MinWindowMatchLength = 12
+
+// conversationWindow is the caller's current message window, normalized so
+// that memories it already repeats verbatim can be left out of recall. A
+// memory the agent can already see costs tokens and invites it to repeat
+// itself.
+type conversationWindow []string
+
+func newConversationWindow(messages []domain.Message) conversationWindow {
+	var w conversationWindow
+	for _, m := range messages {
+		if text := normalizeWindowText(m.Content); text != "" {
+			w = append(w, text)
+		}
+	}
+	return w
+}
+
+// contains reports whether content appears verbatim, ignoring case and
+// whitespace, within a single message of the window.
+func (w conversationWindow) contains(content string) bool {
+	if len(w) == 0 {
+		return false
+	}
+	needle := normalizeWindowText(content)
+	if len(needle) < MinWindowMatchLength {
+		return false
+	}
+	for _, text := range w {
+		if strings.Contains(text, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeWindowText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// suppressInWindow drops recalled memories the conversation already contains.
+func suppressInWindow(memories []domain.MemoryWithScore, messages []domain.Message) []domain.MemoryWithScore {
+	w := newConversationWindow(messages)
+	if len(w) == 0 {
+		return memories
+	}
+	out := memories[:0]
+	for _, m := range memories {
+		if !w.contains(m.Content) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+func TestConversationWindow_Contains(t *testing.T) {
+	w := newConversationWindow([]domain.Message{
+		{Role: "user", Content: "My flight is   on Friday.\nI prefer an aisle seat."},
+		{Role: "assistant", Content: "Noted, booking the 9am departure"},
+	})
+
+	cases := []struct {
+		content string
+		want    bool
+	}{
+		{"I prefer an aisle seat", true},
+		{"my flight is on friday. i prefer", true},
+		{"booking the 9AM departure", true},
+		{"I prefer a window seat", false},
+		{"Friday.", false},                       // too short to count as a repeat
+		{"on Friday. Noted, booking the", false}, // spans two messages
+	}
+	for _, c := range cases {
+		if got := w.contains(c.content); got != c.want {
+			t.Errorf("contains(%q) = %v, want %v", c.content, got, c.want)
+		}
+	}
+
+	if newConversationWindow(nil).contains("I prefer an aisle seat") {
+		t.Error("an empty window contains nothing")
+	}
+}
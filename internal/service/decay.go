@@ -2,8 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"math"
-	"sync"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -66,7 +66,9 @@ type DecayService struct {
 	episodeStore     domain.EpisodeStore
 	mutationLogStore domain.MutationLogStore
 	settings         domain.TenantSettingsStore // optional; nil → service defaults
+	agents           domain.AgentRegistry
 	uow              *store.UnitOfWork
+	hooks            *Hooks
 	logger           *zap.Logger
 
 	// Configurable parameters
@@ -75,11 +77,8 @@ type DecayService struct {
 	SimilarityRadius  float64
 	CompetitionWeight float64
 
-	// Background worker
-	interval   time.Duration
-	stopCh     chan struct{}
-	cancelRuns context.CancelFunc
-	wg         sync.WaitGroup
+	// Background worker interval
+	interval time.Duration
 }
 
 // NewDecayService creates a new decay service
@@ -93,7 +92,6 @@ func NewDecayService(ms domain.MemoryStore, es domain.EpisodeStore, logger *zap.
 		SimilarityRadius:  CompetitorSimilarityThreshold,
 		CompetitionWeight: CompetitionWeight,
 		interval:          time.Hour,
-		stopCh:            make(chan struct{}),
 	}
 }
 
@@ -106,6 +104,11 @@ func (s *DecayService) SetMutationLogStore(mls domain.MutationLogStore) {
 	s.mutationLogStore = mls
 }
 
+// SetHooks reports memories archived by decay to in-process callbacks.
+func (s *DecayService) SetHooks(h *Hooks) {
+	s.hooks = h
+}
+
 // SetSettingsStore wires per-tenant engine tuning. When set, BatchDecay uses the
 // tenant's configured decay rate / floor / archive threshold / competition
 // weight instead of the service defaults.
@@ -195,22 +198,30 @@ func (s *DecayService) applyDecayWrite(ctx context.Context, mem *domain.Memory,
 	}
 
 	if s.uow != nil {
-		return s.uow.Do(ctx, func(st *store.TxStores) error {
+		if err := s.uow.Do(ctx, func(st *store.TxStores) error {
 			if err := stateChange(st.Memory); err != nil {
 				return err
 			}
 			return st.MutationLog.Create(ctx, mutation)
-		})
+		}); err != nil {
+			return err
+		}
+	} else {
+		if err := stateChange(s.memoryStore); err != nil {
+			return err
+		}
+		if s.mutationLogStore != nil {
+			if err := s.mutationLogStore.Create(ctx, mutation); err != nil {
+				logFor(ctx, s.logger).Debug("failed to log decay mutation",
+					zap.String("memory_id", mem.ID.String()), zap.Error(err))
+			}
+		}
 	}
 
-	if err := stateChange(s.memoryStore); err != nil {
-		return err
-	}
-	if s.mutationLogStore != nil {
-		if err := s.mutationLogStore.Create(ctx, mutation); err != nil {
-			s.logger.Debug("failed to log decay mutation",
-				zap.String("memory_id", mem.ID.String()), zap.Error(err))
-		}
+	if archived {
+		e := memoryEvent(mem, mutation.Reason)
+		e.Memory.Confidence = dr.NewConfidence
+		s.hooks.memoryArchived(ctx, e)
 	}
 	return nil
 }
@@ -222,53 +233,29 @@ type domainMemoryWriter interface {
 	ApplyConfidenceDelta(ctx context.Context, id uuid.UUID, delta float32) error
 }
 
-// Start begins the background decay worker
-func (s *DecayService) Start() {
-	baseCtx, cancel := context.WithCancel(context.Background())
-	s.cancelRuns = cancel
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		ticker := time.NewTicker(s.interval)
-		defer ticker.Stop()
-
-		s.logger.Info("decay worker started", zap.Duration("interval", s.interval))
-
-		for {
-			select {
-			case <-ticker.C:
-				ctx, tickCancel := context.WithTimeout(baseCtx, 10*time.Minute)
-				guardPanic(s.logger, "decay tick", func() { s.runDecayAllAgents(ctx) })
-				tickCancel()
-			case <-s.stopCh:
-				s.logger.Info("decay worker stopped")
-				return
-			}
-		}
-	}()
+// SetAgentRegistry makes the decay worker visit agents with live memories or
+// episodes, found from the agents table. Without it only agents with live
+// memories are decayed.
+func (s *DecayService) SetAgentRegistry(r domain.AgentRegistry) {
+	s.agents = r
 }
 
-// Stop halts the background decay worker, cancelling any in-flight pass so
-// shutdown is not held for the remainder of a long tick.
-func (s *DecayService) Stop() {
-	if s.cancelRuns != nil {
-		s.cancelRuns()
-	}
-	close(s.stopCh)
-	s.wg.Wait()
+// ScheduledTask returns the decay worker's periodic pass for the Scheduler.
+func (s *DecayService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "decay", Interval: s.interval, Timeout: 10 * time.Minute, Run: s.runDecayAllAgents}
 }
 
 // runDecayAllAgents runs decay for all agents
-func (s *DecayService) runDecayAllAgents(ctx context.Context) {
-	agentIDs, err := s.memoryStore.ListDistinctAgentIDs(ctx)
+func (s *DecayService) runDecayAllAgents(ctx context.Context) error {
+	agents, err := agentsWithWork(ctx, s.agents, domain.AgentWorkFilter{LiveMemories: true, LiveEpisodes: true}, s.memoryStore.ListDistinctAgentIDs)
 	if err != nil {
-		s.logger.Error("failed to list agents for decay", zap.Error(err))
-		return
+		return fmt.Errorf("list agents for decay: %w", err)
 	}
 
-	for _, agentID := range agentIDs {
+	for _, agent := range agents {
+		agentID := agent.ID
 		if ctx.Err() != nil {
-			return
+			return ctx.Err()
 		}
 		var result *BatchDecayResult
 		var err error
@@ -276,7 +263,7 @@ func (s *DecayService) runDecayAllAgents(ctx context.Context) {
 			result, err = s.BatchDecay(ctx, agentID)
 		})
 		if err != nil {
-			s.logger.Error("decay failed for agent",
+			logFor(ctx, s.logger).Error("decay failed for agent",
 				zap.String("agent_id", agentID.String()),
 				zap.Error(err))
 			continue
@@ -286,7 +273,7 @@ func (s *DecayService) runDecayAllAgents(ctx context.Context) {
 		}
 
 		if result.Decayed > 0 || result.Archived > 0 {
-			s.logger.Info("decay complete",
+			logFor(ctx, s.logger).Info("decay complete",
 				zap.String("agent_id", agentID.String()),
 				zap.Int("processed", result.Processed),
 				zap.Int("decayed", result.Decayed),
@@ -294,6 +281,7 @@ func (s *DecayService) runDecayAllAgents(ctx context.Context) {
 				zap.Int("tier_transitions", len(result.TierTransitions)))
 		}
 	}
+	return nil
 }
 
 // ApplyDecay applies decay to a single memory using the service defaults.
@@ -312,9 +300,9 @@ func (s *DecayService) applyDecayEff(ctx context.Context, memory *domain.Memory,
 	// Calculate time since last access
 	var hoursSinceAccess float64
 	if memory.LastAccessedAt != nil {
-		hoursSinceAccess = time.Since(*memory.LastAccessedAt).Hours()
+		hoursSinceAccess = timeNow().Sub(*memory.LastAccessedAt).Hours()
 	} else {
-		hoursSinceAccess = time.Since(memory.CreatedAt).Hours()
+		hoursSinceAccess = timeNow().Sub(memory.CreatedAt).Hours()
 	}
 	result.HoursSinceAccess = hoursSinceAccess
 
@@ -333,34 +321,72 @@ func (s *DecayService) applyDecayEff(ctx context.Context, memory *domain.Memory,
 	effectiveDecay := eff.baseRate * (1 + competitionFactor)
 	result.EffectiveDecay = effectiveDecay
 
-	// Distance-to-floor decay: conf_new = floor + (conf - floor) × exp(-λ_eff × t)
-	distanceToFloor := float64(memory.Confidence) - eff.floor
-	decayFactor := math.Exp(-effectiveDecay * hoursSinceAccess)
-	newConfidence := eff.floor + distanceToFloor*decayFactor
+	result.NewConfidence = float32(projectConfidence(float64(memory.Confidence), eff.floor, effectiveDecay, memory.ReinforcementCount, hoursSinceAccess))
 
-	// Apply reinforcement bonus (well-reinforced memories resist decay)
-	if memory.ReinforcementCount > 0 {
+	if result.NewConfidence < float32(eff.archiveThreshold) {
+		result.WasArchived = true
+	}
 
-		reinforcementBonus := 1.0 + 0.15*math.Log(float64(memory.ReinforcementCount+1))
+	return result
+}
 
-		resistanceFactor := 1.0 - 1.0/reinforcementBonus
-		newConfidence = newConfidence + (float64(memory.Confidence)-newConfidence)*resistanceFactor
+// projectConfidence applies the decay curve to a confidence over the given
+// number of hours.
+func projectConfidence(confidence, floor, rate float64, reinforcementCount int, hours float64) float64 {
+	// Distance-to-floor decay: conf_new = floor + (conf - floor) × exp(-λ_eff × t)
+	distanceToFloor := confidence - floor
+	decayFactor := math.Exp(-rate * hours)
+	newConfidence := floor + distanceToFloor*decayFactor
+
+	// Apply reinforcement bonus (well-reinforced memories resist decay)
+	if reinforcementCount > 0 {
+		resistanceFactor := reinforcementResistance(reinforcementCount)
+		newConfidence = newConfidence + (confidence-newConfidence)*resistanceFactor
 	}
 
-	if newConfidence < eff.floor {
-		newConfidence = eff.floor
+	if newConfidence < floor {
+		newConfidence = floor
 	}
-	if newConfidence > float64(memory.Confidence) {
-		newConfidence = float64(memory.Confidence)
+	if newConfidence > confidence {
+		newConfidence = confidence
 	}
+	return newConfidence
+}
 
-	result.NewConfidence = float32(newConfidence)
-
-	if result.NewConfidence < float32(eff.archiveThreshold) {
-		result.WasArchived = true
+// simulateDecay replays the decay worker tick by tick: each tick re-applies the
+// decay curve to the current confidence using the total hours since last
+// access, skipping changes too small to be written, exactly as BatchDecay
+// does. It returns the confidence at the end of the horizon and, if the
+// confidence drops below threshold first, how many hours from now that
+// happens.
+func simulateDecay(confidence, floor, rate float64, reinforcementCount int, hoursSinceAccess, tickHours, horizonHours, threshold float64) (projected float64, hoursUntil float64, reached bool) {
+	if tickHours <= 0 {
+		tickHours = 1
+	}
+	projected = confidence
+	for elapsed := tickHours; elapsed <= horizonHours; elapsed += tickHours {
+		t := hoursSinceAccess + elapsed
+		if t < MinHoursForDecay {
+			continue
+		}
+		next := projectConfidence(projected, floor, rate, reinforcementCount, t)
+		if math.Abs(next-projected) < 0.001 {
+			if next > floor+0.001 {
+				continue
+			}
+			break // settled at the floor
+		}
+		projected = next
+		if projected < threshold {
+			return projected, elapsed, true
+		}
 	}
+	return projected, 0, false
+}
 
-	return result
+func reinforcementResistance(reinforcementCount int) float64 {
+	reinforcementBonus := 1.0 + 0.15*math.Log(float64(reinforcementCount+1))
+	return 1.0 - 1.0/reinforcementBonus
 }
 
 // findCompetitors finds memories that compete for the same "slot"
@@ -437,17 +463,18 @@ func (s *DecayService) BatchDecay(ctx context.Context, agentID uuid.UUID) (*Batc
 		return nil, err
 	}
 
-	if len(memories) == 0 {
-		return result, nil
-	}
-
+	// An agent with only episodes still has them decayed below, under the
+	// service defaults since there is no memory to take the tenant from.
 	result.Processed = len(memories)
-	eff := s.effFor(ctx, memories[0].TenantID)
+	eff := s.defaultEff()
+	if len(memories) > 0 {
+		eff = s.effFor(ctx, memories[0].TenantID)
+	}
 
 	for i := range memories {
 		mem := &memories[i]
 
-		if mem.LastAccessedAt == nil && time.Since(mem.CreatedAt).Hours() < MinHoursForDecay {
+		if mem.LastAccessedAt == nil && timeNow().Sub(mem.CreatedAt).Hours() < MinHoursForDecay {
 			continue
 		}
 
@@ -466,12 +493,13 @@ func (s *DecayService) BatchDecay(ctx context.Context, agentID uuid.UUID) (*Batc
 				FromTier:   oldTier,
 				ToTier:     newTier,
 				Reason:     "decay",
-				OccurredAt: time.Now(),
+				Confidence: decayResult.NewConfidence,
+				OccurredAt: timeNow(),
 			})
 		}
 
 		if err := s.applyDecayWrite(ctx, mem, decayResult, decayResult.WasArchived); err != nil {
-			s.logger.Debug("failed to apply decay",
+			logFor(ctx, s.logger).Debug("failed to apply decay",
 				zap.String("memory_id", mem.ID.String()),
 				zap.Error(err))
 			result.Errors++
@@ -487,18 +515,21 @@ func (s *DecayService) BatchDecay(ctx context.Context, agentID uuid.UUID) (*Batc
 	if s.episodeStore != nil {
 		decayed, err := s.episodeStore.ApplyDecay(ctx, agentID)
 		if err != nil {
-			s.logger.Debug("failed to apply episode decay", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to apply episode decay", zap.Error(err))
 		} else {
 			result.EpisodesDecayed = int(decayed)
 		}
 
 		weakEpisodes, err := s.episodeStore.GetWeakMemories(ctx, agentID, float32(eff.archiveThreshold))
 		if err != nil {
-			s.logger.Debug("failed to get weak episodes", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to get weak episodes", zap.Error(err))
 		} else {
 			for _, ep := range weakEpisodes {
+				if ep.IsFlashbulb() {
+					continue
+				}
 				if err := s.episodeStore.Archive(ctx, ep.ID); err != nil {
-					s.logger.Debug("failed to archive episode", zap.Error(err))
+					logFor(ctx, s.logger).Debug("failed to archive episode", zap.Error(err))
 				} else {
 					result.EpisodesArchived++
 				}
@@ -531,7 +562,7 @@ func (s *DecayService) BatchDecayWithDetails(ctx context.Context, agentID uuid.U
 	for i := range memories {
 		mem := &memories[i]
 
-		if mem.LastAccessedAt == nil && time.Since(mem.CreatedAt).Hours() < MinHoursForDecay {
+		if mem.LastAccessedAt == nil && timeNow().Sub(mem.CreatedAt).Hours() < MinHoursForDecay {
 			continue
 		}
 
@@ -551,7 +582,8 @@ func (s *DecayService) BatchDecayWithDetails(ctx context.Context, agentID uuid.U
 				FromTier:   oldTier,
 				ToTier:     newTier,
 				Reason:     "decay",
-				OccurredAt: time.Now(),
+				Confidence: decayResult.NewConfidence,
+				OccurredAt: timeNow(),
 			})
 		}
 
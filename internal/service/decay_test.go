@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/testharness"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -419,3 +420,154 @@ func TestDecay_NoEmbeddingNoCompetition(t *testing.T) {
 		t.Errorf("Expected 0 competitors for memory without embedding, got %d", result.CompetitorCount)
 	}
 }
+
+func TestSimulateDecay_MatchesBatchDecay(t *testing.T) {
+	mockStore := newDecayMockStore()
+	svc := NewDecayService(mockStore, nil, zap.NewNop())
+	ctx := context.Background()
+	agentID := uuid.New()
+
+	mem := createTestMemory(agentID, 0.8, 10, 1, domain.MemoryTypeFact)
+	mem.Embedding = nil
+	mockStore.memories[mem.ID] = mem
+
+	// One tick one hour from now should match what the worker applies then.
+	projected, _, _ := simulateDecay(0.8, ConfidenceFloor, BaseDecayRate, 1, 10, 1, 1, ArchiveThreshold)
+	later := time.Now().Add(-11 * time.Hour)
+	mem.LastAccessedAt = &later
+	if _, err := svc.BatchDecay(ctx, agentID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if math.Abs(projected-float64(mem.Confidence)) > 1e-3 {
+		t.Errorf("simulated %v, worker applied %v", projected, mem.Confidence)
+	}
+}
+
+func TestForecastForgetting(t *testing.T) {
+	mockStore := newDecayMockStore()
+	svc := NewDecayService(mockStore, nil, zap.NewNop())
+	ctx := context.Background()
+	agentID := uuid.New()
+
+	fading := createTestMemory(agentID, 0.16, 2, 0, domain.MemoryTypeFact)
+	fading.Embedding = []float32{1, 0, 0}
+	sooner := createTestMemory(agentID, 0.155, 2, 0, domain.MemoryTypeFact)
+	sooner.Embedding = []float32{0, 1, 0}
+	sooner.TenantID = fading.TenantID
+	healthy := createTestMemory(agentID, 0.9, 2, 0, domain.MemoryTypeFact)
+	healthy.Embedding = []float32{0, 0, 1}
+	healthy.TenantID = fading.TenantID
+	otherTenant := createTestMemory(agentID, 0.16, 2, 0, domain.MemoryTypeFact)
+
+	for _, m := range []*domain.Memory{fading, sooner, healthy, otherTenant} {
+		mockStore.memories[m.ID] = m
+	}
+
+	forecast, err := svc.ForecastForgetting(ctx, agentID, fading.TenantID, 1, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if forecast.Count != 2 {
+		t.Fatalf("expected 2 fading memories, got %d", forecast.Count)
+	}
+	if forecast.Memories[0].ID != sooner.ID || forecast.Memories[1].ID != fading.ID {
+		t.Fatal("expected memories ordered by time until archive")
+	}
+	for _, m := range forecast.Memories {
+		if m.ProjectedConfidence >= float32(ArchiveThreshold) || m.DaysUntilArchive > 1 {
+			t.Errorf("memory %s: projected %v in %v days, expected below threshold within horizon", m.ID, m.ProjectedConfidence, m.DaysUntilArchive)
+		}
+	}
+}
+
+func TestDecay_BatchDecayNeverArchivesFlashbulbEpisodes(t *testing.T) {
+	h := testharness.New(t)
+	episodes := newMockEpisodeStore()
+	svc := NewDecayService(newDecayMockStore(), episodes, testLogger())
+
+	agentID := uuid.New()
+	intense := float32(0.9)
+	newEpisode := func(importance float32, emotion *float32) *domain.Episode {
+		ep := &domain.Episode{
+			ID:                 uuid.New(),
+			AgentID:            agentID,
+			ImportanceScore:    importance,
+			EmotionalIntensity: emotion,
+			MemoryStrength:     1.0,
+			DecayRate:          0.1,
+			LastAccessedAt:     h.Clock.Now(),
+		}
+		episodes.episodes[ep.ID] = ep
+		return ep
+	}
+	ordinary := newEpisode(0.5, nil)
+	important := newEpisode(0.95, nil)
+	emotional := newEpisode(0.3, &intense)
+
+	for day := 0; day < 365; day++ {
+		h.Clock.Advance(24 * time.Hour)
+		if _, err := svc.BatchDecay(context.Background(), agentID); err != nil {
+			t.Fatalf("BatchDecay failed on day %d: %v", day, err)
+		}
+	}
+
+	if ordinary.ConsolidationStatus != domain.ConsolidationArchived {
+		t.Errorf("expected the ordinary episode to be archived, strength %v", ordinary.MemoryStrength)
+	}
+	for name, ep := range map[string]*domain.Episode{"important": important, "emotional": emotional} {
+		if ep.ConsolidationStatus == domain.ConsolidationArchived {
+			t.Errorf("%s flashbulb episode was archived", name)
+		}
+		if ep.MemoryStrength < domain.FlashbulbStrengthFloor {
+			t.Errorf("%s flashbulb episode fell to %v", name, ep.MemoryStrength)
+		}
+	}
+}
+
+func TestForecastForgetting_LimitKeepsTheSoonest(t *testing.T) {
+	mockStore := newDecayMockStore()
+	svc := NewDecayService(mockStore, nil, zap.NewNop())
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+
+	for i := 0; i < 40; i++ {
+		m := createTestMemoryWithEmbedding(agentID, 0.16+float32(i)*0.005, float64(2+i%5), nil)
+		m.TenantID = tenantID
+		mockStore.memories[m.ID] = m
+	}
+	// A confident neighbour pushes the memory next to it over the threshold
+	// sooner than decay alone would.
+	competed := createTestMemoryWithEmbedding(agentID, 0.3, 2, []float32{0, 0, 1})
+	competed.TenantID = tenantID
+	rival := createTestMemoryWithEmbedding(agentID, 0.95, 2, []float32{0, 0.01, 1})
+	rival.TenantID = tenantID
+	mockStore.memories[competed.ID] = competed
+	mockStore.memories[rival.ID] = rival
+
+	full, err := svc.ForecastForgetting(ctx, agentID, tenantID, 30, MaxForecastLimit)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	top, err := svc.ForecastForgetting(ctx, agentID, tenantID, 30, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if full.Count <= 3 || top.Count != 3 {
+		t.Fatalf("expected a limited forecast of 3 out of more, got %d of %d", top.Count, full.Count)
+	}
+	for i, m := range top.Memories {
+		if m.DaysUntilArchive != full.Memories[i].DaysUntilArchive {
+			t.Errorf("position %d: expected archive in %v days, got %v", i, full.Memories[i].DaysUntilArchive, m.DaysUntilArchive)
+		}
+	}
+
+	var found *ForecastedMemory
+	for i := range full.Memories {
+		if full.Memories[i].ID == competed.ID {
+			found = &full.Memories[i]
+		}
+	}
+	if found == nil || found.EffectiveDecayRate <= svc.BaseDecayRate {
+		t.Fatalf("expected the competed memory forecast at a raised rate, got %+v", found)
+	}
+}
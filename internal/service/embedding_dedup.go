@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"go.uber.org/zap"
+)
+
+// DedupEmbeddingClient serves text the tenant has already stored with an
+// embedding from the embeddings table, and calls the provider only for the
+// rest. Memories from one extraction often repeat a text, and a re-embed
+// after a model change computes each distinct text once. Storage is
+// deduplicated by the stores themselves; see MemoryStore.SetEmbeddingDedup.
+// Calls without a tenant on the context, and lookups that fail, go to the
+// provider.
+type DedupEmbeddingClient struct {
+	inner  domain.EmbeddingClient
+	cache  domain.EmbeddingCache
+	logger *zap.Logger
+}
+
+func NewDedupEmbeddingClient(inner domain.EmbeddingClient, cache domain.EmbeddingCache, logger *zap.Logger) *DedupEmbeddingClient {
+	return &DedupEmbeddingClient{inner: inner, cache: cache, logger: logger}
+}
+
+func (c *DedupEmbeddingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if found := c.lookup(ctx, []string{text}); found[0] != nil {
+		return found[0], nil
+	}
+	return c.inner.Embed(ctx, text)
+}
+
+// EmbedBatch embeds the texts not found stored in one provider call when the
+// provider batches, one by one otherwise.
+func (c *DedupEmbeddingClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := c.lookup(ctx, texts)
+	var missing []int
+	for i, v := range vecs {
+		if v == nil {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return vecs, nil
+	}
+
+	if be, ok := c.inner.(domain.BatchEmbedder); ok {
+		batch := make([]string, len(missing))
+		for j, i := range missing {
+			batch[j] = texts[i]
+		}
+		embedded, err := be.EmbedBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(embedded) != len(batch) {
+			return nil, fmt.Errorf("embedding batch returned %d vectors for %d texts", len(embedded), len(batch))
+		}
+		for j, i := range missing {
+			vecs[i] = embedded[j]
+		}
+		return vecs, nil
+	}
+	for _, i := range missing {
+		v, err := c.inner.Embed(ctx, texts[i])
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = v
+	}
+	return vecs, nil
+}
+
+// lookup returns the stored vector of each text, nil where there is none.
+func (c *DedupEmbeddingClient) lookup(ctx context.Context, texts []string) [][]float32 {
+	vecs := make([][]float32, len(texts))
+	tenantID, ok := domain.TenantIDFrom(ctx)
+	if !ok {
+		return vecs
+	}
+	hashes := make([]string, len(texts))
+	for i, t := range texts {
+		hashes[i] = domain.HashContent(t)
+	}
+	found, err := c.cache.LookupEmbeddings(ctx, tenantID, hashes)
+	if err != nil {
+		logFor(ctx, c.logger).Warn("embedding lookup failed, calling the provider", zap.Error(err))
+		return vecs
+	}
+	for i, h := range hashes {
+		vecs[i] = found[h]
+	}
+	return vecs
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type mapEmbeddingCache struct {
+	tenantID uuid.UUID
+	vecs     map[string][]float32
+}
+
+func (c *mapEmbeddingCache) LookupEmbeddings(ctx context.Context, tenantID uuid.UUID, hashes []string) (map[string][]float32, error) {
+	found := map[string][]float32{}
+	if tenantID != c.tenantID {
+		return found, nil
+	}
+	for _, h := range hashes {
+		if v, ok := c.vecs[h]; ok {
+			found[h] = v
+		}
+	}
+	return found, nil
+}
+
+func (c *mapEmbeddingCache) PruneEmbeddings(ctx context.Context) (int64, error) { return 0, nil }
+
+func TestDedupEmbeddingClient_ServesStoredTextsWithoutTheProvider(t *testing.T) {
+	tenantID := uuid.New()
+	stored := []float32{0.5, 0.5}
+	cache := &mapEmbeddingCache{tenantID: tenantID, vecs: map[string][]float32{domain.HashContent("known"): stored}}
+	inner := &batchEmbedder{}
+	c := NewDedupEmbeddingClient(inner, cache, nil)
+	ctx := domain.WithTenantID(context.Background(), tenantID)
+
+	v, err := c.Embed(ctx, "known")
+	if err != nil || len(v) != 2 {
+		t.Fatalf("expected the stored vector, got %v, %v", v, err)
+	}
+	if inner.singles != 0 {
+		t.Errorf("expected no provider call for stored text, got %d", inner.singles)
+	}
+
+	vecs, err := c.EmbedBatch(ctx, []string{"known", "new", "known"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.batches) != 1 || len(inner.batches[0]) != 1 || inner.batches[0][0] != "new" {
+		t.Fatalf("expected one provider batch with the new text only, got %v", inner.batches)
+	}
+	if len(vecs[0]) != 2 || len(vecs[1]) != 1536 || len(vecs[2]) != 2 {
+		t.Errorf("expected stored, embedded, stored vectors in input order")
+	}
+
+	// Without a tenant there is nothing to look up.
+	if _, err := c.Embed(context.Background(), "known"); err != nil {
+		t.Fatal(err)
+	}
+	if inner.singles != 1 {
+		t.Errorf("expected a provider call without a tenant, got %d", inner.singles)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -33,6 +34,17 @@ type EpisodeService struct {
 	episodeStore    domain.EpisodeStore
 	agentStore      domain.AgentStore
 	memoryStore     domain.MemoryStore
+	procedureStore  domain.ProcedureStore
+	usageStore      domain.EpisodeMemoryUsageStore
+	chunkStore      domain.EpisodeChunkStore
+	captioner       domain.ImageCaptioner
+	transcriber     domain.Transcriber
+	entityStore     domain.EntityStore
+	attributor      OutcomeAttributor
+	reconsolidator  Reconsolidator
+	positions       *EpisodePositionService
+	envBoost        float64
+	reliability     *SourceReliabilityService
 	embeddingClient domain.EmbeddingClient
 	llmClient       domain.LLMClient
 	logger          *zap.Logger
@@ -50,6 +62,7 @@ func NewEpisodeService(
 		agentStore:      as,
 		embeddingClient: ec,
 		llmClient:       lc,
+		envBoost:        DefaultEnvironmentBoost,
 		logger:          logger,
 	}
 }
@@ -58,6 +71,36 @@ func (s *EpisodeService) SetMemoryStore(ms domain.MemoryStore) {
 	s.memoryStore = ms
 }
 
+// SetOutcomeAttributor propagates recorded outcomes to the memories and
+// procedures active during the episode's conversation.
+func (s *EpisodeService) SetOutcomeAttributor(a OutcomeAttributor) {
+	s.attributor = a
+}
+
+// SetReconsolidator updates the memories recalled in an episode's
+// conversation that the episode confirms or contradicts.
+func (s *EpisodeService) SetReconsolidator(r Reconsolidator) {
+	s.reconsolidator = r
+}
+
+// SetPositionWeighting ranks the episodes that open or close their
+// conversation higher in recall.
+func (s *EpisodeService) SetPositionWeighting(p *EpisodePositionService) {
+	s.positions = p
+}
+
+// SetEnvironmentBoost sets how much higher an episode recorded in the caller's
+// environment ranks in recall; 0 turns the bias off.
+func (s *EpisodeService) SetEnvironmentBoost(boost float64) {
+	s.envBoost = boost
+}
+
+// SetSourceReliability scales the discount on beliefs extracted from episodes
+// by how the agent's extractions have fared.
+func (s *EpisodeService) SetSourceReliability(sr *SourceReliabilityService) {
+	s.reliability = sr
+}
+
 // EncodeInput is the input for encoding a new episode.
 type EncodeInput struct {
 	AgentID        uuid.UUID
@@ -66,16 +109,95 @@ type EncodeInput struct {
 	ConversationID *uuid.UUID
 	OccurredAt     time.Time
 	Outcome        *domain.OutcomeType
+	// DurationSeconds and MessageSequence place the episode within a
+	// recorded conversation.
+	DurationSeconds *int
+	MessageSequence *int
+	// Attachments are images the episode refers to; ones without a caption
+	// are captioned when an image captioner is set.
+	Attachments []domain.EpisodeAttachment
+	// Participants are who was present; ones without an entity are linked
+	// to a person node when an entity store is set.
+	Participants []domain.EpisodeParticipant
+	// Environment is where the episode happened.
+	Environment domain.Environment
+	// Embedding, when set, is used instead of embedding RawContent, so callers
+	// that embed in batches don't pay for a second call.
+	Embedding []float32
 }
 
 // Encode creates a richly-encoded episode from raw input.
 func (s *EpisodeService) Encode(ctx context.Context, input EncodeInput) (*domain.Episode, error) {
+	episode, err := s.prepareEpisode(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.episodeStore.Create(ctx, episode); err != nil {
+		return nil, err
+	}
+	s.afterEncode(ctx, episode, input)
+	return episode, nil
+}
+
+// EncodeBatch encodes episodes of one tenant and inserts them together when
+// the store supports it. errs is aligned with inputs; the returned episodes
+// are the ones stored, in input order. A failed batch insert fails every
+// input that reached it.
+func (s *EpisodeService) EncodeBatch(ctx context.Context, inputs []EncodeInput) (episodes []*domain.Episode, errs []error) {
+	errs = make([]error, len(inputs))
+	prepared := make([]*domain.Episode, 0, len(inputs))
+	idx := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		ep, err := s.prepareEpisode(ctx, input)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		prepared = append(prepared, ep)
+		idx = append(idx, i)
+	}
+
+	if bc, ok := s.episodeStore.(domain.EpisodeBatchCreator); ok {
+		if err := bc.CreateBatch(ctx, prepared); err != nil {
+			for _, i := range idx {
+				errs[i] = err
+			}
+			return nil, errs
+		}
+	} else {
+		for j, ep := range prepared {
+			if err := s.episodeStore.Create(ctx, ep); err != nil {
+				errs[idx[j]] = err
+				prepared[j] = nil
+			}
+		}
+	}
+
+	for j, ep := range prepared {
+		if ep == nil {
+			continue
+		}
+		s.afterEncode(ctx, ep, inputs[idx[j]])
+		episodes = append(episodes, ep)
+	}
+	return episodes, errs
+}
+
+// prepareEpisode validates input and builds the episode to store: defaults,
+// temporal context, embedding and, for outcome-bearing input, LLM structure.
+func (s *EpisodeService) prepareEpisode(ctx context.Context, input EncodeInput) (*domain.Episode, error) {
 	if input.RawContent == "" {
 		return nil, ErrEpisodeContentEmpty
 	}
 	if input.AgentID == uuid.Nil {
 		return nil, ErrEpisodeAgentIDMissing
 	}
+	if err := ValidateAttachments(input.Attachments); err != nil {
+		return nil, err
+	}
+	if err := ValidateParticipants(input.Participants); err != nil {
+		return nil, err
+	}
 
 	// Verify agent exists
 	_, err := s.agentStore.GetByID(ctx, input.AgentID, input.TenantID)
@@ -88,7 +210,7 @@ func (s *EpisodeService) Encode(ctx context.Context, input EncodeInput) (*domain
 
 	// Set defaults
 	if input.OccurredAt.IsZero() {
-		input.OccurredAt = time.Now()
+		input.OccurredAt = timeNow()
 	}
 
 	episode := &domain.Episode{
@@ -96,28 +218,44 @@ func (s *EpisodeService) Encode(ctx context.Context, input EncodeInput) (*domain
 		TenantID:            input.TenantID,
 		RawContent:          input.RawContent,
 		ConversationID:      input.ConversationID,
+		MessageSequence:     input.MessageSequence,
 		OccurredAt:          input.OccurredAt,
+		DurationSeconds:     input.DurationSeconds,
 		ConsolidationStatus: domain.ConsolidationRaw,
 		MemoryStrength:      1.0,
 		DecayRate:           0.1,
 		AccessCount:         1,
-		LastAccessedAt:      time.Now(),
+		LastAccessedAt:      timeNow(),
 		ImportanceScore:     0.5,
 	}
 
 	if input.Outcome != nil {
 		episode.Outcome = *input.Outcome
 	}
+	if env := input.Environment.Normalize(); !env.IsZero() {
+		episode.Environment = &env
+	}
+
+	if len(input.Attachments) > 0 {
+		episode.Attachments = append([]domain.EpisodeAttachment(nil), input.Attachments...)
+		s.captionAttachments(ctx, episode.Attachments)
+	}
+
+	if len(input.Participants) > 0 {
+		episode.Participants = normalizeParticipants(input.Participants)
+		s.resolveParticipants(ctx, input.AgentID, input.TenantID, episode.Participants)
+	}
 
 	// Extract temporal context
 	episode.TimeOfDay = extractTimeOfDay(input.OccurredAt)
 	episode.DayOfWeek = input.OccurredAt.Weekday().String()
 
 	// Generate embedding
-	if s.embeddingClient != nil {
+	episode.Embedding = input.Embedding
+	if s.embeddingClient != nil && len(episode.Embedding) == 0 {
 		emb, err := s.embeddingClient.Embed(ctx, input.RawContent)
 		if err != nil {
-			s.logger.Warn("failed to generate episode embedding", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to generate episode embedding", zap.Error(err))
 		} else {
 			episode.Embedding = emb
 		}
@@ -125,15 +263,13 @@ func (s *EpisodeService) Encode(ctx context.Context, input EncodeInput) (*domain
 
 	// Gate expensive LLM extraction behind importance/outcome signals.
 	// Only run ExtractEpisodeStructure if outcome indicates it's worth it.
-	hasSignificantOutcome := input.Outcome != nil && (*input.Outcome == domain.OutcomeSuccess || *input.Outcome == domain.OutcomeFailure)
-
-	if s.llmClient != nil && hasSignificantOutcome {
+	if s.llmClient != nil && hasSignificantOutcome(input) {
 		extraction, err := s.llmClient.ExtractEpisodeStructure(ctx, input.RawContent)
 		if err != nil {
-			s.logger.Warn("failed to extract episode structure", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to extract episode structure", zap.Error(err))
 		} else if extraction != nil {
 			episode.Entities = extraction.Entities
-			episode.Topics = extraction.Topics
+			episode.Topics = domain.NormalizeTopics(extraction.Topics)
 			episode.CausalLinks = extraction.CausalLinks
 			episode.EmotionalValence = extraction.EmotionalValence
 			episode.EmotionalIntensity = extraction.EmotionalIntensity
@@ -143,22 +279,35 @@ func (s *EpisodeService) Encode(ctx context.Context, input EncodeInput) (*domain
 		}
 	}
 
-	// Save episode
-	if err := s.episodeStore.Create(ctx, episode); err != nil {
-		return nil, err
-	}
+	return episode, nil
+}
+
+// hasSignificantOutcome reports whether input ended in success or failure,
+// which is what makes an episode worth LLM extraction.
+func hasSignificantOutcome(input EncodeInput) bool {
+	return input.Outcome != nil && (*input.Outcome == domain.OutcomeSuccess || *input.Outcome == domain.OutcomeFailure)
+}
 
+// afterEncode links a stored episode to similar ones, embeds its passages
+// when it is long and, in the background, reconsolidates the memories
+// recalled earlier in its conversation and, for important or outcome-bearing
+// episodes, extracts beliefs.
+func (s *EpisodeService) afterEncode(ctx context.Context, episode *domain.Episode, input EncodeInput) {
 	// Find and create associations with similar episodes
 	if len(episode.Embedding) > 0 {
 		s.createAssociations(ctx, episode)
 	}
 
-	// Only extract beliefs for important or outcome-bearing episodes
-	if s.llmClient != nil && s.memoryStore != nil && (episode.ImportanceScore >= ImportanceThreshold || hasSignificantOutcome) {
-		go s.extractBeliefsFromEpisode(context.Background(), episode)
+	s.indexChunks(ctx, episode)
+
+	if s.reconsolidator != nil && episode.ConversationID != nil {
+		go s.reconsolidate(domain.WithTenantID(domain.CopyTraceIDs(context.Background(), ctx), episode.TenantID), episode)
 	}
 
-	return episode, nil
+	// Only extract beliefs for important or outcome-bearing episodes
+	if s.llmClient != nil && s.memoryStore != nil && (episode.ImportanceScore >= ImportanceThreshold || hasSignificantOutcome(input)) {
+		go s.extractBeliefsFromEpisode(domain.WithTenantID(domain.CopyTraceIDs(context.Background(), ctx), episode.TenantID), episode)
+	}
 }
 
 // createAssociations finds similar episodes and creates associations.
@@ -172,7 +321,7 @@ func (s *EpisodeService) createAssociations(ctx context.Context, episode *domain
 		MaxAssociationsPerEpisode,
 	)
 	if err != nil {
-		s.logger.Warn("failed to find similar episodes", zap.Error(err))
+		logFor(ctx, s.logger).Warn("failed to find similar episodes", zap.Error(err))
 		return
 	}
 
@@ -189,7 +338,7 @@ func (s *EpisodeService) createAssociations(ctx context.Context, episode *domain
 		}
 
 		if err := s.episodeStore.CreateAssociation(ctx, assoc); err != nil {
-			s.logger.Warn("failed to create episode association",
+			logFor(ctx, s.logger).Warn("failed to create episode association",
 				zap.String("episode_a", episode.ID.String()),
 				zap.String("episode_b", sim.ID.String()),
 				zap.Error(err))
@@ -197,19 +346,28 @@ func (s *EpisodeService) createAssociations(ctx context.Context, episode *domain
 	}
 }
 
+func (s *EpisodeService) reconsolidate(ctx context.Context, episode *domain.Episode) {
+	if _, err := s.reconsolidator.Reconsolidate(ctx, episode); err != nil {
+		logFor(ctx, s.logger).Warn("failed to reconsolidate recalled memories",
+			zap.String("episode_id", episode.ID.String()),
+			zap.Error(err))
+	}
+}
+
 func (s *EpisodeService) extractBeliefsFromEpisode(ctx context.Context, episode *domain.Episode) {
 	extracted, err := s.llmClient.Extract(ctx, []domain.Message{
 		{Role: "user", Content: episode.RawContent},
 	})
 	if err != nil {
-		s.logger.Debug("failed to extract beliefs from episode", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to extract beliefs from episode", zap.Error(err))
 		return
 	}
 
+	discount := s.reliability.extractionDiscount(ctx, episode.AgentID, episode.TenantID, ExtractionConfidenceDiscount)
 	for _, belief := range extracted {
-		confidence := belief.Confidence * ExtractionConfidenceDiscount
+		confidence := belief.Confidence * discount
 		if belief.EvidenceType != "" {
-			confidence = belief.EvidenceType.InitialConfidence() * ExtractionConfidenceDiscount
+			confidence = belief.EvidenceType.InitialConfidence() * discount
 		}
 
 		mem := &domain.Memory{
@@ -219,6 +377,11 @@ func (s *EpisodeService) extractBeliefsFromEpisode(ctx context.Context, episode
 			Type:       belief.Type,
 			Confidence: confidence,
 			Source:     "episode:" + episode.ID.String(),
+			Topic:      domain.NormalizeTopic(belief.Topic),
+		}
+		mem.Subject, mem.SubjectID = beliefSubject(episode, belief.Subject)
+		if episode.Environment != nil {
+			mem.Metadata = domain.WithEnvironment(nil, *episode.Environment)
 		}
 
 		// Generate embedding
@@ -230,7 +393,7 @@ func (s *EpisodeService) extractBeliefsFromEpisode(ctx context.Context, episode
 		}
 
 		if err := s.memoryStore.Create(ctx, mem); err != nil {
-			s.logger.Debug("failed to store extracted belief",
+			logFor(ctx, s.logger).Debug("failed to store extracted belief",
 				zap.String("content", belief.Content),
 				zap.Error(err))
 			continue
@@ -238,13 +401,13 @@ func (s *EpisodeService) extractBeliefsFromEpisode(ctx context.Context, episode
 
 		// Link the derived memory to the episode
 		if err := s.episodeStore.LinkDerivedMemory(ctx, episode.ID, mem.ID, "semantic"); err != nil {
-			s.logger.Debug("failed to link derived memory to episode", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to link derived memory to episode", zap.Error(err))
 		}
 	}
 
 	// Mark episode as processed
 	if err := s.episodeStore.UpdateConsolidationStatus(ctx, episode.ID, domain.ConsolidationProcessed); err != nil {
-		s.logger.Debug("failed to update episode consolidation status", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to update episode consolidation status", zap.Error(err))
 	}
 }
 
@@ -260,7 +423,7 @@ func (s *EpisodeService) GetByID(ctx context.Context, id uuid.UUID, tenantID uui
 
 	// Record access (best effort)
 	if err := s.episodeStore.RecordAccess(ctx, id); err != nil {
-		s.logger.Warn("failed to record episode access", zap.Error(err))
+		logFor(ctx, s.logger).Warn("failed to record episode access", zap.Error(err))
 	}
 
 	return episode, nil
@@ -273,6 +436,26 @@ type EpisodeRecallOpts struct {
 	EndTime       *time.Time
 	MinImportance float32
 	Limit         int
+	// Environment is the caller's current environment; episodes recorded in
+	// it rank higher.
+	Environment domain.Environment
+}
+
+// weighEnvironment multiplies the score of episodes recorded in env by up to
+// 1+envBoost, by how much of env matches, and re-ranks the results.
+func (s *EpisodeService) weighEnvironment(results []domain.EpisodeWithScore, env domain.Environment) {
+	env = env.Normalize()
+	if env.IsZero() || s.envBoost <= 0 {
+		return
+	}
+	for i := range results {
+		if results[i].Environment != nil {
+			results[i].Score *= float32(1 + s.envBoost*env.Match(*results[i].Environment))
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
 }
 
 // Recall retrieves episodes based on various criteria.
@@ -301,10 +484,12 @@ func (s *EpisodeService) Recall(ctx context.Context, agentID uuid.UUID, tenantID
 			return nil, err
 		}
 
-		results, err := s.episodeStore.FindSimilar(ctx, agentID, tenantID, emb, 0.5, opts.Limit)
+		results, err := s.findSimilar(ctx, agentID, tenantID, emb, 0.5, opts.Limit)
 		if err != nil {
 			return nil, err
 		}
+		s.weighEnvironment(results, opts.Environment)
+		s.positions.WeighRecall(ctx, agentID, tenantID, results)
 
 		// Record access for retrieved episodes
 		for _, ep := range results {
@@ -350,6 +535,8 @@ func (s *EpisodeService) Recall(ctx context.Context, agentID uuid.UUID, tenantID
 			})
 			_ = s.episodeStore.RecordAccess(ctx, ep.ID)
 		}
+		s.weighEnvironment(results, opts.Environment)
+		s.positions.WeighRecall(ctx, agentID, tenantID, results)
 		return results, nil
 	}
 
@@ -363,7 +550,7 @@ func (s *EpisodeService) RecordOutcome(ctx context.Context, id uuid.UUID, tenant
 	}
 
 	// Verify episode exists
-	_, err := s.episodeStore.GetByID(ctx, id, tenantID)
+	episode, err := s.episodeStore.GetByID(ctx, id, tenantID)
 	if err != nil {
 		if errors.Is(err, store.ErrNotFound) {
 			return ErrEpisodeNotFound
@@ -371,7 +558,19 @@ func (s *EpisodeService) RecordOutcome(ctx context.Context, id uuid.UUID, tenant
 		return err
 	}
 
-	return s.episodeStore.UpdateOutcome(ctx, id, tenantID, outcome, description)
+	if err := s.episodeStore.UpdateOutcome(ctx, id, tenantID, outcome, description); err != nil {
+		return err
+	}
+
+	if s.attributor != nil {
+		if err := s.attributor.AttributeOutcome(ctx, episode, outcome); err != nil {
+			logFor(ctx, s.logger).Warn("failed to attribute episode outcome",
+				zap.String("episode_id", id.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
 }
 
 // GetByConversationID retrieves all episodes for a conversation.
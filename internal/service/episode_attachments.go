@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidAttachment = errors.New("invalid attachment")
+
+// captionTimeout bounds one vision model call.
+const captionTimeout = 30 * time.Second
+
+// SetImageCaptioner has the vision model caption image attachments that come
+// without a caption. Captions are embedded with the episode's passages, so an
+// episode can be recalled by what its images show.
+func (s *EpisodeService) SetImageCaptioner(c domain.ImageCaptioner) {
+	s.captioner = c
+}
+
+// ValidateAttachments checks there are at most domain.MaxEpisodeAttachments,
+// each an absolute http(s) URL with, if given, an image media type.
+func ValidateAttachments(atts []domain.EpisodeAttachment) error {
+	if len(atts) > domain.MaxEpisodeAttachments {
+		return fmt.Errorf("%w: at most %d attachments per episode", ErrInvalidAttachment, domain.MaxEpisodeAttachments)
+	}
+	for i, a := range atts {
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: attachments[%d].url must be an absolute http(s) URL", ErrInvalidAttachment, i)
+		}
+		if a.MediaType != "" && !strings.HasPrefix(a.MediaType, "image/") {
+			return fmt.Errorf("%w: attachments[%d].media_type must be an image type", ErrInvalidAttachment, i)
+		}
+	}
+	return nil
+}
+
+// captionAttachments fills in missing captions. An image the model can't
+// caption is kept without one.
+func (s *EpisodeService) captionAttachments(ctx context.Context, atts []domain.EpisodeAttachment) {
+	if s.captioner == nil {
+		return
+	}
+	for i := range atts {
+		if atts[i].Caption != "" {
+			continue
+		}
+		cctx, cancel := context.WithTimeout(ctx, captionTimeout)
+		caption, err := s.captioner.CaptionImage(cctx, atts[i].URL)
+		cancel()
+		if err != nil {
+			logFor(ctx, s.logger).Warn("failed to caption episode attachment", zap.Int("attachment", i), zap.Error(err))
+			continue
+		}
+		atts[i].Caption = caption
+	}
+}
+
+// attachmentPassages are the texts embedded for an episode's captioned images.
+func attachmentPassages(atts []domain.EpisodeAttachment) []string {
+	var out []string
+	for _, a := range atts {
+		if a.Caption != "" {
+			out = append(out, "Image: "+a.Caption)
+		}
+	}
+	return out
+}
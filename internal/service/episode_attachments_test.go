@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// mockCaptioner captions every image with a fixed text.
+type mockCaptioner struct {
+	calls []string
+	err   error
+}
+
+func (m *mockCaptioner) CaptionImage(ctx context.Context, imageURL string) (string, error) {
+	m.calls = append(m.calls, imageURL)
+	if m.err != nil {
+		return "", m.err
+	}
+	return "A whiteboard with the release checklist", nil
+}
+
+func TestEpisodeService_EncodeCaptionsAndEmbedsAttachments(t *testing.T) {
+	svc, episodeStore, tenantID, agentID := setupEpisodeTest()
+	captioner := &mockCaptioner{}
+	chunkStore := &mockEpisodeChunkStore{}
+	svc.SetImageCaptioner(captioner)
+	svc.SetChunkStore(chunkStore)
+
+	episode, err := svc.Encode(context.Background(), EncodeInput{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		RawContent: "Went over the release plan",
+		Attachments: []domain.EpisodeAttachment{
+			{URL: "https://bucket.example.com/board.png", MediaType: "image/png"},
+			{URL: "https://bucket.example.com/diagram.png", Caption: "Architecture diagram"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(captioner.calls) != 1 || captioner.calls[0] != "https://bucket.example.com/board.png" {
+		t.Fatalf("expected only the uncaptioned image to be captioned, got %v", captioner.calls)
+	}
+	stored := episodeStore.episodes[episode.ID]
+	if stored.Attachments[0].Caption != "A whiteboard with the release checklist" || stored.Attachments[1].Caption != "Architecture diagram" {
+		t.Fatalf("unexpected stored attachments %+v", stored.Attachments)
+	}
+	if len(chunkStore.chunks) != 2 || chunkStore.chunks[0].Content != "Image: A whiteboard with the release checklist" {
+		t.Fatalf("expected one embedded chunk per caption, got %+v", chunkStore.chunks)
+	}
+}
+
+func TestEpisodeService_EncodeKeepsAttachmentWhenCaptioningFails(t *testing.T) {
+	svc, _, tenantID, agentID := setupEpisodeTest()
+	svc.SetImageCaptioner(&mockCaptioner{err: errors.New("model unavailable")})
+
+	episode, err := svc.Encode(context.Background(), EncodeInput{
+		AgentID:     agentID,
+		TenantID:    tenantID,
+		RawContent:  "Screenshot of the error",
+		Attachments: []domain.EpisodeAttachment{{URL: "https://bucket.example.com/error.png"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(episode.Attachments) != 1 || episode.Attachments[0].Caption != "" {
+		t.Fatalf("expected the uncaptioned attachment to be kept, got %+v", episode.Attachments)
+	}
+}
+
+func TestValidateAttachments(t *testing.T) {
+	tooMany := make([]domain.EpisodeAttachment, domain.MaxEpisodeAttachments+1)
+	for i := range tooMany {
+		tooMany[i].URL = "https://example.com/a.png"
+	}
+	for name, atts := range map[string][]domain.EpisodeAttachment{
+		"relative url":   {{URL: "/images/a.png"}},
+		"other scheme":   {{URL: "file:///etc/passwd"}},
+		"non-image type": {{URL: "https://example.com/a.pdf", MediaType: "application/pdf"}},
+		"too many":       tooMany,
+	} {
+		if err := ValidateAttachments(atts); !errors.Is(err, ErrInvalidAttachment) {
+			t.Errorf("%s: expected ErrInvalidAttachment, got %v", name, err)
+		}
+	}
+	if err := ValidateAttachments([]domain.EpisodeAttachment{{URL: "https://example.com/a.png", MediaType: "image/png"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
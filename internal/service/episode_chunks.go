@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// EpisodeChunkSize is the target passage length in runes. Episodes no
+	// longer than this keep only their whole-episode embedding.
+	EpisodeChunkSize = 800
+	// maxEpisodeChunks caps the passages embedded per episode; text past the
+	// cap is only matched through the whole-episode embedding.
+	maxEpisodeChunks = 64
+	// chunkCandidateFactor widens the chunk search so that several passages
+	// of one episode can contribute to its score.
+	chunkCandidateFactor = 4
+	// chunkSupportDecay weighs an episode's further matching passages: the
+	// n-th best adds score × decay^(n-1) to the best one.
+	chunkSupportDecay = 0.1
+)
+
+// SetChunkStore turns on passage-level embeddings: long episodes are split
+// into chunks embedded on their own, and query recall scores each episode by
+// its best-matching passages as well as by its whole-episode embedding.
+func (s *EpisodeService) SetChunkStore(cs domain.EpisodeChunkStore) {
+	s.chunkStore = cs
+}
+
+// indexChunks embeds and stores a newly encoded episode's passages, followed
+// by its image captions. Failures are logged; the episode stays recallable by
+// its whole embedding.
+func (s *EpisodeService) indexChunks(ctx context.Context, episode *domain.Episode) {
+	if s.chunkStore == nil || s.embeddingClient == nil {
+		return
+	}
+	passages := append(chunkText(episode.RawContent, EpisodeChunkSize), attachmentPassages(episode.Attachments)...)
+	if len(passages) == 0 {
+		return
+	}
+
+	vecs, err := s.embedPassages(ctx, passages)
+	if err != nil {
+		logFor(ctx, s.logger).Warn("failed to embed episode chunks",
+			zap.String("episode_id", episode.ID.String()), zap.Error(err))
+		return
+	}
+	chunks := make([]domain.EpisodeChunk, len(passages))
+	for i, p := range passages {
+		chunks[i] = domain.EpisodeChunk{
+			EpisodeID:  episode.ID,
+			AgentID:    episode.AgentID,
+			TenantID:   episode.TenantID,
+			ChunkIndex: i,
+			Content:    p,
+			Embedding:  vecs[i],
+		}
+	}
+	if err := s.chunkStore.CreateChunks(ctx, chunks); err != nil {
+		logFor(ctx, s.logger).Warn("failed to store episode chunks",
+			zap.String("episode_id", episode.ID.String()), zap.Error(err))
+	}
+}
+
+// embedPassages embeds texts in one call when the client can batch.
+func (s *EpisodeService) embedPassages(ctx context.Context, texts []string) ([][]float32, error) {
+	if be, ok := s.embeddingClient.(domain.BatchEmbedder); ok {
+		vecs, err := be.EmbedBatch(ctx, texts)
+		if err == nil && len(vecs) == len(texts) {
+			return vecs, nil
+		}
+		logFor(ctx, s.logger).Debug("batched chunk embedding failed, embedding one by one", zap.Error(err))
+	}
+	vecs := make([][]float32, len(texts))
+	for i, t := range texts {
+		v, err := s.embeddingClient.Embed(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = v
+	}
+	return vecs, nil
+}
+
+// findSimilar is query recall: episodes matching emb as a whole, merged with
+// episodes whose passages match it. A chunk-matched episode scores its best
+// passage plus a decaying share of its other matching passages, and keeps the
+// higher of that and its whole-episode score.
+func (s *EpisodeService) findSimilar(ctx context.Context, agentID, tenantID uuid.UUID, emb []float32, threshold float32, limit int) ([]domain.EpisodeWithScore, error) {
+	results, err := s.episodeStore.FindSimilar(ctx, agentID, tenantID, emb, threshold, limit)
+	if err != nil || s.chunkStore == nil {
+		return results, err
+	}
+	matches, err := s.chunkStore.FindSimilarChunks(ctx, agentID, tenantID, emb, threshold, limit*chunkCandidateFactor)
+	if err != nil {
+		logFor(ctx, s.logger).Warn("episode chunk search failed, using whole-episode matches", zap.Error(err))
+		return results, nil
+	}
+	if len(matches) == 0 {
+		return results, nil
+	}
+
+	byID := make(map[uuid.UUID]int, len(results))
+	for i, r := range results {
+		byID[r.ID] = i
+	}
+	var missing []domain.EpisodeWithScore
+	for _, c := range aggregateChunkMatches(matches) {
+		if i, ok := byID[c.EpisodeID]; ok {
+			if c.Score > results[i].Score {
+				results[i].Score = c.Score
+				results[i].Passage = c.Content
+			}
+			continue
+		}
+		missing = append(missing, domain.EpisodeWithScore{
+			Episode: domain.Episode{ID: c.EpisodeID},
+			Score:   c.Score,
+			Passage: c.Content,
+		})
+	}
+	results = append(results, missing...)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	// Episodes found only through a chunk are loaded once they've made the cut.
+	merged := results[:0]
+	for _, r := range results {
+		if _, ok := byID[r.ID]; !ok {
+			ep, err := s.episodeStore.GetByID(ctx, r.ID, tenantID)
+			if err != nil {
+				logFor(ctx, s.logger).Debug("failed to load chunk-matched episode",
+					zap.String("episode_id", r.ID.String()), zap.Error(err))
+				continue
+			}
+			r.Episode = *ep
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
+
+// aggregateChunkMatches folds chunk matches, best first, into one match per
+// episode carrying its aggregate score and best passage, best first.
+func aggregateChunkMatches(matches []domain.EpisodeChunkMatch) []domain.EpisodeChunkMatch {
+	sorted := append([]domain.EpisodeChunkMatch(nil), matches...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+
+	var out []domain.EpisodeChunkMatch
+	index := map[uuid.UUID]int{}
+	weight := map[uuid.UUID]float32{}
+	for _, m := range sorted {
+		i, ok := index[m.EpisodeID]
+		if !ok {
+			index[m.EpisodeID] = len(out)
+			weight[m.EpisodeID] = chunkSupportDecay
+			out = append(out, m)
+			continue
+		}
+		out[i].Score += m.Score * weight[m.EpisodeID]
+		weight[m.EpisodeID] *= chunkSupportDecay
+	}
+	for i := range out {
+		if out[i].Score > 1 {
+			out[i].Score = 1
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// chunkText splits text longer than size runes into passages of at most about
+// size runes, breaking between sentences. Consecutive passages share a
+// sentence, so a statement that leans on the one before it keeps its context.
+// Text of size runes or fewer yields no passages.
+func chunkText(text string, size int) []string {
+	if len([]rune(text)) <= size {
+		return nil
+	}
+
+	var sentences []string
+	for _, sent := range splitSentences(text) {
+		sentences = append(sentences, splitLong(sent, size)...)
+	}
+
+	var chunks []string
+	var cur []string
+	curLen := 0
+	for _, sent := range sentences {
+		n := len([]rune(sent))
+		if len(cur) > 0 && curLen+1+n > size {
+			chunks = append(chunks, strings.Join(cur, " "))
+			if len(chunks) == maxEpisodeChunks {
+				return chunks
+			}
+			last := cur[len(cur)-1]
+			cur, curLen = nil, 0
+			if l := len([]rune(last)); l+1+n <= size {
+				cur, curLen = []string{last}, l
+			}
+		}
+		if len(cur) > 0 {
+			curLen++
+		}
+		cur = append(cur, sent)
+		curLen += n
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, strings.Join(cur, " "))
+	}
+	return chunks
+}
+
+// splitSentences breaks text after sentence-ending punctuation followed by
+// whitespace and at line breaks, trimming each piece.
+func splitSentences(text string) []string {
+	var out []string
+	runes := []rune(text)
+	start := 0
+	emit := func(end int) {
+		if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+			out = append(out, s)
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case r == '\n':
+			emit(i + 1)
+		case (r == '.' || r == '!' || r == '?') && i+1 < len(runes) && unicode.IsSpace(runes[i+1]):
+			emit(i + 1)
+		}
+	}
+	emit(len(runes))
+	return out
+}
+
+// splitLong cuts a sentence longer than size runes at the last space before
+// each limit, or mid-word when there is none.
+func splitLong(sent string, size int) []string {
+	runes := []rune(sent)
+	var out []string
+	for len(runes) > size {
+		cut := size
+		for i := size; i > size/2; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+		out = append(out, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	if len(runes) > 0 {
+		out = append(out, string(runes))
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// mockEpisodeChunkStore implements domain.EpisodeChunkStore for testing.
+type mockEpisodeChunkStore struct {
+	chunks  []domain.EpisodeChunk
+	matches []domain.EpisodeChunkMatch
+}
+
+func (m *mockEpisodeChunkStore) CreateChunks(ctx context.Context, chunks []domain.EpisodeChunk) error {
+	m.chunks = append(m.chunks, chunks...)
+	return nil
+}
+
+func (m *mockEpisodeChunkStore) FindSimilarChunks(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, limit int) ([]domain.EpisodeChunkMatch, error) {
+	return m.matches, nil
+}
+
+func longEpisodeText(sentences int) string {
+	var b strings.Builder
+	for i := range sentences {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString("The deploy pipeline stalled on the integration stage again today.")
+	}
+	return b.String()
+}
+
+func TestChunkText(t *testing.T) {
+	if got := chunkText("Short episode. Nothing to split.", 100); got != nil {
+		t.Fatalf("expected no chunks for short text, got %v", got)
+	}
+
+	text := longEpisodeText(40)
+	chunks := chunkText(text, 200)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if n := len([]rune(c)); n > 200 {
+			t.Fatalf("chunk %d has %d runes, over the 200 limit", i, n)
+		}
+		if !strings.HasSuffix(c, ".") {
+			t.Fatalf("chunk %d does not end on a sentence boundary: %q", i, c)
+		}
+	}
+	// Consecutive chunks share a sentence.
+	first := chunks[0][strings.LastIndex(chunks[0][:len(chunks[0])-1], ".")+2:]
+	if !strings.HasPrefix(chunks[1], first) {
+		t.Fatalf("expected chunk 1 to start with the last sentence of chunk 0 (%q), got %q", first, chunks[1])
+	}
+
+	// A sentence with no breaks is still cut to size.
+	for _, c := range chunkText(strings.Repeat("word ", 100), 60) {
+		if len([]rune(c)) > 60 {
+			t.Fatalf("expected an overlong sentence to be cut, got %d runes", len([]rune(c)))
+		}
+	}
+}
+
+func TestEpisodeService_EncodeStoresChunksForLongEpisodes(t *testing.T) {
+	svc, _, tenantID, agentID := setupEpisodeTest()
+	chunkStore := &mockEpisodeChunkStore{}
+	svc.SetChunkStore(chunkStore)
+	emb := &batchEmbedder{}
+	svc.embeddingClient = emb
+	ctx := context.Background()
+
+	if _, err := svc.Encode(ctx, EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: "A short note."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunkStore.chunks) != 0 {
+		t.Fatalf("expected no chunks for a short episode, got %d", len(chunkStore.chunks))
+	}
+
+	episode, err := svc.Encode(ctx, EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: longEpisodeText(40)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunkStore.chunks) < 2 {
+		t.Fatalf("expected the long episode to be chunked, got %d chunks", len(chunkStore.chunks))
+	}
+	for i, c := range chunkStore.chunks {
+		if c.EpisodeID != episode.ID || c.ChunkIndex != i || len(c.Embedding) == 0 {
+			t.Fatalf("unexpected chunk %d: %+v", i, c)
+		}
+	}
+	if len(emb.batches) != 1 || len(emb.batches[0]) != len(chunkStore.chunks) {
+		t.Fatalf("expected the chunks to be embedded in one batch, got %d batches", len(emb.batches))
+	}
+}
+
+func TestEpisodeService_RecallMergesChunkMatches(t *testing.T) {
+	svc, _, tenantID, agentID := setupEpisodeTest()
+	ctx := context.Background()
+
+	long, _ := svc.Encode(ctx, EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: longEpisodeText(40)})
+	other, _ := svc.Encode(ctx, EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: longEpisodeText(30)})
+
+	chunkStore := &mockEpisodeChunkStore{matches: []domain.EpisodeChunkMatch{
+		{EpisodeID: other.ID, ChunkIndex: 0, Content: "other passage", Score: 0.7},
+		{EpisodeID: long.ID, ChunkIndex: 3, Content: "best passage", Score: 0.8},
+		{EpisodeID: long.ID, ChunkIndex: 4, Content: "next passage", Score: 0.6},
+	}}
+	svc.SetChunkStore(chunkStore)
+
+	results, err := svc.Recall(ctx, agentID, tenantID, EpisodeRecallOpts{Query: "integration stage", Limit: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected both chunk-matched episodes, got %d", len(results))
+	}
+	if results[0].ID != long.ID || results[0].Passage != "best passage" || results[0].RawContent != long.RawContent {
+		t.Fatalf("expected the long episode first with its best passage, got %+v", results[0])
+	}
+	if want := float32(0.8 + 0.6*chunkSupportDecay); results[0].Score < want-0.001 || results[0].Score > want+0.001 {
+		t.Fatalf("expected aggregate score %.3f, got %.3f", want, results[0].Score)
+	}
+
+	results, _ = svc.Recall(ctx, agentID, tenantID, EpisodeRecallOpts{Query: "integration stage", Limit: 1})
+	if len(results) != 1 || results[0].ID != long.ID {
+		t.Fatalf("expected the limit to keep only the best episode, got %+v", results)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidParticipant = errors.New("invalid participant")
+
+// SetEntityStore links episode participants to person nodes in the agent's
+// entity graph. Without it participants are stored by name only.
+func (s *EpisodeService) SetEntityStore(es domain.EntityStore) {
+	s.entityStore = es
+}
+
+// ValidateParticipants checks there are at most domain.MaxEpisodeParticipants,
+// each named and with, if given, a known role.
+func ValidateParticipants(ps []domain.EpisodeParticipant) error {
+	if len(ps) > domain.MaxEpisodeParticipants {
+		return fmt.Errorf("%w: at most %d participants per episode", ErrInvalidParticipant, domain.MaxEpisodeParticipants)
+	}
+	for i, p := range ps {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("%w: participants[%d].name is required", ErrInvalidParticipant, i)
+		}
+		if p.Role != "" && !domain.ValidParticipantRole(string(p.Role)) {
+			return fmt.Errorf("%w: participants[%d].role must be user, agent or third_party", ErrInvalidParticipant, i)
+		}
+	}
+	return nil
+}
+
+// normalizeParticipants trims names, defaults the role to third_party and
+// merges repeats of a name (case-insensitively), keeping the first role and
+// whether any repeat spoke.
+func normalizeParticipants(ps []domain.EpisodeParticipant) []domain.EpisodeParticipant {
+	var out []domain.EpisodeParticipant
+	index := make(map[string]int, len(ps))
+	for _, p := range ps {
+		p.Name = strings.TrimSpace(p.Name)
+		if p.Role == "" {
+			p.Role = domain.ParticipantThirdParty
+		}
+		key := strings.ToLower(p.Name)
+		if i, ok := index[key]; ok {
+			out[i].Speaking = out[i].Speaking || p.Speaking
+			if out[i].EntityID == nil {
+				out[i].EntityID = p.EntityID
+			}
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, p)
+	}
+	return out
+}
+
+// resolveParticipants points each participant without an entity at the
+// agent's person node of that name, creating it when missing. Names match
+// exactly or by alias only: two people with similar names are still two
+// people. A participant that can't be resolved keeps no entity.
+func (s *EpisodeService) resolveParticipants(ctx context.Context, agentID, tenantID uuid.UUID, ps []domain.EpisodeParticipant) {
+	if s.entityStore == nil {
+		return
+	}
+	for i := range ps {
+		if ps[i].EntityID != nil {
+			continue
+		}
+		entity, err := s.entityStore.FindByNameOrAlias(ctx, agentID, ps[i].Name)
+		if err != nil || entity == nil {
+			entity = &domain.Entity{
+				AgentID:    agentID,
+				TenantID:   tenantID,
+				Name:       ps[i].Name,
+				EntityType: domain.EntityPerson,
+				Aliases:    []string{},
+				Metadata:   map[string]any{"participant_role": string(ps[i].Role)},
+			}
+			if err := s.entityStore.Create(ctx, entity); err != nil {
+				logFor(ctx, s.logger).Warn("failed to create participant entity",
+					zap.String("participant", ps[i].Name), zap.Error(err))
+				continue
+			}
+		}
+		id := entity.ID
+		ps[i].EntityID = &id
+	}
+}
+
+// beliefSubject resolves an extracted belief's subject against the episode's
+// participants. "user" and "assistant" name the one participant in that role
+// (or, for "user", the sole speaker, whose words extraction sees as the
+// user's); a participant's name picks up their entity. Anything else is kept
+// as extracted, without an entity.
+func beliefSubject(ep *domain.Episode, extracted string) (string, *uuid.UUID) {
+	subject := domain.NormalizeSubject(extracted)
+	if subject == "" || len(ep.Participants) == 0 {
+		return subject, nil
+	}
+	var match *domain.EpisodeParticipant
+	switch subject {
+	case domain.SubjectUser, domain.SubjectAssistant:
+		role := domain.ParticipantUser
+		if subject == domain.SubjectAssistant {
+			role = domain.ParticipantAgent
+		}
+		var inRole []domain.EpisodeParticipant
+		for _, p := range ep.Participants {
+			if p.Role == role {
+				inRole = append(inRole, p)
+			}
+		}
+		if speakers := ep.Speakers(); len(inRole) != 1 && subject == domain.SubjectUser && len(speakers) == 1 {
+			inRole = speakers
+		}
+		if len(inRole) == 1 {
+			match = &inRole[0]
+		}
+	default:
+		for i := range ep.Participants {
+			if strings.EqualFold(ep.Participants[i].Name, subject) {
+				match = &ep.Participants[i]
+				break
+			}
+		}
+	}
+	if match == nil {
+		return subject, nil
+	}
+	return match.Name, match.EntityID
+}
+
+// participantPreamble introduces an episode's participants to belief
+// extraction, so a belief names the person it is about rather than "the
+// user". Empty when the episode records none.
+func participantPreamble(ep *domain.Episode) string {
+	if len(ep.Participants) == 0 {
+		return ""
+	}
+	parts := make([]string, len(ep.Participants))
+	for i, p := range ep.Participants {
+		parts[i] = fmt.Sprintf("%s (%s", p.Name, p.Role)
+		if p.Speaking {
+			parts[i] += ", speaking"
+		}
+		parts[i] += ")"
+	}
+	return "Participants: " + strings.Join(parts, ", ") + "\n\n"
+}
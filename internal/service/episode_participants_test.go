@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestEpisodeService_EncodeLinksParticipantsToEntities(t *testing.T) {
+	svc, episodeStore, tenantID, agentID := setupEpisodeTest()
+	entityStore := newMockEntityStore()
+	existing := &domain.Entity{AgentID: agentID, Name: "Alice", EntityType: domain.EntityPerson}
+	_ = entityStore.Create(context.Background(), existing)
+	svc.SetEntityStore(entityStore)
+
+	episode, err := svc.Encode(context.Background(), EncodeInput{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		RawContent: "I'm allergic to peanuts",
+		Participants: []domain.EpisodeParticipant{
+			{Name: " Alice ", Role: domain.ParticipantUser, Speaking: true},
+			{Name: "Dr. Rao"},
+			{Name: "alice"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored := episodeStore.episodes[episode.ID].Participants
+	if len(stored) != 2 {
+		t.Fatalf("expected repeated names to merge into 2 participants, got %+v", stored)
+	}
+	if stored[0].EntityID == nil || *stored[0].EntityID != existing.ID || !stored[0].Speaking {
+		t.Errorf("expected Alice linked to her existing entity and speaking, got %+v", stored[0])
+	}
+	if stored[1].Role != domain.ParticipantThirdParty || stored[1].EntityID == nil {
+		t.Errorf("expected Dr. Rao as a third party with a new entity, got %+v", stored[1])
+	}
+	if e := entityStore.entities[*stored[1].EntityID]; e.EntityType != domain.EntityPerson {
+		t.Errorf("expected a person entity, got %s", e.EntityType)
+	}
+}
+
+func TestEpisodeService_IngestTranscriptMarksTurnSpeakers(t *testing.T) {
+	svc, _, tenantID, agentID := setupEpisodeTest()
+	entityStore := newMockEntityStore()
+	svc.SetEntityStore(entityStore)
+
+	_, episodes, err := svc.IngestTranscript(context.Background(), TranscriptInput{
+		AgentID:  agentID,
+		TenantID: tenantID,
+		Segments: []domain.TranscriptSegment{
+			{Speaker: "Alice", Start: 0, End: 2, Text: "I moved to Berlin."},
+			{Speaker: "Concierge", Start: 2, End: 4, Text: "Congratulations!"},
+		},
+		Participants: []domain.EpisodeParticipant{
+			{Name: "Alice", Role: domain.ParticipantUser},
+			{Name: "Concierge", Role: domain.ParticipantAgent},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entityStore.entities) != 2 {
+		t.Fatalf("expected one entity per participant across turns, got %d", len(entityStore.entities))
+	}
+	for i, speaker := range []string{"Alice", "Concierge"} {
+		speakers := episodes[i].Speakers()
+		if len(episodes[i].Participants) != 2 || len(speakers) != 1 || speakers[0].Name != speaker {
+			t.Errorf("turn %d: expected %s speaking among 2 participants, got %+v", i, speaker, episodes[i].Participants)
+		}
+	}
+}
+
+func TestValidateParticipants(t *testing.T) {
+	tooMany := make([]domain.EpisodeParticipant, domain.MaxEpisodeParticipants+1)
+	for i := range tooMany {
+		tooMany[i].Name = "p"
+	}
+	for name, ps := range map[string][]domain.EpisodeParticipant{
+		"blank name":   {{Name: " "}},
+		"unknown role": {{Name: "Alice", Role: "customer"}},
+		"too many":     tooMany,
+	} {
+		if err := ValidateParticipants(ps); !errors.Is(err, ErrInvalidParticipant) {
+			t.Errorf("%s: expected ErrInvalidParticipant, got %v", name, err)
+		}
+	}
+}
+
+func TestConsolidationService_ExtractSemanticBeliefsAttributesSpeaker(t *testing.T) {
+	agentID := uuid.New()
+	tenantID := uuid.New()
+	aliceID := uuid.New()
+
+	memStore := newMockMemoryStoreForConsolidation()
+	episodeStore := newMockEpisodeStoreForConsolidation()
+	episodeStore.episodes = []domain.Episode{{
+		ID:                  uuid.New(),
+		AgentID:             agentID,
+		TenantID:            tenantID,
+		RawContent:          "Alice: I'm vegetarian",
+		ConsolidationStatus: domain.ConsolidationProcessed,
+		ImportanceScore:     0.8,
+		CreatedAt:           time.Now(),
+		Participants: []domain.EpisodeParticipant{
+			{Name: "Alice", Role: domain.ParticipantUser, Speaking: true, EntityID: &aliceID},
+			{Name: "Bob", Role: domain.ParticipantThirdParty},
+		},
+	}}
+	entityStore := newMockEntityStore()
+	llm := &mockLLMClient{extractResult: []domain.ExtractedMemory{
+		{Type: domain.MemoryTypePreference, Content: "Alice is vegetarian", Subject: "user", Confidence: 0.9},
+	}}
+
+	svc := NewConsolidationService(memStore, episodeStore, nil, nil, &mockAssocStoreForConsolidation{}, nil, nil, llm, zap.NewNop())
+	svc.SetEntityStore(entityStore)
+	if result := svc.extractSemanticBeliefs(context.Background(), agentID, tenantID); result.extracted != 1 {
+		t.Fatalf("expected 1 belief, got %d", result.extracted)
+	}
+
+	mem := memStore.memories[0]
+	if mem.Metadata[domain.AttributedToKey] != "Alice" || mem.Metadata[domain.AttributedEntityIDKey] != aliceID.String() {
+		t.Errorf("expected the belief attributed to Alice, got %v", mem.Metadata)
+	}
+	if mem.Subject != "Alice" || mem.SubjectID == nil || *mem.SubjectID != aliceID {
+		t.Errorf("expected the belief to be about Alice, got %q %v", mem.Subject, mem.SubjectID)
+	}
+	mentions := entityStore.mentions[aliceID]
+	if len(mentions) != 1 || mentions[0].MemoryID != mem.ID || mentions[0].MentionType != domain.MentionSubject {
+		t.Errorf("expected a subject mention of Alice on the belief, got %+v", mentions)
+	}
+}
+
+func TestParticipantPreamble(t *testing.T) {
+	ep := &domain.Episode{Participants: []domain.EpisodeParticipant{
+		{Name: "Alice", Role: domain.ParticipantUser, Speaking: true},
+		{Name: "Bot", Role: domain.ParticipantAgent},
+	}}
+	got := participantPreamble(ep)
+	if !strings.HasPrefix(got, "Participants: Alice (user, speaking), Bot (agent)") {
+		t.Fatalf("unexpected preamble %q", got)
+	}
+	if participantPreamble(&domain.Episode{}) != "" {
+		t.Fatal("expected no preamble without participants")
+	}
+}
+
+func TestBeliefSubject(t *testing.T) {
+	aliceID, botID := uuid.New(), uuid.New()
+	ep := &domain.Episode{Participants: []domain.EpisodeParticipant{
+		{Name: "Alice", Role: domain.ParticipantUser, EntityID: &aliceID},
+		{Name: "Bot", Role: domain.ParticipantAgent, EntityID: &botID},
+		{Name: "Dr. Rao", Role: domain.ParticipantThirdParty},
+	}}
+	cases := []struct {
+		extracted string
+		subject   string
+		entity    *uuid.UUID
+	}{
+		{"the user", "Alice", &aliceID},
+		{"assistant", "Bot", &botID},
+		{"dr. rao", "Dr. Rao", nil},
+		{"Lisbon", "Lisbon", nil},
+		{"", "", nil},
+	}
+	for _, c := range cases {
+		subject, entity := beliefSubject(ep, c.extracted)
+		if subject != c.subject || (entity == nil) != (c.entity == nil) || (entity != nil && *entity != *c.entity) {
+			t.Errorf("%q: expected %q %v, got %q %v", c.extracted, c.subject, c.entity, subject, entity)
+		}
+	}
+
+	subject, entity := beliefSubject(&domain.Episode{}, "user")
+	if subject != domain.SubjectUser || entity != nil {
+		t.Errorf("expected user without participants, got %q %v", subject, entity)
+	}
+}
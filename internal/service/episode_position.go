@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EpisodePositionService weights the first and last episodes of a
+// conversation up (primacy and recency), with per-agent weights. Episode
+// recall multiplies their score by the weight and consolidation their
+// importance.
+type EpisodePositionService struct {
+	ends          domain.ConversationEndsStore
+	settingsStore domain.EpisodePositionSettingsStore
+	logger        *zap.Logger
+}
+
+func NewEpisodePositionService(ends domain.ConversationEndsStore, logger *zap.Logger) *EpisodePositionService {
+	return &EpisodePositionService{ends: ends, logger: logger}
+}
+
+// SetSettingsStore enables per-agent weights. Without it every agent uses
+// domain.DefaultEpisodePositionSettings.
+func (s *EpisodePositionService) SetSettingsStore(ss domain.EpisodePositionSettingsStore) {
+	s.settingsStore = ss
+}
+
+// GetSettings returns the agent's weights, or the defaults if none are
+// stored.
+func (s *EpisodePositionService) GetSettings(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.EpisodePositionSettings, error) {
+	if s.settingsStore != nil {
+		settings, err := s.settingsStore.Get(ctx, agentID, tenantID)
+		if err == nil {
+			return settings, nil
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+	}
+	d := domain.DefaultEpisodePositionSettings()
+	d.AgentID, d.TenantID = agentID, tenantID
+	return &d, nil
+}
+
+// UpdateSettings stores the agent's weights. Returns
+// domain.ErrInvalidPositionWeight for a weight out of bounds.
+func (s *EpisodePositionService) UpdateSettings(ctx context.Context, settings *domain.EpisodePositionSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if s.settingsStore == nil {
+		return errors.New("episode position settings are not configured")
+	}
+	if err := s.settingsStore.Upsert(ctx, settings); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrAgentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Factors returns the position factor of each episode that opens or closes
+// its conversation. Episodes outside a conversation, or in its middle, are
+// absent. A failed lookup weights nothing.
+func (s *EpisodePositionService) Factors(ctx context.Context, agentID, tenantID uuid.UUID, episodes []domain.Episode) map[uuid.UUID]float32 {
+	factors := make(map[uuid.UUID]float32)
+	if s == nil || s.ends == nil {
+		return factors
+	}
+	seen := make(map[uuid.UUID]bool)
+	var convIDs []uuid.UUID
+	for _, ep := range episodes {
+		if ep.ConversationID != nil && !seen[*ep.ConversationID] {
+			seen[*ep.ConversationID] = true
+			convIDs = append(convIDs, *ep.ConversationID)
+		}
+	}
+	if len(convIDs) == 0 {
+		return factors
+	}
+
+	settings, err := s.GetSettings(ctx, agentID, tenantID)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to load episode position settings", zap.Error(err))
+		d := domain.DefaultEpisodePositionSettings()
+		settings = &d
+	}
+	ends, err := s.ends.GetConversationEnds(ctx, tenantID, convIDs)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to find conversation ends", zap.Error(err))
+		return factors
+	}
+	for _, ep := range episodes {
+		if ep.ConversationID == nil {
+			continue
+		}
+		if f := settings.Factor(ep.ID, ends[*ep.ConversationID]); f != 1 {
+			factors[ep.ID] = f
+		}
+	}
+	return factors
+}
+
+// WeighImportance raises the importance of the episodes that open or close
+// their conversation, up to 1. Only the copies in episodes change.
+func (s *EpisodePositionService) WeighImportance(ctx context.Context, agentID, tenantID uuid.UUID, episodes []domain.Episode) {
+	factors := s.Factors(ctx, agentID, tenantID, episodes)
+	for i := range episodes {
+		f, ok := factors[episodes[i].ID]
+		if !ok {
+			continue
+		}
+		episodes[i].ImportanceScore *= f
+		if episodes[i].ImportanceScore > 1 {
+			episodes[i].ImportanceScore = 1
+		}
+	}
+}
+
+// WeighRecall multiplies the recall score of the episodes that open or close
+// their conversation and re-ranks the results.
+func (s *EpisodePositionService) WeighRecall(ctx context.Context, agentID, tenantID uuid.UUID, results []domain.EpisodeWithScore) {
+	episodes := make([]domain.Episode, len(results))
+	for i, r := range results {
+		episodes[i] = r.Episode
+	}
+	factors := s.Factors(ctx, agentID, tenantID, episodes)
+	if len(factors) == 0 {
+		return
+	}
+	for i := range results {
+		if f, ok := factors[results[i].ID]; ok {
+			results[i].Score *= f
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
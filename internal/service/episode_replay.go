@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// SetProcedureStore lets Replay include procedures derived from episodes.
+func (s *EpisodeService) SetProcedureStore(ps domain.ProcedureStore) {
+	s.procedureStore = ps
+}
+
+// SetMemoryUsageStore lets Replay include memories used during episodes.
+func (s *EpisodeService) SetMemoryUsageStore(us domain.EpisodeMemoryUsageStore) {
+	s.usageStore = us
+}
+
+// Replay reconstructs what the agent learned from a conversation: its
+// episodes, the memories used and derived, associations to other episodes and
+// recorded outcomes, in time order. Derived items that have since been deleted
+// are left out.
+func (s *EpisodeService) Replay(ctx context.Context, conversationID, tenantID uuid.UUID) (*domain.ConversationReplay, error) {
+	episodes, err := s.episodeStore.GetByConversationID(ctx, conversationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(episodes) == 0 {
+		return nil, ErrConversationNotFound
+	}
+
+	replay := &domain.ConversationReplay{ConversationID: conversationID, Episodes: len(episodes)}
+	seenAssoc := make(map[uuid.UUID]bool)
+	for i := range episodes {
+		ep := &episodes[i]
+		replay.Events = append(replay.Events, domain.ReplayEvent{
+			Kind: domain.ReplayEpisode, At: ep.OccurredAt, EpisodeID: ep.ID, Episode: ep,
+		})
+
+		if s.usageStore != nil {
+			usages, err := s.usageStore.GetByEpisodeID(ctx, ep.ID)
+			if err != nil {
+				return nil, err
+			}
+			for j := range usages {
+				u := &usages[j]
+				ev := domain.ReplayEvent{Kind: domain.ReplayMemoryUsed, At: u.CreatedAt, EpisodeID: ep.ID, Usage: u}
+				if s.memoryStore != nil {
+					if mem, err := s.memoryStore.GetByID(ctx, u.MemoryID, tenantID); err == nil {
+						ev.Memory = mem
+					}
+				}
+				replay.Events = append(replay.Events, ev)
+			}
+		}
+
+		if s.memoryStore != nil {
+			for _, id := range ep.DerivedSemanticIDs {
+				mem, err := s.memoryStore.GetByID(ctx, id, tenantID)
+				if err != nil {
+					continue
+				}
+				replay.Events = append(replay.Events, domain.ReplayEvent{
+					Kind: domain.ReplayMemoryDerived, At: mem.CreatedAt, EpisodeID: ep.ID, Memory: mem,
+				})
+			}
+		}
+		if s.procedureStore != nil {
+			for _, id := range ep.DerivedProceduralIDs {
+				proc, err := s.procedureStore.GetByID(ctx, id, tenantID)
+				if err != nil {
+					continue
+				}
+				replay.Events = append(replay.Events, domain.ReplayEvent{
+					Kind: domain.ReplayProcedureDerived, At: proc.CreatedAt, EpisodeID: ep.ID, Procedure: proc,
+				})
+			}
+		}
+
+		assocs, err := s.episodeStore.GetAssociations(ctx, ep.ID)
+		if err != nil {
+			return nil, err
+		}
+		for j := range assocs {
+			a := &assocs[j]
+			// Links between two episodes of the conversation are listed once.
+			if seenAssoc[a.ID] {
+				continue
+			}
+			seenAssoc[a.ID] = true
+			replay.Events = append(replay.Events, domain.ReplayEvent{
+				Kind: domain.ReplayAssociation, At: a.CreatedAt, EpisodeID: ep.ID, Association: a,
+			})
+		}
+
+		// Outcomes carry no timestamp of their own; the episode's last update
+		// is when it was recorded.
+		if ep.Outcome != "" && ep.Outcome != domain.OutcomeUnknown {
+			replay.Events = append(replay.Events, domain.ReplayEvent{
+				Kind: domain.ReplayOutcome, At: ep.UpdatedAt, EpisodeID: ep.ID,
+				Outcome: &domain.EpisodeOutcome{Outcome: ep.Outcome, Description: ep.OutcomeDescription, Valence: ep.OutcomeValence},
+			})
+		}
+	}
+
+	sort.SliceStable(replay.Events, func(i, j int) bool { return replay.Events[i].At.Before(replay.Events[j].At) })
+	return replay, nil
+}
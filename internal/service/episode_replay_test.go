@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestEpisodeService_Replay(t *testing.T) {
+	ctx := context.Background()
+	tenantID, agentID, convID := uuid.New(), uuid.New(), uuid.New()
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	memStore := newMockMemoryStore()
+	derived := &domain.Memory{TenantID: tenantID, AgentID: agentID, Content: "User deploys on Fridays"}
+	_ = memStore.Create(ctx, derived)
+	derived.CreatedAt = t0.Add(3 * time.Hour)
+
+	episodes := newMockEpisodeStore()
+	first := &domain.Episode{
+		AgentID: agentID, TenantID: tenantID, ConversationID: &convID, RawContent: "we ship on Friday",
+		DerivedSemanticIDs: []uuid.UUID{derived.ID, uuid.New()},
+		Outcome:            domain.OutcomeSuccess,
+	}
+	second := &domain.Episode{AgentID: agentID, TenantID: tenantID, ConversationID: &convID, RawContent: "rollback plan?"}
+	_ = episodes.Create(ctx, first)
+	_ = episodes.Create(ctx, second)
+	first.OccurredAt, second.OccurredAt = t0, t0.Add(time.Hour)
+	first.UpdatedAt = t0.Add(4 * time.Hour)
+	episodes.associations = append(episodes.associations, domain.EpisodeAssociation{
+		ID: uuid.New(), EpisodeAID: first.ID, EpisodeBID: second.ID, AssociationType: domain.AssociationTemporal, CreatedAt: t0.Add(2 * time.Hour),
+	})
+
+	svc := NewEpisodeService(episodes, nil, nil, nil, zap.NewNop())
+	svc.SetMemoryStore(memStore)
+
+	replay, err := svc.Replay(ctx, convID, tenantID)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replay.Episodes != 2 {
+		t.Errorf("expected 2 episodes, got %d", replay.Episodes)
+	}
+
+	// The deleted derived memory is skipped and the shared association is
+	// listed once.
+	want := []domain.ReplayEventKind{
+		domain.ReplayEpisode, domain.ReplayEpisode, domain.ReplayAssociation, domain.ReplayMemoryDerived, domain.ReplayOutcome,
+	}
+	if len(replay.Events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(replay.Events), replay.Events)
+	}
+	for i, kind := range want {
+		if replay.Events[i].Kind != kind {
+			t.Errorf("event %d: expected %s, got %s", i, kind, replay.Events[i].Kind)
+		}
+	}
+	if replay.Events[3].Memory.ID != derived.ID {
+		t.Errorf("expected derived memory %s", derived.ID)
+	}
+}
+
+func TestEpisodeService_Replay_UnknownConversation(t *testing.T) {
+	svc := NewEpisodeService(newMockEpisodeStore(), nil, nil, nil, zap.NewNop())
+	if _, err := svc.Replay(context.Background(), uuid.New(), uuid.New()); err != ErrConversationNotFound {
+		t.Errorf("expected ErrConversationNotFound, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
@@ -92,6 +93,25 @@ func (m *mockEpisodeStore) GetUnconsolidated(ctx context.Context, agentID uuid.U
 	return results, nil
 }
 
+func (m *mockEpisodeStore) GetReplayCandidates(ctx context.Context, agentID uuid.UUID, limit int) ([]domain.Episode, error) {
+	var raw []domain.Episode
+	for _, e := range m.episodes {
+		if e.AgentID == agentID && e.ConsolidationStatus == domain.ConsolidationRaw {
+			raw = append(raw, *e)
+		}
+	}
+	return replayCandidates(raw, limit), nil
+}
+
+// replayCandidates mirrors EpisodeStore.GetReplayCandidates over raw.
+func replayCandidates(raw []domain.Episode, limit int) []domain.Episode {
+	sort.SliceStable(raw, func(i, j int) bool { return raw[i].OccurredAt.Before(raw[j].OccurredAt) })
+	if len(raw) <= 2*limit {
+		return raw
+	}
+	return append(raw[:limit:limit], raw[len(raw)-limit:]...)
+}
+
 func (m *mockEpisodeStore) GetByConsolidationStatus(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, status domain.ConsolidationStatus, limit int) ([]domain.Episode, error) {
 	var results []domain.Episode
 	for _, e := range m.episodes {
@@ -128,7 +148,15 @@ func (m *mockEpisodeStore) LinkDerivedMemory(ctx context.Context, episodeID uuid
 }
 
 func (m *mockEpisodeStore) ApplyDecay(ctx context.Context, agentID uuid.UUID) (int64, error) {
-	return 0, nil
+	var n int64
+	for _, e := range m.episodes {
+		if e.AgentID != agentID || e.ConsolidationStatus == domain.ConsolidationArchived {
+			continue
+		}
+		e.MemoryStrength = e.DecayedStrength(timeNow().Sub(e.LastAccessedAt).Hours() / 24)
+		n++
+	}
+	return n, nil
 }
 
 func (m *mockEpisodeStore) GetByAgentForDecay(ctx context.Context, agentID uuid.UUID) ([]domain.Episode, error) {
@@ -416,3 +444,106 @@ func TestExtractTimeOfDay(t *testing.T) {
 		}
 	}
 }
+
+// GetConversationEnds mirrors EpisodeStore.GetConversationEnds for episodes
+// with a message sequence.
+func (m *mockEpisodeStore) GetConversationEnds(ctx context.Context, tenantID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]domain.ConversationEnds, error) {
+	ends := make(map[uuid.UUID]domain.ConversationEnds)
+	for _, convID := range conversationIDs {
+		eps, _ := m.GetByConversationID(ctx, convID, tenantID)
+		if len(eps) == 0 {
+			continue
+		}
+		sort.Slice(eps, func(i, j int) bool { return *eps[i].MessageSequence < *eps[j].MessageSequence })
+		ends[convID] = domain.ConversationEnds{First: eps[0].ID, Last: eps[len(eps)-1].ID, Episodes: len(eps)}
+	}
+	return ends, nil
+}
+
+// stubPositionSettingsStore serves one agent's stored weights.
+type stubPositionSettingsStore struct {
+	settings *domain.EpisodePositionSettings
+}
+
+func (s *stubPositionSettingsStore) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.EpisodePositionSettings, error) {
+	if s.settings == nil {
+		return nil, store.ErrNotFound
+	}
+	return s.settings, nil
+}
+
+func (s *stubPositionSettingsStore) Upsert(ctx context.Context, ps *domain.EpisodePositionSettings) error {
+	s.settings = ps
+	return nil
+}
+
+func TestEpisodeService_RecallWeighsConversationEnds(t *testing.T) {
+	svc, episodeStore, tenantID, agentID := setupEpisodeTest()
+	ctx := context.Background()
+	settings := &stubPositionSettingsStore{}
+	positions := NewEpisodePositionService(episodeStore, testLogger())
+	positions.SetSettingsStore(settings)
+	svc.SetPositionWeighting(positions)
+
+	convID := uuid.New()
+	var ids []uuid.UUID
+	for seq := 1; seq <= 4; seq++ {
+		ep := &domain.Episode{AgentID: agentID, TenantID: tenantID, ConversationID: &convID,
+			MessageSequence: &seq, RawContent: "turn", ImportanceScore: 0.7}
+		_ = episodeStore.Create(ctx, ep)
+		ids = append(ids, ep.ID)
+	}
+	scores := func() map[uuid.UUID]float32 {
+		results, err := svc.Recall(ctx, agentID, tenantID, EpisodeRecallOpts{MinImportance: 0.5})
+		if err != nil {
+			t.Fatalf("Recall failed: %v", err)
+		}
+		out := make(map[uuid.UUID]float32)
+		for _, r := range results {
+			out[r.ID] = r.Score
+		}
+		return out
+	}
+
+	got := scores()
+	want := float32(0.7 * (1 + domain.DefaultPrimacyWeight))
+	if !approxEqual(got[ids[0]], want, 0.0001) || !approxEqual(got[ids[3]], want, 0.0001) {
+		t.Errorf("expected the first and last episodes at %v, got %v and %v", want, got[ids[0]], got[ids[3]])
+	}
+	if got[ids[1]] != 0.7 || got[ids[2]] != 0.7 {
+		t.Errorf("middle episodes should keep their score, got %v and %v", got[ids[1]], got[ids[2]])
+	}
+
+	if err := positions.UpdateSettings(ctx, &domain.EpisodePositionSettings{AgentID: agentID, TenantID: tenantID, PrimacyWeight: 0.5}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	got = scores()
+	if !approxEqual(got[ids[0]], 0.7*1.5, 0.0001) || got[ids[3]] != 0.7 {
+		t.Errorf("expected the agent's weights (primacy only), got %v and %v", got[ids[0]], got[ids[3]])
+	}
+
+	if err := positions.UpdateSettings(ctx, &domain.EpisodePositionSettings{PrimacyWeight: 1.5}); err != domain.ErrInvalidPositionWeight {
+		t.Errorf("expected ErrInvalidPositionWeight, got %v", err)
+	}
+}
+
+func TestEpisodePositionService_WeighImportanceCapsAtOne(t *testing.T) {
+	episodeStore := newMockEpisodeStore()
+	positions := NewEpisodePositionService(episodeStore, testLogger())
+	tenantID, agentID, convID := uuid.New(), uuid.New(), uuid.New()
+	for seq, importance := range []float32{0.9, 0.5, 0.5} {
+		_ = episodeStore.Create(context.Background(), &domain.Episode{AgentID: agentID, TenantID: tenantID,
+			ConversationID: &convID, MessageSequence: &seq, ImportanceScore: importance})
+	}
+	episodes, _ := episodeStore.GetByConversationID(context.Background(), convID, tenantID)
+	sort.Slice(episodes, func(i, j int) bool { return *episodes[i].MessageSequence < *episodes[j].MessageSequence })
+
+	positions.WeighImportance(context.Background(), agentID, tenantID, episodes)
+	if episodes[0].ImportanceScore != 1 || episodes[1].ImportanceScore != 0.5 ||
+		!approxEqual(episodes[2].ImportanceScore, 0.6, 0.0001) {
+		t.Errorf("got importances %v, %v, %v", episodes[0].ImportanceScore, episodes[1].ImportanceScore, episodes[2].ImportanceScore)
+	}
+	if stored := episodeStore.episodes[episodes[2].ID].ImportanceScore; stored != 0.5 {
+		t.Errorf("weighting must not change the stored episode, got %v", stored)
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTranscriptEmpty          = errors.New("segments are required")
+	ErrInvalidTranscriptSegment = errors.New("invalid transcript segment")
+	ErrTranscriptionDisabled    = errors.New("audio transcription is not configured")
+)
+
+const (
+	// MaxTranscriptSegments bounds one transcript.
+	MaxTranscriptSegments = 5000
+	// DefaultTranscriptSpeaker labels segments that name no speaker.
+	DefaultTranscriptSpeaker = "Speaker"
+	// maxTurnSeconds splits a long monologue into several episodes, so one
+	// speaker holding the floor doesn't become a single hour-long episode.
+	maxTurnSeconds = 300
+)
+
+// SetTranscriber enables audio uploads: recordings are transcribed before
+// ingestion.
+func (s *EpisodeService) SetTranscriber(t domain.Transcriber) {
+	s.transcriber = t
+}
+
+// TranscriptInput is a transcript to store as episodes.
+type TranscriptInput struct {
+	AgentID  uuid.UUID
+	TenantID uuid.UUID
+	// ConversationID groups the transcript's episodes; nil starts a new
+	// conversation.
+	ConversationID *uuid.UUID
+	// StartedAt is when the recording began. Zero assumes it ended now.
+	StartedAt time.Time
+	// Speaker labels segments that name none; empty uses
+	// DefaultTranscriptSpeaker.
+	Speaker  string
+	Segments []domain.TranscriptSegment
+	// Participants gives speakers their roles; a speaker not listed is a
+	// third party. Listed people who never speak are recorded as present.
+	Participants []domain.EpisodeParticipant
+}
+
+// ValidateTranscript checks segments are present, bounded in number, and each
+// has text and a non-negative time span.
+func ValidateTranscript(segments []domain.TranscriptSegment) error {
+	if len(segments) == 0 {
+		return ErrTranscriptEmpty
+	}
+	if len(segments) > MaxTranscriptSegments {
+		return fmt.Errorf("%w: at most %d segments", ErrInvalidTranscriptSegment, MaxTranscriptSegments)
+	}
+	for i, seg := range segments {
+		if strings.TrimSpace(seg.Text) == "" {
+			return fmt.Errorf("%w: segments[%d].text is required", ErrInvalidTranscriptSegment, i)
+		}
+		if seg.Start < 0 || seg.End < seg.Start {
+			return fmt.Errorf("%w: segments[%d] must have 0 <= start <= end", ErrInvalidTranscriptSegment, i)
+		}
+	}
+	return nil
+}
+
+// TranscribeAudio turns a recording into segments with the configured
+// transcriber.
+func (s *EpisodeService) TranscribeAudio(ctx context.Context, audio io.Reader, filename string) ([]domain.TranscriptSegment, error) {
+	if s.transcriber == nil {
+		return nil, ErrTranscriptionDisabled
+	}
+	return s.transcriber.Transcribe(ctx, audio, filename)
+}
+
+// transcriptTurn is consecutive speech by one speaker.
+type transcriptTurn struct {
+	speaker    string
+	start, end float64
+	text       []string
+}
+
+// IngestTranscript stores a transcript as one episode per speaker turn:
+// consecutive segments by the same speaker, split once a turn passes
+// maxTurnSeconds. Each episode's content is attributed to its speaker, its
+// occurred_at is StartedAt plus the turn's start offset, its duration is the
+// turn's length and its message_sequence is the turn's position. Every
+// episode lists the transcript's participants with the turn's speaker marked
+// as speaking. The episodes share a conversation. Episodes that fail to store are logged and left out;
+// an error is returned only when none could be stored.
+func (s *EpisodeService) IngestTranscript(ctx context.Context, in TranscriptInput) (*uuid.UUID, []*domain.Episode, error) {
+	if err := ValidateTranscript(in.Segments); err != nil {
+		return nil, nil, err
+	}
+	if err := ValidateParticipants(in.Participants); err != nil {
+		return nil, nil, err
+	}
+	turns := transcriptTurns(in.Segments, in.Speaker)
+	roster := s.transcriptRoster(ctx, in, turns)
+
+	started := in.StartedAt
+	if started.IsZero() {
+		var end float64
+		for _, t := range turns {
+			end = math.Max(end, t.end)
+		}
+		started = timeNow().Add(-secondsDuration(end))
+	}
+	convID := in.ConversationID
+	if convID == nil {
+		id := uuid.New()
+		convID = &id
+	}
+
+	inputs := make([]EncodeInput, len(turns))
+	for i, t := range turns {
+		seq := i
+		duration := int(math.Ceil(t.end - t.start))
+		inputs[i] = EncodeInput{
+			AgentID:         in.AgentID,
+			TenantID:        in.TenantID,
+			RawContent:      t.speaker + ": " + strings.Join(t.text, " "),
+			ConversationID:  convID,
+			OccurredAt:      started.Add(secondsDuration(t.start)),
+			DurationSeconds: &duration,
+			MessageSequence: &seq,
+			Participants:    speakingAs(roster, t.speaker),
+		}
+	}
+
+	episodes, errs := s.EncodeBatch(ctx, inputs)
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(episodes) == 0 && len(failed) > 0 {
+		return nil, nil, failed[0]
+	}
+	if len(failed) > 0 {
+		logFor(ctx, s.logger).Warn("some transcript turns failed to store",
+			zap.Int("stored", len(episodes)), zap.Int("failed", len(failed)), zap.Error(errors.Join(failed...)))
+	}
+	return convID, episodes, nil
+}
+
+// transcriptRoster lists everyone in the transcript, given participants
+// first, and links them to entities once for all its episodes.
+func (s *EpisodeService) transcriptRoster(ctx context.Context, in TranscriptInput, turns []transcriptTurn) []domain.EpisodeParticipant {
+	roster := append([]domain.EpisodeParticipant(nil), in.Participants...)
+	for _, t := range turns {
+		roster = append(roster, domain.EpisodeParticipant{Name: t.speaker})
+	}
+	for i := range roster {
+		roster[i].Speaking = false
+	}
+	roster = normalizeParticipants(roster)
+	if len(roster) > domain.MaxEpisodeParticipants {
+		roster = roster[:domain.MaxEpisodeParticipants]
+	}
+	s.resolveParticipants(ctx, in.AgentID, in.TenantID, roster)
+	return roster
+}
+
+// speakingAs copies roster with speaker marked as speaking.
+func speakingAs(roster []domain.EpisodeParticipant, speaker string) []domain.EpisodeParticipant {
+	out := append([]domain.EpisodeParticipant(nil), roster...)
+	for i := range out {
+		out[i].Speaking = strings.EqualFold(out[i].Name, speaker)
+	}
+	return out
+}
+
+// transcriptTurns orders segments by start and merges consecutive ones by the
+// same speaker.
+func transcriptTurns(segments []domain.TranscriptSegment, defaultSpeaker string) []transcriptTurn {
+	if defaultSpeaker == "" {
+		defaultSpeaker = DefaultTranscriptSpeaker
+	}
+	sorted := append([]domain.TranscriptSegment(nil), segments...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var turns []transcriptTurn
+	for _, seg := range sorted {
+		speaker := strings.TrimSpace(seg.Speaker)
+		if speaker == "" {
+			speaker = defaultSpeaker
+		}
+		text := strings.TrimSpace(seg.Text)
+		if n := len(turns); n > 0 && turns[n-1].speaker == speaker && seg.End-turns[n-1].start <= maxTurnSeconds {
+			turns[n-1].text = append(turns[n-1].text, text)
+			turns[n-1].end = math.Max(turns[n-1].end, seg.End)
+			continue
+		}
+		turns = append(turns, transcriptTurn{speaker: speaker, start: seg.Start, end: seg.End, text: []string{text}})
+	}
+	return turns
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
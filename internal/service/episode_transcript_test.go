@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestEpisodeService_IngestTranscriptStoresOneEpisodePerTurn(t *testing.T) {
+	svc, episodeStore, tenantID, agentID := setupEpisodeTest()
+	started := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	convID, episodes, err := svc.IngestTranscript(context.Background(), TranscriptInput{
+		AgentID:   agentID,
+		TenantID:  tenantID,
+		StartedAt: started,
+		Segments: []domain.TranscriptSegment{
+			{Speaker: "Bob", Start: 4, End: 6.5, Text: "Thursday works."},
+			{Speaker: "Alice", Start: 0, End: 2, Text: "Can we move the review?"},
+			{Speaker: "Alice", Start: 2.1, End: 3.8, Text: "Maybe to Thursday."},
+			{Start: 7, End: 8, Text: "Great, done."},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(episodes) != 3 {
+		t.Fatalf("expected 3 turns, got %d", len(episodes))
+	}
+
+	want := []struct {
+		content  string
+		offset   time.Duration
+		duration int
+	}{
+		{"Alice: Can we move the review? Maybe to Thursday.", 0, 4},
+		{"Bob: Thursday works.", 4 * time.Second, 3},
+		{"Speaker: Great, done.", 7 * time.Second, 1},
+	}
+	for i, ep := range episodes {
+		stored := episodeStore.episodes[ep.ID]
+		if stored.RawContent != want[i].content {
+			t.Errorf("turn %d: expected content %q, got %q", i, want[i].content, stored.RawContent)
+		}
+		if !stored.OccurredAt.Equal(started.Add(want[i].offset)) {
+			t.Errorf("turn %d: expected occurred_at %v, got %v", i, started.Add(want[i].offset), stored.OccurredAt)
+		}
+		if stored.DurationSeconds == nil || *stored.DurationSeconds != want[i].duration {
+			t.Errorf("turn %d: expected duration %d, got %v", i, want[i].duration, stored.DurationSeconds)
+		}
+		if stored.MessageSequence == nil || *stored.MessageSequence != i {
+			t.Errorf("turn %d: expected sequence %d, got %v", i, i, stored.MessageSequence)
+		}
+		if stored.ConversationID == nil || *stored.ConversationID != *convID {
+			t.Errorf("turn %d: expected conversation %v, got %v", i, *convID, stored.ConversationID)
+		}
+	}
+}
+
+func TestEpisodeService_IngestTranscriptSplitsLongMonologue(t *testing.T) {
+	svc, _, tenantID, agentID := setupEpisodeTest()
+	convID := uuid.New()
+
+	var segments []domain.TranscriptSegment
+	for i := 0; i < 12; i++ {
+		start := float64(i * 60)
+		segments = append(segments, domain.TranscriptSegment{Start: start, End: start + 59, Text: "More of the lecture."})
+	}
+	got, episodes, err := svc.IngestTranscript(context.Background(), TranscriptInput{
+		AgentID:        agentID,
+		TenantID:       tenantID,
+		ConversationID: &convID,
+		Speaker:        "Lecturer",
+		Segments:       segments,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *got != convID {
+		t.Fatalf("expected the given conversation id, got %v", *got)
+	}
+	if len(episodes) < 3 {
+		t.Fatalf("expected a 12-minute monologue to be split into several turns, got %d", len(episodes))
+	}
+	if !strings.HasPrefix(episodes[0].RawContent, "Lecturer: ") {
+		t.Fatalf("expected default speaker attribution, got %q", episodes[0].RawContent)
+	}
+}
+
+func TestEpisodeService_TranscribeAudioWithoutTranscriber(t *testing.T) {
+	svc, _, _, _ := setupEpisodeTest()
+	if _, err := svc.TranscribeAudio(context.Background(), strings.NewReader("x"), "a.mp3"); !errors.Is(err, ErrTranscriptionDisabled) {
+		t.Fatalf("expected ErrTranscriptionDisabled, got %v", err)
+	}
+}
+
+func TestValidateTranscript(t *testing.T) {
+	if err := ValidateTranscript(nil); !errors.Is(err, ErrTranscriptEmpty) {
+		t.Errorf("expected ErrTranscriptEmpty, got %v", err)
+	}
+	for name, seg := range map[string]domain.TranscriptSegment{
+		"blank text":     {Start: 0, End: 1, Text: "  "},
+		"negative start": {Start: -1, End: 1, Text: "hi"},
+		"end before":     {Start: 5, End: 2, Text: "hi"},
+	} {
+		if err := ValidateTranscript([]domain.TranscriptSegment{seg}); !errors.Is(err, ErrInvalidTranscriptSegment) {
+			t.Errorf("%s: expected ErrInvalidTranscriptSegment, got %v", name, err)
+		}
+	}
+}
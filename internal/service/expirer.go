@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"sync"
+	"fmt"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -11,17 +11,26 @@ import (
 
 const defaultExpirerInterval = 1 * time.Hour
 
+// EpisodePartitionsAhead is how many months of episode partitions are kept
+// created ahead of the current one, so writes never fall into the default
+// partition under normal operation.
+const EpisodePartitionsAhead = 2
+
 type ExpirerService struct {
 	memoryStore   domain.MemoryStore
 	policyStore   domain.PolicyStore
 	feedbackStore domain.FeedbackStore
 	sessionStore  domain.SessionStore
+	idemStore     domain.IdempotencyStore
+	outboxStore   domain.OutboxStore
+	partitions    domain.EpisodePartitionStore
+	retention     domain.RetentionStore
+	agents        domain.AgentRegistry
+	embeddings    domain.EmbeddingCache
+	retainMonths  int
 	logger        *zap.Logger
 
-	interval   time.Duration
-	stopCh     chan struct{}
-	cancelRuns context.CancelFunc
-	wg         sync.WaitGroup
+	interval time.Duration
 }
 
 // SetSessionStore enables the session-memory expiry sweep (optional).
@@ -29,6 +38,40 @@ func (s *ExpirerService) SetSessionStore(ss domain.SessionStore) {
 	s.sessionStore = ss
 }
 
+// SetOutboxStore enables pruning of the event outbox (optional).
+func (s *ExpirerService) SetOutboxStore(obs domain.OutboxStore) {
+	s.outboxStore = obs
+}
+
+// SetEpisodePartitions enables maintenance of the monthly episode partitions:
+// each sweep creates partitions EpisodePartitionsAhead months ahead and, when
+// retainMonths > 0, drops partitions older than that many whole months.
+func (s *ExpirerService) SetEpisodePartitions(ps domain.EpisodePartitionStore, retainMonths int) {
+	s.partitions = ps
+	s.retainMonths = retainMonths
+}
+
+// SetRetentionStore enables enforcement of per-tenant retention rules (optional).
+func (s *ExpirerService) SetRetentionStore(rs domain.RetentionStore) {
+	s.retention = rs
+}
+
+// SetAgentRegistry finds the agents with retention policies from the agents
+// table. Without it only agents with feedback are checked.
+func (s *ExpirerService) SetAgentRegistry(r domain.AgentRegistry) {
+	s.agents = r
+}
+
+// SetEmbeddingCache enables pruning of the embeddings table (optional).
+func (s *ExpirerService) SetEmbeddingCache(ec domain.EmbeddingCache) {
+	s.embeddings = ec
+}
+
+// SetIdempotencyStore enables the sweep of expired idempotency keys (optional).
+func (s *ExpirerService) SetIdempotencyStore(is domain.IdempotencyStore) {
+	s.idemStore = is
+}
+
 func NewExpirerService(ms domain.MemoryStore, ps domain.PolicyStore, fs domain.FeedbackStore, logger *zap.Logger) *ExpirerService {
 	return &ExpirerService{
 		memoryStore:   ms,
@@ -36,7 +79,6 @@ func NewExpirerService(ms domain.MemoryStore, ps domain.PolicyStore, fs domain.F
 		feedbackStore: fs,
 		logger:        logger,
 		interval:      defaultExpirerInterval,
-		stopCh:        make(chan struct{}),
 	}
 }
 
@@ -44,78 +86,74 @@ func (s *ExpirerService) SetInterval(d time.Duration) {
 	s.interval = d
 }
 
-// Start runs the expirer on a periodic schedule in a background goroutine.
-func (s *ExpirerService) Start() {
-	baseCtx, cancel := context.WithCancel(context.Background())
-	s.cancelRuns = cancel
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		ticker := time.NewTicker(s.interval)
-		defer ticker.Stop()
-
-		s.logger.Info("memory expirer started", zap.Duration("interval", s.interval))
-
-		for {
-			select {
-			case <-ticker.C:
-				ctx, tickCancel := context.WithTimeout(baseCtx, 30*time.Second)
-				guardPanic(s.logger, "expirer tick", func() { s.run(ctx) })
-				tickCancel()
-			case <-s.stopCh:
-				s.logger.Info("memory expirer stopped")
-				return
-			}
-		}
-	}()
+// ScheduledTask returns the expirer's periodic sweep for the Scheduler.
+func (s *ExpirerService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "expirer", Interval: s.interval, Timeout: 30 * time.Second, Run: s.run}
 }
 
-// Stop gracefully stops the expirer, cancelling any in-flight sweep.
-func (s *ExpirerService) Stop() {
-	if s.cancelRuns != nil {
-		s.cancelRuns()
-	}
-	close(s.stopCh)
-	s.wg.Wait()
-}
-
-func (s *ExpirerService) run(ctx context.Context) {
+// run performs one sweep. Individual sweep failures are logged; only failing
+// to list agents for policy retention fails the run.
+func (s *ExpirerService) run(ctx context.Context) error {
 	swept, err := s.memoryStore.ArchiveExpiredSessionMemories(ctx)
 	if err != nil {
-		s.logger.Error("failed to archive expired session memories", zap.Error(err))
+		logFor(ctx, s.logger).Error("failed to archive expired session memories", zap.Error(err))
 	} else if swept > 0 {
-		s.logger.Info("archived expired session memories", zap.Int64("count", swept))
+		logFor(ctx, s.logger).Info("archived expired session memories", zap.Int64("count", swept))
 	}
 	if s.sessionStore != nil {
 		if ids, err := s.sessionStore.ListExpired(ctx, 500); err == nil {
 			for _, id := range ids {
 				if err := s.sessionStore.MarkExpired(ctx, id); err != nil {
-					s.logger.Warn("failed to mark session expired", zap.Error(err))
+					logFor(ctx, s.logger).Warn("failed to mark session expired", zap.Error(err))
 				}
 			}
 		}
 	}
 
+	if s.idemStore != nil {
+		if purged, err := s.idemStore.DeleteExpired(ctx); err != nil {
+			logFor(ctx, s.logger).Error("failed to delete expired idempotency keys", zap.Error(err))
+		} else if purged > 0 {
+			logFor(ctx, s.logger).Info("deleted expired idempotency keys", zap.Int64("count", purged))
+		}
+	}
+
+	if s.outboxStore != nil {
+		now := time.Now()
+		if purged, err := s.outboxStore.DeleteBefore(ctx, now.Add(-OutboxPublishedRetention), now.Add(-OutboxUndeliveredRetention)); err != nil {
+			logFor(ctx, s.logger).Error("failed to prune event outbox", zap.Error(err))
+		} else if purged > 0 {
+			logFor(ctx, s.logger).Info("pruned event outbox", zap.Int64("count", purged))
+		}
+	}
+
+	if s.partitions != nil {
+		s.maintainEpisodePartitions(ctx, time.Now())
+	}
+
+	if s.retention != nil {
+		s.applyRetentionRules(ctx)
+	}
+
 	// 1. Delete memories past their explicit expires_at timestamp
 	deleted, err := s.memoryStore.DeleteExpired(ctx)
 	if err != nil {
-		s.logger.Error("failed to delete expired memories", zap.Error(err))
+		logFor(ctx, s.logger).Error("failed to delete expired memories", zap.Error(err))
 	} else if deleted > 0 {
-		s.logger.Info("deleted expired memories", zap.Int64("count", deleted))
+		logFor(ctx, s.logger).Info("deleted expired memories", zap.Int64("count", deleted))
 	}
 
 	// 2. Delete memories past retention_days based on policies
-	// Get all agents that have feedback (they have policies to enforce)
-	agentIDs, err := s.feedbackStore.ListDistinctAgentIDs(ctx)
+	agents, err := agentsWithWork(ctx, s.agents, domain.AgentWorkFilter{RetentionPolicies: true}, s.feedbackStore.ListDistinctAgentIDs)
 	if err != nil {
-		s.logger.Error("failed to list agent IDs for retention", zap.Error(err))
-		return
+		return fmt.Errorf("list agent IDs for retention: %w", err)
 	}
 
-	for _, agentID := range agentIDs {
+	for _, agent := range agents {
+		agentID := agent.ID
 		policies, err := s.policyStore.GetByAgentID(ctx, agentID)
 		if err != nil {
-			s.logger.Warn("failed to get policies for retention check",
+			logFor(ctx, s.logger).Warn("failed to get policies for retention check",
 				zap.String("agent_id", agentID.String()),
 				zap.Error(err))
 			continue
@@ -128,12 +166,12 @@ func (s *ExpirerService) run(ctx context.Context) {
 
 			deleted, err := s.memoryStore.DeleteByRetention(ctx, agentID, policy.MemoryType, *policy.RetentionDays)
 			if err != nil {
-				s.logger.Warn("failed to delete memories by retention",
+				logFor(ctx, s.logger).Warn("failed to delete memories by retention",
 					zap.String("agent_id", agentID.String()),
 					zap.String("memory_type", string(policy.MemoryType)),
 					zap.Error(err))
 			} else if deleted > 0 {
-				s.logger.Info("deleted memories past retention",
+				logFor(ctx, s.logger).Info("deleted memories past retention",
 					zap.String("agent_id", agentID.String()),
 					zap.String("memory_type", string(policy.MemoryType)),
 					zap.Int("retention_days", *policy.RetentionDays),
@@ -141,4 +179,59 @@ func (s *ExpirerService) run(ctx context.Context) {
 			}
 		}
 	}
+
+	// Last, so the vectors of memories deleted above go in the same sweep.
+	if s.embeddings != nil {
+		if pruned, err := s.embeddings.PruneEmbeddings(ctx); err != nil {
+			logFor(ctx, s.logger).Error("failed to prune embeddings", zap.Error(err))
+		} else if pruned > 0 {
+			logFor(ctx, s.logger).Info("pruned unreferenced embeddings", zap.Int64("count", pruned))
+		}
+	}
+	return nil
+}
+
+func (s *ExpirerService) maintainEpisodePartitions(ctx context.Context, now time.Time) {
+	if err := s.partitions.EnsurePartitions(ctx, now, now.AddDate(0, EpisodePartitionsAhead, 0)); err != nil {
+		logFor(ctx, s.logger).Error("failed to create episode partitions", zap.Error(err))
+	}
+	if s.retainMonths <= 0 {
+		return
+	}
+	// Keep the current month plus retainMonths whole months before it.
+	now = now.UTC()
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -s.retainMonths, 0)
+	dropped, err := s.partitions.DropPartitionsBefore(ctx, cutoff)
+	if len(dropped) > 0 {
+		logFor(ctx, s.logger).Info("dropped expired episode partitions", zap.Strings("partitions", dropped))
+	}
+	if err != nil {
+		logFor(ctx, s.logger).Error("failed to drop expired episode partitions", zap.Error(err))
+	}
+}
+
+func (s *ExpirerService) applyRetentionRules(ctx context.Context) {
+	rules, err := s.retention.ListAll(ctx)
+	if err != nil {
+		logFor(ctx, s.logger).Error("failed to list retention rules", zap.Error(err))
+		return
+	}
+	for i := range rules {
+		r := &rules[i]
+		deleted, err := s.retention.Apply(ctx, r)
+		if err != nil {
+			logFor(ctx, s.logger).Error("failed to apply retention rule",
+				zap.String("rule_id", r.ID.String()),
+				zap.String("tenant_id", r.TenantID.String()),
+				zap.Error(err))
+			continue
+		}
+		if deleted > 0 {
+			logFor(ctx, s.logger).Info("applied retention rule",
+				zap.String("rule_id", r.ID.String()),
+				zap.String("tenant_id", r.TenantID.String()),
+				zap.String("target", string(r.Target)),
+				zap.Int64("count", deleted))
+		}
+	}
 }
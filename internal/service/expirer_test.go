@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type recordingPartitionStore struct {
+	ensuredFrom, ensuredThrough time.Time
+	dropCutoff                  time.Time
+	dropCalls                   int
+}
+
+func (r *recordingPartitionStore) EnsurePartitions(ctx context.Context, from, through time.Time) error {
+	r.ensuredFrom, r.ensuredThrough = from, through
+	return nil
+}
+
+func (r *recordingPartitionStore) DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	r.dropCalls++
+	r.dropCutoff = cutoff
+	return nil, nil
+}
+
+func TestExpirer_MaintainEpisodePartitions(t *testing.T) {
+	now := time.Date(2026, 3, 17, 9, 30, 0, 0, time.UTC)
+
+	ps := &recordingPartitionStore{}
+	svc := NewExpirerService(nil, nil, nil, testLogger())
+	svc.SetEpisodePartitions(ps, 12)
+	svc.maintainEpisodePartitions(context.Background(), now)
+
+	if !ps.ensuredFrom.Equal(now) || !ps.ensuredThrough.Equal(now.AddDate(0, EpisodePartitionsAhead, 0)) {
+		t.Errorf("ensured %v..%v, want %v..%v", ps.ensuredFrom, ps.ensuredThrough, now, now.AddDate(0, EpisodePartitionsAhead, 0))
+	}
+	if want := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC); !ps.dropCutoff.Equal(want) {
+		t.Errorf("drop cutoff = %v, want %v", ps.dropCutoff, want)
+	}
+}
+
+func TestExpirer_MaintainEpisodePartitions_NoRetentionKeepsEverything(t *testing.T) {
+	ps := &recordingPartitionStore{}
+	svc := NewExpirerService(nil, nil, nil, testLogger())
+	svc.SetEpisodePartitions(ps, 0)
+	svc.maintainEpisodePartitions(context.Background(), time.Now())
+
+	if ps.dropCalls != 0 {
+		t.Fatalf("DropPartitionsBefore called %d times with retention disabled", ps.dropCalls)
+	}
+	if ps.ensuredThrough.IsZero() {
+		t.Fatal("partitions were not ensured")
+	}
+}
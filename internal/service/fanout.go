@@ -0,0 +1,17 @@
+package service
+
+import "context"
+
+// DatabaseFanout runs fn once per database that holds tenant data, with ctx
+// pinned to each in turn; store.TenantRouter.ForEachDatabase is one. Sweeps
+// that aren't scoped to a tenant use it so routed tenants aren't skipped.
+type DatabaseFanout func(ctx context.Context, fn func(ctx context.Context))
+
+// each runs fn under the fanout, or once with ctx when none is set.
+func (f DatabaseFanout) each(ctx context.Context, fn func(ctx context.Context)) {
+	if f == nil {
+		fn(ctx)
+		return
+	}
+	f(ctx, fn)
+}
@@ -22,6 +22,7 @@ type FeedbackService struct {
 	agentStore       domain.AgentStore
 	mutationLogStore domain.MutationLogStore
 	uow              *store.UnitOfWork
+	reliability      *SourceReliabilityService
 	logger           *zap.Logger
 }
 
@@ -42,6 +43,12 @@ func (s *FeedbackService) SetUnitOfWork(uow *store.UnitOfWork) {
 	s.uow = uow
 }
 
+// SetSourceReliability counts helpful and contradicted feedback toward the
+// reliability of the memory's source.
+func (s *FeedbackService) SetSourceReliability(sr *SourceReliabilityService) {
+	s.reliability = sr
+}
+
 func (s *FeedbackService) SetLogger(logger *zap.Logger) {
 	s.logger = logger
 }
@@ -84,6 +91,15 @@ func (s *FeedbackService) Create(ctx context.Context, f *domain.Feedback, tenant
 		s.applyFeedbackEffect(ctx, f, memory, effect)
 	}
 
+	// Only feedback on whether the memory was true says anything about its
+	// source; ignored, unhelpful and outdated memories may have been right.
+	switch f.SignalType {
+	case domain.FeedbackTypeHelpful:
+		s.reliability.RecordOutcome(ctx, memory, true)
+	case domain.FeedbackTypeContradicted:
+		s.reliability.RecordOutcome(ctx, memory, false)
+	}
+
 	return nil
 }
 
@@ -118,6 +134,11 @@ func (s *FeedbackService) applyFeedbackEffect(ctx context.Context, f *domain.Fee
 			if err := st.Memory.UpdateReinforcement(ctx, memory.ID, newConfidence, newReinforcement); err != nil {
 				return err
 			}
+			if supporting, contradicting := evidenceFor(effect.LogOddsDelta); supporting+contradicting > 0 {
+				if err := st.Memory.AddEvidence(ctx, memory.ID, supporting, contradicting); err != nil {
+					return err
+				}
+			}
 			if effect.TriggerReview {
 				if err := st.Memory.SetNeedsReview(ctx, memory.ID, true); err != nil {
 					return err
@@ -125,28 +146,30 @@ func (s *FeedbackService) applyFeedbackEffect(ctx context.Context, f *domain.Fee
 			}
 			return st.MutationLog.Create(ctx, mutation)
 		}); err != nil {
-			s.logger.Warn("failed to apply feedback effect", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to apply feedback effect", zap.Error(err))
 			return
 		}
 	} else {
 		// Fallback (no unit of work, e.g. unit tests): non-atomic.
 		if err := s.memoryStore.UpdateReinforcement(ctx, memory.ID, newConfidence, newReinforcement); err != nil {
-			s.logger.Warn("failed to update memory on feedback", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to update memory on feedback", zap.Error(err))
 			return
 		}
+		supporting, contradicting := evidenceFor(effect.LogOddsDelta)
+		recordEvidence(ctx, s.logger, s.memoryStore, memory.ID, supporting, contradicting)
 		if effect.TriggerReview {
 			if err := s.memoryStore.SetNeedsReview(ctx, memory.ID, true); err != nil {
-				s.logger.Warn("failed to set needs_review flag", zap.Error(err))
+				logFor(ctx, s.logger).Warn("failed to set needs_review flag", zap.Error(err))
 			}
 		}
 		if s.mutationLogStore != nil {
 			if err := s.mutationLogStore.Create(ctx, mutation); err != nil {
-				s.logger.Warn("failed to log mutation", zap.Error(err))
+				logFor(ctx, s.logger).Warn("failed to log mutation", zap.Error(err))
 			}
 		}
 	}
 
-	s.logger.Debug("applied feedback effect",
+	logFor(ctx, s.logger).Debug("applied feedback effect",
 		zap.String("memory_id", memory.ID.String()),
 		zap.String("signal_type", string(f.SignalType)),
 		zap.Float32("old_confidence", oldConfidence),
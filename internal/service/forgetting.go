@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultForecastDays  = 7
+	MaxForecastDays      = 365
+	DefaultForecastLimit = 50
+	MaxForecastLimit     = 500
+)
+
+// ForgettingForecast lists an agent's memories projected to fall below the
+// archive threshold within the horizon, soonest first.
+type ForgettingForecast struct {
+	AgentID          uuid.UUID          `json:"agent_id"`
+	HorizonDays      int                `json:"horizon_days"`
+	ArchiveThreshold float64            `json:"archive_threshold"`
+	Memories         []ForecastedMemory `json:"memories"`
+	Count            int                `json:"count"`
+}
+
+// ForecastedMemory is one fading memory in a forgetting forecast.
+type ForecastedMemory struct {
+	ID                  uuid.UUID         `json:"id"`
+	Type                domain.MemoryType `json:"type"`
+	Content             string            `json:"content"`
+	Confidence          float32           `json:"confidence"`
+	ProjectedConfidence float32           `json:"projected_confidence"`
+	DaysUntilArchive    float64           `json:"days_until_archive"`
+	ArchiveAt           time.Time         `json:"archive_at"`
+	EffectiveDecayRate  float64           `json:"effective_decay_rate"`
+	ReinforcementCount  int               `json:"reinforcement_count"`
+	LastAccessedAt      *time.Time        `json:"last_accessed_at,omitempty"`
+}
+
+// ForecastForgetting replays the decay worker over the next days for each of
+// an agent's memories, using the tenant's decay settings and the competition
+// each memory currently faces, and returns those that would cross the archive
+// threshold if nothing reinforces or accesses them in the meantime.
+//
+// Finding a memory's competitors compares it with every other memory of its
+// type, so the forecast avoids doing that for each one. It first projects every
+// memory at the fastest rate competition could push it to, which drops the
+// memories that stay above the threshold regardless. The rest are then
+// resolved soonest first, and the search stops once the next one cannot beat
+// the limit-th memory already found.
+func (s *DecayService) ForecastForgetting(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, days int, limit int) (*ForgettingForecast, error) {
+	if days <= 0 {
+		days = DefaultForecastDays
+	}
+	if days > MaxForecastDays {
+		days = MaxForecastDays
+	}
+	if limit <= 0 {
+		limit = DefaultForecastLimit
+	}
+	if limit > MaxForecastLimit {
+		limit = MaxForecastLimit
+	}
+
+	memories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	eff := s.effFor(ctx, tenantID)
+	horizon := float64(days * 24)
+	tick := s.interval.Hours()
+	now := timeNow()
+
+	// Competitors share a type; grouping keeps each search to that type while
+	// preserving the order findCompetitors sees them in.
+	byType := make(map[domain.MemoryType][]domain.Memory)
+	for i := range memories {
+		if memories[i].TenantID == tenantID {
+			byType[memories[i].Type] = append(byType[memories[i].Type], memories[i])
+		}
+	}
+
+	type candidate struct {
+		m             *domain.Memory
+		sinceAccess   float64
+		earliestHours float64
+	}
+	var candidates []candidate
+	for _, group := range byType {
+		for i := range group {
+			m := &group[i]
+			lastUsed := m.CreatedAt
+			if m.LastAccessedAt != nil {
+				lastUsed = *m.LastAccessedAt
+			}
+			sinceAccess := now.Sub(lastUsed).Hours()
+			fastest := eff.baseRate * (1 + maxCompetition(m.Confidence, eff.competitionWeight))
+			if _, hours, reached := simulateDecay(float64(m.Confidence), eff.floor, fastest, m.ReinforcementCount,
+				sinceAccess, tick, horizon, eff.archiveThreshold); reached {
+				candidates = append(candidates, candidate{m: m, sinceAccess: sinceAccess, earliestHours: hours})
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].earliestHours < candidates[j].earliestHours })
+
+	forecast := &ForgettingForecast{
+		AgentID:          agentID,
+		HorizonDays:      days,
+		ArchiveThreshold: eff.archiveThreshold,
+		Memories:         []ForecastedMemory{},
+	}
+	for _, c := range candidates {
+		if len(forecast.Memories) >= limit && c.earliestHours >= forecast.Memories[limit-1].DaysUntilArchive*24 {
+			break
+		}
+		m := c.m
+		rate := eff.baseRate * (1 + s.calculateCompetition(m, s.findCompetitors(m, byType[m.Type]), eff.competitionWeight))
+		projected, hours, reached := simulateDecay(float64(m.Confidence), eff.floor, rate, m.ReinforcementCount,
+			c.sinceAccess, tick, horizon, eff.archiveThreshold)
+		if !reached {
+			continue
+		}
+
+		fm := ForecastedMemory{
+			ID:                  m.ID,
+			Type:                m.Type,
+			Content:             m.Content,
+			Confidence:          m.Confidence,
+			ProjectedConfidence: float32(projected),
+			DaysUntilArchive:    hours / 24,
+			ArchiveAt:           now.Add(time.Duration(hours * float64(time.Hour))),
+			EffectiveDecayRate:  rate,
+			ReinforcementCount:  m.ReinforcementCount,
+			LastAccessedAt:      m.LastAccessedAt,
+		}
+		at := sort.Search(len(forecast.Memories), func(i int) bool { return forecast.Memories[i].DaysUntilArchive > fm.DaysUntilArchive })
+		forecast.Memories = slices.Insert(forecast.Memories, at, fm)
+		if len(forecast.Memories) > limit {
+			forecast.Memories = forecast.Memories[:limit]
+		}
+	}
+	forecast.Count = len(forecast.Memories)
+	return forecast, nil
+}
+
+// maxCompetition bounds calculateCompetition for a memory at the given
+// confidence: at most MaxCompetitors competitors, each at most fully similar
+// and fully confident.
+func maxCompetition(confidence float32, competitionWeight float64) float64 {
+	c := float64(confidence)
+	if c >= 1 {
+		return 0
+	}
+	return competitionWeight * MaxCompetitors * (1 - c) / (1 + c)
+}
@@ -56,15 +56,15 @@ func (s *GraphBuilderService) OnMemoryCreated(ctx context.Context, memory *domai
 	// 1. Extract entities
 	if s.llmClient != nil {
 		if err := s.extractAndLinkEntities(ctx, memory); err != nil {
-			s.logger.Warn("entity extraction failed",
+			logFor(ctx, s.logger).Warn("entity extraction failed",
 				zap.String("memory_id", memory.ID.String()), zap.Error(err))
 		}
 		if err := s.detectAndCreateRelationships(ctx, memory); err != nil {
-			s.logger.Warn("relationship detection failed",
+			logFor(ctx, s.logger).Warn("relationship detection failed",
 				zap.String("memory_id", memory.ID.String()), zap.Error(err))
 		}
 	} else {
-		s.logger.Debug("graph entity extraction skipped: no LLM client configured",
+		logFor(ctx, s.logger).Debug("graph entity extraction skipped: no LLM client configured",
 			zap.String("memory_id", memory.ID.String()))
 	}
 
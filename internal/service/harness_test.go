@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/testharness"
+	"github.com/google/uuid"
+)
+
+// harnessMemory is a fact last touched at the given time on the harness clock.
+func harnessMemory(agentID, tenantID uuid.UUID, confidence float32, at time.Time) *domain.Memory {
+	return &domain.Memory{
+		ID:             uuid.New(),
+		AgentID:        agentID,
+		TenantID:       tenantID,
+		Content:        "test memory",
+		Type:           domain.MemoryTypeFact,
+		Confidence:     confidence,
+		Embedding:      []float32{0.1, 0.2, 0.3, 0.4, 0.5},
+		LastAccessedAt: &at,
+		CreatedAt:      at,
+	}
+}
+
+func TestHarness_DecayFollowsFakeClock(t *testing.T) {
+	h := testharness.New(t)
+	store := newDecayMockStore()
+	svc := NewDecayService(store, nil, testLogger())
+
+	agentID, tenantID := uuid.New(), uuid.New()
+	mem := harnessMemory(agentID, tenantID, 0.9, h.Clock.Now())
+	store.memories[mem.ID] = mem
+
+	result, err := svc.BatchDecay(context.Background(), agentID)
+	if err != nil {
+		t.Fatalf("BatchDecay failed: %v", err)
+	}
+	if result.Decayed != 0 || mem.Confidence != 0.9 {
+		t.Fatalf("expected no decay inside the grace window, got %+v (confidence %v)", result, mem.Confidence)
+	}
+
+	h.Clock.Advance(30 * 24 * time.Hour)
+	result, err = svc.BatchDecay(context.Background(), agentID)
+	if err != nil {
+		t.Fatalf("BatchDecay failed: %v", err)
+	}
+	if result.Decayed != 1 || mem.Confidence >= 0.9 {
+		t.Fatalf("expected decay after 30 days, got %+v (confidence %v)", result, mem.Confidence)
+	}
+}
+
+func TestHarness_DecayIsReproducible(t *testing.T) {
+	run := func() (float32, int) {
+		h := testharness.New(t)
+		store := newDecayMockStore()
+		svc := NewDecayService(store, nil, testLogger())
+
+		agentID, tenantID := uuid.New(), uuid.New()
+		mem := harnessMemory(agentID, tenantID, 0.86, h.Ago(90*24*time.Hour))
+		store.memories[mem.ID] = mem
+
+		result, err := svc.BatchDecay(context.Background(), agentID)
+		if err != nil {
+			t.Fatalf("BatchDecay failed: %v", err)
+		}
+		return mem.Confidence, len(result.TierTransitions)
+	}
+
+	conf1, transitions1 := run()
+	conf2, transitions2 := run()
+	if conf1 != conf2 || transitions1 != transitions2 {
+		t.Fatalf("expected identical runs, got (%v, %d) and (%v, %d)", conf1, transitions1, conf2, transitions2)
+	}
+	if transitions1 == 0 {
+		t.Fatalf("expected a HOT memory to leave its tier after 90 days (confidence %v)", conf1)
+	}
+}
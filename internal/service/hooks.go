@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MemoryEvent describes one memory lifecycle change reported to Hooks.
+type MemoryEvent struct {
+	MemoryID uuid.UUID
+	AgentID  uuid.UUID
+	TenantID uuid.UUID
+	// Memory is the memory's state after the change. Events raised where only
+	// the IDs are at hand, such as redundancy merges, leave it nil.
+	Memory *domain.Memory
+	// ContradictedBy is the belief that contradicted this one; set on
+	// contradiction events and on archives caused by a newer belief.
+	ContradictedBy *uuid.UUID
+	Reason         string
+}
+
+// ConsolidationEvent reports a finished consolidation run.
+type ConsolidationEvent struct {
+	AgentID    uuid.UUID
+	TenantID   uuid.UUID
+	Scope      ConsolidationScope
+	Result     *ConsolidationResult
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+type (
+	MemoryHook        func(ctx context.Context, e MemoryEvent)
+	ConsolidationHook func(ctx context.Context, e ConsolidationEvent)
+	AnomalyHook       func(ctx context.Context, a domain.PipelineAnomaly)
+)
+
+// Hooks lets a program that embeds engram react to memory lifecycle events
+// in-process, without the HTTP server or the outbox. Callbacks run
+// synchronously in the goroutine that made the change, after it committed, so
+// they see the new state; they should return quickly and hand slow work to a
+// goroutine of their own. A panicking callback is recovered and logged.
+//
+// Unlike outbox events, hooks are not persisted: a crash between the commit
+// and the callback loses the notification.
+type Hooks struct {
+	mu           sync.RWMutex
+	created      []MemoryHook
+	reinforced   []MemoryHook
+	contradicted []MemoryHook
+	archived     []MemoryHook
+	consolidated []ConsolidationHook
+	anomalies    []AnomalyHook
+	logger       *zap.Logger
+}
+
+func NewHooks(logger *zap.Logger) *Hooks {
+	return &Hooks{logger: logger}
+}
+
+// OnMemoryCreated registers fn for memories entering active memory: new
+// writes, consolidation summaries, and quarantined memories on release.
+func (h *Hooks) OnMemoryCreated(fn MemoryHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.created = append(h.created, fn)
+}
+
+// OnMemoryReinforced registers fn for memories whose confidence rose because
+// the same belief was seen again.
+func (h *Hooks) OnMemoryReinforced(fn MemoryHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reinforced = append(h.reinforced, fn)
+}
+
+// OnMemoryContradicted registers fn for beliefs demoted or superseded by a
+// contradicting one.
+func (h *Hooks) OnMemoryContradicted(fn MemoryHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.contradicted = append(h.contradicted, fn)
+}
+
+// OnMemoryArchived registers fn for memories moved out of active memory by
+// decay, redundancy merges, summary refreshes or a newer belief.
+func (h *Hooks) OnMemoryArchived(fn MemoryHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.archived = append(h.archived, fn)
+}
+
+// OnConsolidationComplete registers fn for finished consolidation runs.
+func (h *Hooks) OnConsolidationComplete(fn ConsolidationHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consolidated = append(h.consolidated, fn)
+}
+
+// OnPipelineAnomaly registers fn for anomalies PipelineAnomalyService raises
+// on an agent's pipeline metrics.
+func (h *Hooks) OnPipelineAnomaly(fn AnomalyHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.anomalies = append(h.anomalies, fn)
+}
+
+// The firing methods below are safe on a nil *Hooks, so services call them
+// unconditionally.
+
+func (h *Hooks) memoryCreated(ctx context.Context, m *domain.Memory) {
+	if h != nil {
+		h.fire(ctx, "memory created", h.snapshot(&h.created), memoryEvent(m, ""))
+	}
+}
+
+func (h *Hooks) memoryReinforced(ctx context.Context, e MemoryEvent) {
+	if h != nil {
+		h.fire(ctx, "memory reinforced", h.snapshot(&h.reinforced), e)
+	}
+}
+
+func (h *Hooks) memoryContradicted(ctx context.Context, e MemoryEvent) {
+	if h != nil {
+		h.fire(ctx, "memory contradicted", h.snapshot(&h.contradicted), e)
+	}
+}
+
+func (h *Hooks) memoryArchived(ctx context.Context, e MemoryEvent) {
+	if h != nil {
+		h.fire(ctx, "memory archived", h.snapshot(&h.archived), e)
+	}
+}
+
+func (h *Hooks) consolidationComplete(ctx context.Context, e ConsolidationEvent) {
+	if h == nil {
+		return
+	}
+	h.mu.RLock()
+	fns := h.consolidated
+	h.mu.RUnlock()
+	for _, fn := range fns {
+		guardPanic(h.logger, "consolidation complete hook", func() { fn(ctx, e) })
+	}
+}
+
+func (h *Hooks) pipelineAnomaly(ctx context.Context, a domain.PipelineAnomaly) {
+	if h == nil {
+		return
+	}
+	h.mu.RLock()
+	fns := h.anomalies
+	h.mu.RUnlock()
+	for _, fn := range fns {
+		guardPanic(h.logger, "pipeline anomaly hook", func() { fn(ctx, a) })
+	}
+}
+
+func (h *Hooks) snapshot(fns *[]MemoryHook) []MemoryHook {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return *fns
+}
+
+func (h *Hooks) fire(ctx context.Context, what string, fns []MemoryHook, e MemoryEvent) {
+	for _, fn := range fns {
+		guardPanic(h.logger, what+" hook", func() { fn(ctx, e) })
+	}
+}
+
+// memoryEvent builds the event for a change to m. Callbacks get a copy, so
+// they can't alter the memory the service goes on to return.
+func memoryEvent(m *domain.Memory, reason string) MemoryEvent {
+	cp := *m
+	return MemoryEvent{MemoryID: m.ID, AgentID: m.AgentID, TenantID: m.TenantID, Memory: &cp, Reason: reason}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestHooks_MemoryServiceReportsCreate(t *testing.T) {
+	svc, _, tenantID, agentID := setupMemoryTest()
+	hooks := NewHooks(zap.NewNop())
+	svc.SetHooks(hooks)
+
+	var got []MemoryEvent
+	hooks.OnMemoryCreated(func(_ context.Context, e MemoryEvent) { got = append(got, e) })
+
+	mem := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode", Type: domain.MemoryTypePreference}
+	if _, err := svc.Create(context.Background(), mem); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("expected one created event, got %d", len(got))
+	}
+	e := got[0]
+	if e.MemoryID != mem.ID || e.AgentID != agentID || e.TenantID != tenantID {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if e.Memory == nil || e.Memory.Content != mem.Content {
+		t.Fatalf("expected the event to carry the memory, got %+v", e.Memory)
+	}
+	e.Memory.Content = "changed"
+	if mem.Content != "User prefers dark mode" {
+		t.Fatal("expected hooks to get a copy of the memory")
+	}
+}
+
+func TestHooks_ConfidenceServiceReportsReinforce(t *testing.T) {
+	memStore := newMockMemoryStoreForConfidence()
+	mem := &domain.Memory{AgentID: uuid.New(), TenantID: uuid.New(), Confidence: 0.6, ReinforcementCount: 1}
+	_ = memStore.Create(context.Background(), mem)
+
+	svc := NewConfidenceService(memStore, zap.NewNop())
+	hooks := NewHooks(zap.NewNop())
+	svc.SetHooks(hooks)
+	var got []MemoryEvent
+	hooks.OnMemoryReinforced(func(_ context.Context, e MemoryEvent) { got = append(got, e) })
+
+	if err := svc.Reinforce(context.Background(), mem.ID, mem.TenantID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].MemoryID != mem.ID || got[0].Memory.ReinforcementCount != 2 {
+		t.Fatalf("expected one reinforced event with the new count, got %+v", got)
+	}
+}
+
+func TestHooks_PanickingCallbackDoesNotStopOthers(t *testing.T) {
+	hooks := NewHooks(zap.NewNop())
+	var calls []string
+	hooks.OnMemoryArchived(func(context.Context, MemoryEvent) { calls = append(calls, "first"); panic("boom") })
+	hooks.OnMemoryArchived(func(context.Context, MemoryEvent) { calls = append(calls, "second") })
+	hooks.OnConsolidationComplete(func(context.Context, ConsolidationEvent) { calls = append(calls, "consolidated") })
+
+	hooks.memoryArchived(context.Background(), MemoryEvent{MemoryID: uuid.New()})
+	hooks.consolidationComplete(context.Background(), ConsolidationEvent{Result: &ConsolidationResult{}})
+
+	if len(calls) != 3 || calls[0] != "first" || calls[1] != "second" || calls[2] != "consolidated" {
+		t.Fatalf("expected every callback in registration order, got %v", calls)
+	}
+
+	var none *Hooks
+	none.memoryCreated(context.Background(), &domain.Memory{})
+	none.consolidationComplete(context.Background(), ConsolidationEvent{})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -14,6 +15,10 @@ type HybridRecallService struct {
 	graphStore      domain.GraphStore
 	entityStore     domain.EntityStore
 	sessionStore    domain.SessionStore
+	coldSummarizer  ColdSummarizer
+	neighborhoods   domain.NeighborhoodStore
+	envBoost        float64
+	cache           *RecallCache
 	embeddingClient domain.EmbeddingClient
 	llmClient       domain.LLMClient
 }
@@ -31,6 +36,7 @@ func NewHybridRecallService(
 		entityStore:     entityStore,
 		embeddingClient: embeddingClient,
 		llmClient:       llmClient,
+		envBoost:        DefaultEnvironmentBoost,
 	}
 }
 
@@ -38,6 +44,30 @@ func (s *HybridRecallService) SetSessionStore(ss domain.SessionStore) {
 	s.sessionStore = ss
 }
 
+// SetColdSummarizer routes recalled cold-tier memories to background
+// summarization (optional).
+func (s *HybridRecallService) SetColdSummarizer(cs ColdSummarizer) {
+	s.coldSummarizer = cs
+}
+
+// SetNeighborhoodStore lowers the score of recalled memories crowded by
+// similar ones (interference), unless a request opts out (optional).
+func (s *HybridRecallService) SetNeighborhoodStore(ns domain.NeighborhoodStore) {
+	s.neighborhoods = ns
+}
+
+// SetEnvironmentBoost sets how much higher a memory formed in the caller's
+// environment ranks; 0 turns the bias off.
+func (s *HybridRecallService) SetEnvironmentBoost(boost float64) {
+	s.envBoost = boost
+}
+
+// SetRecallCache reuses recent results for a repeated query and options.
+// The cache must also listen to the memory store to stay fresh.
+func (s *HybridRecallService) SetRecallCache(c *RecallCache) {
+	s.cache = c
+}
+
 const (
 	defaultVectorWeight    = 0.6
 	defaultGraphWeight     = 0.4
@@ -46,10 +76,34 @@ const (
 	minActivation          = 0.1
 	hopDecay               = 0.7
 	traversalStrengthBoost = 0.03
+	// graphCandidateFactor widens the vector pool graph mode re-ranks, so
+	// connected memories just outside the top results can be lifted into them.
+	graphCandidateFactor = 4
 )
 
 func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecallRequest) ([]domain.ScoredMemory, error) {
-	// Set defaults
+	req = withHybridDefaults(req)
+	cached, gen, ok := s.cache.get(req)
+	if ok {
+		s.noteUse(req, cached)
+		return cached, nil
+	}
+
+	// Step 1: Vector retrieval
+	embedding, err := s.embeddingClient.Embed(ctx, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.recallEmbedded(ctx, req, embedding)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(req, gen, results)
+	return results, nil
+}
+
+// withHybridDefaults fills in the unset weights and limits of req.
+func withHybridDefaults(req domain.HybridRecallRequest) domain.HybridRecallRequest {
 	if req.TopK <= 0 {
 		req.TopK = defaultTopK
 	}
@@ -62,15 +116,24 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 	if req.MaxGraphHops <= 0 {
 		req.MaxGraphHops = defaultMaxHops
 	}
+	return req
+}
 
-	// Step 1: Vector retrieval
-	embedding, err := s.embeddingClient.Embed(ctx, req.Query)
-	if err != nil {
-		return nil, err
+// hybridVectorWeight is the weight of vector similarity in the final score.
+// Graph mode keeps the full similarity and adds the graph score on top, so a
+// connection lifts a candidate without pulling down the direct hits.
+func hybridVectorWeight(req domain.HybridRecallRequest) float64 {
+	if req.Mode == domain.RecallModeGraph && req.AgentID != uuid.Nil {
+		return 1
 	}
+	return req.VectorWeight
+}
 
+// recallEmbedded runs the rest of Recall for a query already embedded.
+func (s *HybridRecallService) recallEmbedded(ctx context.Context, req domain.HybridRecallRequest, embedding []float32) ([]domain.ScoredMemory, error) {
+	var err error
 	recallOpts := domain.RecallOpts{
-		TopK:          req.TopK * 2, // Get more for merging
+		TopK:          domain.RecallCandidates(req.TopK*2, req.Accuracy), // Get more for merging
 		MemoryType:    req.MemoryType,
 		MinConfidence: req.MinConfidence,
 		IncludeTiers:  req.IncludeTiers,
@@ -81,6 +144,8 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 		MinSimilarity: req.MinSimilarity,
 		MaxResults:    req.MaxResults,
 		AnchorID:      req.AnchorID,
+		Subject:       req.Subject,
+		Accuracy:      req.Accuracy,
 	}
 
 	mode := req.Mode
@@ -89,6 +154,9 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 	}
 
 	composed := req.AnchorID != nil || req.SessionID != nil
+	if mode == domain.RecallModeGraph {
+		recallOpts.TopK = domain.RecallCandidates(req.TopK*graphCandidateFactor, req.Accuracy)
+	}
 
 	var vectorResults []domain.MemoryWithScore
 	switch {
@@ -114,8 +182,10 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 		}
 	}
 
-	if req.UseGraph && !composed && s.graphStore != nil && mode != domain.RecallModeExhaustive && mode != domain.RecallModeHybrid {
-		graphResults := s.traverseGraph(ctx, vectorResults, req.MaxGraphHops)
+	if mode == domain.RecallModeGraph && s.graphStore != nil {
+		s.boostConnected(ctx, vectorResults, scoredResults, req)
+	} else if req.UseGraph && !composed && s.graphStore != nil && mode != domain.RecallModeExhaustive && mode != domain.RecallModeHybrid {
+		graphResults := s.traverseGraphWithConstraints(ctx, vectorResults, req.MaxGraphHops, domain.TraversalConstraints{RelationFilter: req.RelationTypes})
 
 		for _, gr := range graphResults {
 			if existing, ok := scoredResults[gr.MemoryID]; ok {
@@ -125,7 +195,7 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 			} else {
 				// Fetch the memory if not in vector results
 				mem, err := s.memoryStore.GetByID(ctx, gr.MemoryID, req.TenantID)
-				if err == nil && mem != nil {
+				if err == nil && mem != nil && (req.Subject == "" || strings.EqualFold(mem.Subject, req.Subject)) {
 					scoredResults[gr.MemoryID] = &domain.ScoredMemory{
 						Memory:     *mem,
 						GraphScore: gr.GraphRelevance,
@@ -137,10 +207,30 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 		}
 	}
 
-	// Step 3: Compute final scores and rank
+	// Step 3: Compute final scores, with interference and environment, and rank
+	scorer := NewRecallScorer()
+	scorer.Environment = req.Environment.Normalize()
+	scorer.EnvironmentBoost = s.envBoost
+	if !req.NoInterference && s.neighborhoods != nil {
+		ids := make([]uuid.UUID, 0, len(scoredResults))
+		for id := range scoredResults {
+			ids = append(ids, id)
+		}
+		// A failed count leaves the ranking unpenalized
+		_ = scorer.LoadNeighbors(ctx, s.neighborhoods, req.TenantID, ids)
+	}
+	vectorWeight := hybridVectorWeight(req)
 	results := make([]domain.ScoredMemory, 0, len(scoredResults))
 	for _, sm := range scoredResults {
-		sm.FinalScore = float32(float64(sm.VectorScore)*req.VectorWeight + float64(sm.GraphScore)*req.GraphWeight)
+		interference, environment := scorer.Interference(sm.ID), scorer.EnvironmentFactor(&sm.Memory)
+		vector, graph := hybridContributions(sm.VectorScore, sm.GraphScore, vectorWeight, req.GraphWeight, interference, environment)
+		sm.FinalScore = float32(vector + graph)
+		if interference < 1 {
+			sm.Interference = float32(interference)
+		}
+		if environment > 1 {
+			sm.EnvironmentBoost = float32(environment)
+		}
 		results = append(results, *sm)
 	}
 
@@ -149,14 +239,61 @@ func (s *HybridRecallService) Recall(ctx context.Context, req domain.HybridRecal
 		return results[i].FinalScore > results[j].FinalScore
 	})
 
+	if !req.ExpandSummaries {
+		results = collapseSummarized(results)
+	}
+
+	if window := newConversationWindow(req.Conversation); len(window) > 0 {
+		kept := results[:0]
+		for _, r := range results {
+			if !window.contains(r.Content) {
+				kept = append(kept, r)
+			}
+		}
+		results = kept
+	}
+
 	// Limit to topK
 	if len(results) > req.TopK {
 		results = results[:req.TopK]
 	}
 
+	s.noteUse(req, results)
 	return results, nil
 }
 
+// noteUse counts results as used, whether fresh or from the cache.
+func (s *HybridRecallService) noteUse(req domain.HybridRecallRequest, results []domain.ScoredMemory) {
+	// Control recalls are withheld from the agent, so they must not count as use
+	if s.coldSummarizer != nil && !req.Control {
+		for _, r := range results {
+			s.coldSummarizer.Enqueue(r.Memory)
+		}
+	}
+}
+
+// collapseSummarized drops detail memories whose summary is also in the
+// results, freeing their slots for other matches.
+func collapseSummarized(results []domain.ScoredMemory) []domain.ScoredMemory {
+	covered := make(map[uuid.UUID]bool)
+	for _, r := range results {
+		for _, id := range domain.SummaryMemberIDs(r.Memory) {
+			covered[id] = true
+		}
+	}
+	if len(covered) == 0 {
+		return results
+	}
+
+	out := results[:0]
+	for _, r := range results {
+		if !covered[r.ID] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
 func (s *HybridRecallService) composedRecall(ctx context.Context, req domain.HybridRecallRequest, embedding []float32, base domain.RecallOpts) ([]domain.MemoryWithScore, error) {
 	anchorID := req.AnchorID
 	if anchorID == nil && req.SessionID != nil && s.sessionStore != nil {
@@ -228,6 +365,27 @@ func (s *HybridRecallService) composedRecall(ctx context.Context, req domain.Hyb
 	return merged, nil
 }
 
+// boostConnected gives each candidate reachable within req.MaxGraphHops of a
+// direct hit (one of the req.TopK best vector matches) a graph score: the
+// hit's similarity times the strength of the strongest connecting path. Only
+// the candidates are scored, so everything returned still passed the recall
+// filters; the graph reorders them rather than adding to them.
+func (s *HybridRecallService) boostConnected(ctx context.Context, candidates []domain.MemoryWithScore, scored map[uuid.UUID]*domain.ScoredMemory, req domain.HybridRecallRequest) {
+	hits := candidates
+	if len(hits) > req.TopK {
+		hits = hits[:req.TopK]
+	}
+	for _, gr := range s.traverseGraphWithConstraints(ctx, hits, req.MaxGraphHops, domain.TraversalConstraints{RelationFilter: req.RelationTypes}) {
+		sm, ok := scored[gr.MemoryID]
+		if !ok || gr.GraphRelevance <= sm.GraphScore {
+			continue
+		}
+		sm.GraphScore = gr.GraphRelevance
+		sm.PathLength = gr.PathLength
+		sm.GraphPath = gr.Path
+	}
+}
+
 type queueItem struct {
 	memoryID   uuid.UUID
 	activation float32
@@ -302,7 +460,7 @@ func (s *HybridRecallService) traverseGraphWithConstraints(ctx context.Context,
 
 				// Age constraint
 				if constraints.MaxAge > 0 && targetMem != nil {
-					if time.Since(targetMem.CreatedAt) > constraints.MaxAge {
+					if timeNow().Sub(targetMem.CreatedAt) > constraints.MaxAge {
 						continue
 					}
 				}
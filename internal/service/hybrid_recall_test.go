@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -51,6 +52,12 @@ func (m *mockGraphStore) GetNeighbors(ctx context.Context, memoryID uuid.UUID, d
 			}
 		}
 	}
+	// Relation filter, as the store applies it in SQL
+	if len(relationTypes) > 0 {
+		result = slices.DeleteFunc(result, func(e domain.GraphEdge) bool {
+			return !slices.Contains(relationTypes, e.RelationType)
+		})
+	}
 	return result, nil
 }
 
@@ -181,7 +188,16 @@ func (m *mockEntityStore) GetMemoriesForEntity(ctx context.Context, entityID uui
 }
 
 func (m *mockEntityStore) GetEntitiesForMemory(ctx context.Context, memoryID uuid.UUID) ([]domain.Entity, error) {
-	return nil, nil
+	var result []domain.Entity
+	for entityID, mentions := range m.mentions {
+		for _, mention := range mentions {
+			if e, ok := m.entities[entityID]; ok && mention.MemoryID == memoryID {
+				result = append(result, *e)
+				break
+			}
+		}
+	}
+	return result, nil
 }
 
 func (m *mockEntityStore) FindByEmbeddingSimilarity(ctx context.Context, agentID uuid.UUID, entityType domain.EntityType, embedding []float32, threshold float32, limit int) ([]domain.Entity, error) {
@@ -249,6 +265,117 @@ func TestHybridRecallService_VectorOnlyRecall(t *testing.T) {
 	}
 }
 
+type recordingColdSummarizer struct {
+	enqueued []uuid.UUID
+}
+
+func (r *recordingColdSummarizer) Enqueue(m domain.Memory) {
+	r.enqueued = append(r.enqueued, m.ID)
+}
+
+func TestHybridRecallService_ControlSkipsUsageSideEffects(t *testing.T) {
+	memStore := newMockMemoryStore()
+	svc := NewHybridRecallService(memStore, newMockGraphStore(), newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+	cold := &recordingColdSummarizer{}
+	svc.SetColdSummarizer(cold)
+
+	tenantID, agentID := uuid.New(), uuid.New()
+	_ = memStore.Create(context.Background(), &domain.Memory{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       domain.MemoryTypeFact,
+		Content:    "Test memory content",
+		Confidence: 0.2,
+		Embedding:  []float32{0.1, 0.2, 0.3},
+	})
+
+	req := domain.HybridRecallRequest{
+		Query:        "test query",
+		AgentID:      agentID,
+		TenantID:     tenantID,
+		TopK:         10,
+		VectorWeight: 1.0,
+		Control:      true,
+	}
+
+	results, err := svc.Recall(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected control recall to still rank results, got %d", len(results))
+	}
+	if len(cold.enqueued) != 0 {
+		t.Errorf("expected no cold summarization for control recall, got %d", len(cold.enqueued))
+	}
+
+	req.Control = false
+	if _, err := svc.Recall(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cold.enqueued) != 1 {
+		t.Errorf("expected normal recall to enqueue, got %d", len(cold.enqueued))
+	}
+}
+
+func TestHybridRecallService_CollapsesSummarizedMemories(t *testing.T) {
+	memStore := newMockMemoryStore()
+	svc := NewHybridRecallService(memStore, newMockGraphStore(), newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+
+	tenantID := uuid.New()
+	agentID := uuid.New()
+	ctx := context.Background()
+
+	var details []string
+	for i := 0; i < 3; i++ {
+		mem := &domain.Memory{
+			AgentID:    agentID,
+			TenantID:   tenantID,
+			Type:       domain.MemoryTypePreference,
+			Content:    "notification detail",
+			Confidence: 0.9,
+			Embedding:  []float32{0.1, 0.2, 0.3},
+		}
+		_ = memStore.Create(ctx, mem)
+		details = append(details, mem.ID.String())
+	}
+	summary := &domain.Memory{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		Type:       domain.MemoryTypeSummary,
+		Content:    "notification preferences",
+		Confidence: 0.9,
+		Embedding:  []float32{0.1, 0.2, 0.3},
+		Metadata:   map[string]any{domain.SummaryMembersKey: details},
+	}
+	_ = memStore.Create(ctx, summary)
+
+	req := domain.HybridRecallRequest{
+		Query:        "notifications",
+		AgentID:      agentID,
+		TenantID:     tenantID,
+		TopK:         10,
+		VectorWeight: 1.0,
+	}
+
+	results, err := svc.Recall(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != summary.ID {
+		t.Fatalf("expected only the summary, got %d results", len(results))
+	}
+
+	req.ExpandSummaries = true
+	results, err = svc.Recall(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected summary plus 3 details when expanded, got %d", len(results))
+	}
+}
+
 func TestHybridRecallService_WithGraphTraversal(t *testing.T) {
 	// Setup
 	memStore := newMockMemoryStore()
@@ -308,6 +435,78 @@ func TestHybridRecallService_WithGraphTraversal(t *testing.T) {
 	}
 }
 
+// rankedRecallStore returns fixed vector matches in order, like the store's
+// nearest-neighbour search.
+type rankedRecallStore struct {
+	*mockMemoryStore
+	ranked []domain.MemoryWithScore
+}
+
+func (m *rankedRecallStore) Recall(ctx context.Context, emb []float32, agentID uuid.UUID, tenantID uuid.UUID, opts domain.RecallOpts) ([]domain.MemoryWithScore, error) {
+	if len(m.ranked) > opts.TopK {
+		return m.ranked[:opts.TopK], nil
+	}
+	return m.ranked, nil
+}
+
+func TestHybridRecallService_GraphModeBoostsConnectedCandidates(t *testing.T) {
+	graphStore := newMockGraphStore()
+	tenantID := uuid.New()
+	agentID := uuid.New()
+
+	mk := func(content string, score float32) domain.MemoryWithScore {
+		return domain.MemoryWithScore{Memory: domain.Memory{ID: uuid.New(), AgentID: agentID, TenantID: tenantID,
+			Type: domain.MemoryTypeFact, Content: content, Confidence: 0.9}, Score: score}
+	}
+	hit := mk("User is moving to Lisbon", 0.9)
+	near := mk("User likes warm weather", 0.62)
+	linked := mk("User is learning Portuguese", 0.6)
+	store := &rankedRecallStore{mockMemoryStore: newMockMemoryStore(), ranked: []domain.MemoryWithScore{hit, near, linked}}
+
+	_ = graphStore.CreateEdge(context.Background(), &domain.GraphEdge{
+		SourceID: hit.ID, TargetID: linked.ID, RelationType: domain.RelationThematic, Strength: 0.8,
+	})
+	// A neighbour outside the candidate pool is not pulled in.
+	_ = graphStore.CreateEdge(context.Background(), &domain.GraphEdge{
+		SourceID: hit.ID, TargetID: uuid.New(), RelationType: domain.RelationThematic, Strength: 0.9,
+	})
+
+	svc := NewHybridRecallService(store, graphStore, newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+	results, err := svc.Recall(context.Background(), domain.HybridRecallRequest{
+		Query:    "where is the user moving",
+		AgentID:  agentID,
+		TenantID: tenantID,
+		TopK:     1,
+		Mode:     domain.RecallModeGraph,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The direct hit keeps its full similarity rather than losing weight to
+	// the graph.
+	if len(results) != 1 || results[0].ID != hit.ID || results[0].FinalScore != hit.Score {
+		t.Fatalf("expected the direct hit first at its similarity, got %+v", results)
+	}
+
+	results, err = svc.Recall(context.Background(), domain.HybridRecallRequest{
+		Query:    "where is the user moving",
+		AgentID:  agentID,
+		TenantID: tenantID,
+		TopK:     2,
+		Mode:     domain.RecallModeGraph,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != hit.ID || results[1].ID != linked.ID {
+		t.Fatalf("expected the connected candidate to displace the closer unconnected one, got %+v", results)
+	}
+	want := hit.Score * 0.8 * float32(domain.RelationDecayMultipliers[domain.RelationThematic])
+	if results[1].GraphScore != want || results[1].PathLength != 1 {
+		t.Fatalf("expected graph score %f over one hop, got %f over %d", want, results[1].GraphScore, results[1].PathLength)
+	}
+}
+
 func TestHybridRecallService_DefaultValues(t *testing.T) {
 	// Setup
 	memStore := newMockMemoryStore()
@@ -392,3 +591,105 @@ func TestGraphTraversalAlgorithm(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphTraversalFollowsOnlyFilteredRelations(t *testing.T) {
+	memStore := newMockMemoryStore()
+	graphStore := newMockGraphStore()
+	svc := NewHybridRecallService(memStore, graphStore, newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+	ctx := context.Background()
+
+	seed := uuid.New()
+	cause, theme := uuid.New(), uuid.New()
+	_ = graphStore.CreateEdge(ctx, &domain.GraphEdge{SourceID: seed, TargetID: cause, RelationType: domain.RelationCausal, Strength: 0.8})
+	_ = graphStore.CreateEdge(ctx, &domain.GraphEdge{SourceID: seed, TargetID: theme, RelationType: domain.RelationThematic, Strength: 0.8})
+
+	seeds := []domain.MemoryWithScore{{Memory: domain.Memory{ID: seed}, Score: 0.9}}
+	results := svc.traverseGraphWithConstraints(ctx, seeds, 2, domain.TraversalConstraints{
+		RelationFilter: []domain.RelationType{domain.RelationCausal},
+	})
+	if len(results) != 1 || results[0].MemoryID != cause {
+		t.Fatalf("expected only the causal neighbour, got %+v", results)
+	}
+	// Causal links decay more slowly than the default.
+	want := float32(0.9) * 0.8 * float32(domain.RelationDecayMultipliers[domain.RelationCausal])
+	if results[0].GraphRelevance != want || results[0].RelationType != domain.RelationCausal {
+		t.Fatalf("expected relevance %f over a causal edge, got %f over %s", want, results[0].GraphRelevance, results[0].RelationType)
+	}
+}
+
+// stubNeighborhoodStore reports fixed neighbor counts.
+type stubNeighborhoodStore struct {
+	counts map[uuid.UUID]int
+	calls  int
+}
+
+func (s *stubNeighborhoodStore) CountNeighbors(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID, minSim, maxSim float32, k int) (map[uuid.UUID]int, error) {
+	s.calls++
+	return s.counts, nil
+}
+
+func TestHybridRecallService_InterferencePenalizesCrowdedMemories(t *testing.T) {
+	memStore := newMockMemoryStore()
+	svc := NewHybridRecallService(memStore, newMockGraphStore(), newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+
+	tenantID, agentID := uuid.New(), uuid.New()
+	crowded := &domain.Memory{AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact,
+		Content: "User likes tea", Confidence: 0.9, Embedding: []float32{0.1, 0.2, 0.3}}
+	_ = memStore.Create(context.Background(), crowded)
+	neighbors := &stubNeighborhoodStore{counts: map[uuid.UUID]int{crowded.ID: 3}}
+	svc.SetNeighborhoodStore(neighbors)
+
+	req := domain.HybridRecallRequest{Query: "tea", AgentID: agentID, TenantID: tenantID, VectorWeight: 1.0}
+	results, err := svc.Recall(context.Background(), req)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expected one result, got %v (%v)", results, err)
+	}
+	want := float32(1 / (1 + DefaultInterferencePenalty*3))
+	if !approxEqual(results[0].Interference, want, 0.0001) || !approxEqual(results[0].FinalScore, 0.85*want, 0.0001) {
+		t.Errorf("expected interference %v, got %v with score %v", want, results[0].Interference, results[0].FinalScore)
+	}
+
+	req.NoInterference = true
+	results, _ = svc.Recall(context.Background(), req)
+	if results[0].Interference != 0 || !approxEqual(results[0].FinalScore, 0.85, 0.0001) || neighbors.calls != 1 {
+		t.Errorf("opting out should skip the neighbor count, got %+v after %d counts", results[0], neighbors.calls)
+	}
+}
+
+// TestExplainHybrid_ContributionsSumToRecallScore explains real hybrid recall
+// results, with interference and an environment boost applied, and checks the
+// contributions add up to the score recall ranked them on.
+func TestExplainHybrid_ContributionsSumToRecallScore(t *testing.T) {
+	memStore := newMockMemoryStore()
+	svc := NewHybridRecallService(memStore, newMockGraphStore(), newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+	svc.SetEnvironmentBoost(DefaultEnvironmentBoost)
+
+	tenantID, agentID := uuid.New(), uuid.New()
+	env := domain.Environment{Channel: "slack"}
+	var ids []uuid.UUID
+	for _, content := range []string{"User likes tea", "User likes coffee"} {
+		m := &domain.Memory{AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact,
+			Content: content, Confidence: 0.9, Embedding: []float32{0.1, 0.2, 0.3}}
+		m.Metadata = domain.WithEnvironment(m.Metadata, env)
+		_ = memStore.Create(context.Background(), m)
+		ids = append(ids, m.ID)
+	}
+	svc.SetNeighborhoodStore(&stubNeighborhoodStore{counts: map[uuid.UUID]int{ids[0]: 2}})
+
+	req := domain.HybridRecallRequest{Query: "drinks", AgentID: agentID, TenantID: tenantID,
+		VectorWeight: 0.7, GraphWeight: 0.3, Environment: env}
+	results, err := svc.Recall(context.Background(), req)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected two results, got %v (%v)", results, err)
+	}
+
+	ms := &MemoryService{}
+	for i, b := range ms.ExplainHybridScores(req, results) {
+		if results[i].Interference == 0 && results[i].EnvironmentBoost == 0 {
+			t.Errorf("expected factors applied to %s", results[i].Content)
+		}
+		if sum := b.VectorContribution + b.GraphContribution; !approxEqual(float32(sum), results[i].FinalScore, 1e-6) || !floatEq(sum, b.FinalScore) {
+			t.Errorf("%s: contributions sum to %f, recall scored %f, breakdown says %f", results[i].Content, sum, results[i].FinalScore, b.FinalScore)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
@@ -48,6 +49,9 @@ func (d *ImplicitFeedbackDetector) DetectAndApply(ctx context.Context, req Detec
 	if len(req.Memories) == 0 || len(req.Conversation) == 0 {
 		return nil, nil
 	}
+	if d.llmClient == nil {
+		return nil, errors.New("LLM client not configured")
+	}
 
 	// Use LLM to detect implicit feedback
 	feedbacks, err := d.llmClient.DetectImplicitFeedback(ctx, req.Memories, req.Conversation)
@@ -102,6 +106,8 @@ func (d *ImplicitFeedbackDetector) DetectAndApply(ctx context.Context, req Detec
 			d.logger.Warn("failed to update memory on implicit feedback", zap.Error(err))
 			continue
 		}
+		supporting, contradicting := evidenceFor(effect.LogOddsDelta)
+		recordEvidence(ctx, d.logger, d.memoryStore, memory.ID, supporting, contradicting)
 
 		// Set review flag if needed
 		if effect.TriggerReview {
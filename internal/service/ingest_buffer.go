@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrIngestBufferFull    = errors.New("ingest buffer is full")
+	ErrIngestBufferStopped = errors.New("ingest buffer is stopped")
+)
+
+const (
+	DefaultIngestBufferSize    = 1024
+	DefaultIngestBatchSize     = 64
+	DefaultIngestFlushInterval = 200 * time.Millisecond
+
+	// ingestEmbedTimeout bounds one batched embedding call.
+	ingestEmbedTimeout = time.Minute
+)
+
+// IngestItem is one buffered write: an episode or a memory.
+type IngestItem struct {
+	Episode *EncodeInput
+	Memory  *domain.Memory
+}
+
+func (it IngestItem) tenantID() uuid.UUID {
+	if it.Episode != nil {
+		return it.Episode.TenantID
+	}
+	return it.Memory.TenantID
+}
+
+type ingestEntry struct {
+	// ctx carries the submitting request's tenant and trace IDs, not its
+	// lifetime: the write happens after the request has returned.
+	ctx  context.Context
+	item IngestItem
+}
+
+// IngestStats is a snapshot of the buffer.
+type IngestStats struct {
+	Queued   int   `json:"queued"`
+	Capacity int   `json:"capacity"`
+	Accepted int64 `json:"accepted"`
+	Written  int64 `json:"written"`
+	Failed   int64 `json:"failed"`
+}
+
+// IngestBuffer absorbs bursts of episode and memory writes. Submissions are
+// queued in memory and acknowledged at once; a single writer drains the queue
+// in batches of up to batchSize, or whatever arrived within the flush
+// interval, embedding each batch in one provider call when the embedding
+// client supports it and inserting a tenant's episodes in one transaction.
+// Memories are still written one at a time, in arrival order, since each is
+// checked for contradictions against the ones before it.
+//
+// The queue is bounded: a submission that doesn't fit is rejected whole with
+// ErrIngestBufferFull, and callers should back off. Stop writes out
+// everything queued. The buffer is not durable — a crash loses what it holds,
+// so callers that can't lose writes should use the synchronous endpoints.
+type IngestBuffer struct {
+	episodes *EpisodeService
+	memories *MemoryService
+	embedder domain.EmbeddingClient
+	logger   *zap.Logger
+
+	queue     chan ingestEntry
+	batchSize int
+	interval  time.Duration
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	wg      sync.WaitGroup
+
+	accepted, written, failed atomic.Int64
+}
+
+// NewIngestBuffer creates a buffer holding up to size writes. Non-positive
+// sizes and interval fall back to the defaults.
+func NewIngestBuffer(episodes *EpisodeService, memories *MemoryService, embedder domain.EmbeddingClient, size, batchSize int, interval time.Duration, logger *zap.Logger) *IngestBuffer {
+	if size <= 0 {
+		size = DefaultIngestBufferSize
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultIngestBatchSize
+	}
+	if interval <= 0 {
+		interval = DefaultIngestFlushInterval
+	}
+	return &IngestBuffer{
+		episodes:  episodes,
+		memories:  memories,
+		embedder:  embedder,
+		logger:    logger,
+		queue:     make(chan ingestEntry, size),
+		batchSize: batchSize,
+		interval:  interval,
+	}
+}
+
+// Submit queues items, all or none. It never blocks: when the queue lacks
+// room for every item it returns ErrIngestBufferFull.
+func (b *IngestBuffer) Submit(ctx context.Context, items []IngestItem) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		return ErrIngestBufferStopped
+	}
+	// Only the writer takes from the queue, so the room seen here can only grow.
+	if len(items) > cap(b.queue)-len(b.queue) {
+		return ErrIngestBufferFull
+	}
+	base := domain.CopyTraceIDs(context.Background(), ctx)
+	for _, it := range items {
+		b.queue <- ingestEntry{ctx: domain.WithTenantID(base, it.tenantID()), item: it}
+	}
+	b.accepted.Add(int64(len(items)))
+	return nil
+}
+
+// Stats returns the buffer's fill and lifetime counters.
+func (b *IngestBuffer) Stats() IngestStats {
+	return IngestStats{
+		Queued:   len(b.queue),
+		Capacity: cap(b.queue),
+		Accepted: b.accepted.Load(),
+		Written:  b.written.Load(),
+		Failed:   b.failed.Load(),
+	}
+}
+
+// Start runs the writer.
+func (b *IngestBuffer) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started || b.stopped {
+		return
+	}
+	b.started = true
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.drain()
+	}()
+	b.logger.Info("ingest buffer started",
+		zap.Int("capacity", cap(b.queue)), zap.Int("batch_size", b.batchSize), zap.Duration("interval", b.interval))
+}
+
+// Stop rejects new submissions and returns once everything queued has been
+// written.
+func (b *IngestBuffer) Stop() {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return
+	}
+	b.stopped = true
+	close(b.queue)
+	started := b.started
+	b.mu.Unlock()
+
+	if started {
+		b.wg.Wait()
+	} else {
+		b.drain()
+	}
+	b.logger.Info("ingest buffer stopped", zap.Int64("written", b.written.Load()), zap.Int64("failed", b.failed.Load()))
+}
+
+// drain writes batches until the queue is closed and empty.
+func (b *IngestBuffer) drain() {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	batch := make([]ingestEntry, 0, b.batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			guardPanic(b.logger, "ingest buffer flush", func() { b.flush(batch) })
+			batch = batch[:0]
+		}
+	}
+	for {
+		select {
+		case e, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= b.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// flush writes one batch: episodes grouped by tenant, then memories in order.
+func (b *IngestBuffer) flush(batch []ingestEntry) {
+	tenants, groups := groupByTenant(batch)
+	for _, tid := range tenants {
+		b.embed(tenantContext(tid), groups[tid])
+	}
+
+	for _, tid := range tenants {
+		var group []ingestEntry
+		for _, e := range groups[tid] {
+			if e.item.Episode != nil {
+				group = append(group, e)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		inputs := make([]EncodeInput, len(group))
+		for i, e := range group {
+			inputs[i] = *e.item.Episode
+		}
+		_, errs := b.episodes.EncodeBatch(tenantContext(tid), inputs)
+		for i, err := range errs {
+			b.record(group[i], err)
+		}
+	}
+
+	for _, e := range batch {
+		if e.item.Memory != nil {
+			_, err := b.memories.Create(e.ctx, e.item.Memory)
+			b.record(e, err)
+		}
+	}
+}
+
+// groupByTenant splits a batch by tenant, in order of each tenant's first
+// entry, so the writes made for several entries at once run as that tenant.
+func groupByTenant(batch []ingestEntry) ([]uuid.UUID, map[uuid.UUID][]ingestEntry) {
+	var tenants []uuid.UUID
+	groups := map[uuid.UUID][]ingestEntry{}
+	for _, e := range batch {
+		tid := e.item.tenantID()
+		if _, ok := groups[tid]; !ok {
+			tenants = append(tenants, tid)
+		}
+		groups[tid] = append(groups[tid], e)
+	}
+	return tenants, groups
+}
+
+// tenantContext is the context of a write made for several of a tenant's
+// entries: it runs as the tenant but carries no single request's trace IDs.
+func tenantContext(tenantID uuid.UUID) context.Context {
+	return domain.WithTenantID(context.Background(), tenantID)
+}
+
+func (b *IngestBuffer) record(e ingestEntry, err error) {
+	if err == nil {
+		b.written.Add(1)
+		return
+	}
+	b.failed.Add(1)
+	what := "memory"
+	if e.item.Episode != nil {
+		what = "episode"
+	}
+	logFor(e.ctx, b.logger).Warn("buffered write failed", zap.String("kind", what), zap.Error(err))
+}
+
+// embed fills in the embeddings of one tenant's entries with one call when
+// the client can batch. On failure, or without batch support, the services
+// embed each write themselves.
+func (b *IngestBuffer) embed(ctx context.Context, batch []ingestEntry) {
+	be, ok := b.embedder.(domain.BatchEmbedder)
+	if !ok {
+		return
+	}
+	texts := make([]string, 0, len(batch))
+	targets := make([]*[]float32, 0, len(batch))
+	for _, e := range batch {
+		switch {
+		case e.item.Episode != nil && len(e.item.Episode.Embedding) == 0 && e.item.Episode.RawContent != "":
+			texts = append(texts, e.item.Episode.RawContent)
+			targets = append(targets, &e.item.Episode.Embedding)
+		case e.item.Memory != nil && len(e.item.Memory.Embedding) == 0 && e.item.Memory.Content != "":
+			texts = append(texts, e.item.Memory.Content)
+			targets = append(targets, &e.item.Memory.Embedding)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ingestEmbedTimeout)
+	defer cancel()
+	vecs, err := be.EmbedBatch(ctx, texts)
+	if err != nil || len(vecs) != len(texts) {
+		logFor(ctx, b.logger).Warn("batched embedding failed, embedding one by one", zap.Int("texts", len(texts)), zap.Error(err))
+		return
+	}
+	for i, v := range vecs {
+		*targets[i] = v
+	}
+}
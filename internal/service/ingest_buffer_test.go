@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// batchEmbedder records batched and single embedding calls.
+type batchEmbedder struct {
+	mu      sync.Mutex
+	batches [][]string
+	tenants []uuid.UUID // the tenant each batch ran as
+	singles int
+}
+
+func (b *batchEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.singles++
+	return make([]float32, 1536), nil
+}
+
+func (b *batchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.batches = append(b.batches, texts)
+	tid, _ := domain.TenantIDFrom(ctx)
+	b.tenants = append(b.tenants, tid)
+	vecs := make([][]float32, len(texts))
+	for i := range vecs {
+		vecs[i] = make([]float32, 1536)
+		vecs[i][0] = 1
+	}
+	return vecs, nil
+}
+
+func setupIngestTest(size, batchSize int) (*IngestBuffer, *batchEmbedder, *mockEpisodeStore, *mockMemoryStore, uuid.UUID, uuid.UUID) {
+	agents := newMockAgentStore()
+	tenantID := uuid.New()
+	agent := &domain.Agent{TenantID: tenantID, ExternalID: "bot-1", Name: "Test Bot"}
+	_ = agents.Create(context.Background(), agent)
+
+	emb := &batchEmbedder{}
+	episodeStore, memStore := newMockEpisodeStore(), newMockMemoryStore()
+	episodes := NewEpisodeService(episodeStore, agents, emb, nil, testLogger())
+	memories := NewMemoryService(memStore, agents, emb, nil, testLogger())
+	return NewIngestBuffer(episodes, memories, emb, size, batchSize, 0, testLogger()), emb, episodeStore, memStore, tenantID, agent.ID
+}
+
+func TestIngestBuffer_BatchesEmbeddingsAndFlushesOnStop(t *testing.T) {
+	buf, emb, episodeStore, memStore, tenantID, agentID := setupIngestTest(10, 10)
+
+	items := []IngestItem{
+		{Episode: &EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: "Deployed the billing fix"}},
+		{Memory: &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode"}},
+		{Episode: &EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: "Rolled back the release"}},
+		{Memory: &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User works in Berlin"}},
+	}
+	if err := buf.Submit(context.Background(), items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.Stats(); got.Queued != 4 || got.Accepted != 4 || got.Written != 0 {
+		t.Fatalf("expected four queued writes, got %+v", got)
+	}
+
+	// Never started: Stop still writes everything out.
+	buf.Stop()
+
+	if len(emb.batches) != 1 || len(emb.batches[0]) != 4 || emb.singles != 0 {
+		t.Fatalf("expected one batched embedding call for all four writes, got %v batches and %d singles", emb.batches, emb.singles)
+	}
+	if len(episodeStore.episodes) != 2 || len(memStore.memories) != 2 {
+		t.Fatalf("expected 2 episodes and 2 memories, got %d and %d", len(episodeStore.episodes), len(memStore.memories))
+	}
+	for _, m := range memStore.memories {
+		if len(m.Embedding) == 0 || m.Embedding[0] != 1 {
+			t.Fatalf("expected the batched embedding to be stored, got %v", m.Embedding[:1])
+		}
+	}
+	if got := buf.Stats(); got.Queued != 0 || got.Written != 4 || got.Failed != 0 {
+		t.Fatalf("unexpected stats after stop: %+v", got)
+	}
+}
+
+func TestIngestBuffer_BoundedQueueRejectsWholeSubmissions(t *testing.T) {
+	buf, _, _, _, tenantID, agentID := setupIngestTest(2, 10)
+	mem := func(content string) IngestItem {
+		return IngestItem{Memory: &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: content}}
+	}
+	ctx := context.Background()
+
+	if err := buf.Submit(ctx, []IngestItem{mem("a"), mem("b"), mem("c")}); !errors.Is(err, ErrIngestBufferFull) {
+		t.Fatalf("expected ErrIngestBufferFull, got %v", err)
+	}
+	if buf.Stats().Queued != 0 {
+		t.Fatal("expected a rejected submission to queue nothing")
+	}
+	if err := buf.Submit(ctx, []IngestItem{mem("a"), mem("b")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := buf.Submit(ctx, []IngestItem{mem("c")}); !errors.Is(err, ErrIngestBufferFull) {
+		t.Fatalf("expected ErrIngestBufferFull, got %v", err)
+	}
+
+	buf.Stop()
+	if err := buf.Submit(ctx, []IngestItem{mem("d")}); !errors.Is(err, ErrIngestBufferStopped) {
+		t.Fatalf("expected ErrIngestBufferStopped, got %v", err)
+	}
+	if got := buf.Stats(); got.Accepted != 2 || got.Written != 2 {
+		t.Fatalf("expected the two accepted memories to be written, got %+v", got)
+	}
+}
+
+func TestIngestBuffer_WriterFlushesFullBatches(t *testing.T) {
+	buf, emb, episodeStore, _, tenantID, agentID := setupIngestTest(10, 2)
+	buf.Start()
+
+	var items []IngestItem
+	for _, c := range []string{"one", "two", "three"} {
+		items = append(items, IngestItem{Episode: &EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: c}})
+	}
+	if err := buf.Submit(context.Background(), items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf.Stop()
+
+	if len(episodeStore.episodes) != 3 {
+		t.Fatalf("expected 3 episodes, got %d", len(episodeStore.episodes))
+	}
+	for _, b := range emb.batches {
+		if len(b) > 2 {
+			t.Fatalf("expected batches of at most 2, got %v", emb.batches)
+		}
+	}
+}
+
+func TestIngestBuffer_BatchesRunAsEachTenant(t *testing.T) {
+	buf, emb, _, _, tenantID, agentID := setupIngestTest(10, 10)
+	otherTenant := uuid.New()
+
+	if err := buf.Submit(context.Background(), []IngestItem{
+		{Memory: &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode"}},
+		{Memory: &domain.Memory{AgentID: uuid.New(), TenantID: otherTenant, Content: "Ships on Fridays"}},
+		{Episode: &EncodeInput{AgentID: agentID, TenantID: tenantID, RawContent: "Deployed the billing fix"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf.Stop()
+
+	if len(emb.batches) != 2 {
+		t.Fatalf("expected one embedding batch per tenant, got %v", emb.batches)
+	}
+	if emb.tenants[0] != tenantID || len(emb.batches[0]) != 2 || emb.tenants[1] != otherTenant || len(emb.batches[1]) != 1 {
+		t.Errorf("expected each batch to run as its own tenant, got %v for %v", emb.tenants, emb.batches)
+	}
+}
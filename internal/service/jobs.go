@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrJobQueueFull   = errors.New("job queue is full")
+	ErrJobPoolStopped = errors.New("job pool is stopped")
+)
+
+const (
+	DefaultJobWorkers   = 4
+	DefaultJobQueueSize = 256
+
+	// jobRetention is how long finished jobs stay queryable.
+	jobRetention = time.Hour
+)
+
+// JobFunc is the work a job runs. Its result is stored on the job.
+type JobFunc func(ctx context.Context) (any, error)
+
+type jobEntry struct {
+	job     domain.Job
+	fn      JobFunc
+	timeout time.Duration
+	done    chan struct{}
+}
+
+// JobPool runs background jobs on a fixed set of workers. Async extraction and
+// consolidation passes share it, so together they never run more concurrent
+// LLM-heavy work than there are workers. Jobs live in memory: lookups only see
+// jobs submitted to this process, and finished jobs are dropped after
+// jobRetention.
+type JobPool struct {
+	workers int
+	queue   chan *jobEntry
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu      sync.Mutex
+	jobs    map[uuid.UUID]*jobEntry
+	stopped bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewJobPool creates a pool of the given size. Non-positive arguments fall
+// back to the defaults.
+func NewJobPool(workers, queueSize int, logger *zap.Logger) *JobPool {
+	if workers <= 0 {
+		workers = DefaultJobWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultJobQueueSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobPool{
+		workers: workers,
+		queue:   make(chan *jobEntry, queueSize),
+		logger:  logger,
+		now:     time.Now,
+		jobs:    make(map[uuid.UUID]*jobEntry),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start launches the workers.
+func (p *JobPool) Start() {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for e := range p.queue {
+				p.run(e)
+			}
+		}()
+	}
+	p.logger.Info("job pool started", zap.Int("workers", p.workers))
+}
+
+// Stop cancels running jobs, fails queued ones, and waits for the workers.
+func (p *JobPool) Stop() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	p.cancel()
+	p.wg.Wait()
+	// Fail anything the workers left behind (or everything, if never started)
+	for e := range p.queue {
+		p.run(e)
+	}
+	p.logger.Info("job pool stopped")
+}
+
+// Submit queues fn and returns the queued job. timeout bounds the run
+// (0 = no limit beyond pool shutdown). ctx only supplies the request and
+// correlation IDs passed on to fn, which also sees tenantID on its context;
+// the job outlives ctx. Never blocks: a full
+// queue returns ErrJobQueueFull.
+func (p *JobPool) Submit(ctx context.Context, tenantID, agentID uuid.UUID, kind domain.JobKind, timeout time.Duration, fn JobFunc) (*domain.Job, error) {
+	e := &jobEntry{
+		job: domain.Job{
+			ID:            uuid.New(),
+			TenantID:      tenantID,
+			AgentID:       agentID,
+			Kind:          kind,
+			Status:        domain.JobQueued,
+			RequestID:     domain.RequestIDFrom(ctx),
+			CorrelationID: domain.CorrelationIDFrom(ctx),
+			CreatedAt:     p.now(),
+		},
+		fn:      fn,
+		timeout: timeout,
+		done:    make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return nil, ErrJobPoolStopped
+	}
+	p.pruneLocked()
+	select {
+	case p.queue <- e:
+	default:
+		return nil, ErrJobQueueFull
+	}
+	p.jobs[e.job.ID] = e
+	job := e.job
+	return &job, nil
+}
+
+// Get returns a snapshot of the job, scoped to tenantID.
+func (p *JobPool) Get(id, tenantID uuid.UUID) (*domain.Job, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.jobs[id]
+	if !ok || e.job.TenantID != tenantID {
+		return nil, ErrJobNotFound
+	}
+	job := e.job
+	return &job, nil
+}
+
+// Wait blocks until the job finishes or ctx is done, then returns its
+// snapshot.
+func (p *JobPool) Wait(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	p.mu.Lock()
+	e, ok := p.jobs[id]
+	p.mu.Unlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	job := e.job
+	return &job, nil
+}
+
+func (p *JobPool) run(e *jobEntry) {
+	defer close(e.done)
+
+	if p.ctx.Err() != nil {
+		p.finish(e, nil, ErrJobPoolStopped)
+		return
+	}
+
+	p.mu.Lock()
+	started := p.now()
+	e.job.Status = domain.JobRunning
+	e.job.StartedAt = &started
+	p.mu.Unlock()
+
+	ctx, cancel := p.ctx, context.CancelFunc(func() {})
+	if e.timeout > 0 {
+		ctx, cancel = context.WithTimeout(p.ctx, e.timeout)
+	}
+	defer cancel()
+	if e.job.TenantID != uuid.Nil {
+		ctx = domain.WithTenantID(ctx, e.job.TenantID)
+	}
+	if e.job.RequestID != "" {
+		ctx = domain.WithRequestID(ctx, e.job.RequestID)
+	}
+	if e.job.CorrelationID != "" {
+		ctx = domain.WithCorrelationID(ctx, e.job.CorrelationID)
+	}
+
+	var (
+		result any
+		err    = fmt.Errorf("%s job panicked", e.job.Kind)
+	)
+	guardPanic(logFor(ctx, p.logger), string(e.job.Kind)+" job "+e.job.ID.String(), func() {
+		result, err = e.fn(ctx)
+	})
+	p.finish(e, result, err)
+}
+
+func (p *JobPool) finish(e *jobEntry, result any, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	finished := p.now()
+	e.job.FinishedAt = &finished
+	if err != nil {
+		e.job.Status = domain.JobFailed
+		e.job.Error = err.Error()
+		return
+	}
+	e.job.Status = domain.JobSucceeded
+	e.job.Result = result
+}
+
+// pruneLocked drops jobs that finished more than jobRetention ago.
+func (p *JobPool) pruneLocked() {
+	cutoff := p.now().Add(-jobRetention)
+	for id, e := range p.jobs {
+		if e.job.FinishedAt != nil && e.job.FinishedAt.Before(cutoff) {
+			delete(p.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func waitJob(t *testing.T, p *JobPool, id uuid.UUID) *domain.Job {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := p.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	return job
+}
+
+func TestJobPool_RunsJobs(t *testing.T) {
+	p := NewJobPool(2, 10, testLogger())
+	p.Start()
+	defer p.Stop()
+	tenantID := uuid.New()
+
+	ok, err := p.Submit(context.Background(), tenantID, uuid.New(), domain.JobKindExtraction, time.Second, func(context.Context) (any, error) {
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if ok.Status != domain.JobQueued {
+		t.Errorf("Status = %s, want queued", ok.Status)
+	}
+	failed, _ := p.Submit(context.Background(), tenantID, uuid.New(), domain.JobKindExtraction, 0, func(context.Context) (any, error) {
+		return nil, errors.New("llm down")
+	})
+	panicked, _ := p.Submit(context.Background(), tenantID, uuid.New(), domain.JobKindConsolidation, 0, func(context.Context) (any, error) {
+		panic("boom")
+	})
+
+	if job := waitJob(t, p, ok.ID); job.Status != domain.JobSucceeded || job.Result != 42 || job.StartedAt == nil || job.FinishedAt == nil {
+		t.Errorf("ok job = %+v, want succeeded with result 42", job)
+	}
+	if job := waitJob(t, p, failed.ID); job.Status != domain.JobFailed || job.Error != "llm down" {
+		t.Errorf("failed job = %+v, want failed with error", job)
+	}
+	if job := waitJob(t, p, panicked.ID); job.Status != domain.JobFailed || job.Error == "" {
+		t.Errorf("panicked job = %+v, want failed", job)
+	}
+}
+
+func TestJobPool_GetIsTenantScoped(t *testing.T) {
+	p := NewJobPool(1, 10, testLogger())
+	tenantID := uuid.New()
+
+	job, err := p.Submit(context.Background(), tenantID, uuid.New(), domain.JobKindExtraction, 0, func(context.Context) (any, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	if _, err := p.Get(job.ID, tenantID); err != nil {
+		t.Errorf("Get own job: %v", err)
+	}
+	if _, err := p.Get(job.ID, uuid.New()); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get other tenant's job: err = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestJobPool_QueueFull(t *testing.T) {
+	p := NewJobPool(1, 1, testLogger()) // not started, so nothing drains
+	noop := func(context.Context) (any, error) { return nil, nil }
+
+	if _, err := p.Submit(context.Background(), uuid.New(), uuid.New(), domain.JobKindExtraction, 0, noop); err != nil {
+		t.Fatalf("first Submit failed: %v", err)
+	}
+	if _, err := p.Submit(context.Background(), uuid.New(), uuid.New(), domain.JobKindExtraction, 0, noop); !errors.Is(err, ErrJobQueueFull) {
+		t.Errorf("err = %v, want ErrJobQueueFull", err)
+	}
+}
+
+func TestJobPool_StopFailsQueuedJobs(t *testing.T) {
+	p := NewJobPool(1, 10, testLogger())
+	tenantID := uuid.New()
+
+	ran := false
+	job, _ := p.Submit(context.Background(), tenantID, uuid.New(), domain.JobKindExtraction, 0, func(context.Context) (any, error) {
+		ran = true
+		return nil, nil
+	})
+	p.Stop()
+
+	got, err := p.Get(job.ID, tenantID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if ran || got.Status != domain.JobFailed {
+		t.Errorf("queued job after Stop: ran=%v status=%s, want not run and failed", ran, got.Status)
+	}
+	if _, err := p.Submit(context.Background(), tenantID, uuid.New(), domain.JobKindExtraction, 0, nil); !errors.Is(err, ErrJobPoolStopped) {
+		t.Errorf("Submit after Stop: err = %v, want ErrJobPoolStopped", err)
+	}
+}
+
+func TestJobPool_CarriesTraceIDs(t *testing.T) {
+	p := NewJobPool(1, 10, testLogger())
+	p.Start()
+	defer p.Stop()
+
+	ctx := domain.WithCorrelationID(domain.WithRequestID(context.Background(), "req-1"), "flow-1")
+	var gotRequest, gotCorrelation string
+	job, err := p.Submit(ctx, uuid.New(), uuid.New(), domain.JobKindExtraction, 0, func(ctx context.Context) (any, error) {
+		gotRequest, gotCorrelation = domain.RequestIDFrom(ctx), domain.CorrelationIDFrom(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if job.RequestID != "req-1" || job.CorrelationID != "flow-1" {
+		t.Errorf("job IDs = %q, %q", job.RequestID, job.CorrelationID)
+	}
+	waitJob(t, p, job.ID)
+	if gotRequest != "req-1" || gotCorrelation != "flow-1" {
+		t.Errorf("job ctx IDs = %q, %q; want req-1, flow-1", gotRequest, gotCorrelation)
+	}
+}
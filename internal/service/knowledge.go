@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	KnowledgeMaxMemories    = 20  // Beliefs recalled for a narrative
+	KnowledgeMaxEpisodes    = 5   // Episodes recalled for a narrative
+	KnowledgeMaxSchemas     = 3   // Schemas recalled for a narrative
+	KnowledgeMinSimilarity  = 0.5 // Episodes and schemas less similar to the topic are left out
+	KnowledgeMinConfidence  = 0.1 // Beliefs below this are too doubtful to narrate
+	knowledgeMaxTopicLength = 500
+)
+
+var (
+	ErrKnowledgeTopicEmpty    = errors.New("topic is required")
+	ErrKnowledgeTopicTooLong  = errors.New("topic is too long")
+	ErrKnowledgeNotConfigured = errors.New("knowledge narratives need an embedding client and an LLM")
+)
+
+// KnowledgeService answers "what do you know about X": it recalls the
+// agent's beliefs, episodes and schemas about a topic and has the LLM write
+// them up as a narrative whose every claim cites the records it rests on.
+type KnowledgeService struct {
+	memoryStore     domain.MemoryStore
+	episodeStore    domain.EpisodeStore
+	schemaStore     domain.SchemaStore
+	embeddingClient domain.EmbeddingClient
+	llmClient       domain.LLMClient
+	logger          *zap.Logger
+}
+
+// NewKnowledgeService creates a new knowledge service.
+func NewKnowledgeService(
+	memoryStore domain.MemoryStore,
+	episodeStore domain.EpisodeStore,
+	schemaStore domain.SchemaStore,
+	embeddingClient domain.EmbeddingClient,
+	llmClient domain.LLMClient,
+	logger *zap.Logger,
+) *KnowledgeService {
+	return &KnowledgeService{
+		memoryStore:     memoryStore,
+		episodeStore:    episodeStore,
+		schemaStore:     schemaStore,
+		embeddingClient: embeddingClient,
+		llmClient:       llmClient,
+		logger:          logger,
+	}
+}
+
+// Describe writes up what the agent knows about topic. Claims citing nothing
+// that was recalled are dropped, so the narrative only says what the records
+// support.
+func (s *KnowledgeService) Describe(ctx context.Context, agentID, tenantID uuid.UUID, topic string) (*domain.KnowledgeNarrative, error) {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return nil, ErrKnowledgeTopicEmpty
+	}
+	if len(topic) > knowledgeMaxTopicLength {
+		return nil, ErrKnowledgeTopicTooLong
+	}
+	if s.embeddingClient == nil || s.llmClient == nil {
+		return nil, ErrKnowledgeNotConfigured
+	}
+
+	sources, err := s.recall(ctx, agentID, tenantID, topic)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources.Memories) == 0 && len(sources.Episodes) == 0 && len(sources.Schemas) == 0 {
+		return &domain.KnowledgeNarrative{Topic: topic, Claims: []domain.KnowledgeClaim{}}, nil
+	}
+
+	narrative, err := s.llmClient.NarrateKnowledge(ctx, topic, sources)
+	if err != nil {
+		return nil, err
+	}
+	narrative.Topic = topic
+	narrative.Claims = citeClaims(narrative.Claims, sources)
+
+	logFor(ctx, s.logger).Debug("knowledge narrative",
+		zap.String("agent_id", agentID.String()),
+		zap.Int("memories", len(sources.Memories)),
+		zap.Int("episodes", len(sources.Episodes)),
+		zap.Int("schemas", len(sources.Schemas)),
+		zap.Int("claims", len(narrative.Claims)))
+
+	return narrative, nil
+}
+
+// recall gathers the records about topic. Beliefs are required; episodes and
+// schemas only enrich the narrative, so failing to find them is logged.
+func (s *KnowledgeService) recall(ctx context.Context, agentID, tenantID uuid.UUID, topic string) (domain.KnowledgeSources, error) {
+	var sources domain.KnowledgeSources
+
+	embedding, err := s.embeddingClient.Embed(ctx, topic)
+	if err != nil {
+		return sources, err
+	}
+
+	memories, err := s.memoryStore.Recall(ctx, embedding, agentID, tenantID, domain.RecallOpts{
+		TopK:          KnowledgeMaxMemories,
+		MinConfidence: KnowledgeMinConfidence,
+	})
+	if err != nil {
+		return sources, err
+	}
+	for _, m := range memories {
+		sources.Memories = append(sources.Memories, m.Memory)
+	}
+
+	if s.episodeStore != nil {
+		episodes, err := s.episodeStore.FindSimilar(ctx, agentID, tenantID, embedding, KnowledgeMinSimilarity, KnowledgeMaxEpisodes)
+		if err != nil {
+			logFor(ctx, s.logger).Debug("failed to recall episodes for knowledge narrative", zap.Error(err))
+		}
+		for _, e := range episodes {
+			sources.Episodes = append(sources.Episodes, e.Episode)
+		}
+	}
+
+	if s.schemaStore != nil {
+		schemas, err := s.schemaStore.FindSimilar(ctx, agentID, tenantID, embedding, KnowledgeMinSimilarity, KnowledgeMaxSchemas)
+		if err != nil {
+			logFor(ctx, s.logger).Debug("failed to recall schemas for knowledge narrative", zap.Error(err))
+		}
+		for _, sc := range schemas {
+			sources.Schemas = append(sources.Schemas, sc.Schema)
+		}
+	}
+
+	return sources, nil
+}
+
+// citeClaims resolves each claim's citations against the recalled records,
+// dropping IDs that weren't among them and claims left citing nothing, and
+// sets each claim's confidence to the mean of its citations'.
+func citeClaims(claims []domain.KnowledgeClaim, sources domain.KnowledgeSources) []domain.KnowledgeClaim {
+	known := make(map[uuid.UUID]domain.KnowledgeCitation)
+	for _, m := range sources.Memories {
+		known[m.ID] = domain.KnowledgeCitation{ID: m.ID, Kind: domain.KnowledgeSourceMemory, Confidence: m.Confidence}
+	}
+	for _, e := range sources.Episodes {
+		known[e.ID] = domain.KnowledgeCitation{ID: e.ID, Kind: domain.KnowledgeSourceEpisode, Confidence: e.MemoryStrength}
+	}
+	for _, sc := range sources.Schemas {
+		known[sc.ID] = domain.KnowledgeCitation{ID: sc.ID, Kind: domain.KnowledgeSourceSchema, Confidence: sc.Confidence}
+	}
+
+	out := []domain.KnowledgeClaim{}
+	for _, c := range claims {
+		if c.Statement == "" {
+			continue
+		}
+		cited := make(map[uuid.UUID]bool)
+		var citations []domain.KnowledgeCitation
+		var sum float32
+		for _, ref := range c.Citations {
+			citation, ok := known[ref.ID]
+			if !ok || cited[ref.ID] {
+				continue
+			}
+			cited[ref.ID] = true
+			citations = append(citations, citation)
+			sum += citation.Confidence
+		}
+		if len(citations) == 0 {
+			continue
+		}
+		out = append(out, domain.KnowledgeClaim{
+			Statement:  c.Statement,
+			Citations:  citations,
+			Confidence: sum / float32(len(citations)),
+		})
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestKnowledgeService_Describe(t *testing.T) {
+	ctx := context.Background()
+	memStore := newMockMemoryStore()
+	llm := newMockLLMClient()
+	svc := NewKnowledgeService(memStore, newMockEpisodeStore(), newMockSchemaStore(), &mockEmbeddingClient{}, llm, testLogger())
+
+	tenantID, agentID := uuid.New(), uuid.New()
+	vegetarian := &domain.Memory{AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact, Content: "User is vegetarian", Confidence: 0.9}
+	cooks := &domain.Memory{AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact, Content: "User cooks at home", Confidence: 0.5}
+	_ = memStore.Create(ctx, vegetarian)
+	_ = memStore.Create(ctx, cooks)
+
+	llm.narrative = &domain.KnowledgeNarrative{
+		Summary: "The user is a vegetarian who cooks at home.",
+		Claims: []domain.KnowledgeClaim{
+			{Statement: "The user is a vegetarian who cooks", Citations: []domain.KnowledgeCitation{{ID: vegetarian.ID}, {ID: cooks.ID}, {ID: vegetarian.ID}}},
+			{Statement: "The user is allergic to nuts", Citations: []domain.KnowledgeCitation{{ID: uuid.New()}}},
+			{Statement: "The user likes pasta"},
+		},
+	}
+
+	narrative, err := svc.Describe(ctx, agentID, tenantID, "  diet ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if narrative.Topic != "diet" || narrative.Summary == "" {
+		t.Fatalf("unexpected narrative: %+v", narrative)
+	}
+	if len(llm.narrateCalls) != 1 || len(llm.narrateCalls[0].Memories) != 2 {
+		t.Fatalf("expected both memories to be narrated, got %+v", llm.narrateCalls)
+	}
+	if len(narrative.Claims) != 1 {
+		t.Fatalf("expected claims without recalled citations to be dropped, got %+v", narrative.Claims)
+	}
+	claim := narrative.Claims[0]
+	if len(claim.Citations) != 2 || claim.Citations[0].Kind != domain.KnowledgeSourceMemory || claim.Citations[1].Confidence != 0.5 {
+		t.Fatalf("unexpected citations: %+v", claim.Citations)
+	}
+	if claim.Confidence < 0.69 || claim.Confidence > 0.71 {
+		t.Fatalf("expected the mean citation confidence 0.7, got %v", claim.Confidence)
+	}
+}
+
+func TestKnowledgeService_Describe_NothingKnown(t *testing.T) {
+	llm := newMockLLMClient()
+	svc := NewKnowledgeService(newMockMemoryStore(), newMockEpisodeStore(), newMockSchemaStore(), &mockEmbeddingClient{}, llm, testLogger())
+
+	narrative, err := svc.Describe(context.Background(), uuid.New(), uuid.New(), "diet")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(narrative.Claims) != 0 || len(llm.narrateCalls) != 0 {
+		t.Fatalf("expected an empty narrative without calling the LLM, got %+v", narrative)
+	}
+}
+
+func TestKnowledgeService_Describe_Errors(t *testing.T) {
+	svc := NewKnowledgeService(newMockMemoryStore(), nil, nil, &mockEmbeddingClient{}, nil, testLogger())
+	if _, err := svc.Describe(context.Background(), uuid.New(), uuid.New(), " "); !errors.Is(err, ErrKnowledgeTopicEmpty) {
+		t.Fatalf("expected ErrKnowledgeTopicEmpty, got %v", err)
+	}
+	if _, err := svc.Describe(context.Background(), uuid.New(), uuid.New(), "diet"); !errors.Is(err, ErrKnowledgeNotConfigured) {
+		t.Fatalf("expected ErrKnowledgeNotConfigured, got %v", err)
+	}
+}
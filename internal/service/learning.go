@@ -26,6 +26,10 @@ type LearningService struct {
 	episodeMemUsageStore domain.EpisodeMemoryUsageStore
 	mutationLogStore     domain.MutationLogStore
 	learningStatsStore   domain.LearningStatsStore
+	procedureStore       domain.ProcedureStore
+	convActStore         domain.ConversationActivationStore
+	agents               domain.AgentRegistry
+	fanout               DatabaseFanout
 	uow                  *store.UnitOfWork
 	logger               *zap.Logger
 
@@ -69,6 +73,12 @@ func (s *LearningService) SetLearningStatsStore(store domain.LearningStatsStore)
 	s.learningStatsStore = store
 }
 
+// SetAgentRegistry lists agents with live memories for the stats run from the
+// agents table.
+func (s *LearningService) SetAgentRegistry(r domain.AgentRegistry) {
+	s.agents = r
+}
+
 func (s *LearningService) SetUnitOfWork(uow *store.UnitOfWork) {
 	s.uow = uow
 }
@@ -112,13 +122,20 @@ func (s *LearningService) Stop() {
 	s.wg.Wait()
 }
 
+// SetFanout runs the stats worker over every tenant database.
+func (s *LearningService) SetFanout(f DatabaseFanout) {
+	s.fanout = f
+}
+
 func (s *LearningService) runStatsTick(baseCtx context.Context) {
 	ctx, cancel := context.WithTimeout(baseCtx, 60*time.Second)
 	defer cancel()
 	guardPanic(s.logger, "learning-stats tick", func() {
-		if err := s.RunStats(ctx); err != nil {
-			s.logger.Error("learning-stats run failed", zap.Error(err))
-		}
+		s.fanout.each(ctx, func(ctx context.Context) {
+			if err := s.RunStats(ctx); err != nil {
+				s.logger.Error("learning-stats run failed", zap.Error(err))
+			}
+		})
 	})
 }
 
@@ -127,19 +144,19 @@ func (s *LearningService) RunStats(ctx context.Context) error {
 		return nil
 	}
 
-	agentIDs, err := s.memoryStore.ListDistinctAgentIDs(ctx)
+	agents, err := agentsWithWork(ctx, s.agents, domain.AgentWorkFilter{LiveMemories: true}, s.memoryStore.ListDistinctAgentIDs)
 	if err != nil {
 		return err
 	}
 
-	now := time.Now().UTC()
+	now := timeNow().UTC()
 	periodEnd := now.Truncate(24 * time.Hour).Add(24 * time.Hour) // start of next UTC day
 	periodStart := periodEnd.Add(-s.window)
 
-	for _, agentID := range agentIDs {
-		if _, err := s.ComputeLearningStats(ctx, agentID, periodStart, periodEnd); err != nil {
-			s.logger.Warn("learning-stats failed for agent",
-				zap.String("agent_id", agentID.String()),
+	for _, agent := range agents {
+		if _, err := s.ComputeLearningStats(ctx, agent.ID, periodStart, periodEnd); err != nil {
+			logFor(ctx, s.logger).Warn("learning-stats failed for agent",
+				zap.String("agent_id", agent.ID.String()),
 				zap.Error(err))
 		}
 	}
@@ -161,7 +178,7 @@ func (s *LearningService) RecordMemoryUsage(ctx context.Context, episodeID uuid.
 			RelevanceScore: &mem.Score,
 		}
 		if err := s.episodeMemUsageStore.Create(ctx, usage); err != nil {
-			s.logger.Warn("failed to record memory usage",
+			logFor(ctx, s.logger).Warn("failed to record memory usage",
 				zap.String("episode_id", episodeID.String()),
 				zap.String("memory_id", mem.ID.String()),
 				zap.Error(err),
@@ -179,7 +196,7 @@ func (s *LearningService) RecordOutcome(ctx context.Context, tenantID uuid.UUID,
 	// Update episode outcome
 	if s.episodeStore != nil {
 		if err := s.episodeStore.UpdateOutcome(ctx, record.EpisodeID, tenantID, record.Outcome, ""); err != nil {
-			s.logger.Warn("failed to update episode outcome", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to update episode outcome", zap.Error(err))
 		}
 	}
 
@@ -200,15 +217,28 @@ func (s *LearningService) RecordOutcome(ctx context.Context, tenantID uuid.UUID,
 	}
 
 	// Apply effect to all memories used
+	used := make(map[uuid.UUID]bool, len(record.MemoriesUsed))
 	for _, memID := range record.MemoriesUsed {
+		used[memID] = true
 		if err := s.applyOutcomeEffect(ctx, tenantID, memID, effect, feedbackType, record.EpisodeID); err != nil {
-			s.logger.Warn("failed to apply outcome effect to memory",
+			logFor(ctx, s.logger).Warn("failed to apply outcome effect to memory",
 				zap.String("memory_id", memID.String()),
 				zap.Error(err),
 			)
 		}
 	}
 
+	// Then to whatever else was in working memory during the conversation
+	if s.convActStore != nil && s.episodeStore != nil {
+		episode, err := s.episodeStore.GetByID(ctx, record.EpisodeID, tenantID)
+		if err == nil {
+			err = s.attributeOutcome(ctx, episode, record.Outcome, used)
+		}
+		if err != nil {
+			logFor(ctx, s.logger).Warn("failed to attribute outcome to conversation activations", zap.Error(err))
+		}
+	}
+
 	return nil
 }
 
@@ -248,6 +278,11 @@ func (s *LearningService) applyOutcomeEffect(ctx context.Context, tenantID uuid.
 			if err := st.Memory.UpdateReinforcement(ctx, memory.ID, newConfidence, newReinforcement); err != nil {
 				return err
 			}
+			if supporting, contradicting := evidenceFor(effect.LogOddsDelta); supporting+contradicting > 0 {
+				if err := st.Memory.AddEvidence(ctx, memory.ID, supporting, contradicting); err != nil {
+					return err
+				}
+			}
 			return st.MutationLog.Create(ctx, mutation)
 		}); err != nil {
 			return err
@@ -257,14 +292,16 @@ func (s *LearningService) applyOutcomeEffect(ctx context.Context, tenantID uuid.
 		if err := s.memoryStore.UpdateReinforcement(ctx, memory.ID, newConfidence, newReinforcement); err != nil {
 			return err
 		}
+		supporting, contradicting := evidenceFor(effect.LogOddsDelta)
+		recordEvidence(ctx, s.logger, s.memoryStore, memory.ID, supporting, contradicting)
 		if s.mutationLogStore != nil {
 			if err := s.mutationLogStore.Create(ctx, mutation); err != nil {
-				s.logger.Warn("failed to log mutation", zap.Error(err))
+				logFor(ctx, s.logger).Warn("failed to log mutation", zap.Error(err))
 			}
 		}
 	}
 
-	s.logger.Debug("applied outcome effect to memory",
+	logFor(ctx, s.logger).Debug("applied outcome effect to memory",
 		zap.String("memory_id", memory.ID.String()),
 		zap.String("feedback_type", string(feedbackType)),
 		zap.Float32("old_confidence", oldConfidence),
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"go.uber.org/zap"
+)
+
+// logFor tags logger with ctx's request and correlation IDs, so everything
+// logged for one request or background run can be found together.
+func logFor(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	var fields []zap.Field
+	if id := domain.RequestIDFrom(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if id := domain.CorrelationIDFrom(ctx); id != "" {
+		fields = append(fields, zap.String("correlation_id", id))
+	}
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/Harshitk-cp/engram/internal/clock"
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/service/contradiction"
 	"github.com/Harshitk-cp/engram/internal/store"
@@ -103,12 +104,24 @@ const (
 	UsageReinforcementBoost = 0.02
 	// SessionPromotionThreshold is the reinforcement count at which a recurring
 	SessionPromotionThreshold = 3
+	// BeliefCandidateWindow bounds the write-path similarity search to memories
+	// created or last verified (reinforced) this recently, so its cost tracks
+	// recent activity rather than the agent's whole history. updated_at would
+	// not do: decay and access bookkeeping touch it on memories nobody has
+	// written about in months.
+	BeliefCandidateWindow = 90 * 24 * time.Hour
+	// beliefCandidateLimit is the number of nearest memories the write path
+	// considers before thresholding.
+	beliefCandidateLimit = 50
 )
 
+// sameScopeCandidates keeps the candidates in m's anchor and session scope
+// that may be about the same subject: a belief about Alice neither reinforces
+// nor contradicts one about Bob.
 func sameScopeCandidates(candidates []domain.MemoryWithScore, m *domain.Memory) []domain.MemoryWithScore {
 	out := make([]domain.MemoryWithScore, 0, len(candidates))
 	for _, c := range candidates {
-		if sameUUIDPtr(c.AnchorID, m.AnchorID) && sameUUIDPtr(c.SessionID, m.SessionID) {
+		if sameUUIDPtr(c.AnchorID, m.AnchorID) && sameUUIDPtr(c.SessionID, m.SessionID) && domain.SameSubject(&c.Memory, m) {
 			out = append(out, c)
 		}
 	}
@@ -125,13 +138,48 @@ func sameUUIDPtr(a, b *uuid.UUID) bool {
 	return *a == *b
 }
 
+// findBeliefCandidates returns the memories a new write is checked against for
+// reinforcement and contradiction. A near-duplicate among the agent's recent
+// writes short-circuits the vector search entirely; otherwise the search is
+// restricted to comparable types created or verified within
+// BeliefCandidateWindow.
+func (s *MemoryService) findBeliefCandidates(ctx context.Context, m *domain.Memory) []domain.MemoryWithScore {
+	types := domain.ComparableMemoryTypes(m.Type)
+	recent := sameScopeCandidates(s.recent.nearest(m.AgentID, m.TenantID, m.Embedding, types, ReinforcementThreshold), m)
+
+	if len(recent) > 0 {
+		// The snapshot may be stale (reinforced, archived or deleted by another
+		// path since), so re-read it before trusting its confidence.
+		hit := recent[0]
+		current, err := s.memoryStore.GetByID(ctx, hit.ID, m.TenantID)
+		if err == nil {
+			if len(current.Embedding) == 0 {
+				current.Embedding = hit.Embedding
+			}
+			return []domain.MemoryWithScore{{Memory: *current, Score: hit.Score}}
+		}
+		s.recent.forget(hit.ID)
+	}
+
+	similar, err := s.memoryStore.FindSimilarFiltered(ctx, m.AgentID, m.TenantID, m.Embedding, ContradictionCandidateThreshold, domain.SimilarityFilter{
+		Types: types,
+		Since: timeNow().Add(-BeliefCandidateWindow),
+		Limit: beliefCandidateLimit,
+	})
+	if err != nil {
+		logFor(ctx, s.logger).Warn("failed to find similar beliefs", zap.Error(err))
+	}
+	return similar
+}
+
 type GraphBuilder interface {
 	OnMemoryCreated(ctx context.Context, memory *domain.Memory) error
 }
 
 type boostJob struct {
-	id    uuid.UUID
-	boost float32
+	id       uuid.UUID
+	tenantID uuid.UUID
+	boost    float32
 }
 
 type MemoryService struct {
@@ -146,8 +194,15 @@ type MemoryService struct {
 	uow                   *store.UnitOfWork
 	policyEnforcer        PolicyEnforcer
 	graphBuilder          GraphBuilder
+	coldSummarizer        ColdSummarizer
+	neighborhoodStore     domain.NeighborhoodStore // optional; nil → no interference
+	environmentBoost      float64
+	jobs                  *JobPool
+	hooks                 *Hooks
+	reliability           *SourceReliabilityService
 	logger                *zap.Logger
 	boostCh               chan boostJob
+	recent                *recentEmbeddings
 }
 
 func NewMemoryService(ms domain.MemoryStore, as domain.AgentStore, ec domain.EmbeddingClient, lc domain.LLMClient, logger *zap.Logger) *MemoryService {
@@ -173,6 +228,8 @@ func NewMemoryService(ms domain.MemoryStore, as domain.AgentStore, ec domain.Emb
 		contradictionDetector: detector,
 		logger:                logger,
 		boostCh:               make(chan boostJob, 500),
+		recent:                newRecentEmbeddings(RecentEmbeddingsPerAgent, RecentEmbeddingAgents),
+		environmentBoost:      DefaultEnvironmentBoost,
 	}
 	for i := 0; i < 3; i++ {
 		go svc.runBoostWorker()
@@ -182,7 +239,7 @@ func NewMemoryService(ms domain.MemoryStore, as domain.AgentStore, ec domain.Emb
 
 func (s *MemoryService) runBoostWorker() {
 	for job := range s.boostCh {
-		if err := s.memoryStore.IncrementAccessAndBoost(context.Background(), job.id, job.boost); err != nil {
+		if err := s.memoryStore.IncrementAccessAndBoost(domain.WithTenantID(context.Background(), job.tenantID), job.id, job.boost); err != nil {
 			s.logger.Debug("failed to reinforce memory on usage", zap.String("memory_id", job.id.String()), zap.Error(err))
 		}
 	}
@@ -207,6 +264,23 @@ func (s *MemoryService) SetUnitOfWork(uow *store.UnitOfWork) {
 	s.uow = uow
 }
 
+// SetJobPool enables ExtractAsync.
+func (s *MemoryService) SetJobPool(p *JobPool) {
+	s.jobs = p
+}
+
+// SetHooks reports created, reinforced, contradicted and archived memories to
+// in-process callbacks.
+func (s *MemoryService) SetHooks(h *Hooks) {
+	s.hooks = h
+}
+
+// SetSourceReliability counts reinforced beliefs as confirmations of their
+// source and beliefs demoted by a hard contradiction as refutations.
+func (s *MemoryService) SetSourceReliability(sr *SourceReliabilityService) {
+	s.reliability = sr
+}
+
 // buildContradictionMutation builds an audit row for a belief change caused by a
 // contradicting belief; source_id points at the contradicting belief.
 func buildContradictionMutation(existing *domain.MemoryWithScore, contradictedByID uuid.UUID, oldConf, newConf float32, reason string) *domain.MutationLog {
@@ -225,6 +299,16 @@ func buildContradictionMutation(existing *domain.MemoryWithScore, contradictedBy
 	}
 }
 
+// contradictionEvent reports existing after a contradiction by contradictedBy
+// left it at confidence.
+func contradictionEvent(existing *domain.MemoryWithScore, contradictedBy uuid.UUID, confidence float32, reason string) MemoryEvent {
+	m := existing.Memory
+	m.Confidence = confidence
+	e := memoryEvent(&m, reason)
+	e.ContradictedBy = &contradictedBy
+	return e
+}
+
 // tensionWriters bundles the stores a contradiction-handling branch writes to, so
 // the same branch logic runs either inside a transaction or directly.
 type tensionWriters struct {
@@ -249,7 +333,7 @@ func (s *MemoryService) applyTensionWrites(ctx context.Context, fn func(tensionW
 func (s *MemoryService) enforceCreatePolicy(ctx context.Context, m *domain.Memory) {
 	if s.policyEnforcer != nil {
 		if err := s.policyEnforcer.EnforceOnCreate(ctx, m); err != nil {
-			s.logger.Warn("policy enforcement failed after memory creation", zap.Error(err))
+			logFor(ctx, s.logger).Warn("policy enforcement failed after memory creation", zap.Error(err))
 		}
 	}
 }
@@ -258,6 +342,24 @@ func (s *MemoryService) SetPolicyEnforcer(pe PolicyEnforcer) {
 	s.policyEnforcer = pe
 }
 
+// SetColdSummarizer routes recalled cold-tier memories to background
+// summarization (optional).
+func (s *MemoryService) SetColdSummarizer(cs ColdSummarizer) {
+	s.coldSummarizer = cs
+}
+
+// SetEnvironmentBoost sets how much higher a memory formed in the caller's
+// environment ranks; 0 turns the bias off.
+func (s *MemoryService) SetEnvironmentBoost(boost float64) {
+	s.environmentBoost = boost
+}
+
+// SetNeighborhoodStore lowers the recall score of memories crowded by
+// similar ones (interference).
+func (s *MemoryService) SetNeighborhoodStore(ns domain.NeighborhoodStore) {
+	s.neighborhoodStore = ns
+}
+
 func (s *MemoryService) SetGraphBuilder(gb GraphBuilder) {
 	s.graphBuilder = gb
 }
@@ -311,7 +413,7 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 		if s.llmClient != nil {
 			classified, err := s.llmClient.Classify(ctx, m.Content)
 			if err != nil {
-				s.logger.Warn("LLM classification failed, using heuristic", zap.Error(err))
+				logFor(ctx, s.logger).Warn("LLM classification failed, using heuristic", zap.Error(err))
 				m.Type = contradiction.ClassifyHeuristic(m.Content)
 			} else {
 				m.Type = classified
@@ -320,6 +422,7 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 			m.Type = contradiction.ClassifyHeuristic(m.Content)
 		}
 	}
+	m.Topic = domain.NormalizeTopic(m.Topic)
 
 	// Default provenance (source trust), then derive the initial confidence from
 	// it when the caller didn't supply one: user 0.9 > tool 0.8 > agent 0.6 >
@@ -329,11 +432,18 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 	// Any explicit value is still clamped to DefaultMaxConfidence, since the
 	// log-odds dynamics can't represent more than that (1.0 is +∞ log-odds) and
 	// storing above it would make the first reinforcement appear to lower it.
+	// Under the Bayesian update the prior is the evidence type's instead, when
+	// the caller recorded one.
 	if m.Provenance == "" {
 		m.Provenance = domain.ProvenanceAgent
 	}
+	mode := s.beliefUpdateMode(ctx, m.AgentID, m.Type)
 	if m.Confidence == 0 {
-		m.Confidence = m.Provenance.InitialConfidence()
+		if mode == domain.BeliefUpdateBayesian {
+			m.Confidence = beliefPrior(m)
+		} else {
+			m.Confidence = m.Provenance.InitialConfidence()
+		}
 	}
 	if m.Confidence > DefaultMaxConfidence {
 		m.Confidence = DefaultMaxConfidence
@@ -348,11 +458,12 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 		return nil, err
 	}
 
-	// Generate embedding
-	if s.embeddingClient != nil {
+	// Generate embedding, unless the caller embedded the content already (the
+	// ingest buffer embeds in batches).
+	if s.embeddingClient != nil && len(m.Embedding) == 0 {
 		emb, err := s.embeddingClient.Embed(ctx, m.Content)
 		if err != nil {
-			s.logger.Warn("embedding generation failed", zap.Error(err))
+			logFor(ctx, s.logger).Warn("embedding generation failed", zap.Error(err))
 			// Continue without embedding — recall won't find it, but storage still works
 		} else {
 			m.Embedding = emb
@@ -390,15 +501,12 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 	}
 
 	if enableBeliefLogic && len(m.Embedding) > 0 {
-		similar, err := s.memoryStore.FindSimilar(ctx, m.AgentID, m.TenantID, m.Embedding, ContradictionCandidateThreshold)
-		if err != nil {
-			s.logger.Warn("failed to find similar beliefs", zap.Error(err))
-		}
+		similar := s.findBeliefCandidates(ctx, m)
 
 		if m.Type == domain.MemoryTypePreference || m.Type == domain.MemoryTypeConstraint {
 			typed, err := s.memoryStore.GetRecentByType(ctx, m.AgentID, m.TenantID, m.Type, typeAwareCandidateLimit)
 			if err != nil {
-				s.logger.Warn("failed to fetch type-based candidates", zap.Error(err))
+				logFor(ctx, s.logger).Warn("failed to fetch type-based candidates", zap.Error(err))
 			} else {
 				seen := make(map[string]bool, len(similar))
 				for _, s := range similar {
@@ -415,7 +523,7 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 		similar = sameScopeCandidates(similar, m)
 
 		if len(similar) > 0 {
-			s.logger.Info("contradiction candidates found", zap.Int("count", len(similar)))
+			logFor(ctx, s.logger).Info("contradiction candidates found", zap.Int("count", len(similar)))
 			var reinforcementCandidate *domain.MemoryWithScore
 			for i, existing := range similar {
 				tension, err := s.contradictionDetector.CheckTension(
@@ -424,11 +532,11 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 					existing.Embedding, m.Embedding,
 				)
 				if err != nil {
-					s.logger.Warn("tension check failed", zap.Error(err))
+					logFor(ctx, s.logger).Warn("tension check failed", zap.Error(err))
 					continue
 				}
 
-				s.logger.Info("tension result",
+				logFor(ctx, s.logger).Info("tension result",
 					zap.String("existing", existing.Content),
 					zap.String("incoming", m.Content),
 					zap.Float32("similarity", existing.Score),
@@ -436,12 +544,12 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 					zap.Float32("tension_score", tension.TensionScore),
 				)
 
-				handled, err := s.handleTension(ctx, tension, &existing, m)
+				handled, err := s.handleTension(ctx, tension, &existing, m, mode)
 				if err != nil {
 					return nil, err
 				}
 				if handled {
-					s.logger.Info("contradiction handled", zap.String("type", string(tension.Type)), zap.String("existing_id", existing.ID.String()))
+					logFor(ctx, s.logger).Info("contradiction handled", zap.String("type", string(tension.Type)), zap.String("existing_id", existing.ID.String()))
 					return result, nil
 				}
 
@@ -462,14 +570,13 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 			}
 
 			if reinforcementCandidate != nil {
-				newConfidence := reinforcementCandidate.Confidence + ReinforcementConfidenceBoost
-				if newConfidence > MaxConfidence {
-					newConfidence = MaxConfidence
-				}
+				newConfidence := reinforcedConfidence(mode, reinforcementCandidate.Confidence, m)
 				newCount := reinforcementCandidate.ReinforcementCount + 1
 				if err := s.memoryStore.UpdateReinforcement(ctx, reinforcementCandidate.ID, newConfidence, newCount); err != nil {
-					s.logger.Warn("failed to reinforce belief", zap.Error(err))
+					logFor(ctx, s.logger).Warn("failed to reinforce belief", zap.Error(err))
 				} else {
+					recordEvidence(ctx, s.logger, s.memoryStore, reinforcementCandidate.ID, 1, 0)
+					s.reliability.RecordOutcome(ctx, &reinforcementCandidate.Memory, true)
 					m.ID = reinforcementCandidate.ID
 					m.Confidence = newConfidence
 					m.ReinforcementCount = newCount
@@ -478,15 +585,21 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 					result.Reinforced = true
 					result.ReinforcedMemoryID = reinforcementCandidate.ID
 
+					reinforced := reinforcementCandidate.Memory
+					reinforced.Confidence = newConfidence
+					reinforced.ReinforcementCount = newCount
+					s.recent.remember(&reinforced)
+					s.hooks.memoryReinforced(ctx, memoryEvent(&reinforced, "reinforced by a similar belief"))
+
 					if reinforcementCandidate.Binding == domain.BindingSession &&
 						reinforcementCandidate.AnchorID != nil &&
 						newCount >= SessionPromotionThreshold {
 						if promoted, err := s.memoryStore.PromoteSessionToAnchor(ctx, reinforcementCandidate.ID); err != nil {
-							s.logger.Warn("failed to promote session memory", zap.Error(err))
+							logFor(ctx, s.logger).Warn("failed to promote session memory", zap.Error(err))
 						} else if promoted {
 							m.Binding = domain.BindingAnchored
 							m.SessionID = nil
-							s.logger.Info("promoted session memory to anchor",
+							logFor(ctx, s.logger).Info("promoted session memory to anchor",
 								zap.String("memory_id", reinforcementCandidate.ID.String()))
 						}
 					}
@@ -500,11 +613,13 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 	if err := s.memoryStore.Create(ctx, m); err != nil {
 		return nil, err
 	}
+	s.recent.remember(m)
+	s.hooks.memoryCreated(ctx, m)
 
 	// Enforce policies after creation (non-blocking — log errors but don't fail the create)
 	if s.policyEnforcer != nil {
 		if err := s.policyEnforcer.EnforceOnCreate(ctx, m); err != nil {
-			s.logger.Warn("policy enforcement failed after memory creation", zap.Error(err))
+			logFor(ctx, s.logger).Warn("policy enforcement failed after memory creation", zap.Error(err))
 		}
 	}
 
@@ -513,7 +628,7 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 	// writes; failures are non-fatal and logged.
 	if s.graphBuilder != nil {
 		if err := s.graphBuilder.OnMemoryCreated(ctx, m); err != nil {
-			s.logger.Warn("graph building failed after memory creation", zap.Error(err))
+			logFor(ctx, s.logger).Warn("graph building failed after memory creation", zap.Error(err))
 		}
 	}
 
@@ -525,7 +640,7 @@ func (s *MemoryService) createWithOptions(ctx context.Context, m *domain.Memory,
 func (s *MemoryService) quarantineWrite(ctx context.Context, m *domain.Memory, reason string) (*CreateResult, error) {
 	m.Binding = domain.BindingQuarantine
 	m.QuarantineReason = reason
-	now := time.Now()
+	now := timeNow()
 	m.QuarantinedAt = &now
 
 	if err := s.memoryStore.Create(ctx, m); err != nil {
@@ -533,7 +648,7 @@ func (s *MemoryService) quarantineWrite(ctx context.Context, m *domain.Memory, r
 	}
 
 	s.logQuarantineMutation(ctx, m, domain.MutationQuarantine, "quarantine: "+reason)
-	s.logger.Info("write quarantined by firewall",
+	logFor(ctx, s.logger).Info("write quarantined by firewall",
 		zap.String("memory_id", m.ID.String()),
 		zap.String("provenance", string(m.Provenance)),
 		zap.String("reason", reason))
@@ -569,6 +684,7 @@ func (s *MemoryService) ReleaseQuarantine(ctx context.Context, id, tenantID uuid
 		reason = "release: " + note
 	}
 	s.logQuarantineMutation(ctx, m, domain.MutationQuarantineRelease, reason)
+	s.hooks.memoryCreated(ctx, m)
 	return m, nil
 }
 
@@ -622,7 +738,7 @@ func (s *MemoryService) logQuarantineMutation(ctx context.Context, m *domain.Mem
 		entry.ContentSnapshot = &snapshot
 	}
 	if err := s.mutationLogStore.Create(ctx, entry); err != nil {
-		s.logger.Warn("failed to log firewall mutation", zap.String("memory_id", m.ID.String()), zap.Error(err))
+		logFor(ctx, s.logger).Warn("failed to log firewall mutation", zap.String("memory_id", m.ID.String()), zap.Error(err))
 	}
 }
 
@@ -645,6 +761,7 @@ func (s *MemoryService) Delete(ctx context.Context, id uuid.UUID, tenantID uuid.
 		}
 		return err
 	}
+	s.recent.forget(id)
 	return nil
 }
 
@@ -693,6 +810,7 @@ func (s *MemoryService) Recall(ctx context.Context, query string, agentID uuid.U
 		if storeOpts.TopK < 30 {
 			storeOpts.TopK = 30
 		}
+		storeOpts.TopK = domain.RecallCandidates(storeOpts.TopK, opts.Accuracy)
 	}
 
 	emb, err := s.embeddingClient.Embed(ctx, query)
@@ -711,6 +829,10 @@ func (s *MemoryService) Recall(ctx context.Context, query string, agentID uuid.U
 	// Apply composite scoring and re-ranking
 	if opts.Scoring == domain.ScoringWeighted && len(memories) > 0 {
 		scorer := s.buildScorer(ctx, agentID)
+		scorer.Environment = opts.Environment.Normalize()
+		if !opts.NoInterference {
+			s.loadInterference(ctx, scorer, tenantID, memories)
+		}
 		scored := scorer.ScoreAndRank(memories, timeNow())
 		memories = make([]domain.MemoryWithScore, 0, len(scored))
 		for _, sm := range scored {
@@ -718,22 +840,25 @@ func (s *MemoryService) Recall(ctx context.Context, query string, agentID uuid.U
 		}
 	}
 
+	if !opts.ExpandSummaries {
+		memories = collapseSummarizedScores(memories)
+	}
+
+	memories = suppressInWindow(memories, opts.Conversation)
+
 	// Truncate to requested TopK after re-ranking
 	if len(memories) > opts.TopK {
 		memories = memories[:opts.TopK]
 	}
 
-	// Usage reinforcement: recalled memories get a small confidence boost (best-effort, non-blocking)
+	// Usage reinforcement: recalled memories get a small confidence boost (best-effort, non-blocking).
+	// Cold-tier hits are also queued for background summarization.
 	for _, mem := range memories {
-		tier := domain.ComputeTier(float64(mem.Confidence))
-		behavior := domain.GetTierBehavior(tier)
-		if behavior.SummarizeOnAccess {
-			s.logger.Debug("cold tier memory accessed, candidate for summarization",
-				zap.String("memory_id", mem.ID.String()),
-				zap.String("tier", string(tier)))
+		if s.coldSummarizer != nil {
+			s.coldSummarizer.Enqueue(mem.Memory)
 		}
 		select {
-		case s.boostCh <- boostJob{id: mem.ID, boost: UsageReinforcementBoost}:
+		case s.boostCh <- boostJob{id: mem.ID, tenantID: mem.TenantID, boost: UsageReinforcementBoost}:
 		default:
 		}
 	}
@@ -741,6 +866,30 @@ func (s *MemoryService) Recall(ctx context.Context, query string, agentID uuid.U
 	return memories, nil
 }
 
+// collapseSummarizedScores drops detail memories whose summary was also
+// recalled, so the rollup stands in for them.
+func collapseSummarizedScores(memories []domain.MemoryWithScore) []domain.MemoryWithScore {
+	covered := make(map[uuid.UUID]bool)
+	for _, m := range memories {
+		for _, id := range domain.SummaryMemberIDs(m.Memory) {
+			covered[id] = true
+		}
+	}
+	if len(covered) == 0 {
+		return memories
+	}
+
+	out := memories[:0]
+	for _, m := range memories {
+		if !covered[m.ID] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// filterByTier keeps memories whose stored tier is included and whose score
+// clears that tier's retrieval threshold. Pinned memories always pass.
 func (s *MemoryService) filterByTier(memories []domain.MemoryWithScore, includeTiers []domain.MemoryTier) []domain.MemoryWithScore {
 	tierSet := make(map[domain.MemoryTier]bool)
 	for _, t := range includeTiers {
@@ -749,7 +898,11 @@ func (s *MemoryService) filterByTier(memories []domain.MemoryWithScore, includeT
 
 	var filtered []domain.MemoryWithScore
 	for _, m := range memories {
-		tier := domain.ComputeTier(float64(m.Confidence))
+		if m.Pinned {
+			filtered = append(filtered, m)
+			continue
+		}
+		tier := m.CurrentTier()
 		if !tierSet[tier] {
 			continue
 		}
@@ -805,6 +958,10 @@ func (s *MemoryService) RecallWithExplain(ctx context.Context, query string, age
 	memories = s.filterByTier(memories, opts.IncludeTiers)
 
 	scorer := s.buildScorer(ctx, agentID)
+	scorer.Environment = opts.Environment.Normalize()
+	if !opts.NoInterference {
+		s.loadInterference(ctx, scorer, tenantID, memories)
+	}
 	scored := scorer.ScoreAndRank(memories, timeNow())
 
 	if len(scored) > opts.TopK {
@@ -814,7 +971,7 @@ func (s *MemoryService) RecallWithExplain(ctx context.Context, query string, age
 	// Usage reinforcement
 	for _, mem := range scored {
 		select {
-		case s.boostCh <- boostJob{id: mem.ID, boost: UsageReinforcementBoost}:
+		case s.boostCh <- boostJob{id: mem.ID, tenantID: mem.TenantID, boost: UsageReinforcementBoost}:
 		default:
 		}
 	}
@@ -822,6 +979,16 @@ func (s *MemoryService) RecallWithExplain(ctx context.Context, query string, age
 	return scored, nil
 }
 
+// ExplainHybridScores returns a score breakdown for each hybrid recall result,
+// in order, under the request's weights.
+func (s *MemoryService) ExplainHybridScores(req domain.HybridRecallRequest, results []domain.ScoredMemory) []*ScoreBreakdown {
+	breakdowns := make([]*ScoreBreakdown, len(results))
+	for i, sm := range results {
+		breakdowns[i] = ExplainHybrid(sm, hybridVectorWeight(req), req.GraphWeight, req.RecencyBoost)
+	}
+	return breakdowns
+}
+
 // PolicyWeightProvider is an optional interface that PolicyEnforcer can implement
 // to provide per-type importance weights for scoring.
 type PolicyWeightProvider interface {
@@ -831,6 +998,7 @@ type PolicyWeightProvider interface {
 // buildScorer creates a RecallScorer with per-agent policy weights if available.
 func (s *MemoryService) buildScorer(ctx context.Context, agentID uuid.UUID) *RecallScorer {
 	scorer := NewRecallScorer()
+	scorer.EnvironmentBoost = s.environmentBoost
 
 	if s.policyEnforcer == nil {
 		return scorer
@@ -846,7 +1014,20 @@ func (s *MemoryService) buildScorer(ctx context.Context, agentID uuid.UUID) *Rec
 	return scorer
 }
 
-var timeNow = time.Now
+// loadInterference counts the interfering neighbors of the recalled
+// memories into scorer. A failed count leaves the ranking unpenalized.
+func (s *MemoryService) loadInterference(ctx context.Context, scorer *RecallScorer, tenantID uuid.UUID, memories []domain.MemoryWithScore) {
+	ids := make([]uuid.UUID, len(memories))
+	for i, m := range memories {
+		ids[i] = m.ID
+	}
+	if err := scorer.LoadNeighbors(ctx, s.neighborhoodStore, tenantID, ids); err != nil {
+		logFor(ctx, s.logger).Debug("failed to count interfering neighbors", zap.Error(err))
+	}
+}
+
+// timeNow is the service clock; tests swap it via clock.Set.
+var timeNow = clock.Now
 
 type ExtractResult struct {
 	ID         uuid.UUID         `json:"id,omitempty"`
@@ -890,10 +1071,12 @@ func (s *MemoryService) Extract(ctx context.Context, agentID uuid.UUID, tenantID
 				Content:    e.Content,
 				Confidence: confidence,
 				Source:     string(domain.SourceExtraction),
+				Subject:    domain.NormalizeSubject(e.Subject),
+				Topic:      e.Topic,
 			}
 			createResult, err := s.Create(ctx, mem)
 			if err != nil {
-				s.logger.Warn("failed to auto-store extracted memory",
+				logFor(ctx, s.logger).Warn("failed to auto-store extracted memory",
 					zap.String("content", e.Content),
 					zap.Error(err))
 			} else {
@@ -912,6 +1095,29 @@ func (s *MemoryService) Extract(ctx context.Context, agentID uuid.UUID, tenantID
 	return results, nil
 }
 
+// ExtractionJobTimeout bounds an async extraction job, which is not tied to
+// any request's deadline.
+const ExtractionJobTimeout = 5 * time.Minute
+
+// ExtractAsync queues Extract on the job pool and returns the queued job; its
+// Result is the []ExtractResult once it succeeds. The job keeps ctx's request
+// and correlation IDs but not its deadline.
+func (s *MemoryService) ExtractAsync(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, conversation []domain.Message, autoStore bool) (*domain.Job, error) {
+	if s.llmClient == nil {
+		return nil, errors.New("LLM client not configured")
+	}
+	if s.jobs == nil {
+		return nil, errors.New("job pool not configured")
+	}
+	return s.jobs.Submit(ctx, tenantID, agentID, domain.JobKindExtraction, ExtractionJobTimeout, func(ctx context.Context) (any, error) {
+		results, err := s.Extract(ctx, agentID, tenantID, conversation, autoStore)
+		if results == nil {
+			results = []ExtractResult{}
+		}
+		return results, err
+	})
+}
+
 func (s *MemoryService) Summarize(ctx context.Context, memories []domain.Memory) (string, error) {
 	if s.llmClient == nil {
 		return "", errors.New("LLM client not configured")
@@ -919,9 +1125,10 @@ func (s *MemoryService) Summarize(ctx context.Context, memories []domain.Memory)
 	return s.llmClient.Summarize(ctx, memories)
 }
 
-// handleTension applies graded contradiction rules. Returns true if the tension was handled
+// handleTension applies graded contradiction rules, moving the old belief's
+// confidence as mode directs. Returns true if the tension was handled
 // (meaning the caller should not proceed with reinforcement).
-func (s *MemoryService) handleTension(ctx context.Context, tension *domain.TensionResult, existing *domain.MemoryWithScore, m *domain.Memory) (bool, error) {
+func (s *MemoryService) handleTension(ctx context.Context, tension *domain.TensionResult, existing *domain.MemoryWithScore, m *domain.Memory, mode domain.BeliefUpdateMode) (bool, error) {
 	if tension == nil {
 		return false, nil
 	}
@@ -932,15 +1139,18 @@ func (s *MemoryService) handleTension(ctx context.Context, tension *domain.Tensi
 			return false, nil
 		}
 		// Demote old belief significantly, create new — atomically with the audit row.
-		newOldConfidence := existing.Confidence - ContradictionConfidencePenalty
-		if newOldConfidence < MinConfidence {
-			newOldConfidence = MinConfidence
+		// A Bayesian update keeps the new belief at its own prior.
+		newOldConfidence := contradictedConfidence(mode, existing.Confidence, m, 1)
+		if mode != domain.BeliefUpdateBayesian {
+			m.Confidence = NewContradictingBeliefConfidence
 		}
-		m.Confidence = NewContradictingBeliefConfidence
 		if err := s.applyTensionWrites(ctx, func(w tensionWriters) error {
 			if err := w.mem.UpdateConfidence(ctx, existing.ID, newOldConfidence); err != nil {
 				return err
 			}
+			if err := w.mem.AddEvidence(ctx, existing.ID, 0, 1); err != nil {
+				return err
+			}
 			if err := w.mem.Create(ctx, m); err != nil {
 				return err
 			}
@@ -957,6 +1167,9 @@ func (s *MemoryService) handleTension(ctx context.Context, tension *domain.Tensi
 		}); err != nil {
 			return false, err
 		}
+		s.hooks.memoryCreated(ctx, m)
+		s.hooks.memoryContradicted(ctx, contradictionEvent(existing, m.ID, newOldConfidence, "hard contradiction: belief demoted"))
+		s.reliability.RecordOutcome(ctx, &existing.Memory, false)
 		s.enforceCreatePolicy(ctx, m)
 		return true, nil
 
@@ -980,6 +1193,10 @@ func (s *MemoryService) handleTension(ctx context.Context, tension *domain.Tensi
 		}); err != nil {
 			return false, err
 		}
+		s.hooks.memoryCreated(ctx, m)
+		superseded := contradictionEvent(existing, m.ID, existing.Confidence, "temporal contradiction: superseded by a newer belief")
+		s.hooks.memoryContradicted(ctx, superseded)
+		s.hooks.memoryArchived(ctx, superseded)
 		s.enforceCreatePolicy(ctx, m)
 		return true, nil
 
@@ -988,6 +1205,7 @@ func (s *MemoryService) handleTension(ctx context.Context, tension *domain.Tensi
 		if err := s.memoryStore.Create(ctx, m); err != nil {
 			return false, err
 		}
+		s.hooks.memoryCreated(ctx, m)
 		s.enforceCreatePolicy(ctx, m)
 		return true, nil
 
@@ -996,14 +1214,14 @@ func (s *MemoryService) handleTension(ctx context.Context, tension *domain.Tensi
 			// Low tension - treat as reinforcement, fall through
 			return false, nil
 		}
-		newOldConfidence := existing.Confidence - (ContradictionConfidencePenalty / 2)
-		if newOldConfidence < MinConfidence {
-			newOldConfidence = MinConfidence
-		}
+		newOldConfidence := contradictedConfidence(mode, existing.Confidence, m, 0.5)
 		if err := s.applyTensionWrites(ctx, func(w tensionWriters) error {
 			if err := w.mem.UpdateConfidence(ctx, existing.ID, newOldConfidence); err != nil {
 				return err
 			}
+			if err := w.mem.AddEvidence(ctx, existing.ID, 0, 1); err != nil {
+				return err
+			}
 			if err := w.mem.Create(ctx, m); err != nil {
 				return err
 			}
@@ -1015,6 +1233,8 @@ func (s *MemoryService) handleTension(ctx context.Context, tension *domain.Tensi
 		}); err != nil {
 			return false, err
 		}
+		s.hooks.memoryCreated(ctx, m)
+		s.hooks.memoryContradicted(ctx, contradictionEvent(existing, m.ID, newOldConfidence, "soft contradiction: confidence reduced"))
 		return true, nil
 
 	case domain.ContradictionNone:
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// forEachLiveMemory calls fn for each of the agent's live memories. With a
+// scanner it streams them a page at a time; without one it falls back to
+// GetByAgentForDecay, which loads them all (up to the store's decay batch).
+// Returning domain.ErrStopIteration from fn stops early without error.
+func forEachLiveMemory(ctx context.Context, scanner domain.MemoryScanner, ms domain.MemoryStore, agentID uuid.UUID, fn func(*domain.Memory) error) error {
+	if scanner != nil {
+		return scanner.IterateForDecay(ctx, agentID, fn)
+	}
+	memories, err := ms.GetByAgentForDecay(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	for i := range memories {
+		if err := fn(&memories[i]); err != nil {
+			if errors.Is(err, domain.ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// agentMemoryStats aggregates in the database when a scanner is set, and
+// otherwise over the loaded memories.
+func agentMemoryStats(ctx context.Context, scanner domain.MemoryScanner, ms domain.MemoryStore, agentID uuid.UUID, q domain.MemoryStatsQuery) (*domain.AgentMemoryStats, error) {
+	if scanner != nil {
+		return scanner.AgentMemoryStats(ctx, agentID, q)
+	}
+
+	stats := &domain.AgentMemoryStats{
+		UncertainTypes: []string{},
+		ByType:         make(map[string]int),
+		ByTier:         make(map[string]int),
+	}
+	var total float32
+	uncertain := make(map[string]bool)
+	topics := make(map[string]*domain.TopicStats)
+	previousSince := q.TrendSince.Add(-q.TrendWindow)
+	err := forEachLiveMemory(ctx, nil, ms, agentID, func(m *domain.Memory) error {
+		stats.Count++
+		stats.ByType[string(m.Type)]++
+		stats.ByTier[string(m.CurrentTier())]++
+		total += m.Confidence
+		if m.Confidence < q.AtRiskBelow {
+			stats.AtRisk++
+		}
+		if m.LastAccessedAt != nil && m.LastAccessedAt.After(q.RecentSince) {
+			stats.RecentlyReinforced++
+		}
+		if m.Confidence < q.UncertainBelow && !uncertain[string(m.Type)] {
+			uncertain[string(m.Type)] = true
+			stats.UncertainTypes = append(stats.UncertainTypes, string(m.Type))
+		}
+
+		name := domain.TopicOf(m)
+		t := topics[name]
+		if t == nil {
+			t = &domain.TopicStats{Topic: name}
+			topics[name] = t
+		}
+		t.Count++
+		t.AverageConfidence += m.Confidence // summed here, divided below
+		if m.Confidence < q.AtRiskBelow {
+			t.AtRisk++
+		}
+		if m.Confidence < q.UncertainBelow {
+			t.Uncertain++
+		}
+		switch {
+		case !m.CreatedAt.Before(q.TrendSince):
+			t.Recent++
+		case !m.CreatedAt.Before(previousSince):
+			t.Previous++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if stats.Count > 0 {
+		stats.AverageConfidence = total / float32(stats.Count)
+	}
+	sort.Strings(stats.UncertainTypes)
+
+	byTopic := make([]domain.TopicStats, 0, len(topics))
+	for _, t := range topics {
+		t.AverageConfidence /= float32(t.Count)
+		byTopic = append(byTopic, *t)
+	}
+	stats.ByTopic, stats.UncertainTopics = domain.RankTopics(byTopic, q.MaxTopics)
+	return stats, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// fakeMemoryScanner streams a fixed set of memories and records how many
+// were handed out.
+type fakeMemoryScanner struct {
+	memories []domain.Memory
+	yielded  int
+	stats    domain.AgentMemoryStats
+}
+
+func (f *fakeMemoryScanner) IterateForDecay(ctx context.Context, agentID uuid.UUID, fn func(*domain.Memory) error) error {
+	for i := range f.memories {
+		f.yielded++
+		if err := fn(&f.memories[i]); err != nil {
+			if errors.Is(err, domain.ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeMemoryScanner) AgentMemoryStats(ctx context.Context, agentID uuid.UUID, q domain.MemoryStatsQuery) (*domain.AgentMemoryStats, error) {
+	return &f.stats, nil
+}
+
+// fakeHealthStore returns fixed counts.
+type fakeHealthStore struct {
+	counts domain.AgentHealthCounts
+}
+
+func (f *fakeHealthStore) AgentHealthCounts(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.AgentHealthCounts, error) {
+	return &f.counts, nil
+}
+
+func TestAgentMemoryStats_Fallback(t *testing.T) {
+	ms := newMockMemoryStore()
+	agentID := uuid.New()
+	recent := time.Now()
+	for _, m := range []domain.Memory{
+		{Type: domain.MemoryTypeFact, Confidence: 0.2, LastAccessedAt: &recent, CreatedAt: recent},
+		{Type: domain.MemoryTypePreference, Confidence: 0.4, CreatedAt: recent.Add(-90 * time.Minute)},
+		{Type: domain.MemoryTypeFact, Confidence: 0.9},
+	} {
+		m.ID, m.AgentID = uuid.New(), agentID
+		ms.memories[m.ID] = &m
+	}
+
+	stats, err := agentMemoryStats(context.Background(), nil, ms, agentID, domain.MemoryStatsQuery{
+		AtRiskBelow: 0.3, UncertainBelow: 0.5, RecentSince: recent.Add(-time.Hour),
+		TrendSince: recent.Add(-time.Hour), TrendWindow: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := domain.AgentMemoryStats{
+		Count:              3,
+		AverageConfidence:  0.5,
+		AtRisk:             1,
+		RecentlyReinforced: 1,
+		UncertainTypes:     []string{"fact", "preference"},
+		ByType:             map[string]int{"fact": 2, "preference": 1},
+		ByTier:             map[string]int{"archive": 1, "cold": 1, "hot": 1},
+		ByTopic: []domain.TopicStats{
+			{Topic: "fact", Count: 2, AverageConfidence: 0.55, AtRisk: 1, Uncertain: 1, Recent: 1, Trend: domain.TopicTrendRising},
+			{Topic: "preference", Count: 1, AverageConfidence: 0.4, Uncertain: 1, Previous: 1, Trend: domain.TopicTrendFalling},
+		},
+		UncertainTopics: []string{"fact", "preference"},
+	}
+	if math.Abs(float64(stats.AverageConfidence-want.AverageConfidence)) > 1e-6 {
+		t.Fatalf("expected average %v, got %v", want.AverageConfidence, stats.AverageConfidence)
+	}
+	stats.AverageConfidence = want.AverageConfidence
+	for i := range stats.ByTopic {
+		if i < len(want.ByTopic) && math.Abs(float64(stats.ByTopic[i].AverageConfidence-want.ByTopic[i].AverageConfidence)) < 1e-6 {
+			stats.ByTopic[i].AverageConfidence = want.ByTopic[i].AverageConfidence
+		}
+	}
+	if !reflect.DeepEqual(*stats, want) {
+		t.Fatalf("expected %+v, got %+v", want, *stats)
+	}
+}
+
+func TestConsolidationService_UsesMemoryScanner(t *testing.T) {
+	tenantID := uuid.New()
+	scanner := &fakeMemoryScanner{
+		memories: []domain.Memory{{ID: uuid.New(), TenantID: tenantID}, {ID: uuid.New(), TenantID: tenantID}},
+		stats:    domain.AgentMemoryStats{Count: 120000, AtRisk: 7, UncertainTypes: []string{"fact"}, UncertainTopics: []string{"fact"}},
+	}
+	// The memory store holds nothing: every read must go through the scanner.
+	svc := NewConsolidationService(newMockMemoryStore(), nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetMemoryScanner(scanner)
+
+	got, err := svc.getTenantForAgent(context.Background(), uuid.New())
+	if err != nil || got != tenantID {
+		t.Fatalf("expected tenant %v, got %v (%v)", tenantID, got, err)
+	}
+	if scanner.yielded != 1 {
+		t.Fatalf("expected the scan to stop after the first memory, read %d", scanner.yielded)
+	}
+
+	health, err := svc.GetMemoryHealth(context.Background(), uuid.New(), tenantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.SemanticCount != 120000 || health.MemoriesAtRisk != 7 || !reflect.DeepEqual(health.UncertaintyAreas, []string{"fact"}) {
+		t.Fatalf("expected health from the aggregate stats, got %+v", health)
+	}
+}
+
+func TestConsolidationService_GetMemoryHealthUsesHealthStore(t *testing.T) {
+	oldest := time.Now().Add(-3 * time.Hour)
+	hs := &fakeHealthStore{counts: domain.AgentHealthCounts{
+		UnconsolidatedEpisodes: 5000,
+		OldestUnconsolidated:   &oldest,
+		Procedures:             12,
+		Schemas:                3,
+	}}
+	scanner := &fakeMemoryScanner{stats: domain.AgentMemoryStats{
+		Count:  10,
+		ByType: map[string]int{"fact": 10},
+		ByTier: map[string]int{"warm": 4, "cold": 6},
+	}}
+	// No episode, procedure or schema store: the counts must come from the
+	// health store.
+	svc := NewConsolidationService(newMockMemoryStore(), nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetMemoryScanner(scanner)
+	svc.SetHealthStore(hs)
+
+	health, err := svc.GetMemoryHealth(context.Background(), uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.EpisodicCount != 5000 || health.ProceduralCount != 12 || health.SchemaCount != 3 {
+		t.Fatalf("expected counts from the health store, got %+v", health)
+	}
+	if health.OldestUnprocessed == nil || !health.OldestUnprocessed.Equal(oldest) {
+		t.Fatalf("expected oldest unprocessed %v, got %v", oldest, health.OldestUnprocessed)
+	}
+	if !reflect.DeepEqual(health.MemoriesByTier, scanner.stats.ByTier) || !reflect.DeepEqual(health.MemoriesByType, scanner.stats.ByType) {
+		t.Fatalf("expected type/tier breakdown from the scanner, got %+v / %+v", health.MemoriesByType, health.MemoriesByTier)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,7 +14,8 @@ import (
 
 // mockMemoryStore implements domain.MemoryStore for testing.
 type mockMemoryStore struct {
-	memories map[uuid.UUID]*domain.Memory
+	memories    map[uuid.UUID]*domain.Memory
+	tierHistory []domain.TierTransition
 }
 
 func newMockMemoryStore() *mockMemoryStore {
@@ -25,6 +27,7 @@ func (m *mockMemoryStore) Create(ctx context.Context, mem *domain.Memory) error
 	now := time.Now()
 	mem.CreatedAt = now
 	mem.UpdatedAt = now
+	mem.EvidenceFor, mem.EvidenceAgainst = mem.Evidence()
 	m.memories[mem.ID] = mem
 	return nil
 }
@@ -112,6 +115,10 @@ func (m *mockMemoryStore) FindSimilar(ctx context.Context, agentID uuid.UUID, te
 	return []domain.MemoryWithScore{}, nil
 }
 
+func (m *mockMemoryStore) FindSimilarFiltered(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, filter domain.SimilarityFilter) ([]domain.MemoryWithScore, error) {
+	return m.FindSimilar(ctx, agentID, tenantID, embedding, threshold)
+}
+
 func (m *mockMemoryStore) GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType domain.MemoryType, limit int) ([]domain.MemoryWithScore, error) {
 	return []domain.MemoryWithScore{}, nil
 }
@@ -126,7 +133,35 @@ func (m *mockMemoryStore) UpdateReinforcement(ctx context.Context, id uuid.UUID,
 	return nil
 }
 
+func (m *mockMemoryStore) ReinforceBy(ctx context.Context, id uuid.UUID, boost float32) error {
+	mem, ok := m.memories[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	mem.Confidence = clampConf(mem.Confidence + boost)
+	mem.ReinforcementCount++
+	return nil
+}
+
+func (m *mockMemoryStore) AddEvidence(ctx context.Context, id uuid.UUID, supporting, contradicting float64) error {
+	mem, ok := m.memories[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	alpha, beta := mem.Evidence()
+	mem.EvidenceFor, mem.EvidenceAgainst = alpha+supporting, beta+contradicting
+	return nil
+}
+
 func (m *mockMemoryStore) UpdateContent(ctx context.Context, id uuid.UUID, content string, embedding []float32) error {
+	mem, ok := m.memories[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	mem.Content = content
+	if embedding != nil {
+		mem.Embedding = embedding
+	}
 	return nil
 }
 
@@ -240,13 +275,12 @@ func (m *mockMemoryStore) IncrementAccessAndBoost(ctx context.Context, id uuid.U
 }
 
 func (m *mockMemoryStore) GetByTier(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, tier domain.MemoryTier, limit int) ([]domain.Memory, error) {
-	thresholds := domain.TierConfidenceThresholds[tier]
 	var results []domain.Memory
 	for _, mem := range m.memories {
 		if mem.AgentID != agentID || mem.TenantID != tenantID {
 			continue
 		}
-		if float64(mem.Confidence) > thresholds.Min && float64(mem.Confidence) <= thresholds.Max {
+		if mem.CurrentTier() == tier {
 			results = append(results, *mem)
 			if len(results) >= limit {
 				break
@@ -262,12 +296,45 @@ func (m *mockMemoryStore) GetTierCounts(ctx context.Context, agentID uuid.UUID,
 		if mem.AgentID != agentID || mem.TenantID != tenantID {
 			continue
 		}
-		tier := domain.ComputeTier(float64(mem.Confidence))
-		counts[tier]++
+		counts[mem.CurrentTier()]++
 	}
 	return counts, nil
 }
 
+func (m *mockMemoryStore) ApplyTierTransitions(ctx context.Context, transitions []domain.TierTransition) error {
+	for _, t := range transitions {
+		if mem, ok := m.memories[t.MemoryID]; ok {
+			mem.Tier = t.ToTier
+		}
+		m.tierHistory = append(m.tierHistory, t)
+	}
+	return nil
+}
+
+func (m *mockMemoryStore) SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) error {
+	mem, ok := m.memories[id]
+	if !ok || mem.TenantID != tenantID {
+		return store.ErrNotFound
+	}
+	mem.Pinned = pinned
+	return nil
+}
+
+func (m *mockMemoryStore) GetTierHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memoryID *uuid.UUID, since time.Time, limit int) ([]domain.TierTransition, error) {
+	var history []domain.TierTransition
+	for i := len(m.tierHistory) - 1; i >= 0; i-- {
+		t := m.tierHistory[i]
+		if t.AgentID != agentID || t.TenantID != tenantID || t.OccurredAt.Before(since) {
+			continue
+		}
+		if memoryID != nil && t.MemoryID != *memoryID {
+			continue
+		}
+		history = append(history, t)
+	}
+	return history, nil
+}
+
 func (m *mockMemoryStore) SetNeedsReview(ctx context.Context, id uuid.UUID, needsReview bool) error {
 	_, ok := m.memories[id]
 	if !ok {
@@ -305,6 +372,11 @@ type mockLLMClient struct {
 	extractResult            []domain.ExtractedMemory
 	summarizeResult          string
 	checkContradictionResult bool
+
+	verificationQuestions []domain.VerificationQuestion
+	verificationCalls     [][]domain.UncertainBelief
+	narrative             *domain.KnowledgeNarrative
+	narrateCalls          []domain.KnowledgeSources
 }
 
 func newMockLLMClient() *mockLLMClient {
@@ -391,6 +463,20 @@ func (m *mockLLMClient) DetectRelationships(ctx context.Context, memory *domain.
 	return []domain.DetectedRelationship{}, nil
 }
 
+func (m *mockLLMClient) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	m.verificationCalls = append(m.verificationCalls, beliefs)
+	return m.verificationQuestions, nil
+}
+
+func (m *mockLLMClient) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	m.narrateCalls = append(m.narrateCalls, sources)
+	if m.narrative == nil {
+		return &domain.KnowledgeNarrative{Topic: topic}, nil
+	}
+	n := *m.narrative
+	return &n, nil
+}
+
 func testLogger() *zap.Logger {
 	logger, _ := zap.NewDevelopment()
 	return logger
@@ -705,6 +791,33 @@ func TestMemoryService_Recall_WithTypeFilter(t *testing.T) {
 	}
 }
 
+func TestMemoryService_FilterByTier_UsesStoredTier(t *testing.T) {
+	svc, _, _, _ := setupMemoryTest()
+	mem := func(content string, confidence float32, tier domain.MemoryTier, pinned bool) domain.MemoryWithScore {
+		return domain.MemoryWithScore{
+			Memory: domain.Memory{Content: content, Confidence: confidence, Tier: tier, Pinned: pinned},
+			Score:  0.6,
+		}
+	}
+	memories := []domain.MemoryWithScore{
+		// Promoted to warm for frequent access, though its confidence is cold.
+		mem("promoted", 0.5, domain.TierWarm, false),
+		// Demoted to cold as stale, though its confidence is warm.
+		mem("stale", 0.8, domain.TierCold, false),
+		mem("pinned", 0.3, domain.TierArchive, true),
+		// Read without a stored tier: falls back to the confidence band.
+		mem("unstored", 0.8, "", false),
+	}
+
+	var got []string
+	for _, m := range svc.filterByTier(memories, domain.DefaultIncludeTiers()) {
+		got = append(got, m.Content)
+	}
+	if strings.Join(got, ",") != "promoted,pinned,unstored" {
+		t.Fatalf("expected the stored tier to decide and pinned memories to pass, got %v", got)
+	}
+}
+
 func TestMemoryService_Recall_EmptyQuery(t *testing.T) {
 	svc, _, tenantID, agentID := setupMemoryTest()
 
@@ -714,6 +827,29 @@ func TestMemoryService_Recall_EmptyQuery(t *testing.T) {
 	}
 }
 
+func TestMemoryService_Recall_SuppressesConversationWindow(t *testing.T) {
+	svc, _, tenantID, agentID := setupMemoryTest()
+	ctx := context.Background()
+
+	for _, content := range []string{"Prefers dark mode in the editor", "Works at Acme"} {
+		mem := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: content, Type: domain.MemoryTypePreference}
+		_, _ = svc.Create(ctx, mem)
+	}
+
+	results, err := svc.Recall(ctx, "what does the user prefer?", agentID, tenantID, domain.RecallOpts{
+		TopK: 10,
+		Conversation: []domain.Message{
+			{Role: "user", Content: "Reminder: I  prefers DARK mode in the editor, ok?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].Content != "Works at Acme" {
+		t.Fatalf("expected only the memory missing from the window, got %+v", results)
+	}
+}
+
 func TestMemoryService_Recall_TopK(t *testing.T) {
 	svc, _, tenantID, agentID := setupMemoryTest()
 	ctx := context.Background()
@@ -779,6 +915,41 @@ func TestMemoryService_Extract_AutoStore(t *testing.T) {
 	}
 }
 
+func TestMemoryService_ExtractAsync(t *testing.T) {
+	svc, memStore, tenantID, agentID := setupMemoryTest()
+	pool := NewJobPool(1, 10, testLogger())
+	pool.Start()
+	defer pool.Stop()
+	svc.SetJobPool(pool)
+
+	conversation := []domain.Message{
+		{Role: "user", Content: "I prefer dark mode"},
+	}
+
+	job, err := svc.ExtractAsync(context.Background(), agentID, tenantID, conversation, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Kind != domain.JobKindExtraction || job.TenantID != tenantID {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	done, err := pool.Wait(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if done.Status != domain.JobSucceeded {
+		t.Fatalf("expected succeeded, got %s (%s)", done.Status, done.Error)
+	}
+	results, ok := done.Result.([]ExtractResult)
+	if !ok || len(results) != 2 || !results[0].Stored {
+		t.Fatalf("unexpected result: %#v", done.Result)
+	}
+	if len(memStore.memories) != 2 {
+		t.Fatalf("expected 2 memories in store, got %d", len(memStore.memories))
+	}
+}
+
 func TestMemoryService_Summarize(t *testing.T) {
 	svc, _, _, _ := setupMemoryTest()
 	ctx := context.Background()
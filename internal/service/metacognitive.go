@@ -26,6 +26,10 @@ const (
 	StaleMemoryDays        = 30  // Days since verification to be considered stale
 	HighUncertaintyLevel   = 0.7 // Overall uncertainty level considered high
 
+	// MaxVerificationQuestions bounds the beliefs an uncertainty report asks
+	// the LLM to write verification questions for.
+	MaxVerificationQuestions = 5
+
 	// Strategy reflection
 	MinProcedureUsesForEval   = 5   // Minimum uses before evaluating effectiveness
 	LowSuccessRateThreshold   = 0.5 // Below this is underperforming
@@ -49,6 +53,10 @@ type ConfidenceAssessment struct {
 	AdjustedConfidence float32            `json:"adjusted_confidence"`
 	Factors            map[string]float32 `json:"factors"`
 	Explanation        string             `json:"explanation"`
+
+	// Interval is the credible interval from the memory's evidence counts.
+	// AdjustedConfidence never exceeds its upper bound.
+	Interval domain.ConfidenceInterval `json:"interval"`
 }
 
 // UncertaintyReport contains areas of uncertainty for an agent.
@@ -59,6 +67,24 @@ type UncertaintyReport struct {
 	LowConfidenceBeliefs []domain.Memory `json:"low_confidence_beliefs,omitempty"`
 	StaleBeliefs         []domain.Memory `json:"stale_beliefs,omitempty"`
 	Recommendation       string          `json:"recommendation"`
+
+	// Topics breaks the analyzed memories down by topic, most uncertain
+	// first.
+	Topics []TopicUncertainty `json:"topics,omitempty"`
+
+	// Questions are what the agent can ask the user to resolve the most
+	// uncertain beliefs, most urgent first. Empty without an LLM client.
+	Questions []domain.VerificationQuestion `json:"questions,omitempty"`
+}
+
+// TopicUncertainty counts the uncertainty signals among one topic's memories.
+type TopicUncertainty struct {
+	Topic            string  `json:"topic"`
+	Count            int     `json:"count"`
+	Contradicted     int     `json:"contradicted"`
+	LowConfidence    int     `json:"low_confidence"`
+	Stale            int     `json:"stale"`
+	UncertaintyLevel float32 `json:"uncertainty_level"`
 }
 
 // ProcedureAssessment contains the assessment of a procedure's effectiveness.
@@ -74,6 +100,12 @@ type FailurePattern struct {
 	Frequency  int      `json:"frequency"`
 	Topics     []string `json:"topics,omitempty"`
 	Suggestion string   `json:"suggestion"`
+
+	// Recent and Previous split Frequency between the later and earlier half
+	// of the lookback window; Trend compares them.
+	Recent   int               `json:"recent"`
+	Previous int               `json:"previous"`
+	Trend    domain.TopicTrend `json:"trend,omitempty"`
 }
 
 // StrategyReflection contains the reflection on agent strategies.
@@ -101,6 +133,9 @@ type MetacognitiveService struct {
 	schemaStore        domain.SchemaStore
 	contradictionStore domain.ContradictionStore
 	embeddingClient    domain.EmbeddingClient
+	scanner            domain.MemoryScanner
+	sourceReliability  *SourceReliabilityService
+	llmClient          domain.LLMClient
 	logger             *zap.Logger
 }
 
@@ -125,6 +160,24 @@ func NewMetacognitiveService(
 	}
 }
 
+// SetMemoryScanner streams memories during uncertainty detection and
+// reflection instead of loading every memory of the agent.
+func (s *MetacognitiveService) SetMemoryScanner(ms domain.MemoryScanner) {
+	s.scanner = ms
+}
+
+// SetSourceReliability weighs each memory by its source's learned reliability
+// instead of the fixed per-source defaults.
+func (s *MetacognitiveService) SetSourceReliability(sr *SourceReliabilityService) {
+	s.sourceReliability = sr
+}
+
+// SetLLMClient has uncertainty reports include questions for verifying the
+// most uncertain beliefs.
+func (s *MetacognitiveService) SetLLMClient(llm domain.LLMClient) {
+	s.llmClient = llm
+}
+
 // AssessConfidence evaluates how confident we should be in a memory.
 func (s *MetacognitiveService) AssessConfidence(ctx context.Context, memory domain.Memory) (*ConfidenceAssessment, error) {
 	assessment := &ConfidenceAssessment{
@@ -148,7 +201,7 @@ func (s *MetacognitiveService) AssessConfidence(ctx context.Context, memory doma
 	if s.contradictionStore != nil {
 		contradictions, err := s.contradictionStore.GetByBeliefID(ctx, memory.ID)
 		if err != nil {
-			s.logger.Debug("failed to get contradictions", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to get contradictions", zap.Error(err))
 		} else {
 			contradictionPenalty = float32(len(contradictions)) * ContradictionPenaltyPer
 		}
@@ -156,9 +209,13 @@ func (s *MetacognitiveService) AssessConfidence(ctx context.Context, memory doma
 	assessment.Factors["contradictions"] = -contradictionPenalty
 
 	// Factor 4: Source reliability
-	sourceFactor := s.assessSourceReliability(memory.Source)
+	sourceFactor := s.assessSourceReliability(ctx, memory)
 	assessment.Factors["source"] = sourceFactor
 
+	// Factor 5: Evidence - how much corroboration is there, either way?
+	assessment.Interval = memory.ConfidenceInterval(domain.DefaultCredibleLevel)
+	assessment.Factors["uncertainty"] = float32(assessment.Interval.Width())
+
 	// Combined assessment in log-odds space (consistent with the confidence
 	// engine). The previous multiplicative formula
 	//   base * recency * (1+reinforcement) * source - penalty
@@ -180,6 +237,12 @@ func (s *MetacognitiveService) AssessConfidence(ctx context.Context, memory doma
 	if adjusted > MaxAdjustedConfidence {
 		adjusted = MaxAdjustedConfidence
 	}
+	// Recency and reinforcement heuristics can't claim more certainty than the
+	// evidence supports: a thinly evidenced belief has a wide interval and is
+	// barely affected, a well-contradicted one is held down.
+	if upper := float32(assessment.Interval.Upper); adjusted > upper && upper >= MinAdjustedConfidence {
+		adjusted = upper
+	}
 	assessment.AdjustedConfidence = adjusted
 
 	// Generate explanation
@@ -199,7 +262,7 @@ func (s *MetacognitiveService) calculateRecencyFactor(memory domain.Memory) floa
 		lastVerified = memory.UpdatedAt
 	}
 
-	daysSince := time.Since(lastVerified).Hours() / 24
+	daysSince := timeNow().Sub(lastVerified).Hours() / 24
 	// Exponential decay over RecencyDecayDays
 	factor := math.Exp(-daysSince / RecencyDecayDays)
 	return float32(factor)
@@ -218,20 +281,10 @@ func (s *MetacognitiveService) calculateReinforcementFactor(memory domain.Memory
 	return float32(factor)
 }
 
-// assessSourceReliability returns a reliability factor based on memory source.
-func (s *MetacognitiveService) assessSourceReliability(source string) float32 {
-	switch domain.BeliefSource(source) {
-	case domain.SourceUserStatement:
-		return SourceReliabilityUserStatement
-	case domain.SourceExtraction:
-		return SourceReliabilityExtraction
-	case domain.SourceAgentInference:
-		return SourceReliabilityAgentInference
-	case domain.SourceToolOutput:
-		return SourceReliabilityToolOutput
-	default:
-		return SourceReliabilityDefault
-	}
+// assessSourceReliability returns the agent's learned reliability of the
+// memory's source, or the source's default without a reliability service.
+func (s *MetacognitiveService) assessSourceReliability(ctx context.Context, memory domain.Memory) float32 {
+	return float32(s.sourceReliability.Reliability(ctx, memory.AgentID, memory.TenantID, domain.SourceOf(&memory)))
 }
 
 // generateConfidenceExplanation creates a human-readable explanation.
@@ -283,11 +336,11 @@ func (s *MetacognitiveService) DetectUncertainty(ctx context.Context, agentID, t
 		// Search by topic using embedding
 		embedding, err := s.embeddingClient.Embed(ctx, topic)
 		if err != nil {
-			s.logger.Debug("failed to embed topic", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to embed topic", zap.Error(err))
 		} else {
 			similar, err := s.memoryStore.FindSimilar(ctx, agentID, tenantID, embedding, 0.5)
 			if err != nil {
-				s.logger.Debug("failed to find similar memories", zap.Error(err))
+				logFor(ctx, s.logger).Debug("failed to find similar memories", zap.Error(err))
 			} else {
 				for _, ms := range similar {
 					memories = append(memories, ms.Memory)
@@ -296,30 +349,40 @@ func (s *MetacognitiveService) DetectUncertainty(ctx context.Context, agentID, t
 		}
 	}
 
-	// If no topic-specific memories or no topic, get all agent memories
-	if len(memories) == 0 {
-		memories, err = s.memoryStore.GetByAgentForDecay(ctx, agentID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get memories: %w", err)
-		}
-	}
-
 	// Analyze memories for uncertainty signals
-	now := time.Now()
+	now := timeNow()
 	staleThreshold := now.Add(-StaleMemoryDays * 24 * time.Hour)
+	analyzed := 0
+	topics := make(map[string]*TopicUncertainty)
+	open, err := s.openContradictions(ctx, agentID, tenantID)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to list contradictions", zap.Error(err))
+	}
+	contradictedBy := make(map[uuid.UUID][]uuid.UUID)
+
+	analyze := func(mem domain.Memory) {
+		analyzed++
+		name := domain.TopicOf(&mem)
+		topic := topics[name]
+		if topic == nil {
+			topic = &TopicUncertainty{Topic: name}
+			topics[name] = topic
+		}
+		topic.Count++
 
-	for _, mem := range memories {
 		// Check for contradictions
-		if s.contradictionStore != nil {
-			contradictions, err := s.contradictionStore.GetByBeliefID(ctx, mem.ID)
-			if err == nil && len(contradictions) > 0 {
-				report.ContradictedBeliefs = append(report.ContradictedBeliefs, mem)
-			}
+		if others := open[mem.ID]; len(others) > 0 {
+			report.ContradictedBeliefs = append(report.ContradictedBeliefs, mem)
+			topic.Contradicted++
+			contradictedBy[mem.ID] = others
 		}
 
-		// Check for low confidence
-		if mem.Confidence < LowConfidenceThreshold {
+		// Check for low confidence: the point estimate is low, or the evidence
+		// is confident the belief is below the threshold whatever the estimate.
+		if mem.Confidence < LowConfidenceThreshold ||
+			mem.ConfidenceInterval(domain.DefaultCredibleLevel).Upper < LowConfidenceThreshold {
 			report.LowConfidenceBeliefs = append(report.LowConfidenceBeliefs, mem)
+			topic.LowConfidence++
 		}
 
 		// Check for stale memories
@@ -334,34 +397,208 @@ func (s *MetacognitiveService) DetectUncertainty(ctx context.Context, agentID, t
 
 		if lastVerified.Before(staleThreshold) {
 			report.StaleBeliefs = append(report.StaleBeliefs, mem)
+			topic.Stale++
+		}
+	}
+
+	// If no topic-specific memories or no topic, scan all agent memories
+	if len(memories) > 0 {
+		for _, mem := range memories {
+			analyze(mem)
+		}
+	} else {
+		err = forEachLiveMemory(ctx, s.scanner, s.memoryStore, agentID, func(m *domain.Memory) error {
+			analyze(*m)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get memories: %w", err)
 		}
 	}
 
 	// Calculate overall uncertainty level
-	report.UncertaintyLevel = s.calculateUncertaintyLevel(report, len(memories))
+	report.UncertaintyLevel = s.calculateUncertaintyLevel(report, analyzed)
+	report.Topics = rankTopicUncertainty(topics)
 	report.Recommendation = s.generateUncertaintyRecommendation(report)
+	report.Questions = s.verificationQuestions(ctx, tenantID, report, contradictedBy)
 
 	return report, nil
 }
 
+// uncertainBelief is a belief from an uncertainty report with the reasons it
+// is in it, weighed into a priority.
+type uncertainBelief struct {
+	memory   domain.Memory
+	reasons  []domain.UncertaintyReason
+	priority float32
+}
+
+// rankUncertainBeliefs merges the report's contradicted, low-confidence and
+// stale beliefs, weighing each by its reasons like uncertaintyLevel weighs
+// the report, and orders them most urgent first. Ties go to the less
+// confident belief.
+func rankUncertainBeliefs(report *UncertaintyReport) []uncertainBelief {
+	byID := make(map[uuid.UUID]*uncertainBelief)
+	var order []uuid.UUID
+	add := func(mems []domain.Memory, reason domain.UncertaintyReason, weight float32) {
+		for _, m := range mems {
+			b := byID[m.ID]
+			if b == nil {
+				b = &uncertainBelief{memory: m}
+				byID[m.ID] = b
+				order = append(order, m.ID)
+			}
+			b.reasons = append(b.reasons, reason)
+			b.priority += weight
+		}
+	}
+	add(report.ContradictedBeliefs, domain.UncertaintyContradicted, contradictionUncertaintyWeight)
+	add(report.LowConfidenceBeliefs, domain.UncertaintyLowConfidence, lowConfidenceUncertaintyWeight)
+	add(report.StaleBeliefs, domain.UncertaintyStale, stalenessUncertaintyWeight)
+
+	out := make([]uncertainBelief, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byID[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].priority != out[j].priority {
+			return out[i].priority > out[j].priority
+		}
+		return out[i].memory.Confidence < out[j].memory.Confidence
+	})
+	return out
+}
+
+// verificationQuestions asks the LLM how to verify the report's
+// MaxVerificationQuestions most uncertain beliefs, and returns the questions
+// in priority order. Questions are advisory, so a failure is logged and
+// yields none.
+func (s *MetacognitiveService) verificationQuestions(ctx context.Context, tenantID uuid.UUID, report *UncertaintyReport, contradictedBy map[uuid.UUID][]uuid.UUID) []domain.VerificationQuestion {
+	if s.llmClient == nil {
+		return nil
+	}
+	ranked := rankUncertainBeliefs(report)
+	if len(ranked) == 0 {
+		return nil
+	}
+	if len(ranked) > MaxVerificationQuestions {
+		ranked = ranked[:MaxVerificationQuestions]
+	}
+
+	beliefs := make([]domain.UncertainBelief, len(ranked))
+	for i, b := range ranked {
+		beliefs[i] = domain.UncertainBelief{Memory: b.memory, Reasons: b.reasons}
+		for _, id := range contradictedBy[b.memory.ID] {
+			other, err := s.memoryStore.GetByID(ctx, id, tenantID)
+			if err != nil {
+				continue
+			}
+			beliefs[i].Contradictions = append(beliefs[i].Contradictions, other.Content)
+		}
+	}
+
+	generated, err := s.llmClient.GenerateVerificationQuestions(ctx, beliefs)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to generate verification questions", zap.Error(err))
+		return nil
+	}
+	byMemory := make(map[uuid.UUID]string, len(generated))
+	for _, q := range generated {
+		if _, ok := byMemory[q.MemoryID]; !ok && q.Question != "" {
+			byMemory[q.MemoryID] = q.Question
+		}
+	}
+
+	var questions []domain.VerificationQuestion
+	for _, b := range ranked {
+		if q, ok := byMemory[b.memory.ID]; ok {
+			questions = append(questions, domain.VerificationQuestion{
+				MemoryID: b.memory.ID,
+				Question: q,
+				Reasons:  b.reasons,
+				Priority: b.priority,
+			})
+		}
+	}
+	return questions
+}
+
+// contradictionPageSize is how many open contradiction pairs are read per query
+// when loading an agent's contradictions.
+const contradictionPageSize = 500
+
+// openContradictions maps each of the agent's contradicted beliefs to the
+// beliefs contradicting it, read a page of open pairs at a time rather than
+// one query per memory.
+func (s *MetacognitiveService) openContradictions(ctx context.Context, agentID, tenantID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	out := make(map[uuid.UUID][]uuid.UUID)
+	if s.contradictionStore == nil {
+		return out, nil
+	}
+	for offset := 0; ; offset += contradictionPageSize {
+		pairs, err := s.contradictionStore.ListOpenByAgent(ctx, agentID, tenantID, contradictionPageSize, offset)
+		if err != nil {
+			return out, err
+		}
+		for _, p := range pairs {
+			out[p.BeliefID] = append(out[p.BeliefID], p.OtherID)
+		}
+		if len(pairs) < contradictionPageSize {
+			return out, nil
+		}
+	}
+}
+
+// rankTopicUncertainty scores each topic like the report as a whole and keeps
+// the MaxHealthTopics most uncertain.
+func rankTopicUncertainty(topics map[string]*TopicUncertainty) []TopicUncertainty {
+	out := make([]TopicUncertainty, 0, len(topics))
+	for _, t := range topics {
+		t.UncertaintyLevel = uncertaintyLevel(t.Contradicted, t.LowConfidence, t.Stale, t.Count)
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].UncertaintyLevel != out[j].UncertaintyLevel {
+			return out[i].UncertaintyLevel > out[j].UncertaintyLevel
+		}
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Topic < out[j].Topic
+	})
+	if len(out) > MaxHealthTopics {
+		out = out[:MaxHealthTopics]
+	}
+	return out
+}
+
 // calculateUncertaintyLevel computes the overall uncertainty 0-1.
 func (s *MetacognitiveService) calculateUncertaintyLevel(report *UncertaintyReport, totalMemories int) float32 {
+	return uncertaintyLevel(len(report.ContradictedBeliefs), len(report.LowConfidenceBeliefs), len(report.StaleBeliefs), totalMemories)
+}
+
+// Weights of the uncertainty signals in a report's uncertainty level and a
+// belief's verification priority.
+const (
+	contradictionUncertaintyWeight = 0.4
+	lowConfidenceUncertaintyWeight = 0.35
+	stalenessUncertaintyWeight     = 0.25
+)
+
+// uncertaintyLevel weighs the share of contradicted, low-confidence and stale
+// memories among totalMemories into a 0-1 uncertainty.
+func uncertaintyLevel(contradicted, lowConfidence, stale, totalMemories int) float32 {
 	if totalMemories == 0 {
 		return 0
 	}
 
-	// Weight different uncertainty factors
-	contradictionWeight := 0.4
-	lowConfidenceWeight := 0.35
-	stalenessWeight := 0.25
+	contradictionRatio := float32(contradicted) / float32(totalMemories)
+	lowConfidenceRatio := float32(lowConfidence) / float32(totalMemories)
+	stalenessRatio := float32(stale) / float32(totalMemories)
 
-	contradictionRatio := float32(len(report.ContradictedBeliefs)) / float32(totalMemories)
-	lowConfidenceRatio := float32(len(report.LowConfidenceBeliefs)) / float32(totalMemories)
-	stalenessRatio := float32(len(report.StaleBeliefs)) / float32(totalMemories)
-
-	uncertainty := float32(contradictionWeight)*contradictionRatio +
-		float32(lowConfidenceWeight)*lowConfidenceRatio +
-		float32(stalenessWeight)*stalenessRatio
+	uncertainty := contradictionUncertaintyWeight*contradictionRatio +
+		lowConfidenceUncertaintyWeight*lowConfidenceRatio +
+		stalenessUncertaintyWeight*stalenessRatio
 
 	if uncertainty > 1.0 {
 		uncertainty = 1.0
@@ -416,7 +653,7 @@ func (s *MetacognitiveService) ReflectOnStrategy(ctx context.Context, agentID, t
 
 	procedures, err := s.procedureStore.GetByAgent(ctx, agentID, tenantID)
 	if err != nil {
-		s.logger.Debug("failed to get procedures", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to get procedures", zap.Error(err))
 		return reflection, nil
 	}
 
@@ -461,17 +698,24 @@ func (s *MetacognitiveService) analyzeFailurePatterns(ctx context.Context, agent
 	}
 
 	// Get recent episodes with failures
-	endTime := time.Now()
+	endTime := timeNow()
 	startTime := endTime.Add(-RecentFailureLookbackDays * 24 * time.Hour)
 
 	episodes, err := s.episodeStore.GetByTimeRange(ctx, agentID, tenantID, startTime, endTime)
 	if err != nil {
-		s.logger.Debug("failed to get episodes for failure analysis", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to get episodes for failure analysis", zap.Error(err))
 		return patterns
 	}
 
-	// Collect topics from failed episodes
-	topicFrequency := make(map[string]int)
+	// Collect topics from failed episodes, split at the middle of the window
+	// so each topic's trend can be reported.
+	type topicCount struct {
+		topic            string
+		count            int
+		recent, previous int
+	}
+	counts := make(map[string]*topicCount)
+	midpoint := startTime.Add(endTime.Sub(startTime) / 2)
 	failureCount := 0
 
 	for _, ep := range episodes {
@@ -480,8 +724,19 @@ func (s *MetacognitiveService) analyzeFailurePatterns(ctx context.Context, agent
 		}
 		failureCount++
 
-		for _, topic := range ep.Topics {
-			topicFrequency[topic]++
+		// Topics of episodes stored before normalization are folded here.
+		for _, topic := range domain.NormalizeTopics(ep.Topics) {
+			tc := counts[topic]
+			if tc == nil {
+				tc = &topicCount{topic: topic}
+				counts[topic] = tc
+			}
+			tc.count++
+			if ep.OccurredAt.Before(midpoint) {
+				tc.previous++
+			} else {
+				tc.recent++
+			}
 		}
 	}
 
@@ -490,19 +745,18 @@ func (s *MetacognitiveService) analyzeFailurePatterns(ctx context.Context, agent
 	}
 
 	// Find topics that appear frequently in failures
-	type topicCount struct {
-		topic string
-		count int
-	}
 	var sortedTopics []topicCount
-	for topic, count := range topicFrequency {
-		if count >= 2 { // At least 2 occurrences
-			sortedTopics = append(sortedTopics, topicCount{topic, count})
+	for _, tc := range counts {
+		if tc.count >= 2 { // At least 2 occurrences
+			sortedTopics = append(sortedTopics, *tc)
 		}
 	}
 
 	sort.Slice(sortedTopics, func(i, j int) bool {
-		return sortedTopics[i].count > sortedTopics[j].count
+		if sortedTopics[i].count != sortedTopics[j].count {
+			return sortedTopics[i].count > sortedTopics[j].count
+		}
+		return sortedTopics[i].topic < sortedTopics[j].topic
 	})
 
 	// Convert to failure patterns (top 3)
@@ -515,6 +769,9 @@ func (s *MetacognitiveService) analyzeFailurePatterns(ctx context.Context, agent
 			Frequency:  tc.count,
 			Topics:     []string{tc.topic},
 			Suggestion: fmt.Sprintf("Review and improve handling of '%s' related situations", tc.topic),
+			Recent:     tc.recent,
+			Previous:   tc.previous,
+			Trend:      domain.TrendOf(tc.recent, tc.previous),
 		}
 		patterns = append(patterns, pattern)
 	}
@@ -558,21 +815,25 @@ func (s *MetacognitiveService) Reflect(ctx context.Context, agentID, tenantID uu
 
 	// Confidence assessments
 	if focus == "" || focus == "all" || focus == "confidence" {
-		memories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
-		if err != nil {
-			s.logger.Debug("failed to get memories for confidence assessment", zap.Error(err))
-		} else {
-			// Assess top memories (limit to avoid overwhelming response)
-			limit := min(20, len(memories))
-
-			for i := range limit {
-				assessment, err := s.AssessConfidence(ctx, memories[i])
-				if err != nil {
-					s.logger.Debug("failed to assess confidence", zap.Error(err))
-					continue
-				}
-				result.ConfidenceAssessments = append(result.ConfidenceAssessments, *assessment)
+		// Assess the first memories only, to avoid overwhelming the response
+		// (and loading every memory of a large agent).
+		const limit = 20
+		seen := 0
+		err := forEachLiveMemory(ctx, s.scanner, s.memoryStore, agentID, func(m *domain.Memory) error {
+			if seen == limit {
+				return domain.ErrStopIteration
 			}
+			seen++
+			assessment, err := s.AssessConfidence(ctx, *m)
+			if err != nil {
+				logFor(ctx, s.logger).Debug("failed to assess confidence", zap.Error(err))
+				return nil
+			}
+			result.ConfidenceAssessments = append(result.ConfidenceAssessments, *assessment)
+			return nil
+		})
+		if err != nil {
+			logFor(ctx, s.logger).Debug("failed to get memories for confidence assessment", zap.Error(err))
 		}
 	}
 
@@ -580,7 +841,7 @@ func (s *MetacognitiveService) Reflect(ctx context.Context, agentID, tenantID uu
 	if focus == "" || focus == "all" || focus == "uncertainty" {
 		uncertainty, err := s.DetectUncertainty(ctx, agentID, tenantID, "")
 		if err != nil {
-			s.logger.Debug("failed to detect uncertainty", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to detect uncertainty", zap.Error(err))
 		} else {
 			result.UncertaintyReport = uncertainty
 		}
@@ -590,7 +851,7 @@ func (s *MetacognitiveService) Reflect(ctx context.Context, agentID, tenantID uu
 	if focus == "" || focus == "all" || focus == "strategy" {
 		strategy, err := s.ReflectOnStrategy(ctx, agentID, tenantID)
 		if err != nil {
-			s.logger.Debug("failed to reflect on strategy", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to reflect on strategy", zap.Error(err))
 		} else {
 			result.StrategyReflection = strategy
 		}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
@@ -28,6 +29,36 @@ func (m *mockContradictionStoreForMetacog) ListByAgent(ctx context.Context, agen
 	return nil, nil
 }
 
+func (m *mockContradictionStoreForMetacog) ListOpenByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]domain.ContradictionPair, error) {
+	var pairs []domain.ContradictionPair
+	for _, contradictions := range m.contradictions {
+		for _, c := range contradictions {
+			pairs = append(pairs, domain.ContradictionPair{BeliefID: c.BeliefID, OtherID: c.ContradictedByID, DetectedAt: c.DetectedAt.(time.Time)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if !pairs[i].DetectedAt.Equal(pairs[j].DetectedAt) {
+			return pairs[i].DetectedAt.After(pairs[j].DetectedAt)
+		}
+		if pairs[i].BeliefID != pairs[j].BeliefID {
+			return pairs[i].BeliefID.String() < pairs[j].BeliefID.String()
+		}
+		return pairs[i].OtherID.String() < pairs[j].OtherID.String()
+	})
+	if offset >= len(pairs) {
+		return nil, nil
+	}
+	pairs = pairs[offset:]
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs, nil
+}
+
+func (m *mockContradictionStoreForMetacog) CountByTopic(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.ContradictionTopicCount, error) {
+	return nil, nil
+}
+
 func (m *mockContradictionStoreForMetacog) Create(ctx context.Context, beliefID, contradictedByID uuid.UUID) error {
 	contradiction := domain.BeliefContradiction{
 		ID:               uuid.New(),
@@ -271,6 +302,89 @@ func TestMetacognitiveService_DetectUncertainty(t *testing.T) {
 	}
 }
 
+func TestMetacognitiveService_DetectUncertainty_ByTopic(t *testing.T) {
+	svc, memStore, _, _, _, tenantID, agentID := setupMetacognitiveTest()
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, m := range []*domain.Memory{
+		{Content: "User eats no meat", Topic: "diet", Confidence: 0.9},
+		{Content: "User might be vegan", Topic: "diet", Confidence: 0.3},
+		{Content: "User works nights", Topic: "work schedule", Confidence: 0.9},
+	} {
+		m.AgentID, m.TenantID, m.Type, m.LastVerifiedAt = agentID, tenantID, domain.MemoryTypeFact, &now
+		_ = memStore.Create(ctx, m)
+	}
+
+	report, err := svc.DetectUncertainty(ctx, agentID, tenantID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(report.Topics) != 2 {
+		t.Fatalf("expected 2 topics, got %+v", report.Topics)
+	}
+	diet := report.Topics[0]
+	if diet.Topic != "diet" || diet.Count != 2 || diet.LowConfidence != 1 || diet.UncertaintyLevel == 0 {
+		t.Fatalf("expected diet to be the most uncertain topic, got %+v", report.Topics)
+	}
+	if report.Topics[1].UncertaintyLevel != 0 {
+		t.Fatalf("expected no uncertainty about work schedule, got %+v", report.Topics[1])
+	}
+}
+
+func TestMetacognitiveService_DetectUncertainty_Questions(t *testing.T) {
+	svc, memStore, contradictionStore, _, _, tenantID, agentID := setupMetacognitiveTest()
+	ctx := context.Background()
+
+	now := time.Now()
+	stale := now.Add(-60 * 24 * time.Hour)
+	mk := func(content string, confidence float32, verified *time.Time) *domain.Memory {
+		m := &domain.Memory{AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact,
+			Content: content, Confidence: confidence, LastVerifiedAt: verified}
+		_ = memStore.Create(ctx, m)
+		return m
+	}
+	nights := mk("User works night shifts", 0.8, &now)
+	days := mk("User works day shifts", 0.9, &now)
+	vegan := mk("User might be vegan", 0.3, &stale)
+	_ = mk("User lives in Lisbon", 0.95, &now)
+	_ = contradictionStore.Create(ctx, nights.ID, days.ID)
+
+	llm := newMockLLMClient()
+	llm.verificationQuestions = []domain.VerificationQuestion{
+		{MemoryID: nights.ID, Question: "Are you working nights or days these days?"},
+		{MemoryID: vegan.ID, Question: "Do you eat a vegan diet?"},
+	}
+	svc.SetLLMClient(llm)
+
+	report, err := svc.DetectUncertainty(ctx, agentID, tenantID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(llm.verificationCalls) != 1 {
+		t.Fatalf("expected one question generation call, got %d", len(llm.verificationCalls))
+	}
+	asked := llm.verificationCalls[0]
+	if len(asked) != 2 || asked[0].Memory.ID != vegan.ID || asked[1].Memory.ID != nights.ID {
+		t.Fatalf("expected the stale low-confidence belief before the contradicted one, got %+v", asked)
+	}
+	if len(asked[1].Contradictions) != 1 || asked[1].Contradictions[0] != days.Content {
+		t.Fatalf("expected the contradicting belief's content, got %v", asked[1].Contradictions)
+	}
+
+	if len(report.Questions) != 2 {
+		t.Fatalf("expected 2 questions, got %+v", report.Questions)
+	}
+	first := report.Questions[0]
+	if first.MemoryID != vegan.ID || first.Question != "Do you eat a vegan diet?" || len(first.Reasons) != 2 {
+		t.Fatalf("unexpected first question: %+v", first)
+	}
+	if report.Questions[1].Reasons[0] != domain.UncertaintyContradicted || report.Questions[1].Priority >= first.Priority {
+		t.Fatalf("unexpected second question: %+v", report.Questions[1])
+	}
+}
+
 func TestMetacognitiveService_DetectUncertainty_NoIssues(t *testing.T) {
 	svc, memStore, _, _, _, tenantID, agentID := setupMetacognitiveTest()
 	ctx := context.Background()
@@ -300,6 +414,33 @@ func TestMetacognitiveService_DetectUncertainty_NoIssues(t *testing.T) {
 	}
 }
 
+func TestMetacognitiveService_OpenContradictionsPages(t *testing.T) {
+	svc, _, contradictionStore, _, _, tenantID, agentID := setupMetacognitiveTest()
+	ctx := context.Background()
+
+	// More pairs than one page, so the later pages must be read too.
+	belief := uuid.New()
+	for i := 0; i < contradictionPageSize+3; i++ {
+		_ = contradictionStore.Create(ctx, belief, uuid.New())
+	}
+	other := uuid.New()
+	_ = contradictionStore.Create(ctx, other, belief)
+
+	open, err := svc.openContradictions(ctx, agentID, tenantID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(open) != 2 {
+		t.Fatalf("expected 2 contradicted beliefs, got %d", len(open))
+	}
+	if len(open[belief]) != contradictionPageSize+3 {
+		t.Fatalf("expected %d contradictions of the first belief, got %d", contradictionPageSize+3, len(open[belief]))
+	}
+	if len(open[other]) != 1 || open[other][0] != belief {
+		t.Fatalf("expected the second belief contradicted by the first, got %v", open[other])
+	}
+}
+
 func TestMetacognitiveService_ReflectOnStrategy(t *testing.T) {
 	svc, _, _, procedureStore, episodeStore, tenantID, agentID := setupMetacognitiveTest()
 	ctx := context.Background()
@@ -574,3 +715,35 @@ func TestAssessConfidence_NoSaturation(t *testing.T) {
 			a.AdjustedConfidence, b.AdjustedConfidence)
 	}
 }
+
+func TestMetacognitiveService_AssessConfidence_CappedByEvidence(t *testing.T) {
+	svc, _, _, _, _, _, _ := setupMetacognitiveTest()
+	now := time.Now()
+
+	// A confident, recently verified belief that has been contradicted far
+	// more often than reinforced.
+	mem := domain.Memory{
+		ID:                 uuid.New(),
+		Content:            "User prefers dark mode",
+		Type:               domain.MemoryTypePreference,
+		Confidence:         0.9,
+		LastVerifiedAt:     &now,
+		ReinforcementCount: 5,
+		Source:             string(domain.SourceUserStatement),
+		EvidenceFor:        3,
+		EvidenceAgainst:    12,
+	}
+	assessment, err := svc.AssessConfidence(context.Background(), mem)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if assessment.Interval.Upper >= 0.5 {
+		t.Fatalf("expected the interval well below 0.5, got %+v", assessment.Interval)
+	}
+	if float64(assessment.AdjustedConfidence) > assessment.Interval.Upper+1e-6 {
+		t.Errorf("expected adjusted confidence capped at %f, got %f", assessment.Interval.Upper, assessment.AdjustedConfidence)
+	}
+	if _, ok := assessment.Factors["uncertainty"]; !ok {
+		t.Error("expected an uncertainty factor")
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultOutboxInterval = 5 * time.Second
+	OutboxBatchSize       = 100
+	OutboxClaimLease      = time.Minute
+	OutboxMaxBackoff      = time.Hour
+	outboxBaseBackoff     = 10 * time.Second
+
+	// Delivered events are kept briefly for inspection; undelivered ones are
+	// retried for a week and then abandoned.
+	OutboxPublishedRetention   = 24 * time.Hour
+	OutboxUndeliveredRetention = 7 * 24 * time.Hour
+)
+
+// OutboxPublisherService drains the event outbox into an EventPublisher.
+// Delivery is at-least-once: an event is marked published only after the sink
+// accepts it, and failed events are retried with exponential backoff until the
+// expirer abandons them. Events go out in id order within a batch, but a
+// retried event can arrive after later ones.
+type OutboxPublisherService struct {
+	outboxStore domain.OutboxStore
+	publisher   domain.EventPublisher
+	fanout      DatabaseFanout
+	logger      *zap.Logger
+
+	interval   time.Duration
+	stopCh     chan struct{}
+	cancelRuns context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewOutboxPublisherService creates the publisher worker. With a nil
+// publisher, Start is a no-op and events stay in the outbox until pruned.
+func NewOutboxPublisherService(outbox domain.OutboxStore, pub domain.EventPublisher, logger *zap.Logger) *OutboxPublisherService {
+	return &OutboxPublisherService{
+		outboxStore: outbox,
+		publisher:   pub,
+		logger:      logger,
+		interval:    defaultOutboxInterval,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+func (s *OutboxPublisherService) SetInterval(d time.Duration) {
+	s.interval = d
+}
+
+// SetFanout drains the outbox of every tenant database.
+func (s *OutboxPublisherService) SetFanout(f DatabaseFanout) {
+	s.fanout = f
+}
+
+// Start runs the publisher on a periodic schedule in a background goroutine.
+func (s *OutboxPublisherService) Start() {
+	if s.publisher == nil {
+		s.logger.Info("event publisher not configured, outbox worker disabled")
+		return
+	}
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancelRuns = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.logger.Info("outbox publisher started", zap.Duration("interval", s.interval))
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, tickCancel := context.WithTimeout(baseCtx, OutboxClaimLease)
+				guardPanic(s.logger, "outbox publish tick", func() {
+					s.fanout.each(ctx, func(ctx context.Context) { s.drain(ctx) })
+				})
+				tickCancel()
+			case <-s.stopCh:
+				s.logger.Info("outbox publisher stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop gracefully stops the worker, cancelling any in-flight delivery.
+func (s *OutboxPublisherService) Stop() {
+	if s.cancelRuns != nil {
+		s.cancelRuns()
+	}
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// drain publishes full batches back to back so a backlog clears within one tick.
+func (s *OutboxPublisherService) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		if n := s.RunOnce(ctx); n < OutboxBatchSize {
+			return
+		}
+	}
+}
+
+// RunOnce claims one batch of due events and publishes it, returning the
+// number of events claimed.
+func (s *OutboxPublisherService) RunOnce(ctx context.Context) int {
+	if s.publisher == nil {
+		return 0
+	}
+	events, err := s.outboxStore.Claim(ctx, OutboxBatchSize, OutboxClaimLease)
+	if err != nil {
+		logFor(ctx, s.logger).Error("failed to claim outbox events", zap.Error(err))
+		return 0
+	}
+
+	var published []int64
+	for _, e := range events {
+		if ctx.Err() != nil {
+			// Unresolved claims expire with their lease and are picked up again.
+			break
+		}
+		if err := s.publisher.Publish(ctx, e); err != nil {
+			next := time.Now().Add(outboxBackoff(e.Attempts))
+			if markErr := s.outboxStore.MarkFailed(ctx, e.ID, err.Error(), next); markErr != nil {
+				logFor(ctx, s.logger).Warn("failed to record outbox failure", zap.Int64("event_id", e.ID), zap.Error(markErr))
+			}
+			logFor(ctx, s.logger).Debug("event delivery failed",
+				zap.Int64("event_id", e.ID),
+				zap.String("type", e.Type),
+				zap.Int("attempts", e.Attempts+1),
+				zap.Error(err))
+			continue
+		}
+		published = append(published, e.ID)
+	}
+
+	if err := s.outboxStore.MarkPublished(ctx, published); err != nil {
+		// The events will be delivered again once their lease expires.
+		logFor(ctx, s.logger).Warn("failed to mark outbox events published", zap.Int("count", len(published)), zap.Error(err))
+	}
+	return len(events)
+}
+
+// outboxBackoff doubles the retry delay per failed attempt, capped at OutboxMaxBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 0; i < attempts && d < OutboxMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > OutboxMaxBackoff {
+		d = OutboxMaxBackoff
+	}
+	return d
+}
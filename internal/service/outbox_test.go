@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"go.uber.org/zap"
+)
+
+type mockOutboxStore struct {
+	pending   []domain.OutboxEvent
+	published []int64
+	failed    map[int64]time.Time
+}
+
+func (m *mockOutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	if len(m.pending) > limit {
+		out := m.pending[:limit]
+		m.pending = m.pending[limit:]
+		return out, nil
+	}
+	out := m.pending
+	m.pending = nil
+	return out, nil
+}
+
+func (m *mockOutboxStore) MarkPublished(ctx context.Context, ids []int64) error {
+	m.published = append(m.published, ids...)
+	return nil
+}
+
+func (m *mockOutboxStore) MarkFailed(ctx context.Context, id int64, errMsg string, nextAttempt time.Time) error {
+	if m.failed == nil {
+		m.failed = make(map[int64]time.Time)
+	}
+	m.failed[id] = nextAttempt
+	return nil
+}
+
+func (m *mockOutboxStore) Enqueue(ctx context.Context, e *domain.OutboxEvent) error {
+	e.ID = int64(len(m.pending) + 1)
+	m.pending = append(m.pending, *e)
+	return nil
+}
+
+func (m *mockOutboxStore) DeleteBefore(ctx context.Context, publishedCutoff, abandonCutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+type mockEventPublisher struct {
+	failIDs   map[int64]bool
+	delivered []int64
+}
+
+func (m *mockEventPublisher) Publish(ctx context.Context, e domain.OutboxEvent) error {
+	if m.failIDs[e.ID] {
+		return errors.New("sink unavailable")
+	}
+	m.delivered = append(m.delivered, e.ID)
+	return nil
+}
+
+func TestOutboxPublisher_RunOnce(t *testing.T) {
+	st := &mockOutboxStore{pending: []domain.OutboxEvent{
+		{ID: 1, Type: domain.EventMemoryCreated},
+		{ID: 2, Type: "memory.feedback", Attempts: 3},
+		{ID: 3, Type: "memory.deletion"},
+	}}
+	pub := &mockEventPublisher{failIDs: map[int64]bool{2: true}}
+	svc := NewOutboxPublisherService(st, pub, zap.NewNop())
+
+	before := time.Now()
+	if n := svc.RunOnce(context.Background()); n != 3 {
+		t.Fatalf("claimed %d events, want 3", n)
+	}
+
+	if len(st.published) != 2 || st.published[0] != 1 || st.published[1] != 3 {
+		t.Errorf("published = %v, want [1 3] in id order", st.published)
+	}
+	next, ok := st.failed[2]
+	if !ok {
+		t.Fatal("failed event 2 was not rescheduled")
+	}
+	// Fourth attempt: base backoff doubled three times.
+	if want := before.Add(outboxBaseBackoff * 8); next.Before(want) {
+		t.Errorf("next attempt %v earlier than expected backoff %v", next, want)
+	}
+}
+
+func TestOutboxPublisher_NoPublisher(t *testing.T) {
+	st := &mockOutboxStore{pending: []domain.OutboxEvent{{ID: 1}}}
+	svc := NewOutboxPublisherService(st, nil, zap.NewNop())
+	if n := svc.RunOnce(context.Background()); n != 0 {
+		t.Fatalf("RunOnce without publisher claimed %d events", n)
+	}
+	if len(st.pending) != 1 {
+		t.Error("events must stay in the outbox when no publisher is configured")
+	}
+}
+
+func TestOutboxBackoff_Capped(t *testing.T) {
+	if got := outboxBackoff(0); got != outboxBaseBackoff {
+		t.Errorf("first retry = %v, want %v", got, outboxBaseBackoff)
+	}
+	if got := outboxBackoff(50); got != OutboxMaxBackoff {
+		t.Errorf("backoff after many attempts = %v, want cap %v", got, OutboxMaxBackoff)
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OutcomeAttributor propagates an episode's outcome to whatever was active in
+// working memory during its conversation.
+type OutcomeAttributor interface {
+	AttributeOutcome(ctx context.Context, episode *domain.Episode, outcome domain.OutcomeType) error
+}
+
+// SetProcedureStore lets outcome attribution update procedure success stats.
+func (s *LearningService) SetProcedureStore(ps domain.ProcedureStore) {
+	s.procedureStore = ps
+}
+
+// SetConversationActivationStore enables attributing outcomes to the memories
+// and procedures that held a working memory slot during the conversation.
+func (s *LearningService) SetConversationActivationStore(cs domain.ConversationActivationStore) {
+	s.convActStore = cs
+}
+
+// AttributeOutcome applies a success or failure outcome to every semantic
+// memory and procedure activated during the episode's conversation. Memory
+// confidence moves by the helpful/unhelpful log-odds delta scaled by the
+// memory's peak activation level, so something that barely made the slots is
+// credited less than what dominated them; procedures record a use. Each
+// activation is attributed at most once per episode.
+func (s *LearningService) AttributeOutcome(ctx context.Context, episode *domain.Episode, outcome domain.OutcomeType) error {
+	return s.attributeOutcome(ctx, episode, outcome, nil)
+}
+
+// attributeOutcome is AttributeOutcome skipping memories the caller already
+// applied the outcome to.
+func (s *LearningService) attributeOutcome(ctx context.Context, episode *domain.Episode, outcome domain.OutcomeType, skip map[uuid.UUID]bool) error {
+	if s.convActStore == nil || episode == nil || episode.ConversationID == nil {
+		return nil
+	}
+
+	var feedbackType domain.FeedbackType
+	switch outcome {
+	case domain.OutcomeSuccess:
+		feedbackType = domain.FeedbackTypeHelpful
+	case domain.OutcomeFailure:
+		feedbackType = domain.FeedbackTypeUnhelpful
+	default:
+		return nil
+	}
+	effect := domain.FeedbackEffects[feedbackType]
+
+	acts, err := s.convActStore.ClaimForEpisode(ctx, episode.ID, *episode.ConversationID, episode.TenantID)
+	if err != nil {
+		return err
+	}
+
+	for _, act := range acts {
+		switch act.MemoryType {
+		case domain.ActivatedMemoryTypeSemantic:
+			if skip[act.MemoryID] {
+				continue
+			}
+			scaled := effect
+			scaled.LogOddsDelta *= float64(act.ActivationLevel)
+			if err := s.applyOutcomeEffect(ctx, episode.TenantID, act.MemoryID, scaled, feedbackType, episode.ID); err != nil {
+				logFor(ctx, s.logger).Warn("failed to attribute outcome to memory",
+					zap.String("memory_id", act.MemoryID.String()),
+					zap.Error(err),
+				)
+			}
+		case domain.ActivatedMemoryTypeProcedural:
+			if s.procedureStore == nil {
+				continue
+			}
+			if err := s.procedureStore.RecordUse(ctx, act.MemoryID, outcome == domain.OutcomeSuccess); err != nil {
+				logFor(ctx, s.logger).Warn("failed to attribute outcome to procedure",
+					zap.String("procedure_id", act.MemoryID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAnomalyInterval    = time.Hour
+	AnomalyBaselineDays       = 14             // Days before the last 24 hours that form the baseline
+	MinAnomalyBaselineDays    = 7              // Baseline days with activity needed before anything is flagged
+	AnomalySpikeScore         = 3.0            // Standard deviations above the baseline that make a spike
+	MinAnomalySpikeCount      = 5              // Events in the last 24 hours needed for a spike
+	AnomalyYieldCollapseRatio = 0.25           // Share of the baseline extraction yield below which it has collapsed
+	MinAnomalyYieldEpisodes   = 10             // Episodes consolidated, in the last day and in the baseline, to judge yield
+	AnomalyAlertCooldown      = 24 * time.Hour // An agent's anomaly on a metric is raised once per cooldown
+)
+
+// PipelineAnomalyService watches each agent's memory pipeline for the
+// patterns a silent prompt or model regression leaves behind: a sudden spike
+// in contradictions, extraction yielding far fewer memories per episode, or
+// decay archiving far more than usual. The last 24 hours are compared with
+// the days before; anomalies are logged, passed to Hooks and written to the
+// outbox as pipeline.anomaly events.
+type PipelineAnomalyService struct {
+	metrics  domain.PipelineMetricsStore
+	agents   domain.AgentRegistry
+	outbox   domain.OutboxStore
+	hooks    *Hooks
+	logger   *zap.Logger
+	interval time.Duration
+
+	mu     sync.Mutex
+	raised map[anomalyKey]time.Time
+}
+
+type anomalyKey struct {
+	agentID uuid.UUID
+	metric  domain.PipelineMetric
+}
+
+func NewPipelineAnomalyService(metrics domain.PipelineMetricsStore, agents domain.AgentRegistry, logger *zap.Logger) *PipelineAnomalyService {
+	return &PipelineAnomalyService{
+		metrics:  metrics,
+		agents:   agents,
+		logger:   logger,
+		interval: defaultAnomalyInterval,
+		raised:   make(map[anomalyKey]time.Time),
+	}
+}
+
+// SetOutboxStore raises anomalies as pipeline.anomaly outbox events, which
+// reach the webhook sink.
+func (s *PipelineAnomalyService) SetOutboxStore(o domain.OutboxStore) {
+	s.outbox = o
+}
+
+// SetHooks reports anomalies to in-process callbacks.
+func (s *PipelineAnomalyService) SetHooks(h *Hooks) {
+	s.hooks = h
+}
+
+// ScheduledTask returns the hourly anomaly scan for the Scheduler.
+func (s *PipelineAnomalyService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "pipeline-anomalies", Interval: s.interval, Timeout: 10 * time.Minute, Run: s.scan}
+}
+
+// Detect returns the agent's current pipeline anomalies without raising them.
+func (s *PipelineAnomalyService) Detect(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.PipelineAnomaly, error) {
+	now := timeNow()
+	days, err := s.metrics.DailyByAgent(ctx, agentID, tenantID, now, AnomalyBaselineDays+1)
+	if err != nil {
+		return nil, err
+	}
+	anomalies := detectPipelineAnomalies(days)
+	for i := range anomalies {
+		anomalies[i].AgentID = agentID
+		anomalies[i].TenantID = tenantID
+		anomalies[i].DetectedAt = now
+	}
+	return anomalies, nil
+}
+
+func (s *PipelineAnomalyService) scan(ctx context.Context) error {
+	agents, err := s.agents.ListWithWork(ctx, domain.AgentWorkFilter{LiveMemories: true, LiveEpisodes: true})
+	if err != nil {
+		return fmt.Errorf("list agents for anomaly detection: %w", err)
+	}
+	for _, agent := range agents {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		anomalies, err := s.Detect(ctx, agent.ID, agent.TenantID)
+		if err != nil {
+			logFor(ctx, s.logger).Error("pipeline anomaly detection failed",
+				zap.String("agent_id", agent.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		for _, a := range anomalies {
+			s.raise(ctx, a)
+		}
+	}
+	return nil
+}
+
+// raise reports an anomaly unless the same agent and metric were reported
+// within AnomalyAlertCooldown; the last 24 hours overlap between scans, so
+// one spike would otherwise be raised every hour.
+func (s *PipelineAnomalyService) raise(ctx context.Context, a domain.PipelineAnomaly) {
+	key := anomalyKey{agentID: a.AgentID, metric: a.Metric}
+	s.mu.Lock()
+	if last, ok := s.raised[key]; ok && a.DetectedAt.Sub(last) < AnomalyAlertCooldown {
+		s.mu.Unlock()
+		return
+	}
+	s.raised[key] = a.DetectedAt
+	s.mu.Unlock()
+
+	logFor(ctx, s.logger).Warn("memory pipeline anomaly",
+		zap.String("agent_id", a.AgentID.String()),
+		zap.String("metric", string(a.Metric)),
+		zap.String("kind", string(a.Kind)),
+		zap.Float64("current", a.Current),
+		zap.Float64("baseline", a.Baseline),
+		zap.Float64("score", a.Score))
+	s.hooks.pipelineAnomaly(ctx, a)
+
+	if s.outbox == nil {
+		return
+	}
+	agentID, tenantID := a.AgentID, a.TenantID
+	err := s.outbox.Enqueue(ctx, &domain.OutboxEvent{
+		TenantID: &tenantID,
+		AgentID:  &agentID,
+		Type:     domain.EventPipelineAnomaly,
+		Payload: map[string]any{
+			"agent_id": agentID,
+			"metric":   a.Metric,
+			"kind":     a.Kind,
+			"current":  a.Current,
+			"baseline": a.Baseline,
+			"score":    a.Score,
+		},
+	})
+	if err != nil {
+		logFor(ctx, s.logger).Warn("failed to enqueue pipeline anomaly event",
+			zap.String("agent_id", a.AgentID.String()), zap.Error(err))
+	}
+}
+
+// detectPipelineAnomalies compares day 0 (the last 24 hours) with the
+// baseline days after it. Days missing from days had no activity. Nothing is
+// flagged until MinAnomalyBaselineDays of the baseline saw activity, so a new
+// agent's first busy day isn't a spike.
+func detectPipelineAnomalies(days []domain.PipelineMetricDay) []domain.PipelineAnomaly {
+	var current domain.PipelineMetricDay
+	baseline := make([]domain.PipelineMetricDay, AnomalyBaselineDays)
+	active := 0
+	for _, d := range days {
+		switch {
+		case d.Age == 0:
+			current = d
+		case d.Age >= 1 && d.Age <= AnomalyBaselineDays:
+			baseline[d.Age-1] = d
+			active++
+		}
+	}
+	if active < MinAnomalyBaselineDays {
+		return nil
+	}
+
+	var out []domain.PipelineAnomaly
+	spike := func(metric domain.PipelineMetric, value func(domain.PipelineMetricDay) int) {
+		values := make([]float64, len(baseline))
+		for i, d := range baseline {
+			values[i] = float64(value(d))
+		}
+		mean, std := meanStd(values)
+		cur := float64(value(current))
+		// Counts are roughly Poisson, so a quiet baseline's spread is at least
+		// the square root of its mean.
+		score := (cur - mean) / math.Max(math.Max(std, math.Sqrt(mean)), 1)
+		if cur >= MinAnomalySpikeCount && score >= AnomalySpikeScore {
+			out = append(out, domain.PipelineAnomaly{
+				Metric: metric, Kind: domain.PipelineAnomalySpike,
+				Current: cur, Baseline: roundTo(mean, 2), Score: roundTo(score, 2),
+			})
+		}
+	}
+	spike(domain.PipelineMetricContradictions, func(d domain.PipelineMetricDay) int { return d.Contradictions })
+	spike(domain.PipelineMetricDecayArchived, func(d domain.PipelineMetricDay) int { return d.DecayArchived })
+
+	var episodes, extracted int
+	for _, d := range baseline {
+		episodes += d.EpisodesProcessed
+		extracted += d.SemanticExtracted
+	}
+	if current.EpisodesProcessed >= MinAnomalyYieldEpisodes && episodes >= MinAnomalyYieldEpisodes && extracted > 0 {
+		base := float64(extracted) / float64(episodes)
+		cur := float64(current.SemanticExtracted) / float64(current.EpisodesProcessed)
+		if cur < base*AnomalyYieldCollapseRatio {
+			out = append(out, domain.PipelineAnomaly{
+				Metric: domain.PipelineMetricExtractionYield, Kind: domain.PipelineAnomalyCollapse,
+				Current: roundTo(cur, 3), Baseline: roundTo(base, 3), Score: roundTo(cur/base, 2),
+			})
+		}
+	}
+	return out
+}
+
+func meanStd(values []float64) (mean, std float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
+
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
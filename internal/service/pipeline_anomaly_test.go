@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type fakePipelineMetrics struct {
+	days map[uuid.UUID][]domain.PipelineMetricDay
+}
+
+func (f *fakePipelineMetrics) DailyByAgent(ctx context.Context, agentID, tenantID uuid.UUID, now time.Time, days int) ([]domain.PipelineMetricDay, error) {
+	return f.days[agentID], nil
+}
+
+// steadyPipeline is two weeks of one contradiction, two decay archives and
+// ten episodes yielding five memories a day.
+func steadyPipeline(activeDays int) []domain.PipelineMetricDay {
+	var days []domain.PipelineMetricDay
+	for age := 1; age <= activeDays; age++ {
+		days = append(days, domain.PipelineMetricDay{Age: age, Contradictions: 1, DecayArchived: 2, EpisodesProcessed: 10, SemanticExtracted: 5})
+	}
+	return days
+}
+
+func TestPipelineAnomalyService_RaisesSpikesAndYieldCollapseOnce(t *testing.T) {
+	broken, young := uuid.New(), uuid.New()
+	tenantID := uuid.New()
+	brokenDays := append(steadyPipeline(AnomalyBaselineDays),
+		domain.PipelineMetricDay{Age: 0, Contradictions: 12, DecayArchived: 3, EpisodesProcessed: 20, SemanticExtracted: 0})
+	youngDays := append(steadyPipeline(3),
+		domain.PipelineMetricDay{Age: 0, Contradictions: 12, EpisodesProcessed: 20})
+	metrics := &fakePipelineMetrics{days: map[uuid.UUID][]domain.PipelineMetricDay{broken: brokenDays, young: youngDays}}
+	agents := &fakeAgentRegistry{refs: []domain.AgentRef{{ID: broken, TenantID: tenantID}, {ID: young, TenantID: tenantID}}}
+
+	outbox := &mockOutboxStore{}
+	hooks := NewHooks(testLogger())
+	var hooked []domain.PipelineAnomaly
+	hooks.OnPipelineAnomaly(func(ctx context.Context, a domain.PipelineAnomaly) { hooked = append(hooked, a) })
+
+	svc := NewPipelineAnomalyService(metrics, agents, testLogger())
+	svc.SetOutboxStore(outbox)
+	svc.SetHooks(hooks)
+	ctx := context.Background()
+
+	if err := svc.scan(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hooked) != 2 {
+		t.Fatalf("expected a contradiction spike and a yield collapse, got %+v", hooked)
+	}
+	byMetric := map[domain.PipelineMetric]domain.PipelineAnomaly{}
+	for _, a := range hooked {
+		if a.AgentID != broken {
+			t.Errorf("expected only the established agent to be flagged, got %s", a.AgentID)
+		}
+		byMetric[a.Metric] = a
+	}
+	if a, ok := byMetric[domain.PipelineMetricContradictions]; !ok || a.Kind != domain.PipelineAnomalySpike || a.Current != 12 || a.Baseline != 1 {
+		t.Errorf("expected a contradiction spike from 1 to 12, got %+v", a)
+	}
+	if a, ok := byMetric[domain.PipelineMetricExtractionYield]; !ok || a.Kind != domain.PipelineAnomalyCollapse || a.Baseline != 0.5 || a.Current != 0 {
+		t.Errorf("expected extraction yield to collapse from 0.5 to 0, got %+v", a)
+	}
+	if len(outbox.pending) != 2 || outbox.pending[0].Type != domain.EventPipelineAnomaly || *outbox.pending[0].AgentID != broken {
+		t.Errorf("expected 2 pipeline.anomaly outbox events for the agent, got %+v", outbox.pending)
+	}
+
+	// The next scan still sees the same day but stays quiet.
+	if err := svc.scan(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hooked) != 2 || len(outbox.pending) != 2 {
+		t.Errorf("expected anomalies to be raised once per cooldown, got %d hooks and %d events", len(hooked), len(outbox.pending))
+	}
+}
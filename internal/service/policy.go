@@ -15,6 +15,7 @@ var (
 	ErrPolicyInvalidType    = errors.New("invalid memory type in policy")
 	ErrPolicyMaxMemories    = errors.New("max_memories must be positive")
 	ErrPolicyPriorityWeight = errors.New("priority_weight must be positive")
+	ErrPolicyBeliefUpdate   = errors.New("belief_update must be fixed or bayesian")
 )
 
 type PolicyService struct {
@@ -78,6 +79,9 @@ func (s *PolicyService) UpsertPolicies(ctx context.Context, agentID uuid.UUID, t
 		if p.PriorityWeight <= 0 {
 			return nil, ErrPolicyPriorityWeight
 		}
+		if p.BeliefUpdate != "" && !domain.ValidBeliefUpdateMode(string(p.BeliefUpdate)) {
+			return nil, ErrPolicyBeliefUpdate
+		}
 	}
 
 	var result []domain.Policy
@@ -124,7 +128,7 @@ func (s *PolicyService) EnforceOnCreate(ctx context.Context, m *domain.Memory) e
 	if policy.AutoSummarize && s.llmClient != nil && len(oldest) > 0 {
 		summary, err := s.llmClient.Summarize(ctx, oldest)
 		if err != nil {
-			s.logger.Warn("failed to summarize memories during policy enforcement", zap.Error(err))
+			logFor(ctx, s.logger).Warn("failed to summarize memories during policy enforcement", zap.Error(err))
 		} else {
 			// Create a summarized memory to replace the ones being deleted
 			summarized := &domain.Memory{
@@ -138,13 +142,13 @@ func (s *PolicyService) EnforceOnCreate(ctx context.Context, m *domain.Memory) e
 			if s.embClient != nil {
 				emb, err := s.embClient.Embed(ctx, summary)
 				if err != nil {
-					s.logger.Warn("failed to embed summarized memory", zap.Error(err))
+					logFor(ctx, s.logger).Warn("failed to embed summarized memory", zap.Error(err))
 				} else {
 					summarized.Embedding = emb
 				}
 			}
 			if err := s.memoryStore.Create(ctx, summarized); err != nil {
-				s.logger.Warn("failed to store summarized memory", zap.Error(err))
+				logFor(ctx, s.logger).Warn("failed to store summarized memory", zap.Error(err))
 			}
 		}
 	}
@@ -152,7 +156,7 @@ func (s *PolicyService) EnforceOnCreate(ctx context.Context, m *domain.Memory) e
 	// Delete the excess oldest memories
 	for _, old := range oldest {
 		if err := s.memoryStore.Delete(ctx, old.ID, old.TenantID); err != nil {
-			s.logger.Warn("failed to delete excess memory during enforcement",
+			logFor(ctx, s.logger).Warn("failed to delete excess memory during enforcement",
 				zap.String("memory_id", old.ID.String()),
 				zap.Error(err))
 		}
@@ -161,10 +165,23 @@ func (s *PolicyService) EnforceOnCreate(ctx context.Context, m *domain.Memory) e
 	return nil
 }
 
+// BeliefUpdateMode returns the belief update mode of the agent's policy for
+// memType, or "" when it has none.
+func (s *PolicyService) BeliefUpdateMode(ctx context.Context, agentID uuid.UUID, memType domain.MemoryType) domain.BeliefUpdateMode {
+	policy, err := s.policyStore.GetByAgentIDAndType(ctx, agentID, memType)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			logFor(ctx, s.logger).Debug("failed to load policy for belief update mode", zap.Error(err))
+		}
+		return ""
+	}
+	return policy.BeliefUpdate
+}
+
 func (s *PolicyService) GetTypeWeights(ctx context.Context, agentID uuid.UUID) map[domain.MemoryType]float64 {
 	policies, err := s.policyStore.GetByAgentID(ctx, agentID)
 	if err != nil {
-		s.logger.Debug("failed to load policies for type weights", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to load policies for type weights", zap.Error(err))
 		return nil
 	}
 
@@ -312,3 +312,17 @@ func TestPolicyService_EnforceOnCreate_OverLimit_Summarize(t *testing.T) {
 		t.Fatalf("expected 3 memories (2 original + 1 summary), got %d", count)
 	}
 }
+
+func TestPolicyService_UpsertPolicies_InvalidBeliefUpdate(t *testing.T) {
+	svc, _, _, tenantID, agentID := setupPolicyTest()
+
+	policies := []domain.Policy{{
+		MemoryType:     domain.MemoryTypeFact,
+		MaxMemories:    50,
+		PriorityWeight: 1,
+		BeliefUpdate:   "frequentist",
+	}}
+	if _, err := svc.UpsertPolicies(context.Background(), agentID, tenantID, policies); err != ErrPolicyBeliefUpdate {
+		t.Fatalf("expected ErrPolicyBeliefUpdate, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -21,12 +22,16 @@ const (
 	ProcedureMinConfidenceDefault = 0.4  // Minimum confidence for applicable procedures
 	NewProcedureInitialConfidence = 0.5  // Initial confidence for new procedures
 	RecencyBoostDecayDays         = 30.0 // Days after which recency boost is minimal
+	ProcedureKeywordMatchMin      = 0.5  // Fraction of trigger keywords a cue must contain to match without embedding
 )
 
 var (
 	ErrProcedureNotFound     = errors.New("procedure not found")
 	ErrProcedureContentEmpty = errors.New("episode content is empty")
 	ErrInvalidProcedureID    = errors.New("invalid procedure ID")
+	ErrProcedureTriggerEmpty = errors.New("procedure trigger_pattern is required")
+	ErrProcedureActionEmpty  = errors.New("procedure action_template is required")
+	ErrInvalidActionType     = errors.New("invalid action type")
 )
 
 // ProceduralService handles learning and retrieval of procedural memories (skills).
@@ -89,18 +94,22 @@ func (s *ProceduralService) LearnFromOutcome(ctx context.Context, episodeID uuid
 	}
 
 	if s.llmClient == nil {
-		s.logger.Debug("no LLM client, skipping procedure extraction")
+		logFor(ctx, s.logger).Debug("no LLM client, skipping procedure extraction")
 		return nil
 	}
 
 	pattern, err := s.llmClient.ExtractProcedure(ctx, episode.RawContent)
 	if err != nil {
-		s.logger.Debug("failed to extract procedure", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to extract procedure", zap.Error(err))
 		return nil // Don't fail the whole operation
 	}
 
 	if pattern == nil || pattern.TriggerPattern == "" || pattern.ActionTemplate == "" {
-		s.logger.Debug("no valid procedure pattern extracted")
+		logFor(ctx, s.logger).Debug("no valid procedure pattern extracted")
+		return nil
+	}
+	if err := domain.ValidateActionTemplate(pattern.ActionTemplate); err != nil {
+		logFor(ctx, s.logger).Debug("extracted procedure has an invalid action template", zap.Error(err))
 		return nil
 	}
 
@@ -109,7 +118,7 @@ func (s *ProceduralService) LearnFromOutcome(ctx context.Context, episodeID uuid
 	if s.embeddingClient != nil {
 		embedding, err = s.embeddingClient.Embed(ctx, pattern.TriggerPattern)
 		if err != nil {
-			s.logger.Debug("failed to generate trigger embedding", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to generate trigger embedding", zap.Error(err))
 		}
 	}
 
@@ -121,7 +130,7 @@ func (s *ProceduralService) LearnFromOutcome(ctx context.Context, episodeID uuid
 		)
 		if err == nil && len(similar) > 0 {
 			// Reinforce existing procedure
-			s.logger.Debug("reinforcing existing procedure",
+			logFor(ctx, s.logger).Debug("reinforcing existing procedure",
 				zap.String("procedure_id", similar[0].ID.String()),
 				zap.Float32("similarity", similar[0].Score))
 			return s.reinforceProcedure(ctx, similar[0].ID, episodeID)
@@ -142,7 +151,7 @@ func (s *ProceduralService) LearnFromOutcome(ctx context.Context, episodeID uuid
 		MemoryStrength:      1.0,
 	}
 
-	now := time.Now()
+	now := timeNow()
 	procedure.LastVerifiedAt = &now
 
 	if err := s.procedureStore.Create(ctx, procedure); err != nil {
@@ -151,10 +160,10 @@ func (s *ProceduralService) LearnFromOutcome(ctx context.Context, episodeID uuid
 
 	// Link the episode to this procedure
 	if err := s.episodeStore.LinkDerivedMemory(ctx, episodeID, procedure.ID, "procedural"); err != nil {
-		s.logger.Debug("failed to link episode to procedure", zap.Error(err))
+		logFor(ctx, s.logger).Debug("failed to link episode to procedure", zap.Error(err))
 	}
 
-	s.logger.Info("created new procedure",
+	logFor(ctx, s.logger).Info("created new procedure",
 		zap.String("procedure_id", procedure.ID.String()),
 		zap.String("trigger", pattern.TriggerPattern),
 		zap.String("action_type", string(pattern.ActionType)))
@@ -175,31 +184,39 @@ func (s *ProceduralService) GetApplicableProcedures(ctx context.Context, input P
 		input.Limit = 5
 	}
 
-	// Generate embedding for the situation
-	if s.embeddingClient == nil {
-		return nil, nil
+	qualified := func(candidates []domain.ProcedureWithScore) []domain.ProcedureWithScore {
+		var out []domain.ProcedureWithScore
+		for _, p := range candidates {
+			if p.SuccessRate >= input.MinSuccessRate && p.Confidence >= input.MinConfidence {
+				out = append(out, p)
+			}
+		}
+		return out
 	}
 
-	embedding, err := s.embeddingClient.Embed(ctx, input.Situation)
+	// Situations that name a procedure's trigger keywords are answered from
+	// the keyword index; the situation is only embedded when that falls short.
+	keywordMatches, err := matchTriggerKeywords(ctx, s.procedureStore, input.AgentID, input.TenantID, input.Situation, input.Limit*2)
 	if err != nil {
-		return nil, err
+		logFor(ctx, s.logger).Debug("procedure keyword lookup failed", zap.Error(err))
 	}
+	applicable := qualified(keywordMatches)
 
-	// Find procedures with similar triggers
-	candidates, err := s.procedureStore.FindByTriggerSimilarity(
-		ctx, input.AgentID, input.TenantID,
-		embedding, ProcedureSimilarityThreshold, input.Limit*2, // Get more than needed for filtering
-	)
-	if err != nil {
-		return nil, err
-	}
+	if len(applicable) < input.Limit && s.embeddingClient != nil {
+		embedding, err := s.embeddingClient.Embed(ctx, input.Situation)
+		if err != nil {
+			return nil, err
+		}
 
-	// Filter by minimum success rate and confidence
-	var applicable []domain.ProcedureWithScore
-	for _, p := range candidates {
-		if p.SuccessRate >= input.MinSuccessRate && p.Confidence >= input.MinConfidence {
-			applicable = append(applicable, p)
+		// Find procedures with similar triggers
+		candidates, err := s.procedureStore.FindByTriggerSimilarity(
+			ctx, input.AgentID, input.TenantID,
+			embedding, ProcedureSimilarityThreshold, input.Limit*2, // Get more than needed for filtering
+		)
+		if err != nil {
+			return nil, err
 		}
+		applicable = mergeProcedureMatches(applicable, qualified(candidates))
 	}
 
 	// Sort by combined score: (success_rate * confidence * similarity * recency_boost)
@@ -217,6 +234,90 @@ func (s *ProceduralService) GetApplicableProcedures(ctx context.Context, input P
 	return applicable, nil
 }
 
+// matchTriggerKeywords is the exact-match fast path of procedure lookup: the
+// procedures whose trigger keywords text mostly contains, found through the
+// keyword index without embedding text.
+func matchTriggerKeywords(ctx context.Context, procedureStore domain.ProcedureStore, agentID uuid.UUID, tenantID uuid.UUID, text string, limit int) ([]domain.ProcedureWithScore, error) {
+	terms := domain.TriggerTerms(text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	found, err := procedureStore.FindByTriggerKeywords(ctx, agentID, tenantID, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+	var matches []domain.ProcedureWithScore
+	for _, p := range found {
+		if p.Score >= ProcedureKeywordMatchMin {
+			matches = append(matches, p)
+		}
+	}
+	return matches, nil
+}
+
+// mergeProcedureMatches adds the similarity matches not already found by
+// keyword, keeping the better score for a procedure found both ways.
+func mergeProcedureMatches(keyword, similar []domain.ProcedureWithScore) []domain.ProcedureWithScore {
+	index := make(map[uuid.UUID]int, len(keyword))
+	for i, p := range keyword {
+		index[p.ID] = i
+	}
+	for _, p := range similar {
+		if i, ok := index[p.ID]; ok {
+			if p.Score > keyword[i].Score {
+				keyword[i].Score = p.Score
+			}
+			continue
+		}
+		index[p.ID] = len(keyword)
+		keyword = append(keyword, p)
+	}
+	return keyword
+}
+
+// RenderActionInput supplies the variables for rendering an action template
+// outside activation. Variables are set directly and win over the values
+// derived from the other fields.
+type RenderActionInput struct {
+	Variables      map[string]string
+	UserAttributes map[string]string
+	Goal           string
+	Topics         []string
+}
+
+// RenderAction fills a procedure's action template as activation would.
+// Variables without a value are reported rather than failing.
+func (s *ProceduralService) RenderAction(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, input RenderActionInput) (*domain.RenderedAction, error) {
+	procedure, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	vars := templateVars(input.Goal, input.Topics, input.UserAttributes)
+	for k, v := range input.Variables {
+		vars[k] = v
+	}
+	rendered := domain.RenderActionTemplate(procedure.ActionTemplate, vars)
+	return &rendered, nil
+}
+
+// templateVars resolves the action template variables that come with a
+// request: the goal, the first topic and the user's attributes.
+func templateVars(goal string, topics []string, userAttributes map[string]string) map[string]string {
+	vars := make(map[string]string, len(userAttributes)+2)
+	for k, v := range userAttributes {
+		if name := domain.TemplateUserVariable(k); name != "" {
+			vars[name] = v
+		}
+	}
+	if goal != "" {
+		vars[domain.TemplateVarGoal] = goal
+	}
+	if len(topics) > 0 {
+		vars[domain.TemplateVarTopic] = topics[0]
+	}
+	return vars
+}
+
 // GetByID retrieves a procedure by ID.
 func (s *ProceduralService) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.Procedure, error) {
 	procedure, err := s.procedureStore.GetByID(ctx, id, tenantID)
@@ -243,6 +344,105 @@ func (s *ProceduralService) RecordProcedureOutcome(ctx context.Context, procedur
 	return s.procedureStore.RecordUse(ctx, procedureID, success)
 }
 
+// ValidateSeedProcedure checks a declared procedure before anything is written.
+func ValidateSeedProcedure(p domain.SeedProcedure) error {
+	if strings.TrimSpace(p.TriggerPattern) == "" {
+		return ErrProcedureTriggerEmpty
+	}
+	if strings.TrimSpace(p.ActionTemplate) == "" {
+		return ErrProcedureActionEmpty
+	}
+	if !p.ActionType.IsValid() {
+		return ErrInvalidActionType
+	}
+	if p.Confidence < 0 || p.Confidence > 1 {
+		return ErrInvalidConfidence
+	}
+	if err := domain.ValidateActionTemplate(p.ActionTemplate); err != nil {
+		return err
+	}
+	return p.Conditions.Validate()
+}
+
+// SeedProcedures creates declared procedures for an agent. A procedure whose
+// trigger matches one the agent already has is skipped rather than reinforced,
+// so re-seeding the same document leaves learned statistics untouched.
+func (s *ProceduralService) SeedProcedures(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, seeds []domain.SeedProcedure) ([]domain.Procedure, int, error) {
+	for _, p := range seeds {
+		if err := ValidateSeedProcedure(p); err != nil {
+			return nil, 0, err
+		}
+	}
+	if _, err := s.agentStore.GetByID(ctx, agentID, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, 0, ErrAgentNotFound
+		}
+		return nil, 0, err
+	}
+
+	created := make([]domain.Procedure, 0, len(seeds))
+	skipped := 0
+	for _, seed := range seeds {
+		embedding, known, err := s.knownTrigger(ctx, agentID, tenantID, seed.TriggerPattern)
+		if err != nil {
+			return nil, 0, err
+		}
+		if known {
+			skipped++
+			continue
+		}
+
+		confidence := seed.Confidence
+		if confidence == 0 {
+			confidence = NewProcedureInitialConfidence
+		}
+		now := timeNow()
+		procedure := &domain.Procedure{
+			AgentID:             agentID,
+			TenantID:            tenantID,
+			TriggerPattern:      seed.TriggerPattern,
+			TriggerKeywords:     seed.TriggerKeywords,
+			TriggerEmbedding:    embedding,
+			Conditions:          seed.Conditions,
+			Source:              domain.ProcedureSourceSeeded,
+			ActionTemplate:      seed.ActionTemplate,
+			ActionType:          seed.ActionType,
+			DerivedFromEpisodes: []uuid.UUID{},
+			ExampleExchanges:    seed.Examples,
+			Confidence:          confidence,
+			MemoryStrength:      1.0,
+			LastVerifiedAt:      &now,
+		}
+		if err := s.procedureStore.Create(ctx, procedure); err != nil {
+			return nil, 0, err
+		}
+		created = append(created, *procedure)
+	}
+
+	return created, skipped, nil
+}
+
+// knownTrigger embeds a declared trigger and reports whether the agent already
+// has a procedure with a similar one. Without an embedding nothing is known.
+func (s *ProceduralService) knownTrigger(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, trigger string) ([]float32, bool, error) {
+	if s.embeddingClient == nil {
+		return nil, false, nil
+	}
+	embedding, err := s.embeddingClient.Embed(ctx, trigger)
+	if err != nil {
+		logFor(ctx, s.logger).Warn("failed to embed declared procedure trigger", zap.Error(err))
+		return nil, false, nil
+	}
+	similar, err := s.procedureStore.FindByTriggerSimilarity(
+		ctx, agentID, tenantID,
+		embedding, ProcedureSimilarityThreshold, 1,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	return embedding, len(similar) > 0, nil
+}
+
 // reinforceProcedure increases confidence and links the episode.
 func (s *ProceduralService) reinforceProcedure(ctx context.Context, procedureID uuid.UUID, episodeID uuid.UUID) error {
 	return s.procedureStore.Reinforce(ctx, procedureID, episodeID, ProcedureReinforcementBoost)
@@ -283,7 +483,7 @@ func (s *ProceduralService) recencyBoost(lastUsedAt *time.Time) float64 {
 		return 0.5 // Never used gets lower boost
 	}
 
-	daysSinceUse := time.Since(*lastUsedAt).Hours() / 24
+	daysSinceUse := timeNow().Sub(*lastUsedAt).Hours() / 24
 	if daysSinceUse <= 0 {
 		return 1.0
 	}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -51,6 +52,39 @@ func (m *mockProcedureStore) FindByTriggerSimilarity(ctx context.Context, agentI
 	return []domain.ProcedureWithScore{}, nil
 }
 
+func (m *mockProcedureStore) FindByTriggerKeywords(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, terms []string, limit int) ([]domain.ProcedureWithScore, error) {
+	inTerms := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		inTerms[t] = true
+	}
+	var results []domain.ProcedureWithScore
+	for _, p := range m.procedures {
+		if p.AgentID != agentID || p.TenantID != tenantID || len(p.TriggerKeywords) == 0 {
+			continue
+		}
+		found := 0
+		for _, k := range p.TriggerKeywords {
+			if inTerms[k] {
+				found++
+			}
+		}
+		if found > 0 {
+			results = append(results, domain.ProcedureWithScore{Procedure: *p, Score: float32(found) / float32(len(p.TriggerKeywords))})
+		}
+	}
+	return results, nil
+}
+
+func (m *mockProcedureStore) FindConditional(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]domain.Procedure, error) {
+	var results []domain.Procedure
+	for _, p := range m.procedures {
+		if p.AgentID == agentID && p.TenantID == tenantID && p.Conditions != nil {
+			results = append(results, *p)
+		}
+	}
+	return results, nil
+}
+
 func (m *mockProcedureStore) RecordUse(ctx context.Context, id uuid.UUID, success bool) error {
 	p, ok := m.procedures[id]
 	if !ok {
@@ -347,6 +381,55 @@ func TestProceduralService_GetApplicableProcedures_Empty(t *testing.T) {
 	}
 }
 
+// failingEmbeddingClient fails every embedding, for checking that a path
+// never embeds.
+type failingEmbeddingClient struct{}
+
+func (failingEmbeddingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	return nil, errors.New("embedding unavailable")
+}
+
+func TestProceduralService_GetApplicableProcedures_KeywordFastPath(t *testing.T) {
+	procedureStore := newMockProcedureStore()
+	svc := NewProceduralService(procedureStore, newMockEpisodeStore(), newMockAgentStore(), failingEmbeddingClient{}, newMockLLMClient(), testLogger())
+	ctx := context.Background()
+	tenantID, agentID := uuid.New(), uuid.New()
+
+	reset := &domain.Procedure{
+		AgentID: agentID, TenantID: tenantID,
+		TriggerPattern:  "User cannot log in",
+		TriggerKeywords: domain.NormalizeTriggerKeywords([]string{"Password", "Locked-Out"}),
+		ActionTemplate:  "Walk them through the password reset",
+		SuccessRate:     0.9, Confidence: 0.8,
+	}
+	_ = procedureStore.Create(ctx, reset)
+
+	procedures, err := svc.GetApplicableProcedures(ctx, ProcedureMatchInput{
+		AgentID: agentID, TenantID: tenantID,
+		Situation: "I'm locked out, how do I reset my password?",
+		Limit:     1,
+	})
+	if err != nil {
+		t.Fatalf("keyword match should not embed the situation, got %v", err)
+	}
+	if len(procedures) != 1 || procedures[0].ID != reset.ID {
+		t.Fatalf("expected the reset procedure, got %+v", procedures)
+	}
+	if procedures[0].Score != 1 {
+		t.Errorf("expected score 1 with every keyword present, got %v", procedures[0].Score)
+	}
+
+	// Too few keywords present falls through to similarity search.
+	_, err = svc.GetApplicableProcedures(ctx, ProcedureMatchInput{
+		AgentID: agentID, TenantID: tenantID,
+		Situation: "what's the difference between a lock and a mutex",
+		Limit:     1,
+	})
+	if err == nil {
+		t.Fatal("expected the situation to be embedded without a keyword match")
+	}
+}
+
 func TestProceduralService_RecencyBoost(t *testing.T) {
 	svc, _, _, _, _ := setupProceduralTest()
 
@@ -370,3 +453,85 @@ func TestProceduralService_RecencyBoost(t *testing.T) {
 		t.Fatalf("expected boost ~0.37 for 30 days ago, got %f", boost)
 	}
 }
+
+func TestProceduralService_SeedProcedures(t *testing.T) {
+	svc, procedureStore, _, tenantID, agentID := setupProceduralTest()
+	ctx := context.Background()
+
+	created, skipped, err := svc.SeedProcedures(ctx, agentID, tenantID, []domain.SeedProcedure{
+		{
+			TriggerPattern: "user reports a failed deploy",
+			ActionTemplate: "Ask for the deploy ID first",
+			ActionType:     domain.ActionTypeProblemSolving,
+			Examples:       []domain.ExampleExchange{{Trigger: "deploy broke", Response: "Which deploy ID?"}},
+		},
+		{
+			TriggerPattern: "user asks for a summary",
+			ActionTemplate: "Answer in three bullets",
+			ActionType:     domain.ActionTypeResponseStyle,
+			Confidence:     0.8,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(created) != 2 || skipped != 0 {
+		t.Fatalf("expected 2 created and 0 skipped, got %d and %d", len(created), skipped)
+	}
+	if len(procedureStore.procedures) != 2 {
+		t.Fatalf("expected 2 stored procedures, got %d", len(procedureStore.procedures))
+	}
+	if created[0].Confidence != NewProcedureInitialConfidence {
+		t.Fatalf("expected default confidence %v, got %v", NewProcedureInitialConfidence, created[0].Confidence)
+	}
+	if created[1].Confidence != 0.8 {
+		t.Fatalf("expected declared confidence 0.8, got %v", created[1].Confidence)
+	}
+	if len(created[0].ExampleExchanges) != 1 || len(created[0].TriggerEmbedding) == 0 {
+		t.Fatal("expected examples and a trigger embedding on the seeded procedure")
+	}
+}
+
+func TestProceduralService_SeedProcedures_Invalid(t *testing.T) {
+	svc, procedureStore, _, tenantID, agentID := setupProceduralTest()
+
+	_, _, err := svc.SeedProcedures(context.Background(), agentID, tenantID, []domain.SeedProcedure{
+		{TriggerPattern: "ok", ActionTemplate: "ok", ActionType: domain.ActionTypeWorkflow},
+		{TriggerPattern: "bad", ActionTemplate: "bad", ActionType: "dance"},
+	})
+	if err != ErrInvalidActionType {
+		t.Fatalf("expected ErrInvalidActionType, got %v", err)
+	}
+	if len(procedureStore.procedures) != 0 {
+		t.Fatal("expected nothing stored when any procedure is invalid")
+	}
+}
+
+func TestProceduralService_RenderAction(t *testing.T) {
+	svc, procedureStore, _, tenantID, agentID := setupProceduralTest()
+	ctx := context.Background()
+	p := &domain.Procedure{AgentID: agentID, TenantID: tenantID, TriggerPattern: "greeting", ActionTemplate: "Welcome back, {{user_name}}! Still working on {{topic}}?"}
+	_ = procedureStore.Create(ctx, p)
+
+	rendered, err := svc.RenderAction(ctx, p.ID, tenantID, RenderActionInput{
+		UserAttributes: map[string]string{"name": "Ada"},
+		Variables:      map[string]string{"user_name": "Dr. Lovelace"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rendered.Rendered != "Welcome back, Dr. Lovelace! Still working on {{topic}}?" {
+		t.Errorf("unexpected rendering %q", rendered.Rendered)
+	}
+	if len(rendered.Missing) != 1 || rendered.Missing[0] != "topic" {
+		t.Errorf("expected topic to be missing, got %v", rendered.Missing)
+	}
+
+	if _, err := svc.RenderAction(ctx, uuid.New(), tenantID, RenderActionInput{}); err != ErrProcedureNotFound {
+		t.Errorf("expected ErrProcedureNotFound, got %v", err)
+	}
+	bad := domain.SeedProcedure{TriggerPattern: "t", ActionTemplate: "Ask about {{weather}}", ActionType: domain.ActionTypeResponseStyle}
+	if err := ValidateSeedProcedure(bad); !errors.Is(err, domain.ErrInvalidActionTemplate) {
+		t.Errorf("expected ErrInvalidActionTemplate, got %v", err)
+	}
+}
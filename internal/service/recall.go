@@ -1,30 +1,71 @@
 package service
 
 import (
+	"context"
 	"math"
 	"sort"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
 )
 
 const (
 	DefaultFreshnessDecay  = 0.0001
 	DefaultConfidenceFloor = 0.3
+	// DefaultEnvironmentBoost is the extra weight of a memory formed in the
+	// caller's environment, scaled by how much of it matches.
+	DefaultEnvironmentBoost = 0.2
+)
+
+// Interference constants. Memories similar enough to compete with each other
+// but below RedundancyThreshold, where consolidation would merge them, lower
+// each other's retrieval score.
+const (
+	InterferenceSimilarity     = 0.85 // Least similarity of an interfering neighbor
+	InterferenceNeighbors      = 10   // Nearest neighbors probed per memory
+	DefaultInterferencePenalty = 0.1  // Score lost per interfering neighbor, before damping
 )
 
 type RecallScorer struct {
 	FreshnessDecay  float64
 	ConfidenceFloor float64
 	TypeWeights     map[domain.MemoryType]float64
+	// InterferencePenalty scales the score loss for each interfering
+	// neighbor; Neighbors holds the neighbor count of each memory. A memory
+	// with n neighbors keeps 1/(1+penalty*n) of its score.
+	InterferencePenalty float64
+	Neighbors           map[uuid.UUID]int
+	// Environment is the caller's; a memory formed in it scores up to
+	// 1+EnvironmentBoost times higher.
+	Environment      domain.Environment
+	EnvironmentBoost float64
 }
 
+// ScoreBreakdown explains a recall score factor by factor. Similarity,
+// Confidence, Freshness, TypeWeight and, when they apply, Interference and
+// EnvironmentBoost multiply into the weighted score. A hybrid recall score
+// blends similarity and graph relevance instead: VectorContribution and
+// GraphContribution, each already scaled by Interference and
+// EnvironmentBoost, sum to its FinalScore. Tier and TierThreshold record the
+// retrieval gate the memory passed.
 type ScoreBreakdown struct {
-	Similarity float64 `json:"similarity"`
-	Confidence float64 `json:"confidence"`
-	Freshness  float64 `json:"freshness"`
-	TypeWeight float64 `json:"type_weight,omitempty"`
-	FinalScore float64 `json:"final_score"`
+	Similarity         float64           `json:"similarity"`
+	Confidence         float64           `json:"confidence"`
+	Freshness          float64           `json:"freshness,omitempty"`
+	TypeWeight         float64           `json:"type_weight,omitempty"`
+	ReinforcementCount int               `json:"reinforcement_count"`
+	Tier               domain.MemoryTier `json:"tier"`
+	TierThreshold      float64           `json:"tier_threshold"`
+	Interference       float64           `json:"interference,omitempty"`
+	EnvironmentBoost   float64           `json:"environment_boost,omitempty"`
+	RecencyBoost       float64           `json:"recency_boost,omitempty"`
+	VectorWeight       float64           `json:"vector_weight,omitempty"`
+	GraphScore         float64           `json:"graph_score,omitempty"`
+	GraphWeight        float64           `json:"graph_weight,omitempty"`
+	VectorContribution float64           `json:"vector_contribution,omitempty"`
+	GraphContribution  float64           `json:"graph_contribution,omitempty"`
+	FinalScore         float64           `json:"final_score"`
 }
 
 type ScoredMemory struct {
@@ -34,9 +75,45 @@ type ScoredMemory struct {
 
 func NewRecallScorer() *RecallScorer {
 	return &RecallScorer{
-		FreshnessDecay:  DefaultFreshnessDecay,
-		ConfidenceFloor: DefaultConfidenceFloor,
+		FreshnessDecay:      DefaultFreshnessDecay,
+		ConfidenceFloor:     DefaultConfidenceFloor,
+		InterferencePenalty: DefaultInterferencePenalty,
+		EnvironmentBoost:    DefaultEnvironmentBoost,
+	}
+}
+
+// EnvironmentFactor returns the factor a memory's score is multiplied by for
+// being formed in the caller's environment: 1 when none of it matches.
+func (s *RecallScorer) EnvironmentFactor(m *domain.Memory) float64 {
+	if s.Environment.IsZero() || s.EnvironmentBoost <= 0 {
+		return 1
+	}
+	return 1 + s.EnvironmentBoost*s.Environment.Match(domain.EnvironmentOf(m))
+}
+
+// Interference returns the factor a memory's score is multiplied by for its
+// interfering neighbors: 1 when it has none.
+func (s *RecallScorer) Interference(id uuid.UUID) float64 {
+	n := s.Neighbors[id]
+	if n == 0 || s.InterferencePenalty <= 0 {
+		return 1
+	}
+	return 1 / (1 + s.InterferencePenalty*float64(n))
+}
+
+// LoadNeighbors counts the interfering neighbors of the candidate memories.
+// Without a store, or when the count fails, recall is scored as if no
+// memory had any.
+func (s *RecallScorer) LoadNeighbors(ctx context.Context, ns domain.NeighborhoodStore, tenantID uuid.UUID, ids []uuid.UUID) error {
+	if ns == nil || len(ids) == 0 {
+		return nil
+	}
+	counts, err := ns.CountNeighbors(ctx, tenantID, ids, InterferenceSimilarity, RedundancyThreshold, InterferenceNeighbors)
+	if err != nil {
+		return err
 	}
+	s.Neighbors = counts
+	return nil
 }
 
 func (s *RecallScorer) Score(mem domain.MemoryWithScore, now time.Time) ScoredMemory {
@@ -56,7 +133,17 @@ func (s *RecallScorer) Score(mem domain.MemoryWithScore, now time.Time) ScoredMe
 		}
 	}
 
-	finalScore := similarity * confidence * freshness * typeWeight
+	interference := s.Interference(mem.ID)
+	environment := s.EnvironmentFactor(&mem.Memory)
+	finalScore := similarity * confidence * freshness * typeWeight * interference * environment
+	tier := mem.CurrentTier()
+	// Factors that don't apply are omitted from the breakdown
+	if interference == 1 {
+		interference = 0
+	}
+	if environment == 1 {
+		environment = 0
+	}
 
 	return ScoredMemory{
 		MemoryWithScore: domain.MemoryWithScore{
@@ -64,15 +151,60 @@ func (s *RecallScorer) Score(mem domain.MemoryWithScore, now time.Time) ScoredMe
 			Score:  float32(finalScore),
 		},
 		Breakdown: &ScoreBreakdown{
-			Similarity: similarity,
-			Confidence: confidence,
-			Freshness:  freshness,
-			TypeWeight: typeWeight,
-			FinalScore: finalScore,
+			Similarity:         similarity,
+			Confidence:         confidence,
+			Freshness:          freshness,
+			TypeWeight:         typeWeight,
+			Interference:       interference,
+			EnvironmentBoost:   environment,
+			ReinforcementCount: mem.ReinforcementCount,
+			Tier:               tier,
+			TierThreshold:      domain.GetTierBehavior(tier).RetrievalThreshold,
+			FinalScore:         finalScore,
 		},
 	}
 }
 
+// hybridContributions splits a hybrid recall score into the parts the vector
+// and graph scores contribute under their weights, each scaled by the
+// interference and environment factors (1 when they don't apply). Hybrid
+// recall ranks on their sum.
+func hybridContributions(vectorScore, graphScore float32, vectorWeight, graphWeight, interference, environment float64) (vector, graph float64) {
+	scale := interference * environment
+	return float64(vectorScore) * vectorWeight * scale, float64(graphScore) * graphWeight * scale
+}
+
+// ExplainHybrid breaks down a hybrid recall result with the weights it was
+// ranked under. The contributions are recomputed the way recall computed
+// FinalScore, from the interference and environment factors it recorded.
+func ExplainHybrid(sm domain.ScoredMemory, vectorWeight, graphWeight float64, recencyBoost float32) *ScoreBreakdown {
+	interference, environment := 1.0, 1.0
+	if sm.Interference > 0 {
+		interference = float64(sm.Interference)
+	}
+	if sm.EnvironmentBoost > 0 {
+		environment = float64(sm.EnvironmentBoost)
+	}
+	vector, graph := hybridContributions(sm.VectorScore, sm.GraphScore, vectorWeight, graphWeight, interference, environment)
+	tier := sm.CurrentTier()
+	return &ScoreBreakdown{
+		Similarity:         float64(sm.VectorScore),
+		Confidence:         float64(sm.Confidence),
+		ReinforcementCount: sm.ReinforcementCount,
+		Tier:               tier,
+		TierThreshold:      domain.GetTierBehavior(tier).RetrievalThreshold,
+		Interference:       float64(sm.Interference),
+		EnvironmentBoost:   float64(sm.EnvironmentBoost),
+		RecencyBoost:       float64(recencyBoost),
+		VectorWeight:       vectorWeight,
+		GraphScore:         float64(sm.GraphScore),
+		GraphWeight:        graphWeight,
+		VectorContribution: vector,
+		GraphContribution:  graph,
+		FinalScore:         vector + graph,
+	}
+}
+
 func (s *RecallScorer) Rank(memories []ScoredMemory) []ScoredMemory {
 	sort.Slice(memories, func(i, j int) bool {
 		return memories[i].Breakdown.FinalScore > memories[j].Breakdown.FinalScore
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MaxRecallBatchQueries bounds the queries of one batch recall.
+const MaxRecallBatchQueries = 20
+
+var ErrRecallBatchTooLarge = errors.New("too many queries in batch")
+
+// RecallBatch recalls for each query with the shared options of req, whose
+// own Query is ignored. The queries are embedded in one call when the
+// embedding client supports batches. Results come back grouped in query
+// order; a memory matched by several queries is kept only in the group of
+// the query that ranked it highest, so sub-questions of one task don't
+// return the same memory over and over.
+func (s *HybridRecallService) RecallBatch(ctx context.Context, queries []string, req domain.HybridRecallRequest) ([]domain.RecallGroup, error) {
+	if len(queries) == 0 {
+		return nil, ErrRecallQueryEmpty
+	}
+	if len(queries) > MaxRecallBatchQueries {
+		return nil, ErrRecallBatchTooLarge
+	}
+	for _, q := range queries {
+		if strings.TrimSpace(q) == "" {
+			return nil, ErrRecallQueryEmpty
+		}
+	}
+	req = withHybridDefaults(req)
+
+	// Cached queries skip the embedding; the rest are embedded together
+	groups := make([]domain.RecallGroup, len(queries))
+	gens := make([]uint64, len(queries))
+	var missed []int
+	var missedQueries []string
+	for i, q := range queries {
+		qreq := req
+		qreq.Query = q
+		groups[i].Query = q
+		cached, gen, ok := s.cache.get(qreq)
+		if ok {
+			s.noteUse(qreq, cached)
+			groups[i].Memories = cached
+			continue
+		}
+		gens[i] = gen
+		missed = append(missed, i)
+		missedQueries = append(missedQueries, q)
+	}
+
+	if len(missed) > 0 {
+		embeddings, err := s.embedQueries(ctx, missedQueries)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range missed {
+			qreq := req
+			qreq.Query = queries[i]
+			results, err := s.recallEmbedded(ctx, qreq, embeddings[j])
+			if err != nil {
+				return nil, err
+			}
+			s.cache.put(qreq, gens[i], results)
+			groups[i].Memories = results
+		}
+	}
+	dedupeGroups(groups)
+	return groups, nil
+}
+
+// embedQueries embeds the queries in one batch call, falling back to one
+// call per query when the client can't batch or the batch fails.
+func (s *HybridRecallService) embedQueries(ctx context.Context, queries []string) ([][]float32, error) {
+	if be, ok := s.embeddingClient.(domain.BatchEmbedder); ok {
+		if vecs, err := be.EmbedBatch(ctx, queries); err == nil && len(vecs) == len(queries) {
+			return vecs, nil
+		}
+	}
+	vecs := make([][]float32, len(queries))
+	for i, q := range queries {
+		v, err := s.embeddingClient.Embed(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = v
+	}
+	return vecs, nil
+}
+
+// dedupeGroups keeps each memory only in the group where it scored highest,
+// the earliest such group on a tie.
+func dedupeGroups(groups []domain.RecallGroup) {
+	best := make(map[uuid.UUID]int)
+	for gi, g := range groups {
+		for _, m := range g.Memories {
+			if bi, ok := best[m.ID]; !ok || m.FinalScore > scoreIn(groups[bi], m.ID) {
+				best[m.ID] = gi
+			}
+		}
+	}
+	for gi := range groups {
+		kept := groups[gi].Memories[:0]
+		for _, m := range groups[gi].Memories {
+			if best[m.ID] == gi {
+				kept = append(kept, m)
+			} else {
+				groups[gi].Deduplicated++
+			}
+		}
+		groups[gi].Memories = kept
+	}
+}
+
+func scoreIn(g domain.RecallGroup, id uuid.UUID) float32 {
+	for _, m := range g.Memories {
+		if m.ID == id {
+			return m.FinalScore
+		}
+	}
+	return 0
+}
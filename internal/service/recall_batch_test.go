@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestHybridRecallService_RecallBatchEmbedsOnceAndDedupes(t *testing.T) {
+	memStore := newMockMemoryStore()
+	emb := &batchEmbedder{}
+	svc := NewHybridRecallService(memStore, newMockGraphStore(), newMockEntityStore(), emb, newMockLLMClient())
+
+	tenantID, agentID := uuid.New(), uuid.New()
+	for i := 0; i < 3; i++ {
+		_ = memStore.Create(context.Background(), &domain.Memory{
+			AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact,
+			Content: "memory", Confidence: 0.9,
+		})
+	}
+
+	groups, err := svc.RecallBatch(context.Background(), []string{"first", "second"}, domain.HybridRecallRequest{
+		AgentID: agentID, TenantID: tenantID, VectorWeight: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(emb.batches) != 1 || len(emb.batches[0]) != 2 || emb.singles != 0 {
+		t.Errorf("expected both queries embedded in one batch, got %d batches and %d single calls", len(emb.batches), emb.singles)
+	}
+	if len(groups) != 2 || groups[0].Query != "first" || groups[1].Query != "second" {
+		t.Fatalf("expected a group per query in order, got %+v", groups)
+	}
+	// Every memory scores the same for both queries, so the first keeps them
+	if len(groups[0].Memories) != 3 || len(groups[1].Memories) != 0 || groups[1].Deduplicated != 3 {
+		t.Errorf("expected the memories only in the first group, got %d and %d (%d deduplicated)",
+			len(groups[0].Memories), len(groups[1].Memories), groups[1].Deduplicated)
+	}
+}
+
+func TestHybridRecallService_RecallBatchRejectsBadBatches(t *testing.T) {
+	svc := NewHybridRecallService(newMockMemoryStore(), newMockGraphStore(), newMockEntityStore(), &mockEmbeddingClient{}, newMockLLMClient())
+	req := domain.HybridRecallRequest{AgentID: uuid.New(), TenantID: uuid.New()}
+
+	if _, err := svc.RecallBatch(context.Background(), nil, req); !errors.Is(err, ErrRecallQueryEmpty) {
+		t.Errorf("expected ErrRecallQueryEmpty for no queries, got %v", err)
+	}
+	if _, err := svc.RecallBatch(context.Background(), []string{"a", " "}, req); !errors.Is(err, ErrRecallQueryEmpty) {
+		t.Errorf("expected ErrRecallQueryEmpty for a blank query, got %v", err)
+	}
+	if _, err := svc.RecallBatch(context.Background(), make([]string, MaxRecallBatchQueries+1), req); !errors.Is(err, ErrRecallBatchTooLarge) {
+		t.Errorf("expected ErrRecallBatchTooLarge, got %v", err)
+	}
+}
+
+func TestDedupeGroups_KeepsMemoryWhereItScoredHighest(t *testing.T) {
+	shared, other := uuid.New(), uuid.New()
+	scored := func(id uuid.UUID, score float32) domain.ScoredMemory {
+		return domain.ScoredMemory{Memory: domain.Memory{ID: id}, FinalScore: score}
+	}
+	groups := []domain.RecallGroup{
+		{Query: "a", Memories: []domain.ScoredMemory{scored(other, 0.9), scored(shared, 0.4)}},
+		{Query: "b", Memories: []domain.ScoredMemory{scored(shared, 0.8)}},
+	}
+
+	dedupeGroups(groups)
+
+	if len(groups[0].Memories) != 1 || groups[0].Memories[0].ID != other || groups[0].Deduplicated != 1 {
+		t.Errorf("expected the shared memory dropped from the first group, got %+v", groups[0])
+	}
+	if len(groups[1].Memories) != 1 || groups[1].Memories[0].ID != shared || groups[1].Deduplicated != 0 {
+		t.Errorf("expected the shared memory kept in the second group, got %+v", groups[1])
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultRecallCacheTTL     = 30 * time.Second
+	DefaultRecallCacheEntries = 4096 // Entries cached before the cache is reset
+)
+
+// RecallCache keeps hybrid recall results for a short TTL, keyed by the
+// agent, query and options, so a cue repeated within a conversation skips
+// the embedding and the search. As a domain.MemoryChangeListener on the
+// memory store it drops the results a write could have changed. Writes made
+// by other instances aren't seen, so their effects wait out the TTL. A nil
+// cache caches nothing.
+type RecallCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]recallCacheEntry
+	// gen counts changes, so a recall that raced one isn't cached.
+	gen uint64
+}
+
+type recallCacheEntry struct {
+	tenantID uuid.UUID
+	agentID  uuid.UUID // uuid.Nil for recalls across agents, by anchor
+	storedAt time.Time
+	results  []domain.ScoredMemory
+}
+
+// NewRecallCache returns a cache holding results for ttl, or nil when ttl is
+// not positive.
+func NewRecallCache(ttl time.Duration, maxEntries int) *RecallCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultRecallCacheEntries
+	}
+	return &RecallCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[[sha256.Size]byte]recallCacheEntry),
+	}
+}
+
+// recallCacheKey hashes every field of req, so any option that changes the
+// ranking changes the key. ok is false when req can't be encoded.
+func recallCacheKey(req domain.HybridRecallRequest) (key [sha256.Size]byte, ok bool) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return key, false
+	}
+	return sha256.Sum256(b), true
+}
+
+// get returns a copy of the cached results for req, and the generation to
+// pass to put on a miss.
+func (c *RecallCache) get(req domain.HybridRecallRequest) ([]domain.ScoredMemory, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	key, ok := recallCacheKey(req)
+	if !ok {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, c.gen, false
+	}
+	if timeNow().Sub(e.storedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, c.gen, false
+	}
+	return append([]domain.ScoredMemory(nil), e.results...), c.gen, true
+}
+
+// put caches results for req unless a change came in since gen.
+func (c *RecallCache) put(req domain.HybridRecallRequest, gen uint64, results []domain.ScoredMemory) {
+	if c == nil {
+		return
+	}
+	key, ok := recallCacheKey(req)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = make(map[[sha256.Size]byte]recallCacheEntry)
+	}
+	c.entries[key] = recallCacheEntry{
+		tenantID: req.TenantID,
+		agentID:  req.AgentID,
+		storedAt: timeNow(),
+		results:  append([]domain.ScoredMemory(nil), results...),
+	}
+}
+
+// MemoryChanged drops the cached recalls ch could have changed. The store
+// scopes confidence changes, reinforcement and restores to the whole agent:
+// they can surface a memory in recalls that did not return it.
+func (c *RecallCache) MemoryChanged(ch domain.MemoryChange) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for key, e := range c.entries {
+		if ch.MemoryID != uuid.Nil {
+			if !containsMemory(e.results, ch.MemoryID) {
+				continue
+			}
+		} else if (ch.TenantID != uuid.Nil && ch.TenantID != e.tenantID) ||
+			(ch.AgentID != uuid.Nil && e.agentID != uuid.Nil && ch.AgentID != e.agentID) {
+			continue
+		}
+		delete(c.entries, key)
+	}
+}
+
+func containsMemory(results []domain.ScoredMemory, id uuid.UUID) bool {
+	for _, r := range results {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
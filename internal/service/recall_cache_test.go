@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestHybridRecallService_CachesRepeatedRecallUntilMemoriesChange(t *testing.T) {
+	memStore := newMockMemoryStore()
+	emb := &batchEmbedder{}
+	cache := NewRecallCache(time.Minute, 0)
+	svc := NewHybridRecallService(memStore, newMockGraphStore(), newMockEntityStore(), emb, newMockLLMClient())
+	svc.SetRecallCache(cache)
+
+	tenantID, agentID := uuid.New(), uuid.New()
+	mem := &domain.Memory{AgentID: agentID, TenantID: tenantID, Type: domain.MemoryTypeFact, Content: "likes tea", Confidence: 0.9}
+	_ = memStore.Create(context.Background(), mem)
+	req := domain.HybridRecallRequest{Query: "tea?", AgentID: agentID, TenantID: tenantID}
+
+	recall := func() []domain.ScoredMemory {
+		t.Helper()
+		results, err := svc.Recall(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return results
+	}
+
+	first := recall()
+	if second := recall(); emb.singles != 1 || len(second) != len(first) {
+		t.Fatalf("expected the repeat served from the cache, got %d embeddings", emb.singles)
+	}
+
+	// A change to another agent's memories or to a memory not returned keeps it
+	cache.MemoryChanged(domain.MemoryChange{TenantID: tenantID, AgentID: uuid.New()})
+	cache.MemoryChanged(domain.MemoryChange{MemoryID: uuid.New()})
+	if recall(); emb.singles != 1 {
+		t.Errorf("expected unrelated changes to keep the cached recall, got %d embeddings", emb.singles)
+	}
+
+	cache.MemoryChanged(domain.MemoryChange{MemoryID: mem.ID})
+	if recall(); emb.singles != 2 {
+		t.Errorf("expected a change to a returned memory to drop the cached recall, got %d embeddings", emb.singles)
+	}
+
+	cache.MemoryChanged(domain.MemoryChange{TenantID: tenantID, AgentID: agentID})
+	if recall(); emb.singles != 3 {
+		t.Errorf("expected a new memory for the agent to drop the cached recall, got %d embeddings", emb.singles)
+	}
+
+	req.TopK = 3
+	if recall(); emb.singles != 4 {
+		t.Errorf("expected different options to miss the cache, got %d embeddings", emb.singles)
+	}
+}
+
+func TestRecallCache_ExpiresAndSkipsRacedResults(t *testing.T) {
+	cache := NewRecallCache(time.Minute, 0)
+	req := domain.HybridRecallRequest{Query: "q", AgentID: uuid.New(), TenantID: uuid.New()}
+	results := []domain.ScoredMemory{{Memory: domain.Memory{ID: uuid.New()}}}
+
+	// A change between the miss and the put leaves the results uncached
+	_, gen, _ := cache.get(req)
+	cache.MemoryChanged(domain.MemoryChange{})
+	cache.put(req, gen, results)
+	if _, _, ok := cache.get(req); ok {
+		t.Fatal("expected results computed across a change not to be cached")
+	}
+
+	_, gen, _ = cache.get(req)
+	cache.put(req, gen, results)
+	if _, _, ok := cache.get(req); !ok {
+		t.Fatal("expected the results cached")
+	}
+	for key, e := range cache.entries {
+		e.storedAt = e.storedAt.Add(-time.Minute)
+		cache.entries[key] = e
+	}
+	if _, _, ok := cache.get(req); ok {
+		t.Error("expected the results expired after the TTL")
+	}
+
+	if NewRecallCache(0, 0) != nil {
+		t.Error("expected a zero TTL to disable the cache")
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// RecallScorerVersion tags recall logs so rankings can be compared across
+	// scorer changes. Bump it whenever hybrid scoring or ScoreBreakdown changes.
+	RecallScorerVersion = "hybrid-v1"
+
+	recallLogQueueSize = 256
+)
+
+// RecallLogService records a random sample of recalls, with each result's
+// score breakdown, for offline ranking analysis. Query text is hashed before
+// it leaves the request. Writes happen on a background worker run by Start;
+// samples are dropped rather than slowing recall when the queue is full, and
+// after Stop.
+type RecallLogService struct {
+	store      domain.RecallLogStore
+	logger     *zap.Logger
+	sampleRate atomic.Uint64 // math.Float64bits of the rate
+	random     func() float64
+	queue      chan *domain.RecallLog
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	done    chan struct{}
+}
+
+// NewRecallLogService creates a recall logger sampling the given fraction of
+// recalls (0 disables logging, 1 logs every recall).
+func NewRecallLogService(store domain.RecallLogStore, sampleRate float64, logger *zap.Logger) *RecallLogService {
+	s := &RecallLogService{
+		store:  store,
+		logger: logger,
+		random: rand.Float64,
+		queue:  make(chan *domain.RecallLog, recallLogQueueSize),
+		done:   make(chan struct{}),
+	}
+	s.SetSampleRate(sampleRate)
+	return s
+}
+
+// Start runs the background writer. Samples recorded before Start wait in the
+// queue.
+func (s *RecallLogService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	go func() {
+		defer close(s.done)
+		s.runWriter()
+	}()
+}
+
+// Stop drops further samples and returns once the queued ones have been
+// written, or when ctx is done, whichever comes first.
+func (s *RecallLogService) Stop(ctx context.Context) {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	close(s.queue)
+	if !s.started {
+		s.started = true
+		go func() {
+			defer close(s.done)
+			s.runWriter()
+		}()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		s.logger.Warn("recall log stopped before its queue was written", zap.Int("pending", len(s.queue)))
+	}
+}
+
+// SetSampleRate changes the fraction of recalls logged. Safe to call while
+// recalls are in flight.
+func (s *RecallLogService) SetSampleRate(rate float64) {
+	s.sampleRate.Store(math.Float64bits(rate))
+}
+
+// Sampled reports whether the current recall should be logged.
+func (s *RecallLogService) Sampled() bool {
+	rate := math.Float64frombits(s.sampleRate.Load())
+	if rate <= 0 {
+		return false
+	}
+	return rate >= 1 || s.random() < rate
+}
+
+// Record queues a recall's ranking for storage. breakdowns must be parallel
+// to results. Never blocks.
+func (s *RecallLogService) Record(req domain.HybridRecallRequest, results []domain.ScoredMemory, breakdowns []*ScoreBreakdown, latency time.Duration) {
+	l := &domain.RecallLog{
+		TenantID:      req.TenantID,
+		QueryHash:     domain.HashContent(req.Query),
+		Mode:          req.Mode,
+		ScorerVersion: RecallScorerVersion,
+		TopK:          req.TopK,
+		VectorWeight:  req.VectorWeight,
+		GraphWeight:   req.GraphWeight,
+		LatencyMs:     latency.Milliseconds(),
+		Control:       req.Control,
+		Results:       make([]domain.RecallLogResult, len(results)),
+	}
+	if l.Mode == "" {
+		l.Mode = domain.RecallModeSimilarity
+	}
+	if req.AgentID != uuid.Nil {
+		agentID := req.AgentID
+		l.AgentID = &agentID
+	}
+	for i, r := range results {
+		l.Results[i] = domain.RecallLogResult{
+			MemoryID: r.ID,
+			Rank:     i + 1,
+			Type:     r.Type,
+			Score:    r.FinalScore,
+		}
+		if i < len(breakdowns) && breakdowns[i] != nil {
+			l.Results[i].Breakdown = breakdowns[i]
+		}
+	}
+
+	s.enqueue(l)
+}
+
+// RecordActivation queues a working memory activation's winners for storage,
+// ranked as returned. Used for control activations, whose results the agent
+// never sees. Never blocks.
+func (s *RecallLogService) RecordActivation(input domain.ActivationInput, result *domain.WorkingMemoryResult, latency time.Duration) {
+	agentID := input.AgentID
+	l := &domain.RecallLog{
+		TenantID:      input.TenantID,
+		AgentID:       &agentID,
+		QueryHash:     domain.HashContent(input.Goal + "\n" + strings.Join(input.Cues, "\n")),
+		Mode:          domain.RecallModeActivation,
+		ScorerVersion: RecallScorerVersion,
+		TopK:          result.MaxSlots,
+		LatencyMs:     latency.Milliseconds(),
+		Control:       input.Control,
+		Results:       make([]domain.RecallLogResult, len(result.Activations)),
+	}
+	for i, a := range result.Activations {
+		l.Results[i] = domain.RecallLogResult{
+			MemoryID:      a.ID,
+			Rank:          i + 1,
+			ActivatedType: a.Type,
+			Score:         a.Score,
+		}
+	}
+	s.enqueue(l)
+}
+
+func (s *RecallLogService) enqueue(l *domain.RecallLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	select {
+	case s.queue <- l:
+	default:
+		s.logger.Debug("recall log queue full, dropping sample")
+	}
+}
+
+func (s *RecallLogService) runWriter() {
+	for l := range s.queue {
+		ctx, cancel := context.WithTimeout(domain.WithTenantID(context.Background(), l.TenantID), 5*time.Second)
+		if err := s.store.Create(ctx, l); err != nil {
+			s.logger.Debug("failed to write recall log", zap.Error(err))
+		}
+		cancel()
+	}
+}
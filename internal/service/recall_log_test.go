@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type mockRecallLogStore struct {
+	logs chan *domain.RecallLog
+}
+
+func (m *mockRecallLogStore) Create(ctx context.Context, l *domain.RecallLog) error {
+	m.logs <- l
+	return nil
+}
+
+func TestRecallLogService_Sampled(t *testing.T) {
+	store := &mockRecallLogStore{logs: make(chan *domain.RecallLog, 1)}
+
+	if NewRecallLogService(store, 0, zap.NewNop()).Sampled() {
+		t.Error("expected no sampling at rate 0")
+	}
+	if !NewRecallLogService(store, 1, zap.NewNop()).Sampled() {
+		t.Error("expected every recall sampled at rate 1")
+	}
+
+	svc := NewRecallLogService(store, 0.25, zap.NewNop())
+	svc.random = func() float64 { return 0.2 }
+	if !svc.Sampled() {
+		t.Error("expected draw below rate to be sampled")
+	}
+	svc.random = func() float64 { return 0.3 }
+	if svc.Sampled() {
+		t.Error("expected draw above rate to be skipped")
+	}
+}
+
+func TestRecallLogService_RecordHashesQuery(t *testing.T) {
+	store := &mockRecallLogStore{logs: make(chan *domain.RecallLog, 1)}
+	svc := NewRecallLogService(store, 1, zap.NewNop())
+	svc.Start()
+	defer svc.Stop(context.Background())
+
+	req := domain.HybridRecallRequest{
+		Query:        "where does the user live",
+		AgentID:      uuid.New(),
+		TenantID:     uuid.New(),
+		TopK:         5,
+		VectorWeight: 0.6,
+		GraphWeight:  0.4,
+	}
+	results := []domain.ScoredMemory{
+		{Memory: domain.Memory{ID: uuid.New(), Type: domain.MemoryTypeFact}, FinalScore: 0.9},
+		{Memory: domain.Memory{ID: uuid.New(), Type: domain.MemoryTypePreference}, FinalScore: 0.5},
+	}
+	breakdowns := []*ScoreBreakdown{{FinalScore: 0.9}, {FinalScore: 0.5}}
+
+	svc.Record(req, results, breakdowns, 12*time.Millisecond)
+
+	var l *domain.RecallLog
+	select {
+	case l = <-store.logs:
+	case <-time.After(time.Second):
+		t.Fatal("expected recall log to be written")
+	}
+
+	if l.QueryHash != domain.HashContent(req.Query) || strings.Contains(l.QueryHash, "user") {
+		t.Errorf("expected hashed query, got %q", l.QueryHash)
+	}
+	if l.AgentID == nil || *l.AgentID != req.AgentID || l.TenantID != req.TenantID {
+		t.Error("expected agent and tenant recorded")
+	}
+	if l.Mode != domain.RecallModeSimilarity || l.ScorerVersion != RecallScorerVersion || l.LatencyMs != 12 {
+		t.Errorf("unexpected log header %+v", l)
+	}
+	if len(l.Results) != 2 || l.Results[1].Rank != 2 || l.Results[1].MemoryID != results[1].ID || l.Results[0].Breakdown != breakdowns[0] {
+		t.Errorf("expected ranked results with breakdowns, got %+v", l.Results)
+	}
+}
+
+func TestRecallLogService_RecordActivation(t *testing.T) {
+	store := &mockRecallLogStore{logs: make(chan *domain.RecallLog, 1)}
+	svc := NewRecallLogService(store, 0, zap.NewNop())
+	svc.Start()
+	defer svc.Stop(context.Background())
+
+	input := domain.ActivationInput{
+		AgentID:  uuid.New(),
+		TenantID: uuid.New(),
+		Goal:     "book a flight",
+		Cues:     []string{"window seat"},
+		Control:  true,
+	}
+	result := &domain.WorkingMemoryResult{
+		MaxSlots: 7,
+		Activations: []domain.ActivatedContent{
+			{Type: domain.ActivatedMemoryTypeSemantic, ID: uuid.New(), Score: 0.8},
+			{Type: domain.ActivatedMemoryTypeProcedural, ID: uuid.New(), Score: 0.4},
+		},
+	}
+
+	svc.RecordActivation(input, result, 5*time.Millisecond)
+
+	var l *domain.RecallLog
+	select {
+	case l = <-store.logs:
+	case <-time.After(time.Second):
+		t.Fatal("expected activation log to be written")
+	}
+
+	if !l.Control || l.Mode != domain.RecallModeActivation || l.TopK != 7 {
+		t.Errorf("unexpected log header %+v", l)
+	}
+	if strings.Contains(l.QueryHash, "flight") {
+		t.Errorf("expected hashed goal, got %q", l.QueryHash)
+	}
+	if len(l.Results) != 2 || l.Results[1].ActivatedType != domain.ActivatedMemoryTypeProcedural || l.Results[1].Rank != 2 {
+		t.Errorf("expected ranked activations, got %+v", l.Results)
+	}
+}
+
+func TestRecallLogService_StopWritesQueuedLogs(t *testing.T) {
+	store := &mockRecallLogStore{logs: make(chan *domain.RecallLog, 3)}
+	svc := NewRecallLogService(store, 1, zap.NewNop())
+
+	req := domain.HybridRecallRequest{Query: "q", TenantID: uuid.New()}
+	svc.Record(req, nil, nil, time.Millisecond)
+	svc.Record(req, nil, nil, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	svc.Stop(ctx)
+
+	if len(store.logs) != 2 {
+		t.Fatalf("expected Stop to write both queued logs, got %d", len(store.logs))
+	}
+
+	svc.Record(req, nil, nil, time.Millisecond)
+	svc.Stop(ctx)
+	if len(store.logs) != 2 {
+		t.Errorf("expected samples after Stop to be dropped, got %d logs", len(store.logs))
+	}
+}
@@ -198,3 +198,104 @@ func TestRecallScorer_BreakdownFieldsSet(t *testing.T) {
 		t.Error("final score should be set")
 	}
 }
+
+func TestExplainHybrid(t *testing.T) {
+	now := time.Now()
+
+	sm := domain.ScoredMemory{
+		Memory: domain.Memory{
+			ID:                 uuid.New(),
+			Type:               domain.MemoryTypeConstraint,
+			Confidence:         0.9,
+			ReinforcementCount: 4,
+			UpdatedAt:          now,
+		},
+		VectorScore: 0.8,
+		GraphScore:  0.5,
+		FinalScore:  0.8*0.6 + 0.5*0.4,
+	}
+
+	b := ExplainHybrid(sm, 0.6, 0.4, 0.2)
+
+	if !floatEq(b.Similarity, 0.8) || !floatEq(b.GraphScore, 0.5) {
+		t.Errorf("expected vector and graph scores carried over, got %f and %f", b.Similarity, b.GraphScore)
+	}
+	if !floatEq(b.FinalScore, float64(sm.FinalScore)) {
+		t.Errorf("expected final score to match hybrid score %f, got %f", sm.FinalScore, b.FinalScore)
+	}
+	if !floatEq(b.VectorContribution, 0.8*0.6) || !floatEq(b.GraphContribution, 0.5*0.4) {
+		t.Errorf("expected weighted contributions, got %f and %f", b.VectorContribution, b.GraphContribution)
+	}
+	if !floatEq(b.VectorWeight, 0.6) || !floatEq(b.GraphWeight, 0.4) || !floatEq(b.RecencyBoost, 0.2) {
+		t.Errorf("expected request weights in breakdown, got %+v", b)
+	}
+	if b.TypeWeight != 0 || b.Freshness != 0 || b.ReinforcementCount != 4 {
+		t.Errorf("expected no unweighted-score factors and reinforcement 4, got %+v", b)
+	}
+	if b.Tier != domain.TierHot || b.TierThreshold != domain.GetTierBehavior(domain.TierHot).RetrievalThreshold {
+		t.Errorf("expected hot tier gate, got %s at %f", b.Tier, b.TierThreshold)
+	}
+}
+
+func TestRecallScorer_InterferenceLowersCrowdedMemories(t *testing.T) {
+	scorer := NewRecallScorer()
+	now := time.Now()
+	crowded, alone := uuid.New(), uuid.New()
+	scorer.Neighbors = map[uuid.UUID]int{crowded: 5}
+
+	memories := []domain.MemoryWithScore{
+		{Memory: domain.Memory{ID: crowded, Confidence: 0.9, UpdatedAt: now}, Score: 0.9},
+		{Memory: domain.Memory{ID: alone, Confidence: 0.9, UpdatedAt: now}, Score: 0.8},
+	}
+	ranked := scorer.ScoreAndRank(memories, now)
+
+	if ranked[0].ID != alone {
+		t.Fatalf("expected the uncrowded memory first, got %v", ranked[0].ID)
+	}
+	want := 1 / (1 + DefaultInterferencePenalty*5)
+	if b := ranked[1].Breakdown; !floatEq(b.Interference, want) || !floatEq(b.FinalScore, 0.9*0.9*want) {
+		t.Errorf("expected interference %f, got %+v", want, b)
+	}
+	if ranked[0].Breakdown.Interference != 0 {
+		t.Errorf("a memory without neighbors should report no interference, got %f", ranked[0].Breakdown.Interference)
+	}
+
+	scorer.InterferencePenalty = 0
+	if f := scorer.Interference(crowded); f != 1 {
+		t.Errorf("a zero penalty should disable interference, got %f", f)
+	}
+}
+
+func TestRecallScorer_EnvironmentFavorsMatchingMemories(t *testing.T) {
+	scorer := NewRecallScorer()
+	scorer.Environment = domain.Environment{Channel: "slack", Device: "mobile"}
+	now := time.Now()
+	formed := func(e domain.Environment) map[string]any { return domain.WithEnvironment(nil, e) }
+
+	memories := []domain.MemoryWithScore{
+		{Memory: domain.Memory{ID: uuid.New(), Confidence: 0.9, UpdatedAt: now}, Score: 0.85},
+		{Memory: domain.Memory{ID: uuid.New(), Confidence: 0.9, UpdatedAt: now,
+			Metadata: formed(domain.Environment{Channel: "Slack", Device: "desktop"})}, Score: 0.8},
+		{Memory: domain.Memory{ID: uuid.New(), Confidence: 0.9, UpdatedAt: now,
+			Metadata: formed(domain.Environment{Channel: "slack", Device: "mobile"})}, Score: 0.75},
+	}
+	ranked := scorer.ScoreAndRank(memories, now)
+
+	if ranked[0].ID != memories[2].ID || ranked[1].ID != memories[1].ID {
+		t.Fatalf("expected the fully then partly matching memory first, got %v, %v", ranked[0].ID, ranked[1].ID)
+	}
+	if b := ranked[0].Breakdown; !floatEq(b.EnvironmentBoost, 1+DefaultEnvironmentBoost) {
+		t.Errorf("expected a full match to boost by %f, got %f", 1+DefaultEnvironmentBoost, b.EnvironmentBoost)
+	}
+	if b := ranked[1].Breakdown; !floatEq(b.EnvironmentBoost, 1+DefaultEnvironmentBoost/2) {
+		t.Errorf("expected half a match to boost by %f, got %f", 1+DefaultEnvironmentBoost/2, b.EnvironmentBoost)
+	}
+	if ranked[2].Breakdown.EnvironmentBoost != 0 {
+		t.Errorf("a memory without an environment should report no boost, got %f", ranked[2].Breakdown.EnvironmentBoost)
+	}
+
+	scorer.EnvironmentBoost = 0
+	if f := scorer.EnvironmentFactor(&memories[2].Memory); f != 1 {
+		t.Errorf("a zero boost should disable the bias, got %f", f)
+	}
+}
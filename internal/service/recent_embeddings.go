@@ -0,0 +1,142 @@
+package service
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	RecentEmbeddingsPerAgent = 128  // Memories remembered per agent
+	RecentEmbeddingAgents    = 1024 // Agents tracked before the least recent is evicted
+)
+
+// recentEmbeddings is an in-process LRU of the memories each agent most
+// recently wrote or reinforced, so a repeated write can find its duplicate
+// without a vector search. Entries are snapshots: callers must re-read a hit
+// from the store before acting on its confidence, and a hit that no longer
+// exists is dropped with forget.
+type recentEmbeddings struct {
+	mu        sync.Mutex
+	perAgent  int
+	maxAgents int
+	agents    map[uuid.UUID]*list.Element // agent ID -> element in order
+	order     *list.List                  // most recently used agent at the front
+	owner     map[uuid.UUID]uuid.UUID     // memory ID -> agent ID
+}
+
+type agentRecentMemories struct {
+	agentID  uuid.UUID
+	memories []domain.Memory // oldest first
+}
+
+func newRecentEmbeddings(perAgent, maxAgents int) *recentEmbeddings {
+	return &recentEmbeddings{
+		perAgent:  perAgent,
+		maxAgents: maxAgents,
+		agents:    make(map[uuid.UUID]*list.Element),
+		order:     list.New(),
+		owner:     make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+// remember records m as the agent's most recent memory, replacing any older
+// snapshot of the same memory.
+func (r *recentEmbeddings) remember(m *domain.Memory) {
+	if len(m.Embedding) == 0 || m.ID == uuid.Nil {
+		return
+	}
+	snap := *m
+	snap.Embedding = append([]float32(nil), m.Embedding...)
+	snap.Metadata = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	el, ok := r.agents[m.AgentID]
+	if !ok {
+		el = r.order.PushFront(&agentRecentMemories{agentID: m.AgentID})
+		r.agents[m.AgentID] = el
+		if r.order.Len() > r.maxAgents {
+			r.evictAgent(r.order.Back())
+		}
+	} else {
+		r.order.MoveToFront(el)
+	}
+
+	a := el.Value.(*agentRecentMemories)
+	a.memories = removeMemoryByID(a.memories, m.ID)
+	a.memories = append(a.memories, snap)
+	r.owner[m.ID] = m.AgentID
+	if len(a.memories) > r.perAgent {
+		delete(r.owner, a.memories[0].ID)
+		a.memories = a.memories[1:]
+	}
+}
+
+// nearest returns the agent's remembered memories of the given types in the
+// tenant whose cosine similarity to embedding is at least threshold, most
+// similar first.
+func (r *recentEmbeddings) nearest(agentID, tenantID uuid.UUID, embedding []float32, types []domain.MemoryType, threshold float32) []domain.MemoryWithScore {
+	r.mu.Lock()
+	el, ok := r.agents[agentID]
+	var candidates []domain.Memory
+	if ok {
+		r.order.MoveToFront(el)
+		candidates = append(candidates, el.Value.(*agentRecentMemories).memories...)
+	}
+	r.mu.Unlock()
+
+	allowed := make(map[domain.MemoryType]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+
+	var out []domain.MemoryWithScore
+	for _, m := range candidates {
+		if m.TenantID != tenantID || (len(allowed) > 0 && !allowed[m.Type]) {
+			continue
+		}
+		if sim := cosineSimilarity(embedding, m.Embedding); sim >= threshold {
+			out = append(out, domain.MemoryWithScore{Memory: m, Score: sim})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// forget drops a memory, e.g. after it was deleted or found missing.
+func (r *recentEmbeddings) forget(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	agentID, ok := r.owner[id]
+	if !ok {
+		return
+	}
+	delete(r.owner, id)
+	if el, ok := r.agents[agentID]; ok {
+		a := el.Value.(*agentRecentMemories)
+		a.memories = removeMemoryByID(a.memories, id)
+	}
+}
+
+func (r *recentEmbeddings) evictAgent(el *list.Element) {
+	a := r.order.Remove(el).(*agentRecentMemories)
+	delete(r.agents, a.agentID)
+	for _, m := range a.memories {
+		delete(r.owner, m.ID)
+	}
+}
+
+func removeMemoryByID(ms []domain.Memory, id uuid.UUID) []domain.Memory {
+	for i := range ms {
+		if ms[i].ID == id {
+			return append(ms[:i], ms[i+1:]...)
+		}
+	}
+	return ms
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// constantEmbeddingClient embeds every text to the same unit vector, so any two
+// writes are near-duplicates.
+type constantEmbeddingClient struct{}
+
+func (constantEmbeddingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	v := make([]float32, 8)
+	v[0] = 1
+	return v, nil
+}
+
+func recentMemory(agentID, tenantID uuid.UUID, t domain.MemoryType, emb ...float32) *domain.Memory {
+	return &domain.Memory{ID: uuid.New(), AgentID: agentID, TenantID: tenantID, Type: t, Embedding: emb}
+}
+
+func TestRecentEmbeddings_NearestFiltersAndRanks(t *testing.T) {
+	r := newRecentEmbeddings(8, 8)
+	agentID, tenantID := uuid.New(), uuid.New()
+
+	near := recentMemory(agentID, tenantID, domain.MemoryTypeFact, 1, 0.1)
+	far := recentMemory(agentID, tenantID, domain.MemoryTypeFact, 0, 1)
+	otherType := recentMemory(agentID, tenantID, domain.MemoryTypePreference, 1, 0)
+	otherTenant := recentMemory(agentID, uuid.New(), domain.MemoryTypeFact, 1, 0)
+	for _, m := range []*domain.Memory{near, far, otherType, otherTenant} {
+		r.remember(m)
+	}
+
+	got := r.nearest(agentID, tenantID, []float32{1, 0}, domain.ComparableMemoryTypes(domain.MemoryTypeFact), 0.5)
+	if len(got) != 1 || got[0].ID != near.ID {
+		t.Fatalf("nearest = %+v, want only the close same-type memory", got)
+	}
+}
+
+func TestRecentEmbeddings_EvictsOldestMemoryAndAgent(t *testing.T) {
+	r := newRecentEmbeddings(2, 2)
+	tenantID := uuid.New()
+	a1, a2, a3 := uuid.New(), uuid.New(), uuid.New()
+
+	first := recentMemory(a1, tenantID, domain.MemoryTypeFact, 1, 0)
+	r.remember(first)
+	r.remember(recentMemory(a1, tenantID, domain.MemoryTypeFact, 1, 0))
+	r.remember(recentMemory(a1, tenantID, domain.MemoryTypeFact, 1, 0))
+	if got := r.nearest(a1, tenantID, []float32{1, 0}, nil, 0.5); len(got) != 2 {
+		t.Fatalf("agent holds %d memories, want cap of 2", len(got))
+	}
+	if _, ok := r.owner[first.ID]; ok {
+		t.Error("evicted memory still indexed")
+	}
+
+	r.remember(recentMemory(a2, tenantID, domain.MemoryTypeFact, 1, 0))
+	r.remember(recentMemory(a3, tenantID, domain.MemoryTypeFact, 1, 0))
+	if got := r.nearest(a1, tenantID, []float32{1, 0}, nil, 0.5); len(got) != 0 {
+		t.Fatalf("least recently used agent not evicted, still has %d memories", len(got))
+	}
+}
+
+func TestRecentEmbeddings_Forget(t *testing.T) {
+	r := newRecentEmbeddings(8, 8)
+	m := recentMemory(uuid.New(), uuid.New(), domain.MemoryTypeFact, 1, 0)
+	r.remember(m)
+	r.forget(m.ID)
+	if got := r.nearest(m.AgentID, m.TenantID, m.Embedding, nil, 0); len(got) != 0 {
+		t.Fatalf("forgotten memory still returned: %+v", got)
+	}
+}
+
+func TestMemoryService_Create_ReinforcesRecentDuplicateWithoutVectorSearch(t *testing.T) {
+	svc, memStore, tenantID, agentID := setupMemoryTest()
+	svc.embeddingClient = constantEmbeddingClient{}
+	ctx := context.Background()
+
+	// The mock store's FindSimilar never returns candidates, so the second
+	// write can only be matched through the recent-embedding cache.
+	first := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode", Type: domain.MemoryTypePreference}
+	if _, err := svc.Create(ctx, first); err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	second := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode", Type: domain.MemoryTypePreference}
+	res, err := svc.Create(ctx, second)
+	if err != nil {
+		t.Fatalf("second create: %v", err)
+	}
+
+	if !res.Reinforced || res.ReinforcedMemoryID != first.ID {
+		t.Fatalf("result = %+v, want reinforcement of %s", res, first.ID)
+	}
+	if len(memStore.memories) != 1 {
+		t.Fatalf("store holds %d memories, want 1", len(memStore.memories))
+	}
+
+	// A deleted memory must not be reinforced from a stale cache entry.
+	if err := svc.Delete(ctx, first.ID, tenantID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	third := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User prefers dark mode", Type: domain.MemoryTypePreference}
+	res, err = svc.Create(ctx, third)
+	if err != nil {
+		t.Fatalf("third create: %v", err)
+	}
+	if res.Reinforced {
+		t.Fatal("write after delete reinforced a deleted memory")
+	}
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Reconsolidation constants
+const (
+	// ReconsolidationMinConfidence is the least confident judgment that
+	// updates a memory, as for implicit feedback.
+	ReconsolidationMinConfidence = 0.6
+	// ReconsolidationRevisionSimilarity is how close a belief drawn from the
+	// contradicting episode must be to a memory to become its new content.
+	ReconsolidationRevisionSimilarity = 0.6
+)
+
+// Reconsolidator updates memories recalled in a conversation that a new
+// episode of the same conversation confirms or contradicts.
+type Reconsolidator interface {
+	Reconsolidate(ctx context.Context, episode *domain.Episode) (*ReconsolidationResult, error)
+}
+
+// ReconsolidationService reopens a memory as soon as a conversation that
+// recalled it confirms or contradicts it, rather than leaving the evidence to
+// the next consolidation run. A confirmed memory is reinforced. A
+// contradicted one loses confidence and, when the episode states what
+// replaced it, takes on the new content with a fresh embedding.
+type ReconsolidationService struct {
+	memoryStore      domain.MemoryStore
+	activations      domain.ConversationActivationStore
+	mutationLogStore domain.MutationLogStore
+	hooks            *Hooks
+	embeddingClient  domain.EmbeddingClient
+	llmClient        domain.LLMClient
+	logger           *zap.Logger
+}
+
+func NewReconsolidationService(
+	ms domain.MemoryStore,
+	activations domain.ConversationActivationStore,
+	ec domain.EmbeddingClient,
+	lc domain.LLMClient,
+	logger *zap.Logger,
+) *ReconsolidationService {
+	return &ReconsolidationService{
+		memoryStore:     ms,
+		activations:     activations,
+		embeddingClient: ec,
+		llmClient:       lc,
+		logger:          logger,
+	}
+}
+
+func (s *ReconsolidationService) SetMutationLogStore(mls domain.MutationLogStore) {
+	s.mutationLogStore = mls
+}
+
+// SetHooks reports reconsolidated memories to in-process callbacks as
+// reinforced or contradicted.
+func (s *ReconsolidationService) SetHooks(h *Hooks) {
+	s.hooks = h
+}
+
+// ReconsolidationResult lists the memories an episode updated. Revised
+// memories are contradicted ones whose content was replaced.
+type ReconsolidationResult struct {
+	Confirmed    []uuid.UUID `json:"confirmed"`
+	Contradicted []uuid.UUID `json:"contradicted"`
+	Revised      []uuid.UUID `json:"revised"`
+}
+
+// Reconsolidate checks the semantic memories activated during the episode's
+// conversation against the episode and updates the ones it confirms or
+// contradicts. Episodes outside a conversation are ignored.
+func (s *ReconsolidationService) Reconsolidate(ctx context.Context, episode *domain.Episode) (*ReconsolidationResult, error) {
+	result := &ReconsolidationResult{Confirmed: []uuid.UUID{}, Contradicted: []uuid.UUID{}, Revised: []uuid.UUID{}}
+	if s.llmClient == nil || s.activations == nil || episode == nil || episode.ConversationID == nil {
+		return result, nil
+	}
+
+	acts, err := s.activations.ListByConversation(ctx, *episode.ConversationID, episode.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	var recalled []domain.Memory
+	for _, act := range acts {
+		if act.MemoryType != domain.ActivatedMemoryTypeSemantic {
+			continue
+		}
+		mem, err := s.memoryStore.GetByID(ctx, act.MemoryID, episode.TenantID)
+		if err != nil {
+			continue
+		}
+		recalled = append(recalled, *mem)
+	}
+	if len(recalled) == 0 {
+		return result, nil
+	}
+
+	judgments, err := s.llmClient.DetectImplicitFeedback(ctx, recalled, []domain.Message{
+		{Role: "user", Content: episode.RawContent},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Memory, len(recalled))
+	for i := range recalled {
+		byID[recalled[i].ID] = &recalled[i]
+	}
+	revisions := &episodeRevisions{svc: s, episode: episode}
+	for _, j := range judgments {
+		mem, ok := byID[j.MemoryID]
+		if !ok || j.Confidence < ReconsolidationMinConfidence {
+			continue
+		}
+		switch j.SignalType {
+		case domain.FeedbackTypeHelpful, domain.FeedbackTypeUsed:
+			if s.reinforce(ctx, episode, mem, j) {
+				result.Confirmed = append(result.Confirmed, mem.ID)
+			}
+		case domain.FeedbackTypeContradicted, domain.FeedbackTypeOutdated:
+			applied, revised := s.revise(ctx, episode, mem, j, revisions)
+			if applied {
+				result.Contradicted = append(result.Contradicted, mem.ID)
+			}
+			if revised {
+				result.Revised = append(result.Revised, mem.ID)
+			}
+		}
+		delete(byID, j.MemoryID) // One judgment per memory
+	}
+
+	if len(result.Confirmed) > 0 || len(result.Contradicted) > 0 {
+		logFor(ctx, s.logger).Info("reconsolidated recalled memories",
+			zap.String("episode_id", episode.ID.String()),
+			zap.Int("confirmed", len(result.Confirmed)),
+			zap.Int("contradicted", len(result.Contradicted)),
+			zap.Int("revised", len(result.Revised)))
+	}
+	return result, nil
+}
+
+// reinforce applies a confirmation.
+func (s *ReconsolidationService) reinforce(ctx context.Context, episode *domain.Episode, mem *domain.Memory, j domain.ImplicitFeedback) bool {
+	effect := domain.FeedbackEffects[domain.FeedbackTypeHelpful]
+	oldConfidence, oldReinforcement := mem.Confidence, mem.ReinforcementCount
+	newConfidence := ApplyLogOddsDelta(mem.Confidence, effect.LogOddsDelta)
+	newReinforcement := mem.ReinforcementCount + effect.ReinforcementDelta
+	if err := s.memoryStore.UpdateReinforcement(ctx, mem.ID, newConfidence, newReinforcement); err != nil {
+		logFor(ctx, s.logger).Warn("failed to reinforce recalled memory", zap.String("memory_id", mem.ID.String()), zap.Error(err))
+		return false
+	}
+	recordEvidence(ctx, s.logger, s.memoryStore, mem.ID, 1, 0)
+
+	s.logMutation(ctx, episode, mem, oldConfidence, newConfidence, oldReinforcement, newReinforcement,
+		"confirmed in conversation: "+j.Evidence, nil)
+	mem.Confidence, mem.ReinforcementCount = newConfidence, newReinforcement
+	s.hooks.memoryReinforced(ctx, memoryEvent(mem, "reconsolidation"))
+	return true
+}
+
+// revise applies a contradiction, replacing the memory's content when the
+// episode states what replaced it. A memory left unrevised is flagged for
+// review.
+func (s *ReconsolidationService) revise(ctx context.Context, episode *domain.Episode, mem *domain.Memory, j domain.ImplicitFeedback, revisions *episodeRevisions) (applied, revised bool) {
+	effect := domain.FeedbackEffects[j.SignalType]
+	oldConfidence, oldReinforcement, oldContent := mem.Confidence, mem.ReinforcementCount, mem.Content
+	newConfidence := ApplyLogOddsDelta(mem.Confidence, effect.LogOddsDelta)
+	newReinforcement := max(mem.ReinforcementCount+effect.ReinforcementDelta, 0)
+
+	var metadata map[string]any
+	if rev := revisions.replacementFor(ctx, mem); rev != nil {
+		if err := s.memoryStore.UpdateContent(ctx, mem.ID, rev.content, rev.embedding); err != nil {
+			logFor(ctx, s.logger).Warn("failed to revise recalled memory", zap.String("memory_id", mem.ID.String()), zap.Error(err))
+		} else {
+			// The revised belief rests on the episode, not on the evidence
+			// that built up the old one.
+			newConfidence = rev.confidence
+			newReinforcement = 0
+			mem.Content, mem.Embedding = rev.content, rev.embedding
+			metadata = map[string]any{
+				"old_content_hash": domain.HashContent(oldContent),
+				"new_content_hash": domain.HashContent(rev.content),
+			}
+			revised = true
+		}
+	}
+
+	if err := s.memoryStore.UpdateReinforcement(ctx, mem.ID, newConfidence, newReinforcement); err != nil {
+		logFor(ctx, s.logger).Warn("failed to weaken recalled memory", zap.String("memory_id", mem.ID.String()), zap.Error(err))
+		return false, revised
+	}
+	if !revised {
+		recordEvidence(ctx, s.logger, s.memoryStore, mem.ID, 0, 1)
+		if err := s.memoryStore.SetNeedsReview(ctx, mem.ID, true); err != nil {
+			logFor(ctx, s.logger).Warn("failed to set needs_review flag", zap.Error(err))
+		}
+	}
+
+	s.logMutation(ctx, episode, mem, oldConfidence, newConfidence, oldReinforcement, newReinforcement,
+		string(j.SignalType)+" in conversation: "+j.Evidence, metadata)
+	mem.Confidence, mem.ReinforcementCount = newConfidence, newReinforcement
+	s.hooks.memoryContradicted(ctx, memoryEvent(mem, "reconsolidation"))
+	return true, revised
+}
+
+func (s *ReconsolidationService) logMutation(ctx context.Context, episode *domain.Episode, mem *domain.Memory, oldConfidence, newConfidence float32, oldReinforcement, newReinforcement int, reason string, metadata map[string]any) {
+	if s.mutationLogStore == nil {
+		return
+	}
+	episodeID := episode.ID
+	if err := s.mutationLogStore.Create(ctx, &domain.MutationLog{
+		MemoryID:              mem.ID,
+		AgentID:               mem.AgentID,
+		TenantID:              &mem.TenantID,
+		MutationType:          domain.MutationReconsolidation,
+		SourceType:            domain.MutationSourceImplicit,
+		SourceID:              &episodeID,
+		OldConfidence:         &oldConfidence,
+		NewConfidence:         &newConfidence,
+		OldReinforcementCount: &oldReinforcement,
+		NewReinforcementCount: &newReinforcement,
+		Reason:                reason,
+		Metadata:              metadata,
+	}); err != nil {
+		logFor(ctx, s.logger).Warn("failed to log mutation", zap.Error(err))
+	}
+}
+
+// episodeRevisions extracts the beliefs an episode states, once, and matches
+// contradicted memories to the one that replaces them.
+type episodeRevisions struct {
+	svc        *ReconsolidationService
+	episode    *domain.Episode
+	loaded     bool
+	beliefs    []domain.ExtractedMemory
+	embeddings [][]float32
+}
+
+// beliefRevision is the new content of a contradicted memory.
+type beliefRevision struct {
+	content    string
+	embedding  []float32
+	confidence float32
+}
+
+// replacementFor returns the episode's belief closest to mem, or nil when
+// none is close enough to be about the same thing.
+func (r *episodeRevisions) replacementFor(ctx context.Context, mem *domain.Memory) *beliefRevision {
+	if r.svc.embeddingClient == nil || len(mem.Embedding) == 0 {
+		return nil
+	}
+	if !r.loaded {
+		r.loaded = true
+		beliefs, err := r.svc.llmClient.Extract(ctx, []domain.Message{{Role: "user", Content: r.episode.RawContent}})
+		if err != nil {
+			logFor(ctx, r.svc.logger).Debug("failed to extract beliefs for reconsolidation", zap.Error(err))
+			return nil
+		}
+		for _, b := range beliefs {
+			emb, err := r.svc.embeddingClient.Embed(ctx, b.Content)
+			if err != nil {
+				continue
+			}
+			r.beliefs = append(r.beliefs, b)
+			r.embeddings = append(r.embeddings, emb)
+		}
+	}
+
+	best, bestScore := -1, float32(ReconsolidationRevisionSimilarity)
+	for i, emb := range r.embeddings {
+		if score := cosineSimilarity(mem.Embedding, emb); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 || strings.EqualFold(strings.TrimSpace(r.beliefs[best].Content), strings.TrimSpace(mem.Content)) {
+		return nil
+	}
+	b := r.beliefs[best]
+	rev := &beliefRevision{content: b.Content, embedding: r.embeddings[best], confidence: b.Confidence * ExtractionConfidenceDiscount}
+	if b.EvidenceType != "" {
+		rev.confidence = b.EvidenceType.InitialConfidence() * ExtractionConfidenceDiscount
+	}
+	return rev
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// judgingLLMClient returns scripted implicit feedback judgments.
+type judgingLLMClient struct {
+	*mockLLMClient
+	judgments []domain.ImplicitFeedback
+}
+
+func (c *judgingLLMClient) DetectImplicitFeedback(ctx context.Context, memories []domain.Memory, conversation []domain.Message) ([]domain.ImplicitFeedback, error) {
+	return c.judgments, nil
+}
+
+// vectorEmbeddingClient embeds known texts to fixed vectors.
+type vectorEmbeddingClient map[string][]float32
+
+func (c vectorEmbeddingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if v, ok := c[text]; ok {
+		return v, nil
+	}
+	return []float32{0, 0, 1}, nil
+}
+
+type reconsolidationFixture struct {
+	memories  *mockMemoryStore
+	mutations *mockMutationLogStore
+	llm       *judgingLLMClient
+	svc       *ReconsolidationService
+	memory    *domain.Memory
+	episode   *domain.Episode
+}
+
+func setupReconsolidationFixture(t *testing.T) *reconsolidationFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &reconsolidationFixture{
+		memories:  newMockMemoryStore(),
+		mutations: &mockMutationLogStore{},
+		llm:       &judgingLLMClient{mockLLMClient: newMockLLMClient()},
+	}
+	tenantID, agentID, convID := uuid.New(), uuid.New(), uuid.New()
+
+	f.memory = &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "User lives in Berlin",
+		Type: domain.MemoryTypeFact, Confidence: 0.7, ReinforcementCount: 3, Embedding: []float32{1, 0, 0}}
+	_ = f.memories.Create(ctx, f.memory)
+	f.episode = &domain.Episode{ID: uuid.New(), AgentID: agentID, TenantID: tenantID,
+		RawContent: "I moved to Lisbon last month", ConversationID: &convID}
+
+	acts := newMockConversationActivationStore()
+	_ = acts.Record(ctx, []domain.ConversationActivation{
+		{TenantID: tenantID, AgentID: agentID, ConversationID: convID, MemoryType: domain.ActivatedMemoryTypeSemantic, MemoryID: f.memory.ID, ActivationLevel: 0.8},
+	})
+
+	embedder := vectorEmbeddingClient{"User lives in Lisbon": {0.9, 0.1, 0}}
+	f.svc = NewReconsolidationService(f.memories, acts, embedder, f.llm, testLogger())
+	f.svc.SetMutationLogStore(f.mutations)
+	return f
+}
+
+func TestReconsolidation_ConfirmedMemoryIsReinforced(t *testing.T) {
+	f := setupReconsolidationFixture(t)
+	f.llm.judgments = []domain.ImplicitFeedback{
+		{MemoryID: f.memory.ID, SignalType: domain.FeedbackTypeHelpful, Confidence: 0.9, Evidence: "still in Berlin"},
+	}
+
+	result, err := f.svc.Reconsolidate(context.Background(), f.episode)
+	if err != nil {
+		t.Fatalf("Reconsolidate failed: %v", err)
+	}
+	if len(result.Confirmed) != 1 || len(result.Contradicted) != 0 {
+		t.Fatalf("expected one confirmation, got %+v", result)
+	}
+	want := ApplyLogOddsDelta(0.7, domain.FeedbackEffects[domain.FeedbackTypeHelpful].LogOddsDelta)
+	if !approxEqual(f.memory.Confidence, want, 0.001) || f.memory.ReinforcementCount != 4 {
+		t.Errorf("got confidence %v and reinforcement %d, want ~%v and 4", f.memory.Confidence, f.memory.ReinforcementCount, want)
+	}
+	if len(f.mutations.logs) != 1 || f.mutations.logs[0].MutationType != domain.MutationReconsolidation ||
+		*f.mutations.logs[0].SourceID != f.episode.ID {
+		t.Errorf("expected a reconsolidation mutation sourced from the episode, got %+v", f.mutations.logs)
+	}
+}
+
+func TestReconsolidation_ContradictedMemoryIsRevised(t *testing.T) {
+	f := setupReconsolidationFixture(t)
+	f.llm.judgments = []domain.ImplicitFeedback{
+		{MemoryID: f.memory.ID, SignalType: domain.FeedbackTypeOutdated, Confidence: 0.9, Evidence: "moved to Lisbon"},
+	}
+	f.llm.extractResult = []domain.ExtractedMemory{
+		{Type: domain.MemoryTypeFact, Content: "User lives in Lisbon", Confidence: 0.9},
+	}
+
+	result, err := f.svc.Reconsolidate(context.Background(), f.episode)
+	if err != nil {
+		t.Fatalf("Reconsolidate failed: %v", err)
+	}
+	if len(result.Contradicted) != 1 || len(result.Revised) != 1 {
+		t.Fatalf("expected one revision, got %+v", result)
+	}
+	if f.memory.Content != "User lives in Lisbon" || f.memory.Embedding[0] != 0.9 {
+		t.Errorf("expected revised content and embedding, got %q %v", f.memory.Content, f.memory.Embedding)
+	}
+	if !approxEqual(f.memory.Confidence, 0.9*ExtractionConfidenceDiscount, 0.001) || f.memory.ReinforcementCount != 0 {
+		t.Errorf("revised belief should start over from the episode, got confidence %v and reinforcement %d",
+			f.memory.Confidence, f.memory.ReinforcementCount)
+	}
+	if meta := f.mutations.logs[0].Metadata; meta["new_content_hash"] != domain.HashContent("User lives in Lisbon") {
+		t.Errorf("expected the new content hash in the mutation, got %v", meta)
+	}
+}
+
+func TestReconsolidation_ContradictionWithoutReplacementOnlyWeakens(t *testing.T) {
+	f := setupReconsolidationFixture(t)
+	f.llm.judgments = []domain.ImplicitFeedback{
+		{MemoryID: f.memory.ID, SignalType: domain.FeedbackTypeContradicted, Confidence: 0.8, Evidence: "not in Berlin"},
+	}
+	f.llm.extractResult = []domain.ExtractedMemory{
+		{Type: domain.MemoryTypePreference, Content: "User likes tea", Confidence: 0.9},
+	}
+
+	result, err := f.svc.Reconsolidate(context.Background(), f.episode)
+	if err != nil {
+		t.Fatalf("Reconsolidate failed: %v", err)
+	}
+	if len(result.Contradicted) != 1 || len(result.Revised) != 0 {
+		t.Fatalf("expected a contradiction without revision, got %+v", result)
+	}
+	if f.memory.Content != "User lives in Berlin" || f.memory.Confidence >= 0.7 {
+		t.Errorf("expected the same content at lower confidence, got %q at %v", f.memory.Content, f.memory.Confidence)
+	}
+}
+
+func TestReconsolidation_IgnoresWeakJudgmentsAndEpisodesOutsideConversations(t *testing.T) {
+	f := setupReconsolidationFixture(t)
+	f.llm.judgments = []domain.ImplicitFeedback{
+		{MemoryID: f.memory.ID, SignalType: domain.FeedbackTypeContradicted, Confidence: 0.4},
+	}
+
+	result, err := f.svc.Reconsolidate(context.Background(), f.episode)
+	if err != nil {
+		t.Fatalf("Reconsolidate failed: %v", err)
+	}
+	if len(result.Contradicted) != 0 || f.memory.Confidence != 0.7 {
+		t.Errorf("a weak judgment must not change the memory, got %+v at %v", result, f.memory.Confidence)
+	}
+
+	f.llm.judgments[0].Confidence = 0.9
+	f.episode.ConversationID = nil
+	if result, _ := f.svc.Reconsolidate(context.Background(), f.episode); len(result.Contradicted) != 0 {
+		t.Errorf("an episode outside a conversation must not reconsolidate, got %+v", result)
+	}
+}
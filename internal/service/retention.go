@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+)
+
+var ErrRetentionRuleNotFound = errors.New("retention rule not found")
+
+// RetentionPreviewSample is how many IDs a preview lists per rule.
+const RetentionPreviewSample = 20
+
+// InvalidRetentionRuleError wraps a rejected rule so handlers can surface the
+// reason.
+type InvalidRetentionRuleError struct{ Err error }
+
+func (e *InvalidRetentionRuleError) Error() string { return e.Err.Error() }
+func (e *InvalidRetentionRuleError) Unwrap() error { return e.Err }
+
+// RetentionService manages per-tenant retention rules. The rules themselves
+// are enforced by the ExpirerService.
+type RetentionService struct {
+	store domain.RetentionStore
+}
+
+func NewRetentionService(rs domain.RetentionStore) *RetentionService {
+	return &RetentionService{store: rs}
+}
+
+// Upsert validates and saves the rule, replacing the tenant's rule for the
+// same target and memory type if there is one.
+func (s *RetentionService) Upsert(ctx context.Context, r *domain.RetentionRule) error {
+	if err := r.Validate(); err != nil {
+		return &InvalidRetentionRuleError{Err: err}
+	}
+	return s.store.Upsert(ctx, r)
+}
+
+func (s *RetentionService) List(ctx context.Context, tenantID uuid.UUID) ([]domain.RetentionRule, error) {
+	return s.store.List(ctx, tenantID)
+}
+
+func (s *RetentionService) Delete(ctx context.Context, id, tenantID uuid.UUID) error {
+	if err := s.store.Delete(ctx, id, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrRetentionRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Preview reports what each of the tenant's rules would delete if the expirer
+// ran now. Nothing is deleted.
+func (s *RetentionService) Preview(ctx context.Context, tenantID uuid.UUID) ([]domain.RetentionPreview, error) {
+	rules, err := s.store.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	previews := make([]domain.RetentionPreview, 0, len(rules))
+	for i := range rules {
+		p, err := s.store.Preview(ctx, &rules[i], RetentionPreviewSample)
+		if err != nil {
+			return nil, err
+		}
+		previews = append(previews, *p)
+	}
+	return previews, nil
+}
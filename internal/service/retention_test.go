@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+)
+
+type mockRetentionStore struct {
+	rules    []domain.RetentionRule
+	applyErr map[uuid.UUID]error
+	applied  []uuid.UUID
+}
+
+func (m *mockRetentionStore) Upsert(ctx context.Context, r *domain.RetentionRule) error {
+	r.ID = uuid.New()
+	m.rules = append(m.rules, *r)
+	return nil
+}
+
+func (m *mockRetentionStore) List(ctx context.Context, tenantID uuid.UUID) ([]domain.RetentionRule, error) {
+	var out []domain.RetentionRule
+	for _, r := range m.rules {
+		if r.TenantID == tenantID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRetentionStore) ListAll(ctx context.Context) ([]domain.RetentionRule, error) {
+	return m.rules, nil
+}
+
+func (m *mockRetentionStore) Delete(ctx context.Context, id, tenantID uuid.UUID) error {
+	for i, r := range m.rules {
+		if r.ID == id && r.TenantID == tenantID {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (m *mockRetentionStore) Preview(ctx context.Context, r *domain.RetentionRule, sample int) (*domain.RetentionPreview, error) {
+	return &domain.RetentionPreview{Rule: *r, Count: int64(r.AfterDays)}, nil
+}
+
+func (m *mockRetentionStore) Apply(ctx context.Context, r *domain.RetentionRule) (int64, error) {
+	if err := m.applyErr[r.ID]; err != nil {
+		return 0, err
+	}
+	m.applied = append(m.applied, r.ID)
+	return 1, nil
+}
+
+func TestRetentionService_UpsertValidates(t *testing.T) {
+	tenantID := uuid.New()
+	fact := domain.MemoryTypeFact
+
+	cases := []struct {
+		name  string
+		rule  domain.RetentionRule
+		valid bool
+	}{
+		{"archived memories for a year", domain.RetentionRule{Target: domain.RetentionArchivedMemories, AfterDays: 365}, true},
+		{"typed memories", domain.RetentionRule{Target: domain.RetentionMemories, MemoryType: &fact, AfterDays: 30}, true},
+		{"abstracted episodes", domain.RetentionRule{Target: domain.RetentionAbstractedEpisodes, AfterDays: 90}, true},
+		{"unknown target", domain.RetentionRule{Target: "procedures", AfterDays: 30}, false},
+		{"zero days", domain.RetentionRule{Target: domain.RetentionMemories, AfterDays: 0}, false},
+		{"type on episodes", domain.RetentionRule{Target: domain.RetentionAbstractedEpisodes, MemoryType: &fact, AfterDays: 30}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewRetentionService(&mockRetentionStore{})
+			rule := tc.rule
+			rule.TenantID = tenantID
+			err := svc.Upsert(context.Background(), &rule)
+			var invalid *InvalidRetentionRuleError
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.valid && !errors.As(err, &invalid) {
+				t.Fatalf("err = %v, want InvalidRetentionRuleError", err)
+			}
+		})
+	}
+}
+
+func TestRetentionService_DeleteNotFound(t *testing.T) {
+	svc := NewRetentionService(&mockRetentionStore{})
+	if err := svc.Delete(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, ErrRetentionRuleNotFound) {
+		t.Fatalf("err = %v, want ErrRetentionRuleNotFound", err)
+	}
+}
+
+func TestRetentionService_PreviewIsTenantScoped(t *testing.T) {
+	tenantID := uuid.New()
+	rs := &mockRetentionStore{rules: []domain.RetentionRule{
+		{ID: uuid.New(), TenantID: tenantID, Target: domain.RetentionMemories, AfterDays: 3},
+		{ID: uuid.New(), TenantID: tenantID, Target: domain.RetentionArchivedMemories, AfterDays: 4},
+		{ID: uuid.New(), TenantID: uuid.New(), Target: domain.RetentionMemories, AfterDays: 5},
+	}}
+	previews, err := NewRetentionService(rs).Preview(context.Background(), tenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(previews) != 2 {
+		t.Fatalf("got %d previews, want 2", len(previews))
+	}
+	for _, p := range previews {
+		if p.Rule.TenantID != tenantID {
+			t.Errorf("preview for another tenant's rule %s", p.Rule.ID)
+		}
+	}
+}
+
+func TestExpirer_ApplyRetentionRulesContinuesPastFailure(t *testing.T) {
+	failing, ok := uuid.New(), uuid.New()
+	rs := &mockRetentionStore{
+		rules: []domain.RetentionRule{
+			{ID: failing, TenantID: uuid.New(), Target: domain.RetentionMemories, AfterDays: 30},
+			{ID: ok, TenantID: uuid.New(), Target: domain.RetentionAbstractedEpisodes, AfterDays: 90},
+		},
+		applyErr: map[uuid.UUID]error{failing: errors.New("boom")},
+	}
+	svc := NewExpirerService(nil, nil, nil, testLogger())
+	svc.SetRetentionStore(rs)
+	svc.applyRetentionRules(context.Background())
+
+	if len(rs.applied) != 1 || rs.applied[0] != ok {
+		t.Fatalf("applied = %v, want only %s", rs.applied, ok)
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrScheduledTaskNotFound  = errors.New("scheduled task not found")
+	ErrScheduledTaskDuplicate = errors.New("scheduled task already registered")
+)
+
+// ScheduledTask is a periodic background worker. Run is called every Interval
+// under a context bounded by Timeout; an error or panic counts as a failed run.
+type ScheduledTask struct {
+	Name     string
+	Interval time.Duration
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// ScheduledTaskStatus is a task's schedule and the outcome of its last run.
+// Each run gets its own correlation ID, tagged on everything it logs.
+type ScheduledTaskStatus struct {
+	Name              string     `json:"name"`
+	Interval          string     `json:"interval"`
+	Paused            bool       `json:"paused"`
+	Running           bool       `json:"running"`
+	RunCount          int64      `json:"run_count"`
+	ErrorCount        int64      `json:"error_count"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	LastDuration      string     `json:"last_duration,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	LastCorrelationID string     `json:"last_correlation_id,omitempty"`
+	NextRunAt         *time.Time `json:"next_run_at,omitempty"`
+}
+
+type scheduledTask struct {
+	ScheduledTask
+	defaultInterval time.Duration
+	ticker          *time.Ticker
+	status          ScheduledTaskStatus
+}
+
+// Scheduler runs registered periodic tasks, each on its own ticker, and keeps
+// their last-run status for the admin API. A paused task keeps ticking but
+// skips its runs until resumed; pausing never interrupts a run in progress.
+type Scheduler struct {
+	logger *zap.Logger
+	now    func() time.Time
+	fanout DatabaseFanout
+
+	mu      sync.Mutex
+	tasks   map[string]*scheduledTask
+	order   []string
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(logger *zap.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger: logger,
+		now:    time.Now,
+		tasks:  make(map[string]*scheduledTask),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// SetFanout runs each task once per tenant database instead of once against
+// the default one. A run fails if any database's run fails.
+func (s *Scheduler) SetFanout(f DatabaseFanout) {
+	s.fanout = f
+}
+
+// Register adds a task. Tasks registered after Start begin immediately.
+func (s *Scheduler) Register(task ScheduledTask) error {
+	if task.Name == "" || task.Interval <= 0 || task.Run == nil {
+		return fmt.Errorf("invalid scheduled task %q", task.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[task.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrScheduledTaskDuplicate, task.Name)
+	}
+	t := &scheduledTask{
+		ScheduledTask:   task,
+		defaultInterval: task.Interval,
+		status:          ScheduledTaskStatus{Name: task.Name, Interval: task.Interval.String()},
+	}
+	s.tasks[task.Name] = t
+	s.order = append(s.order, task.Name)
+	if s.started {
+		s.launchLocked(t)
+	}
+	return nil
+}
+
+// Start launches every registered task.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, name := range s.order {
+		s.launchLocked(s.tasks[name])
+	}
+}
+
+// Stop cancels in-flight runs and waits for every task to exit.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) launchLocked(t *scheduledTask) {
+	next := s.now().Add(t.Interval)
+	t.status.NextRunAt = &next
+	t.ticker = time.NewTicker(t.Interval)
+	ticker, interval := t.ticker, t.Interval
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer ticker.Stop()
+
+		s.logger.Info("scheduled task started", zap.String("task", t.Name), zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-ticker.C:
+				s.tick(t)
+			case <-s.ctx.Done():
+				s.logger.Info("scheduled task stopped", zap.String("task", t.Name))
+				return
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) tick(t *scheduledTask) {
+	s.mu.Lock()
+	start := s.now()
+	next := start.Add(t.Interval)
+	run := t.Run
+	t.status.NextRunAt = &next
+	if t.status.Paused {
+		s.mu.Unlock()
+		return
+	}
+	t.status.Running = true
+	correlationID := t.Name + "-" + uuid.NewString()
+	t.status.LastCorrelationID = correlationID
+	s.mu.Unlock()
+
+	ctx, cancel := s.ctx, context.CancelFunc(func() {})
+	if t.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(s.ctx, t.Timeout)
+	}
+	ctx = domain.WithCorrelationID(ctx, correlationID)
+	logger := logFor(ctx, s.logger)
+	err := errors.New("panicked")
+	guardPanic(logger, t.Name+" tick", func() { err = s.runEach(ctx, run) })
+	cancel()
+	if err != nil {
+		logger.Error("scheduled task failed", zap.String("task", t.Name), zap.Error(err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.status.Running = false
+	t.status.RunCount++
+	t.status.LastRunAt = &start
+	t.status.LastDuration = s.now().Sub(start).String()
+	t.status.LastError = ""
+	if err != nil {
+		t.status.ErrorCount++
+		t.status.LastError = err.Error()
+	}
+}
+
+func (s *Scheduler) runEach(ctx context.Context, run func(ctx context.Context) error) error {
+	var errs []error
+	s.fanout.each(ctx, func(ctx context.Context) {
+		if err := run(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// List returns every task's status in registration order.
+func (s *Scheduler) List() []ScheduledTaskStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ScheduledTaskStatus, 0, len(s.order))
+	for _, name := range s.order {
+		out = append(out, s.tasks[name].status)
+	}
+	return out
+}
+
+// SetInterval changes how often a task runs, restarting its ticker if it is
+// running. A non-positive interval restores the one it was registered with.
+func (s *Scheduler) SetInterval(name string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[name]
+	if !ok {
+		return ErrScheduledTaskNotFound
+	}
+	if d <= 0 {
+		d = t.defaultInterval
+	}
+	if d == t.Interval {
+		return nil
+	}
+	t.Interval = d
+	t.status.Interval = d.String()
+	if t.ticker != nil {
+		t.ticker.Reset(d)
+		next := s.now().Add(d)
+		t.status.NextRunAt = &next
+	}
+	s.logger.Info("scheduled task interval changed", zap.String("task", name), zap.Duration("interval", d))
+	return nil
+}
+
+// Pause stops a task's future runs until Resume.
+func (s *Scheduler) Pause(name string) (*ScheduledTaskStatus, error) {
+	return s.setPaused(name, true)
+}
+
+// Resume re-enables a paused task from its next tick.
+func (s *Scheduler) Resume(name string) (*ScheduledTaskStatus, error) {
+	return s.setPaused(name, false)
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) (*ScheduledTaskStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[name]
+	if !ok {
+		return nil, ErrScheduledTaskNotFound
+	}
+	t.status.Paused = paused
+	st := t.status
+	return &st, nil
+}
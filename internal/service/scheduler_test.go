@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_RecordsRunsAndErrors(t *testing.T) {
+	s := NewScheduler(testLogger())
+	var calls atomic.Int32
+	_ = s.Register(ScheduledTask{Name: "ok", Interval: 5 * time.Millisecond, Run: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+	_ = s.Register(ScheduledTask{Name: "failing", Interval: 5 * time.Millisecond, Run: func(context.Context) error {
+		return errors.New("db down")
+	}})
+	_ = s.Register(ScheduledTask{Name: "panicking", Interval: 5 * time.Millisecond, Run: func(context.Context) error {
+		panic("boom")
+	}})
+
+	s.Start()
+	time.Sleep(30 * time.Millisecond)
+	s.Stop()
+
+	statuses := s.List()
+	if len(statuses) != 3 || statuses[0].Name != "ok" {
+		t.Fatalf("expected tasks in registration order, got %+v", statuses)
+	}
+	ok, failing, panicking := statuses[0], statuses[1], statuses[2]
+	if ok.RunCount == 0 || int32(ok.RunCount) != calls.Load() || ok.ErrorCount != 0 || ok.LastRunAt == nil {
+		t.Errorf("ok task status = %+v", ok)
+	}
+	if failing.ErrorCount == 0 || failing.ErrorCount != failing.RunCount || failing.LastError != "db down" {
+		t.Errorf("failing task status = %+v", failing)
+	}
+	if panicking.ErrorCount == 0 || panicking.LastError == "" {
+		t.Errorf("panicking task status = %+v", panicking)
+	}
+}
+
+func TestScheduler_PauseSkipsRuns(t *testing.T) {
+	s := NewScheduler(testLogger())
+	var calls atomic.Int32
+	_ = s.Register(ScheduledTask{Name: "decay", Interval: 5 * time.Millisecond, Run: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+
+	st, err := s.Pause("decay")
+	if err != nil || !st.Paused {
+		t.Fatalf("Pause = %+v, %v", st, err)
+	}
+	s.Start()
+	time.Sleep(25 * time.Millisecond)
+	if calls.Load() != 0 {
+		t.Errorf("paused task ran %d times", calls.Load())
+	}
+
+	if _, err := s.Resume("decay"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	time.Sleep(25 * time.Millisecond)
+	s.Stop()
+	if calls.Load() == 0 {
+		t.Error("resumed task never ran")
+	}
+}
+
+func TestScheduler_RegisterValidation(t *testing.T) {
+	s := NewScheduler(testLogger())
+	noop := func(context.Context) error { return nil }
+
+	if err := s.Register(ScheduledTask{Name: "tuner", Interval: time.Hour, Run: noop}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := s.Register(ScheduledTask{Name: "tuner", Interval: time.Hour, Run: noop}); !errors.Is(err, ErrScheduledTaskDuplicate) {
+		t.Errorf("duplicate: err = %v, want ErrScheduledTaskDuplicate", err)
+	}
+	if err := s.Register(ScheduledTask{Name: "zero", Run: noop}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+	if _, err := s.Pause("missing"); !errors.Is(err, ErrScheduledTaskNotFound) {
+		t.Errorf("Pause missing: err = %v, want ErrScheduledTaskNotFound", err)
+	}
+}
+
+func TestScheduler_SetInterval(t *testing.T) {
+	s := NewScheduler(testLogger())
+	var calls atomic.Int32
+	_ = s.Register(ScheduledTask{Name: "tuner", Interval: time.Hour, Run: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+	s.Start()
+	defer s.Stop()
+
+	if err := s.SetInterval("tuner", 5*time.Millisecond); err != nil {
+		t.Fatalf("SetInterval: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() == 0 {
+		t.Error("expected task to run on the shortened interval")
+	}
+	if got := s.List()[0].Interval; got != "5ms" {
+		t.Errorf("Interval = %q, want 5ms", got)
+	}
+
+	if err := s.SetInterval("tuner", 0); err != nil {
+		t.Fatalf("SetInterval reset: %v", err)
+	}
+	if got := s.List()[0].Interval; got != "1h0m0s" {
+		t.Errorf("Interval after reset = %q, want registered 1h0m0s", got)
+	}
+	if err := s.SetInterval("missing", time.Second); !errors.Is(err, ErrScheduledTaskNotFound) {
+		t.Errorf("SetInterval(missing) = %v, want ErrScheduledTaskNotFound", err)
+	}
+}
+
+func TestScheduler_FanoutRunsOncePerDatabase(t *testing.T) {
+	type dbKey struct{}
+	s := NewScheduler(testLogger())
+	s.SetFanout(func(ctx context.Context, fn func(context.Context)) {
+		for _, db := range []string{"default", "eu"} {
+			fn(context.WithValue(ctx, dbKey{}, db))
+		}
+	})
+
+	var defaultRuns, euRuns atomic.Int32
+	_ = s.Register(ScheduledTask{Name: "decay", Interval: time.Hour, Run: func(ctx context.Context) error {
+		if ctx.Value(dbKey{}) == "eu" {
+			euRuns.Add(1)
+			return errors.New("eu down")
+		}
+		defaultRuns.Add(1)
+		return nil
+	}})
+	s.tick(s.tasks["decay"])
+
+	if defaultRuns.Load() != 1 || euRuns.Load() != 1 {
+		t.Fatalf("expected one run per database, got default=%d eu=%d", defaultRuns.Load(), euRuns.Load())
+	}
+	if st := s.List()[0]; st.ErrorCount != 1 || st.LastError != "eu down" {
+		t.Fatalf("expected the eu failure to fail the run, got %+v", st)
+	}
+}
@@ -16,23 +16,31 @@ import (
 
 // Schema service constants
 const (
-	MinClusterSize            = 5              // Minimum memories to form a schema
-	MinEvidenceConfidence     = 0.6            // Minimum confidence for memories to count as evidence
-	MinEvidenceAge            = 24 * time.Hour // Minimum stability period
-	SchemaSimilarityThreshold = 0.7            // For finding similar schemas
-	SchemaConfidenceBoost     = 0.05           // Confidence boost per evidence
-	MaxSchemaConfidence       = 0.95           // Maximum schema confidence
-	MinSchemaMatchScore       = 0.3            // Minimum score to consider a schema match
-	ContextMatchWeight        = 0.3            // Weight for context matching
-	TimeMatchWeight           = 0.2            // Weight for time-based matching
-	EmbeddingSimilarityWeight = 0.5            // Weight for embedding similarity
-	ClusteringThreshold       = 0.65           // Cosine similarity threshold for clustering
+	MinClusterSize                 = 5              // Minimum memories to form a schema
+	MinEvidenceConfidence          = 0.6            // Minimum confidence for memories to count as evidence
+	MinEvidenceAge                 = 24 * time.Hour // Minimum stability period
+	SchemaSimilarityThreshold      = 0.7            // For finding similar schemas
+	SchemaConfidenceBoost          = 0.05           // Confidence boost per evidence
+	MaxSchemaConfidence            = 0.95           // Maximum schema confidence
+	MinSchemaMatchScore            = 0.3            // Minimum score to consider a schema match
+	ContextMatchWeight             = 0.3            // Weight for context matching
+	TimeMatchWeight                = 0.2            // Weight for time-based matching
+	EmbeddingSimilarityWeight      = 0.5            // Weight for embedding similarity
+	SchemaMatchSimilarityThreshold = 0.5            // Less similar schemas get no semantic credit when matching
+	ClusteringThreshold            = 0.65           // Cosine similarity threshold for clustering
+	SchemaParentActivation         = 0.5            // Fraction of a child's match score passed to each ancestor level
 )
 
 var (
 	ErrSchemaNotFound       = errors.New("schema not found")
 	ErrInvalidSchemaType    = errors.New("invalid schema type")
 	ErrInsufficientEvidence = errors.New("insufficient evidence to form schema")
+	ErrSchemaNameRequired   = errors.New("schema name is required")
+	ErrSchemaExists         = errors.New("schema with this type and name already exists")
+	ErrInvalidConfidence    = errors.New("confidence must be between 0 and 1")
+	ErrEvidenceNotFound     = errors.New("evidence not found")
+	ErrParentSchemaNotFound = errors.New("parent schema not found")
+	ErrSchemaCycle          = errors.New("schema hierarchy would contain a cycle")
 )
 
 // SchemaService handles schema detection, matching, and management.
@@ -40,8 +48,11 @@ type SchemaService struct {
 	schemaStore     domain.SchemaStore
 	memoryStore     domain.MemoryStore
 	agentStore      domain.AgentStore
+	episodeStore    domain.EpisodeStore
 	embeddingClient domain.EmbeddingClient
 	llmClient       domain.LLMClient
+	clusterer       Clusterer
+	scanner         domain.MemoryScanner
 	logger          *zap.Logger
 }
 
@@ -60,10 +71,27 @@ func NewSchemaService(
 		agentStore:      agentStore,
 		embeddingClient: embeddingClient,
 		llmClient:       llmClient,
+		clusterer:       NewAgglomerativeClusterer(ClusteringThreshold),
 		logger:          logger,
 	}
 }
 
+// SetClusterer overrides the clustering algorithm used for schema detection.
+func (s *SchemaService) SetClusterer(c Clusterer) {
+	s.clusterer = c
+}
+
+// SetMemoryScanner streams memories during detection instead of loading every
+// memory of the agent.
+func (s *SchemaService) SetMemoryScanner(ms domain.MemoryScanner) {
+	s.scanner = ms
+}
+
+// SetEpisodeStore enables verification of episode evidence attached to schemas.
+func (s *SchemaService) SetEpisodeStore(es domain.EpisodeStore) {
+	s.episodeStore = es
+}
+
 // SchemaMatchInput contains input for schema matching.
 type SchemaMatchInput struct {
 	AgentID       uuid.UUID
@@ -71,116 +99,44 @@ type SchemaMatchInput struct {
 	Query         string   // Current query/situation to match against
 	Contexts      []string // Current context tags (e.g., "debugging", "late_night")
 	TimeOfDay     string   // "morning", "afternoon", "evening", "night"
+	DayOfWeek     string   // "Monday" ... "Sunday"
 	MinMatchScore float32  // Minimum match score (defaults to MinSchemaMatchScore)
 	Limit         int      // Maximum results (defaults to 5)
 }
 
 // DetectSchemas identifies patterns across semantic memories and creates schemas.
+// Runs incrementally: memories that became eligible since the last pass are
+// attached to the nearest existing schema or clustered among themselves, with
+// a full re-cluster forced periodically (see planSchemaPass).
 func (s *SchemaService) DetectSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]domain.Schema, error) {
-	// Get all memories for the agent
-	allMemories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
-	if err != nil {
-		return nil, err
-	}
+	return s.detectSchemas(ctx, agentID, tenantID, false)
+}
 
-	// Filter to memories that meet evidence quality thresholds
-	now := time.Now()
-	var memories []domain.Memory
-	for _, m := range allMemories {
-		if m.Confidence < MinEvidenceConfidence {
-			continue
-		}
-		if now.Sub(m.CreatedAt) < MinEvidenceAge {
-			continue
-		}
-		memories = append(memories, m)
-	}
+// RebuildSchemas re-clusters every eligible memory regardless of when the last
+// full pass ran.
+func (s *SchemaService) RebuildSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]domain.Schema, error) {
+	return s.detectSchemas(ctx, agentID, tenantID, true)
+}
 
-	if len(memories) < MinClusterSize {
-		s.logger.Debug("not enough qualified memories for schema detection",
-			zap.String("agent_id", agentID.String()),
-			zap.Int("qualified_count", len(memories)),
-			zap.Int("total_count", len(allMemories)))
-		return nil, nil
+func (s *SchemaService) detectSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, forceFull bool) ([]domain.Schema, error) {
+	result, err := s.former().pass(ctx, agentID, tenantID, forceFull)
+	if err != nil {
+		return nil, err
 	}
+	return append(result.matched, result.created...), nil
+}
 
-	// Cluster memories by similarity
-	clusters := s.clusterMemories(memories)
-
-	var detectedSchemas []domain.Schema
-	for _, cluster := range clusters {
-		if len(cluster.Memories) < MinClusterSize {
-			continue // Need minimum evidence
-		}
-
-		// Use LLM to detect schema pattern
-		if s.llmClient == nil {
-			continue
-		}
-
-		schemaExtraction, err := s.llmClient.DetectSchemaPattern(ctx, cluster.Memories)
-		if err != nil {
-			s.logger.Debug("failed to detect schema pattern", zap.Error(err))
-			continue
-		}
-		if schemaExtraction == nil {
-			continue
-		}
-
-		// Check if schema already exists
-		existing, err := s.schemaStore.GetByName(ctx, agentID, tenantID, schemaExtraction.SchemaType, schemaExtraction.Name)
-		if err == nil && existing != nil {
-			// Update existing schema with new evidence
-			if err := s.updateSchemaEvidence(ctx, existing, cluster); err != nil {
-				s.logger.Debug("failed to update schema evidence", zap.Error(err))
-			}
-			detectedSchemas = append(detectedSchemas, *existing)
-			continue
-		}
-
-		// Create new schema
-		schema := &domain.Schema{
-			AgentID:            agentID,
-			TenantID:           tenantID,
-			SchemaType:         schemaExtraction.SchemaType,
-			Name:               schemaExtraction.Name,
-			Description:        schemaExtraction.Description,
-			Attributes:         schemaExtraction.Attributes,
-			ApplicableContexts: schemaExtraction.ApplicableContexts,
-			EvidenceMemories:   cluster.MemoryIDs,
-			EvidenceCount:      len(cluster.Memories),
-			Confidence:         s.calculateInitialConfidence(len(cluster.Memories)),
-		}
-
-		now := time.Now()
-		schema.LastValidatedAt = &now
-
-		// Generate embedding for the schema
-		if s.embeddingClient != nil {
-			schemaText := schema.Name + ": " + schema.Description
-			embedding, err := s.embeddingClient.Embed(ctx, schemaText)
-			if err != nil {
-				s.logger.Debug("failed to generate schema embedding", zap.Error(err))
-			} else {
-				schema.Embedding = embedding
-			}
-		}
-
-		if err := s.schemaStore.Create(ctx, schema); err != nil {
-			s.logger.Debug("failed to create schema", zap.Error(err))
-			continue
-		}
-
-		s.logger.Info("detected new schema",
-			zap.String("schema_id", schema.ID.String()),
-			zap.String("name", schema.Name),
-			zap.String("type", string(schema.SchemaType)),
-			zap.Int("evidence_count", schema.EvidenceCount))
-
-		detectedSchemas = append(detectedSchemas, *schema)
+// former runs schema passes with this service's stores and clients.
+func (s *SchemaService) former() *schemaFormer {
+	return &schemaFormer{
+		schemaStore:     s.schemaStore,
+		memoryStore:     s.memoryStore,
+		scanner:         s.scanner,
+		embeddingClient: s.embeddingClient,
+		llmClient:       s.llmClient,
+		clusterer:       s.clusterer,
+		logger:          s.logger,
 	}
-
-	return detectedSchemas, nil
 }
 
 // MatchSchemas finds schemas that apply to the current situation.
@@ -203,12 +159,15 @@ func (s *SchemaService) MatchSchemas(ctx context.Context, input SchemaMatchInput
 		return nil, nil
 	}
 
+	// Children inherit attributes from their ancestors before scoring
+	schemas, byID := resolveSchemaHierarchy(schemas)
+
 	// Generate embedding for the query
 	var queryEmbedding []float32
 	if s.embeddingClient != nil && input.Query != "" {
 		embedding, err := s.embeddingClient.Embed(ctx, input.Query)
 		if err != nil {
-			s.logger.Debug("failed to generate query embedding", zap.Error(err))
+			logFor(ctx, s.logger).Debug("failed to generate query embedding", zap.Error(err))
 		} else {
 			queryEmbedding = embedding
 		}
@@ -227,6 +186,9 @@ func (s *SchemaService) MatchSchemas(ctx context.Context, input SchemaMatchInput
 		}
 	}
 
+	// Matching a child partially activates its ancestors
+	matches = propagateSchemaMatches(matches, byID, input.MinMatchScore)
+
 	// Sort by score descending
 	sort.Slice(matches, func(i, j int) bool {
 		return matches[i].MatchScore > matches[j].MatchScore
@@ -257,6 +219,29 @@ func (s *SchemaService) GetByAgent(ctx context.Context, agentID uuid.UUID, tenan
 	return s.schemaStore.GetByAgent(ctx, agentID, tenantID)
 }
 
+// GetWithAncestors retrieves a schema along with its ancestor chain (nearest
+// first) and the attributes it inherits from them.
+func (s *SchemaService) GetWithAncestors(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.Schema, []domain.Schema, map[string]any, error) {
+	schema, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if schema.ParentID == nil {
+		return schema, nil, schema.Attributes, nil
+	}
+
+	schemas, err := s.schemaStore.GetByAgent(ctx, schema.AgentID, tenantID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	byID := make(map[uuid.UUID]domain.Schema, len(schemas))
+	for _, sc := range schemas {
+		byID[sc.ID] = sc
+	}
+
+	return schema, domain.SchemaAncestors(*schema, byID), domain.EffectiveSchemaAttributes(*schema, byID), nil
+}
+
 // Delete removes a schema.
 func (s *SchemaService) Delete(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) error {
 	err := s.schemaStore.Delete(ctx, id, tenantID)
@@ -303,92 +288,496 @@ func (s *SchemaService) ValidateSchema(ctx context.Context, id uuid.UUID, tenant
 	return s.schemaStore.UpdateValidation(ctx, id)
 }
 
-// clusterMemories groups memories by semantic similarity.
-func (s *SchemaService) clusterMemories(memories []domain.Memory) []domain.MemoryCluster {
-	if len(memories) == 0 {
-		return nil
+// CreateSchemaInput contains input for manually creating a schema.
+type CreateSchemaInput struct {
+	AgentID            uuid.UUID
+	TenantID           uuid.UUID
+	SchemaType         domain.SchemaType
+	Name               string
+	Description        string
+	Attributes         map[string]any
+	ApplicableContexts []string
+	EvidenceMemories   []uuid.UUID
+	EvidenceEpisodes   []uuid.UUID
+	Confidence         *float32   // Defaults to initialSchemaConfidence(evidence count)
+	ParentID           *uuid.UUID // Optional parent schema
+	ParentName         string     // Resolves the parent by name (same type) when ParentID is unset
+	// ReplaceEvidence makes SeedSchemas overwrite an existing schema's
+	// evidence and confidence with the input's instead of attaching the
+	// evidence and boosting confidence, for schemas recomputed from scratch on
+	// every run, such as mined patterns.
+	ReplaceEvidence bool
+}
+
+// CreateSchema creates a schema from operator-supplied content rather than
+// consolidation, e.g. to onboard an agent with known user archetypes.
+func (s *SchemaService) CreateSchema(ctx context.Context, input CreateSchemaInput) (*domain.Schema, error) {
+	if err := s.validateCreateInput(input); err != nil {
+		return nil, err
+	}
+	if err := s.verifyAgent(ctx, input.AgentID, input.TenantID); err != nil {
+		return nil, err
 	}
 
-	// Simple single-linkage clustering based on embeddings
-	// Memories with embeddings get clustered; others are ignored for now
-	var memoriesWithEmbeddings []domain.Memory
-	for _, m := range memories {
-		if len(m.Embedding) > 0 {
-			memoriesWithEmbeddings = append(memoriesWithEmbeddings, m)
-		}
+	existing, err := s.schemaStore.GetByName(ctx, input.AgentID, input.TenantID, input.SchemaType, input.Name)
+	if err == nil && existing != nil {
+		return nil, ErrSchemaExists
+	}
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
 	}
 
-	if len(memoriesWithEmbeddings) < MinClusterSize {
-		return nil
+	return s.createSchema(ctx, input)
+}
+
+// SeedSchemas creates or updates a batch of schemas for an agent. Schemas are
+// matched by type and name, so seeding the same archetypes twice is idempotent.
+func (s *SchemaService) SeedSchemas(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, inputs []CreateSchemaInput) ([]domain.Schema, error) {
+	for i := range inputs {
+		inputs[i].AgentID = agentID
+		inputs[i].TenantID = tenantID
+		if err := s.validateCreateInput(inputs[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.verifyAgent(ctx, agentID, tenantID); err != nil {
+		return nil, err
 	}
 
-	// Track which memories are assigned to clusters
-	assigned := make(map[uuid.UUID]bool)
-	var clusters []domain.MemoryCluster
+	seeded := make([]domain.Schema, 0, len(inputs))
+	for _, input := range inputs {
+		existing, err := s.schemaStore.GetByName(ctx, agentID, tenantID, input.SchemaType, input.Name)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
 
-	for i, seed := range memoriesWithEmbeddings {
-		if assigned[seed.ID] {
+		if existing == nil {
+			schema, err := s.createSchema(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			seeded = append(seeded, *schema)
 			continue
 		}
 
-		// Start a new cluster with this memory
-		cluster := domain.MemoryCluster{
-			Memories:  []domain.Memory{seed},
-			MemoryIDs: []uuid.UUID{seed.ID},
-			Centroid:  seed.Embedding,
+		update := UpdateSchemaInput{
+			ID:         existing.ID,
+			TenantID:   tenantID,
+			Attributes: input.Attributes,
+			Confidence: input.Confidence,
+		}
+		if input.Description != "" {
+			update.Description = &input.Description
+		}
+		if input.ApplicableContexts != nil {
+			update.ApplicableContexts = &input.ApplicableContexts
 		}
-		assigned[seed.ID] = true
+		if input.ParentID != nil || input.ParentName != "" {
+			parent, err := s.resolveParent(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			update.ParentID = &parent.ID
+		}
+		if input.ReplaceEvidence {
+			memoryIDs, episodeIDs := input.EvidenceMemories, input.EvidenceEpisodes
+			update.EvidenceMemories, update.EvidenceEpisodes = &memoryIDs, &episodeIDs
+		}
+		schema, err := s.UpdateSchema(ctx, update)
+		if err != nil {
+			return nil, err
+		}
+		if !input.ReplaceEvidence && (len(input.EvidenceMemories) > 0 || len(input.EvidenceEpisodes) > 0) {
+			schema, err = s.AttachEvidence(ctx, schema.ID, tenantID, input.EvidenceMemories, input.EvidenceEpisodes)
+			if err != nil {
+				return nil, err
+			}
+		}
+		seeded = append(seeded, *schema)
+	}
 
-		// Find similar memories
-		for j := i + 1; j < len(memoriesWithEmbeddings); j++ {
-			candidate := memoriesWithEmbeddings[j]
-			if assigned[candidate.ID] {
-				continue
+	return seeded, nil
+}
+
+// UpdateSchemaInput contains input for updating a schema. Nil fields are left
+// unchanged.
+type UpdateSchemaInput struct {
+	ID                 uuid.UUID
+	TenantID           uuid.UUID
+	Name               *string
+	Description        *string
+	Attributes         map[string]any
+	ApplicableContexts *[]string
+	Confidence         *float32
+	ParentID           *uuid.UUID // uuid.Nil detaches the schema from its parent
+	// EvidenceMemories and EvidenceEpisodes, when set, replace the schema's
+	// evidence and its evidence count.
+	EvidenceMemories *[]uuid.UUID
+	EvidenceEpisodes *[]uuid.UUID
+}
+
+// UpdateSchema applies a partial update to a schema, regenerating its embedding
+// when the name or description changes.
+func (s *SchemaService) UpdateSchema(ctx context.Context, input UpdateSchemaInput) (*domain.Schema, error) {
+	schema, err := s.schemaStore.GetByID(ctx, input.ID, input.TenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrSchemaNotFound
+		}
+		return nil, err
+	}
+
+	reembed := false
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, ErrSchemaNameRequired
+		}
+		if name != schema.Name {
+			existing, err := s.schemaStore.GetByName(ctx, schema.AgentID, schema.TenantID, schema.SchemaType, name)
+			if err == nil && existing != nil {
+				return nil, ErrSchemaExists
+			}
+			if err != nil && !errors.Is(err, store.ErrNotFound) {
+				return nil, err
 			}
+			schema.Name = name
+			reembed = true
+		}
+	}
+	if input.Description != nil && *input.Description != schema.Description {
+		schema.Description = *input.Description
+		reembed = true
+	}
+	if input.Attributes != nil {
+		schema.Attributes = input.Attributes
+	}
+	if input.ApplicableContexts != nil {
+		schema.ApplicableContexts = *input.ApplicableContexts
+	}
+	if input.Confidence != nil {
+		if *input.Confidence < 0 || *input.Confidence > 1 {
+			return nil, ErrInvalidConfidence
+		}
+		schema.Confidence = *input.Confidence
+	}
 
-			similarity := cosineSimilarity(cluster.Centroid, candidate.Embedding)
-			if similarity >= ClusteringThreshold {
-				cluster.Memories = append(cluster.Memories, candidate)
-				cluster.MemoryIDs = append(cluster.MemoryIDs, candidate.ID)
-				assigned[candidate.ID] = true
+	if input.EvidenceMemories != nil || input.EvidenceEpisodes != nil {
+		memoryIDs, episodeIDs := schema.EvidenceMemories, schema.EvidenceEpisodes
+		if input.EvidenceMemories != nil {
+			memoryIDs = append([]uuid.UUID{}, dedupeUUIDs(*input.EvidenceMemories)...)
+		}
+		if input.EvidenceEpisodes != nil {
+			episodeIDs = append([]uuid.UUID{}, dedupeUUIDs(*input.EvidenceEpisodes)...)
+		}
+		if err := s.verifyEvidence(ctx, schema.AgentID, schema.TenantID, memoryIDs, episodeIDs); err != nil {
+			return nil, err
+		}
+		schema.EvidenceMemories, schema.EvidenceEpisodes = memoryIDs, episodeIDs
+		schema.EvidenceCount = len(memoryIDs) + len(episodeIDs)
+	}
 
-				// Update centroid (simple average)
-				cluster.Centroid = averageVectors(cluster.Centroid, candidate.Embedding)
+	if input.ParentID != nil {
+		var parentID *uuid.UUID
+		if *input.ParentID != uuid.Nil {
+			parentID = input.ParentID
+		}
+		if err := s.schemaStore.SetParent(ctx, schema.ID, schema.TenantID, parentID); err != nil {
+			switch {
+			case errors.Is(err, store.ErrCycle):
+				return nil, ErrSchemaCycle
+			case errors.Is(err, store.ErrNotFound):
+				return nil, ErrParentSchemaNotFound
 			}
+			return nil, err
 		}
+		schema.ParentID = parentID
+	}
 
-		// Extract theme from cluster
-		cluster.Theme = s.extractClusterTheme(cluster.Memories)
+	// The store keeps the existing embedding when none is supplied.
+	schema.Embedding = nil
+	if reembed {
+		s.former().embed(ctx, schema)
+	}
 
-		clusters = append(clusters, cluster)
+	if err := s.schemaStore.Update(ctx, schema); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrSchemaNotFound
+		}
+		return nil, err
 	}
 
-	return clusters
+	return s.schemaStore.GetByID(ctx, schema.ID, schema.TenantID)
 }
 
-// extractClusterTheme extracts a theme from cluster memories.
-func (s *SchemaService) extractClusterTheme(memories []domain.Memory) string {
-	if len(memories) == 0 {
-		return ""
+// AttachEvidence links memories and episodes to a schema as supporting
+// evidence. IDs already attached are skipped; each new piece of evidence
+// boosts confidence the same way consolidation-detected evidence does.
+func (s *SchemaService) AttachEvidence(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, memoryIDs []uuid.UUID, episodeIDs []uuid.UUID) (*domain.Schema, error) {
+	schema, err := s.schemaStore.GetByID(ctx, id, tenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrSchemaNotFound
+		}
+		return nil, err
 	}
 
-	// Simple approach: find common words/themes
-	// For MVP, just use the type distribution
-	typeCounts := make(map[domain.MemoryType]int)
-	for _, m := range memories {
-		typeCounts[m.Type]++
+	if err := s.verifyEvidence(ctx, schema.AgentID, tenantID, memoryIDs, episodeIDs); err != nil {
+		return nil, err
+	}
+
+	existingMemories := make(map[uuid.UUID]bool, len(schema.EvidenceMemories))
+	for _, mid := range schema.EvidenceMemories {
+		existingMemories[mid] = true
+	}
+	existingEpisodes := make(map[uuid.UUID]bool, len(schema.EvidenceEpisodes))
+	for _, eid := range schema.EvidenceEpisodes {
+		existingEpisodes[eid] = true
+	}
+
+	newCount := 0
+	for _, mid := range memoryIDs {
+		if existingMemories[mid] {
+			continue
+		}
+		if err := s.schemaStore.AddEvidence(ctx, id, &mid, nil); err != nil {
+			return nil, err
+		}
+		existingMemories[mid] = true
+		newCount++
+	}
+	for _, eid := range episodeIDs {
+		if existingEpisodes[eid] {
+			continue
+		}
+		if err := s.schemaStore.AddEvidence(ctx, id, nil, &eid); err != nil {
+			return nil, err
+		}
+		existingEpisodes[eid] = true
+		newCount++
+	}
+
+	if newCount > 0 {
+		newConfidence := schema.Confidence + float32(newCount)*SchemaConfidenceBoost
+		if newConfidence > MaxSchemaConfidence {
+			newConfidence = MaxSchemaConfidence
+		}
+		if err := s.schemaStore.UpdateConfidence(ctx, id, newConfidence); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.schemaStore.GetByID(ctx, id, tenantID)
+}
+
+// createSchema persists a validated CreateSchemaInput.
+func (s *SchemaService) createSchema(ctx context.Context, input CreateSchemaInput) (*domain.Schema, error) {
+	if err := s.verifyEvidence(ctx, input.AgentID, input.TenantID, input.EvidenceMemories, input.EvidenceEpisodes); err != nil {
+		return nil, err
+	}
+
+	evidenceMemories := dedupeUUIDs(input.EvidenceMemories)
+	evidenceEpisodes := dedupeUUIDs(input.EvidenceEpisodes)
+	evidenceCount := len(evidenceMemories) + len(evidenceEpisodes)
+
+	schema := &domain.Schema{
+		AgentID:            input.AgentID,
+		TenantID:           input.TenantID,
+		SchemaType:         input.SchemaType,
+		Name:               strings.TrimSpace(input.Name),
+		Description:        input.Description,
+		Attributes:         input.Attributes,
+		ApplicableContexts: input.ApplicableContexts,
+		EvidenceMemories:   evidenceMemories,
+		EvidenceEpisodes:   evidenceEpisodes,
+		EvidenceCount:      evidenceCount,
+		Confidence:         initialSchemaConfidence(evidenceCount),
+	}
+	if input.Confidence != nil {
+		schema.Confidence = *input.Confidence
+	}
+	if schema.Attributes == nil {
+		schema.Attributes = map[string]any{}
+	}
+	if input.ParentID != nil || input.ParentName != "" {
+		parent, err := s.resolveParent(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		schema.ParentID = &parent.ID
+	}
+
+	now := timeNow()
+	schema.LastValidatedAt = &now
+
+	s.former().embed(ctx, schema)
+
+	if err := s.schemaStore.Create(ctx, schema); err != nil {
+		return nil, err
 	}
 
-	maxType := domain.MemoryTypeFact
-	maxCount := 0
-	for t, count := range typeCounts {
-		if count > maxCount {
-			maxType = t
-			maxCount = count
+	logFor(ctx, s.logger).Info("created schema",
+		zap.String("schema_id", schema.ID.String()),
+		zap.String("name", schema.Name),
+		zap.String("type", string(schema.SchemaType)),
+		zap.Int("evidence_count", schema.EvidenceCount))
+
+	return schema, nil
+}
+
+// resolveParent looks up the parent named by a CreateSchemaInput, either by ID
+// or by name within the same schema type. The parent must belong to the agent.
+func (s *SchemaService) resolveParent(ctx context.Context, input CreateSchemaInput) (*domain.Schema, error) {
+	var parent *domain.Schema
+	var err error
+	if input.ParentID != nil {
+		parent, err = s.schemaStore.GetByID(ctx, *input.ParentID, input.TenantID)
+	} else {
+		parent, err = s.schemaStore.GetByName(ctx, input.AgentID, input.TenantID, input.SchemaType, input.ParentName)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrParentSchemaNotFound
 		}
+		return nil, err
 	}
+	if parent == nil || parent.AgentID != input.AgentID {
+		return nil, ErrParentSchemaNotFound
+	}
+	return parent, nil
+}
+
+// validateCreateInput checks the caller-supplied fields of a CreateSchemaInput.
+func (s *SchemaService) validateCreateInput(input CreateSchemaInput) error {
+	if !input.SchemaType.IsValid() {
+		return ErrInvalidSchemaType
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return ErrSchemaNameRequired
+	}
+	if input.Confidence != nil && (*input.Confidence < 0 || *input.Confidence > 1) {
+		return ErrInvalidConfidence
+	}
+	return nil
+}
 
-	return string(maxType) + "-cluster"
+// verifyAgent checks that the agent exists within the tenant.
+func (s *SchemaService) verifyAgent(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) error {
+	if _, err := s.agentStore.GetByID(ctx, agentID, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrAgentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyEvidence checks that every evidence memory and episode belongs to the
+// schema's agent. Another agent's evidence is reported as not found, the same
+// as evidence in another tenant, so schemas can't reach across agents.
+// Episodes are only verified when an episode store is configured.
+func (s *SchemaService) verifyEvidence(ctx context.Context, agentID, tenantID uuid.UUID, memoryIDs []uuid.UUID, episodeIDs []uuid.UUID) error {
+	for _, mid := range memoryIDs {
+		mem, err := s.memoryStore.GetByID(ctx, mid, tenantID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return ErrEvidenceNotFound
+			}
+			return err
+		}
+		if mem.AgentID != agentID {
+			return ErrEvidenceNotFound
+		}
+	}
+	if s.episodeStore == nil {
+		return nil
+	}
+	for _, eid := range episodeIDs {
+		ep, err := s.episodeStore.GetByID(ctx, eid, tenantID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return ErrEvidenceNotFound
+			}
+			return err
+		}
+		if ep.AgentID != agentID {
+			return ErrEvidenceNotFound
+		}
+	}
+	return nil
+}
+
+// dedupeUUIDs returns ids with duplicates removed, preserving order.
+func dedupeUUIDs(ids []uuid.UUID) []uuid.UUID {
+	if len(ids) == 0 {
+		return ids
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// resolveSchemaHierarchy replaces each schema's attributes with those it
+// inherits from its ancestors and returns an ID index of the resolved schemas.
+func resolveSchemaHierarchy(schemas []domain.Schema) ([]domain.Schema, map[uuid.UUID]domain.Schema) {
+	raw := make(map[uuid.UUID]domain.Schema, len(schemas))
+	for _, sc := range schemas {
+		raw[sc.ID] = sc
+	}
+
+	resolved := make([]domain.Schema, len(schemas))
+	byID := make(map[uuid.UUID]domain.Schema, len(schemas))
+	for i, sc := range schemas {
+		sc.Attributes = domain.EffectiveSchemaAttributes(sc, raw)
+		resolved[i] = sc
+		byID[sc.ID] = sc
+	}
+	return resolved, byID
+}
+
+// propagateSchemaMatches passes a decaying share of each match's score up to
+// the matched schema's ancestors, so matching "Frontend Developer" partially
+// activates "Developer". An ancestor keeps the higher of its own score and any
+// inherited one.
+func propagateSchemaMatches(matches []domain.SchemaMatch, byID map[uuid.UUID]domain.Schema, minScore float32) []domain.SchemaMatch {
+	out := append([]domain.SchemaMatch(nil), matches...)
+	index := make(map[uuid.UUID]int, len(out))
+	for i, m := range out {
+		index[m.Schema.ID] = i
+	}
+
+	for _, m := range matches {
+		score := m.MatchScore
+		for _, ancestor := range domain.SchemaAncestors(m.Schema, byID) {
+			score *= SchemaParentActivation
+			if score < minScore {
+				break
+			}
+			reason := "inherited from " + m.Schema.Name
+			if i, ok := index[ancestor.ID]; ok {
+				if out[i].MatchScore < score {
+					out[i].MatchScore = score
+					out[i].MatchReason = reason
+				}
+				continue
+			}
+			index[ancestor.ID] = len(out)
+			out = append(out, domain.SchemaMatch{
+				Schema:      ancestor,
+				MatchScore:  score,
+				MatchReason: reason,
+			})
+		}
+	}
+
+	return out
 }
 
 // scoreSchemaMatch calculates how well a schema matches the current situation.
@@ -404,8 +793,8 @@ func (s *SchemaService) scoreSchemaMatch(schema domain.Schema, input SchemaMatch
 	}
 
 	// Time matching
-	if input.TimeOfDay != "" {
-		timeScore := s.scoreTimeMatch(schema.Attributes, input.TimeOfDay)
+	if input.TimeOfDay != "" || input.DayOfWeek != "" {
+		timeScore := s.scoreTimeMatch(schema.Attributes, input.TimeOfDay, input.DayOfWeek)
 		if timeScore > 0 {
 			score += timeScore * TimeMatchWeight
 			reasons = append(reasons, "time preference match")
@@ -415,7 +804,7 @@ func (s *SchemaService) scoreSchemaMatch(schema domain.Schema, input SchemaMatch
 	// Embedding similarity
 	if len(queryEmbedding) > 0 && len(schema.Embedding) > 0 {
 		similarity := cosineSimilarity(queryEmbedding, schema.Embedding)
-		if similarity > 0.5 {
+		if similarity > SchemaMatchSimilarityThreshold {
 			score += similarity * EmbeddingSimilarityWeight
 			reasons = append(reasons, "semantic similarity")
 		}
@@ -455,9 +844,19 @@ func (s *SchemaService) scoreContextMatch(schemaContexts []string, inputContexts
 	return float32(matches) / float32(len(schemaContexts))
 }
 
-// scoreTimeMatch scores time preference matching.
-func (s *SchemaService) scoreTimeMatch(attributes map[string]any, timeOfDay string) float32 {
-	if timeOfDay == "" || attributes == nil {
+// scoreTimeMatch scores time preference matching. A day_preference, as
+// mined by TemporalPatternService, matches the day of week.
+func (s *SchemaService) scoreTimeMatch(attributes map[string]any, timeOfDay, dayOfWeek string) float32 {
+	if (timeOfDay == "" && dayOfWeek == "") || attributes == nil {
+		return 0
+	}
+
+	if dayOfWeek != "" {
+		if pref, ok := attributes["day_preference"].(string); ok && strings.EqualFold(pref, dayOfWeek) {
+			return 1.0
+		}
+	}
+	if timeOfDay == "" {
 		return 0
 	}
 
@@ -482,47 +881,6 @@ func (s *SchemaService) scoreTimeMatch(attributes map[string]any, timeOfDay stri
 	return 0
 }
 
-// updateSchemaEvidence updates an existing schema with new evidence.
-func (s *SchemaService) updateSchemaEvidence(ctx context.Context, schema *domain.Schema, cluster domain.MemoryCluster) error {
-	// Add new memory IDs that aren't already in evidence
-	existingIDs := make(map[uuid.UUID]bool)
-	for _, id := range schema.EvidenceMemories {
-		existingIDs[id] = true
-	}
-
-	newCount := 0
-	for _, id := range cluster.MemoryIDs {
-		if !existingIDs[id] {
-			if err := s.schemaStore.AddEvidence(ctx, schema.ID, &id, nil); err != nil {
-				s.logger.Debug("failed to add evidence", zap.Error(err))
-				continue
-			}
-			newCount++
-		}
-	}
-
-	if newCount > 0 {
-		// Boost confidence
-		newConfidence := schema.Confidence + float32(newCount)*SchemaConfidenceBoost
-		if newConfidence > MaxSchemaConfidence {
-			newConfidence = MaxSchemaConfidence
-		}
-		return s.schemaStore.UpdateConfidence(ctx, schema.ID, newConfidence)
-	}
-
-	return nil
-}
-
-// calculateInitialConfidence calculates initial confidence based on evidence count.
-func (s *SchemaService) calculateInitialConfidence(evidenceCount int) float32 {
-	// Start at 0.5 and increase with more evidence
-	confidence := 0.5 + float32(evidenceCount)*0.05
-	if confidence > 0.8 {
-		confidence = 0.8 // Cap initial confidence
-	}
-	return confidence
-}
-
 // cosineSimilarity calculates cosine similarity between two vectors.
 func cosineSimilarity(a, b []float32) float32 {
 	if len(a) != len(b) || len(a) == 0 {
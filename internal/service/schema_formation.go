@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// schemaFormer runs schema maintenance passes. SchemaService runs them on
+// demand and ConsolidationService as a consolidation stage; both go through
+// here so evidence eligibility, clustering and confidence updates follow one
+// set of thresholds (see the schema constants in schema.go).
+type schemaFormer struct {
+	schemaStore     domain.SchemaStore
+	memoryStore     domain.MemoryStore
+	scanner         domain.MemoryScanner
+	assocStore      domain.MemoryAssociationStore // optional: links evidence to new schemas
+	embeddingClient domain.EmbeddingClient
+	llmClient       domain.LLMClient
+	clusterer       Clusterer
+	logger          *zap.Logger
+}
+
+// schemaPassResult is what a pass found: the existing schemas new memories
+// were matched to (grown of them gained evidence), and schemas created from
+// new clusters.
+type schemaPassResult struct {
+	matched []domain.Schema
+	grown   int
+	created []domain.Schema
+}
+
+func (r *schemaPassResult) match(schema domain.Schema, grew bool) {
+	r.matched = append(r.matched, schema)
+	if grew {
+		r.grown++
+	}
+}
+
+// schemaEvidenceEligible reports whether a memory may back a schema: it must
+// be embedded, confident and stable. Rollups restate their members, so
+// they're skipped.
+func schemaEvidenceEligible(m *domain.Memory, now time.Time) bool {
+	return len(m.Embedding) > 0 && m.Type != domain.MemoryTypeSummary &&
+		m.Confidence >= MinEvidenceConfidence && now.Sub(m.CreatedAt) >= MinEvidenceAge
+}
+
+// pass attaches memories that became eligible to the nearest existing schema
+// and clusters the rest into new schemas, or re-clusters everything when a
+// full pass is due (see planSchemaPass). The pass state is recorded even when
+// forming schemas partly failed.
+func (f *schemaFormer) pass(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, forceFull bool) (schemaPassResult, error) {
+	var result schemaPassResult
+
+	state, err := loadSchemaPassState(ctx, f.schemaStore, agentID, tenantID)
+	if err != nil {
+		logFor(ctx, f.logger).Warn("failed to load schema pass state, running full pass",
+			zap.String("agent_id", agentID.String()), zap.Error(err))
+	}
+	existingSchemas, err := f.schemaStore.GetByAgent(ctx, agentID, tenantID)
+	if err != nil {
+		return result, err
+	}
+
+	now := timeNow()
+	evidence, memories, total, err := collectSchemaInputs(ctx, f.scanner, f.memoryStore, agentID, existingSchemas, func(m *domain.Memory) bool {
+		return schemaEvidenceEligible(m, now)
+	})
+	if err != nil {
+		return result, err
+	}
+	plan := planSchemaPass(state, evidence, memories, existingSchemas, agentID, tenantID, forceFull, now)
+
+	for i := range existingSchemas {
+		assigned := plan.assignments[existingSchemas[i].ID]
+		if len(assigned) == 0 {
+			continue
+		}
+		result.match(existingSchemas[i], f.addEvidence(ctx, &existingSchemas[i], memoryCluster(assigned).MemoryIDs))
+	}
+
+	if len(plan.unassigned) >= MinClusterSize {
+		formed := f.form(ctx, agentID, tenantID, plan.unassigned)
+		result.matched = append(result.matched, formed.matched...)
+		result.grown += formed.grown
+		result.created = append(result.created, formed.created...)
+	} else if plan.full {
+		logFor(ctx, f.logger).Debug("not enough qualified memories for schema detection",
+			zap.String("agent_id", agentID.String()),
+			zap.Int("qualified_count", len(memories)),
+			zap.Int("total_count", total))
+	}
+
+	if err := f.schemaStore.RecordPass(ctx, &plan.next); err != nil {
+		logFor(ctx, f.logger).Warn("failed to record schema pass", zap.String("agent_id", agentID.String()), zap.Error(err))
+	}
+
+	logFor(ctx, f.logger).Debug("schema pass complete",
+		zap.String("agent_id", agentID.String()),
+		zap.Bool("full", plan.full),
+		zap.Int("assigned_schemas", len(plan.assignments)),
+		zap.Int("unassigned_memories", len(plan.unassigned)),
+		zap.Int("created", len(result.created)))
+
+	return result, nil
+}
+
+// form clusters memories and turns each large enough cluster into a schema,
+// either adding evidence to a same-named existing schema or creating a new
+// one. Without an LLM to name the pattern nothing is formed.
+func (f *schemaFormer) form(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memories []domain.Memory) schemaPassResult {
+	var result schemaPassResult
+	if f.llmClient == nil {
+		return result
+	}
+
+	for _, cluster := range f.clusterMemories(memories) {
+		if len(cluster.Memories) < MinClusterSize {
+			continue // Need minimum evidence
+		}
+
+		extraction, err := f.llmClient.DetectSchemaPattern(ctx, cluster.Memories)
+		if err != nil {
+			logFor(ctx, f.logger).Debug("failed to detect schema pattern", zap.Error(err))
+			continue
+		}
+		if extraction == nil {
+			continue
+		}
+
+		// Check if schema already exists
+		existing, err := f.schemaStore.GetByName(ctx, agentID, tenantID, extraction.SchemaType, extraction.Name)
+		if err == nil && existing != nil {
+			result.match(*existing, f.addEvidence(ctx, existing, cluster.MemoryIDs))
+			continue
+		}
+
+		now := timeNow()
+		schema := &domain.Schema{
+			AgentID:            agentID,
+			TenantID:           tenantID,
+			SchemaType:         extraction.SchemaType,
+			Name:               extraction.Name,
+			Description:        extraction.Description,
+			Attributes:         extraction.Attributes,
+			ApplicableContexts: extraction.ApplicableContexts,
+			EvidenceMemories:   cluster.MemoryIDs,
+			EvidenceCount:      len(cluster.Memories),
+			Confidence:         initialSchemaConfidence(len(cluster.Memories)),
+			LastValidatedAt:    &now,
+		}
+		f.embed(ctx, schema)
+
+		if err := f.schemaStore.Create(ctx, schema); err != nil {
+			logFor(ctx, f.logger).Debug("failed to create schema", zap.Error(err))
+			continue
+		}
+		f.linkEvidence(ctx, schema)
+
+		logFor(ctx, f.logger).Info("detected new schema",
+			zap.String("schema_id", schema.ID.String()),
+			zap.String("name", schema.Name),
+			zap.String("type", string(schema.SchemaType)),
+			zap.Int("evidence_count", schema.EvidenceCount))
+
+		result.created = append(result.created, *schema)
+	}
+
+	return result
+}
+
+// linkEvidence associates a new schema's evidence memories with it, so
+// spreading activation can reach the schema from its memories.
+func (f *schemaFormer) linkEvidence(ctx context.Context, schema *domain.Schema) {
+	if f.assocStore == nil {
+		return
+	}
+	for _, memID := range schema.EvidenceMemories {
+		_ = f.assocStore.Create(ctx, &domain.MemoryAssociation{
+			TenantID:            schema.TenantID,
+			SourceMemoryType:    domain.ActivatedMemoryTypeSemantic,
+			SourceMemoryID:      memID,
+			TargetMemoryType:    domain.ActivatedMemoryTypeSchema,
+			TargetMemoryID:      schema.ID,
+			AssociationType:     domain.AssociationTypeDerived,
+			AssociationStrength: 0.8,
+		})
+	}
+}
+
+// addEvidence attaches memories not yet backing the schema as evidence and
+// boosts its confidence. Reports whether anything was added.
+func (f *schemaFormer) addEvidence(ctx context.Context, schema *domain.Schema, memoryIDs []uuid.UUID) bool {
+	existingIDs := make(map[uuid.UUID]bool)
+	for _, id := range schema.EvidenceMemories {
+		existingIDs[id] = true
+	}
+
+	newCount := 0
+	for _, id := range memoryIDs {
+		if existingIDs[id] {
+			continue
+		}
+		if err := f.schemaStore.AddEvidence(ctx, schema.ID, &id, nil); err != nil {
+			logFor(ctx, f.logger).Debug("failed to add evidence", zap.Error(err))
+			continue
+		}
+		existingIDs[id] = true
+		newCount++
+	}
+	if newCount == 0 {
+		return false
+	}
+
+	newConfidence := schema.Confidence + float32(newCount)*SchemaConfidenceBoost
+	if newConfidence > MaxSchemaConfidence {
+		newConfidence = MaxSchemaConfidence
+	}
+	if err := f.schemaStore.UpdateConfidence(ctx, schema.ID, newConfidence); err != nil {
+		logFor(ctx, f.logger).Debug("failed to update schema confidence", zap.Error(err))
+	}
+	return true
+}
+
+// embed generates the similarity embedding for a schema from its name and
+// description. Failures are logged and leave the embedding unset.
+func (f *schemaFormer) embed(ctx context.Context, schema *domain.Schema) {
+	if f.embeddingClient == nil {
+		return
+	}
+	embedding, err := f.embeddingClient.Embed(ctx, schema.Name+": "+schema.Description)
+	if err != nil {
+		logFor(ctx, f.logger).Debug("failed to generate schema embedding", zap.Error(err))
+		return
+	}
+	schema.Embedding = embedding
+}
+
+// clusterMemories groups memories by embedding similarity.
+func (f *schemaFormer) clusterMemories(memories []domain.Memory) []domain.MemoryCluster {
+	if len(memories) == 0 {
+		return nil
+	}
+
+	clusterer := f.clusterer
+	if clusterer == nil {
+		clusterer = NewAgglomerativeClusterer(ClusteringThreshold)
+	}
+	clusters := clusterer.Cluster(memories)
+	for i := range clusters {
+		clusters[i].Theme = clusterTheme(clusters[i].Memories)
+	}
+	return clusters
+}
+
+// clusterTheme names a cluster after its most common memory type.
+func clusterTheme(memories []domain.Memory) string {
+	if len(memories) == 0 {
+		return ""
+	}
+
+	typeCounts := make(map[domain.MemoryType]int)
+	for _, m := range memories {
+		typeCounts[m.Type]++
+	}
+
+	maxType := domain.MemoryTypeFact
+	maxCount := 0
+	for t, count := range typeCounts {
+		if count > maxCount {
+			maxType = t
+			maxCount = count
+		}
+	}
+
+	return string(maxType) + "-cluster"
+}
+
+// initialSchemaConfidence starts a schema at 0.5 plus 0.05 per piece of
+// evidence, capped at 0.8 until later evidence confirms it.
+func initialSchemaConfidence(evidenceCount int) float32 {
+	confidence := 0.5 + float32(evidenceCount)*0.05
+	if confidence > 0.8 {
+		confidence = 0.8
+	}
+	return confidence
+}
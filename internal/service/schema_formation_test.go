@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestSchemaFormer_ClusterMemories(t *testing.T) {
+	f := &schemaFormer{}
+
+	// Create memories with similar embeddings
+	agentID := uuid.New()
+	tenantID := uuid.New()
+
+	// Two memories in one cluster (similar embeddings)
+	mem1 := domain.Memory{
+		ID:        uuid.New(),
+		AgentID:   agentID,
+		TenantID:  tenantID,
+		Content:   "memory 1",
+		Embedding: []float32{1.0, 0.0, 0.0},
+	}
+	mem2 := domain.Memory{
+		ID:        uuid.New(),
+		AgentID:   agentID,
+		TenantID:  tenantID,
+		Content:   "memory 2",
+		Embedding: []float32{0.95, 0.1, 0.0}, // Very similar to mem1
+	}
+	// One memory in another cluster
+	mem3 := domain.Memory{
+		ID:        uuid.New(),
+		AgentID:   agentID,
+		TenantID:  tenantID,
+		Content:   "memory 3",
+		Embedding: []float32{0.0, 1.0, 0.0}, // Different direction
+	}
+
+	memories := []domain.Memory{mem1, mem2, mem3}
+	clusters := f.clusterMemories(memories)
+
+	if len(clusters) != 2 {
+		t.Errorf("expected 2 clusters, got %d", len(clusters))
+	}
+
+	// First cluster should have mem1 and mem2
+	found1 := false
+	found2 := false
+	for _, cluster := range clusters {
+		if len(cluster.Memories) == 2 {
+			for _, m := range cluster.Memories {
+				if m.ID == mem1.ID {
+					found1 = true
+				}
+				if m.ID == mem2.ID {
+					found2 = true
+				}
+			}
+		}
+	}
+
+	if !found1 || !found2 {
+		t.Error("mem1 and mem2 should be in the same cluster")
+	}
+}
+
+func TestInitialSchemaConfidence(t *testing.T) {
+	// Test minimum cluster size
+	confidence := initialSchemaConfidence(3)
+	expectedMin := float32(0.5 + 3*0.05) // 0.65
+	if confidence != expectedMin {
+		t.Fatalf("expected confidence %f for 3 items, got %f", expectedMin, confidence)
+	}
+
+	// Test large evidence count (should cap at 0.8)
+	confidence = initialSchemaConfidence(100)
+	if confidence != 0.8 {
+		t.Fatalf("expected confidence to cap at 0.8, got %f", confidence)
+	}
+}
+
+func TestSchemaFormer_Pass(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	schemaStore := newMockSchemaStore()
+	memoryStore := newMockMemoryStoreForSchema()
+
+	addMemory := func(i int, confidence float32, embedded bool) {
+		m := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "memory", Confidence: confidence}
+		if embedded {
+			m.Embedding = []float32{1, 0.02 * float32(i), 0}
+		}
+		_ = memoryStore.Create(ctx, m)
+		m.CreatedAt = timeNow().Add(-48 * time.Hour)
+	}
+	for i := 0; i < MinClusterSize; i++ {
+		addMemory(i, 0.9, true)
+	}
+	addMemory(MinClusterSize, MinEvidenceConfidence-0.1, true) // too doubtful
+	addMemory(MinClusterSize, 0.9, false)                      // can't be clustered
+
+	assocStore := new(MockMemoryAssociationStore)
+	assocStore.On("Create", ctx, mock.MatchedBy(func(a *domain.MemoryAssociation) bool {
+		return a.TargetMemoryType == domain.ActivatedMemoryTypeSchema && a.AssociationType == domain.AssociationTypeDerived
+	})).Return(nil).Times(MinClusterSize)
+
+	former := &schemaFormer{
+		schemaStore: schemaStore,
+		memoryStore: memoryStore,
+		assocStore:  assocStore,
+		llmClient:   newMockLLMClient(),
+		logger:      zap.NewNop(),
+	}
+
+	result, err := former.pass(ctx, agentID, tenantID, true)
+	if err != nil {
+		t.Fatalf("pass: %v", err)
+	}
+	if len(result.created) != 1 || len(result.matched) != 0 {
+		t.Fatalf("expected one new schema, got %+v", result)
+	}
+	created := result.created[0]
+	if created.EvidenceCount != MinClusterSize || created.Confidence != initialSchemaConfidence(MinClusterSize) {
+		t.Fatalf("new schema = %+v, want %d pieces of evidence at initial confidence", created, MinClusterSize)
+	}
+	assocStore.AssertExpectations(t)
+
+	// A later memory joining the same pattern grows the existing schema.
+	addMemory(MinClusterSize+1, 0.9, true)
+	result, err = former.pass(ctx, agentID, tenantID, true)
+	if err != nil {
+		t.Fatalf("second pass: %v", err)
+	}
+	if len(result.created) != 0 || len(result.matched) != 1 || result.grown != 1 {
+		t.Fatalf("expected the existing schema to grow, got %+v", result)
+	}
+	got := schemaStore.schemas[created.ID]
+	if len(got.EvidenceMemories) != MinClusterSize+1 {
+		t.Fatalf("evidence = %d, want %d", len(got.EvidenceMemories), MinClusterSize+1)
+	}
+	if want := created.Confidence + SchemaConfidenceBoost; got.Confidence < want-1e-6 || got.Confidence > want+1e-6 {
+		t.Fatalf("confidence = %f, want %f", got.Confidence, want)
+	}
+}
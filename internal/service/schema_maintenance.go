@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+)
+
+const (
+	SchemaFullReclusterInterval = 7 * 24 * time.Hour // Force a full re-cluster at least this often
+	SchemaFullReclusterEvery    = 20                 // Incremental passes between full re-clusters
+	SchemaAssignThreshold       = ClusteringThreshold
+)
+
+// schemaPassPlan describes the work for one schema maintenance pass. A full
+// pass re-clusters every eligible memory. An incremental pass only looks at
+// memories that became eligible since the previous pass: those close enough to
+// an existing schema's evidence become new evidence for it, and the rest are
+// clustered among themselves to look for new schemas.
+type schemaPassPlan struct {
+	full        bool
+	assignments map[uuid.UUID][]domain.Memory // schema ID -> new evidence
+	unassigned  []domain.Memory               // memories to cluster
+	next        domain.SchemaPassState        // state to record once the pass completes
+}
+
+// loadSchemaPassState returns the agent's last pass state, or nil if no pass
+// has run yet.
+func loadSchemaPassState(ctx context.Context, schemaStore domain.SchemaStore, agentID uuid.UUID, tenantID uuid.UUID) (*domain.SchemaPassState, error) {
+	state, err := schemaStore.GetPassState(ctx, agentID, tenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+// planSchemaPass decides between a full and an incremental pass. all is every
+// memory loaded for the agent (used to locate existing schemas' evidence),
+// eligible the subset that qualifies as schema evidence. Memories that became
+// eligible since the last pass but fit no schema and form no cluster are left
+// for the next full re-cluster.
+func planSchemaPass(
+	state *domain.SchemaPassState,
+	all []domain.Memory,
+	eligible []domain.Memory,
+	schemas []domain.Schema,
+	agentID uuid.UUID,
+	tenantID uuid.UUID,
+	forceFull bool,
+	now time.Time,
+) schemaPassPlan {
+	plan := schemaPassPlan{
+		next: domain.SchemaPassState{
+			AgentID:    agentID,
+			TenantID:   tenantID,
+			LastPassAt: now,
+		},
+	}
+
+	if forceFull || state == nil ||
+		now.Sub(state.LastFullPassAt) >= SchemaFullReclusterInterval ||
+		state.IncrementalPasses >= SchemaFullReclusterEvery {
+		plan.full = true
+		plan.unassigned = eligible
+		plan.next.LastFullPassAt = now
+		return plan
+	}
+
+	plan.next.LastFullPassAt = state.LastFullPassAt
+	plan.next.IncrementalPasses = state.IncrementalPasses + 1
+
+	evidence := make(map[uuid.UUID]bool)
+	for _, schema := range schemas {
+		for _, id := range schema.EvidenceMemories {
+			evidence[id] = true
+		}
+	}
+
+	// A memory is new if it crossed the evidence age threshold after the
+	// previous pass and isn't already backing a schema.
+	var fresh []domain.Memory
+	for _, m := range eligible {
+		if evidence[m.ID] || len(m.Embedding) == 0 {
+			continue
+		}
+		if m.CreatedAt.Add(MinEvidenceAge).After(state.LastPassAt) {
+			fresh = append(fresh, m)
+		}
+	}
+	if len(fresh) == 0 {
+		return plan
+	}
+
+	centroids := schemaCentroids(schemas, all)
+	plan.assignments = make(map[uuid.UUID][]domain.Memory)
+	for _, m := range fresh {
+		var bestID uuid.UUID
+		var bestSim float32
+		for _, schema := range schemas {
+			centroid := centroids[schema.ID]
+			if len(centroid) == 0 {
+				continue
+			}
+			if sim := cosineSimilarity(centroid, m.Embedding); sim >= SchemaAssignThreshold && sim > bestSim {
+				bestID, bestSim = schema.ID, sim
+			}
+		}
+		if bestID == uuid.Nil {
+			plan.unassigned = append(plan.unassigned, m)
+			continue
+		}
+		plan.assignments[bestID] = append(plan.assignments[bestID], m)
+	}
+
+	return plan
+}
+
+// collectSchemaInputs streams the agent's memories and keeps only what a pass
+// needs: the eligible ones, and those backing existing schemas (for
+// planSchemaPass's centroids). total counts every memory seen.
+func collectSchemaInputs(
+	ctx context.Context,
+	scanner domain.MemoryScanner,
+	ms domain.MemoryStore,
+	agentID uuid.UUID,
+	schemas []domain.Schema,
+	isEligible func(*domain.Memory) bool,
+) (evidence, eligible []domain.Memory, total int, err error) {
+	backing := make(map[uuid.UUID]bool)
+	for _, schema := range schemas {
+		for _, id := range schema.EvidenceMemories {
+			backing[id] = true
+		}
+	}
+	err = forEachLiveMemory(ctx, scanner, ms, agentID, func(m *domain.Memory) error {
+		total++
+		if backing[m.ID] {
+			evidence = append(evidence, *m)
+		}
+		if isEligible(m) {
+			eligible = append(eligible, *m)
+		}
+		return nil
+	})
+	return evidence, eligible, total, err
+}
+
+// schemaCentroids returns, per schema, the mean embedding of its evidence
+// memories, falling back to the schema's own embedding when none of its
+// evidence is loaded.
+func schemaCentroids(schemas []domain.Schema, memories []domain.Memory) map[uuid.UUID][]float32 {
+	byID := make(map[uuid.UUID]domain.Memory, len(memories))
+	for _, m := range memories {
+		if len(m.Embedding) > 0 {
+			byID[m.ID] = m
+		}
+	}
+
+	centroids := make(map[uuid.UUID][]float32, len(schemas))
+	for _, schema := range schemas {
+		var centroid []float32
+		n := 0
+		for _, id := range schema.EvidenceMemories {
+			m, ok := byID[id]
+			if !ok {
+				continue
+			}
+			n++
+			if centroid == nil {
+				centroid = cloneVector(m.Embedding)
+				continue
+			}
+			centroid = incrementalMean(centroid, m.Embedding, n)
+		}
+		if centroid == nil && len(schema.Embedding) > 0 {
+			centroid = schema.Embedding
+		}
+		centroids[schema.ID] = centroid
+	}
+	return centroids
+}
+
+// memoryCluster wraps memories as a cluster so they can go through the same
+// evidence paths as clustering output.
+func memoryCluster(memories []domain.Memory) domain.MemoryCluster {
+	cluster := domain.MemoryCluster{Memories: memories}
+	for _, m := range memories {
+		cluster.MemoryIDs = append(cluster.MemoryIDs, m.ID)
+	}
+	return cluster
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestPlanSchemaPass_FullWhenDue(t *testing.T) {
+	now := time.Now()
+	agentID, tenantID := uuid.New(), uuid.New()
+	eligible := []domain.Memory{{ID: uuid.New(), Embedding: []float32{1, 0}, CreatedAt: now.Add(-48 * time.Hour)}}
+
+	tests := []struct {
+		name      string
+		state     *domain.SchemaPassState
+		forceFull bool
+		wantFull  bool
+	}{
+		{"no previous pass", nil, false, true},
+		{"forced", &domain.SchemaPassState{LastPassAt: now, LastFullPassAt: now}, true, true},
+		{"interval elapsed", &domain.SchemaPassState{LastPassAt: now, LastFullPassAt: now.Add(-SchemaFullReclusterInterval)}, false, true},
+		{"too many incremental passes", &domain.SchemaPassState{LastPassAt: now, LastFullPassAt: now, IncrementalPasses: SchemaFullReclusterEvery}, false, true},
+		{"recent full pass", &domain.SchemaPassState{LastPassAt: now, LastFullPassAt: now, IncrementalPasses: 1}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planSchemaPass(tt.state, eligible, eligible, nil, agentID, tenantID, tt.forceFull, now)
+			if plan.full != tt.wantFull {
+				t.Fatalf("expected full=%v, got %v", tt.wantFull, plan.full)
+			}
+			if plan.full {
+				if len(plan.unassigned) != len(eligible) || plan.next.IncrementalPasses != 0 || !plan.next.LastFullPassAt.Equal(now) {
+					t.Fatalf("full pass should cluster everything and reset the counter, got %+v", plan)
+				}
+			} else if plan.next.IncrementalPasses != tt.state.IncrementalPasses+1 {
+				t.Fatalf("expected incremental counter %d, got %d", tt.state.IncrementalPasses+1, plan.next.IncrementalPasses)
+			}
+		})
+	}
+}
+
+func TestSchemaService_DetectSchemas_Incremental(t *testing.T) {
+	svc, schemaStore, memoryStore, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+	now := time.Now()
+
+	addMemory := func(embedding []float32, age time.Duration) *domain.Memory {
+		m := &domain.Memory{
+			AgentID:    agentID,
+			TenantID:   tenantID,
+			Content:    "memory",
+			Confidence: 0.9,
+			Embedding:  embedding,
+		}
+		_ = memoryStore.Create(ctx, m)
+		m.CreatedAt = now.Add(-age)
+		return m
+	}
+
+	// An existing schema backed by old memories along the first axis.
+	var evidence []uuid.UUID
+	for i := 0; i < MinClusterSize; i++ {
+		evidence = append(evidence, addMemory([]float32{1, 0.05 * float32(i), 0}, 72*time.Hour).ID)
+	}
+	schema := &domain.Schema{
+		AgentID:          agentID,
+		TenantID:         tenantID,
+		SchemaType:       domain.SchemaTypeUserArchetype,
+		Name:             "Existing",
+		EvidenceMemories: evidence,
+		EvidenceCount:    len(evidence),
+		Confidence:       0.5,
+	}
+	_ = schemaStore.Create(ctx, schema)
+
+	lastPass := now.Add(-2 * time.Hour)
+	_ = schemaStore.RecordPass(ctx, &domain.SchemaPassState{
+		AgentID:           agentID,
+		TenantID:          tenantID,
+		LastPassAt:        lastPass,
+		LastFullPassAt:    lastPass,
+		IncrementalPasses: 0,
+	})
+
+	// Became eligible after the last pass and sits near the schema.
+	near := addMemory([]float32{0.95, 0.1, 0}, 25*time.Hour)
+	// Became eligible after the last pass but unrelated to any schema.
+	far := addMemory([]float32{0, 0, 1}, 25*time.Hour)
+	// Already eligible at the last pass: left for the next full re-cluster.
+	stale := addMemory([]float32{0.9, 0.1, 0}, 96*time.Hour)
+
+	detected, err := svc.DetectSchemas(ctx, agentID, tenantID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(detected) != 1 || detected[0].ID != schema.ID {
+		t.Fatalf("expected the existing schema to be updated, got %d schemas", len(detected))
+	}
+
+	got := schemaStore.schemas[schema.ID]
+	attached := make(map[uuid.UUID]bool)
+	for _, id := range got.EvidenceMemories {
+		attached[id] = true
+	}
+	if !attached[near.ID] {
+		t.Fatal("expected new nearby memory to be attached as evidence")
+	}
+	if attached[far.ID] || attached[stale.ID] {
+		t.Fatal("expected unrelated and previously seen memories to be left alone")
+	}
+
+	state := schemaStore.passStates[agentID]
+	if state.IncrementalPasses != 1 || !state.LastFullPassAt.Equal(lastPass) || !state.LastPassAt.After(lastPass) {
+		t.Fatalf("expected incremental pass to be recorded, got %+v", state)
+	}
+
+	// A forced rebuild re-clusters everything and resets the counter.
+	if _, err := svc.RebuildSchemas(ctx, agentID, tenantID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	state = schemaStore.passStates[agentID]
+	if state.IncrementalPasses != 0 || !state.LastFullPassAt.Equal(state.LastPassAt) {
+		t.Fatalf("expected full pass to be recorded, got %+v", state)
+	}
+}
@@ -13,11 +13,16 @@ import (
 
 // mockSchemaStore implements domain.SchemaStore for testing.
 type mockSchemaStore struct {
-	schemas map[uuid.UUID]*domain.Schema
+	schemas    map[uuid.UUID]*domain.Schema
+	passStates map[uuid.UUID]domain.SchemaPassState
+	similar    []domain.SchemaWithScore // what FindSimilar returns
 }
 
 func newMockSchemaStore() *mockSchemaStore {
-	return &mockSchemaStore{schemas: make(map[uuid.UUID]*domain.Schema)}
+	return &mockSchemaStore{
+		schemas:    make(map[uuid.UUID]*domain.Schema),
+		passStates: make(map[uuid.UUID]domain.SchemaPassState),
+	}
 }
 
 func (m *mockSchemaStore) Create(ctx context.Context, s *domain.Schema) error {
@@ -66,7 +71,13 @@ func (m *mockSchemaStore) Delete(ctx context.Context, id uuid.UUID, tenantID uui
 }
 
 func (m *mockSchemaStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, limit int) ([]domain.SchemaWithScore, error) {
-	return []domain.SchemaWithScore{}, nil
+	results := []domain.SchemaWithScore{}
+	for _, sc := range m.similar {
+		if sc.Score >= threshold && len(results) < limit {
+			results = append(results, sc)
+		}
+	}
+	return results, nil
 }
 
 func (m *mockSchemaStore) AddEvidence(ctx context.Context, id uuid.UUID, memoryID *uuid.UUID, episodeID *uuid.UUID) error {
@@ -134,6 +145,40 @@ func (m *mockSchemaStore) Update(ctx context.Context, s *domain.Schema) error {
 	return nil
 }
 
+func (m *mockSchemaStore) SetParent(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, parentID *uuid.UUID) error {
+	s, ok := m.schemas[id]
+	if !ok || s.TenantID != tenantID {
+		return store.ErrNotFound
+	}
+	if parentID != nil {
+		for cur := parentID; cur != nil; {
+			if *cur == id {
+				return store.ErrCycle
+			}
+			parent, ok := m.schemas[*cur]
+			if !ok {
+				return store.ErrNotFound
+			}
+			cur = parent.ParentID
+		}
+	}
+	s.ParentID = parentID
+	return nil
+}
+
+func (m *mockSchemaStore) GetPassState(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*domain.SchemaPassState, error) {
+	state, ok := m.passStates[agentID]
+	if !ok || state.TenantID != tenantID {
+		return nil, store.ErrNotFound
+	}
+	return &state, nil
+}
+
+func (m *mockSchemaStore) RecordPass(ctx context.Context, state *domain.SchemaPassState) error {
+	m.passStates[state.AgentID] = *state
+	return nil
+}
+
 // mockMemoryStoreForSchema implements domain.MemoryStore for schema testing.
 type mockMemoryStoreForSchema struct {
 	memories map[uuid.UUID]*domain.Memory
@@ -194,6 +239,10 @@ func (m *mockMemoryStoreForSchema) FindSimilar(ctx context.Context, agentID uuid
 	return []domain.MemoryWithScore{}, nil
 }
 
+func (m *mockMemoryStoreForSchema) FindSimilarFiltered(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, filter domain.SimilarityFilter) ([]domain.MemoryWithScore, error) {
+	return m.FindSimilar(ctx, agentID, tenantID, embedding, threshold)
+}
+
 func (m *mockMemoryStoreForSchema) GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType domain.MemoryType, limit int) ([]domain.MemoryWithScore, error) {
 	return []domain.MemoryWithScore{}, nil
 }
@@ -202,6 +251,14 @@ func (m *mockMemoryStoreForSchema) UpdateReinforcement(ctx context.Context, id u
 	return nil
 }
 
+func (m *mockMemoryStoreForSchema) ReinforceBy(ctx context.Context, id uuid.UUID, boost float32) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForSchema) AddEvidence(ctx context.Context, id uuid.UUID, supporting, contradicting float64) error {
+	return nil
+}
+
 func (m *mockMemoryStoreForSchema) UpdateContent(ctx context.Context, id uuid.UUID, content string, embedding []float32) error {
 	return nil
 }
@@ -263,6 +320,18 @@ func (m *mockMemoryStoreForSchema) GetTierCounts(ctx context.Context, agentID uu
 	return nil, nil
 }
 
+func (m *mockMemoryStoreForSchema) ApplyTierTransitions(ctx context.Context, transitions []domain.TierTransition) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForSchema) SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) error {
+	return nil
+}
+
+func (m *mockMemoryStoreForSchema) GetTierHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memoryID *uuid.UUID, since time.Time, limit int) ([]domain.TierTransition, error) {
+	return nil, nil
+}
+
 func (m *mockMemoryStoreForSchema) SetNeedsReview(ctx context.Context, id uuid.UUID, needsReview bool) error {
 	return nil
 }
@@ -635,23 +704,6 @@ func TestAverageVectors(t *testing.T) {
 	}
 }
 
-func TestCalculateInitialConfidence(t *testing.T) {
-	svc, _, _, _, _ := setupSchemaTest()
-
-	// Test minimum cluster size
-	confidence := svc.calculateInitialConfidence(3)
-	expectedMin := float32(0.5 + 3*0.05) // 0.65
-	if confidence != expectedMin {
-		t.Fatalf("expected confidence %f for 3 items, got %f", expectedMin, confidence)
-	}
-
-	// Test large evidence count (should cap at 0.8)
-	confidence = svc.calculateInitialConfidence(100)
-	if confidence != 0.8 {
-		t.Fatalf("expected confidence to cap at 0.8, got %f", confidence)
-	}
-}
-
 func TestScoreContextMatch(t *testing.T) {
 	svc, _, _, _, _ := setupSchemaTest()
 
@@ -689,35 +741,44 @@ func TestScoreTimeMatch(t *testing.T) {
 
 	// Test matching time preference
 	attributes := map[string]any{"time_preference": "night"}
-	score := svc.scoreTimeMatch(attributes, "night")
+	score := svc.scoreTimeMatch(attributes, "night", "")
 	if score != 1.0 {
 		t.Fatalf("expected score 1.0 for matching time preference, got %f", score)
 	}
 
 	// Test non-matching time
-	score = svc.scoreTimeMatch(attributes, "morning")
+	score = svc.scoreTimeMatch(attributes, "morning", "")
 	if score != 0 {
 		t.Fatalf("expected score 0 for non-matching time, got %f", score)
 	}
 
 	// Test work_hours attribute
 	attributes = map[string]any{"work_hours": "late night"}
-	score = svc.scoreTimeMatch(attributes, "night")
+	score = svc.scoreTimeMatch(attributes, "night", "")
 	if score != 0.8 {
 		t.Fatalf("expected score 0.8 for work_hours containing time, got %f", score)
 	}
 
 	// Test nil attributes
-	score = svc.scoreTimeMatch(nil, "night")
+	score = svc.scoreTimeMatch(nil, "night", "")
 	if score != 0 {
 		t.Fatalf("expected score 0 for nil attributes, got %f", score)
 	}
 
 	// Test empty time of day
-	score = svc.scoreTimeMatch(attributes, "")
+	score = svc.scoreTimeMatch(attributes, "", "")
 	if score != 0 {
 		t.Fatalf("expected score 0 for empty time_of_day, got %f", score)
 	}
+
+	// Test day_preference attribute
+	attributes = map[string]any{"day_preference": "Monday"}
+	if score = svc.scoreTimeMatch(attributes, "", "monday"); score != 1.0 {
+		t.Fatalf("expected score 1.0 for matching day preference, got %f", score)
+	}
+	if score = svc.scoreTimeMatch(attributes, "morning", "Tuesday"); score != 0 {
+		t.Fatalf("expected score 0 for non-matching day, got %f", score)
+	}
 }
 
 func (m *mockMemoryStoreForSchema) ListByAgentFiltered(ctx context.Context, agentID, tenantID uuid.UUID, f domain.MemoryFilter, limit, offset int) ([]domain.Memory, int, error) {
@@ -762,3 +823,323 @@ func TestIncrementalMean_DoesNotMutateSeed(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaService_CreateSchema(t *testing.T) {
+	svc, schemaStore, memoryStore, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+
+	mem := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "Prefers terse answers"}
+	_ = memoryStore.Create(ctx, mem)
+
+	schema, err := svc.CreateSchema(ctx, CreateSchemaInput{
+		AgentID:            agentID,
+		TenantID:           tenantID,
+		SchemaType:         domain.SchemaTypeUserArchetype,
+		Name:               "Senior Engineer",
+		Description:        "Experienced developer who wants concise answers",
+		ApplicableContexts: []string{"code_review"},
+		EvidenceMemories:   []uuid.UUID{mem.ID, mem.ID},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(schema.EvidenceMemories) != 1 || schema.EvidenceCount != 1 {
+		t.Fatalf("expected deduplicated evidence, got %v (count %d)", schema.EvidenceMemories, schema.EvidenceCount)
+	}
+	if len(schema.Embedding) == 0 {
+		t.Fatal("expected embedding to be generated")
+	}
+	if _, ok := schemaStore.schemas[schema.ID]; !ok {
+		t.Fatal("expected schema to be persisted")
+	}
+
+	_, err = svc.CreateSchema(ctx, CreateSchemaInput{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		SchemaType: domain.SchemaTypeUserArchetype,
+		Name:       "Senior Engineer",
+	})
+	if err != ErrSchemaExists {
+		t.Fatalf("expected ErrSchemaExists, got %v", err)
+	}
+}
+
+func TestSchemaService_CreateSchema_Validation(t *testing.T) {
+	svc, _, _, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+	tooHigh := float32(1.5)
+
+	tests := []struct {
+		name  string
+		input CreateSchemaInput
+		want  error
+	}{
+		{"invalid type", CreateSchemaInput{AgentID: agentID, TenantID: tenantID, SchemaType: "bogus", Name: "x"}, ErrInvalidSchemaType},
+		{"missing name", CreateSchemaInput{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeCausalModel, Name: "  "}, ErrSchemaNameRequired},
+		{"bad confidence", CreateSchemaInput{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeCausalModel, Name: "x", Confidence: &tooHigh}, ErrInvalidConfidence},
+		{"unknown agent", CreateSchemaInput{AgentID: uuid.New(), TenantID: tenantID, SchemaType: domain.SchemaTypeCausalModel, Name: "x"}, ErrAgentNotFound},
+		{"unknown evidence", CreateSchemaInput{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeCausalModel, Name: "x", EvidenceMemories: []uuid.UUID{uuid.New()}}, ErrEvidenceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateSchema(ctx, tt.input); err != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSchemaService_SeedSchemas_Idempotent(t *testing.T) {
+	svc, schemaStore, _, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+
+	inputs := []CreateSchemaInput{
+		{SchemaType: domain.SchemaTypeUserArchetype, Name: "Night Owl", Description: "Works late"},
+		{SchemaType: domain.SchemaTypeSituationTemplate, Name: "Incident Response"},
+	}
+
+	if _, err := svc.SeedSchemas(ctx, agentID, tenantID, inputs); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	inputs[0].Description = "Works late, prefers async communication"
+	seeded, err := svc.SeedSchemas(ctx, agentID, tenantID, inputs)
+	if err != nil {
+		t.Fatalf("expected no error on reseed, got %v", err)
+	}
+	if len(seeded) != 2 {
+		t.Fatalf("expected 2 seeded schemas, got %d", len(seeded))
+	}
+	if len(schemaStore.schemas) != 2 {
+		t.Fatalf("expected reseed to update in place, got %d schemas", len(schemaStore.schemas))
+	}
+	if seeded[0].Description != inputs[0].Description {
+		t.Fatalf("expected description to be updated, got %q", seeded[0].Description)
+	}
+}
+
+func TestSchemaService_UpdateSchema(t *testing.T) {
+	svc, schemaStore, _, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+
+	schema := &domain.Schema{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		SchemaType: domain.SchemaTypeUserArchetype,
+		Name:       "Beginner",
+		Confidence: 0.6,
+	}
+	_ = schemaStore.Create(ctx, schema)
+
+	name := "Novice Developer"
+	contexts := []string{"onboarding"}
+	updated, err := svc.UpdateSchema(ctx, UpdateSchemaInput{
+		ID:                 schema.ID,
+		TenantID:           tenantID,
+		Name:               &name,
+		ApplicableContexts: &contexts,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Name != name {
+		t.Fatalf("expected name %q, got %q", name, updated.Name)
+	}
+	if len(updated.ApplicableContexts) != 1 || updated.ApplicableContexts[0] != "onboarding" {
+		t.Fatalf("expected contexts to be replaced, got %v", updated.ApplicableContexts)
+	}
+	if len(updated.Embedding) == 0 {
+		t.Fatal("expected embedding to be regenerated after rename")
+	}
+	if updated.Confidence != 0.6 {
+		t.Fatalf("expected confidence to be unchanged, got %f", updated.Confidence)
+	}
+
+	_, err = svc.UpdateSchema(ctx, UpdateSchemaInput{ID: uuid.New(), TenantID: tenantID})
+	if err != ErrSchemaNotFound {
+		t.Fatalf("expected ErrSchemaNotFound, got %v", err)
+	}
+}
+
+func TestSchemaService_AttachEvidence(t *testing.T) {
+	svc, schemaStore, memoryStore, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+
+	schema := &domain.Schema{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		SchemaType: domain.SchemaTypeUserArchetype,
+		Name:       "Power User",
+		Confidence: 0.5,
+	}
+	_ = schemaStore.Create(ctx, schema)
+
+	mem := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "Uses keyboard shortcuts"}
+	_ = memoryStore.Create(ctx, mem)
+
+	updated, err := svc.AttachEvidence(ctx, schema.ID, tenantID, []uuid.UUID{mem.ID}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.EvidenceCount != 1 {
+		t.Fatalf("expected evidence count 1, got %d", updated.EvidenceCount)
+	}
+	if math.Abs(float64(updated.Confidence-0.55)) > 0.001 {
+		t.Fatalf("expected confidence 0.55, got %f", updated.Confidence)
+	}
+
+	// Re-attaching the same memory is a no-op.
+	updated, err = svc.AttachEvidence(ctx, schema.ID, tenantID, []uuid.UUID{mem.ID}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.EvidenceCount != 1 {
+		t.Fatalf("expected evidence count to stay 1, got %d", updated.EvidenceCount)
+	}
+
+	_, err = svc.AttachEvidence(ctx, schema.ID, tenantID, []uuid.UUID{uuid.New()}, nil)
+	if err != ErrEvidenceNotFound {
+		t.Fatalf("expected ErrEvidenceNotFound, got %v", err)
+	}
+}
+
+func TestSchemaService_EvidenceMustBelongToTheSchemasAgent(t *testing.T) {
+	svc, schemaStore, memoryStore, tenantID, agentID := setupSchemaTest()
+	episodeStore := newMockEpisodeStore()
+	svc.SetEpisodeStore(episodeStore)
+	ctx := context.Background()
+
+	otherAgent := uuid.New()
+	otherMem := &domain.Memory{AgentID: otherAgent, TenantID: tenantID, Content: "Another agent's note"}
+	_ = memoryStore.Create(ctx, otherMem)
+	otherEp := &domain.Episode{AgentID: otherAgent, TenantID: tenantID, RawContent: "Another agent's conversation"}
+	_ = episodeStore.Create(ctx, otherEp)
+	ownEp := &domain.Episode{AgentID: agentID, TenantID: tenantID, RawContent: "Our conversation"}
+	_ = episodeStore.Create(ctx, ownEp)
+
+	base := CreateSchemaInput{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeCausalModel, Name: "x"}
+	withMem, withEp := base, base
+	withMem.EvidenceMemories = []uuid.UUID{otherMem.ID}
+	withEp.EvidenceEpisodes = []uuid.UUID{otherEp.ID}
+	for _, in := range []CreateSchemaInput{withMem, withEp} {
+		if _, err := svc.CreateSchema(ctx, in); err != ErrEvidenceNotFound {
+			t.Fatalf("expected ErrEvidenceNotFound for another agent's evidence, got %v", err)
+		}
+	}
+
+	schema := &domain.Schema{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: "Power User", Confidence: 0.5}
+	_ = schemaStore.Create(ctx, schema)
+	if _, err := svc.AttachEvidence(ctx, schema.ID, tenantID, []uuid.UUID{otherMem.ID}, nil); err != ErrEvidenceNotFound {
+		t.Fatalf("expected ErrEvidenceNotFound attaching another agent's memory, got %v", err)
+	}
+	if _, err := svc.AttachEvidence(ctx, schema.ID, tenantID, nil, []uuid.UUID{otherEp.ID}); err != ErrEvidenceNotFound {
+		t.Fatalf("expected ErrEvidenceNotFound attaching another agent's episode, got %v", err)
+	}
+	updated, err := svc.AttachEvidence(ctx, schema.ID, tenantID, nil, []uuid.UUID{ownEp.ID})
+	if err != nil {
+		t.Fatalf("expected the agent's own episode to attach, got %v", err)
+	}
+	if updated.EvidenceCount != 1 {
+		t.Fatalf("expected evidence count 1, got %d", updated.EvidenceCount)
+	}
+}
+
+func TestSchemaService_MatchSchemas_Hierarchy(t *testing.T) {
+	svc, schemaStore, _, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+
+	parent := &domain.Schema{
+		AgentID:    agentID,
+		TenantID:   tenantID,
+		SchemaType: domain.SchemaTypeUserArchetype,
+		Name:       "Developer",
+		Attributes: map[string]any{"time_preference": "night", "technical_level": "intermediate"},
+		Confidence: 1.0,
+	}
+	_ = schemaStore.Create(ctx, parent)
+
+	child, err := svc.CreateSchema(ctx, CreateSchemaInput{
+		AgentID:            agentID,
+		TenantID:           tenantID,
+		SchemaType:         domain.SchemaTypeUserArchetype,
+		Name:               "Frontend Developer",
+		Attributes:         map[string]any{"technical_level": "expert"},
+		ApplicableContexts: []string{"css"},
+		ParentName:         "Developer",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if child.ParentID == nil || *child.ParentID != parent.ID {
+		t.Fatalf("expected parent %s, got %v", parent.ID, child.ParentID)
+	}
+	one := float32(1.0)
+	if _, err := svc.UpdateSchema(ctx, UpdateSchemaInput{ID: child.ID, TenantID: tenantID, Confidence: &one}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	matches, err := svc.MatchSchemas(ctx, SchemaMatchInput{
+		AgentID:       agentID,
+		TenantID:      tenantID,
+		Contexts:      []string{"css"},
+		TimeOfDay:     "night",
+		MinMatchScore: 0.1,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected child and parent to match, got %d matches", len(matches))
+	}
+	if matches[0].Schema.ID != child.ID {
+		t.Fatalf("expected child to rank first, got %s", matches[0].Schema.Name)
+	}
+
+	// Context (0.3) plus inherited time preference (0.2).
+	if math.Abs(float64(matches[0].MatchScore-0.5)) > 0.001 {
+		t.Fatalf("expected child score 0.5, got %f", matches[0].MatchScore)
+	}
+	if got := matches[0].Schema.Attributes["technical_level"]; got != "expert" {
+		t.Fatalf("expected child attribute to override parent, got %v", got)
+	}
+	if got := matches[0].Schema.Attributes["time_preference"]; got != "night" {
+		t.Fatalf("expected inherited time_preference, got %v", got)
+	}
+
+	// The parent only matches through the child: it scores time (0.2) on its
+	// own, but inherits 0.5 * 0.5 = 0.25 from the child.
+	if matches[1].Schema.ID != parent.ID {
+		t.Fatalf("expected parent second, got %s", matches[1].Schema.Name)
+	}
+	if math.Abs(float64(matches[1].MatchScore-0.25)) > 0.001 {
+		t.Fatalf("expected parent score 0.25, got %f", matches[1].MatchScore)
+	}
+}
+
+func TestSchemaService_UpdateSchema_RejectsCycle(t *testing.T) {
+	svc, schemaStore, _, tenantID, agentID := setupSchemaTest()
+	ctx := context.Background()
+
+	root := &domain.Schema{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: "Developer"}
+	_ = schemaStore.Create(ctx, root)
+	mid := &domain.Schema{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: "Frontend Developer", ParentID: &root.ID}
+	_ = schemaStore.Create(ctx, mid)
+	leaf := &domain.Schema{AgentID: agentID, TenantID: tenantID, SchemaType: domain.SchemaTypeUserArchetype, Name: "React Developer", ParentID: &mid.ID}
+	_ = schemaStore.Create(ctx, leaf)
+
+	_, err := svc.UpdateSchema(ctx, UpdateSchemaInput{ID: root.ID, TenantID: tenantID, ParentID: &leaf.ID})
+	if err != ErrSchemaCycle {
+		t.Fatalf("expected ErrSchemaCycle, got %v", err)
+	}
+
+	// Detaching with uuid.Nil clears the parent.
+	detach := uuid.Nil
+	updated, err := svc.UpdateSchema(ctx, UpdateSchemaInput{ID: leaf.ID, TenantID: tenantID, ParentID: &detach})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.ParentID != nil {
+		t.Fatalf("expected parent to be cleared, got %v", updated.ParentID)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Skill pack constants
+const (
+	SkillExportMinSuccessRate = 0.7 // Default success rate a procedure needs to be exported
+	SkillExportMinUses        = 3   // Default uses before a success rate counts as proven
+	// TransferredSkillConfidence scales a skill's confidence on install: it
+	// proved itself with another agent's users, not yet with this one's.
+	TransferredSkillConfidence = 0.8
+)
+
+var (
+	ErrSkillPackEmpty     = errors.New("skill pack contains no skills")
+	ErrSkillPackVersion   = fmt.Errorf("unsupported skill pack format_version (want %d)", domain.SkillPackFormatVersion)
+	ErrInvalidSkillStats  = errors.New("skill stats must be non-negative and successes plus failures must not exceed uses")
+	ErrInvalidSkillExport = errors.New("min_success_rate must be within [0, 1] and min_uses must not be negative")
+)
+
+// SkillExportOptions selects the procedures that go into a skill pack. Zero
+// values take the SkillExport defaults.
+type SkillExportOptions struct {
+	Name           string
+	MinSuccessRate float32
+	MinUses        int
+}
+
+// SkillImportResult reports an install: procedures created, and skills
+// skipped because the agent already has a procedure with a similar trigger.
+type SkillImportResult struct {
+	Installed []domain.Procedure `json:"installed"`
+	Skipped   int                `json:"skipped"`
+}
+
+// ExportSkills packs the agent's live procedures with a proven success rate,
+// best first.
+func (s *ProceduralService) ExportSkills(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, opts SkillExportOptions) (*domain.SkillPack, error) {
+	if opts.MinSuccessRate < 0 || opts.MinSuccessRate > 1 || opts.MinUses < 0 {
+		return nil, ErrInvalidSkillExport
+	}
+	if opts.MinSuccessRate == 0 {
+		opts.MinSuccessRate = SkillExportMinSuccessRate
+	}
+	if opts.MinUses == 0 {
+		opts.MinUses = SkillExportMinUses
+	}
+	agent, err := s.agentStore.GetByID(ctx, agentID, tenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+	if opts.Name == "" {
+		opts.Name = agent.Name
+	}
+
+	procedures, err := s.procedureStore.GetByAgent(ctx, agentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(procedures, func(i, j int) bool {
+		return procedures[i].SuccessRate*procedures[i].Confidence > procedures[j].SuccessRate*procedures[j].Confidence
+	})
+
+	pack := &domain.SkillPack{
+		FormatVersion: domain.SkillPackFormatVersion,
+		Name:          opts.Name,
+		SourceAgentID: agentID,
+		ExportedAt:    timeNow(),
+		Skills:        []domain.Skill{},
+	}
+	for _, p := range procedures {
+		if p.MemoryStrength <= 0 || p.UseCount < opts.MinUses || p.SuccessRate < opts.MinSuccessRate {
+			continue
+		}
+		pack.Skills = append(pack.Skills, domain.Skill{
+			TriggerPattern:  p.TriggerPattern,
+			TriggerKeywords: p.TriggerKeywords,
+			Conditions:      p.Conditions,
+			ActionTemplate:  p.ActionTemplate,
+			ActionType:      p.ActionType,
+			Examples:        p.ExampleExchanges,
+			Stats: domain.SkillStats{
+				UseCount:     p.UseCount,
+				SuccessCount: p.SuccessCount,
+				FailureCount: p.FailureCount,
+				SuccessRate:  p.SuccessRate,
+				Confidence:   p.Confidence,
+			},
+		})
+	}
+	return pack, nil
+}
+
+// ValidateSkill checks a skill of a pack before anything is installed.
+func ValidateSkill(sk domain.Skill) error {
+	st := sk.Stats
+	if st.UseCount < 0 || st.SuccessCount < 0 || st.FailureCount < 0 || st.SuccessCount+st.FailureCount > st.UseCount {
+		return ErrInvalidSkillStats
+	}
+	return ValidateSeedProcedure(domain.SeedProcedure{
+		TriggerPattern: sk.TriggerPattern,
+		Conditions:     sk.Conditions,
+		ActionTemplate: sk.ActionTemplate,
+		ActionType:     sk.ActionType,
+		Confidence:     st.Confidence,
+	})
+}
+
+// ImportSkills installs a skill pack into an agent. Each skill keeps its
+// usage statistics, is marked as transferred from the pack and starts at a
+// discounted confidence. Skills whose trigger matches one the agent already
+// has are skipped, so installing a pack twice is safe.
+func (s *ProceduralService) ImportSkills(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, pack domain.SkillPack) (*SkillImportResult, error) {
+	if pack.FormatVersion != domain.SkillPackFormatVersion {
+		return nil, ErrSkillPackVersion
+	}
+	if len(pack.Skills) == 0 {
+		return nil, ErrSkillPackEmpty
+	}
+	for i, sk := range pack.Skills {
+		if err := ValidateSkill(sk); err != nil {
+			return nil, &SeedValidationError{Section: "skills", Index: i, Err: err}
+		}
+	}
+	if _, err := s.agentStore.GetByID(ctx, agentID, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+
+	result := &SkillImportResult{Installed: []domain.Procedure{}}
+	for _, sk := range pack.Skills {
+		embedding, known, err := s.knownTrigger(ctx, agentID, tenantID, sk.TriggerPattern)
+		if err != nil {
+			return nil, err
+		}
+		if known {
+			result.Skipped++
+			continue
+		}
+
+		confidence := sk.Stats.Confidence
+		if confidence == 0 {
+			confidence = NewProcedureInitialConfidence
+		}
+		now := timeNow()
+		procedure := &domain.Procedure{
+			AgentID:             agentID,
+			TenantID:            tenantID,
+			TriggerPattern:      sk.TriggerPattern,
+			TriggerKeywords:     sk.TriggerKeywords,
+			TriggerEmbedding:    embedding,
+			Conditions:          sk.Conditions,
+			ActionTemplate:      sk.ActionTemplate,
+			ActionType:          sk.ActionType,
+			UseCount:            sk.Stats.UseCount,
+			SuccessCount:        sk.Stats.SuccessCount,
+			FailureCount:        sk.Stats.FailureCount,
+			DerivedFromEpisodes: []uuid.UUID{},
+			ExampleExchanges:    sk.Examples,
+			Confidence:          confidence * TransferredSkillConfidence,
+			MemoryStrength:      1.0,
+			LastVerifiedAt:      &now,
+			Source:              domain.ProcedureSourceTransferred,
+			SourcePack:          pack.Name,
+		}
+		if err := s.procedureStore.Create(ctx, procedure); err != nil {
+			return nil, err
+		}
+		if procedure.UseCount > 0 {
+			procedure.SuccessRate = float32(procedure.SuccessCount) / float32(procedure.UseCount)
+		}
+		result.Installed = append(result.Installed, *procedure)
+	}
+
+	logFor(ctx, s.logger).Info("installed skill pack",
+		zap.String("agent_id", agentID.String()),
+		zap.String("pack", pack.Name),
+		zap.Int("installed", len(result.Installed)),
+		zap.Int("skipped", result.Skipped))
+	return result, nil
+}
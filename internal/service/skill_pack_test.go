@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+func TestProceduralService_ExportImportSkills(t *testing.T) {
+	svc, procedureStore, _, tenantID, sourceID := setupProceduralTest()
+	ctx := context.Background()
+
+	proven := &domain.Procedure{
+		AgentID: sourceID, TenantID: tenantID,
+		TriggerPattern: "User reports a failed deploy", ActionTemplate: "Ask for the deploy ID first",
+		ActionType: domain.ActionTypeProblemSolving,
+		UseCount:   10, SuccessCount: 9, FailureCount: 1, SuccessRate: 0.9,
+		Confidence: 0.9, MemoryStrength: 1,
+	}
+	unproven := &domain.Procedure{
+		AgentID: sourceID, TenantID: tenantID,
+		TriggerPattern: "User asks for a joke", ActionTemplate: "Tell a pun",
+		ActionType: domain.ActionTypeCommunication,
+		UseCount:   1, SuccessCount: 1, SuccessRate: 1,
+		Confidence: 0.6, MemoryStrength: 1,
+	}
+	_ = procedureStore.Create(ctx, proven)
+	_ = procedureStore.Create(ctx, unproven)
+
+	pack, err := svc.ExportSkills(ctx, sourceID, tenantID, SkillExportOptions{Name: "deploy-support"})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if pack.FormatVersion != domain.SkillPackFormatVersion || pack.Name != "deploy-support" || pack.SourceAgentID != sourceID {
+		t.Errorf("unexpected pack header %+v", pack)
+	}
+	if len(pack.Skills) != 1 || pack.Skills[0].TriggerPattern != proven.TriggerPattern {
+		t.Fatalf("expected only the proven procedure, got %+v", pack.Skills)
+	}
+	if pack.Skills[0].Stats.SuccessCount != 9 {
+		t.Errorf("expected stats to travel with the skill, got %+v", pack.Skills[0].Stats)
+	}
+
+	target := &domain.Agent{TenantID: tenantID, ExternalID: "bot-2", Name: "Target"}
+	_ = svc.agentStore.Create(ctx, target)
+
+	result, err := svc.ImportSkills(ctx, target.ID, tenantID, *pack)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Installed) != 1 || result.Skipped != 0 {
+		t.Fatalf("expected one installed skill, got %+v", result)
+	}
+	installed := result.Installed[0]
+	if installed.Source != domain.ProcedureSourceTransferred || installed.SourcePack != "deploy-support" {
+		t.Errorf("expected transferred provenance, got source %q pack %q", installed.Source, installed.SourcePack)
+	}
+	if installed.AgentID != target.ID || installed.SuccessCount != 9 || installed.SuccessRate != 0.9 {
+		t.Errorf("unexpected installed procedure %+v", installed)
+	}
+	if want := float32(0.9) * TransferredSkillConfidence; installed.Confidence != want {
+		t.Errorf("expected discounted confidence %v, got %v", want, installed.Confidence)
+	}
+}
+
+func TestProceduralService_ImportSkills_Invalid(t *testing.T) {
+	svc, _, _, tenantID, agentID := setupProceduralTest()
+	ctx := context.Background()
+
+	if _, err := svc.ImportSkills(ctx, agentID, tenantID, domain.SkillPack{FormatVersion: 99}); !errors.Is(err, ErrSkillPackVersion) {
+		t.Errorf("expected ErrSkillPackVersion, got %v", err)
+	}
+	if _, err := svc.ImportSkills(ctx, agentID, tenantID, domain.SkillPack{FormatVersion: domain.SkillPackFormatVersion}); !errors.Is(err, ErrSkillPackEmpty) {
+		t.Errorf("expected ErrSkillPackEmpty, got %v", err)
+	}
+	pack := domain.SkillPack{FormatVersion: domain.SkillPackFormatVersion, Skills: []domain.Skill{{
+		TriggerPattern: "t", ActionTemplate: "a", ActionType: domain.ActionTypeWorkflow,
+		Stats: domain.SkillStats{UseCount: 1, SuccessCount: 2},
+	}}}
+	var invalid *SeedValidationError
+	if _, err := svc.ImportSkills(ctx, agentID, tenantID, pack); !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidSkillStats) {
+		t.Errorf("expected skills[0] stats error, got %v", err)
+	}
+}
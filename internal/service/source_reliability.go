@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	sourceReliabilityCacheTTL    = time.Minute
+	sourceReliabilityCacheAgents = 1024 // Agents cached before the cache is reset
+)
+
+// defaultSourceReliability is the reliability a source starts from before the
+// agent has observed any of its outcomes.
+func defaultSourceReliability(source domain.BeliefSource) float64 {
+	switch source {
+	case domain.SourceUserStatement:
+		return SourceReliabilityUserStatement
+	case domain.SourceExtraction:
+		return SourceReliabilityExtraction
+	case domain.SourceAgentInference:
+		return SourceReliabilityAgentInference
+	case domain.SourceToolOutput:
+		return SourceReliabilityToolOutput
+	default:
+		return SourceReliabilityDefault
+	}
+}
+
+// SourceReliabilityService learns, per agent, how often each belief source is
+// right. A nil service reports the default reliabilities and records nothing,
+// so services that take one treat it as optional.
+type SourceReliabilityService struct {
+	store  domain.SourceReliabilityStore
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedSourceReliability
+}
+
+type cachedSourceReliability struct {
+	loadedAt time.Time
+	sources  map[domain.BeliefSource]domain.SourceReliability
+}
+
+func NewSourceReliabilityService(st domain.SourceReliabilityStore, logger *zap.Logger) *SourceReliabilityService {
+	return &SourceReliabilityService{
+		store:  st,
+		logger: logger,
+		cache:  make(map[uuid.UUID]cachedSourceReliability),
+	}
+}
+
+// Reliability returns the agent's learned reliability for source, or the
+// source's default when nothing has been observed or the lookup fails. An
+// unclassified source ("") is never learned.
+func (s *SourceReliabilityService) Reliability(ctx context.Context, agentID, tenantID uuid.UUID, source domain.BeliefSource) float64 {
+	prior := defaultSourceReliability(source)
+	if s == nil || source == "" {
+		return prior
+	}
+	r, ok := s.load(ctx, agentID, tenantID)[source]
+	if !ok {
+		return prior
+	}
+	return r.Reliability(prior)
+}
+
+// List returns the agent's observed sources with their learned reliability.
+func (s *SourceReliabilityService) List(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.SourceReliability, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return s.store.ListByAgent(ctx, agentID, tenantID)
+}
+
+// RecordOutcome counts one confirmation (correct) or refutation of m against
+// its source. Reliability refines assessments rather than drives writes, so a
+// failure is logged and not returned.
+func (s *SourceReliabilityService) RecordOutcome(ctx context.Context, m *domain.Memory, correct bool) {
+	if s == nil {
+		return
+	}
+	source := domain.SourceOf(m)
+	if source == "" || m.AgentID == uuid.Nil {
+		return
+	}
+	var ok, bad float64 = 1, 0
+	if !correct {
+		ok, bad = 0, 1
+	}
+	if err := s.store.Record(ctx, m.AgentID, m.TenantID, source, ok, bad); err != nil {
+		logFor(ctx, s.logger).Warn("failed to record source outcome",
+			zap.String("agent_id", m.AgentID.String()),
+			zap.String("source", string(source)), zap.Error(err))
+		return
+	}
+	s.mu.Lock()
+	delete(s.cache, m.AgentID)
+	s.mu.Unlock()
+}
+
+// extractionDiscount scales base, the discount applied to extracted beliefs,
+// by how the agent's extractions have fared against the default extraction
+// reliability.
+func (s *SourceReliabilityService) extractionDiscount(ctx context.Context, agentID, tenantID uuid.UUID, base float32) float32 {
+	r := s.Reliability(ctx, agentID, tenantID, domain.SourceExtraction)
+	return base * float32(r/SourceReliabilityExtraction)
+}
+
+func (s *SourceReliabilityService) load(ctx context.Context, agentID, tenantID uuid.UUID) map[domain.BeliefSource]domain.SourceReliability {
+	now := timeNow()
+	s.mu.Lock()
+	if c, ok := s.cache[agentID]; ok && now.Sub(c.loadedAt) < sourceReliabilityCacheTTL {
+		s.mu.Unlock()
+		return c.sources
+	}
+	s.mu.Unlock()
+
+	rows, err := s.store.ListByAgent(ctx, agentID, tenantID)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to load source reliability", zap.Error(err))
+		return nil
+	}
+	sources := make(map[domain.BeliefSource]domain.SourceReliability, len(rows))
+	for _, r := range rows {
+		sources[r.Source] = r
+	}
+
+	s.mu.Lock()
+	if len(s.cache) >= sourceReliabilityCacheAgents {
+		s.cache = make(map[uuid.UUID]cachedSourceReliability)
+	}
+	s.cache[agentID] = cachedSourceReliability{loadedAt: now, sources: sources}
+	s.mu.Unlock()
+	return sources
+}
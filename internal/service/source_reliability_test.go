@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type mockSourceReliabilityStore struct {
+	rows  map[uuid.UUID]map[domain.BeliefSource]*domain.SourceReliability
+	lists int
+}
+
+func newMockSourceReliabilityStore() *mockSourceReliabilityStore {
+	return &mockSourceReliabilityStore{rows: make(map[uuid.UUID]map[domain.BeliefSource]*domain.SourceReliability)}
+}
+
+func (m *mockSourceReliabilityStore) Record(_ context.Context, agentID, tenantID uuid.UUID, source domain.BeliefSource, correct, incorrect float64) error {
+	if m.rows[agentID] == nil {
+		m.rows[agentID] = make(map[domain.BeliefSource]*domain.SourceReliability)
+	}
+	r := m.rows[agentID][source]
+	if r == nil {
+		r = &domain.SourceReliability{AgentID: agentID, TenantID: tenantID, Source: source}
+		m.rows[agentID][source] = r
+	}
+	r.Correct += correct
+	r.Incorrect += incorrect
+	return nil
+}
+
+func (m *mockSourceReliabilityStore) ListByAgent(_ context.Context, agentID, _ uuid.UUID) ([]domain.SourceReliability, error) {
+	m.lists++
+	var out []domain.SourceReliability
+	for _, r := range m.rows[agentID] {
+		out = append(out, *r)
+	}
+	return out, nil
+}
+
+func TestSourceReliabilityService_NilUsesDefaults(t *testing.T) {
+	var svc *SourceReliabilityService
+	ctx := context.Background()
+
+	if got := svc.Reliability(ctx, uuid.New(), uuid.New(), domain.SourceToolOutput); got != SourceReliabilityToolOutput {
+		t.Errorf("expected the tool-output default, got %f", got)
+	}
+	if got := svc.extractionDiscount(ctx, uuid.New(), uuid.New(), ExtractionConfidenceDiscount); got != ExtractionConfidenceDiscount {
+		t.Errorf("expected the base discount, got %f", got)
+	}
+	svc.RecordOutcome(ctx, &domain.Memory{AgentID: uuid.New(), Source: string(domain.SourceExtraction)}, true)
+}
+
+func TestSourceReliabilityService_LearnsFromOutcomes(t *testing.T) {
+	st := newMockSourceReliabilityStore()
+	svc := NewSourceReliabilityService(st, zap.NewNop())
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	extracted := &domain.Memory{AgentID: agentID, TenantID: tenantID, Source: "episode:" + uuid.NewString()}
+
+	before := svc.Reliability(ctx, agentID, tenantID, domain.SourceExtraction)
+	for i := 0; i < 10; i++ {
+		svc.RecordOutcome(ctx, extracted, false)
+	}
+	// (0.8 * 10 + 0) / (10 + 10)
+	after := svc.Reliability(ctx, agentID, tenantID, domain.SourceExtraction)
+	if math.Abs(after-0.4) > 1e-9 {
+		t.Fatalf("expected 0.4 after ten refutations, got %f (was %f)", after, before)
+	}
+	if got := svc.extractionDiscount(ctx, agentID, tenantID, ExtractionConfidenceDiscount); math.Abs(float64(got)-0.4) > 1e-6 {
+		t.Errorf("expected the extraction discount to halve to 0.4, got %f", got)
+	}
+	if got := svc.Reliability(ctx, uuid.New(), tenantID, domain.SourceExtraction); got != SourceReliabilityExtraction {
+		t.Errorf("expected another agent to keep the default, got %f", got)
+	}
+}
+
+func TestSourceReliabilityService_CachesUntilRecorded(t *testing.T) {
+	st := newMockSourceReliabilityStore()
+	svc := NewSourceReliabilityService(st, zap.NewNop())
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+
+	svc.Reliability(ctx, agentID, tenantID, domain.SourceUserStatement)
+	svc.Reliability(ctx, agentID, tenantID, domain.SourceToolOutput)
+	if st.lists != 1 {
+		t.Fatalf("expected one load for repeated lookups, got %d", st.lists)
+	}
+
+	svc.RecordOutcome(ctx, &domain.Memory{AgentID: agentID, TenantID: tenantID, Provenance: domain.ProvenanceTool}, false)
+	if got := svc.Reliability(ctx, agentID, tenantID, domain.SourceToolOutput); got >= SourceReliabilityToolOutput {
+		t.Errorf("expected the refutation to be visible immediately, got %f", got)
+	}
+	if st.lists != 2 {
+		t.Errorf("expected a reload after recording, got %d loads", st.lists)
+	}
+}
+
+func TestFeedbackService_Create_RecordsSourceOutcome(t *testing.T) {
+	svc, _, memStore, tenantID, agentID := setupFeedbackTest()
+	st := newMockSourceReliabilityStore()
+	svc.SetSourceReliability(NewSourceReliabilityService(st, zap.NewNop()))
+	ctx := context.Background()
+
+	mem := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "test", Type: domain.MemoryTypeFact, Source: string(domain.SourceAgentInference)}
+	_ = memStore.Create(ctx, mem)
+
+	for _, signal := range []domain.FeedbackType{domain.FeedbackTypeHelpful, domain.FeedbackTypeContradicted, domain.FeedbackTypeIgnored} {
+		if err := svc.Create(ctx, &domain.Feedback{MemoryID: mem.ID, AgentID: agentID, SignalType: signal}, tenantID); err != nil {
+			t.Fatalf("%s: %v", signal, err)
+		}
+	}
+
+	r := st.rows[agentID][domain.SourceAgentInference]
+	if r == nil || r.Correct != 1 || r.Incorrect != 1 {
+		t.Fatalf("expected one confirmation and one refutation, got %+v", r)
+	}
+}
+
+func TestMetacognitiveService_AssessConfidence_UsesLearnedReliability(t *testing.T) {
+	svc, _, _, _, _, _, _ := setupMetacognitiveTest()
+	st := newMockSourceReliabilityStore()
+	svc.SetSourceReliability(NewSourceReliabilityService(st, zap.NewNop()))
+	ctx := context.Background()
+	agentID := uuid.New()
+	_ = st.Record(ctx, agentID, uuid.Nil, domain.SourceToolOutput, 0, 10)
+
+	mem := domain.Memory{ID: uuid.New(), AgentID: agentID, Confidence: 0.8, Source: string(domain.SourceToolOutput)}
+	assessment, err := svc.AssessConfidence(ctx, mem)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// (0.9 * 10 + 0) / (10 + 10)
+	if got := assessment.Factors["source"]; math.Abs(float64(got)-0.45) > 1e-6 {
+		t.Errorf("expected the learned reliability 0.45, got %f", got)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var ErrAnchorNotFound = errors.New("anchor not found")
+
+// SubjectAnchorStore loads anchors and the other entities a subject's memories mention.
+type SubjectAnchorStore interface {
+	GetAnchor(ctx context.Context, id, tenantID uuid.UUID) (*domain.Entity, error)
+	ListMentionedIn(ctx context.Context, memoryIDs []uuid.UUID, exclude uuid.UUID) ([]domain.Entity, error)
+}
+
+// SubjectSessionStore lists an anchor's sessions.
+type SubjectSessionStore interface {
+	ListByAnchor(ctx context.Context, anchorID, tenantID uuid.UUID) ([]domain.Session, error)
+}
+
+// SubjectMemoryStore lists every memory about an anchor.
+type SubjectMemoryStore interface {
+	ListForSubject(ctx context.Context, anchorID, tenantID uuid.UUID) ([]domain.SubjectMemory, error)
+}
+
+// SubjectEpisodeStore lists the episodes behind a subject's sessions and memories.
+type SubjectEpisodeStore interface {
+	ListForSubject(ctx context.Context, tenantID uuid.UUID, conversationIDs, memoryIDs []uuid.UUID) ([]domain.Episode, error)
+}
+
+// SubjectExportService gathers everything stored about one end-user, to
+// answer a right-of-access (DSAR) request. It is the read-side counterpart of
+// the anchor purge.
+type SubjectExportService struct {
+	anchors  SubjectAnchorStore
+	sessions SubjectSessionStore
+	memories SubjectMemoryStore
+	episodes SubjectEpisodeStore
+	logger   *zap.Logger
+}
+
+// NewSubjectExportService creates a new subject export service.
+func NewSubjectExportService(anchors SubjectAnchorStore, sessions SubjectSessionStore, memories SubjectMemoryStore, episodes SubjectEpisodeStore, logger *zap.Logger) *SubjectExportService {
+	return &SubjectExportService{anchors: anchors, sessions: sessions, memories: memories, episodes: episodes, logger: logger}
+}
+
+// Export collects the anchor, its sessions, the memories bound to it, to its
+// sessions or mentioning it, the episodes recorded in those sessions or that
+// those memories were derived from, and the other entities they mention.
+func (s *SubjectExportService) Export(ctx context.Context, anchorID, tenantID uuid.UUID) (*domain.SubjectExport, error) {
+	anchor, err := s.anchors.GetAnchor(ctx, anchorID, tenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAnchorNotFound
+		}
+		return nil, err
+	}
+
+	sessions, err := s.sessions.ListByAnchor(ctx, anchorID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	memories, err := s.memories.ListForSubject(ctx, anchorID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIDs := make([]uuid.UUID, len(sessions))
+	for i := range sessions {
+		sessionIDs[i] = sessions[i].ID
+	}
+	memoryIDs := make([]uuid.UUID, len(memories))
+	for i := range memories {
+		memoryIDs[i] = memories[i].ID
+	}
+
+	episodes, err := s.episodes.ListForSubject(ctx, tenantID, sessionIDs, memoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := s.anchors.ListMentionedIn(ctx, memoryIDs, anchorID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.SubjectExport{
+		Anchor:     anchor,
+		Sessions:   sessions,
+		Memories:   memories,
+		Episodes:   episodes,
+		Entities:   entities,
+		ExportedAt: time.Now().UTC(),
+	}
+	if export.Sessions == nil {
+		export.Sessions = []domain.Session{}
+	}
+	if export.Memories == nil {
+		export.Memories = []domain.SubjectMemory{}
+	}
+	if export.Episodes == nil {
+		export.Episodes = []domain.Episode{}
+	}
+	if export.Entities == nil {
+		export.Entities = []domain.Entity{}
+	}
+
+	logFor(ctx, s.logger).Info("subject export",
+		zap.String("anchor_id", anchorID.String()),
+		zap.Int("sessions", len(export.Sessions)),
+		zap.Int("memories", len(export.Memories)),
+		zap.Int("episodes", len(export.Episodes)),
+		zap.Int("entities", len(export.Entities)))
+
+	return export, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+)
+
+type fakeSubjectStores struct {
+	anchor   *domain.Entity
+	sessions []domain.Session
+	memories []domain.SubjectMemory
+	episodes []domain.Episode
+	entities []domain.Entity
+
+	episodeConversations []uuid.UUID
+	episodeMemories      []uuid.UUID
+}
+
+func (f *fakeSubjectStores) GetAnchor(ctx context.Context, id, tenantID uuid.UUID) (*domain.Entity, error) {
+	if f.anchor == nil || f.anchor.ID != id || f.anchor.TenantID != tenantID {
+		return nil, store.ErrNotFound
+	}
+	return f.anchor, nil
+}
+
+func (f *fakeSubjectStores) ListMentionedIn(ctx context.Context, memoryIDs []uuid.UUID, exclude uuid.UUID) ([]domain.Entity, error) {
+	return f.entities, nil
+}
+
+func (f *fakeSubjectStores) ListByAnchor(ctx context.Context, anchorID, tenantID uuid.UUID) ([]domain.Session, error) {
+	return f.sessions, nil
+}
+
+type fakeSubjectMemories struct{ *fakeSubjectStores }
+
+func (f fakeSubjectMemories) ListForSubject(ctx context.Context, anchorID, tenantID uuid.UUID) ([]domain.SubjectMemory, error) {
+	return f.memories, nil
+}
+
+type fakeSubjectEpisodes struct{ *fakeSubjectStores }
+
+func (f fakeSubjectEpisodes) ListForSubject(ctx context.Context, tenantID uuid.UUID, conversationIDs, memoryIDs []uuid.UUID) ([]domain.Episode, error) {
+	f.episodeConversations = conversationIDs
+	f.episodeMemories = memoryIDs
+	return f.episodes, nil
+}
+
+func newSubjectExportFixture() (*SubjectExportService, *fakeSubjectStores) {
+	f := &fakeSubjectStores{}
+	svc := NewSubjectExportService(f, f, fakeSubjectMemories{f}, fakeSubjectEpisodes{f}, testLogger())
+	return svc, f
+}
+
+func TestSubjectExportService_Export(t *testing.T) {
+	svc, f := newSubjectExportFixture()
+	tenantID := uuid.New()
+	f.anchor = &domain.Entity{ID: uuid.New(), TenantID: tenantID, Name: "Guest 42", IsAnchor: true, ExternalID: "guest-42"}
+	session := domain.Session{ID: uuid.New(), AnchorID: &f.anchor.ID}
+	f.sessions = []domain.Session{session}
+	anchored := domain.SubjectMemory{Memory: domain.Memory{ID: uuid.New(), Content: "Guest is vegetarian", AnchorID: &f.anchor.ID}}
+	archived := domain.SubjectMemory{Memory: domain.Memory{ID: uuid.New(), Content: "Guest asked for a late checkout", SessionID: &session.ID}, Archived: true}
+	f.memories = []domain.SubjectMemory{anchored, archived}
+	f.episodes = []domain.Episode{{ID: uuid.New(), RawContent: "I'm vegetarian", ConversationID: &session.ID}}
+	f.entities = []domain.Entity{{ID: uuid.New(), Name: "Hotel Aurora"}}
+
+	export, err := svc.Export(context.Background(), f.anchor.ID, tenantID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if export.Anchor.ExternalID != "guest-42" || len(export.Sessions) != 1 || len(export.Memories) != 2 || len(export.Episodes) != 1 || len(export.Entities) != 1 {
+		t.Fatalf("unexpected export: %+v", export)
+	}
+	if !export.Memories[1].Archived {
+		t.Fatal("expected archived memories to be exported and flagged")
+	}
+	if len(f.episodeConversations) != 1 || f.episodeConversations[0] != session.ID || len(f.episodeMemories) != 2 {
+		t.Fatalf("expected episodes looked up by session and memory IDs, got %v / %v", f.episodeConversations, f.episodeMemories)
+	}
+	if export.ExportedAt.IsZero() {
+		t.Fatal("expected an export timestamp")
+	}
+}
+
+func TestSubjectExportService_EmptySubject(t *testing.T) {
+	svc, f := newSubjectExportFixture()
+	tenantID := uuid.New()
+	f.anchor = &domain.Entity{ID: uuid.New(), TenantID: tenantID, Name: "Walk-in"}
+
+	export, err := svc.Export(context.Background(), f.anchor.ID, tenantID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if export.Sessions == nil || export.Memories == nil || export.Episodes == nil || export.Entities == nil {
+		t.Fatal("expected empty lists rather than nulls")
+	}
+}
+
+func TestSubjectExportService_AnchorNotFound(t *testing.T) {
+	svc, f := newSubjectExportFixture()
+	f.anchor = &domain.Entity{ID: uuid.New(), TenantID: uuid.New()}
+
+	if _, err := svc.Export(context.Background(), f.anchor.ID, uuid.New()); !errors.Is(err, ErrAnchorNotFound) {
+		t.Fatalf("expected ErrAnchorNotFound for another tenant's anchor, got %v", err)
+	}
+}
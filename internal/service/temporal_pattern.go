@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTemporalPatternInterval = 24 * time.Hour
+	TemporalPatternWindow          = 90 * 24 * time.Hour // Episodes mined for patterns
+	MinTemporalPatternEpisodes     = 3                   // Episodes of a subject needed in one slot
+	MinTemporalPatternShare        = 0.5                 // Share of a subject's episodes falling in the slot
+	MinTemporalPatternLift         = 1.5                 // How much likelier the slot is for the subject than for the agent overall
+	TemporalPatternValence         = 0.3                 // |valence| at which an episode counts toward a mood
+	TemporalPatternRetireDecay     = 0.5                 // Confidence kept per run by a pattern that no longer holds
+	MinTemporalPatternConfidence   = 0.2                 // Confidence below which a pattern that no longer holds is deleted
+	maxTemporalPatternEvidence     = 10                  // Most recent episodes kept as evidence
+)
+
+// Attributes of a mined temporal pattern schema. time_preference and
+// day_preference are what scoreTimeMatch matches against.
+const (
+	temporalPatternKind = "temporal"
+	slotTimeOfDay       = "time_preference"
+	slotDayOfWeek       = "day_preference"
+)
+
+// TemporalPatternService mines an agent's episodes for subjects that recur at
+// a time of day or on a day of week — the user asks about reports on Mondays,
+// frustration spikes at night — and keeps each as a situation_template schema
+// whose time attributes let schema matching favour it at that time. Each run
+// recomputes the patterns from scratch: a pattern's evidence and confidence
+// are overwritten, and a pattern that no longer holds fades and is deleted.
+type TemporalPatternService struct {
+	counter     domain.EpisodeSlotCounter
+	schemas     SchemaSeeder
+	schemaStore domain.SchemaStore
+	agents      domain.AgentRegistry
+	logger      *zap.Logger
+	interval    time.Duration
+}
+
+func NewTemporalPatternService(counter domain.EpisodeSlotCounter, schemas SchemaSeeder, schemaStore domain.SchemaStore, agents domain.AgentRegistry, logger *zap.Logger) *TemporalPatternService {
+	return &TemporalPatternService{
+		counter:     counter,
+		schemas:     schemas,
+		schemaStore: schemaStore,
+		agents:      agents,
+		logger:      logger,
+		interval:    defaultTemporalPatternInterval,
+	}
+}
+
+// ScheduledTask returns the daily mining pass over every agent with episodes.
+func (s *TemporalPatternService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "temporal-patterns", Interval: s.interval, Timeout: 30 * time.Minute, Run: s.mineAllAgents}
+}
+
+func (s *TemporalPatternService) mineAllAgents(ctx context.Context) error {
+	agents, err := s.agents.ListWithWork(ctx, domain.AgentWorkFilter{LiveEpisodes: true})
+	if err != nil {
+		return fmt.Errorf("list agents for temporal patterns: %w", err)
+	}
+	for _, agent := range agents {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var schemas []domain.Schema
+		var err error
+		guardPanic(s.logger, "temporal patterns agent "+agent.ID.String(), func() {
+			schemas, err = s.MinePatterns(ctx, agent.ID, agent.TenantID)
+		})
+		if err != nil {
+			logFor(ctx, s.logger).Error("temporal pattern mining failed",
+				zap.String("agent_id", agent.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		if len(schemas) > 0 {
+			logFor(ctx, s.logger).Info("mined temporal patterns",
+				zap.String("agent_id", agent.ID.String()),
+				zap.Int("patterns", len(schemas)))
+		}
+	}
+	return nil
+}
+
+// MinePatterns looks for temporal patterns in the agent's episodes of the
+// last TemporalPatternWindow, creates or overwrites a schema for each, and
+// retires the previously mined patterns that no longer hold.
+func (s *TemporalPatternService) MinePatterns(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.Schema, error) {
+	now := timeNow()
+	counts, err := s.counter.CountEpisodeSlots(ctx, agentID, tenantID, now.Add(-TemporalPatternWindow), TemporalPatternValence, maxTemporalPatternEvidence)
+	if err != nil {
+		return nil, err
+	}
+	inputs := minePatternSchemas(counts)
+	var mined []domain.Schema
+	if len(inputs) > 0 {
+		if mined, err = s.schemas.SeedSchemas(ctx, agentID, tenantID, inputs); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.retireStalePatterns(ctx, agentID, tenantID, inputs); err != nil {
+		return mined, err
+	}
+	return mined, nil
+}
+
+// retireStalePatterns multiplies the confidence of each of the agent's mined
+// patterns missing from this run by TemporalPatternRetireDecay, and deletes
+// the ones that fall below MinTemporalPatternConfidence.
+func (s *TemporalPatternService) retireStalePatterns(ctx context.Context, agentID, tenantID uuid.UUID, current []CreateSchemaInput) error {
+	schemas, err := s.schemaStore.GetByAgent(ctx, agentID, tenantID)
+	if err != nil {
+		return err
+	}
+	holding := make(map[string]bool, len(current))
+	for _, in := range current {
+		holding[in.Name] = true
+	}
+	for _, sc := range schemas {
+		if sc.SchemaType != domain.SchemaTypeSituationTemplate || sc.Attributes["pattern"] != temporalPatternKind || holding[sc.Name] {
+			continue
+		}
+		confidence := sc.Confidence * TemporalPatternRetireDecay
+		if confidence < MinTemporalPatternConfidence {
+			err = s.schemaStore.Delete(ctx, sc.ID, tenantID)
+		} else {
+			err = s.schemaStore.UpdateConfidence(ctx, sc.ID, confidence)
+		}
+		if err != nil {
+			return fmt.Errorf("retire temporal pattern %q: %w", sc.Name, err)
+		}
+	}
+	return nil
+}
+
+// temporalSubject is what recurs: an episode topic or a mood.
+type temporalSubject struct {
+	kind string // "topic" or "mood"
+	name string
+}
+
+// temporalSlot is when it recurs: a time of day or a day of week.
+type temporalSlot struct {
+	attribute string // slotTimeOfDay or slotDayOfWeek
+	value     string
+}
+
+// minePatternSchemas finds the subjects whose episodes concentrate in one
+// slot. A subject qualifies when at least MinTemporalPatternEpisodes and
+// MinTemporalPatternShare of its episodes fall in the slot, and the slot is
+// MinTemporalPatternLift times likelier for it than for the agent's episodes
+// overall, so an agent that only runs on Mondays yields no Monday patterns.
+func minePatternSchemas(counts []domain.EpisodeSlotCount) []CreateSchemaInput {
+	total := make(map[temporalSlot]int)
+	var episodes int
+	subjectTotal := make(map[temporalSubject]int)
+	bySubject := make(map[temporalSubject][]domain.EpisodeSlotCount)
+	for _, c := range counts {
+		slot := temporalSlot{attribute: slotAttribute(c.Slot), value: c.Value}
+		// Every episode has exactly one time of day, so those slots sum to
+		// the episodes counted.
+		if c.SubjectKind == "" {
+			total[slot] = c.Count
+			if c.Slot == domain.EpisodeSlotTimeOfDay {
+				episodes += c.Count
+			}
+			continue
+		}
+		subj := temporalSubject{kind: c.SubjectKind, name: c.Subject}
+		bySubject[subj] = append(bySubject[subj], c)
+		if c.Slot == domain.EpisodeSlotTimeOfDay {
+			subjectTotal[subj] += c.Count
+		}
+	}
+
+	var inputs []CreateSchemaInput
+	for subj, slots := range bySubject {
+		of := subjectTotal[subj]
+		if of < MinTemporalPatternEpisodes {
+			continue
+		}
+		for _, c := range slots {
+			slot := temporalSlot{attribute: slotAttribute(c.Slot), value: c.Value}
+			if total[slot] == 0 {
+				continue
+			}
+			share := float64(c.Count) / float64(of)
+			lift := share / (float64(total[slot]) / float64(episodes))
+			if c.Count < MinTemporalPatternEpisodes || share < MinTemporalPatternShare || lift < MinTemporalPatternLift {
+				continue
+			}
+			inputs = append(inputs, patternSchema(subj, slot, c.Recent, c.Count, of, share, lift))
+		}
+	}
+
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Name < inputs[j].Name })
+	return inputs
+}
+
+// patternSchema describes one mined pattern as a situation template backed by
+// the slot's most recent episodes.
+func patternSchema(subj temporalSubject, slot temporalSlot, evidence []uuid.UUID, hits, of int, share, lift float64) CreateSchemaInput {
+	about := "about " + subj.name
+	if subj.kind == "mood" {
+		about = "in a " + subj.name
+	}
+	confidence := float32(math.Min(share, MaxSchemaConfidence))
+	return CreateSchemaInput{
+		SchemaType: domain.SchemaTypeSituationTemplate,
+		Name:       subj.name + " " + slotPhrase(slot),
+		Description: fmt.Sprintf("%d of %d episodes %s happened %s, %.1fx the agent's usual rate.",
+			hits, of, about, slotPhrase(slot), lift),
+		Attributes: map[string]any{
+			"pattern":      temporalPatternKind,
+			"subject":      subj.name,
+			"subject_kind": subj.kind,
+			slot.attribute: slot.value,
+			"occurrences":  hits,
+			"share":        math.Round(share*100) / 100,
+			"lift":         math.Round(lift*100) / 100,
+		},
+		ApplicableContexts: []string{subj.name, strings.ToLower(slot.value)},
+		EvidenceEpisodes:   evidence,
+		Confidence:         &confidence,
+		ReplaceEvidence:    true,
+	}
+}
+
+// slotAttribute is the schema attribute a slot kind is matched on.
+func slotAttribute(slot domain.EpisodeSlot) string {
+	if slot == domain.EpisodeSlotDayOfWeek {
+		return slotDayOfWeek
+	}
+	return slotTimeOfDay
+}
+
+// slotPhrase renders a slot for a schema name: "at night", "on Mondays".
+func slotPhrase(slot temporalSlot) string {
+	if slot.attribute == slotDayOfWeek {
+		return "on " + slot.value + "s"
+	}
+	if slot.value == "night" {
+		return "at night"
+	}
+	return "in the " + slot.value
+}
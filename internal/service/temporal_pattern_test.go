@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fakeSlotCounter counts its episodes by subject and slot the way
+// EpisodeStore.CountEpisodeSlots does in SQL.
+type fakeSlotCounter struct {
+	episodes []domain.Episode
+}
+
+func (f *fakeSlotCounter) CountEpisodeSlots(_ context.Context, agentID, tenantID uuid.UUID, since time.Time, valence float64, recent int) ([]domain.EpisodeSlotCount, error) {
+	type key struct {
+		kind, name string
+		slot       domain.EpisodeSlot
+		value      string
+	}
+	eps := make([]domain.Episode, 0, len(f.episodes))
+	for _, ep := range f.episodes {
+		if ep.AgentID == agentID && ep.TenantID == tenantID && !ep.OccurredAt.Before(since) {
+			eps = append(eps, ep)
+		}
+	}
+	sort.Slice(eps, func(i, j int) bool { return eps[i].OccurredAt.After(eps[j].OccurredAt) })
+
+	counts := make(map[key]*domain.EpisodeSlotCount)
+	var order []key
+	for _, ep := range eps {
+		subjects := [][2]string{{"", ""}}
+		seen := make(map[string]bool)
+		for _, t := range ep.Topics {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t != "" && !seen[t] {
+				seen[t] = true
+				subjects = append(subjects, [2]string{"topic", t})
+			}
+		}
+		if v := ep.EmotionalValence; v != nil && float64(*v) <= -valence {
+			subjects = append(subjects, [2]string{"mood", "negative mood"})
+		} else if v != nil && float64(*v) >= valence {
+			subjects = append(subjects, [2]string{"mood", "positive mood"})
+		}
+		slots := map[domain.EpisodeSlot]string{
+			domain.EpisodeSlotTimeOfDay: extractTimeOfDay(ep.OccurredAt),
+			domain.EpisodeSlotDayOfWeek: ep.OccurredAt.Weekday().String(),
+		}
+		for _, subj := range subjects {
+			for slot, value := range slots {
+				k := key{subj[0], subj[1], slot, value}
+				c, ok := counts[k]
+				if !ok {
+					c = &domain.EpisodeSlotCount{SubjectKind: subj[0], Subject: subj[1], Slot: slot, Value: value}
+					counts[k] = c
+					order = append(order, k)
+				}
+				c.Count++
+				if len(c.Recent) < recent {
+					c.Recent = append(c.Recent, ep.ID)
+				}
+			}
+		}
+	}
+	out := make([]domain.EpisodeSlotCount, 0, len(order))
+	for _, k := range order {
+		out = append(out, *counts[k])
+	}
+	return out, nil
+}
+
+func TestTemporalPatternService_MinesRecurringTopicsAndMoods(t *testing.T) {
+	schemaSvc, schemaStore, _, tenantID, agentID := setupSchemaTest()
+	episodeStore := newMockEpisodeStore()
+	schemaSvc.SetEpisodeStore(episodeStore)
+	counter := &fakeSlotCounter{}
+	svc := NewTemporalPatternService(counter, schemaSvc, schemaStore, nil, testLogger())
+	ctx := context.Background()
+
+	monday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -28)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	at := func(day, hour int) time.Time { return monday.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+	negative := float32(-0.6)
+	add := func(occurredAt time.Time, topics []string, valence *float32) {
+		ep := &domain.Episode{
+			AgentID: agentID, TenantID: tenantID, OccurredAt: occurredAt,
+			Topics: topics, EmotionalValence: valence,
+		}
+		_ = episodeStore.Create(ctx, ep)
+		counter.episodes = append(counter.episodes, *ep)
+	}
+	// Reports come up on three Mondays at different hours, and once midweek.
+	add(at(0, 9), []string{"Reports"}, nil)
+	add(at(7, 14), []string{"reports"}, nil)
+	add(at(14, 19), []string{"reports"}, nil)
+	add(at(2, 10), []string{"reports"}, nil)
+	// Frustration shows up late at night on different days.
+	add(at(1, 23), nil, &negative)
+	add(at(10, 23), nil, &negative)
+	add(at(12, 22), nil, &negative)
+	add(at(4, 15), nil, nil)
+	add(at(13, 11), nil, nil)
+	add(at(9, 16), nil, nil)
+
+	schemas, err := svc.MinePatterns(ctx, agentID, tenantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemas) != 2 {
+		t.Fatalf("expected 2 patterns, got %+v", schemas)
+	}
+	mood, reports := schemas[0], schemas[1]
+	if mood.Name != "negative mood at night" || mood.Attributes["time_preference"] != "night" {
+		t.Errorf("expected a night-time negative mood pattern, got %q %v", mood.Name, mood.Attributes)
+	}
+	if reports.Name != "reports on Mondays" || reports.Attributes["day_preference"] != "Monday" {
+		t.Errorf("expected a Monday reports pattern, got %q %v", reports.Name, reports.Attributes)
+	}
+	if reports.SchemaType != domain.SchemaTypeSituationTemplate || len(reports.EvidenceEpisodes) != 3 {
+		t.Errorf("expected a situation template backed by 3 episodes, got %s with %d", reports.SchemaType, len(reports.EvidenceEpisodes))
+	}
+	if score := schemaSvc.scoreTimeMatch(reports.Attributes, "morning", "Monday"); score != 1.0 {
+		t.Errorf("expected the mined day preference to match Mondays, got %f", score)
+	}
+
+	// Mining again overwrites the same schemas: no new schemas, and the
+	// evidence and confidence stay as mined rather than accumulating.
+	again, err := svc.MinePatterns(ctx, agentID, tenantID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemaStore.schemas) != 2 {
+		t.Errorf("expected re-mining to keep 2 schemas, got %d", len(schemaStore.schemas))
+	}
+	if len(again) != 2 || again[1].Confidence != reports.Confidence || again[1].EvidenceCount != 3 || len(again[1].EvidenceEpisodes) != 3 {
+		t.Errorf("expected re-mining to leave the reports pattern as mined, got confidence %.2f (was %.2f) with %d evidence",
+			again[1].Confidence, reports.Confidence, again[1].EvidenceCount)
+	}
+
+	// Once the late-night frustration stops, the mood pattern fades and is
+	// then deleted, while the reports pattern stays.
+	var kept []domain.Episode
+	for _, ep := range counter.episodes {
+		if ep.EmotionalValence == nil {
+			kept = append(kept, ep)
+		}
+	}
+	counter.episodes = kept
+	if _, err := svc.MinePatterns(ctx, agentID, tenantID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	faded := schemaStore.schemas[mood.ID]
+	if faded == nil || faded.Confidence != mood.Confidence*TemporalPatternRetireDecay {
+		t.Fatalf("expected the stale mood pattern's confidence to decay, got %+v", faded)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := svc.MinePatterns(ctx, agentID, tenantID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, ok := schemaStore.schemas[mood.ID]; ok {
+		t.Errorf("expected the stale mood pattern to be deleted")
+	}
+	if _, ok := schemaStore.schemas[reports.ID]; !ok {
+		t.Errorf("expected the reports pattern to stay")
+	}
+}
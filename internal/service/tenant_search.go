@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	tenantSearchDefaultLimit = 50
+	tenantSearchMaxLimit     = 200
+	// searchExcerptRadius is how many characters of an episode are kept on
+	// each side of an exact match.
+	searchExcerptRadius = 120
+)
+
+var (
+	ErrSearchQueryEmpty          = errors.New("search query is required")
+	ErrInvalidSearchMode         = errors.New("invalid search mode")
+	ErrSemanticSearchUnavailable = errors.New("semantic search needs an embedding client")
+)
+
+// TenantSearchService finds where a piece of information is stored across all
+// agents of a tenant — for data subject access requests and debugging.
+type TenantSearchService struct {
+	memories        domain.MemorySearchStore
+	episodes        domain.EpisodeSearchStore
+	embeddingClient domain.EmbeddingClient
+	logger          *zap.Logger
+}
+
+// NewTenantSearchService creates a new tenant search service.
+func NewTenantSearchService(memories domain.MemorySearchStore, episodes domain.EpisodeSearchStore, embeddingClient domain.EmbeddingClient, logger *zap.Logger) *TenantSearchService {
+	return &TenantSearchService{memories: memories, episodes: episodes, embeddingClient: embeddingClient, logger: logger}
+}
+
+// TenantSearchInput is a tenant-wide search. Mode defaults to full-text;
+// IncludeEpisodes also searches raw episode content.
+type TenantSearchInput struct {
+	TenantID        uuid.UUID
+	Query           string
+	Mode            domain.SearchMode
+	AgentID         *uuid.UUID
+	IncludeEpisodes bool
+	Limit           int
+	Offset          int
+}
+
+// TenantSearchResult holds the matches; Episodes is omitted unless requested.
+type TenantSearchResult struct {
+	Query    string                    `json:"query"`
+	Mode     domain.SearchMode         `json:"mode"`
+	Memories []domain.MemorySearchHit  `json:"memories"`
+	Episodes []domain.EpisodeSearchHit `json:"episodes,omitempty"`
+	Limit    int                       `json:"limit"`
+	Offset   int                       `json:"offset"`
+}
+
+// Search runs the query over memories and, on request, episodes. Limit and
+// Offset page each list independently.
+func (s *TenantSearchService) Search(ctx context.Context, input TenantSearchInput) (*TenantSearchResult, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, ErrSearchQueryEmpty
+	}
+	if input.Mode == "" {
+		input.Mode = domain.SearchModeText
+	}
+	if !input.Mode.IsValid() {
+		return nil, ErrInvalidSearchMode
+	}
+	if input.Limit <= 0 {
+		input.Limit = tenantSearchDefaultLimit
+	}
+	if input.Limit > tenantSearchMaxLimit {
+		input.Limit = tenantSearchMaxLimit
+	}
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	q := domain.TenantSearchQuery{
+		Mode:    input.Mode,
+		Query:   query,
+		AgentID: input.AgentID,
+		Limit:   input.Limit,
+		Offset:  input.Offset,
+	}
+	if input.Mode == domain.SearchModeSemantic {
+		if s.embeddingClient == nil {
+			return nil, ErrSemanticSearchUnavailable
+		}
+		emb, err := s.embeddingClient.Embed(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		q.Embedding = emb
+	}
+
+	memories, err := s.memories.SearchTenant(ctx, input.TenantID, q)
+	if err != nil {
+		return nil, err
+	}
+	if memories == nil {
+		memories = []domain.MemorySearchHit{}
+	}
+	result := &TenantSearchResult{
+		Query:    query,
+		Mode:     input.Mode,
+		Memories: memories,
+		Limit:    input.Limit,
+		Offset:   input.Offset,
+	}
+
+	if input.IncludeEpisodes {
+		episodes, err := s.episodes.SearchTenant(ctx, input.TenantID, q)
+		if err != nil {
+			return nil, err
+		}
+		for i := range episodes {
+			episodes[i].Excerpt = searchExcerpt(episodes[i].RawContent, query, input.Mode)
+		}
+		if episodes == nil {
+			episodes = []domain.EpisodeSearchHit{}
+		}
+		result.Episodes = episodes
+	}
+
+	logFor(ctx, s.logger).Info("tenant search",
+		zap.String("tenant_id", input.TenantID.String()),
+		zap.String("mode", string(input.Mode)),
+		zap.Int("memories", len(result.Memories)),
+		zap.Int("episodes", len(result.Episodes)))
+
+	return result, nil
+}
+
+// searchExcerpt returns the part of content around an exact match, or its
+// opening for other modes, cut on rune boundaries.
+func searchExcerpt(content, query string, mode domain.SearchMode) string {
+	runes := []rune(content)
+	start := 0
+	if mode == domain.SearchModeExact {
+		// ToLower can change byte lengths outside ASCII; ignore a match past the end.
+		if i := strings.Index(strings.ToLower(content), strings.ToLower(query)); i >= 0 && i <= len(content) {
+			start = len([]rune(content[:i])) - searchExcerptRadius
+			if start < 0 {
+				start = 0
+			}
+		}
+	}
+	end := start + 2*searchExcerptRadius + len([]rune(query))
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	excerpt := string(runes[start:end])
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
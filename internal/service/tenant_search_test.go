@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type fakeMemorySearchStore struct {
+	hits []domain.MemorySearchHit
+	last domain.TenantSearchQuery
+}
+
+func (f *fakeMemorySearchStore) SearchTenant(ctx context.Context, tenantID uuid.UUID, q domain.TenantSearchQuery) ([]domain.MemorySearchHit, error) {
+	f.last = q
+	return f.hits, nil
+}
+
+type fakeEpisodeSearchStore struct {
+	hits   []domain.EpisodeSearchHit
+	called bool
+}
+
+func (f *fakeEpisodeSearchStore) SearchTenant(ctx context.Context, tenantID uuid.UUID, q domain.TenantSearchQuery) ([]domain.EpisodeSearchHit, error) {
+	f.called = true
+	return f.hits, nil
+}
+
+func TestTenantSearchService_Defaults(t *testing.T) {
+	memories := &fakeMemorySearchStore{}
+	episodes := &fakeEpisodeSearchStore{}
+	svc := NewTenantSearchService(memories, episodes, &mockEmbeddingClient{}, testLogger())
+
+	result, err := svc.Search(context.Background(), TenantSearchInput{TenantID: uuid.New(), Query: "  alice@example.com ", Limit: 1000})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if memories.last.Mode != domain.SearchModeText || memories.last.Query != "alice@example.com" || memories.last.Limit != tenantSearchMaxLimit {
+		t.Fatalf("unexpected store query: %+v", memories.last)
+	}
+	if result.Memories == nil || result.Episodes != nil || episodes.called {
+		t.Fatalf("expected an empty memory list and no episode search, got %+v", result)
+	}
+}
+
+func TestTenantSearchService_SemanticEmbedsQuery(t *testing.T) {
+	memories := &fakeMemorySearchStore{}
+	svc := NewTenantSearchService(memories, &fakeEpisodeSearchStore{}, &mockEmbeddingClient{}, testLogger())
+
+	if _, err := svc.Search(context.Background(), TenantSearchInput{TenantID: uuid.New(), Query: "dark mode", Mode: domain.SearchModeSemantic}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(memories.last.Embedding) == 0 {
+		t.Fatal("expected the query to be embedded")
+	}
+
+	svc = NewTenantSearchService(memories, &fakeEpisodeSearchStore{}, nil, testLogger())
+	if _, err := svc.Search(context.Background(), TenantSearchInput{TenantID: uuid.New(), Query: "dark mode", Mode: domain.SearchModeSemantic}); !errors.Is(err, ErrSemanticSearchUnavailable) {
+		t.Fatalf("expected ErrSemanticSearchUnavailable, got %v", err)
+	}
+}
+
+func TestTenantSearchService_EpisodeExcerpts(t *testing.T) {
+	raw := strings.Repeat("x", 300) + " contact ALICE@example.com please " + strings.Repeat("y", 300)
+	episodes := &fakeEpisodeSearchStore{hits: []domain.EpisodeSearchHit{{ID: uuid.New(), RawContent: raw}}}
+	svc := NewTenantSearchService(&fakeMemorySearchStore{}, episodes, nil, testLogger())
+
+	result, err := svc.Search(context.Background(), TenantSearchInput{TenantID: uuid.New(), Query: "alice@example.com", Mode: domain.SearchModeExact, IncludeEpisodes: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	excerpt := result.Episodes[0].Excerpt
+	if !strings.Contains(excerpt, "ALICE@example.com") || !strings.HasPrefix(excerpt, "…") || !strings.HasSuffix(excerpt, "…") {
+		t.Fatalf("expected an excerpt around the match, got %q", excerpt)
+	}
+}
+
+func TestTenantSearchService_Errors(t *testing.T) {
+	svc := NewTenantSearchService(&fakeMemorySearchStore{}, &fakeEpisodeSearchStore{}, nil, testLogger())
+	ctx := context.Background()
+
+	if _, err := svc.Search(ctx, TenantSearchInput{TenantID: uuid.New(), Query: "   "}); !errors.Is(err, ErrSearchQueryEmpty) {
+		t.Fatalf("expected ErrSearchQueryEmpty, got %v", err)
+	}
+	if _, err := svc.Search(ctx, TenantSearchInput{TenantID: uuid.New(), Query: "x", Mode: "fuzzy"}); !errors.Is(err, ErrInvalidSearchMode) {
+		t.Fatalf("expected ErrInvalidSearchMode, got %v", err)
+	}
+}
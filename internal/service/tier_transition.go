@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const defaultTierTransitionInterval = 30 * time.Minute
+
+// TierTransitionService keeps each memory's materialized tier in line with
+// the tier policy. Confidence sets the baseline band; access frequency, age
+// since last access and pinning move memories from there. Every move is
+// recorded in tier history.
+type TierTransitionService struct {
+	memoryStore domain.MemoryStore
+	agents      domain.AgentRegistry
+	fanout      DatabaseFanout
+	logger      *zap.Logger
+	policy      domain.TierPolicy
+
+	interval   time.Duration
+	stopCh     chan struct{}
+	cancelRuns context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func NewTierTransitionService(ms domain.MemoryStore, logger *zap.Logger) *TierTransitionService {
+	return &TierTransitionService{
+		memoryStore: ms,
+		logger:      logger,
+		policy:      domain.DefaultTierPolicy(),
+		interval:    defaultTierTransitionInterval,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+func (s *TierTransitionService) SetInterval(d time.Duration) {
+	s.interval = d
+}
+
+// SetPolicy replaces the default transition rules.
+func (s *TierTransitionService) SetPolicy(p domain.TierPolicy) {
+	s.policy = p
+}
+
+// SetAgentRegistry lists agents with live memories from the agents table.
+func (s *TierTransitionService) SetAgentRegistry(r domain.AgentRegistry) {
+	s.agents = r
+}
+
+// SetFanout runs the transition worker over every tenant database.
+func (s *TierTransitionService) SetFanout(f DatabaseFanout) {
+	s.fanout = f
+}
+
+// Start runs the transition worker on a periodic schedule in a background goroutine.
+func (s *TierTransitionService) Start() {
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancelRuns = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.logger.Info("tier transition worker started", zap.Duration("interval", s.interval))
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, tickCancel := context.WithTimeout(baseCtx, 2*time.Minute)
+				guardPanic(s.logger, "tier transition tick", func() {
+					s.fanout.each(ctx, func(ctx context.Context) { s.RunOnce(ctx) })
+				})
+				tickCancel()
+			case <-s.stopCh:
+				s.logger.Info("tier transition worker stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop gracefully stops the worker, cancelling any in-flight pass.
+func (s *TierTransitionService) Stop() {
+	if s.cancelRuns != nil {
+		s.cancelRuns()
+	}
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// RunOnce re-evaluates every agent's memories and returns the number of tier
+// changes applied.
+func (s *TierTransitionService) RunOnce(ctx context.Context) int {
+	agents, err := agentsWithWork(ctx, s.agents, domain.AgentWorkFilter{LiveMemories: true}, s.memoryStore.ListDistinctAgentIDs)
+	if err != nil {
+		logFor(ctx, s.logger).Error("failed to list agents for tier transitions", zap.Error(err))
+		return 0
+	}
+
+	total := 0
+	for _, agent := range agents {
+		if ctx.Err() != nil {
+			break
+		}
+		transitions, err := s.TransitionAgent(ctx, agent.ID)
+		if err != nil {
+			logFor(ctx, s.logger).Warn("tier transition failed", zap.String("agent_id", agent.ID.String()), zap.Error(err))
+			continue
+		}
+		total += len(transitions)
+	}
+
+	if total > 0 {
+		logFor(ctx, s.logger).Info("applied tier transitions", zap.Int("agents", len(agents)), zap.Int("transitions", total))
+	}
+	return total
+}
+
+// TransitionAgent moves an agent's memories whose policy tier differs from
+// their stored tier and returns the applied transitions.
+func (s *TierTransitionService) TransitionAgent(ctx context.Context, agentID uuid.UUID) ([]domain.TierTransition, error) {
+	memories, err := s.memoryStore.GetByAgentForDecay(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timeNow()
+	var transitions []domain.TierTransition
+	for _, m := range memories {
+		if t, ok := s.evaluate(m, now); ok {
+			transitions = append(transitions, t)
+		}
+	}
+
+	if err := s.memoryStore.ApplyTierTransitions(ctx, transitions); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// SetPinned pins or unpins a memory and applies the resulting tier change
+// immediately rather than waiting for the next worker pass.
+func (s *TierTransitionService) SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) (*domain.Memory, error) {
+	if err := s.memoryStore.SetPinned(ctx, id, tenantID, pinned); err != nil {
+		return nil, mapMemoryErr(err)
+	}
+	m, err := s.memoryStore.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, mapMemoryErr(err)
+	}
+
+	t, ok := s.evaluate(*m, timeNow())
+	if !ok {
+		return m, nil
+	}
+	if !pinned {
+		t.Reason = domain.TierReasonUnpinned
+	}
+	if err := s.memoryStore.ApplyTierTransitions(ctx, []domain.TierTransition{t}); err != nil {
+		return nil, err
+	}
+	m.Tier = t.ToTier
+	return m, nil
+}
+
+// MemoryHistory returns a memory's tier changes, newest first.
+func (s *TierTransitionService) MemoryHistory(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, limit int) ([]domain.TierTransition, error) {
+	m, err := s.memoryStore.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, mapMemoryErr(err)
+	}
+	return s.memoryStore.GetTierHistory(ctx, m.AgentID, tenantID, &id, time.Time{}, limit)
+}
+
+// AgentHistory returns tier changes across an agent's memories since the
+// given time, newest first.
+func (s *TierTransitionService) AgentHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, since time.Time, limit int) ([]domain.TierTransition, error) {
+	return s.memoryStore.GetTierHistory(ctx, agentID, tenantID, nil, since, limit)
+}
+
+func (s *TierTransitionService) evaluate(m domain.Memory, now time.Time) (domain.TierTransition, bool) {
+	from := m.CurrentTier()
+	to, reason := s.policy.Evaluate(m, now)
+	if to == from {
+		return domain.TierTransition{}, false
+	}
+	return domain.TierTransition{
+		MemoryID:   m.ID,
+		AgentID:    m.AgentID,
+		TenantID:   m.TenantID,
+		FromTier:   from,
+		ToTier:     to,
+		Reason:     reason,
+		Confidence: m.Confidence,
+		OccurredAt: now,
+	}, true
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestTierTransitionService_TransitionAgent(t *testing.T) {
+	memoryStore := newMockMemoryStore()
+	svc := NewTierTransitionService(memoryStore, zap.NewNop())
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	recent := time.Now().Add(-time.Hour)
+
+	add := func(confidence float32, accesses int, tier domain.MemoryTier) *domain.Memory {
+		m := &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: "memory", Confidence: confidence, Tier: tier}
+		_ = memoryStore.Create(ctx, m)
+		m.LastAccessedAt = &recent
+		m.AccessCount = accesses
+		return m
+	}
+
+	steady := add(0.8, 1, domain.TierWarm)
+	popular := add(0.6, 10, domain.TierCold)
+	drifted := add(0.3, 0, domain.TierWarm)
+
+	transitions, err := svc.TransitionAgent(ctx, agentID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(transitions) != 2 {
+		t.Fatalf("expected 2 transitions, got %d", len(transitions))
+	}
+	if steady.Tier != domain.TierWarm {
+		t.Fatalf("expected steady memory to stay warm, got %s", steady.Tier)
+	}
+	if popular.Tier != domain.TierWarm {
+		t.Fatalf("expected frequently accessed memory to be promoted to warm, got %s", popular.Tier)
+	}
+	if drifted.Tier != domain.TierArchive {
+		t.Fatalf("expected low-confidence memory to move to archive, got %s", drifted.Tier)
+	}
+
+	history, _ := svc.MemoryHistory(ctx, popular.ID, tenantID, 10)
+	if len(history) != 1 || history[0].FromTier != domain.TierCold || history[0].Reason != domain.TierReasonFrequentAccess {
+		t.Fatalf("expected promotion to be recorded in history, got %+v", history)
+	}
+
+	// A second pass finds nothing to do.
+	if again, _ := svc.TransitionAgent(ctx, agentID); len(again) != 0 {
+		t.Fatalf("expected no further transitions, got %d", len(again))
+	}
+}
+
+func TestTierTransitionService_SetPinned(t *testing.T) {
+	memoryStore := newMockMemoryStore()
+	svc := NewTierTransitionService(memoryStore, zap.NewNop())
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	m := &domain.Memory{AgentID: uuid.New(), TenantID: tenantID, Content: "memory", Confidence: 0.5, Tier: domain.TierCold}
+	_ = memoryStore.Create(ctx, m)
+	now := time.Now()
+	m.LastAccessedAt = &now
+
+	pinned, err := svc.SetPinned(ctx, m.ID, tenantID, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !pinned.Pinned || pinned.Tier != domain.TierHot {
+		t.Fatalf("expected pinned memory in hot tier, got pinned=%v tier=%s", pinned.Pinned, pinned.Tier)
+	}
+
+	unpinned, err := svc.SetPinned(ctx, m.ID, tenantID, false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if unpinned.Tier != domain.TierCold {
+		t.Fatalf("expected unpinned memory back in cold tier, got %s", unpinned.Tier)
+	}
+
+	history, _ := svc.AgentHistory(ctx, m.AgentID, tenantID, time.Time{}, 10)
+	if len(history) != 2 || history[0].Reason != domain.TierReasonUnpinned || history[1].Reason != domain.TierReasonPinned {
+		t.Fatalf("expected pin and unpin in history, got %+v", history)
+	}
+
+	if _, err := svc.SetPinned(ctx, uuid.New(), tenantID, true); err != ErrMemoryNotFound {
+		t.Fatalf("expected ErrMemoryNotFound, got %v", err)
+	}
+}
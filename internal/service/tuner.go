@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"sync"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
@@ -34,12 +33,10 @@ const (
 type TunerService struct {
 	feedbackStore domain.FeedbackStore
 	policyStore   domain.PolicyStore
+	agents        domain.AgentRegistry
 	logger        *zap.Logger
 
-	interval   time.Duration
-	stopCh     chan struct{}
-	cancelRuns context.CancelFunc
-	wg         sync.WaitGroup
+	interval time.Duration
 }
 
 func NewTunerService(fs domain.FeedbackStore, ps domain.PolicyStore, logger *zap.Logger) *TunerService {
@@ -48,7 +45,6 @@ func NewTunerService(fs domain.FeedbackStore, ps domain.PolicyStore, logger *zap
 		policyStore:   ps,
 		logger:        logger,
 		interval:      defaultTunerInterval,
-		stopCh:        make(chan struct{}),
 	}
 }
 
@@ -56,56 +52,28 @@ func (s *TunerService) SetInterval(d time.Duration) {
 	s.interval = d
 }
 
-// Start runs the tuner on a periodic schedule in a background goroutine.
-func (s *TunerService) Start() {
-	baseCtx, cancel := context.WithCancel(context.Background())
-	s.cancelRuns = cancel
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		ticker := time.NewTicker(s.interval)
-		defer ticker.Stop()
-
-		s.logger.Info("policy tuner started", zap.Duration("interval", s.interval))
-
-		for {
-			select {
-			case <-ticker.C:
-				ctx, tickCancel := context.WithTimeout(baseCtx, 30*time.Second)
-				guardPanic(s.logger, "tuner tick", func() {
-					if err := s.RunAll(ctx); err != nil {
-						s.logger.Error("policy tuner run failed", zap.Error(err))
-					}
-				})
-				tickCancel()
-			case <-s.stopCh:
-				s.logger.Info("policy tuner stopped")
-				return
-			}
-		}
-	}()
+// SetAgentRegistry lists the agents with feedback from the agents table instead of a DISTINCT
+// scan.
+func (s *TunerService) SetAgentRegistry(r domain.AgentRegistry) {
+	s.agents = r
 }
 
-// Stop gracefully stops the tuner, cancelling any in-flight run.
-func (s *TunerService) Stop() {
-	if s.cancelRuns != nil {
-		s.cancelRuns()
-	}
-	close(s.stopCh)
-	s.wg.Wait()
+// ScheduledTask returns the tuner's periodic run for the Scheduler.
+func (s *TunerService) ScheduledTask() ScheduledTask {
+	return ScheduledTask{Name: "tuner", Interval: s.interval, Timeout: 30 * time.Second, Run: s.RunAll}
 }
 
 // RunAll runs the tuner for all agents that have feedback.
 func (s *TunerService) RunAll(ctx context.Context) error {
-	agentIDs, err := s.feedbackStore.ListDistinctAgentIDs(ctx)
+	agents, err := agentsWithWork(ctx, s.agents, domain.AgentWorkFilter{Feedback: true}, s.feedbackStore.ListDistinctAgentIDs)
 	if err != nil {
 		return err
 	}
 
-	for _, agentID := range agentIDs {
-		if err := s.RunForAgent(ctx, agentID); err != nil {
-			s.logger.Warn("tuner failed for agent",
-				zap.String("agent_id", agentID.String()),
+	for _, agent := range agents {
+		if err := s.RunForAgent(ctx, agent.ID); err != nil {
+			logFor(ctx, s.logger).Warn("tuner failed for agent",
+				zap.String("agent_id", agent.ID.String()),
 				zap.Error(err))
 		}
 	}
@@ -187,7 +155,7 @@ func (s *TunerService) RunForAgent(ctx context.Context, agentID uuid.UUID) error
 				newWeight = minPriorityWeight
 			}
 			if newWeight != policy.PriorityWeight {
-				s.logger.Info("tuner: reducing priority_weight",
+				logFor(ctx, s.logger).Info("tuner: reducing priority_weight",
 					zap.String("agent_id", agentID.String()),
 					zap.String("memory_type", string(memType)),
 					zap.Float64("old", policy.PriorityWeight),
@@ -201,7 +169,7 @@ func (s *TunerService) RunForAgent(ctx context.Context, agentID uuid.UUID) error
 		helpfulRate := float64(stats.helpful) / float64(stats.total)
 		if helpfulRate > helpfulThreshold {
 			newWeight := policy.PriorityWeight + weightDelta
-			s.logger.Info("tuner: increasing priority_weight",
+			logFor(ctx, s.logger).Info("tuner: increasing priority_weight",
 				zap.String("agent_id", agentID.String()),
 				zap.String("memory_type", string(memType)),
 				zap.Float64("old", policy.PriorityWeight),
@@ -218,7 +186,7 @@ func (s *TunerService) RunForAgent(ctx context.Context, agentID uuid.UUID) error
 				newMax = minMaxMemories
 			}
 			if newMax != policy.MaxMemories {
-				s.logger.Info("tuner: reducing max_memories",
+				logFor(ctx, s.logger).Info("tuner: reducing max_memories",
 					zap.String("agent_id", agentID.String()),
 					zap.String("memory_type", string(memType)),
 					zap.Int("old", policy.MaxMemories),
@@ -230,7 +198,7 @@ func (s *TunerService) RunForAgent(ctx context.Context, agentID uuid.UUID) error
 
 		if adjusted {
 			if err := s.policyStore.Upsert(ctx, policy); err != nil {
-				s.logger.Warn("tuner: failed to update policy",
+				logFor(ctx, s.logger).Warn("tuner: failed to update policy",
 					zap.String("agent_id", agentID.String()),
 					zap.String("memory_type", string(memType)),
 					zap.Error(err))
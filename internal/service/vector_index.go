@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrVectorIndexNotFound = errors.New("vector index not found")
+	ErrVectorIndexBuilding = errors.New("vector index rebuild already in progress")
+	ErrVectorIndexEmpty    = errors.New("no stored vectors to sample")
+)
+
+const (
+	DefaultIndexHealthSamples = 20
+	MaxIndexHealthSamples     = 200
+	DefaultIndexHealthK       = 10
+	MaxIndexHealthK           = 100
+	vectorIndexBuildTimeout   = 6 * time.Hour
+)
+
+// InvalidIndexParamsError wraps a rejected parameter set so handlers can
+// surface the reason.
+type InvalidIndexParamsError struct{ Err error }
+
+func (e *InvalidIndexParamsError) Error() string { return e.Err.Error() }
+func (e *InvalidIndexParamsError) Unwrap() error { return e.Err }
+
+// VectorIndexService manages pgvector index build parameters, background
+// rebuilds, and recall-vs-latency health sampling.
+type VectorIndexService struct {
+	store  domain.VectorIndexStore
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	building map[string]bool
+	wg       sync.WaitGroup
+}
+
+func NewVectorIndexService(vs domain.VectorIndexStore, logger *zap.Logger) *VectorIndexService {
+	return &VectorIndexService{store: vs, logger: logger, building: make(map[string]bool)}
+}
+
+func (s *VectorIndexService) List(ctx context.Context) ([]domain.VectorIndex, error) {
+	return s.store.List(ctx)
+}
+
+func (s *VectorIndexService) Get(ctx context.Context, name string) (*domain.VectorIndex, error) {
+	idx, err := s.store.Get(ctx, name)
+	if err != nil {
+		return nil, mapVectorIndexErr(err)
+	}
+	return idx, nil
+}
+
+// Rebuild validates params and starts a background rebuild of the index,
+// returning it marked as building. Only one rebuild per index runs at a time.
+func (s *VectorIndexService) Rebuild(ctx context.Context, name string, params domain.VectorIndexParams) (*domain.VectorIndex, error) {
+	params = params.Normalize()
+	if err := params.Validate(); err != nil {
+		return nil, &InvalidIndexParamsError{Err: err}
+	}
+	if _, err := s.store.Get(ctx, name); err != nil {
+		return nil, mapVectorIndexErr(err)
+	}
+
+	s.mu.Lock()
+	if s.building[name] {
+		s.mu.Unlock()
+		return nil, ErrVectorIndexBuilding
+	}
+	s.building[name] = true
+	s.mu.Unlock()
+
+	if err := s.store.SetBuildStatus(ctx, name, domain.VectorIndexBuilding, ""); err != nil {
+		s.finishBuild(name)
+		return nil, mapVectorIndexErr(err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.finishBuild(name)
+		guardPanic(s.logger, "vector index rebuild", func() { s.rebuild(name, params) })
+	}()
+
+	return s.store.Get(ctx, name)
+}
+
+func (s *VectorIndexService) rebuild(name string, params domain.VectorIndexParams) {
+	ctx, cancel := context.WithTimeout(context.Background(), vectorIndexBuildTimeout)
+	defer cancel()
+
+	s.logger.Info("rebuilding vector index", zap.String("index", name), zap.String("method", string(params.Method)))
+	start := time.Now()
+	if err := s.store.Rebuild(ctx, name, params); err != nil {
+		s.logger.Error("vector index rebuild failed", zap.String("index", name), zap.Error(err))
+		if serr := s.store.SetBuildStatus(context.Background(), name, domain.VectorIndexFailed, err.Error()); serr != nil {
+			s.logger.Warn("failed to record vector index failure", zap.String("index", name), zap.Error(serr))
+		}
+		return
+	}
+	s.logger.Info("vector index rebuilt", zap.String("index", name), zap.Duration("took", time.Since(start)))
+}
+
+func (s *VectorIndexService) finishBuild(name string) {
+	s.mu.Lock()
+	delete(s.building, name)
+	s.mu.Unlock()
+}
+
+// Wait blocks until in-flight rebuilds finish. Used by tests and shutdown.
+func (s *VectorIndexService) Wait() {
+	s.wg.Wait()
+}
+
+// Health samples stored vectors as queries and runs each through the index
+// and through an exact sequential search, reporting mean recall@k and latency
+// percentiles for both. efSearch and probes try query-time settings other than
+// the session defaults.
+func (s *VectorIndexService) Health(ctx context.Context, name string, samples, k, efSearch, probes int) (*domain.VectorIndexHealth, error) {
+	if samples <= 0 {
+		samples = DefaultIndexHealthSamples
+	}
+	if samples > MaxIndexHealthSamples {
+		samples = MaxIndexHealthSamples
+	}
+	if k <= 0 {
+		k = DefaultIndexHealthK
+	}
+	if k > MaxIndexHealthK {
+		k = MaxIndexHealthK
+	}
+
+	queries, err := s.store.SampleVectors(ctx, name, samples)
+	if err != nil {
+		return nil, mapVectorIndexErr(err)
+	}
+	if len(queries) == 0 {
+		return nil, ErrVectorIndexEmpty
+	}
+
+	usesIndex, err := s.store.UsesIndex(ctx, name, queries[0], k)
+	if err != nil {
+		return nil, fmt.Errorf("explain search: %w", err)
+	}
+
+	var approxTimes, exactTimes []time.Duration
+	var recallSum float64
+	for _, q := range queries {
+		approx, err := s.store.Search(ctx, name, q, k, false, efSearch, probes)
+		if err != nil {
+			return nil, fmt.Errorf("approximate search: %w", err)
+		}
+		exact, err := s.store.Search(ctx, name, q, k, true, 0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("exact search: %w", err)
+		}
+		approxTimes = append(approxTimes, approx.Duration)
+		exactTimes = append(exactTimes, exact.Duration)
+		recallSum += searchRecall(approx, exact)
+	}
+
+	h := &domain.VectorIndexHealth{
+		Index:       name,
+		Samples:     len(queries),
+		K:           k,
+		EfSearch:    efSearch,
+		Probes:      probes,
+		UsesIndex:   usesIndex,
+		Recall:      recallSum / float64(len(queries)),
+		ApproxP50Ms: durationPercentileMs(approxTimes, 0.50),
+		ApproxP95Ms: durationPercentileMs(approxTimes, 0.95),
+		ExactP50Ms:  durationPercentileMs(exactTimes, 0.50),
+		ExactP95Ms:  durationPercentileMs(exactTimes, 0.95),
+	}
+	if h.ApproxP50Ms > 0 {
+		h.SpeedupRatio = h.ExactP50Ms / h.ApproxP50Ms
+	}
+	return h, nil
+}
+
+// searchRecall is the fraction of the exact result set the approximate
+// search also returned. An empty exact set counts as full recall.
+func searchRecall(approx, exact *domain.VectorSearchSample) float64 {
+	if len(exact.IDs) == 0 {
+		return 1
+	}
+	found := make(map[uuid.UUID]bool, len(approx.IDs))
+	for _, id := range approx.IDs {
+		found[id] = true
+	}
+	hits := 0
+	for _, id := range exact.IDs {
+		if found[id] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(exact.IDs))
+}
+
+// durationPercentileMs returns the nearest-rank percentile in milliseconds.
+func durationPercentileMs(ds []time.Duration, p float64) float64 {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(p*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return float64(sorted[i].Microseconds()) / 1000
+}
+
+func mapVectorIndexErr(err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return ErrVectorIndexNotFound
+	}
+	return err
+}
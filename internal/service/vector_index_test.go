@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/store"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type mockVectorIndexStore struct {
+	mu      sync.Mutex
+	indexes map[string]*domain.VectorIndex
+	release chan struct{} // Rebuild blocks until closed when set
+	rebuilt []domain.VectorIndexParams
+	approx  []uuid.UUID
+	exact   []uuid.UUID
+	vectors [][]float32
+}
+
+func (m *mockVectorIndexStore) List(ctx context.Context) ([]domain.VectorIndex, error) {
+	return nil, nil
+}
+
+func (m *mockVectorIndexStore) Get(ctx context.Context, name string) (*domain.VectorIndex, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	idx, ok := m.indexes[name]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	cp := *idx
+	return &cp, nil
+}
+
+func (m *mockVectorIndexStore) SetBuildStatus(ctx context.Context, name string, status domain.VectorIndexBuildStatus, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.indexes[name].BuildStatus = status
+	m.indexes[name].LastError = errMsg
+	return nil
+}
+
+func (m *mockVectorIndexStore) Rebuild(ctx context.Context, name string, params domain.VectorIndexParams) error {
+	if m.release != nil {
+		<-m.release
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rebuilt = append(m.rebuilt, params)
+	m.indexes[name].Params = params
+	m.indexes[name].BuildStatus = domain.VectorIndexReady
+	return nil
+}
+
+func (m *mockVectorIndexStore) SampleVectors(ctx context.Context, name string, n int) ([][]float32, error) {
+	if _, err := m.Get(ctx, name); err != nil {
+		return nil, err
+	}
+	return m.vectors, nil
+}
+
+func (m *mockVectorIndexStore) Search(ctx context.Context, name string, query []float32, k int, exact bool, efSearch, probes int) (*domain.VectorSearchSample, error) {
+	if exact {
+		return &domain.VectorSearchSample{IDs: m.exact, Duration: 20 * time.Millisecond}, nil
+	}
+	return &domain.VectorSearchSample{IDs: m.approx, Duration: 2 * time.Millisecond}, nil
+}
+
+func (m *mockVectorIndexStore) UsesIndex(ctx context.Context, name string, query []float32, k int) (bool, error) {
+	return true, nil
+}
+
+func newMockVectorIndexStore() *mockVectorIndexStore {
+	return &mockVectorIndexStore{indexes: map[string]*domain.VectorIndex{
+		"idx_memories_embedding": {Name: "idx_memories_embedding", Table: "memories", Column: "embedding",
+			Params: domain.DefaultVectorIndexParams(), BuildStatus: domain.VectorIndexReady},
+	}}
+}
+
+func TestVectorIndexService_Health(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	st := newMockVectorIndexStore()
+	st.vectors = [][]float32{{0.1, 0.2}, {0.3, 0.4}}
+	st.exact = []uuid.UUID{a, b, c, d}
+	st.approx = []uuid.UUID{a, b, c, uuid.New()}
+
+	h, err := NewVectorIndexService(st, zap.NewNop()).Health(context.Background(), "idx_memories_embedding", 0, 4, 100, 0)
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if h.Samples != 2 || h.K != 4 || h.EfSearch != 100 {
+		t.Errorf("unexpected report header: %+v", h)
+	}
+	if h.Recall != 0.75 {
+		t.Errorf("recall = %v, want 0.75", h.Recall)
+	}
+	if h.ApproxP50Ms != 2 || h.ExactP95Ms != 20 || h.SpeedupRatio != 10 {
+		t.Errorf("latencies = approx p50 %v, exact p95 %v, speedup %v", h.ApproxP50Ms, h.ExactP95Ms, h.SpeedupRatio)
+	}
+
+	if _, err := NewVectorIndexService(newMockVectorIndexStore(), zap.NewNop()).Health(context.Background(), "idx_memories_embedding", 0, 0, 0, 0); !errors.Is(err, ErrVectorIndexEmpty) {
+		t.Errorf("empty table: err = %v, want ErrVectorIndexEmpty", err)
+	}
+}
+
+func TestVectorIndexService_Rebuild(t *testing.T) {
+	st := newMockVectorIndexStore()
+	st.release = make(chan struct{})
+	svc := NewVectorIndexService(st, zap.NewNop())
+	ctx := context.Background()
+
+	var invalid *InvalidIndexParamsError
+	if _, err := svc.Rebuild(ctx, "idx_memories_embedding", domain.VectorIndexParams{Method: domain.VectorIndexHNSW, M: 32, EfConstruction: 40}); !errors.As(err, &invalid) {
+		t.Fatalf("ef_construction < 2m: err = %v, want InvalidIndexParamsError", err)
+	}
+	if _, err := svc.Rebuild(ctx, "idx_unknown", domain.VectorIndexParams{}); !errors.Is(err, ErrVectorIndexNotFound) {
+		t.Fatalf("unknown index: err = %v, want ErrVectorIndexNotFound", err)
+	}
+
+	idx, err := svc.Rebuild(ctx, "idx_memories_embedding", domain.VectorIndexParams{Method: domain.VectorIndexIVFFlat})
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if idx.BuildStatus != domain.VectorIndexBuilding {
+		t.Errorf("status = %q, want building", idx.BuildStatus)
+	}
+	if _, err := svc.Rebuild(ctx, "idx_memories_embedding", domain.VectorIndexParams{}); !errors.Is(err, ErrVectorIndexBuilding) {
+		t.Errorf("concurrent rebuild: err = %v, want ErrVectorIndexBuilding", err)
+	}
+
+	close(st.release)
+	svc.Wait()
+	if len(st.rebuilt) != 1 || st.rebuilt[0].Method != domain.VectorIndexIVFFlat || st.rebuilt[0].Lists != 100 {
+		t.Errorf("rebuilt with %+v, want ivfflat with default lists", st.rebuilt)
+	}
+}
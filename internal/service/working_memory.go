@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -13,11 +14,12 @@ import (
 	"github.com/Harshitk-cp/engram/internal/store"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 // Working memory constants
 const (
-	DefaultMaxSlots        = 7   // Miller's Law: 7 +/- 2
+	DefaultMaxSlots        = domain.DefaultWorkingMemorySlots
 	SpreadingDecay         = 0.5 // Activation decays 50% per hop
 	MaxSpreadingDepth      = 2   // Maximum hops for spreading activation
 	DirectActivationBoost  = 1.0 // Full activation for direct matches
@@ -27,10 +29,23 @@ const (
 	RecencyDecay           = 0.1 // Decay per hour for recency activation
 	MinActivationLevel     = 0.1 // Minimum activation to be considered
 	// MinSchemaMatchScore is defined in schema.go
+
+	// ActivationSourceConcurrency bounds how many activation sources (cues,
+	// goal, schemas, recent episodes) one Activate call runs at once.
+	ActivationSourceConcurrency = 4
+
+	// Under a latency budget a stage only starts when at least this much of
+	// the budget is left.
+	SchemaStageMinBudget    = 150 * time.Millisecond
+	SpreadingStageMinBudget = 50 * time.Millisecond
+
+	// ContextCharsPerToken approximates tokenization for context budgets.
+	ContextCharsPerToken = 4
 )
 
 var (
-	ErrSessionNotFound = errors.New("working memory session not found")
+	ErrSessionNotFound  = errors.New("working memory session not found")
+	ErrSnapshotNotFound = errors.New("working memory snapshot not found")
 )
 
 // WorkingMemoryService manages working memory sessions and memory activation.
@@ -42,9 +57,27 @@ type WorkingMemoryService struct {
 	procedureStore  domain.ProcedureStore
 	schemaStore     domain.SchemaStore
 	embeddingClient domain.EmbeddingClient
+	settingsStore   domain.WorkingMemorySettingsStore
+	snapshotStore   domain.WorkingMemorySnapshotStore
+	convActStore    domain.ConversationActivationStore
+	entityStore     domain.EntityStore
 	logger          *zap.Logger
 }
 
+// InvalidWorkingMemorySettingsError wraps rejected settings so handlers can
+// surface the reason.
+type InvalidWorkingMemorySettingsError struct{ Err error }
+
+func (e *InvalidWorkingMemorySettingsError) Error() string { return e.Err.Error() }
+func (e *InvalidWorkingMemorySettingsError) Unwrap() error { return e.Err }
+
+// InvalidReasoningStateError wraps a rejected reasoning state patch so
+// handlers can surface the reason.
+type InvalidReasoningStateError struct{ Err error }
+
+func (e *InvalidReasoningStateError) Error() string { return e.Err.Error() }
+func (e *InvalidReasoningStateError) Unwrap() error { return e.Err }
+
 // NewWorkingMemoryService creates a new working memory service.
 func NewWorkingMemoryService(
 	wmStore domain.WorkingMemoryStore,
@@ -68,6 +101,30 @@ func NewWorkingMemoryService(
 	}
 }
 
+// SetSettingsStore enables per-agent working memory capacity. Without it every
+// agent uses domain.DefaultWorkingMemorySettings.
+func (s *WorkingMemoryService) SetSettingsStore(ss domain.WorkingMemorySettingsStore) {
+	s.settingsStore = ss
+}
+
+// SetSnapshotStore enables session snapshots.
+func (s *WorkingMemoryService) SetSnapshotStore(ss domain.WorkingMemorySnapshotStore) {
+	s.snapshotStore = ss
+}
+
+// SetConversationActivationStore records which memories and procedures won a
+// slot during a conversation, so outcomes can be attributed back to them.
+func (s *WorkingMemoryService) SetConversationActivationStore(cs domain.ConversationActivationStore) {
+	s.convActStore = cs
+}
+
+// SetEntityStore lets action templates name entity types ({{person}},
+// {{product}}, ...), resolved from the entities the winning memories mention.
+// Without it those placeholders stay unfilled.
+func (s *WorkingMemoryService) SetEntityStore(es domain.EntityStore) {
+	s.entityStore = es
+}
+
 // ActivationResult holds the result of memory activation.
 type ActivationResult struct {
 	Session          *domain.WorkingMemorySession
@@ -89,8 +146,64 @@ type activatedItem struct {
 	Cue             string
 }
 
+// memoryRef identifies an activatable memory across the four stores.
+type memoryRef struct {
+	Type domain.ActivatedMemoryType
+	ID   uuid.UUID
+}
+
+type fetchedContent struct {
+	content    string
+	confidence float32
+	found      bool
+}
+
+// activationCache holds what one Activate call has already loaded, so schema
+// evidence and spreading targets are fetched at most once per request.
+// Misses are cached too; a missing memory stays missing for the request.
+type activationCache map[memoryRef]fetchedContent
+
+func newActivationCache() activationCache {
+	return make(activationCache)
+}
+
+// seed records content the activation sources already returned.
+func (c activationCache) seed(items []activatedItem) {
+	for _, item := range items {
+		ref := memoryRef{item.Type, item.ID}
+		if _, ok := c[ref]; !ok {
+			c[ref] = fetchedContent{item.Content, item.Confidence, true}
+		}
+	}
+}
+
 // Activate performs intelligent memory activation using spreading activation.
 func (s *WorkingMemoryService) Activate(ctx context.Context, input domain.ActivationInput) (*domain.WorkingMemoryResult, error) {
+	if input.MaxSlots != 0 && !domain.ValidSlotCount(input.MaxSlots) {
+		return nil, domain.ErrInvalidSlotCount
+	}
+	if input.MaxLatencyMs < 0 || input.MaxContextTokens < 0 {
+		return nil, domain.ErrInvalidActivationBudget
+	}
+	if input.IsolateConversation && input.ConversationID == nil {
+		return nil, domain.ErrIsolationWithoutConversation
+	}
+	for _, t := range input.AssociationTypes {
+		if !domain.ValidMemoryAssociationType(t) {
+			return nil, domain.ErrInvalidAssociationType
+		}
+	}
+
+	var deadline time.Time
+	if input.MaxLatencyMs > 0 {
+		deadline = time.Now().Add(time.Duration(input.MaxLatencyMs) * time.Millisecond)
+	}
+	// fits reports whether a stage needing need still fits the latency budget.
+	fits := func(need time.Duration) bool {
+		return deadline.IsZero() || time.Until(deadline) >= need
+	}
+	var skipped []domain.ActivationStage
+
 	// 1. Get or create session
 	session, err := s.getOrCreateSession(ctx, input)
 	if err != nil {
@@ -107,52 +220,123 @@ func (s *WorkingMemoryService) Activate(ctx context.Context, input domain.Activa
 		session.ActiveContext = input.Context
 	}
 
-	// 2. Direct activation from cues
-	activations := s.activateFromCues(ctx, input.AgentID, input.TenantID, input.Cues)
-	s.logger.Debug("direct activations", zap.Int("count", len(activations)))
-
-	// 3. Goal-directed activation bias
-	if session.CurrentGoal != "" {
-		goalActivations := s.activateFromGoal(ctx, input.AgentID, input.TenantID, session.CurrentGoal)
-		activations = s.mergeActivations(activations, goalActivations, GoalActivationBoost)
-		s.logger.Debug("after goal activation", zap.Int("count", len(activations)))
+	session.MaxSlots = s.slotsFor(ctx, input, session.CurrentGoal)
+
+	cache := newActivationCache()
+
+	// 2-5. Direct, goal, schema and temporal activation are independent
+	// embedding + store round trips, so they run concurrently. Results are
+	// merged afterwards in a fixed order so scoring does not depend on timing.
+	var (
+		cueActivations    []activatedItem
+		goalActivations   []activatedItem
+		activeSchemas     []domain.SchemaMatch
+		schemaActivations [][]activatedItem
+		recentActivations []activatedItem
+	)
+	var g errgroup.Group
+	g.SetLimit(ActivationSourceConcurrency)
+	g.Go(func() error {
+		cueActivations = s.activateFromCues(ctx, input.AgentID, input.TenantID, input.Cues)
+		return nil
+	})
+	if goal := goalQuery(session.CurrentGoal, session.ReasoningState); goal != "" {
+		g.Go(func() error {
+			goalActivations = s.activateFromGoal(ctx, input.AgentID, input.TenantID, goal)
+			return nil
+		})
 	}
-
-	// 4. Schema-directed activation
-	activeSchemas := s.getActiveSchemas(ctx, input.AgentID, input.TenantID, input.Cues, input.Context)
-	for _, schemaMatch := range activeSchemas {
-		schemaActivations := s.activateFromSchema(ctx, input.AgentID, input.TenantID, schemaMatch.Schema)
-		activations = s.mergeActivations(activations, schemaActivations, SchemaActivationBoost)
+	if fits(SchemaStageMinBudget) {
+		g.Go(func() error {
+			// The only source that reads the request cache while others run.
+			activeSchemas = s.getActiveSchemas(ctx, input.AgentID, input.TenantID, input.Cues, session.CurrentGoal, input.Context)
+			for _, schemaMatch := range activeSchemas {
+				schemaActivations = append(schemaActivations, s.activateFromSchema(ctx, cache, input.TenantID, schemaMatch.Schema))
+			}
+			return nil
+		})
+	} else {
+		skipped = append(skipped, domain.ActivationStageSchemas)
 	}
-	s.logger.Debug("after schema activation", zap.Int("count", len(activations)), zap.Int("active_schemas", len(activeSchemas)))
+	g.Go(func() error {
+		recentActivations = s.activateRecent(ctx, input.AgentID, input.TenantID, 24*time.Hour)
+		return nil
+	})
+	_ = g.Wait()
 
-	// 5. Temporal activation (recent episodes)
-	recentActivations := s.activateRecent(ctx, input.AgentID, input.TenantID, 24*time.Hour)
+	activations := cueActivations
+	logFor(ctx, s.logger).Debug("direct activations", zap.Int("count", len(activations)))
+	activations = s.mergeActivations(activations, goalActivations, GoalActivationBoost)
+	for _, items := range schemaActivations {
+		activations = s.mergeActivations(activations, items, SchemaActivationBoost)
+	}
+	logFor(ctx, s.logger).Debug("after schema activation", zap.Int("count", len(activations)), zap.Int("active_schemas", len(activeSchemas)))
 	activations = s.mergeActivations(activations, recentActivations, TemporalActivationBase)
 
+	cache.seed(cueActivations)
+	cache.seed(goalActivations)
+	cache.seed(recentActivations)
+
 	// 6. Spreading activation through associations
-	spreadActivations := s.spread(ctx, input.TenantID, activations, MaxSpreadingDepth)
-	activations = s.mergeActivations(activations, spreadActivations, 1.0)
-	s.logger.Debug("after spreading", zap.Int("count", len(activations)))
+	if fits(SpreadingStageMinBudget) {
+		spreadActivations := s.spread(ctx, cache, input.TenantID, activations, MaxSpreadingDepth, input.AssociationTypes)
+		activations = s.mergeActivations(activations, spreadActivations, 1.0)
+		logFor(ctx, s.logger).Debug("after spreading", zap.Int("count", len(activations)))
+	} else {
+		skipped = append(skipped, domain.ActivationStageSpreading)
+	}
+
+	// What the conversation already shows needn't take up a slot
+	if window := newConversationWindow(input.Context); len(window) > 0 {
+		kept := activations[:0]
+		for _, item := range activations {
+			if !window.contains(item.Content) {
+				kept = append(kept, item)
+			}
+		}
+		activations = kept
+	}
+
+	// Procedures with structured conditions are decided by them alone
+	activations = s.applyConditions(ctx, input, activeSchemas, activations)
 
 	// 7. Competition for limited slots (weighted by confidence)
 	winners := s.compete(activations, session.MaxSlots)
+	s.renderActions(ctx, input, session.CurrentGoal, winners)
 
-	// 8. Save activations to session
-	if err := s.saveActivations(ctx, session, winners, activeSchemas); err != nil {
-		s.logger.Error("failed to save activations", zap.Error(err))
+	trimmed := 0
+	if input.MaxContextTokens > 0 {
+		winners, activeSchemas, trimmed = s.fitContext(winners, activeSchemas, input.MaxContextTokens, input.Citations)
+	}
+	if len(skipped) > 0 || trimmed > 0 {
+		logFor(ctx, s.logger).Debug("activation budget applied",
+			zap.Int("skipped_stages", len(skipped)),
+			zap.Int("trimmed", trimmed))
 	}
 
-	// 9. Update session
-	if err := s.wmStore.UpdateSession(ctx, session); err != nil {
-		s.logger.Error("failed to update session", zap.Error(err))
+	// 8-9. Save activations and update the session. A control activation
+	// leaves the session as it was: the agent never sees these winners, so
+	// they must not linger in its working memory or collect outcome credit.
+	if !input.Control {
+		if err := s.saveActivations(ctx, session, winners, activeSchemas); err != nil {
+			logFor(ctx, s.logger).Error("failed to save activations", zap.Error(err))
+		}
+		if input.ConversationID != nil {
+			s.recordConversationActivations(ctx, *input.ConversationID, session, winners)
+		}
+
+		if err := s.wmStore.UpdateSession(ctx, session); err != nil {
+			logFor(ctx, s.logger).Error("failed to update session", zap.Error(err))
+		}
 	}
 
 	// 10. Build result
 	result := &domain.WorkingMemoryResult{
-		Session:   session,
-		SlotUsage: len(winners),
-		MaxSlots:  session.MaxSlots,
+		Session:       session,
+		SlotUsage:     len(winners),
+		MaxSlots:      session.MaxSlots,
+		SkippedStages: skipped,
+		Trimmed:       trimmed,
 	}
 
 	// Convert to ActivatedContent
@@ -166,18 +350,118 @@ func (s *WorkingMemoryService) Activate(ctx context.Context, input domain.Activa
 		})
 	}
 
+	if input.Vision {
+		s.attachEpisodeImages(ctx, input.TenantID, result.Activations)
+	}
+
 	// Add active schemas
 	result.ActiveSchemas = append(result.ActiveSchemas, activeSchemas...)
 
 	// Assemble context for LLM
-	result.AssembledContext = s.assembleContext(winners, activeSchemas)
+	var markers map[uuid.UUID]string
+	if input.Citations {
+		markers, result.Citations = citationMarkers(winners, activeSchemas)
+	}
+	result.AssembledContext = s.assembleContext(winners, activeSchemas, markers)
 
 	return result, nil
 }
 
-// getOrCreateSession retrieves or creates a working memory session.
+// attachEpisodeImages fills in the image attachments of activated episodes.
+func (s *WorkingMemoryService) attachEpisodeImages(ctx context.Context, tenantID uuid.UUID, activations []domain.ActivatedContent) {
+	if s.episodeStore == nil {
+		return
+	}
+	for i := range activations {
+		if activations[i].Type != domain.ActivatedMemoryTypeEpisodic {
+			continue
+		}
+		ep, err := s.episodeStore.GetByID(ctx, activations[i].ID, tenantID)
+		if err != nil {
+			logFor(ctx, s.logger).Debug("failed to load activated episode attachments", zap.Error(err))
+			continue
+		}
+		activations[i].Attachments = ep.Attachments
+	}
+}
+
+// slotsFor resolves this call's capacity: the caller's override if given,
+// otherwise the agent's base slots expanded by one per extra sub-task in the
+// goal, up to the agent's expanded limit.
+func (s *WorkingMemoryService) slotsFor(ctx context.Context, input domain.ActivationInput, goal string) int {
+	if input.MaxSlots != 0 {
+		return input.MaxSlots
+	}
+	settings, err := s.GetSettings(ctx, input.AgentID, input.TenantID)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to load working memory settings", zap.Error(err))
+		d := domain.DefaultWorkingMemorySettings()
+		settings = &d
+	}
+	slots := settings.Slots + goalComplexity(goal) - 1
+	if slots > settings.ExpandedSlots {
+		slots = settings.ExpandedSlots
+	}
+	return slots
+}
+
+var goalSeparators = strings.NewReplacer(" and then ", ";", " then ", ";", " and ", ";", ",", ";", "\n", ";")
+
+// goalComplexity estimates how many sub-tasks a goal names by splitting it on
+// list separators and conjunctions. An empty goal counts as one.
+func goalComplexity(goal string) int {
+	n := 0
+	for _, part := range strings.Split(goalSeparators.Replace(strings.ToLower(goal)), ";") {
+		if len(strings.Fields(part)) > 0 {
+			n++
+		}
+	}
+	return max(n, 1)
+}
+
+// GetSettings returns the agent's working memory capacity, or the defaults if
+// none are stored.
+func (s *WorkingMemoryService) GetSettings(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.WorkingMemorySettings, error) {
+	if s.settingsStore != nil {
+		settings, err := s.settingsStore.Get(ctx, agentID, tenantID)
+		if err == nil {
+			return settings, nil
+		}
+		if !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+	}
+	d := domain.DefaultWorkingMemorySettings()
+	d.AgentID, d.TenantID = agentID, tenantID
+	return &d, nil
+}
+
+// UpdateSettings stores the agent's working memory capacity. It applies from
+// the agent's next activation.
+func (s *WorkingMemoryService) UpdateSettings(ctx context.Context, settings *domain.WorkingMemorySettings) error {
+	if err := settings.Validate(); err != nil {
+		return &InvalidWorkingMemorySettingsError{Err: err}
+	}
+	if s.settingsStore == nil {
+		return errors.New("working memory settings are not configured")
+	}
+	if err := s.settingsStore.Upsert(ctx, settings); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrAgentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// getOrCreateSession retrieves or creates a working memory session: the
+// conversation's own when the input isolates it, the agent-wide one otherwise.
 func (s *WorkingMemoryService) getOrCreateSession(ctx context.Context, input domain.ActivationInput) (*domain.WorkingMemorySession, error) {
-	session, err := s.wmStore.GetSession(ctx, input.AgentID, input.TenantID)
+	var conversationID *uuid.UUID
+	if input.IsolateConversation {
+		conversationID = input.ConversationID
+	}
+	session, err := s.findSession(ctx, input.AgentID, input.TenantID, conversationID)
 	if err == nil {
 		return session, nil
 	}
@@ -190,6 +474,7 @@ func (s *WorkingMemoryService) getOrCreateSession(ctx context.Context, input dom
 	session = &domain.WorkingMemorySession{
 		AgentID:        input.AgentID,
 		TenantID:       input.TenantID,
+		ConversationID: conversationID,
 		CurrentGoal:    input.Goal,
 		ActiveContext:  input.Context,
 		ReasoningState: make(map[string]any),
@@ -203,21 +488,59 @@ func (s *WorkingMemoryService) getOrCreateSession(ctx context.Context, input dom
 	return session, nil
 }
 
+// findSession looks up the session isolated to conversationID, or the
+// agent-wide session when it is nil.
+func (s *WorkingMemoryService) findSession(ctx context.Context, agentID, tenantID uuid.UUID, conversationID *uuid.UUID) (*domain.WorkingMemorySession, error) {
+	if conversationID != nil {
+		return s.wmStore.GetConversationSession(ctx, agentID, tenantID, *conversationID)
+	}
+	return s.wmStore.GetSession(ctx, agentID, tenantID)
+}
+
 // activateFromCues activates memories based on semantic similarity to cues.
 func (s *WorkingMemoryService) activateFromCues(ctx context.Context, agentID, tenantID uuid.UUID, cues []string) []activatedItem {
-	if len(cues) == 0 || s.embeddingClient == nil {
+	if len(cues) == 0 {
 		return nil
 	}
 
 	// Combine cues for embedding
 	combinedCue := strings.Join(cues, " ")
-	embedding, err := s.embeddingClient.Embed(ctx, combinedCue)
-	if err != nil {
-		s.logger.Debug("failed to embed cues", zap.Error(err))
-		return nil
+
+	// Procedures whose trigger keywords the cues name are found through the
+	// keyword index, sparing the trigger similarity search.
+	var procedures []domain.ProcedureWithScore
+	if s.procedureStore != nil {
+		var err error
+		procedures, err = matchTriggerKeywords(ctx, s.procedureStore, agentID, tenantID, combinedCue, 5)
+		if err != nil {
+			logFor(ctx, s.logger).Debug("procedure keyword lookup failed", zap.Error(err))
+		}
 	}
 
 	var activations []activatedItem
+	addProcedures := func(procedures []domain.ProcedureWithScore) {
+		for _, p := range procedures {
+			activations = append(activations, activatedItem{
+				Type:            domain.ActivatedMemoryTypeProcedural,
+				ID:              p.ID,
+				Content:         fmt.Sprintf("When: %s\nDo: %s", p.TriggerPattern, p.ActionTemplate),
+				Confidence:      p.Confidence * p.SuccessRate,
+				ActivationLevel: p.Score * DirectActivationBoost,
+				Source:          domain.ActivationSourceDirect,
+				Cue:             combinedCue,
+			})
+		}
+	}
+	addProcedures(procedures)
+
+	if s.embeddingClient == nil {
+		return activations
+	}
+	embedding, err := s.embeddingClient.Embed(ctx, combinedCue)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to embed cues", zap.Error(err))
+		return activations
+	}
 
 	// Search semantic memories
 	if s.memoryStore != nil {
@@ -259,20 +582,10 @@ func (s *WorkingMemoryService) activateFromCues(ctx context.Context, agentID, te
 	}
 
 	// Search procedural memories
-	if s.procedureStore != nil {
+	if s.procedureStore != nil && len(procedures) == 0 {
 		procedures, err := s.procedureStore.FindByTriggerSimilarity(ctx, agentID, tenantID, embedding, 0.6, 5)
 		if err == nil {
-			for _, p := range procedures {
-				activations = append(activations, activatedItem{
-					Type:            domain.ActivatedMemoryTypeProcedural,
-					ID:              p.ID,
-					Content:         fmt.Sprintf("When: %s\nDo: %s", p.TriggerPattern, p.ActionTemplate),
-					Confidence:      p.Confidence * p.SuccessRate,
-					ActivationLevel: p.Score * DirectActivationBoost,
-					Source:          domain.ActivationSourceDirect,
-					Cue:             combinedCue,
-				})
-			}
+			addProcedures(procedures)
 		}
 	}
 
@@ -334,8 +647,9 @@ func (s *WorkingMemoryService) activateFromGoal(ctx context.Context, agentID, te
 	return activations
 }
 
-// getActiveSchemas finds schemas that match the current context.
-func (s *WorkingMemoryService) getActiveSchemas(ctx context.Context, agentID, tenantID uuid.UUID, cues []string, context []domain.Message) []domain.SchemaMatch {
+// getActiveSchemas finds schemas that match the current context, by meaning
+// as well as by their applicable contexts and attributes.
+func (s *WorkingMemoryService) getActiveSchemas(ctx context.Context, agentID, tenantID uuid.UUID, cues []string, goal string, context []domain.Message) []domain.SchemaMatch {
 	if s.schemaStore == nil {
 		return nil
 	}
@@ -344,11 +658,13 @@ func (s *WorkingMemoryService) getActiveSchemas(ctx context.Context, agentID, te
 	if err != nil || len(schemas) == 0 {
 		return nil
 	}
+	similarity := s.schemaSimilarities(ctx, agentID, tenantID, cues, goal, len(schemas))
+	schemas, byID := resolveSchemaHierarchy(schemas)
 
 	// Score each schema
 	var matches []domain.SchemaMatch
 	for _, schema := range schemas {
-		score := s.scoreSchemaForContext(schema, cues, context)
+		score := s.scoreSchemaForContext(schema, cues, context, similarity[schema.ID])
 		if score >= MinSchemaMatchScore {
 			matches = append(matches, domain.SchemaMatch{
 				Schema:     schema,
@@ -357,6 +673,8 @@ func (s *WorkingMemoryService) getActiveSchemas(ctx context.Context, agentID, te
 		}
 	}
 
+	matches = propagateSchemaMatches(matches, byID, MinSchemaMatchScore)
+
 	// Sort by score
 	sort.Slice(matches, func(i, j int) bool {
 		return matches[i].MatchScore > matches[j].MatchScore
@@ -370,8 +688,37 @@ func (s *WorkingMemoryService) getActiveSchemas(ctx context.Context, agentID, te
 	return matches
 }
 
-// scoreSchemaForContext scores how well a schema matches the current context.
-func (s *WorkingMemoryService) scoreSchemaForContext(schema domain.Schema, cues []string, context []domain.Message) float32 {
+// schemaSimilarities embeds the cues and goal together and returns the
+// similarity of each of the agent's schemas that clears
+// SchemaMatchSimilarityThreshold. Without an embedding client schemas are matched
+// on keywords alone.
+func (s *WorkingMemoryService) schemaSimilarities(ctx context.Context, agentID, tenantID uuid.UUID, cues []string, goal string, limit int) map[uuid.UUID]float32 {
+	query := strings.TrimSpace(strings.Join(append(append([]string{}, cues...), goal), " "))
+	if s.embeddingClient == nil || query == "" {
+		return nil
+	}
+
+	embedding, err := s.embeddingClient.Embed(ctx, query)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to embed schema query", zap.Error(err))
+		return nil
+	}
+	similar, err := s.schemaStore.FindSimilar(ctx, agentID, tenantID, embedding, SchemaMatchSimilarityThreshold, limit)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to find similar schemas", zap.Error(err))
+		return nil
+	}
+
+	similarity := make(map[uuid.UUID]float32, len(similar))
+	for _, sc := range similar {
+		similarity[sc.ID] = sc.Score
+	}
+	return similarity
+}
+
+// scoreSchemaForContext scores how well a schema matches the current context,
+// blending keyword matches with its embedding similarity to the cues and goal.
+func (s *WorkingMemoryService) scoreSchemaForContext(schema domain.Schema, cues []string, context []domain.Message, similarity float32) float32 {
 	var score float32
 
 	// Check cue overlap with applicable contexts
@@ -393,6 +740,8 @@ func (s *WorkingMemoryService) scoreSchemaForContext(schema domain.Schema, cues
 		}
 	}
 
+	score += similarity * EmbeddingSimilarityWeight
+
 	// Weight by schema confidence
 	score *= schema.Confidence
 
@@ -405,42 +754,38 @@ func (s *WorkingMemoryService) scoreSchemaForContext(schema domain.Schema, cues
 }
 
 // activateFromSchema activates memories related to an active schema.
-func (s *WorkingMemoryService) activateFromSchema(ctx context.Context, _, tenantID uuid.UUID, schema domain.Schema) []activatedItem {
+func (s *WorkingMemoryService) activateFromSchema(ctx context.Context, cache activationCache, tenantID uuid.UUID, schema domain.Schema) []activatedItem {
 	var activations []activatedItem
 
 	// Activate evidence memories
 	for _, memID := range schema.EvidenceMemories {
-		if s.memoryStore != nil {
-			mem, err := s.memoryStore.GetByID(ctx, memID, tenantID)
-			if err == nil {
-				activations = append(activations, activatedItem{
-					Type:            domain.ActivatedMemoryTypeSemantic,
-					ID:              mem.ID,
-					Content:         mem.Content,
-					Confidence:      mem.Confidence,
-					ActivationLevel: 0.6, // Moderate activation from schema
-					Source:          domain.ActivationSourceSchema,
-					Cue:             "schema: " + schema.Name,
-				})
-			}
+		content, confidence, ok := s.getMemoryContent(ctx, cache, domain.ActivatedMemoryTypeSemantic, memID, tenantID)
+		if ok {
+			activations = append(activations, activatedItem{
+				Type:            domain.ActivatedMemoryTypeSemantic,
+				ID:              memID,
+				Content:         content,
+				Confidence:      confidence,
+				ActivationLevel: 0.6, // Moderate activation from schema
+				Source:          domain.ActivationSourceSchema,
+				Cue:             "schema: " + schema.Name,
+			})
 		}
 	}
 
 	// Activate evidence episodes
 	for _, epID := range schema.EvidenceEpisodes {
-		if s.episodeStore != nil {
-			ep, err := s.episodeStore.GetByID(ctx, epID, tenantID)
-			if err == nil {
-				activations = append(activations, activatedItem{
-					Type:            domain.ActivatedMemoryTypeEpisodic,
-					ID:              ep.ID,
-					Content:         ep.RawContent,
-					Confidence:      ep.MemoryStrength,
-					ActivationLevel: 0.5,
-					Source:          domain.ActivationSourceSchema,
-					Cue:             "schema: " + schema.Name,
-				})
-			}
+		content, confidence, ok := s.getMemoryContent(ctx, cache, domain.ActivatedMemoryTypeEpisodic, epID, tenantID)
+		if ok {
+			activations = append(activations, activatedItem{
+				Type:            domain.ActivatedMemoryTypeEpisodic,
+				ID:              epID,
+				Content:         content,
+				Confidence:      confidence,
+				ActivationLevel: 0.5,
+				Source:          domain.ActivationSourceSchema,
+				Cue:             "schema: " + schema.Name,
+			})
 		}
 	}
 
@@ -450,15 +795,15 @@ func (s *WorkingMemoryService) activateFromSchema(ctx context.Context, _, tenant
 // activateRecent activates recently accessed memories.
 func (s *WorkingMemoryService) activateRecent(ctx context.Context, agentID, tenantID uuid.UUID, window time.Duration) []activatedItem {
 	var activations []activatedItem
-	cutoff := time.Now().Add(-window)
+	cutoff := timeNow().Add(-window)
 
 	// Get recent episodes
 	if s.episodeStore != nil {
-		episodes, err := s.episodeStore.GetByTimeRange(ctx, agentID, tenantID, cutoff, time.Now())
+		episodes, err := s.episodeStore.GetByTimeRange(ctx, agentID, tenantID, cutoff, timeNow())
 		if err == nil {
 			for _, ep := range episodes {
 				// Exponential recency decay
-				hoursSinceOccurred := time.Since(ep.OccurredAt).Hours()
+				hoursSinceOccurred := timeNow().Sub(ep.OccurredAt).Hours()
 				recencyLevel := float32(math.Exp(-RecencyDecay * hoursSinceOccurred))
 				if recencyLevel < MinActivationLevel {
 					continue
@@ -480,11 +825,21 @@ func (s *WorkingMemoryService) activateRecent(ctx context.Context, agentID, tena
 	return activations
 }
 
-// spread performs spreading activation through memory associations.
-func (s *WorkingMemoryService) spread(ctx context.Context, tenantID uuid.UUID, seeds []activatedItem, maxDepth int) []activatedItem {
+// spread performs spreading activation through memory associations, only
+// along the given association types when any are given. Each type carries
+// activation by its domain.AssociationDecayMultipliers factor, as each
+// relation type does in graph traversal.
+func (s *WorkingMemoryService) spread(ctx context.Context, cache activationCache, tenantID uuid.UUID, seeds []activatedItem, maxDepth int, types []string) []activatedItem {
 	if s.assocStore == nil || maxDepth == 0 {
 		return nil
 	}
+	var allowed map[string]bool
+	if len(types) > 0 {
+		allowed = make(map[string]bool, len(types))
+		for _, t := range types {
+			allowed[t] = true
+		}
+	}
 
 	var spread []activatedItem
 	visited := make(map[string]bool)
@@ -512,6 +867,9 @@ func (s *WorkingMemoryService) spread(ctx context.Context, tenantID uuid.UUID, s
 			}
 
 			for _, assoc := range assocs {
+				if allowed != nil && !allowed[assoc.AssociationType] {
+					continue
+				}
 				key := fmt.Sprintf("%s:%s", assoc.TargetMemoryType, assoc.TargetMemoryID)
 				if visited[key] {
 					continue
@@ -520,13 +878,16 @@ func (s *WorkingMemoryService) spread(ctx context.Context, tenantID uuid.UUID, s
 
 				// Calculate spread activation
 				spreadLevel := item.ActivationLevel * assoc.AssociationStrength * decayFactor
+				if m, ok := domain.AssociationDecayMultipliers[assoc.AssociationType]; ok {
+					spreadLevel *= float32(m)
+				}
 				if spreadLevel < MinActivationLevel {
 					continue
 				}
 
 				// Get the target memory content
-				content, confidence := s.getMemoryContent(ctx, assoc.TargetMemoryType, assoc.TargetMemoryID, tenantID)
-				if content == "" {
+				content, confidence, ok := s.getMemoryContent(ctx, cache, assoc.TargetMemoryType, assoc.TargetMemoryID, tenantID)
+				if !ok || content == "" {
 					continue
 				}
 
@@ -537,7 +898,7 @@ func (s *WorkingMemoryService) spread(ctx context.Context, tenantID uuid.UUID, s
 					Confidence:      confidence,
 					ActivationLevel: spreadLevel,
 					Source:          domain.ActivationSourceSpread,
-					Cue:             fmt.Sprintf("spread from %s", item.Type),
+					Cue:             fmt.Sprintf("spread from %s via %s", item.Type, assoc.AssociationType),
 				}
 				spread = append(spread, activated)
 				next = append(next, activated)
@@ -549,39 +910,50 @@ func (s *WorkingMemoryService) spread(ctx context.Context, tenantID uuid.UUID, s
 	return spread
 }
 
-// getMemoryContent retrieves content for a memory by type.
-func (s *WorkingMemoryService) getMemoryContent(ctx context.Context, memType domain.ActivatedMemoryType, memID, tenantID uuid.UUID) (string, float32) {
+// getMemoryContent retrieves content for a memory by type, consulting the
+// request's cache first. ok is false when the memory could not be loaded.
+func (s *WorkingMemoryService) getMemoryContent(ctx context.Context, cache activationCache, memType domain.ActivatedMemoryType, memID, tenantID uuid.UUID) (string, float32, bool) {
+	ref := memoryRef{memType, memID}
+	if c, hit := cache[ref]; hit {
+		return c.content, c.confidence, c.found
+	}
+	content, confidence, ok := s.fetchMemoryContent(ctx, memType, memID, tenantID)
+	cache[ref] = fetchedContent{content, confidence, ok}
+	return content, confidence, ok
+}
+
+func (s *WorkingMemoryService) fetchMemoryContent(ctx context.Context, memType domain.ActivatedMemoryType, memID, tenantID uuid.UUID) (string, float32, bool) {
 	switch memType {
 	case domain.ActivatedMemoryTypeSemantic:
 		if s.memoryStore != nil {
 			mem, err := s.memoryStore.GetByID(ctx, memID, tenantID)
 			if err == nil {
-				return mem.Content, mem.Confidence
+				return mem.Content, mem.Confidence, true
 			}
 		}
 	case domain.ActivatedMemoryTypeEpisodic:
 		if s.episodeStore != nil {
 			ep, err := s.episodeStore.GetByID(ctx, memID, tenantID)
 			if err == nil {
-				return ep.RawContent, ep.MemoryStrength
+				return ep.RawContent, ep.MemoryStrength, true
 			}
 		}
 	case domain.ActivatedMemoryTypeProcedural:
 		if s.procedureStore != nil {
 			proc, err := s.procedureStore.GetByID(ctx, memID, tenantID)
 			if err == nil {
-				return fmt.Sprintf("When: %s\nDo: %s", proc.TriggerPattern, proc.ActionTemplate), proc.Confidence
+				return fmt.Sprintf("When: %s\nDo: %s", proc.TriggerPattern, proc.ActionTemplate), proc.Confidence, true
 			}
 		}
 	case domain.ActivatedMemoryTypeSchema:
 		if s.schemaStore != nil {
 			schema, err := s.schemaStore.GetByID(ctx, memID, tenantID)
 			if err == nil {
-				return fmt.Sprintf("%s: %s", schema.Name, schema.Description), schema.Confidence
+				return fmt.Sprintf("%s: %s", schema.Name, schema.Description), schema.Confidence, true
 			}
 		}
 	}
-	return "", 0
+	return "", 0, false
 }
 
 // mergeActivations merges two activation lists, combining duplicates.
@@ -619,7 +991,106 @@ func (s *WorkingMemoryService) mergeActivations(a, b []activatedItem, boost floa
 	return result
 }
 
+// applyConditions evaluates the agent's procedure conditions against the
+// call. A procedure whose conditions all hold is activated by them; one whose
+// conditions fail is removed, however strongly other sources activated it.
+func (s *WorkingMemoryService) applyConditions(ctx context.Context, input domain.ActivationInput, activeSchemas []domain.SchemaMatch, activations []activatedItem) []activatedItem {
+	if s.procedureStore == nil {
+		return activations
+	}
+	procedures, err := s.procedureStore.FindConditional(ctx, input.AgentID, input.TenantID)
+	if err != nil {
+		logFor(ctx, s.logger).Debug("failed to load conditional procedures", zap.Error(err))
+		return activations
+	}
+	if len(procedures) == 0 {
+		return activations
+	}
+
+	cc := domain.ConditionContext{
+		Now:            timeNow(),
+		UserAttributes: input.UserAttributes,
+		Topics:         input.Topics,
+	}
+	for _, m := range activeSchemas {
+		cc.ActiveSchemas = append(cc.ActiveSchemas, m.Schema.Name)
+	}
+
+	decided := make(map[uuid.UUID]bool, len(procedures))
+	var fired []activatedItem
+	for _, p := range procedures {
+		decided[p.ID] = true
+		if !p.Conditions.Match(cc) {
+			continue
+		}
+		fired = append(fired, activatedItem{
+			Type:            domain.ActivatedMemoryTypeProcedural,
+			ID:              p.ID,
+			Content:         fmt.Sprintf("When: %s\nDo: %s", p.TriggerPattern, p.ActionTemplate),
+			Confidence:      p.Confidence * p.SuccessRate,
+			ActivationLevel: DirectActivationBoost,
+			Source:          domain.ActivationSourceCondition,
+		})
+	}
+
+	kept := activations[:0]
+	for _, item := range activations {
+		if item.Type == domain.ActivatedMemoryTypeProcedural && decided[item.ID] {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	logFor(ctx, s.logger).Debug("procedure conditions evaluated",
+		zap.Int("conditional", len(procedures)),
+		zap.Int("fired", len(fired)))
+	return append(kept, fired...)
+}
+
+// renderActions fills the action templates of winning procedures: the goal,
+// first topic and user attributes come with the call, and each entity type
+// resolves to the first entity of that type the winning memories mention,
+// strongest memory first.
+func (s *WorkingMemoryService) renderActions(ctx context.Context, input domain.ActivationInput, goal string, winners []activatedItem) {
+	templated := false
+	for _, item := range winners {
+		if item.Type == domain.ActivatedMemoryTypeProcedural && strings.Contains(item.Content, "{{") {
+			templated = true
+			break
+		}
+	}
+	if !templated {
+		return
+	}
+
+	vars := templateVars(goal, input.Topics, input.UserAttributes)
+	if s.entityStore != nil {
+		for _, item := range winners {
+			if item.Type != domain.ActivatedMemoryTypeSemantic {
+				continue
+			}
+			entities, err := s.entityStore.GetEntitiesForMemory(ctx, item.ID)
+			if err != nil {
+				logFor(ctx, s.logger).Debug("failed to load memory entities for action templates", zap.Error(err))
+				continue
+			}
+			for _, e := range entities {
+				name := string(e.EntityType)
+				if _, ok := vars[name]; !ok && domain.KnownTemplateVariable(name) {
+					vars[name] = e.Name
+				}
+			}
+		}
+	}
+
+	for i := range winners {
+		if winners[i].Type == domain.ActivatedMemoryTypeProcedural {
+			winners[i].Content = domain.RenderActionTemplate(winners[i].Content, vars).Rendered
+		}
+	}
+}
+
 // compete selects the top memories for limited working memory slots.
+// Procedures fired by their conditions are placed first.
 func (s *WorkingMemoryService) compete(activations []activatedItem, maxSlots int) []activatedItem {
 	if len(activations) == 0 {
 		return nil
@@ -627,6 +1098,11 @@ func (s *WorkingMemoryService) compete(activations []activatedItem, maxSlots int
 
 	// Sort by effective score: activation_level * confidence
 	sort.Slice(activations, func(i, j int) bool {
+		condI := activations[i].Source == domain.ActivationSourceCondition
+		condJ := activations[j].Source == domain.ActivationSourceCondition
+		if condI != condJ {
+			return condI
+		}
 		scoreI := activations[i].ActivationLevel * activations[i].Confidence
 		scoreJ := activations[j].ActivationLevel * activations[j].Confidence
 		return scoreI > scoreJ
@@ -640,6 +1116,36 @@ func (s *WorkingMemoryService) compete(activations []activatedItem, maxSlots int
 	return activations
 }
 
+// recordConversationActivations remembers the semantic memories and
+// procedures that won a slot, for outcome attribution. Episodes and schemas
+// are left out: outcomes only adjust beliefs and procedure stats.
+func (s *WorkingMemoryService) recordConversationActivations(ctx context.Context, conversationID uuid.UUID, session *domain.WorkingMemorySession, items []activatedItem) {
+	if s.convActStore == nil {
+		return
+	}
+	var acts []domain.ConversationActivation
+	for _, item := range items {
+		if item.Type != domain.ActivatedMemoryTypeSemantic && item.Type != domain.ActivatedMemoryTypeProcedural {
+			continue
+		}
+		level := item.ActivationLevel
+		if level > 1 {
+			level = 1
+		}
+		acts = append(acts, domain.ConversationActivation{
+			TenantID:        session.TenantID,
+			AgentID:         session.AgentID,
+			ConversationID:  conversationID,
+			MemoryType:      item.Type,
+			MemoryID:        item.ID,
+			ActivationLevel: level,
+		})
+	}
+	if err := s.convActStore.Record(ctx, acts); err != nil {
+		logFor(ctx, s.logger).Warn("failed to record conversation activations", zap.Error(err))
+	}
+}
+
 // saveActivations persists activations to the database.
 func (s *WorkingMemoryService) saveActivations(ctx context.Context, session *domain.WorkingMemorySession, items []activatedItem, schemas []domain.SchemaMatch) error {
 	// Clear existing activations
@@ -651,40 +1157,48 @@ func (s *WorkingMemoryService) saveActivations(ctx context.Context, session *dom
 	}
 
 	// Save new activations
+	activations := make([]domain.WorkingMemoryActivation, len(items))
 	for i, item := range items {
 		pos := i + 1
-		activation := &domain.WorkingMemoryActivation{
+		// Boosts can push a level past 1; the column is bounded, and one
+		// out-of-range row would fail the whole batch.
+		level := item.ActivationLevel
+		if level > 1 {
+			level = 1
+		}
+		activations[i] = domain.WorkingMemoryActivation{
 			SessionID:        session.ID,
 			TenantID:         session.TenantID,
 			MemoryType:       item.Type,
 			MemoryID:         item.ID,
-			ActivationLevel:  item.ActivationLevel,
+			ActivationLevel:  level,
 			ActivationSource: item.Source,
 			ActivationCue:    item.Cue,
 			SlotPosition:     &pos,
 		}
-		if err := s.wmStore.CreateActivation(ctx, activation); err != nil {
-			s.logger.Debug("failed to save activation", zap.Error(err))
-		}
+	}
+	if err := s.wmStore.CreateActivationsBatch(ctx, activations); err != nil {
+		logFor(ctx, s.logger).Debug("failed to save activations", zap.Error(err))
 	}
 
 	// Save schema activations
-	for _, match := range schemas {
-		schemaAct := &domain.SchemaActivation{
+	schemaActs := make([]domain.SchemaActivation, len(schemas))
+	for i, match := range schemas {
+		schemaActs[i] = domain.SchemaActivation{
 			SessionID:  session.ID,
 			SchemaID:   match.Schema.ID,
 			MatchScore: match.MatchScore,
 		}
-		if err := s.wmStore.CreateSchemaActivation(ctx, schemaAct); err != nil {
-			s.logger.Debug("failed to save schema activation", zap.Error(err))
-		}
+	}
+	if err := s.wmStore.CreateSchemaActivationsBatch(ctx, schemaActs); err != nil {
+		logFor(ctx, s.logger).Debug("failed to save schema activations", zap.Error(err))
 	}
 
 	return nil
 }
 
 // assembleContext creates a formatted context string for LLM injection.
-func (s *WorkingMemoryService) assembleContext(items []activatedItem, schemas []domain.SchemaMatch) string {
+func (s *WorkingMemoryService) assembleContext(items []activatedItem, schemas []domain.SchemaMatch, markers map[uuid.UUID]string) string {
 	if len(items) == 0 && len(schemas) == 0 {
 		return ""
 	}
@@ -697,16 +1211,16 @@ func (s *WorkingMemoryService) assembleContext(items []activatedItem, schemas []
 	for _, item := range items {
 		switch item.Type {
 		case domain.ActivatedMemoryTypeSemantic:
-			beliefs = append(beliefs, fmt.Sprintf("- %s (confidence: %.0f%%)", item.Content, item.Confidence*100))
+			beliefs = append(beliefs, fmt.Sprintf("- %s (confidence: %.0f%%)", item.Content, item.Confidence*100)+citeMarker(markers, item.ID))
 		case domain.ActivatedMemoryTypeEpisodic:
 			// Truncate long episodes
 			content := item.Content
 			if len(content) > 200 {
 				content = content[:200] + "..."
 			}
-			episodes = append(episodes, fmt.Sprintf("- %s", content))
+			episodes = append(episodes, fmt.Sprintf("- %s", content)+citeMarker(markers, item.ID))
 		case domain.ActivatedMemoryTypeProcedural:
-			procedures = append(procedures, fmt.Sprintf("- %s", item.Content))
+			procedures = append(procedures, fmt.Sprintf("- %s", item.Content)+citeMarker(markers, item.ID))
 		}
 	}
 
@@ -725,7 +1239,7 @@ func (s *WorkingMemoryService) assembleContext(items []activatedItem, schemas []
 	if len(schemas) > 0 {
 		var schemaStrs []string
 		for _, sm := range schemas {
-			schemaStrs = append(schemaStrs, fmt.Sprintf("- %s: %s", sm.Schema.Name, sm.Schema.Description))
+			schemaStrs = append(schemaStrs, fmt.Sprintf("- %s: %s", sm.Schema.Name, sm.Schema.Description)+citeMarker(markers, sm.Schema.ID))
 		}
 		parts = append(parts, "**Active Mental Models:**\n"+strings.Join(schemaStrs, "\n"))
 	}
@@ -733,9 +1247,116 @@ func (s *WorkingMemoryService) assembleContext(items []activatedItem, schemas []
 	return strings.Join(parts, "\n\n")
 }
 
-// GetSession retrieves the current working memory session for an agent.
-func (s *WorkingMemoryService) GetSession(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.WorkingMemorySession, error) {
-	session, err := s.wmStore.GetSession(ctx, agentID, tenantID)
+// fitContext shrinks the assembled context to at most maxTokens (estimated).
+// Each step drops the lowest-scoring entry of the largest section, so no
+// single kind of content crowds out the rest. It returns what is kept and
+// how many entries were dropped.
+func (s *WorkingMemoryService) fitContext(items []activatedItem, schemas []domain.SchemaMatch, maxTokens int, cite bool) ([]activatedItem, []domain.SchemaMatch, int) {
+	var markers map[uuid.UUID]string
+	if cite {
+		// Markers only get shorter as entries are dropped, so the full
+		// set's markers give an upper bound.
+		markers, _ = citationMarkers(items, schemas)
+	}
+
+	dropped := 0
+	for len(items)+len(schemas) > 0 && estimateTokens(s.assembleContext(items, schemas, markers)) > maxTokens {
+		largest, largestTokens := domain.ActivatedMemoryTypeSchema, estimateTokens(s.assembleContext(nil, schemas, markers))
+		for _, t := range []domain.ActivatedMemoryType{domain.ActivatedMemoryTypeSemantic, domain.ActivatedMemoryTypeEpisodic, domain.ActivatedMemoryTypeProcedural} {
+			var section []activatedItem
+			for _, item := range items {
+				if item.Type == t {
+					section = append(section, item)
+				}
+			}
+			if tokens := estimateTokens(s.assembleContext(section, nil, markers)); tokens > largestTokens {
+				largest, largestTokens = t, tokens
+			}
+		}
+
+		// Items and schemas are both sorted best first.
+		if largest == domain.ActivatedMemoryTypeSchema {
+			schemas = schemas[:len(schemas)-1]
+		} else {
+			for i := len(items) - 1; i >= 0; i-- {
+				if items[i].Type == largest {
+					items = append(items[:i:i], items[i+1:]...)
+					break
+				}
+			}
+		}
+		dropped++
+	}
+	return items, schemas, dropped
+}
+
+func estimateTokens(s string) int {
+	return (len(s) + ContextCharsPerToken - 1) / ContextCharsPerToken
+}
+
+// citationPrefixLength is the shortest ID prefix a citation marker uses.
+const citationPrefixLength = 8
+
+// citationMarkers names each item and schema of an assembled context with a
+// marker of its kind and ID prefix ("m:3f2a9c1b"). Prefixes start at
+// citationPrefixLength hex digits and grow until no two markers collide. It
+// returns the marker of each ID and what each marker cites.
+func citationMarkers(items []activatedItem, schemas []domain.SchemaMatch) (map[uuid.UUID]string, map[string]domain.ContextCitation) {
+	var cited []domain.ContextCitation
+	for _, item := range items {
+		cited = append(cited, domain.ContextCitation{Type: item.Type, ID: item.ID, Confidence: item.Confidence})
+	}
+	for _, sm := range schemas {
+		cited = append(cited, domain.ContextCitation{Type: domain.ActivatedMemoryTypeSchema, ID: sm.Schema.ID, Confidence: sm.Schema.Confidence})
+	}
+	if len(cited) == 0 {
+		return nil, nil
+	}
+
+	for n := citationPrefixLength; ; n++ {
+		markers := make(map[uuid.UUID]string, len(cited))
+		citations := make(map[string]domain.ContextCitation, len(cited))
+		unique := true
+		for _, c := range cited {
+			marker := citationKind(c.Type) + ":" + strings.ReplaceAll(c.ID.String(), "-", "")[:n]
+			if prev, ok := citations[marker]; ok && prev.ID != c.ID {
+				unique = false
+				break
+			}
+			markers[c.ID] = marker
+			citations[marker] = c
+		}
+		if unique || n == 32 {
+			return markers, citations
+		}
+	}
+}
+
+func citationKind(t domain.ActivatedMemoryType) string {
+	switch t {
+	case domain.ActivatedMemoryTypeEpisodic:
+		return domain.CitationKindEpisode
+	case domain.ActivatedMemoryTypeProcedural:
+		return domain.CitationKindProcedure
+	case domain.ActivatedMemoryTypeSchema:
+		return domain.CitationKindSchema
+	}
+	return domain.CitationKindMemory
+}
+
+// citeMarker is the bracketed marker appended to a cited line, or "" when
+// citations are off.
+func citeMarker(markers map[uuid.UUID]string, id uuid.UUID) string {
+	if m, ok := markers[id]; ok {
+		return " [" + m + "]"
+	}
+	return ""
+}
+
+// GetSession retrieves the current working memory session for an agent, or
+// the one isolated to conversationID when it is set.
+func (s *WorkingMemoryService) GetSession(ctx context.Context, agentID, tenantID uuid.UUID, conversationID *uuid.UUID) (*domain.WorkingMemorySession, error) {
+	session, err := s.findSession(ctx, agentID, tenantID, conversationID)
 	if err != nil {
 		if errors.Is(err, store.ErrNotFound) {
 			return nil, ErrSessionNotFound
@@ -758,14 +1379,45 @@ func (s *WorkingMemoryService) GetSession(ctx context.Context, agentID, tenantID
 	return session, nil
 }
 
-// ClearSession clears the working memory session for an agent.
-func (s *WorkingMemoryService) ClearSession(ctx context.Context, agentID, tenantID uuid.UUID) error {
+// ListSessions reports the agent's active sessions, agent-wide and per
+// conversation, with the slots each fills and their total.
+func (s *WorkingMemoryService) ListSessions(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.WorkingMemoryUsage, error) {
+	sessions, err := s.wmStore.ListSessions(ctx, agentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	usage := &domain.WorkingMemoryUsage{AgentID: agentID, Sessions: make([]domain.WorkingMemorySessionUsage, 0, len(sessions))}
+	for _, session := range sessions {
+		activations, err := s.wmStore.GetActivations(ctx, session.ID)
+		if err != nil {
+			return nil, err
+		}
+		usage.Sessions = append(usage.Sessions, domain.WorkingMemorySessionUsage{
+			SessionID:      session.ID,
+			ConversationID: session.ConversationID,
+			CurrentGoal:    session.CurrentGoal,
+			SlotsUsed:      len(activations),
+			MaxSlots:       session.MaxSlots,
+			LastActivityAt: session.LastActivityAt,
+		})
+		usage.TotalSlotsUsed += len(activations)
+		usage.TotalMaxSlots += session.MaxSlots
+	}
+	return usage, nil
+}
+
+// ClearSession clears the working memory session for an agent, or the one
+// isolated to conversationID when it is set.
+func (s *WorkingMemoryService) ClearSession(ctx context.Context, agentID, tenantID uuid.UUID, conversationID *uuid.UUID) error {
+	if conversationID != nil {
+		return s.wmStore.DeleteConversationSession(ctx, agentID, tenantID, *conversationID)
+	}
 	return s.wmStore.DeleteSession(ctx, agentID, tenantID)
 }
 
 // UpdateGoal updates the current goal in working memory.
-func (s *WorkingMemoryService) UpdateGoal(ctx context.Context, agentID, tenantID uuid.UUID, goal string) error {
-	session, err := s.wmStore.GetSession(ctx, agentID, tenantID)
+func (s *WorkingMemoryService) UpdateGoal(ctx context.Context, agentID, tenantID uuid.UUID, conversationID *uuid.UUID, goal string) error {
+	session, err := s.findSession(ctx, agentID, tenantID, conversationID)
 	if err != nil {
 		return err
 	}
@@ -774,6 +1426,216 @@ func (s *WorkingMemoryService) UpdateGoal(ctx context.Context, agentID, tenantID
 	return s.wmStore.UpdateSession(ctx, session)
 }
 
+// GetReasoningState returns the session's reasoning scratchpad.
+func (s *WorkingMemoryService) GetReasoningState(ctx context.Context, agentID, tenantID uuid.UUID, conversationID *uuid.UUID) (*domain.WorkingMemorySession, error) {
+	session, err := s.findSession(ctx, agentID, tenantID, conversationID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	if session.ReasoningState == nil {
+		session.ReasoningState = make(map[string]any)
+	}
+	return session, nil
+}
+
+// PatchReasoningState merges patch into the session's reasoning state: each
+// key replaces the stored value, and a null value removes the key.
+func (s *WorkingMemoryService) PatchReasoningState(ctx context.Context, agentID, tenantID uuid.UUID, conversationID *uuid.UUID, patch map[string]any) (*domain.WorkingMemorySession, error) {
+	session, err := s.GetReasoningState(ctx, agentID, tenantID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range patch {
+		if v == nil {
+			delete(session.ReasoningState, k)
+			continue
+		}
+		if k == domain.ReasoningKeyConclusions || k == domain.ReasoningKeyOpenQuestions {
+			if _, ok := stringList(v); !ok {
+				return nil, &InvalidReasoningStateError{Err: fmt.Errorf("%s must be a list of strings", k)}
+			}
+		}
+		session.ReasoningState[k] = v
+	}
+	encoded, err := json.Marshal(session.ReasoningState)
+	if err != nil {
+		return nil, &InvalidReasoningStateError{Err: err}
+	}
+	if len(encoded) > domain.MaxReasoningStateBytes {
+		return nil, &InvalidReasoningStateError{Err: fmt.Errorf("reasoning state exceeds %d bytes", domain.MaxReasoningStateBytes)}
+	}
+
+	if err := s.wmStore.UpdateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// goalQuery is the text goal-directed activation embeds: the goal followed by
+// the session's open questions, so unresolved reasoning pulls in the memories
+// that could answer it.
+func goalQuery(goal string, state map[string]any) string {
+	questions, _ := stringList(state[domain.ReasoningKeyOpenQuestions])
+	parts := make([]string, 0, len(questions)+1)
+	if goal != "" {
+		parts = append(parts, goal)
+	}
+	for _, q := range questions {
+		if q = strings.TrimSpace(q); q != "" {
+			parts = append(parts, q)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// stringList reads a JSON-decoded list of strings.
+func stringList(v any) ([]string, bool) {
+	switch list := v.(type) {
+	case []string:
+		return list, true
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, str)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// Snapshot saves the agent's current session under label.
+func (s *WorkingMemoryService) Snapshot(ctx context.Context, agentID, tenantID uuid.UUID, label string) (*domain.WorkingMemorySnapshot, error) {
+	if s.snapshotStore == nil {
+		return nil, errors.New("working memory snapshots are not configured")
+	}
+	session, err := s.GetSession(ctx, agentID, tenantID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &domain.WorkingMemorySnapshot{
+		TenantID:       tenantID,
+		AgentID:        agentID,
+		Label:          label,
+		Goal:           session.CurrentGoal,
+		ActiveContext:  session.ActiveContext,
+		ReasoningState: session.ReasoningState,
+		MaxSlots:       session.MaxSlots,
+		Activations:    session.Activations,
+		ActiveSchemas:  session.ActiveSchemas,
+	}
+	if err := s.snapshotStore.Create(ctx, snap); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrAgentNotFound
+		}
+		return nil, err
+	}
+	return snap, nil
+}
+
+// ListSnapshots returns the agent's snapshots, newest first.
+func (s *WorkingMemoryService) ListSnapshots(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.WorkingMemorySnapshot, error) {
+	if s.snapshotStore == nil {
+		return nil, nil
+	}
+	return s.snapshotStore.ListByAgent(ctx, agentID, tenantID)
+}
+
+func (s *WorkingMemoryService) DeleteSnapshot(ctx context.Context, id, tenantID uuid.UUID) error {
+	if s.snapshotStore == nil {
+		return ErrSnapshotNotFound
+	}
+	if err := s.snapshotStore.Delete(ctx, id, tenantID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrSnapshotNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the agent's session state with the snapshot's,
+// creating the session if it has ended. Activations of memories and schemas
+// that no longer exist are dropped; the count is returned alongside the
+// restored session.
+func (s *WorkingMemoryService) RestoreSnapshot(ctx context.Context, id, tenantID uuid.UUID) (*domain.WorkingMemorySession, int, error) {
+	if s.snapshotStore == nil {
+		return nil, 0, ErrSnapshotNotFound
+	}
+	snap, err := s.snapshotStore.GetByID(ctx, id, tenantID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, 0, ErrSnapshotNotFound
+		}
+		return nil, 0, err
+	}
+
+	session, err := s.getOrCreateSession(ctx, domain.ActivationInput{AgentID: snap.AgentID, TenantID: tenantID})
+	if err != nil {
+		return nil, 0, fmt.Errorf("get or create session: %w", err)
+	}
+	session.CurrentGoal = snap.Goal
+	session.ActiveContext = snap.ActiveContext
+	session.ReasoningState = snap.ReasoningState
+	if session.ReasoningState == nil {
+		session.ReasoningState = make(map[string]any)
+	}
+	if domain.ValidSlotCount(snap.MaxSlots) {
+		session.MaxSlots = snap.MaxSlots
+	}
+
+	dropped := 0
+	cache := newActivationCache()
+	activations := make([]domain.WorkingMemoryActivation, 0, len(snap.Activations))
+	for _, a := range snap.Activations {
+		if _, _, ok := s.getMemoryContent(ctx, cache, a.MemoryType, a.MemoryID, tenantID); !ok {
+			dropped++
+			continue
+		}
+		a.ID = uuid.Nil
+		a.SessionID, a.TenantID = session.ID, tenantID
+		a.ActivatedAt = time.Time{}
+		a.Content, a.MemoryConfidence = "", 0
+		activations = append(activations, a)
+	}
+	schemaActs := make([]domain.SchemaActivation, 0, len(snap.ActiveSchemas))
+	for _, a := range snap.ActiveSchemas {
+		if _, _, ok := s.getMemoryContent(ctx, cache, domain.ActivatedMemoryTypeSchema, a.SchemaID, tenantID); !ok {
+			dropped++
+			continue
+		}
+		schemaActs = append(schemaActs, domain.SchemaActivation{SessionID: session.ID, SchemaID: a.SchemaID, MatchScore: a.MatchScore})
+	}
+
+	if err := s.wmStore.ClearActivations(ctx, session.ID); err != nil {
+		return nil, 0, err
+	}
+	if err := s.wmStore.ClearSchemaActivations(ctx, session.ID); err != nil {
+		return nil, 0, err
+	}
+	if err := s.wmStore.CreateActivationsBatch(ctx, activations); err != nil {
+		return nil, 0, err
+	}
+	if err := s.wmStore.CreateSchemaActivationsBatch(ctx, schemaActs); err != nil {
+		return nil, 0, err
+	}
+	if err := s.wmStore.UpdateSession(ctx, session); err != nil {
+		return nil, 0, err
+	}
+
+	session.Activations = activations
+	session.ActiveSchemas = schemaActs
+	return session, dropped, nil
+}
+
 // CreateAssociation creates a memory association for spreading activation.
 func (s *WorkingMemoryService) CreateAssociation(ctx context.Context, assoc *domain.MemoryAssociation) error {
 	return s.assocStore.Create(ctx, assoc)
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWorkingMemoryFlushInterval = 30 * time.Second
+	workingMemoryFlushTimeout         = 30 * time.Second
+)
+
+// WorkingMemoryFlushService periodically persists working memory buffered in
+// a cache (see store.RedisWorkingMemoryStore) to Postgres, and once more on
+// Stop so a clean shutdown loses nothing.
+type WorkingMemoryFlushService struct {
+	flusher domain.WorkingMemoryFlusher
+	logger  *zap.Logger
+
+	interval   time.Duration
+	stopCh     chan struct{}
+	cancelRuns context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewWorkingMemoryFlushService creates the flush worker. With a nil flusher
+// (no cache configured) Start and Stop are no-ops.
+func NewWorkingMemoryFlushService(f domain.WorkingMemoryFlusher, logger *zap.Logger) *WorkingMemoryFlushService {
+	return &WorkingMemoryFlushService{
+		flusher:  f,
+		logger:   logger,
+		interval: defaultWorkingMemoryFlushInterval,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+func (s *WorkingMemoryFlushService) SetInterval(d time.Duration) {
+	s.interval = d
+}
+
+// Start runs the flush on a periodic schedule in a background goroutine.
+func (s *WorkingMemoryFlushService) Start() {
+	if s.flusher == nil {
+		return
+	}
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancelRuns = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.logger.Info("working memory flusher started", zap.Duration("interval", s.interval))
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, tickCancel := context.WithTimeout(baseCtx, workingMemoryFlushTimeout)
+				guardPanic(s.logger, "working memory flush tick", func() { s.RunOnce(ctx) })
+				tickCancel()
+			case <-s.stopCh:
+				s.logger.Info("working memory flusher stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic flush and runs a final one.
+func (s *WorkingMemoryFlushService) Stop() {
+	if s.flusher == nil {
+		return
+	}
+	if s.cancelRuns != nil {
+		s.cancelRuns()
+	}
+	close(s.stopCh)
+	s.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), workingMemoryFlushTimeout)
+	defer cancel()
+	s.RunOnce(ctx)
+}
+
+// RunOnce persists all buffered sessions and returns how many were written.
+func (s *WorkingMemoryFlushService) RunOnce(ctx context.Context) int {
+	if s.flusher == nil {
+		return 0
+	}
+	n, err := s.flusher.Flush(ctx)
+	if err != nil {
+		logFor(ctx, s.logger).Error("failed to flush working memory", zap.Int("flushed", n), zap.Error(err))
+	} else if n > 0 {
+		logFor(ctx, s.logger).Debug("flushed working memory", zap.Int("sessions", n))
+	}
+	return n
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type mockWorkingMemoryFlusher struct {
+	mu    sync.Mutex
+	calls int
+	n     int
+	err   error
+}
+
+func (m *mockWorkingMemoryFlusher) Flush(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	return m.n, m.err
+}
+
+func (m *mockWorkingMemoryFlusher) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+func TestWorkingMemoryFlush_RunOnce(t *testing.T) {
+	f := &mockWorkingMemoryFlusher{n: 3}
+	svc := NewWorkingMemoryFlushService(f, zap.NewNop())
+
+	if got := svc.RunOnce(context.Background()); got != 3 {
+		t.Errorf("expected 3 sessions flushed, got %d", got)
+	}
+
+	f.err = errors.New("db down")
+	f.n = 1
+	if got := svc.RunOnce(context.Background()); got != 1 {
+		t.Errorf("expected partial count 1 on error, got %d", got)
+	}
+}
+
+func TestWorkingMemoryFlush_StopRunsFinalFlush(t *testing.T) {
+	f := &mockWorkingMemoryFlusher{}
+	svc := NewWorkingMemoryFlushService(f, zap.NewNop())
+	svc.SetInterval(time.Hour)
+
+	svc.Start()
+	svc.Stop()
+
+	if f.Calls() != 1 {
+		t.Errorf("expected exactly one final flush on Stop, got %d", f.Calls())
+	}
+}
+
+func TestWorkingMemoryFlush_NilFlusherIsNoop(t *testing.T) {
+	svc := NewWorkingMemoryFlushService(nil, zap.NewNop())
+	svc.Start()
+	svc.Stop()
+	if got := svc.RunOnce(context.Background()); got != 0 {
+		t.Errorf("expected 0 without a flusher, got %d", got)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/Harshitk-cp/engram/internal/store"
@@ -33,6 +34,22 @@ func (m *MockWorkingMemoryStore) GetSession(ctx context.Context, agentID uuid.UU
 	return args.Get(0).(*domain.WorkingMemorySession), args.Error(1)
 }
 
+func (m *MockWorkingMemoryStore) GetConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) (*domain.WorkingMemorySession, error) {
+	args := m.Called(ctx, agentID, tenantID, conversationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WorkingMemorySession), args.Error(1)
+}
+
+func (m *MockWorkingMemoryStore) ListSessions(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.WorkingMemorySession, error) {
+	args := m.Called(ctx, agentID, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WorkingMemorySession), args.Error(1)
+}
+
 func (m *MockWorkingMemoryStore) GetSessionByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.WorkingMemorySession, error) {
 	args := m.Called(ctx, id, tenantID)
 	if args.Get(0) == nil {
@@ -51,6 +68,11 @@ func (m *MockWorkingMemoryStore) DeleteSession(ctx context.Context, agentID uuid
 	return args.Error(0)
 }
 
+func (m *MockWorkingMemoryStore) DeleteConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) error {
+	args := m.Called(ctx, agentID, tenantID, conversationID)
+	return args.Error(0)
+}
+
 func (m *MockWorkingMemoryStore) UpdateLastActivity(ctx context.Context, sessionID uuid.UUID) error {
 	args := m.Called(ctx, sessionID)
 	return args.Error(0)
@@ -64,6 +86,11 @@ func (m *MockWorkingMemoryStore) CreateActivation(ctx context.Context, a *domain
 	return args.Error(0)
 }
 
+func (m *MockWorkingMemoryStore) CreateActivationsBatch(ctx context.Context, acts []domain.WorkingMemoryActivation) error {
+	args := m.Called(ctx, acts)
+	return args.Error(0)
+}
+
 func (m *MockWorkingMemoryStore) GetActivations(ctx context.Context, sessionID uuid.UUID) ([]domain.WorkingMemoryActivation, error) {
 	args := m.Called(ctx, sessionID)
 	if args.Get(0) == nil {
@@ -87,6 +114,11 @@ func (m *MockWorkingMemoryStore) CreateSchemaActivation(ctx context.Context, a *
 	return args.Error(0)
 }
 
+func (m *MockWorkingMemoryStore) CreateSchemaActivationsBatch(ctx context.Context, acts []domain.SchemaActivation) error {
+	args := m.Called(ctx, acts)
+	return args.Error(0)
+}
+
 func (m *MockWorkingMemoryStore) GetSchemaActivations(ctx context.Context, sessionID uuid.UUID) ([]domain.SchemaActivation, error) {
 	args := m.Called(ctx, sessionID)
 	if args.Get(0) == nil {
@@ -166,6 +198,8 @@ func TestWorkingMemoryService_Activate_CreatesNewSession(t *testing.T) {
 	// Clear activations
 	wmStore.On("ClearActivations", ctx, sessionID).Return(nil)
 	wmStore.On("ClearSchemaActivations", ctx, sessionID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
 
 	// Update session
 	wmStore.On("UpdateSession", ctx, mock.AnythingOfType("*domain.WorkingMemorySession")).Return(nil)
@@ -215,6 +249,8 @@ func TestWorkingMemoryService_Activate_UsesExistingSession(t *testing.T) {
 	// Clear and save activations
 	wmStore.On("ClearActivations", ctx, sessionID).Return(nil)
 	wmStore.On("ClearSchemaActivations", ctx, sessionID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
 	wmStore.On("UpdateSession", ctx, mock.AnythingOfType("*domain.WorkingMemorySession")).Return(nil)
 
 	svc := NewWorkingMemoryService(wmStore, assocStore, nil, nil, nil, nil, nil, logger)
@@ -236,6 +272,41 @@ func TestWorkingMemoryService_Activate_UsesExistingSession(t *testing.T) {
 	wmStore.AssertExpectations(t)
 }
 
+func TestWorkingMemoryService_Activate_ControlLeavesSessionUntouched(t *testing.T) {
+	ctx := context.Background()
+
+	wmStore := new(MockWorkingMemoryStore)
+	agentID, tenantID := uuid.New(), uuid.New()
+	existingSession := &domain.WorkingMemorySession{
+		ID:          uuid.New(),
+		AgentID:     agentID,
+		TenantID:    tenantID,
+		CurrentGoal: "previous goal",
+		MaxSlots:    7,
+	}
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(existingSession, nil)
+
+	svc := NewWorkingMemoryService(wmStore, new(MockMemoryAssociationStore), nil, nil, nil, nil, nil, zap.NewNop())
+	convActs := newMockConversationActivationStore()
+	svc.SetConversationActivationStore(convActs)
+
+	convID := uuid.New()
+	result, err := svc.Activate(ctx, domain.ActivationInput{
+		AgentID:        agentID,
+		TenantID:       tenantID,
+		Goal:           "new goal",
+		Cues:           []string{"test"},
+		ConversationID: &convID,
+		Control:        true,
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	wmStore.AssertNotCalled(t, "ClearActivations", mock.Anything, mock.Anything)
+	wmStore.AssertNotCalled(t, "UpdateSession", mock.Anything, mock.Anything)
+	assert.Empty(t, convActs.acts)
+}
+
 func TestWorkingMemoryService_GetSession_Found(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -260,7 +331,7 @@ func TestWorkingMemoryService_GetSession_Found(t *testing.T) {
 
 	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, logger)
 
-	session, err := svc.GetSession(ctx, agentID, tenantID)
+	session, err := svc.GetSession(ctx, agentID, tenantID, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, session)
@@ -282,7 +353,7 @@ func TestWorkingMemoryService_GetSession_NotFound(t *testing.T) {
 
 	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, logger)
 
-	session, err := svc.GetSession(ctx, agentID, tenantID)
+	session, err := svc.GetSession(ctx, agentID, tenantID, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, ErrSessionNotFound, err)
@@ -291,6 +362,62 @@ func TestWorkingMemoryService_GetSession_NotFound(t *testing.T) {
 	wmStore.AssertExpectations(t)
 }
 
+func TestWorkingMemoryService_Activate_IsolatesConversation(t *testing.T) {
+	ctx := context.Background()
+	wmStore := new(MockWorkingMemoryStore)
+	agentID, tenantID, convID, sessionID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	// The conversation's own session is looked up and created, never the agent-wide one
+	wmStore.On("GetConversationSession", ctx, agentID, tenantID, convID).Return(nil, store.ErrNotFound)
+	wmStore.On("CreateSession", ctx, mock.MatchedBy(func(s *domain.WorkingMemorySession) bool {
+		return s.ConversationID != nil && *s.ConversationID == convID
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.WorkingMemorySession).ID = sessionID
+	}).Return(nil)
+	wmStore.On("ClearActivations", ctx, sessionID).Return(nil)
+	wmStore.On("ClearSchemaActivations", ctx, sessionID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("UpdateSession", ctx, mock.AnythingOfType("*domain.WorkingMemorySession")).Return(nil)
+
+	svc := NewWorkingMemoryService(wmStore, new(MockMemoryAssociationStore), nil, nil, nil, nil, nil, zap.NewNop())
+
+	result, err := svc.Activate(ctx, domain.ActivationInput{
+		AgentID: agentID, TenantID: tenantID, Goal: "refund order", ConversationID: &convID, IsolateConversation: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, result.Session.ID)
+	wmStore.AssertExpectations(t)
+	wmStore.AssertNotCalled(t, "GetSession", ctx, agentID, tenantID)
+
+	_, err = svc.Activate(ctx, domain.ActivationInput{AgentID: agentID, TenantID: tenantID, Goal: "x", IsolateConversation: true})
+	assert.ErrorIs(t, err, domain.ErrIsolationWithoutConversation)
+}
+
+func TestWorkingMemoryService_ListSessions_TotalsSlotUsage(t *testing.T) {
+	ctx := context.Background()
+	wmStore := new(MockWorkingMemoryStore)
+	agentID, tenantID, convID := uuid.New(), uuid.New(), uuid.New()
+	shared, isolated := uuid.New(), uuid.New()
+
+	wmStore.On("ListSessions", ctx, agentID, tenantID).Return([]domain.WorkingMemorySession{
+		{ID: isolated, AgentID: agentID, ConversationID: &convID, MaxSlots: 5},
+		{ID: shared, AgentID: agentID, MaxSlots: 7},
+	}, nil)
+	wmStore.On("GetActivations", ctx, isolated).Return(make([]domain.WorkingMemoryActivation, 2), nil)
+	wmStore.On("GetActivations", ctx, shared).Return(make([]domain.WorkingMemoryActivation, 4), nil)
+
+	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	usage, err := svc.ListSessions(ctx, agentID, tenantID)
+	assert.NoError(t, err)
+	assert.Len(t, usage.Sessions, 2)
+	assert.Equal(t, &convID, usage.Sessions[0].ConversationID)
+	assert.Equal(t, 2, usage.Sessions[0].SlotsUsed)
+	assert.Equal(t, 6, usage.TotalSlotsUsed)
+	assert.Equal(t, 12, usage.TotalMaxSlots)
+}
+
 func TestWorkingMemoryService_ClearSession(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -304,7 +431,7 @@ func TestWorkingMemoryService_ClearSession(t *testing.T) {
 
 	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, logger)
 
-	err := svc.ClearSession(ctx, agentID, tenantID)
+	err := svc.ClearSession(ctx, agentID, tenantID, nil)
 
 	assert.NoError(t, err)
 
@@ -336,7 +463,7 @@ func TestWorkingMemoryService_UpdateGoal(t *testing.T) {
 
 	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, logger)
 
-	err := svc.UpdateGoal(ctx, agentID, tenantID, "new goal")
+	err := svc.UpdateGoal(ctx, agentID, tenantID, nil, "new goal")
 
 	assert.NoError(t, err)
 
@@ -415,7 +542,7 @@ func TestWorkingMemoryService_AssembleContext(t *testing.T) {
 		{Schema: domain.Schema{Name: "Power User", Description: "Prefers efficiency and dark themes"}, MatchScore: 0.8},
 	}
 
-	context := svc.assembleContext(items, schemas)
+	context := svc.assembleContext(items, schemas, nil)
 
 	assert.Contains(t, context, "Known Facts/Preferences")
 	assert.Contains(t, context, "User prefers dark mode")
@@ -425,6 +552,37 @@ func TestWorkingMemoryService_AssembleContext(t *testing.T) {
 	assert.Contains(t, context, "Power User")
 }
 
+func TestWorkingMemoryService_AssembleContext_Citations(t *testing.T) {
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	belief := uuid.MustParse("3f2a9c1b-0000-4000-8000-000000000001")
+	twin := uuid.MustParse("3f2a9c1b-0100-4000-8000-000000000002")
+	episode := uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000003")
+	schema := uuid.MustParse("5c4d3e2f-0000-4000-8000-000000000004")
+	items := []activatedItem{
+		{Type: domain.ActivatedMemoryTypeSemantic, ID: belief, Content: "User prefers dark mode", Confidence: 0.9},
+		{Type: domain.ActivatedMemoryTypeSemantic, ID: twin, Content: "User works late", Confidence: 0.6},
+		{Type: domain.ActivatedMemoryTypeEpisodic, ID: episode, Content: "User complained about glare", Confidence: 0.7},
+	}
+	schemas := []domain.SchemaMatch{
+		{Schema: domain.Schema{ID: schema, Name: "Night Owl", Description: "Active late at night", Confidence: 0.8}, MatchScore: 0.8},
+	}
+
+	markers, citations := citationMarkers(items, schemas)
+	context := svc.assembleContext(items, schemas, markers)
+
+	// The two beliefs share their first 9 hex digits, so the prefix grows
+	// until every marker is unique.
+	beliefMarker := "m:3f2a9c1b00"
+	assert.Equal(t, beliefMarker, markers[belief])
+	assert.Contains(t, context, "User prefers dark mode (confidence: 90%) ["+beliefMarker+"]")
+	assert.Contains(t, context, "User complained about glare [e:a1b2c3d400]")
+	assert.Contains(t, context, "Night Owl: Active late at night ["+markers[schema]+"]")
+	assert.Len(t, citations, 4)
+	assert.Equal(t, domain.ContextCitation{Type: domain.ActivatedMemoryTypeSemantic, ID: belief, Confidence: 0.9}, citations[beliefMarker])
+	assert.Equal(t, domain.ActivatedMemoryTypeSchema, citations[markers[schema]].Type)
+}
+
 func TestWorkingMemoryService_CreateAssociation(t *testing.T) {
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -449,3 +607,592 @@ func TestWorkingMemoryService_CreateAssociation(t *testing.T) {
 	assert.NoError(t, err)
 	assocStore.AssertExpectations(t)
 }
+
+func TestWorkingMemoryService_SpreadFollowsOnlyRequestedAssociationTypes(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	memStore := newMockMemoryStore()
+	cause := &domain.Memory{TenantID: tenantID, Content: "Deploy failed after the config change", Confidence: 0.8}
+	theme := &domain.Memory{TenantID: tenantID, Content: "Deploys happen on Fridays", Confidence: 0.8}
+	_ = memStore.Create(ctx, cause)
+	_ = memStore.Create(ctx, theme)
+
+	seed := activatedItem{Type: domain.ActivatedMemoryTypeSemantic, ID: uuid.New(), ActivationLevel: 1}
+	assocStore := new(MockMemoryAssociationStore)
+	assocStore.On("GetBySource", ctx, tenantID, domain.ActivatedMemoryTypeSemantic, seed.ID).Return([]domain.MemoryAssociation{
+		{TargetMemoryType: domain.ActivatedMemoryTypeSemantic, TargetMemoryID: cause.ID, AssociationType: domain.AssociationTypeCausal, AssociationStrength: 0.8},
+		{TargetMemoryType: domain.ActivatedMemoryTypeSemantic, TargetMemoryID: theme.ID, AssociationType: domain.AssociationTypeThematic, AssociationStrength: 0.8},
+	}, nil)
+
+	svc := NewWorkingMemoryService(nil, assocStore, memStore, nil, nil, nil, nil, zap.NewNop())
+
+	all := svc.spread(ctx, newActivationCache(), tenantID, []activatedItem{seed}, 1, nil)
+	if !assert.Len(t, all, 2) {
+		return
+	}
+	// Without a type filter each association still decays by its type, as
+	// graph traversal does.
+	for _, a := range all {
+		typ := domain.AssociationTypeThematic
+		if a.ID == cause.ID {
+			typ = domain.AssociationTypeCausal
+		}
+		assert.InDelta(t, 0.8*SpreadingDecay*domain.AssociationDecayMultipliers[typ], a.ActivationLevel, 1e-6)
+	}
+
+	causal := svc.spread(ctx, newActivationCache(), tenantID, []activatedItem{seed}, 1, []string{domain.AssociationTypeCausal})
+	if !assert.Len(t, causal, 1) {
+		return
+	}
+	assert.Equal(t, cause.ID, causal[0].ID)
+	assert.InDelta(t, 0.8*SpreadingDecay*domain.AssociationDecayMultipliers[domain.AssociationTypeCausal], causal[0].ActivationLevel, 1e-6)
+
+	both := svc.spread(ctx, newActivationCache(), tenantID, []activatedItem{seed}, 1,
+		[]string{domain.AssociationTypeCausal, domain.AssociationTypeThematic})
+	if !assert.Len(t, both, 2) {
+		return
+	}
+	levels := map[uuid.UUID]float32{both[0].ID: both[0].ActivationLevel, both[1].ID: both[1].ActivationLevel}
+	assert.Greater(t, levels[cause.ID], levels[theme.ID])
+}
+
+func TestWorkingMemoryService_ActivateRejectsUnknownAssociationType(t *testing.T) {
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	_, err := svc.Activate(context.Background(), domain.ActivationInput{Cues: []string{"why"}, AssociationTypes: []string{"causes"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidAssociationType)
+}
+
+type countingMemoryStore struct {
+	*mockMemoryStore
+	gets int
+}
+
+func (m *countingMemoryStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.Memory, error) {
+	m.gets++
+	return m.mockMemoryStore.GetByID(ctx, id, tenantID)
+}
+
+func TestWorkingMemoryService_ActivateFromSchema_FetchesEachTargetOnce(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	memStore := &countingMemoryStore{mockMemoryStore: newMockMemoryStore()}
+	mem := &domain.Memory{TenantID: tenantID, Content: "prefers dark mode", Confidence: 0.8}
+	_ = memStore.Create(ctx, mem)
+	missing := uuid.New()
+
+	svc := NewWorkingMemoryService(nil, nil, memStore, nil, nil, nil, nil, zap.NewNop())
+	cache := newActivationCache()
+
+	a := domain.Schema{Name: "a", EvidenceMemories: []uuid.UUID{mem.ID, missing}}
+	b := domain.Schema{Name: "b", EvidenceMemories: []uuid.UUID{mem.ID, missing}}
+
+	first := svc.activateFromSchema(ctx, cache, tenantID, a)
+	second := svc.activateFromSchema(ctx, cache, tenantID, b)
+
+	assert.Len(t, first, 1)
+	assert.Len(t, second, 1)
+	assert.Equal(t, "prefers dark mode", second[0].Content)
+	assert.Equal(t, 2, memStore.gets, "hit and miss should each be fetched once")
+}
+
+func TestWorkingMemoryService_ActivationCache_SeededItemsSkipFetch(t *testing.T) {
+	ctx := context.Background()
+	memStore := &countingMemoryStore{mockMemoryStore: newMockMemoryStore()}
+	svc := NewWorkingMemoryService(nil, nil, memStore, nil, nil, nil, nil, zap.NewNop())
+
+	id := uuid.New()
+	cache := newActivationCache()
+	cache.seed([]activatedItem{{Type: domain.ActivatedMemoryTypeSemantic, ID: id, Content: "from cues", Confidence: 0.7}})
+
+	content, confidence, ok := svc.getMemoryContent(ctx, cache, domain.ActivatedMemoryTypeSemantic, id, uuid.New())
+	assert.True(t, ok)
+	assert.Equal(t, "from cues", content)
+	assert.Equal(t, float32(0.7), confidence)
+	assert.Zero(t, memStore.gets)
+}
+
+// rendezvousEmbeddingClient blocks each Embed until `want` calls are in
+// flight, so it only succeeds when the callers run concurrently.
+type rendezvousEmbeddingClient struct {
+	want    int
+	arrived chan struct{}
+	release chan struct{}
+}
+
+func (c *rendezvousEmbeddingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	c.arrived <- struct{}{}
+	select {
+	case <-c.release:
+		return []float32{1, 0}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestWorkingMemoryService_Activate_RunsSourcesConcurrently(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	agentID, tenantID, sessionID := uuid.New(), uuid.New(), uuid.New()
+	wmStore := new(MockWorkingMemoryStore)
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(&domain.WorkingMemorySession{
+		ID: sessionID, AgentID: agentID, TenantID: tenantID, MaxSlots: DefaultMaxSlots,
+	}, nil)
+	wmStore.On("ClearActivations", ctx, sessionID).Return(nil)
+	wmStore.On("ClearSchemaActivations", ctx, sessionID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("UpdateSession", ctx, mock.AnythingOfType("*domain.WorkingMemorySession")).Return(nil)
+
+	emb := &rendezvousEmbeddingClient{want: 2, arrived: make(chan struct{}, 2), release: make(chan struct{})}
+	go func() {
+		// Cue and goal embeddings must both be requested before either returns.
+		for i := 0; i < emb.want; i++ {
+			select {
+			case <-emb.arrived:
+			case <-ctx.Done():
+				return
+			}
+		}
+		close(emb.release)
+	}()
+
+	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, emb, zap.NewNop())
+	_, err := svc.Activate(ctx, domain.ActivationInput{
+		AgentID: agentID, TenantID: tenantID, Goal: "ship the release", Cues: []string{"deploy"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Err(), "cue and goal activation did not overlap")
+}
+
+func TestWorkingMemoryService_SaveActivations_SingleBatch(t *testing.T) {
+	ctx := context.Background()
+	wmStore := new(MockWorkingMemoryStore)
+	session := &domain.WorkingMemorySession{ID: uuid.New(), TenantID: uuid.New()}
+
+	items := []activatedItem{
+		{Type: domain.ActivatedMemoryTypeSemantic, ID: uuid.New(), ActivationLevel: 1.2, Source: domain.ActivationSourceGoal},
+		{Type: domain.ActivatedMemoryTypeEpisodic, ID: uuid.New(), ActivationLevel: 0.4, Source: domain.ActivationSourceTemporal},
+	}
+	schemas := []domain.SchemaMatch{{Schema: domain.Schema{ID: uuid.New()}, MatchScore: 0.7}}
+
+	wmStore.On("ClearActivations", ctx, session.ID).Return(nil)
+	wmStore.On("ClearSchemaActivations", ctx, session.ID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.MatchedBy(func(acts []domain.WorkingMemoryActivation) bool {
+		return len(acts) == 2 &&
+			acts[0].ActivationLevel == 1 && *acts[0].SlotPosition == 1 &&
+			acts[1].ActivationLevel == 0.4 && *acts[1].SlotPosition == 2 &&
+			acts[1].SessionID == session.ID && acts[1].TenantID == session.TenantID
+	})).Return(nil).Once()
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.MatchedBy(func(acts []domain.SchemaActivation) bool {
+		return len(acts) == 1 && acts[0].SchemaID == schemas[0].Schema.ID && acts[0].MatchScore == 0.7
+	})).Return(nil).Once()
+
+	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	assert.NoError(t, svc.saveActivations(ctx, session, items, schemas))
+
+	wmStore.AssertExpectations(t)
+	wmStore.AssertNotCalled(t, "CreateActivation", mock.Anything, mock.Anything)
+}
+
+type mockWorkingMemorySettingsStore struct {
+	settings map[uuid.UUID]domain.WorkingMemorySettings
+}
+
+func (m *mockWorkingMemorySettingsStore) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.WorkingMemorySettings, error) {
+	ws, ok := m.settings[agentID]
+	if !ok || ws.TenantID != tenantID {
+		return nil, store.ErrNotFound
+	}
+	return &ws, nil
+}
+
+func (m *mockWorkingMemorySettingsStore) Upsert(ctx context.Context, ws *domain.WorkingMemorySettings) error {
+	if m.settings == nil {
+		m.settings = make(map[uuid.UUID]domain.WorkingMemorySettings)
+	}
+	m.settings[ws.AgentID] = *ws
+	return nil
+}
+
+func TestGoalComplexity(t *testing.T) {
+	assert.Equal(t, 1, goalComplexity(""))
+	assert.Equal(t, 1, goalComplexity("fix the login bug"))
+	assert.Equal(t, 3, goalComplexity("reproduce the crash, write a test and then ship the fix"))
+	assert.Equal(t, 2, goalComplexity("Plan the migration\nReview the rollout"))
+}
+
+func TestWorkingMemoryService_SlotsFor(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	settings := &mockWorkingMemorySettingsStore{}
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetSettingsStore(settings)
+	input := domain.ActivationInput{AgentID: agentID, TenantID: tenantID}
+
+	// No stored settings: defaults, expanded for a multi-part goal.
+	assert.Equal(t, DefaultMaxSlots, svc.slotsFor(ctx, input, "debug the error"))
+	assert.Equal(t, DefaultMaxSlots+1, svc.slotsFor(ctx, input, "debug the error and add a test"))
+
+	assert.NoError(t, svc.UpdateSettings(ctx, &domain.WorkingMemorySettings{AgentID: agentID, TenantID: tenantID, Slots: 4, ExpandedSlots: 5}))
+	assert.Equal(t, 4, svc.slotsFor(ctx, input, "one thing"))
+	assert.Equal(t, 5, svc.slotsFor(ctx, input, "a, b, c, d"), "expansion is capped at expanded_slots")
+
+	// The per-call override wins and is not expanded.
+	input.MaxSlots = 12
+	assert.Equal(t, 12, svc.slotsFor(ctx, input, "a, b, c"))
+}
+
+func TestWorkingMemoryService_UpdateSettings_Validates(t *testing.T) {
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetSettingsStore(&mockWorkingMemorySettingsStore{})
+	var invalid *InvalidWorkingMemorySettingsError
+
+	for _, ws := range []domain.WorkingMemorySettings{
+		{Slots: 2, ExpandedSlots: 5},
+		{Slots: 7, ExpandedSlots: 21},
+		{Slots: 8, ExpandedSlots: 7},
+	} {
+		err := svc.UpdateSettings(context.Background(), &ws)
+		assert.ErrorAs(t, err, &invalid, "settings %+v", ws)
+	}
+}
+
+func TestWorkingMemoryService_Activate_RejectsBadSlotOverride(t *testing.T) {
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	_, err := svc.Activate(context.Background(), domain.ActivationInput{AgentID: uuid.New(), TenantID: uuid.New(), MaxSlots: 50})
+	assert.ErrorIs(t, err, domain.ErrInvalidSlotCount)
+}
+
+func TestWorkingMemoryService_PatchReasoningState(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	session := &domain.WorkingMemorySession{
+		ID: uuid.New(), AgentID: agentID, TenantID: tenantID,
+		ReasoningState: map[string]any{"hypothesis": "cache miss", "draft": "x"},
+	}
+
+	wmStore := new(MockWorkingMemoryStore)
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(session, nil)
+	wmStore.On("UpdateSession", ctx, session).Return(nil).Once()
+	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	got, err := svc.PatchReasoningState(ctx, agentID, tenantID, nil, map[string]any{
+		"draft":                          nil,
+		domain.ReasoningKeyOpenQuestions: []any{"why does the retry loop spin?"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "cache miss", got.ReasoningState["hypothesis"])
+	assert.NotContains(t, got.ReasoningState, "draft")
+	assert.Equal(t, []any{"why does the retry loop spin?"}, got.ReasoningState[domain.ReasoningKeyOpenQuestions])
+	wmStore.AssertExpectations(t)
+
+	var invalid *InvalidReasoningStateError
+	_, err = svc.PatchReasoningState(ctx, agentID, tenantID, nil, map[string]any{domain.ReasoningKeyConclusions: "not a list"})
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestWorkingMemoryService_PatchReasoningState_NoSession(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	wmStore := new(MockWorkingMemoryStore)
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(nil, store.ErrNotFound)
+	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	_, err := svc.PatchReasoningState(ctx, agentID, tenantID, nil, map[string]any{"k": "v"})
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func TestGoalQuery_IncludesOpenQuestions(t *testing.T) {
+	state := map[string]any{domain.ReasoningKeyOpenQuestions: []any{"which region failed?", " "}}
+	assert.Equal(t, "restore service\nwhich region failed?", goalQuery("restore service", state))
+	assert.Equal(t, "which region failed?", goalQuery("", state))
+	assert.Equal(t, "", goalQuery("", nil))
+}
+
+type mockWorkingMemorySnapshotStore struct {
+	snaps map[uuid.UUID]domain.WorkingMemorySnapshot
+}
+
+func (m *mockWorkingMemorySnapshotStore) Create(ctx context.Context, snap *domain.WorkingMemorySnapshot) error {
+	if m.snaps == nil {
+		m.snaps = make(map[uuid.UUID]domain.WorkingMemorySnapshot)
+	}
+	snap.ID = uuid.New()
+	m.snaps[snap.ID] = *snap
+	return nil
+}
+
+func (m *mockWorkingMemorySnapshotStore) GetByID(ctx context.Context, id, tenantID uuid.UUID) (*domain.WorkingMemorySnapshot, error) {
+	snap, ok := m.snaps[id]
+	if !ok || snap.TenantID != tenantID {
+		return nil, store.ErrNotFound
+	}
+	return &snap, nil
+}
+
+func (m *mockWorkingMemorySnapshotStore) ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.WorkingMemorySnapshot, error) {
+	var out []domain.WorkingMemorySnapshot
+	for _, snap := range m.snaps {
+		if snap.AgentID == agentID && snap.TenantID == tenantID {
+			out = append(out, snap)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockWorkingMemorySnapshotStore) Delete(ctx context.Context, id, tenantID uuid.UUID) error {
+	if _, err := m.GetByID(ctx, id, tenantID); err != nil {
+		return err
+	}
+	delete(m.snaps, id)
+	return nil
+}
+
+func TestWorkingMemoryService_SnapshotAndRestore(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+
+	memStore := newMockMemoryStore()
+	kept := &domain.Memory{TenantID: tenantID, Content: "deploys go through staging", Confidence: 0.9}
+	_ = memStore.Create(ctx, kept)
+	deleted := uuid.New()
+
+	original := &domain.WorkingMemorySession{
+		ID: uuid.New(), AgentID: agentID, TenantID: tenantID, MaxSlots: 9,
+		CurrentGoal:    "finish the rollout",
+		ReasoningState: map[string]any{domain.ReasoningKeyOpenQuestions: []any{"is eu-west healthy?"}},
+	}
+	activations := []domain.WorkingMemoryActivation{
+		{SessionID: original.ID, MemoryType: domain.ActivatedMemoryTypeSemantic, MemoryID: kept.ID, ActivationLevel: 0.8},
+		{SessionID: original.ID, MemoryType: domain.ActivatedMemoryTypeSemantic, MemoryID: deleted, ActivationLevel: 0.5},
+	}
+
+	wmStore := new(MockWorkingMemoryStore)
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(original, nil)
+	wmStore.On("GetActivations", ctx, original.ID).Return(activations, nil)
+	wmStore.On("GetSchemaActivations", ctx, original.ID).Return([]domain.SchemaActivation{}, nil)
+
+	snapshots := &mockWorkingMemorySnapshotStore{}
+	svc := NewWorkingMemoryService(wmStore, nil, memStore, nil, nil, nil, nil, zap.NewNop())
+	svc.SetSnapshotStore(snapshots)
+
+	snap, err := svc.Snapshot(ctx, agentID, tenantID, "before lunch")
+	assert.NoError(t, err)
+	assert.Len(t, snap.Activations, 2)
+
+	// The session moves on before the restore.
+	original.CurrentGoal = "something else"
+	original.ReasoningState = map[string]any{}
+
+	wmStore.On("ClearActivations", ctx, original.ID).Return(nil)
+	wmStore.On("ClearSchemaActivations", ctx, original.ID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.MatchedBy(func(acts []domain.WorkingMemoryActivation) bool {
+		return len(acts) == 1 && acts[0].MemoryID == kept.ID && acts[0].TenantID == tenantID
+	})).Return(nil).Once()
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("UpdateSession", ctx, original).Return(nil)
+
+	restored, dropped, err := svc.RestoreSnapshot(ctx, snap.ID, tenantID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, "finish the rollout", restored.CurrentGoal)
+	assert.Equal(t, 9, restored.MaxSlots)
+	assert.Contains(t, restored.ReasoningState, domain.ReasoningKeyOpenQuestions)
+	wmStore.AssertExpectations(t)
+
+	_, _, err = svc.RestoreSnapshot(ctx, snap.ID, uuid.New())
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestWorkingMemoryService_Activate_SkipsWhatContextShows(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID, sessionID := uuid.New(), uuid.New(), uuid.New()
+	wmStore := new(MockWorkingMemoryStore)
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(&domain.WorkingMemorySession{
+		ID: sessionID, AgentID: agentID, TenantID: tenantID, MaxSlots: DefaultMaxSlots,
+	}, nil)
+	wmStore.On("ClearActivations", ctx, sessionID).Return(nil)
+	wmStore.On("ClearSchemaActivations", ctx, sessionID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("UpdateSession", ctx, mock.AnythingOfType("*domain.WorkingMemorySession")).Return(nil)
+
+	memStore := newMockMemoryStore()
+	for _, content := range []string{"User is allergic to peanuts", "User lives in Lisbon"} {
+		_ = memStore.Create(ctx, &domain.Memory{AgentID: agentID, TenantID: tenantID, Content: content, Type: domain.MemoryTypeFact, Confidence: 0.9})
+	}
+
+	svc := NewWorkingMemoryService(wmStore, nil, memStore, nil, nil, nil, &mockEmbeddingClient{}, zap.NewNop())
+	result, err := svc.Activate(ctx, domain.ActivationInput{
+		AgentID:  agentID,
+		TenantID: tenantID,
+		Cues:     []string{"dinner plans"},
+		Context:  []domain.Message{{Role: "user", Content: "Remember, the user is allergic to peanuts."}},
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, result.Activations, 1) {
+		assert.Equal(t, "User lives in Lisbon", result.Activations[0].Content)
+	}
+	assert.NotContains(t, result.AssembledContext, "peanuts")
+}
+
+func TestWorkingMemoryService_Activate_LatencyBudgetSkipsStages(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID, sessionID := uuid.New(), uuid.New(), uuid.New()
+	wmStore := new(MockWorkingMemoryStore)
+	wmStore.On("GetSession", ctx, agentID, tenantID).Return(&domain.WorkingMemorySession{
+		ID: sessionID, AgentID: agentID, TenantID: tenantID, MaxSlots: DefaultMaxSlots,
+	}, nil)
+	wmStore.On("ClearActivations", ctx, sessionID).Return(nil)
+	wmStore.On("ClearSchemaActivations", ctx, sessionID).Return(nil)
+	wmStore.On("CreateActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("CreateSchemaActivationsBatch", ctx, mock.Anything).Return(nil)
+	wmStore.On("UpdateSession", ctx, mock.AnythingOfType("*domain.WorkingMemorySession")).Return(nil)
+
+	svc := NewWorkingMemoryService(wmStore, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	input := domain.ActivationInput{AgentID: agentID, TenantID: tenantID, Cues: []string{"deploy"}}
+
+	result, err := svc.Activate(ctx, input)
+	assert.NoError(t, err)
+	assert.Empty(t, result.SkippedStages, "no budget, nothing skipped")
+
+	input.MaxLatencyMs = 1
+	result, err = svc.Activate(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ActivationStage{domain.ActivationStageSchemas, domain.ActivationStageSpreading}, result.SkippedStages)
+
+	input.MaxLatencyMs = -1
+	_, err = svc.Activate(ctx, input)
+	assert.ErrorIs(t, err, domain.ErrInvalidActivationBudget)
+}
+
+func TestWorkingMemoryService_FitContext(t *testing.T) {
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	belief := func(content string) activatedItem {
+		return activatedItem{Type: domain.ActivatedMemoryTypeSemantic, ID: uuid.New(), Content: content, Confidence: 0.9}
+	}
+	items := []activatedItem{
+		belief("User deploys with Terraform"),
+		belief("User's staging cluster runs in eu-west-1"),
+		belief("User prefers blue-green deploys over rolling updates"),
+		{Type: domain.ActivatedMemoryTypeProcedural, ID: uuid.New(), Content: "Run the smoke tests before promoting", Confidence: 0.8},
+	}
+	schemas := []domain.SchemaMatch{{Schema: domain.Schema{ID: uuid.New(), Name: "Cautious operator", Description: "Tests first"}, MatchScore: 0.7}}
+
+	full := estimateTokens(svc.assembleContext(items, schemas, nil))
+	kept, keptSchemas, dropped := svc.fitContext(items, schemas, full, false)
+	assert.Equal(t, 0, dropped, "a context within budget is left alone")
+	assert.Len(t, kept, 4)
+	assert.Len(t, keptSchemas, 1)
+
+	kept, keptSchemas, dropped = svc.fitContext(items, schemas, full-10, false)
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, keptSchemas, 1)
+	if assert.Len(t, kept, 3) {
+		// The beliefs are the largest section; their weakest goes first.
+		assert.NotContains(t, svc.assembleContext(kept, nil, nil), "blue-green")
+	}
+	assert.LessOrEqual(t, estimateTokens(svc.assembleContext(kept, keptSchemas, nil)), full-10)
+	assert.Equal(t, domain.ActivatedMemoryTypeProcedural, items[3].Type, "the caller's slice is not modified")
+
+	kept, keptSchemas, dropped = svc.fitContext(items, schemas, 1, false)
+	assert.Equal(t, 5, dropped)
+	assert.Empty(t, kept)
+	assert.Empty(t, keptSchemas)
+}
+
+func TestWorkingMemoryService_GetActiveSchemas_MatchesByEmbedding(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	schemaStore := newMockSchemaStore()
+	related := &domain.Schema{AgentID: agentID, TenantID: tenantID, Name: "Night owl", Confidence: 0.9}
+	unrelated := &domain.Schema{AgentID: agentID, TenantID: tenantID, Name: "Budget traveller", Confidence: 0.9}
+	_ = schemaStore.Create(ctx, related)
+	_ = schemaStore.Create(ctx, unrelated)
+	schemaStore.similar = []domain.SchemaWithScore{
+		{Schema: *related, Score: 0.9},
+		{Schema: *unrelated, Score: 0.2},
+	}
+
+	// No applicable context or attribute mentions the cues, so only
+	// similarity can activate a schema.
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, schemaStore, nil, zap.NewNop())
+	assert.Empty(t, svc.getActiveSchemas(ctx, agentID, tenantID, []string{"late evening meetings"}, "plan the week", nil))
+
+	svc = NewWorkingMemoryService(nil, nil, nil, nil, nil, schemaStore, &mockEmbeddingClient{}, zap.NewNop())
+	matches := svc.getActiveSchemas(ctx, agentID, tenantID, []string{"late evening meetings"}, "plan the week", nil)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, related.ID, matches[0].Schema.ID)
+		assert.InDelta(t, 0.9*EmbeddingSimilarityWeight*0.9, matches[0].MatchScore, 1e-6)
+	}
+}
+
+func TestWorkingMemoryService_ApplyConditions(t *testing.T) {
+	ctx := context.Background()
+	agentID, tenantID := uuid.New(), uuid.New()
+	procedures := newMockProcedureStore()
+	escalate := &domain.Procedure{
+		AgentID: agentID, TenantID: tenantID,
+		TriggerPattern: "Enterprise customer asks about billing",
+		ActionTemplate: "Offer to escalate to their account manager",
+		Conditions:     &domain.ProcedureConditions{UserAttributes: map[string]string{"plan": "enterprise"}, Topics: []string{"billing"}},
+		SuccessRate:    0.2, Confidence: 0.5,
+	}
+	upsell := &domain.Procedure{
+		AgentID: agentID, TenantID: tenantID,
+		TriggerPattern: "Free user asks about billing",
+		ActionTemplate: "Mention the annual discount",
+		Conditions:     &domain.ProcedureConditions{UserAttributes: map[string]string{"plan": "free"}},
+		SuccessRate:    0.9, Confidence: 0.9,
+	}
+	_ = procedures.Create(ctx, escalate)
+	_ = procedures.Create(ctx, upsell)
+
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, procedures, nil, nil, zap.NewNop())
+	belief := activatedItem{Type: domain.ActivatedMemoryTypeSemantic, ID: uuid.New(), Content: "User is on the enterprise plan", Confidence: 0.9, ActivationLevel: 1}
+	activations := []activatedItem{
+		belief,
+		// Strongly activated by similarity, but its conditions fail.
+		{Type: domain.ActivatedMemoryTypeProcedural, ID: upsell.ID, Content: "upsell", Confidence: 0.9, ActivationLevel: 1, Source: domain.ActivationSourceDirect},
+	}
+	input := domain.ActivationInput{
+		AgentID: agentID, TenantID: tenantID,
+		UserAttributes: map[string]string{"plan": "Enterprise"},
+		Topics:         []string{"Billing"},
+	}
+
+	got := svc.applyConditions(ctx, input, nil, activations)
+	assert.Len(t, got, 2)
+	for _, item := range got {
+		assert.NotEqual(t, upsell.ID, item.ID, "a procedure whose conditions fail is removed")
+	}
+	winners := svc.compete(got, 1)
+	if assert.Len(t, winners, 1) {
+		assert.Equal(t, escalate.ID, winners[0].ID, "a fired procedure wins a slot over a stronger belief")
+		assert.Equal(t, domain.ActivationSourceCondition, winners[0].Source)
+	}
+}
+
+func TestWorkingMemoryService_RenderActions(t *testing.T) {
+	ctx := context.Background()
+	entities := newMockEntityStore()
+	belief := activatedItem{Type: domain.ActivatedMemoryTypeSemantic, ID: uuid.New(), Content: "User's team is migrating to Postgres", Confidence: 0.9}
+	postgres := &domain.Entity{Name: "Postgres", EntityType: domain.EntityProduct}
+	_ = entities.Create(ctx, postgres)
+	_ = entities.CreateMention(ctx, &domain.EntityMention{EntityID: postgres.ID, MemoryID: belief.ID})
+
+	svc := NewWorkingMemoryService(nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetEntityStore(entities)
+	procedure := activatedItem{Type: domain.ActivatedMemoryTypeProcedural, ID: uuid.New(),
+		Content: "When: migration questions\nDo: Ask {{user_name}} which {{product}} version they run before {{goal}}; cite {{location}}"}
+	winners := []activatedItem{belief, procedure}
+
+	svc.renderActions(ctx, domain.ActivationInput{UserAttributes: map[string]string{"Name": "Ada"}}, "planning the cutover", winners)
+	assert.Equal(t, "When: migration questions\nDo: Ask Ada which Postgres version they run before planning the cutover; cite {{location}}", winners[1].Content)
+	assert.Equal(t, "User's team is migrating to Postgres", winners[0].Content, "only procedures are rendered")
+}
@@ -3,19 +3,19 @@ package store
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AgentStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewAgentStore(db *pgxpool.Pool) *AgentStore {
+func NewAgentStore(db DB) *AgentStore {
 	return &AgentStore{db: db}
 }
 
@@ -114,3 +114,45 @@ func (s *AgentStore) GetByExternalID(ctx context.Context, externalID string, ten
 	}
 	return a, nil
 }
+
+// ListWithWork returns the agents with any of the kinds of work set in f, each
+// kind an EXISTS probe on the agent's own rows.
+func (s *AgentStore) ListWithWork(ctx context.Context, f domain.AgentWorkFilter) ([]domain.AgentRef, error) {
+	var conds []string
+	if f.UnconsolidatedEpisodes {
+		conds = append(conds, `EXISTS (SELECT 1 FROM episodes e WHERE e.agent_id = a.id AND e.consolidation_status = 'raw')`)
+	}
+	if f.LiveMemories {
+		conds = append(conds, `EXISTS (SELECT 1 FROM memories m WHERE m.agent_id = a.id AND m.is_archived = FALSE)`)
+	}
+	if f.LiveEpisodes {
+		conds = append(conds, `EXISTS (SELECT 1 FROM episodes e WHERE e.agent_id = a.id AND e.consolidation_status <> 'archived')`)
+	}
+	if f.Feedback {
+		conds = append(conds, `EXISTS (SELECT 1 FROM feedback_signals fs WHERE fs.agent_id = a.id)`)
+	}
+	if f.RetentionPolicies {
+		conds = append(conds, `EXISTS (SELECT 1 FROM memory_policies p WHERE p.agent_id = a.id AND p.retention_days > 0)`)
+	}
+	query := `SELECT a.id, a.tenant_id FROM agents a`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, ` OR `)
+	}
+	query += ` ORDER BY a.id`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []domain.AgentRef
+	for rows.Next() {
+		var r domain.AgentRef
+		if err := rows.Scan(&r.ID, &r.TenantID); err != nil {
+			return nil, err
+		}
+		refs = append(refs, r)
+	}
+	return refs, rows.Err()
+}
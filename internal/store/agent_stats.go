@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type AgentStatisticsStore struct {
+	db DB
+}
+
+func NewAgentStatisticsStore(db DB) *AgentStatisticsStore {
+	return &AgentStatisticsStore{db: db}
+}
+
+const agentStatisticsColumns = `agent_id, tenant_id, memory_count,
+	CASE WHEN memory_count > 0 THEN confidence_sum / memory_count ELSE 0 END,
+	hot_count, warm_count, cold_count, archive_count,
+	last_consolidated_at, reconciled_at, updated_at`
+
+func scanAgentStatistics(row pgx.Row) (*domain.AgentStatistics, error) {
+	var st domain.AgentStatistics
+	err := row.Scan(&st.AgentID, &st.TenantID, &st.MemoryCount, &st.AverageConfidence,
+		&st.HotCount, &st.WarmCount, &st.ColdCount, &st.ArchiveCount,
+		&st.LastConsolidatedAt, &st.ReconciledAt, &st.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (s *AgentStatisticsStore) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.AgentStatistics, error) {
+	st, err := scanAgentStatistics(s.db.QueryRow(ctx,
+		`SELECT `+agentStatisticsColumns+` FROM memory_stats WHERE agent_id = $1 AND tenant_id = $2`,
+		agentID, tenantID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return st, err
+}
+
+// ListByTenant returns the tenant's rows, largest agents first, and the total.
+func (s *AgentStatisticsStore) ListByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]domain.AgentStatistics, int, error) {
+	var total int
+	if err := s.db.QueryRow(ctx, `SELECT count(*) FROM memory_stats WHERE tenant_id = $1`, tenantID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT `+agentStatisticsColumns+` FROM memory_stats WHERE tenant_id = $1
+		 ORDER BY memory_count DESC, agent_id
+		 LIMIT $2 OFFSET $3`,
+		tenantID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var stats []domain.AgentStatistics
+	for rows.Next() {
+		st, err := scanAgentStatistics(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		stats = append(stats, *st)
+	}
+	return stats, total, rows.Err()
+}
+
+// Reconcile recomputes all rows in one statement. The aggregates come from the
+// statement's snapshot, so a memory write that commits while it runs can be
+// missing from the result until the next reconcile.
+func (s *AgentStatisticsStore) Reconcile(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx,
+		`INSERT INTO memory_stats (agent_id, tenant_id, memory_count, confidence_sum,
+		                           hot_count, warm_count, cold_count, archive_count,
+		                           last_consolidated_at, reconciled_at, updated_at)
+		 SELECT a.id, a.tenant_id,
+		        COALESCE(m.n, 0), COALESCE(m.conf, 0),
+		        COALESCE(m.hot, 0), COALESCE(m.warm, 0), COALESCE(m.cold, 0), COALESCE(m.archive, 0),
+		        r.last_at, NOW(), NOW()
+		 FROM agents a
+		 LEFT JOIN (
+		     SELECT agent_id, count(*) AS n, sum(confidence) AS conf,
+		            count(*) FILTER (WHERE tier = 'hot') AS hot,
+		            count(*) FILTER (WHERE tier = 'warm') AS warm,
+		            count(*) FILTER (WHERE tier = 'cold') AS cold,
+		            count(*) FILTER (WHERE tier = 'archive') AS archive
+		     FROM memories WHERE is_archived = FALSE AND binding <> 'quarantine'
+		     GROUP BY agent_id
+		 ) m ON m.agent_id = a.id
+		 LEFT JOIN (
+		     SELECT agent_id, max(finished_at) AS last_at FROM consolidation_runs GROUP BY agent_id
+		 ) r ON r.agent_id = a.id
+		 ON CONFLICT (agent_id) DO UPDATE SET
+		     memory_count         = EXCLUDED.memory_count,
+		     confidence_sum       = EXCLUDED.confidence_sum,
+		     hot_count            = EXCLUDED.hot_count,
+		     warm_count           = EXCLUDED.warm_count,
+		     cold_count           = EXCLUDED.cold_count,
+		     archive_count        = EXCLUDED.archive_count,
+		     last_consolidated_at = EXCLUDED.last_consolidated_at,
+		     reconciled_at        = EXCLUDED.reconciled_at,
+		     updated_at           = EXCLUDED.updated_at`,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
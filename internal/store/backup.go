@@ -0,0 +1,286 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Row scopes select the tenant's rows on export and re-check them on
+// restore, where they run against the incoming row: a backup can only
+// restore rows that hang off the restoring tenant's own agents, memories and
+// episodes. $1 is the tenant ID.
+const (
+	scopeTenant  = `tenant_id = $1`
+	scopeAgent   = `agent_id IN (SELECT id FROM agents WHERE tenant_id = $1)`
+	scopeOwned   = scopeTenant + ` AND ` + scopeAgent
+	scopeEntity  = `IN (SELECT id FROM entities WHERE agent_id IN (SELECT id FROM agents WHERE tenant_id = $1))`
+	scopeMemory  = `IN (SELECT id FROM memories WHERE tenant_id = $1)`
+	scopeEpisode = `IN (SELECT id FROM episodes WHERE tenant_id = $1)`
+)
+
+// ErrBadBackupRow reports a backup row that can't be restored: an unknown
+// table, malformed JSON, or values the table rejects.
+var ErrBadBackupRow = errors.New("backup row cannot be restored")
+
+// backupTable is one table in a backup. Tables are listed in restore order,
+// parents before children.
+type backupTable struct {
+	name  string
+	scope string
+	// selfRef names a column referencing the same table. Restore inserts
+	// it as NULL and sets it once every row is in, so row order doesn't matter.
+	selfRef string
+	// partitioned tables have the partition key in their primary key, so ON
+	// CONFLICT misses an id already stored in another partition. Restore
+	// skips their rows by id instead.
+	partitioned bool
+}
+
+// backupTables is what a backup covers: the tenant's agents and everything
+// they remember. Derived and transient state (working memory, stats, run
+// history, recall logs, the outbox) is rebuilt by the server, and the audit
+// trail has its own export.
+var backupTables = []backupTable{
+	{name: "agents", scope: scopeTenant},
+	{name: "memory_policies", scope: scopeAgent},
+	{name: "retention_rules", scope: scopeTenant},
+	{name: "entities", scope: scopeAgent},
+	{name: "sessions", scope: scopeOwned + ` AND (anchor_id IS NULL OR anchor_id ` + scopeEntity + `)`},
+	// Shared vectors, referenced by memories and episodes in place of their own.
+	{name: "embeddings", scope: scopeTenant},
+	{name: "memories", partitioned: true, scope: scopeOwned +
+		` AND (anchor_id IS NULL OR anchor_id ` + scopeEntity + `)` +
+		` AND (session_id IS NULL OR session_id IN (SELECT id FROM sessions WHERE tenant_id = $1))`},
+	{name: "episodes", partitioned: true, scope: scopeOwned},
+	{name: "episode_chunks", scope: scopeOwned + ` AND episode_id ` + scopeEpisode},
+	{name: "procedures", scope: scopeOwned, selfRef: "previous_version_id"},
+	{name: "schemas", scope: scopeOwned, selfRef: "parent_id"},
+	{name: "entity_mentions", scope: `entity_id ` + scopeEntity + ` AND memory_id ` + scopeMemory},
+	{name: "memory_associations", scope: scopeTenant},
+	{name: "memory_graph", scope: `source_id ` + scopeMemory + ` AND target_id ` + scopeMemory},
+	{name: "belief_contradictions", scope: `belief_id ` + scopeMemory + ` AND contradicted_by_id ` + scopeMemory},
+	{name: "episode_associations", scope: `episode_a_id ` + scopeEpisode + ` AND episode_b_id ` + scopeEpisode},
+	{name: "feedback_signals", scope: scopeAgent + ` AND memory_id ` + scopeMemory},
+	{name: "episode_memory_usage", scope: `episode_id ` + scopeEpisode + ` AND memory_id ` + scopeMemory},
+	{name: "outcome_attributions", scope: `episode_id ` + scopeEpisode},
+	{name: "memory_tier_history", scope: scopeOwned + ` AND memory_id ` + scopeMemory},
+}
+
+func backupTableByName(name string) (backupTable, bool) {
+	for _, t := range backupTables {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return backupTable{}, false
+}
+
+// BackupStore exports and restores a tenant's data.
+type BackupStore struct {
+	db DB
+}
+
+func NewBackupStore(db DB) *BackupStore {
+	return &BackupStore{db: db}
+}
+
+// Export reads every table in one REPEATABLE READ, READ ONLY transaction, so
+// the backup is a consistent point-in-time snapshot even while the tenant
+// keeps writing. Rows are the JSON Postgres produces for them, embeddings
+// included; generated columns are left out.
+func (s *BackupStore) Export(ctx context.Context, tenantID uuid.UUID, start func(snapshotAt time.Time) error, emit func(table string, row json.RawMessage) error) error {
+	ctx = domain.WithTenantID(ctx, tenantID)
+	return WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`); err != nil {
+			return err
+		}
+		var snapshotAt time.Time
+		if err := tx.QueryRow(ctx, `SELECT now()`).Scan(&snapshotAt); err != nil {
+			return err
+		}
+		if err := start(snapshotAt); err != nil {
+			return err
+		}
+		for _, t := range backupTables {
+			if err := exportTable(ctx, tx, t, tenantID, emit); err != nil {
+				return fmt.Errorf("export %s: %w", t.name, err)
+			}
+		}
+		return nil
+	})
+}
+
+func exportTable(ctx context.Context, tx pgx.Tx, t backupTable, tenantID uuid.UUID, emit func(string, json.RawMessage) error) error {
+	_, generated, err := tableColumns(ctx, tx, t.name)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.Query(ctx,
+		`SELECT (to_jsonb(t) - $2::text[])::text FROM `+t.name+` t WHERE `+t.scope,
+		tenantID, generated)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+		if err := emit(t.name, json.RawMessage(row)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// tableColumns returns a table's writable and generated column names.
+func tableColumns(ctx context.Context, q DBTX, table string) (writable, generated []string, err error) {
+	rows, err := q.Query(ctx,
+		`SELECT attname, attgenerated <> '' FROM pg_attribute
+		 WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped
+		 ORDER BY attnum`, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var gen bool
+		if err := rows.Scan(&name, &gen); err != nil {
+			return nil, nil, err
+		}
+		if gen {
+			generated = append(generated, name)
+		} else {
+			writable = append(writable, name)
+		}
+	}
+	return writable, generated, rows.Err()
+}
+
+// restoreInsertSQL is the statement restoring one row of t, given as $2, for
+// the tenant $1. cols are the table's writable columns, quoted.
+func restoreInsertSQL(t backupTable, cols []string) string {
+	list := strings.Join(cols, ", ")
+	where := t.scope
+	if t.partitioned {
+		where = `(` + where + `) AND NOT EXISTS (SELECT 1 FROM ` + t.name + ` existing WHERE existing.id = r.id)`
+	}
+	return `INSERT INTO ` + t.name + ` (` + list + `)
+	       SELECT ` + list + ` FROM jsonb_populate_record(NULL::` + t.name + `, $2::jsonb) r
+	       WHERE ` + where + `
+	       ON CONFLICT DO NOTHING`
+}
+
+// pendingRef is a self-reference held back until every row is restored.
+type pendingRef struct {
+	table  backupTable
+	id, to json.RawMessage
+}
+
+// Restore inserts each row under tenantID: tenant_id columns are rewritten
+// to it, so a backup restores into a different tenant or environment. Rows
+// whose primary or unique keys already exist are skipped, as are memories and
+// episodes whose id is taken and rows that don't pass their table's scope for
+// the tenant.
+func (s *BackupStore) Restore(ctx context.Context, tenantID uuid.UUID, next func() (string, json.RawMessage, error)) (*domain.BackupRestoreResult, error) {
+	ctx = domain.WithTenantID(ctx, tenantID)
+	res := &domain.BackupRestoreResult{Restored: map[string]int{}, Skipped: map[string]int{}}
+	tenant, _ := json.Marshal(tenantID)
+
+	err := WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		inserts := map[string]string{}
+		var refs []pendingRef
+		for {
+			name, raw, err := next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			t, ok := backupTableByName(name)
+			if !ok {
+				return fmt.Errorf("%w: unknown table %q", ErrBadBackupRow, name)
+			}
+
+			var row map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &row); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrBadBackupRow, name, err)
+			}
+			if v, ok := row["tenant_id"]; ok && string(v) != "null" {
+				row["tenant_id"] = tenant
+			}
+			if t.selfRef != "" {
+				if v, ok := row[t.selfRef]; ok && string(v) != "null" {
+					refs = append(refs, pendingRef{table: t, id: row["id"], to: v})
+					row[t.selfRef] = json.RawMessage("null")
+				}
+			}
+			b, err := json.Marshal(row)
+			if err != nil {
+				return err
+			}
+
+			sql, ok := inserts[name]
+			if !ok {
+				writable, _, err := tableColumns(ctx, tx, name)
+				if err != nil {
+					return fmt.Errorf("restore %s: %w", name, err)
+				}
+				cols := make([]string, len(writable))
+				for i, c := range writable {
+					cols[i] = pgx.Identifier{c}.Sanitize()
+				}
+				sql = restoreInsertSQL(t, cols)
+				inserts[name] = sql
+			}
+			tag, err := tx.Exec(ctx, sql, tenantID, string(b))
+			if err != nil {
+				// Classes 22 (data exception) and 23 (integrity violation)
+				// are the row's fault, not the server's.
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && (strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")) {
+					return fmt.Errorf("%w: %s: %s", ErrBadBackupRow, name, pgErr.Message)
+				}
+				return fmt.Errorf("restore %s: %w", name, err)
+			}
+			if tag.RowsAffected() == 1 {
+				res.Restored[name]++
+			} else {
+				res.Skipped[name]++
+			}
+		}
+
+		for _, r := range refs {
+			var id, to uuid.UUID
+			if json.Unmarshal(r.id, &id) != nil || json.Unmarshal(r.to, &to) != nil {
+				continue
+			}
+			if _, err := tx.Exec(ctx,
+				`UPDATE `+r.table.name+` SET `+r.table.selfRef+` = $2
+				 WHERE id = $1 AND tenant_id = $3
+				   AND EXISTS (SELECT 1 FROM `+r.table.name+` WHERE id = $2 AND tenant_id = $3)`,
+				id, to, tenantID,
+			); err != nil {
+				return fmt.Errorf("restore %s.%s: %w", r.table.name, r.table.selfRef, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
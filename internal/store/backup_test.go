@@ -0,0 +1,27 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRestoreInsertSQL_PartitionedTablesSkipTakenIDs(t *testing.T) {
+	cols := []string{`"id"`, `"tenant_id"`}
+	for _, name := range []string{"memories", "episodes"} {
+		tbl, _ := backupTableByName(name)
+		sql := restoreInsertSQL(tbl, cols)
+		// The primary key includes the partition key, so an id stored in the
+		// other partition only shows up through an explicit check.
+		if !strings.Contains(sql, `NOT EXISTS (SELECT 1 FROM `+name+` existing WHERE existing.id = r.id)`) {
+			t.Errorf("expected %s rows to be skipped when their id is taken, got:\n%s", name, sql)
+		}
+		if !strings.Contains(sql, `WHERE (`+tbl.scope+`) AND`) {
+			t.Errorf("expected the %s scope to still apply, got:\n%s", name, sql)
+		}
+	}
+
+	agents, _ := backupTableByName("agents")
+	if sql := restoreInsertSQL(agents, cols); strings.Contains(sql, "NOT EXISTS") || !strings.Contains(sql, "ON CONFLICT DO NOTHING") {
+		t.Errorf("expected unpartitioned tables to rely on ON CONFLICT, got:\n%s", sql)
+	}
+}
@@ -22,10 +22,19 @@ func monthStartUTC() time.Time {
 // usage_counters rollup. See migration 017_billing.
 type BillingStore struct {
 	db *pgxpool.Pool
+	// agents is where CountAgents looks: the tenant's own database when
+	// tenants are routed, the control-plane pool otherwise.
+	agents DB
 }
 
 func NewBillingStore(db *pgxpool.Pool) *BillingStore {
-	return &BillingStore{db: db}
+	return &BillingStore{db: db, agents: db}
+}
+
+// SetAgentsDB points CountAgents at the database holding each tenant's
+// agents, typically a *TenantRouter.
+func (s *BillingStore) SetAgentsDB(db DB) {
+	s.agents = db
 }
 
 func (s *BillingStore) scanBilling(row pgx.Row) (*domain.Billing, error) {
@@ -118,7 +127,7 @@ func (s *BillingStore) increment(ctx context.Context, tenantID uuid.UUID, col st
 
 func (s *BillingStore) CountAgents(ctx context.Context, tenantID uuid.UUID) (int, error) {
 	var count int
-	err := s.db.QueryRow(ctx,
+	err := s.agents.QueryRow(domain.WithTenantID(ctx, tenantID),
 		`SELECT COUNT(*) FROM agents WHERE tenant_id = $1`, tenantID).Scan(&count)
 	return count, err
 }
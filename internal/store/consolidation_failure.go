@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ConsolidationFailureStore struct {
+	db DB
+}
+
+func NewConsolidationFailureStore(db DB) *ConsolidationFailureStore {
+	return &ConsolidationFailureStore{db: db}
+}
+
+func (s *ConsolidationFailureStore) RecordFailure(ctx context.Context, f *domain.ConsolidationFailure, deadLetterAfter int) error {
+	return s.db.QueryRow(ctx,
+		`INSERT INTO consolidation_failures (episode_id, tenant_id, agent_id, stage, failure_count, last_error, dead_lettered_at)
+		 VALUES ($1, $2, $3, $4, 1, $5, CASE WHEN $6 <= 1 THEN NOW() END)
+		 ON CONFLICT (episode_id) DO UPDATE SET
+			stage = EXCLUDED.stage,
+			failure_count = consolidation_failures.failure_count + 1,
+			last_error = EXCLUDED.last_error,
+			last_failed_at = NOW(),
+			dead_lettered_at = COALESCE(consolidation_failures.dead_lettered_at,
+				CASE WHEN consolidation_failures.failure_count + 1 >= $6 THEN NOW() END)
+		 RETURNING failure_count, first_failed_at, last_failed_at, dead_lettered_at`,
+		f.EpisodeID, f.TenantID, f.AgentID, f.Stage, f.LastError, deadLetterAfter,
+	).Scan(&f.FailureCount, &f.FirstFailedAt, &f.LastFailedAt, &f.DeadLetteredAt)
+}
+
+// ListDeadLettered returns the agent's dead-lettered episodes, most recent
+// first, with the total count.
+func (s *ConsolidationFailureStore) ListDeadLettered(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]domain.ConsolidationFailure, int, error) {
+	var total int
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM consolidation_failures
+		 WHERE agent_id = $1 AND tenant_id = $2 AND dead_lettered_at IS NOT NULL`,
+		agentID, tenantID,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT episode_id, tenant_id, agent_id, stage, failure_count, last_error,
+			first_failed_at, last_failed_at, dead_lettered_at
+		 FROM consolidation_failures
+		 WHERE agent_id = $1 AND tenant_id = $2 AND dead_lettered_at IS NOT NULL
+		 ORDER BY dead_lettered_at DESC
+		 LIMIT $3 OFFSET $4`,
+		agentID, tenantID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var out []domain.ConsolidationFailure
+	for rows.Next() {
+		var f domain.ConsolidationFailure
+		if err := rows.Scan(&f.EpisodeID, &f.TenantID, &f.AgentID, &f.Stage, &f.FailureCount, &f.LastError,
+			&f.FirstFailedAt, &f.LastFailedAt, &f.DeadLetteredAt); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, f)
+	}
+	return out, total, rows.Err()
+}
+
+func (s *ConsolidationFailureStore) Requeue(ctx context.Context, episodeID, tenantID uuid.UUID) error {
+	var n int
+	err := s.db.QueryRow(ctx,
+		`WITH cleared AS (
+			DELETE FROM consolidation_failures
+			 WHERE episode_id = $1 AND tenant_id = $2 AND dead_lettered_at IS NOT NULL
+			RETURNING episode_id
+		), requeued AS (
+			UPDATE episodes SET consolidation_status = 'processed'
+			 WHERE id IN (SELECT episode_id FROM cleared) AND tenant_id = $2
+			   AND consolidation_status = 'dead_letter'
+			RETURNING id
+		)
+		SELECT COUNT(*) FROM cleared`,
+		episodeID, tenantID,
+	).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
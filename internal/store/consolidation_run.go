@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ConsolidationRunStore struct {
+	db DB
+}
+
+func NewConsolidationRunStore(db DB) *ConsolidationRunStore {
+	return &ConsolidationRunStore{db: db}
+}
+
+func (s *ConsolidationRunStore) Create(ctx context.Context, run *domain.ConsolidationRun) error {
+	result := run.Result
+	if len(result) == 0 {
+		result = []byte("{}")
+	}
+	u := run.Usage
+	return s.db.QueryRow(ctx,
+		`INSERT INTO consolidation_runs (
+			tenant_id, agent_id, scope, result, llm_calls, input_tokens, output_tokens,
+			embedding_calls, embedding_tokens, estimated_cost_usd, started_at, finished_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id`,
+		run.TenantID, run.AgentID, run.Scope, result, u.LLMCalls, u.InputTokens, u.OutputTokens,
+		u.EmbeddingCalls, u.EmbeddingTokens, u.EstimatedCostUSD, run.StartedAt, run.FinishedAt,
+	).Scan(&run.ID)
+}
+
+// ListByAgent returns the agent's most recent runs, newest first.
+func (s *ConsolidationRunStore) ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit int) ([]domain.ConsolidationRun, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, tenant_id, agent_id, scope, result, llm_calls, input_tokens, output_tokens,
+			embedding_calls, embedding_tokens, estimated_cost_usd, started_at, finished_at
+		 FROM consolidation_runs
+		 WHERE agent_id = $1 AND tenant_id = $2
+		 ORDER BY started_at DESC
+		 LIMIT $3`,
+		agentID, tenantID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []domain.ConsolidationRun
+	for rows.Next() {
+		var run domain.ConsolidationRun
+		u := &run.Usage
+		if err := rows.Scan(&run.ID, &run.TenantID, &run.AgentID, &run.Scope, &run.Result,
+			&u.LLMCalls, &u.InputTokens, &u.OutputTokens, &u.EmbeddingCalls, &u.EmbeddingTokens,
+			&u.EstimatedCostUSD, &run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
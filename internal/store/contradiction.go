@@ -7,15 +7,14 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type ContradictionStore struct {
 	db   DBTX
-	pool *pgxpool.Pool
+	pool DB
 }
 
-func NewContradictionStore(db *pgxpool.Pool) *ContradictionStore {
+func NewContradictionStore(db DB) *ContradictionStore {
 	return &ContradictionStore{db: db, pool: db}
 }
 
@@ -77,6 +76,71 @@ func (s *ContradictionStore) ListByAgent(ctx context.Context, agentID, tenantID
 	return out, rows.Err()
 }
 
+// ListOpenByAgent returns one page of the agent's contradiction pairs whose
+// beliefs are both unarchived, newest first.
+func (s *ContradictionStore) ListOpenByAgent(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]domain.ContradictionPair, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT bc.belief_id, b.content, b.confidence, bc.contradicted_by_id, c.content, c.confidence, bc.detected_at
+		 FROM belief_contradictions bc
+		 JOIN memories b ON b.id = bc.belief_id
+		 JOIN memories c ON c.id = bc.contradicted_by_id
+		 WHERE b.agent_id = $1 AND b.tenant_id = $2
+		   AND b.is_archived = FALSE AND c.is_archived = FALSE
+		 ORDER BY bc.detected_at DESC, bc.id
+		 LIMIT $3 OFFSET $4`,
+		agentID, tenantID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.ContradictionPair
+	for rows.Next() {
+		var p domain.ContradictionPair
+		if err := rows.Scan(&p.BeliefID, &p.BeliefContent, &p.BeliefConfidence, &p.OtherID, &p.OtherContent, &p.OtherConfidence, &p.DetectedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// CountByTopic aggregates the agent's open contradiction pairs by the topic of
+// the contradicted belief, most contradicted topic first.
+func (s *ContradictionStore) CountByTopic(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.ContradictionTopicCount, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT COALESCE(NULLIF(b.topic, ''), b.type::text) AS topic,
+		        COUNT(DISTINCT bc.belief_id), COUNT(*)
+		 FROM belief_contradictions bc
+		 JOIN memories b ON b.id = bc.belief_id
+		 JOIN memories c ON c.id = bc.contradicted_by_id
+		 WHERE b.agent_id = $1 AND b.tenant_id = $2
+		   AND b.is_archived = FALSE AND c.is_archived = FALSE
+		 GROUP BY 1
+		 ORDER BY 2 DESC, 3 DESC, 1`,
+		agentID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.ContradictionTopicCount
+	for rows.Next() {
+		var c domain.ContradictionTopicCount
+		if err := rows.Scan(&c.Topic, &c.Contradicted, &c.Pairs); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
 func (s *ContradictionStore) GetByBeliefID(ctx context.Context, beliefID uuid.UUID) ([]domain.BeliefContradiction, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, belief_id, contradicted_by_id, detected_at
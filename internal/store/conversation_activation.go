@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConversationActivationStore struct {
+	db DB
+}
+
+func NewConversationActivationStore(db DB) *ConversationActivationStore {
+	return &ConversationActivationStore{db: db}
+}
+
+// Record upserts the activations in one statement. A memory activated more
+// than once in a conversation keeps its peak level.
+func (s *ConversationActivationStore) Record(ctx context.Context, acts []domain.ConversationActivation) error {
+	if len(acts) == 0 {
+		return nil
+	}
+	n := len(acts)
+	conversationIDs, tenantIDs, agentIDs, memoryIDs := make([]uuid.UUID, n), make([]uuid.UUID, n), make([]uuid.UUID, n), make([]uuid.UUID, n)
+	memoryTypes := make([]string, n)
+	levels := make([]float32, n)
+	for i := range acts {
+		a := &acts[i]
+		conversationIDs[i], tenantIDs[i], agentIDs[i], memoryIDs[i] = a.ConversationID, a.TenantID, a.AgentID, a.MemoryID
+		memoryTypes[i] = string(a.MemoryType)
+		levels[i] = a.ActivationLevel
+	}
+
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO conversation_activations (
+			conversation_id, tenant_id, agent_id, memory_type, memory_id, activation_level
+		)
+		SELECT conversation_id, tenant_id, agent_id, memory_type, memory_id, activation_level
+		FROM unnest($1::uuid[], $2::uuid[], $3::uuid[], $4::text[], $5::uuid[], $6::real[])
+			AS t(conversation_id, tenant_id, agent_id, memory_type, memory_id, activation_level)
+		ON CONFLICT (conversation_id, memory_type, memory_id) DO UPDATE SET
+			activation_level = GREATEST(conversation_activations.activation_level, EXCLUDED.activation_level),
+			activated_at = NOW()`,
+		conversationIDs, tenantIDs, agentIDs, memoryTypes, memoryIDs, levels,
+	)
+	return err
+}
+
+// ClaimForEpisode marks the conversation's activations as attributed to
+// episodeID and returns only those not already claimed by it, so a repeated
+// outcome for the same episode is applied once.
+func (s *ConversationActivationStore) ClaimForEpisode(ctx context.Context, episodeID, conversationID, tenantID uuid.UUID) ([]domain.ConversationActivation, error) {
+	rows, err := s.db.Query(ctx,
+		`WITH claimed AS (
+			INSERT INTO outcome_attributions (episode_id, memory_type, memory_id)
+			SELECT $1, memory_type, memory_id FROM conversation_activations
+			WHERE conversation_id = $2 AND tenant_id = $3
+			ON CONFLICT DO NOTHING
+			RETURNING memory_type, memory_id
+		)
+		SELECT a.tenant_id, a.agent_id, a.conversation_id, a.memory_type, a.memory_id,
+			a.activation_level, a.activated_at
+		FROM conversation_activations a
+		JOIN claimed c ON c.memory_type = a.memory_type AND c.memory_id = a.memory_id
+		WHERE a.conversation_id = $2 AND a.tenant_id = $3
+		ORDER BY a.activation_level DESC`,
+		episodeID, conversationID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanConversationActivations(rows)
+}
+
+// ListByConversation returns the conversation's activations, strongest first.
+func (s *ConversationActivationStore) ListByConversation(ctx context.Context, conversationID, tenantID uuid.UUID) ([]domain.ConversationActivation, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT tenant_id, agent_id, conversation_id, memory_type, memory_id,
+			activation_level, activated_at
+		FROM conversation_activations
+		WHERE conversation_id = $1 AND tenant_id = $2
+		ORDER BY activation_level DESC`,
+		conversationID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanConversationActivations(rows)
+}
+
+func scanConversationActivations(rows pgx.Rows) ([]domain.ConversationActivation, error) {
+	defer rows.Close()
+	var acts []domain.ConversationActivation
+	for rows.Next() {
+		var a domain.ConversationActivation
+		if err := rows.Scan(&a.TenantID, &a.AgentID, &a.ConversationID, &a.MemoryType, &a.MemoryID,
+			&a.ActivationLevel, &a.ActivatedAt); err != nil {
+			return nil, err
+		}
+		acts = append(acts, a)
+	}
+	return acts, rows.Err()
+}
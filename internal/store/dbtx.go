@@ -17,10 +17,23 @@ type DBTX interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// DB is what the data-plane stores hold: a DBTX that can also start a
+// transaction. *pgxpool.Pool satisfies it directly; a *TenantRouter satisfies
+// it by picking the pool that holds the calling tenant's data.
+type DB interface {
+	DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var (
+	_ DB = (*pgxpool.Pool)(nil)
+	_ DB = (*TenantRouter)(nil)
+)
+
 // WithTx runs fn inside a single transaction, committing on success and rolling
 // back on any error or panic (the deferred Rollback is a no-op after Commit).
-func WithTx(ctx context.Context, pool *pgxpool.Pool, fn func(tx pgx.Tx) error) error {
-	tx, err := pool.Begin(ctx)
+func WithTx(ctx context.Context, db DB, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
 	if err != nil {
 		return err
 	}
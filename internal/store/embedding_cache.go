@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	pgvector "github.com/pgvector/pgvector-go"
+)
+
+// storedEmbedding is the SQL for the full-precision vector of the memory or
+// episode aliased alias ("" when unaliased): its own embedding column, or
+// the embeddings row it references once the vector is shared.
+func storedEmbedding(alias string) string {
+	if alias != "" {
+		alias += "."
+	}
+	return fmt.Sprintf("stored_embedding(%[1]sembedding, %[1]stenant_id, %[1]sembedding_dedup_model, %[1]sembedding_hash)", alias)
+}
+
+// hasEmbedding is the SQL condition that the row aliased alias has a vector,
+// of its own or shared. The embeddings foreign key guarantees the latter.
+func hasEmbedding(alias string) string {
+	if alias != "" {
+		alias += "."
+	}
+	return fmt.Sprintf("(%[1]sembedding IS NOT NULL OR %[1]sembedding_hash IS NOT NULL)", alias)
+}
+
+// EmbeddingStore reads the content-addressed embeddings table for one model.
+// Rows are written by MemoryStore and EpisodeStore alongside the rows that
+// reference them; see their SetEmbeddingDedup.
+type EmbeddingStore struct {
+	db    DB
+	model string
+}
+
+func NewEmbeddingStore(db DB, model string) *EmbeddingStore {
+	return &EmbeddingStore{db: db, model: model}
+}
+
+func (s *EmbeddingStore) LookupEmbeddings(ctx context.Context, tenantID uuid.UUID, hashes []string) (map[string][]float32, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT content_hash, embedding FROM embeddings
+		 WHERE tenant_id = $1 AND model = $2 AND content_hash = ANY($3)`,
+		tenantID, s.model, hashes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[string][]float32, len(hashes))
+	for rows.Next() {
+		var hash string
+		var vec pgvector.Vector
+		if err := rows.Scan(&hash, &vec); err != nil {
+			return nil, err
+		}
+		found[hash] = vec.Slice()
+	}
+	return found, rows.Err()
+}
+
+// PruneEmbeddings deletes the vectors no memory or episode references.
+// Deletes and re-embeds release their vector as they happen (see migration
+// 060); this catches rows that went without row triggers, such as dropped
+// episode partitions. Vectors of an earlier model stay until no row still
+// referencing them is left un-re-embedded.
+func (s *EmbeddingStore) PruneEmbeddings(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx,
+		`DELETE FROM embeddings e
+		 WHERE NOT EXISTS (SELECT 1 FROM memories m
+		                    WHERE m.tenant_id = e.tenant_id AND m.embedding_dedup_model = e.model AND m.embedding_hash = e.content_hash)
+		   AND NOT EXISTS (SELECT 1 FROM episodes ep
+		                    WHERE ep.tenant_id = e.tenant_id AND ep.embedding_dedup_model = e.model AND ep.embedding_hash = e.content_hash)`,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
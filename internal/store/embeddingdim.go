@@ -8,20 +8,50 @@ import (
 	"go.uber.org/zap"
 )
 
-// embeddingVectorColumns are every (table, column, index) triple that stores a
-// pgvector embedding. Kept in sync with the migrations.
-var embeddingVectorColumns = []struct{ table, column, index string }{
-	{"memories", "embedding", "idx_memories_embedding"},
-	{"episodes", "embedding", "idx_episodes_embedding"},
-	{"procedures", "trigger_embedding", "idx_procedures_trigger_embedding"},
-	{"schemas", "embedding", "idx_schemas_embedding"},
-	{"entities", "embedding", "idx_entity_embedding"},
+// embeddingVectorColumns are every pgvector column sized to the embedding
+// dimension, with its index, type and operator class. Kept in sync with the
+// migrations.
+var embeddingVectorColumns = []struct{ table, column, index, typ, ops string }{
+	{"memories", "embedding", "idx_memories_embedding", "vector", "vector_cosine_ops"},
+	{"memories", "embedding_half", "idx_memories_embedding_half", "halfvec", "halfvec_cosine_ops"},
+	{"memories", "embedding_bit", "idx_memories_embedding_bit", "bit", "bit_hamming_ops"},
+	{"episodes", "embedding", "idx_episodes_embedding", "vector", "vector_cosine_ops"},
+	{"episode_chunks", "embedding", "idx_episode_chunks_embedding", "vector", "vector_cosine_ops"},
+	{"procedures", "trigger_embedding", "idx_procedures_trigger_embedding", "vector", "vector_cosine_ops"},
+	{"schemas", "embedding", "idx_schemas_embedding", "vector", "vector_cosine_ops"},
+	{"entities", "embedding", "idx_entity_embedding", "vector", "vector_cosine_ops"},
+	// Looked up by hash only, so never indexed.
+	{"embeddings", "embedding", "", "vector", ""},
+}
+
+// vectorIndexTable is the table a column's vector index is built on. Memories
+// and episodes are partitioned by archive state and only the active partition
+// is indexed.
+func vectorIndexTable(table string) string {
+	switch table {
+	case "memories", "episodes":
+		return table + "_active"
+	}
+	return table
 }
 
 // hnswMaxDim is pgvector's hard limit for an hnsw index (vectors wider than this
 // are stored but left unindexed — recall falls back to a sequential scan).
 const hnswMaxDim = 2000
 
+// hnswMaxDimFor is the hnsw limit for a pgvector type; the quantized types
+// index wider vectors than vector does.
+func hnswMaxDimFor(typ string) int {
+	switch typ {
+	case "halfvec":
+		return 4000
+	case "bit":
+		return 64000
+	default:
+		return hnswMaxDim
+	}
+}
+
 // EnsureEmbeddingDimension reconciles the vector columns with the configured
 // embedding dimension. It is a no-op when they already match. When they differ
 // AND no embeddings exist yet (a fresh deployment), it resizes the columns and
@@ -57,21 +87,26 @@ func EnsureEmbeddingDimension(ctx context.Context, pool *pgxpool.Pool, wantDim i
 		zap.Int("from", currentDim), zap.Int("to", wantDim))
 
 	for _, c := range embeddingVectorColumns {
-		if _, err := pool.Exec(ctx, fmt.Sprintf(`DROP INDEX IF EXISTS %s`, c.index)); err != nil {
-			return fmt.Errorf("drop index %s: %w", c.index, err)
+		if c.index != "" {
+			if _, err := pool.Exec(ctx, fmt.Sprintf(`DROP INDEX IF EXISTS %s`, c.index)); err != nil {
+				return fmt.Errorf("drop index %s: %w", c.index, err)
+			}
 		}
 		if _, err := pool.Exec(ctx, fmt.Sprintf(
-			`ALTER TABLE %s ALTER COLUMN %s TYPE vector(%d)`, c.table, c.column, wantDim)); err != nil {
+			`ALTER TABLE %s ALTER COLUMN %s TYPE %s(%d)`, c.table, c.column, c.typ, wantDim)); err != nil {
 			return fmt.Errorf("resize %s.%s: %w", c.table, c.column, err)
 		}
-		if wantDim <= hnswMaxDim {
+		if c.index == "" {
+			continue
+		}
+		if wantDim <= hnswMaxDimFor(c.typ) {
 			if _, err := pool.Exec(ctx, fmt.Sprintf(
-				`CREATE INDEX %s ON %s USING hnsw (%s vector_cosine_ops)`, c.index, c.table, c.column)); err != nil {
+				`CREATE INDEX %s ON %s USING hnsw (%s %s)`, c.index, vectorIndexTable(c.table), c.column, c.ops)); err != nil {
 				return fmt.Errorf("recreate index %s: %w", c.index, err)
 			}
 		} else {
 			logger.Warn("embedding dimension exceeds hnsw limit; column left unindexed (recall uses sequential scan)",
-				zap.String("table", c.table), zap.Int("dim", wantDim), zap.Int("hnsw_max", hnswMaxDim))
+				zap.String("table", c.table), zap.String("column", c.column), zap.Int("dim", wantDim), zap.Int("hnsw_max", hnswMaxDimFor(c.typ)))
 		}
 	}
 
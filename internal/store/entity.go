@@ -8,14 +8,13 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type EntityStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewEntityStore(db *pgxpool.Pool) *EntityStore {
+func NewEntityStore(db DB) *EntityStore {
 	return &EntityStore{db: db}
 }
 
@@ -332,6 +331,36 @@ func (s *EntityStore) GetEntitiesForMemory(ctx context.Context, memoryID uuid.UU
 	return entities, rows.Err()
 }
 
+// ListMentionedIn returns the distinct entities mentioned by any of the
+// memories, excluding the given one (typically the subject's own anchor).
+func (s *EntityStore) ListMentionedIn(ctx context.Context, memoryIDs []uuid.UUID, exclude uuid.UUID) ([]domain.Entity, error) {
+	if len(memoryIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT DISTINCT e.id, e.agent_id, e.name, e.entity_type, e.aliases, e.metadata, e.created_at, e.updated_at
+		 FROM entities e
+		 INNER JOIN entity_mentions em ON em.entity_id = e.id
+		 WHERE em.memory_id = ANY($1) AND e.id <> $2
+		 ORDER BY e.name`,
+		memoryIDs, exclude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []domain.Entity
+	for rows.Next() {
+		var e domain.Entity
+		if err := rows.Scan(&e.ID, &e.AgentID, &e.Name, &e.EntityType, &e.Aliases, &e.Metadata, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entities = append(entities, e)
+	}
+	return entities, rows.Err()
+}
+
 func (s *EntityStore) FindByEmbeddingSimilarity(ctx context.Context, agentID uuid.UUID, entityType domain.EntityType, embedding []float32, threshold float32, limit int) ([]domain.Entity, error) {
 	if len(embedding) == 0 {
 		return nil, nil
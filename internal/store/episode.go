@@ -5,21 +5,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	pgvector "github.com/pgvector/pgvector-go"
 )
 
 type EpisodeStore struct {
-	db *pgxpool.Pool
+	db   DBTX
+	pool DB
+	// embeddingModel, when set, files each embedding in the embeddings table;
+	// see SetEmbeddingDedup.
+	embeddingModel string
 }
 
-func NewEpisodeStore(db *pgxpool.Pool) *EpisodeStore {
-	return &EpisodeStore{db: db}
+func NewEpisodeStore(db DB) *EpisodeStore {
+	return &EpisodeStore{db: db, pool: db}
+}
+
+// withTx returns a clone of the store that runs against the given transaction.
+func (s *EpisodeStore) withTx(tx pgx.Tx) *EpisodeStore {
+	return &EpisodeStore{db: tx, pool: s.pool, embeddingModel: s.embeddingModel}
+}
+
+// SetEmbeddingDedup files every episode embedding in the embeddings table
+// under model, keyed by the hash of the raw content, and leaves the episode
+// referencing it in place of a copy of its own.
+func (s *EpisodeStore) SetEmbeddingDedup(model string) {
+	s.embeddingModel = model
 }
 
 func (s *EpisodeStore) Create(ctx context.Context, e *domain.Episode) error {
@@ -45,6 +61,33 @@ func (s *EpisodeStore) Create(ctx context.Context, e *domain.Episode) error {
 		return fmt.Errorf("marshal topics: %w", err)
 	}
 
+	attachments := e.Attachments
+	if attachments == nil {
+		attachments = []domain.EpisodeAttachment{}
+	}
+	attachmentsJSON, err := json.Marshal(attachments)
+	if err != nil {
+		return fmt.Errorf("marshal attachments: %w", err)
+	}
+
+	participants := e.Participants
+	if participants == nil {
+		participants = []domain.EpisodeParticipant{}
+	}
+	participantsJSON, err := json.Marshal(participants)
+	if err != nil {
+		return fmt.Errorf("marshal participants: %w", err)
+	}
+
+	environment := domain.Environment{}
+	if e.Environment != nil {
+		environment = *e.Environment
+	}
+	environmentJSON, err := json.Marshal(environment)
+	if err != nil {
+		return fmt.Errorf("marshal environment: %w", err)
+	}
+
 	// Set defaults
 	if e.ConsolidationStatus == "" {
 		e.ConsolidationStatus = domain.ConsolidationRaw
@@ -71,37 +114,86 @@ func (s *EpisodeStore) Create(ctx context.Context, e *domain.Episode) error {
 		outcome = &outcomeStr
 	}
 
+	// A shared vector is stored once, in embeddings, and not on the row.
+	var embeddingHash *string
+	rowEmbedding := embedding
+	if s.embeddingModel != "" && embedding != nil {
+		h := domain.HashContent(e.RawContent)
+		embeddingHash = &h
+		rowEmbedding = nil
+	}
+
 	return s.db.QueryRow(ctx,
-		`INSERT INTO episodes (
-			agent_id, tenant_id, raw_content, conversation_id, message_sequence,
-			occurred_at, duration_seconds, time_of_day, day_of_week,
-			emotional_valence, emotional_intensity, importance_score,
-			entities, causal_links, topics,
-			outcome, outcome_description, outcome_valence,
-			consolidation_status, memory_strength, decay_rate, access_count,
-			embedding
-		) VALUES (
-			$1, $2, $3, $4, $5,
-			$6, $7, $8, $9,
-			$10, $11, $12,
-			$13, $14, $15,
-			$16, $17, $18,
-			$19, $20, $21, $22,
-			$23
-		) RETURNING id, last_accessed_at, created_at, updated_at`,
+		`WITH ep AS (
+			INSERT INTO episodes (
+				agent_id, tenant_id, raw_content, conversation_id, message_sequence,
+				occurred_at, duration_seconds, time_of_day, day_of_week,
+				emotional_valence, emotional_intensity, importance_score,
+				entities, causal_links, topics,
+				outcome, outcome_description, outcome_valence,
+				consolidation_status, memory_strength, decay_rate, access_count,
+				embedding, attachments, participants, environment, embedding_hash, embedding_dedup_model
+			) VALUES (
+				$1, $2, $3, $4, $5,
+				$6, $7, $8, $9,
+				$10, $11, $12,
+				$13, $14, $15,
+				$16, $17, $18,
+				$19, $20, $21, $22,
+				$23, $24, $25, $26, $27, CASE WHEN $27::text IS NOT NULL THEN $28::text END
+			) RETURNING id, tenant_id, embedding_hash, last_accessed_at, created_at, updated_at
+		), emb AS (
+			INSERT INTO embeddings (tenant_id, model, content_hash, embedding)
+			SELECT tenant_id, $28, embedding_hash, $29::vector FROM ep WHERE embedding_hash IS NOT NULL
+			ON CONFLICT DO NOTHING
+		)
+		SELECT id, last_accessed_at, created_at, updated_at FROM ep`,
 		e.AgentID, e.TenantID, e.RawContent, e.ConversationID, e.MessageSequence,
 		e.OccurredAt, e.DurationSeconds, e.TimeOfDay, e.DayOfWeek,
 		e.EmotionalValence, e.EmotionalIntensity, e.ImportanceScore,
 		entitiesJSON, causalLinksJSON, topicsJSON,
 		outcome, e.OutcomeDescription, e.OutcomeValence,
 		e.ConsolidationStatus, e.MemoryStrength, e.DecayRate, e.AccessCount,
-		embedding,
+		rowEmbedding, attachmentsJSON, participantsJSON, environmentJSON, embeddingHash, s.embeddingModel, embedding,
 	).Scan(&e.ID, &e.LastAccessedAt, &e.CreatedAt, &e.UpdatedAt)
 }
 
+// unmarshalEnvironment sets e.Environment from its column, leaving it nil
+// when none was recorded.
+func unmarshalEnvironment(raw []byte, e *domain.Episode) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var env domain.Environment
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("unmarshal environment: %w", err)
+	}
+	if !env.IsZero() {
+		e.Environment = &env
+	}
+	return nil
+}
+
+// CreateBatch inserts episodes in one transaction, so a burst commits once
+// instead of once per row. They must share a tenant, which picks the database.
+func (s *EpisodeStore) CreateBatch(ctx context.Context, episodes []*domain.Episode) error {
+	if len(episodes) == 0 {
+		return nil
+	}
+	return WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		ts := s.withTx(tx)
+		for _, e := range episodes {
+			if err := ts.Create(ctx, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *EpisodeStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.Episode, error) {
 	e := &domain.Episode{}
-	var entitiesJSON, causalLinksJSON, topicsJSON []byte
+	var entitiesJSON, causalLinksJSON, topicsJSON, attachmentsJSON, participantsJSON, environmentJSON []byte
 	var outcome *string
 
 	err := s.db.QueryRow(ctx,
@@ -112,7 +204,7 @@ func (s *EpisodeStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
 		FROM episodes WHERE id = $1 AND tenant_id = $2`,
 		id, tenantID,
@@ -124,7 +216,7 @@ func (s *EpisodeStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.
 		&outcome, &e.OutcomeDescription, &e.OutcomeValence,
 		&e.ConsolidationStatus, &e.LastConsolidatedAt, &e.AbstractionCount,
 		&e.DerivedSemanticIDs, &e.DerivedProceduralIDs,
-		&e.MemoryStrength, &e.LastAccessedAt, &e.AccessCount, &e.DecayRate,
+		&e.MemoryStrength, &e.LastAccessedAt, &e.AccessCount, &e.DecayRate, &attachmentsJSON, &participantsJSON, &environmentJSON,
 		&e.CreatedAt, &e.UpdatedAt,
 	)
 	if err != nil {
@@ -150,6 +242,19 @@ func (s *EpisodeStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.
 			return nil, fmt.Errorf("unmarshal topics: %w", err)
 		}
 	}
+	if len(attachmentsJSON) > 0 {
+		if err := json.Unmarshal(attachmentsJSON, &e.Attachments); err != nil {
+			return nil, fmt.Errorf("unmarshal attachments: %w", err)
+		}
+	}
+	if len(participantsJSON) > 0 {
+		if err := json.Unmarshal(participantsJSON, &e.Participants); err != nil {
+			return nil, fmt.Errorf("unmarshal participants: %w", err)
+		}
+	}
+	if err := unmarshalEnvironment(environmentJSON, e); err != nil {
+		return nil, err
+	}
 
 	if outcome != nil {
 		e.Outcome = domain.OutcomeType(*outcome)
@@ -167,7 +272,7 @@ func (s *EpisodeStore) GetByConversationID(ctx context.Context, conversationID u
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
 		FROM episodes WHERE conversation_id = $1 AND tenant_id = $2
 		ORDER BY message_sequence, occurred_at`,
@@ -181,6 +286,67 @@ func (s *EpisodeStore) GetByConversationID(ctx context.Context, conversationID u
 	return s.scanEpisodes(rows)
 }
 
+// GetConversationEnds orders each conversation's episodes as
+// GetByConversationID does and returns the first and last of them.
+func (s *EpisodeStore) GetConversationEnds(ctx context.Context, tenantID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]domain.ConversationEnds, error) {
+	ends := make(map[uuid.UUID]domain.ConversationEnds)
+	if len(conversationIDs) == 0 {
+		return ends, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT conversation_id, COUNT(*),
+			(array_agg(id ORDER BY message_sequence, occurred_at, id))[1],
+			(array_agg(id ORDER BY message_sequence DESC, occurred_at DESC, id DESC))[1]
+		FROM episodes WHERE tenant_id = $1 AND conversation_id = ANY($2)
+		GROUP BY conversation_id`,
+		tenantID, conversationIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var convID uuid.UUID
+		var e domain.ConversationEnds
+		if err := rows.Scan(&convID, &e.Episodes, &e.First, &e.Last); err != nil {
+			return nil, err
+		}
+		ends[convID] = e
+	}
+	return ends, rows.Err()
+}
+
+// ListForSubject returns the episodes behind a subject's data: those recorded
+// under one of the conversation IDs, or from which one of the memories was
+// derived, including archived ones, oldest first.
+func (s *EpisodeStore) ListForSubject(ctx context.Context, tenantID uuid.UUID, conversationIDs, memoryIDs []uuid.UUID) ([]domain.Episode, error) {
+	if len(conversationIDs) == 0 && len(memoryIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, raw_content, conversation_id, message_sequence,
+			occurred_at, duration_seconds, time_of_day, day_of_week,
+			emotional_valence, emotional_intensity, importance_score,
+			entities, causal_links, topics,
+			outcome, outcome_description, outcome_valence,
+			consolidation_status, last_consolidated_at, abstraction_count,
+			derived_semantic_ids, derived_procedural_ids,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
+			created_at, updated_at
+		FROM episodes
+		WHERE tenant_id = $1 AND (conversation_id = ANY($2) OR derived_semantic_ids && $3)
+		ORDER BY occurred_at`,
+		tenantID, conversationIDs, memoryIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanEpisodes(rows)
+}
+
 func (s *EpisodeStore) GetByTimeRange(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, start, end time.Time) ([]domain.Episode, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, agent_id, tenant_id, raw_content, conversation_id, message_sequence,
@@ -190,7 +356,7 @@ func (s *EpisodeStore) GetByTimeRange(ctx context.Context, agentID uuid.UUID, te
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
 		FROM episodes WHERE agent_id = $1 AND tenant_id = $2 AND occurred_at >= $3 AND occurred_at <= $4
 		ORDER BY occurred_at DESC`,
@@ -217,7 +383,7 @@ func (s *EpisodeStore) GetByImportance(ctx context.Context, agentID uuid.UUID, t
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
 		FROM episodes WHERE agent_id = $1 AND tenant_id = $2 AND importance_score >= $3
 		ORDER BY importance_score DESC, occurred_at DESC
@@ -247,11 +413,11 @@ func (s *EpisodeStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenan
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at,
-			1 - (embedding <=> $1) AS score
+			1 - (`+storedEmbedding("")+` <=> $1) AS score
 		FROM episodes
-		WHERE agent_id = $2 AND tenant_id = $3 AND embedding IS NOT NULL AND 1 - (embedding <=> $1) >= $4
+		WHERE agent_id = $2 AND tenant_id = $3 AND is_archived = FALSE AND `+hasEmbedding("")+` AND 1 - (`+storedEmbedding("")+` <=> $1) >= $4
 		ORDER BY score DESC
 		LIMIT $5`,
 		vec, agentID, tenantID, threshold, limit,
@@ -264,7 +430,7 @@ func (s *EpisodeStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenan
 	var results []domain.EpisodeWithScore
 	for rows.Next() {
 		var e domain.EpisodeWithScore
-		var entitiesJSON, causalLinksJSON, topicsJSON []byte
+		var entitiesJSON, causalLinksJSON, topicsJSON, attachmentsJSON, participantsJSON, environmentJSON []byte
 		var outcome *string
 
 		err := rows.Scan(
@@ -275,7 +441,7 @@ func (s *EpisodeStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenan
 			&outcome, &e.OutcomeDescription, &e.OutcomeValence,
 			&e.ConsolidationStatus, &e.LastConsolidatedAt, &e.AbstractionCount,
 			&e.DerivedSemanticIDs, &e.DerivedProceduralIDs,
-			&e.MemoryStrength, &e.LastAccessedAt, &e.AccessCount, &e.DecayRate,
+			&e.MemoryStrength, &e.LastAccessedAt, &e.AccessCount, &e.DecayRate, &attachmentsJSON, &participantsJSON, &environmentJSON,
 			&e.CreatedAt, &e.UpdatedAt,
 			&e.Score,
 		)
@@ -292,6 +458,13 @@ func (s *EpisodeStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenan
 		if len(topicsJSON) > 0 {
 			_ = json.Unmarshal(topicsJSON, &e.Topics)
 		}
+		if len(attachmentsJSON) > 0 {
+			_ = json.Unmarshal(attachmentsJSON, &e.Attachments)
+		}
+		if len(participantsJSON) > 0 {
+			_ = json.Unmarshal(participantsJSON, &e.Participants)
+		}
+		_ = unmarshalEnvironment(environmentJSON, &e.Episode)
 		if outcome != nil {
 			e.Outcome = domain.OutcomeType(*outcome)
 		}
@@ -315,7 +488,7 @@ func (s *EpisodeStore) GetUnconsolidated(ctx context.Context, agentID uuid.UUID,
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
 		FROM episodes WHERE agent_id = $1 AND consolidation_status = 'raw'
 		ORDER BY occurred_at ASC
@@ -330,6 +503,39 @@ func (s *EpisodeStore) GetUnconsolidated(ctx context.Context, agentID uuid.UUID,
 	return s.scanEpisodes(rows)
 }
 
+// GetReplayCandidates returns up to limit of the oldest and up to limit of
+// the newest raw episodes, oldest first, so that consolidation can pick what
+// to replay from both ends of the backlog.
+func (s *EpisodeStore) GetReplayCandidates(ctx context.Context, agentID uuid.UUID, limit int) ([]domain.Episode, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, raw_content, conversation_id, message_sequence,
+			occurred_at, duration_seconds, time_of_day, day_of_week,
+			emotional_valence, emotional_intensity, importance_score,
+			entities, causal_links, topics,
+			outcome, outcome_description, outcome_valence,
+			consolidation_status, last_consolidated_at, abstraction_count,
+			derived_semantic_ids, derived_procedural_ids,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
+			created_at, updated_at
+		FROM episodes WHERE agent_id = $1 AND consolidation_status = 'raw' AND id IN (
+			(SELECT id FROM episodes WHERE agent_id = $1 AND consolidation_status = 'raw' ORDER BY occurred_at ASC LIMIT $2)
+			UNION
+			(SELECT id FROM episodes WHERE agent_id = $1 AND consolidation_status = 'raw' ORDER BY occurred_at DESC LIMIT $2))
+		ORDER BY occurred_at ASC`,
+		agentID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanEpisodes(rows)
+}
+
 func (s *EpisodeStore) GetByConsolidationStatus(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, status domain.ConsolidationStatus, limit int) ([]domain.Episode, error) {
 	if limit <= 0 {
 		limit = 100
@@ -343,7 +549,7 @@ func (s *EpisodeStore) GetByConsolidationStatus(ctx context.Context, agentID uui
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
 		FROM episodes WHERE agent_id = $1 AND tenant_id = $2 AND consolidation_status = $3
 		ORDER BY occurred_at ASC
@@ -360,7 +566,10 @@ func (s *EpisodeStore) GetByConsolidationStatus(ctx context.Context, agentID uui
 
 func (s *EpisodeStore) UpdateConsolidationStatus(ctx context.Context, id uuid.UUID, status domain.ConsolidationStatus) error {
 	tag, err := s.db.Exec(ctx,
-		`UPDATE episodes SET consolidation_status = $1, last_consolidated_at = NOW(), updated_at = NOW() WHERE id = $2`,
+		`UPDATE episodes SET consolidation_status = $1, is_archived = ($1 = 'archived'),
+			archived_at = CASE WHEN $1 = 'archived' THEN COALESCE(archived_at, NOW()) END,
+			last_consolidated_at = NOW(), updated_at = NOW()
+		WHERE id = $2`,
 		status, id,
 	)
 	if err != nil {
@@ -396,12 +605,19 @@ func (s *EpisodeStore) LinkDerivedMemory(ctx context.Context, episodeID uuid.UUI
 func (s *EpisodeStore) ApplyDecay(ctx context.Context, agentID uuid.UUID) (int64, error) {
 	// Apply exponential decay based on time since last access
 	// new_strength = memory_strength * exp(-decay_rate * hours_since_access / 24)
+	// Flashbulb episodes decay at a fraction of their rate and stop at a
+	// floor; see domain.Episode.DecayedStrength.
 	tag, err := s.db.Exec(ctx,
 		`UPDATE episodes
-		SET memory_strength = GREATEST(0.0, memory_strength * EXP(-decay_rate * EXTRACT(EPOCH FROM (NOW() - last_accessed_at)) / 86400)),
+		SET memory_strength = CASE
+				WHEN importance_score >= $2 OR COALESCE(emotional_intensity, 0) >= $3 THEN
+					GREATEST(LEAST(memory_strength, $5),
+						memory_strength * EXP(-decay_rate * $4 * EXTRACT(EPOCH FROM (NOW() - last_accessed_at)) / 86400))
+				ELSE GREATEST(0.0, memory_strength * EXP(-decay_rate * EXTRACT(EPOCH FROM (NOW() - last_accessed_at)) / 86400))
+			END,
 			updated_at = NOW()
-		WHERE agent_id = $1 AND consolidation_status != 'archived'`,
-		agentID,
+		WHERE agent_id = $1 AND is_archived = FALSE`,
+		agentID, domain.FlashbulbImportance, domain.FlashbulbEmotionalIntensity, domain.FlashbulbDecaySlowdown, domain.FlashbulbStrengthFloor,
 	)
 	if err != nil {
 		return 0, err
@@ -418,9 +634,9 @@ func (s *EpisodeStore) GetByAgentForDecay(ctx context.Context, agentID uuid.UUID
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
-		FROM episodes WHERE agent_id = $1 AND consolidation_status != 'archived'
+		FROM episodes WHERE agent_id = $1 AND is_archived = FALSE
 		ORDER BY last_accessed_at ASC
 		LIMIT 10000`,
 		agentID,
@@ -442,11 +658,12 @@ func (s *EpisodeStore) GetWeakMemories(ctx context.Context, agentID uuid.UUID, t
 			outcome, outcome_description, outcome_valence,
 			consolidation_status, last_consolidated_at, abstraction_count,
 			derived_semantic_ids, derived_procedural_ids,
-			memory_strength, last_accessed_at, access_count, decay_rate,
+			memory_strength, last_accessed_at, access_count, decay_rate, attachments, participants, environment,
 			created_at, updated_at
-		FROM episodes WHERE agent_id = $1 AND memory_strength < $2 AND consolidation_status != 'archived'
+		FROM episodes WHERE agent_id = $1 AND memory_strength < $2 AND is_archived = FALSE
+			AND importance_score < $3 AND COALESCE(emotional_intensity, 0) < $4
 		ORDER BY memory_strength ASC`,
-		agentID, threshold,
+		agentID, threshold, domain.FlashbulbImportance, domain.FlashbulbEmotionalIntensity,
 	)
 	if err != nil {
 		return nil, err
@@ -456,9 +673,11 @@ func (s *EpisodeStore) GetWeakMemories(ctx context.Context, agentID uuid.UUID, t
 	return s.scanEpisodes(rows)
 }
 
+// Archive marks the episode archived, which moves it to episodes_archived.
 func (s *EpisodeStore) Archive(ctx context.Context, id uuid.UUID) error {
 	tag, err := s.db.Exec(ctx,
-		`UPDATE episodes SET consolidation_status = 'archived', updated_at = NOW() WHERE id = $1`,
+		`UPDATE episodes SET consolidation_status = 'archived', is_archived = TRUE, archived_at = NOW(), updated_at = NOW()
+		WHERE id = $1`,
 		id,
 	)
 	if err != nil {
@@ -553,7 +772,7 @@ func (s *EpisodeStore) scanEpisodes(rows pgx.Rows) ([]domain.Episode, error) {
 	var episodes []domain.Episode
 	for rows.Next() {
 		var e domain.Episode
-		var entitiesJSON, causalLinksJSON, topicsJSON []byte
+		var entitiesJSON, causalLinksJSON, topicsJSON, attachmentsJSON, participantsJSON, environmentJSON []byte
 		var outcome *string
 
 		err := rows.Scan(
@@ -564,7 +783,7 @@ func (s *EpisodeStore) scanEpisodes(rows pgx.Rows) ([]domain.Episode, error) {
 			&outcome, &e.OutcomeDescription, &e.OutcomeValence,
 			&e.ConsolidationStatus, &e.LastConsolidatedAt, &e.AbstractionCount,
 			&e.DerivedSemanticIDs, &e.DerivedProceduralIDs,
-			&e.MemoryStrength, &e.LastAccessedAt, &e.AccessCount, &e.DecayRate,
+			&e.MemoryStrength, &e.LastAccessedAt, &e.AccessCount, &e.DecayRate, &attachmentsJSON, &participantsJSON, &environmentJSON,
 			&e.CreatedAt, &e.UpdatedAt,
 		)
 		if err != nil {
@@ -580,6 +799,13 @@ func (s *EpisodeStore) scanEpisodes(rows pgx.Rows) ([]domain.Episode, error) {
 		if len(topicsJSON) > 0 {
 			_ = json.Unmarshal(topicsJSON, &e.Topics)
 		}
+		if len(attachmentsJSON) > 0 {
+			_ = json.Unmarshal(attachmentsJSON, &e.Attachments)
+		}
+		if len(participantsJSON) > 0 {
+			_ = json.Unmarshal(participantsJSON, &e.Participants)
+		}
+		_ = unmarshalEnvironment(environmentJSON, &e)
 		if outcome != nil {
 			e.Outcome = domain.OutcomeType(*outcome)
 		}
@@ -589,3 +815,136 @@ func (s *EpisodeStore) scanEpisodes(rows pgx.Rows) ([]domain.Episode, error) {
 
 	return episodes, rows.Err()
 }
+
+// EnsurePartitions creates the monthly episode partitions from the month of
+// from through the month of through (inclusive, UTC), skipping those that
+// already exist.
+func (s *EpisodeStore) EnsurePartitions(ctx context.Context, from, through time.Time) error {
+	for m := monthStart(from); !m.After(through); m = m.AddDate(0, 1, 0) {
+		if _, err := s.db.Exec(ctx, `SELECT ensure_episode_partition($1::date)`, m); err != nil {
+			return fmt.Errorf("ensure episode partition %s: %w", m.Format("2006-01"), err)
+		}
+	}
+	return nil
+}
+
+// DropPartitionsBefore drops every monthly episode partition, active or
+// archived, that ends at or before cutoff, together with the associations and
+// usage rows that point into it, and returns the names of the dropped
+// partitions. The default partitions are never dropped.
+func (s *EpisodeStore) DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT c.relname, p.relname FROM pg_inherits i
+		   JOIN pg_class c ON c.oid = i.inhrelid
+		   JOIN pg_class p ON p.oid = i.inhparent
+		  WHERE i.inhparent IN ('episodes_active'::regclass, 'episodes_archived'::regclass)`)
+	if err != nil {
+		return nil, err
+	}
+	type partition struct{ name, parent string }
+	var expired []partition
+	for rows.Next() {
+		var p partition
+		if err := rows.Scan(&p.name, &p.parent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if start, ok := episodePartitionMonth(p.name); ok && !start.AddDate(0, 1, 0).After(cutoff) {
+			expired = append(expired, p)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, p := range expired {
+		name, part := p.name, pgx.Identifier{p.name}.Sanitize()
+		err := WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, `ALTER TABLE `+pgx.Identifier{p.parent}.Sanitize()+` DETACH PARTITION `+part); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`DELETE FROM episode_associations
+				  WHERE episode_a_id IN (SELECT id FROM `+part+`)
+				     OR episode_b_id IN (SELECT id FROM `+part+`)`); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`DELETE FROM episode_memory_usage WHERE episode_id IN (SELECT id FROM `+part+`)`); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`DELETE FROM episode_chunks WHERE episode_id IN (SELECT id FROM `+part+`)`); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`DELETE FROM outcome_attributions WHERE episode_id IN (SELECT id FROM `+part+`)`); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`DELETE FROM consolidation_failures WHERE episode_id IN (SELECT id FROM `+part+`)`); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `DROP TABLE `+part)
+			return err
+		})
+		if err != nil {
+			return dropped, fmt.Errorf("drop episode partition %s: %w", name, err)
+		}
+		dropped = append(dropped, name)
+	}
+	return dropped, nil
+}
+
+// episodePartitionMonth parses the month out of a partition name created by
+// ensure_episode_partition ("episodes_y2024m03", "episodes_archived_y2024m03").
+func episodePartitionMonth(name string) (time.Time, bool) {
+	t, err := time.Parse(`episodes_y2006m01`, strings.Replace(name, "episodes_archived_", "episodes_", 1))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// SearchTenant finds episodes of every agent in the tenant, including archived
+// ones, best match first. Full-text matching computes the tsvector on the fly,
+// since episodes carry no stored one.
+func (s *EpisodeStore) SearchTenant(ctx context.Context, tenantID uuid.UUID, q domain.TenantSearchQuery) ([]domain.EpisodeSearchHit, error) {
+	args := []any{tenantID}
+	match, score := tenantSearchCondition(q, "raw_content", "to_tsvector('english', raw_content)", &args)
+	where := "tenant_id = $1 AND " + match
+	if q.AgentID != nil {
+		args = append(args, *q.AgentID)
+		where += fmt.Sprintf(" AND agent_id = $%d", len(args))
+	}
+	args = append(args, q.Limit, q.Offset)
+
+	rows, err := s.db.Query(ctx,
+		fmt.Sprintf(`SELECT id, agent_id, conversation_id, raw_content, occurred_at, is_archived, (%s)::real AS score
+		 FROM episodes WHERE %s
+		 ORDER BY score DESC, occurred_at DESC
+		 LIMIT $%d OFFSET $%d`, score, where, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search tenant episodes: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []domain.EpisodeSearchHit
+	for rows.Next() {
+		var h domain.EpisodeSearchHit
+		if err := rows.Scan(&h.ID, &h.AgentID, &h.ConversationID, &h.RawContent, &h.OccurredAt, &h.Archived, &h.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// benchRows replays one decoded row n times, so benchmarks measure the scan
+// loop and JSON decoding without a database.
+type benchRows struct {
+	row []any
+	n   int
+}
+
+func (r *benchRows) Close()                                       {}
+func (r *benchRows) Err() error                                   { return nil }
+func (r *benchRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *benchRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *benchRows) Values() ([]any, error)                       { return r.row, nil }
+func (r *benchRows) RawValues() [][]byte                          { return nil }
+func (r *benchRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *benchRows) Next() bool {
+	r.n--
+	return r.n >= 0
+}
+
+func (r *benchRows) Scan(dest ...any) error {
+	if len(dest) != len(r.row) {
+		return fmt.Errorf("scan: %d destinations for %d columns", len(dest), len(r.row))
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.row[i]))
+	}
+	return nil
+}
+
+// benchEpisodeRow is an episodes row in the column order scanEpisodes reads.
+func benchEpisodeRow() []any {
+	now := time.Now()
+	conversationID := uuid.New()
+	seq, duration := 3, 240
+	valence, intensity, outcomeValence := float32(0.4), float32(0.6), float32(0.8)
+	outcome := string(domain.OutcomeSuccess)
+	return []any{
+		uuid.New(), uuid.New(), uuid.New(),
+		"user: the CSV importer times out on large files\nassistant: raised the timeout, please retry\nuser: works now, thanks",
+		&conversationID, &seq,
+		now, &duration, "morning", "tuesday",
+		&valence, &intensity, float32(0.7),
+		[]byte(`["CSV importer","Alice Okafor","Northwind"]`),
+		[]byte(`[{"cause":"large file","effect":"timeout","confidence":0.8}]`),
+		[]byte(`["support","imports"]`),
+		&outcome, "timeout raised", &outcomeValence,
+		domain.ConsolidationProcessed, &now, 1,
+		[]uuid.UUID{uuid.New(), uuid.New()}, []uuid.UUID{},
+		float32(0.9), now, 4, float32(0.01),
+		now, now,
+	}
+}
+
+func BenchmarkScanEpisodes(b *testing.B) {
+	s := &EpisodeStore{}
+	row := benchEpisodeRow()
+	for _, n := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				episodes, err := s.scanEpisodes(&benchRows{row: row, n: n})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(episodes) != n {
+					b.Fatalf("expected %d episodes, got %d", n, len(episodes))
+				}
+			}
+		})
+	}
+}
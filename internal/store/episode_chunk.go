@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	pgvector "github.com/pgvector/pgvector-go"
+)
+
+// CreateChunks stores an episode's passage embeddings in one transaction.
+// Re-chunking an episode overwrites chunks with the same index.
+func (s *EpisodeStore) CreateChunks(ctx context.Context, chunks []domain.EpisodeChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	return WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		for _, c := range chunks {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO episode_chunks (episode_id, chunk_index, agent_id, tenant_id, content, embedding)
+				 VALUES ($1, $2, $3, $4, $5, $6)
+				 ON CONFLICT (episode_id, chunk_index) DO UPDATE
+				    SET content = EXCLUDED.content, embedding = EXCLUDED.embedding`,
+				c.EpisodeID, c.ChunkIndex, c.AgentID, c.TenantID, c.Content, pgvector.NewVector(c.Embedding),
+			); err != nil {
+				return fmt.Errorf("insert episode chunk %d: %w", c.ChunkIndex, err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindSimilarChunks returns the agent's chunks scoring at least threshold
+// against embedding, best first.
+func (s *EpisodeStore) FindSimilarChunks(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, limit int) ([]domain.EpisodeChunkMatch, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT episode_id, chunk_index, content, 1 - (embedding <=> $1) AS score
+		 FROM episode_chunks
+		 WHERE agent_id = $2 AND tenant_id = $3 AND 1 - (embedding <=> $1) >= $4
+		 ORDER BY embedding <=> $1
+		 LIMIT $5`,
+		pgvector.NewVector(embedding), agentID, tenantID, threshold, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find similar episode chunks query: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []domain.EpisodeChunkMatch
+	for rows.Next() {
+		var m domain.EpisodeChunkMatch
+		if err := rows.Scan(&m.EpisodeID, &m.ChunkIndex, &m.Content, &m.Score); err != nil {
+			return nil, fmt.Errorf("scan episode chunk row: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+var _ domain.EpisodeChunkStore = (*EpisodeStore)(nil)
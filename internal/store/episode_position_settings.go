@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type EpisodePositionSettingsStore struct {
+	db DB
+}
+
+func NewEpisodePositionSettingsStore(db DB) *EpisodePositionSettingsStore {
+	return &EpisodePositionSettingsStore{db: db}
+}
+
+// Get returns the agent's stored settings, or ErrNotFound if it has none.
+func (s *EpisodePositionSettingsStore) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.EpisodePositionSettings, error) {
+	ps := &domain.EpisodePositionSettings{}
+	err := s.db.QueryRow(ctx,
+		`SELECT agent_id, tenant_id, primacy_weight, recency_weight, updated_at
+		 FROM episode_position_settings WHERE agent_id = $1 AND tenant_id = $2`,
+		agentID, tenantID,
+	).Scan(&ps.AgentID, &ps.TenantID, &ps.PrimacyWeight, &ps.RecencyWeight, &ps.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return ps, err
+}
+
+// Upsert stores the agent's settings. Returns ErrNotFound if the agent does
+// not belong to the tenant.
+func (s *EpisodePositionSettingsStore) Upsert(ctx context.Context, ps *domain.EpisodePositionSettings) error {
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO episode_position_settings (agent_id, tenant_id, primacy_weight, recency_weight)
+		 SELECT id, tenant_id, $3, $4 FROM agents WHERE id = $1 AND tenant_id = $2
+		 ON CONFLICT (agent_id) DO UPDATE SET
+			primacy_weight = EXCLUDED.primacy_weight,
+			recency_weight = EXCLUDED.recency_weight,
+			updated_at = NOW()
+		 RETURNING updated_at`,
+		ps.AgentID, ps.TenantID, ps.PrimacyWeight, ps.RecencyWeight,
+	).Scan(&ps.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CountEpisodeSlots counts the agent's episodes since the given time by
+// subject (topic, mood, or all episodes) and time slot, grouping in SQL so no
+// episode row leaves the database. Episodes stored without a time of day or
+// day of week get them from occurred_at, with the same bands as the episode
+// service.
+func (s *EpisodeStore) CountEpisodeSlots(ctx context.Context, agentID, tenantID uuid.UUID, since time.Time, valence float64, recent int) ([]domain.EpisodeSlotCount, error) {
+	rows, err := s.db.Query(ctx,
+		`WITH ep AS (
+			SELECT id, occurred_at, topics, emotional_valence,
+			       COALESCE(NULLIF(time_of_day, ''), CASE
+			           WHEN EXTRACT(HOUR FROM occurred_at) >= 5 AND EXTRACT(HOUR FROM occurred_at) < 12 THEN 'morning'
+			           WHEN EXTRACT(HOUR FROM occurred_at) >= 12 AND EXTRACT(HOUR FROM occurred_at) < 17 THEN 'afternoon'
+			           WHEN EXTRACT(HOUR FROM occurred_at) >= 17 AND EXTRACT(HOUR FROM occurred_at) < 21 THEN 'evening'
+			           ELSE 'night' END) AS tod,
+			       COALESCE(NULLIF(day_of_week, ''), to_char(occurred_at, 'FMDay')) AS dow
+			FROM episodes
+			WHERE agent_id = $1 AND tenant_id = $2 AND occurred_at >= $3
+		), subject AS (
+			SELECT DISTINCT ep.id, ep.occurred_at, ep.tod, ep.dow, 'topic' AS kind, lower(btrim(t.topic)) AS name
+			FROM ep, jsonb_array_elements_text(CASE WHEN jsonb_typeof(ep.topics) = 'array' THEN ep.topics ELSE '[]'::jsonb END) AS t(topic)
+			WHERE btrim(t.topic) <> ''
+			UNION ALL
+			SELECT id, occurred_at, tod, dow, 'mood',
+			       CASE WHEN emotional_valence < 0 THEN 'negative mood' ELSE 'positive mood' END
+			FROM ep WHERE abs(emotional_valence) >= $4
+			UNION ALL
+			SELECT id, occurred_at, tod, dow, '', '' FROM ep
+		), slot AS (
+			SELECT kind, name, 'time_of_day' AS slot, tod AS value, id, occurred_at FROM subject
+			UNION ALL
+			SELECT kind, name, 'day_of_week', dow, id, occurred_at FROM subject
+		)
+		SELECT kind, name, slot, value, count(*)::int,
+		       (array_agg(id ORDER BY occurred_at DESC))[1:$5]
+		FROM slot
+		GROUP BY kind, name, slot, value`,
+		agentID, tenantID, since, valence, recent,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.EpisodeSlotCount
+	for rows.Next() {
+		var c domain.EpisodeSlotCount
+		if err := rows.Scan(&c.SubjectKind, &c.Subject, &c.Slot, &c.Value, &c.Count, &c.Recent); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+var _ domain.EpisodeSlotCounter = (*EpisodeStore)(nil)
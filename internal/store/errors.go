@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrNotFound = errors.New("not found")
 	ErrConflict = errors.New("conflict")
+	ErrCycle    = errors.New("cycle")
 )
@@ -5,14 +5,13 @@ import (
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type FeedbackStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewFeedbackStore(db *pgxpool.Pool) *FeedbackStore {
+func NewFeedbackStore(db DB) *FeedbackStore {
 	return &FeedbackStore{db: db}
 }
 
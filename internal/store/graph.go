@@ -8,14 +8,13 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type GraphStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewGraphStore(db *pgxpool.Pool) *GraphStore {
+func NewGraphStore(db DB) *GraphStore {
 	return &GraphStore{db: db}
 }
 
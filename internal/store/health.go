@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type HealthStore struct {
+	db DB
+}
+
+func NewHealthStore(db DB) *HealthStore {
+	return &HealthStore{db: db}
+}
+
+// AgentHealthCounts counts the agent's raw episodes, procedures and schemas in
+// one round trip.
+func (s *HealthStore) AgentHealthCounts(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.AgentHealthCounts, error) {
+	var c domain.AgentHealthCounts
+	err := s.db.QueryRow(ctx,
+		`SELECT e.n, e.oldest,
+		        (SELECT count(*) FROM procedures WHERE agent_id = $1 AND tenant_id = $2),
+		        (SELECT count(*) FROM schemas WHERE agent_id = $1 AND tenant_id = $2)
+		 FROM (SELECT count(*) AS n, min(created_at) AS oldest
+		       FROM episodes WHERE agent_id = $1 AND consolidation_status = 'raw') e`,
+		agentID, tenantID,
+	).Scan(&c.UnconsolidatedEpisodes, &c.OldestUnconsolidated, &c.Procedures, &c.Schemas)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyStore struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyStore(db *pgxpool.Pool) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// Reserve inserts a pending row for the key, taking over an expired one. If a
+// live row already exists it is returned instead.
+func (s *IdempotencyStore) Reserve(ctx context.Context, tenantID uuid.UUID, key, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	tag, err := s.db.Exec(ctx,
+		`INSERT INTO idempotency_keys (tenant_id, key, request_hash, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (tenant_id, key) DO UPDATE SET
+		     request_hash = EXCLUDED.request_hash,
+		     status_code = NULL,
+		     content_type = NULL,
+		     response_body = NULL,
+		     created_at = NOW(),
+		     expires_at = EXCLUDED.expires_at
+		 WHERE idempotency_keys.expires_at < NOW()`,
+		tenantID, key, requestHash, time.Now().Add(ttl),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, true, nil
+	}
+
+	r := &domain.IdempotencyRecord{TenantID: tenantID, Key: key}
+	var status *int
+	var contentType *string
+	err = s.db.QueryRow(ctx,
+		`SELECT request_hash, status_code, content_type, response_body, created_at, expires_at
+		 FROM idempotency_keys WHERE tenant_id = $1 AND key = $2`,
+		tenantID, key,
+	).Scan(&r.RequestHash, &status, &contentType, &r.ResponseBody, &r.CreatedAt, &r.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Released between the insert attempt and the read; let the
+			// caller retry rather than guess.
+			return nil, false, ErrConflict
+		}
+		return nil, false, err
+	}
+	if status != nil {
+		r.StatusCode = *status
+	}
+	if contentType != nil {
+		r.ContentType = *contentType
+	}
+	return r, false, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, tenantID uuid.UUID, key string, statusCode int, contentType string, body []byte) error {
+	_, err := s.db.Exec(ctx,
+		`UPDATE idempotency_keys SET status_code = $3, content_type = $4, response_body = $5
+		 WHERE tenant_id = $1 AND key = $2`,
+		tenantID, key, statusCode, contentType, body,
+	)
+	return err
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, tenantID uuid.UUID, key string) error {
+	_, err := s.db.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE tenant_id = $1 AND key = $2 AND status_code IS NULL`,
+		tenantID, key,
+	)
+	return err
+}
+
+func (s *IdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -8,15 +8,14 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type MutationLogStore struct {
 	db   DBTX
-	pool *pgxpool.Pool
+	pool DB
 }
 
-func NewMutationLogStore(db *pgxpool.Pool) *MutationLogStore {
+func NewMutationLogStore(db DB) *MutationLogStore {
 	return &MutationLogStore{db: db, pool: db}
 }
 
@@ -30,13 +29,28 @@ func (s *MutationLogStore) Create(ctx context.Context, m *domain.MutationLog) er
 }
 
 // insertMutationLog writes one audit row against any DBTX (pool or tx), so
-// deletion/archive paths can log atomically inside their own transaction.
+// deletion/archive paths can log atomically inside their own transaction. The
+// same statement enqueues the matching memory.<mutation_type> outbox event, so
+// the event commits exactly when the audited change does.
 func insertMutationLog(ctx context.Context, db DBTX, m *domain.MutationLog) error {
 	return db.QueryRow(ctx,
-		`INSERT INTO mutation_log (memory_id, agent_id, mutation_type, source_type, source_id, old_confidence, new_confidence, old_reinforcement_count, new_reinforcement_count, reason, metadata, tenant_id, anchor_id, binding, content_hash, content_snapshot, actor_type, actor_id)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
-		 RETURNING id, created_at`,
+		`WITH ml AS (
+			INSERT INTO mutation_log (memory_id, agent_id, mutation_type, source_type, source_id, old_confidence, new_confidence, old_reinforcement_count, new_reinforcement_count, reason, metadata, tenant_id, anchor_id, binding, content_hash, content_snapshot, actor_type, actor_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			RETURNING id, memory_id, agent_id, tenant_id, mutation_type, source_type, old_confidence, new_confidence, reason, created_at
+		), ev AS (
+			INSERT INTO event_outbox (tenant_id, agent_id, aggregate_id, event_type, payload, created_at)
+			SELECT tenant_id, agent_id, memory_id, $19::text || mutation_type,
+			       jsonb_build_object('mutation_id', id, 'memory_id', memory_id, 'agent_id', agent_id,
+			                          'mutation_type', mutation_type, 'source_type', source_type,
+			                          'old_confidence', old_confidence, 'new_confidence', new_confidence,
+			                          'reason', reason),
+			       created_at
+			  FROM ml
+		)
+		SELECT id, created_at FROM ml`,
 		m.MemoryID, m.AgentID, m.MutationType, m.SourceType, m.SourceID, m.OldConfidence, m.NewConfidence, m.OldReinforcementCount, m.NewReinforcementCount, m.Reason, m.Metadata, m.TenantID, m.AnchorID, nullIfEmpty(m.Binding), nullIfEmpty(m.ContentHash), m.ContentSnapshot, nullIfEmpty(m.ActorType), m.ActorID,
+		domain.EventMemoryMutationPrefix,
 	).Scan(&m.ID, &m.CreatedAt)
 }
 
@@ -233,10 +247,10 @@ func (s *MutationLogStore) ExportByTenant(ctx context.Context, tenantID uuid.UUI
 }
 
 type EpisodeMemoryUsageStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewEpisodeMemoryUsageStore(db *pgxpool.Pool) *EpisodeMemoryUsageStore {
+func NewEpisodeMemoryUsageStore(db DB) *EpisodeMemoryUsageStore {
 	return &EpisodeMemoryUsageStore{db: db}
 }
 
@@ -297,10 +311,10 @@ func (s *EpisodeMemoryUsageStore) GetByMemoryID(ctx context.Context, memoryID uu
 }
 
 type LearningStatsStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewLearningStatsStore(db *pgxpool.Pool) *LearningStatsStore {
+func NewLearningStatsStore(db DB) *LearningStatsStore {
 	return &LearningStatsStore{db: db}
 }
 
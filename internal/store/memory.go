@@ -10,22 +10,93 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	pgvector "github.com/pgvector/pgvector-go"
+	"go.uber.org/zap"
 )
 
 type MemoryStore struct {
 	db   DBTX
-	pool *pgxpool.Pool
+	pool DB
+	// vectors, when set, serves similarity search; see SetVectorStore.
+	vectors domain.VectorStore
+	logger  *zap.Logger
+	// listener, when set, is told of every write; see SetChangeListener.
+	listener domain.MemoryChangeListener
+	// quantization, when set, shortlists recall on a quantized embedding
+	// copy; see SetQuantization.
+	quantization domain.VectorQuantization
+	// embeddingModel, when set, files each embedding in the embeddings table;
+	// see SetEmbeddingDedup.
+	embeddingModel string
 }
 
-func NewMemoryStore(db *pgxpool.Pool) *MemoryStore {
+func NewMemoryStore(db DB) *MemoryStore {
 	return &MemoryStore{db: db, pool: db}
 }
 
 // withTx returns a clone of the store that runs against the given transaction.
 func (s *MemoryStore) withTx(tx pgx.Tx) *MemoryStore {
-	return &MemoryStore{db: tx, pool: s.pool}
+	return &MemoryStore{db: tx, pool: s.pool, vectors: s.vectors, logger: s.logger, listener: s.listener, quantization: s.quantization, embeddingModel: s.embeddingModel}
+}
+
+// SetChangeListener tells l of every write to memories made through the
+// store, so caches of recall results can drop what a write made stale.
+func (s *MemoryStore) SetChangeListener(l domain.MemoryChangeListener) {
+	s.listener = l
+}
+
+// SetEmbeddingDedup files every embedding written through the store in the
+// embeddings table under model, keyed by the hash of the memory's content,
+// for an EmbeddingStore of the same model to serve again. Under quantization
+// the memory then references that vector instead of keeping a copy: its
+// quantized copy carries the index, and the rerank reads the shared vector.
+func (s *MemoryStore) SetEmbeddingDedup(model string) {
+	s.embeddingModel = model
+}
+
+// sharesEmbedding reports whether memories with an embedding_hash leave
+// their own embedding column NULL. Without a quantized copy the column has
+// to stay, as the full-precision index is built on it.
+func (s *MemoryStore) sharesEmbedding() bool {
+	return s.embeddingModel != "" && s.quantization != domain.QuantizationNone
+}
+
+// embeddingExpr is the SQL for the full-precision vector of the memory
+// aliased alias. It is the bare column unless vectors are shared, so that
+// distance sorts on it still use idx_memories_embedding.
+func (s *MemoryStore) embeddingExpr(alias string) string {
+	if s.sharesEmbedding() {
+		return storedEmbedding(alias)
+	}
+	if alias != "" {
+		return alias + ".embedding"
+	}
+	return "embedding"
+}
+
+// embeddingPresent is the SQL condition that the memory aliased alias has
+// a vector, matching embeddingExpr.
+func (s *MemoryStore) embeddingPresent(alias string) string {
+	if s.sharesEmbedding() {
+		return hasEmbedding(alias)
+	}
+	return s.embeddingExpr(alias) + " IS NOT NULL"
+}
+
+// embeddingHash is the embeddings table key of content embedded as vec, or
+// nil when there is no vector or dedup is off.
+func (s *MemoryStore) embeddingHash(content string, vec []float32) *string {
+	if s.embeddingModel == "" || len(vec) == 0 {
+		return nil
+	}
+	h := domain.HashContent(content)
+	return &h
+}
+
+func (s *MemoryStore) changed(c domain.MemoryChange) {
+	if s.listener != nil {
+		s.listener.MemoryChanged(c)
+	}
 }
 
 func (s *MemoryStore) Create(ctx context.Context, m *domain.Memory) error {
@@ -71,25 +142,66 @@ func (s *MemoryStore) Create(ctx context.Context, m *domain.Memory) error {
 		m.DecayRate = domain.DefaultDecayRate(m.Binding)
 	}
 
+	// New memories start in their confidence band; the tier transition worker
+	// applies the access and age rules from there.
+	if m.Tier == "" {
+		m.Tier = domain.ComputeTier(float64(m.Confidence))
+	}
+
 	var quarantineReason *string
 	if m.QuarantineReason != "" {
 		quarantineReason = &m.QuarantineReason
 	}
-	return s.db.QueryRow(ctx,
-		`INSERT INTO memories (agent_id, tenant_id, type, content, embedding, embedding_provider, embedding_model, source, provenance, confidence, metadata, event_date, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, binding, anchor_id, session_id, quarantine_reason, quarantined_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $14, NOW(), $12, $13, NOW(), 0, $15, $16, $17, $18, $19)
-		 RETURNING id, created_at, updated_at, last_verified_at, last_accessed_at`,
-		m.AgentID, m.TenantID, m.Type, m.Content, embedding, m.EmbeddingProvider, m.EmbeddingModel, m.Source, m.Provenance, m.Confidence, m.Metadata, m.ReinforcementCount, m.DecayRate, m.EventDate, m.Binding, m.AnchorID, m.SessionID, quarantineReason, m.QuarantinedAt,
-	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt, &m.LastVerifiedAt, &m.LastAccessedAt)
+	var subject *string
+	if m.Subject != "" {
+		subject = &m.Subject
+	}
+	evidenceFor, evidenceAgainst := m.Evidence()
+	// The memory.created outbox event is written by the same statement so it
+	// commits exactly when the memory does.
+	embeddingHash := s.embeddingHash(m.Content, m.Embedding)
+	rowEmbedding := embedding
+	if embeddingHash != nil && s.sharesEmbedding() {
+		rowEmbedding = nil
+	}
+	qCol, qVal := s.quantizedInsert("$5::vector")
+	if err := s.db.QueryRow(ctx, fmt.Sprintf(
+		`WITH m AS (
+			INSERT INTO memories (agent_id, tenant_id, type, content, embedding, embedding_provider, embedding_model, source, provenance, confidence, metadata, event_date, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, binding, anchor_id, session_id, quarantine_reason, quarantined_at, tier, pinned, tier_changed_at, subject, subject_id, evidence_for, evidence_against, topic, embedding_hash, embedding_dedup_model%s)
+			VALUES ($1, $2, $3, $4, $30, $6, $7, $8, $9, $10, $11, $14, NOW(), $12, $13, NOW(), 0, $15, $16, $17, $18, $19, $20, $21, NOW(), $23, $24, $25, $26, $27, $28, CASE WHEN $28::text IS NOT NULL THEN $29::text END%s)
+			RETURNING id, agent_id, tenant_id, type, source, provenance, confidence, binding, anchor_id, session_id, created_at, updated_at, last_verified_at, last_accessed_at, embedding_hash
+		), emb AS (
+			INSERT INTO embeddings (tenant_id, model, content_hash, embedding)
+			SELECT tenant_id, $29, embedding_hash, $5::vector FROM m WHERE embedding_hash IS NOT NULL
+			ON CONFLICT DO NOTHING
+		), ev AS (
+			INSERT INTO event_outbox (tenant_id, agent_id, aggregate_id, event_type, payload, created_at)
+			SELECT tenant_id, agent_id, id, $22,
+			       jsonb_build_object('memory_id', id, 'agent_id', agent_id, 'type', type, 'source', source,
+			                          'provenance', provenance, 'confidence', confidence, 'binding', binding,
+			                          'anchor_id', anchor_id, 'session_id', session_id),
+			       created_at
+			  FROM m
+		)
+		SELECT id, created_at, updated_at, last_verified_at, last_accessed_at FROM m`, qCol, qVal),
+		m.AgentID, m.TenantID, m.Type, m.Content, embedding, m.EmbeddingProvider, m.EmbeddingModel, m.Source, m.Provenance, m.Confidence, m.Metadata, m.ReinforcementCount, m.DecayRate, m.EventDate, m.Binding, m.AnchorID, m.SessionID, quarantineReason, m.QuarantinedAt, m.Tier, m.Pinned,
+		domain.EventMemoryCreated, subject, m.SubjectID, evidenceFor, evidenceAgainst, m.Topic, embeddingHash, s.embeddingModel, rowEmbedding,
+	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt, &m.LastVerifiedAt, &m.LastAccessedAt); err != nil {
+		return err
+	}
+	m.EvidenceFor, m.EvidenceAgainst = evidenceFor, evidenceAgainst
+	s.indexVector(ctx, domain.VectorPoint{ID: m.ID, TenantID: m.TenantID, AgentID: m.AgentID, Type: m.Type, Embedding: m.Embedding})
+	s.changed(domain.MemoryChange{TenantID: m.TenantID, AgentID: m.AgentID})
+	return nil
 }
 
 func (s *MemoryStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.Memory, error) {
 	m := &domain.Memory{}
 	err := s.db.QueryRow(ctx,
-		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id
+		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, tier, pinned, COALESCE(subject, ''), subject_id, evidence_for, evidence_against, topic
 		 FROM memories WHERE id = $1 AND tenant_id = $2 AND is_archived = FALSE`,
 		id, tenantID,
-	).Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt, &m.Binding, &m.AnchorID, &m.SessionID)
+	).Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt, &m.Binding, &m.AnchorID, &m.SessionID, &m.Tier, &m.Pinned, &m.Subject, &m.SubjectID, &m.EvidenceFor, &m.EvidenceAgainst, &m.Topic)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -172,7 +284,7 @@ func snapshotMemoriesForRemoval(ctx context.Context, tx pgx.Tx, mutationType dom
 }
 
 func (s *MemoryStore) Delete(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) error {
-	return WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+	err := WithTx(ctx, s.pool, func(tx pgx.Tx) error {
 		if err := snapshotMemoriesForRemoval(ctx, tx, domain.MutationDeletion, "deletion: api delete", true,
 			"id = $1 AND tenant_id = $2", id, tenantID); err != nil {
 			return err
@@ -186,6 +298,12 @@ func (s *MemoryStore) Delete(ctx context.Context, id uuid.UUID, tenantID uuid.UU
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	s.unindexVectors(ctx, id)
+	s.changed(domain.MemoryChange{MemoryID: id})
+	return nil
 }
 
 func (s *MemoryStore) ListByAnchor(ctx context.Context, anchorID, tenantID uuid.UUID, limit int) ([]domain.Memory, error) {
@@ -278,6 +396,9 @@ func (s *MemoryStore) PurgeByAnchor(ctx context.Context, anchorID, tenantID uuid
 		}
 		return nil
 	})
+	if err == nil && affected > 0 {
+		s.changed(domain.MemoryChange{TenantID: tenantID})
+	}
 	return affected, err
 }
 
@@ -439,6 +560,9 @@ func (s *MemoryStore) ArchiveExpiredSessionMemories(ctx context.Context) (int64,
 		affected = tag.RowsAffected()
 		return nil
 	})
+	if err == nil && affected > 0 {
+		s.changed(domain.MemoryChange{})
+	}
 	return affected, err
 }
 
@@ -452,7 +576,12 @@ func (s *MemoryStore) PromoteSessionToAnchor(ctx context.Context, id uuid.UUID)
 	if err != nil {
 		return false, err
 	}
-	return tag.RowsAffected() > 0, nil
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	// The memory now surfaces outside its session, for an agent not at hand
+	s.changed(domain.MemoryChange{})
+	return true, nil
 }
 
 func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID uuid.UUID, tenantID uuid.UUID, opts domain.RecallOpts) ([]domain.MemoryWithScore, error) {
@@ -497,7 +626,7 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 		conditions = append(conditions, "session_id IS NULL")
 	}
 
-	conditions = append(conditions, "embedding IS NOT NULL")
+	conditions = append(conditions, s.embeddingPresent(""))
 	conditions = append(conditions, "is_archived = FALSE")
 	// Provenance Firewall: quarantined (untrusted) traces never surface in recall.
 	conditions = append(conditions, "binding <> 'quarantine'")
@@ -512,6 +641,11 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 		args = append(args, opts.MinConfidence)
 	}
 
+	if opts.Subject != "" {
+		conditions = append(conditions, fmt.Sprintf("LOWER(subject) = LOWER($%d)", len(args)+1))
+		args = append(args, opts.Subject)
+	}
+
 	if opts.EventDateFrom != nil {
 		conditions = append(conditions, fmt.Sprintf("COALESCE(event_date, created_at) >= $%d", len(args)+1))
 		args = append(args, *opts.EventDateFrom)
@@ -521,10 +655,36 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 		args = append(args, *opts.EventDateTo)
 	}
 
+	// An external vector store narrows the scan to its nearest candidates; the
+	// filters and scoring below then run on those rows alone.
+	var types []domain.MemoryType
+	if opts.MemoryType != nil {
+		types = []domain.MemoryType{*opts.MemoryType}
+	}
+	ids, external := s.vectorCandidates(ctx, domain.VectorQuery{
+		TenantID: tenantID, AgentID: agentID, Embedding: embedding, Types: types, Limit: opts.TopK,
+	})
+	if external {
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d)", len(args)+1))
+		args = append(args, ids)
+	}
+
 	// Add the embedding parameter
 	embeddingParam := len(args) + 1
 	args = append(args, vec)
 
+	// Otherwise a quantized index shortlists the candidates, which the
+	// full-precision distance below reranks.
+	if !external {
+		if shortlist := s.quantizedShortlist(strings.Join(conditions, " AND "), embeddingParam, len(args)+1); shortlist != "" {
+			conditions = append(conditions, shortlist)
+			args = append(args, quantizedCandidates(opts.TopK))
+		}
+	}
+
 	// Add the limit parameter
 	limitParam := len(args) + 1
 	args = append(args, opts.TopK)
@@ -538,7 +698,8 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 			   SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model,
 			          source, provenance, confidence, metadata, event_date, last_verified_at, reinforcement_count,
 			          decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id,
-			          (embedding <=> $%d) AS vec_dist,
+			          subject, subject_id, tier, pinned,
+			          (`+s.embeddingExpr("")+` <=> $%d) AS vec_dist,
 			          COALESCE(
 			            EXTRACT(EPOCH FROM (COALESCE(event_date, created_at)
 			              - MIN(COALESCE(event_date, created_at)) OVER (PARTITION BY agent_id))) /
@@ -554,6 +715,7 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 			 SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model,
 			        source, provenance, confidence, metadata, event_date, last_verified_at, reinforcement_count,
 			        decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id,
+			        COALESCE(subject, ''), subject_id, tier, pinned,
 			        (1 - vec_dist) + $%d * relative_recency AS score
 			 FROM ranked
 			 ORDER BY vec_dist - $%d * relative_recency ASC
@@ -565,11 +727,11 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 		)
 	} else {
 		query = fmt.Sprintf(
-			`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, event_date, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id,
-			        1 - (embedding <=> $%d) AS score
+			`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, event_date, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, COALESCE(subject, ''), subject_id, tier, pinned,
+			        1 - (`+s.embeddingExpr("")+` <=> $%d) AS score
 			 FROM memories
 			 WHERE %s
-			 ORDER BY embedding <=> $%d ASC
+			 ORDER BY `+s.embeddingExpr("")+` <=> $%d ASC
 			 LIMIT $%d`,
 			embeddingParam,
 			strings.Join(conditions, " AND "),
@@ -578,36 +740,64 @@ func (s *MemoryStore) Recall(ctx context.Context, embedding []float32, agentID u
 		)
 	}
 
-	rows, err := s.db.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("recall query: %w", err)
-	}
-	defer rows.Close()
-
 	var results []domain.MemoryWithScore
-	for rows.Next() {
-		var ms domain.MemoryWithScore
-		err := rows.Scan(
-			&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
-			&ms.EmbeddingProvider, &ms.EmbeddingModel,
-			&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.EventDate,
-			&ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate, &ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
-			&ms.Binding, &ms.AnchorID, &ms.SessionID,
-			&ms.Score,
-		)
+	err := s.withSearchEffort(ctx, domain.RecallSearchEffort(opts.Accuracy), func(db DBTX) error {
+		rows, err := db.Query(ctx, query, args...)
 		if err != nil {
-			return nil, fmt.Errorf("scan recall row: %w", err)
+			return fmt.Errorf("recall query: %w", err)
 		}
-		results = append(results, ms)
-	}
+		defer rows.Close()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("recall rows: %w", err)
-	}
+		for rows.Next() {
+			var ms domain.MemoryWithScore
+			err := rows.Scan(
+				&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
+				&ms.EmbeddingProvider, &ms.EmbeddingModel,
+				&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.EventDate,
+				&ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate, &ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
+				&ms.Binding, &ms.AnchorID, &ms.SessionID, &ms.Subject, &ms.SubjectID, &ms.Tier, &ms.Pinned,
+				&ms.Score,
+			)
+			if err != nil {
+				return fmt.Errorf("scan recall row: %w", err)
+			}
+			results = append(results, ms)
+		}
 
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("recall rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
+// withSearchEffort runs fn with the vector index scanned at effort, set with
+// SET LOCAL in a transaction of its own so it also holds behind PgBouncer. A
+// zero effort, or a store bound to the caller's transaction, runs fn on the
+// store's connection with the session's settings.
+func (s *MemoryStore) withSearchEffort(ctx context.Context, effort domain.SearchEffort, fn func(db DBTX) error) error {
+	if _, inTx := s.db.(pgx.Tx); effort.IsZero() || inTx {
+		return fn(s.db)
+	}
+	return WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		if effort.EfSearch > 0 {
+			if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", effort.EfSearch)); err != nil {
+				return err
+			}
+		}
+		if effort.Probes > 0 {
+			if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL ivfflat.probes = %d", effort.Probes)); err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
 func (s *MemoryStore) RecallExhaustive(ctx context.Context, queryEmbedding []float32, agentID uuid.UUID, tenantID uuid.UUID, opts domain.RecallOpts) ([]domain.MemoryWithScore, error) {
 	minSim := opts.MinSimilarity
 	if minSim <= 0 {
@@ -631,6 +821,10 @@ func (s *MemoryStore) RecallExhaustive(ctx context.Context, queryEmbedding []flo
 		anchorClause = "AND anchor_id = $5"
 		anchorArg = []any{*opts.AnchorID}
 	}
+	if opts.Subject != "" {
+		anchorArg = append(anchorArg, opts.Subject)
+		anchorClause += fmt.Sprintf(" AND LOWER(subject) = LOWER($%d)", 4+len(anchorArg))
+	}
 
 	for {
 		queryArgs := append([]any{agentID, tenantID, pageSize, offset}, anchorArg...)
@@ -638,9 +832,9 @@ func (s *MemoryStore) RecallExhaustive(ctx context.Context, queryEmbedding []flo
 			fmt.Sprintf(`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model,
 			        source, provenance, confidence, metadata, event_date, last_verified_at,
 			        reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at,
-			        embedding
+			        `+s.embeddingExpr("")+`
 			 FROM memories
-			 WHERE agent_id = $1 AND tenant_id = $2 AND embedding IS NOT NULL AND is_archived = FALSE AND binding <> 'quarantine' %s
+			 WHERE agent_id = $1 AND tenant_id = $2 AND `+s.embeddingPresent("")+` AND is_archived = FALSE AND binding <> 'quarantine' %s
 			 ORDER BY created_at
 			 LIMIT $3 OFFSET $4`, anchorClause),
 			queryArgs...,
@@ -693,6 +887,10 @@ func (s *MemoryStore) RecallExhaustive(ctx context.Context, queryEmbedding []flo
 	return allResults, nil
 }
 
+// hybridVectorCandidates is how many nearest memories RecallHybrid fuses
+// with the full-text matches, at the default accuracy.
+const hybridVectorCandidates = 100
+
 func (s *MemoryStore) RecallHybrid(ctx context.Context, query string, queryEmbedding []float32, agentID uuid.UUID, tenantID uuid.UUID, opts domain.RecallOpts) ([]domain.MemoryWithScore, error) {
 	topK := opts.TopK
 	if topK <= 0 {
@@ -703,7 +901,7 @@ func (s *MemoryStore) RecallHybrid(ctx context.Context, query string, queryEmbed
 
 	var typeCondition string
 	var args []any
-	args = append(args, agentID, tenantID, query, vec, topK)
+	args = append(args, agentID, tenantID, query, vec, topK, domain.RecallCandidates(hybridVectorCandidates, opts.Accuracy))
 
 	if opts.MemoryType != nil {
 		args = append(args, string(*opts.MemoryType))
@@ -717,6 +915,18 @@ func (s *MemoryStore) RecallHybrid(ctx context.Context, query string, queryEmbed
 		args = append(args, *opts.AnchorID)
 		anchorCondition = fmt.Sprintf("AND anchor_id = $%d", len(args))
 	}
+	if opts.Subject != "" {
+		args = append(args, opts.Subject)
+		anchorCondition += fmt.Sprintf(" AND LOWER(subject) = LOWER($%d)", len(args))
+	}
+
+	// A quantized index shortlists the vector candidates, reranked below by
+	// their full-precision distance.
+	var shortlist string
+	if cond := s.quantizedShortlist("agent_id = $1 AND tenant_id = $2 AND is_archived = FALSE "+typeCondition+" "+anchorCondition, 4, len(args)+1); cond != "" {
+		args = append(args, quantizedCandidates(domain.RecallCandidates(hybridVectorCandidates, opts.Accuracy)))
+		shortlist = "AND " + cond
+	}
 
 	hybridQuery := fmt.Sprintf(`
 		WITH bm25_ranked AS (
@@ -729,11 +939,11 @@ func (s *MemoryStore) RecallHybrid(ctx context.Context, query string, queryEmbed
 		),
 		vec_ranked AS (
 		  SELECT id,
-		         1 - (embedding <=> $4) AS vec_score,
-		         ROW_NUMBER() OVER (ORDER BY embedding <=> $4 ASC) AS vec_rank
+		         1 - (`+s.embeddingExpr("")+` <=> $4) AS vec_score,
+		         ROW_NUMBER() OVER (ORDER BY `+s.embeddingExpr("")+` <=> $4 ASC) AS vec_rank
 		  FROM memories
-		  WHERE agent_id = $1 AND tenant_id = $2 AND embedding IS NOT NULL AND is_archived = FALSE %s %s
-		  LIMIT 100
+		  WHERE agent_id = $1 AND tenant_id = $2 AND `+s.embeddingPresent("")+` AND is_archived = FALSE %s %s %s
+		  LIMIT $6
 		),
 		rrf AS (
 		  SELECT
@@ -750,31 +960,34 @@ func (s *MemoryStore) RecallHybrid(ctx context.Context, query string, queryEmbed
 		WHERE m.is_archived = FALSE AND m.binding <> 'quarantine'
 		ORDER BY r.rrf_score DESC
 		LIMIT $5
-	`, typeCondition, anchorCondition, typeCondition, anchorCondition)
-
-	rows, err := s.db.Query(ctx, hybridQuery, args...)
-	if err != nil {
-		return nil, fmt.Errorf("hybrid recall: %w", err)
-	}
-	defer rows.Close()
+	`, typeCondition, anchorCondition, typeCondition, anchorCondition, shortlist)
 
 	var results []domain.MemoryWithScore
-	for rows.Next() {
-		var ms domain.MemoryWithScore
-		err := rows.Scan(
-			&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
-			&ms.EmbeddingProvider, &ms.EmbeddingModel,
-			&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.EventDate,
-			&ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate,
-			&ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
-			&ms.Score,
-		)
+	err := s.withSearchEffort(ctx, domain.RecallSearchEffort(opts.Accuracy), func(db DBTX) error {
+		rows, err := db.Query(ctx, hybridQuery, args...)
 		if err != nil {
-			return nil, fmt.Errorf("hybrid scan: %w", err)
+			return fmt.Errorf("hybrid recall: %w", err)
 		}
-		results = append(results, ms)
-	}
-	return results, rows.Err()
+		defer rows.Close()
+
+		for rows.Next() {
+			var ms domain.MemoryWithScore
+			err := rows.Scan(
+				&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
+				&ms.EmbeddingProvider, &ms.EmbeddingModel,
+				&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.EventDate,
+				&ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate,
+				&ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
+				&ms.Score,
+			)
+			if err != nil {
+				return fmt.Errorf("hybrid scan: %w", err)
+			}
+			results = append(results, ms)
+		}
+		return rows.Err()
+	})
+	return results, err
 }
 
 func cosineSimilarity(a, b []float32) float32 {
@@ -843,6 +1056,9 @@ func (s *MemoryStore) DeleteExpired(ctx context.Context) (int64, error) {
 		affected = tag.RowsAffected()
 		return nil
 	})
+	if err == nil && affected > 0 {
+		s.changed(domain.MemoryChange{})
+	}
 	return affected, err
 }
 
@@ -861,20 +1077,31 @@ func (s *MemoryStore) DeleteByRetention(ctx context.Context, agentID uuid.UUID,
 		affected = tag.RowsAffected()
 		return nil
 	})
+	if err == nil && affected > 0 {
+		s.changed(domain.MemoryChange{AgentID: agentID})
+	}
 	return affected, err
 }
 
 func (s *MemoryStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32) ([]domain.MemoryWithScore, error) {
 	vec := pgvector.NewVector(embedding)
+	// A nil candidate list leaves the search to pgvector.
+	candidates, ok := s.vectorCandidates(ctx, domain.VectorQuery{
+		TenantID: tenantID, AgentID: agentID, Embedding: embedding, MinScore: threshold, Limit: vectorSimilarLimit / vectorOversample,
+	})
+	if ok && len(candidates) == 0 {
+		return nil, nil
+	}
 
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id,
-		        embedding::text,
-		        1 - (embedding <=> $1) AS score
+		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, COALESCE(subject, ''), subject_id,
+		        `+s.embeddingExpr("")+`::text,
+		        1 - (`+s.embeddingExpr("")+` <=> $1) AS score
 		 FROM memories
-		 WHERE agent_id = $2 AND tenant_id = $3 AND embedding IS NOT NULL AND is_archived = FALSE AND binding <> 'quarantine' AND 1 - (embedding <=> $1) >= $4
+		 WHERE agent_id = $2 AND tenant_id = $3 AND `+s.embeddingPresent("")+` AND is_archived = FALSE AND binding <> 'quarantine' AND 1 - (`+s.embeddingExpr("")+` <=> $1) >= $4
+		   AND ($5::uuid[] IS NULL OR id = ANY($5))
 		 ORDER BY score DESC`,
-		vec, agentID, tenantID, threshold,
+		vec, agentID, tenantID, threshold, candidates,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("find similar query: %w", err)
@@ -889,7 +1116,7 @@ func (s *MemoryStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenant
 			&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
 			&ms.EmbeddingProvider, &ms.EmbeddingModel,
 			&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate, &ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
-			&ms.Binding, &ms.AnchorID, &ms.SessionID,
+			&ms.Binding, &ms.AnchorID, &ms.SessionID, &ms.Subject, &ms.SubjectID,
 			&embVec,
 			&ms.Score,
 		)
@@ -907,10 +1134,88 @@ func (s *MemoryStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenant
 	return results, nil
 }
 
-func (s *MemoryStore) GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType domain.MemoryType, limit int) ([]domain.MemoryWithScore, error) {
+// FindSimilarFiltered returns the filter.Limit nearest memories of the agent
+// among the filtered rows, keeping those at or above threshold. Unlike
+// FindSimilar it is bounded: the type and recency filters are served by
+// idx_memories_belief_candidates and the LIMIT stops the distance sort early.
+func (s *MemoryStore) FindSimilarFiltered(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, embedding []float32, threshold float32, filter domain.SimilarityFilter) ([]domain.MemoryWithScore, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	types := make([]string, 0, len(filter.Types))
+	for _, t := range filter.Types {
+		types = append(types, string(t))
+	}
+	var since *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	candidates, ok := s.vectorCandidates(ctx, domain.VectorQuery{
+		TenantID: tenantID, AgentID: agentID, Embedding: embedding, Types: filter.Types, MinScore: threshold, Limit: limit,
+	})
+	if ok && len(candidates) == 0 {
+		return nil, nil
+	}
+
+	where := `agent_id = $2 AND tenant_id = $3 AND ` + s.embeddingPresent("") + ` AND is_archived = FALSE AND binding <> 'quarantine'
+		       AND (cardinality($5::text[]) = 0 OR type = ANY($5))
+		       AND ($6::timestamptz IS NULL OR COALESCE(last_verified_at, created_at) >= $6)
+		       AND ($8::uuid[] IS NULL OR id = ANY($8))`
+	args := []any{pgvector.NewVector(embedding), agentID, tenantID, threshold, types, since, limit, candidates}
+	if shortlist := s.quantizedShortlist(where, 1, len(args)+1); shortlist != "" && !ok {
+		where += " AND " + shortlist
+		args = append(args, quantizedCandidates(limit))
+	}
+
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id,
+		fmt.Sprintf(`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, COALESCE(subject, ''), subject_id,
 		        embedding::text,
+		        (1 - dist)::float4 AS score
+		 FROM (
+		     SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, subject, subject_id,
+		            %[2]s AS embedding, %[2]s <=> $1 AS dist
+		     FROM memories
+		     WHERE %[1]s
+		     ORDER BY dist
+		     LIMIT $7
+		 ) candidates
+		 WHERE 1 - dist >= $4
+		 ORDER BY dist`, where, s.embeddingExpr("")),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find similar filtered query: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.MemoryWithScore
+	for rows.Next() {
+		var ms domain.MemoryWithScore
+		var embVec pgvector.Vector
+		if err := rows.Scan(
+			&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
+			&ms.EmbeddingProvider, &ms.EmbeddingModel,
+			&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate, &ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
+			&ms.Binding, &ms.AnchorID, &ms.SessionID, &ms.Subject, &ms.SubjectID,
+			&embVec,
+			&ms.Score,
+		); err != nil {
+			return nil, fmt.Errorf("scan find similar filtered row: %w", err)
+		}
+		ms.Embedding = embVec.Slice()
+		results = append(results, ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find similar filtered rows: %w", err)
+	}
+	return results, nil
+}
+
+func (s *MemoryStore) GetRecentByType(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memType domain.MemoryType, limit int) ([]domain.MemoryWithScore, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, COALESCE(subject, ''), subject_id,
+		        `+s.embeddingExpr("")+`::text,
 		        1.0::float4 AS score
 		 FROM memories
 		 WHERE agent_id = $1 AND tenant_id = $2 AND type = $3 AND is_archived = FALSE
@@ -931,7 +1236,7 @@ func (s *MemoryStore) GetRecentByType(ctx context.Context, agentID uuid.UUID, te
 			&ms.ID, &ms.AgentID, &ms.TenantID, &ms.Type, &ms.Content,
 			&ms.EmbeddingProvider, &ms.EmbeddingModel,
 			&ms.Source, &ms.Provenance, &ms.Confidence, &ms.Metadata, &ms.LastVerifiedAt, &ms.ReinforcementCount, &ms.DecayRate, &ms.LastAccessedAt, &ms.AccessCount, &ms.CreatedAt, &ms.UpdatedAt,
-			&ms.Binding, &ms.AnchorID, &ms.SessionID,
+			&ms.Binding, &ms.AnchorID, &ms.SessionID, &ms.Subject, &ms.SubjectID,
 			&embVec,
 			&ms.Score,
 		); err != nil {
@@ -943,25 +1248,88 @@ func (s *MemoryStore) GetRecentByType(ctx context.Context, agentID uuid.UUID, te
 	return results, rows.Err()
 }
 
+// tierBand is ComputeTier's confidence band for the SQL expression conf.
+func tierBand(conf string) string {
+	return fmt.Sprintf(`CASE WHEN %[1]s > 0.85 THEN 'hot' WHEN %[1]s > 0.70 THEN 'warm' WHEN %[1]s > 0.40 THEN 'cold' ELSE 'archive' END`, conf)
+}
+
+// tierAssignments keeps the stored tier in step with a confidence write,
+// where conf is the new confidence as an SQL expression over the row before
+// the update. A memory whose confidence crosses into another band moves to
+// that band; within a band it keeps the tier the transition worker gave it
+// for access or age. Pinned memories keep theirs.
+func tierAssignments(conf string) string {
+	moved := fmt.Sprintf(`NOT pinned AND %s <> %s`, tierBand("confidence"), tierBand(conf))
+	return fmt.Sprintf(`tier = CASE WHEN %[1]s THEN %[2]s ELSE tier END,
+		     tier_changed_at = CASE WHEN %[1]s THEN NOW() ELSE tier_changed_at END`, moved, tierBand(conf))
+}
+
+// withMemoryEvent wraps an UPDATE of memories so the same statement writes
+// an outbox event of type $eventArg for every row it changes, and returns the
+// tenant and agent of each row changed. The event commits exactly when the
+// change does.
+func withMemoryEvent(update string, eventArg int) string {
+	return fmt.Sprintf(`WITH m AS (
+			%s
+			RETURNING id, agent_id, tenant_id, confidence, reinforcement_count, tier, is_archived, updated_at
+		), ev AS (
+			INSERT INTO event_outbox (tenant_id, agent_id, aggregate_id, event_type, payload, created_at)
+			SELECT tenant_id, agent_id, id, $%d::text,
+			       jsonb_build_object('memory_id', id, 'agent_id', agent_id, 'confidence', confidence,
+			                          'reinforcement_count', reinforcement_count, 'tier', tier,
+			                          'is_archived', is_archived),
+			       updated_at
+			  FROM m
+		)
+		SELECT tenant_id, agent_id FROM m`, update, eventArg)
+}
+
+// execWithEvent runs a single-row update through withMemoryEvent with an
+// event of type eventType, returning the changed memory's tenant and agent,
+// or ErrNotFound when it changed no row.
+func (s *MemoryStore) execWithEvent(ctx context.Context, eventType, update string, args ...any) (tenantID, agentID uuid.UUID, err error) {
+	err = s.db.QueryRow(ctx, withMemoryEvent(update, len(args)+1), append(args, eventType)...).Scan(&tenantID, &agentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = ErrNotFound
+	}
+	return tenantID, agentID, err
+}
+
 // UpdateReinforcement atomically updates confidence, reinforcement_count, and last_verified_at.
 func (s *MemoryStore) UpdateReinforcement(ctx context.Context, id uuid.UUID, confidence float32, reinforcementCount int) error {
-	tag, err := s.db.Exec(ctx,
-		`UPDATE memories SET confidence = $1, reinforcement_count = $2, last_verified_at = NOW(), updated_at = NOW() WHERE id = $3`,
+	tenantID, agentID, err := s.execWithEvent(ctx, domain.EventMemoryReinforced,
+		`UPDATE memories SET confidence = $1, reinforcement_count = $2, last_verified_at = NOW(), updated_at = NOW(), `+tierAssignments("$1")+` WHERE id = $3`,
 		confidence, reinforcementCount, id,
 	)
 	if err != nil {
 		return err
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
+	s.changed(domain.MemoryChange{TenantID: tenantID, AgentID: agentID})
+	return nil
+}
+
+// ReinforceBy raises confidence by boost, capped at 0.99, and increments
+// reinforcement_count in one statement, so reinforcements from concurrent
+// writers add up instead of each writing back its own snapshot plus boost.
+func (s *MemoryStore) ReinforceBy(ctx context.Context, id uuid.UUID, boost float32) error {
+	conf := "LEAST(confidence + $1, 0.99)"
+	tenantID, agentID, err := s.execWithEvent(ctx, domain.EventMemoryReinforced,
+		`UPDATE memories SET confidence = `+conf+`, reinforcement_count = reinforcement_count + 1, last_verified_at = NOW(), updated_at = NOW(), `+tierAssignments(conf)+` WHERE id = $2`,
+		boost, id,
+	)
+	if err != nil {
+		return err
 	}
+	s.changed(domain.MemoryChange{TenantID: tenantID, AgentID: agentID})
 	return nil
 }
 
-func (s *MemoryStore) UpdateConfidence(ctx context.Context, id uuid.UUID, confidence float32) error {
+// AddEvidence adds observations for and against a memory to its evidence
+// counts.
+func (s *MemoryStore) AddEvidence(ctx context.Context, id uuid.UUID, supporting, contradicting float64) error {
 	tag, err := s.db.Exec(ctx,
-		`UPDATE memories SET confidence = $1, updated_at = NOW() WHERE id = $2`,
-		confidence, id,
+		`UPDATE memories SET evidence_for = evidence_for + $2, evidence_against = evidence_against + $3 WHERE id = $1`,
+		id, supporting, contradicting,
 	)
 	if err != nil {
 		return err
@@ -969,6 +1337,23 @@ func (s *MemoryStore) UpdateConfidence(ctx context.Context, id uuid.UUID, confid
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
 	}
+	s.changed(domain.MemoryChange{MemoryID: id})
+	return nil
+}
+
+// UpdateConfidence sets a memory's confidence. A raised confidence can lift
+// the memory over a recall's confidence or tier threshold, so the change
+// covers the agent's recalls, not only those that returned it; the same goes
+// for reinforcement.
+func (s *MemoryStore) UpdateConfidence(ctx context.Context, id uuid.UUID, confidence float32) error {
+	tenantID, agentID, err := s.execWithEvent(ctx, domain.EventMemoryConfidenceChanged,
+		`UPDATE memories SET confidence = $1, updated_at = NOW(), `+tierAssignments("$1")+` WHERE id = $2`,
+		confidence, id,
+	)
+	if err != nil {
+		return err
+	}
+	s.changed(domain.MemoryChange{TenantID: tenantID, AgentID: agentID})
 	return nil
 }
 
@@ -980,7 +1365,8 @@ func (s *MemoryStore) ApplyConfidenceDelta(ctx context.Context, id uuid.UUID, de
 	tag, err := s.db.Exec(ctx,
 		`UPDATE memories
 		 SET confidence = GREATEST(0, LEAST(confidence + $2, 0.99)),
-		     updated_at = NOW()
+		     updated_at = NOW(),
+		     `+tierAssignments("GREATEST(0, LEAST(confidence + $2, 0.99))")+`
 		 WHERE id = $1`,
 		id, delta,
 	)
@@ -990,6 +1376,7 @@ func (s *MemoryStore) ApplyConfidenceDelta(ctx context.Context, id uuid.UUID, de
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
 	}
+	s.changed(domain.MemoryChange{MemoryID: id})
 	return nil
 }
 
@@ -997,28 +1384,64 @@ func (s *MemoryStore) ApplyConfidenceDelta(ctx context.Context, id uuid.UUID, de
 // embedding is provided it is updated too; otherwise the existing embedding is
 // left in place.
 func (s *MemoryStore) UpdateContent(ctx context.Context, id uuid.UUID, content string, embedding []float32) error {
-	query := `UPDATE memories SET content = $1, updated_at = NOW() WHERE id = $2`
-	args := []any{content, id}
 	if len(embedding) > 0 {
-		v := pgvector.NewVector(embedding)
-		query = `UPDATE memories SET content = $1, embedding = $2, updated_at = NOW() WHERE id = $3`
-		args = []any{content, v, id}
+		p := domain.VectorPoint{ID: id, Embedding: embedding}
+		hash := s.embeddingHash(content, embedding)
+		var rowEmbedding *pgvector.Vector
+		if hash == nil || !s.sharesEmbedding() {
+			v := pgvector.NewVector(embedding)
+			rowEmbedding = &v
+		}
+		err := s.db.QueryRow(ctx,
+			fmt.Sprintf(`WITH u AS (
+			   UPDATE memories SET content = $1, embedding = $6, embedding_hash = $4,
+			          embedding_dedup_model = CASE WHEN $4::text IS NOT NULL THEN $5::text END, %s, updated_at = NOW()
+			    WHERE id = $3
+			   RETURNING tenant_id, agent_id, type
+			 ), emb AS (
+			   INSERT INTO embeddings (tenant_id, model, content_hash, embedding)
+			   SELECT tenant_id, $5, $4, $2::vector FROM u WHERE $4::text IS NOT NULL
+			   ON CONFLICT DO NOTHING
+			 )
+			 SELECT tenant_id, agent_id, type FROM u`, s.quantizedAssignments("$2::vector")),
+			content, pgvector.NewVector(embedding), id, hash, s.embeddingModel, rowEmbedding,
+		).Scan(&p.TenantID, &p.AgentID, &p.Type)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		s.indexVector(ctx, p)
+		s.changed(domain.MemoryChange{TenantID: p.TenantID, AgentID: p.AgentID})
+		return nil
 	}
-	tag, err := s.db.Exec(ctx, query, args...)
+	// The kept embedding no longer matches the content's hash, so the memory
+	// takes back its own copy of a shared one.
+	tag, err := s.db.Exec(ctx,
+		`UPDATE memories SET content = $1, embedding = `+storedEmbedding("")+`,
+		        embedding_hash = NULL, embedding_dedup_model = NULL, updated_at = NOW()
+		 WHERE id = $2`,
+		content, id)
 	if err != nil {
 		return err
 	}
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
 	}
+	s.changed(domain.MemoryChange{MemoryID: id})
 	return nil
 }
 
 // RedactContent overwrites content with a tombstone and clears the embedding, so
 // neither the original text nor its vector remains recoverable (GDPR redaction).
+// Clearing embedding_hash also drops the shared copy in the embeddings table,
+// unless another memory or episode of the tenant holds the same text.
 func (s *MemoryStore) RedactContent(ctx context.Context, id uuid.UUID, tombstone string) error {
 	tag, err := s.db.Exec(ctx,
-		`UPDATE memories SET content = $1, embedding = NULL, updated_at = NOW() WHERE id = $2`,
+		`UPDATE memories SET content = $1, embedding = NULL, embedding_half = NULL, embedding_bit = NULL,
+		        embedding_hash = NULL, embedding_dedup_model = NULL, updated_at = NOW()
+		 WHERE id = $2`,
 		tombstone, id,
 	)
 	if err != nil {
@@ -1027,6 +1450,8 @@ func (s *MemoryStore) RedactContent(ctx context.Context, id uuid.UUID, tombstone
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
 	}
+	s.unindexVectors(ctx, id)
+	s.changed(domain.MemoryChange{MemoryID: id})
 	return nil
 }
 
@@ -1053,9 +1478,28 @@ func (s *MemoryStore) ListDistinctAgentIDs(ctx context.Context) ([]uuid.UUID, er
 // OOM the whole process. Agents above the cap are processed partially per tick.
 const decayBatchLimit = 10000
 
+// iteratePageSize is how many rows IterateForDecay holds at a time.
+const iteratePageSize = 500
+
+// decayColumns are the columns scanDecayMemory reads, the vector given by
+// embedding, the SQL of MemoryStore.embeddingExpr.
+func decayColumns(embedding string) string {
+	return `id, agent_id, tenant_id, type, content, ` + embedding + `, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, tier, pinned, evidence_for, evidence_against, topic`
+}
+
+func scanDecayMemory(rows pgx.Rows) (domain.Memory, error) {
+	var m domain.Memory
+	var emb pgvector.Vector
+	if err := rows.Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &emb, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt, &m.Tier, &m.Pinned, &m.EvidenceFor, &m.EvidenceAgainst, &m.Topic); err != nil {
+		return m, err
+	}
+	m.Embedding = emb.Slice()
+	return m, nil
+}
+
 func (s *MemoryStore) GetByAgentForDecay(ctx context.Context, agentID uuid.UUID) ([]domain.Memory, error) {
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, tenant_id, type, content, embedding, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at
+		`SELECT `+decayColumns(s.embeddingExpr(""))+`
 		 FROM memories WHERE agent_id = $1 AND is_archived = FALSE AND binding <> 'quarantine'
 		 ORDER BY last_accessed_at ASC NULLS FIRST
 		 LIMIT $2`,
@@ -1068,17 +1512,252 @@ func (s *MemoryStore) GetByAgentForDecay(ctx context.Context, agentID uuid.UUID)
 
 	var memories []domain.Memory
 	for rows.Next() {
-		var m domain.Memory
-		var emb pgvector.Vector
-		if err := rows.Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &emb, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		m, err := scanDecayMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
+
+// GetUnsummarized returns the agent's live memories among ids, with their
+// embeddings, leaving out any that a live summary already lists as a member.
+func (s *MemoryStore) GetUnsummarized(ctx context.Context, agentID uuid.UUID, ids []uuid.UUID) ([]domain.Memory, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+decayColumns(s.embeddingExpr(""))+`
+		 FROM memories
+		 WHERE agent_id = $1 AND id = ANY($2) AND is_archived = FALSE AND binding <> 'quarantine'
+		   AND NOT EXISTS (
+		       SELECT 1 FROM memories sm
+		        WHERE sm.agent_id = $1 AND sm.type = $3 AND sm.is_archived = FALSE
+		          AND sm.metadata -> $4::text ? memories.id::text)`,
+		agentID, ids, domain.MemoryTypeSummary, domain.SummaryMembersKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []domain.Memory
+	for rows.Next() {
+		m, err := scanDecayMemory(rows)
+		if err != nil {
 			return nil, err
 		}
-		m.Embedding = emb.Slice()
 		memories = append(memories, m)
 	}
 	return memories, rows.Err()
 }
 
+// IterateForDecay pages through the agent's live memories by ID (keyset
+// pagination), so no connection is held while fn runs and fn may write.
+func (s *MemoryStore) IterateForDecay(ctx context.Context, agentID uuid.UUID, fn func(*domain.Memory) error) error {
+	after := uuid.Nil
+	for {
+		page, err := s.decayPage(ctx, agentID, after)
+		if err != nil {
+			return err
+		}
+		for i := range page {
+			if err := fn(&page[i]); err != nil {
+				if errors.Is(err, domain.ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		if len(page) < iteratePageSize {
+			return nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+func (s *MemoryStore) decayPage(ctx context.Context, agentID, after uuid.UUID) ([]domain.Memory, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+decayColumns(s.embeddingExpr(""))+`
+		 FROM memories WHERE agent_id = $1 AND is_archived = FALSE AND binding <> 'quarantine' AND id > $2
+		 ORDER BY id
+		 LIMIT $3`,
+		agentID, after, iteratePageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := make([]domain.Memory, 0, iteratePageSize)
+	for rows.Next() {
+		m, err := scanDecayMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, m)
+	}
+	return page, rows.Err()
+}
+
+// AgentMemoryStats aggregates the agent's live memories in three queries: the
+// scalar figures, the type/tier breakdown, then the topic breakdown.
+func (s *MemoryStore) AgentMemoryStats(ctx context.Context, agentID uuid.UUID, q domain.MemoryStatsQuery) (*domain.AgentMemoryStats, error) {
+	var st domain.AgentMemoryStats
+	err := s.db.QueryRow(ctx,
+		`SELECT count(*),
+		        COALESCE(avg(confidence), 0)::float4,
+		        count(*) FILTER (WHERE confidence < $2),
+		        count(*) FILTER (WHERE last_accessed_at > $3),
+		        COALESCE(array_agg(DISTINCT type::text ORDER BY type::text) FILTER (WHERE confidence < $4), '{}')
+		 FROM memories WHERE agent_id = $1 AND is_archived = FALSE AND binding <> 'quarantine'`,
+		agentID, q.AtRiskBelow, q.RecentSince, q.UncertainBelow,
+	).Scan(&st.Count, &st.AverageConfidence, &st.AtRisk, &st.RecentlyReinforced, &st.UncertainTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT type::text, tier, count(*)
+		 FROM memories WHERE agent_id = $1 AND is_archived = FALSE AND binding <> 'quarantine'
+		 GROUP BY type, tier`,
+		agentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	st.ByType = make(map[string]int)
+	st.ByTier = make(map[string]int)
+	for rows.Next() {
+		var memType, tier string
+		var n int
+		if err := rows.Scan(&memType, &tier, &n); err != nil {
+			return nil, err
+		}
+		st.ByType[memType] += n
+		st.ByTier[tier] += n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Memories without a topic are reported under their type, as domain.TopicOf.
+	topicRows, err := s.db.Query(ctx,
+		`SELECT COALESCE(NULLIF(topic, ''), type::text) AS t, count(*),
+		        COALESCE(avg(confidence), 0)::float4,
+		        count(*) FILTER (WHERE confidence < $2),
+		        count(*) FILTER (WHERE confidence < $3),
+		        count(*) FILTER (WHERE created_at >= $4),
+		        count(*) FILTER (WHERE created_at >= $5 AND created_at < $4)
+		 FROM memories WHERE agent_id = $1 AND is_archived = FALSE AND binding <> 'quarantine'
+		 GROUP BY t`,
+		agentID, q.AtRiskBelow, q.UncertainBelow, q.TrendSince, q.TrendSince.Add(-q.TrendWindow),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer topicRows.Close()
+
+	var topics []domain.TopicStats
+	for topicRows.Next() {
+		var t domain.TopicStats
+		if err := topicRows.Scan(&t.Topic, &t.Count, &t.AverageConfidence, &t.AtRisk, &t.Uncertain, &t.Recent, &t.Previous); err != nil {
+			return nil, err
+		}
+		topics = append(topics, t)
+	}
+	if err := topicRows.Err(); err != nil {
+		return nil, err
+	}
+	st.ByTopic, st.UncertainTopics = domain.RankTopics(topics, q.MaxTopics)
+	return &st, nil
+}
+
+// neighborProbeWhere selects the neighbors StreamRedundantPairs and
+// CountNeighbors compare a memory a with.
+func (s *MemoryStore) neighborProbeWhere() string {
+	return `b.agent_id = a.agent_id AND b.id <> a.id AND ` + s.embeddingPresent("b") + `
+		       AND b.is_archived = FALSE AND b.binding <> 'quarantine' AND b.type <> 'summary'`
+}
+
+// StreamRedundantPairs runs one nearest-neighbor probe per memory through the
+// embedding index instead of comparing every pair, and hands pairs to fn as
+// rows arrive.
+func (s *MemoryStore) StreamRedundantPairs(ctx context.Context, agentID uuid.UUID, threshold float32, neighbors int, fn func(domain.RedundantPair) error) error {
+	rows, err := s.db.Query(ctx,
+		fmt.Sprintf(`SELECT a.id, a.confidence, a.reinforcement_count,
+		        n.id, n.confidence, n.reinforcement_count,
+		        (1 - n.dist)::float4
+		 FROM memories a
+		 CROSS JOIN LATERAL (
+		     SELECT b.id, b.confidence, b.reinforcement_count, %[2]s <=> %[3]s AS dist
+		     FROM %[1]s
+		     ORDER BY %[2]s <=> %[3]s
+		     LIMIT $3
+		 ) n
+		 WHERE a.agent_id = $1 AND %[4]s
+		   AND a.is_archived = FALSE AND a.binding <> 'quarantine' AND a.type <> 'summary'
+		   AND 1 - n.dist >= $2
+		 ORDER BY a.id, n.dist`, s.neighborSource(s.neighborProbeWhere(), s.embeddingExpr("a"), "$3"),
+			s.embeddingExpr("b"), s.embeddingExpr("a"), s.embeddingPresent("a")),
+		agentID, threshold, neighbors,
+	)
+	if err != nil {
+		return fmt.Errorf("redundant pairs query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.RedundantPair
+		if err := rows.Scan(&p.A.ID, &p.A.Confidence, &p.A.ReinforcementCount,
+			&p.B.ID, &p.B.Confidence, &p.B.ReinforcementCount, &p.Similarity); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CountNeighbors runs one nearest-neighbor probe per requested memory, as
+// StreamRedundantPairs does, and counts the neighbors in the band.
+func (s *MemoryStore) CountNeighbors(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID, minSim, maxSim float32, k int) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	rows, err := s.db.Query(ctx,
+		fmt.Sprintf(`SELECT a.id, COUNT(*)
+		 FROM memories a
+		 CROSS JOIN LATERAL (
+		     SELECT %[2]s <=> %[3]s AS dist
+		     FROM %[1]s
+		     ORDER BY %[2]s <=> %[3]s
+		     LIMIT $5
+		 ) n
+		 WHERE a.id = ANY($1) AND a.tenant_id = $2 AND %[4]s
+		   AND 1 - n.dist >= $3 AND 1 - n.dist < $4
+		 GROUP BY a.id`, s.neighborSource(s.neighborProbeWhere(), s.embeddingExpr("a"), "$5"),
+			s.embeddingExpr("b"), s.embeddingExpr("a"), s.embeddingPresent("a")),
+		ids, tenantID, minSim, maxSim, k,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("count neighbors query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
 // ListQuarantined returns the firewall review queue for an agent, newest first,
 // with the quarantine reason/time populated.
 func (s *MemoryStore) ListQuarantined(ctx context.Context, agentID, tenantID uuid.UUID, limit, offset int) ([]domain.Memory, int, error) {
@@ -1139,44 +1818,47 @@ func (s *MemoryStore) ReleaseQuarantine(ctx context.Context, id, tenantID uuid.U
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
 	}
+	s.changed(domain.MemoryChange{TenantID: tenantID})
 	return nil
 }
 
 func (s *MemoryStore) Archive(ctx context.Context, id uuid.UUID) error {
-	tag, err := s.db.Exec(ctx,
+	_, _, err := s.execWithEvent(ctx, domain.EventMemoryArchived,
 		`UPDATE memories SET is_archived = TRUE, archived_at = NOW(), updated_at = NOW() WHERE id = $1 AND is_archived = FALSE`,
 		id,
 	)
 	if err != nil {
 		return err
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
-	}
+	s.changed(domain.MemoryChange{MemoryID: id})
 	return nil
 }
 
+// Restore brings an archived memory back. Like a confidence change, it can
+// put the memory into the agent's recalls that did not return it before, so
+// the change covers all of them.
 func (s *MemoryStore) Restore(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) error {
-	tag, err := s.db.Exec(ctx,
+	_, agentID, err := s.execWithEvent(ctx, domain.EventMemoryRestored,
 		`UPDATE memories SET is_archived = FALSE, archived_at = NULL, updated_at = NOW() WHERE id = $1 AND tenant_id = $2 AND is_archived = TRUE`,
 		id, tenantID,
 	)
 	if err != nil {
 		return err
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
-	}
+	s.changed(domain.MemoryChange{TenantID: tenantID, AgentID: agentID})
 	return nil
 }
 
+// IncrementAccessAndBoost is recall's own bookkeeping for the memories it
+// returned, so it doesn't count as a change to cached recalls.
 func (s *MemoryStore) IncrementAccessAndBoost(ctx context.Context, id uuid.UUID, boost float32) error {
 	tag, err := s.db.Exec(ctx,
 		`UPDATE memories
 		 SET access_count = access_count + 1,
 		     last_accessed_at = NOW(),
 		     confidence = LEAST(confidence + $2, 0.99),
-		     updated_at = NOW()
+		     updated_at = NOW(),
+		     `+tierAssignments("LEAST(confidence + $2, 0.99)")+`
 		 WHERE id = $1`,
 		id, boost,
 	)
@@ -1190,18 +1872,17 @@ func (s *MemoryStore) IncrementAccessAndBoost(ctx context.Context, id uuid.UUID,
 }
 
 func (s *MemoryStore) GetByTier(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, tier domain.MemoryTier, limit int) ([]domain.Memory, error) {
-	thresholds := domain.TierConfidenceThresholds[tier]
 	if limit <= 0 {
 		limit = 100
 	}
 
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at
+		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, tier, pinned
 		 FROM memories
-		 WHERE agent_id = $1 AND tenant_id = $2 AND confidence > $3 AND confidence <= $4 AND is_archived = FALSE
-		 ORDER BY confidence DESC
-		 LIMIT $5`,
-		agentID, tenantID, thresholds.Min, thresholds.Max, limit,
+		 WHERE agent_id = $1 AND tenant_id = $2 AND tier = $3 AND is_archived = FALSE
+		 ORDER BY pinned DESC, confidence DESC
+		 LIMIT $4`,
+		agentID, tenantID, string(tier), limit,
 	)
 	if err != nil {
 		return nil, err
@@ -1211,7 +1892,7 @@ func (s *MemoryStore) GetByTier(ctx context.Context, agentID uuid.UUID, tenantID
 	var memories []domain.Memory
 	for rows.Next() {
 		var m domain.Memory
-		if err := rows.Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		if err := rows.Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt, &m.Tier, &m.Pinned); err != nil {
 			return nil, err
 		}
 		memories = append(memories, m)
@@ -1221,14 +1902,7 @@ func (s *MemoryStore) GetByTier(ctx context.Context, agentID uuid.UUID, tenantID
 
 func (s *MemoryStore) GetTierCounts(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (map[domain.MemoryTier]int, error) {
 	rows, err := s.db.Query(ctx,
-		`SELECT
-			CASE
-				WHEN confidence > 0.85 THEN 'hot'
-				WHEN confidence > 0.70 THEN 'warm'
-				WHEN confidence > 0.40 THEN 'cold'
-				ELSE 'archive'
-			END as tier,
-			COUNT(*) as count
+		`SELECT tier, COUNT(*) as count
 		 FROM memories
 		 WHERE agent_id = $1 AND tenant_id = $2 AND is_archived = FALSE
 		 GROUP BY tier`,
@@ -1251,6 +1925,88 @@ func (s *MemoryStore) GetTierCounts(ctx context.Context, agentID uuid.UUID, tena
 	return counts, rows.Err()
 }
 
+// ApplyTierTransitions moves each memory to its new tier and appends the
+// change to memory_tier_history in a single transaction.
+func (s *MemoryStore) ApplyTierTransitions(ctx context.Context, transitions []domain.TierTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+	err := WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		for _, t := range transitions {
+			if _, err := tx.Exec(ctx,
+				`UPDATE memories SET tier = $1, tier_changed_at = $2 WHERE id = $3`,
+				string(t.ToTier), t.OccurredAt, t.MemoryID,
+			); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO memory_tier_history (memory_id, agent_id, tenant_id, from_tier, to_tier, reason, confidence, occurred_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				t.MemoryID, t.AgentID, t.TenantID, string(t.FromTier), string(t.ToTier), t.Reason, t.Confidence, t.OccurredAt,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// A tier change can bring a memory into a recall filtered by tier
+	for _, t := range transitions {
+		s.changed(domain.MemoryChange{TenantID: t.TenantID, AgentID: t.AgentID})
+	}
+	return nil
+}
+
+func (s *MemoryStore) SetPinned(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, pinned bool) error {
+	tag, err := s.db.Exec(ctx,
+		`UPDATE memories SET pinned = $1, updated_at = NOW() WHERE id = $2 AND tenant_id = $3 AND is_archived = FALSE`,
+		pinned, id, tenantID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	s.changed(domain.MemoryChange{MemoryID: id})
+	return nil
+}
+
+// GetTierHistory returns tier changes newest first, for one memory when
+// memoryID is set and otherwise for the whole agent since the given time.
+func (s *MemoryStore) GetTierHistory(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, memoryID *uuid.UUID, since time.Time, limit int) ([]domain.TierTransition, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT memory_id, agent_id, tenant_id, from_tier, to_tier, reason, confidence, occurred_at
+		 FROM memory_tier_history
+		 WHERE agent_id = $1 AND tenant_id = $2 AND ($3::uuid IS NULL OR memory_id = $3) AND occurred_at >= $4
+		 ORDER BY occurred_at DESC
+		 LIMIT $5`,
+		agentID, tenantID, memoryID, since, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []domain.TierTransition
+	for rows.Next() {
+		var t domain.TierTransition
+		var from, to string
+		if err := rows.Scan(&t.MemoryID, &t.AgentID, &t.TenantID, &from, &to, &t.Reason, &t.Confidence, &t.OccurredAt); err != nil {
+			return nil, err
+		}
+		t.FromTier, t.ToTier = domain.MemoryTier(from), domain.MemoryTier(to)
+		history = append(history, t)
+	}
+	return history, rows.Err()
+}
+
 func (s *MemoryStore) SetNeedsReview(ctx context.Context, id uuid.UUID, needsReview bool) error {
 	tag, err := s.db.Exec(ctx,
 		`UPDATE memories SET needs_review = $1, updated_at = NOW() WHERE id = $2`,
@@ -1262,6 +2018,7 @@ func (s *MemoryStore) SetNeedsReview(ctx context.Context, id uuid.UUID, needsRev
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
 	}
+	s.changed(domain.MemoryChange{MemoryID: id})
 	return nil
 }
 
@@ -1417,3 +2174,93 @@ func (s *MemoryStore) CountNeedsReview(ctx context.Context, agentID, tenantID uu
 	).Scan(&n)
 	return n, err
 }
+
+// tenantSearchCondition builds the match predicate and score expression of a
+// tenant-wide search, appending its parameter to args. content is the text
+// column and tsv its tsvector expression.
+func tenantSearchCondition(q domain.TenantSearchQuery, content, tsv string, args *[]any) (where, score string) {
+	switch q.Mode {
+	case domain.SearchModeExact:
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(q.Query)
+		*args = append(*args, "%"+escaped+"%")
+		return fmt.Sprintf(`%s ILIKE $%d ESCAPE '\'`, content, len(*args)), "1.0"
+	case domain.SearchModeSemantic:
+		*args = append(*args, pgvector.NewVector(q.Embedding))
+		n := len(*args)
+		return hasEmbedding(""), fmt.Sprintf("1 - (%s <=> $%d)", storedEmbedding(""), n)
+	default:
+		*args = append(*args, q.Query)
+		n := len(*args)
+		return fmt.Sprintf("%s @@ plainto_tsquery('english', $%d)", tsv, n),
+			fmt.Sprintf("ts_rank(%s, plainto_tsquery('english', $%d))", tsv, n)
+	}
+}
+
+// SearchTenant finds memories of every agent in the tenant, including archived
+// and quarantined ones, best match first.
+func (s *MemoryStore) SearchTenant(ctx context.Context, tenantID uuid.UUID, q domain.TenantSearchQuery) ([]domain.MemorySearchHit, error) {
+	args := []any{tenantID}
+	match, score := tenantSearchCondition(q, "content", "content_tsv", &args)
+	where := "tenant_id = $1 AND " + match
+	if q.AgentID != nil {
+		args = append(args, *q.AgentID)
+		where += fmt.Sprintf(" AND agent_id = $%d", len(args))
+	}
+	args = append(args, q.Limit, q.Offset)
+
+	rows, err := s.db.Query(ctx,
+		fmt.Sprintf(`SELECT id, agent_id, type, content, binding, anchor_id, session_id, confidence, is_archived, created_at, (%s)::real AS score
+		 FROM memories WHERE %s
+		 ORDER BY score DESC, created_at DESC
+		 LIMIT $%d OFFSET $%d`, score, where, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search tenant memories: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []domain.MemorySearchHit
+	for rows.Next() {
+		var h domain.MemorySearchHit
+		if err := rows.Scan(&h.ID, &h.AgentID, &h.Type, &h.Content, &h.Binding, &h.AnchorID, &h.SessionID, &h.Confidence, &h.Archived, &h.CreatedAt, &h.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// ListForSubject returns every memory about an anchor — bound to it, bound to
+// one of its sessions, or mentioning it — including archived and quarantined
+// rows, oldest first.
+func (s *MemoryStore) ListForSubject(ctx context.Context, anchorID, tenantID uuid.UUID) ([]domain.SubjectMemory, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, type, content, embedding_provider, embedding_model, source, provenance, confidence, metadata, expires_at, last_verified_at, reinforcement_count, decay_rate, last_accessed_at, access_count, created_at, updated_at, binding, anchor_id, session_id, quarantine_reason, quarantined_at, is_archived
+		 FROM memories
+		 WHERE tenant_id = $2 AND (
+			anchor_id = $1
+			OR session_id IN (SELECT id FROM sessions WHERE anchor_id = $1 AND tenant_id = $2)
+			OR id IN (SELECT memory_id FROM entity_mentions WHERE entity_id = $1))
+		 ORDER BY created_at`,
+		anchorID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []domain.SubjectMemory
+	for rows.Next() {
+		var m domain.SubjectMemory
+		var quarantineReason *string
+		if err := rows.Scan(&m.ID, &m.AgentID, &m.TenantID, &m.Type, &m.Content, &m.EmbeddingProvider, &m.EmbeddingModel, &m.Source, &m.Provenance, &m.Confidence, &m.Metadata, &m.ExpiresAt, &m.LastVerifiedAt, &m.ReinforcementCount, &m.DecayRate, &m.LastAccessedAt, &m.AccessCount, &m.CreatedAt, &m.UpdatedAt, &m.Binding, &m.AnchorID, &m.SessionID, &quarantineReason, &m.QuarantinedAt, &m.Archived); err != nil {
+			return nil, err
+		}
+		if quarantineReason != nil {
+			m.QuarantineReason = *quarantineReason
+		}
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
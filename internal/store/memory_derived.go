@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// derivedConfidenceMin and derivedConfidenceMax bound a recomputed
+	// confidence the way confidence updates bound it.
+	derivedConfidenceMin = 0.01
+	derivedConfidenceMax = 0.99
+	// derivedConfidenceTolerance is the difference below which a stored
+	// confidence is left alone, so float4 rounding isn't corrected.
+	derivedConfidenceTolerance = 0.005
+	// derivedStrengthTolerance does the same for association strengths.
+	derivedStrengthTolerance = 0.001
+)
+
+// RecomputeDerived recomputes the given derived fields of an agent from the
+// data they derive from, in one transaction, and returns how many rows each
+// changed. Confidence changes are audited as admin_override mutations.
+func (s *MemoryStore) RecomputeDerived(ctx context.Context, agentID, tenantID uuid.UUID, fields []domain.DerivedField, audit domain.DerivedAudit) (map[domain.DerivedField]int, error) {
+	counts := make(map[domain.DerivedField]int, len(fields))
+	var confidenceChanged bool
+	err := WithTx(ctx, s.pool, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM agents WHERE id = $1 AND tenant_id = $2)`,
+			agentID, tenantID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+
+		txs := s.withTx(tx)
+		for _, f := range fields {
+			var n int
+			var err error
+			switch f {
+			case domain.DerivedSuccessRate:
+				n, err = execCount(ctx, tx,
+					`UPDATE procedures SET use_count = success_count + failure_count, updated_at = NOW()
+					 WHERE agent_id = $1 AND tenant_id = $2 AND use_count <> success_count + failure_count`,
+					agentID, tenantID)
+			case domain.DerivedEvidenceCount:
+				n, err = execCount(ctx, tx,
+					`UPDATE schemas
+					 SET evidence_count = COALESCE(cardinality(evidence_memories), 0) + COALESCE(cardinality(evidence_episodes), 0),
+					     updated_at = NOW()
+					 WHERE agent_id = $1 AND tenant_id = $2
+					   AND evidence_count <> COALESCE(cardinality(evidence_memories), 0) + COALESCE(cardinality(evidence_episodes), 0)`,
+					agentID, tenantID)
+			case domain.DerivedConfidence:
+				n, err = txs.recomputeConfidence(ctx, agentID, tenantID, audit)
+				confidenceChanged = n > 0
+			case domain.DerivedAssociations:
+				n, err = recomputeAssociationStrengths(ctx, tx, agentID, tenantID)
+			default:
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("recompute %s: %w", f, err)
+			}
+			counts[f] = n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if confidenceChanged {
+		s.changed(domain.MemoryChange{TenantID: tenantID, AgentID: agentID})
+	}
+	return counts, nil
+}
+
+type derivedConfidence struct {
+	mem     domain.Memory
+	derived float32
+}
+
+// recomputeConfidence sets the confidence of the agent's live memories that
+// carry evidence counts to the mean of Beta(evidence_for, evidence_against),
+// writing an audit row for each change. Run on a store bound to a transaction.
+func (s *MemoryStore) recomputeConfidence(ctx context.Context, agentID, tenantID uuid.UUID, audit domain.DerivedAudit) (int, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, content, confidence, anchor_id, binding, evidence_for, evidence_against
+		 FROM memories
+		 WHERE agent_id = $1 AND tenant_id = $2 AND is_archived = FALSE AND binding <> 'quarantine'
+		   AND evidence_for + evidence_against > 0`,
+		agentID, tenantID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var stale []derivedConfidence
+	for rows.Next() {
+		m := domain.Memory{AgentID: agentID, TenantID: tenantID}
+		if err := rows.Scan(&m.ID, &m.Content, &m.Confidence, &m.AnchorID, &m.Binding, &m.EvidenceFor, &m.EvidenceAgainst); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		derived := float32(math.Min(math.Max(m.EvidenceFor/(m.EvidenceFor+m.EvidenceAgainst), derivedConfidenceMin), derivedConfidenceMax))
+		if math.Abs(float64(derived-m.Confidence)) >= derivedConfidenceTolerance {
+			stale = append(stale, derivedConfidence{mem: m, derived: derived})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	actorID := audit.ActorID
+	for _, d := range stale {
+		if _, err := s.db.Exec(ctx,
+			`UPDATE memories SET confidence = $1, updated_at = NOW(), `+tierAssignments("$1")+` WHERE id = $2`,
+			d.derived, d.mem.ID,
+		); err != nil {
+			return 0, err
+		}
+		old, tid := d.mem.Confidence, tenantID
+		mut := &domain.MutationLog{
+			MemoryID:      d.mem.ID,
+			AgentID:       agentID,
+			MutationType:  domain.MutationAdminOverride,
+			SourceType:    domain.MutationSourceAdmin,
+			OldConfidence: &old,
+			NewConfidence: &d.derived,
+			Reason:        audit.Reason,
+			TenantID:      &tid,
+			AnchorID:      d.mem.AnchorID,
+			Binding:       string(d.mem.Binding),
+			ContentHash:   domain.HashContent(d.mem.Content),
+			ActorType:     audit.ActorType,
+		}
+		if actorID != uuid.Nil {
+			mut.ActorID = &actorID
+		}
+		if err := insertMutationLog(ctx, s.db, mut); err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
+
+// recomputeAssociationStrengths sets the strength of the agent's thematic
+// associations, which are created with the similarity of the two embeddings,
+// back to that similarity: episode-to-memory links in memory_associations and
+// episode-to-episode links in episode_associations.
+func recomputeAssociationStrengths(ctx context.Context, db DBTX, agentID, tenantID uuid.UUID) (int, error) {
+	memoryLinks, err := execCount(ctx, db,
+		`UPDATE memory_associations a
+		 SET association_strength = LEAST(GREATEST(1 - (`+storedEmbedding("e")+` <=> `+storedEmbedding("m")+`), 0), 1)
+		 FROM episodes e, memories m
+		 WHERE a.tenant_id = $2 AND a.association_type = 'thematic'
+		   AND a.source_memory_type = 'episodic' AND a.target_memory_type = 'semantic'
+		   AND e.id = a.source_memory_id AND e.agent_id = $1 AND e.tenant_id = $2 AND `+hasEmbedding("e")+`
+		   AND m.id = a.target_memory_id AND m.tenant_id = $2 AND `+hasEmbedding("m")+`
+		   AND abs(a.association_strength - LEAST(GREATEST(1 - (`+storedEmbedding("e")+` <=> `+storedEmbedding("m")+`), 0), 1)) >= $3`,
+		agentID, tenantID, derivedStrengthTolerance)
+	if err != nil {
+		return 0, err
+	}
+	episodeLinks, err := execCount(ctx, db,
+		`UPDATE episode_associations a
+		 SET association_strength = LEAST(GREATEST(1 - (`+storedEmbedding("ea")+` <=> `+storedEmbedding("eb")+`), 0), 1)
+		 FROM episodes ea, episodes eb
+		 WHERE a.association_type = 'thematic'
+		   AND ea.id = a.episode_a_id AND ea.agent_id = $1 AND ea.tenant_id = $2 AND `+hasEmbedding("ea")+`
+		   AND eb.id = a.episode_b_id AND eb.tenant_id = $2 AND `+hasEmbedding("eb")+`
+		   AND abs(a.association_strength - LEAST(GREATEST(1 - (`+storedEmbedding("ea")+` <=> `+storedEmbedding("eb")+`), 0), 1)) >= $3`,
+		agentID, tenantID, derivedStrengthTolerance)
+	if err != nil {
+		return memoryLinks, err
+	}
+	return memoryLinks + episodeLinks, nil
+}
+
+func execCount(ctx context.Context, db DBTX, sql string, args ...any) (int, error) {
+	tag, err := db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+var _ domain.DerivedFieldRecomputer = (*MemoryStore)(nil)
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// quantizedRerankFactor widens the quantized shortlist over the results
+	// wanted, since quantization blurs the ranking the rerank then restores.
+	quantizedRerankFactor = 4
+	// quantizedMinCandidates floors the shortlist for small TopK.
+	quantizedMinCandidates = 40
+)
+
+// SetQuantization makes the nearest-neighbor searches shortlist candidates
+// on the quantized copy of each embedding and rerank them at full precision,
+// and keeps that copy written alongside new embeddings. Memories embedded
+// before it was enabled join every shortlist unranked until QuantizeVectors
+// fills them in, so none drop out of recall. Ignored by Recall when an
+// external vector store is set. EnsureVectorQuantization builds the matching
+// index.
+func (s *MemoryStore) SetQuantization(q domain.VectorQuantization) {
+	s.quantization = q
+}
+
+// quantizedColumn returns the column holding the quantized embedding and the
+// SQL quantizing the vector expression v into it; col is "" when off.
+func (s *MemoryStore) quantizedColumn(v string) (col, expr, op string) {
+	switch s.quantization {
+	case domain.QuantizationHalfvec:
+		return "embedding_half", fmt.Sprintf("(%s)::halfvec", v), "<=>"
+	case domain.QuantizationBinary:
+		return "embedding_bit", fmt.Sprintf("binary_quantize(%s)", v), "<~>"
+	default:
+		return "", "", ""
+	}
+}
+
+// quantizedShortlist returns a condition keeping only the limit rows matching
+// where that are nearest the query on the quantized index, plus the matching
+// rows not quantized yet, or "" when quantization is off. The caller's ORDER
+// BY on the full-precision embedding then reranks the shortlist.
+func (s *MemoryStore) quantizedShortlist(where string, embeddingParam, limitParam int) string {
+	col, expr, op := s.quantizedColumn(fmt.Sprintf("$%d::vector", embeddingParam))
+	if col == "" {
+		return ""
+	}
+	return fmt.Sprintf(
+		`id IN ((SELECT id FROM memories WHERE %s AND %s IS NOT NULL ORDER BY %s %s %s LIMIT $%d)
+		        UNION ALL
+		        SELECT id FROM memories WHERE %s AND %s IS NULL)`,
+		where, col, col, op, expr, limitParam, where, col)
+}
+
+// neighborSource returns a FROM item, aliased b, holding the memories
+// matching where (written against b) that are candidates for the limit
+// nearest neighbors of the vector expression probe. With quantization on it
+// is the quantized index's shortlist plus the rows not quantized yet, for the
+// caller to rerank at full precision; otherwise every matching row, left to
+// the full-precision index.
+func (s *MemoryStore) neighborSource(where, probe, limit string) string {
+	col, expr, op := s.quantizedColumn(probe)
+	if col == "" {
+		return fmt.Sprintf("(SELECT b.* FROM memories b WHERE %s) b", where)
+	}
+	return fmt.Sprintf(
+		`((SELECT b.* FROM memories b WHERE %s AND b.%s IS NOT NULL ORDER BY b.%s %s %s LIMIT GREATEST(%s * %d, %d))
+		  UNION ALL
+		  SELECT b.* FROM memories b WHERE %s AND b.%s IS NULL) b`,
+		where, col, col, op, expr, limit, quantizedRerankFactor, quantizedMinCandidates, where, col)
+}
+
+// quantizedCandidates is the shortlist size for topK results.
+func quantizedCandidates(topK int) int {
+	return max(topK*quantizedRerankFactor, quantizedMinCandidates)
+}
+
+// quantizedInsert returns the extra column and value that write the quantized
+// copy of the embedding v on insert; both are "" when quantization is off.
+func (s *MemoryStore) quantizedInsert(v string) (col, val string) {
+	c, expr, _ := s.quantizedColumn(v)
+	if c == "" {
+		return "", ""
+	}
+	return ", " + c, ", " + expr
+}
+
+// quantizedAssignments is the SET list for a new embedding v: the active
+// quantized column is rewritten and the other cleared, so neither goes stale.
+func (s *MemoryStore) quantizedAssignments(v string) string {
+	half, bit := "NULL", "NULL"
+	switch col, expr, _ := s.quantizedColumn(v); col {
+	case "embedding_half":
+		half = expr
+	case "embedding_bit":
+		bit = expr
+	}
+	return fmt.Sprintf("embedding_half = %s, embedding_bit = %s", half, bit)
+}
+
+// QuantizeVectors fills the quantized copy of every embedding of the agent
+// that lacks one, page by page, and drops the row's own copy of a vector it
+// shares with the embeddings table (see SetEmbeddingDedup). Use it after
+// enabling VECTOR_QUANTIZATION; memories embedded since are quantized as they
+// are written. Returns the number of memories written.
+func (s *MemoryStore) QuantizeVectors(ctx context.Context, agentID, tenantID uuid.UUID) (int, error) {
+	col, expr, _ := s.quantizedColumn(storedEmbedding(""))
+	if col == "" {
+		return 0, nil
+	}
+	// Assignments all read the row as it was, so the quantized copy is taken
+	// before the row's own vector is cleared.
+	release, pending := "embedding", "FALSE"
+	if s.sharesEmbedding() {
+		release = "CASE WHEN embedding_hash IS NULL THEN embedding END"
+		pending = "(embedding IS NOT NULL AND embedding_hash IS NOT NULL)"
+	}
+	query := fmt.Sprintf(
+		`UPDATE memories SET %[1]s = %[2]s, embedding = %[3]s
+		 WHERE id IN (SELECT id FROM memories
+		              WHERE agent_id = $1 AND tenant_id = $2 AND %[4]s AND (%[1]s IS NULL OR %[5]s)
+		              LIMIT $3)`,
+		col, expr, release, hasEmbedding(""), pending)
+	count := 0
+	for {
+		tag, err := s.db.Exec(ctx, query, agentID, tenantID, iteratePageSize)
+		if err != nil {
+			return count, err
+		}
+		n := int(tag.RowsAffected())
+		count += n
+		if n < iteratePageSize {
+			return count, nil
+		}
+	}
+}
+
+// releaseSharedVectors gives every memory referencing a shared vector its
+// own copy back, for the full-precision index to find it once quantization
+// is off. Returns the number of memories written.
+func releaseSharedVectors(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	tag, err := pool.Exec(ctx,
+		`UPDATE memories SET embedding = `+storedEmbedding("")+`
+		 WHERE embedding IS NULL AND embedding_hash IS NOT NULL`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// memoryVectorIndex returns the memories vector index searched under
+// quantization q: the quantized column's index alone when shortlisting,
+// since the rerank reads the full-precision vectors by id, and the
+// full-precision index otherwise.
+func memoryVectorIndex(q domain.VectorQuantization) string {
+	switch q {
+	case domain.QuantizationHalfvec:
+		return "idx_memories_embedding_half"
+	case domain.QuantizationBinary:
+		return "idx_memories_embedding_bit"
+	default:
+		return "idx_memories_embedding"
+	}
+}
+
+// EnsureVectorQuantization builds the memories vector index the configured
+// quantization searches and drops the other two, so only one HNSW index is
+// kept up to date on every write. It is a no-op when they already match.
+// Without quantization, memories that share their vector take a copy back,
+// as the full-precision index only sees the row's own column.
+// Columns wider than the index type allows are left unindexed, as in
+// EnsureEmbeddingDimension.
+func EnsureVectorQuantization(ctx context.Context, pool *pgxpool.Pool, q domain.VectorQuantization, logger *zap.Logger) error {
+	keep := memoryVectorIndex(q)
+	if q == domain.QuantizationNone {
+		n, err := releaseSharedVectors(ctx, pool)
+		if err != nil {
+			return fmt.Errorf("copy shared vectors back to memories: %w", err)
+		}
+		if n > 0 {
+			logger.Info("copied shared vectors back to memories for the full-precision index", zap.Int64("count", n))
+		}
+	}
+	for _, c := range embeddingVectorColumns {
+		if c.table != "memories" || c.index == "" {
+			continue
+		}
+		if c.index != keep {
+			if _, err := pool.Exec(ctx, fmt.Sprintf(`DROP INDEX IF EXISTS %s`, c.index)); err != nil {
+				return fmt.Errorf("drop index %s: %w", c.index, err)
+			}
+			continue
+		}
+
+		var exists bool
+		if err := pool.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, c.index).Scan(&exists); err != nil {
+			return fmt.Errorf("look up index %s: %w", c.index, err)
+		}
+		if exists {
+			continue
+		}
+		dim, err := vectorColumnDim(ctx, pool, c.table, c.column)
+		if err != nil {
+			return fmt.Errorf("read dimension of %s.%s: %w", c.table, c.column, err)
+		}
+		if dim > hnswMaxDimFor(c.typ) {
+			logger.Warn("embedding dimension exceeds hnsw limit; column left unindexed (recall uses sequential scan)",
+				zap.String("table", c.table), zap.String("column", c.column), zap.Int("dim", dim), zap.Int("hnsw_max", hnswMaxDimFor(c.typ)))
+			continue
+		}
+		logger.Info("building memory vector index for the configured quantization",
+			zap.String("index", c.index), zap.String("quantization", string(q)))
+		if _, err := pool.Exec(ctx, fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (%s %s)`, c.index, vectorIndexTable(c.table), c.column, c.ops)); err != nil {
+			return fmt.Errorf("create index %s: %w", c.index, err)
+		}
+	}
+	return nil
+}
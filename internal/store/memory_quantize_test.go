@@ -0,0 +1,104 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+func TestQuantizedShortlist_KeepsUnquantizedRows(t *testing.T) {
+	s := &MemoryStore{}
+	if got := s.quantizedShortlist("agent_id = $1", 2, 3); got != "" {
+		t.Errorf("expected no shortlist with quantization off, got %s", got)
+	}
+	if got := s.neighborSource("b.agent_id = a.agent_id", "a.embedding", "$3"); strings.Contains(got, "UNION") {
+		t.Errorf("expected every matching row with quantization off, got %s", got)
+	}
+
+	s.SetQuantization(domain.QuantizationHalfvec)
+	shortlist := s.quantizedShortlist("agent_id = $1", 2, 3)
+	for _, want := range []string{
+		"embedding_half IS NOT NULL ORDER BY embedding_half <=> ($2::vector)::halfvec LIMIT $3",
+		"UNION ALL",
+		"WHERE agent_id = $1 AND embedding_half IS NULL",
+	} {
+		if !strings.Contains(shortlist, want) {
+			t.Errorf("expected shortlist to contain %q, got %s", want, shortlist)
+		}
+	}
+
+	s.SetQuantization(domain.QuantizationBinary)
+	source := s.neighborSource("b.agent_id = a.agent_id", "a.embedding", "$3")
+	for _, want := range []string{
+		"ORDER BY b.embedding_bit <~> binary_quantize(a.embedding) LIMIT GREATEST($3 * 4, 40)",
+		"WHERE b.agent_id = a.agent_id AND b.embedding_bit IS NULL",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("expected neighbor source to contain %q, got %s", want, source)
+		}
+	}
+}
+
+func TestMemoryVectorIndex(t *testing.T) {
+	for q, want := range map[domain.VectorQuantization]string{
+		domain.QuantizationNone:    "idx_memories_embedding",
+		domain.QuantizationHalfvec: "idx_memories_embedding_half",
+		domain.QuantizationBinary:  "idx_memories_embedding_bit",
+	} {
+		if got := memoryVectorIndex(q); got != want {
+			t.Errorf("quantization %q: expected %s, got %s", q, want, got)
+		}
+	}
+	if sql := readMigration(t, "059_embedding_quantization.up.sql"); strings.Contains(sql, "CREATE INDEX") {
+		t.Errorf("expected 059 to leave the quantized indexes to EnsureVectorQuantization")
+	}
+}
+
+func TestEmbeddingExpr_SharedOnlyUnderQuantization(t *testing.T) {
+	s := &MemoryStore{}
+	s.SetEmbeddingDedup("openai/text-embedding-3-small/1536")
+	// Without a quantized copy the row keeps its vector, and distance sorts
+	// must name the bare column to use idx_memories_embedding.
+	if got := s.embeddingExpr("b"); got != "b.embedding" {
+		t.Errorf("expected the bare column without quantization, got %s", got)
+	}
+	if got := s.embeddingPresent(""); got != "embedding IS NOT NULL" {
+		t.Errorf("expected a plain presence check without quantization, got %s", got)
+	}
+
+	s.SetQuantization(domain.QuantizationHalfvec)
+	if got := s.embeddingExpr("b"); got != "stored_embedding(b.embedding, b.tenant_id, b.embedding_dedup_model, b.embedding_hash)" {
+		t.Errorf("expected the shared vector under quantization, got %s", got)
+	}
+	if got := s.embeddingPresent(""); got != "(embedding IS NOT NULL OR embedding_hash IS NOT NULL)" {
+		t.Errorf("expected shared rows to count as embedded, got %s", got)
+	}
+	if got := s.neighborSource(s.neighborProbeWhere(), s.embeddingExpr("a"), "$3"); !strings.Contains(got,
+		"ORDER BY b.embedding_half <=> (stored_embedding(a.embedding, a.tenant_id, a.embedding_dedup_model, a.embedding_hash))::halfvec") {
+		t.Errorf("expected probes to shortlist on the quantized copy of the shared vector, got %s", got)
+	}
+}
+
+func TestEmbeddingDedupMigration_RowsReferenceSharedVectors(t *testing.T) {
+	up := readMigration(t, "060_embedding_dedup.up.sql")
+	down := readMigration(t, "060_embedding_dedup.down.sql")
+	for _, table := range []string{"memories", "episodes"} {
+		for _, want := range []string{
+			table + "_embedding_ref_fkey\n        FOREIGN KEY (tenant_id, embedding_dedup_model, embedding_hash)\n        REFERENCES embeddings (tenant_id, model, content_hash)",
+			"CREATE INDEX IF NOT EXISTS idx_" + table + "_embedding_ref",
+		} {
+			if !strings.Contains(up, want) {
+				t.Errorf("up: expected %q", want)
+			}
+		}
+		// The shared vector goes with the table, so rows get theirs back first.
+		restore := strings.Index(down, "UPDATE "+table+" SET embedding = stored_embedding(")
+		if restore < 0 || restore > strings.Index(down, "DROP TABLE IF EXISTS embeddings;") {
+			t.Errorf("down: expected %s to copy shared vectors back before embeddings is dropped", table)
+		}
+	}
+	if !strings.Contains(up, "CREATE OR REPLACE FUNCTION stored_embedding(") {
+		t.Errorf("up: expected stored_embedding")
+	}
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	pgvector "github.com/pgvector/pgvector-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// vectorOversample widens external searches: binding, anchor, confidence
+	// and date filters are applied in Postgres, after the ANN search.
+	vectorOversample = 4
+	// vectorMinCandidates floors a widened search for small TopK.
+	vectorMinCandidates = 50
+	// vectorSimilarLimit bounds FindSimilar, which is unbounded on pgvector.
+	vectorSimilarLimit = 256
+)
+
+// SetVectorStore serves Recall, FindSimilar and FindSimilarFiltered from an
+// external vector store instead of pgvector. Writes that set or clear an
+// embedding are mirrored to it; a failed mirror or search is logged, and a
+// failed search falls back to pgvector. RecallExhaustive and RecallHybrid
+// always run in Postgres.
+func (s *MemoryStore) SetVectorStore(vs domain.VectorStore, logger *zap.Logger) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	s.vectors, s.logger = vs, logger
+}
+
+// vectorCandidates returns the IDs the external store proposes for q, widened
+// by vectorOversample. ok is false when there is no external store or the
+// search failed, and the caller should search pgvector itself.
+func (s *MemoryStore) vectorCandidates(ctx context.Context, q domain.VectorQuery) (ids []uuid.UUID, ok bool) {
+	if s.vectors == nil {
+		return nil, false
+	}
+	q.Limit = max(q.Limit*vectorOversample, vectorMinCandidates)
+	matches, err := s.vectors.Search(ctx, q)
+	if err != nil {
+		s.logger.Warn("vector store search failed, using pgvector", zap.Error(err))
+		return nil, false
+	}
+	ids = make([]uuid.UUID, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return ids, true
+}
+
+// indexVector mirrors one memory's embedding to the external store.
+func (s *MemoryStore) indexVector(ctx context.Context, p domain.VectorPoint) {
+	if s.vectors == nil || len(p.Embedding) == 0 {
+		return
+	}
+	if err := s.vectors.Upsert(ctx, []domain.VectorPoint{p}); err != nil {
+		s.logger.Warn("vector store upsert failed; run a vector sync to repair",
+			zap.String("memory_id", p.ID.String()), zap.Error(err))
+	}
+}
+
+// unindexVectors drops memories from the external store. Rows removed without
+// it are only filtered out at read time.
+func (s *MemoryStore) unindexVectors(ctx context.Context, ids ...uuid.UUID) {
+	if s.vectors == nil || len(ids) == 0 {
+		return
+	}
+	if err := s.vectors.Delete(ctx, ids); err != nil {
+		s.logger.Warn("vector store delete failed", zap.Int("count", len(ids)), zap.Error(err))
+	}
+}
+
+// SyncVectors upserts every live embedding of the agent into the external
+// store, page by page. Use it to backfill a new store or repair failed
+// mirrors. Returns the number of memories sent.
+func (s *MemoryStore) SyncVectors(ctx context.Context, agentID, tenantID uuid.UUID) (int, error) {
+	if s.vectors == nil {
+		return 0, nil
+	}
+	count := 0
+	after := uuid.Nil
+	for {
+		page, err := s.vectorPage(ctx, agentID, tenantID, after)
+		if err != nil {
+			return count, err
+		}
+		if len(page) == 0 {
+			return count, nil
+		}
+		if err := s.vectors.Upsert(ctx, page); err != nil {
+			return count, err
+		}
+		count += len(page)
+		if len(page) < iteratePageSize {
+			return count, nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+func (s *MemoryStore) vectorPage(ctx context.Context, agentID, tenantID, after uuid.UUID) ([]domain.VectorPoint, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, type, `+s.embeddingExpr("")+` FROM memories
+		 WHERE agent_id = $1 AND tenant_id = $2 AND id > $3
+		   AND `+s.embeddingPresent("")+` AND is_archived = FALSE AND binding <> 'quarantine'
+		 ORDER BY id
+		 LIMIT $4`,
+		agentID, tenantID, after, iteratePageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := make([]domain.VectorPoint, 0, iteratePageSize)
+	for rows.Next() {
+		p := domain.VectorPoint{AgentID: agentID, TenantID: tenantID}
+		var vec pgvector.Vector
+		if err := rows.Scan(&p.ID, &p.Type, &vec); err != nil {
+			return nil, err
+		}
+		p.Embedding = vec.Slice()
+		page = append(page, p)
+	}
+	return page, rows.Err()
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readMigration(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile("../../migrations/" + name)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(b)
+}
+
+// TestPartitionMemoriesMigration checks that 061 and its down migration
+// release everything that pins the table they swap out, and that the down
+// migration undoes what the up migration adds.
+func TestPartitionMemoriesMigration(t *testing.T) {
+	up := readMigration(t, "061_partition_memories_by_archive.up.sql")
+	down := readMigration(t, "061_partition_memories_by_archive.down.sql")
+
+	// memory_stats_add takes a memories row, so DROP TABLE on the old table
+	// fails while it exists.
+	pins := []string{
+		"DROP TRIGGER IF EXISTS memories_stats_insert_delete ON memories;",
+		"DROP TRIGGER IF EXISTS memories_stats_update ON memories;",
+		"DROP FUNCTION IF EXISTS memory_stats_add(memories, INT);",
+	}
+	recreated := []string{
+		"CREATE OR REPLACE FUNCTION memory_stats_add(m memories, sign INT)",
+		"CREATE TRIGGER memories_stats_insert_delete",
+		"CREATE TRIGGER memories_stats_update",
+	}
+	for name, sql := range map[string]string{"up": up, "down": down} {
+		swap := strings.Index(sql, "ALTER TABLE memories RENAME TO")
+		if swap < 0 {
+			t.Fatalf("%s: no table swap found", name)
+		}
+		for _, stmt := range pins {
+			if i := strings.Index(sql, stmt); i < 0 || i > swap {
+				t.Errorf("%s: expected %q before the swap", name, stmt)
+			}
+		}
+		for _, stmt := range recreated {
+			if i := strings.Index(sql, stmt); i < swap {
+				t.Errorf("%s: expected %q after the swap", name, stmt)
+			}
+		}
+		if regexp.MustCompile(`(?i)DROP [^;]*CASCADE;`).MatchString(sql) {
+			t.Errorf("%s: expected no DROP ... CASCADE", name)
+		}
+	}
+
+	// Every edge the delete trigger stands in for gets its foreign key back.
+	cascaded := tableNames(up, `DELETE FROM (\w+) WHERE`)
+	restored := tableNames(down, `ALTER TABLE (\w+)\s+ADD CONSTRAINT`)
+	if len(cascaded) == 0 || !slices.Equal(cascaded, restored) {
+		t.Errorf("expected the down migration to restore foreign keys on %v, got %v", cascaded, restored)
+	}
+
+	// Whatever the up migration creates, the down migration drops.
+	for _, obj := range []struct{ created, dropped string }{
+		{"CREATE TABLE memory_ids", "DROP TABLE IF EXISTS memory_ids;"},
+		{"CREATE OR REPLACE FUNCTION memory_ids_sync()", "DROP FUNCTION IF EXISTS memory_ids_sync();"},
+		{"CREATE TRIGGER trg_memory_ids", "DROP TRIGGER IF EXISTS trg_memory_ids ON memories;"},
+		{"CREATE OR REPLACE FUNCTION memories_cascade_delete()", "DROP FUNCTION IF EXISTS memories_cascade_delete();"},
+		{"CREATE TRIGGER trg_memories_cascade_delete", "DROP TRIGGER IF EXISTS trg_memories_cascade_delete ON memories;"},
+	} {
+		if !strings.Contains(up, obj.created) {
+			t.Errorf("up: expected %q", obj.created)
+		}
+		if !strings.Contains(down, obj.dropped) {
+			t.Errorf("down: expected %q", obj.dropped)
+		}
+	}
+}
+
+// TestPartitionEpisodesMigration checks that 062 keeps an episode's edges
+// when archiving moves it, still cleans up every edge on a real delete, and
+// that the down migration points the vector index back at episodes.
+func TestPartitionEpisodesMigration(t *testing.T) {
+	up := readMigration(t, "062_partition_episodes_by_archive.up.sql")
+	down := readMigration(t, "062_partition_episodes_by_archive.down.sql")
+
+	fn := up[strings.Index(up, "CREATE OR REPLACE FUNCTION episodes_cascade_delete()"):]
+	fn = fn[:strings.Index(fn, "END $$;")]
+	if !strings.Contains(fn, "EXISTS (SELECT 1 FROM episodes WHERE id = OLD.id)") {
+		t.Error("expected the cascade trigger to skip episodes that moved partition")
+	}
+	edges := tableNames(fn, `DELETE FROM (\w+) WHERE`)
+	want := []string{"consolidation_failures", "episode_associations", "episode_chunks", "episode_memory_usage", "outcome_attributions"}
+	if !slices.Equal(edges, want) {
+		t.Errorf("expected the cascade trigger to clean up %v, got %v", want, edges)
+	}
+
+	if !strings.Contains(up, "CHECK (is_archived = (consolidation_status = 'archived'))") {
+		t.Error("expected is_archived to be tied to the archived status")
+	}
+	for name, sql := range map[string]string{"up": up, "down": down} {
+		if regexp.MustCompile(`(?i)DROP [^;]*CASCADE;`).MatchString(sql) {
+			t.Errorf("%s: expected no DROP ... CASCADE", name)
+		}
+	}
+	if !strings.Contains(up, "SET table_name = 'episodes_active'") || !strings.Contains(down, "SET table_name = 'episodes'") {
+		t.Error("expected the vector index settings to follow the index")
+	}
+}
+
+func TestEpisodePartitionMonth(t *testing.T) {
+	want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{"episodes_y2024m03", "episodes_archived_y2024m03"} {
+		if got, ok := episodePartitionMonth(name); !ok || !got.Equal(want) {
+			t.Errorf("%s: expected %s, got %s (%v)", name, want, got, ok)
+		}
+	}
+	for _, name := range []string{"episodes_default", "episodes_archived_default", "episodes_y2024m03_h0"} {
+		if _, ok := episodePartitionMonth(name); ok {
+			t.Errorf("%s: expected no month", name)
+		}
+	}
+}
+
+// tableNames returns the distinct first submatches of pattern in sql, sorted.
+func tableNames(sql, pattern string) []string {
+	var names []string
+	for _, m := range regexp.MustCompile(pattern).FindAllStringSubmatch(sql, -1) {
+		if name := m[1]; name != "memories" && name != "memory_ids" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestVectorIndexTable(t *testing.T) {
+	if got := vectorIndexTable("memories"); got != "memories_active" {
+		t.Errorf("expected memory vector indexes on the active partition, got %s", got)
+	}
+	if got := vectorIndexTable("episodes"); got != "episodes_active" {
+		t.Errorf("expected episode vector indexes on the active partition, got %s", got)
+	}
+	if got := vectorIndexTable("procedures"); got != "procedures" {
+		t.Errorf("expected other tables to be indexed in place, got %s", got)
+	}
+}
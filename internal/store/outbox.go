@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// OutboxStore reads and resolves rows of the event outbox. Memory events are
+// written by MemoryStore (Create, UpdateReinforcement, UpdateConfidence,
+// Archive, Restore) and insertMutationLog in the statement that makes the
+// change; Enqueue writes the rest.
+type OutboxStore struct {
+	db DB
+}
+
+func NewOutboxStore(db DB) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+// Claim pushes the next attempt time of up to limit due events out by lease
+// and returns them in id order. SKIP LOCKED keeps concurrent publishers from
+// claiming the same rows.
+func (s *OutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	rows, err := s.db.Query(ctx,
+		`UPDATE event_outbox o SET next_attempt_at = $2
+		   FROM (SELECT id FROM event_outbox
+		          WHERE published_at IS NULL AND next_attempt_at <= NOW()
+		          ORDER BY id
+		          LIMIT $1
+		          FOR UPDATE SKIP LOCKED) due
+		  WHERE o.id = due.id
+		 RETURNING o.id, o.tenant_id, o.agent_id, o.aggregate_id, o.event_type, o.payload, o.created_at, o.attempts`,
+		limit, time.Now().Add(lease),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for rows.Next() {
+		var e domain.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.TenantID, &e.AgentID, &e.AggregateID, &e.Type, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (s *OutboxStore) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.db.Exec(ctx,
+		`UPDATE event_outbox SET published_at = NOW(), attempts = attempts + 1, last_error = NULL
+		 WHERE id = ANY($1)`,
+		ids,
+	)
+	return err
+}
+
+func (s *OutboxStore) MarkFailed(ctx context.Context, id int64, errMsg string, nextAttempt time.Time) error {
+	_, err := s.db.Exec(ctx,
+		`UPDATE event_outbox SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		 WHERE id = $1`,
+		id, errMsg, nextAttempt,
+	)
+	return err
+}
+
+func (s *OutboxStore) DeleteBefore(ctx context.Context, publishedCutoff, abandonCutoff time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx,
+		`DELETE FROM event_outbox
+		 WHERE (published_at IS NOT NULL AND created_at < $1) OR created_at < $2`,
+		publishedCutoff, abandonCutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *OutboxStore) Enqueue(ctx context.Context, e *domain.OutboxEvent) error {
+	payload := e.Payload
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	return s.db.QueryRow(ctx,
+		`INSERT INTO event_outbox (tenant_id, agent_id, aggregate_id, event_type, payload)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at`,
+		e.TenantID, e.AgentID, e.AggregateID, e.Type, payload,
+	).Scan(&e.ID, &e.CreatedAt)
+}
@@ -0,0 +1,22 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithMemoryEvent_WritesEventInTheSameStatement(t *testing.T) {
+	update := `UPDATE memories SET confidence = $1 WHERE id = $2`
+	sql := withMemoryEvent(update, 3)
+
+	if !strings.HasPrefix(strings.TrimSpace(sql), "WITH m AS (") || !strings.Contains(sql, update) {
+		t.Fatalf("expected the update inside the statement, got %s", sql)
+	}
+	ev := strings.Index(sql, "INSERT INTO event_outbox")
+	if ev < 0 || !strings.Contains(sql[ev:], "$3::text") || !strings.Contains(sql[ev:], "FROM m") {
+		t.Errorf("expected one outbox row of type $3 per updated row, got %s", sql)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(sql), "SELECT tenant_id, agent_id FROM m") {
+		t.Errorf("expected the statement to return the changed row's scope, got %s", sql)
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PipelineMetricsStore aggregates per-day pipeline activity for anomaly
+// detection. Contradictions are attributed to the agent of the contradicted
+// belief; decay archives are the decay rows of the mutation log whose reason
+// marks an archive.
+type PipelineMetricsStore struct {
+	db DB
+}
+
+func NewPipelineMetricsStore(db DB) *PipelineMetricsStore {
+	return &PipelineMetricsStore{db: db}
+}
+
+func (s *PipelineMetricsStore) DailyByAgent(ctx context.Context, agentID, tenantID uuid.UUID, now time.Time, days int) ([]domain.PipelineMetricDay, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT floor(extract(epoch FROM ($3 - at)) / 86400)::int AS age,
+		        sum(contradictions)::int, sum(episodes)::int, sum(extracted)::int, sum(archived)::int
+		   FROM (
+		     SELECT bc.detected_at AS at, 1 AS contradictions, 0 AS episodes, 0 AS extracted, 0 AS archived
+		       FROM belief_contradictions bc
+		       JOIN memories m ON m.id = bc.belief_id
+		      WHERE m.agent_id = $1 AND m.tenant_id = $2
+		        AND bc.detected_at > $3 - make_interval(days => $4) AND bc.detected_at <= $3
+		     UNION ALL
+		     SELECT finished_at, 0,
+		            COALESCE((result->>'episodes_processed')::int, 0),
+		            COALESCE((result->>'semantic_extracted')::int, 0), 0
+		       FROM consolidation_runs
+		      WHERE agent_id = $1 AND tenant_id = $2
+		        AND finished_at > $3 - make_interval(days => $4) AND finished_at <= $3
+		     UNION ALL
+		     SELECT created_at, 0, 0, 0, 1
+		       FROM mutation_log
+		      WHERE agent_id = $1 AND tenant_id = $2
+		        AND mutation_type = 'decay' AND reason LIKE 'decay: archived%'
+		        AND created_at > $3 - make_interval(days => $4) AND created_at <= $3
+		   ) activity
+		  GROUP BY age
+		  ORDER BY age`,
+		agentID, tenantID, now, days,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.PipelineMetricDay
+	for rows.Next() {
+		var d domain.PipelineMetricDay
+		if err := rows.Scan(&d.Age, &d.Contradictions, &d.EpisodesProcessed, &d.SemanticExtracted, &d.DecayArchived); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -7,35 +7,35 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PolicyStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewPolicyStore(db *pgxpool.Pool) *PolicyStore {
+func NewPolicyStore(db DB) *PolicyStore {
 	return &PolicyStore{db: db}
 }
 
 func (s *PolicyStore) Upsert(ctx context.Context, p *domain.Policy) error {
 	return s.db.QueryRow(ctx,
-		`INSERT INTO memory_policies (agent_id, memory_type, max_memories, retention_days, priority_weight, auto_summarize)
-		 VALUES ($1, $2, $3, $4, $5, $6)
+		`INSERT INTO memory_policies (agent_id, memory_type, max_memories, retention_days, priority_weight, auto_summarize, belief_update)
+		 VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'fixed'))
 		 ON CONFLICT (agent_id, memory_type)
 		 DO UPDATE SET max_memories = EXCLUDED.max_memories,
 		               retention_days = EXCLUDED.retention_days,
 		               priority_weight = EXCLUDED.priority_weight,
 		               auto_summarize = EXCLUDED.auto_summarize,
+		               belief_update = EXCLUDED.belief_update,
 		               updated_at = NOW()
-		 RETURNING id, created_at, updated_at`,
-		p.AgentID, p.MemoryType, p.MaxMemories, p.RetentionDays, p.PriorityWeight, p.AutoSummarize,
-	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+		 RETURNING id, belief_update, created_at, updated_at`,
+		p.AgentID, p.MemoryType, p.MaxMemories, p.RetentionDays, p.PriorityWeight, p.AutoSummarize, p.BeliefUpdate,
+	).Scan(&p.ID, &p.BeliefUpdate, &p.CreatedAt, &p.UpdatedAt)
 }
 
 func (s *PolicyStore) GetByAgentID(ctx context.Context, agentID uuid.UUID) ([]domain.Policy, error) {
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, memory_type, max_memories, retention_days, priority_weight, auto_summarize, created_at, updated_at
+		`SELECT id, agent_id, memory_type, max_memories, retention_days, priority_weight, auto_summarize, belief_update, created_at, updated_at
 		 FROM memory_policies WHERE agent_id = $1
 		 ORDER BY memory_type`,
 		agentID,
@@ -48,7 +48,7 @@ func (s *PolicyStore) GetByAgentID(ctx context.Context, agentID uuid.UUID) ([]do
 	var policies []domain.Policy
 	for rows.Next() {
 		var p domain.Policy
-		if err := rows.Scan(&p.ID, &p.AgentID, &p.MemoryType, &p.MaxMemories, &p.RetentionDays, &p.PriorityWeight, &p.AutoSummarize, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.AgentID, &p.MemoryType, &p.MaxMemories, &p.RetentionDays, &p.PriorityWeight, &p.AutoSummarize, &p.BeliefUpdate, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		policies = append(policies, p)
@@ -59,10 +59,10 @@ func (s *PolicyStore) GetByAgentID(ctx context.Context, agentID uuid.UUID) ([]do
 func (s *PolicyStore) GetByAgentIDAndType(ctx context.Context, agentID uuid.UUID, memType domain.MemoryType) (*domain.Policy, error) {
 	p := &domain.Policy{}
 	err := s.db.QueryRow(ctx,
-		`SELECT id, agent_id, memory_type, max_memories, retention_days, priority_weight, auto_summarize, created_at, updated_at
+		`SELECT id, agent_id, memory_type, max_memories, retention_days, priority_weight, auto_summarize, belief_update, created_at, updated_at
 		 FROM memory_policies WHERE agent_id = $1 AND memory_type = $2`,
 		agentID, memType,
-	).Scan(&p.ID, &p.AgentID, &p.MemoryType, &p.MaxMemories, &p.RetentionDays, &p.PriorityWeight, &p.AutoSummarize, &p.CreatedAt, &p.UpdatedAt)
+	).Scan(&p.ID, &p.AgentID, &p.MemoryType, &p.MaxMemories, &p.RetentionDays, &p.PriorityWeight, &p.AutoSummarize, &p.BeliefUpdate, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolOptions sizes the pgx pool and picks how it executes queries.
+type PoolOptions struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+
+	// QueryExecMode is a pgx default_query_exec_mode name ("cache_statement",
+	// "cache_describe", "describe_exec", "exec", "simple_protocol"). Empty keeps
+	// the one in the database URL, or "exec" behind PgBouncer.
+	QueryExecMode string
+
+	// PgBouncer says the pool talks to PgBouncer in transaction pooling mode,
+	// where consecutive transactions on one client connection can land on
+	// different server connections. Statements pgx prepares and caches per
+	// connection then go missing, and session settings made when a connection
+	// opens do not follow it. The store itself only uses transaction-scoped
+	// state (SET LOCAL, set_config(..., true), advisory xact locks), which is
+	// safe in that mode.
+	PgBouncer bool
+
+	// EfSearch and IVFFlatProbes are applied to each new connection with SET.
+	// Behind PgBouncer they are skipped: set them on the database role instead.
+	EfSearch      int
+	IVFFlatProbes int
+}
+
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// NewPoolConfig parses dbURL and applies o to the resulting pool config.
+func NewPoolConfig(dbURL string, o PoolOptions) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := o.QueryExecMode
+	if mode == "" && o.PgBouncer {
+		mode = "exec"
+	}
+	if mode != "" {
+		m, ok := queryExecModes[mode]
+		if !ok {
+			return nil, fmt.Errorf("unknown query exec mode %q", mode)
+		}
+		cfg.ConnConfig.DefaultQueryExecMode = m
+	}
+
+	if o.MaxConns > 0 {
+		cfg.MaxConns = o.MaxConns
+	}
+	if o.MinConns > 0 {
+		cfg.MinConns = min(o.MinConns, cfg.MaxConns)
+	}
+	cfg.MaxConnLifetime = o.MaxConnLifetime
+	cfg.MaxConnIdleTime = o.MaxConnIdleTime
+
+	if o.PgBouncer || (o.EfSearch <= 0 && o.IVFFlatProbes <= 0) {
+		return cfg, nil
+	}
+	ef, probes := o.EfSearch, o.IVFFlatProbes
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		if ef > 0 {
+			if _, err := conn.Exec(ctx, fmt.Sprintf("SET hnsw.ef_search = %d", ef)); err != nil {
+				return err
+			}
+		}
+		if probes > 0 {
+			if _, err := conn.Exec(ctx, fmt.Sprintf("SET ivfflat.probes = %d", probes)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return cfg, nil
+}
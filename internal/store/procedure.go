@@ -9,15 +9,14 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	pgvector "github.com/pgvector/pgvector-go"
 )
 
 type ProcedureStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewProcedureStore(db *pgxpool.Pool) *ProcedureStore {
+func NewProcedureStore(db DB) *ProcedureStore {
 	return &ProcedureStore{db: db}
 }
 
@@ -28,6 +27,8 @@ func (s *ProcedureStore) Create(ctx context.Context, p *domain.Procedure) error
 		triggerEmbedding = &v
 	}
 
+	// Keywords are stored normalized so the keyword index matches TriggerTerms.
+	p.TriggerKeywords = domain.NormalizeTriggerKeywords(p.TriggerKeywords)
 	triggerKeywordsJSON, err := json.Marshal(p.TriggerKeywords)
 	if err != nil {
 		return fmt.Errorf("marshal trigger_keywords: %w", err)
@@ -38,6 +39,14 @@ func (s *ProcedureStore) Create(ctx context.Context, p *domain.Procedure) error
 		return fmt.Errorf("marshal example_exchanges: %w", err)
 	}
 
+	var conditionsJSON []byte
+	if !p.Conditions.IsZero() {
+		conditionsJSON, err = json.Marshal(p.Conditions)
+		if err != nil {
+			return fmt.Errorf("marshal conditions: %w", err)
+		}
+	}
+
 	// Set defaults
 	if p.Confidence == 0 {
 		p.Confidence = 0.5
@@ -48,34 +57,38 @@ func (s *ProcedureStore) Create(ctx context.Context, p *domain.Procedure) error
 	if p.Version == 0 {
 		p.Version = 1
 	}
+	if p.Source == "" {
+		p.Source = domain.ProcedureSourceLearned
+	}
 
 	return s.db.QueryRow(ctx,
 		`INSERT INTO procedures (
 			agent_id, tenant_id, trigger_pattern, trigger_keywords, trigger_embedding,
 			action_template, action_type, use_count, success_count, failure_count,
-			last_used_at, derived_from_episodes, example_exchanges,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
 			confidence, memory_strength, last_verified_at, version, previous_version_id
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9, $10,
-			$11, $12, $13,
+			$11, $12, $13, $19, $20, $21,
 			$14, $15, $16, $17, $18
 		) RETURNING id, created_at, updated_at`,
 		p.AgentID, p.TenantID, p.TriggerPattern, triggerKeywordsJSON, triggerEmbedding,
 		p.ActionTemplate, p.ActionType, p.UseCount, p.SuccessCount, p.FailureCount,
 		p.LastUsedAt, p.DerivedFromEpisodes, exampleExchangesJSON,
 		p.Confidence, p.MemoryStrength, p.LastVerifiedAt, p.Version, p.PreviousVersionID,
+		conditionsJSON, p.Source, p.SourcePack,
 	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
 }
 
 func (s *ProcedureStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.Procedure, error) {
 	p := &domain.Procedure{}
-	var triggerKeywordsJSON, exampleExchangesJSON []byte
+	var triggerKeywordsJSON, exampleExchangesJSON, conditionsJSON []byte
 
 	err := s.db.QueryRow(ctx,
 		`SELECT id, agent_id, tenant_id, trigger_pattern, trigger_keywords,
 			action_template, action_type, use_count, success_count, failure_count, success_rate,
-			last_used_at, derived_from_episodes, example_exchanges,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
 			confidence, memory_strength, last_verified_at, version, previous_version_id,
 			created_at, updated_at
 		FROM procedures WHERE id = $1 AND tenant_id = $2`,
@@ -83,7 +96,7 @@ func (s *ProcedureStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uui
 	).Scan(
 		&p.ID, &p.AgentID, &p.TenantID, &p.TriggerPattern, &triggerKeywordsJSON,
 		&p.ActionTemplate, &p.ActionType, &p.UseCount, &p.SuccessCount, &p.FailureCount, &p.SuccessRate,
-		&p.LastUsedAt, &p.DerivedFromEpisodes, &exampleExchangesJSON,
+		&p.LastUsedAt, &p.DerivedFromEpisodes, &exampleExchangesJSON, &conditionsJSON, &p.Source, &p.SourcePack,
 		&p.Confidence, &p.MemoryStrength, &p.LastVerifiedAt, &p.Version, &p.PreviousVersionID,
 		&p.CreatedAt, &p.UpdatedAt,
 	)
@@ -104,6 +117,11 @@ func (s *ProcedureStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uui
 			return nil, fmt.Errorf("unmarshal example_exchanges: %w", err)
 		}
 	}
+	if len(conditionsJSON) > 0 {
+		if err := json.Unmarshal(conditionsJSON, &p.Conditions); err != nil {
+			return nil, fmt.Errorf("unmarshal conditions: %w", err)
+		}
+	}
 
 	return p, nil
 }
@@ -112,7 +130,7 @@ func (s *ProcedureStore) GetByAgent(ctx context.Context, agentID uuid.UUID, tena
 	rows, err := s.db.Query(ctx,
 		`SELECT id, agent_id, tenant_id, trigger_pattern, trigger_keywords,
 			action_template, action_type, use_count, success_count, failure_count, success_rate,
-			last_used_at, derived_from_episodes, example_exchanges,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
 			confidence, memory_strength, last_verified_at, version, previous_version_id,
 			created_at, updated_at
 		FROM procedures WHERE agent_id = $1 AND tenant_id = $2
@@ -137,7 +155,7 @@ func (s *ProcedureStore) FindByTriggerSimilarity(ctx context.Context, agentID uu
 	rows, err := s.db.Query(ctx,
 		`SELECT id, agent_id, tenant_id, trigger_pattern, trigger_keywords,
 			action_template, action_type, use_count, success_count, failure_count, success_rate,
-			last_used_at, derived_from_episodes, example_exchanges,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
 			confidence, memory_strength, last_verified_at, version, previous_version_id,
 			created_at, updated_at,
 			1 - (trigger_embedding <=> $1) AS score
@@ -152,15 +170,52 @@ func (s *ProcedureStore) FindByTriggerSimilarity(ctx context.Context, agentID uu
 	}
 	defer rows.Close()
 
+	return scanProceduresWithScore(rows)
+}
+
+// FindByTriggerKeywords finds procedures with a trigger keyword among terms
+// (see domain.TriggerTerms) through the keyword index. A procedure scores the
+// fraction of its keywords found.
+func (s *ProcedureStore) FindByTriggerKeywords(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID, terms []string, limit int) ([]domain.ProcedureWithScore, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, trigger_pattern, trigger_keywords,
+			action_template, action_type, use_count, success_count, failure_count, success_rate,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
+			confidence, memory_strength, last_verified_at, version, previous_version_id,
+			created_at, updated_at,
+			(SELECT COUNT(*) FROM jsonb_array_elements_text(trigger_keywords) k WHERE k = ANY($3))::real
+				/ GREATEST(jsonb_array_length(trigger_keywords), 1) AS score
+		FROM procedures
+		WHERE agent_id = $1 AND tenant_id = $2 AND trigger_keywords ?| $3
+		ORDER BY score DESC, confidence DESC
+		LIMIT $4`,
+		agentID, tenantID, terms, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find procedures by keyword query: %w", err)
+	}
+	defer rows.Close()
+
+	return scanProceduresWithScore(rows)
+}
+
+func scanProceduresWithScore(rows pgx.Rows) ([]domain.ProcedureWithScore, error) {
 	var results []domain.ProcedureWithScore
 	for rows.Next() {
 		var p domain.ProcedureWithScore
-		var triggerKeywordsJSON, exampleExchangesJSON []byte
+		var triggerKeywordsJSON, exampleExchangesJSON, conditionsJSON []byte
 
 		err := rows.Scan(
 			&p.ID, &p.AgentID, &p.TenantID, &p.TriggerPattern, &triggerKeywordsJSON,
 			&p.ActionTemplate, &p.ActionType, &p.UseCount, &p.SuccessCount, &p.FailureCount, &p.SuccessRate,
-			&p.LastUsedAt, &p.DerivedFromEpisodes, &exampleExchangesJSON,
+			&p.LastUsedAt, &p.DerivedFromEpisodes, &exampleExchangesJSON, &conditionsJSON, &p.Source, &p.SourcePack,
 			&p.Confidence, &p.MemoryStrength, &p.LastVerifiedAt, &p.Version, &p.PreviousVersionID,
 			&p.CreatedAt, &p.UpdatedAt,
 			&p.Score,
@@ -175,6 +230,9 @@ func (s *ProcedureStore) FindByTriggerSimilarity(ctx context.Context, agentID uu
 		if len(exampleExchangesJSON) > 0 {
 			_ = json.Unmarshal(exampleExchangesJSON, &p.ExampleExchanges)
 		}
+		if len(conditionsJSON) > 0 {
+			_ = json.Unmarshal(conditionsJSON, &p.Conditions)
+		}
 
 		results = append(results, p)
 	}
@@ -182,6 +240,28 @@ func (s *ProcedureStore) FindByTriggerSimilarity(ctx context.Context, agentID uu
 	return results, rows.Err()
 }
 
+// FindConditional returns the agent's live procedures that have structured
+// conditions, for evaluation at activation time.
+func (s *ProcedureStore) FindConditional(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]domain.Procedure, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, trigger_pattern, trigger_keywords,
+			action_template, action_type, use_count, success_count, failure_count, success_rate,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
+			confidence, memory_strength, last_verified_at, version, previous_version_id,
+			created_at, updated_at
+		FROM procedures
+		WHERE agent_id = $1 AND tenant_id = $2 AND conditions IS NOT NULL AND memory_strength > 0
+		ORDER BY confidence DESC`,
+		agentID, tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find conditional procedures query: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanProcedures(rows)
+}
+
 func (s *ProcedureStore) RecordUse(ctx context.Context, id uuid.UUID, success bool) error {
 	var query string
 	if success {
@@ -262,7 +342,7 @@ func (s *ProcedureStore) GetByAgentForDecay(ctx context.Context, agentID uuid.UU
 	rows, err := s.db.Query(ctx,
 		`SELECT id, agent_id, tenant_id, trigger_pattern, trigger_keywords,
 			action_template, action_type, use_count, success_count, failure_count, success_rate,
-			last_used_at, derived_from_episodes, example_exchanges,
+			last_used_at, derived_from_episodes, example_exchanges, conditions, source, source_pack,
 			confidence, memory_strength, last_verified_at, version, previous_version_id,
 			created_at, updated_at
 		FROM procedures
@@ -303,12 +383,12 @@ func (s *ProcedureStore) scanProcedures(rows pgx.Rows) ([]domain.Procedure, erro
 	var procedures []domain.Procedure
 	for rows.Next() {
 		var p domain.Procedure
-		var triggerKeywordsJSON, exampleExchangesJSON []byte
+		var triggerKeywordsJSON, exampleExchangesJSON, conditionsJSON []byte
 
 		err := rows.Scan(
 			&p.ID, &p.AgentID, &p.TenantID, &p.TriggerPattern, &triggerKeywordsJSON,
 			&p.ActionTemplate, &p.ActionType, &p.UseCount, &p.SuccessCount, &p.FailureCount, &p.SuccessRate,
-			&p.LastUsedAt, &p.DerivedFromEpisodes, &exampleExchangesJSON,
+			&p.LastUsedAt, &p.DerivedFromEpisodes, &exampleExchangesJSON, &conditionsJSON, &p.Source, &p.SourcePack,
 			&p.Confidence, &p.MemoryStrength, &p.LastVerifiedAt, &p.Version, &p.PreviousVersionID,
 			&p.CreatedAt, &p.UpdatedAt,
 		)
@@ -322,6 +402,9 @@ func (s *ProcedureStore) scanProcedures(rows pgx.Rows) ([]domain.Procedure, erro
 		if len(exampleExchangesJSON) > 0 {
 			_ = json.Unmarshal(exampleExchangesJSON, &p.ExampleExchanges)
 		}
+		if len(conditionsJSON) > 0 {
+			_ = json.Unmarshal(conditionsJSON, &p.Conditions)
+		}
 
 		procedures = append(procedures, p)
 	}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+type RecallLogStore struct {
+	db DB
+}
+
+func NewRecallLogStore(db DB) *RecallLogStore {
+	return &RecallLogStore{db: db}
+}
+
+func (s *RecallLogStore) Create(ctx context.Context, l *domain.RecallLog) error {
+	resultsJSON, err := json.Marshal(l.Results)
+	if err != nil {
+		return fmt.Errorf("marshal results: %w", err)
+	}
+
+	return s.db.QueryRow(ctx,
+		`INSERT INTO recall_logs (tenant_id, agent_id, query_hash, mode, scorer_version, top_k, vector_weight, graph_weight, latency_ms, control, results)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 RETURNING id, created_at`,
+		l.TenantID, l.AgentID, l.QueryHash, string(l.Mode), l.ScorerVersion, l.TopK, l.VectorWeight, l.GraphWeight, l.LatencyMs, l.Control, resultsJSON,
+	).Scan(&l.ID, &l.CreatedAt)
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// RetentionBatchSize caps how many rows one rule deletes per Apply call, so a
+// newly added rule over a large backlog is worked off across sweeps instead of
+// in one long transaction.
+const RetentionBatchSize = 5000
+
+type RetentionStore struct {
+	db DB
+}
+
+func NewRetentionStore(db DB) *RetentionStore {
+	return &RetentionStore{db: db}
+}
+
+const retentionRuleColumns = `id, tenant_id, target, memory_type, after_days, created_at, updated_at`
+
+func scanRetentionRules(rows pgx.Rows) ([]domain.RetentionRule, error) {
+	defer rows.Close()
+	var rules []domain.RetentionRule
+	for rows.Next() {
+		var r domain.RetentionRule
+		var memType *string
+		if err := rows.Scan(&r.ID, &r.TenantID, &r.Target, &memType, &r.AfterDays, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if memType != nil {
+			t := domain.MemoryType(*memType)
+			r.MemoryType = &t
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// Upsert creates the rule, or updates after_days of the tenant's existing rule
+// for the same target and memory type.
+func (s *RetentionStore) Upsert(ctx context.Context, r *domain.RetentionRule) error {
+	return s.db.QueryRow(ctx,
+		`INSERT INTO retention_rules (tenant_id, target, memory_type, after_days)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (tenant_id, target, COALESCE(memory_type, ''))
+		 DO UPDATE SET after_days = EXCLUDED.after_days, updated_at = NOW()
+		 RETURNING id, created_at, updated_at`,
+		r.TenantID, r.Target, retentionMemoryType(r), r.AfterDays,
+	).Scan(&r.ID, &r.CreatedAt, &r.UpdatedAt)
+}
+
+func (s *RetentionStore) List(ctx context.Context, tenantID uuid.UUID) ([]domain.RetentionRule, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+retentionRuleColumns+` FROM retention_rules WHERE tenant_id = $1 ORDER BY target, memory_type NULLS FIRST`,
+		tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRetentionRules(rows)
+}
+
+// ListAll returns every tenant's rules, for the expirer.
+func (s *RetentionStore) ListAll(ctx context.Context) ([]domain.RetentionRule, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+retentionRuleColumns+` FROM retention_rules ORDER BY tenant_id, target`)
+	if err != nil {
+		return nil, err
+	}
+	return scanRetentionRules(rows)
+}
+
+func (s *RetentionStore) Delete(ctx context.Context, id, tenantID uuid.UUID) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM retention_rules WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// retentionScope returns the table, the age column and the filter selecting
+// the rows a rule covers. The filter takes $1 tenant, $2 after_days and
+// $3 memory type (NULL for any).
+func retentionScope(target domain.RetentionTarget) (table, ageColumn, where string, err error) {
+	switch target {
+	case domain.RetentionMemories:
+		return "memories", "created_at",
+			`tenant_id = $1 AND is_archived = FALSE AND pinned = FALSE
+			 AND created_at < NOW() - make_interval(days => $2)
+			 AND ($3::text IS NULL OR type = $3)`, nil
+	case domain.RetentionArchivedMemories:
+		return "memories", "COALESCE(archived_at, updated_at)",
+			`tenant_id = $1 AND is_archived = TRUE
+			 AND COALESCE(archived_at, updated_at) < NOW() - make_interval(days => $2)
+			 AND ($3::text IS NULL OR type = $3)`, nil
+	case domain.RetentionAbstractedEpisodes:
+		return "episodes", "last_consolidated_at",
+			`tenant_id = $1 AND consolidation_status IN ('abstracted', 'archived')
+			 AND last_consolidated_at < NOW() - make_interval(days => $2)
+			 AND $3::text IS NULL`, nil
+	}
+	return "", "", "", fmt.Errorf("unknown retention target %q", target)
+}
+
+func retentionMemoryType(r *domain.RetentionRule) *string {
+	if r.MemoryType == nil {
+		return nil
+	}
+	t := string(*r.MemoryType)
+	return &t
+}
+
+// Preview reports how many rows the rule would delete now, the oldest one's
+// age timestamp, and up to sample of their IDs, oldest first.
+func (s *RetentionStore) Preview(ctx context.Context, r *domain.RetentionRule, sample int) (*domain.RetentionPreview, error) {
+	table, age, where, err := retentionScope(r.Target)
+	if err != nil {
+		return nil, err
+	}
+	args := []any{r.TenantID, r.AfterDays, retentionMemoryType(r)}
+
+	p := &domain.RetentionPreview{Rule: *r, SampleIDs: []uuid.UUID{}}
+	if err := s.db.QueryRow(ctx,
+		fmt.Sprintf(`SELECT COUNT(*), MIN(%s) FROM %s WHERE %s`, age, table, where), args...,
+	).Scan(&p.Count, &p.OldestAt); err != nil {
+		return nil, err
+	}
+	if p.Count == 0 || sample <= 0 {
+		return p, nil
+	}
+
+	rows, err := s.db.Query(ctx,
+		fmt.Sprintf(`SELECT id FROM %s WHERE %s ORDER BY %s LIMIT %d`, table, where, age, sample), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		p.SampleIDs = append(p.SampleIDs, id)
+	}
+	return p, rows.Err()
+}
+
+// Apply deletes up to RetentionBatchSize rows covered by the rule, oldest
+// first. Memory deletions are snapshotted into the audit chain first, like
+// every other removal.
+func (s *RetentionStore) Apply(ctx context.Context, r *domain.RetentionRule) (int64, error) {
+	table, age, where, err := retentionScope(r.Target)
+	if err != nil {
+		return 0, err
+	}
+	args := []any{r.TenantID, r.AfterDays, retentionMemoryType(r)}
+	batch := fmt.Sprintf(`id IN (SELECT id FROM %s WHERE %s ORDER BY %s, id LIMIT %d)`, table, where, age, RetentionBatchSize)
+
+	var affected int64
+	err = WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		if table == "memories" {
+			reason := fmt.Sprintf("deletion: retention rule %s (%d days)", r.Target, r.AfterDays)
+			if err := snapshotMemoriesForRemoval(ctx, tx, domain.MutationDeletion, reason, true, batch, args...); err != nil {
+				return err
+			}
+		}
+		tag, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE `+batch, args...)
+		if err != nil {
+			return err
+		}
+		affected = tag.RowsAffected()
+		return nil
+	})
+	return affected, err
+}
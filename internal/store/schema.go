@@ -9,15 +9,14 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	pgvector "github.com/pgvector/pgvector-go"
 )
 
 type SchemaStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewSchemaStore(db *pgxpool.Pool) *SchemaStore {
+func NewSchemaStore(db DB) *SchemaStore {
 	return &SchemaStore{db: db}
 }
 
@@ -47,15 +46,18 @@ func (s *SchemaStore) Create(ctx context.Context, schema *domain.Schema) error {
 		`INSERT INTO schemas (
 			agent_id, tenant_id, schema_type, name, description,
 			attributes, evidence_memories, evidence_episodes, evidence_count,
-			confidence, last_validated_at, contradiction_count, applicable_contexts, embedding
+			confidence, last_validated_at, contradiction_count, applicable_contexts, embedding,
+			parent_id
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9,
-			$10, $11, $12, $13, $14
+			$10, $11, $12, $13, $14,
+			$15
 		) RETURNING id, created_at, updated_at`,
 		schema.AgentID, schema.TenantID, schema.SchemaType, schema.Name, schema.Description,
 		attributesJSON, schema.EvidenceMemories, schema.EvidenceEpisodes, schema.EvidenceCount,
 		schema.Confidence, schema.LastValidatedAt, schema.ContradictionCount, applicableContextsJSON, embedding,
+		schema.ParentID,
 	).Scan(&schema.ID, &schema.CreatedAt, &schema.UpdatedAt)
 }
 
@@ -64,14 +66,14 @@ func (s *SchemaStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.U
 	var attributesJSON, applicableContextsJSON []byte
 
 	err := s.db.QueryRow(ctx,
-		`SELECT id, agent_id, tenant_id, schema_type, name, description,
+		`SELECT id, agent_id, tenant_id, parent_id, schema_type, name, description,
 			attributes, evidence_memories, evidence_episodes, evidence_count,
 			confidence, last_validated_at, contradiction_count, applicable_contexts,
 			created_at, updated_at
 		FROM schemas WHERE id = $1 AND tenant_id = $2`,
 		id, tenantID,
 	).Scan(
-		&schema.ID, &schema.AgentID, &schema.TenantID, &schema.SchemaType, &schema.Name, &schema.Description,
+		&schema.ID, &schema.AgentID, &schema.TenantID, &schema.ParentID, &schema.SchemaType, &schema.Name, &schema.Description,
 		&attributesJSON, &schema.EvidenceMemories, &schema.EvidenceEpisodes, &schema.EvidenceCount,
 		&schema.Confidence, &schema.LastValidatedAt, &schema.ContradictionCount, &applicableContextsJSON,
 		&schema.CreatedAt, &schema.UpdatedAt,
@@ -99,7 +101,7 @@ func (s *SchemaStore) GetByID(ctx context.Context, id uuid.UUID, tenantID uuid.U
 
 func (s *SchemaStore) GetByAgent(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) ([]domain.Schema, error) {
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, tenant_id, schema_type, name, description,
+		`SELECT id, agent_id, tenant_id, parent_id, schema_type, name, description,
 			attributes, evidence_memories, evidence_episodes, evidence_count,
 			confidence, last_validated_at, contradiction_count, applicable_contexts,
 			created_at, updated_at
@@ -120,14 +122,14 @@ func (s *SchemaStore) GetByName(ctx context.Context, agentID uuid.UUID, tenantID
 	var attributesJSON, applicableContextsJSON []byte
 
 	err := s.db.QueryRow(ctx,
-		`SELECT id, agent_id, tenant_id, schema_type, name, description,
+		`SELECT id, agent_id, tenant_id, parent_id, schema_type, name, description,
 			attributes, evidence_memories, evidence_episodes, evidence_count,
 			confidence, last_validated_at, contradiction_count, applicable_contexts,
 			created_at, updated_at
 		FROM schemas WHERE agent_id = $1 AND tenant_id = $2 AND schema_type = $3 AND name = $4`,
 		agentID, tenantID, schemaType, name,
 	).Scan(
-		&schema.ID, &schema.AgentID, &schema.TenantID, &schema.SchemaType, &schema.Name, &schema.Description,
+		&schema.ID, &schema.AgentID, &schema.TenantID, &schema.ParentID, &schema.SchemaType, &schema.Name, &schema.Description,
 		&attributesJSON, &schema.EvidenceMemories, &schema.EvidenceEpisodes, &schema.EvidenceCount,
 		&schema.Confidence, &schema.LastValidatedAt, &schema.ContradictionCount, &applicableContextsJSON,
 		&schema.CreatedAt, &schema.UpdatedAt,
@@ -175,7 +177,7 @@ func (s *SchemaStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenant
 	vec := pgvector.NewVector(embedding)
 
 	rows, err := s.db.Query(ctx,
-		`SELECT id, agent_id, tenant_id, schema_type, name, description,
+		`SELECT id, agent_id, tenant_id, parent_id, schema_type, name, description,
 			attributes, evidence_memories, evidence_episodes, evidence_count,
 			confidence, last_validated_at, contradiction_count, applicable_contexts,
 			created_at, updated_at,
@@ -197,7 +199,7 @@ func (s *SchemaStore) FindSimilar(ctx context.Context, agentID uuid.UUID, tenant
 		var attributesJSON, applicableContextsJSON []byte
 
 		err := rows.Scan(
-			&s.ID, &s.AgentID, &s.TenantID, &s.SchemaType, &s.Name, &s.Description,
+			&s.ID, &s.AgentID, &s.TenantID, &s.ParentID, &s.SchemaType, &s.Name, &s.Description,
 			&attributesJSON, &s.EvidenceMemories, &s.EvidenceEpisodes, &s.EvidenceCount,
 			&s.Confidence, &s.LastValidatedAt, &s.ContradictionCount, &applicableContextsJSON,
 			&s.CreatedAt, &s.UpdatedAt,
@@ -342,6 +344,8 @@ func (s *SchemaStore) UpdateValidation(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// Update overwrites a schema's mutable fields. A nil embedding keeps the
+// stored one so partial updates don't drop similarity matching.
 func (s *SchemaStore) Update(ctx context.Context, schema *domain.Schema) error {
 	var embedding *pgvector.Vector
 	if len(schema.Embedding) > 0 {
@@ -364,7 +368,7 @@ func (s *SchemaStore) Update(ctx context.Context, schema *domain.Schema) error {
 			schema_type = $1, name = $2, description = $3,
 			attributes = $4, evidence_memories = $5, evidence_episodes = $6, evidence_count = $7,
 			confidence = $8, last_validated_at = $9, contradiction_count = $10,
-			applicable_contexts = $11, embedding = $12, updated_at = NOW()
+			applicable_contexts = $11, embedding = COALESCE($12, embedding), updated_at = NOW()
 		WHERE id = $13 AND tenant_id = $14`,
 		schema.SchemaType, schema.Name, schema.Description,
 		attributesJSON, schema.EvidenceMemories, schema.EvidenceEpisodes, schema.EvidenceCount,
@@ -380,6 +384,107 @@ func (s *SchemaStore) Update(ctx context.Context, schema *domain.Schema) error {
 	return nil
 }
 
+// SetParent moves a schema under parentID in the hierarchy, or detaches it
+// when parentID is nil. The parent must belong to the same agent and tenant.
+// Returns ErrCycle if parentID is the schema itself or one of its descendants.
+func (s *SchemaStore) SetParent(ctx context.Context, id uuid.UUID, tenantID uuid.UUID, parentID *uuid.UUID) error {
+	return WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		// Lock the agent's schemas so concurrent re-parenting can't race two
+		// individually-valid moves into a cycle.
+		var agentID uuid.UUID
+		err := tx.QueryRow(ctx,
+			`SELECT agent_id FROM schemas WHERE id = $1 AND tenant_id = $2`,
+			id, tenantID,
+		).Scan(&agentID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`SELECT id FROM schemas WHERE agent_id = $1 AND tenant_id = $2 FOR UPDATE`,
+			agentID, tenantID,
+		); err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			if *parentID == id {
+				return ErrCycle
+			}
+
+			var exists bool
+			if err := tx.QueryRow(ctx,
+				`SELECT EXISTS (SELECT 1 FROM schemas WHERE id = $1 AND agent_id = $2 AND tenant_id = $3)`,
+				*parentID, agentID, tenantID,
+			).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return ErrNotFound
+			}
+
+			// Walk up from the proposed parent; reaching the schema itself means
+			// the move would close a loop.
+			var cycle bool
+			err := tx.QueryRow(ctx,
+				`WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM schemas WHERE id = $1 AND tenant_id = $2
+					UNION
+					SELECT sc.id, sc.parent_id
+					FROM schemas sc
+					JOIN ancestors a ON sc.id = a.parent_id
+					WHERE sc.tenant_id = $2
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $3)`,
+				*parentID, tenantID, id,
+			).Scan(&cycle)
+			if err != nil {
+				return fmt.Errorf("check schema ancestry: %w", err)
+			}
+			if cycle {
+				return ErrCycle
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE schemas SET parent_id = $1, updated_at = NOW() WHERE id = $2 AND tenant_id = $3`,
+			parentID, id, tenantID,
+		)
+		return err
+	})
+}
+
+func (s *SchemaStore) GetPassState(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*domain.SchemaPassState, error) {
+	state := &domain.SchemaPassState{}
+	err := s.db.QueryRow(ctx,
+		`SELECT agent_id, tenant_id, last_pass_at, last_full_pass_at, incremental_passes
+		 FROM schema_passes WHERE agent_id = $1 AND tenant_id = $2`,
+		agentID, tenantID,
+	).Scan(&state.AgentID, &state.TenantID, &state.LastPassAt, &state.LastFullPassAt, &state.IncrementalPasses)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *SchemaStore) RecordPass(ctx context.Context, state *domain.SchemaPassState) error {
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO schema_passes (agent_id, tenant_id, last_pass_at, last_full_pass_at, incremental_passes)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (agent_id) DO UPDATE SET
+			last_pass_at = EXCLUDED.last_pass_at,
+			last_full_pass_at = EXCLUDED.last_full_pass_at,
+			incremental_passes = EXCLUDED.incremental_passes`,
+		state.AgentID, state.TenantID, state.LastPassAt, state.LastFullPassAt, state.IncrementalPasses,
+	)
+	return err
+}
+
 func (s *SchemaStore) scanSchemas(rows pgx.Rows) ([]domain.Schema, error) {
 	var schemas []domain.Schema
 	for rows.Next() {
@@ -387,7 +492,7 @@ func (s *SchemaStore) scanSchemas(rows pgx.Rows) ([]domain.Schema, error) {
 		var attributesJSON, applicableContextsJSON []byte
 
 		err := rows.Scan(
-			&schema.ID, &schema.AgentID, &schema.TenantID, &schema.SchemaType, &schema.Name, &schema.Description,
+			&schema.ID, &schema.AgentID, &schema.TenantID, &schema.ParentID, &schema.SchemaType, &schema.Name, &schema.Description,
 			&attributesJSON, &schema.EvidenceMemories, &schema.EvidenceEpisodes, &schema.EvidenceCount,
 			&schema.Confidence, &schema.LastValidatedAt, &schema.ContradictionCount, &applicableContextsJSON,
 			&schema.CreatedAt, &schema.UpdatedAt,
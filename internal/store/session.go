@@ -8,14 +8,13 @@ import (
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type SessionStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewSessionStore(db *pgxpool.Pool) *SessionStore {
+func NewSessionStore(db DB) *SessionStore {
 	return &SessionStore{db: db}
 }
 
@@ -108,3 +107,25 @@ func (s *SessionStore) MarkExpired(ctx context.Context, id uuid.UUID) error {
 	_, err := s.db.Exec(ctx, `UPDATE sessions SET status = 'expired' WHERE id = $1`, id)
 	return err
 }
+
+// ListByAnchor returns every session of an anchor, oldest first.
+func (s *SessionStore) ListByAnchor(ctx context.Context, anchorID, tenantID uuid.UUID) ([]domain.Session, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+sessionCols+` FROM sessions WHERE anchor_id = $1 AND tenant_id = $2
+		 ORDER BY started_at`,
+		anchorID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var sessions []domain.Session
+	for rows.Next() {
+		sess, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *sess)
+	}
+	return sessions, rows.Err()
+}
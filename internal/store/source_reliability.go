@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+type SourceReliabilityStore struct {
+	db DB
+}
+
+func NewSourceReliabilityStore(db DB) *SourceReliabilityStore {
+	return &SourceReliabilityStore{db: db}
+}
+
+func (s *SourceReliabilityStore) Record(ctx context.Context, agentID, tenantID uuid.UUID, source domain.BeliefSource, correct, incorrect float64) error {
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO source_reliability AS r (agent_id, tenant_id, source, correct, incorrect)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (agent_id, source) DO UPDATE SET
+		     correct    = r.correct + EXCLUDED.correct,
+		     incorrect  = r.incorrect + EXCLUDED.incorrect,
+		     updated_at = NOW()`,
+		agentID, tenantID, string(source), correct, incorrect,
+	)
+	return err
+}
+
+func (s *SourceReliabilityStore) ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.SourceReliability, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT agent_id, tenant_id, source, correct, incorrect, updated_at
+		 FROM source_reliability WHERE agent_id = $1 AND tenant_id = $2
+		 ORDER BY source`,
+		agentID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.SourceReliability
+	for rows.Next() {
+		var r domain.SourceReliability
+		var source string
+		if err := rows.Scan(&r.AgentID, &r.TenantID, &source, &r.Correct, &r.Incorrect, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		r.Source = domain.BeliefSource(source)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
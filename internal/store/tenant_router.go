@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultDatabase names the primary database. It holds the control plane
+// (tenants, users, API keys, billing) and the data of every tenant not routed
+// elsewhere.
+const DefaultDatabase = "default"
+
+type pinnedDatabaseKey struct{}
+
+// TenantRouter sends each query to the database that holds the calling
+// tenant's data, so large customers can live on their own Postgres (or in
+// their own region) behind a single deployment. The tenant comes from
+// domain.TenantIDFrom; work with no tenant, such as a background sweep, runs
+// against the database pinned with WithDatabase, or the default one.
+//
+// Databases and routes are registered at startup, before the router is
+// shared; they are not safe to change while queries are running.
+type TenantRouter struct {
+	def    *pgxpool.Pool
+	dbs    map[string]*pgxpool.Pool
+	routes map[uuid.UUID]string
+}
+
+// NewTenantRouter returns a router that sends everything to def until
+// databases and routes are added.
+func NewTenantRouter(def *pgxpool.Pool) *TenantRouter {
+	return &TenantRouter{
+		def:    def,
+		dbs:    map[string]*pgxpool.Pool{},
+		routes: map[uuid.UUID]string{},
+	}
+}
+
+// Default returns the default database's pool.
+func (r *TenantRouter) Default() *pgxpool.Pool {
+	return r.def
+}
+
+// AddDatabase registers a named tenant database.
+func (r *TenantRouter) AddDatabase(name string, pool *pgxpool.Pool) error {
+	if name == "" || name == DefaultDatabase {
+		return fmt.Errorf("invalid tenant database name %q", name)
+	}
+	if _, ok := r.dbs[name]; ok {
+		return fmt.Errorf("tenant database %q registered twice", name)
+	}
+	r.dbs[name] = pool
+	return nil
+}
+
+// Route places a tenant's data on the named database.
+func (r *TenantRouter) Route(tenantID uuid.UUID, database string) error {
+	if database == DefaultDatabase {
+		delete(r.routes, tenantID)
+		return nil
+	}
+	if _, ok := r.dbs[database]; !ok {
+		return fmt.Errorf("tenant %s routed to unknown database %q", tenantID, database)
+	}
+	r.routes[tenantID] = database
+	return nil
+}
+
+// Databases returns the database names, the default first.
+func (r *TenantRouter) Databases() []string {
+	names := make([]string, 0, len(r.dbs)+1)
+	for name := range r.dbs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultDatabase}, names...)
+}
+
+// DatabaseFor returns the name of the database holding tenantID's data.
+func (r *TenantRouter) DatabaseFor(tenantID uuid.UUID) string {
+	if name, ok := r.routes[tenantID]; ok {
+		return name
+	}
+	return DefaultDatabase
+}
+
+// WithDatabase pins ctx to a database for work that has no tenant. A tenant
+// on the context still takes precedence.
+func WithDatabase(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, pinnedDatabaseKey{}, name)
+}
+
+// ForEachDatabase runs fn once per database, in Databases order, with ctx
+// pinned to each in turn. Background sweeps use it to cover every tenant.
+func (r *TenantRouter) ForEachDatabase(ctx context.Context, fn func(ctx context.Context)) {
+	for _, name := range r.Databases() {
+		if ctx.Err() != nil {
+			return
+		}
+		fn(WithDatabase(ctx, name))
+	}
+}
+
+// Pool returns the pool a query made with ctx runs against.
+func (r *TenantRouter) Pool(ctx context.Context) *pgxpool.Pool {
+	if id, ok := domain.TenantIDFrom(ctx); ok {
+		if name, ok := r.routes[id]; ok {
+			return r.dbs[name]
+		}
+		return r.def
+	}
+	if name, ok := ctx.Value(pinnedDatabaseKey{}).(string); ok {
+		if pool, ok := r.dbs[name]; ok {
+			return pool
+		}
+	}
+	return r.def
+}
+
+func (r *TenantRouter) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return r.Pool(ctx).Exec(ctx, sql, args...)
+}
+
+func (r *TenantRouter) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return r.Pool(ctx).Query(ctx, sql, args...)
+}
+
+func (r *TenantRouter) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return r.Pool(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (r *TenantRouter) Begin(ctx context.Context) (pgx.Tx, error) {
+	return r.Pool(ctx).Begin(ctx)
+}
+
+// SyncTenants copies the tenants row of every routed tenant from the default
+// database into its own, where the foreign keys on agents and the other
+// tenant-scoped tables need it. Existing rows only have their name refreshed.
+func (r *TenantRouter) SyncTenants(ctx context.Context) error {
+	for id, name := range r.routes {
+		var tenantName string
+		var createdAt time.Time
+		err := r.def.QueryRow(ctx,
+			`SELECT name, created_at FROM tenants WHERE id = $1`, id,
+		).Scan(&tenantName, &createdAt)
+		if err != nil {
+			return fmt.Errorf("load tenant %s: %w", id, err)
+		}
+		if _, err := r.dbs[name].Exec(ctx,
+			`INSERT INTO tenants (id, name, created_at) VALUES ($1, $2, $3)
+			 ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name`,
+			id, tenantName, createdAt,
+		); err != nil {
+			return fmt.Errorf("sync tenant %s to database %q: %w", id, name, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the tenant databases. The default pool belongs to the caller.
+func (r *TenantRouter) Close() {
+	for _, pool := range r.dbs {
+		pool.Close()
+	}
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newLazyPool builds a pool without connecting; pgxpool only dials on first use.
+func newLazyPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), testDBURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestTenantRouter_Pool(t *testing.T) {
+	def, eu := newLazyPool(t), newLazyPool(t)
+	r := NewTenantRouter(def)
+	if err := r.AddDatabase("eu", eu); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	routed, unrouted := uuid.New(), uuid.New()
+	if err := r.Route(routed, "eu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want *pgxpool.Pool
+	}{
+		{"no tenant", ctx, def},
+		{"routed tenant", domain.WithTenantID(ctx, routed), eu},
+		{"unrouted tenant", domain.WithTenantID(ctx, unrouted), def},
+		{"pinned database", WithDatabase(ctx, "eu"), eu},
+		{"tenant wins over pin", domain.WithTenantID(WithDatabase(ctx, "eu"), unrouted), def},
+		{"unknown pin", WithDatabase(ctx, "us"), def},
+	}
+	for _, c := range cases {
+		if got := r.Pool(c.ctx); got != c.want {
+			t.Errorf("%s: routed to the wrong pool", c.name)
+		}
+	}
+	if r.DatabaseFor(routed) != "eu" || r.DatabaseFor(unrouted) != DefaultDatabase {
+		t.Fatalf("unexpected DatabaseFor results")
+	}
+}
+
+func TestTenantRouter_Validation(t *testing.T) {
+	r := NewTenantRouter(newLazyPool(t))
+	if err := r.AddDatabase(DefaultDatabase, newLazyPool(t)); err == nil {
+		t.Fatal("expected the default name to be rejected")
+	}
+	if err := r.AddDatabase("eu", newLazyPool(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.AddDatabase("eu", newLazyPool(t)); err == nil {
+		t.Fatal("expected a duplicate database to be rejected")
+	}
+	if err := r.Route(uuid.New(), "us"); err == nil {
+		t.Fatal("expected a route to an unknown database to be rejected")
+	}
+}
+
+func TestTenantRouter_ForEachDatabase(t *testing.T) {
+	r := NewTenantRouter(newLazyPool(t))
+	for _, name := range []string{"us", "eu"} {
+		if err := r.AddDatabase(name, newLazyPool(t)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var visited []*pgxpool.Pool
+	r.ForEachDatabase(context.Background(), func(ctx context.Context) {
+		visited = append(visited, r.Pool(ctx))
+	})
+	want := []*pgxpool.Pool{r.Default(), r.dbs["eu"], r.dbs["us"]}
+	if len(visited) != len(want) {
+		t.Fatalf("expected %d databases, visited %d", len(want), len(visited))
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Fatalf("expected default, eu, us in order")
+		}
+	}
+}
@@ -4,28 +4,32 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // UnitOfWork runs operations on the audited stores (memory, mutation log,
-// contradiction) atomically within a single transaction, so a state change and
-// its audit-log row commit together or not at all.
+// contradiction) and the consolidation stores (episode, association)
+// atomically within a single transaction, so a state change and its audit-log
+// row, or a derived memory and its episode links, commit together or not at all.
 type UnitOfWork struct {
-	pool          *pgxpool.Pool
+	pool          DB
 	memory        *MemoryStore
 	mutationLog   *MutationLogStore
 	contradiction *ContradictionStore
+	episode       *EpisodeStore
+	association   *MemoryAssociationStore
 }
 
-func NewUnitOfWork(pool *pgxpool.Pool, memory *MemoryStore, mutationLog *MutationLogStore, contradiction *ContradictionStore) *UnitOfWork {
-	return &UnitOfWork{pool: pool, memory: memory, mutationLog: mutationLog, contradiction: contradiction}
+func NewUnitOfWork(pool DB, memory *MemoryStore, mutationLog *MutationLogStore, contradiction *ContradictionStore, episode *EpisodeStore, association *MemoryAssociationStore) *UnitOfWork {
+	return &UnitOfWork{pool: pool, memory: memory, mutationLog: mutationLog, contradiction: contradiction, episode: episode, association: association}
 }
 
-// TxStores exposes the audited stores bound to one transaction.
+// TxStores exposes the stores bound to one transaction.
 type TxStores struct {
 	Memory        *MemoryStore
 	MutationLog   *MutationLogStore
 	Contradiction *ContradictionStore
+	Episode       *EpisodeStore
+	Association   *MemoryAssociationStore
 }
 
 // Do runs fn with transaction-bound stores; all writes commit or roll back together.
@@ -35,6 +39,8 @@ func (u *UnitOfWork) Do(ctx context.Context, fn func(*TxStores) error) error {
 			Memory:        u.memory.withTx(tx),
 			MutationLog:   u.mutationLog.withTx(tx),
 			Contradiction: u.contradiction.withTx(tx),
+			Episode:       u.episode.withTx(tx),
+			Association:   u.association.withTx(tx),
 		})
 	})
 }
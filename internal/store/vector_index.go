@@ -0,0 +1,284 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	pgvector "github.com/pgvector/pgvector-go"
+)
+
+// VectorIndexStore manages the pgvector indexes listed in
+// vector_index_settings. Table, column and index identifiers are always taken
+// from that table, never from the caller, before being formatted into DDL.
+type VectorIndexStore struct {
+	db *pgxpool.Pool
+}
+
+func NewVectorIndexStore(db *pgxpool.Pool) *VectorIndexStore {
+	return &VectorIndexStore{db: db}
+}
+
+const vectorIndexSelect = `
+	SELECT s.index_name, s.table_name, s.column_name, s.method,
+	       COALESCE(s.m, 0), COALESCE(s.ef_construction, 0), COALESCE(s.lists, 0),
+	       s.build_status, COALESCE(s.last_error, ''), s.last_built_at, s.build_duration_ms,
+	       i.indexrelid IS NOT NULL, COALESCE(i.indisvalid, FALSE),
+	       COALESCE(pg_get_indexdef(i.indexrelid), ''),
+	       COALESCE(pg_relation_size(i.indexrelid), 0),
+	       GREATEST(COALESCE(t.reltuples, 0), 0)::BIGINT
+	  FROM vector_index_settings s
+	  LEFT JOIN pg_index i ON i.indexrelid = to_regclass(s.index_name)
+	  LEFT JOIN pg_class t ON t.oid = to_regclass(s.table_name)`
+
+func scanVectorIndex(row pgx.Row) (*domain.VectorIndex, error) {
+	var v domain.VectorIndex
+	err := row.Scan(&v.Name, &v.Table, &v.Column, &v.Params.Method,
+		&v.Params.M, &v.Params.EfConstruction, &v.Params.Lists,
+		&v.BuildStatus, &v.LastError, &v.LastBuiltAt, &v.BuildDurationMs,
+		&v.Exists, &v.Valid, &v.Definition, &v.SizeBytes, &v.EstimatedRows)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (s *VectorIndexStore) List(ctx context.Context) ([]domain.VectorIndex, error) {
+	rows, err := s.db.Query(ctx, vectorIndexSelect+` ORDER BY s.index_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.VectorIndex
+	for rows.Next() {
+		v, err := scanVectorIndex(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *v)
+	}
+	return out, rows.Err()
+}
+
+func (s *VectorIndexStore) Get(ctx context.Context, name string) (*domain.VectorIndex, error) {
+	v, err := scanVectorIndex(s.db.QueryRow(ctx, vectorIndexSelect+` WHERE s.index_name = $1`, name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *VectorIndexStore) SetBuildStatus(ctx context.Context, name string, status domain.VectorIndexBuildStatus, errMsg string) error {
+	tag, err := s.db.Exec(ctx,
+		`UPDATE vector_index_settings SET build_status = $2, last_error = NULLIF($3, ''), updated_at = NOW()
+		 WHERE index_name = $1`,
+		name, status, errMsg,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Rebuild creates the replacement under a temporary name with CREATE INDEX
+// CONCURRENTLY, so writes continue during the build, then drops the old index
+// and renames the new one in a short transaction.
+func (s *VectorIndexStore) Rebuild(ctx context.Context, name string, p domain.VectorIndexParams) error {
+	idx, err := s.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	dim, err := vectorColumnDim(ctx, s.db, idx.Table, idx.Column)
+	if err != nil {
+		return fmt.Errorf("read dimension of %s.%s: %w", idx.Table, idx.Column, err)
+	}
+	if dim > hnswMaxDim {
+		return fmt.Errorf("%s.%s has %d dimensions; pgvector indexes support at most %d", idx.Table, idx.Column, dim, hnswMaxDim)
+	}
+
+	var with string
+	switch p.Method {
+	case domain.VectorIndexHNSW:
+		with = fmt.Sprintf("m = %d, ef_construction = %d", p.M, p.EfConstruction)
+	case domain.VectorIndexIVFFlat:
+		with = fmt.Sprintf("lists = %d", p.Lists)
+	default:
+		return fmt.Errorf("unsupported index method %q", p.Method)
+	}
+
+	tmp := pgx.Identifier{idx.Name + "_rebuild"}.Sanitize()
+	start := time.Now()
+
+	var partitioned bool
+	if err := s.db.QueryRow(ctx,
+		`SELECT relkind = 'p' FROM pg_class WHERE oid = to_regclass($1)`, idx.Table,
+	).Scan(&partitioned); err != nil {
+		return fmt.Errorf("inspect %s: %w", idx.Table, err)
+	}
+	if partitioned {
+		// Postgres cannot build indexes CONCURRENTLY on a partitioned table, so
+		// the new index is built in place inside the swap transaction; writes to
+		// the table block until it finishes.
+		return WithTx(ctx, s.db, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, `DROP INDEX IF EXISTS `+pgx.Identifier{idx.Name}.Sanitize()); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE INDEX %s ON %s USING %s (%s vector_cosine_ops) WITH (%s)`,
+				pgx.Identifier{idx.Name}.Sanitize(), pgx.Identifier{idx.Table}.Sanitize(), p.Method,
+				pgx.Identifier{idx.Column}.Sanitize(), with)); err != nil {
+				return fmt.Errorf("build index: %w", err)
+			}
+			return markVectorIndexBuilt(ctx, tx, idx.Name, p, time.Since(start))
+		})
+	}
+
+	if _, err := s.db.Exec(ctx, `DROP INDEX CONCURRENTLY IF EXISTS `+tmp); err != nil {
+		return fmt.Errorf("drop leftover rebuild index: %w", err)
+	}
+	if _, err := s.db.Exec(ctx, fmt.Sprintf(`CREATE INDEX CONCURRENTLY %s ON %s USING %s (%s vector_cosine_ops) WITH (%s)`,
+		tmp, pgx.Identifier{idx.Table}.Sanitize(), p.Method, pgx.Identifier{idx.Column}.Sanitize(), with)); err != nil {
+		// A failed concurrent build leaves an invalid index behind.
+		_, _ = s.db.Exec(context.Background(), `DROP INDEX CONCURRENTLY IF EXISTS `+tmp)
+		return fmt.Errorf("build index: %w", err)
+	}
+	took := time.Since(start)
+
+	return WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DROP INDEX IF EXISTS `+pgx.Identifier{idx.Name}.Sanitize()); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER INDEX %s RENAME TO %s`, tmp, pgx.Identifier{idx.Name}.Sanitize())); err != nil {
+			return err
+		}
+		return markVectorIndexBuilt(ctx, tx, idx.Name, p, took)
+	})
+}
+
+func markVectorIndexBuilt(ctx context.Context, tx pgx.Tx, name string, p domain.VectorIndexParams, took time.Duration) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE vector_index_settings
+		    SET method = $2, m = NULLIF($3, 0), ef_construction = NULLIF($4, 0), lists = NULLIF($5, 0),
+		        build_status = 'ready', last_error = NULL, last_built_at = NOW(),
+		        build_duration_ms = $6, updated_at = NOW()
+		  WHERE index_name = $1`,
+		name, p.Method, p.M, p.EfConstruction, p.Lists, took.Milliseconds(),
+	)
+	return err
+}
+
+func (s *VectorIndexStore) SampleVectors(ctx context.Context, name string, n int) ([][]float32, error) {
+	idx, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	col := pgx.Identifier{idx.Column}.Sanitize()
+	rows, err := s.db.Query(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s WHERE %s IS NOT NULL ORDER BY random() LIMIT $1`,
+			col, pgx.Identifier{idx.Table}.Sanitize(), col),
+		n,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out [][]float32
+	for rows.Next() {
+		var v pgvector.Vector
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		out = append(out, v.Slice())
+	}
+	return out, rows.Err()
+}
+
+func (s *VectorIndexStore) Search(ctx context.Context, name string, query []float32, k int, exact bool, efSearch, probes int) (*domain.VectorSearchSample, error) {
+	idx, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &domain.VectorSearchSample{}
+	err = WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		var settings []string
+		if exact {
+			settings = append(settings, "SET LOCAL enable_indexscan = off", "SET LOCAL enable_bitmapscan = off")
+		}
+		if efSearch > 0 {
+			settings = append(settings, fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", efSearch))
+		}
+		if probes > 0 {
+			settings = append(settings, fmt.Sprintf("SET LOCAL ivfflat.probes = %d", probes))
+		}
+		for _, stmt := range settings {
+			if _, err := tx.Exec(ctx, stmt); err != nil {
+				return err
+			}
+		}
+
+		start := time.Now()
+		rows, err := tx.Query(ctx, nearestNeighbourSQL(idx), pgvector.NewVector(query), k)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			sample.IDs = append(sample.IDs, id)
+		}
+		sample.Duration = time.Since(start)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+// UsesIndex reports whether the planner chooses the index for a top-k search.
+// On small tables a sequential scan is cheaper and expected.
+func (s *VectorIndexStore) UsesIndex(ctx context.Context, name string, query []float32, k int) (bool, error) {
+	idx, err := s.Get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	rows, err := s.db.Query(ctx, `EXPLAIN `+nearestNeighbourSQL(idx), pgvector.NewVector(query), k)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	uses := false
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return false, err
+		}
+		if strings.Contains(line, "using "+idx.Name) {
+			uses = true
+		}
+	}
+	return uses, rows.Err()
+}
+
+func nearestNeighbourSQL(idx *domain.VectorIndex) string {
+	return fmt.Sprintf(`SELECT id FROM %s ORDER BY %s <=> $1 LIMIT $2`,
+		pgx.Identifier{idx.Table}.Sanitize(), pgx.Identifier{idx.Column}.Sanitize())
+}
@@ -5,18 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Harshitk-cp/engram/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type WorkingMemoryStore struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewWorkingMemoryStore(db *pgxpool.Pool) *WorkingMemoryStore {
+func NewWorkingMemoryStore(db DB) *WorkingMemoryStore {
 	return &WorkingMemoryStore{db: db}
 }
 
@@ -39,9 +39,9 @@ func (s *WorkingMemoryStore) CreateSession(ctx context.Context, sess *domain.Wor
 	return s.db.QueryRow(ctx,
 		`INSERT INTO working_memory_sessions (
 			agent_id, tenant_id, current_goal, active_context, reasoning_state,
-			max_slots, expires_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
-		ON CONFLICT (agent_id) DO UPDATE SET
+			max_slots, expires_at, conversation_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (agent_id, conversation_id) DO UPDATE SET
 			current_goal = EXCLUDED.current_goal,
 			active_context = EXCLUDED.active_context,
 			reasoning_state = EXCLUDED.reasoning_state,
@@ -51,23 +51,33 @@ func (s *WorkingMemoryStore) CreateSession(ctx context.Context, sess *domain.Wor
 			updated_at = NOW()
 		RETURNING id, started_at, last_activity_at, created_at, updated_at`,
 		sess.AgentID, sess.TenantID, sess.CurrentGoal, activeContextJSON, reasoningStateJSON,
-		sess.MaxSlots, sess.ExpiresAt,
+		sess.MaxSlots, sess.ExpiresAt, sess.ConversationID,
 	).Scan(&sess.ID, &sess.StartedAt, &sess.LastActivityAt, &sess.CreatedAt, &sess.UpdatedAt)
 }
 
-// GetSession retrieves the active working memory session for an agent.
+// GetSession retrieves the agent-wide working memory session of an agent.
 func (s *WorkingMemoryStore) GetSession(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*domain.WorkingMemorySession, error) {
+	return s.getSession(ctx, `agent_id = $1 AND tenant_id = $2 AND conversation_id IS NULL`, agentID, tenantID)
+}
+
+// GetConversationSession retrieves the working memory session isolated to
+// one of the agent's conversations.
+func (s *WorkingMemoryStore) GetConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) (*domain.WorkingMemorySession, error) {
+	return s.getSession(ctx, `agent_id = $1 AND tenant_id = $2 AND conversation_id = $3`, agentID, tenantID, conversationID)
+}
+
+func (s *WorkingMemoryStore) getSession(ctx context.Context, where string, args ...any) (*domain.WorkingMemorySession, error) {
 	sess := &domain.WorkingMemorySession{}
 	var activeContextJSON, reasoningStateJSON []byte
 
 	err := s.db.QueryRow(ctx,
-		`SELECT id, agent_id, tenant_id, current_goal, active_context, reasoning_state,
+		`SELECT id, agent_id, tenant_id, conversation_id, current_goal, active_context, reasoning_state,
 			max_slots, started_at, last_activity_at, expires_at, created_at, updated_at
 		FROM working_memory_sessions
-		WHERE agent_id = $1 AND tenant_id = $2`,
-		agentID, tenantID,
+		WHERE `+where,
+		args...,
 	).Scan(
-		&sess.ID, &sess.AgentID, &sess.TenantID, &sess.CurrentGoal, &activeContextJSON, &reasoningStateJSON,
+		&sess.ID, &sess.AgentID, &sess.TenantID, &sess.ConversationID, &sess.CurrentGoal, &activeContextJSON, &reasoningStateJSON,
 		&sess.MaxSlots, &sess.StartedAt, &sess.LastActivityAt, &sess.ExpiresAt, &sess.CreatedAt, &sess.UpdatedAt,
 	)
 	if err != nil {
@@ -91,19 +101,49 @@ func (s *WorkingMemoryStore) GetSession(ctx context.Context, agentID uuid.UUID,
 	return sess, nil
 }
 
+// ListSessions returns the agent's unexpired sessions, agent-wide and per
+// conversation, most recently active first. Activations are not loaded.
+func (s *WorkingMemoryStore) ListSessions(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.WorkingMemorySession, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, agent_id, tenant_id, conversation_id, current_goal, max_slots,
+			started_at, last_activity_at, expires_at, created_at, updated_at
+		FROM working_memory_sessions
+		WHERE agent_id = $1 AND tenant_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY last_activity_at DESC`,
+		agentID, tenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []domain.WorkingMemorySession
+	for rows.Next() {
+		var sess domain.WorkingMemorySession
+		if err := rows.Scan(
+			&sess.ID, &sess.AgentID, &sess.TenantID, &sess.ConversationID, &sess.CurrentGoal, &sess.MaxSlots,
+			&sess.StartedAt, &sess.LastActivityAt, &sess.ExpiresAt, &sess.CreatedAt, &sess.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions, rows.Err()
+}
+
 // GetSessionByID retrieves a working memory session by ID.
 func (s *WorkingMemoryStore) GetSessionByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.WorkingMemorySession, error) {
 	sess := &domain.WorkingMemorySession{}
 	var activeContextJSON, reasoningStateJSON []byte
 
 	err := s.db.QueryRow(ctx,
-		`SELECT id, agent_id, tenant_id, current_goal, active_context, reasoning_state,
+		`SELECT id, agent_id, tenant_id, conversation_id, current_goal, active_context, reasoning_state,
 			max_slots, started_at, last_activity_at, expires_at, created_at, updated_at
 		FROM working_memory_sessions
 		WHERE id = $1 AND tenant_id = $2`,
 		id, tenantID,
 	).Scan(
-		&sess.ID, &sess.AgentID, &sess.TenantID, &sess.CurrentGoal, &activeContextJSON, &reasoningStateJSON,
+		&sess.ID, &sess.AgentID, &sess.TenantID, &sess.ConversationID, &sess.CurrentGoal, &activeContextJSON, &reasoningStateJSON,
 		&sess.MaxSlots, &sess.StartedAt, &sess.LastActivityAt, &sess.ExpiresAt, &sess.CreatedAt, &sess.UpdatedAt,
 	)
 	if err != nil {
@@ -152,12 +192,19 @@ func (s *WorkingMemoryStore) UpdateSession(ctx context.Context, sess *domain.Wor
 	return nil
 }
 
-// DeleteSession deletes a working memory session.
+// DeleteSession deletes the agent-wide working memory session of an agent.
 func (s *WorkingMemoryStore) DeleteSession(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) error {
-	tag, err := s.db.Exec(ctx,
-		`DELETE FROM working_memory_sessions WHERE agent_id = $1 AND tenant_id = $2`,
-		agentID, tenantID,
-	)
+	return s.deleteSession(ctx, `agent_id = $1 AND tenant_id = $2 AND conversation_id IS NULL`, agentID, tenantID)
+}
+
+// DeleteConversationSession deletes the session isolated to one of the
+// agent's conversations.
+func (s *WorkingMemoryStore) DeleteConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) error {
+	return s.deleteSession(ctx, `agent_id = $1 AND tenant_id = $2 AND conversation_id = $3`, agentID, tenantID, conversationID)
+}
+
+func (s *WorkingMemoryStore) deleteSession(ctx context.Context, where string, args ...any) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM working_memory_sessions WHERE `+where, args...)
 	if err != nil {
 		return err
 	}
@@ -195,6 +242,82 @@ func (s *WorkingMemoryStore) CreateActivation(ctx context.Context, a *domain.Wor
 	).Scan(&a.ID, &a.ActivatedAt)
 }
 
+// CreateActivationsBatch upserts many activations in a single multi-row
+// insert, filling in each one's ID and ActivatedAt. A zero ActivatedAt means
+// now. The batch must not name the same (session, type, memory) twice.
+func (s *WorkingMemoryStore) CreateActivationsBatch(ctx context.Context, acts []domain.WorkingMemoryActivation) error {
+	return insertActivations(ctx, s.db, acts)
+}
+
+func insertActivations(ctx context.Context, db DBTX, acts []domain.WorkingMemoryActivation) error {
+	if len(acts) == 0 {
+		return nil
+	}
+	n := len(acts)
+	sessionIDs, tenantIDs, memoryIDs := make([]uuid.UUID, n), make([]uuid.UUID, n), make([]uuid.UUID, n)
+	memoryTypes, sources, cues := make([]string, n), make([]string, n), make([]string, n)
+	levels := make([]float32, n)
+	slots := make([]*int, n)
+	activatedAt := make([]*time.Time, n)
+	for i := range acts {
+		a := &acts[i]
+		sessionIDs[i], tenantIDs[i], memoryIDs[i] = a.SessionID, a.TenantID, a.MemoryID
+		memoryTypes[i], sources[i], cues[i] = string(a.MemoryType), string(a.ActivationSource), a.ActivationCue
+		levels[i] = a.ActivationLevel
+		slots[i] = a.SlotPosition
+		if !a.ActivatedAt.IsZero() {
+			activatedAt[i] = &a.ActivatedAt
+		}
+	}
+
+	rows, err := db.Query(ctx,
+		`INSERT INTO working_memory_activations (
+			session_id, tenant_id, memory_type, memory_id, activation_level, activation_source,
+			activation_cue, slot_position, activated_at
+		)
+		SELECT session_id, tenant_id, memory_type, memory_id, activation_level, activation_source,
+			activation_cue, slot_position, COALESCE(activated_at, NOW())
+		FROM unnest($1::uuid[], $2::uuid[], $3::text[], $4::uuid[], $5::real[], $6::text[],
+			$7::text[], $8::int[], $9::timestamptz[])
+			AS t(session_id, tenant_id, memory_type, memory_id, activation_level, activation_source,
+				activation_cue, slot_position, activated_at)
+		ON CONFLICT (session_id, memory_type, memory_id) DO UPDATE SET
+			activation_level = EXCLUDED.activation_level,
+			activation_source = EXCLUDED.activation_source,
+			activation_cue = EXCLUDED.activation_cue,
+			slot_position = EXCLUDED.slot_position,
+			activated_at = EXCLUDED.activated_at
+		RETURNING session_id, memory_type, memory_id, id, activated_at`,
+		sessionIDs, tenantIDs, memoryTypes, memoryIDs, levels, sources, cues, slots, activatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type actKey struct {
+		session uuid.UUID
+		typ     string
+		memory  uuid.UUID
+	}
+	index := make(map[actKey]int, n)
+	for i := range acts {
+		index[actKey{acts[i].SessionID, string(acts[i].MemoryType), acts[i].MemoryID}] = i
+	}
+	for rows.Next() {
+		var k actKey
+		var id uuid.UUID
+		var at time.Time
+		if err := rows.Scan(&k.session, &k.typ, &k.memory, &id, &at); err != nil {
+			return err
+		}
+		if i, ok := index[k]; ok {
+			acts[i].ID, acts[i].ActivatedAt = id, at
+		}
+	}
+	return rows.Err()
+}
+
 // GetActivations retrieves all activations for a session.
 func (s *WorkingMemoryStore) GetActivations(ctx context.Context, sessionID uuid.UUID) ([]domain.WorkingMemoryActivation, error) {
 	rows, err := s.db.Query(ctx,
@@ -258,6 +381,63 @@ func (s *WorkingMemoryStore) CreateSchemaActivation(ctx context.Context, a *doma
 	).Scan(&a.ID, &a.ActivatedAt)
 }
 
+// CreateSchemaActivationsBatch upserts many schema activations in a single
+// multi-row insert, filling in each one's ID and ActivatedAt. A zero
+// ActivatedAt means now.
+func (s *WorkingMemoryStore) CreateSchemaActivationsBatch(ctx context.Context, acts []domain.SchemaActivation) error {
+	return insertSchemaActivations(ctx, s.db, acts)
+}
+
+func insertSchemaActivations(ctx context.Context, db DBTX, acts []domain.SchemaActivation) error {
+	if len(acts) == 0 {
+		return nil
+	}
+	n := len(acts)
+	sessionIDs, schemaIDs := make([]uuid.UUID, n), make([]uuid.UUID, n)
+	scores := make([]float32, n)
+	activatedAt := make([]*time.Time, n)
+	for i := range acts {
+		sessionIDs[i], schemaIDs[i], scores[i] = acts[i].SessionID, acts[i].SchemaID, acts[i].MatchScore
+		if !acts[i].ActivatedAt.IsZero() {
+			activatedAt[i] = &acts[i].ActivatedAt
+		}
+	}
+
+	rows, err := db.Query(ctx,
+		`INSERT INTO schema_activations (session_id, schema_id, match_score, activated_at)
+		SELECT session_id, schema_id, match_score, COALESCE(activated_at, NOW())
+		FROM unnest($1::uuid[], $2::uuid[], $3::real[], $4::timestamptz[])
+			AS t(session_id, schema_id, match_score, activated_at)
+		ON CONFLICT (session_id, schema_id) DO UPDATE SET
+			match_score = EXCLUDED.match_score,
+			activated_at = EXCLUDED.activated_at
+		RETURNING session_id, schema_id, id, activated_at`,
+		sessionIDs, schemaIDs, scores, activatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type schemaKey struct{ session, schema uuid.UUID }
+	index := make(map[schemaKey]int, n)
+	for i := range acts {
+		index[schemaKey{acts[i].SessionID, acts[i].SchemaID}] = i
+	}
+	for rows.Next() {
+		var k schemaKey
+		var id uuid.UUID
+		var at time.Time
+		if err := rows.Scan(&k.session, &k.schema, &id, &at); err != nil {
+			return err
+		}
+		if i, ok := index[k]; ok {
+			acts[i].ID, acts[i].ActivatedAt = id, at
+		}
+	}
+	return rows.Err()
+}
+
 // GetSchemaActivations retrieves all schema activations for a session.
 func (s *WorkingMemoryStore) GetSchemaActivations(ctx context.Context, sessionID uuid.UUID) ([]domain.SchemaActivation, error) {
 	rows, err := s.db.Query(ctx,
@@ -305,16 +485,74 @@ func (s *WorkingMemoryStore) DeleteExpiredSessions(ctx context.Context) (int64,
 	return tag.RowsAffected(), nil
 }
 
+// ReplaceState overwrites a session's state and its memory and schema
+// activations in one transaction. Used to persist a session held in a cache.
+func (s *WorkingMemoryStore) ReplaceState(ctx context.Context, sess *domain.WorkingMemorySession, acts []domain.WorkingMemoryActivation, schemaActs []domain.SchemaActivation) error {
+	activeContextJSON, err := json.Marshal(sess.ActiveContext)
+	if err != nil {
+		return fmt.Errorf("marshal active_context: %w", err)
+	}
+	reasoningStateJSON, err := json.Marshal(sess.ReasoningState)
+	if err != nil {
+		return fmt.Errorf("marshal reasoning_state: %w", err)
+	}
+
+	return WithTx(ctx, s.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE working_memory_sessions SET
+				current_goal = $1, active_context = $2, reasoning_state = $3,
+				max_slots = $4, expires_at = $5, last_activity_at = $6, updated_at = NOW()
+			WHERE id = $7 AND tenant_id = $8`,
+			sess.CurrentGoal, activeContextJSON, reasoningStateJSON,
+			sess.MaxSlots, sess.ExpiresAt, sess.LastActivityAt, sess.ID, sess.TenantID,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM working_memory_activations WHERE session_id = $1`, sess.ID); err != nil {
+			return err
+		}
+		rows := make([]domain.WorkingMemoryActivation, len(acts))
+		for i, a := range acts {
+			a.SessionID, a.TenantID = sess.ID, sess.TenantID
+			rows[i] = a
+		}
+		if err := insertActivations(ctx, tx, rows); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_activations WHERE session_id = $1`, sess.ID); err != nil {
+			return err
+		}
+		schemaRows := make([]domain.SchemaActivation, len(schemaActs))
+		for i, a := range schemaActs {
+			a.SessionID = sess.ID
+			schemaRows[i] = a
+		}
+		return insertSchemaActivations(ctx, tx, schemaRows)
+	})
+}
+
 // Verify interface compliance at compile time
 var _ domain.WorkingMemoryStore = (*WorkingMemoryStore)(nil)
 
 // MemoryAssociationStore handles cross-memory associations.
 type MemoryAssociationStore struct {
-	db *pgxpool.Pool
+	db   DBTX
+	pool DB
+}
+
+func NewMemoryAssociationStore(db DB) *MemoryAssociationStore {
+	return &MemoryAssociationStore{db: db, pool: db}
 }
 
-func NewMemoryAssociationStore(db *pgxpool.Pool) *MemoryAssociationStore {
-	return &MemoryAssociationStore{db: db}
+// withTx returns a clone of the store that runs against the given transaction.
+func (s *MemoryAssociationStore) withTx(tx pgx.Tx) *MemoryAssociationStore {
+	return &MemoryAssociationStore{db: tx, pool: s.pool}
 }
 
 // Create creates a new memory association.
@@ -0,0 +1,606 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/redis"
+	"github.com/google/uuid"
+)
+
+// DefaultWorkingMemoryCacheTTL is how long an idle session stays in Redis.
+const DefaultWorkingMemoryCacheTTL = 24 * time.Hour
+
+const (
+	wmKeyPrefix = "engram:wm:"
+	wmDirtyKey  = wmKeyPrefix + "dirty" // session IDs with state not yet persisted
+)
+
+// RedisWorkingMemoryStore serves working memory from Redis in front of the
+// Postgres WorkingMemoryStore. Sessions are created in Postgres (so the row
+// and its ID are durable) and then cached; the per-turn writes — session
+// state and activations — go to Redis only and are marked dirty until Flush
+// copies them back. Every read and write falls back to Postgres when Redis is
+// unreachable or the session is not cached, so losing Redis loses at most the
+// writes since the last flush.
+//
+// Keys: session:{tenant}:{agent}[:{conversation}] holds the session JSON, id:{session} points
+// at it, and act:{session} / schema:{session} are hashes of activations. The
+// id key's presence is what marks a session as served from Redis.
+type RedisWorkingMemoryStore struct {
+	rdb *redis.Client
+	pg  *WorkingMemoryStore
+	ttl time.Duration
+}
+
+func NewRedisWorkingMemoryStore(rdb *redis.Client, pg *WorkingMemoryStore, ttl time.Duration) *RedisWorkingMemoryStore {
+	if ttl <= 0 {
+		ttl = DefaultWorkingMemoryCacheTTL
+	}
+	return &RedisWorkingMemoryStore{rdb: rdb, pg: pg, ttl: ttl}
+}
+
+func wmSessionKey(tenantID, agentID uuid.UUID, conversationID *uuid.UUID) string {
+	key := wmKeyPrefix + "session:" + tenantID.String() + ":" + agentID.String()
+	if conversationID != nil {
+		key += ":" + conversationID.String()
+	}
+	return key
+}
+func wmIDKey(id uuid.UUID) string     { return wmKeyPrefix + "id:" + id.String() }
+func wmActKey(id uuid.UUID) string    { return wmKeyPrefix + "act:" + id.String() }
+func wmSchemaKey(id uuid.UUID) string { return wmKeyPrefix + "schema:" + id.String() }
+
+func wmActField(memoryType domain.ActivatedMemoryType, memoryID uuid.UUID) string {
+	return string(memoryType) + ":" + memoryID.String()
+}
+
+// ttlFor bounds the cache lifetime by the session's own expiry.
+func (s *RedisWorkingMemoryStore) ttlFor(sess *domain.WorkingMemorySession) int64 {
+	ttl := s.ttl
+	if sess.ExpiresAt != nil {
+		if until := time.Until(*sess.ExpiresAt); until < ttl {
+			ttl = until
+		}
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return ttl.Milliseconds()
+}
+
+func encodeSession(sess *domain.WorkingMemorySession) ([]byte, error) {
+	cp := *sess
+	cp.Activations, cp.ActiveSchemas = nil, nil
+	return json.Marshal(&cp)
+}
+
+// sessionCommands writes the session and its id pointer and refreshes the TTL
+// of its activation hashes.
+func (s *RedisWorkingMemoryStore) sessionCommands(sess *domain.WorkingMemorySession) ([][]any, error) {
+	data, err := encodeSession(sess)
+	if err != nil {
+		return nil, err
+	}
+	ttl := s.ttlFor(sess)
+	key := wmSessionKey(sess.TenantID, sess.AgentID, sess.ConversationID)
+	return [][]any{
+		{"SET", key, data, "PX", ttl},
+		{"SET", wmIDKey(sess.ID), key, "PX", ttl},
+		{"PEXPIRE", wmActKey(sess.ID), ttl},
+		{"PEXPIRE", wmSchemaKey(sess.ID), ttl},
+	}, nil
+}
+
+func (s *RedisWorkingMemoryStore) exec(ctx context.Context, cmds ...[]any) error {
+	replies, err := s.rdb.Pipeline(ctx, cmds...)
+	if err != nil {
+		return err
+	}
+	return redis.FirstError(replies)
+}
+
+// warm loads a session's activations from Postgres and caches the whole
+// session. It is best effort: on failure the partial entry is evicted so
+// reads keep going to Postgres.
+func (s *RedisWorkingMemoryStore) warm(ctx context.Context, sess *domain.WorkingMemorySession) {
+	acts, err := s.pg.GetActivations(ctx, sess.ID)
+	if err != nil {
+		return
+	}
+	schemaActs, err := s.pg.GetSchemaActivations(ctx, sess.ID)
+	if err != nil {
+		return
+	}
+
+	ttl := s.ttlFor(sess)
+	cmds := [][]any{{"DEL", wmActKey(sess.ID), wmSchemaKey(sess.ID)}}
+	for i := range acts {
+		data, err := json.Marshal(&acts[i])
+		if err != nil {
+			return
+		}
+		cmds = append(cmds, []any{"HSET", wmActKey(sess.ID), wmActField(acts[i].MemoryType, acts[i].MemoryID), data})
+	}
+	for i := range schemaActs {
+		data, err := json.Marshal(&schemaActs[i])
+		if err != nil {
+			return
+		}
+		cmds = append(cmds, []any{"HSET", wmSchemaKey(sess.ID), schemaActs[i].SchemaID.String(), data})
+	}
+	cmds = append(cmds,
+		[]any{"PEXPIRE", wmActKey(sess.ID), ttl},
+		[]any{"PEXPIRE", wmSchemaKey(sess.ID), ttl},
+	)
+	// The id pointer goes last: until it exists the session is not served
+	// from Redis.
+	sessCmds, err := s.sessionCommands(sess)
+	if err != nil {
+		return
+	}
+	cmds = append(cmds, sessCmds[:2]...)
+
+	if err := s.exec(ctx, cmds...); err != nil {
+		s.evict(context.Background(), sess)
+	}
+}
+
+func (s *RedisWorkingMemoryStore) evict(ctx context.Context, sess *domain.WorkingMemorySession) {
+	_ = s.exec(ctx,
+		[]any{"DEL", wmIDKey(sess.ID), wmSessionKey(sess.TenantID, sess.AgentID, sess.ConversationID), wmActKey(sess.ID), wmSchemaKey(sess.ID)},
+		[]any{"SREM", wmDirtyKey, sess.ID.String()},
+	)
+}
+
+// cachedByKey reads a session from Redis. A miss is reported as ErrNotFound;
+// any other error means Redis is unavailable.
+func (s *RedisWorkingMemoryStore) cachedByKey(ctx context.Context, key string) (*domain.WorkingMemorySession, error) {
+	data, err := redis.String(s.rdb.Do(ctx, "GET", key))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var sess domain.WorkingMemorySession
+	if err := json.Unmarshal([]byte(data), &sess); err != nil {
+		return nil, fmt.Errorf("decode cached session: %w", err)
+	}
+	return &sess, nil
+}
+
+func (s *RedisWorkingMemoryStore) cachedByID(ctx context.Context, id uuid.UUID) (*domain.WorkingMemorySession, error) {
+	key, err := redis.String(s.rdb.Do(ctx, "GET", wmIDKey(id)))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s.cachedByKey(ctx, key)
+}
+
+// isCached reports whether the session's activations are served from Redis.
+func (s *RedisWorkingMemoryStore) isCached(ctx context.Context, sessionID uuid.UUID) bool {
+	n, err := redis.Int64(s.rdb.Do(ctx, "EXISTS", wmIDKey(sessionID)))
+	return err == nil && n == 1
+}
+
+// CreateSession creates (or resets) the session in Postgres and caches it.
+func (s *RedisWorkingMemoryStore) CreateSession(ctx context.Context, sess *domain.WorkingMemorySession) error {
+	if err := s.pg.CreateSession(ctx, sess); err != nil {
+		return err
+	}
+	s.warm(ctx, sess)
+	return nil
+}
+
+func (s *RedisWorkingMemoryStore) GetSession(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) (*domain.WorkingMemorySession, error) {
+	return s.getSession(ctx, wmSessionKey(tenantID, agentID, nil), func() (*domain.WorkingMemorySession, error) {
+		return s.pg.GetSession(ctx, agentID, tenantID)
+	})
+}
+
+func (s *RedisWorkingMemoryStore) GetConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) (*domain.WorkingMemorySession, error) {
+	return s.getSession(ctx, wmSessionKey(tenantID, agentID, &conversationID), func() (*domain.WorkingMemorySession, error) {
+		return s.pg.GetConversationSession(ctx, agentID, tenantID, conversationID)
+	})
+}
+
+// getSession reads the session cached under key, falling back to load and
+// caching what it returns on a miss.
+func (s *RedisWorkingMemoryStore) getSession(ctx context.Context, key string, load func() (*domain.WorkingMemorySession, error)) (*domain.WorkingMemorySession, error) {
+	sess, err := s.cachedByKey(ctx, key)
+	if err == nil {
+		return sess, nil
+	}
+	sess, pgErr := load()
+	if pgErr != nil {
+		return nil, pgErr
+	}
+	if errors.Is(err, ErrNotFound) {
+		s.warm(ctx, sess)
+	}
+	return sess, nil
+}
+
+// ListSessions lists the agent's sessions from Postgres, with the state of
+// those cached in Redis, which may be newer.
+func (s *RedisWorkingMemoryStore) ListSessions(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.WorkingMemorySession, error) {
+	sessions, err := s.pg.ListSessions(ctx, agentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		if cached, err := s.cachedByID(ctx, sessions[i].ID); err == nil && cached.TenantID == tenantID {
+			sessions[i] = *cached
+		}
+	}
+	return sessions, nil
+}
+
+func (s *RedisWorkingMemoryStore) GetSessionByID(ctx context.Context, id uuid.UUID, tenantID uuid.UUID) (*domain.WorkingMemorySession, error) {
+	sess, err := s.cachedByID(ctx, id)
+	if err == nil {
+		if sess.TenantID != tenantID {
+			return nil, ErrNotFound
+		}
+		return sess, nil
+	}
+	sess, pgErr := s.pg.GetSessionByID(ctx, id, tenantID)
+	if pgErr != nil {
+		return nil, pgErr
+	}
+	if errors.Is(err, ErrNotFound) {
+		s.warm(ctx, sess)
+	}
+	return sess, nil
+}
+
+// UpdateSession writes the session to Redis when it is cached there and to
+// Postgres otherwise.
+func (s *RedisWorkingMemoryStore) UpdateSession(ctx context.Context, sess *domain.WorkingMemorySession) error {
+	key, err := redis.String(s.rdb.Do(ctx, "GET", wmIDKey(sess.ID)))
+	if err != nil || key != wmSessionKey(sess.TenantID, sess.AgentID, sess.ConversationID) {
+		return s.pg.UpdateSession(ctx, sess)
+	}
+
+	now := time.Now()
+	sess.LastActivityAt, sess.UpdatedAt = now, now
+	cmds, err := s.sessionCommands(sess)
+	if err != nil {
+		return err
+	}
+	cmds = append(cmds, []any{"SADD", wmDirtyKey, sess.ID.String()})
+	if err := s.exec(ctx, cmds...); err != nil {
+		return s.pg.UpdateSession(ctx, sess)
+	}
+	return nil
+}
+
+// DeleteSession removes the session from both Redis and Postgres.
+func (s *RedisWorkingMemoryStore) DeleteSession(ctx context.Context, agentID uuid.UUID, tenantID uuid.UUID) error {
+	if sess, err := s.cachedByKey(ctx, wmSessionKey(tenantID, agentID, nil)); err == nil {
+		s.evict(ctx, sess)
+	}
+	return s.pg.DeleteSession(ctx, agentID, tenantID)
+}
+
+// DeleteConversationSession removes a conversation's session from both Redis
+// and Postgres.
+func (s *RedisWorkingMemoryStore) DeleteConversationSession(ctx context.Context, agentID, tenantID, conversationID uuid.UUID) error {
+	if sess, err := s.cachedByKey(ctx, wmSessionKey(tenantID, agentID, &conversationID)); err == nil {
+		s.evict(ctx, sess)
+	}
+	return s.pg.DeleteConversationSession(ctx, agentID, tenantID, conversationID)
+}
+
+func (s *RedisWorkingMemoryStore) UpdateLastActivity(ctx context.Context, sessionID uuid.UUID) error {
+	sess, err := s.cachedByID(ctx, sessionID)
+	if err != nil {
+		return s.pg.UpdateLastActivity(ctx, sessionID)
+	}
+	return s.UpdateSession(ctx, sess)
+}
+
+// markDirty appends the commands that keep an activation hash alive and flag
+// its session for the next flush.
+func (s *RedisWorkingMemoryStore) markDirty(key string, sessionID uuid.UUID) [][]any {
+	return [][]any{
+		{"PEXPIRE", key, s.ttl.Milliseconds()},
+		{"SADD", wmDirtyKey, sessionID.String()},
+	}
+}
+
+func (s *RedisWorkingMemoryStore) CreateActivation(ctx context.Context, a *domain.WorkingMemoryActivation) error {
+	if !s.isCached(ctx, a.SessionID) {
+		return s.pg.CreateActivation(ctx, a)
+	}
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	a.ActivatedAt = time.Now()
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	key := wmActKey(a.SessionID)
+	cmds := append([][]any{{"HSET", key, wmActField(a.MemoryType, a.MemoryID), data}}, s.markDirty(key, a.SessionID)...)
+	if err := s.exec(ctx, cmds...); err != nil {
+		return s.pg.CreateActivation(ctx, a)
+	}
+	return nil
+}
+
+// CreateActivationsBatch writes the activations of cached sessions in one
+// pipeline; activations of uncached sessions go to Postgres in one insert.
+func (s *RedisWorkingMemoryStore) CreateActivationsBatch(ctx context.Context, acts []domain.WorkingMemoryActivation) error {
+	cached := make(map[uuid.UUID]bool)
+	var cmds, expire [][]any
+	var uncached []domain.WorkingMemoryActivation
+	now := time.Now()
+	for i := range acts {
+		a := &acts[i]
+		isCached, seen := cached[a.SessionID]
+		if !seen {
+			isCached = s.isCached(ctx, a.SessionID)
+			cached[a.SessionID] = isCached
+			if isCached {
+				expire = append(expire, s.markDirty(wmActKey(a.SessionID), a.SessionID)...)
+			}
+		}
+		if !isCached {
+			uncached = append(uncached, *a)
+			continue
+		}
+		if a.ID == uuid.Nil {
+			a.ID = uuid.New()
+		}
+		a.ActivatedAt = now
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, []any{"HSET", wmActKey(a.SessionID), wmActField(a.MemoryType, a.MemoryID), data})
+	}
+	if len(cmds) > 0 {
+		// TTLs go last: PEXPIRE is a no-op on a hash HSET has not created yet.
+		if err := s.exec(ctx, append(cmds, expire...)...); err != nil {
+			return s.pg.CreateActivationsBatch(ctx, acts)
+		}
+	}
+	return s.pg.CreateActivationsBatch(ctx, uncached)
+}
+
+func (s *RedisWorkingMemoryStore) GetActivations(ctx context.Context, sessionID uuid.UUID) ([]domain.WorkingMemoryActivation, error) {
+	if !s.isCached(ctx, sessionID) {
+		return s.pg.GetActivations(ctx, sessionID)
+	}
+	fields, err := redis.Strings(s.rdb.Do(ctx, "HGETALL", wmActKey(sessionID)))
+	if err != nil {
+		return s.pg.GetActivations(ctx, sessionID)
+	}
+	return decodeActivations(fields)
+}
+
+func decodeActivations(fields []string) ([]domain.WorkingMemoryActivation, error) {
+	var acts []domain.WorkingMemoryActivation
+	for i := 1; i < len(fields); i += 2 {
+		var a domain.WorkingMemoryActivation
+		if err := json.Unmarshal([]byte(fields[i]), &a); err != nil {
+			return nil, fmt.Errorf("decode cached activation: %w", err)
+		}
+		acts = append(acts, a)
+	}
+	sort.SliceStable(acts, func(i, j int) bool { return acts[i].ActivationLevel > acts[j].ActivationLevel })
+	return acts, nil
+}
+
+func (s *RedisWorkingMemoryStore) ClearActivations(ctx context.Context, sessionID uuid.UUID) error {
+	if !s.isCached(ctx, sessionID) {
+		return s.pg.ClearActivations(ctx, sessionID)
+	}
+	if err := s.exec(ctx,
+		[]any{"DEL", wmActKey(sessionID)},
+		[]any{"SADD", wmDirtyKey, sessionID.String()},
+	); err != nil {
+		return s.pg.ClearActivations(ctx, sessionID)
+	}
+	return nil
+}
+
+func (s *RedisWorkingMemoryStore) DeleteActivation(ctx context.Context, sessionID uuid.UUID, memoryType domain.ActivatedMemoryType, memoryID uuid.UUID) error {
+	if !s.isCached(ctx, sessionID) {
+		return s.pg.DeleteActivation(ctx, sessionID, memoryType, memoryID)
+	}
+	key := wmActKey(sessionID)
+	cmds := append([][]any{{"HDEL", key, wmActField(memoryType, memoryID)}}, s.markDirty(key, sessionID)...)
+	if err := s.exec(ctx, cmds...); err != nil {
+		return s.pg.DeleteActivation(ctx, sessionID, memoryType, memoryID)
+	}
+	return nil
+}
+
+func (s *RedisWorkingMemoryStore) CreateSchemaActivation(ctx context.Context, a *domain.SchemaActivation) error {
+	if !s.isCached(ctx, a.SessionID) {
+		return s.pg.CreateSchemaActivation(ctx, a)
+	}
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	a.ActivatedAt = time.Now()
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	key := wmSchemaKey(a.SessionID)
+	cmds := append([][]any{{"HSET", key, a.SchemaID.String(), data}}, s.markDirty(key, a.SessionID)...)
+	if err := s.exec(ctx, cmds...); err != nil {
+		return s.pg.CreateSchemaActivation(ctx, a)
+	}
+	return nil
+}
+
+// CreateSchemaActivationsBatch is the schema counterpart of
+// CreateActivationsBatch.
+func (s *RedisWorkingMemoryStore) CreateSchemaActivationsBatch(ctx context.Context, acts []domain.SchemaActivation) error {
+	cached := make(map[uuid.UUID]bool)
+	var cmds, expire [][]any
+	var uncached []domain.SchemaActivation
+	now := time.Now()
+	for i := range acts {
+		a := &acts[i]
+		isCached, seen := cached[a.SessionID]
+		if !seen {
+			isCached = s.isCached(ctx, a.SessionID)
+			cached[a.SessionID] = isCached
+			if isCached {
+				expire = append(expire, s.markDirty(wmSchemaKey(a.SessionID), a.SessionID)...)
+			}
+		}
+		if !isCached {
+			uncached = append(uncached, *a)
+			continue
+		}
+		if a.ID == uuid.Nil {
+			a.ID = uuid.New()
+		}
+		a.ActivatedAt = now
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, []any{"HSET", wmSchemaKey(a.SessionID), a.SchemaID.String(), data})
+	}
+	if len(cmds) > 0 {
+		// TTLs go last: PEXPIRE is a no-op on a hash HSET has not created yet.
+		if err := s.exec(ctx, append(cmds, expire...)...); err != nil {
+			return s.pg.CreateSchemaActivationsBatch(ctx, acts)
+		}
+	}
+	return s.pg.CreateSchemaActivationsBatch(ctx, uncached)
+}
+
+func (s *RedisWorkingMemoryStore) GetSchemaActivations(ctx context.Context, sessionID uuid.UUID) ([]domain.SchemaActivation, error) {
+	if !s.isCached(ctx, sessionID) {
+		return s.pg.GetSchemaActivations(ctx, sessionID)
+	}
+	fields, err := redis.Strings(s.rdb.Do(ctx, "HGETALL", wmSchemaKey(sessionID)))
+	if err != nil {
+		return s.pg.GetSchemaActivations(ctx, sessionID)
+	}
+	return decodeSchemaActivations(fields)
+}
+
+func decodeSchemaActivations(fields []string) ([]domain.SchemaActivation, error) {
+	var acts []domain.SchemaActivation
+	for i := 1; i < len(fields); i += 2 {
+		var a domain.SchemaActivation
+		if err := json.Unmarshal([]byte(fields[i]), &a); err != nil {
+			return nil, fmt.Errorf("decode cached schema activation: %w", err)
+		}
+		acts = append(acts, a)
+	}
+	sort.SliceStable(acts, func(i, j int) bool { return acts[i].MatchScore > acts[j].MatchScore })
+	return acts, nil
+}
+
+func (s *RedisWorkingMemoryStore) ClearSchemaActivations(ctx context.Context, sessionID uuid.UUID) error {
+	if !s.isCached(ctx, sessionID) {
+		return s.pg.ClearSchemaActivations(ctx, sessionID)
+	}
+	if err := s.exec(ctx,
+		[]any{"DEL", wmSchemaKey(sessionID)},
+		[]any{"SADD", wmDirtyKey, sessionID.String()},
+	); err != nil {
+		return s.pg.ClearSchemaActivations(ctx, sessionID)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions removes expired sessions from Postgres. Their Redis
+// entries expire on their own: the cache TTL never outlives expires_at.
+func (s *RedisWorkingMemoryStore) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	return s.pg.DeleteExpiredSessions(ctx)
+}
+
+// Flush persists every session with unflushed Redis writes to Postgres and
+// returns how many were written. A session is unflagged before it is read, so
+// writes racing with the flush flag it again for the next one.
+func (s *RedisWorkingMemoryStore) Flush(ctx context.Context) (int, error) {
+	ids, err := redis.Strings(s.rdb.Do(ctx, "SMEMBERS", wmDirtyKey))
+	if err != nil {
+		return 0, err
+	}
+
+	var flushed int
+	var firstErr error
+	for _, raw := range ids {
+		if _, err := s.rdb.Do(ctx, "SREM", wmDirtyKey, raw); err != nil {
+			return flushed, err
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			continue
+		}
+		if err := s.flushSession(ctx, id); err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				_, _ = s.rdb.Do(ctx, "SADD", wmDirtyKey, raw)
+				if firstErr == nil {
+					firstErr = fmt.Errorf("flush session %s: %w", id, err)
+				}
+			}
+			continue
+		}
+		flushed++
+	}
+	return flushed, firstErr
+}
+
+func (s *RedisWorkingMemoryStore) flushSession(ctx context.Context, id uuid.UUID) error {
+	sess, err := s.cachedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	replies, err := s.rdb.Pipeline(ctx,
+		[]any{"HGETALL", wmActKey(id)},
+		[]any{"HGETALL", wmSchemaKey(id)},
+	)
+	if err != nil {
+		return err
+	}
+	actFields, err := redis.Strings(replies[0], nil)
+	if err != nil {
+		return err
+	}
+	schemaFields, err := redis.Strings(replies[1], nil)
+	if err != nil {
+		return err
+	}
+	acts, err := decodeActivations(actFields)
+	if err != nil {
+		return err
+	}
+	schemaActs, err := decodeSchemaActivations(schemaFields)
+	if err != nil {
+		return err
+	}
+
+	if err := s.pg.ReplaceState(domain.WithTenantID(ctx, sess.TenantID), sess, acts, schemaActs); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			// Deleted or expired in Postgres: drop the orphaned cache entry.
+			s.evict(ctx, sess)
+		}
+		return err
+	}
+	return nil
+}
+
+// Verify interface compliance at compile time
+var _ domain.WorkingMemoryStore = (*RedisWorkingMemoryStore)(nil)
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type WorkingMemorySettingsStore struct {
+	db DB
+}
+
+func NewWorkingMemorySettingsStore(db DB) *WorkingMemorySettingsStore {
+	return &WorkingMemorySettingsStore{db: db}
+}
+
+// Get returns the agent's stored settings, or ErrNotFound if it has none.
+func (s *WorkingMemorySettingsStore) Get(ctx context.Context, agentID, tenantID uuid.UUID) (*domain.WorkingMemorySettings, error) {
+	ws := &domain.WorkingMemorySettings{}
+	err := s.db.QueryRow(ctx,
+		`SELECT agent_id, tenant_id, slots, expanded_slots, updated_at
+		 FROM working_memory_settings WHERE agent_id = $1 AND tenant_id = $2`,
+		agentID, tenantID,
+	).Scan(&ws.AgentID, &ws.TenantID, &ws.Slots, &ws.ExpandedSlots, &ws.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return ws, err
+}
+
+// Upsert stores the agent's settings. Returns ErrNotFound if the agent does
+// not belong to the tenant.
+func (s *WorkingMemorySettingsStore) Upsert(ctx context.Context, ws *domain.WorkingMemorySettings) error {
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO working_memory_settings (agent_id, tenant_id, slots, expanded_slots)
+		 SELECT id, tenant_id, $3, $4 FROM agents WHERE id = $1 AND tenant_id = $2
+		 ON CONFLICT (agent_id) DO UPDATE SET
+			slots = EXCLUDED.slots,
+			expanded_slots = EXCLUDED.expanded_slots,
+			updated_at = NOW()
+		 RETURNING updated_at`,
+		ws.AgentID, ws.TenantID, ws.Slots, ws.ExpandedSlots,
+	).Scan(&ws.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type WorkingMemorySnapshotStore struct {
+	db DB
+}
+
+func NewWorkingMemorySnapshotStore(db DB) *WorkingMemorySnapshotStore {
+	return &WorkingMemorySnapshotStore{db: db}
+}
+
+// snapshotState is the JSON document stored in working_memory_snapshots.state.
+type snapshotState struct {
+	Goal           string                           `json:"goal,omitempty"`
+	ActiveContext  []domain.Message                 `json:"active_context,omitempty"`
+	ReasoningState map[string]any                   `json:"reasoning_state,omitempty"`
+	MaxSlots       int                              `json:"max_slots"`
+	Activations    []domain.WorkingMemoryActivation `json:"activations,omitempty"`
+	ActiveSchemas  []domain.SchemaActivation        `json:"active_schemas,omitempty"`
+}
+
+// Create stores the snapshot. Returns ErrNotFound if the agent does not
+// belong to the tenant.
+func (s *WorkingMemorySnapshotStore) Create(ctx context.Context, snap *domain.WorkingMemorySnapshot) error {
+	state, err := json.Marshal(snapshotState{
+		Goal:           snap.Goal,
+		ActiveContext:  snap.ActiveContext,
+		ReasoningState: snap.ReasoningState,
+		MaxSlots:       snap.MaxSlots,
+		Activations:    snap.Activations,
+		ActiveSchemas:  snap.ActiveSchemas,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal snapshot state: %w", err)
+	}
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO working_memory_snapshots (tenant_id, agent_id, label, state)
+		 SELECT tenant_id, id, $3, $4 FROM agents WHERE id = $1 AND tenant_id = $2
+		 RETURNING id, created_at`,
+		snap.AgentID, snap.TenantID, snap.Label, state,
+	).Scan(&snap.ID, &snap.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func scanSnapshot(row pgx.Row) (*domain.WorkingMemorySnapshot, error) {
+	var snap domain.WorkingMemorySnapshot
+	var raw []byte
+	if err := row.Scan(&snap.ID, &snap.TenantID, &snap.AgentID, &snap.Label, &raw, &snap.CreatedAt); err != nil {
+		return nil, err
+	}
+	var state snapshotState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("unmarshal snapshot state: %w", err)
+	}
+	snap.Goal = state.Goal
+	snap.ActiveContext = state.ActiveContext
+	snap.ReasoningState = state.ReasoningState
+	snap.MaxSlots = state.MaxSlots
+	snap.Activations = state.Activations
+	snap.ActiveSchemas = state.ActiveSchemas
+	return &snap, nil
+}
+
+func (s *WorkingMemorySnapshotStore) GetByID(ctx context.Context, id, tenantID uuid.UUID) (*domain.WorkingMemorySnapshot, error) {
+	snap, err := scanSnapshot(s.db.QueryRow(ctx,
+		`SELECT id, tenant_id, agent_id, label, state, created_at
+		 FROM working_memory_snapshots WHERE id = $1 AND tenant_id = $2`,
+		id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return snap, err
+}
+
+// ListByAgent returns the agent's snapshots, newest first.
+func (s *WorkingMemorySnapshotStore) ListByAgent(ctx context.Context, agentID, tenantID uuid.UUID) ([]domain.WorkingMemorySnapshot, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, tenant_id, agent_id, label, state, created_at
+		 FROM working_memory_snapshots WHERE agent_id = $1 AND tenant_id = $2
+		 ORDER BY created_at DESC`,
+		agentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snaps []domain.WorkingMemorySnapshot
+	for rows.Next() {
+		snap, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, *snap)
+	}
+	return snaps, rows.Err()
+}
+
+func (s *WorkingMemorySnapshotStore) Delete(ctx context.Context, id, tenantID uuid.UUID) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM working_memory_snapshots WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
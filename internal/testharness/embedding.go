@@ -0,0 +1,56 @@
+package testharness
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Harshitk-cp/engram/internal/embedding"
+)
+
+// Embedder returns the same vector for the same text on every run. Alias
+// makes different texts embed identically, so near-duplicate and similarity
+// paths can be driven without a real model.
+type Embedder struct {
+	mock *embedding.MockClient
+
+	mu      sync.Mutex
+	aliases map[string]string
+	calls   []string
+}
+
+// NewEmbedder returns an embedder producing 1536-dimensional unit vectors.
+func NewEmbedder() *Embedder {
+	return NewEmbedderDim(0)
+}
+
+// NewEmbedderDim returns an embedder producing vectors of the given width.
+func NewEmbedderDim(dim int) *Embedder {
+	return &Embedder{mock: embedding.NewMockClientDim(dim), aliases: make(map[string]string)}
+}
+
+// Alias makes text embed exactly as canonical does.
+func (e *Embedder) Alias(text, canonical string) {
+	e.mu.Lock()
+	e.aliases[text] = canonical
+	e.mu.Unlock()
+}
+
+// Embed implements domain.EmbeddingClient.
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, text)
+	if canonical, ok := e.aliases[text]; ok {
+		text = canonical
+	}
+	v, err := e.mock.Embed(ctx, text)
+	e.mock.EmbedCalls = nil // the call log above is the one callers see
+	return v, err
+}
+
+// Calls returns the texts embedded so far, in order.
+func (e *Embedder) Calls() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.calls...)
+}
@@ -0,0 +1,45 @@
+// Package testharness makes time- and model-dependent behaviour reproducible
+// in tests: a fake clock installed as the service clock, deterministic
+// embeddings, and an LLM whose answers are scripted per call.
+//
+//	h := testharness.New(t)
+//	h.LLM.CheckContradictionFunc = func(a, b string) (bool, error) { return strings.Contains(b, "not"), nil }
+//	svc := service.NewDecayService(store, nil, logger)
+//	h.Clock.Advance(30 * 24 * time.Hour)
+//	svc.BatchDecay(ctx, agentID)
+package testharness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/clock"
+)
+
+// Epoch is the instant every harness clock starts at.
+var Epoch = time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+// Harness bundles the deterministic stand-ins.
+type Harness struct {
+	Clock    *clock.Fake
+	LLM      *LLM
+	Embedder *Embedder
+}
+
+// New installs a fake clock stopped at Epoch as the process clock until the
+// test ends. Tests using a harness must not call t.Parallel.
+func New(t testing.TB) *Harness {
+	t.Helper()
+	fake := clock.NewFake(Epoch)
+	t.Cleanup(clock.Set(fake))
+	return &Harness{
+		Clock:    fake,
+		LLM:      NewLLM(),
+		Embedder: NewEmbedder(),
+	}
+}
+
+// Ago returns the harness time d before now, for back-dating fixtures.
+func (h *Harness) Ago(d time.Duration) time.Time {
+	return h.Clock.Now().Add(-d)
+}
@@ -0,0 +1,86 @@
+package testharness
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/clock"
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+func TestHarness_ClockRestoredOnCleanup(t *testing.T) {
+	t.Run("inner", func(t *testing.T) {
+		h := New(t)
+		if !clock.Now().Equal(Epoch) {
+			t.Fatalf("expected the fake clock at %v, got %v", Epoch, clock.Now())
+		}
+		h.Clock.Advance(time.Hour)
+		if got := h.Ago(time.Hour); !got.Equal(Epoch) {
+			t.Fatalf("expected Ago to count back from the fake clock, got %v", got)
+		}
+	})
+	if time.Since(clock.Now()) > time.Minute {
+		t.Fatal("expected the real clock to be restored after the test")
+	}
+}
+
+func TestEmbedder_DeterministicAndAliased(t *testing.T) {
+	e := NewEmbedderDim(8)
+	ctx := context.Background()
+
+	a, _ := e.Embed(ctx, "the user likes tea")
+	b, _ := e.Embed(ctx, "the user likes tea")
+	if !equalVec(a, b) {
+		t.Fatal("expected identical embeddings for identical text")
+	}
+
+	e.Alias("the user enjoys tea", "the user likes tea")
+	c, _ := e.Embed(ctx, "the user enjoys tea")
+	if !equalVec(a, c) {
+		t.Fatal("expected an aliased text to embed like its canonical text")
+	}
+	if calls := e.Calls(); len(calls) != 3 || calls[2] != "the user enjoys tea" {
+		t.Fatalf("expected the original texts to be recorded, got %v", calls)
+	}
+}
+
+func TestLLM_ScriptedResponses(t *testing.T) {
+	l := NewLLM()
+	ctx := context.Background()
+
+	if _, err := l.CheckContradiction(ctx, "a", "b"); err != nil {
+		t.Fatalf("expected the mock default, got %v", err)
+	}
+
+	next := Sequence(true, false)
+	l.CheckContradictionFunc = func(a, b string) (bool, error) { return next(), nil }
+	for i, want := range []bool{true, false, false} {
+		got, _ := l.CheckContradiction(ctx, "a", "b")
+		if got != want {
+			t.Fatalf("call %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	boom := errors.New("boom")
+	l.ClassifyFunc = func(string) (domain.MemoryType, error) { return "", boom }
+	if _, err := l.Classify(ctx, "x"); !errors.Is(err, boom) {
+		t.Fatalf("expected the scripted error, got %v", err)
+	}
+	if len(l.CheckContradictionCalls) != 4 || len(l.ClassifyCalls) != 1 {
+		t.Fatalf("expected calls to be recorded, got %d and %d", len(l.CheckContradictionCalls), len(l.ClassifyCalls))
+	}
+}
+
+func equalVec(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
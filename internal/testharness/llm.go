@@ -0,0 +1,198 @@
+package testharness
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/Harshitk-cp/engram/internal/llm"
+)
+
+// LLM is a domain.LLMClient with scriptable answers. A method whose Func is
+// set answers from it; otherwise it answers with the embedded mock's fixed
+// response. Every call is recorded on the mock either way, and calls are
+// serialised so concurrent services can share one LLM.
+type LLM struct {
+	*llm.MockClient
+
+	ClassifyFunc                func(content string) (domain.MemoryType, error)
+	ExtractFunc                 func(conversation []domain.Message) ([]domain.ExtractedMemory, error)
+	IngestConversationFunc      func(messages []domain.Message) ([]domain.ExtractedConversationMemory, error)
+	SummarizeFunc               func(memories []domain.Memory) (string, error)
+	CheckContradictionFunc      func(stmtA, stmtB string) (bool, error)
+	CheckTensionFunc            func(stmtA, stmtB string) (*domain.TensionResult, error)
+	ExtractEpisodeStructureFunc func(content string) (*domain.EpisodeExtraction, error)
+	ExtractProcedureFunc        func(content string) (*domain.ProcedureExtraction, error)
+	DetectSchemaPatternFunc     func(memories []domain.Memory) (*domain.SchemaExtraction, error)
+	DetectImplicitFeedbackFunc  func(memories []domain.Memory, conversation []domain.Message) ([]domain.ImplicitFeedback, error)
+	ExtractEntitiesFunc         func(content string) ([]domain.ExtractedEntity, error)
+	DetectRelationshipsFunc     func(memory *domain.Memory, similar []domain.MemoryWithScore) ([]domain.DetectedRelationship, error)
+
+	GenerateVerificationQuestionsFunc func(beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error)
+	NarrateKnowledgeFunc              func(topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error)
+
+	mu sync.Mutex
+}
+
+// NewLLM returns an LLM answering with llm.NewMockClient's defaults.
+func NewLLM() *LLM {
+	return &LLM{MockClient: llm.NewMockClient()}
+}
+
+// Sequence returns a func that answers with each response in turn and then
+// keeps repeating the last one — for scripting a run of calls.
+func Sequence[T any](responses ...T) func() T {
+	var mu sync.Mutex
+	i := 0
+	return func() T {
+		mu.Lock()
+		defer mu.Unlock()
+		r := responses[i]
+		if i < len(responses)-1 {
+			i++
+		}
+		return r
+	}
+}
+
+func (l *LLM) Classify(ctx context.Context, content string) (domain.MemoryType, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, err := l.MockClient.Classify(ctx, content)
+	if l.ClassifyFunc != nil {
+		return l.ClassifyFunc(content)
+	}
+	return t, err
+}
+
+func (l *LLM) Extract(ctx context.Context, conversation []domain.Message) ([]domain.ExtractedMemory, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.Extract(ctx, conversation)
+	if l.ExtractFunc != nil {
+		return l.ExtractFunc(conversation)
+	}
+	return out, err
+}
+
+func (l *LLM) IngestConversation(ctx context.Context, messages []domain.Message) ([]domain.ExtractedConversationMemory, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.IngestConversation(ctx, messages)
+	if l.IngestConversationFunc != nil {
+		return l.IngestConversationFunc(messages)
+	}
+	return out, err
+}
+
+func (l *LLM) Summarize(ctx context.Context, memories []domain.Memory) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.Summarize(ctx, memories)
+	if l.SummarizeFunc != nil {
+		return l.SummarizeFunc(memories)
+	}
+	return out, err
+}
+
+func (l *LLM) CheckContradiction(ctx context.Context, stmtA, stmtB string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.CheckContradiction(ctx, stmtA, stmtB)
+	if l.CheckContradictionFunc != nil {
+		return l.CheckContradictionFunc(stmtA, stmtB)
+	}
+	return out, err
+}
+
+func (l *LLM) CheckTension(ctx context.Context, stmtA, stmtB string) (*domain.TensionResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.CheckTension(ctx, stmtA, stmtB)
+	if l.CheckTensionFunc != nil {
+		return l.CheckTensionFunc(stmtA, stmtB)
+	}
+	return out, err
+}
+
+func (l *LLM) ExtractEpisodeStructure(ctx context.Context, content string) (*domain.EpisodeExtraction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.ExtractEpisodeStructure(ctx, content)
+	if l.ExtractEpisodeStructureFunc != nil {
+		return l.ExtractEpisodeStructureFunc(content)
+	}
+	return out, err
+}
+
+func (l *LLM) ExtractProcedure(ctx context.Context, content string) (*domain.ProcedureExtraction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.ExtractProcedure(ctx, content)
+	if l.ExtractProcedureFunc != nil {
+		return l.ExtractProcedureFunc(content)
+	}
+	return out, err
+}
+
+func (l *LLM) DetectSchemaPattern(ctx context.Context, memories []domain.Memory) (*domain.SchemaExtraction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.DetectSchemaPattern(ctx, memories)
+	if l.DetectSchemaPatternFunc != nil {
+		return l.DetectSchemaPatternFunc(memories)
+	}
+	return out, err
+}
+
+func (l *LLM) DetectImplicitFeedback(ctx context.Context, memories []domain.Memory, conversation []domain.Message) ([]domain.ImplicitFeedback, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.DetectImplicitFeedback(ctx, memories, conversation)
+	if l.DetectImplicitFeedbackFunc != nil {
+		return l.DetectImplicitFeedbackFunc(memories, conversation)
+	}
+	return out, err
+}
+
+func (l *LLM) ExtractEntities(ctx context.Context, content string) ([]domain.ExtractedEntity, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.ExtractEntities(ctx, content)
+	if l.ExtractEntitiesFunc != nil {
+		return l.ExtractEntitiesFunc(content)
+	}
+	return out, err
+}
+
+func (l *LLM) DetectRelationships(ctx context.Context, memory *domain.Memory, similar []domain.MemoryWithScore) ([]domain.DetectedRelationship, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.DetectRelationships(ctx, memory, similar)
+	if l.DetectRelationshipsFunc != nil {
+		return l.DetectRelationshipsFunc(memory, similar)
+	}
+	return out, err
+}
+
+func (l *LLM) GenerateVerificationQuestions(ctx context.Context, beliefs []domain.UncertainBelief) ([]domain.VerificationQuestion, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.GenerateVerificationQuestions(ctx, beliefs)
+	if l.GenerateVerificationQuestionsFunc != nil {
+		return l.GenerateVerificationQuestionsFunc(beliefs)
+	}
+	return out, err
+}
+
+func (l *LLM) NarrateKnowledge(ctx context.Context, topic string, sources domain.KnowledgeSources) (*domain.KnowledgeNarrative, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out, err := l.MockClient.NarrateKnowledge(ctx, topic, sources)
+	if l.NarrateKnowledgeFunc != nil {
+		return l.NarrateKnowledgeFunc(topic, sources)
+	}
+	return out, err
+}
+
+var _ domain.LLMClient = (*LLM)(nil)
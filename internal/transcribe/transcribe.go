@@ -0,0 +1,150 @@
+// Package transcribe turns recorded audio into timestamped transcript
+// segments through an OpenAI-compatible /audio/transcriptions endpoint:
+// OpenAI's Whisper API, or a self-hosted server (faster-whisper, whisper.cpp,
+// LocalAI) that mirrors its wire format.
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// DefaultBaseURL is OpenAI's API root.
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// transcribeHTTPTimeout bounds one transcription; long recordings take a while.
+const transcribeHTTPTimeout = 5 * time.Minute
+
+// Client is an OpenAI-compatible transcription client.
+type Client struct {
+	url        string // full /audio/transcriptions URL
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// New returns a client for model at baseURL, or nil when model is empty.
+// "/audio/transcriptions" is appended to baseURL unless already present.
+func New(baseURL, apiKey, model string) *Client {
+	if model == "" {
+		return nil
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	url := strings.TrimRight(baseURL, "/")
+	if !strings.HasSuffix(url, "/audio/transcriptions") {
+		url += "/audio/transcriptions"
+	}
+	return &Client{
+		url:        url,
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: transcribeHTTPTimeout},
+	}
+}
+
+// verboseTranscription is the response_format=verbose_json shape.
+type verboseTranscription struct {
+	Text     string  `json:"text"`
+	Duration float64 `json:"duration"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Transcribe uploads audio and returns its segments. The endpoint doesn't
+// tell speakers apart, so segments come back without a speaker. A server that
+// returns text without segments yields one segment spanning the recording.
+func (c *Client) Transcribe(ctx context.Context, audio io.Reader, filename string) ([]domain.TranscriptSegment, error) {
+	body, contentType, err := c.form(audio, filename)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("create transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if id := domain.RequestIDFrom(ctx); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("transcription request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result verboseTranscription
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode transcription response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("transcription error: %s", result.Error.Message)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("transcription returned status %d", resp.StatusCode)
+	}
+
+	var segments []domain.TranscriptSegment
+	for _, s := range result.Segments {
+		if text := strings.TrimSpace(s.Text); text != "" {
+			segments = append(segments, domain.TranscriptSegment{Start: s.Start, End: s.End, Text: text})
+		}
+	}
+	if len(segments) == 0 {
+		text := strings.TrimSpace(result.Text)
+		if text == "" {
+			return nil, errors.New("transcription is empty")
+		}
+		segments = []domain.TranscriptSegment{{End: result.Duration, Text: text}}
+	}
+	return segments, nil
+}
+
+// form builds the multipart upload.
+func (c *Client) form(audio io.Reader, filename string) (*bytes.Buffer, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, f := range [][2]string{
+		{"model", c.model},
+		{"response_format", "verbose_json"},
+		{"timestamp_granularities[]", "segment"},
+	} {
+		if err := mw.WriteField(f[0], f[1]); err != nil {
+			return nil, "", err
+		}
+	}
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(fw, audio); err != nil {
+		return nil, "", fmt.Errorf("read audio: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, mw.FormDataContentType(), nil
+}
+
+var _ domain.Transcriber = (*Client)(nil)
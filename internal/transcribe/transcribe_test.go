@@ -0,0 +1,64 @@
+package transcribe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_TranscribeReturnsSegments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected request %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if r.FormValue("model") != "whisper-1" || r.FormValue("response_format") != "verbose_json" {
+			t.Errorf("unexpected form %v", r.MultipartForm.Value)
+		}
+		f, hdr, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("missing file: %v", err)
+		}
+		data, _ := io.ReadAll(f)
+		if hdr.Filename != "call.mp3" || string(data) != "audio-bytes" {
+			t.Errorf("unexpected file %q: %q", hdr.Filename, data)
+		}
+		_, _ = w.Write([]byte(`{"text":"Hi. Hello there.","duration":3.5,"segments":[
+			{"start":0,"end":1.2,"text":" Hi."},{"start":1.2,"end":1.4,"text":"  "},{"start":1.5,"end":3.5,"text":" Hello there."}]}`))
+	}))
+	defer srv.Close()
+
+	segments, err := New(srv.URL+"/v1", "secret", "whisper-1").Transcribe(context.Background(), strings.NewReader("audio-bytes"), "call.mp3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segments) != 2 || segments[0].Text != "Hi." || segments[1].Start != 1.5 || segments[1].End != 3.5 {
+		t.Fatalf("unexpected segments %+v", segments)
+	}
+}
+
+func TestClient_TranscribeWithoutSegments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"text":"The whole recording.","duration":12}`))
+	}))
+	defer srv.Close()
+
+	segments, err := New(srv.URL, "", "base").Transcribe(context.Background(), strings.NewReader("x"), "a.wav")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segments) != 1 || segments[0].End != 12 || segments[0].Text != "The whole recording." {
+		t.Fatalf("unexpected segments %+v", segments)
+	}
+}
+
+func TestNew_DisabledWithoutModel(t *testing.T) {
+	if New("", "key", "") != nil {
+		t.Fatal("expected no client without a model")
+	}
+}
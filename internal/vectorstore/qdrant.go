@@ -0,0 +1,150 @@
+package vectorstore
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultQdrantCollection holds memory points when no collection is named.
+const DefaultQdrantCollection = "engram_memories"
+
+// Qdrant stores memory vectors in one Qdrant collection, using the memory ID
+// as the point ID. The collection is created with cosine distance, sized to
+// the first embedding written, if it doesn't exist.
+type Qdrant struct {
+	http       httpClient
+	collection string
+}
+
+func NewQdrant(baseURL, apiKey, collection string) *Qdrant {
+	if collection == "" {
+		collection = DefaultQdrantCollection
+	}
+	return &Qdrant{
+		http: newHTTPClient(baseURL, func(r *http.Request) {
+			if apiKey != "" {
+				r.Header.Set("api-key", apiKey)
+			}
+		}),
+		collection: collection,
+	}
+}
+
+type qdrantPoint struct {
+	ID      uuid.UUID      `json:"id"`
+	Vector  []float32      `json:"vector"`
+	Payload map[string]any `json:"payload"`
+}
+
+func (q *Qdrant) path(suffix string) string {
+	return "/collections/" + url.PathEscape(q.collection) + suffix
+}
+
+func (q *Qdrant) Upsert(ctx context.Context, points []domain.VectorPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	body := struct {
+		Points []qdrantPoint `json:"points"`
+	}{Points: make([]qdrantPoint, len(points))}
+	for i, p := range points {
+		body.Points[i] = qdrantPoint{
+			ID:     p.ID,
+			Vector: p.Embedding,
+			Payload: map[string]any{
+				"tenant_id": p.TenantID.String(),
+				"agent_id":  p.AgentID.String(),
+				"type":      string(p.Type),
+			},
+		}
+	}
+	err := q.http.do(ctx, http.MethodPut, q.path("/points?wait=true"), body, nil)
+	if isStatus(err, http.StatusNotFound) {
+		if err := q.createCollection(ctx, len(points[0].Embedding)); err != nil {
+			return err
+		}
+		err = q.http.do(ctx, http.MethodPut, q.path("/points?wait=true"), body, nil)
+	}
+	return err
+}
+
+// createCollection creates the collection with keyword indexes on the fields
+// every search filters by. A concurrent creator winning the race is fine.
+func (q *Qdrant) createCollection(ctx context.Context, dim int) error {
+	err := q.http.do(ctx, http.MethodPut, q.path(""), map[string]any{
+		"vectors": map[string]any{"size": dim, "distance": "Cosine"},
+	}, nil)
+	if err != nil && !isStatus(err, http.StatusConflict) {
+		return err
+	}
+	for _, field := range []string{"tenant_id", "agent_id", "type"} {
+		if err := q.http.do(ctx, http.MethodPut, q.path("/index?wait=true"), map[string]any{
+			"field_name": field, "field_schema": "keyword",
+		}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *Qdrant) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := q.http.do(ctx, http.MethodPost, q.path("/points/delete?wait=true"), map[string]any{"points": ids}, nil)
+	if isStatus(err, http.StatusNotFound) {
+		return nil
+	}
+	return err
+}
+
+type qdrantMatch struct {
+	Key   string `json:"key"`
+	Match any    `json:"match"`
+}
+
+func (q *Qdrant) Search(ctx context.Context, vq domain.VectorQuery) ([]domain.VectorMatch, error) {
+	must := []qdrantMatch{{Key: "tenant_id", Match: map[string]any{"value": vq.TenantID.String()}}}
+	if vq.AgentID != uuid.Nil {
+		must = append(must, qdrantMatch{Key: "agent_id", Match: map[string]any{"value": vq.AgentID.String()}})
+	}
+	if len(vq.Types) > 0 {
+		types := make([]string, len(vq.Types))
+		for i, t := range vq.Types {
+			types[i] = string(t)
+		}
+		must = append(must, qdrantMatch{Key: "type", Match: map[string]any{"any": types}})
+	}
+	body := map[string]any{
+		"vector": vq.Embedding,
+		"limit":  vq.Limit,
+		"filter": map[string]any{"must": must},
+	}
+	if vq.MinScore > 0 {
+		body["score_threshold"] = vq.MinScore
+	}
+
+	var resp struct {
+		Result []struct {
+			ID    uuid.UUID `json:"id"`
+			Score float32   `json:"score"`
+		} `json:"result"`
+	}
+	err := q.http.do(ctx, http.MethodPost, q.path("/points/search"), body, &resp)
+	if isStatus(err, http.StatusNotFound) {
+		// Nothing has been written yet.
+		return []domain.VectorMatch{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]domain.VectorMatch, len(resp.Result))
+	for i, r := range resp.Result {
+		matches[i] = domain.VectorMatch{ID: r.ID, Score: r.Score}
+	}
+	return matches, nil
+}
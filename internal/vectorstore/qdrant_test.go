@@ -0,0 +1,111 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestQdrant_UpsertCreatesMissingCollection(t *testing.T) {
+	var calls []string
+	created := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Header.Get("api-key") != "secret" {
+			t.Errorf("missing api key header")
+		}
+		switch r.URL.Path {
+		case "/collections/mem":
+			var body struct {
+				Vectors struct {
+					Size     int    `json:"size"`
+					Distance string `json:"distance"`
+				} `json:"vectors"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Vectors.Size != 3 || body.Vectors.Distance != "Cosine" {
+				t.Errorf("unexpected collection config %+v", body.Vectors)
+			}
+			created = true
+		case "/collections/mem/points":
+			if !created {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var body struct {
+				Points []qdrantPoint `json:"points"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Points) != 1 || body.Points[0].Payload["type"] != "fact" {
+				t.Errorf("unexpected points %+v", body.Points)
+			}
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	q := NewQdrant(srv.URL, "secret", "mem")
+	err := q.Upsert(context.Background(), []domain.VectorPoint{{
+		ID: uuid.New(), TenantID: uuid.New(), AgentID: uuid.New(), Type: domain.MemoryTypeFact, Embedding: []float32{1, 0, 0},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Failed write, collection, three payload indexes, retried write.
+	if len(calls) != 6 {
+		t.Fatalf("expected 6 calls, got %v", calls)
+	}
+}
+
+func TestQdrant_SearchScopesByTenantAgentAndType(t *testing.T) {
+	tenantID, agentID, hit := uuid.New(), uuid.New(), uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Limit          int     `json:"limit"`
+			ScoreThreshold float32 `json:"score_threshold"`
+			Filter         struct {
+				Must []struct {
+					Key   string         `json:"key"`
+					Match map[string]any `json:"match"`
+				} `json:"must"`
+			} `json:"filter"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		must := body.Filter.Must
+		if body.Limit != 20 || body.ScoreThreshold != 0.5 || len(must) != 3 {
+			t.Errorf("unexpected search %+v", body)
+		} else if must[0].Match["value"] != tenantID.String() || must[1].Match["value"] != agentID.String() || must[2].Key != "type" {
+			t.Errorf("unexpected filter %+v", must)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": []map[string]any{{"id": hit, "score": 0.9}}})
+	}))
+	defer srv.Close()
+
+	matches, err := NewQdrant(srv.URL, "", "").Search(context.Background(), domain.VectorQuery{
+		TenantID: tenantID, AgentID: agentID, Embedding: []float32{1, 0}, Types: []domain.MemoryType{domain.MemoryTypeFact},
+		MinScore: 0.5, Limit: 20,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != hit || matches[0].Score != 0.9 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+}
+
+func TestQdrant_SearchMissingCollectionIsEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	matches, err := NewQdrant(srv.URL, "", "").Search(context.Background(), domain.VectorQuery{TenantID: uuid.New(), Limit: 5})
+	if err != nil || matches == nil || len(matches) != 0 {
+		t.Fatalf("expected no matches and no error, got %v, %v", matches, err)
+	}
+}
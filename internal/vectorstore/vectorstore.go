@@ -0,0 +1,102 @@
+// Package vectorstore implements domain.VectorStore over the HTTP APIs of
+// external vector databases, so memory similarity search can move off
+// pgvector at high scale. Each point carries the memory's tenant, agent and
+// type as payload; searches are always scoped by tenant. Postgres remains the
+// system of record and re-checks every candidate (see store.MemoryStore).
+package vectorstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+)
+
+// New returns the store named by kind ("qdrant" or "weaviate"), or nil for
+// "" and "pgvector", which keep search in Postgres. collection is the Qdrant
+// collection or Weaviate class; empty uses the default.
+func New(kind, url, apiKey, collection string) (domain.VectorStore, error) {
+	switch kind {
+	case "", "pgvector":
+		return nil, nil
+	case "qdrant":
+		return NewQdrant(url, apiKey, collection), nil
+	case "weaviate":
+		return NewWeaviate(url, apiKey, collection), nil
+	default:
+		return nil, fmt.Errorf("unknown vector store %q", kind)
+	}
+}
+
+// httpClient is the JSON-over-HTTP plumbing shared by the adapters.
+type httpClient struct {
+	baseURL string
+	// auth sets the adapter's credentials on each request.
+	auth func(*http.Request)
+	hc   *http.Client
+}
+
+func newHTTPClient(baseURL string, auth func(*http.Request)) httpClient {
+	return httpClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		auth:    auth,
+		hc:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// statusError is a non-2xx response.
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("vector store responded %d: %s", e.status, e.body)
+}
+
+func isStatus(err error, status int) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.status == status
+}
+
+// do sends in as JSON and decodes the response into out, when both are set.
+func (c httpClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.auth(req)
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return &statusError{status: resp.StatusCode, body: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,197 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultWeaviateClass holds memory objects when no class is named.
+const DefaultWeaviateClass = "EngramMemory"
+
+// Weaviate stores memory vectors as objects of one Weaviate class, using the
+// memory ID as the object ID. The class is created on first write with no
+// vectorizer (Engram supplies the vectors) and cosine distance.
+type Weaviate struct {
+	http  httpClient
+	class string
+
+	mu    sync.Mutex
+	ready bool
+}
+
+func NewWeaviate(baseURL, apiKey, class string) *Weaviate {
+	if class == "" {
+		class = DefaultWeaviateClass
+	}
+	return &Weaviate{
+		http: newHTTPClient(baseURL, func(r *http.Request) {
+			if apiKey != "" {
+				r.Header.Set("Authorization", "Bearer "+apiKey)
+			}
+		}),
+		class: class,
+	}
+}
+
+// ensureClass creates the class once per process; 422 means it exists.
+func (w *Weaviate) ensureClass(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ready {
+		return nil
+	}
+	prop := func(name string) map[string]any {
+		return map[string]any{"name": name, "dataType": []string{"text"}, "tokenization": "field"}
+	}
+	err := w.http.do(ctx, http.MethodPost, "/v1/schema", map[string]any{
+		"class":             w.class,
+		"vectorizer":        "none",
+		"vectorIndexConfig": map[string]any{"distance": "cosine"},
+		"properties":        []any{prop("tenant_id"), prop("agent_id"), prop("type")},
+	}, nil)
+	if err != nil && !isStatus(err, http.StatusUnprocessableEntity) {
+		return err
+	}
+	w.ready = true
+	return nil
+}
+
+type weaviateObject struct {
+	Class      string            `json:"class"`
+	ID         uuid.UUID         `json:"id"`
+	Vector     []float32         `json:"vector"`
+	Properties map[string]string `json:"properties"`
+}
+
+// Upsert writes points with the batch API, which replaces objects whose ID
+// already exists.
+func (w *Weaviate) Upsert(ctx context.Context, points []domain.VectorPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	if err := w.ensureClass(ctx); err != nil {
+		return err
+	}
+	body := struct {
+		Objects []weaviateObject `json:"objects"`
+	}{Objects: make([]weaviateObject, len(points))}
+	for i, p := range points {
+		body.Objects[i] = weaviateObject{
+			Class:  w.class,
+			ID:     p.ID,
+			Vector: p.Embedding,
+			Properties: map[string]string{
+				"tenant_id": p.TenantID.String(),
+				"agent_id":  p.AgentID.String(),
+				"type":      string(p.Type),
+			},
+		}
+	}
+
+	// The batch endpoint answers 200 and reports failures per object.
+	var resp []struct {
+		ID     string `json:"id"`
+		Result struct {
+			Errors *struct {
+				Error []struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+	if err := w.http.do(ctx, http.MethodPost, "/v1/batch/objects", body, &resp); err != nil {
+		return err
+	}
+	for _, r := range resp {
+		if r.Result.Errors != nil && len(r.Result.Errors.Error) > 0 {
+			return fmt.Errorf("weaviate object %s: %s", r.ID, r.Result.Errors.Error[0].Message)
+		}
+	}
+	return nil
+}
+
+func (w *Weaviate) Delete(ctx context.Context, ids []uuid.UUID) error {
+	for _, id := range ids {
+		err := w.http.do(ctx, http.MethodDelete, "/v1/objects/"+url.PathEscape(w.class)+"/"+id.String(), nil, nil)
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search runs a nearVector GraphQL query. Weaviate reports cosine distance,
+// converted here to similarity.
+func (w *Weaviate) Search(ctx context.Context, vq domain.VectorQuery) ([]domain.VectorMatch, error) {
+	vec, err := json.Marshal(vq.Embedding)
+	if err != nil {
+		return nil, err
+	}
+	near := "vector: " + string(vec)
+	if vq.MinScore > 0 {
+		near += fmt.Sprintf(", distance: %g", 1-vq.MinScore)
+	}
+	query := fmt.Sprintf(`{ Get { %s(nearVector: {%s}, limit: %d, where: %s) { _additional { id distance } } } }`,
+		w.class, near, vq.Limit, weaviateWhere(vq))
+
+	var resp struct {
+		Data struct {
+			Get map[string][]struct {
+				Additional struct {
+					ID       uuid.UUID `json:"id"`
+					Distance float32   `json:"distance"`
+				} `json:"_additional"`
+			} `json:"Get"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := w.http.do(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": query}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		msg := resp.Errors[0].Message
+		if strings.Contains(msg, "Cannot query field") {
+			// The class doesn't exist until the first write.
+			return []domain.VectorMatch{}, nil
+		}
+		return nil, errors.New("weaviate: " + msg)
+	}
+	hits := resp.Data.Get[w.class]
+	matches := make([]domain.VectorMatch, len(hits))
+	for i, h := range hits {
+		matches[i] = domain.VectorMatch{ID: h.Additional.ID, Score: 1 - h.Additional.Distance}
+	}
+	return matches, nil
+}
+
+// weaviateWhere builds the GraphQL filter scoping a search. Values are UUIDs
+// and memory types, quoted with JSON string escaping.
+func weaviateWhere(vq domain.VectorQuery) string {
+	equal := func(path, value string) string {
+		v, _ := json.Marshal(value)
+		return fmt.Sprintf(`{path: ["%s"], operator: Equal, valueText: %s}`, path, v)
+	}
+	operands := []string{equal("tenant_id", vq.TenantID.String())}
+	if vq.AgentID != uuid.Nil {
+		operands = append(operands, equal("agent_id", vq.AgentID.String()))
+	}
+	if len(vq.Types) > 0 {
+		types := make([]string, len(vq.Types))
+		for i, t := range vq.Types {
+			types[i] = equal("type", string(t))
+		}
+		operands = append(operands, `{operator: Or, operands: [`+strings.Join(types, ", ")+`]}`)
+	}
+	return `{operator: And, operands: [` + strings.Join(operands, ", ") + `]}`
+}
@@ -0,0 +1,87 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Harshitk-cp/engram/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestWeaviate_UpsertReportsObjectErrors(t *testing.T) {
+	schemaCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing bearer token")
+		}
+		switch r.URL.Path {
+		case "/v1/schema":
+			schemaCalls++
+			w.WriteHeader(http.StatusUnprocessableEntity) // class exists
+		case "/v1/batch/objects":
+			_, _ = w.Write([]byte(`[{"id":"x","result":{"errors":{"error":[{"message":"vector lengths don't match"}]}}}]`))
+		}
+	}))
+	defer srv.Close()
+
+	wv := NewWeaviate(srv.URL, "secret", "")
+	p := []domain.VectorPoint{{ID: uuid.New(), TenantID: uuid.New(), AgentID: uuid.New(), Embedding: []float32{1}}}
+	for range 2 {
+		err := wv.Upsert(context.Background(), p)
+		if err == nil || !strings.Contains(err.Error(), "vector lengths") {
+			t.Fatalf("expected the object error, got %v", err)
+		}
+	}
+	if schemaCalls != 1 {
+		t.Fatalf("expected the class to be ensured once, got %d calls", schemaCalls)
+	}
+}
+
+func TestWeaviate_SearchConvertsDistance(t *testing.T) {
+	tenantID, hit := uuid.New(), uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, want := range []string{"EngramMemory(nearVector:", "distance: 0.25", "limit: 10", tenantID.String(), `valueText: "fact"`} {
+			if !strings.Contains(body.Query, want) {
+				t.Errorf("query %q lacks %q", body.Query, want)
+			}
+		}
+		if strings.Contains(body.Query, "agent_id") {
+			t.Errorf("expected a tenant-wide search, got %q", body.Query)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"Get": map[string]any{
+			"EngramMemory": []map[string]any{{"_additional": map[string]any{"id": hit, "distance": 0.2}}},
+		}}})
+	}))
+	defer srv.Close()
+
+	matches, err := NewWeaviate(srv.URL, "", "").Search(context.Background(), domain.VectorQuery{
+		TenantID: tenantID, Embedding: []float32{0.1, 0.2}, Types: []domain.MemoryType{domain.MemoryTypeFact},
+		MinScore: 0.75, Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != hit || matches[0].Score < 0.79 || matches[0].Score > 0.81 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+}
+
+func TestNew_SelectsBackend(t *testing.T) {
+	for kind, want := range map[string]bool{"": false, "pgvector": false, "qdrant": true, "weaviate": true} {
+		vs, err := New(kind, "http://localhost:1", "", "")
+		if err != nil || (vs != nil) != want {
+			t.Errorf("New(%q) = %v, %v", kind, vs, err)
+		}
+	}
+	if _, err := New("milvus", "http://localhost:1", "", ""); err == nil {
+		t.Error("expected an unknown backend to be rejected")
+	}
+}
@@ -0,0 +1,354 @@
+// Package websocket is a minimal RFC 6455 server covering what the streaming
+// session endpoint needs: the opening handshake, text and binary messages
+// (reassembled from fragments), ping/pong and the closing handshake.
+// Extensions and subprotocols are not negotiated.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Frame opcodes.
+const (
+	OpContinuation byte = 0x0
+	OpText         byte = 0x1
+	OpBinary       byte = 0x2
+	OpClose        byte = 0x8
+	OpPing         byte = 0x9
+	OpPong         byte = 0xA
+)
+
+// Close codes.
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseInvalidData   = 1007
+	CloseTooLarge      = 1009
+	CloseInternalError = 1011
+)
+
+// DefaultMaxMessageSize bounds a reassembled message when the Upgrader sets
+// no limit.
+const DefaultMaxMessageSize = 1 << 20
+
+// acceptGUID is appended to the client's key to derive Sec-WebSocket-Accept.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	ErrBadHandshake     = errors.New("websocket: bad handshake")
+	ErrOriginNotAllowed = errors.New("websocket: origin not allowed")
+	ErrMessageTooLarge  = errors.New("websocket: message too large")
+	ErrProtocol         = errors.New("websocket: protocol error")
+	ErrInvalidUTF8      = errors.New("websocket: invalid utf-8 in text message")
+)
+
+// CloseError is the close frame the peer sent.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed by peer (%d %s)", e.Code, e.Reason)
+}
+
+// Upgrader turns HTTP requests into WebSocket connections.
+type Upgrader struct {
+	// AllowedOrigins lists the browser origins allowed to connect besides the
+	// server's own; "*" allows any. Requests without an Origin header, from
+	// non-browser clients, are always allowed.
+	AllowedOrigins []string
+	// MaxMessageSize bounds a reassembled message; 0 means
+	// DefaultMaxMessageSize.
+	MaxMessageSize int64
+}
+
+// Upgrade completes the opening handshake and takes over the connection. On
+// failure it has already written the HTTP error response.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!headerHasToken(r.Header, "Connection", "upgrade") ||
+		!headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, ErrBadHandshake
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+	if !u.originAllowed(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return nil, ErrOriginNotAllowed
+	}
+
+	nc, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("websocket: hijack: %w", err)
+	}
+	// The HTTP server's deadlines no longer apply to a hijacked connection.
+	_ = nc.SetDeadline(time.Time{})
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+	if _, err := brw.WriteString(resp); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	if err := brw.Flush(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	maxSize := u.MaxMessageSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	return &Conn{nc: nc, br: brw.Reader, maxSize: maxSize}, nil
+}
+
+func (u *Upgrader) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if o, err := url.Parse(origin); err == nil && strings.EqualFold(o.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range u.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptKey derives the Sec-WebSocket-Accept value for a client key.
+func AcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Conn is a server-side WebSocket connection. One goroutine may read while
+// others write; writes are serialized.
+type Conn struct {
+	nc      net.Conn
+	br      *bufio.Reader
+	maxSize int64
+
+	wmu       sync.Mutex
+	closeSent bool
+}
+
+// SetReadDeadline bounds the next reads; a zero time removes the bound.
+func (c *Conn) SetReadDeadline(t time.Time) error { return c.nc.SetReadDeadline(t) }
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// and pongs dropped on the way. A close frame from the peer is answered and
+// returned as a *CloseError; protocol violations close the connection with
+// the matching code.
+func (c *Conn) ReadMessage() (byte, []byte, error) {
+	var (
+		op      byte
+		message []byte
+		started bool
+	)
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrMessageTooLarge):
+				_ = c.Close(CloseTooLarge, "message too large")
+			case errors.Is(err, ErrProtocol):
+				_ = c.Close(CloseProtocolError, "protocol error")
+			}
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			ce := &CloseError{Code: CloseNormal}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			_ = c.Close(ce.Code, "")
+			return 0, nil, ce
+		case OpText, OpBinary:
+			if started {
+				_ = c.Close(CloseProtocolError, "expected continuation frame")
+				return 0, nil, ErrProtocol
+			}
+			op, started = frameOp, true
+		case OpContinuation:
+			if !started {
+				_ = c.Close(CloseProtocolError, "unexpected continuation frame")
+				return 0, nil, ErrProtocol
+			}
+		default:
+			_ = c.Close(CloseProtocolError, "unknown opcode")
+			return 0, nil, ErrProtocol
+		}
+
+		if int64(len(message)+len(payload)) > c.maxSize {
+			_ = c.Close(CloseTooLarge, "message too large")
+			return 0, nil, ErrMessageTooLarge
+		}
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+		if op == OpText && !utf8.Valid(message) {
+			_ = c.Close(CloseInvalidData, "invalid utf-8")
+			return 0, nil, ErrInvalidUTF8
+		}
+		return op, message, nil
+	}
+}
+
+// readFrame reads one frame and unmasks its payload. Client frames must be
+// masked, control frames unfragmented and at most 125 bytes.
+func (c *Conn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	op = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := int64(head[1] & 0x7F)
+
+	if head[0]&0x70 != 0 || !masked {
+		return false, 0, nil, ErrProtocol
+	}
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n := binary.BigEndian.Uint64(ext[:])
+		if n > uint64(c.maxSize) {
+			return false, 0, nil, ErrMessageTooLarge
+		}
+		length = int64(n)
+	}
+	if op >= OpClose && (!fin || length > 125) {
+		return false, 0, nil, ErrProtocol
+	}
+	if length > c.maxSize {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// WriteMessage sends data as a single unmasked frame.
+func (c *Conn) WriteMessage(op byte, data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	return c.writeFrame(op, data)
+}
+
+// WriteJSON sends v encoded as a text message.
+func (c *Conn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(OpText, data)
+}
+
+func (c *Conn) writeFrame(op byte, data []byte) error {
+	head := make([]byte, 2, 10)
+	head[0] = 0x80 | op
+	switch n := len(data); {
+	case n <= 125:
+		head[1] = byte(n)
+	case n <= 0xFFFF:
+		head[1] = 126
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head[1] = 127
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+	_, err := c.nc.Write(append(head, data...))
+	return err
+}
+
+// Close sends a close frame with code and reason, unless one was already
+// sent, and closes the connection.
+func (c *Conn) Close(code int, reason string) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if !c.closeSent {
+		c.closeSent = true
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+		payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+		_ = c.writeFrame(OpClose, append(payload, reason...))
+	}
+	return c.nc.Close()
+}
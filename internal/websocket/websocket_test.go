@@ -0,0 +1,173 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// dial opens a raw connection to srv and completes the handshake.
+func dial(t *testing.T, srv *httptest.Server, header string) (net.Conn, *bufio.Reader, string) {
+	t.Helper()
+	nc, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { nc.Close() })
+	req := "GET / HTTP/1.1\r\nHost: " + strings.TrimPrefix(srv.URL, "http://") + "\r\n" +
+		"Connection: keep-alive, Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" + header + "\r\n"
+	if _, err := nc.Write([]byte(req)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	return nc, br, resp.Status + " " + resp.Header.Get("Sec-WebSocket-Accept")
+}
+
+// clientFrame encodes a masked client frame.
+func clientFrame(fin bool, op byte, payload []byte) []byte {
+	b := []byte{op, 0x80}
+	if fin {
+		b[0] |= 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		b[1] |= byte(n)
+	default:
+		b[1] |= 126
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	}
+	mask := []byte{1, 2, 3, 4}
+	b = append(b, mask...)
+	for i, c := range payload {
+		b = append(b, c^mask[i%4])
+	}
+	return b
+}
+
+// readServerFrame decodes one unmasked server frame.
+func readServerFrame(t *testing.T, br *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	n := int(head[1] & 0x7F)
+	if n == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(br, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+// echoServer echoes every message until the connection ends, reporting how it
+// ended.
+func echoServer(t *testing.T, u *Upgrader) (*httptest.Server, chan error) {
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := u.Upgrade(w, r)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close(CloseNormal, "")
+		for {
+			op, data, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			if err := conn.WriteMessage(op, data); err != nil {
+				done <- err
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, done
+}
+
+func TestUpgrade_EchoesFragmentedMessagesAndAnswersPings(t *testing.T) {
+	srv, done := echoServer(t, &Upgrader{})
+	nc, br, status := dial(t, srv, "")
+	if want := "101 Switching Protocols s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; status != want {
+		t.Fatalf("handshake: got %q, want %q", status, want)
+	}
+
+	// A ping between the fragments of a message is answered right away
+	_, _ = nc.Write(clientFrame(false, OpText, []byte("hel")))
+	_, _ = nc.Write(clientFrame(true, OpPing, []byte("p")))
+	_, _ = nc.Write(clientFrame(true, OpContinuation, []byte(strings.Repeat("lo", 100))))
+
+	if op, payload := readServerFrame(t, br); op != OpPong || string(payload) != "p" {
+		t.Fatalf("expected pong, got op %d %q", op, payload)
+	}
+	if op, payload := readServerFrame(t, br); op != OpText || string(payload) != "hel"+strings.Repeat("lo", 100) {
+		t.Fatalf("expected the reassembled message echoed, got op %d %q", op, payload)
+	}
+
+	_, _ = nc.Write(clientFrame(true, OpClose, binary.BigEndian.AppendUint16(nil, CloseGoingAway)))
+	if op, payload := readServerFrame(t, br); op != OpClose || binary.BigEndian.Uint16(payload) != CloseGoingAway {
+		t.Fatalf("expected the close echoed, got op %d %v", op, payload)
+	}
+	var ce *CloseError
+	if err := <-done; !errors.As(err, &ce) || ce.Code != CloseGoingAway {
+		t.Errorf("expected a close error, got %v", err)
+	}
+}
+
+func TestUpgrade_ClosesOnViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+		code  uint16
+		err   error
+	}{
+		{"unmasked", []byte{0x81, 0x01, 'x'}, CloseProtocolError, ErrProtocol},
+		{"too large", clientFrame(true, OpText, make([]byte, 200)), CloseTooLarge, ErrMessageTooLarge},
+		{"invalid utf-8", clientFrame(true, OpText, []byte{0xff, 0xfe}), CloseInvalidData, ErrInvalidUTF8},
+		{"stray continuation", clientFrame(true, OpContinuation, []byte("x")), CloseProtocolError, ErrProtocol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, done := echoServer(t, &Upgrader{MaxMessageSize: 100})
+			nc, br, _ := dial(t, srv, "")
+			_, _ = nc.Write(tt.frame)
+			if op, payload := readServerFrame(t, br); op != OpClose || binary.BigEndian.Uint16(payload) != tt.code {
+				t.Errorf("expected close %d, got op %d %v", tt.code, op, payload)
+			}
+			if err := <-done; !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestUpgrade_RejectsForeignOrigins(t *testing.T) {
+	srv, done := echoServer(t, &Upgrader{AllowedOrigins: []string{"https://app.example.com"}})
+
+	if _, _, status := dial(t, srv, "Origin: https://evil.example.com\r\n"); !strings.HasPrefix(status, "403") {
+		t.Errorf("expected a foreign origin to be refused, got %q", status)
+	}
+	if err := <-done; !errors.Is(err, ErrOriginNotAllowed) {
+		t.Errorf("expected ErrOriginNotAllowed, got %v", err)
+	}
+	if _, _, status := dial(t, srv, "Origin: https://app.example.com\r\n"); !strings.HasPrefix(status, "101") {
+		t.Errorf("expected an allowed origin to connect, got %q", status)
+	}
+}
@@ -794,6 +794,43 @@ func TestToolsList(t *testing.T) {
 	}
 }
 
+func TestOpenAITools_MatchMCPTools(t *testing.T) {
+	s := mcp.NewServer("engram", "test")
+	mcp.RegisterTools(s, mcp.NewClient("http://localhost", "k", "a"))
+	resp := s.Handle(context.Background(), &mcp.Request{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "tools/list"})
+	data, _ := json.Marshal(resp.Result)
+	var listed struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	_ = json.Unmarshal(data, &listed)
+	byName := map[string]mcp.Tool{}
+	for _, tool := range listed.Tools {
+		byName[tool.Name] = tool
+	}
+
+	tools := mcp.OpenAITools()
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Function.Name)
+		if tool.Type != "function" {
+			t.Errorf("%s: type = %q, want function", tool.Function.Name, tool.Type)
+		}
+		mcpTool, ok := byName[tool.Function.Name]
+		if !ok {
+			t.Errorf("%s is not an MCP tool, so its calls can't be forwarded", tool.Function.Name)
+			continue
+		}
+		got, _ := json.Marshal(tool.Function.Parameters)
+		want, _ := json.Marshal(mcpTool.InputSchema)
+		if string(got) != string(want) || tool.Function.Description != mcpTool.Description {
+			t.Errorf("%s: schema differs from the MCP tool", tool.Function.Name)
+		}
+	}
+	if strings.Join(names, ",") != "remember,recall,reflect,activate_context" {
+		t.Errorf("tools = %v", names)
+	}
+}
+
 // ─── Stdio transport tests ──────────────────────────────────────────────────────
 
 func stdioRoundtrip(t *testing.T, s *mcp.Server, requests ...interface{}) []map[string]interface{} {
@@ -0,0 +1,31 @@
+package mcp
+
+// OpenAITool is a tool definition in the OpenAI chat completions format,
+// accepted as-is in the request's "tools" list.
+type OpenAITool struct {
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the function half of an OpenAITool.
+type OpenAIFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Parameters  interface{} `json:"parameters"`
+}
+
+// OpenAITools returns the core memory tools (remember, recall, reflect and
+// activate_context) as OpenAI function schemas. They are built from the MCP
+// tool definitions, so a model's tool call can be executed by forwarding its
+// name and arguments to the MCP endpoint's tools/call unchanged.
+func OpenAITools() []OpenAITool {
+	tools := []Tool{rememberTool(), recallTool(), reflectTool(), activateContextTool()}
+	out := make([]OpenAITool, 0, len(tools))
+	for _, t := range tools {
+		out = append(out, OpenAITool{
+			Type:     "function",
+			Function: OpenAIFunction{Name: t.Name, Description: t.Description, Parameters: t.InputSchema},
+		})
+	}
+	return out
+}
@@ -971,8 +971,15 @@ func activateContextTool() Tool {
 				"description": "Salient terms/phrases from the current turn to activate memory from.",
 				"items":       map[string]interface{}{"type": "string"},
 			},
-			"goal":     strF("The agent's current goal, to bias activation. Optional."),
-			"agent_id": strF("Agent ID. Uses the default agent if omitted."),
+			"goal":               strF("The agent's current goal, to bias activation. Optional."),
+			"agent_id":           strF("Agent ID. Uses the default agent if omitted."),
+			"citations":          map[string]interface{}{"type": "boolean", "description": "Mark each item of the context with a citation marker like [m:3f2a9c1b] and return a map from marker to memory ID, so answers can say how they know something. Optional."},
+			"max_context_tokens": map[string]interface{}{"type": "integer", "minimum": 1, "description": "Cap on the assembled context's size in tokens; the least relevant items are dropped to fit. Optional."},
+			"topics": map[string]interface{}{
+				"type":        "array",
+				"description": "Topics of the conversation, checked by procedures with topic conditions. Optional.",
+				"items":       map[string]interface{}{"type": "string"},
+			},
 		}, "cues")}
 }
 func activateContextHandler(c *Client) ToolHandler {
@@ -991,6 +998,21 @@ func activateContextHandler(c *Client) ToolHandler {
 		if v := strArg(a, "goal"); v != "" {
 			body["goal"] = v
 		}
+		if v, _ := a["citations"].(bool); v {
+			body["citations"] = true
+		}
+		if v := intArg(a, "max_context_tokens", 0); v > 0 {
+			body["max_context_tokens"] = v
+		}
+		if rawTopics, _ := a["topics"].([]interface{}); len(rawTopics) > 0 {
+			topics := make([]string, 0, len(rawTopics))
+			for _, t := range rawTopics {
+				if s, ok := t.(string); ok {
+					topics = append(topics, s)
+				}
+			}
+			body["topics"] = topics
+		}
 		return rawResult(c.PostRaw(ctx, "/v1/cognitive/activate", body))
 	}
 }
@@ -0,0 +1,5 @@
+BEGIN;
+DROP INDEX IF EXISTS idx_schemas_parent;
+ALTER TABLE schemas DROP CONSTRAINT IF EXISTS schemas_parent_not_self;
+ALTER TABLE schemas DROP COLUMN IF EXISTS parent_id;
+COMMIT;
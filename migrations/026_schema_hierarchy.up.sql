@@ -0,0 +1,16 @@
+-- 026_schema_hierarchy.up.sql
+-- Parent/child schema relationships ("Developer" -> "Frontend Developer").
+-- A child inherits its ancestors' attributes, and matching a child partially
+-- activates its parent. Deleting a parent orphans its children rather than
+-- cascading. Cycles are rejected by the store when the parent is set.
+BEGIN;
+
+ALTER TABLE schemas
+    ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES schemas(id) ON DELETE SET NULL;
+
+ALTER TABLE schemas
+    ADD CONSTRAINT schemas_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_schemas_parent ON schemas(parent_id) WHERE parent_id IS NOT NULL;
+
+COMMIT;
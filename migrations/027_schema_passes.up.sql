@@ -0,0 +1,17 @@
+-- 027_schema_passes.up.sql
+-- Per-agent bookkeeping for incremental schema maintenance. Each schema pass
+-- records when it ran so the next pass only has to place memories that became
+-- eligible since then; a full re-cluster is forced periodically.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS schema_passes (
+    agent_id           UUID PRIMARY KEY REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id          UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    last_pass_at       TIMESTAMPTZ NOT NULL,
+    last_full_pass_at  TIMESTAMPTZ NOT NULL,
+    incremental_passes INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_schema_passes_tenant ON schema_passes(tenant_id);
+
+COMMIT;
@@ -0,0 +1,4 @@
+-- 028_summary_memory_type.down.sql
+-- Postgres cannot drop a value from an enum type, so only the summary rows are
+-- removed; the 'summary' value itself stays.
+DELETE FROM memories WHERE type = 'summary';
@@ -0,0 +1,8 @@
+-- 028_summary_memory_type.up.sql
+-- Adds the `summary` memory type: per-topic rollups that consolidation writes
+-- from clusters of related beliefs. Recall returns a summary in place of the
+-- detail memories it covers (listed in metadata.summarized_memory_ids), which
+-- stay stored and individually retrievable. Runs outside a transaction like
+-- 022, since Postgres forbids using a new enum value in the transaction that
+-- adds it.
+ALTER TYPE memory_type ADD VALUE IF NOT EXISTS 'summary';
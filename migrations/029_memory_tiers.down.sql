@@ -0,0 +1,11 @@
+-- 029_memory_tiers.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS memory_tier_history;
+DROP INDEX IF EXISTS idx_memories_agent_tier;
+ALTER TABLE memories
+    DROP COLUMN IF EXISTS tier_changed_at,
+    DROP COLUMN IF EXISTS pinned,
+    DROP COLUMN IF EXISTS tier;
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- 029_memory_tiers.up.sql
+-- Materialized memory tiers. The tier used to be derived from confidence on
+-- every read; it is now stored so the transition worker can also weigh access
+-- frequency, age and pinning, and every change is logged for analytics.
+BEGIN;
+
+ALTER TABLE memories
+    ADD COLUMN IF NOT EXISTS tier TEXT NOT NULL DEFAULT 'warm'
+        CHECK (tier IN ('hot', 'warm', 'cold', 'archive')),
+    ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tier_changed_at TIMESTAMPTZ;
+
+-- Backfill from the confidence bands ComputeTier used.
+UPDATE memories SET
+    tier = CASE
+        WHEN confidence > 0.85 THEN 'hot'
+        WHEN confidence > 0.70 THEN 'warm'
+        WHEN confidence > 0.40 THEN 'cold'
+        ELSE 'archive'
+    END,
+    tier_changed_at = NOW();
+
+CREATE INDEX IF NOT EXISTS idx_memories_agent_tier ON memories(agent_id, tier) WHERE is_archived = FALSE;
+
+CREATE TABLE IF NOT EXISTS memory_tier_history (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    memory_id   UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    agent_id    UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    from_tier   TEXT NOT NULL,
+    to_tier     TEXT NOT NULL,
+    reason      TEXT NOT NULL,
+    confidence  REAL NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_tier_history_memory ON memory_tier_history(memory_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_memory_tier_history_agent ON memory_tier_history(agent_id, occurred_at DESC);
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+DROP TABLE IF EXISTS recall_logs;
+COMMIT;
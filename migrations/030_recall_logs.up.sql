@@ -0,0 +1,27 @@
+-- 030_recall_logs.up.sql
+-- Sampled recall rankings for offline analysis. Each row holds the ranked
+-- results of one recall with their per-factor score breakdowns, so ranking
+-- behavior can be compared across scorer changes. The query text is never
+-- stored, only its SHA-256 hash.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS recall_logs (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id       UUID REFERENCES agents(id) ON DELETE CASCADE,
+    query_hash     TEXT NOT NULL,
+    mode           TEXT NOT NULL,
+    scorer_version TEXT NOT NULL,
+    top_k          INT NOT NULL,
+    vector_weight  REAL NOT NULL,
+    graph_weight   REAL NOT NULL,
+    latency_ms     BIGINT NOT NULL,
+    results        JSONB NOT NULL DEFAULT '[]',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recall_logs_tenant_created ON recall_logs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_recall_logs_agent_created ON recall_logs(agent_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_recall_logs_query_hash ON recall_logs(query_hash);
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- 031_idempotency_keys.up.sql
+-- Idempotency-Key deduplication for create endpoints. A row is reserved when a
+-- keyed request starts and filled with the response once it completes, so a
+-- retry with the same key replays that response instead of writing again.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    tenant_id     UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    key           TEXT NOT NULL,
+    request_hash  TEXT NOT NULL,
+    status_code   INT,
+    content_type  TEXT,
+    response_body BYTEA,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (tenant_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
+
+COMMIT;
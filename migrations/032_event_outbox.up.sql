@@ -0,0 +1,26 @@
+-- 032_event_outbox.up.sql
+-- Transactional outbox for memory change events. Rows are inserted by the same
+-- statement that writes the memory or its mutation_log entry, so an event
+-- exists if and only if the change committed. A publisher worker claims
+-- unpublished rows in id order and delivers them to the configured sink.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    tenant_id       UUID,
+    agent_id        UUID,
+    aggregate_id    UUID,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending
+    ON event_outbox(next_attempt_at, id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_created ON event_outbox(created_at);
+
+COMMIT;
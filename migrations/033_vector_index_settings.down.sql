@@ -0,0 +1,3 @@
+BEGIN;
+DROP TABLE IF EXISTS vector_index_settings;
+COMMIT;
@@ -0,0 +1,30 @@
+-- 033_vector_index_settings.up.sql
+-- Build parameters and rebuild status for each pgvector index, managed through
+-- the admin vector-index API. Seeded with the pgvector HNSW defaults that the
+-- indexes from 008_hnsw_index were built with.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS vector_index_settings (
+    index_name        TEXT PRIMARY KEY,
+    table_name        TEXT NOT NULL,
+    column_name       TEXT NOT NULL,
+    method            TEXT NOT NULL DEFAULT 'hnsw' CHECK (method IN ('hnsw', 'ivfflat')),
+    m                 INT,
+    ef_construction   INT,
+    lists             INT,
+    build_status      TEXT NOT NULL DEFAULT 'ready' CHECK (build_status IN ('ready', 'building', 'failed')),
+    last_error        TEXT,
+    last_built_at     TIMESTAMPTZ,
+    build_duration_ms BIGINT,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO vector_index_settings (index_name, table_name, column_name, method, m, ef_construction) VALUES
+    ('idx_memories_embedding',           'memories',   'embedding',         'hnsw', 16, 64),
+    ('idx_episodes_embedding',           'episodes',   'embedding',         'hnsw', 16, 64),
+    ('idx_procedures_trigger_embedding', 'procedures', 'trigger_embedding', 'hnsw', 16, 64),
+    ('idx_schemas_embedding',            'schemas',    'embedding',         'hnsw', 16, 64),
+    ('idx_entity_embedding',             'entities',   'embedding',         'hnsw', 16, 64)
+ON CONFLICT (index_name) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+DROP INDEX IF EXISTS idx_memories_belief_candidates;
+COMMIT;
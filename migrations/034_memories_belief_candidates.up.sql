@@ -0,0 +1,11 @@
+-- 034_memories_belief_candidates.up.sql
+-- Supports the restricted similarity search run on every memory write, which
+-- only considers an agent's live, embedded memories of comparable types
+-- updated within a recent window.
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_memories_belief_candidates
+    ON memories(agent_id, type, updated_at DESC)
+    WHERE is_archived = FALSE AND embedding IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,49 @@
+-- 035_partition_episodes.down.sql
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_episodes_cascade_delete ON episodes;
+DROP FUNCTION IF EXISTS episodes_cascade_delete();
+
+ALTER TABLE episodes RENAME TO episodes_partitioned;
+ALTER TABLE episodes_partitioned DROP CONSTRAINT episodes_pkey;
+DROP INDEX IF EXISTS idx_episodes_id;
+DROP INDEX IF EXISTS idx_episodes_agent_id;
+DROP INDEX IF EXISTS idx_episodes_tenant_id;
+DROP INDEX IF EXISTS idx_episodes_occurred_at;
+DROP INDEX IF EXISTS idx_episodes_consolidation_status;
+DROP INDEX IF EXISTS idx_episodes_memory_strength;
+DROP INDEX IF EXISTS idx_episodes_conversation_id;
+DROP INDEX IF EXISTS idx_episodes_embedding;
+
+CREATE TABLE episodes (LIKE episodes_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+INSERT INTO episodes SELECT * FROM episodes_partitioned;
+DROP TABLE episodes_partitioned;
+DROP FUNCTION IF EXISTS ensure_episode_partition(DATE);
+
+ALTER TABLE episodes
+    ADD PRIMARY KEY (id),
+    ADD CONSTRAINT episodes_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    ADD CONSTRAINT episodes_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_episodes_agent_id ON episodes(agent_id);
+CREATE INDEX idx_episodes_tenant_id ON episodes(tenant_id);
+CREATE INDEX idx_episodes_occurred_at ON episodes(occurred_at DESC);
+CREATE INDEX idx_episodes_consolidation_status ON episodes(consolidation_status);
+CREATE INDEX idx_episodes_memory_strength ON episodes(memory_strength);
+CREATE INDEX idx_episodes_conversation_id ON episodes(conversation_id);
+CREATE INDEX idx_episodes_embedding ON episodes USING hnsw (embedding vector_cosine_ops)
+    WITH (m = 16, ef_construction = 64);
+
+DELETE FROM episode_associations a
+ WHERE NOT EXISTS (SELECT 1 FROM episodes e WHERE e.id = a.episode_a_id)
+    OR NOT EXISTS (SELECT 1 FROM episodes e WHERE e.id = a.episode_b_id);
+DELETE FROM episode_memory_usage u
+ WHERE NOT EXISTS (SELECT 1 FROM episodes e WHERE e.id = u.episode_id);
+
+ALTER TABLE episode_associations
+    ADD CONSTRAINT episode_associations_episode_a_id_fkey FOREIGN KEY (episode_a_id) REFERENCES episodes(id) ON DELETE CASCADE,
+    ADD CONSTRAINT episode_associations_episode_b_id_fkey FOREIGN KEY (episode_b_id) REFERENCES episodes(id) ON DELETE CASCADE;
+ALTER TABLE episode_memory_usage
+    ADD CONSTRAINT episode_memory_usage_episode_id_fkey FOREIGN KEY (episode_id) REFERENCES episodes(id) ON DELETE CASCADE;
+
+COMMIT;
@@ -0,0 +1,99 @@
+-- 035_partition_episodes.up.sql
+-- Converts episodes into a natively partitioned table: monthly RANGE partitions
+-- on occurred_at (UTC), each sub-partitioned by HASH(tenant_id). Time-range
+-- reads prune to the months they touch, and retention drops a whole month
+-- instead of deleting rows. Rows outside every monthly partition land in
+-- episodes_default until ensure_episode_partition creates their month.
+--
+-- A partitioned table's primary key must include its partition keys, so
+-- episodes(id) alone is no longer unique-constrained and cannot be the target
+-- of a foreign key. The ON DELETE CASCADE edges from episode_associations and
+-- episode_memory_usage are replaced by a delete trigger; dropping a partition
+-- cleans those rows up explicitly (see EpisodeStore.DropPartitionsBefore).
+
+BEGIN;
+
+ALTER TABLE episode_associations
+    DROP CONSTRAINT IF EXISTS episode_associations_episode_a_id_fkey,
+    DROP CONSTRAINT IF EXISTS episode_associations_episode_b_id_fkey;
+ALTER TABLE episode_memory_usage
+    DROP CONSTRAINT IF EXISTS episode_memory_usage_episode_id_fkey;
+
+ALTER TABLE episodes RENAME TO episodes_unpartitioned;
+
+CREATE TABLE episodes (LIKE episodes_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY RANGE (occurred_at);
+
+CREATE TABLE episodes_default PARTITION OF episodes DEFAULT;
+
+-- Creates the monthly partition containing p_month (and its hash
+-- sub-partitions) if it does not exist yet, moving any rows for that month out
+-- of episodes_default first. Safe to call concurrently and repeatedly.
+CREATE OR REPLACE FUNCTION ensure_episode_partition(p_month DATE) RETURNS TEXT LANGUAGE plpgsql AS $$
+DECLARE
+    v_lo   TIMESTAMPTZ := date_trunc('month', p_month)::timestamp AT TIME ZONE 'UTC';
+    v_hi   TIMESTAMPTZ := (date_trunc('month', p_month) + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC';
+    v_part TEXT := 'episodes_' || to_char(date_trunc('month', p_month), '"y"YYYY"m"MM');
+    v_hash INT := 8;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('ensure_episode_partition'));
+    IF to_regclass(v_part) IS NOT NULL THEN
+        RETURN v_part;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE episodes INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY HASH (tenant_id)', v_part);
+    FOR i IN 0..v_hash - 1 LOOP
+        EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES WITH (MODULUS %s, REMAINDER %s)',
+            v_part || '_h' || i, v_part, v_hash, i);
+    END LOOP;
+
+    -- Moving rows is not a deletion: keep the cascade trigger out of it.
+    PERFORM set_config('engram.moving_episodes', 'on', true);
+    EXECUTE format('WITH moved AS (DELETE FROM episodes_default WHERE occurred_at >= %L AND occurred_at < %L RETURNING *)
+                    INSERT INTO %I SELECT * FROM moved', v_lo, v_hi, v_part);
+    PERFORM set_config('engram.moving_episodes', 'off', true);
+
+    EXECUTE format('ALTER TABLE episodes ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', v_part, v_lo, v_hi);
+    RETURN v_part;
+END $$;
+
+-- Partitions for every month that has data, plus the current and next two.
+SELECT ensure_episode_partition(m::date)
+  FROM generate_series(
+        date_trunc('month', COALESCE((SELECT MIN(occurred_at) FROM episodes_unpartitioned), NOW()) AT TIME ZONE 'UTC'),
+        date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '2 months',
+        INTERVAL '1 month') AS m;
+
+INSERT INTO episodes SELECT * FROM episodes_unpartitioned;
+DROP TABLE episodes_unpartitioned;
+
+ALTER TABLE episodes
+    ADD CONSTRAINT episodes_pkey PRIMARY KEY (id, occurred_at, tenant_id),
+    ADD CONSTRAINT episodes_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    ADD CONSTRAINT episodes_tenant_id_fkey FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_episodes_id ON episodes(id);
+CREATE INDEX idx_episodes_agent_id ON episodes(agent_id);
+CREATE INDEX idx_episodes_tenant_id ON episodes(tenant_id);
+CREATE INDEX idx_episodes_occurred_at ON episodes(occurred_at DESC);
+CREATE INDEX idx_episodes_consolidation_status ON episodes(consolidation_status);
+CREATE INDEX idx_episodes_memory_strength ON episodes(memory_strength);
+CREATE INDEX idx_episodes_conversation_id ON episodes(conversation_id);
+CREATE INDEX idx_episodes_embedding ON episodes USING hnsw (embedding vector_cosine_ops)
+    WITH (m = 16, ef_construction = 64);
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+CREATE TRIGGER trg_episodes_cascade_delete
+    AFTER DELETE ON episodes
+    FOR EACH ROW EXECUTE FUNCTION episodes_cascade_delete();
+
+COMMIT;
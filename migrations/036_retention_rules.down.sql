@@ -0,0 +1,6 @@
+-- 036_retention_rules.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS retention_rules;
+
+COMMIT;
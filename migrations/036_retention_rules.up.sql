@@ -0,0 +1,21 @@
+-- 036_retention_rules.up.sql
+-- Per-tenant retention rules evaluated by the expirer: delete live memories,
+-- archived memories or already-abstracted episodes once they pass an age,
+-- optionally narrowed to one memory type.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS retention_rules (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    target      TEXT NOT NULL CHECK (target IN ('memories', 'archived_memories', 'abstracted_episodes')),
+    memory_type TEXT,
+    after_days  INT  NOT NULL CHECK (after_days > 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One rule per (tenant, target, type); a NULL type is its own slot.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_retention_rules_scope
+    ON retention_rules(tenant_id, target, COALESCE(memory_type, ''));
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- 037_working_memory_settings.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS working_memory_settings;
+
+COMMIT;
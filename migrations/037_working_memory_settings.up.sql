@@ -0,0 +1,17 @@
+-- 037_working_memory_settings.up.sql
+-- Per-agent working memory capacity. Agents without a row use the built-in
+-- default (7 slots, expandable to 9 for complex goals).
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS working_memory_settings (
+    agent_id       UUID PRIMARY KEY REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    slots          INT  NOT NULL CHECK (slots BETWEEN 3 AND 20),
+    expanded_slots INT  NOT NULL CHECK (expanded_slots BETWEEN 3 AND 20),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (expanded_slots >= slots)
+);
+
+CREATE INDEX IF NOT EXISTS idx_working_memory_settings_tenant ON working_memory_settings(tenant_id);
+
+COMMIT;
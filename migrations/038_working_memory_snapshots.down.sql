@@ -0,0 +1,6 @@
+-- 038_working_memory_snapshots.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS working_memory_snapshots;
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- 038_working_memory_snapshots.up.sql
+-- Saved working memory sessions. The state (goal, context, reasoning,
+-- activation and schema references) is kept as one JSON document; restoring
+-- it re-validates every reference.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS working_memory_snapshots (
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id  UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id   UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    label      TEXT NOT NULL DEFAULT '',
+    state      JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_wm_snapshots_agent ON working_memory_snapshots(agent_id, created_at DESC);
+
+COMMIT;
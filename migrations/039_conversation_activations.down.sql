@@ -0,0 +1,17 @@
+-- 039_conversation_activations.down.sql
+BEGIN;
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+DROP TABLE IF EXISTS outcome_attributions;
+DROP TABLE IF EXISTS conversation_activations;
+
+COMMIT;
@@ -0,0 +1,41 @@
+-- 039_conversation_activations.up.sql
+-- Memories and procedures that held a working memory slot during a
+-- conversation, and which episode outcomes have already been attributed to
+-- them, so recording an outcome twice does not apply its effect twice.
+-- Like episode_memory_usage, outcome_attributions cannot reference the
+-- partitioned episodes table and is cleaned up by the delete trigger.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS conversation_activations (
+    conversation_id  UUID NOT NULL,
+    memory_type      TEXT NOT NULL CHECK (memory_type IN ('semantic', 'procedural')),
+    memory_id        UUID NOT NULL,
+    tenant_id        UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id         UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    activation_level REAL NOT NULL CHECK (activation_level >= 0 AND activation_level <= 1),
+    activated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (conversation_id, memory_type, memory_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_activations_tenant ON conversation_activations(tenant_id, conversation_id);
+
+CREATE TABLE IF NOT EXISTS outcome_attributions (
+    episode_id    UUID NOT NULL,
+    memory_type   TEXT NOT NULL,
+    memory_id     UUID NOT NULL,
+    attributed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (episode_id, memory_type, memory_id)
+);
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    DELETE FROM outcome_attributions WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+COMMIT;
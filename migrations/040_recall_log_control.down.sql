@@ -0,0 +1,7 @@
+-- 040_recall_log_control.down.sql
+BEGIN;
+
+DROP INDEX IF EXISTS idx_recall_logs_control;
+ALTER TABLE recall_logs DROP COLUMN IF EXISTS control;
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- 040_recall_log_control.up.sql
+-- Flags counterfactual "memory off" requests in recall_logs. A control row
+-- records what recall or activation would have returned while the agent got
+-- nothing, so outcomes with and without memory can be compared.
+BEGIN;
+
+ALTER TABLE recall_logs ADD COLUMN IF NOT EXISTS control BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_recall_logs_control ON recall_logs(tenant_id, created_at DESC) WHERE control;
+
+COMMIT;
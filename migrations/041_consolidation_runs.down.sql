@@ -0,0 +1,6 @@
+-- 041_consolidation_runs.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS consolidation_runs;
+
+COMMIT;
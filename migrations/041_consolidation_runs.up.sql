@@ -0,0 +1,25 @@
+-- 041_consolidation_runs.up.sql
+-- History of consolidation passes with the LLM and embedding usage each one
+-- incurred, so operators can see what an agent's memory maintenance costs.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS consolidation_runs (
+    id                 UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id          UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id           UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    scope              TEXT NOT NULL,
+    result             JSONB NOT NULL DEFAULT '{}',
+    llm_calls          INT NOT NULL DEFAULT 0,
+    input_tokens       BIGINT NOT NULL DEFAULT 0,
+    output_tokens      BIGINT NOT NULL DEFAULT 0,
+    embedding_calls    INT NOT NULL DEFAULT 0,
+    embedding_tokens   BIGINT NOT NULL DEFAULT 0,
+    estimated_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    started_at         TIMESTAMPTZ NOT NULL,
+    finished_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_consolidation_runs_agent ON consolidation_runs(agent_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_consolidation_runs_tenant ON consolidation_runs(tenant_id, started_at DESC);
+
+COMMIT;
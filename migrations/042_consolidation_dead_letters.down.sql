@@ -0,0 +1,23 @@
+-- 042_consolidation_dead_letters.down.sql
+BEGIN;
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    DELETE FROM outcome_attributions WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+UPDATE episodes SET consolidation_status = 'processed' WHERE consolidation_status = 'dead_letter';
+
+ALTER TABLE episodes DROP CONSTRAINT IF EXISTS episodes_consolidation_status_check;
+ALTER TABLE episodes ADD CONSTRAINT episodes_consolidation_status_check
+    CHECK (consolidation_status IN ('raw', 'processed', 'abstracted', 'archived'));
+
+DROP TABLE IF EXISTS consolidation_failures;
+
+COMMIT;
@@ -0,0 +1,56 @@
+-- 042_consolidation_dead_letters.up.sql
+-- Episodes whose consolidation keeps failing. Each failure is counted; after
+-- too many the episode moves to the 'dead_letter' consolidation status so
+-- passes stop retrying it, until an operator requeues it. Like
+-- outcome_attributions, the table cannot reference the partitioned episodes
+-- table and is cleaned up by the delete trigger.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS consolidation_failures (
+    episode_id       UUID PRIMARY KEY,
+    tenant_id        UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id         UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    stage            TEXT NOT NULL,
+    failure_count    INT NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    first_failed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_failed_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dead_lettered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_consolidation_failures_dead
+    ON consolidation_failures(agent_id, dead_lettered_at DESC) WHERE dead_lettered_at IS NOT NULL;
+
+-- Partitions created before this migration carry their own copy of the
+-- status check (ensure_episode_partition copies constraints with LIKE), so
+-- drop every copy the parent does not remove for us before re-adding it.
+ALTER TABLE episodes DROP CONSTRAINT IF EXISTS episodes_consolidation_status_check;
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT conrelid::regclass AS rel FROM pg_constraint
+              WHERE conname = 'episodes_consolidation_status_check' AND conislocal LOOP
+        BEGIN
+            EXECUTE format('ALTER TABLE %s DROP CONSTRAINT IF EXISTS episodes_consolidation_status_check', r.rel);
+        EXCEPTION WHEN others THEN
+            NULL; -- inherited copy, removed with its parent
+        END;
+    END LOOP;
+END $$;
+ALTER TABLE episodes ADD CONSTRAINT episodes_consolidation_status_check
+    CHECK (consolidation_status IN ('raw', 'processed', 'abstracted', 'archived', 'dead_letter'));
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    DELETE FROM outcome_attributions WHERE episode_id = OLD.id;
+    DELETE FROM consolidation_failures WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+COMMIT;
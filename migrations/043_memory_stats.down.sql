@@ -0,0 +1,12 @@
+-- 043_memory_stats.down.sql
+BEGIN;
+
+DROP TRIGGER IF EXISTS consolidation_runs_stats ON consolidation_runs;
+DROP TRIGGER IF EXISTS memories_stats_update ON memories;
+DROP TRIGGER IF EXISTS memories_stats_insert_delete ON memories;
+DROP FUNCTION IF EXISTS consolidation_runs_stats_trigger();
+DROP FUNCTION IF EXISTS memories_stats_trigger();
+DROP FUNCTION IF EXISTS memory_stats_add(memories, INT);
+DROP TABLE IF EXISTS memory_stats;
+
+COMMIT;
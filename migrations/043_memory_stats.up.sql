@@ -0,0 +1,112 @@
+-- 043_memory_stats.up.sql
+-- Per-agent memory statistics kept current by triggers, so dashboards read
+-- one row instead of scanning memories. Counts cover live memories (not
+-- archived, not quarantined). The nightly reconcile recomputes every row from
+-- memories and consolidation_runs to repair any drift.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS memory_stats (
+    agent_id             UUID PRIMARY KEY REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id            UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    memory_count         BIGINT NOT NULL DEFAULT 0,
+    confidence_sum       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    hot_count            BIGINT NOT NULL DEFAULT 0,
+    warm_count           BIGINT NOT NULL DEFAULT 0,
+    cold_count           BIGINT NOT NULL DEFAULT 0,
+    archive_count        BIGINT NOT NULL DEFAULT 0,
+    last_consolidated_at TIMESTAMPTZ,
+    reconciled_at        TIMESTAMPTZ,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_stats_tenant ON memory_stats(tenant_id);
+
+-- memory_stats_add folds one memory into its agent's row with sign +1 or -1.
+-- It reads the agent rather than trusting m.agent_id so that the cascade
+-- from deleting an agent does not try to recreate the agent's row.
+CREATE OR REPLACE FUNCTION memory_stats_add(m memories, sign INT) RETURNS VOID LANGUAGE plpgsql AS $$
+BEGIN
+    IF m.agent_id IS NULL OR m.is_archived OR m.binding = 'quarantine' THEN
+        RETURN;
+    END IF;
+    INSERT INTO memory_stats AS s (agent_id, tenant_id, memory_count, confidence_sum,
+                                   hot_count, warm_count, cold_count, archive_count)
+    SELECT a.id, a.tenant_id, sign, sign * m.confidence,
+           CASE WHEN m.tier = 'hot' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'warm' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'cold' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'archive' THEN sign ELSE 0 END
+    FROM agents a WHERE a.id = m.agent_id
+    ON CONFLICT (agent_id) DO UPDATE SET
+        memory_count   = s.memory_count + EXCLUDED.memory_count,
+        confidence_sum = s.confidence_sum + EXCLUDED.confidence_sum,
+        hot_count      = s.hot_count + EXCLUDED.hot_count,
+        warm_count     = s.warm_count + EXCLUDED.warm_count,
+        cold_count     = s.cold_count + EXCLUDED.cold_count,
+        archive_count  = s.archive_count + EXCLUDED.archive_count,
+        updated_at     = NOW();
+END $$;
+
+CREATE OR REPLACE FUNCTION memories_stats_trigger() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM memory_stats_add(OLD, -1);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM memory_stats_add(NEW, 1);
+    END IF;
+    RETURN NULL;
+END $$;
+
+DROP TRIGGER IF EXISTS memories_stats_insert_delete ON memories;
+CREATE TRIGGER memories_stats_insert_delete
+    AFTER INSERT OR DELETE ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_stats_trigger();
+
+-- Only the columns the statistics depend on fire the update trigger, so
+-- access tracking and content edits do not touch memory_stats.
+DROP TRIGGER IF EXISTS memories_stats_update ON memories;
+CREATE TRIGGER memories_stats_update
+    AFTER UPDATE OF agent_id, confidence, tier, is_archived, binding ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_stats_trigger();
+
+CREATE OR REPLACE FUNCTION consolidation_runs_stats_trigger() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO memory_stats AS s (agent_id, tenant_id, last_consolidated_at)
+    SELECT a.id, a.tenant_id, NEW.finished_at
+    FROM agents a WHERE a.id = NEW.agent_id
+    ON CONFLICT (agent_id) DO UPDATE SET
+        last_consolidated_at = GREATEST(s.last_consolidated_at, EXCLUDED.last_consolidated_at),
+        updated_at = NOW();
+    RETURN NULL;
+END $$;
+
+DROP TRIGGER IF EXISTS consolidation_runs_stats ON consolidation_runs;
+CREATE TRIGGER consolidation_runs_stats
+    AFTER INSERT ON consolidation_runs
+    FOR EACH ROW EXECUTE FUNCTION consolidation_runs_stats_trigger();
+
+-- Backfill: one row per agent, computed the same way the reconcile does.
+INSERT INTO memory_stats (agent_id, tenant_id, memory_count, confidence_sum,
+                          hot_count, warm_count, cold_count, archive_count,
+                          last_consolidated_at, reconciled_at)
+SELECT a.id, a.tenant_id,
+       COALESCE(m.n, 0), COALESCE(m.conf, 0),
+       COALESCE(m.hot, 0), COALESCE(m.warm, 0), COALESCE(m.cold, 0), COALESCE(m.archive, 0),
+       r.last_at, NOW()
+FROM agents a
+LEFT JOIN (
+    SELECT agent_id, count(*) AS n, sum(confidence) AS conf,
+           count(*) FILTER (WHERE tier = 'hot') AS hot,
+           count(*) FILTER (WHERE tier = 'warm') AS warm,
+           count(*) FILTER (WHERE tier = 'cold') AS cold,
+           count(*) FILTER (WHERE tier = 'archive') AS archive
+    FROM memories WHERE is_archived = FALSE AND binding <> 'quarantine'
+    GROUP BY agent_id
+) m ON m.agent_id = a.id
+LEFT JOIN (
+    SELECT agent_id, max(finished_at) AS last_at FROM consolidation_runs GROUP BY agent_id
+) r ON r.agent_id = a.id
+ON CONFLICT (agent_id) DO NOTHING;
+
+COMMIT;
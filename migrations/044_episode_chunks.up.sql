@@ -0,0 +1,48 @@
+-- 044_episode_chunks.up.sql
+-- Passage-level embeddings for long episodes. One embedding per episode
+-- dilutes a long transcript toward its average topic; recall also matches
+-- against these chunks and scores the parent episode by its best passages.
+--
+-- episodes is partitioned, so episode_id cannot reference it: rows are removed
+-- by the episodes delete trigger and when a partition is dropped, like
+-- episode_associations.
+BEGIN;
+
+-- The embedding column takes the dimension episodes.embedding already has,
+-- which may have been resized from the default by EnsureEmbeddingDimension.
+DO $$
+DECLARE
+    v_type TEXT;
+BEGIN
+    SELECT format_type(a.atttypid, a.atttypmod) INTO v_type
+      FROM pg_attribute a
+     WHERE a.attrelid = 'episodes'::regclass AND a.attname = 'embedding';
+
+    EXECUTE format('CREATE TABLE IF NOT EXISTS episode_chunks (
+        episode_id  UUID NOT NULL,
+        chunk_index INT NOT NULL,
+        agent_id    UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+        tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+        content     TEXT NOT NULL,
+        embedding   %s NOT NULL,
+        created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (episode_id, chunk_index)
+    )', v_type);
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_episode_chunks_agent ON episode_chunks(agent_id, tenant_id);
+CREATE INDEX IF NOT EXISTS idx_episode_chunks_embedding ON episode_chunks USING hnsw (embedding vector_cosine_ops)
+    WITH (m = 16, ef_construction = 64);
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    DELETE FROM episode_chunks WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+COMMIT;
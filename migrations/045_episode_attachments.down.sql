@@ -0,0 +1,6 @@
+-- 045_episode_attachments.down.sql
+BEGIN;
+
+ALTER TABLE episodes DROP COLUMN IF EXISTS attachments;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- 045_episode_attachments.up.sql
+-- Image attachments on episodes: object-storage URLs with an optional media
+-- type and a caption, written by the vision model when one is configured.
+BEGIN;
+
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS attachments JSONB NOT NULL DEFAULT '[]';
+
+COMMIT;
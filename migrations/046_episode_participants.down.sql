@@ -0,0 +1,6 @@
+-- 046_episode_participants.down.sql
+BEGIN;
+
+ALTER TABLE episodes DROP COLUMN IF EXISTS participants;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- 046_episode_participants.up.sql
+-- Structured participants on episodes: name, role (user, agent, third_party),
+-- whether they spoke the episode's content, and their person entity node.
+BEGIN;
+
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS participants JSONB NOT NULL DEFAULT '[]';
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- 047_memory_subject.down.sql
+BEGIN;
+
+DROP INDEX IF EXISTS idx_memories_agent_subject;
+ALTER TABLE memories DROP COLUMN IF EXISTS subject_id, DROP COLUMN IF EXISTS subject;
+
+COMMIT;
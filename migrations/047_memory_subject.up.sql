@@ -0,0 +1,15 @@
+-- 047_memory_subject.up.sql
+-- Who or what a belief is about: "user", "assistant" or a name, and the
+-- entity node it resolves to. Recall can filter by subject, and belief
+-- reinforcement/contradiction only compares beliefs about the same subject.
+BEGIN;
+
+ALTER TABLE memories
+    ADD COLUMN IF NOT EXISTS subject TEXT NULL,
+    ADD COLUMN IF NOT EXISTS subject_id UUID NULL REFERENCES entities(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_memories_agent_subject
+    ON memories (agent_id, LOWER(subject))
+    WHERE subject IS NOT NULL;
+
+COMMIT;
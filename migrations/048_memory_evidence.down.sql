@@ -0,0 +1,8 @@
+-- 048_memory_evidence.down.sql
+BEGIN;
+
+ALTER TABLE memories
+    DROP COLUMN IF EXISTS evidence_against,
+    DROP COLUMN IF EXISTS evidence_for;
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- 048_memory_evidence.up.sql
+-- Beta(alpha, beta) evidence counts behind each memory's confidence: the
+-- prior implied by its confidence when written (worth two observations),
+-- plus one per reinforcement or contradiction since. Existing memories are
+-- backfilled from their confidence, reinforcement count and recorded
+-- contradictions.
+BEGIN;
+
+ALTER TABLE memories
+    ADD COLUMN IF NOT EXISTS evidence_for REAL NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS evidence_against REAL NOT NULL DEFAULT 0;
+
+UPDATE memories m
+SET evidence_for = 2 * m.confidence + GREATEST(m.reinforcement_count, 0),
+    evidence_against = 2 * (1 - m.confidence) + COALESCE(
+        (SELECT COUNT(*) FROM belief_contradictions bc WHERE bc.belief_id = m.id), 0)
+WHERE m.evidence_for = 0 AND m.evidence_against = 0;
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- 049_policy_belief_update.down.sql
+BEGIN;
+
+ALTER TABLE memory_policies DROP COLUMN IF EXISTS belief_update;
+
+COMMIT;
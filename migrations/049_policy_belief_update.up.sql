@@ -0,0 +1,11 @@
+-- 049_policy_belief_update.up.sql
+-- How the write path reinforces and contradicts beliefs of a policy's memory
+-- type: 'fixed' confidence steps (the original behavior) or a 'bayesian'
+-- update weighted by source reliability.
+BEGIN;
+
+ALTER TABLE memory_policies
+    ADD COLUMN IF NOT EXISTS belief_update TEXT NOT NULL DEFAULT 'fixed'
+        CHECK (belief_update IN ('fixed', 'bayesian'));
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- 050_source_reliability.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS source_reliability;
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- 050_source_reliability.up.sql
+-- Per-agent track record of each belief source. Feedback and contradiction
+-- outcomes accumulate here, and the learned reliability replaces the fixed
+-- per-source defaults in confidence assessment and extraction discounts.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS source_reliability (
+    agent_id   UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id  UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    source     TEXT NOT NULL,
+    correct    REAL NOT NULL DEFAULT 0,
+    incorrect  REAL NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (agent_id, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_source_reliability_tenant ON source_reliability(tenant_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- 051_memory_topic.down.sql
+BEGIN;
+
+DROP INDEX IF EXISTS idx_memories_agent_topic;
+ALTER TABLE memories DROP COLUMN IF EXISTS topic;
+
+COMMIT;
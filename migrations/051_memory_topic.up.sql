@@ -0,0 +1,14 @@
+-- 051_memory_topic.up.sql
+-- What a belief is about, as a normalized topic ("diet", "work schedule").
+-- Health, uncertainty and failure reports are broken down by it; memories
+-- without one are reported under their type.
+BEGIN;
+
+ALTER TABLE memories
+    ADD COLUMN IF NOT EXISTS topic TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_memories_agent_topic
+    ON memories (agent_id, topic)
+    WHERE topic <> '';
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- 052_procedure_trigger_keywords.down.sql
+BEGIN;
+
+DROP INDEX IF EXISTS idx_procedures_trigger_keywords;
+
+COMMIT;
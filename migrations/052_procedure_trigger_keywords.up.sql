@@ -0,0 +1,23 @@
+-- 052_procedure_trigger_keywords.up.sql
+-- Exact-match fast path for procedure lookup: a cue that contains a
+-- procedure's trigger keywords finds it through this index before the cue is
+-- embedded. Keywords are stored normalized (lower case, punctuation dropped,
+-- single spaces), so existing ones are brought into that form.
+BEGIN;
+
+UPDATE procedures p
+SET trigger_keywords = COALESCE((
+    SELECT jsonb_agg(DISTINCT k)
+    FROM (
+        SELECT btrim(regexp_replace(lower(raw), '[^[:alnum:]]+', ' ', 'g')) AS k
+        FROM jsonb_array_elements_text(p.trigger_keywords) raw
+    ) normalized
+    WHERE k <> ''
+), '[]'::jsonb)
+WHERE jsonb_typeof(trigger_keywords) = 'array'
+  AND jsonb_array_length(trigger_keywords) > 0;
+
+CREATE INDEX IF NOT EXISTS idx_procedures_trigger_keywords
+    ON procedures USING GIN (trigger_keywords);
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- 053_procedure_conditions.down.sql
+BEGIN;
+
+UPDATE working_memory_activations SET activation_source = 'direct' WHERE activation_source = 'condition';
+ALTER TABLE working_memory_activations
+    DROP CONSTRAINT IF EXISTS working_memory_activations_activation_source_check;
+ALTER TABLE working_memory_activations
+    ADD CONSTRAINT working_memory_activations_activation_source_check
+    CHECK (activation_source IN ('direct', 'spread', 'goal', 'temporal', 'recency', 'schema'));
+
+DROP INDEX IF EXISTS idx_procedures_agent_conditional;
+ALTER TABLE procedures DROP COLUMN IF EXISTS conditions;
+
+COMMIT;
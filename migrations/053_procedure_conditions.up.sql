@@ -0,0 +1,21 @@
+-- 053_procedure_conditions.up.sql
+-- Structured procedure conditions (time of day, user attributes, topics,
+-- active schemas), evaluated exactly at activation time. NULL means the
+-- procedure triggers by similarity alone. Procedures their conditions fire
+-- are recorded in working memory with the 'condition' activation source.
+BEGIN;
+
+ALTER TABLE procedures
+    ADD COLUMN IF NOT EXISTS conditions JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_procedures_agent_conditional
+    ON procedures (agent_id)
+    WHERE conditions IS NOT NULL;
+
+ALTER TABLE working_memory_activations
+    DROP CONSTRAINT IF EXISTS working_memory_activations_activation_source_check;
+ALTER TABLE working_memory_activations
+    ADD CONSTRAINT working_memory_activations_activation_source_check
+    CHECK (activation_source IN ('direct', 'spread', 'goal', 'temporal', 'recency', 'schema', 'condition'));
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- 054_procedure_source.down.sql
+BEGIN;
+
+ALTER TABLE procedures
+    DROP COLUMN IF EXISTS source_pack,
+    DROP COLUMN IF EXISTS source;
+
+COMMIT;
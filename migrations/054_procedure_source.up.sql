@@ -0,0 +1,12 @@
+-- 054_procedure_source.up.sql
+-- Procedure provenance: learned from the agent's own episodes, declared in a
+-- seed document, or transferred from another agent's skill pack (named in
+-- source_pack). Existing procedures are taken to be learned.
+BEGIN;
+
+ALTER TABLE procedures
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'learned'
+        CHECK (source IN ('learned', 'seeded', 'transferred')),
+    ADD COLUMN IF NOT EXISTS source_pack TEXT NOT NULL DEFAULT '';
+
+COMMIT;
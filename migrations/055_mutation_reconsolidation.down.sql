@@ -0,0 +1,12 @@
+-- 055_mutation_reconsolidation.down.sql
+-- Fails if any row already records a reconsolidation: mutation_log is
+-- append-only, so those rows cannot be removed to satisfy the old check.
+BEGIN;
+
+ALTER TABLE mutation_log DROP CONSTRAINT IF EXISTS mutation_log_mutation_type_check;
+ALTER TABLE mutation_log ADD CONSTRAINT mutation_log_mutation_type_check
+    CHECK (mutation_type IN ('feedback', 'outcome', 'decay', 'reinforcement', 'contradiction',
+                             'deletion', 'archive', 'admin_override', 'redaction',
+                             'quarantine', 'quarantine_release', 'quarantine_reject'));
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- 055_mutation_reconsolidation.up.sql
+-- Reconsolidation on recall: a memory recalled in a conversation and then
+-- confirmed or contradicted in it is updated at once, and the update is
+-- recorded in the audit chain under its own mutation type.
+BEGIN;
+
+ALTER TABLE mutation_log DROP CONSTRAINT IF EXISTS mutation_log_mutation_type_check;
+ALTER TABLE mutation_log ADD CONSTRAINT mutation_log_mutation_type_check
+    CHECK (mutation_type IN ('feedback', 'outcome', 'decay', 'reinforcement', 'contradiction',
+                             'deletion', 'archive', 'admin_override', 'redaction',
+                             'quarantine', 'quarantine_release', 'quarantine_reject',
+                             'reconsolidation'));
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- 056_episode_position_settings.down.sql
+BEGIN;
+
+DROP TABLE IF EXISTS episode_position_settings;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- 056_episode_position_settings.up.sql
+-- Per-agent primacy and recency weighting of a conversation's first and last
+-- episodes. Agents without a row use the built-in default (0.2 each).
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS episode_position_settings (
+    agent_id       UUID PRIMARY KEY REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    primacy_weight REAL NOT NULL CHECK (primacy_weight BETWEEN 0 AND 1),
+    recency_weight REAL NOT NULL CHECK (recency_weight BETWEEN 0 AND 1),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_episode_position_settings_tenant ON episode_position_settings(tenant_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- 057_environment_context.down.sql
+BEGIN;
+
+ALTER TABLE episodes DROP COLUMN IF EXISTS environment;
+
+COMMIT;
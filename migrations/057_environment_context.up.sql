@@ -0,0 +1,10 @@
+-- 057_environment_context.up.sql
+-- The environment an episode was formed in (channel, device, locale, product
+-- surface); recall leans toward episodes formed in the caller's environment.
+-- Memories keep theirs under metadata->'environment'.
+BEGIN;
+
+ALTER TABLE episodes
+    ADD COLUMN IF NOT EXISTS environment JSONB NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- 058_conversation_working_memory.down.sql
+BEGIN;
+
+DELETE FROM working_memory_sessions WHERE conversation_id IS NOT NULL;
+
+ALTER TABLE working_memory_sessions
+    DROP CONSTRAINT IF EXISTS working_memory_sessions_agent_conversation_key;
+
+ALTER TABLE working_memory_sessions DROP COLUMN IF EXISTS conversation_id;
+
+ALTER TABLE working_memory_sessions
+    ADD CONSTRAINT working_memory_sessions_agent_id_key UNIQUE (agent_id);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- 058_conversation_working_memory.up.sql
+-- Working memory sessions scoped to one conversation. An agent keeps its
+-- agent-wide session (conversation_id NULL) and may hold one isolated session
+-- per conversation besides; all of them recall from the same long-term store.
+BEGIN;
+
+ALTER TABLE working_memory_sessions
+    ADD COLUMN IF NOT EXISTS conversation_id UUID;
+
+ALTER TABLE working_memory_sessions
+    DROP CONSTRAINT IF EXISTS working_memory_sessions_agent_id_key;
+
+ALTER TABLE working_memory_sessions
+    ADD CONSTRAINT working_memory_sessions_agent_conversation_key
+    UNIQUE NULLS NOT DISTINCT (agent_id, conversation_id);
+
+COMMIT;
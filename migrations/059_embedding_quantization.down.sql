@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_memories_embedding_bit;
+DROP INDEX IF EXISTS idx_memories_embedding_half;
+
+ALTER TABLE memories
+    DROP COLUMN IF EXISTS embedding_bit,
+    DROP COLUMN IF EXISTS embedding_half;
+
+COMMIT;
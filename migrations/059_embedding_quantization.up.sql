@@ -0,0 +1,16 @@
+-- 059_embedding_quantization.up.sql
+-- Compact copies of memory embeddings for VECTOR_QUANTIZATION: recall
+-- shortlists on the quantized index, then reranks the shortlist against the
+-- full-precision embedding. Both columns stay NULL until quantization is
+-- enabled and an agent is backfilled. Needs pgvector 0.7 or later.
+--
+-- No index is created here. At startup the server builds the HNSW index for
+-- the configured mode only and drops the others, including the
+-- full-precision one while shortlisting (store.EnsureVectorQuantization).
+BEGIN;
+
+ALTER TABLE memories
+    ADD COLUMN IF NOT EXISTS embedding_half halfvec(1536),
+    ADD COLUMN IF NOT EXISTS embedding_bit  bit(1536);
+
+COMMIT;
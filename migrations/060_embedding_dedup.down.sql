@@ -0,0 +1,30 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_episodes_embeddings_release ON episodes;
+DROP TRIGGER IF EXISTS trg_memories_embeddings_release ON memories;
+DROP FUNCTION IF EXISTS embeddings_release();
+
+-- Rows sharing a vector take their own copy back before the table goes.
+UPDATE memories SET embedding = stored_embedding(embedding, tenant_id, embedding_dedup_model, embedding_hash)
+ WHERE embedding IS NULL AND embedding_hash IS NOT NULL;
+UPDATE episodes SET embedding = stored_embedding(embedding, tenant_id, embedding_dedup_model, embedding_hash)
+ WHERE embedding IS NULL AND embedding_hash IS NOT NULL;
+DROP FUNCTION IF EXISTS stored_embedding(vector, UUID, TEXT, TEXT);
+
+DROP INDEX IF EXISTS idx_episodes_embedding_ref;
+DROP INDEX IF EXISTS idx_memories_embedding_ref;
+
+ALTER TABLE episodes
+    DROP CONSTRAINT IF EXISTS episodes_embedding_ref_fkey,
+    DROP CONSTRAINT IF EXISTS episodes_embedding_ref_check,
+    DROP COLUMN IF EXISTS embedding_dedup_model,
+    DROP COLUMN IF EXISTS embedding_hash;
+ALTER TABLE memories
+    DROP CONSTRAINT IF EXISTS memories_embedding_ref_fkey,
+    DROP CONSTRAINT IF EXISTS memories_embedding_ref_check,
+    DROP COLUMN IF EXISTS embedding_dedup_model,
+    DROP COLUMN IF EXISTS embedding_hash;
+
+DROP TABLE IF EXISTS embeddings;
+
+COMMIT;
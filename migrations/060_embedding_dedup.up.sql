@@ -0,0 +1,104 @@
+-- 060_embedding_dedup.up.sql
+-- Content-addressed embeddings: one vector per tenant, embedding model and
+-- hash of the embedded text. Memories and episodes reference theirs through
+-- (tenant_id, embedding_dedup_model, embedding_hash), a foreign key into
+-- embeddings, so text the tenant has already embedded needs no provider
+-- call, a re-embed computes each distinct text once, and a referencing row
+-- stores no vector of its own.
+--
+-- Episodes always leave their embedding column NULL once the vector is
+-- shared. Memories do so when they also keep a quantized copy (059), which
+-- carries their ANN index; without one, the column keeps the vector the
+-- full-precision index searches. stored_embedding reads the vector of either
+-- kind of row.
+--
+-- A vector is deleted with the last row referencing it: embeddings_release
+-- runs when a memory or episode is deleted or changes its reference, so a
+-- hard delete or redaction leaves no copy behind. Dropping an episode
+-- partition fires no row triggers; the expirer prunes those vectors in the
+-- same sweep.
+BEGIN;
+
+-- The embedding column takes the dimension memories.embedding already has,
+-- which may have been resized from the default by EnsureEmbeddingDimension.
+DO $$
+DECLARE
+    v_type TEXT;
+BEGIN
+    SELECT format_type(a.atttypid, a.atttypmod) INTO v_type
+      FROM pg_attribute a
+     WHERE a.attrelid = 'memories'::regclass AND a.attname = 'embedding';
+
+    EXECUTE format('CREATE TABLE IF NOT EXISTS embeddings (
+        tenant_id    UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+        model        TEXT NOT NULL,
+        content_hash TEXT NOT NULL,
+        embedding    %s NOT NULL,
+        created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (tenant_id, model, content_hash)
+    )', v_type);
+END $$;
+
+ALTER TABLE memories
+    ADD COLUMN IF NOT EXISTS embedding_hash TEXT,
+    ADD COLUMN IF NOT EXISTS embedding_dedup_model TEXT,
+    ADD CONSTRAINT memories_embedding_ref_check
+        CHECK ((embedding_hash IS NULL) = (embedding_dedup_model IS NULL)),
+    ADD CONSTRAINT memories_embedding_ref_fkey
+        FOREIGN KEY (tenant_id, embedding_dedup_model, embedding_hash)
+        REFERENCES embeddings (tenant_id, model, content_hash);
+ALTER TABLE episodes
+    ADD COLUMN IF NOT EXISTS embedding_hash TEXT,
+    ADD COLUMN IF NOT EXISTS embedding_dedup_model TEXT,
+    ADD CONSTRAINT episodes_embedding_ref_check
+        CHECK ((embedding_hash IS NULL) = (embedding_dedup_model IS NULL)),
+    ADD CONSTRAINT episodes_embedding_ref_fkey
+        FOREIGN KEY (tenant_id, embedding_dedup_model, embedding_hash)
+        REFERENCES embeddings (tenant_id, model, content_hash);
+
+-- Serve the foreign key checks of deletes from embeddings and the lookups of
+-- embeddings_release.
+CREATE INDEX IF NOT EXISTS idx_memories_embedding_ref
+    ON memories (tenant_id, embedding_dedup_model, embedding_hash) WHERE embedding_hash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_episodes_embedding_ref
+    ON episodes (tenant_id, embedding_dedup_model, embedding_hash) WHERE embedding_hash IS NOT NULL;
+
+-- The full-precision vector of a memory or episode: its own column, or the
+-- shared row it references.
+CREATE OR REPLACE FUNCTION stored_embedding(p_embedding vector, p_tenant_id UUID, p_model TEXT, p_hash TEXT)
+RETURNS vector LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(p_embedding,
+                    (SELECT embedding FROM embeddings
+                      WHERE tenant_id = p_tenant_id AND model = p_model AND content_hash = p_hash))
+$$;
+
+CREATE OR REPLACE FUNCTION embeddings_release() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    -- A memory moving between partitions, or another row of the tenant with
+    -- the same text, still holds the vector.
+    IF EXISTS (SELECT 1 FROM memories
+                WHERE tenant_id = OLD.tenant_id AND embedding_dedup_model = OLD.embedding_dedup_model
+                  AND embedding_hash = OLD.embedding_hash)
+       OR EXISTS (SELECT 1 FROM episodes
+                   WHERE tenant_id = OLD.tenant_id AND embedding_dedup_model = OLD.embedding_dedup_model
+                     AND embedding_hash = OLD.embedding_hash) THEN
+        RETURN NULL;
+    END IF;
+    DELETE FROM embeddings
+     WHERE tenant_id = OLD.tenant_id AND model = OLD.embedding_dedup_model AND content_hash = OLD.embedding_hash;
+    RETURN NULL;
+END $$;
+
+DROP TRIGGER IF EXISTS trg_memories_embeddings_release ON memories;
+CREATE TRIGGER trg_memories_embeddings_release
+    AFTER DELETE OR UPDATE OF embedding_hash, embedding_dedup_model ON memories
+    FOR EACH ROW WHEN (OLD.embedding_hash IS NOT NULL)
+    EXECUTE FUNCTION embeddings_release();
+
+DROP TRIGGER IF EXISTS trg_episodes_embeddings_release ON episodes;
+CREATE TRIGGER trg_episodes_embeddings_release
+    AFTER DELETE OR UPDATE OF embedding_hash, embedding_dedup_model ON episodes
+    FOR EACH ROW WHEN (OLD.embedding_hash IS NOT NULL)
+    EXECUTE FUNCTION embeddings_release();
+
+COMMIT;
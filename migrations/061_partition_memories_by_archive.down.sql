@@ -0,0 +1,103 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_memories_cascade_delete ON memories;
+DROP FUNCTION IF EXISTS memories_cascade_delete();
+DROP TRIGGER IF EXISTS trg_memory_ids ON memories;
+DROP FUNCTION IF EXISTS memory_ids_sync();
+DROP TABLE IF EXISTS memory_ids;
+
+-- memory_stats_add takes a memories row; recreated after the swap below.
+DROP TRIGGER IF EXISTS memories_stats_insert_delete ON memories;
+DROP TRIGGER IF EXISTS memories_stats_update ON memories;
+DROP FUNCTION IF EXISTS memory_stats_add(memories, INT);
+
+DO $$
+DECLARE
+    r        RECORD;
+    v_cols   TEXT;
+    v_stmts  TEXT[] := '{}';
+    v_stmt   TEXT;
+BEGIN
+    FOR r IN SELECT conname, pg_get_constraintdef(oid) AS def
+               FROM pg_constraint
+              WHERE contype = 'f' AND conrelid = 'memories'::regclass AND conparentid = 0 LOOP
+        v_stmts := v_stmts || format('ALTER TABLE memories ADD CONSTRAINT %I %s', r.conname, r.def);
+    END LOOP;
+    -- Indexes of the parent, then the vector indexes built on memories_active.
+    FOR r IN SELECT i.indexrelid, i.indrelid
+               FROM pg_index i
+              WHERE i.indrelid IN ('memories'::regclass, 'memories_active'::regclass)
+                AND NOT i.indisprimary
+                AND NOT EXISTS (SELECT 1 FROM pg_inherits h WHERE h.inhrelid = i.indexrelid) LOOP
+        v_stmts := v_stmts || regexp_replace(pg_get_indexdef(r.indexrelid), ' ON (ONLY )?(\S+\.)?memories(_active)? ', ' ON \2memories ');
+    END LOOP;
+    FOR r IN SELECT pg_get_triggerdef(oid) AS def
+               FROM pg_trigger
+              WHERE tgrelid = 'memories'::regclass AND NOT tgisinternal AND tgparentid = 0 LOOP
+        v_stmts := v_stmts || r.def;
+    END LOOP;
+
+    SELECT string_agg(quote_ident(attname), ', ' ORDER BY attnum) INTO v_cols
+      FROM pg_attribute
+     WHERE attrelid = 'memories'::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = '';
+
+    ALTER TABLE memories RENAME TO memories_partitioned;
+    CREATE TABLE memories (LIKE memories_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING GENERATED);
+    EXECUTE format('INSERT INTO memories (%s) SELECT %s FROM memories_partitioned', v_cols, v_cols);
+    DROP TABLE memories_partitioned;
+
+    ALTER TABLE memories ADD CONSTRAINT memories_pkey PRIMARY KEY (id);
+    FOREACH v_stmt IN ARRAY v_stmts LOOP
+        EXECUTE v_stmt;
+    END LOOP;
+END $$;
+
+ALTER TABLE belief_contradictions
+    ADD CONSTRAINT belief_contradictions_belief_id_fkey FOREIGN KEY (belief_id) REFERENCES memories(id) ON DELETE CASCADE,
+    ADD CONSTRAINT belief_contradictions_contradicted_by_id_fkey FOREIGN KEY (contradicted_by_id) REFERENCES memories(id) ON DELETE CASCADE;
+ALTER TABLE feedback_signals
+    ADD CONSTRAINT feedback_signals_memory_id_fkey FOREIGN KEY (memory_id) REFERENCES memories(id) ON DELETE CASCADE;
+ALTER TABLE episode_memory_usage
+    ADD CONSTRAINT episode_memory_usage_memory_id_fkey FOREIGN KEY (memory_id) REFERENCES memories(id) ON DELETE CASCADE;
+ALTER TABLE memory_graph
+    ADD CONSTRAINT memory_graph_source_id_fkey FOREIGN KEY (source_id) REFERENCES memories(id) ON DELETE CASCADE,
+    ADD CONSTRAINT memory_graph_target_id_fkey FOREIGN KEY (target_id) REFERENCES memories(id) ON DELETE CASCADE;
+ALTER TABLE entity_mentions
+    ADD CONSTRAINT entity_mentions_memory_id_fkey FOREIGN KEY (memory_id) REFERENCES memories(id) ON DELETE CASCADE;
+ALTER TABLE memory_tier_history
+    ADD CONSTRAINT memory_tier_history_memory_id_fkey FOREIGN KEY (memory_id) REFERENCES memories(id) ON DELETE CASCADE;
+
+CREATE OR REPLACE FUNCTION memory_stats_add(m memories, sign INT) RETURNS VOID LANGUAGE plpgsql AS $$
+BEGIN
+    IF m.agent_id IS NULL OR m.is_archived OR m.binding = 'quarantine' THEN
+        RETURN;
+    END IF;
+    INSERT INTO memory_stats AS s (agent_id, tenant_id, memory_count, confidence_sum,
+                                   hot_count, warm_count, cold_count, archive_count)
+    SELECT a.id, a.tenant_id, sign, sign * m.confidence,
+           CASE WHEN m.tier = 'hot' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'warm' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'cold' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'archive' THEN sign ELSE 0 END
+    FROM agents a WHERE a.id = m.agent_id
+    ON CONFLICT (agent_id) DO UPDATE SET
+        memory_count   = s.memory_count + EXCLUDED.memory_count,
+        confidence_sum = s.confidence_sum + EXCLUDED.confidence_sum,
+        hot_count      = s.hot_count + EXCLUDED.hot_count,
+        warm_count     = s.warm_count + EXCLUDED.warm_count,
+        cold_count     = s.cold_count + EXCLUDED.cold_count,
+        archive_count  = s.archive_count + EXCLUDED.archive_count,
+        updated_at     = NOW();
+END $$;
+
+CREATE TRIGGER memories_stats_insert_delete
+    AFTER INSERT OR DELETE ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_stats_trigger();
+CREATE TRIGGER memories_stats_update
+    AFTER UPDATE OF agent_id, confidence, tier, is_archived, binding ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_stats_trigger();
+
+UPDATE vector_index_settings SET table_name = 'memories', updated_at = NOW()
+ WHERE table_name = 'memories_active';
+
+COMMIT;
@@ -0,0 +1,170 @@
+-- 061_partition_memories_by_archive.up.sql
+-- Splits memories into LIST partitions on is_archived: memories_active and
+-- memories_archived. Archiving or restoring a memory moves its row between
+-- them, so reads filtered on is_archived = FALSE (recall, similarity, decay)
+-- prune to the active partition, and the vector indexes are built on
+-- memories_active only.
+--
+-- Episodes get the same split in 062, on top of their monthly partitions.
+--
+-- As with episodes (035), the primary key must include the partition key, so
+-- memories(id) is no longer unique on its own and can no longer be the
+-- target of a foreign key. memory_ids keeps ids unique across both
+-- partitions, and the ON DELETE CASCADE edges into memories are replaced by
+-- a delete trigger, which ignores the delete half of a row moving between
+-- partitions.
+--
+-- 043's memory_stats_add takes a memories row, which pins the old table: it
+-- and the two triggers calling it are dropped before the swap and recreated
+-- against the partitioned table.
+--
+-- Columns added to memories later reach both partitions; vector indexes on
+-- memories go on memories_active.
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS memories_stats_insert_delete ON memories;
+DROP TRIGGER IF EXISTS memories_stats_update ON memories;
+DROP FUNCTION IF EXISTS memory_stats_add(memories, INT);
+
+DO $$
+DECLARE
+    r        RECORD;
+    v_cols   TEXT;
+    v_fks    TEXT[] := '{}';
+    v_idx    TEXT[] := '{}';
+    v_trg    TEXT[] := '{}';
+    v_def    TEXT;
+BEGIN
+    -- Edges into memories; replaced by memories_cascade_delete below.
+    FOR r IN SELECT conrelid::regclass AS tbl, conname
+               FROM pg_constraint
+              WHERE contype = 'f' AND confrelid = 'memories'::regclass LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', r.tbl, r.conname);
+    END LOOP;
+
+    -- Everything LIKE does not copy: outgoing foreign keys, indexes, triggers.
+    FOR r IN SELECT conname, pg_get_constraintdef(oid) AS def
+               FROM pg_constraint
+              WHERE contype = 'f' AND conrelid = 'memories'::regclass LOOP
+        v_fks := v_fks || format('ALTER TABLE memories ADD CONSTRAINT %I %s', r.conname, r.def);
+    END LOOP;
+    FOR r IN SELECT i.indexrelid, am.amname
+               FROM pg_index i
+               JOIN pg_class c ON c.oid = i.indexrelid
+               JOIN pg_am am ON am.oid = c.relam
+              WHERE i.indrelid = 'memories'::regclass AND NOT i.indisprimary LOOP
+        v_def := pg_get_indexdef(r.indexrelid);
+        IF r.amname IN ('hnsw', 'ivfflat') THEN
+            v_idx := v_idx || regexp_replace(v_def, ' ON (\S+\.)?memories ', ' ON \1memories_active ');
+        ELSE
+            v_idx := v_idx || v_def;
+        END IF;
+    END LOOP;
+    FOR r IN SELECT pg_get_triggerdef(oid) AS def
+               FROM pg_trigger
+              WHERE tgrelid = 'memories'::regclass AND NOT tgisinternal LOOP
+        v_trg := v_trg || r.def;
+    END LOOP;
+
+    SELECT string_agg(quote_ident(attname), ', ' ORDER BY attnum) INTO v_cols
+      FROM pg_attribute
+     WHERE attrelid = 'memories'::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = '';
+
+    ALTER TABLE memories RENAME TO memories_unpartitioned;
+
+    CREATE TABLE memories (LIKE memories_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING GENERATED)
+        PARTITION BY LIST (is_archived);
+    CREATE TABLE memories_active PARTITION OF memories FOR VALUES IN (FALSE);
+    CREATE TABLE memories_archived PARTITION OF memories FOR VALUES IN (TRUE);
+
+    EXECUTE format('INSERT INTO memories (%s) SELECT %s FROM memories_unpartitioned', v_cols, v_cols);
+    DROP TABLE memories_unpartitioned;
+
+    ALTER TABLE memories ADD CONSTRAINT memories_pkey PRIMARY KEY (id, is_archived);
+    FOREACH v_def IN ARRAY v_fks || v_idx || v_trg LOOP
+        EXECUTE v_def;
+    END LOOP;
+END $$;
+
+-- Same as 043, against the partitioned row type.
+CREATE OR REPLACE FUNCTION memory_stats_add(m memories, sign INT) RETURNS VOID LANGUAGE plpgsql AS $$
+BEGIN
+    IF m.agent_id IS NULL OR m.is_archived OR m.binding = 'quarantine' THEN
+        RETURN;
+    END IF;
+    INSERT INTO memory_stats AS s (agent_id, tenant_id, memory_count, confidence_sum,
+                                   hot_count, warm_count, cold_count, archive_count)
+    SELECT a.id, a.tenant_id, sign, sign * m.confidence,
+           CASE WHEN m.tier = 'hot' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'warm' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'cold' THEN sign ELSE 0 END,
+           CASE WHEN m.tier = 'archive' THEN sign ELSE 0 END
+    FROM agents a WHERE a.id = m.agent_id
+    ON CONFLICT (agent_id) DO UPDATE SET
+        memory_count   = s.memory_count + EXCLUDED.memory_count,
+        confidence_sum = s.confidence_sum + EXCLUDED.confidence_sum,
+        hot_count      = s.hot_count + EXCLUDED.hot_count,
+        warm_count     = s.warm_count + EXCLUDED.warm_count,
+        cold_count     = s.cold_count + EXCLUDED.cold_count,
+        archive_count  = s.archive_count + EXCLUDED.archive_count,
+        updated_at     = NOW();
+END $$;
+
+-- A row moving between partitions fires the delete and insert triggers, not
+-- the update ones: the delete takes an active row out of the counts and the
+-- insert of the archived row is ignored, and the reverse on restore.
+CREATE TRIGGER memories_stats_insert_delete
+    AFTER INSERT OR DELETE ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_stats_trigger();
+CREATE TRIGGER memories_stats_update
+    AFTER UPDATE OF agent_id, confidence, tier, is_archived, binding ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_stats_trigger();
+
+-- memory_ids holds every memory id once. A second row with an id already
+-- in either partition fails on its primary key. A row moving between
+-- partitions is a delete then an insert, and AFTER triggers fire in that
+-- order, so its id is released before it is taken again.
+CREATE TABLE memory_ids (id UUID PRIMARY KEY);
+INSERT INTO memory_ids (id) SELECT id FROM memories;
+
+CREATE OR REPLACE FUNCTION memory_ids_sync() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        DELETE FROM memory_ids WHERE id = OLD.id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO memory_ids (id) VALUES (NEW.id);
+    END IF;
+    RETURN NULL;
+END $$;
+
+CREATE TRIGGER trg_memory_ids
+    AFTER INSERT OR DELETE OR UPDATE OF id ON memories
+    FOR EACH ROW EXECUTE FUNCTION memory_ids_sync();
+
+CREATE OR REPLACE FUNCTION memories_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    -- Archiving or restoring moves the row to the other partition as a delete
+    -- and an insert; the row is still there, so keep its edges. memory_ids
+    -- guarantees a row with this id is the moved one.
+    IF EXISTS (SELECT 1 FROM memories WHERE id = OLD.id) THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM belief_contradictions WHERE belief_id = OLD.id OR contradicted_by_id = OLD.id;
+    DELETE FROM feedback_signals WHERE memory_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE memory_id = OLD.id;
+    DELETE FROM memory_graph WHERE source_id = OLD.id OR target_id = OLD.id;
+    DELETE FROM entity_mentions WHERE memory_id = OLD.id;
+    DELETE FROM memory_tier_history WHERE memory_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+CREATE TRIGGER trg_memories_cascade_delete
+    AFTER DELETE ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_cascade_delete();
+
+UPDATE vector_index_settings SET table_name = 'memories_active', updated_at = NOW()
+ WHERE table_name = 'memories';
+
+COMMIT;
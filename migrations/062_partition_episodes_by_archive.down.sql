@@ -0,0 +1,90 @@
+BEGIN;
+
+ALTER TABLE episodes DROP CONSTRAINT IF EXISTS episodes_is_archived_check;
+
+-- Archived episodes go back to the monthly tree; before 062 is_archived was
+-- unused and consolidation_status alone marked them.
+UPDATE episodes SET is_archived = FALSE WHERE is_archived;
+
+DO $$
+DECLARE
+    r       RECORD;
+    v_stmts TEXT[] := '{}';
+    v_stmt  TEXT;
+BEGIN
+    FOR r IN SELECT conname, pg_get_constraintdef(oid) AS def
+               FROM pg_constraint
+              WHERE contype = 'f' AND conrelid = 'episodes'::regclass AND conparentid = 0 LOOP
+        v_stmts := v_stmts || format('ALTER TABLE episodes ADD CONSTRAINT %I %s', r.conname, r.def);
+        EXECUTE format('ALTER TABLE episodes DROP CONSTRAINT %I', r.conname);
+    END LOOP;
+    FOR r IN SELECT tgname, pg_get_triggerdef(oid) AS def
+               FROM pg_trigger
+              WHERE tgrelid = 'episodes'::regclass AND NOT tgisinternal AND tgparentid = 0 LOOP
+        v_stmts := v_stmts || r.def;
+        EXECUTE format('DROP TRIGGER %I ON episodes', r.tgname);
+    END LOOP;
+
+    ALTER TABLE episodes DETACH PARTITION episodes_active;
+    DROP TABLE episodes;
+
+    FOR r IN SELECT conname FROM pg_constraint
+              WHERE contype = 'p' AND conrelid = 'episodes_active'::regclass LOOP
+        EXECUTE format('ALTER TABLE episodes_active DROP CONSTRAINT %I', r.conname);
+    END LOOP;
+    ALTER TABLE episodes_active RENAME TO episodes;
+    FOR r IN SELECT c.relname
+               FROM pg_index i
+               JOIN pg_class c ON c.oid = i.indexrelid
+              WHERE i.indrelid = 'episodes'::regclass AND c.relname LIKE '%\_active' LOOP
+        EXECUTE format('ALTER INDEX %I RENAME TO %I', r.relname, left(r.relname, -length('_active')));
+    END LOOP;
+
+    ALTER TABLE episodes ADD CONSTRAINT episodes_pkey PRIMARY KEY (id, occurred_at, tenant_id);
+    FOREACH v_stmt IN ARRAY v_stmts LOOP
+        EXECUTE v_stmt;
+    END LOOP;
+END $$;
+
+CREATE OR REPLACE FUNCTION ensure_episode_partition(p_month DATE) RETURNS TEXT LANGUAGE plpgsql AS $$
+DECLARE
+    v_lo   TIMESTAMPTZ := date_trunc('month', p_month)::timestamp AT TIME ZONE 'UTC';
+    v_hi   TIMESTAMPTZ := (date_trunc('month', p_month) + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC';
+    v_part TEXT := 'episodes_' || to_char(date_trunc('month', p_month), '"y"YYYY"m"MM');
+    v_hash INT := 8;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('ensure_episode_partition'));
+    IF to_regclass(v_part) IS NOT NULL THEN
+        RETURN v_part;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE episodes INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY HASH (tenant_id)', v_part);
+    FOR i IN 0..v_hash - 1 LOOP
+        EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES WITH (MODULUS %s, REMAINDER %s)',
+            v_part || '_h' || i, v_part, v_hash, i);
+    END LOOP;
+
+    PERFORM set_config('engram.moving_episodes', 'on', true);
+    EXECUTE format('WITH moved AS (DELETE FROM episodes_default WHERE occurred_at >= %L AND occurred_at < %L RETURNING *)
+                    INSERT INTO %I SELECT * FROM moved', v_lo, v_hi, v_part);
+    PERFORM set_config('engram.moving_episodes', 'off', true);
+
+    EXECUTE format('ALTER TABLE episodes ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', v_part, v_lo, v_hi);
+    RETURN v_part;
+END $$;
+
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    DELETE FROM episode_chunks WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+UPDATE vector_index_settings SET table_name = 'episodes', updated_at = NOW()
+ WHERE table_name = 'episodes_active';
+
+COMMIT;
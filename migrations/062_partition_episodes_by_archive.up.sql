@@ -0,0 +1,153 @@
+-- 062_partition_episodes_by_archive.up.sql
+-- Splits episodes on is_archived, as 061 does for memories: the monthly
+-- tree from 035 becomes episodes_active, and archived episodes move to
+-- episodes_archived, partitioned by the same months so retention still
+-- drops whole months. Archiving an episode (consolidation_status
+-- 'archived') sets is_archived and moves the row across; a check keeps the
+-- two in step. Reads of live episodes filter on is_archived = FALSE and
+-- prune to episodes_active, and idx_episodes_embedding stays on it alone.
+--
+-- The existing tree is renamed rather than copied, so active rows stay
+-- where they are. Its indexes are recreated on the new parent, which adopts
+-- them for the active side; its foreign keys and triggers move to the
+-- parent so they cover both sides.
+
+BEGIN;
+
+UPDATE episodes SET is_archived = (consolidation_status = 'archived')
+ WHERE is_archived <> (consolidation_status = 'archived');
+
+ALTER TABLE episodes RENAME TO episodes_active;
+
+CREATE TABLE episodes (LIKE episodes_active INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY LIST (is_archived);
+CREATE TABLE episodes_archived PARTITION OF episodes FOR VALUES IN (TRUE)
+    PARTITION BY RANGE (occurred_at);
+CREATE TABLE episodes_archived_default PARTITION OF episodes_archived DEFAULT;
+
+-- Creates the monthly partitions containing p_month on both sides if they do
+-- not exist yet, moving any rows for that month out of the side's default
+-- partition first: a RANGE(occurred_at) -> HASH(tenant_id) tree under
+-- episodes_active, and a single table under episodes_archived. Safe to call
+-- concurrently and repeatedly.
+CREATE OR REPLACE FUNCTION ensure_episode_partition(p_month DATE) RETURNS TEXT LANGUAGE plpgsql AS $$
+DECLARE
+    v_lo       TIMESTAMPTZ := date_trunc('month', p_month)::timestamp AT TIME ZONE 'UTC';
+    v_hi       TIMESTAMPTZ := (date_trunc('month', p_month) + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC';
+    v_suffix   TEXT := to_char(date_trunc('month', p_month), '"y"YYYY"m"MM');
+    v_part     TEXT := 'episodes_' || v_suffix;
+    v_archived TEXT := 'episodes_archived_' || v_suffix;
+    v_hash     INT := 8;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('ensure_episode_partition'));
+    -- Moving rows is not a deletion: keep the cascade trigger out of it.
+    PERFORM set_config('engram.moving_episodes', 'on', true);
+
+    IF to_regclass(v_part) IS NULL THEN
+        EXECUTE format('CREATE TABLE %I (LIKE episodes INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY HASH (tenant_id)', v_part);
+        FOR i IN 0..v_hash - 1 LOOP
+            EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES WITH (MODULUS %s, REMAINDER %s)',
+                v_part || '_h' || i, v_part, v_hash, i);
+        END LOOP;
+        EXECUTE format('WITH moved AS (DELETE FROM episodes_default WHERE occurred_at >= %L AND occurred_at < %L RETURNING *)
+                        INSERT INTO %I SELECT * FROM moved', v_lo, v_hi, v_part);
+        EXECUTE format('ALTER TABLE episodes_active ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', v_part, v_lo, v_hi);
+    END IF;
+
+    IF to_regclass(v_archived) IS NULL THEN
+        EXECUTE format('CREATE TABLE %I (LIKE episodes INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', v_archived);
+        EXECUTE format('WITH moved AS (DELETE FROM episodes_archived_default WHERE occurred_at >= %L AND occurred_at < %L RETURNING *)
+                        INSERT INTO %I SELECT * FROM moved', v_lo, v_hi, v_archived);
+        EXECUTE format('ALTER TABLE episodes_archived ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)', v_archived, v_lo, v_hi);
+    END IF;
+
+    PERFORM set_config('engram.moving_episodes', 'off', true);
+    RETURN v_part;
+END $$;
+
+-- Archived partitions for every month the active side has.
+SELECT ensure_episode_partition(m::date)
+  FROM generate_series(
+        date_trunc('month', COALESCE((SELECT MIN(occurred_at) FROM episodes_active), NOW()) AT TIME ZONE 'UTC'),
+        date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '2 months',
+        INTERVAL '1 month') AS m;
+
+-- The triggers on the old tree find the moved rows through the new parent,
+-- so neither the cascade nor the shared vector release takes them.
+SELECT set_config('engram.moving_episodes', 'on', true);
+WITH moved AS (DELETE FROM episodes_active WHERE is_archived RETURNING *)
+INSERT INTO episodes_archived SELECT * FROM moved;
+SELECT set_config('engram.moving_episodes', 'off', true);
+
+DO $$
+DECLARE
+    r      RECORD;
+    v_fks  TEXT[] := '{}';
+    v_idx  TEXT[] := '{}';
+    v_trg  TEXT[] := '{}';
+    v_def  TEXT;
+BEGIN
+    -- Outgoing foreign keys and row triggers move to the parent.
+    FOR r IN SELECT conname, pg_get_constraintdef(oid) AS def
+               FROM pg_constraint
+              WHERE contype = 'f' AND conrelid = 'episodes_active'::regclass LOOP
+        v_fks := v_fks || format('ALTER TABLE episodes ADD CONSTRAINT %I %s', r.conname, r.def);
+        EXECUTE format('ALTER TABLE episodes_active DROP CONSTRAINT %I', r.conname);
+    END LOOP;
+    FOR r IN SELECT tgname, pg_get_triggerdef(oid) AS def
+               FROM pg_trigger
+              WHERE tgrelid = 'episodes_active'::regclass AND NOT tgisinternal AND tgparentid = 0 LOOP
+        v_trg := v_trg || regexp_replace(r.def, ' ON (\S+\.)?episodes_active ', ' ON \1episodes ');
+        EXECUTE format('DROP TRIGGER %I ON episodes_active', r.tgname);
+    END LOOP;
+
+    -- Indexes other than the vector ones go on the parent, which adopts the
+    -- active side's copy once it is renamed out of the way.
+    FOR r IN SELECT c.relname, i.indexrelid
+               FROM pg_index i
+               JOIN pg_class c ON c.oid = i.indexrelid
+               JOIN pg_am am ON am.oid = c.relam
+              WHERE i.indrelid = 'episodes_active'::regclass AND NOT i.indisprimary
+                AND am.amname NOT IN ('hnsw', 'ivfflat') LOOP
+        v_idx := v_idx || pg_get_indexdef(r.indexrelid);
+        EXECUTE format('ALTER INDEX %I RENAME TO %I', r.relname, r.relname || '_active');
+    END LOOP;
+
+    -- The primary key must include the new partition key.
+    ALTER TABLE episodes_active DROP CONSTRAINT episodes_pkey;
+
+    ALTER TABLE episodes ATTACH PARTITION episodes_active FOR VALUES IN (FALSE);
+    ALTER TABLE episodes ADD CONSTRAINT episodes_pkey PRIMARY KEY (id, occurred_at, tenant_id, is_archived);
+
+    FOREACH v_def IN ARRAY v_idx LOOP
+        EXECUTE regexp_replace(v_def, ' ON (\S+\.)?episodes_active ', ' ON \1episodes ');
+    END LOOP;
+    FOREACH v_def IN ARRAY v_fks || v_trg LOOP
+        EXECUTE v_def;
+    END LOOP;
+END $$;
+
+ALTER TABLE episodes ADD CONSTRAINT episodes_is_archived_check
+    CHECK (is_archived = (consolidation_status = 'archived'));
+
+-- Archiving moves the row to the other side as a delete and an insert; the
+-- episode is still there, so keep its edges. This also restores the
+-- outcome_attributions and consolidation_failures cleanup that 044 dropped.
+CREATE OR REPLACE FUNCTION episodes_cascade_delete() RETURNS TRIGGER LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_setting('engram.moving_episodes', true) = 'on'
+       OR EXISTS (SELECT 1 FROM episodes WHERE id = OLD.id) THEN
+        RETURN OLD;
+    END IF;
+    DELETE FROM episode_associations WHERE episode_a_id = OLD.id OR episode_b_id = OLD.id;
+    DELETE FROM episode_memory_usage WHERE episode_id = OLD.id;
+    DELETE FROM episode_chunks WHERE episode_id = OLD.id;
+    DELETE FROM outcome_attributions WHERE episode_id = OLD.id;
+    DELETE FROM consolidation_failures WHERE episode_id = OLD.id;
+    RETURN OLD;
+END $$;
+
+UPDATE vector_index_settings SET table_name = 'episodes_active', updated_at = NOW()
+ WHERE table_name = 'episodes';
+
+COMMIT;
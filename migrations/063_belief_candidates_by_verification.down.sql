@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_memories_belief_candidates;
+
+CREATE INDEX idx_memories_belief_candidates
+    ON memories(agent_id, type, updated_at DESC)
+    WHERE is_archived = FALSE AND embedding IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- 063_belief_candidates_by_verification.up.sql
+-- The write-path similarity search now bounds its window on when a memory
+-- was created or last verified rather than on updated_at, which decay and
+-- access bookkeeping bump on memories nobody has written about in months.
+-- The index from 034 follows. It no longer requires a stored embedding:
+-- since 060 a memory can carry its vector through the embeddings table with
+-- the column left NULL.
+BEGIN;
+
+DROP INDEX IF EXISTS idx_memories_belief_candidates;
+
+CREATE INDEX idx_memories_belief_candidates
+    ON memories(agent_id, type, (COALESCE(last_verified_at, created_at)) DESC)
+    WHERE is_archived = FALSE;
+
+COMMIT;
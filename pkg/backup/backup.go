@@ -0,0 +1,260 @@
+// Package backup reads and writes Engram tenant backups.
+//
+// A backup is NDJSON: a header line, one line per table row, and a trailer
+// carrying per-table row counts and a SHA-256 over every line before it. A
+// backup whose trailer is missing, or whose counts or checksum don't match, is
+// rejected as truncated or altered. When the server has a signing key the
+// trailer also carries an HMAC of the checksum, proving where it came from.
+//
+//	{"_header":true,"format":"engram-backup/1","tenant_id":"...","snapshot_at":"..."}
+//	{"table":"agents","row":{...}}
+//	{"_trailer":true,"records":1,"counts":{"agents":1},"sha256":"..."}
+package backup
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// Format identifies the backup layout; readers reject any other.
+const Format = "engram-backup/1"
+
+// maxLineBytes bounds a single row, which may carry a long episode and
+// several embeddings.
+const maxLineBytes = 64 << 20
+
+var (
+	ErrBadFormat        = errors.New("backup: not an engram backup")
+	ErrTruncated        = errors.New("backup: missing trailer, backup is truncated")
+	ErrChecksumMismatch = errors.New("backup: checksum mismatch, backup is corrupt or altered")
+	ErrCountMismatch    = errors.New("backup: row counts do not match the trailer")
+)
+
+// Header opens a backup.
+type Header struct {
+	Marker     bool      `json:"_header"`
+	Format     string    `json:"format"`
+	TenantID   string    `json:"tenant_id"`
+	SnapshotAt time.Time `json:"snapshot_at"`
+}
+
+// Record is one table row, as the JSON object Postgres produced for it.
+type Record struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// Trailer closes a backup.
+type Trailer struct {
+	Marker       bool           `json:"_trailer"`
+	Records      int            `json:"records"`
+	Counts       map[string]int `json:"counts"`
+	SHA256       string         `json:"sha256"`
+	Signature    string         `json:"signature,omitempty"`
+	SignatureAlg string         `json:"signature_alg,omitempty"`
+}
+
+const signatureAlg = "HMAC-SHA256(sha256)"
+
+// VerifySignature reports whether the trailer was signed with key.
+func (t *Trailer) VerifySignature(key string) bool {
+	if t.Signature == "" || key == "" {
+		return false
+	}
+	return hmac.Equal([]byte(t.Signature), []byte(sign(key, t.SHA256)))
+}
+
+func sign(key, sum string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(sum))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Writer writes a backup.
+type Writer struct {
+	w      io.Writer
+	sum    hash.Hash
+	counts map[string]int
+	n      int
+}
+
+// NewWriter writes h, filling in its marker and format, and returns a Writer
+// for the rows.
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	bw := &Writer{w: w, sum: sha256.New(), counts: map[string]int{}}
+	h.Marker, h.Format = true, Format
+	if err := bw.line(h); err != nil {
+		return nil, err
+	}
+	return bw, nil
+}
+
+// Write adds one row of table.
+func (w *Writer) Write(table string, row json.RawMessage) error {
+	if err := w.line(Record{Table: table, Row: row}); err != nil {
+		return err
+	}
+	w.counts[table]++
+	w.n++
+	return nil
+}
+
+// Close writes the trailer, signed when signingKey is set, and returns it.
+func (w *Writer) Close(signingKey string) (*Trailer, error) {
+	t := &Trailer{Marker: true, Records: w.n, Counts: w.counts, SHA256: hex.EncodeToString(w.sum.Sum(nil))}
+	if signingKey != "" {
+		t.Signature, t.SignatureAlg = sign(signingKey, t.SHA256), signatureAlg
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.w.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (w *Writer) line(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	w.sum.Write(b)
+	_, err = w.w.Write(b)
+	return err
+}
+
+// Reader reads a backup, checking it against its trailer as it goes.
+type Reader struct {
+	sc      *bufio.Scanner
+	sum     hash.Hash
+	header  Header
+	trailer *Trailer
+	counts  map[string]int
+	n       int
+}
+
+// NewReader reads and checks the header.
+func NewReader(r io.Reader) (*Reader, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	br := &Reader{sc: sc, sum: sha256.New(), counts: map[string]int{}}
+	line, err := br.next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrBadFormat
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(line, &br.header); err != nil || !br.header.Marker {
+		return nil, ErrBadFormat
+	}
+	if br.header.Format != Format {
+		return nil, fmt.Errorf("backup: unsupported format %q", br.header.Format)
+	}
+	br.sum.Write(line)
+	br.sum.Write([]byte{'\n'})
+	return br, nil
+}
+
+// Header returns the backup's header.
+func (r *Reader) Header() Header { return r.header }
+
+// Trailer returns the trailer once Next has returned io.EOF.
+func (r *Reader) Trailer() *Trailer { return r.trailer }
+
+// Next returns the next row. After the last one it checks the trailer and
+// returns io.EOF if the backup is whole, or the reason it isn't.
+func (r *Reader) Next() (*Record, error) {
+	if r.trailer != nil {
+		return nil, io.EOF
+	}
+	line, err := r.next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrTruncated
+		}
+		return nil, err
+	}
+
+	var probe struct {
+		Trailer bool   `json:"_trailer"`
+		Table   string `json:"table"`
+	}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return nil, fmt.Errorf("backup: record %d: %w", r.n+1, err)
+	}
+	if probe.Trailer {
+		return nil, r.finish(line)
+	}
+
+	rec := &Record{}
+	if err := json.Unmarshal(line, rec); err != nil || rec.Table == "" || len(rec.Row) == 0 {
+		return nil, fmt.Errorf("backup: record %d is malformed", r.n+1)
+	}
+	r.sum.Write(line)
+	r.sum.Write([]byte{'\n'})
+	r.counts[rec.Table]++
+	r.n++
+	return rec, nil
+}
+
+func (r *Reader) finish(line []byte) error {
+	t := &Trailer{}
+	if err := json.Unmarshal(line, t); err != nil {
+		return fmt.Errorf("backup: trailer: %w", err)
+	}
+	if hex.EncodeToString(r.sum.Sum(nil)) != t.SHA256 {
+		return ErrChecksumMismatch
+	}
+	if t.Records != r.n || len(t.Counts) != len(r.counts) {
+		return ErrCountMismatch
+	}
+	for table, n := range r.counts {
+		if t.Counts[table] != n {
+			return ErrCountMismatch
+		}
+	}
+	r.trailer = t
+	return io.EOF
+}
+
+// next returns the next non-empty line.
+func (r *Reader) next() ([]byte, error) {
+	for r.sc.Scan() {
+		if line := r.sc.Bytes(); len(line) > 0 {
+			return line, nil
+		}
+	}
+	if err := r.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Verify reads a whole backup and returns its trailer, or why it is not
+// intact. Rows are discarded.
+func Verify(r io.Reader) (Header, *Trailer, error) {
+	br, err := NewReader(r)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	for {
+		if _, err := br.Next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return br.Header(), br.Trailer(), nil
+			}
+			return br.Header(), nil, err
+		}
+	}
+}
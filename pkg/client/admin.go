@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Consolidation scopes.
+const (
+	ConsolidationRecent = "recent"
+	ConsolidationFull   = "full"
+)
+
+// CreateTenant provisions a tenant and its master key. It authenticates with
+// the server's setup token rather than the client's API key.
+func (c *Client) CreateTenant(ctx context.Context, setupToken, orgName string) (*Tenant, error) {
+	body := map[string]string{"org_name": orgName}
+	header := http.Header{"X-Setup-Token": []string{setupToken}}
+	var t Tenant
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/setup", body: body, header: header}, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateAPIKey creates an API key for the client's tenant. Needs admin scope.
+func (c *Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*APIKey, error) {
+	var k APIKey
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/keys", body: req}, &k); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// ListMemories returns a page of an agent's memories, highest confidence
+// first. A non-positive limit uses the server default.
+func (c *Client) ListMemories(ctx context.Context, agentID string, limit, offset int) (*MemoryPage, error) {
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+	var page MemoryPage
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/agents/" + url.PathEscape(agentID) + "/memories", query: q}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Consolidate runs a consolidation pass for the agent and waits for it.
+// Scope is ConsolidationRecent (the default when empty) or ConsolidationFull.
+func (c *Client) Consolidate(ctx context.Context, agentID, scope string) (*ConsolidationResult, error) {
+	body := map[string]string{"agent_id": agentID, "scope": scope}
+	var res ConsolidationResult
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/cognitive/consolidate", body: body}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListConsolidationRuns returns the agent's most recent consolidation runs,
+// newest first.
+func (c *Client) ListConsolidationRuns(ctx context.Context, agentID string, limit int) ([]ConsolidationRun, error) {
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var res struct {
+		Runs []ConsolidationRun `json:"runs"`
+	}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/agents/" + url.PathEscape(agentID) + "/consolidation-runs", query: q}, &res); err != nil {
+		return nil, err
+	}
+	return res.Runs, nil
+}
+
+// Reembed recomputes the agent's vectors with the server's current embedding
+// model and returns how many were rewritten. Needs admin scope.
+func (c *Client) Reembed(ctx context.Context, agentID string) (int, error) {
+	var res struct {
+		Reembedded int `json:"reembedded"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/admin/agents/" + url.PathEscape(agentID) + "/reembed"}, &res); err != nil {
+		return 0, err
+	}
+	return res.Reembedded, nil
+}
+
+// SyncVectors copies the agent's stored embeddings to the server's external
+// vector store and returns how many were sent. Needs admin scope.
+func (c *Client) SyncVectors(ctx context.Context, agentID string) (int, error) {
+	var res struct {
+		Synced int `json:"synced"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/admin/agents/" + url.PathEscape(agentID) + "/vectors/sync"}, &res); err != nil {
+		return 0, err
+	}
+	return res.Synced, nil
+}
+
+// QuantizeVectors fills the quantized copy of the agent's stored embeddings
+// after VECTOR_QUANTIZATION is enabled on the server and returns how many
+// were written. Needs admin scope.
+func (c *Client) QuantizeVectors(ctx context.Context, agentID string) (int, error) {
+	var res struct {
+		Quantized int `json:"quantized"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/admin/agents/" + url.PathEscape(agentID) + "/vectors/quantize"}, &res); err != nil {
+		return 0, err
+	}
+	return res.Quantized, nil
+}
+
+// Backup streams a point-in-time backup of the tenant to w, in the
+// pkg/backup format. Needs admin scope. Check the result with backup.Verify;
+// a backup cut short by a server error has no trailer.
+func (c *Client) Backup(ctx context.Context, w io.Writer) error {
+	resp, err := c.send(ctx, http.MethodGet, c.baseURL+"/v1/backup", nil, "", nil)
+	if err != nil {
+		return fmt.Errorf("engram API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp, body)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("engram API: read backup: %w", err)
+	}
+	return nil
+}
+
+// Restore loads a backup into the client's tenant. The server applies it in
+// one transaction and rejects it whole if it is truncated or altered. Needs
+// admin scope.
+func (c *Client) Restore(ctx context.Context, r io.Reader) (*RestoreResult, error) {
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("engram API: read backup: %w", err)
+	}
+	resp, err := c.send(ctx, http.MethodPost, c.baseURL+"/v1/backup/restore", payload, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("engram API: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("engram API: read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, newAPIError(resp, body)
+	}
+	var res RestoreResult
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("engram API: decode response: %w", err)
+	}
+	return &res, nil
+}
@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CreateAgent creates an agent.
+func (c *Client) CreateAgent(ctx context.Context, req CreateAgentRequest) (*Agent, error) {
+	var agent Agent
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/agents", body: req, create: true}, &agent); err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// ListAgents returns a page of the tenant's agents. A non-positive limit uses
+// the server default.
+func (c *Client) ListAgents(ctx context.Context, limit, offset int) (*AgentList, error) {
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+	var list AgentList
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/agents", query: q}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetAgent returns an agent by ID.
+func (c *Client) GetAgent(ctx context.Context, id string) (*Agent, error) {
+	var agent Agent
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/agents/" + url.PathEscape(id)}, &agent); err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// DeleteAgent deletes an agent and everything it owns.
+func (c *Client) DeleteAgent(ctx context.Context, id string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/v1/agents/" + url.PathEscape(id)}, nil)
+}
+
+// CreateMemory stores a memory.
+func (c *Client) CreateMemory(ctx context.Context, req CreateMemoryRequest) (*CreateMemoryResult, error) {
+	var res CreateMemoryResult
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/memories", body: req, create: true}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetMemory returns a memory by ID.
+func (c *Client) GetMemory(ctx context.Context, id string) (*Memory, error) {
+	var m Memory
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/memories/" + url.PathEscape(id)}, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// DeleteMemory deletes a memory.
+func (c *Client) DeleteMemory(ctx context.Context, id string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/v1/memories/" + url.PathEscape(id)}, nil)
+}
+
+// Recall returns the agent's memories ranked against the query.
+func (c *Client) Recall(ctx context.Context, req RecallRequest) (*RecallResult, error) {
+	q := url.Values{}
+	q.Set("query", req.Query)
+	setNonEmpty(q, "agent_id", req.AgentID)
+	setNonEmpty(q, "anchor_id", req.AnchorID)
+	setNonEmpty(q, "anchor_external_id", req.AnchorExternalID)
+	setNonEmpty(q, "session_id", req.SessionID)
+	setNonEmpty(q, "type", req.Type)
+	setNonEmpty(q, "subject", req.Subject)
+	if req.TopK > 0 {
+		q.Set("top_k", strconv.Itoa(req.TopK))
+	}
+	if req.MinConfidence > 0 {
+		q.Set("min_confidence", strconv.FormatFloat(req.MinConfidence, 'f', -1, 64))
+	}
+	if req.GraphWeight > 0 {
+		q.Set("graph_weight", strconv.FormatFloat(req.GraphWeight, 'f', -1, 64))
+	}
+	if len(req.IncludeTiers) > 0 {
+		q.Set("include_tiers", strings.Join(req.IncludeTiers, ","))
+	}
+	if req.Explain {
+		q.Set("explain", "true")
+	}
+	if req.NoInterference {
+		q.Set("no_interference", "true")
+	}
+	if req.Accuracy > 0 {
+		q.Set("accuracy", strconv.FormatFloat(req.Accuracy, 'f', -1, 64))
+	}
+	req.Environment.setQuery(q)
+
+	var res RecallResult
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/memories/recall", query: q}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RecallBatch recalls for several queries with shared options, returning a
+// group per query in order. A memory matched by several queries is only in
+// the group of the one that ranked it highest.
+func (c *Client) RecallBatch(ctx context.Context, req RecallBatchRequest) ([]RecallGroup, error) {
+	var res struct {
+		Results []RecallGroup `json:"results"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/memories/recall/batch", body: req, readOnly: true}, &res); err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
+
+// Extract extracts memories from a conversation and waits for the result.
+// Extraction is not retried, since with AutoStore a repeat could store twice.
+func (c *Client) Extract(ctx context.Context, req ExtractRequest) ([]ExtractedMemory, error) {
+	var res struct {
+		Extracted []ExtractedMemory `json:"extracted"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/memories/extract", body: req}, &res); err != nil {
+		return nil, err
+	}
+	return res.Extracted, nil
+}
+
+// ExtractAsync queues an extraction and returns its job; see WaitJob and
+// ExtractedMemories.
+func (c *Client) ExtractAsync(ctx context.Context, req ExtractRequest) (*Job, error) {
+	body := struct {
+		ExtractRequest
+		Async bool `json:"async"`
+	}{req, true}
+	var job Job
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/memories/extract", body: body}, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ExtractedMemories decodes a succeeded extraction job's result.
+func (j *Job) ExtractedMemories() ([]ExtractedMemory, error) {
+	if j.Status != JobSucceeded {
+		return nil, fmt.Errorf("engram API: job %s is %s", j.ID, j.Status)
+	}
+	var out []ExtractedMemory
+	if err := json.Unmarshal(j.Result, &out); err != nil {
+		return nil, fmt.Errorf("engram API: decode job result: %w", err)
+	}
+	return out, nil
+}
+
+// GetJob returns a background job's current state.
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/jobs/" + url.PathEscape(id)}, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitJob polls a job every interval (1s if non-positive) until it finishes
+// or ctx is done. A failed job is returned with an error carrying its message.
+func (c *Client) WaitJob(ctx context.Context, id string, interval time.Duration) (*Job, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status == JobFailed {
+			return job, errors.New("engram API: job failed: " + job.Error)
+		}
+		if job.Done() {
+			return job, nil
+		}
+		if err := c.sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// CreateEpisode records an episode.
+func (c *Client) CreateEpisode(ctx context.Context, req CreateEpisodeRequest) (*Episode, error) {
+	var ep Episode
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/episodes", body: req, create: true}, &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// IngestTranscript stores a timestamped transcript as speaker-attributed
+// episodes.
+func (c *Client) IngestTranscript(ctx context.Context, req IngestTranscriptRequest) (*IngestTranscriptResponse, error) {
+	var resp IngestTranscriptResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/episodes/transcript", body: req}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetEpisode returns an episode by ID.
+func (c *Client) GetEpisode(ctx context.Context, id string) (*Episode, error) {
+	var ep Episode
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/episodes/" + url.PathEscape(id)}, &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// RecallEpisodes returns the agent's episodes matching the request.
+func (c *Client) RecallEpisodes(ctx context.Context, req RecallEpisodesRequest) ([]RecalledEpisode, error) {
+	q := url.Values{}
+	q.Set("agent_id", req.AgentID)
+	setNonEmpty(q, "query", req.Query)
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.MinImportance > 0 {
+		q.Set("min_importance", strconv.FormatFloat(req.MinImportance, 'f', -1, 64))
+	}
+	if !req.Start.IsZero() {
+		q.Set("start_time", req.Start.Format(time.RFC3339))
+	}
+	if !req.End.IsZero() {
+		q.Set("end_time", req.End.Format(time.RFC3339))
+	}
+	req.Environment.setQuery(q)
+	var res struct {
+		Episodes []RecalledEpisode `json:"episodes"`
+	}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/episodes/recall", query: q}, &res); err != nil {
+		return nil, err
+	}
+	return res.Episodes, nil
+}
+
+func setNonEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
+func (e Environment) setQuery(q url.Values) {
+	setNonEmpty(q, "channel", e.Channel)
+	setNonEmpty(q, "device", e.Device)
+	setNonEmpty(q, "locale", e.Locale)
+	setNonEmpty(q, "surface", e.Surface)
+}
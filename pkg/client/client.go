@@ -0,0 +1,292 @@
+// Package client is a Go client for the Engram HTTP API.
+//
+//	c := client.New("https://engram.example.com", apiKey)
+//	agent, err := c.CreateAgent(ctx, client.CreateAgentRequest{Name: "support-bot"})
+//	...
+//	_, err = c.CreateMemory(ctx, client.CreateMemoryRequest{AgentID: agent.ID, Content: "Prefers email"})
+//	res, err := c.Recall(ctx, client.RecallRequest{AgentID: agent.ID, Query: "how to contact"})
+//
+// Requests that fail with a network error, 429 or a 502/503/504 are retried
+// with exponential backoff, honouring Retry-After. Reads and deletes are
+// always retried; creates are retried because each one carries an
+// Idempotency-Key (generated per call unless set with WithIdempotencyKey), so
+// a retry never stores twice. Other writes are not retried.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxRetries  = 3
+	defaultBaseBackoff = 200 * time.Millisecond
+	defaultMaxBackoff  = 5 * time.Second
+)
+
+// Client calls the Engram API. It is safe for concurrent use.
+type Client struct {
+	baseURL     string
+	apiKey      string
+	http        *http.Client
+	maxRetries  int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client (default: 30s timeout).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http = hc
+		}
+	}
+}
+
+// WithMaxRetries sets how many times a retryable request is retried
+// (default 3). 0 disables retries.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithBackoff sets the first retry delay and the cap on later ones; each
+// retry doubles the delay, with jitter.
+func WithBackoff(base, max time.Duration) Option {
+	return func(c *Client) {
+		if base > 0 {
+			c.baseBackoff = base
+		}
+		if max >= base {
+			c.maxBackoff = max
+		}
+	}
+}
+
+// New returns a client for the server at baseURL authenticating with apiKey.
+func New(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		apiKey:      apiKey,
+		http:        &http.Client{Timeout: 30 * time.Second},
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBaseBackoff,
+		maxBackoff:  defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is a non-2xx response from the server.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RequestID is the server's X-Request-ID for the failed call; quote it
+	// when reporting problems.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("engram API: status %d: %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("engram API: status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the server.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+type idempotencyKeyCtx struct{}
+
+type correlationIDCtx struct{}
+
+// WithIdempotencyKey returns a context whose create call uses key as its
+// Idempotency-Key, so retrying the call later (even from another process)
+// returns the original result instead of creating a duplicate. The server
+// remembers keys for 24 hours.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// WithCorrelationID returns a context whose calls send id as
+// X-Correlation-ID, tying them together in the server's logs.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDCtx{}, id)
+}
+
+// call describes one API request.
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	// create marks endpoints that accept an Idempotency-Key.
+	create bool
+	// readOnly marks a POST that only reads, so is retried like a GET.
+	readOnly bool
+	// header carries extra request headers, such as X-Setup-Token.
+	header http.Header
+}
+
+// do sends req, retrying where safe, and decodes a successful response into
+// out (if non-nil).
+func (c *Client) do(ctx context.Context, req call, out any) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("engram API: encode request: %w", err)
+		}
+	}
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var idemKey string
+	if req.create {
+		idemKey, _ = ctx.Value(idempotencyKeyCtx{}).(string)
+		if idemKey == "" {
+			idemKey = uuid.NewString()
+		}
+	}
+	retryable := req.method == http.MethodGet || req.method == http.MethodPut || req.method == http.MethodDelete || req.readOnly || idemKey != ""
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req.method, target, payload, idemKey, req.header)
+		if err != nil {
+			if ctx.Err() != nil || !retryable || attempt >= c.maxRetries {
+				return fmt.Errorf("engram API: %w", err)
+			}
+			if err := c.sleep(ctx, c.backoff(attempt)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("engram API: read response: %w", err)
+		}
+		if resp.StatusCode < 300 {
+			if out == nil || len(bytes.TrimSpace(respBody)) == 0 {
+				return nil
+			}
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("engram API: decode response: %w", err)
+			}
+			return nil
+		}
+
+		apiErr := newAPIError(resp, respBody)
+		if !retryable || attempt >= c.maxRetries || !shouldRetry(apiErr, idemKey != "") {
+			return apiErr
+		}
+		wait := c.backoff(attempt)
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+			wait = time.Duration(s) * time.Second
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// newAPIError builds the error for a non-2xx response from its body.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+	var msg struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &msg) == nil && msg.Error != "" {
+		apiErr.Message = msg.Error
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
+
+func (c *Client) send(ctx context.Context, method, target string, payload []byte, idemKey string, header http.Header) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		httpReq.Header[k] = v
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if idemKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idemKey)
+	}
+	if id, _ := ctx.Value(correlationIDCtx{}).(string); id != "" {
+		httpReq.Header.Set("X-Correlation-ID", id)
+	}
+	return c.http.Do(httpReq)
+}
+
+// shouldRetry reports whether a failed response may succeed if sent again.
+// A 409 is only retried when it is the server saying the same idempotency
+// key is still being processed.
+func shouldRetry(err *APIError, idempotent bool) bool {
+	switch err.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return idempotent && strings.Contains(strings.ToLower(err.Message), "idempotency")
+	}
+	return false
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.baseBackoff << attempt
+	if d <= 0 || d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+	// Full jitter over the upper half keeps retries from synchronising.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
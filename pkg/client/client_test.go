@@ -0,0 +1,282 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", "test-key", WithBackoff(time.Millisecond, 5*time.Millisecond))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestCreateMemory_RetriesWithSameIdempotencyKey(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" || r.URL.Path != "/v1/memories" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		n := len(keys)
+		mu.Unlock()
+		if n < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "busy"})
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"id": "m1", "content": "likes tea", "reinforced": true})
+	})
+
+	res, err := c.CreateMemory(context.Background(), CreateMemoryRequest{AgentID: "a1", Content: "likes tea"})
+	if err != nil {
+		t.Fatalf("CreateMemory: %v", err)
+	}
+	if res.ID != "m1" || !res.Reinforced {
+		t.Errorf("result = %+v", res)
+	}
+	if len(keys) != 3 || keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Errorf("idempotency keys = %v, want one key reused across retries", keys)
+	}
+}
+
+func TestCreateAgent_UsesCallerIdempotencyKey(t *testing.T) {
+	var got string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Idempotency-Key")
+		writeJSON(w, http.StatusCreated, map[string]any{"id": "a1", "name": "bot"})
+	})
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+	if _, err := c.CreateAgent(ctx, CreateAgentRequest{Name: "bot"}); err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	if got != "order-42" {
+		t.Errorf("Idempotency-Key = %q, want order-42", got)
+	}
+}
+
+func TestExtract_NotRetried(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-Request-ID", "req-7")
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "extraction queue is full; retry later"})
+	})
+	_, err := c.Extract(context.Background(), ExtractRequest{AgentID: "a1", Conversation: []Message{{Role: "user", Content: "hi"}}})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.RequestID != "req-7" || apiErr.Message != "extraction queue is full; retry later" {
+		t.Fatalf("err = %#v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("extract sent %d times, want 1", calls.Load())
+	}
+}
+
+func TestGetMemory_NotFound(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "memory not found"})
+	})
+	_, err := c.GetMemory(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("404 retried: %d calls", calls.Load())
+	}
+}
+
+func TestRecall_EncodesQuery(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/memories/recall" || q.Get("query") != "tea or coffee?" || q.Get("agent_id") != "a1" ||
+			q.Get("top_k") != "5" || q.Get("include_tiers") != "hot,warm" || q.Get("explain") != "true" || q.Has("session_id") {
+			t.Errorf("unexpected recall request %s", r.URL)
+		}
+		if r.Header.Get("X-Correlation-ID") != "flow-1" {
+			t.Errorf("X-Correlation-ID = %q", r.Header.Get("X-Correlation-ID"))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"memories": []map[string]any{{"id": "m1", "content": "likes tea", "score": 0.9, "score_breakdown": map[string]any{"final_score": 0.9}}},
+			"count":    1,
+		})
+	})
+	ctx := WithCorrelationID(context.Background(), "flow-1")
+	res, err := c.Recall(ctx, RecallRequest{AgentID: "a1", Query: "tea or coffee?", TopK: 5, IncludeTiers: []string{"hot", "warm"}, Explain: true})
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if res.Count != 1 || res.Memories[0].ID != "m1" || res.Memories[0].ScoreBreakdown.FinalScore != 0.9 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestRecallBatch_PostsQueriesAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/memories/recall/batch" || body["agent_id"] != "a1" || len(body["queries"].([]any)) != 2 {
+			t.Errorf("unexpected batch recall request %s %s %v", r.Method, r.URL, body)
+		}
+		if calls.Add(1) == 1 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "busy"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"results": []map[string]any{
+				{"query": "diet?", "memories": []map[string]any{{"id": "m1", "score": 0.9}}, "count": 1},
+				{"query": "allergies?", "memories": []map[string]any{}, "count": 0, "deduplicated": 1},
+			},
+		})
+	})
+	groups, err := c.RecallBatch(context.Background(), RecallBatchRequest{AgentID: "a1", Queries: []string{"diet?", "allergies?"}})
+	if err != nil {
+		t.Fatalf("RecallBatch: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected the read-only POST retried once, got %d calls", calls.Load())
+	}
+	if len(groups) != 2 || groups[0].Memories[0].ID != "m1" || groups[1].Deduplicated != 1 {
+		t.Errorf("groups = %+v", groups)
+	}
+}
+
+func TestExtractAsync_WaitJob(t *testing.T) {
+	var polls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/memories/extract":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["async"] != true || body["agent_id"] != "a1" {
+				t.Errorf("extract body = %v", body)
+			}
+			writeJSON(w, http.StatusAccepted, map[string]any{"id": "j1", "status": JobQueued})
+		case "/v1/jobs/j1":
+			if polls.Add(1) < 2 {
+				writeJSON(w, http.StatusOK, map[string]any{"id": "j1", "status": JobRunning})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"id": "j1", "status": JobSucceeded,
+				"result": []map[string]any{{"content": "likes tea", "stored": true}}})
+		}
+	})
+
+	ctx := context.Background()
+	job, err := c.ExtractAsync(ctx, ExtractRequest{AgentID: "a1", Conversation: []Message{{Role: "user", Content: "I like tea"}}, AutoStore: true})
+	if err != nil || job.ID != "j1" {
+		t.Fatalf("ExtractAsync = %+v, %v", job, err)
+	}
+	job, err = c.WaitJob(ctx, job.ID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitJob: %v", err)
+	}
+	mems, err := job.ExtractedMemories()
+	if err != nil || len(mems) != 1 || mems[0].Content != "likes tea" || !mems[0].Stored {
+		t.Errorf("ExtractedMemories = %+v, %v", mems, err)
+	}
+}
+
+func TestRetry_StopsWhenContextDone(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "rate limited"})
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.ListAgents(ctx, 0, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("retry wait ignored context cancellation")
+	}
+}
+
+func TestCreateTenant_SendsSetupTokenWithoutAPIKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/setup" || r.Header.Get("X-Setup-Token") != "setup-secret" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected setup request %s %v", r.URL.Path, r.Header)
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"tenant_id": "t1", "tenant_name": "Acme", "api_key": "mk_live"})
+	}))
+	t.Cleanup(srv.Close)
+
+	tenant, err := New(srv.URL, "").CreateTenant(context.Background(), "setup-secret", "Acme")
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if tenant.TenantID != "t1" || tenant.APIKey != "mk_live" {
+		t.Errorf("tenant = %+v", tenant)
+	}
+}
+
+func TestListMemories_Pages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/agents/a1/memories" || q.Get("limit") != "2" || q.Get("offset") != "4" {
+			t.Errorf("unexpected list request %s", r.URL)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"items": []map[string]any{{"id": "m5"}}, "total": 5, "limit": 2, "offset": 4})
+	})
+	page, err := c.ListMemories(context.Background(), "a1", 2, 4)
+	if err != nil {
+		t.Fatalf("ListMemories: %v", err)
+	}
+	if page.Total != 5 || len(page.Items) != 1 || page.Items[0].ID != "m5" {
+		t.Errorf("page = %+v", page)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	const data = "{\"_header\":true}\n{\"_trailer\":true}\n"
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/backup":
+			_, _ = w.Write([]byte(data))
+		case "/v1/backup/restore":
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != data {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid backup"})
+				return
+			}
+			writeJSON(w, http.StatusOK, RestoreResult{Restored: map[string]int{"agents": 1}})
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := c.Backup(context.Background(), &buf); err != nil || buf.String() != data {
+		t.Fatalf("unexpected backup %q, %v", buf.String(), err)
+	}
+	res, err := c.Restore(context.Background(), &buf)
+	if err != nil || res.Restored["agents"] != 1 {
+		t.Fatalf("unexpected restore %+v, %v", res, err)
+	}
+	_, err = c.Restore(context.Background(), strings.NewReader("garbage"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "invalid backup" {
+		t.Fatalf("expected a 400 APIError, got %v", err)
+	}
+}
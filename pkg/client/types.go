@@ -0,0 +1,422 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Agent is an agent owning memories and episodes.
+type Agent struct {
+	ID         string         `json:"id"`
+	ExternalID string         `json:"external_id"`
+	Name       string         `json:"name"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// CreateAgentRequest creates an agent. ExternalID is derived from Name when
+// empty.
+type CreateAgentRequest struct {
+	ExternalID string         `json:"external_id,omitempty"`
+	Name       string         `json:"name"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// AgentList is a page of agents.
+type AgentList struct {
+	Agents []Agent `json:"agents"`
+	Total  int     `json:"total"`
+	Count  int     `json:"count"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// Memory is a stored memory trace.
+type Memory struct {
+	ID                 string         `json:"id"`
+	AgentID            string         `json:"agent_id"`
+	Binding            string         `json:"binding,omitempty"`
+	AnchorID           string         `json:"anchor_id,omitempty"`
+	SessionID          string         `json:"session_id,omitempty"`
+	Type               string         `json:"type"`
+	Content            string         `json:"content"`
+	Subject            string         `json:"subject,omitempty"`
+	SubjectID          string         `json:"subject_id,omitempty"`
+	Topic              string         `json:"topic,omitempty"`
+	Source             string         `json:"source,omitempty"`
+	Provenance         string         `json:"provenance"`
+	Confidence         float64        `json:"confidence"`
+	Metadata           map[string]any `json:"metadata,omitempty"`
+	EventDate          *time.Time     `json:"event_date,omitempty"`
+	ExpiresAt          *time.Time     `json:"expires_at,omitempty"`
+	ReinforcementCount int            `json:"reinforcement_count"`
+	EvidenceFor        float64        `json:"evidence_for,omitempty"`
+	EvidenceAgainst    float64        `json:"evidence_against,omitempty"`
+	AccessCount        int            `json:"access_count"`
+	Tier               string         `json:"tier,omitempty"`
+	TierReason         string         `json:"tier_reason,omitempty"`
+	Pinned             bool           `json:"pinned,omitempty"`
+	QuarantineReason   string         `json:"quarantine_reason,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+// CreateMemoryRequest stores a memory. Type, Provenance and Confidence are
+// inferred by the server when empty. Set at most one of AnchorID and
+// AnchorExternalID.
+type CreateMemoryRequest struct {
+	AgentID          string         `json:"agent_id"`
+	Content          string         `json:"content"`
+	Type             string         `json:"type,omitempty"`
+	Subject          string         `json:"subject,omitempty"`
+	Topic            string         `json:"topic,omitempty"`
+	Source           string         `json:"source,omitempty"`
+	Provenance       string         `json:"provenance,omitempty"`
+	Confidence       float64        `json:"confidence,omitempty"`
+	Metadata         map[string]any `json:"metadata,omitempty"`
+	EventDate        string         `json:"event_date,omitempty"`
+	AnchorID         string         `json:"anchor_id,omitempty"`
+	AnchorExternalID string         `json:"anchor_external_id,omitempty"`
+	SessionID        string         `json:"session_id,omitempty"`
+	Quarantine       bool           `json:"quarantine,omitempty"`
+	Environment      *Environment   `json:"environment,omitempty"`
+}
+
+// CreateMemoryResult is the stored memory. Reinforced is set when the content
+// matched an existing memory, which was strengthened instead.
+type CreateMemoryResult struct {
+	Memory
+	Reinforced  bool `json:"reinforced"`
+	Quarantined bool `json:"quarantined,omitempty"`
+}
+
+// RecallRequest searches an agent's memories. AgentID may be left empty when
+// recalling by anchor or session. Zero values use the server defaults.
+type RecallRequest struct {
+	AgentID          string
+	Query            string
+	AnchorID         string
+	AnchorExternalID string
+	SessionID        string
+	TopK             int
+	Type             string
+	Subject          string
+	MinConfidence    float64
+	GraphWeight      float64
+	IncludeTiers     []string
+	Explain          bool
+	// NoInterference ranks without the penalty for memories crowded by
+	// similar ones.
+	NoInterference bool
+	// Environment is the caller's; memories formed in a matching one rank
+	// higher.
+	Environment Environment
+	// Accuracy, in (0, 1], trades recall quality for latency; lower is
+	// faster. 0 keeps the server's search settings.
+	Accuracy float64
+}
+
+// RecalledMemory is a memory ranked by a recall.
+type RecalledMemory struct {
+	Memory
+	Score          float64         `json:"score"`
+	DecayStatus    string          `json:"decay_status"`
+	ScoreBreakdown *ScoreBreakdown `json:"score_breakdown,omitempty"`
+	VectorScore    float64         `json:"vector_score,omitempty"`
+	GraphScore     float64         `json:"graph_score,omitempty"`
+}
+
+// ScoreBreakdown explains a recall score; returned when RecallRequest.Explain
+// is set.
+type ScoreBreakdown struct {
+	Similarity         float64 `json:"similarity"`
+	Confidence         float64 `json:"confidence"`
+	Freshness          float64 `json:"freshness"`
+	TypeWeight         float64 `json:"type_weight,omitempty"`
+	ReinforcementCount int     `json:"reinforcement_count"`
+	Tier               string  `json:"tier"`
+	Interference       float64 `json:"interference,omitempty"`
+	EnvironmentBoost   float64 `json:"environment_boost,omitempty"`
+	RecencyBoost       float64 `json:"recency_boost,omitempty"`
+	GraphScore         float64 `json:"graph_score,omitempty"`
+	FinalScore         float64 `json:"final_score"`
+}
+
+// RecallResult is the ranked memories for a query.
+type RecallResult struct {
+	Memories []RecalledMemory `json:"memories"`
+	Query    string           `json:"query"`
+	Count    int              `json:"count"`
+}
+
+// RecallBatchRequest recalls for several queries, e.g. the sub-questions of
+// a task, with the same options. At most 20 queries.
+type RecallBatchRequest struct {
+	Queries          []string    `json:"queries"`
+	AgentID          string      `json:"agent_id,omitempty"`
+	AnchorID         string      `json:"anchor_id,omitempty"`
+	AnchorExternalID string      `json:"anchor_external_id,omitempty"`
+	SessionID        string      `json:"session_id,omitempty"`
+	TopK             int         `json:"top_k,omitempty"`
+	Type             string      `json:"type,omitempty"`
+	Subject          string      `json:"subject,omitempty"`
+	MinConfidence    float64     `json:"min_confidence,omitempty"`
+	GraphWeight      *float64    `json:"graph_weight,omitempty"`
+	IncludeTiers     []string    `json:"include_tiers,omitempty"`
+	NoInterference   bool        `json:"no_interference,omitempty"`
+	Environment      Environment `json:"environment,omitempty"`
+	Accuracy         float64     `json:"accuracy,omitempty"`
+}
+
+// RecallGroup is the recall for one query of a batch.
+type RecallGroup struct {
+	Query    string           `json:"query"`
+	Memories []RecalledMemory `json:"memories"`
+	Count    int              `json:"count"`
+	// Deduplicated counts memories left out because another query of the
+	// batch ranked them higher.
+	Deduplicated int `json:"deduplicated,omitempty"`
+}
+
+// Message is one turn of a conversation.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ExtractRequest extracts memories from a conversation, storing them when
+// AutoStore is set.
+type ExtractRequest struct {
+	AgentID      string    `json:"agent_id"`
+	Conversation []Message `json:"conversation"`
+	AutoStore    bool      `json:"auto_store"`
+}
+
+// ExtractedMemory is one memory found in a conversation.
+type ExtractedMemory struct {
+	ID         string  `json:"id,omitempty"`
+	Type       string  `json:"type"`
+	Content    string  `json:"content"`
+	Confidence float64 `json:"confidence"`
+	Stored     bool    `json:"stored"`
+	Reinforced bool    `json:"reinforced,omitempty"`
+}
+
+// Job statuses.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is a background job, such as an async extraction. Result holds the
+// job's raw output once it has succeeded.
+type Job struct {
+	ID            string          `json:"id"`
+	AgentID       string          `json:"agent_id"`
+	Kind          string          `json:"kind"`
+	Status        string          `json:"status"`
+	Result        json.RawMessage `json:"result,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	StartedAt     *time.Time      `json:"started_at,omitempty"`
+	FinishedAt    *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j *Job) Done() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+// Episode is a recorded raw experience.
+type Episode struct {
+	ID                  string              `json:"id"`
+	AgentID             string              `json:"agent_id"`
+	RawContent          string              `json:"raw_content"`
+	ConversationID      string              `json:"conversation_id,omitempty"`
+	OccurredAt          time.Time           `json:"occurred_at"`
+	ImportanceScore     float64             `json:"importance_score"`
+	Entities            []string            `json:"entities,omitempty"`
+	Topics              []string            `json:"topics,omitempty"`
+	Outcome             string              `json:"outcome,omitempty"`
+	OutcomeDescription  string              `json:"outcome_description,omitempty"`
+	ConsolidationStatus string              `json:"consolidation_status,omitempty"`
+	Attachments         []EpisodeAttachment `json:"attachments,omitempty"`
+	Participants        []Participant       `json:"participants,omitempty"`
+	Environment         *Environment        `json:"environment,omitempty"`
+	CreatedAt           time.Time           `json:"created_at"`
+}
+
+// EpisodeAttachment is an image an episode refers to by URL. The server
+// captions it when Caption is empty and a vision model is configured.
+type EpisodeAttachment struct {
+	URL       string `json:"url"`
+	MediaType string `json:"media_type,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+}
+
+// Participant is someone present in an episode. Role is user, agent or
+// third_party (the default); Speaking marks who said the episode's content.
+// The server fills EntityID with the participant's person entity.
+type Participant struct {
+	Name     string `json:"name"`
+	Role     string `json:"role,omitempty"`
+	Speaking bool   `json:"speaking,omitempty"`
+	EntityID string `json:"entity_id,omitempty"`
+}
+
+// Environment is the context a memory or episode was formed in; recall given
+// a matching one ranks it higher. Empty fields are unknown.
+type Environment struct {
+	Channel string `json:"channel,omitempty"`
+	Device  string `json:"device,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+	Surface string `json:"surface,omitempty"`
+}
+
+// CreateEpisodeRequest records an episode. OccurredAt defaults to now.
+type CreateEpisodeRequest struct {
+	AgentID        string              `json:"agent_id"`
+	RawContent     string              `json:"raw_content"`
+	ConversationID string              `json:"conversation_id,omitempty"`
+	OccurredAt     *time.Time          `json:"occurred_at,omitempty"`
+	Outcome        string              `json:"outcome,omitempty"`
+	Attachments    []EpisodeAttachment `json:"attachments,omitempty"`
+	Participants   []Participant       `json:"participants,omitempty"`
+	Environment    *Environment        `json:"environment,omitempty"`
+}
+
+// TranscriptSegment is one timed utterance; Start and End are seconds from
+// the start of the recording.
+type TranscriptSegment struct {
+	Speaker string  `json:"speaker,omitempty"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+}
+
+// IngestTranscriptRequest stores a transcript as one episode per speaker
+// turn. StartedAt defaults to the transcript ending now; Speaker labels
+// segments that name none; Participants gives speakers their roles.
+type IngestTranscriptRequest struct {
+	AgentID        string              `json:"agent_id"`
+	ConversationID string              `json:"conversation_id,omitempty"`
+	StartedAt      *time.Time          `json:"started_at,omitempty"`
+	Speaker        string              `json:"speaker,omitempty"`
+	Segments       []TranscriptSegment `json:"segments"`
+	Participants   []Participant       `json:"participants,omitempty"`
+}
+
+// IngestTranscriptResponse lists the episodes a transcript produced.
+type IngestTranscriptResponse struct {
+	ConversationID string    `json:"conversation_id"`
+	Episodes       []Episode `json:"episodes"`
+	Count          int       `json:"count"`
+}
+
+// RecallEpisodesRequest searches an agent's episodes.
+type RecallEpisodesRequest struct {
+	AgentID       string
+	Query         string
+	Limit         int
+	MinImportance float64
+	Start, End    time.Time
+	Environment   Environment
+}
+
+// RecalledEpisode is an episode ranked by a recall.
+type RecalledEpisode struct {
+	Episode
+	Score float64 `json:"score"`
+	// Passage is the best-matching chunk of a long episode, when recall
+	// matched one.
+	Passage string `json:"passage,omitempty"`
+}
+
+// Tenant is a newly provisioned tenant and its first (master) API key, which
+// the server shows only once.
+type Tenant struct {
+	TenantID   string    `json:"tenant_id"`
+	TenantName string    `json:"tenant_name"`
+	KeyID      string    `json:"key_id"`
+	KeyPrefix  string    `json:"key_prefix"`
+	APIKey     string    `json:"api_key"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateAPIKeyRequest creates an API key for the authenticated tenant.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKey is a created API key; the key itself is only returned once.
+type APIKey struct {
+	KeyID     string     `json:"key_id"`
+	KeyPrefix string     `json:"key_prefix"`
+	APIKey    string     `json:"api_key"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MemoryPage is a page of an agent's memories.
+type MemoryPage struct {
+	Items  []Memory `json:"items"`
+	Total  int      `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+}
+
+// LLMUsage is the model usage and estimated cost of an operation.
+type LLMUsage struct {
+	LLMCalls         int     `json:"llm_calls"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	EmbeddingCalls   int     `json:"embedding_calls"`
+	EmbeddingTokens  int64   `json:"embedding_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// ConsolidationResult counts what a consolidation pass changed.
+type ConsolidationResult struct {
+	EpisodesProcessed    int      `json:"episodes_processed"`
+	EpisodesDeferred     int      `json:"episodes_deferred"`
+	SemanticExtracted    int      `json:"semantic_extracted"`
+	SemanticReinforced   int      `json:"semantic_reinforced"`
+	ProceduresLearned    int      `json:"procedures_learned"`
+	ProceduresReinforced int      `json:"procedures_reinforced"`
+	SchemasDetected      int      `json:"schemas_detected"`
+	SchemasUpdated       int      `json:"schemas_updated"`
+	MemoriesDecayed      int      `json:"memories_decayed"`
+	MemoriesArchived     int      `json:"memories_archived"`
+	MemoriesMerged       int      `json:"memories_merged"`
+	AssociationsCreated  int      `json:"associations_created"`
+	Usage                LLMUsage `json:"usage"`
+}
+
+// ConsolidationRun is one recorded consolidation pass, with Result holding
+// the run's raw counts.
+type ConsolidationRun struct {
+	ID         string          `json:"id"`
+	AgentID    string          `json:"agent_id"`
+	Scope      string          `json:"scope"`
+	Result     json.RawMessage `json:"result"`
+	Usage      LLMUsage        `json:"usage"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+}
+
+// RestoreResult counts, per table, the rows a restore inserted and the rows
+// it skipped as already present or not the tenant's.
+type RestoreResult struct {
+	Restored          map[string]int `json:"restored"`
+	Skipped           map[string]int `json:"skipped"`
+	SignatureVerified bool           `json:"signature_verified"`
+}